@@ -8,8 +8,10 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
 
 	"ariga.io/atlas/sql/sqlite"
@@ -28,6 +30,64 @@ func TestVars_String(t *testing.T) {
 	require.Equal(t, "[a:[b, d], b:c]", vs.String(), "multiple values of the same key: --var url=<one> --var url=<two>")
 }
 
+func TestLogFormat(t *testing.T) {
+	run := func(args ...string) (events []map[string]any, stderr string, err error) {
+		var out, errOut bytes.Buffer
+		root, migrate, schema := &cobra.Command{Use: "atlas"}, migrateCmd(), schemaCmd()
+		addFlagLogFormat(root.PersistentFlags())
+		migrate.AddCommand(migrateApplyCmd())
+		schema.AddCommand(schemaApplyCmd())
+		root.AddCommand(migrate, schema)
+		root.SetOut(&out)
+		root.SetErr(&errOut)
+		root.SetArgs(args)
+		err = root.ExecuteContext(context.Background())
+		// Unknown log formats are rejected before any event is written.
+		if !slices.Contains(args, logFormatJSON) {
+			require.Empty(t, out.String())
+			return nil, errOut.String(), err
+		}
+		dec := json.NewDecoder(&out)
+		for dec.More() {
+			var e map[string]any
+			require.NoError(t, dec.Decode(&e), "stdout must contain only events")
+			events = append(events, e)
+		}
+		return events, errOut.String(), err
+	}
+	names := func(events []map[string]any) []string {
+		ns := make([]string, len(events))
+		for i, e := range events {
+			ns[i] = e["event"].(string)
+		}
+		return ns
+	}
+
+	events, stderr, err := run("migrate", "apply", "--log-format", "json", "--dir", "file://testdata/sqlite", "--url", openSQLite(t, ""))
+	require.NoError(t, err)
+	require.Equal(t, []string{"command.started", "plan.started", "file.started", "statement.applied", "file.started", "statement.applied", "command.finished"}, names(events))
+	require.Equal(t, "migrate apply", events[0]["command"])
+	require.Equal(t, "ALTER TABLE `tbl` ADD `col_2` bigint;", events[5]["stmt"])
+	require.Equal(t, "20220318104615_second.sql", events[5]["file"])
+	require.Contains(t, stderr, "Migrating to version 20220318104615 (2 migrations in total)")
+
+	events, _, err = run("migrate", "apply", "--log-format", "json", "--dir", "file://testdata/sqlite", "--url", openSQLite(t, "CREATE TABLE tbl(c int);"), "--allow-dirty")
+	require.Error(t, err)
+	require.Equal(t, []string{"command.started", "plan.started", "file.started", "statement.failed", "command.failed"}, names(events))
+	require.Equal(t, "error", events[3]["level"])
+	require.Contains(t, events[3]["error"], "table tbl already exists")
+	require.Equal(t, err.Error(), events[4]["error"])
+
+	events, _, err = run("schema", "apply", "--log-format", "json", "--url", openSQLite(t, ""), "--to", openSQLite(t, "create table t1 (id int);"), "--auto-approve")
+	require.NoError(t, err)
+	require.Equal(t, []string{"command.started", "plan.started", "statement.applied", "command.finished"}, names(events))
+	require.Equal(t, "schema apply", events[0]["command"])
+	require.Equal(t, float64(1), events[1]["statements"])
+
+	_, _, err = run("schema", "apply", "--log-format", "xml", "--url", openSQLite(t, ""), "--to", openSQLite(t, ""))
+	require.EqualError(t, err, `unknown log format "xml". Supported formats are "text" and "json"`)
+}
+
 func runCmd(cmd *cobra.Command, args ...string) (string, error) {
 	return runCmdContext(context.Background(), cmd, args...)
 }
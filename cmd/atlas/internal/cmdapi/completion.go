@@ -0,0 +1,135 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// completionFunc is the signature of cobra dynamic completion functions.
+type completionFunc func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+
+// flagCompletions holds the dynamic completions of the flags shared by many commands.
+var flagCompletions = map[string]completionFunc{
+	flagEnv:       completeEnvs,
+	flagURL:       completeURLs(),
+	flagDevURL:    completeURLs(),
+	flagTo:        completeURLs(cmdmigrate.DirTypeFile, envAttrScheme),
+	flagFrom:      completeURLs(cmdmigrate.DirTypeFile, envAttrScheme),
+	flagBaseline:  completeVersions,
+	flagLogFormat: cobra.FixedCompletions([]string{logFormatText, logFormatJSON}, cobra.ShellCompDirectiveNoFileComp),
+}
+
+// registerCompletions registers the dynamic completions of the flags defined by the given
+// command and its sub-commands. It is called after the command tree is built, as the shell
+// completion scripts generated by the 'completion' command are derived from it.
+func registerCompletions(cmd *cobra.Command) {
+	registered := make(map[*pflag.Flag]bool)
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for name, f := range flagCompletions {
+			fl := cmd.Flags().Lookup(name)
+			if fl == nil {
+				fl = cmd.PersistentFlags().Lookup(name)
+			}
+			// Persistent flags are registered once, by the command that defines them.
+			if fl != nil && !registered[fl] {
+				registered[fl] = true
+				cobra.CheckErr(cmd.RegisterFlagCompletionFunc(name, f))
+			}
+		}
+		for _, c := range cmd.Commands() {
+			walk(c)
+		}
+	}
+	walk(cmd)
+}
+
+// completeEnvs completes the names of the environments defined in the project file.
+// The project file is not evaluated, allowing completing the names of environments
+// that depend on input variables.
+func completeEnvs(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	u, err := url.Parse(GlobalFlags.ConfigURL)
+	if err != nil || u.Scheme != "file" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	f, diags := hclparse.NewParser().ParseHCLFile(filepath.Join(u.Host, u.Path))
+	if diags.HasErrors() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, b := range f.Body.(*hclsyntax.Body).Blocks {
+		if b.Type == blockEnv && len(b.Labels) == 1 && strings.HasPrefix(b.Labels[0], toComplete) && !slices.Contains(names, b.Labels[0]) {
+			names = append(names, b.Labels[0])
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeURLs returns a function that completes the schemes of database URLs, based on the
+// drivers registered in this build, and the given extra schemes. e.g., flags that accept a
+// desired state can also reference files, or the attributes of the selected environment.
+func completeURLs(extra ...string) completionFunc {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var urls []string
+		for _, s := range append([]string{"mysql", "maria", "postgres", "cockroach", "sqlite", "libsql", "trino", "docker"}, extra...) {
+			switch u := s + "://"; {
+			case !strings.HasPrefix(u, toComplete):
+			// The attributes of the environment can be referenced only if it was selected.
+			case s == envAttrScheme && GlobalFlags.SelectedEnv == "":
+			case slices.Contains(extra, s) || sqlclient.HasDriver(s):
+				urls = append(urls, u)
+			}
+		}
+		return urls, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeVersions completes the versions of the migration directory, described
+// by their files. The directory is resolved from the --dir flag, or from the
+// selected environment.
+func completeVersions(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var (
+		dirURL string
+		f      = cmd.Flag(flagDirURL)
+	)
+	if f != nil {
+		dirURL = f.Value.String()
+	}
+	if (f == nil || !f.Changed) && GlobalFlags.SelectedEnv != "" {
+		if _, envs, err := EnvByName(cmd, GlobalFlags.SelectedEnv, GlobalFlags.Vars); err == nil && len(envs) > 0 && envs[0].Migration != nil && envs[0].Migration.Dir != "" {
+			dirURL = envs[0].Migration.Dir
+		}
+	}
+	if dirURL == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	dir, err := cmdmigrate.Dir(cmd.Context(), dirURL, false)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var versions []string
+	for _, f := range files {
+		if v := f.Version(); strings.HasPrefix(v, toComplete) {
+			versions = append(versions, v+"\t"+f.Desc())
+		}
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,63 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletion(t *testing.T) {
+	h := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(h, []byte(`
+variable "url" {
+  type    = string
+  default = "sqlite://dev?mode=memory"
+}
+
+env "local" {
+  migration {
+    dir = "file://testdata/sqlite"
+  }
+}
+
+env "prod" {
+  url = var.url
+}`), 0600))
+	complete := func(args ...string) []string {
+		root, migrate, schema := &cobra.Command{Use: "atlas"}, migrateCmd(), schemaCmd()
+		addFlagLogFormat(root.PersistentFlags())
+		migrate.AddCommand(migrateApplyCmd(), migrateSetCmd())
+		schema.AddCommand(schemaApplyCmd())
+		root.AddCommand(migrate, schema)
+		registerCompletions(root)
+		s, err := runCmd(root, append([]string{cobra.ShellCompRequestCmd}, args...)...)
+		require.NoError(t, err)
+		// Drop the debug message written by cobra to stderr.
+		s, _, _ = strings.Cut(s, "Completion ended with directive")
+		return strings.Split(strings.TrimSpace(s), "\n")
+	}
+	require.Equal(t, []string{"local", "prod", ":4"}, complete("migrate", "apply", "-c", "file://"+h, "--env", ""))
+	require.Equal(t, []string{"prod", ":4"}, complete("schema", "apply", "-c", "file://"+h, "--env", "p"))
+	require.Equal(t, []string{":4"}, complete("schema", "apply", "-c", "file://unknown.hcl", "--env", ""))
+	require.Equal(t, []string{"text", "json", ":4"}, complete("migrate", "apply", "--log-format", ""))
+
+	// URL schemes are completed from the registered drivers.
+	require.Equal(t, []string{"sqlite://", ":6"}, complete("migrate", "apply", "--url", "s"))
+	require.Equal(t, []string{"file://", ":6"}, complete("schema", "apply", "--to", "f"))
+	require.Equal(t, []string{":6"}, complete("schema", "apply", "--to", "e"))
+	require.Equal(t, []string{"env://", ":6"}, complete("schema", "apply", "-c", "file://"+h, "--env", "local", "--to", "e"))
+
+	// Versions are completed from the migration directory.
+	require.Equal(t, []string{"20220318104614\tinitial", "20220318104615\tsecond", ":4"}, complete("migrate", "set", "--dir", "file://testdata/sqlite", ""))
+	require.Equal(t, []string{"20220318104615\tsecond", ":4"}, complete("migrate", "set", "-c", "file://"+h, "--env", "local", "20220318104615"))
+	require.Equal(t, []string{"20220318104614\tinitial", ":4"}, complete("migrate", "apply", "--dir", "file://testdata/sqlite", "--baseline", "20220318104614"))
+	require.Equal(t, []string{":4"}, complete("migrate", "set", "--dir", "file://testdata/sqlite", "20220318104614", ""))
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExec(t *testing.T) {
+	u := openSQLite(t, "CREATE TABLE users (id int NOT NULL PRIMARY KEY, name text);")
+	s, err := runCmd(execCmd(), "-u", u, "INSERT INTO users VALUES (1, 'a8m'), (2, NULL)", "SELECT * FROM users ORDER BY id")
+	require.NoError(t, err)
+	require.Equal(t, "id  name\n1   a8m\n2   NULL\n", s)
+
+	// Statements are read from files and stdin.
+	p := filepath.Join(t.TempDir(), "script.sql")
+	require.NoError(t, os.WriteFile(p, []byte("DELETE FROM users WHERE id = 2;\nSELECT count(*) AS n FROM users;"), 0600))
+	cmd := execCmd()
+	cmd.SetIn(strings.NewReader("SELECT name FROM users;"))
+	s, err = runCmd(cmd, "-u", u, "--file", p, "--file", "-")
+	require.NoError(t, err)
+	require.Equal(t, "n\n1\n\nname\na8m\n", s)
+
+	s, err = runCmd(execCmd(), "-u", u, "--format", "{{ json .Results }}", "SELECT id FROM users")
+	require.NoError(t, err)
+	require.Equal(t, `[{"Stmt":"SELECT id FROM users","Columns":["id"],"Rows":[[1]]}]`, s)
+
+	// Execution stops on the first error.
+	s, err = runCmd(execCmd(), "-u", u, "SELECT id FROM users", "SELECT * FROM unknown", "DROP TABLE users")
+	require.EqualError(t, err, `executing statement "SELECT * FROM unknown": no such table: unknown`)
+	require.True(t, strings.HasPrefix(s, "id\n1\n"))
+	_, err = runCmd(execCmd(), "-u", u)
+	require.EqualError(t, err, "no statements to execute. Pass them as arguments or using the --file flag")
+
+	// URL is resolved from the selected environment.
+	h := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(h, []byte(`
+variable "url" {
+  type = string
+}
+
+env "local" {
+  url = var.url
+}`), 0600))
+	s, err = runCmd(execCmd(), "-c", "file://"+h, "--env", "local", "--var", "url="+u, "SELECT name FROM users")
+	require.NoError(t, err)
+	require.Equal(t, "name\na8m\n", s)
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
@@ -98,6 +100,57 @@ func TestMigrate_Import(t *testing.T) {
 	}
 }
 
+func TestMigrate_ImportBaseline(t *testing.T) {
+	p := t.TempDir()
+	out, err := runCmd(
+		migrateImportCmd(),
+		"--from", "file://testdata/import/golang-migrate?format=golang-migrate",
+		"--to", "file://"+p,
+		"--baseline", "1",
+	)
+	require.NoError(t, err)
+	require.Zero(t, out)
+	ac, err := os.ReadDir(p)
+	require.NoError(t, err)
+	require.Len(t, ac, 3) // baseline, second migration and sum file
+	e, err := os.ReadFile("testdata/import/golang-migrate_gold/1_initial.sql")
+	require.NoError(t, err)
+	a, err := os.ReadFile(filepath.Join(p, "1_baseline.sql"))
+	require.NoError(t, err)
+	require.Equal(t, string(e), string(a))
+	require.FileExists(t, filepath.Join(p, "2_second_migration.sql"))
+
+	_, err = runCmd(
+		migrateImportCmd(),
+		"--from", "file://testdata/import/golang-migrate?format=golang-migrate",
+		"--to", "file://"+t.TempDir(),
+		"--baseline", "3",
+	)
+	require.EqualError(t, err, `baseline version "3" was not found in the source directory`)
+}
+
+func TestMigrate_ImportPlaceholders(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "V1__initial.sql"), []byte("CREATE TABLE ${schema}.t1 (c int);\n"), 0644))
+	_, err := runCmd(
+		migrateImportCmd(),
+		"--from", fmt.Sprintf("file://%s?format=flyway", src),
+		"--to", "file://"+dst,
+	)
+	require.EqualError(t, err, `sql/sqltool: flyway: V1__initial.sql: no value provided for placeholder "schema"`)
+
+	_, err = runCmd(
+		migrateImportCmd(),
+		"--from", fmt.Sprintf("file://%s?format=flyway", src),
+		"--to", "file://"+dst,
+		"--placeholder", "schema=public",
+	)
+	require.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(dst, "1_initial.sql"))
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE public.t1 (c int);\n", string(b))
+}
+
 func TestMigrate_Apply(t *testing.T) {
 	var (
 		p   = t.TempDir()
@@ -598,6 +651,85 @@ env "prod" {
 			require.Contains(t, s, fmt.Sprintf("create table users_%s2 (c text);", e))
 		}
 	})
+
+	t.Run("Parallel", func(t *testing.T) {
+		var (
+			h = `
+variable "path" {
+  type = string
+}
+
+env "staging" {
+  url = "sqlite://file:${var.path}/staging.db?cache=shared&_fk=1"
+  migration {
+    dir = "file://testdata/sqlite"
+  }
+}
+
+env "prod" {
+  url = "sqlite://file:${var.path}/prod.db?cache=shared&_fk=1"
+  migration {
+    dir = "file://testdata/sqlite"
+  }
+}
+
+env "broken" {
+  url = "sqlite://file:${var.path}/broken.db?cache=shared&_fk=1"
+  migration {
+    dir = "file://testdata/unknown"
+  }
+}
+`
+			p    = t.TempDir()
+			path = filepath.Join(p, "atlas.hcl")
+		)
+		err := os.WriteFile(path, []byte(h), 0600)
+		require.NoError(t, err)
+		cmd := migrateCmd()
+		cmd.AddCommand(migrateApplyCmd())
+		s, err := runCmd(
+			cmd, "apply",
+			"-c", "file://"+path,
+			"--env", "staging,prod",
+			"--var", "path="+p,
+			"--parallel", "2",
+		)
+		require.NoError(t, err)
+		require.Equal(t, 2, strings.Count(s, "Migrating to version 20220318104615 (2 migrations in total)"), "execution per environment")
+		require.Regexp(t, `staging\s+sqlite://file:.+/staging.db\?.+\s+ok\s+`, s)
+		require.Regexp(t, `prod\s+sqlite://file:.+/prod.db\?.+\s+ok\s+`, s)
+		for _, n := range []string{"staging.db", "prod.db"} {
+			_, err = os.Stat(filepath.Join(p, n))
+			require.NoError(t, err)
+		}
+
+		// A failing environment does not stop the others.
+		cmd = migrateCmd()
+		cmd.AddCommand(migrateApplyCmd())
+		s, err = runCmd(
+			cmd, "apply",
+			"-c", "file://"+path,
+			"--env", "broken,staging",
+			"--var", "path="+p,
+			"--parallel", "1",
+		)
+		require.EqualError(t, err, "1 of 2 environments failed")
+		require.Contains(t, s, "No migration files to execute")
+		require.Regexp(t, `broken\s+sqlite://file:.+/broken.db\?.+\s+error\s+`, s)
+		require.Regexp(t, `staging\s+sqlite://file:.+/staging.db\?.+\s+ok\s+`, s)
+
+		// Unknown environments are rejected.
+		cmd = migrateCmd()
+		cmd.AddCommand(migrateApplyCmd())
+		_, err = runCmd(
+			cmd, "apply",
+			"-c", "file://"+path,
+			"--env", "staging,unknown",
+			"--var", "path="+p,
+			"--parallel", "2",
+		)
+		require.EqualError(t, err, `env "unknown" not defined in config file`)
+	})
 }
 
 func TestMigrate_ApplyTxMode(t *testing.T) {
@@ -798,6 +930,53 @@ func TestMigrate_ApplyExecOrder(t *testing.T) {
 	}
 }
 
+func TestMigrate_ApplyTelemetry(t *testing.T) {
+	var traces []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		traces = append(traces, r.URL.Path+" "+string(b))
+	}))
+	defer srv.Close()
+	p := t.TempDir()
+	h := filepath.Join(p, "atlas.hcl")
+	require.NoError(t, os.WriteFile(h, []byte(fmt.Sprintf(`
+env "local" {
+  url = %q
+  migration {
+    dir = "file://testdata/sqlite"
+  }
+  telemetry {
+    metrics {
+      textfile = %q
+    }
+    traces {
+      endpoint = %q
+    }
+  }
+}`, openSQLite(t, ""), filepath.Join(p, "atlas.prom"), srv.URL)), 0600))
+	cmd := migrateCmd()
+	cmd.AddCommand(migrateApplyCmd())
+	_, err := runCmd(cmd, "apply", "-c", "file://"+h, "--env", "local")
+	require.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(p, "atlas.prom"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), `atlas_apply_success{command="migrate apply",env="local"} 1`)
+	require.Contains(t, string(b), `atlas_apply_statements{command="migrate apply",env="local"} 2`)
+	require.Contains(t, string(b), `atlas_apply_file_statements{command="migrate apply",env="local",file="20220318104615_second.sql"} 1`)
+	require.Len(t, traces, 1)
+	require.True(t, strings.HasPrefix(traces[0], "/v1/traces "))
+	require.Contains(t, traces[0], `"name":"20220318104614_initial.sql"`)
+
+	// Export failures do not fail the command.
+	srv.Close()
+	cmd = migrateCmd()
+	cmd.AddCommand(migrateApplyCmd())
+	s, err := runCmd(cmd, "apply", "-c", "file://"+h, "--env", "local")
+	require.NoError(t, err)
+	require.Contains(t, s, "Error exporting apply telemetry: cmdtelemetry: export traces:")
+}
+
 func TestMigrate_ApplyBaseline(t *testing.T) {
 	t.Run("FromFlags", func(t *testing.T) {
 		p := t.TempDir()
@@ -1264,6 +1443,52 @@ func TestMigrate_StatusJSON(t *testing.T) {
 	require.Equal(t, `"sqlite"`, s)
 }
 
+func TestMigrate_History(t *testing.T) {
+	u := fmt.Sprintf("sqlite://file:%s?_fk=1", filepath.Join(t.TempDir(), "test.db"))
+	s, err := runCmd(migrateHistoryCmd(), "-u", u)
+	require.NoError(t, err)
+	require.Equal(t, "No migrations were executed on the database\n", s)
+	// The revisions table is not created by the history command.
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM sqlite_master WHERE name = 'atlas_schema_revisions'").Scan(&n))
+	require.Zero(t, n)
+
+	_, err = runCmd(
+		migrateApplyCmd(),
+		"--dir", "file://testdata/sqlite",
+		"--url", u,
+	)
+	require.NoError(t, err)
+	s, err = runCmd(migrateHistoryCmd(), "-u", u)
+	require.NoError(t, err)
+	require.Contains(t, s, "20220318104614")
+	require.Contains(t, s, "20220318104615")
+	require.Contains(t, s, "second")
+
+	s, err = runCmd(migrateHistoryCmd(), "-u", u, "--limit", "1", "--format", "json")
+	require.NoError(t, err)
+	var h struct{ Revisions []*migrate.Revision }
+	require.NoError(t, json.Unmarshal([]byte(s), &h))
+	require.Len(t, h.Revisions, 1)
+	require.Equal(t, "20220318104615", h.Revisions[0].Version)
+	require.Equal(t, h.Revisions[0].Total, h.Revisions[0].Applied)
+	require.Equal(t, migrate.RevisionTypeExecute, h.Revisions[0].Type)
+
+	s, err = runCmd(migrateHistoryCmd(), "-u", u, "--errors")
+	require.NoError(t, err)
+	require.Equal(t, "No migrations were executed on the database\n", s)
+
+	s, err = runCmd(migrateHistoryCmd(), "-u", u, "--until", "2000-01-01")
+	require.NoError(t, err)
+	require.Equal(t, "No migrations were executed on the database\n", s)
+
+	_, err = runCmd(migrateHistoryCmd(), "-u", u, "--since", "yesterday")
+	require.EqualError(t, err, `invalid value for --since: "yesterday" is not a RFC 3339 time or a YYYY-MM-DD date`)
+}
+
 func TestMigrate_Set(t *testing.T) {
 	u := fmt.Sprintf("sqlite://file:%s?_fk=1", filepath.Join(t.TempDir(), "test.db"))
 	_, err := runCmd(
@@ -1426,6 +1651,58 @@ func TestMigrate_New(t *testing.T) {
 	})
 }
 
+func TestMigrate_PushPull(t *testing.T) {
+	reg := "file://" + t.TempDir()
+	s, err := runCmd(migratePushCmd(), "app", "--dir", "file://testdata/mysql", "--registry", reg)
+	require.NoError(t, err)
+	require.Regexp(t, `^Pushed app:20220420213403 \(sha256:[a-f0-9]{64}\)\nPushed app:latest \(sha256:[a-f0-9]{64}\)\n$`, s)
+	s, err = runCmd(migratePushCmd(), "app:v1", "--dir", "file://testdata/mysql", "--registry", reg)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(s, "Pushed app:v1 (sha256:"))
+
+	// Pulling replaces the contents of the directory.
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_stale.sql"), []byte("stale"), 0600))
+	s, err = runCmd(migratePullCmd(), "app", "--dir", "file://"+p, "--registry", reg)
+	require.NoError(t, err)
+	require.Regexp(t, `^Pulled app:latest \(sha256:[a-f0-9]{64}\): 2 migration files\n$`, s)
+	require.NoFileExists(t, filepath.Join(p, "1_stale.sql"))
+	for _, f := range []string{"20220318104614_initial.sql", "20220420213403_second.sql", "atlas.sum"} {
+		expected, err := os.ReadFile(filepath.Join("testdata", "mysql", f))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(p, f))
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(actual))
+	}
+	_, err = runCmd(migrateValidateCmd(), "--dir", "file://"+p)
+	require.NoError(t, err)
+
+	_, err = runCmd(migratePullCmd(), "app:v2", "--dir", "file://"+p, "--registry", reg)
+	require.ErrorContains(t, err, `cmdregistry: artifact "app:v2" was not found`)
+	_, err = runCmd(migratePushCmd(), "--dir", "file://testdata/mysql", "--registry", reg)
+	require.EqualError(t, err, "missing repository name. Pass it as an argument or set the repo block of the migration configuration")
+	_, err = runCmd(migratePushCmd(), "app", "--dir", "file://testdata/mysql")
+	require.EqualError(t, err, `required flag(s) "registry" not set`)
+
+	// Repository and registry are read from the project file.
+	h := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(h, []byte(`
+env "local" {
+  migration {
+    dir = "file://testdata/mysql"
+    repo {
+      name     = "team/app"
+      registry = "`+reg+`"
+    }
+  }
+}`), 0600))
+	cmd := migrateCmd()
+	cmd.AddCommand(migratePushCmd())
+	s, err = runCmd(cmd, "push", "-c", "file://"+h, "--env", "local")
+	require.NoError(t, err)
+	require.Contains(t, s, "Pushed team/app:latest (sha256:")
+}
+
 func TestMigrate_Validate(t *testing.T) {
 	// Without re-playing.
 	s, err := runCmd(migrateValidateCmd(), "--dir", "file://testdata/mysql")
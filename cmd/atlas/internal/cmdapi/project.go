@@ -11,11 +11,13 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
 	"ariga.io/atlas/cmd/atlas/internal/cloudapi"
 	"ariga.io/atlas/cmd/atlas/internal/cmdext"
+	"ariga.io/atlas/cmd/atlas/internal/cmdtelemetry"
 	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
 	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/schema"
@@ -70,6 +72,9 @@ type (
 		// Test configuration of the environment.
 		Test *Test `spec:"test"`
 
+		// Telemetry configures the export of metrics and traces of the apply commands.
+		Telemetry *Telemetry `spec:"telemetry"`
+
 		schemahcl.DefaultExtension
 		cloud  *cmdext.AtlasConfig
 		config *Project
@@ -98,7 +103,28 @@ type (
 	// Repo represents a repository in the schema registry
 	// for a schema or migrations directory.
 	Repo struct {
-		Name string `spec:"name"` // Name of the repository.
+		Name     string `spec:"name"`     // Name of the repository.
+		Registry string `spec:"registry"` // URL of the registry. e.g., file://registry.
+	}
+
+	// Telemetry represents the export configuration of the metrics
+	// and traces of 'migrate apply' and 'schema apply'.
+	Telemetry struct {
+		Metrics *Metrics `spec:"metrics"`
+		Traces  *Traces  `spec:"traces"`
+	}
+
+	// Metrics configures the export of Prometheus metrics.
+	Metrics struct {
+		Textfile    string `spec:"textfile"`    // Path of the node_exporter textfile collector file.
+		Pushgateway string `spec:"pushgateway"` // URL of the Prometheus Pushgateway.
+		Job         string `spec:"job"`         // Job name used when pushing metrics.
+	}
+
+	// Traces configures the export of OpenTelemetry traces using OTLP/HTTP.
+	Traces struct {
+		Endpoint string `spec:"endpoint"` // e.g., http://localhost:4318.
+		Headers  Vars   `spec:"headers"`
 	}
 
 	// Lint represents the configuration of migration linting.
@@ -172,6 +198,8 @@ type (
 			Lint string `spec:"lint"`
 			// Status configures the formatting for 'migrate status'.
 			Status string `spec:"status"`
+			// History configures the formatting for 'migrate history'.
+			History string `spec:"history"`
 			// Apply configures the formatting for 'migrate diff'.
 			Diff string `spec:"diff"`
 		} `spec:"migrate"`
@@ -186,6 +214,8 @@ type (
 			Diff string `spec:"diff"`
 			// Push configures the formatting for 'schema push'.
 			Push string `spec:"push"`
+			// Drift configures the formatting for 'schema drift'.
+			Drift string `spec:"drift"`
 		} `spec:"schema"`
 		schemahcl.DefaultExtension
 	}
@@ -226,6 +256,27 @@ func (e *Env) LintReview() string {
 	return ReviewAlways
 }
 
+// TelemetryConfig returns the export configuration of the apply metrics and traces, if set.
+func (e *Env) TelemetryConfig() (*cmdtelemetry.Config, error) {
+	if e == nil || e.Telemetry == nil {
+		return nil, nil
+	}
+	c := &cmdtelemetry.Config{}
+	if m := e.Telemetry.Metrics; m != nil {
+		c.Metrics = &cmdtelemetry.MetricsConfig{Textfile: m.Textfile, Pushgateway: m.Pushgateway, Job: m.Job}
+	}
+	if t := e.Telemetry.Traces; t != nil {
+		c.Traces = &cmdtelemetry.TracesConfig{Endpoint: t.Endpoint, Headers: make(map[string]string, len(t.Headers))}
+		for k, v := range t.Headers {
+			if !v.IsKnown() || v.IsNull() || !v.Type().Equals(cty.String) {
+				return nil, fmt.Errorf("telemetry: header %q of traces must be a string", k)
+			}
+			c.Traces.Headers[k] = v.AsString()
+		}
+	}
+	return c, nil
+}
+
 // VarFromURL returns the string variable (env attribute) from the URL.
 func (e *Env) VarFromURL(s string) (string, error) {
 	u, err := url.Parse(s)
@@ -525,7 +576,7 @@ func (t *Test) Extend(global *Test) *Test {
 func EnvByName(cmd *cobra.Command, name string, vars map[string]cty.Value) (*Project, []*Env, error) {
 	envs := make(map[string][]*Env)
 	defer func() {
-		setEnvs(cmd.Context(), envs[name])
+		setEnvs(cmd.Context(), selectEnvs(envs, name))
 	}()
 	if p, e, ok := envsCache.load(GlobalFlags.ConfigURL, name, vars); ok {
 		return p, e, maySetLoginContext(cmd, p)
@@ -580,17 +631,36 @@ func EnvByName(cmd *cobra.Command, name string, vars map[string]cty.Value) (*Pro
 		e.Test = e.Test.Extend(project.Test)
 		envs[e.Name] = append(envs[e.Name], e)
 	}
-	envsCache.store(GlobalFlags.ConfigURL, name, vars, project, envs[name])
-	switch {
-	case name == "":
+	if name == "" {
 		// If no env was selected,
 		// return only the project.
+		envsCache.store(GlobalFlags.ConfigURL, name, vars, project, nil)
 		return project, nil, nil
-	case len(envs[name]) == 0:
-		return nil, nil, fmt.Errorf("env %q not defined in config file", name)
-	default:
-		return project, envs[name], nil
 	}
+	// Multiple environments can be selected
+	// using a comma-separated list of names.
+	for _, n := range strings.Split(name, ",") {
+		if len(envs[n]) == 0 {
+			return nil, nil, fmt.Errorf("env %q not defined in config file", n)
+		}
+	}
+	selected := selectEnvs(envs, name)
+	envsCache.store(GlobalFlags.ConfigURL, name, vars, project, selected)
+	return project, selected, nil
+}
+
+// selectEnvs returns the environments selected by the given
+// name, or comma-separated list of names, in their given order.
+// Names that were given more than once are selected once.
+func selectEnvs(envs map[string][]*Env, name string) []*Env {
+	var selected []*Env
+	names := strings.Split(name, ",")
+	for i, n := range names {
+		if !slices.Contains(names[:i], n) {
+			selected = append(selected, envs[n]...)
+		}
+	}
+	return selected
 }
 
 type (
@@ -628,7 +698,33 @@ const (
 	defaultConfigPath = "file://atlas.hcl"
 )
 
+// parseConfig parses the project file for the given env, or comma-separated list of envs.
+// Each env is evaluated separately, as the atlas.env reference holds the name of the env
+// being evaluated, and the environments of all evaluations are collected into a single project.
 func parseConfig(ctx context.Context, path, env string, vars map[string]cty.Value) (*Project, error) {
+	names := strings.Split(env, ",")
+	p, err := parseEnvConfig(ctx, path, names[0], vars)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range names[1:] {
+		// Envs that were selected more than once are evaluated once.
+		if slices.Contains(names[:i+1], n) {
+			continue
+		}
+		pn, err := parseEnvConfig(ctx, path, n, vars)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range pn.Envs {
+			e.config, e.cloud = p, p.cloud
+			p.Envs = append(p.Envs, e)
+		}
+	}
+	return p, nil
+}
+
+func parseEnvConfig(ctx context.Context, path, env string, vars map[string]cty.Value) (*Project, error) {
 	pr, err := partialParse(path, env)
 	if err != nil {
 		return nil, err
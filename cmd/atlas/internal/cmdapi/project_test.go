@@ -296,6 +296,15 @@ env {
 	require.Len(t, envs, 1)
 	require.Equal(t, "local", envs[0].Name)
 	require.Equal(t, "env: local", envs[0].Format.Schema.Apply)
+
+	// Each selected env is evaluated with its own name.
+	_, envs, err = EnvByName(&cobra.Command{}, "staging,prod,staging", nil)
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+	require.Equal(t, "staging", envs[0].Name)
+	require.Equal(t, "env: staging", envs[0].Format.Schema.Apply)
+	require.Equal(t, "prod", envs[1].Name)
+	require.Equal(t, "env: prod", envs[1].Format.Schema.Apply)
 }
 
 func TestEnvCache(t *testing.T) {
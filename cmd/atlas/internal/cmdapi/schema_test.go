@@ -9,17 +9,22 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlite"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
 
@@ -590,6 +595,28 @@ func TestSchema_ApplyLog(t *testing.T) {
 	})
 }
 
+func TestSchema_ApplyTelemetry(t *testing.T) {
+	p := t.TempDir()
+	h := filepath.Join(p, "atlas.hcl")
+	require.NoError(t, os.WriteFile(h, []byte(fmt.Sprintf(`
+env "local" {
+  url = %q
+  telemetry {
+    metrics {
+      textfile = %q
+    }
+  }
+}`, openSQLite(t, ""), filepath.Join(p, "atlas.prom"))), 0600))
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaApplyCmd())
+	_, err := runCmd(cmd, "apply", "-c", "file://"+h, "--env", "local", "--to", openSQLite(t, "create table t1 (id int);"), "--auto-approve")
+	require.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(p, "atlas.prom"))
+	require.NoError(t, err)
+	require.Contains(t, string(b), `atlas_apply_success{command="schema apply",env="local"} 1`)
+	require.Contains(t, string(b), `atlas_apply_file_statements{command="schema apply",env="local",file="plan"} 1`)
+}
+
 func TestSchema_ApplySchemaMismatch(t *testing.T) {
 	var (
 		p   = t.TempDir()
@@ -1119,6 +1146,241 @@ func TestSchema_Clean(t *testing.T) {
 	require.NoError(t, c.Driver.CheckClean(context.Background(), nil))
 }
 
+func TestSchema_Seed(t *testing.T) {
+	var (
+		ddl = "CREATE TABLE users (id int PRIMARY KEY, name text); CREATE TABLE posts (id int PRIMARY KEY, author_id int REFERENCES users(id), title text);"
+		dir = t.TempDir()
+	)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "posts.json"), []byte(`[{"id": 1, "author_id": 1, "title": "hello"}]`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte("id,name\n1,a8m\n2,\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "names.sql"), []byte("UPDATE users SET name = 'rotem' WHERE id = 2;"), 0644))
+	count := func(t *testing.T, u, query string) (n int) {
+		c, err := sqlclient.Open(context.Background(), u)
+		require.NoError(t, err)
+		defer c.Close()
+		require.NoError(t, c.DB.QueryRow(query).Scan(&n))
+		return n
+	}
+
+	u := openSQLite(t, ddl)
+	s, err := runCmd(schemaSeedCmd(), "-u", u, "--fixtures", dir, "--dev-url", "sqlite://dev?mode=memory&_fk=1")
+	require.NoError(t, err)
+	require.Equal(t, "Loaded 2 rows into users from users.csv\nLoaded 1 rows into posts from posts.json\nExecuted 1 statements from names.sql\n", s)
+	require.Equal(t, 2, count(t, u, "SELECT COUNT(*) FROM users WHERE name IS NOT NULL"))
+	require.Equal(t, 1, count(t, u, "SELECT COUNT(*) FROM posts"))
+
+	u = openSQLite(t, ddl)
+	s, err = runCmd(schemaSeedCmd(), "-u", u, "--fixtures", dir, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, s, "Dry run, the loaded fixtures were rolled back")
+	require.Zero(t, count(t, u, "SELECT COUNT(*) FROM users"))
+
+	u = openSQLite(t, "CREATE TABLE posts (id int PRIMARY KEY);")
+	_, err = runCmd(schemaSeedCmd(), "-u", u, "--fixtures", filepath.Join(dir, "users.csv"))
+	require.EqualError(t, err, `fixture "users.csv": table "users" was not found`)
+}
+
+func TestSchema_Bundle(t *testing.T) {
+	u := openSQLite(t, "CREATE TABLE users (id int PRIMARY KEY, name text DEFAULT 'anonymous' CHECK (name <> 'admin')); CREATE TABLE posts (id int, author_id int REFERENCES users(id)); CREATE INDEX author_idx ON posts(author_id);")
+	s, err := runCmd(schemaBundleCmd(), "-u", u, "--salt", "secret")
+	require.NoError(t, err)
+	for _, n := range []string{"users", "posts", "author_id", "author_idx", "anonymous", "admin"} {
+		require.NotContains(t, s, n)
+	}
+	a := newAnonymizer("secret")
+	require.Contains(t, s, fmt.Sprintf("table %q {", a.name("users")))
+	require.Contains(t, s, fmt.Sprintf("table.%s.column.%s", a.name("users"), a.name("id")))
+	require.Contains(t, s, a.name("anonymous"))
+	require.True(t, strings.HasPrefix(s, "# Anonymized schema of a sqlite database"))
+
+	// Pseudonyms are consistent for the same salt.
+	s2, err := runCmd(schemaBundleCmd(), "-u", u, "--salt", "secret")
+	require.NoError(t, err)
+	require.Equal(t, s, s2)
+	s2, err = runCmd(schemaBundleCmd(), "-u", u)
+	require.NoError(t, err)
+	require.NotEqual(t, s, s2)
+
+	a.names["users"] = true
+	require.Equal(t, fmt.Sprintf("SELECT * FROM %s WHERE \"%s\" = '%s'", a.name("users"), a.name("id"), a.name("x")), a.expr(`SELECT * FROM users WHERE "id" = 'x'`))
+}
+
+func TestSchema_BundleDriverAttrs(t *testing.T) {
+	t.Run("Postgres", func(t *testing.T) {
+		status := schema.NewStringColumn("secret_status", "text")
+		tbl := schema.NewTable("customers").
+			AddColumns(schema.NewIntColumn("id", "int"), status).
+			AddAttrs(
+				&postgres.Policy{
+					Name:  "tenant_isolation",
+					To:    []string{"billing_role", "PUBLIC"},
+					Using: "(secret_status = current_setting('app.tenant'))",
+					Check: "(secret_status <> 'blocked_tenant'::text)",
+				},
+				&postgres.Partition{T: "LIST", Parts: []*postgres.PartitionPart{{X: &schema.RawExpr{X: "lower(secret_status)"}}}},
+				&postgres.Owner{Name: "billing_role"},
+			)
+		tbl.Attrs[0].(*postgres.Policy).Table = tbl
+		tbl.AddIndexes(
+			schema.NewIndex("vip_idx").
+				AddColumns(tbl.Columns[0]).
+				AddAttrs(&postgres.IndexPredicate{P: "(secret_status = 'vip_customer'::text)"}),
+			schema.NewIndex("no_overlap_excl").
+				AddColumns(status).
+				AddAttrs(&postgres.Constraint{N: "no_overlap_excl", T: "x"}),
+		)
+		r := schema.NewRealm(schema.New("billing").AddTables(tbl))
+		newAnonymizer("secret").realm(r)
+		buf, err := postgres.MarshalHCL(r)
+		require.NoError(t, err)
+		for _, n := range []string{"billing", "customers", "secret_status", "tenant_isolation", "billing_role", "app.tenant", "blocked_tenant", "vip_customer", "vip_idx", "no_overlap_excl"} {
+			require.NotContains(t, string(buf), n)
+		}
+		require.Contains(t, string(buf), "PUBLIC")
+	})
+	t.Run("SQLite", func(t *testing.T) {
+		tbl := schema.NewTable("customers").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("secret_status", "text")).
+			AddAttrs(&sqlite.CreateStmt{S: "CREATE TABLE customers (id int, secret_status text)"})
+		tbl.AddIndexes(
+			schema.NewIndex("vip_idx").
+				AddColumns(tbl.Columns[0]).
+				AddAttrs(&sqlite.IndexPredicate{P: "secret_status = 'vip_customer'"}),
+		)
+		r := schema.NewRealm(schema.New("main").AddTables(tbl))
+		newAnonymizer("secret").realm(r)
+		buf, err := sqlite.MarshalHCL(r)
+		require.NoError(t, err)
+		for _, n := range []string{"customers", "secret_status", "vip_customer", "vip_idx"} {
+			require.NotContains(t, string(buf), n)
+		}
+	})
+}
+
+func TestSchema_Generate(t *testing.T) {
+	u := openSQLite(t, "CREATE TABLE users (id int NOT NULL PRIMARY KEY, user_name text);")
+	s, err := runCmd(schemaGenerateCmd(), "-u", u, "--template", "gorm", "--package", "db")
+	require.NoError(t, err)
+	require.Contains(t, s, "package db\n")
+	require.Contains(t, s, "type User struct {\n\tID       int64   `gorm:\"column:id;primaryKey;not null\"`\n\tUserName *string `gorm:\"column:user_name\"`\n}")
+
+	out := filepath.Join(t.TempDir(), "models", "models.go")
+	s, err = runCmd(schemaGenerateCmd(), "-u", u, "--out", out)
+	require.NoError(t, err)
+	require.Empty(t, s)
+	b, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "\tUserName *string `db:\"user_name\"`\n")
+
+	_, err = runCmd(schemaGenerateCmd(), "-u", u, "--template", "unknown")
+	require.ErrorContains(t, err, `template "unknown" is not one of`)
+}
+
+func TestSchema_Test(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		tests  = filepath.Join(dir, "tbl.test.hcl")
+		failed = filepath.Join(dir, "failed.test.hcl")
+		devURL = "sqlite://dev?mode=memory&_fk=1"
+	)
+	require.NoError(t, os.WriteFile(tests, []byte(`
+test "schema" "tbl" {
+  table "tbl" {
+    columns = ["col", "col_2"]
+  }
+  exec {
+    sql = "INSERT INTO tbl (col, col_2) VALUES (1, 2)"
+  }
+  exec {
+    sql    = "SELECT col, col_2 FROM tbl"
+    output = "1, 2"
+  }
+  assert {
+    sql = "SELECT COUNT(*) = 1 FROM tbl"
+  }
+  catch {
+    sql   = "INSERT INTO tbl (col_2) VALUES (1)"
+    error = "NOT NULL constraint failed"
+  }
+}
+
+test "schema" "isolated" {
+  assert {
+    sql           = "SELECT COUNT(*) = 0 FROM tbl"
+    error_message = "expect no rows"
+  }
+}
+`), 0644))
+	s, err := runCmd(schemaTestCmd(), "--dir", "file://testdata/sqlite", "--dev-url", devURL, tests)
+	require.NoError(t, err)
+	require.Regexp(t, `^--- PASS: tbl \(.+\)\n--- PASS: isolated \(.+\)\nPASS\n$`, s)
+
+	schemaFile := filepath.Join(dir, "schema.sql")
+	require.NoError(t, os.WriteFile(schemaFile, []byte("CREATE TABLE tbl (`col` int NOT NULL, `col_2` bigint);"), 0644))
+	s, err = runCmd(schemaTestCmd(), "--url", "file://"+schemaFile, "--dev-url", devURL, "--run", "^tbl$", dir)
+	require.NoError(t, err)
+	require.Regexp(t, `^--- PASS: tbl \(.+\)\nPASS\n$`, s)
+
+	require.NoError(t, os.WriteFile(failed, []byte(`
+test "schema" "failed" {
+  exec {
+    sql    = "SELECT COUNT(*) FROM tbl"
+    output = "1"
+  }
+}
+
+test "schema" "skipped" {
+  skip = true
+}
+`), 0644))
+	s, err = runCmd(schemaTestCmd(), "--url", "file://"+schemaFile, "--dev-url", devURL, failed)
+	require.EqualError(t, err, "1 of 1 tests failed")
+	require.Contains(t, s, "--- FAIL: failed")
+	require.Contains(t, s, failed+`:3: exec "SELECT COUNT(*) FROM tbl": unexpected output:`)
+	require.Contains(t, s, "--- SKIP: skipped\nFAIL\n")
+
+	_, err = runCmd(schemaTestCmd(), "--dev-url", devURL, tests)
+	require.EqualError(t, err, `one of the flags "url" or "dir" must be set`)
+}
+
+func TestWatchRun(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		cmd  = &cobra.Command{}
+		runs = make(chan struct{}, 10)
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd.SetContext(ctx)
+	cmd.SetErr(io.Discard)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.sql"), []byte("CREATE TABLE t1 (c int);"), 0644))
+	err := watchRun(cmd, []string{"mysql://localhost"}, nil)
+	require.EqualError(t, err, "--watch requires the desired state to be local files or directories (file://)")
+
+	done := make(chan error)
+	go func() {
+		done <- watchRun(cmd, []string{"file://" + dir}, func() error {
+			runs <- struct{}{}
+			return nil
+		})
+	}()
+	wait := func() {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expect command to run")
+		}
+	}
+	wait() // Initial run.
+	// Files other than HCL or SQL are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Schema"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.sql"), []byte("CREATE TABLE t2 (c int);"), 0644))
+	wait()
+	require.Empty(t, runs)
+	cancel()
+	require.NoError(t, <-done)
+}
+
 func assertDir(t *testing.T, dir string, expected map[string]string) {
 	act := make(map[string]string)
 	files, err := os.ReadDir(dir)
@@ -1169,3 +1431,89 @@ func (d *assertNormalizerDriver) NormalizeRealm(context.Context, *schema.Realm)
 	d.t.Fatal("did not expect a call to NormalizeRealm")
 	return nil, nil
 }
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, fmt.Sprintf("sqlite://file:%s?cache=shared&_fk=1", filepath.Join(t.TempDir(), "test.db")))
+	require.NoError(t, err)
+	defer c.Close()
+	plan := &migrate.Plan{
+		Changes: []*migrate.Change{
+			{Cmd: "CREATE TABLE `t1` (`id` int NOT NULL)"},
+			{Cmd: "CREATE TABLE `t2` (`id` int NOT NULL)"},
+		},
+	}
+	require.NoError(t, applyPlan(ctx, c, plan, txModeFile))
+	s, err := c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+
+	// Failed statements are rolled back in file mode.
+	plan.Changes = []*migrate.Change{
+		{Cmd: "CREATE TABLE `t3` (`id` int NOT NULL)"},
+		{Cmd: "CREATE TABLE `t1` (`id` int NOT NULL)"},
+	}
+	err = applyPlan(ctx, c, plan, txModeFile)
+	require.ErrorContains(t, err, "executing statement \"CREATE TABLE `t1` (`id` int NOT NULL)\"")
+	s, err = c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 2)
+}
+
+func TestSchema_Drift(t *testing.T) {
+	var (
+		fe   *FormattedError
+		seed = "create table users (id int);"
+	)
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaDriftCmd())
+	s, err := runCmd(
+		cmd, "drift",
+		"--url", openSQLite(t, seed),
+		"--to", openSQLite(t, seed),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "No drift detected, the database is in sync with its desired state\n", s)
+
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaDriftCmd())
+	s, err = runCmd(
+		cmd, "drift",
+		"--url", openSQLite(t, seed),
+		"--to", openSQLite(t, ""),
+	)
+	require.ErrorAs(t, err, &fe)
+	require.Equal(t, 2, fe.ExitCode())
+	require.True(t, strings.HasPrefix(s, "Schema drift detected"))
+	require.Contains(t, s, "DROP TABLE `users`;")
+
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaDriftCmd())
+	s, err = runCmd(
+		cmd, "drift",
+		"--url", openSQLite(t, seed),
+		"--to", openSQLite(t, ""),
+		"--format", "{{ json . }}",
+	)
+	require.ErrorAs(t, err, &fe)
+	require.Equal(t, 2, fe.ExitCode())
+	var report struct {
+		Drift   bool
+		Changes struct{ Pending []string }
+	}
+	require.NoError(t, json.Unmarshal([]byte(s), &report))
+	require.True(t, report.Drift)
+	// SQLite disables foreign key checks when dropping tables.
+	require.Equal(t, []string{"PRAGMA foreign_keys = off", "DROP TABLE `users`", "PRAGMA foreign_keys = on"}, report.Changes.Pending)
+
+	cmd = schemaCmd()
+	cmd.AddCommand(schemaDriftCmd())
+	s, err = runCmd(
+		cmd, "drift",
+		"--url", openSQLite(t, seed),
+		"--to", "file://"+filepath.Join(t.TempDir(), "missing.hcl"),
+	)
+	require.ErrorAs(t, err, &fe)
+	require.Equal(t, 1, fe.ExitCode())
+	require.True(t, strings.HasPrefix(s, "Failed checking for drift:"))
+}
@@ -0,0 +1,169 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package cmdevent writes the structured events of Atlas commands as
+// line-delimited JSON, allowing log pipelines to consume the progress
+// and the errors of commands without parsing their human-readable output.
+package cmdevent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Events emitted by Atlas commands.
+const (
+	CommandStarted  = "command.started"
+	CommandFinished = "command.finished"
+	CommandFailed   = "command.failed"
+	PlanStarted     = "plan.started"
+	FileStarted     = "file.started"
+	StmtApplied     = "statement.applied"
+	StmtFailed      = "statement.failed"
+	ExecFailed      = "execution.failed"
+)
+
+// Logger writes events as JSON objects, one per line. Each event holds the time it
+// was emitted at, its level, its name and the command that emitted it, in addition
+// to its own attributes. A nil Logger is valid and discards all events.
+type Logger struct {
+	l *slog.Logger
+}
+
+// New returns a Logger that writes the events of the given command to w.
+func New(w io.Writer, command string) *Logger {
+	h := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.MessageKey:
+				a.Key = "event"
+			case slog.LevelKey:
+				a.Value = slog.StringValue(strings.ToLower(a.Value.String()))
+			}
+			return a
+		},
+	})
+	return &Logger{l: slog.New(h).With("command", command)}
+}
+
+type loggerCtxKey struct{}
+
+// NewContext returns a new context with the given Logger attached.
+func NewContext(parent context.Context, l *Logger) context.Context {
+	return context.WithValue(parent, loggerCtxKey{}, l)
+}
+
+// FromContext returns a Logger stored inside a context, or nil if there isn't one.
+func FromContext(ctx context.Context) *Logger {
+	l, _ := ctx.Value(loggerCtxKey{}).(*Logger)
+	return l
+}
+
+// Info emits an informational event with the given key-value attributes.
+func (l *Logger) Info(event string, args ...any) {
+	if l != nil {
+		l.l.Info(event, args...)
+	}
+}
+
+// Error emits an error event with the given key-value attributes. The error
+// message is recorded under the "error" key, and its SQLSTATE code, if it
+// was returned by the database, under the "sqlstate" key.
+func (l *Logger) Error(event string, err error, args ...any) {
+	if l == nil {
+		return
+	}
+	args = append(args, "error", err.Error())
+	if s := SQLState(err); s != "" {
+		args = append(args, "sqlstate", s)
+	}
+	l.l.Error(event, args...)
+}
+
+// SQLState returns the SQLSTATE code of the given database error,
+// or an empty string if the error does not carry one.
+func SQLState(err error) string {
+	var s interface{ SQLState() string }
+	if errors.As(err, &s) {
+		return s.SQLState()
+	}
+	var m *mysql.MySQLError
+	if errors.As(err, &m) && m.SQLState != [5]byte{} {
+		return string(m.SQLState[:])
+	}
+	return ""
+}
+
+// Duration returns the attribute of the duration since the given time.
+func Duration(start time.Time) slog.Attr {
+	return slog.Int64("duration_ms", time.Since(start).Milliseconds())
+}
+
+// MigrateLogger returns a migrate.Logger that emits the events of the migration
+// execution, and passes the log entries to the given logger. If l is nil, next
+// is returned as is.
+func (l *Logger) MigrateLogger(next migrate.Logger) migrate.Logger {
+	if l == nil {
+		return next
+	}
+	return &migrateLogger{Logger: l, next: next}
+}
+
+// migrateLogger emits the events of a migration execution. Statements are logged
+// by the executor before they are executed. Hence, a statement is reported as
+// applied only when the next entry is not an error caused by it.
+type migrateLogger struct {
+	*Logger
+	next  migrate.Logger
+	file  string
+	stmt  string
+	start time.Time
+}
+
+// Log implements the migrate.Logger interface.
+func (m *migrateLogger) Log(e migrate.LogEntry) {
+	m.next.Log(e)
+	if le, ok := e.(migrate.LogError); !ok || le.SQL == "" {
+		m.applied()
+	}
+	switch e := e.(type) {
+	case migrate.LogExecution:
+		files := make([]string, len(e.Files))
+		for i, f := range e.Files {
+			files[i] = f.Name()
+		}
+		m.Info(PlanStarted, "from", e.From, "to", e.To, "files", files)
+	case migrate.LogFile:
+		m.file = e.File.Name()
+		m.Info(FileStarted, "file", m.file, "version", e.File.Version(), "skip", e.Skip)
+	case migrate.LogStmt:
+		m.stmt, m.start = e.SQL, time.Now()
+	case migrate.LogError:
+		m.stmt = ""
+		if e.SQL == "" {
+			m.Error(ExecFailed, e.Error, "file", m.file)
+		} else {
+			m.Error(StmtFailed, e.Error, "file", m.file, "stmt", e.SQL)
+		}
+	}
+}
+
+// applied reports the pending statement as applied, if there is one.
+func (m *migrateLogger) applied() {
+	if m.stmt != "" {
+		m.Info(StmtApplied, "file", m.file, "stmt", m.stmt, Duration(m.start))
+		m.stmt = ""
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdevent_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/cmd/atlas/internal/cmdevent"
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	var (
+		b bytes.Buffer
+		l = cmdevent.New(&b, "schema apply")
+	)
+	l.Info(cmdevent.PlanStarted, "statements", 2)
+	l.Error(cmdevent.CommandFailed, fmt.Errorf("executing statement: %w", &pq.Error{Code: "42P07", Message: `relation "t" already exists`}))
+	events := decode(t, &b)
+	require.Len(t, events, 2)
+	require.NotEmpty(t, events[0]["time"])
+	delete(events[0], "time")
+	require.Equal(t, map[string]any{"level": "info", "event": "plan.started", "command": "schema apply", "statements": float64(2)}, events[0])
+	require.Equal(t, "error", events[1]["level"])
+	require.Equal(t, "42P07", events[1]["sqlstate"])
+	require.Equal(t, `executing statement: pq: relation "t" already exists (42P07)`, events[1]["error"])
+
+	// Nil loggers discard events.
+	var nl *cmdevent.Logger
+	nl.Info(cmdevent.PlanStarted)
+	nl.Error(cmdevent.CommandFailed, errors.New("error"))
+	ctx := context.Background()
+	require.Nil(t, cmdevent.FromContext(ctx))
+	require.Equal(t, l, cmdevent.FromContext(cmdevent.NewContext(ctx, l)))
+	next := migrate.NopLogger{}
+	require.Equal(t, next, nl.MigrateLogger(next))
+}
+
+func TestSQLState(t *testing.T) {
+	require.Equal(t, "42P07", cmdevent.SQLState(&pq.Error{Code: "42P07"}))
+	require.Equal(t, "42S01", cmdevent.SQLState(fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1050, SQLState: [5]byte{'4', '2', 'S', '0', '1'}})))
+	require.Empty(t, cmdevent.SQLState(&mysql.MySQLError{Number: 1050}))
+	require.Empty(t, cmdevent.SQLState(errors.New("error")))
+}
+
+func TestMigrateLogger(t *testing.T) {
+	var (
+		b    bytes.Buffer
+		next = &entries{}
+		l    = cmdevent.New(&b, "migrate apply").MigrateLogger(next)
+		f1   = migrate.NewLocalFile("1_init.sql", []byte("CREATE TABLE t(c int);\nCREATE TABLE t2(c int);"))
+		f2   = migrate.NewLocalFile("2_fail.sql", []byte("CREATE TABLE t(c int);"))
+	)
+	for _, e := range []migrate.LogEntry{
+		migrate.LogExecution{To: "2", Files: []migrate.File{f1, f2}},
+		migrate.LogFile{File: f1},
+		migrate.LogStmt{SQL: "CREATE TABLE t(c int);"},
+		migrate.LogStmt{SQL: "CREATE TABLE t2(c int);"},
+		migrate.LogFile{File: f2},
+		migrate.LogStmt{SQL: "CREATE TABLE t(c int);"},
+		migrate.LogError{SQL: "CREATE TABLE t(c int);", Error: &mysql.MySQLError{Number: 1050, SQLState: [5]byte{'4', '2', 'S', '0', '1'}, Message: "Table 't' already exists"}},
+		migrate.LogDone{},
+	} {
+		l.Log(e)
+	}
+	require.Len(t, *next, 8)
+	events := decode(t, &b)
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e["event"].(string)
+	}
+	require.Equal(t, []string{"plan.started", "file.started", "statement.applied", "statement.applied", "file.started", "statement.failed"}, names)
+	require.Equal(t, []any{"1_init.sql", "2_fail.sql"}, events[0]["files"])
+	require.Equal(t, "CREATE TABLE t2(c int);", events[3]["stmt"])
+	require.Equal(t, "1_init.sql", events[3]["file"])
+	require.Equal(t, "2_fail.sql", events[5]["file"])
+	require.Equal(t, "42S01", events[5]["sqlstate"])
+	require.Equal(t, "Error 1050 (42S01): Table 't' already exists", events[5]["error"])
+}
+
+type entries []migrate.LogEntry
+
+func (e *entries) Log(l migrate.LogEntry) { *e = append(*e, l) }
+
+func decode(t *testing.T, b *bytes.Buffer) []map[string]any {
+	var events []map[string]any
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		var e map[string]any
+		require.NoError(t, dec.Decode(&e))
+		events = append(events, e)
+	}
+	return events
+}
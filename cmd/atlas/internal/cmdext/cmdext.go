@@ -477,7 +477,7 @@ func TemplateDir(_ context.Context, ectx *hcl.EvalContext, block *hclsyntax.Bloc
 	u := fmt.Sprintf("mem://%s", dirname)
 	// Allow using reading the computed dir as a state source.
 	memLoader.states[u] = StateLoaderFunc(func(ctx context.Context, config *StateReaderConfig) (*StateReadCloser, error) {
-		return stateReaderSQL(ctx, config, dir, nil, nil)
+		return stateReaderSQL(ctx, config, dir, nil, "")
 	})
 	return cty.ObjectVal(map[string]cty.Value{
 		"url": cty.StringVal(u),
@@ -709,14 +709,10 @@ func StateReaderSQL(ctx context.Context, config *StateReaderConfig) (*StateReadC
 		return stateSchemaSQL(ctx, config, dir)
 	// A migration directory.
 	default:
-		var opts []migrate.ReplayOption
 		if dir, err = cmdmigrate.DirURL(ctx, config.URLs[0], false); err != nil {
 			return nil, err
 		}
-		if v := config.URLs[0].Query().Get("version"); v != "" {
-			opts = append(opts, migrate.ReplayToVersion(v))
-		}
-		return stateReaderSQL(ctx, config, dir, nil, opts)
+		return stateReaderSQL(ctx, config, dir, nil, config.URLs[0].Query().Get("version"))
 	}
 }
 
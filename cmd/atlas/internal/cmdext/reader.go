@@ -6,6 +6,8 @@ package cmdext
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -73,7 +75,7 @@ func stateSchemaSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir
 		return nil, errNoDevURL
 	}
 	log := &errorRecorder{}
-	r, err := stateReaderSQL(ctx, cfg, dir, []migrate.ExecutorOption{migrate.WithLogger(log)}, nil)
+	r, err := stateReaderSQL(ctx, cfg, dir, []migrate.ExecutorOption{migrate.WithLogger(log)}, "")
 	if n := len(log.applied); err != nil && n > 0 && log.stmt != "" && log.text != "" {
 		err = fmt.Errorf("read state from %q: executing statement: %q: %s", log.applied[n-1], log.stmt, log.text)
 	}
@@ -97,7 +99,7 @@ func (r *errorRecorder) Log(e migrate.LogEntry) {
 }
 
 // stateReaderSQL returns a migrate.StateReader from an SQL file or a directory of migrations.
-func stateReaderSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir, optsExec []migrate.ExecutorOption, optsReplay []migrate.ReplayOption) (*StateReadCloser, error) {
+func stateReaderSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir, optsExec []migrate.ExecutorOption, version string) (*StateReadCloser, error) {
 	if cfg.Dev == nil {
 		return nil, errNoDevURL
 	}
@@ -105,7 +107,7 @@ func stateReaderSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir
 	if err != nil {
 		return nil, err
 	}
-	sr, err := ex.Replay(ctx, func() migrate.StateReader {
+	r := func() migrate.StateReader {
 		if cfg.Dev.URL.Schema != "" {
 			return migrate.SchemaConn(cfg.Dev, "", &schema.InspectOptions{
 				Exclude: cfg.Exclude,
@@ -117,7 +119,19 @@ func stateReaderSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir
 			Exclude: cfg.Exclude,
 			Include: cfg.Include,
 		})
-	}(), optsReplay...)
+	}()
+	var optsReplay []migrate.ReplayOption
+	if version != "" {
+		optsReplay = append(optsReplay, migrate.ReplayToVersion(version))
+	}
+	var sr *schema.Realm
+	// Executor options (e.g., logging) require the
+	// directory to be executed, and cannot be cached.
+	if s, ok := cfg.Dev.Driver.(replaySnapshotter); ok && len(optsExec) == 0 {
+		sr, err = replayCached(ctx, s, ex, dir, r, version, optsReplay)
+	} else {
+		sr, err = ex.Replay(ctx, r, optsReplay...)
+	}
 	if err != nil && !errors.Is(err, migrate.ErrNoPendingFiles) {
 		return nil, err
 	}
@@ -127,6 +141,74 @@ func stateReaderSQL(ctx context.Context, cfg *StateReaderConfig, dir migrate.Dir
 	}, nil
 }
 
+// replaySnapshotter is implemented by dev drivers that can save the state
+// of a replayed migration directory to a file and load it back. e.g., SQLite.
+type replaySnapshotter interface {
+	migrate.Snapshoter
+	SaveSnapshot(context.Context, string) error
+	LoadSnapshot(context.Context, string) error
+}
+
+// replayCached loads the state of the migration directory from its cached snapshot, if exists.
+// Otherwise, the directory is replayed and its state is saved to the cache for the next runs.
+// Caching is best-effort, and failures fall back to replaying the directory as usual.
+func replayCached(ctx context.Context, s replaySnapshotter, ex *migrate.Executor, dir migrate.Dir, r migrate.StateReader, version string, opts []migrate.ReplayOption) (*schema.Realm, error) {
+	path, err := replayCachePath(dir, version)
+	if err != nil {
+		return ex.Replay(ctx, r, opts...)
+	}
+	if _, err := os.Stat(path); err == nil {
+		restore, err := s.Snapshot(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("taking database snapshot: %w", err)
+		}
+		if err := s.LoadSnapshot(ctx, path); err == nil {
+			sr, err := r.ReadState(ctx)
+			return sr, errors.Join(err, restore(ctx))
+		}
+		// Restore the database to its clean state, and replay the directory.
+		if err := restore(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return ex.Replay(ctx, migrate.StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
+		sr, err := r.ReadState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// The snapshot is written to a temporary file first, to ensure
+		// concurrent runs never observe a partially written snapshot.
+		tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+		if err := s.SaveSnapshot(ctx, tmp); err == nil {
+			_ = os.Rename(tmp, path)
+		}
+		_ = os.Remove(tmp)
+		return sr, nil
+	}), opts...)
+}
+
+// replayCachePath returns the path of the cached snapshot of the migration
+// directory. The key is derived from the directory checksum and the version.
+func replayCachePath(dir migrate.Dir, version string) (string, error) {
+	sum, err := dir.Checksum()
+	if err != nil {
+		return "", err
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	cache = filepath.Join(cache, "atlas", "replay")
+	if err := os.MkdirAll(cache, 0755); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(sum.Sum()))
+	h.Write([]byte{0})
+	h.Write([]byte(version))
+	return filepath.Join(cache, hex.EncodeToString(h.Sum(nil))+".db"), nil
+}
+
 // StateReaderHCL returns a StateReader that reads the state from the given HCL paths urls.
 func StateReaderHCL(ctx context.Context, c *StateReaderConfig) (*StateReadCloser, error) {
 	paths := make([]string, len(c.URLs))
@@ -0,0 +1,246 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// Data is the data passed to the generation templates.
+	Data struct {
+		Package string        // Package name of the generated file.
+		Imports []string      // Imports required by the field types.
+		Tables  []*Table      // Tables and their Go structs.
+		Realm   *schema.Realm // The realm the code is generated from.
+	}
+
+	// Table represents a table and its Go struct.
+	Table struct {
+		*schema.Table
+		GoName string   // Go name of the struct. e.g., User.
+		Fields []*Field // Fields of the struct.
+	}
+
+	// Field represents a column and its struct field.
+	Field struct {
+		*schema.Column
+		GoName string // Go name of the field. e.g., UserID.
+		GoType string // Go type of the field. e.g., *time.Time.
+		PK     bool   // Reports if the column is part of the primary key.
+	}
+)
+
+// Templates lists the names of the builtin templates.
+var Templates = []string{"gorm", "sqlc", "sqlx"}
+
+var (
+	// Funcs are the functions available to the generation templates.
+	Funcs = template.FuncMap{
+		"goName":   GoName,
+		"singular": singular,
+		"lower":    strings.ToLower,
+	}
+
+	header = `{{ define "header" -}}
+// Code generated by atlas. DO NOT EDIT.
+
+package {{ $.Package }}
+{{ with $.Imports }}
+import (
+	{{- range . }}
+	"{{ . }}"
+	{{- end }}
+)
+{{ end }}
+{{- end }}`
+
+	builtins = map[string]string{
+		"gorm": `{{ template "header" $ }}
+{{- range $t := $.Tables }}
+// {{ $t.GoName }} represents the "{{ $t.Name }}" table.
+type {{ $t.GoName }} struct {
+	{{- range $f := $t.Fields }}
+	{{ $f.GoName }} {{ $f.GoType }} ` + "`" + `gorm:"column:{{ $f.Name }}{{ if $f.PK }};primaryKey{{ end }}{{ if not $f.Type.Null }};not null{{ end }}"` + "`" + `
+	{{- end }}
+}
+
+// TableName returns the table name of {{ $t.GoName }}.
+func ({{ $t.GoName }}) TableName() string {
+	return "{{ $t.Name }}"
+}
+{{ end }}`,
+		"sqlc": `{{ template "header" $ }}
+{{- range $t := $.Tables }}
+type {{ $t.GoName }} struct {
+	{{- range $f := $t.Fields }}
+	{{ $f.GoName }} {{ $f.GoType }} ` + "`" + `db:"{{ $f.Name }}" json:"{{ $f.Name }}"` + "`" + `
+	{{- end }}
+}
+{{ end }}`,
+		"sqlx": `{{ template "header" $ }}
+{{- range $t := $.Tables }}
+// {{ $t.GoName }} represents a row of the "{{ $t.Name }}" table.
+type {{ $t.GoName }} struct {
+	{{- range $f := $t.Fields }}
+	{{ $f.GoName }} {{ $f.GoType }} ` + "`" + `db:"{{ $f.Name }}"` + "`" + `
+	{{- end }}
+}
+{{ end }}`,
+	}
+)
+
+// Template returns the builtin template with the given name, or
+// parses the template file at the given path (e.g., file://models.tmpl).
+// The "header" template, that writes the package clause and the imports,
+// is available to all templates.
+func Template(name string) (*template.Template, error) {
+	t, err := template.New("header").Funcs(Funcs).Parse(header)
+	if err != nil {
+		return nil, err
+	}
+	text, ok := builtins[name]
+	if !ok {
+		path := strings.TrimPrefix(name, "file://")
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cmdgen: template %q is not one of %q, or a template file: %w", name, Templates, err)
+		}
+		name, text = path, string(b)
+	}
+	if t, err = t.New(name).Parse(text); err != nil {
+		return nil, fmt.Errorf("cmdgen: parse template %q: %w", name, err)
+	}
+	return t, nil
+}
+
+// Generate executes the template on the tables of the given realm,
+// and returns the generated Go source code formatted.
+func Generate(tmpl *template.Template, r *schema.Realm, pkg string) ([]byte, error) {
+	data := &Data{Package: pkg, Realm: r}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			gt := &Table{Table: t, GoName: GoName(singular(t.Name))}
+			// Qualify the struct names in case there are multiple schemas.
+			if len(r.Schemas) > 1 {
+				gt.GoName = GoName(s.Name) + gt.GoName
+			}
+			for _, c := range t.Columns {
+				typ, imp := goType(c)
+				if imp != "" && !slices.Contains(data.Imports, imp) {
+					data.Imports = append(data.Imports, imp)
+				}
+				gt.Fields = append(gt.Fields, &Field{
+					Column: c,
+					GoName: GoName(c.Name),
+					GoType: typ,
+					PK:     isPK(t, c),
+				})
+			}
+			data.Tables = append(data.Tables, gt)
+		}
+	}
+	slices.Sort(data.Imports)
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("cmdgen: execute template: %w", err)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("cmdgen: format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// goType returns the Go type of the column, and the package it requires, if any.
+// Nullable columns are represented as pointers, unless their type is nilable.
+func goType(c *schema.Column) (string, string) {
+	var typ, imp string
+	switch t := c.Type.Type.(type) {
+	case *schema.BoolType:
+		typ = "bool"
+	case *schema.IntegerType:
+		typ = "int64"
+		if t.Unsigned {
+			typ = "uint64"
+		}
+	case *schema.FloatType:
+		typ = "float64"
+	case *schema.StringType, *schema.EnumType, *schema.UUIDType, *schema.DecimalType:
+		// Decimals are represented as strings to avoid precision loss.
+		typ = "string"
+	case *schema.TimeType:
+		typ, imp = "time.Time", "time"
+	case *schema.BinaryType, *schema.SpatialType:
+		return "[]byte", ""
+	case *schema.JSONType:
+		return "json.RawMessage", "encoding/json"
+	default:
+		return "any", ""
+	}
+	if c.Type.Null {
+		typ = "*" + typ
+	}
+	return typ, imp
+}
+
+// isPK reports if the column is part of the table primary key.
+func isPK(t *schema.Table, c *schema.Column) bool {
+	if t.PrimaryKey == nil {
+		return false
+	}
+	return slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool {
+		return p.C == c
+	})
+}
+
+// initialisms are the common Go initialisms used in field names.
+var initialisms = []string{"API", "HTML", "HTTP", "ID", "IP", "JSON", "SQL", "URL", "UUID", "XML"}
+
+// GoName converts a database identifier to an exported Go name.
+// For example, "user_id" is converted to "UserID".
+func GoName(s string) string {
+	var b strings.Builder
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if u := strings.ToUpper(w); slices.Contains(initialisms, u) {
+			b.WriteString(u)
+			continue
+		}
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "T" + name
+	}
+	return name
+}
+
+// singular returns the naive singular form of the given table name.
+// For example, "users" is converted to "user", and "categories" to "category".
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") && len(s) > 1:
+		return s[:len(s)-1]
+	default:
+		return s
+	}
+}
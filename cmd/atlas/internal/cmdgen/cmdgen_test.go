@@ -0,0 +1,96 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdgen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/cmd/atlas/internal/cmdgen"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	var (
+		id    = schema.NewIntColumn("id", "bigint")
+		users = schema.NewTable("users").
+			AddColumns(
+				id,
+				schema.NewNullStringColumn("user_name", "text"),
+				schema.NewTimeColumn("created_at", "timestamp"),
+				schema.NewNullColumn("data").SetType(&schema.JSONType{T: "json"}),
+			)
+		r = schema.NewRealm(schema.New("public").AddTables(users))
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(id))
+
+	tmpl, err := cmdgen.Template("gorm")
+	require.NoError(t, err)
+	b, err := cmdgen.Generate(tmpl, r, "models")
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by atlas. DO NOT EDIT.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User represents the "users" table.
+type User struct {
+	ID        int64           `+"`"+`gorm:"column:id;primaryKey;not null"`+"`"+`
+	UserName  *string         `+"`"+`gorm:"column:user_name"`+"`"+`
+	CreatedAt time.Time       `+"`"+`gorm:"column:created_at;not null"`+"`"+`
+	Data      json.RawMessage `+"`"+`gorm:"column:data"`+"`"+`
+}
+
+// TableName returns the table name of User.
+func (User) TableName() string {
+	return "users"
+}
+`, string(b))
+
+	tmpl, err = cmdgen.Template("sqlx")
+	require.NoError(t, err)
+	b, err = cmdgen.Generate(tmpl, r, "models")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "\tUserName  *string         `db:\"user_name\"`\n")
+
+	tmpl, err = cmdgen.Template("sqlc")
+	require.NoError(t, err)
+	b, err = cmdgen.Generate(tmpl, r, "db")
+	require.NoError(t, err)
+	require.Contains(t, string(b), "\tCreatedAt time.Time       `db:\"created_at\" json:\"created_at\"`\n")
+
+	// Custom templates.
+	path := filepath.Join(t.TempDir(), "names.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{ template "header" $ }}
+var Tables = []string{ {{- range $.Tables }}"{{ .GoName }}",{{ end }} }
+`), 0644))
+	tmpl, err = cmdgen.Template("file://" + path)
+	require.NoError(t, err)
+	b, err = cmdgen.Generate(tmpl, r, "names")
+	require.NoError(t, err)
+	require.Contains(t, string(b), `var Tables = []string{"User"}`)
+
+	_, err = cmdgen.Template("unknown")
+	require.ErrorContains(t, err, `cmdgen: template "unknown" is not one of ["gorm" "sqlc" "sqlx"]`)
+}
+
+func TestGoName(t *testing.T) {
+	for s, name := range map[string]string{
+		"id":          "ID",
+		"user_id":     "UserID",
+		"api-key":     "APIKey",
+		"createdAt":   "CreatedAt",
+		"2fa_enabled": "T2faEnabled",
+	} {
+		require.Equal(t, name, cmdgen.GoName(s))
+	}
+}
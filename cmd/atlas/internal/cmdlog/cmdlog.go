@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/url"
 	"reflect"
@@ -18,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"text/template"
 	"time"
 
@@ -300,6 +302,117 @@ const (
 	statusPending = "PENDING"
 )
 
+// MigrateHistory contains the history of migrations executed on a database.
+type MigrateHistory struct {
+	Env       `json:"Env"`
+	Revisions []*migrate.Revision `json:"Revisions,omitempty"` // Revisions ordered by their version
+}
+
+var (
+	// HistoryTemplateFuncs are global functions available in history report templates.
+	HistoryTemplateFuncs = WithColorFuncs(template.FuncMap{
+		"json":       jsonEncode,
+		"json_merge": jsonMerge,
+		"status":     revisionStatus,
+		"default":    historyDefault,
+	})
+
+	// MigrateHistoryTemplate holds the default template of the 'migrate history' command.
+	MigrateHistoryTemplate = template.Must(template.New("history").Funcs(HistoryTemplateFuncs).Parse("{{ default . }}"))
+)
+
+// revisionStatus returns the status of the revision: OK if it was fully
+// applied (or resolved manually), ERROR if it failed, and PARTIAL otherwise.
+func revisionStatus(r *migrate.Revision) string {
+	switch {
+	case r.Type.Has(migrate.RevisionTypeResolved), r.Error == "" && r.Applied == r.Total:
+		return statusOK
+	case r.Error != "":
+		return "ERROR"
+	default:
+		return "PARTIAL"
+	}
+}
+
+// historyDefault renders the revisions as a table, one row per revision.
+func historyDefault(report *MigrateHistory) (string, error) {
+	if len(report.Revisions) == 0 {
+		return "No migrations were executed on the database\n", nil
+	}
+	var (
+		b strings.Builder
+		w = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	)
+	fmt.Fprintln(w, "Version\tDescription\tType\tExecuted At\tExecution Time\tStatements\tStatus")
+	for _, r := range report.Revisions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%s\n",
+			r.Version, r.Description, r.Type, r.ExecutedAt.Format(time.RFC3339),
+			r.ExecutionTime.Round(time.Millisecond), r.Applied, r.Total, revisionStatus(r),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type (
+	// Exec contains the results of the statements executed by 'atlas exec'.
+	Exec struct {
+		Env     `json:"Env"`
+		Results []*ExecResult `json:"Results,omitempty"` // Results ordered by their execution
+	}
+
+	// ExecResult holds the result of a single statement.
+	ExecResult struct {
+		Stmt    string   `json:"Stmt"`
+		Columns []string `json:"Columns,omitempty"` // Columns of the returned rows, if any.
+		Rows    [][]any  `json:"Rows,omitempty"`
+	}
+)
+
+var (
+	// ExecTemplateFuncs are global functions available in exec report templates.
+	ExecTemplateFuncs = WithColorFuncs(template.FuncMap{
+		"json":       jsonEncode,
+		"json_merge": jsonMerge,
+		"default":    execDefault,
+	})
+
+	// ExecTemplate holds the default template of the 'exec' command.
+	ExecTemplate = template.Must(template.New("exec").Funcs(ExecTemplateFuncs).Parse("{{ default . }}"))
+)
+
+// execDefault renders the rows returned by the statements as tables.
+// Statements that do not return rows (e.g., DDL) are not printed.
+func execDefault(report *Exec) (string, error) {
+	var b strings.Builder
+	for _, r := range report.Results {
+		if len(r.Columns) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(r.Columns, "\t"))
+		for _, row := range r.Rows {
+			vs := make([]string, len(row))
+			for i, v := range row {
+				vs[i] = "NULL"
+				if v != nil {
+					vs[i] = fmt.Sprint(v)
+				}
+			}
+			fmt.Fprintln(w, strings.Join(vs, "\t"))
+		}
+		if err := w.Flush(); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
 // MigrateSetTemplate holds the default template of the 'migrate set' command.
 var MigrateSetTemplate = template.Must(template.New("set").
 	Funcs(ColorTemplateFuncs).Parse(`
@@ -767,6 +880,8 @@ var (
 		"sql":       sqlInspect,
 		"json":      jsonEncode,
 		"mermaid":   mermaid,
+		"dot":       dot,
+		"plantuml":  plantuml,
 		"split": func(...any) (string, error) {
 			return "", cmdext.UnsupportedErr("\n'atlas schema inspect' with 'split' function")
 		},
@@ -1028,72 +1143,82 @@ func fmtPlan(ctx context.Context, client *sqlclient.Client, changes schema.Chang
 	return string(f.Bytes()), nil
 }
 
-func mermaid(i *SchemaInspect, _ ...string) (string, error) {
+// erdFuncs returns the template functions shared by the ERD formats.
+func erdFuncs(i *SchemaInspect, format string) (template.FuncMap, error) {
 	ft, ok := i.client.Driver.(interface {
 		FormatType(schema.Type) (string, error)
 	})
 	if !ok {
-		return "", fmt.Errorf("mermaid: driver does not support FormatType")
+		return nil, fmt.Errorf("%s: driver does not support FormatType", format)
 	}
-	var (
-		b       strings.Builder
-		qualify = len(i.Realm.Schemas) > 1
-		funcs   = template.FuncMap{
-			"nospace":    strings.NewReplacer(" ", "_").Replace,
-			"formatType": ft.FormatType,
-			"tableName": func(t *schema.Table) string {
-				if qualify {
-					return fmt.Sprintf("%[1]s_%[2]s[\"%[1]s.%[2]s\"]", t.Schema.Name, t.Name)
-				}
-				return t.Name
-			},
-			"tableIdent": func(t *schema.Table) string {
-				if qualify {
-					return fmt.Sprintf("%s_%s", t.Schema.Name, t.Name)
-				}
-				return t.Name
-			},
-			"pkfk": func(t *schema.Table, c *schema.Column) string {
-				var pkfk []string
-				if t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c }) {
-					pkfk = append(pkfk, "PK")
-				}
-				if c.ForeignKeys != nil {
-					pkfk = append(pkfk, "FK")
-				}
-				return strings.Join(pkfk, ",")
-			},
-			"card": func(fk *schema.ForeignKey) string {
-				var (
-					hasU = func(t *schema.Table, cs []*schema.Column) bool {
-						if t.PrimaryKey != nil && slices.EqualFunc(t.PrimaryKey.Parts, cs, func(p *schema.IndexPart, c *schema.Column) bool {
+	qualify := len(i.Realm.Schemas) > 1
+	return template.FuncMap{
+		"nospace":    strings.NewReplacer(" ", "_").Replace,
+		"formatType": ft.FormatType,
+		"tableName": func(t *schema.Table) string {
+			if qualify {
+				return fmt.Sprintf("%s.%s", t.Schema.Name, t.Name)
+			}
+			return t.Name
+		},
+		"tableIdent": func(t *schema.Table) string {
+			if qualify {
+				return fmt.Sprintf("%s_%s", t.Schema.Name, t.Name)
+			}
+			return t.Name
+		},
+		"pkfk": func(t *schema.Table, c *schema.Column) string {
+			var pkfk []string
+			if t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c }) {
+				pkfk = append(pkfk, "PK")
+			}
+			if c.ForeignKeys != nil {
+				pkfk = append(pkfk, "FK")
+			}
+			return strings.Join(pkfk, ",")
+		},
+		"card": func(fk *schema.ForeignKey) string {
+			var (
+				hasU = func(t *schema.Table, cs []*schema.Column) bool {
+					if t.PrimaryKey != nil && slices.EqualFunc(t.PrimaryKey.Parts, cs, func(p *schema.IndexPart, c *schema.Column) bool {
+						return p.C != nil && p.C.Name == c.Name
+					}) {
+						return true
+					}
+					return slices.ContainsFunc(t.Indexes, func(idx *schema.Index) bool {
+						return idx.Unique && slices.EqualFunc(idx.Parts, cs, func(p *schema.IndexPart, c *schema.Column) bool {
 							return p.C != nil && p.C.Name == c.Name
-						}) {
-							return true
-						}
-						return slices.ContainsFunc(t.Indexes, func(idx *schema.Index) bool {
-							return idx.Unique && slices.EqualFunc(idx.Parts, cs, func(p *schema.IndexPart, c *schema.Column) bool {
-								return p.C != nil && p.C.Name == c.Name
-							})
 						})
-					}
-					from, to = "}", "{"
-				)
-				if hasU(fk.Table, fk.Columns) {
-					from = "|"
+					})
 				}
-				if hasU(fk.RefTable, fk.RefColumns) {
-					to = "|"
-				}
-				return fmt.Sprintf("%so--o%s", from, to)
-			},
-		}
+				from, to = "}", "{"
+			)
+			if hasU(fk.Table, fk.Columns) {
+				from = "|"
+			}
+			if hasU(fk.RefTable, fk.RefColumns) {
+				to = "|"
+			}
+			return fmt.Sprintf("%so--o%s", from, to)
+		},
+		"html":  html.EscapeString,
+		"quote": strconv.Quote,
+	}, nil
+}
+
+func mermaid(i *SchemaInspect, _ ...string) (string, error) {
+	funcs, err := erdFuncs(i, "mermaid")
+	if err != nil {
+		return "", err
+	}
+	var (
+		b strings.Builder
 		t = template.Must(template.New("mermaid").
 			Funcs(funcs).
 			Parse(`erDiagram
 {{- range $s := .Schemas }}
   {{- range $t := $s.Tables }}
-    {{ tableName $t }} {
+    {{ tableIdent $t }}{{ if ne (tableIdent $t) (tableName $t) }}["{{ tableName $t }}"]{{ end }} {
     {{- range $c := $t.Columns }}
       {{ formatType $c.Type.Type | nospace }} {{ nospace $c.Name }}{{ with pkfk $t $c }} {{ . }}{{ end }}
     {{- end }}
@@ -1111,6 +1236,78 @@ func mermaid(i *SchemaInspect, _ ...string) (string, error) {
 	return b.String(), nil
 }
 
+// dot renders the inspected schema as a Graphviz (DOT) entity-relationship diagram.
+func dot(i *SchemaInspect, _ ...string) (string, error) {
+	funcs, err := erdFuncs(i, "dot")
+	if err != nil {
+		return "", err
+	}
+	var (
+		b strings.Builder
+		t = template.Must(template.New("dot").
+			Funcs(funcs).
+			Parse(`digraph {
+  rankdir=LR
+  node [shape=plaintext]
+{{- range $s := .Schemas }}
+  {{- range $t := $s.Tables }}
+  {{ quote (tableName $t) }} [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td><b>{{ html (tableName $t) }}</b></td></tr>
+    {{- range $c := $t.Columns }}<tr><td port={{ quote $c.Name }} align="left">{{ html $c.Name }}: {{ formatType $c.Type.Type | html }}{{ with pkfk $t $c }} {{ . }}{{ end }}</td></tr>{{ end -}}
+  </table>>]
+  {{- end }}
+{{- end }}
+{{- range $s := .Schemas }}
+  {{- range $t := $s.Tables }}
+    {{- range $fk := $t.ForeignKeys }}
+  {{ quote (tableName $t) }}:{{ quote (index $fk.Columns 0).Name }} -> {{ quote (tableName $fk.RefTable) }}:{{ quote (index $fk.RefColumns 0).Name }} [label={{ quote $fk.Symbol }}]
+    {{- end }}
+  {{- end }}
+{{- end }}
+}
+`))
+	)
+	if err := t.Execute(&b, i.Realm); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// plantuml renders the inspected schema as a PlantUML entity-relationship diagram.
+func plantuml(i *SchemaInspect, _ ...string) (string, error) {
+	funcs, err := erdFuncs(i, "plantuml")
+	if err != nil {
+		return "", err
+	}
+	var (
+		b strings.Builder
+		t = template.Must(template.New("plantuml").
+			Funcs(funcs).
+			Parse(`@startuml
+{{- range $s := .Schemas }}
+  {{- range $t := $s.Tables }}
+entity {{ quote (tableName $t) }} as {{ tableIdent $t }} {
+    {{- range $c := $t.Columns }}
+  {{ if not $c.Type.Null }}* {{ end }}{{ $c.Name }} : {{ formatType $c.Type.Type }}{{ with pkfk $t $c }} <<{{ . }}>>{{ end }}
+    {{- end }}
+}
+  {{- end }}
+{{- end }}
+{{- range $s := .Schemas }}
+  {{- range $t := $s.Tables }}
+    {{- range $fk := $t.ForeignKeys }}
+{{ tableIdent $t }} {{ card $fk }} {{ tableIdent $fk.RefTable }} : {{ $fk.Symbol }}
+    {{- end }}
+  {{- end }}
+{{- end }}
+@enduml
+`))
+	)
+	if err := t.Execute(&b, i.Realm); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 func jsonEncode(v any, args ...string) (string, error) {
 	var (
 		b   []byte
@@ -1205,6 +1402,44 @@ func NewSchemaPlan(ctx context.Context, env Env, pending []*migrate.Change, err
 	return NewSchemaApply(ctx, env, nil, pending, err)
 }
 
+// SchemaDrift contains a summary of a 'schema drift' check on a database.
+type SchemaDrift struct {
+	ctx context.Context `json:"-"`
+	Env
+	// Drift indicates if the database drifted from its desired state.
+	Drift bool `json:"Drift"`
+	// Changes holds the pending changes required to bring
+	// the database to its desired state.
+	Changes Changes `json:"Changes,omitempty"`
+	// Error that occurred during the check.
+	Error string `json:"Error,omitempty"`
+}
+
+// SchemaDriftTemplate holds the default template of the 'schema drift' command.
+var SchemaDriftTemplate = template.Must(template.
+	New("drift").
+	Funcs(ApplyTemplateFuncs).
+	Parse(`{{- if .Error -}}
+Failed checking for drift: {{ .Error }}
+{{ else if .Drift -}}
+Schema drift detected, the following changes are required to bring the database to its desired state:
+
+{{ range .Changes.Pending -}}
+{{- if .Comment -}}
+{{- printf "-- %s%s\n" (slice .Comment 0 1 | upper ) (slice .Comment 1) -}}
+{{- end -}}
+{{- printf "%s;\n" .Cmd -}}
+{{- end -}}
+{{- else -}}
+No drift detected, the database is in sync with its desired state
+{{ end -}}
+`))
+
+// NewSchemaDrift returns a SchemaDrift.
+func NewSchemaDrift(ctx context.Context, env Env) *SchemaDrift {
+	return &SchemaDrift{ctx: ctx, Env: env}
+}
+
 func (*MigrateApply) MaskedText(s *migrate.Stmt) string {
 	return s.Text // Unsupported feature.
 }
@@ -0,0 +1,380 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package cmdregistry implements the storage of migration directories as
+// versioned artifacts in a local (filesystem) or a self-hosted OCI registry.
+package cmdregistry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Registry stores migration directory archives (see migrate.ArchiveDir)
+	// under a repository name and a tag.
+	Registry interface {
+		// Push stores the archive under the given reference,
+		// and returns the digest of the archive.
+		Push(context.Context, Ref, []byte) (string, error)
+		// Pull returns the archive stored under the given reference.
+		Pull(context.Context, Ref) ([]byte, error)
+	}
+
+	// Ref references an artifact in the registry. e.g., app:20240101000000.
+	Ref struct {
+		Name string // Repository name. e.g., app or team/app.
+		Tag  string // Artifact tag. e.g., latest.
+	}
+)
+
+// Registry types (URL schemes).
+const (
+	TypeFile = "file"
+	TypeOCI  = "oci"
+)
+
+// DefaultTag is the tag used when a reference is not tagged.
+const DefaultTag = "latest"
+
+var (
+	// Repository names and tags follow the OCI distribution specification.
+	reName = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	reTag  = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// ParseRef parses a reference in the form of <name>[:<tag>].
+// If the tag is omitted, DefaultTag is used.
+func ParseRef(s string) (Ref, error) {
+	r := Ref{Name: s, Tag: DefaultTag}
+	if i := strings.LastIndexByte(s, ':'); i > strings.LastIndexByte(s, '/') {
+		r.Name, r.Tag = s[:i], s[i+1:]
+	}
+	if err := r.Validate(); err != nil {
+		return Ref{}, err
+	}
+	return r, nil
+}
+
+// Validate reports if the reference name or tag are invalid.
+func (r Ref) Validate() error {
+	if !reName.MatchString(r.Name) {
+		return fmt.Errorf("cmdregistry: invalid repository name %q", r.Name)
+	}
+	if !reTag.MatchString(r.Tag) {
+		return fmt.Errorf("cmdregistry: invalid tag %q", r.Tag)
+	}
+	return nil
+}
+
+// String implements the fmt.Stringer interface.
+func (r Ref) String() string {
+	return r.Name + ":" + r.Tag
+}
+
+// Digest returns the digest of the given archive. e.g., sha256:<hex>.
+func Digest(b []byte) string {
+	h := sha256.Sum256(b)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+// Open returns the registry for the given URL. Supported URLs are:
+//
+//	file://path/to/registry
+//	oci://localhost:5000/prefix?insecure=true
+//
+// OCI registries are accessed over HTTPS, unless the "insecure" parameter is
+// set. Credentials can be provided using the user information of the URL.
+func Open(s string) (Registry, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("cmdregistry: parse registry url: %w", err)
+	}
+	switch u.Scheme {
+	case TypeFile:
+		p := filepath.Join(u.Host, u.Path)
+		if p == "" {
+			return nil, errors.New("cmdregistry: missing path for file registry")
+		}
+		return &FileRegistry{Root: p}, nil
+	case TypeOCI:
+		if u.Host == "" {
+			return nil, errors.New("cmdregistry: missing host for oci registry")
+		}
+		r := &OCIRegistry{
+			BaseURL: "https://" + u.Host,
+			Prefix:  strings.Trim(u.Path, "/"),
+			Client:  http.DefaultClient,
+		}
+		if u.Query().Get("insecure") == "true" {
+			r.BaseURL = "http://" + u.Host
+		}
+		if u.User != nil {
+			r.Username = u.User.Username()
+			r.Password, _ = u.User.Password()
+		}
+		return r, nil
+	case "":
+		return nil, fmt.Errorf("cmdregistry: missing scheme for registry url. Did you mean %q?", "file://"+s)
+	default:
+		return nil, fmt.Errorf("cmdregistry: unsupported registry scheme %q", u.Scheme)
+	}
+}
+
+// FileRegistry is a registry that stores artifacts on the local filesystem,
+// using the following layout: <root>/<name>/<tag>.tar.
+type FileRegistry struct {
+	Root string
+}
+
+// Push implements Registry.Push.
+func (r *FileRegistry) Push(_ context.Context, ref Ref, b []byte) (string, error) {
+	if err := ref.Validate(); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(r.Root, filepath.FromSlash(ref.Name))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("cmdregistry: create repository directory: %w", err)
+	}
+	// Write to a temporary file first to not leave partial artifacts behind.
+	f, err := os.CreateTemp(dir, ".push-*")
+	if err != nil {
+		return "", fmt.Errorf("cmdregistry: create artifact file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return "", fmt.Errorf("cmdregistry: write artifact: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("cmdregistry: write artifact: %w", err)
+	}
+	if err := os.Rename(f.Name(), r.path(ref)); err != nil {
+		return "", fmt.Errorf("cmdregistry: write artifact: %w", err)
+	}
+	return Digest(b), nil
+}
+
+// Pull implements Registry.Pull.
+func (r *FileRegistry) Pull(_ context.Context, ref Ref) ([]byte, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(r.path(ref))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cmdregistry: artifact %q was not found in %q", ref, r.Root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cmdregistry: read artifact: %w", err)
+	}
+	return b, nil
+}
+
+func (r *FileRegistry) path(ref Ref) string {
+	return filepath.Join(r.Root, filepath.FromSlash(ref.Name), ref.Tag+".tar")
+}
+
+// Media types of the artifacts stored in OCI registries.
+const (
+	MediaTypeArtifact = "application/vnd.atlas.migration.dir.v1"
+	MediaTypeLayer    = "application/vnd.atlas.migration.dir.v1.tar"
+	mediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeEmpty    = "application/vnd.oci.empty.v1+json"
+)
+
+type (
+	// OCIRegistry is a registry that stores artifacts in an OCI registry,
+	// using the distribution API. Each artifact is stored as an image manifest
+	// with a single layer holding the migration directory archive.
+	OCIRegistry struct {
+		BaseURL            string // e.g., https://localhost:5000.
+		Prefix             string // Optional repository prefix. e.g., team.
+		Username, Password string // Optional basic authentication.
+		Client             *http.Client
+	}
+
+	// descriptor describes the content of a manifest, a layer or a config.
+	descriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int               `json:"size"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
+
+	// manifest is an OCI image manifest.
+	manifest struct {
+		SchemaVersion int           `json:"schemaVersion"`
+		MediaType     string        `json:"mediaType"`
+		ArtifactType  string        `json:"artifactType,omitempty"`
+		Config        descriptor    `json:"config"`
+		Layers        []*descriptor `json:"layers"`
+	}
+)
+
+// Push implements Registry.Push.
+func (r *OCIRegistry) Push(ctx context.Context, ref Ref, b []byte) (string, error) {
+	if err := ref.Validate(); err != nil {
+		return "", err
+	}
+	empty := []byte("{}")
+	for _, blob := range [][]byte{empty, b} {
+		if err := r.uploadBlob(ctx, ref, blob); err != nil {
+			return "", err
+		}
+	}
+	m, err := json.Marshal(&manifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeManifest,
+		ArtifactType:  MediaTypeArtifact,
+		Config:        descriptor{MediaType: mediaTypeEmpty, Digest: Digest(empty), Size: len(empty)},
+		Layers: []*descriptor{
+			{
+				MediaType:   MediaTypeLayer,
+				Digest:      Digest(b),
+				Size:        len(b),
+				Annotations: map[string]string{"org.opencontainers.image.title": "migrations.tar"},
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := r.request(ctx, http.MethodPut, r.path(ref, "manifests", ref.Tag), bytes.NewReader(m))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mediaTypeManifest)
+	if _, err := r.do(req, http.StatusCreated); err != nil {
+		return "", err
+	}
+	return Digest(b), nil
+}
+
+// Pull implements Registry.Pull.
+func (r *OCIRegistry) Pull(ctx context.Context, ref Ref) ([]byte, error) {
+	if err := ref.Validate(); err != nil {
+		return nil, err
+	}
+	req, err := r.request(ctx, http.MethodGet, r.path(ref, "manifests", ref.Tag), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeManifest)
+	b, err := r.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("cmdregistry: decode manifest of %q: %w", ref, err)
+	}
+	var layer *descriptor
+	for _, l := range m.Layers {
+		if l.MediaType == MediaTypeLayer {
+			layer = l
+			break
+		}
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("cmdregistry: artifact %q is not a migration directory", ref)
+	}
+	if req, err = r.request(ctx, http.MethodGet, r.path(ref, "blobs", layer.Digest), nil); err != nil {
+		return nil, err
+	}
+	if b, err = r.do(req, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if d := Digest(b); d != layer.Digest {
+		return nil, fmt.Errorf("cmdregistry: digest mismatch for %q: expected %s, got %s", ref, layer.Digest, d)
+	}
+	return b, nil
+}
+
+// uploadBlob uploads the given blob to the repository, unless it already exists.
+func (r *OCIRegistry) uploadBlob(ctx context.Context, ref Ref, b []byte) error {
+	d := Digest(b)
+	req, err := r.request(ctx, http.MethodHead, r.path(ref, "blobs", d), nil)
+	if err != nil {
+		return err
+	}
+	if _, err := r.do(req, http.StatusOK); err == nil {
+		return nil
+	}
+	if req, err = r.request(ctx, http.MethodPost, r.path(ref, "blobs", "uploads")+"/", nil); err != nil {
+		return err
+	}
+	res, err := r.send(req, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	// The upload location may be relative to the request URL.
+	loc, err := req.URL.Parse(res.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("cmdregistry: parse upload location: %w", err)
+	}
+	q := loc.Query()
+	q.Set("digest", d)
+	loc.RawQuery = q.Encode()
+	if req, err = r.request(ctx, http.MethodPut, loc.String(), bytes.NewReader(b)); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = r.do(req, http.StatusCreated)
+	return err
+}
+
+// path returns the URL of the given repository endpoint.
+func (r *OCIRegistry) path(ref Ref, kind, key string) string {
+	return r.BaseURL + "/v2/" + path.Join(r.Prefix, ref.Name, kind, key)
+}
+
+func (r *OCIRegistry) request(ctx context.Context, method, u string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if r.Username != "" || r.Password != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+	return req, nil
+}
+
+// send sends the request, and returns an error if
+// the response status is not the expected one.
+func (r *OCIRegistry) send(req *http.Request, status int) (*http.Response, error) {
+	res, err := r.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cmdregistry: %w", err)
+	}
+	if res.StatusCode != status {
+		defer res.Body.Close()
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return nil, fmt.Errorf("cmdregistry: unexpected status %d for %s %s: %s", res.StatusCode, req.Method, req.URL.Path, bytes.TrimSpace(b))
+	}
+	return res, nil
+}
+
+// do sends the request, and returns the response body.
+func (r *OCIRegistry) do(req *http.Request, status int) ([]byte, error) {
+	res, err := r.send(req, status)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return io.ReadAll(res.Body)
+}
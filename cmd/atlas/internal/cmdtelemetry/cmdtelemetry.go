@@ -0,0 +1,356 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package cmdtelemetry exports the metrics and traces of apply operations
+// (e.g., 'migrate apply' and 'schema apply') to Prometheus, using the
+// textfile collector or a Pushgateway, and to OpenTelemetry collectors,
+// using the OTLP/HTTP protocol.
+package cmdtelemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Config configures the export of the apply metrics and traces.
+	Config struct {
+		Metrics *MetricsConfig
+		Traces  *TracesConfig
+	}
+
+	// MetricsConfig configures the export of Prometheus metrics.
+	MetricsConfig struct {
+		Textfile    string // Path of the file to write for the node_exporter textfile collector.
+		Pushgateway string // URL of the Prometheus Pushgateway. e.g., http://localhost:9091.
+		Job         string // Job name used when pushing metrics. Defaults to "atlas".
+	}
+
+	// TracesConfig configures the export of OpenTelemetry traces.
+	TracesConfig struct {
+		Endpoint string            // OTLP/HTTP endpoint. e.g., http://localhost:4318.
+		Headers  map[string]string // Additional headers. e.g., for authentication.
+	}
+
+	// Apply describes an apply operation on a target database.
+	Apply struct {
+		Command    string // e.g., "migrate apply".
+		Env        string // Name of the environment, if any.
+		URL        string // Redacted URL of the target database.
+		Start, End time.Time
+		Files      []*File // Executed migration files. For declarative applies, the plan.
+		Error      string
+	}
+
+	// File describes the execution of a migration file (or a plan).
+	File struct {
+		Name       string
+		Version    string
+		Start, End time.Time
+		Stmts      int // Number of statements applied with success.
+		Error      string
+	}
+)
+
+// Export exports the metrics and traces of the apply operation
+// to the configured destinations. A nil config is a no-op.
+func Export(ctx context.Context, c *Config, a *Apply) error {
+	if c == nil {
+		return nil
+	}
+	var errs []error
+	if m := c.Metrics; m != nil {
+		b := Metrics(a)
+		if m.Textfile != "" {
+			errs = append(errs, writeTextfile(m.Textfile, b))
+		}
+		if m.Pushgateway != "" {
+			errs = append(errs, push(ctx, m, a.Env, b))
+		}
+	}
+	if t := c.Traces; t != nil && t.Endpoint != "" {
+		errs = append(errs, exportTraces(ctx, t, a))
+	}
+	return errors.Join(errs...)
+}
+
+// Metrics returns the metrics of the apply operation
+// in the Prometheus text exposition format.
+func Metrics(a *Apply) []byte {
+	var (
+		b      bytes.Buffer
+		labels = labelSet("command", a.Command, "env", a.Env)
+		stmts  int
+		failed int
+	)
+	for _, f := range a.Files {
+		stmts += f.Stmts
+		if f.Error != "" {
+			failed++
+		}
+	}
+	success := 1
+	if a.Error != "" {
+		success = 0
+	}
+	gauge(&b, "atlas_apply_duration_seconds", "Duration of the last apply in seconds.", labels, a.End.Sub(a.Start).Seconds())
+	gauge(&b, "atlas_apply_timestamp_seconds", "Unix time the last apply ended at.", labels, float64(a.End.Unix()))
+	gauge(&b, "atlas_apply_success", "Whether the last apply succeeded (1) or failed (0).", labels, float64(success))
+	gauge(&b, "atlas_apply_files", "Number of files executed by the last apply.", labels, float64(len(a.Files)))
+	gauge(&b, "atlas_apply_statements", "Number of statements applied by the last apply.", labels, float64(stmts))
+	gauge(&b, "atlas_apply_failures", "Number of files that failed in the last apply.", labels, float64(failed))
+	if len(a.Files) > 0 {
+		header(&b, "atlas_apply_file_duration_seconds", "Duration of the files executed by the last apply in seconds.")
+		for _, f := range a.Files {
+			sample(&b, "atlas_apply_file_duration_seconds", labelSet("command", a.Command, "env", a.Env, "file", f.Name), f.End.Sub(f.Start).Seconds())
+		}
+		header(&b, "atlas_apply_file_statements", "Number of statements applied per file by the last apply.")
+		for _, f := range a.Files {
+			sample(&b, "atlas_apply_file_statements", labelSet("command", a.Command, "env", a.Env, "file", f.Name), float64(f.Stmts))
+		}
+	}
+	return b.Bytes()
+}
+
+func gauge(b *bytes.Buffer, name, help, labels string, v float64) {
+	header(b, name, help)
+	sample(b, name, labels, v)
+}
+
+func header(b *bytes.Buffer, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func sample(b *bytes.Buffer, name, labels string, v float64) {
+	fmt.Fprintf(b, "%s%s %s\n", name, labels, strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// labelEscaper escapes label values according to the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelSet formats the given key-value pairs as a label set,
+// skipping the labels with empty values.
+func labelSet(kvs ...string) string {
+	var ls []string
+	for i := 0; i < len(kvs); i += 2 {
+		if kvs[i+1] != "" {
+			ls = append(ls, fmt.Sprintf(`%s="%s"`, kvs[i], labelEscaper.Replace(kvs[i+1])))
+		}
+	}
+	if len(ls) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(ls, ",") + "}"
+}
+
+// writeTextfile writes the metrics atomically, as the
+// textfile collector may read the file at any time.
+func writeTextfile(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".atlas-*.prom")
+	if err != nil {
+		return fmt.Errorf("cmdtelemetry: create metrics file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return fmt.Errorf("cmdtelemetry: write metrics file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cmdtelemetry: write metrics file: %w", err)
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return fmt.Errorf("cmdtelemetry: write metrics file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("cmdtelemetry: write metrics file: %w", err)
+	}
+	return nil
+}
+
+// push pushes the metrics to the Pushgateway, replacing
+// the metrics of the job (and the env) grouping key.
+func push(ctx context.Context, c *MetricsConfig, env string, b []byte) error {
+	job := c.Job
+	if job == "" {
+		job = "atlas"
+	}
+	u := strings.TrimSuffix(c.Pushgateway, "/") + "/metrics/job/" + url.PathEscape(job)
+	if env != "" {
+		u += "/env/" + url.PathEscape(env)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("cmdtelemetry: create pushgateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	return send(req, "push metrics")
+}
+
+type (
+	// The OTLP/JSON encoding of the trace export request. See:
+	// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource struct {
+			Attributes []otlpAttr `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []*otlpSpan `json:"spans"`
+	}
+	otlpSpan struct {
+		TraceID      string     `json:"traceId"`
+		SpanID       string     `json:"spanId"`
+		ParentSpanID string     `json:"parentSpanId,omitempty"`
+		Name         string     `json:"name"`
+		Kind         int        `json:"kind"`
+		Start        string     `json:"startTimeUnixNano"`
+		End          string     `json:"endTimeUnixNano"`
+		Attributes   []otlpAttr `json:"attributes,omitempty"`
+		Status       otlpStatus `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttr struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		String *string `json:"stringValue,omitempty"`
+		Int    *string `json:"intValue,omitempty"`
+	}
+)
+
+// Span kinds and status codes defined by OpenTelemetry.
+const (
+	spanKindInternal = 1
+	statusOK         = 1
+	statusError      = 2
+)
+
+// Spans returns the OTLP/JSON encoded trace of the apply operation.
+// The apply is the root span, and each executed file is a child span.
+func Spans(a *Apply) ([]byte, error) {
+	traceID, err := randomID(16)
+	if err != nil {
+		return nil, err
+	}
+	root, err := span(traceID, "", a.Command, a.Start, a.End, a.Error)
+	if err != nil {
+		return nil, err
+	}
+	root.Attributes = attrs("atlas.env", a.Env, "db.connection_string", a.URL)
+	rs := otlpResourceSpans{ScopeSpans: []otlpScopeSpans{{Spans: []*otlpSpan{root}}}}
+	rs.Resource.Attributes = attrs("service.name", "atlas")
+	rs.ScopeSpans[0].Scope.Name = "ariga.io/atlas"
+	for _, f := range a.Files {
+		s, err := span(traceID, root.SpanID, f.Name, f.Start, f.End, f.Error)
+		if err != nil {
+			return nil, err
+		}
+		s.Attributes = append(attrs("atlas.file.name", f.Name, "atlas.file.version", f.Version), otlpAttr{
+			Key:   "atlas.file.statements",
+			Value: otlpValue{Int: ptr(strconv.Itoa(f.Stmts))},
+		})
+		rs.ScopeSpans[0].Spans = append(rs.ScopeSpans[0].Spans, s)
+	}
+	return json.Marshal(&otlpTraces{ResourceSpans: []otlpResourceSpans{rs}})
+}
+
+func span(traceID, parentID, name string, start, end time.Time, errMsg string) (*otlpSpan, error) {
+	id, err := randomID(8)
+	if err != nil {
+		return nil, err
+	}
+	s := &otlpSpan{
+		TraceID:      traceID,
+		SpanID:       id,
+		ParentSpanID: parentID,
+		Name:         name,
+		Kind:         spanKindInternal,
+		Start:        strconv.FormatInt(start.UnixNano(), 10),
+		End:          strconv.FormatInt(end.UnixNano(), 10),
+		Status:       otlpStatus{Code: statusOK},
+	}
+	if errMsg != "" {
+		s.Status = otlpStatus{Code: statusError, Message: errMsg}
+	}
+	return s, nil
+}
+
+// attrs returns the string attributes of the given
+// key-value pairs, skipping the ones with empty values.
+func attrs(kvs ...string) []otlpAttr {
+	var as []otlpAttr
+	for i := 0; i < len(kvs); i += 2 {
+		if kvs[i+1] != "" {
+			as = append(as, otlpAttr{Key: kvs[i], Value: otlpValue{String: ptr(kvs[i+1])}})
+		}
+	}
+	return as
+}
+
+func exportTraces(ctx context.Context, c *TracesConfig, a *Apply) error {
+	b, err := Spans(a)
+	if err != nil {
+		return fmt.Errorf("cmdtelemetry: encode traces: %w", err)
+	}
+	u := strings.TrimSuffix(c.Endpoint, "/")
+	if !strings.HasSuffix(u, "/v1/traces") {
+		u += "/v1/traces"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("cmdtelemetry: create traces request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+	return send(req, "export traces")
+}
+
+func send(req *http.Request, action string) error {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cmdtelemetry: %s: %w", action, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return fmt.Errorf("cmdtelemetry: %s: unexpected status %d: %s", action, res.StatusCode, bytes.TrimSpace(b))
+	}
+	return nil
+}
+
+func randomID(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -59,5 +59,6 @@ func ParserFor(name string) Parser {
 func init() {
 	Register(mysql.DriverName, &myparse.FileParser{})
 	Register(postgres.DriverName, &pgparse.Parser{})
+	Register(postgres.CockroachDriverName, &pgparse.Parser{})
 	Register(sqlite.DriverName, &sqliteparse.FileParser{})
 }
@@ -7,6 +7,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -62,7 +63,13 @@ func main() {
 	}
 	done(err)
 	if err != nil {
-		os.Exit(1)
+		code := 1
+		// Errors may set a distinct exit code (e.g., drift
+		// detection), to allow scripts to act on the result.
+		if fe := (*cmdapi.FormattedError)(nil); errors.As(err, &fe) {
+			code = fe.ExitCode()
+		}
+		os.Exit(code)
 	}
 }
 
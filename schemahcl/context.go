@@ -7,7 +7,9 @@ package schemahcl
 import (
 	"context"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -20,10 +22,12 @@ import (
 
 // blockVar is an HCL resource that defines an input variable to the Atlas DDL document.
 type blockVar struct {
-	Name        string    `hcl:",label"`
-	Type        cty.Value `hcl:"type"`
-	Default     cty.Value `hcl:"default,optional"`
-	Description string    `hcl:"description,optional"`
+	Name        string       `hcl:",label"`
+	Type        cty.Value    `hcl:"type"`
+	Default     cty.Value    `hcl:"default,optional"`
+	Description string       `hcl:"description,optional"`
+	Sensitive   bool         `hcl:"sensitive,optional"`
+	Sources     []*varSource `hcl:"source,block"`
 }
 
 // setInputVals sets the input values into the evaluation context. HCL documents can define
@@ -33,29 +37,57 @@ type blockVar struct {
 //	  type = string // also supported: number, bool
 //	  default = "rotemtam"
 //	}
-func (s *State) setInputVals(ctx *hcl.EvalContext, body hcl.Body, input map[string]cty.Value) error {
+//
+// Variables that were not set by the input values can be resolved from external sources
+// (see VarSource), and sensitive variables are redacted from the returned error messages:
+//
+//	variable "url" {
+//	  type      = string
+//	  sensitive = true
+//	  source "exec" {
+//	    command = ["vault", "kv", "get", "-field=url", "secret/db"]
+//	  }
+//	}
+//
+// The returned values are the values of the sensitive variables.
+func (s *State) setInputVals(ctx *hcl.EvalContext, body hcl.Body, input map[string]cty.Value) ([]string, error) {
 	var doc struct {
 		Vars   []*blockVar `hcl:"variable,block"`
 		Remain hcl.Body    `hcl:",remain"`
 	}
 	if diag := gohcl.DecodeBody(body, ctx, &doc); diag.HasErrors() {
-		return diag
+		return nil, diag
 	}
-	ctxVars := make(map[string]cty.Value)
+	var (
+		secrets []string
+		ctxVars = make(map[string]cty.Value)
+	)
 	for _, v := range doc.Vars {
 		var vv cty.Value
 		switch iv, ok := input[v.Name]; {
 		case !v.Type.Type().IsCapsuleType():
-			return fmt.Errorf(
+			return secrets, fmt.Errorf(
 				"invalid type %q for variable %q. Valid types are: string, number, bool, list, map, or set",
 				v.Type.AsString(), v.Name,
 			)
 		case ok:
 			vv = iv
-		case v.Default != cty.NilVal:
-			vv = v.Default
 		default:
-			return fmt.Errorf("missing value for required variable %q", v.Name)
+			sv, err := s.sourceVal(ctx, v)
+			if err != nil {
+				return secrets, err
+			}
+			switch {
+			case !sv.IsNull():
+				vv = sv
+			case v.Default != cty.NilVal:
+				vv = v.Default
+			default:
+				return secrets, fmt.Errorf("missing value for required variable %q", v.Name)
+			}
+		}
+		if v.Sensitive {
+			secrets = append(secrets, sensitiveVals(vv)...)
 		}
 		vt := v.Type.EncapsulatedValue().(*cty.Type)
 		// In case the input value is a primitive type and the expected type is a list,
@@ -65,12 +97,12 @@ func (s *State) setInputVals(ctx *hcl.EvalContext, body hcl.Body, input map[stri
 		}
 		cv, err := convert.Convert(vv, *vt)
 		if err != nil {
-			return fmt.Errorf("variable %q: %w", v.Name, err)
+			return secrets, fmt.Errorf("variable %q: %w", v.Name, err)
 		}
 		ctxVars[v.Name] = cv
 	}
 	mergeCtxVar(ctx, ctxVars)
-	return nil
+	return secrets, nil
 }
 
 // evalReferences evaluates local and data blocks.
@@ -85,6 +117,7 @@ func (s *State) evalReferences(ctx *hcl.EvalContext, body *hclsyntax.Body) error
 		goctx   = s.config.ctx
 		typeblk = make(map[string]bool)
 		nodes   = make(map[[3]string]*node)
+		modblk  = make(map[string]hclsyntax.Blocks)
 		blocks  = make(hclsyntax.Blocks, 0, len(body.Blocks))
 	)
 	if goctx == nil {
@@ -108,6 +141,28 @@ func (s *State) evalReferences(ctx *hcl.EvalContext, body *hclsyntax.Body) error
 				value: func() (cty.Value, error) { return h(goctx, ctx, b) },
 				edges: func() []hcl.Traversal { return bodyVars(b.Body) },
 			}
+		case b.Type == BlockModule:
+			if len(b.Labels) != 1 {
+				return fmt.Errorf("module block must have exactly 1 label")
+			}
+			// Module references are combined from
+			// "module" and "name" labels.
+			addr := [3]string{RefModule, b.Labels[0], ""}
+			if nodes[addr] != nil {
+				return fmt.Errorf("duplicate module block %q", b.Labels[0])
+			}
+			nodes[addr] = &node{
+				addr:  addr,
+				edges: func() []hcl.Traversal { return bodyVars(b.Body) },
+				value: func() (cty.Value, error) {
+					v, bs, err := s.evalModule(goctx, ctx, b)
+					if err != nil {
+						return cty.NilVal, err
+					}
+					modblk[b.Labels[0]] = bs
+					return v, nil
+				},
+			}
 		case b.Type == BlockLocals:
 			for k, v := range b.Body.Attributes {
 				k, v := k, v
@@ -186,6 +241,10 @@ func (s *State) evalReferences(ctx *hcl.EvalContext, body *hclsyntax.Body) error
 			switch root := e.RootName(); {
 			case root == RefLocal && len(e) > 1:
 				addr = [3]string{RefLocal, e[1].(hcl.TraverseAttr).Name, ""}
+			case root == RefModule && len(e) > 1:
+				if a, ok := e[1].(hcl.TraverseAttr); ok {
+					addr = [3]string{RefModule, a.Name, ""}
+				}
 			case (root == RefData || typeblk[e.RootName()]) && len(e) > 2:
 				addr = [3]string{e.RootName(), e[1].(hcl.TraverseAttr).Name, e[2].(hcl.TraverseAttr).Name}
 			case s.config.initblk[root] != nil && len(e) == 1:
@@ -217,13 +276,13 @@ func (s *State) evalReferences(ctx *hcl.EvalContext, body *hclsyntax.Body) error
 			typ[n.addr[2]] = v
 			top[n.addr[1]] = cty.ObjectVal(typ)
 			ctx.Variables[n.addr[0]] = cty.ObjectVal(top)
-		case n.addr[0] == RefLocal:
-			locals := make(map[string]cty.Value)
-			if vv, ok := ctx.Variables[RefLocal]; ok {
-				locals = vv.AsValueMap()
+		case n.addr[0] == RefLocal, n.addr[0] == RefModule:
+			vs := make(map[string]cty.Value)
+			if vv, ok := ctx.Variables[n.addr[0]]; ok {
+				vs = vv.AsValueMap()
 			}
-			locals[n.addr[1]] = v
-			ctx.Variables[RefLocal] = cty.ObjectVal(locals)
+			vs[n.addr[1]] = v
+			ctx.Variables[n.addr[0]] = cty.ObjectVal(vs)
 		default:
 			ctx.Variables[n.addr[0]] = v
 		}
@@ -267,6 +326,10 @@ func (s *State) evalReferences(ctx *hcl.EvalContext, body *hclsyntax.Body) error
 			return err
 		}
 	}
+	// Blocks defined by modules are added to the body.
+	for _, name := range slices.Sorted(maps.Keys(modblk)) {
+		body.Blocks = append(body.Blocks, modblk[name]...)
+	}
 	return nil
 }
 
@@ -414,7 +477,13 @@ const (
 	RefLocal      = "local"
 	AttrName      = "name"
 	forEachAttr   = "for_each"
+	whenAttr      = "when"
 	eachRef       = "each"
+	blockDynamic  = "dynamic"
+	// Attributes and blocks of dynamic blocks.
+	dynamicContent  = "content"
+	dynamicLabels   = "labels"
+	dynamicIterator = "iterator"
 )
 
 // Variables represents the dynamic variables used in a body.
@@ -5,6 +5,7 @@
 package schemahcl
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
@@ -417,6 +418,42 @@ func TestWithTypes(t *testing.T) {
 	require.EqualValues(t, f, string(after))
 }
 
+func TestWithTypeHints(t *testing.T) {
+	var (
+		doc struct {
+			Table struct {
+				Name   string `spec:",name"`
+				Column struct {
+					Name string `spec:",name"`
+					Type *Type  `spec:"type"`
+				} `spec:"column"`
+			} `spec:"table"`
+		}
+		s = New(
+			WithTypes("table.column.type", []*TypeSpec{
+				{Name: "text", T: "text"},
+				{Name: "varchar", T: "varchar", Attributes: []*TypeAttr{{Name: "size", Kind: reflect.Int}}},
+			}),
+			WithTypeHints("postgres", map[string]string{"text": "varchar", "clob": "text"}),
+		)
+		column = func(typ string) []byte {
+			return []byte(fmt.Sprintf("table \"t\" {\n  column \"c\" {\n    type = %s\n  }\n}\n", typ))
+		}
+	)
+	err := s.EvalBytes(column("text(255)"), &doc, nil)
+	require.EqualError(t, err, `:3,12-16: Call to unknown function; "text(255)" is invalid for postgres; did you mean "varchar(255)"?`)
+	err = s.EvalBytes(column("clob"), &doc, nil)
+	require.EqualError(t, err, `:3,12-16: Unknown variable; "clob" is invalid for postgres; did you mean "text"?`)
+	err = s.EvalBytes(column("varchr(255)"), &doc, nil)
+	require.EqualError(t, err, `:3,12-18: Call to unknown function; "varchr(255)" is invalid for postgres; did you mean "varchar(255)"?`)
+	err = s.EvalBytes(column(`varchar("255")`), &doc, nil)
+	require.EqualError(t, err, `:3,12-20: Error in function call; Call to function "varchar" failed: invalid value "255" for attribute "size" of type "varchar": expected an integer.`)
+	err = s.EvalBytes(column("varchar(25.5)"), &doc, nil)
+	require.EqualError(t, err, `:3,12-20: Error in function call; Call to function "varchar" failed: invalid value 25.5 for attribute "size" of type "varchar": expected an integer.`)
+	require.NoError(t, s.EvalBytes(column("varchar(255)"), &doc, nil))
+	require.Equal(t, "varchar", doc.Table.Column.Type.T)
+}
+
 func TestEmptyStrSQL(t *testing.T) {
 	s := New(WithTypes("", nil))
 	h := `x = sql("")`
@@ -0,0 +1,92 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Deprecation describes a deprecated attribute or block that was used in an evaluated
+// document. Fields are marked as deprecated using the "deprecated" option of their spec
+// tag, with an optional replacement that is suggested to the user instead. For example:
+//
+//	type Table struct {
+//		Name    string   `spec:",name"`
+//		Charset string   `spec:"charset,deprecated=collation"`
+//		Options *Options `spec:"options,deprecated"`
+//	}
+//
+// Deprecated fields are still decoded, and deprecations are reported as warnings using
+// the EvalOptions.Deprecated handler, so that documents can be upgraded gradually.
+type Deprecation struct {
+	// Path of the block that holds the element. e.g., table.users.column.id.
+	// An empty path indicates the element is defined at the top-level.
+	Path string
+	// Name of the attribute, or the type of the block.
+	Name string
+	// Block reports if the element is a block or an attribute.
+	Block bool
+	// Replacement is the suggested replacement of the element, if there is one.
+	Replacement string
+	// Range of the element in the document.
+	Range *hcl.Range
+}
+
+// String implements fmt.Stringer.
+func (d *Deprecation) String() string {
+	var b strings.Builder
+	if d.Range != nil {
+		b.WriteString(d.Range.String())
+		b.WriteString(": ")
+	}
+	if d.Block {
+		fmt.Fprintf(&b, "block %q", d.Name)
+	} else {
+		fmt.Fprintf(&b, "attribute %q", d.Name)
+	}
+	if d.Path != "" {
+		fmt.Fprintf(&b, " in %s", d.Path)
+	}
+	b.WriteString(" is deprecated")
+	if d.Replacement != "" {
+		fmt.Fprintf(&b, ", use %q instead", d.Replacement)
+	}
+	return b.String()
+}
+
+// deprecated returns the replacement of the field and reports if it is deprecated.
+func (f fieldDesc) deprecated() (string, bool) {
+	for _, opt := range strings.Split(f.options, ",") {
+		if opt == "deprecated" {
+			return "", true
+		}
+		if r, ok := strings.CutPrefix(opt, "deprecated="); ok {
+			return r, true
+		}
+	}
+	return "", false
+}
+
+// reportDeprecated reports the elements of the resource that are read by a deprecated field.
+func (d *decoder) reportDeprecated(r *Resource, f fieldDesc) {
+	if d == nil || d.deprecated == nil || f.tag == "" || f.isName() || f.isQualifier() || f.isRange() {
+		return
+	}
+	repl, ok := f.deprecated()
+	if !ok {
+		return
+	}
+	path := strings.Join(d.path, ".")
+	if a, ok := r.Attr(f.tag); ok {
+		d.deprecated(&Deprecation{Path: path, Name: f.tag, Replacement: repl, Range: a.Range()})
+		return
+	}
+	for _, c := range childrenOfType(r, f.tag) {
+		d.deprecated(&Deprecation{Path: path, Name: f.tag, Block: true, Replacement: repl, Range: c.Range()})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalOptions_Deprecated(t *testing.T) {
+	var (
+		d struct {
+			Table []*struct {
+				Name      string `spec:",name"`
+				Charset   string `spec:"charset,deprecated=collation"`
+				Collation string `spec:"collation"`
+				Columns   []*struct {
+					Name string `spec:",name"`
+					Type string `spec:"type"`
+				} `spec:"column"`
+				Options *struct {
+					Engine string `spec:"engine"`
+				} `spec:"options,deprecated"`
+			} `spec:"table"`
+		}
+		p = hclparse.NewParser()
+	)
+	_, diags := p.ParseHCL([]byte(`
+table "users" {
+  charset = "utf8mb4"
+  column "id" {
+    type = "int"
+  }
+  options {
+    engine = "InnoDB"
+  }
+}
+
+table "posts" {
+  collation = "utf8mb4_bin"
+}
+`), "schema.hcl")
+	require.False(t, diags.HasErrors())
+	var deprecated []string
+	err := New().EvalOptions(p, &d, &EvalOptions{
+		Deprecated: func(d *Deprecation) {
+			deprecated = append(deprecated, d.String())
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`schema.hcl:3,3-22: attribute "charset" in table.users is deprecated, use "collation" instead`,
+		`schema.hcl:7,3-10: block "options" in table.users is deprecated`,
+	}, deprecated)
+	// Deprecated fields are still decoded.
+	require.Equal(t, "utf8mb4", d.Table[0].Charset)
+	require.Equal(t, "InnoDB", d.Table[0].Options.Engine)
+
+	b, err := New().JSONSchema(&d)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"description": "Deprecated: use \"collation\" instead.",
+            "deprecated": true`)
+}
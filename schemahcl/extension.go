@@ -94,18 +94,19 @@ func (r *Resource) As(target any) error {
 	if err := validateStructPtr(target); err != nil {
 		return err
 	}
-	return r.as(target)
+	return r.as(target, nil)
 }
 
 var typeRange = reflect.TypeOf(&hcl.Range{})
 
 // As reads the attributes and children resources of the resource into the target struct.
-func (r *Resource) as(target any) error {
+func (r *Resource) as(target any, d *decoder) error {
 	existingAttrs, existingChildren := existingElements(r)
 	var seenName, seenQualifier, setRange bool
 	v := reflect.ValueOf(target).Elem()
 	for _, ft := range specFields(target) {
 		field := v.FieldByName(ft.Name)
+		d.reportDeprecated(r, ft)
 		switch {
 		case ft.isName() && !hasAttr(r, ft.tag):
 			if seenName {
@@ -151,7 +152,7 @@ func (r *Resource) as(target any) error {
 				}
 				n := reflect.New(reflect.TypeOf(typ).Elem())
 				ext := n.Interface()
-				if err := c.as(ext); err != nil {
+				if err := c.as(ext, d.child(c)); err != nil {
 					return err
 				}
 				slc = reflect.Append(slc, reflect.ValueOf(ext))
@@ -179,12 +180,13 @@ func (r *Resource) as(target any) error {
 			}
 			n := reflect.New(reflect.TypeOf(typ).Elem())
 			ext := n.Interface()
-			if err := c.as(ext); err != nil {
+			if err := c.as(ext, d.child(c)); err != nil {
 				return err
 			}
 			field.Set(n)
+			delete(existingChildren, c.Type)
 		case isResourceSlice(field.Type()):
-			if err := setChildSlice(field, childrenOfType(r, ft.tag)); err != nil {
+			if err := setChildSlice(field, childrenOfType(r, ft.tag), d); err != nil {
 				return err
 			}
 			delete(existingChildren, ft.tag)
@@ -201,14 +203,14 @@ func (r *Resource) as(target any) error {
 			case reflect.Struct:
 				n = reflect.New(field.Type())
 				ext := n.Interface()
-				if err := res.as(ext); err != nil {
+				if err := res.as(ext, d.child(res)); err != nil {
 					return err
 				}
 				n = n.Elem()
 			case reflect.Pointer:
 				n = reflect.New(field.Type().Elem())
 				ext := n.Interface()
-				if err := res.as(ext); err != nil {
+				if err := res.as(ext, d.child(res)); err != nil {
 					return err
 				}
 			}
@@ -218,7 +220,7 @@ func (r *Resource) as(target any) error {
 	}
 	rem, ok := target.(Remainer)
 	if !ok {
-		return nil
+		return d.reportUnknown(r, existingAttrs, existingChildren)
 	}
 	extras := rem.Remain()
 	for attrName := range existingAttrs {
@@ -286,7 +288,7 @@ func existingElements(r *Resource) (attrs, children map[string]struct{}) {
 	return
 }
 
-func setChildSlice(field reflect.Value, children []*Resource) error {
+func setChildSlice(field reflect.Value, children []*Resource, d *decoder) error {
 	if field.Type().Kind() != reflect.Slice {
 		return fmt.Errorf("schemahcl: expected field to be of kind slice")
 	}
@@ -298,7 +300,7 @@ func setChildSlice(field reflect.Value, children []*Resource) error {
 	for _, c := range children {
 		n := reflect.New(typ.Elem())
 		ext := n.Interface()
-		if err := c.as(ext); err != nil {
+		if err := c.as(ext, d.child(c)); err != nil {
 			return err
 		}
 		slc = reflect.Append(slc, reflect.ValueOf(ext))
@@ -0,0 +1,543 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// MarshalSpecJSON returns the Atlas HCL encoding of v in the JSON syntax of HCL. Blocks
+// are encoded as objects keyed by their type and labels, and repeated blocks as arrays.
+// Expressions that cannot be represented as JSON values, such as references, types and
+// raw expressions, are encoded as template strings. e.g., "${column.id}" or "${int}".
+// Literal strings are escaped accordingly. i.e., "${" is encoded as "$${".
+func (s *State) MarshalSpecJSON(v any) ([]byte, error) {
+	r := &Resource{}
+	if err := r.Scan(v); err != nil {
+		return nil, fmt.Errorf("schemahcl: failed scanning %T to resource: %w", v, err)
+	}
+	if r.Type != "" {
+		r = &Resource{Children: []*Resource{r}}
+	}
+	var buf bytes.Buffer
+	if err := s.writeJSONBody(&buf, r); err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// EvalJSONBytes evaluates the data byte-slice as an Atlas HCL document written in
+// the JSON syntax of HCL (e.g., created by MarshalSpecJSON), using the input variables,
+// and stores the result in v. The type of v is used to determine which object properties
+// are blocks and how many labels they have, and the rest are read as attributes.
+func (s *State) EvalJSONBytes(data []byte, v any, input map[string]cty.Value) error {
+	f, err := s.jsonFile(data, "", v)
+	if err != nil {
+		return err
+	}
+	p := hclparse.NewParser()
+	p.AddFile("", f)
+	return s.Eval(p, v, input)
+}
+
+// jsonBlock describes a block type that is expected in a JSON document.
+type jsonBlock struct {
+	labels    int          // Number of labels.
+	qualifier bool         // Can have an additional qualifier label.
+	typ       reflect.Type // Struct type, if known.
+	children  map[string]*jsonBlock
+}
+
+// jsonFile converts the JSON document to an HCL file.
+func (s *State) jsonFile(data []byte, filename string, v any) (*hcl.File, error) {
+	if err := validateStructPtr(v); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := readJSON(dec)
+	if err != nil {
+		return nil, fmt.Errorf("schemahcl: decoding JSON document: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("schemahcl: decoding JSON document: unexpected data after top-level object")
+	}
+	obj, ok := root.([]jsonProp)
+	if !ok {
+		return nil, errors.New("schemahcl: expected JSON document to be an object")
+	}
+	top := s.jsonBlockOf(reflect.TypeOf(v))
+	// Top-level blocks that are handled by the evaluation.
+	top.children[BlockVariable] = &jsonBlock{labels: 1, children: map[string]*jsonBlock{"source": {labels: 1}}}
+	top.children[BlockLocals] = &jsonBlock{}
+	top.children[BlockData] = &jsonBlock{labels: 2}
+	top.children[BlockModule] = &jsonBlock{labels: 1}
+	top.children[BlockOutput] = &jsonBlock{labels: 1}
+	for n := range s.config.typedblk {
+		top.children[n] = &jsonBlock{labels: 2}
+	}
+	for n := range s.config.initblk {
+		top.children[n] = &jsonBlock{}
+	}
+	rng := hcl.Range{Filename: filename, Start: hcl.InitialPos, End: hcl.InitialPos}
+	body, err := s.jsonBody(obj, top, rng)
+	if err != nil {
+		return nil, err
+	}
+	return &hcl.File{Body: body, Bytes: data}, nil
+}
+
+// jsonBlockOf returns the block description of the given struct type.
+func (s *State) jsonBlockOf(t reflect.Type) *jsonBlock {
+	t = indirect(t)
+	b := &jsonBlock{typ: t, children: make(map[string]*jsonBlock)}
+	for _, f := range specFields(reflect.New(t).Interface()) {
+		switch {
+		case f.isName():
+			b.labels = 1
+		case f.isQualifier():
+			b.qualifier = true
+		}
+	}
+	return b
+}
+
+// jsonChild returns the description of the given child block type, if it is a block.
+// Struct fields are resolved by the block type, and interface fields (e.g., "partition")
+// by the registered extensions. Unknown properties are read as attributes.
+func (s *State) jsonChild(b *jsonBlock, name string) (*jsonBlock, bool) {
+	if c, ok := b.children[name]; ok {
+		return c, true
+	}
+	if name == blockDynamic {
+		return &jsonBlock{labels: 1}, true
+	}
+	if b.typ != nil {
+		for _, f := range specFields(reflect.New(b.typ).Interface()) {
+			if f.tag != name || f.isName() || f.isQualifier() {
+				continue
+			}
+			switch ft := f.Type; {
+			case isResourceSlice(ft) && !isAttrType(ft.Elem()):
+				return s.jsonBlockOf(ft.Elem()), true
+			case isSingleResource(ft) && !isAttrType(ft):
+				return s.jsonBlockOf(ft), true
+			}
+		}
+	}
+	extensionsMu.RLock()
+	ext, ok := extensions[name]
+	extensionsMu.RUnlock()
+	if ok {
+		return s.jsonBlockOf(reflect.TypeOf(ext)), true
+	}
+	return nil, false
+}
+
+// jsonBody converts the properties of a JSON object to an HCL body.
+func (s *State) jsonBody(obj []jsonProp, b *jsonBlock, rng hcl.Range) (*hclsyntax.Body, error) {
+	body := &hclsyntax.Body{Attributes: make(hclsyntax.Attributes), SrcRange: rng, EndRange: rng}
+	for _, p := range obj {
+		// Comments in the JSON syntax.
+		if p.key == "//" {
+			continue
+		}
+		c, ok := s.jsonChild(b, p.key)
+		if !ok {
+			x, err := jsonExpr(p.value, rng)
+			if err != nil {
+				return nil, fmt.Errorf("schemahcl: attribute %q: %w", p.key, err)
+			}
+			body.Attributes[p.key] = &hclsyntax.Attribute{Name: p.key, Expr: x, SrcRange: rng, NameRange: rng, EqualsRange: rng}
+			continue
+		}
+		blocks, err := s.jsonBlocks(p.key, p.value, c, b, nil, rng)
+		if err != nil {
+			return nil, err
+		}
+		body.Blocks = append(body.Blocks, blocks...)
+	}
+	return body, nil
+}
+
+// jsonBlocks converts the JSON value of a block property to HCL blocks.
+func (s *State) jsonBlocks(typ string, v any, b, parent *jsonBlock, labels []string, rng hcl.Range) ([]*hclsyntax.Block, error) {
+	switch v := v.(type) {
+	case []any:
+		var blocks []*hclsyntax.Block
+		// Arrays can appear in any level, and their
+		// elements are processed as the array itself.
+		for _, e := range v {
+			if _, ok := e.([]jsonProp); !ok {
+				return nil, fmt.Errorf("schemahcl: expected elements of block %q to be objects", typ)
+			}
+			nb, err := s.jsonBlocks(typ, e, b, parent, labels, rng)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, nb...)
+		}
+		return blocks, nil
+	case []jsonProp:
+		if len(labels) < b.labels || b.qualifier && len(labels) == b.labels && s.jsonQualified(v, b) {
+			var blocks []*hclsyntax.Block
+			for _, p := range v {
+				nb, err := s.jsonBlocks(typ, p.value, b, parent, append(labels[:len(labels):len(labels)], p.key), rng)
+				if err != nil {
+					return nil, err
+				}
+				blocks = append(blocks, nb...)
+			}
+			return blocks, nil
+		}
+		nb, err := s.jsonNewBlock(typ, v, b, parent, labels, rng)
+		if err != nil {
+			return nil, err
+		}
+		return []*hclsyntax.Block{nb}, nil
+	default:
+		return nil, fmt.Errorf("schemahcl: expected block %q to be an object or an array of objects", typ)
+	}
+}
+
+// jsonQualified reports if the object holds qualified blocks. i.e.,
+// all its properties are objects that are not fields of the block.
+func (s *State) jsonQualified(obj []jsonProp, b *jsonBlock) bool {
+	if len(obj) == 0 || b.typ == nil {
+		return false
+	}
+	for _, p := range obj {
+		if _, ok := p.value.([]jsonProp); !ok {
+			return false
+		}
+		for _, f := range specFields(reflect.New(b.typ).Interface()) {
+			if f.tag == p.key {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// jsonNewBlock creates an HCL block from the given JSON object.
+func (s *State) jsonNewBlock(typ string, obj []jsonProp, b, parent *jsonBlock, labels []string, rng hcl.Range) (*hclsyntax.Block, error) {
+	// The content of dynamic blocks is of the generated block type.
+	if typ == blockDynamic && len(labels) == 1 {
+		c, ok := s.jsonChild(parent, labels[0])
+		if !ok {
+			c = &jsonBlock{}
+		}
+		b = &jsonBlock{labels: 1, children: map[string]*jsonBlock{dynamicContent: {typ: c.typ, children: c.children}}}
+	}
+	body, err := s.jsonBody(obj, b, rng)
+	if err != nil {
+		return nil, err
+	}
+	lr := make([]hcl.Range, len(labels))
+	for i := range lr {
+		lr[i] = rng
+	}
+	return &hclsyntax.Block{
+		Type:            typ,
+		Labels:          labels,
+		Body:            body,
+		TypeRange:       rng,
+		LabelRanges:     lr,
+		OpenBraceRange:  rng,
+		CloseBraceRange: rng,
+	}, nil
+}
+
+// jsonExpr converts a JSON value to an HCL expression.
+// Strings are parsed as templates, as defined by the spec.
+func jsonExpr(v any, rng hcl.Range) (hclsyntax.Expression, error) {
+	switch v := v.(type) {
+	case nil:
+		return &hclsyntax.LiteralValueExpr{Val: cty.NullVal(cty.DynamicPseudoType), SrcRange: rng}, nil
+	case bool:
+		return &hclsyntax.LiteralValueExpr{Val: cty.BoolVal(v), SrcRange: rng}, nil
+	case json.Number:
+		n, err := cty.ParseNumberVal(v.String())
+		if err != nil {
+			return nil, err
+		}
+		return &hclsyntax.LiteralValueExpr{Val: n, SrcRange: rng}, nil
+	case string:
+		x, diags := hclsyntax.ParseTemplate([]byte(v), rng.Filename, rng.Start)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return x, nil
+	case []any:
+		x := &hclsyntax.TupleConsExpr{SrcRange: rng, OpenRange: rng}
+		for _, e := range v {
+			ex, err := jsonExpr(e, rng)
+			if err != nil {
+				return nil, err
+			}
+			x.Exprs = append(x.Exprs, ex)
+		}
+		return x, nil
+	case []jsonProp:
+		x := &hclsyntax.ObjectConsExpr{SrcRange: rng, OpenRange: rng}
+		for _, p := range v {
+			ex, err := jsonExpr(p.value, rng)
+			if err != nil {
+				return nil, err
+			}
+			x.Items = append(x.Items, hclsyntax.ObjectConsItem{
+				KeyExpr:   &hclsyntax.ObjectConsKeyExpr{Wrapped: &hclsyntax.LiteralValueExpr{Val: cty.StringVal(p.key), SrcRange: rng}},
+				ValueExpr: ex,
+			})
+		}
+		return x, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON value %T", v)
+	}
+}
+
+// jsonProp is a property of a JSON object. Objects are decoded
+// as a list of properties, as their order must be preserved.
+type jsonProp struct {
+	key   string
+	value any
+}
+
+// readJSON reads the next JSON value from the decoder.
+func readJSON(dec *json.Decoder) (any, error) {
+	t, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case json.Delim('{'):
+		obj := make([]jsonProp, 0)
+		for dec.More() {
+			k, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := readJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, jsonProp{key: k.(string), value: v})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := make([]any, 0)
+		for dec.More() {
+			v, err := readJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return t, nil
+	}
+}
+
+// writeJSONBody writes the attributes and children of the resource as a JSON object.
+func (s *State) writeJSONBody(w *bytes.Buffer, r *Resource) error {
+	attrs, children := flatten(r)
+	w.WriteByte('{')
+	n := 0
+	sep := func(k string) {
+		if n > 0 {
+			w.WriteByte(',')
+		}
+		n++
+		writeJSONString(w, k)
+		w.WriteByte(':')
+	}
+	for _, a := range attrs {
+		v, ok, err := s.attrJSON(a)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		sep(a.K)
+		w.Write(v)
+	}
+	// Blocks are grouped by their types and labels, in order of appearance.
+	type group struct {
+		keys   []string
+		groups map[string]*group
+		blocks []*Resource
+	}
+	var (
+		types []string
+		roots = make(map[string]*group)
+	)
+	for _, c := range children {
+		g, ok := roots[c.Type]
+		if !ok {
+			g = &group{groups: make(map[string]*group)}
+			roots[c.Type], types = g, append(types, c.Type)
+		}
+		for _, l := range labels(c) {
+			ng, ok := g.groups[l]
+			if !ok {
+				ng = &group{groups: make(map[string]*group)}
+				g.groups[l], g.keys = ng, append(g.keys, l)
+			}
+			g = ng
+		}
+		g.blocks = append(g.blocks, c)
+	}
+	var writeGroup func(string, *group) error
+	writeGroup = func(typ string, g *group) error {
+		switch {
+		case len(g.blocks) > 0 && len(g.keys) > 0:
+			return fmt.Errorf("schemahcl: cannot encode labeled and unlabeled %q blocks at the same level in JSON", typ)
+		case len(g.blocks) == 1:
+			return s.writeJSONBody(w, g.blocks[0])
+		case len(g.blocks) > 1:
+			w.WriteByte('[')
+			for i, b := range g.blocks {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				if err := s.writeJSONBody(w, b); err != nil {
+					return err
+				}
+			}
+			w.WriteByte(']')
+			return nil
+		}
+		w.WriteByte('{')
+		for i, k := range g.keys {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeJSONString(w, k)
+			w.WriteByte(':')
+			if err := writeGroup(typ, g.groups[k]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('}')
+		return nil
+	}
+	for _, t := range types {
+		sep(t)
+		if err := writeGroup(t, roots[t]); err != nil {
+			return err
+		}
+	}
+	w.WriteByte('}')
+	return nil
+}
+
+// attrJSON returns the JSON encoding of the attribute value. False is
+// returned if the attribute is omitted, as done by the HCL encoding.
+func (s *State) attrJSON(a *Attr) ([]byte, bool, error) {
+	// Plain values are encoded as JSON values.
+	if t := a.V.Type(); !t.IsCapsuleType() && !((t.IsListType() || t.IsSetType()) && t.ElementType().IsCapsuleType()) {
+		b, err := ctyjson.Marshal(a.V, t)
+		if err != nil {
+			return nil, false, err
+		}
+		// Re-encode the value to avoid escaping HTML characters (e.g., "<").
+		// Object keys are sorted by both encoders, and numbers are kept as-is.
+		var (
+			x   any
+			dec = json.NewDecoder(bytes.NewReader(b))
+		)
+		dec.UseNumber()
+		if err := dec.Decode(&x); err != nil {
+			return nil, false, err
+		}
+		var w bytes.Buffer
+		enc := json.NewEncoder(&w)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(x); err != nil {
+			return nil, false, err
+		}
+		return escapeTemplate(bytes.TrimSuffix(w.Bytes(), []byte("\n"))), true, nil
+	}
+	if a.V.Type().IsListType() {
+		if a.V.LengthInt() == 0 {
+			return nil, false, nil
+		}
+		var w bytes.Buffer
+		w.WriteByte('[')
+		for i, v := range a.V.AsValueSlice() {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			switch ev := v.EncapsulatedValue().(type) {
+			case *Ref:
+				ts, err := hclRefTokens(ev.V)
+				if err != nil {
+					return nil, false, err
+				}
+				writeJSONString(&w, "${"+string(ts.Bytes())+"}")
+			case *EnumString:
+				if ev.E != "" {
+					writeJSONString(&w, "${"+ev.E+"}")
+				} else {
+					writeJSONString(&w, strings.NewReplacer("${", "$${", "%{", "%%{").Replace(ev.S))
+				}
+			default:
+				return nil, false, fmt.Errorf("unsupported capsule type: %v", v.Type())
+			}
+		}
+		w.WriteByte(']')
+		return w.Bytes(), true, nil
+	}
+	// References, types and raw expressions are
+	// encoded as templates of their HCL expressions.
+	scratch := hclwrite.NewEmptyFile().Body()
+	if err := s.writeAttr(a, scratch); err != nil {
+		return nil, false, err
+	}
+	at := scratch.GetAttribute(a.K)
+	if at == nil {
+		return nil, false, nil
+	}
+	var w bytes.Buffer
+	writeJSONString(&w, "${"+strings.TrimSpace(string(at.Expr().BuildTokens(nil).Bytes()))+"}")
+	return w.Bytes(), true, nil
+}
+
+// escapeTemplate escapes the template sequences in the JSON encoded value.
+// Such sequences can only appear in strings, and therefore are always escaped.
+func escapeTemplate(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("${"), []byte("$${"))
+	return bytes.ReplaceAll(b, []byte("%{"), []byte("%%{"))
+}
+
+// writeJSONString writes s as a JSON string without escaping HTML characters.
+func writeJSONString(w *bytes.Buffer, s string) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	// Remove the newline added by the encoder.
+	w.Truncate(w.Len() - 1)
+}
@@ -0,0 +1,197 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestMarshalSpecJSON(t *testing.T) {
+	type (
+		column struct {
+			Name    string `spec:",name"`
+			Type    *Type  `spec:"type"`
+			Null    bool   `spec:"null,omitempty"`
+			Default string `spec:"default,omitempty"`
+		}
+		doc struct {
+			Schema []*struct {
+				Name string `spec:",name"`
+			} `spec:"schema"`
+			Table []*struct {
+				Name       string    `spec:",name"`
+				Qualifier  string    `spec:",qualifier"`
+				Schema     *Ref      `spec:"schema"`
+				Comment    string    `spec:"comment,omitempty"`
+				Tags       []string  `spec:"tags,omitempty"`
+				Columns    []*column `spec:"column"`
+				PrimaryKey *struct {
+					Columns []*Ref `spec:"columns"`
+				} `spec:"primary_key"`
+				Checks []*struct {
+					Expr string `spec:"expr"`
+				} `spec:"check"`
+			} `spec:"table"`
+		}
+	)
+	const text = `schema "public" {
+}
+table "users" {
+  schema  = schema.public
+  comment = "costs $${price}"
+  tags    = ["a", "b"]
+  column "id" {
+    type = int
+  }
+  column "name" {
+    type    = varchar(255)
+    null    = true
+    default = "unknown"
+  }
+  primary_key {
+    columns = [table.users.column.id]
+  }
+  check {
+    expr = "id > 0"
+  }
+  check {
+    expr = "id < 100"
+  }
+}
+table "other" "users" {
+  schema = schema.public
+  column "id" {
+    type = int
+  }
+}
+`
+	s := New(WithTypes("table.column.type", []*TypeSpec{
+		{Name: "int", T: "int"},
+		{Name: "varchar", T: "varchar", Attributes: []*TypeAttr{{Name: "size", Kind: reflect.Int}}},
+	}))
+	var d doc
+	require.NoError(t, s.EvalBytes([]byte(text), &d, nil))
+	require.Equal(t, "costs ${price}", d.Table[0].Comment)
+
+	b, err := s.MarshalSpecJSON(&d)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "schema": {
+    "public": {}
+  },
+  "table": {
+    "users": {
+      "schema": "${schema.public}",
+      "comment": "costs $${price}",
+      "tags": [
+        "a",
+        "b"
+      ],
+      "column": {
+        "id": {
+          "type": "${int}"
+        },
+        "name": {
+          "type": "${varchar(255)}",
+          "null": true,
+          "default": "unknown"
+        }
+      },
+      "primary_key": {
+        "columns": [
+          "${table.users.column.id}"
+        ]
+      },
+      "check": [
+        {
+          "expr": "id > 0"
+        },
+        {
+          "expr": "id < 100"
+        }
+      ]
+    },
+    "other": {
+      "users": {
+        "schema": "${schema.public}",
+        "column": {
+          "id": {
+            "type": "${int}"
+          }
+        }
+      }
+    }
+  }
+}`, string(b))
+
+	var fromJSON doc
+	require.NoError(t, s.EvalJSONBytes(b, &fromJSON, nil))
+	require.Equal(t, d, fromJSON)
+	out, err := s.MarshalSpec(&fromJSON)
+	require.NoError(t, err)
+	require.Equal(t, text, string(out))
+}
+
+func TestEvalJSONBytes(t *testing.T) {
+	var d struct {
+		Table []*struct {
+			Name    string `spec:",name"`
+			Comment string `spec:"comment"`
+			Columns []*struct {
+				Name string `spec:",name"`
+				Type string `spec:"type"`
+			} `spec:"column"`
+		} `spec:"table"`
+	}
+	err := New().EvalJSONBytes([]byte(`{
+  "//": "variables, locals and dynamic blocks are supported as well",
+  "variable": {
+    "tenant": {
+      "type": "${string}"
+    }
+  },
+  "locals": {
+    "columns": ["created_at", "updated_at"]
+  },
+  "table": [
+    {
+      "users": {
+        "comment": "users of ${var.tenant}",
+        "column": {
+          "id": {
+            "type": "int"
+          }
+        },
+        "dynamic": {
+          "column": {
+            "for_each": "${local.columns}",
+            "labels": ["${column.value}"],
+            "content": {
+              "type": "timestamp"
+            }
+          }
+        }
+      }
+    }
+  ]
+}`), &d, map[string]cty.Value{"tenant": cty.StringVal("acme")})
+	require.NoError(t, err)
+	require.Len(t, d.Table, 1)
+	require.Equal(t, "users", d.Table[0].Name)
+	require.Equal(t, "users of acme", d.Table[0].Comment)
+	require.Len(t, d.Table[0].Columns, 3)
+	require.Equal(t, "id", d.Table[0].Columns[0].Name)
+	require.Equal(t, "created_at", d.Table[0].Columns[1].Name)
+	require.Equal(t, "timestamp", d.Table[0].Columns[2].Type)
+
+	err = New().EvalJSONBytes([]byte(`[]`), &d, nil)
+	require.EqualError(t, err, "schemahcl: expected JSON document to be an object")
+	err = New().EvalJSONBytes([]byte(`{"table": "users"}`), &d, nil)
+	require.EqualError(t, err, `schemahcl: expected block "table" to be an object or an array of objects`)
+}
@@ -0,0 +1,181 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// jsonSchema is the subset of JSON Schema used for describing HCL documents.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	// AdditionalProperties is either a boolean or a *jsonSchema.
+	AdditionalProperties any           `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema   `json:"items,omitempty"`
+	Enum                 []string      `json:"enum,omitempty"`
+	Pattern              string        `json:"pattern,omitempty"`
+	AnyOf                []*jsonSchema `json:"anyOf,omitempty"`
+	Deprecated           bool          `json:"deprecated,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema that describes the HCL documents that are
+// decoded into the given document type, for editors to autocomplete and validate
+// them. The document is expected to be a pointer to a struct whose fields are
+// tagged with "spec" (e.g., the document of a driver), and the types and enums
+// that were configured for the State (see WithTypes and WithScopedEnums) are used
+// to describe the attributes they are allowed in.
+//
+// Blocks are described in their HCL-JSON form, where named blocks are objects
+// keyed by their names, and attributes that hold identifiers or function calls
+// (e.g., column types) are described by their source text. e.g., "varchar(255)".
+func (s *State) JSONSchema(doc any) ([]byte, error) {
+	t := reflect.TypeOf(doc)
+	if t == nil || indirect(t).Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schemahcl: expected document to be a struct, got %T", doc)
+	}
+	root := s.blockSchema(indirect(t), "", nil)
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	// Documents can hold other top-level blocks, such as variables or locals.
+	root.AdditionalProperties = true
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// blockSchema returns the schema of the block of the given type and path.
+func (s *State) blockSchema(t reflect.Type, path string, seen []reflect.Type) *jsonSchema {
+	b := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema), AdditionalProperties: false}
+	if slices.Contains(seen, t) {
+		b.AdditionalProperties = true
+		return b
+	}
+	seen = append(seen, t)
+	for i := 0; i < t.NumField(); i++ {
+		// Extended blocks can hold additional attributes and blocks.
+		if f := t.Field(i); f.Anonymous && f.Type == reflect.TypeOf(DefaultExtension{}) {
+			b.AdditionalProperties = true
+		}
+	}
+	for _, f := range specFields(reflect.New(t).Interface()) {
+		if f.isName() || f.isQualifier() || f.isRange() || f.tag == "" {
+			continue
+		}
+		p := f.tag
+		if path != "" {
+			p = path + "." + f.tag
+		}
+		switch ft := f.Type; {
+		case isResourceSlice(ft) && !isAttrType(ft.Elem()):
+			child := s.blockSchema(indirect(ft.Elem()), p, seen)
+			if named(indirect(ft.Elem())) {
+				b.Properties[f.tag] = &jsonSchema{Type: "object", AdditionalProperties: child}
+			} else {
+				b.Properties[f.tag] = &jsonSchema{Type: "array", Items: child}
+			}
+		case isSingleResource(ft) && !isAttrType(ft):
+			b.Properties[f.tag] = s.blockSchema(indirect(ft), p, seen)
+		default:
+			b.Properties[f.tag] = s.attrSchema(ft, p)
+		}
+		if repl, ok := f.deprecated(); ok {
+			x := *b.Properties[f.tag]
+			x.Deprecated = true
+			if repl != "" {
+				x.Description = strings.TrimSpace(fmt.Sprintf("%s Deprecated: use %q instead.", x.Description, repl))
+			}
+			b.Properties[f.tag] = &x
+		}
+	}
+	// Scoped enums and types can be configured for attributes
+	// that are not defined by the block type (extension attributes).
+	for _, p := range slices.Sorted(maps.Keys(s.config.pathVars)) {
+		name, ok := strings.CutPrefix(p, path+".")
+		if path == "" || !ok || strings.Contains(name, ".") {
+			continue
+		}
+		if _, ok := b.Properties[name]; !ok {
+			b.Properties[name] = s.attrSchema(reflect.TypeOf(""), p)
+		}
+	}
+	return b
+}
+
+// attrSchema returns the schema of the attribute of the given type and path.
+func (s *State) attrSchema(t reflect.Type, path string) *jsonSchema {
+	if x := s.identSchema(path); x != nil {
+		return x
+	}
+	switch t {
+	case reflect.TypeOf(&Ref{}):
+		return &jsonSchema{Type: "string", Description: "A reference to a block. e.g., column.id"}
+	case reflect.TypeOf(&Type{}):
+		return &jsonSchema{Type: "string"}
+	case reflect.TypeOf(cty.Value{}):
+		return &jsonSchema{}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice:
+		return &jsonSchema{Type: "array", Items: s.attrSchema(t.Elem(), "")}
+	default:
+		return &jsonSchema{}
+	}
+}
+
+// identSchema returns the schema of an attribute that holds one of the
+// identifiers or function calls configured for its path, if there are any.
+func (s *State) identSchema(path string) *jsonSchema {
+	vars, funcs := s.config.pathVars[path], s.config.pathFuncs[path]
+	if len(vars) == 0 && len(funcs) == 0 {
+		return nil
+	}
+	var x []*jsonSchema
+	if len(vars) > 0 {
+		x = append(x, &jsonSchema{Type: "string", Enum: slices.Sorted(maps.Keys(vars))})
+	}
+	if len(funcs) > 0 {
+		names := slices.Sorted(maps.Keys(funcs))
+		for i := range names {
+			names[i] = regexp.QuoteMeta(names[i])
+		}
+		x = append(x, &jsonSchema{Type: "string", Pattern: fmt.Sprintf(`^(%s)\(.*\)$`, strings.Join(names, "|"))})
+	}
+	if len(x) == 1 {
+		return x[0]
+	}
+	return &jsonSchema{AnyOf: x}
+}
+
+// named reports if the block type has a name label.
+func named(t reflect.Type) bool {
+	return slices.ContainsFunc(specFields(reflect.New(t).Interface()), fieldDesc.isName)
+}
+
+// isAttrType reports if the struct type is decoded from an attribute value.
+func isAttrType(t reflect.Type) bool {
+	switch indirect(t) {
+	case reflect.TypeOf(Ref{}), reflect.TypeOf(Type{}), reflect.TypeOf(cty.Value{}), reflect.TypeOf(hcl.Range{}):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestState_JSONSchema(t *testing.T) {
+	type (
+		column struct {
+			Name string           `spec:",name"`
+			Null bool             `spec:"null"`
+			Type *schemahcl.Type  `spec:"type"`
+			Tags []string         `spec:"tags"`
+			Ref  []*schemahcl.Ref `spec:"refs"`
+			schemahcl.DefaultExtension
+		}
+		part struct {
+			Column *schemahcl.Ref `spec:"column"`
+			Desc   bool           `spec:"desc"`
+		}
+		table struct {
+			Name    string    `spec:",name"`
+			Columns []*column `spec:"column"`
+			Parts   []*part   `spec:"on"`
+			Size    int       `spec:"size"`
+			schemahcl.DefaultExtension
+		}
+		doc struct {
+			Tables []*table `spec:"table"`
+		}
+	)
+	s := schemahcl.New(
+		schemahcl.WithTypes("table.column.type", []*schemahcl.TypeSpec{
+			schemahcl.NewTypeSpec("int"),
+			schemahcl.NewTypeSpec("varchar", schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
+			schemahcl.NewTypeSpec("text", schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		}),
+		schemahcl.WithScopedEnums("table.engine", "InnoDB", "MyISAM"),
+	)
+	b, err := s.JSONSchema(&doc{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "additionalProperties": true,
+  "properties": {
+    "table": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "additionalProperties": true,
+        "properties": {
+          "column": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "object",
+              "additionalProperties": true,
+              "properties": {
+                "null": {"type": "boolean"},
+                "refs": {"type": "array", "items": {"type": "string", "description": "A reference to a block. e.g., column.id"}},
+                "tags": {"type": "array", "items": {"type": "string"}},
+                "type": {
+                  "anyOf": [
+                    {"type": "string", "enum": ["int", "text"]},
+                    {"type": "string", "pattern": "^(text|varchar)\\(.*\\)$"}
+                  ]
+                }
+              }
+            }
+          },
+          "engine": {"type": "string", "enum": ["InnoDB", "MyISAM"]},
+          "on": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "additionalProperties": false,
+              "properties": {
+                "column": {"type": "string", "description": "A reference to a block. e.g., column.id"},
+                "desc": {"type": "boolean"}
+              }
+            }
+          },
+          "size": {"type": "integer"}
+        }
+      }
+    }
+  }
+}`, string(b))
+
+	_, err = s.JSONSchema("doc")
+	require.EqualError(t, err, "schemahcl: expected document to be a struct, got string")
+}
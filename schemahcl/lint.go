@@ -0,0 +1,390 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+type (
+	// A LintRule is a user-defined rule that is evaluated against the elements of
+	// a schema. Rules are defined in HCL files using "rule" blocks. For example:
+	//
+	//	rule "table_created_at" {
+	//	  description = "tables must have a created_at column"
+	//	  on          = "table"
+	//	  assert      = contains(self.columns[*].name, "created_at")
+	//	  message     = "table ${self.name} is missing a created_at column"
+	//	}
+	//
+	//	rule "fk_indexed" {
+	//	  on       = "foreign_key"
+	//	  severity = "warning"
+	//	  assert   = self.indexed
+	//	}
+	//
+	// The assert and message expressions are evaluated for each element of the kind
+	// the rule is defined on, which is available as the "self" variable. The table and
+	// schema of the element are available as the "table" and "schema" variables.
+	LintRule struct {
+		Name        string
+		Description string
+		// On is the kind of elements the rule is evaluated on. One of:
+		// "schema", "table", "column", "index" or "foreign_key".
+		On       string
+		Severity LintSeverity
+		// Assert is the boolean expression that is expected to hold.
+		Assert hcl.Expression
+		// Message is the optional expression that describes a failure.
+		Message hcl.Expression
+		// Range is the source range of the rule block.
+		Range hcl.Range
+	}
+
+	// LintSeverity is the severity of the diagnostics reported by a rule.
+	LintSeverity string
+
+	// A Linter evaluates lint rules against schema elements.
+	Linter struct {
+		// Rules to evaluate.
+		Rules []*LintRule
+		// Funcs holds additional functions that can be used by the rules
+		// expressions, on top of the standard functions of the language.
+		Funcs map[string]function.Function
+	}
+
+	// A LintDiagnostic describes an element that failed a lint rule.
+	LintDiagnostic struct {
+		Rule     string
+		Severity LintSeverity
+		Text     string
+		// Path is the path of the element, in the form of its HCL
+		// reference. e.g., "schema.public.table.users.column.id".
+		Path string
+		// Range is the source range of the element, if
+		// it was loaded with its position. See WithPos.
+		Range *hcl.Range
+	}
+)
+
+// List of rule severities.
+const (
+	LintError   LintSeverity = "error"
+	LintWarning LintSeverity = "warning"
+)
+
+// List of element kinds that rules can be evaluated on.
+var lintKinds = []string{"schema", "table", "column", "index", "foreign_key"}
+
+// String implements the fmt.Stringer interface.
+func (d *LintDiagnostic) String() string {
+	var loc string
+	if d.Range != nil {
+		loc = d.Range.String() + ": "
+	}
+	return fmt.Sprintf("%s%s: %s (%s)", loc, d.Path, d.Text, d.Rule)
+}
+
+// ParseLintRules parses the "rule" blocks defined in the given HCL source.
+func ParseLintRules(filename string, src []byte) ([]*LintRule, error) {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body := f.Body.(*hclsyntax.Body)
+	if len(body.Attributes) > 0 {
+		a := body.Attributes[slices.Sorted(maps.Keys(body.Attributes))[0]]
+		return nil, fmt.Errorf("%s: unexpected attribute %q", a.NameRange, a.Name)
+	}
+	rules := make([]*LintRule, 0, len(body.Blocks))
+	for _, b := range body.Blocks {
+		if b.Type != "rule" || len(b.Labels) != 1 {
+			return nil, fmt.Errorf(`%s: expected a "rule" block with a name`, b.DefRange())
+		}
+		var r struct {
+			Description string         `hcl:"description,optional"`
+			On          string         `hcl:"on"`
+			Severity    string         `hcl:"severity,optional"`
+			Assert      hcl.Expression `hcl:"assert"`
+			Message     hcl.Expression `hcl:"message,optional"`
+		}
+		if diags := gohcl.DecodeBody(b.Body, nil, &r); diags.HasErrors() {
+			return nil, diags
+		}
+		if !slices.Contains(lintKinds, r.On) {
+			return nil, fmt.Errorf("%s: unknown element kind %q for rule %q", b.DefRange(), r.On, b.Labels[0])
+		}
+		rule := &LintRule{
+			Name:        b.Labels[0],
+			Description: r.Description,
+			On:          r.On,
+			Severity:    LintSeverity(r.Severity),
+			Assert:      r.Assert,
+			Message:     r.Message,
+			Range:       b.DefRange(),
+		}
+		switch rule.Severity {
+		case "":
+			rule.Severity = LintError
+		case LintError, LintWarning:
+		default:
+			return nil, fmt.Errorf("%s: unknown severity %q for rule %q", b.DefRange(), r.Severity, b.Labels[0])
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Lint evaluates the rules against the elements of the realm, and returns the
+// diagnostics of the elements that failed them, ordered by the realm elements.
+func (l *Linter) Lint(r *schema.Realm) ([]*LintDiagnostic, error) {
+	funcs := stdFuncs()
+	for n, f := range l.Funcs {
+		funcs[n] = f
+	}
+	lt := &linter{Linter: l, funcs: funcs}
+	for _, s := range r.Schemas {
+		sv := schemaVal(s)
+		sp := "schema." + s.Name
+		if err := lt.eval("schema", sp, s.Attrs, map[string]cty.Value{"self": sv, "schema": sv}); err != nil {
+			return nil, err
+		}
+		for _, t := range s.Tables {
+			tv := tableVal(t)
+			tp := sp + ".table." + t.Name
+			if err := lt.eval("table", tp, t.Attrs, map[string]cty.Value{"self": tv, "table": tv, "schema": sv}); err != nil {
+				return nil, err
+			}
+			vars := func(self cty.Value) map[string]cty.Value {
+				return map[string]cty.Value{"self": self, "table": tv, "schema": sv}
+			}
+			for _, c := range t.Columns {
+				if err := lt.eval("column", tp+".column."+c.Name, c.Attrs, vars(columnVal(c))); err != nil {
+					return nil, err
+				}
+			}
+			for _, idx := range t.Indexes {
+				if err := lt.eval("index", tp+".index."+idx.Name, idx.Attrs, vars(indexVal(idx))); err != nil {
+					return nil, err
+				}
+			}
+			for _, fk := range t.ForeignKeys {
+				if err := lt.eval("foreign_key", tp+".foreign_key."+fk.Symbol, fk.Attrs, vars(foreignKeyVal(t, fk))); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return lt.diags, nil
+}
+
+type linter struct {
+	*Linter
+	funcs map[string]function.Function
+	diags []*LintDiagnostic
+}
+
+// eval evaluates the rules of the given kind against an element.
+func (l *linter) eval(kind, path string, attrs []schema.Attr, vars map[string]cty.Value) error {
+	ctx := &hcl.EvalContext{Variables: vars, Functions: l.funcs}
+	for _, r := range l.Rules {
+		if r.On != kind {
+			continue
+		}
+		v, diags := r.Assert.Value(ctx)
+		if diags.HasErrors() {
+			return fmt.Errorf("evaluating rule %q on %s: %w", r.Name, path, diags)
+		}
+		if v.IsNull() || !v.IsKnown() || v.Type() != cty.Bool {
+			return fmt.Errorf("evaluating rule %q on %s: assert must be a boolean, got %s", r.Name, path, v.Type().FriendlyName())
+		}
+		if v.True() {
+			continue
+		}
+		d := &LintDiagnostic{Rule: r.Name, Severity: r.Severity, Path: path, Text: r.Description}
+		if r.Message != nil {
+			m, diags := r.Message.Value(ctx)
+			if diags.HasErrors() {
+				return fmt.Errorf("evaluating message of rule %q on %s: %w", r.Name, path, diags)
+			}
+			switch {
+			case m.IsNull():
+			case m.IsKnown() && m.Type() == cty.String:
+				d.Text = m.AsString()
+			default:
+				return fmt.Errorf("evaluating message of rule %q on %s: message must be a string, got %s", r.Name, path, m.Type().FriendlyName())
+			}
+		}
+		if d.Text == "" {
+			d.Text = fmt.Sprintf("%s does not satisfy rule %q", kind, r.Name)
+		}
+		for _, a := range attrs {
+			if p, ok := a.(*schema.Pos); ok {
+				d.Range = &hcl.Range{Filename: p.Filename, Start: hcl.Pos(p.Start), End: hcl.Pos(p.End)}
+			}
+		}
+		l.diags = append(l.diags, d)
+	}
+	return nil
+}
+
+func schemaVal(s *schema.Schema) cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":    cty.StringVal(s.Name),
+		"comment": commentVal(s.Attrs),
+		"tables": namesVal(len(s.Tables), func(i int) string {
+			return s.Tables[i].Name
+		}),
+	})
+}
+
+func tableVal(t *schema.Table) cty.Value {
+	cs := make([]cty.Value, len(t.Columns))
+	for i, c := range t.Columns {
+		cs[i] = columnVal(c)
+	}
+	idxs := make([]cty.Value, len(t.Indexes))
+	for i, idx := range t.Indexes {
+		idxs[i] = indexVal(idx)
+	}
+	fks := make([]cty.Value, len(t.ForeignKeys))
+	for i, fk := range t.ForeignKeys {
+		fks[i] = foreignKeyVal(t, fk)
+	}
+	pk := cty.NullVal(cty.DynamicPseudoType)
+	if t.PrimaryKey != nil {
+		pk = partsVal(t.PrimaryKey.Parts)
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":         cty.StringVal(t.Name),
+		"comment":      commentVal(t.Attrs),
+		"columns":      cty.TupleVal(cs),
+		"primary_key":  pk,
+		"indexes":      cty.TupleVal(idxs),
+		"foreign_keys": cty.TupleVal(fks),
+	})
+}
+
+func columnVal(c *schema.Column) cty.Value {
+	var (
+		null bool
+		typ  string
+		def  = cty.NullVal(cty.String)
+	)
+	if c.Type != nil {
+		null = c.Type.Null
+		typ = typeName(c.Type)
+	}
+	switch x := schema.UnderlyingExpr(c.Default).(type) {
+	case *schema.Literal:
+		def = cty.StringVal(x.V)
+	case *schema.RawExpr:
+		def = cty.StringVal(x.X)
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":    cty.StringVal(c.Name),
+		"type":    cty.StringVal(typ),
+		"null":    cty.BoolVal(null),
+		"default": def,
+		"comment": commentVal(c.Attrs),
+	})
+}
+
+func indexVal(idx *schema.Index) cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":    cty.StringVal(idx.Name),
+		"unique":  cty.BoolVal(idx.Unique),
+		"columns": partsVal(idx.Parts),
+		"comment": commentVal(idx.Attrs),
+	})
+}
+
+func foreignKeyVal(t *schema.Table, fk *schema.ForeignKey) cty.Value {
+	refT := cty.NullVal(cty.String)
+	if fk.RefTable != nil {
+		refT = cty.StringVal(fk.RefTable.Name)
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal(fk.Symbol),
+		"columns": namesVal(len(fk.Columns), func(i int) string {
+			return fk.Columns[i].Name
+		}),
+		"ref_table": refT,
+		"ref_columns": namesVal(len(fk.RefColumns), func(i int) string {
+			return fk.RefColumns[i].Name
+		}),
+		"indexed": cty.BoolVal(fkIndexed(t, fk)),
+	})
+}
+
+// fkIndexed reports if the columns of the foreign key are the
+// leftmost columns of the primary key or one of the table indexes.
+func fkIndexed(t *schema.Table, fk *schema.ForeignKey) bool {
+	covers := func(idx *schema.Index) bool {
+		if idx == nil || len(idx.Parts) < len(fk.Columns) {
+			return false
+		}
+		for i, c := range fk.Columns {
+			if idx.Parts[i].C == nil || idx.Parts[i].C.Name != c.Name {
+				return false
+			}
+		}
+		return true
+	}
+	return len(fk.Columns) > 0 && (covers(t.PrimaryKey) || slices.ContainsFunc(t.Indexes, covers))
+}
+
+// partsVal returns the column names of the index parts.
+// Expression parts are represented as empty strings.
+func partsVal(parts []*schema.IndexPart) cty.Value {
+	return namesVal(len(parts), func(i int) string {
+		if parts[i].C != nil {
+			return parts[i].C.Name
+		}
+		return ""
+	})
+}
+
+func namesVal(n int, name func(int) string) cty.Value {
+	vs := make([]cty.Value, n)
+	for i := range vs {
+		vs[i] = cty.StringVal(name(i))
+	}
+	return cty.TupleVal(vs)
+}
+
+func commentVal(attrs []schema.Attr) cty.Value {
+	for _, a := range attrs {
+		if c, ok := a.(*schema.Comment); ok {
+			return cty.StringVal(c.Text)
+		}
+	}
+	return cty.NullVal(cty.String)
+}
+
+// typeName returns the name of the column type, as it is defined by
+// the driver (e.g., "varchar" or "bigint"), if it is available.
+func typeName(ct *schema.ColumnType) string {
+	if ct.Raw != "" {
+		return ct.Raw
+	}
+	if t := reflect.Indirect(reflect.ValueOf(ct.Type)); t.Kind() == reflect.Struct {
+		if f := t.FieldByName("T"); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl_test
+
+import (
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	rules, err := schemahcl.ParseLintRules("rules.hcl", []byte(`
+rule "snake_case" {
+  description = "names must be snake_case"
+  on          = "table"
+  assert      = can(regex("^[a-z][a-z0-9_]*$", self.name))
+}
+
+rule "created_at" {
+  on      = "table"
+  assert  = contains(self.columns[*].name, "created_at")
+  message = "table ${self.name} is missing a created_at column"
+}
+
+rule "fk_indexed" {
+  on       = "foreign_key"
+  severity = "warning"
+  assert   = self.indexed
+  message  = "columns ${join(", ", self.columns)} of ${table.name} are not indexed"
+}
+
+rule "no_tmp" {
+  on     = "column"
+  assert = !has_prefix(self.name, "tmp_")
+}
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 4)
+	require.Equal(t, schemahcl.LintWarning, rules[2].Severity)
+	require.Equal(t, 2, rules[0].Range.Start.Line)
+
+	var (
+		pos = func(line int) *schema.Pos {
+			p := schema.NewFilePos("schema.hcl")
+			p.Start.Line, p.End.Line = line, line+1
+			return p
+		}
+		users = schema.NewTable("users").
+			AddAttrs(pos(1)).
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewTimeColumn("created_at", "timestamp"),
+			)
+		posts = schema.NewTable("Posts").
+			AddAttrs(pos(10)).
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("author_id", "int"),
+				schema.NewIntColumn("tmp_x", "int").AddAttrs(pos(13)),
+			)
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	posts.AddForeignKeys(
+		schema.NewForeignKey("author").
+			AddColumns(posts.Columns[1]).
+			SetRefTable(users).
+			AddRefColumns(users.Columns[0]).
+			AddAttrs(pos(15)),
+	)
+	r := schema.NewRealm(schema.New("public").AddTables(users, posts))
+	l := &schemahcl.Linter{
+		Rules: rules,
+		Funcs: map[string]function.Function{
+			"has_prefix": function.New(&function.Spec{
+				Params: []function.Parameter{{Name: "s", Type: cty.String}, {Name: "prefix", Type: cty.String}},
+				Type:   function.StaticReturnType(cty.Bool),
+				Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+					return cty.BoolVal(strings.HasPrefix(args[0].AsString(), args[1].AsString())), nil
+				},
+			}),
+		},
+	}
+	diags, err := l.Lint(r)
+	require.NoError(t, err)
+	require.Len(t, diags, 4)
+	require.Equal(t, "schema.hcl:10,0-11,0: schema.public.table.Posts: names must be snake_case (snake_case)", diags[0].String())
+	require.Equal(t, "table Posts is missing a created_at column", diags[1].Text)
+	require.Equal(t, schemahcl.LintError, diags[1].Severity)
+	require.Equal(t, "schema.public.table.Posts.column.tmp_x", diags[2].Path)
+	require.Equal(t, `column does not satisfy rule "no_tmp"`, diags[2].Text)
+	require.Equal(t, &hcl.Range{Filename: "schema.hcl", Start: hcl.Pos{Line: 13}, End: hcl.Pos{Line: 14}}, diags[2].Range)
+	require.Equal(t, "fk_indexed", diags[3].Rule)
+	require.Equal(t, schemahcl.LintWarning, diags[3].Severity)
+	require.Equal(t, "columns author_id of Posts are not indexed", diags[3].Text)
+
+	// Indexed foreign keys pass the rule.
+	posts.AddIndexes(schema.NewIndex("author_id").AddColumns(posts.Columns[1], posts.Columns[0]))
+	diags, err = l.Lint(r)
+	require.NoError(t, err)
+	require.Len(t, diags, 3)
+
+	_, err = schemahcl.ParseLintRules("rules.hcl", []byte(`
+rule "x" {
+  on     = "trigger"
+  assert = true
+}`))
+	require.EqualError(t, err, `rules.hcl:2,1-9: unknown element kind "trigger" for rule "x"`)
+	_, err = schemahcl.ParseLintRules("rules.hcl", []byte(`
+rule "x" {
+  on       = "table"
+  severity = "fatal"
+  assert   = true
+}`))
+	require.EqualError(t, err, `rules.hcl:2,1-9: unknown severity "fatal" for rule "x"`)
+	rules, err = schemahcl.ParseLintRules("rules.hcl", []byte(`
+rule "x" {
+  on     = "table"
+  assert = self.name
+}`))
+	require.NoError(t, err)
+	_, err = (&schemahcl.Linter{Rules: rules}).Lint(r)
+	require.EqualError(t, err, `evaluating rule "x" on schema.public.table.users: assert must be a boolean, got string`)
+}
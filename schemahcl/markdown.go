@@ -0,0 +1,205 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MarshalMarkdown renders v into a human-readable Markdown document (e.g., a data
+// dictionary of a schema), using the same spec that is used for encoding it as HCL.
+// Each top-level block is rendered as a section, where its "comment" attribute is used
+// as its description and the rest of its attributes are listed in a table. The children
+// of the block are rendered as tables grouped by their types, with one row per block
+// and one column per attribute. For example:
+//
+//	## table `users`
+//
+//	Users of the application.
+//
+//	| Attribute | Value           |
+//	|-----------|-----------------|
+//	| schema    | `schema.public` |
+//
+//	### column
+//
+//	| Name | type           | null   | comment           |
+//	|------|----------------|--------|-------------------|
+//	| id   | `int`          |        |                   |
+//	| name | `varchar(255)` | `true` | Full name of user |
+//
+// Expressions, such as types and references, are rendered by their source text, and
+// blocks that are nested in child blocks (e.g., index parts) are not rendered.
+func (s *State) MarshalMarkdown(v any) ([]byte, error) {
+	r := &Resource{}
+	if err := r.Scan(v); err != nil {
+		return nil, fmt.Errorf("schemahcl: failed scanning %T to resource: %w", v, err)
+	}
+	if r.Type != "" {
+		r = &Resource{Children: []*Resource{r}}
+	}
+	var b bytes.Buffer
+	_, children := flatten(r)
+	for i, c := range children {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if err := s.writeMarkdown(&b, c); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// writeMarkdown writes the section of a top-level resource.
+func (s *State) writeMarkdown(b *bytes.Buffer, r *Resource) error {
+	b.WriteString("## ")
+	b.WriteString(r.Type)
+	if l := labels(r); len(l) > 0 {
+		fmt.Fprintf(b, " `%s`", strings.Join(l, "."))
+	}
+	b.WriteString("\n")
+	attrs, children := flatten(r)
+	rows := make([][]string, 0, len(attrs))
+	for _, a := range attrs {
+		v, ok, err := s.mdValue(a)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !ok:
+		case a.K == "comment" && a.V.Type() == cty.String:
+			fmt.Fprintf(b, "\n%s\n", a.V.AsString())
+		default:
+			rows = append(rows, []string{mdEscape(a.K), v})
+		}
+	}
+	if len(rows) > 0 {
+		b.WriteByte('\n')
+		writeMarkdownTable(b, []string{"Attribute", "Value"}, rows)
+	}
+	var (
+		types  []string
+		groups = make(map[string][]*Resource)
+	)
+	for _, c := range children {
+		if _, ok := groups[c.Type]; !ok {
+			types = append(types, c.Type)
+		}
+		groups[c.Type] = append(groups[c.Type], c)
+	}
+	for _, t := range types {
+		if err := s.writeMarkdownGroup(b, t, groups[t]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMarkdownGroup writes the children of the same type as a table.
+func (s *State) writeMarkdownGroup(b *bytes.Buffer, typ string, children []*Resource) error {
+	var (
+		keys   []string
+		named  bool
+		values = make([]map[string]string, len(children))
+	)
+	for i, c := range children {
+		named = named || len(labels(c)) > 0
+		attrs, _ := flatten(c)
+		values[i] = make(map[string]string, len(attrs))
+		for _, a := range attrs {
+			v, ok, err := s.mdValue(a)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if !slices.Contains(keys, a.K) {
+				keys = append(keys, a.K)
+			}
+			values[i][a.K] = v
+		}
+	}
+	header := make([]string, 0, len(keys)+1)
+	if named {
+		header = append(header, "Name")
+	}
+	for _, k := range keys {
+		header = append(header, mdEscape(k))
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	rows := make([][]string, len(children))
+	for i, c := range children {
+		if named {
+			rows[i] = append(rows[i], mdEscape(strings.Join(labels(c), ".")))
+		}
+		for _, k := range keys {
+			rows[i] = append(rows[i], values[i][k])
+		}
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", typ)
+	writeMarkdownTable(b, header, rows)
+	return nil
+}
+
+// mdValue returns the Markdown representation of the attribute value.
+// False is returned if the attribute is omitted, as done by the HCL encoding.
+func (s *State) mdValue(a *Attr) (string, bool, error) {
+	if a.V.Type() == cty.String && !a.V.IsNull() {
+		return mdEscape(a.V.AsString()), true, nil
+	}
+	scratch := hclwrite.NewEmptyFile().Body()
+	if err := s.writeAttr(a, scratch); err != nil {
+		return "", false, err
+	}
+	at := scratch.GetAttribute(a.K)
+	if at == nil {
+		return "", false, nil
+	}
+	return "`" + mdEscape(strings.TrimSpace(string(at.Expr().BuildTokens(nil).Bytes()))) + "`", true, nil
+}
+
+// writeMarkdownTable writes the rows as a Markdown table with aligned columns.
+func writeMarkdownTable(b *bytes.Buffer, header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = max(utf8.RuneCountInString(h), 3)
+		for _, r := range rows {
+			widths[i] = max(widths[i], utf8.RuneCountInString(r[i]))
+		}
+	}
+	row := func(cells []string) {
+		b.WriteByte('|')
+		for i, c := range cells {
+			fmt.Fprintf(b, " %-*s |", widths[i], c)
+		}
+		b.WriteByte('\n')
+	}
+	row(header)
+	b.WriteByte('|')
+	for _, w := range widths {
+		b.WriteString(strings.Repeat("-", w+2))
+		b.WriteByte('|')
+	}
+	b.WriteByte('\n')
+	for _, r := range rows {
+		row(r)
+	}
+}
+
+// mdEscape escapes the given text to be used in a Markdown table cell.
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(s)
+}
@@ -0,0 +1,77 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalMarkdown(t *testing.T) {
+	var (
+		d struct {
+			Schema []*struct {
+				Name    string `spec:",name"`
+				Comment string `spec:"comment,omitempty"`
+			} `spec:"schema"`
+			Table []*struct {
+				Name    string `spec:",name"`
+				Schema  *Ref   `spec:"schema"`
+				Comment string `spec:"comment,omitempty"`
+				Columns []*struct {
+					Name    string `spec:",name"`
+					Type    *Type  `spec:"type"`
+					Null    bool   `spec:"null,omitempty"`
+					Comment string `spec:"comment,omitempty"`
+				} `spec:"column"`
+				PrimaryKey *struct {
+					Columns []*Ref `spec:"columns"`
+				} `spec:"primary_key"`
+			} `spec:"table"`
+		}
+		s = New(WithTypes("table.column.type", []*TypeSpec{
+			{Name: "int", T: "int"},
+			{Name: "varchar", T: "varchar", Attributes: []*TypeAttr{{Name: "size", Kind: reflect.Int}}},
+		}))
+	)
+	err := s.EvalBytes([]byte(`
+schema "public" {
+  comment = "Default schema"
+}
+table "users" {
+  schema  = schema.public
+  comment = "Users of the application."
+  column "id" {
+    type = int
+  }
+  column "name" {
+    type    = varchar(255)
+    null    = true
+    comment = "First | last name"
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+`), &d, nil)
+	require.NoError(t, err)
+	b, err := s.MarshalMarkdown(&d)
+	require.NoError(t, err)
+	require.Equal(t, "## schema `public`\n\nDefault schema\n\n## table `users`\n\nUsers of the application.\n\n"+
+		"| Attribute | Value           |\n"+
+		"|-----------|-----------------|\n"+
+		"| schema    | `schema.public` |\n"+
+		"\n### column\n\n"+
+		"| Name | type           | null   | comment            |\n"+
+		"|------|----------------|--------|--------------------|\n"+
+		"| id   | `int`          |        |                    |\n"+
+		"| name | `varchar(255)` | `true` | First \\| last name |\n"+
+		"\n### primary_key\n\n"+
+		"| columns       |\n"+
+		"|---------------|\n"+
+		"| `[column.id]` |\n", string(b))
+}
@@ -0,0 +1,202 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// MarshalSpecInto marshals v into the given Atlas HCL document and returns the updated
+// document. Unlike MarshalSpec, the order of the blocks and attributes of the document and
+// its comments are preserved, and only the attributes and blocks that were changed are
+// rewritten. This allows writing an edited (or re-inspected) schema back into its source
+// file with minimal textual diffs.
+//
+// Blocks are matched by their type and labels, and unlabeled blocks (e.g., primary_key) by
+// their order. Attributes are compared by their tokens, ignoring whitespaces, and therefore,
+// attributes that are computed from expressions (e.g., var.name) are rewritten with their
+// values if they were changed. Blocks that are not part of the schema definition (e.g.,
+// variable, locals, data or module blocks) and blocks with the for_each or when meta
+// arguments or dynamic blocks are kept as-is.
+func (s *State) MarshalSpecInto(src []byte, v any) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(src, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("schemahcl: failed parsing document: %w", diags)
+	}
+	r := &Resource{}
+	if err := r.Scan(v); err != nil {
+		return nil, fmt.Errorf("schemahcl: failed scanning %T to resource: %w", v, err)
+	}
+	if r.Type != "" {
+		r = &Resource{Children: []*Resource{r}}
+	}
+	if err := s.mergeBody(f.Body(), r, true); err != nil {
+		return nil, err
+	}
+	return f.Bytes(), nil
+}
+
+// mergeBody merges the attributes and children of the resource into the body.
+func (s *State) mergeBody(body *hclwrite.Body, r *Resource, top bool) error {
+	attrs, children := flatten(r)
+	// Attributes are written to a scratch body to get their tokens.
+	var (
+		added   []string
+		scratch = hclwrite.NewEmptyFile().Body()
+	)
+	for _, a := range attrs {
+		if err := s.writeAttr(a, scratch); err != nil {
+			return err
+		}
+	}
+	for name := range body.Attributes() {
+		if scratch.GetAttribute(name) == nil {
+			body.RemoveAttribute(name)
+		}
+	}
+	for _, a := range attrs {
+		sa := scratch.GetAttribute(a.K)
+		switch da := body.GetAttribute(a.K); {
+		case sa == nil || slices.Contains(added, a.K):
+		case da == nil:
+			added = append(added, a.K)
+		case !sameTokens(da.Expr().BuildTokens(nil), sa.Expr().BuildTokens(nil)):
+			body.SetAttributeRaw(a.K, sa.Expr().BuildTokens(nil))
+		}
+	}
+	// New attributes are appended to the end of the body by hclwrite.
+	// Hence, blocks are re-appended to keep attributes before blocks.
+	if len(added) > 0 {
+		blocks := body.Blocks()
+		for _, b := range blocks {
+			body.RemoveBlock(b)
+		}
+		for _, k := range added {
+			body.SetAttributeRaw(k, scratch.GetAttribute(k).Expr().BuildTokens(nil))
+		}
+		for _, b := range blocks {
+			body.AppendBlock(b)
+		}
+	}
+	var (
+		keys    = make(map[*hclwrite.Block]string)
+		matched = make(map[string]*hclwrite.Block)
+		counter = make(map[string]int)
+	)
+	for _, b := range body.Blocks() {
+		if s.keepBlock(b, top) {
+			continue
+		}
+		k := blockKey(b.Type(), b.Labels(), counter)
+		keys[b], matched[k] = k, b
+	}
+	clear(counter)
+	seen := make(map[string]bool, len(children))
+	for _, c := range children {
+		k := blockKey(c.Type, labels(c), counter)
+		seen[k] = true
+		if b, ok := matched[k]; ok {
+			if err := s.mergeBody(b.Body(), c, false); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.writeResource(c, scratch); err != nil {
+			return err
+		}
+		nb := scratch.Blocks()[len(scratch.Blocks())-1]
+		scratch.RemoveBlock(nb)
+		insertBlock(body, nb)
+	}
+	for b, k := range keys {
+		if !seen[k] {
+			body.RemoveBlock(b)
+		}
+	}
+	return nil
+}
+
+// flatten returns the attributes and children of the resource,
+// including the ones of its anonymous (embedded) children.
+func flatten(r *Resource) ([]*Attr, []*Resource) {
+	attrs, children := slices.Clone(r.Attrs), make([]*Resource, 0, len(r.Children))
+	for _, c := range r.Children {
+		if c.Type != "" {
+			children = append(children, c)
+			continue
+		}
+		ca, cc := flatten(c)
+		attrs, children = append(attrs, ca...), append(children, cc...)
+	}
+	return attrs, children
+}
+
+// keepBlock reports if the block should be kept as-is in the document.
+func (s *State) keepBlock(b *hclwrite.Block, top bool) bool {
+	switch t := b.Type(); {
+	case t == blockDynamic, b.Body().GetAttribute(forEachAttr) != nil, b.Body().GetAttribute(whenAttr) != nil:
+		return true
+	case !top:
+		return false
+	case t == BlockVariable, t == BlockLocals, t == BlockData, t == BlockModule, t == BlockOutput:
+		return true
+	default:
+		return s.config.initblk[t] != nil || s.config.typedblk[t] != nil
+	}
+}
+
+// blockKey returns the key used for matching blocks. Unlabeled
+// blocks are keyed by their order among blocks of the same type.
+func blockKey(typ string, labels []string, counter map[string]int) string {
+	if len(labels) > 0 {
+		return typ + "." + strings.Join(labels, ".")
+	}
+	k := typ + "#" + strconv.Itoa(counter[typ])
+	counter[typ]++
+	return k
+}
+
+// insertBlock inserts the block after the last block of the same type,
+// or to the end of the body if there are no blocks of the same type.
+func insertBlock(body *hclwrite.Body, nb *hclwrite.Block) {
+	blocks := body.Blocks()
+	last := -1
+	for i, b := range blocks {
+		if b.Type() == nb.Type() {
+			last = i
+		}
+	}
+	if last == -1 {
+		body.AppendBlock(nb)
+		return
+	}
+	tail := blocks[last+1:]
+	for _, b := range tail {
+		body.RemoveBlock(b)
+	}
+	body.AppendBlock(nb)
+	for _, b := range tail {
+		body.AppendBlock(b)
+	}
+}
+
+// sameTokens reports if the two token sequences are equal, ignoring whitespaces.
+func sameTokens(t1, t2 hclwrite.Tokens) bool {
+	var b1, b2 bytes.Buffer
+	for _, t := range t1 {
+		b1.Write(t.Bytes)
+	}
+	for _, t := range t2 {
+		b2.Write(t.Bytes)
+	}
+	return bytes.Equal(bytes.TrimSpace(b1.Bytes()), bytes.TrimSpace(b2.Bytes()))
+}
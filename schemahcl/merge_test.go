@@ -0,0 +1,149 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalSpecInto(t *testing.T) {
+	type (
+		Column struct {
+			Name    string `spec:",name"`
+			Type    string `spec:"type"`
+			Null    bool   `spec:"null"`
+			Comment string `spec:"comment,omitempty"`
+		}
+		Index struct {
+			Name    string `spec:",name"`
+			Columns []*Ref `spec:"columns"`
+		}
+		PrimaryKey struct {
+			Columns []*Ref `spec:"columns"`
+		}
+		Table struct {
+			Name       string      `spec:",name"`
+			Schema     *Ref        `spec:"schema"`
+			Columns    []*Column   `spec:"column"`
+			PrimaryKey *PrimaryKey `spec:"primary_key"`
+			Indexes    []*Index    `spec:"index"`
+		}
+		Schema struct {
+			Name string `spec:",name"`
+		}
+		Doc struct {
+			Tables  []*Table  `spec:"table"`
+			Schemas []*Schema `spec:"schema"`
+		}
+	)
+	var (
+		doc Doc
+		src = []byte(`variable "tenant" {
+  type    = string
+  default = "public"
+}
+
+# The public schema.
+schema "public" {}
+
+# Users of the system.
+table "users" {
+  schema = schema.public
+  # Primary key column.
+  column "id" {
+    null = false
+    type = "int" # Keep this comment.
+  }
+  column "name" {
+    null = true
+    type = "varchar(255)"
+  }
+
+  primary_key {
+    columns = [column.id]
+  }
+  index "users_name" {
+    columns = [column.name]
+  }
+}
+
+table "posts" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = "int"
+  }
+}
+`)
+	)
+	require.NoError(t, New().EvalBytes(src, &doc, nil))
+	// Marshaling an unchanged document returns the same document.
+	buf, err := New().MarshalSpecInto(src, &doc)
+	require.NoError(t, err)
+	require.Equal(t, string(src), string(buf))
+
+	users := doc.Tables[0]
+	users.Columns[1].Type = "text"
+	users.Columns = append(users.Columns, &Column{Name: "email", Type: "varchar(255)", Comment: "The email of the user."})
+	users.Indexes = nil
+	doc.Tables[1].Schema = nil
+	doc.Tables = append(doc.Tables, &Table{
+		Name:    "comments",
+		Schema:  &Ref{V: "$schema.public"},
+		Columns: []*Column{{Name: "id", Type: "int"}},
+	})
+	buf, err = New().MarshalSpecInto(src, &doc)
+	require.NoError(t, err)
+	require.Equal(t, `variable "tenant" {
+  type    = string
+  default = "public"
+}
+
+# The public schema.
+schema "public" {}
+
+# Users of the system.
+table "users" {
+  schema = schema.public
+  # Primary key column.
+  column "id" {
+    null = false
+    type = "int" # Keep this comment.
+  }
+  column "name" {
+    null = true
+    type = "text"
+  }
+
+  column "email" {
+    type    = "varchar(255)"
+    null    = false
+    comment = "The email of the user."
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+
+table "posts" {
+  column "id" {
+    null = false
+    type = "int"
+  }
+}
+table "comments" {
+  schema = schema.public
+  column "id" {
+    type = "int"
+    null = false
+  }
+}
+`, string(buf))
+
+	_, err = New().MarshalSpecInto([]byte(`table "t" {`), &doc)
+	require.Error(t, err)
+}
@@ -0,0 +1,211 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Module blocks and attributes.
+const (
+	BlockModule  = "module"
+	BlockOutput  = "output"
+	RefModule    = "module"
+	moduleSource = "source"
+	outputValue  = "value"
+)
+
+// ModuleLoader loads the source of modules whose addresses are not local paths,
+// such as registry addresses (e.g., "registry.example.com/atlas/audit").
+type ModuleLoader func(ctx context.Context, source string) ([]byte, error)
+
+// WithModuleLoader configures the loader used for loading modules that are
+// not local paths. Local paths (starting with "./", "../" or "/") are read
+// from the filesystem, relative to the file that imports them.
+func WithModuleLoader(l ModuleLoader) Option {
+	return func(c *Config) {
+		c.modloader = l
+	}
+}
+
+type (
+	// moduleKey is the key of the module import chain in the context.
+	moduleKey struct{}
+	// moduleSrc describes a loaded module source.
+	moduleSrc struct {
+		name   string // Source address or file path.
+		remote bool   // Loaded by the ModuleLoader.
+	}
+)
+
+// evalModule evaluates the module block and returns its outputs along with
+// the blocks it defines. Modules allow reusing schema fragments across documents:
+//
+//	module "audit" {
+//	  source = "./modules/audit.hcl"
+//	  prefix = "app"
+//	}
+//
+// The module file is evaluated in its own namespace: the attributes of the module block
+// (other than "source") are passed as the input variables of the module, and its variables,
+// locals, data sources and modules are not visible to the importing document. The "output"
+// blocks of the module are accessible using the "module.<name>.<output>" reference, and its
+// other top-level blocks (e.g., tables) are added to the importing document, where attributes
+// that reference the module namespace are evaluated, and others (e.g., schema.public) are
+// resolved by the importing document.
+func (s *State) evalModule(goctx context.Context, ctx *hcl.EvalContext, b *hclsyntax.Block) (cty.Value, hclsyntax.Blocks, error) {
+	name := b.Labels[0]
+	if len(b.Body.Blocks) > 0 {
+		return cty.NilVal, nil, fmt.Errorf("unexpected block %q in module %q", b.Body.Blocks[0].Type, name)
+	}
+	at, ok := b.Body.Attributes[moduleSource]
+	if !ok {
+		return cty.NilVal, nil, fmt.Errorf("missing source attribute in module %q", name)
+	}
+	sv, diags := at.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.NilVal, nil, diags
+	}
+	if sv.IsNull() || sv.Type() != cty.String || sv.AsString() == "" {
+		return cty.NilVal, nil, fmt.Errorf("source of module %q must be a non-empty string", name)
+	}
+	input := make(map[string]cty.Value, len(b.Body.Attributes))
+	for k, at := range b.Body.Attributes {
+		if k == moduleSource {
+			continue
+		}
+		v, diags := at.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return cty.NilVal, nil, diags
+		}
+		input[k] = v
+	}
+	stack, _ := goctx.Value(moduleKey{}).([]moduleSrc)
+	src, data, err := s.loadModule(goctx, b.DefRange().Filename, sv.AsString(), stack)
+	if err != nil {
+		return cty.NilVal, nil, fmt.Errorf("loading module %q: %w", name, err)
+	}
+	f, diags := hclparse.NewParser().ParseHCL(data, src.name)
+	if diags.HasErrors() {
+		return cty.NilVal, nil, diags
+	}
+	// Modules are evaluated by a state with the same configuration,
+	// that tracks the import chain for detecting import cycles.
+	cfg := *s.config
+	cfg.ctx = context.WithValue(goctx, moduleKey{}, append(slices.Clip(stack), src))
+	ms := &State{config: &cfg, newCtx: s.newCtx}
+	outputs, blocks, err := ms.moduleBody(name, f.Body.(*hclsyntax.Body), input)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+	return outputs, blocks, nil
+}
+
+// moduleBody evaluates the body of a module file using the given input variables.
+func (s *State) moduleBody(name string, body *hclsyntax.Body, input map[string]cty.Value) (cty.Value, hclsyntax.Blocks, error) {
+	if len(body.Attributes) > 0 {
+		k := slices.Sorted(maps.Keys(body.Attributes))[0]
+		return cty.NilVal, nil, fmt.Errorf("unexpected attribute %q in module %q", k, name)
+	}
+	vars := make(map[string]bool)
+	for _, b := range body.Blocks {
+		if b.Type == BlockVariable && len(b.Labels) == 1 {
+			vars[b.Labels[0]] = true
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(input)) {
+		if !vars[k] {
+			return cty.NilVal, nil, fmt.Errorf("module %q does not declare variable %q", name, k)
+		}
+	}
+	ctx := s.newCtx().NewChild()
+	ctx.Variables = make(map[string]cty.Value)
+	if _, err := s.setInputVals(ctx, body, input); err != nil {
+		return cty.NilVal, nil, fmt.Errorf("module %q: %w", name, err)
+	}
+	if err := s.evalReferences(ctx, body); err != nil {
+		return cty.NilVal, nil, fmt.Errorf("module %q: %w", name, err)
+	}
+	if err := s.expandDynamic(ctx, body, nil); err != nil {
+		return cty.NilVal, nil, err
+	}
+	var (
+		blocks  hclsyntax.Blocks
+		outputs = make(map[string]cty.Value)
+		// Attributes that reference the module namespace
+		// are evaluated in the context of the module.
+		scoped = append(slices.Collect(maps.Keys(ctx.Variables)), RefVar, RefLocal, RefData, RefModule)
+	)
+	for _, b := range body.Blocks {
+		switch b.Type {
+		case BlockVariable:
+		case BlockOutput:
+			if len(b.Labels) != 1 {
+				return cty.NilVal, nil, fmt.Errorf("output block in module %q must have exactly 1 label", name)
+			}
+			at, ok := b.Body.Attributes[outputValue]
+			if !ok {
+				return cty.NilVal, nil, fmt.Errorf("missing value attribute in output %q of module %q", b.Labels[0], name)
+			}
+			v, diags := at.Expr.Value(ctx)
+			if diags.HasErrors() {
+				return cty.NilVal, nil, diags
+			}
+			outputs[b.Labels[0]] = v
+		default:
+			nb, err := s.bindBody(ctx, b.Body, scoped, []string{b.Type})
+			if err != nil {
+				return cty.NilVal, nil, fmt.Errorf("module %q: %w", name, err)
+			}
+			cb := *b
+			cb.Body = nb
+			blocks = append(blocks, &cb)
+		}
+	}
+	return cty.ObjectVal(outputs), blocks, nil
+}
+
+// loadModule loads the module source imported by the given file.
+func (s *State) loadModule(ctx context.Context, importer, source string, stack []moduleSrc) (moduleSrc, []byte, error) {
+	var (
+		src   moduleSrc
+		local = strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") || filepath.IsAbs(source)
+	)
+	switch last := len(stack) - 1; {
+	// Local paths in remote modules are relative to their address.
+	case local && last >= 0 && stack[last].remote:
+		src = moduleSrc{name: path.Join(path.Dir(stack[last].name), source), remote: true}
+	case local && filepath.IsAbs(source):
+		src = moduleSrc{name: filepath.Clean(source)}
+	case local:
+		src = moduleSrc{name: filepath.Join(filepath.Dir(importer), source)}
+	default:
+		src = moduleSrc{name: source, remote: true}
+	}
+	if slices.Contains(stack, src) {
+		return src, nil, fmt.Errorf("import cycle detected for source %q", source)
+	}
+	if !src.remote {
+		data, err := os.ReadFile(src.name)
+		return src, data, err
+	}
+	if s.config.modloader == nil {
+		return src, nil, fmt.Errorf("no module loader configured for source %q", src.name)
+	}
+	data, err := s.config.modloader(ctx, src.name)
+	return src, data, err
+}
@@ -0,0 +1,225 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestModules(t *testing.T) {
+	type doc struct {
+		Schema []*struct {
+			Name string `spec:",name"`
+		} `spec:"schema"`
+		Table []*struct {
+			Name    string `spec:",name"`
+			Schema  *Ref   `spec:"schema"`
+			Comment string `spec:"comment"`
+			Columns []*struct {
+				Name string `spec:",name"`
+				Type string `spec:"type"`
+			} `spec:"column"`
+		} `spec:"table"`
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "outbox.hcl"), []byte(`
+variable "prefix" {
+  type = string
+}
+
+variable "service" {
+  type    = string
+  default = "unknown"
+}
+
+locals {
+  name = "${var.prefix}_outbox"
+}
+
+module "audit" {
+  source = "./audit.hcl"
+}
+
+table "outbox" {
+  schema  = schema.public
+  comment = "outbox of ${var.service}"
+  column "id" {
+    type = "bigint"
+  }
+  dynamic "column" {
+    for_each = module.audit.columns
+    labels   = [column.key]
+    content {
+      type = column.value
+    }
+  }
+}
+
+output "table" {
+  value = local.name
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "audit.hcl"), []byte(`
+output "columns" {
+  value = {
+    created_at = "timestamp"
+    updated_at = "timestamp"
+  }
+}
+`), 0644))
+	main := filepath.Join(dir, "main.hcl")
+	require.NoError(t, os.WriteFile(main, []byte(`
+variable "prefix" {
+  type    = string
+  default = "app"
+}
+
+module "outbox" {
+  source = "./modules/outbox.hcl"
+  prefix = var.prefix
+}
+
+schema "public" {}
+
+table "users" {
+  schema  = schema.public
+  comment = module.outbox.table
+  column "id" {
+    type = "int"
+  }
+}
+`), 0644))
+	var d doc
+	require.NoError(t, New().EvalFiles([]string{main}, &d, nil))
+	buf, err := Marshal.MarshalSpec(&d)
+	require.NoError(t, err)
+	require.Equal(t, `schema "public" {
+}
+table "users" {
+  schema  = schema.public
+  comment = "app_outbox"
+  column "id" {
+    type = "int"
+  }
+}
+table "outbox" {
+  schema  = schema.public
+  comment = "outbox of unknown"
+  column "id" {
+    type = "bigint"
+  }
+  column "created_at" {
+    type = "timestamp"
+  }
+  column "updated_at" {
+    type = "timestamp"
+  }
+}
+`, string(buf))
+
+	// Namespaces are isolated.
+	require.NoError(t, os.WriteFile(main, []byte(`
+module "audit" {
+  source = "./modules/audit.hcl"
+}
+table "t" {
+  comment = local.name
+}
+`), 0644))
+	err = New().EvalFiles([]string{main}, &d, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `There is no variable named "local"`)
+
+	// Undeclared variables.
+	require.NoError(t, os.WriteFile(main, []byte(`
+module "audit" {
+  source = "./modules/audit.hcl"
+  prefix = "app"
+}
+`), 0644))
+	err = New().EvalFiles([]string{main}, &d, nil)
+	require.EqualError(t, err, `module "audit" does not declare variable "prefix"`)
+
+	// Missing required variables.
+	require.NoError(t, os.WriteFile(main, []byte(`
+module "outbox" {
+  source = "./modules/outbox.hcl"
+}
+`), 0644))
+	err = New().EvalFiles([]string{main}, &d, nil)
+	require.EqualError(t, err, `module "outbox": missing value for required variable "prefix"`)
+
+	// Import cycles.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "cycle.hcl"), []byte(`
+module "self" {
+  source = "./cycle.hcl"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(main, []byte(`
+module "cycle" {
+  source = "./modules/cycle.hcl"
+}
+`), 0644))
+	err = New().EvalFiles([]string{main}, &d, nil)
+	require.EqualError(t, err, `module "cycle": loading module "self": import cycle detected for source "./cycle.hcl"`)
+}
+
+func TestModules_Loader(t *testing.T) {
+	var (
+		d struct {
+			Table []*struct {
+				Name    string `spec:",name"`
+				Comment string `spec:"comment"`
+			} `spec:"table"`
+		}
+		sources = map[string]string{
+			"registry.example.com/atlas/audit/main.hcl": `
+module "names" {
+  source = "./names.hcl"
+}
+output "comment" {
+  value = "audited by ${module.names.owner}"
+}
+`,
+			"registry.example.com/atlas/audit/names.hcl": `
+output "owner" {
+  value = "atlas"
+}
+`,
+		}
+		b = []byte(`
+module "audit" {
+  source = "registry.example.com/atlas/audit/main.hcl"
+}
+table "t" {
+  comment = module.audit.comment
+}
+`)
+	)
+	err := New().EvalBytes(b, &d, nil)
+	require.EqualError(t, err, `loading module "audit": no module loader configured for source "registry.example.com/atlas/audit/main.hcl"`)
+
+	var loaded []string
+	err = New(WithModuleLoader(func(_ context.Context, source string) ([]byte, error) {
+		loaded = append(loaded, source)
+		src, ok := sources[source]
+		if !ok {
+			return nil, fmt.Errorf("module %q not found", source)
+		}
+		return []byte(src), nil
+	})).EvalBytes(b, &d, map[string]cty.Value{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"registry.example.com/atlas/audit/main.hcl", "registry.example.com/atlas/audit/names.hcl"}, loaded)
+	require.Len(t, d.Table, 1)
+	require.Equal(t, "audited by atlas", d.Table[0].Comment)
+}
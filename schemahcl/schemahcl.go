@@ -9,17 +9,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/agext/levenshtein"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
 )
 
@@ -35,6 +38,11 @@ type (
 		datasrc, initblk map[string]BlockFunc
 		typedblk         map[string]map[string]BlockFunc
 		lazyattrs        map[string]bool
+		modloader        ModuleLoader
+		varsrc           map[string]VarSource
+		dialect          string
+		typehints        map[string]string
+		quoting          SQLQuoting
 		// Optional context to pass to dynamic block handlers,
 		// such as data-sources, type-blocks, etc.
 		ctx     context.Context
@@ -58,6 +66,15 @@ func New(opts ...Option) *State {
 	for n, f := range stdFuncs() {
 		cfg.funcs[n] = f
 	}
+	for n, f := range sqlQuoteFuncs(cfg.quoting) {
+		cfg.funcs[n] = f
+	}
+	cfg.funcs["dialect"] = dialectFunc(cfg.dialect)
+	for k, h := range map[string]VarSource{"env": EnvVarSource, "file": FileVarSource, "exec": ExecVarSource} {
+		if _, ok := cfg.varsrc[k]; !ok {
+			WithVarSource(k, h)(cfg)
+		}
+	}
 	return &State{
 		config: cfg,
 		newCtx: func() *hcl.EvalContext {
@@ -121,7 +138,20 @@ func WithVariables(vars map[string]cty.Value) Option {
 	}
 }
 
-// WithFunctions registers a list of functions to be injected into the context.
+// WithFunctions registers a list of functions to be injected into the context. It is the
+// hook for extending the standard library of the language (e.g., format, regex, lookup or
+// cidrsubnet) with custom functions. Note that functions of the standard library cannot be
+// overridden. For example:
+//
+//	WithFunctions(map[string]function.Function{
+//		"env_prefix": function.New(&function.Spec{
+//			Params: []function.Parameter{{Name: "name", Type: cty.String}},
+//			Type:   function.StaticReturnType(cty.String),
+//			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+//				return cty.StringVal(os.Getenv("ENV") + "_" + args[0].AsString()), nil
+//			},
+//		}),
+//	})
 func WithFunctions(funcs map[string]function.Function) Option {
 	return func(c *Config) {
 		if c.funcs == nil {
@@ -242,6 +272,42 @@ func WithTypes(path string, typeSpecs []*TypeSpec) Option {
 	}
 }
 
+// WithDialect configures the name of the dialect the documents are evaluated for. The name
+// is returned by the dialect function, allowing a single document to target multiple
+// dialects using conditional blocks. For example:
+//
+//	index "idx_name" {
+//	  when    = dialect() == "mysql"
+//	  type    = FULLTEXT
+//	  columns = [column.name]
+//	}
+func WithDialect(name string) Option {
+	return func(c *Config) {
+		c.dialect = name
+	}
+}
+
+// WithTypeHints configures the dialect name and the suggested replacements for type names
+// that are invalid in this dialect, for reporting fix-it hints in diagnostics. For example,
+// the option below reports `"text(255)" is invalid for postgres; did you mean "varchar(255)"?`
+// in case the text type is used with a size argument.
+//
+//	WithTypeHints("postgres", map[string]string{
+//		"text":   "varchar",
+//		"string": "text",
+//	})
+func WithTypeHints(dialect string, hints map[string]string) Option {
+	return func(c *Config) {
+		if c.typehints == nil {
+			c.typehints = make(map[string]string)
+		}
+		c.dialect = dialect
+		for k, v := range hints {
+			c.typehints[k] = v
+		}
+	}
+}
+
 type (
 	// SchemaValidator is the interface used for validating HCL documents.
 	SchemaValidator interface {
@@ -318,6 +384,18 @@ type EvalOptions struct {
 	// Validator is the schema validator to be used during evaluation.
 	// It defaults to the State (Driver) config.
 	Validator SchemaValidator
+
+	// Unknown, if set, is called for each attribute or block of the evaluated documents
+	// that is not defined by the target type (or kept by its extension, see Remainer) and
+	// therefore is ignored. Returning an error fails the evaluation, and returning nil allows
+	// reading documents written for newer versions, while reporting precisely what was
+	// ignored (see CollectUnknown). Positions are recorded when Unknown is set.
+	Unknown func(*UnknownElement) error
+
+	// Deprecated, if set, is called for each attribute or block of the evaluated documents
+	// that is read by a deprecated field of the target type (see Deprecation). Deprecations
+	// are warnings and do not fail the evaluation. Positions are recorded when it is set.
+	Deprecated func(*Deprecation)
 }
 
 // EvalFiles evaluates the files in the provided paths using the input variables and
@@ -341,9 +419,10 @@ func (s *State) Eval(parsed *hclparse.Parser, v any, input map[string]cty.Value)
 }
 
 // EvalOptions evaluates the parsed HCL documents and populates v using the result.
-func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) error {
+func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) (err error) {
 	var (
 		hasVars      bool
+		secrets      []string
 		ctx          = s.newCtx()
 		files        = parsed.Files()
 		fileNames    = make([]string, 0, len(files))
@@ -356,15 +435,22 @@ func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) e
 	if ctx.Variables == nil {
 		ctx.Variables = make(map[string]cty.Value)
 	}
+	// Sensitive values are redacted from the returned errors.
+	defer func() { err = redact(err, secrets) }()
 	for name, file := range files {
 		fileNames = append(fileNames, name)
-		if err := s.setInputVals(ctx, file.Body, opts.Variables); err != nil {
+		vs, err := s.setInputVals(ctx, file.Body, opts.Variables)
+		secrets = append(secrets, vs...)
+		if err != nil {
 			return err
 		}
 		body := file.Body.(*hclsyntax.Body)
 		if err := s.evalReferences(ctx, body); err != nil {
 			return err
 		}
+		if err := s.expandDynamic(ctx, body, nil); err != nil {
+			return err
+		}
 		blocks := make(hclsyntax.Blocks, 0, len(body.Blocks))
 		for _, b := range body.Blocks {
 			switch {
@@ -420,6 +506,9 @@ func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) e
 			}
 		}
 	}
+	if opts.Unknown != nil || opts.Deprecated != nil {
+		opts.RecordPos = true
+	}
 	spec := &Resource{}
 	sort.Slice(fileNames, func(i, j int) bool {
 		return fileNames[i] < fileNames[j]
@@ -456,7 +545,14 @@ func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) e
 			return err
 		}
 	}
-	if err := spec.As(v); err != nil {
+	if err := validateStructPtr(v); err != nil {
+		return fmt.Errorf("schemahcl: failed reading spec as %T: %w", v, err)
+	}
+	var d *decoder
+	if opts.Unknown != nil || opts.Deprecated != nil {
+		d = &decoder{unknown: opts.Unknown, deprecated: opts.Deprecated}
+	}
+	if err := spec.as(v, d); err != nil {
 		return fmt.Errorf("schemahcl: failed reading spec as %T: %w", v, err)
 	}
 	return nil
@@ -685,6 +781,9 @@ func (s *State) typeError(diag hcl.Diagnostics, scope []string) error {
 			if t, ok := s.findTypeSpec(e.Name); ok && len(t.Attributes) == 0 {
 				d.Detail = fmt.Sprintf("Type %q does not accept attributes", t.Name)
 			}
+			if h, ok := s.typeHint(path, e.Name, e.Args); ok {
+				d.Detail = h
+			}
 		case *hclsyntax.ScopeTraversalExpr:
 			if d.Summary != "Unknown variable" {
 				continue
@@ -707,7 +806,9 @@ func (s *State) typeError(diag hcl.Diagnostics, scope []string) error {
 			case root == RefLocal:
 				d.Summary = "Unknown local"
 			default:
-				if t, ok := s.findTypeSpec(root); ok && len(t.Attributes) > 0 {
+				if h, ok := s.typeHint(path, root, nil); ok && len(e.Traversal) == 1 {
+					d.Detail = h
+				} else if t, ok := s.findTypeSpec(root); ok && len(t.Attributes) > 0 {
 					d.Detail = fmt.Sprintf("Type %q requires at least 1 argument", t.Name)
 				} else if n := len(scope); n > 1 && (s.config.pathVars[path] != nil || s.config.pathFuncs[path] != nil) {
 					d.Summary = strings.Replace(d.Summary, "variable", fmt.Sprintf("%s.%s", scope[n-2], scope[n-1]), 1)
@@ -719,6 +820,67 @@ func (s *State) typeError(diag hcl.Diagnostics, scope []string) error {
 	return diag
 }
 
+// typeHint returns a fix-it hint for an invalid type usage in the given path (e.g.,
+// a type that does not accept arguments, or a misspelled type), if there is one.
+func (s *State) typeHint(path, name string, args []hclsyntax.Expression) (string, bool) {
+	vars, funcs := s.config.pathVars[path], s.config.pathFuncs[path]
+	if len(vars) == 0 && len(funcs) == 0 {
+		return "", false
+	}
+	valid := func(n string) bool {
+		if args == nil {
+			_, ok := vars[n]
+			return ok
+		}
+		_, ok := funcs[n]
+		return ok
+	}
+	// Dialect hints come first, and then names with a small edit distance.
+	suggest, ok := s.config.typehints[name]
+	if !ok || !valid(suggest) {
+		suggest = ""
+		names := slices.Sorted(maps.Keys(funcs))
+		if args == nil {
+			names = slices.Sorted(maps.Keys(vars))
+		}
+		for _, n := range names {
+			if d := levenshtein.Distance(name, n, nil); d < 3 && n != name && (suggest == "" || d < levenshtein.Distance(name, suggest, nil)) {
+				suggest = n
+			}
+		}
+	}
+	if suggest == "" {
+		return "", false
+	}
+	used, fixed := name, suggest
+	if args != nil {
+		text := make([]string, len(args))
+		for i, a := range args {
+			text[i] = exprText(a)
+		}
+		used, fixed = fmt.Sprintf("%s(%s)", name, strings.Join(text, ", ")), fmt.Sprintf("%s(%s)", suggest, strings.Join(text, ", "))
+	}
+	if s.config.dialect != "" {
+		return fmt.Sprintf("%q is invalid for %s; did you mean %q?", used, s.config.dialect, fixed), true
+	}
+	return fmt.Sprintf("%q is invalid; did you mean %q?", used, fixed), true
+}
+
+// exprText returns the source text of literal expressions,
+// or a placeholder for other expressions.
+func exprText(x hclsyntax.Expression) string {
+	switch x := x.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return strings.TrimSpace(string(hclwrite.TokensForValue(x.Val).Bytes()))
+	case *hclsyntax.TemplateExpr:
+		if x.IsStringLiteral() {
+			v, _ := x.Value(nil)
+			return strings.TrimSpace(string(hclwrite.TokensForValue(v).Bytes()))
+		}
+	}
+	return "..."
+}
+
 // isRef checks if the given value is a reference or a list of references.
 // Exists here for backward compatibility, use isOneRef and isRefList instead.
 func isRef(v cty.Value) bool {
@@ -1099,6 +1261,12 @@ func (s *State) forEachBlocks(ctx *hcl.EvalContext, b *hclsyntax.Block) ([]*hcls
 				"value": v,
 			}),
 		}
+		switch ok, err := s.included(nctx, b); {
+		case err != nil:
+			return nil, fmt.Errorf("schemahcl: evaluate block for value %q: %w", v, err)
+		case !ok:
+			continue
+		}
 		nb, err := s.copyBlock(nctx, b, []string{b.Type})
 		if err != nil {
 			return nil, fmt.Errorf("schemahcl: evaluate block for value %q: %w", v, err)
@@ -1119,6 +1287,9 @@ func (s *State) copyBlock(ctx *hcl.EvalContext, b *hclsyntax.Block, scope []stri
 		},
 	}
 	for k, v := range b.Body.Attributes {
+		if k == whenAttr {
+			continue
+		}
 		x, diags := v.Expr.Value(s.mayScopeContext(ctx, append(scope, k)))
 		if diags.HasErrors() {
 			return nil, diags
@@ -1128,11 +1299,249 @@ func (s *State) copyBlock(ctx *hcl.EvalContext, b *hclsyntax.Block, scope []stri
 		nb.Body.Attributes[k] = &nv
 	}
 	for _, v := range b.Body.Blocks {
-		nv, err := s.copyBlock(ctx, v, append(scope, v.Type))
+		if v.Type != blockDynamic {
+			switch ok, err := s.included(ctx, v); {
+			case err != nil:
+				return nil, err
+			case !ok:
+				continue
+			}
+			nv, err := s.copyBlock(ctx, v, append(scope, v.Type))
+			if err != nil {
+				return nil, err
+			}
+			nb.Body.Blocks = append(nb.Body.Blocks, nv)
+			continue
+		}
+		bs, err := s.dynamicBlocks(ctx, v, scope, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range bs {
+			switch ok, err := s.included(ctx, v); {
+			case err != nil:
+				return nil, err
+			case !ok:
+				continue
+			}
+			nv, err := s.copyBlock(ctx, v, append(scope, v.Type))
+			if err != nil {
+				return nil, err
+			}
+			nb.Body.Blocks = append(nb.Body.Blocks, nv)
+		}
+	}
+	return nb, nil
+}
+
+// expandDynamic replaces the dynamic blocks in the body and its nested blocks with the
+// blocks they generate, and drops the blocks whose "when" condition is false. Blocks with
+// the for_each meta argument are skipped, as their dynamic blocks and conditions are
+// evaluated when the blocks are copied for each of their elements.
+func (s *State) expandDynamic(ctx *hcl.EvalContext, body *hclsyntax.Body, scope []string) error {
+	blocks := make(hclsyntax.Blocks, 0, len(body.Blocks))
+	for _, b := range body.Blocks {
+		switch {
+		case b.Type == blockDynamic:
+			bs, err := s.dynamicBlocks(ctx, b, scope, nil)
+			if err != nil {
+				return err
+			}
+			for _, b := range bs {
+				switch ok, err := s.included(ctx, b); {
+				case err != nil:
+					return err
+				case ok:
+					delete(b.Body.Attributes, whenAttr)
+					if err := s.expandDynamic(ctx, b.Body, append(scope, b.Type)); err != nil {
+						return err
+					}
+					blocks = append(blocks, b)
+				}
+			}
+		case b.Body != nil && b.Body.Attributes[forEachAttr] == nil:
+			switch ok, err := s.included(ctx, b); {
+			case err != nil:
+				return err
+			case !ok:
+				continue
+			}
+			delete(b.Body.Attributes, whenAttr)
+			if err := s.expandDynamic(ctx, b.Body, append(scope, b.Type)); err != nil {
+				return err
+			}
+			fallthrough
+		default:
+			blocks = append(blocks, b)
+		}
+	}
+	body.Blocks = blocks
+	return nil
+}
+
+// included reports if the block should be included in the document, based on its
+// optional "when" meta argument. For example, the index below is defined only when
+// the document is evaluated for PostgreSQL:
+//
+//	index "idx_name" {
+//	  when    = dialect() == "postgres"
+//	  columns = [column.name]
+//	}
+func (s *State) included(ctx *hcl.EvalContext, b *hclsyntax.Block) (bool, error) {
+	at, ok := b.Body.Attributes[whenAttr]
+	if !ok {
+		return true, nil
+	}
+	v, diags := at.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	v, err := convert.Convert(v, cty.Bool)
+	if err != nil || v.IsNull() || !v.IsKnown() {
+		return false, fmt.Errorf("schemahcl: when condition of block %q at %s must be a known boolean value", b.Type, at.SrcRange)
+	}
+	return v.True(), nil
+}
+
+// dynamicBlocks returns the blocks generated by the given dynamic block. For example:
+//
+//	dynamic "column" {
+//	  for_each = var.columns
+//	  labels   = [column.key]
+//	  content {
+//	    type = column.value
+//	  }
+//	}
+//
+// The current element is accessible in the content block using the iterator variable,
+// which is named after the block type, unless it was set by the "iterator" attribute.
+// Attributes that reference the iterator are evaluated, and others are left as-is.
+func (s *State) dynamicBlocks(ctx *hcl.EvalContext, b *hclsyntax.Block, scope, iters []string) ([]*hclsyntax.Block, error) {
+	if len(b.Labels) != 1 {
+		return nil, fmt.Errorf("schemahcl: dynamic block at %s must have exactly one label: the type of the generated blocks", b.TypeRange)
+	}
+	var (
+		typ     = b.Labels[0]
+		content *hclsyntax.Block
+	)
+	for _, v := range b.Body.Blocks {
+		if v.Type != dynamicContent || content != nil {
+			return nil, fmt.Errorf("schemahcl: dynamic block %q must have exactly one content block and no other blocks", typ)
+		}
+		content = v
+	}
+	if content == nil {
+		return nil, fmt.Errorf("schemahcl: dynamic block %q must have exactly one content block and no other blocks", typ)
+	}
+	iter := typ
+	for name, at := range b.Body.Attributes {
+		switch name {
+		case forEachAttr, dynamicLabels:
+		case dynamicIterator:
+			tr, diags := hcl.AbsTraversalForExpr(at.Expr)
+			if diags.HasErrors() || len(tr) != 1 {
+				return nil, fmt.Errorf("schemahcl: iterator of dynamic block %q must be an identifier", typ)
+			}
+			iter = tr.RootName()
+		default:
+			return nil, fmt.Errorf("schemahcl: unexpected attribute %q in dynamic block %q", name, typ)
+		}
+	}
+	at, ok := b.Body.Attributes[forEachAttr]
+	if !ok {
+		return nil, fmt.Errorf("schemahcl: missing for_each attribute in dynamic block %q", typ)
+	}
+	forEach, diags := at.Expr.Value(ctx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if t := forEach.Type(); !t.IsSetType() && !t.IsObjectType() && !t.IsTupleType() && !t.IsListType() && !t.IsMapType() {
+		return nil, fmt.Errorf("schemahcl: for_each of dynamic block %q does not support %s type", typ, t.FriendlyName())
+	}
+	if forEach.IsNull() || !forEach.IsKnown() {
+		return nil, fmt.Errorf("schemahcl: for_each of dynamic block %q must be a known, non-null value", typ)
+	}
+	blocks := make([]*hclsyntax.Block, 0, forEach.LengthInt())
+	for it := forEach.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		nctx := ctx.NewChild()
+		nctx.Variables = map[string]cty.Value{
+			iter: cty.ObjectVal(map[string]cty.Value{
+				"key":   k,
+				"value": v,
+			}),
+		}
+		nb := &hclsyntax.Block{
+			Type:            typ,
+			TypeRange:       b.LabelRanges[0],
+			OpenBraceRange:  content.OpenBraceRange,
+			CloseBraceRange: content.CloseBraceRange,
+		}
+		if at, ok := b.Body.Attributes[dynamicLabels]; ok {
+			labels, diags := at.Expr.Value(nctx)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if t := labels.Type(); labels.IsNull() || !t.IsListType() && !t.IsTupleType() {
+				return nil, fmt.Errorf("schemahcl: labels of dynamic block %q must be a list of strings", typ)
+			}
+			for it := labels.ElementIterator(); it.Next(); {
+				_, l := it.Element()
+				if l.IsNull() || l.Type() != cty.String {
+					return nil, fmt.Errorf("schemahcl: labels of dynamic block %q must be a list of strings", typ)
+				}
+				nb.Labels = append(nb.Labels, l.AsString())
+				nb.LabelRanges = append(nb.LabelRanges, at.SrcRange)
+			}
+		}
+		body, err := s.bindBody(nctx, content.Body, append(iters, iter), append(scope, typ))
+		if err != nil {
+			return nil, fmt.Errorf("schemahcl: evaluate dynamic block %q for key %q: %w", typ, k.GoString(), err)
+		}
+		nb.Body = body
+		blocks = append(blocks, nb)
+	}
+	return blocks, nil
+}
+
+// bindBody returns a copy of the body where the attributes that reference
+// one of the iterators are evaluated, and its dynamic blocks are expanded.
+func (s *State) bindBody(ctx *hcl.EvalContext, body *hclsyntax.Body, iters, scope []string) (*hclsyntax.Body, error) {
+	nb := &hclsyntax.Body{
+		Attributes: make(hclsyntax.Attributes, len(body.Attributes)),
+		Blocks:     make(hclsyntax.Blocks, 0, len(body.Blocks)),
+		SrcRange:   body.SrcRange,
+		EndRange:   body.EndRange,
+	}
+	for k, v := range body.Attributes {
+		if !slices.ContainsFunc(v.Expr.Variables(), func(tr hcl.Traversal) bool { return slices.Contains(iters, tr.RootName()) }) {
+			nb.Attributes[k] = v
+			continue
+		}
+		x, diags := v.Expr.Value(s.mayScopeContext(ctx, append(scope, k)))
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		nv := *v
+		nv.Expr = &hclsyntax.LiteralValueExpr{Val: x, SrcRange: v.Expr.Range()}
+		nb.Attributes[k] = &nv
+	}
+	for _, v := range body.Blocks {
+		if v.Type == blockDynamic {
+			bs, err := s.dynamicBlocks(ctx, v, scope, iters)
+			if err != nil {
+				return nil, err
+			}
+			nb.Blocks = append(nb.Blocks, bs...)
+			continue
+		}
+		cb, err := s.bindBody(ctx, v.Body, iters, append(scope, v.Type))
 		if err != nil {
 			return nil, err
 		}
-		nb.Body.Blocks = append(nb.Body.Blocks, nv)
+		nv := *v
+		nv.Body = cb
+		nb.Blocks = append(nb.Blocks, &nv)
 	}
 	return nb, nil
 }
@@ -1194,6 +1603,9 @@ func typeFuncSpecImpl(_ *function.Spec, typeSpec *TypeSpec) function.ImplFunc {
 			if len(args) == 0 {
 				break
 			}
+			if err := checkTypeArg(typeSpec, attr, args[0]); err != nil {
+				return cty.NilVal, err
+			}
 			t.Attrs = append(t.Attrs, &Attr{K: attr.Name, V: args[0]})
 			args = args[1:]
 		}
@@ -1201,6 +1613,49 @@ func typeFuncSpecImpl(_ *function.Spec, typeSpec *TypeSpec) function.ImplFunc {
 	}
 }
 
+// checkTypeArg checks that the argument matches the kind of the type attribute. Optional
+// arguments are not typed by the function spec, and therefore, are checked here to report
+// invalid types at decoding time. e.g., varchar("255") or decimal(10.5).
+func checkTypeArg(spec *TypeSpec, attr *TypeAttr, arg cty.Value) error {
+	if arg.IsNull() || !arg.IsKnown() {
+		return nil
+	}
+	var ok bool
+	switch t := arg.Type(); attr.Kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ok = t == cty.Number && arg.AsBigFloat().IsInt()
+	case reflect.Float32, reflect.Float64:
+		ok = t == cty.Number
+	case reflect.String:
+		ok = t == cty.String
+	case reflect.Bool:
+		ok = t == cty.Bool
+	default:
+		// Other kinds are checked when the
+		// type is converted to schema.Type.
+		return nil
+	}
+	if !ok {
+		return fmt.Errorf("invalid value %s for attribute %q of type %q: expected %s", strings.TrimSpace(string(hclwrite.TokensForValue(arg).Bytes())), attr.Name, spec.Name, kindName(attr.Kind))
+	}
+	return nil
+}
+
+// kindName returns the HCL name of the given kind.
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a bool"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "an integer"
+	}
+}
+
 // typeFuncArgs returns the type attributes that are configured via arguments to the
 // type definition, for example precision and scale in a decimal definition, i.e `decimal(10,2)`.
 func typeFuncArgs(spec *TypeSpec) []*TypeAttr {
@@ -591,6 +591,197 @@ table "s2" {
 `, string(buf))
 }
 
+func TestDynamicBlocks(t *testing.T) {
+	var (
+		doc struct {
+			Schema []*struct {
+				Name string `spec:",name"`
+			} `spec:"schema"`
+			Table []*struct {
+				Name    string `spec:"name,name"`
+				Schema  *Ref   `spec:"schema"`
+				Columns []*struct {
+					Name string `spec:",name"`
+					Type string `spec:"type"`
+					Null bool   `spec:"null"`
+				} `spec:"column"`
+				Index []*struct {
+					Name    string `spec:",name"`
+					Columns []*Ref `spec:"columns"`
+				} `spec:"index"`
+			} `spec:"table"`
+		}
+		b = []byte(`
+variable "tenants" {
+  type    = list(string)
+  default = ["t1", "t2"]
+}
+
+variable "columns" {
+  type    = map(string)
+  default = {
+    a = "int"
+    b = "text"
+  }
+}
+
+schema "public" {}
+
+table "users" {
+  schema = schema.public
+  dynamic "column" {
+    for_each = var.columns
+    labels   = [column.key]
+    content {
+      type = column.value
+      null = false
+    }
+  }
+  index "idx" {
+    columns = [column.a, column.b]
+  }
+}
+
+table {
+  for_each = toset(var.tenants)
+  name     = "${each.value}_events"
+  schema   = schema.public
+  dynamic "column" {
+    for_each = ["id", "${each.value}_id"]
+    iterator = c
+    labels   = [c.value]
+    content {
+      type = "${each.value}_${c.key}"
+    }
+  }
+}
+`)
+	)
+	require.NoError(t, New().EvalBytes(b, &doc, nil))
+	buf, err := Marshal.MarshalSpec(&doc)
+	require.NoError(t, err)
+	require.Equal(t, `schema "public" {
+}
+table "users" {
+  schema = schema.public
+  column "a" {
+    type = "int"
+    null = false
+  }
+  column "b" {
+    type = "text"
+    null = false
+  }
+  index "idx" {
+    columns = [column.a, column.b]
+  }
+}
+table "t1_events" {
+  schema = schema.public
+  column "id" {
+    type = "t1_0"
+    null = false
+  }
+  column "t1_id" {
+    type = "t1_1"
+    null = false
+  }
+}
+table "t2_events" {
+  schema = schema.public
+  column "id" {
+    type = "t2_0"
+    null = false
+  }
+  column "t2_id" {
+    type = "t2_1"
+    null = false
+  }
+}
+`, string(buf))
+
+	// Nested dynamic blocks can reference the outer iterators.
+	var doc1 struct {
+		Table []*struct {
+			Name  string `spec:",name"`
+			Index []*struct {
+				Name    string   `spec:",name"`
+				Columns []string `spec:"columns"`
+				Parts   []*struct {
+					Expr string `spec:"expr"`
+				} `spec:"on"`
+			} `spec:"index"`
+		} `spec:"table"`
+	}
+	b = []byte(`
+table "t" {
+  dynamic "index" {
+    for_each = {
+      i1 = ["a"]
+      i2 = ["a", "b"]
+    }
+    labels = [index.key]
+    content {
+      columns = index.value
+      dynamic "on" {
+        for_each = index.value
+        content {
+          expr = "lower(${on.value}) -- ${index.key}"
+        }
+      }
+    }
+  }
+}
+`)
+	require.NoError(t, New().EvalBytes(b, &doc1, nil))
+	require.Len(t, doc1.Table, 1)
+	require.Len(t, doc1.Table[0].Index, 2)
+	require.Equal(t, "i1", doc1.Table[0].Index[0].Name)
+	require.Equal(t, []string{"a"}, doc1.Table[0].Index[0].Columns)
+	require.Len(t, doc1.Table[0].Index[0].Parts, 1)
+	require.Equal(t, "lower(a) -- i1", doc1.Table[0].Index[0].Parts[0].Expr)
+	require.Equal(t, "i2", doc1.Table[0].Index[1].Name)
+	require.Len(t, doc1.Table[0].Index[1].Parts, 2)
+	require.Equal(t, "lower(b) -- i2", doc1.Table[0].Index[1].Parts[1].Expr)
+
+	// Invalid dynamic blocks.
+	for src, msg := range map[string]string{
+		`table "t" {
+  dynamic "column" {
+    for_each = ["a"]
+  }
+}`: `schemahcl: dynamic block "column" must have exactly one content block and no other blocks`,
+		`table "t" {
+  dynamic "column" {
+    content {}
+  }
+}`: `schemahcl: missing for_each attribute in dynamic block "column"`,
+		`table "t" {
+  dynamic "column" {
+    for_each = "a"
+    content {}
+  }
+}`: `schemahcl: for_each of dynamic block "column" does not support string type`,
+		`table "t" {
+  dynamic "column" {
+    for_each = ["a"]
+    labels   = [1]
+    content {}
+  }
+}`: `schemahcl: labels of dynamic block "column" must be a list of strings`,
+		`table "t" {
+  dynamic "column" {
+    for_each = ["a"]
+    unknown  = true
+    content {}
+  }
+}`: `schemahcl: unexpected attribute "unknown" in dynamic block "column"`,
+	} {
+		err := New().EvalBytes([]byte(src), &doc1, nil)
+		require.EqualError(t, err, msg)
+	}
+}
+
 func TestDataLocalsRefs(t *testing.T) {
 	var (
 		opts = []Option{
@@ -1356,3 +1547,113 @@ $${{text}}, %%%{text}
 		got.Blocks[0].Attrs[1].V,
 	)
 }
+
+func TestConditionalBlocks(t *testing.T) {
+	var (
+		doc struct {
+			Table []*struct {
+				Name    string `spec:"name,name"`
+				Columns []*struct {
+					Name string `spec:",name"`
+					Type string `spec:"type"`
+				} `spec:"column"`
+				Index []*struct {
+					Name    string   `spec:",name"`
+					Columns []string `spec:"columns"`
+				} `spec:"index"`
+			} `spec:"table"`
+		}
+		b = []byte(`
+variable "enable_audit" {
+  type    = bool
+  default = false
+}
+
+table "users" {
+  column "id" {
+    type = "int"
+  }
+  column "name" {
+    type = "text"
+  }
+  dynamic "column" {
+    for_each = ["created_at", "deleted_at"]
+    labels   = [column.value]
+    content {
+      when = column.value != "deleted_at"
+      type = "timestamp"
+    }
+  }
+  index "idx_name_ft" {
+    when    = dialect() == "mysql"
+    columns = ["name"]
+  }
+  index "idx_name_gin" {
+    when    = dialect() == "postgres"
+    columns = ["name"]
+  }
+}
+
+table "audit" {
+  when = var.enable_audit
+  column "id" {
+    type = "int"
+  }
+}
+
+table {
+  for_each = toset(["t1", "t2"])
+  when     = each.value != "t2"
+  name     = each.value
+  column "id" {
+    type = "int"
+  }
+  column "tenant" {
+    when = each.value == "t1"
+    type = "text"
+  }
+}
+`)
+	)
+	require.NoError(t, New(WithDialect("postgres")).EvalBytes(b, &doc, nil))
+	buf, err := Marshal.MarshalSpec(&doc)
+	require.NoError(t, err)
+	require.Equal(t, `table "users" {
+  column "id" {
+    type = "int"
+  }
+  column "name" {
+    type = "text"
+  }
+  column "created_at" {
+    type = "timestamp"
+  }
+  index "idx_name_gin" {
+    columns = ["name"]
+  }
+}
+table "t1" {
+  column "id" {
+    type = "int"
+  }
+  column "tenant" {
+    type = "text"
+  }
+}
+`, string(buf))
+
+	doc.Table = nil
+	require.NoError(t, New(WithDialect("mysql")).EvalBytes(b, &doc, map[string]cty.Value{
+		"enable_audit": cty.True,
+	}))
+	require.Len(t, doc.Table, 3)
+	require.Equal(t, "idx_name_ft", doc.Table[0].Index[0].Name)
+	require.Equal(t, "audit", doc.Table[1].Name)
+
+	err = New().EvalBytes([]byte(`
+table "users" {
+  when = "maybe"
+}
+`), &doc, nil)
+	require.EqualError(t, err, `schemahcl: when condition of block "table" at :3,3-17 must be a known boolean value`)
+}
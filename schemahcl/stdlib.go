@@ -5,14 +5,21 @@
 package schemahcl
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bmatcuk/doublestar"
 	"github.com/hashicorp/hcl/v2"
@@ -111,10 +118,17 @@ func stdFuncs() map[string]function.Function {
 	return map[string]function.Function{
 		"abs":             stdlib.AbsoluteFunc,
 		"alltrue":         allTrueFunc,
+		"base64decode":    base64DecodeFunc,
+		"base64encode":    base64EncodeFunc,
 		"can":             tryfunc.CanFunc,
 		"ceil":            stdlib.CeilFunc,
 		"chomp":           stdlib.ChompFunc,
 		"chunklist":       stdlib.ChunklistFunc,
+		"cidrhost":        cidrHostFunc,
+		"cidrnetmask":     cidrNetmaskFunc,
+		"cidrsubnet":      cidrSubnetFunc,
+		"cidrsubnets":     cidrSubnetsFunc,
+		"coalesce":        stdlib.CoalesceFunc,
 		"coalescelist":    stdlib.CoalesceListFunc,
 		"compact":         stdlib.CompactFunc,
 		"concat":          stdlib.ConcatFunc,
@@ -140,10 +154,12 @@ func stdFuncs() map[string]function.Function {
 		"keys":            stdlib.KeysFunc,
 		"length":          stdlib.LengthFunc,
 		"log":             stdlib.LogFunc,
+		"lookup":          stdlib.LookupFunc,
 		"lower":           stdlib.LowerFunc,
 		"max":             stdlib.MaxFunc,
 		"merge":           stdlib.MergeFunc,
 		"min":             stdlib.MinFunc,
+		"one":             oneFunc,
 		"parseint":        stdlib.ParseIntFunc,
 		"pow":             stdlib.PowFunc,
 		"print":           printFunc,
@@ -158,13 +174,17 @@ func stdFuncs() map[string]function.Function {
 		"setproduct":      stdlib.SetProductFunc,
 		"setsubtract":     stdlib.SetSubtractFunc,
 		"setunion":        stdlib.SetUnionFunc,
+		"sha256":          sha256Func,
 		"signum":          stdlib.SignumFunc,
 		"slice":           stdlib.SliceFunc,
 		"sort":            stdlib.SortFunc,
 		"split":           stdlib.SplitFunc,
 		"startswith":      startsWithFunc,
+		"strcontains":     strContainsFunc,
+		"strlen":          stdlib.StrlenFunc,
 		"strrev":          stdlib.ReverseFunc,
 		"substr":          stdlib.SubstrFunc,
+		"sum":             sumFunc,
 		"timeadd":         stdlib.TimeAddFunc,
 		"title":           stdlib.TitleFunc,
 		"tobool":          makeToFunc(cty.Bool),
@@ -190,6 +210,109 @@ func stdFuncs() map[string]function.Function {
 	}
 }
 
+// SQLQuoting configures how values are quoted by the sqlliteral and sqlident functions.
+// Standard SQL quoting is used by default, where string literals are enclosed in single
+// quotes and identifiers in double quotes, and embedded quotes are doubled.
+type SQLQuoting struct {
+	Literal func(string) string // Quotes string literals. e.g., 'it''s'.
+	Ident   func(string) string // Quotes identifiers. e.g., "user".
+}
+
+// WithSQLQuoting configures the SQL quoting of the target dialect. The sqlliteral and sqlident
+// functions allow interpolating variables and locals into SQL expressions (e.g., checks, view
+// definitions or defaults) with correct escaping, instead of wrapping them with quotes:
+//
+//	check "status" {
+//	  expr = <<-SQL
+//	    status IN (${sqlliteral(var.statuses)}) AND ${sqlident(local.column)} > 0
+//	  SQL
+//	}
+func WithSQLQuoting(q SQLQuoting) Option {
+	return func(c *Config) {
+		c.quoting = q
+	}
+}
+
+// sqlQuoteFuncs returns the SQL quoting functions for the given configuration.
+func sqlQuoteFuncs(q SQLQuoting) map[string]function.Function {
+	if q.Literal == nil {
+		q.Literal = func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+	}
+	if q.Ident == nil {
+		q.Ident = func(s string) string {
+			return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+		}
+	}
+	var literal func(cty.Value) (string, error)
+	literal = func(v cty.Value) (string, error) {
+		switch t := v.Type(); {
+		case v.IsNull():
+			return "NULL", nil
+		case !v.IsKnown():
+			return "", errors.New("value is not known")
+		case t == cty.String:
+			return q.Literal(v.AsString()), nil
+		case t == cty.Number:
+			return v.AsBigFloat().Text('f', -1), nil
+		case t == cty.Bool:
+			return strings.ToUpper(strconv.FormatBool(v.True())), nil
+		case t.IsListType(), t.IsSetType(), t.IsTupleType():
+			vs := make([]string, 0, v.LengthInt())
+			for it := v.ElementIterator(); it.Next(); {
+				_, ev := it.Element()
+				s, err := literal(ev)
+				if err != nil {
+					return "", err
+				}
+				vs = append(vs, s)
+			}
+			return strings.Join(vs, ", "), nil
+		default:
+			return "", fmt.Errorf("unsupported value type %s", t.FriendlyName())
+		}
+	}
+	return map[string]function.Function{
+		"sqlliteral": function.New(&function.Spec{
+			Params: []function.Parameter{
+				{Name: "value", Type: cty.DynamicPseudoType, AllowNull: true},
+			},
+			Type:        function.StaticReturnType(cty.String),
+			Description: "sqlliteral returns the value as an SQL literal. Lists are returned as a comma-separated list of literals.",
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				s, err := literal(args[0])
+				if err != nil {
+					return cty.NilVal, err
+				}
+				return cty.StringVal(s), nil
+			},
+		}),
+		"sqlident": function.New(&function.Spec{
+			Params: []function.Parameter{
+				{Name: "name", Type: cty.String},
+			},
+			Type:        function.StaticReturnType(cty.String),
+			Description: "sqlident returns the name as a quoted SQL identifier.",
+			Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+				return cty.StringVal(q.Ident(args[0].AsString())), nil
+			},
+		}),
+	}
+}
+
+// dialectFunc returns the dialect function that returns the name of the
+// configured dialect, or an empty string if no dialect was configured.
+func dialectFunc(name string) function.Function {
+	return function.New(&function.Spec{
+		Type:        function.StaticReturnType(cty.String),
+		Description: "dialect returns the name of the dialect the document is evaluated for.",
+		Impl: func([]cty.Value, cty.Type) (cty.Value, error) {
+			return cty.StringVal(name), nil
+		},
+	})
+}
+
 // makeToFunc constructs a "to..." function, like "tostring", which converts
 // its argument to a specific type or type kind. Code was copied from:
 // github.com/hashicorp/terraform/blob/master/internal/lang/funcs/conversion.go
@@ -573,6 +696,255 @@ var (
 	})
 )
 
+var (
+	strContainsFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "s", Type: cty.String},
+			{Name: "substr", Type: cty.String},
+		},
+		Type:        function.StaticReturnType(cty.Bool),
+		Description: "strcontains reports whether the substring is within the given string.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			return cty.BoolVal(strings.Contains(args[0].AsString(), args[1].AsString())), nil
+		},
+	})
+
+	oneFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "list", Type: cty.DynamicPseudoType},
+		},
+		Description: "one returns the single element of the given collection, or null if it is empty.",
+		Type: func(args []cty.Value) (cty.Type, error) {
+			switch t := args[0].Type(); {
+			case t.IsListType() || t.IsSetType():
+				return t.ElementType(), nil
+			case t.IsTupleType() && t.Length() == 0:
+				return cty.DynamicPseudoType, nil
+			case t.IsTupleType() && t.Length() == 1:
+				return t.TupleElementType(0), nil
+			case t.IsTupleType():
+				return cty.NilType, function.NewArgErrorf(0, "must be a list, set or tuple value with either zero or one elements")
+			default:
+				return cty.NilType, function.NewArgErrorf(0, "must be a list, set or tuple value")
+			}
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			switch l := args[0]; {
+			case l.IsNull():
+				return cty.NilVal, function.NewArgErrorf(0, "argument must not be null")
+			case l.LengthInt() == 0:
+				return cty.NullVal(retType), nil
+			case l.LengthInt() > 1:
+				return cty.NilVal, function.NewArgErrorf(0, "must be a list, set or tuple value with either zero or one elements")
+			default:
+				it := l.ElementIterator()
+				it.Next()
+				_, v := it.Element()
+				return v, nil
+			}
+		},
+	})
+
+	sumFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "list", Type: cty.DynamicPseudoType},
+		},
+		Type:        function.StaticReturnType(cty.Number),
+		Description: "sum returns the total of the numbers in the given collection.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			l := args[0]
+			if t := l.Type(); l.IsNull() || !t.IsListType() && !t.IsSetType() && !t.IsTupleType() {
+				return cty.NilVal, function.NewArgErrorf(0, "argument must be a list, set or tuple of numbers")
+			}
+			sum := cty.Zero
+			for it := l.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				n, err := convert.Convert(v, cty.Number)
+				if err != nil || n.IsNull() {
+					return cty.NilVal, function.NewArgErrorf(0, "argument must be a list, set or tuple of numbers")
+				}
+				sum = sum.Add(n)
+			}
+			return sum, nil
+		},
+	})
+
+	sha256Func = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "s", Type: cty.String},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "sha256 returns the hexadecimal SHA256 hash of the given string.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			h := sha256.Sum256([]byte(args[0].AsString()))
+			return cty.StringVal(hex.EncodeToString(h[:])), nil
+		},
+	})
+
+	base64EncodeFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "s", Type: cty.String},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "base64encode returns the Base64 encoding of the given string.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			return cty.StringVal(base64.StdEncoding.EncodeToString([]byte(args[0].AsString()))), nil
+		},
+	})
+
+	base64DecodeFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "s", Type: cty.String},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "base64decode decodes the given Base64 string.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			b, err := base64.StdEncoding.DecodeString(args[0].AsString())
+			if err != nil {
+				return cty.NilVal, function.NewArgErrorf(0, "failed to decode base64 data: %v", err)
+			}
+			if !utf8.Valid(b) {
+				return cty.NilVal, function.NewArgErrorf(0, "the decoded data is not valid UTF-8")
+			}
+			return cty.StringVal(string(b)), nil
+		},
+	})
+
+	cidrHostFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "prefix", Type: cty.String},
+			{Name: "hostnum", Type: cty.Number},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "cidrhost returns the IP address of the given host number within the given prefix. Negative numbers count from the end of the range.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			p, err := parsePrefix(args[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			num, _ := args[1].AsBigFloat().Int(nil)
+			size := prefixSize(p, p.Bits())
+			if num.Sign() < 0 {
+				num.Add(num, size)
+			}
+			if num.Sign() < 0 || num.Cmp(size) >= 0 {
+				return cty.NilVal, function.NewArgErrorf(1, "host number %s does not fit in prefix %s", args[1].AsBigFloat().String(), p)
+			}
+			return cty.StringVal(addrAdd(p.Addr(), num).String()), nil
+		},
+	})
+
+	cidrNetmaskFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "prefix", Type: cty.String},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "cidrnetmask returns the subnet mask of the given IPv4 prefix in dotted-decimal notation.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			p, err := parsePrefix(args[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			if !p.Addr().Is4() {
+				return cty.NilVal, function.NewArgErrorf(0, "only IPv4 prefixes have netmasks, got %s", p)
+			}
+			return cty.StringVal(net.IP(net.CIDRMask(p.Bits(), 32)).String()), nil
+		},
+	})
+
+	cidrSubnetFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "prefix", Type: cty.String},
+			{Name: "newbits", Type: cty.Number},
+			{Name: "netnum", Type: cty.Number},
+		},
+		Type:        function.StaticReturnType(cty.String),
+		Description: "cidrsubnet returns the subnet with the given number within the given prefix, extended by the given number of bits.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			p, err := parsePrefix(args[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			newbits, _ := args[1].AsBigFloat().Int64()
+			bits := p.Bits() + int(newbits)
+			if newbits < 0 || bits > p.Addr().BitLen() {
+				return cty.NilVal, function.NewArgErrorf(1, "cannot extend prefix %s by %d bits", p, newbits)
+			}
+			num, _ := args[2].AsBigFloat().Int(nil)
+			if num.Sign() < 0 || num.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(newbits))) >= 0 {
+				return cty.NilVal, function.NewArgErrorf(2, "prefix extension of %d bits does not accommodate subnet number %s", newbits, num)
+			}
+			return cty.StringVal(netip.PrefixFrom(addrAdd(p.Addr(), num.Mul(num, prefixSize(p, bits))), bits).String()), nil
+		},
+	})
+
+	cidrSubnetsFunc = function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "prefix", Type: cty.String},
+		},
+		VarParam: &function.Parameter{
+			Name: "newbits",
+			Type: cty.Number,
+		},
+		Type:        function.StaticReturnType(cty.List(cty.String)),
+		Description: "cidrsubnets returns consecutive subnets within the given prefix, extended by the given number of bits each.",
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			p, err := parsePrefix(args[0])
+			if err != nil {
+				return cty.NilVal, err
+			}
+			if len(args) == 1 {
+				return cty.ListValEmpty(cty.String), nil
+			}
+			var (
+				next   = big.NewInt(0)
+				end    = prefixSize(p, p.Bits())
+				blocks = make([]cty.Value, 0, len(args)-1)
+			)
+			for i, a := range args[1:] {
+				newbits, _ := a.AsBigFloat().Int64()
+				bits := p.Bits() + int(newbits)
+				if newbits < 1 || bits > p.Addr().BitLen() {
+					return cty.NilVal, function.NewArgErrorf(i+1, "cannot extend prefix %s by %d bits", p, newbits)
+				}
+				// Subnets are aligned to their own size.
+				size := prefixSize(p, bits)
+				if r := new(big.Int).Mod(next, size); r.Sign() > 0 {
+					next.Add(next, new(big.Int).Sub(size, r))
+				}
+				if new(big.Int).Add(next, size).Cmp(end) > 0 {
+					return cty.NilVal, function.NewArgErrorf(i+1, "not enough remaining address space for a subnet with a prefix of %d bits", bits)
+				}
+				blocks = append(blocks, cty.StringVal(netip.PrefixFrom(addrAdd(p.Addr(), next), bits).String()))
+				next.Add(next, size)
+			}
+			return cty.ListVal(blocks), nil
+		},
+	})
+)
+
+// parsePrefix parses the given value as a CIDR prefix, and returns its masked form.
+func parsePrefix(v cty.Value) (netip.Prefix, error) {
+	p, err := netip.ParsePrefix(v.AsString())
+	if err != nil {
+		return netip.Prefix{}, function.NewArgErrorf(0, "invalid CIDR expression: %v", err)
+	}
+	return p.Masked(), nil
+}
+
+// prefixSize returns the number of addresses in a prefix of the given
+// length, that is a subnet of the given prefix (of the same family).
+func prefixSize(p netip.Prefix, bits int) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.Addr().BitLen()-bits))
+}
+
+// addrAdd returns the address that is n addresses after the given address.
+func addrAdd(a netip.Addr, n *big.Int) netip.Addr {
+	b := new(big.Int).Add(new(big.Int).SetBytes(a.AsSlice()), n).FillBytes(make([]byte, a.BitLen()/8))
+	r, _ := netip.AddrFromSlice(b)
+	return r
+}
+
 // MakeFileFunc returns a function that reads a file
 // from the given base directory.
 func MakeFileFunc(base string) function.Function {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -395,3 +396,111 @@ func TestYAMLMerge(t *testing.T) {
 	})
 	require.EqualError(t, err, "yamlmerge: failed to merge yaml: key zzz is defined in both src and dst, but has a different type")
 }
+
+func TestStdFuncs(t *testing.T) {
+	var doc struct {
+		Values []string `spec:"values"`
+	}
+	err := New().EvalBytes([]byte(`
+locals {
+  sizes = { small = 10, large = 100 }
+}
+values = [
+  format("%s_%03d", "shard", 7),
+  tostring(lookup(local.sizes, "large", 0)),
+  tostring(lookup(local.sizes, "medium", 50)),
+  tostring(strcontains("atlasgo.io", "go")),
+  tostring(sum([1, 2, 3.5])),
+  one(["only"]),
+  tostring(one([]) == null),
+  coalesce(null, "fallback"),
+  substr(sha256("users"), 0, 8),
+  base64decode(base64encode("atlas")),
+  try(regex("^v([0-9]+)$", "latest")[0], "default"),
+  tostring(can(regex("^v([0-9]+)$", "latest"))),
+  cidrhost("10.0.0.0/24", 5),
+  cidrhost("10.0.0.0/24", -2),
+  cidrnetmask("172.16.0.0/12"),
+  cidrsubnet("10.0.0.0/16", 8, 2),
+  cidrsubnet("fd00::/48", 16, 255),
+  join(",", cidrsubnets("10.1.0.0/16", 4, 4, 8, 4)),
+]
+`), &doc, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"shard_007",
+		"100",
+		"50",
+		"true",
+		"6.5",
+		"only",
+		"true",
+		"fallback",
+		"7dfb4cf6",
+		"atlas",
+		"default",
+		"false",
+		"10.0.0.5",
+		"10.0.0.254",
+		"255.240.0.0",
+		"10.0.2.0/24",
+		"fd00:0:0:ff::/64",
+		"10.1.0.0/20,10.1.16.0/20,10.1.32.0/24,10.1.48.0/20",
+	}, doc.Values)
+
+	_, err = cidrSubnetFunc.Call([]cty.Value{cty.StringVal("10.0.0.0/24"), cty.NumberIntVal(2), cty.NumberIntVal(4)})
+	require.EqualError(t, err, "prefix extension of 2 bits does not accommodate subnet number 4")
+	_, err = cidrHostFunc.Call([]cty.Value{cty.StringVal("10.0.0.0/30"), cty.NumberIntVal(4)})
+	require.EqualError(t, err, "host number 4 does not fit in prefix 10.0.0.0/30")
+	_, err = cidrNetmaskFunc.Call([]cty.Value{cty.StringVal("fd00::/48")})
+	require.EqualError(t, err, "only IPv4 prefixes have netmasks, got fd00::/48")
+	_, err = oneFunc.Call([]cty.Value{cty.ListVal([]cty.Value{cty.True, cty.False})})
+	require.EqualError(t, err, "must be a list, set or tuple value with either zero or one elements")
+}
+
+func TestSQLQuoting(t *testing.T) {
+	var (
+		doc struct {
+			Values []string `spec:"values"`
+		}
+		b = []byte(`
+variable "status" {
+  type    = string
+  default = "it's \\active\\"
+}
+locals {
+  statuses = ["active", "o'neil"]
+  column   = "user\"id"
+}
+values = [
+  sqlliteral(var.status),
+  sqlliteral(local.statuses),
+  sqlliteral([1, 2.5, true, null]),
+  sqlident(local.column),
+  <<-SQL
+    ${sqlident(local.column)} > 0 AND status IN (${sqlliteral(local.statuses)})
+  SQL
+]
+`)
+	)
+	require.NoError(t, New().EvalBytes(b, &doc, nil))
+	require.Equal(t, []string{
+		`'it''s \active\'`,
+		`'active', 'o''neil'`,
+		`1, 2.5, TRUE, NULL`,
+		`"user""id"`,
+		"\"user\"\"id\" > 0 AND status IN ('active', 'o''neil')\n",
+	}, doc.Values)
+
+	// Dialect-specific quoting.
+	s := New(WithSQLQuoting(SQLQuoting{
+		Literal: func(s string) string { return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'" },
+		Ident:   func(s string) string { return "`" + s + "`" },
+	}))
+	require.NoError(t, s.EvalBytes(b, &doc, nil))
+	require.Equal(t, `'it''s \\active\\'`, doc.Values[0])
+	require.Equal(t, "`user\"id`", doc.Values[3])
+
+	err := New().EvalBytes([]byte(`values = [sqlliteral({a = 1})]`), &doc, nil)
+	require.ErrorContains(t, err, "unsupported value type object")
+}
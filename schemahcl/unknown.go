@@ -0,0 +1,102 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// UnknownElement describes an attribute or a block of a document that is not defined
+// by the type it was decoded into (e.g., an attribute that was added in a newer driver
+// version), and therefore was ignored.
+type UnknownElement struct {
+	// Path of the block that holds the element. e.g., table.users.column.id.
+	// An empty path indicates the element is defined at the top-level.
+	Path string
+	// Name of the attribute, or the type of the block.
+	Name string
+	// Block reports if the element is a block or an attribute.
+	Block bool
+	// Range of the element in the document.
+	Range *hcl.Range
+}
+
+// String implements fmt.Stringer.
+func (u *UnknownElement) String() string {
+	var b strings.Builder
+	if u.Range != nil {
+		b.WriteString(u.Range.String())
+		b.WriteString(": ")
+	}
+	if u.Block {
+		fmt.Fprintf(&b, "unknown block %q", u.Name)
+	} else {
+		fmt.Fprintf(&b, "unknown attribute %q", u.Name)
+	}
+	if u.Path != "" {
+		fmt.Fprintf(&b, " in %s", u.Path)
+	}
+	return b.String()
+}
+
+// CollectUnknown returns an EvalOptions.Unknown handler that collects the unknown
+// elements into the given slice, for reporting them as warnings instead of failing.
+func CollectUnknown(elems *[]*UnknownElement) func(*UnknownElement) error {
+	return func(u *UnknownElement) error {
+		*elems = append(*elems, u)
+		return nil
+	}
+}
+
+// decoder holds the state of reading a resource into a struct. A nil decoder
+// is valid and ignores the elements that were not read, as before.
+type decoder struct {
+	path       []string
+	unknown    func(*UnknownElement) error
+	deprecated func(*Deprecation)
+}
+
+// child returns the decoder of the given child resource.
+func (d *decoder) child(r *Resource) *decoder {
+	if d == nil {
+		return nil
+	}
+	path := slices.Clip(append(d.path, r.Type))
+	for _, l := range labels(r) {
+		path = append(path, l)
+	}
+	return &decoder{path: path, unknown: d.unknown, deprecated: d.deprecated}
+}
+
+// reportUnknown reports the attributes and children of the resource that were not read.
+func (d *decoder) reportUnknown(r *Resource, attrs, children map[string]struct{}) error {
+	if d == nil || d.unknown == nil {
+		return nil
+	}
+	path := strings.Join(d.path, ".")
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		u := &UnknownElement{Path: path, Name: k}
+		if a, ok := r.Attr(k); ok {
+			u.Range = a.Range()
+		}
+		if err := d.unknown(u); err != nil {
+			return err
+		}
+	}
+	for _, c := range r.Children {
+		if _, ok := children[c.Type]; !ok {
+			continue
+		}
+		if err := d.unknown(&UnknownElement{Path: path, Name: c.Type, Block: true, Range: c.Range()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
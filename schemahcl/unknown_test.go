@@ -0,0 +1,66 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalOptions_Unknown(t *testing.T) {
+	type (
+		Column struct {
+			Name string `spec:",name"`
+			Type string `spec:"type"`
+		}
+		Table struct {
+			Name    string    `spec:",name"`
+			Columns []*Column `spec:"column"`
+			DefaultExtension
+		}
+		Doc struct {
+			Tables []*Table `spec:"table"`
+		}
+	)
+	p := hclparse.NewParser()
+	_, diags := p.ParseHCL([]byte(`table "users" {
+  column "id" {
+    type     = "int"
+    identity = true
+  }
+  # Kept by the table extension.
+  charset = "utf8"
+}
+
+view "v" {}
+
+version = 2
+`), "schema.hcl")
+	require.False(t, diags.HasErrors())
+
+	var (
+		doc   Doc
+		elems []*UnknownElement
+	)
+	err := New().EvalOptions(p, &doc, &EvalOptions{Unknown: CollectUnknown(&elems)})
+	require.NoError(t, err)
+	require.Len(t, doc.Tables, 1)
+	require.Equal(t, "int", doc.Tables[0].Columns[0].Type)
+	require.Len(t, elems, 3)
+	require.Equal(t, `schema.hcl:4,5-20: unknown attribute "identity" in table.users.column.id`, elems[0].String())
+	require.Equal(t, `schema.hcl:12,1-12: unknown attribute "version"`, elems[1].String())
+	require.Equal(t, `schema.hcl:10,1-5: unknown block "view"`, elems[2].String())
+
+	// Fail on unknown elements.
+	err = New().EvalOptions(p, &doc, &EvalOptions{
+		Unknown: func(u *UnknownElement) error {
+			return errors.New(u.String())
+		},
+	})
+	require.EqualError(t, err, `schemahcl: failed reading spec as *schemahcl.Doc: schema.hcl:4,5-20: unknown attribute "identity" in table.users.column.id`)
+}
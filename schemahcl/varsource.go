@@ -0,0 +1,212 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schemahcl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+type (
+	// VarSource resolves the value of an input variable from an external source, such as
+	// the environment, a file or a secret manager. The attributes are the evaluated attributes
+	// of the "source" block of the variable. A null value indicates that the source does not
+	// hold a value for the variable, and the next source (or the default value) is used.
+	VarSource func(ctx context.Context, name string, attrs map[string]cty.Value) (cty.Value, error)
+
+	// varSource is a source block of a variable.
+	//
+	//	variable "url" {
+	//	  type      = string
+	//	  sensitive = true
+	//	  source "env" {
+	//	    name = "DB_URL"
+	//	  }
+	//	}
+	varSource struct {
+		Kind   string   `hcl:",label"`
+		Remain hcl.Body `hcl:",remain"`
+	}
+)
+
+// WithVarSource registers a variable source kind and its resolver. The built-in
+// sources are "env", "file" and "exec", and they can be overridden by this option.
+func WithVarSource(kind string, s VarSource) Option {
+	return func(c *Config) {
+		if c.varsrc == nil {
+			c.varsrc = make(map[string]VarSource)
+		}
+		c.varsrc[kind] = s
+	}
+}
+
+// EnvVarSource resolves variables from environment variables. The
+// source block expects the "name" attribute of the variable to read.
+func EnvVarSource(_ context.Context, _ string, attrs map[string]cty.Value) (cty.Value, error) {
+	name, err := sourceString(attrs, "name")
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return cty.StringVal(v), nil
+	}
+	return cty.NullVal(cty.String), nil
+}
+
+// FileVarSource resolves variables from files (e.g., mounted secrets). The source
+// block expects the "path" attribute of the file to read. Trailing newlines are
+// trimmed, and a missing file resolves to null.
+func FileVarSource(_ context.Context, _ string, attrs map[string]cty.Value) (cty.Value, error) {
+	path, err := sourceString(attrs, "path")
+	if err != nil {
+		return cty.NilVal, err
+	}
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return cty.NullVal(cty.String), nil
+	case err != nil:
+		return cty.NilVal, err
+	}
+	return cty.StringVal(strings.TrimRight(string(b), "\r\n")), nil
+}
+
+// ExecVarSource resolves variables by executing an external provider, such as the CLI
+// of a secret manager. The source block expects the "command" attribute to hold the
+// program and its arguments. The contract of the provider is as follows:
+//
+//   - The name of the variable is passed in the ATLAS_VAR_NAME environment variable.
+//   - The value is written to stdout, and trailing newlines are trimmed.
+//   - A non-zero exit code fails the evaluation, and stderr is used as the error message.
+func ExecVarSource(ctx context.Context, name string, attrs map[string]cty.Value) (cty.Value, error) {
+	v, ok := attrs["command"]
+	if !ok || v.IsNull() {
+		return cty.NilVal, errors.New(`missing "command" attribute`)
+	}
+	v, err := convert.Convert(v, cty.List(cty.String))
+	if err != nil || v.LengthInt() == 0 {
+		return cty.NilVal, errors.New(`"command" attribute must be a non-empty list of strings`)
+	}
+	args := make([]string, 0, v.LengthInt())
+	for _, a := range v.AsValueSlice() {
+		args = append(args, a.AsString())
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "ATLAS_VAR_NAME="+name)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return cty.NilVal, fmt.Errorf("executing %q: %s", args[0], msg)
+		}
+		return cty.NilVal, fmt.Errorf("executing %q: %w", args[0], err)
+	}
+	return cty.StringVal(strings.TrimRight(stdout.String(), "\r\n")), nil
+}
+
+// sourceString returns the string attribute of a source block.
+func sourceString(attrs map[string]cty.Value, name string) (string, error) {
+	v, ok := attrs[name]
+	if !ok || v.IsNull() || v.Type() != cty.String {
+		return "", fmt.Errorf("missing or invalid %q attribute", name)
+	}
+	return v.AsString(), nil
+}
+
+// sourceVal resolves the value of the variable from its sources, in order.
+// A null value is returned if none of the sources holds a value.
+func (s *State) sourceVal(ctx *hcl.EvalContext, v *blockVar) (cty.Value, error) {
+	goctx := s.config.ctx
+	if goctx == nil {
+		goctx = context.Background()
+	}
+	for _, src := range v.Sources {
+		h, ok := s.config.varsrc[src.Kind]
+		if !ok {
+			return cty.NilVal, fmt.Errorf("unknown source %q for variable %q", src.Kind, v.Name)
+		}
+		attrs, diags := src.Remain.JustAttributes()
+		if diags.HasErrors() {
+			return cty.NilVal, diags
+		}
+		vals := make(map[string]cty.Value, len(attrs))
+		for k, at := range attrs {
+			x, diags := at.Expr.Value(ctx)
+			if diags.HasErrors() {
+				return cty.NilVal, diags
+			}
+			vals[k] = x
+		}
+		x, err := h(goctx, v.Name, vals)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("resolving variable %q from %s source: %w", v.Name, src.Kind, err)
+		}
+		if !x.IsNull() {
+			return x, nil
+		}
+	}
+	return cty.NullVal(cty.DynamicPseudoType), nil
+}
+
+// sensitiveVals returns the string forms of the primitive values held by
+// the given value, which should be redacted from error messages.
+func sensitiveVals(v cty.Value) (vs []string) {
+	switch t := v.Type(); {
+	case v.IsNull() || !v.IsKnown():
+	case t == cty.String:
+		if s := v.AsString(); s != "" {
+			vs = append(vs, s)
+		}
+	case t.IsPrimitiveType():
+	case t.IsListType(), t.IsSetType(), t.IsTupleType(), t.IsMapType(), t.IsObjectType():
+		for it := v.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			vs = append(vs, sensitiveVals(ev)...)
+		}
+	}
+	return vs
+}
+
+// redactedError wraps an error and redacts the sensitive values from its message.
+type redactedError struct {
+	err     error
+	secrets []string
+}
+
+// redact returns an error that redacts the given secrets from its message.
+func redact(err error, secrets []string) error {
+	if err == nil || len(secrets) == 0 {
+		return err
+	}
+	return &redactedError{err: err, secrets: secrets}
+}
+
+// Error implements the error interface.
+func (e *redactedError) Error() string {
+	msg := e.err.Error()
+	// Longer secrets are replaced first, in case they contain shorter ones.
+	secrets := slices.Clone(e.secrets)
+	slices.SortFunc(secrets, func(a, b string) int { return len(b) - len(a) })
+	for _, s := range secrets {
+		msg = strings.ReplaceAll(msg, s, "<sensitive>")
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -7,6 +7,7 @@ package specutil
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlspec"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -119,6 +121,9 @@ func Scan(r *schema.Realm, doc *ScanDoc, funcs *ScanFuncs) error {
 		if err := convertCommentFromSpec(s, &s1.Attrs); err != nil {
 			return err
 		}
+		if err := convertExtFromSpec(s, &s1.Attrs); err != nil {
+			return err
+		}
 		schemahcl.AppendPos(&s1.Attrs, s.Range)
 		r.AddSchemas(s1)
 		byName[s.Name] = s1
@@ -139,7 +144,7 @@ func Scan(r *schema.Realm, doc *ScanDoc, funcs *ScanFuncs) error {
 		}
 		t, err := funcs.Table(st, s)
 		if err != nil {
-			return fmt.Errorf("cannot convert table %q: %w", st.Name, err)
+			return schema.WrapPos(rangePos(st.Range), err, "cannot convert table %q", st.Name)
 		}
 		if tn := typeName(t); tn != typeTable {
 			aliases[tn] = typeTable
@@ -191,7 +196,7 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	for _, cs := range spec.Columns {
 		c, err := convertColumn(cs, t)
 		if err != nil {
-			return nil, err
+			return nil, withPos(cs.Range, err)
 		}
 		schemahcl.AppendPos(&c.Attrs, cs.Range)
 		t.AddColumns(c)
@@ -199,7 +204,7 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	if spec.PrimaryKey != nil {
 		pk, err := convertPK(spec.PrimaryKey, t)
 		if err != nil {
-			return nil, err
+			return nil, withPos(spec.PrimaryKey.Range, err)
 		}
 		schemahcl.AppendPos(&pk.Attrs, spec.PrimaryKey.Range)
 		t.SetPrimaryKey(pk)
@@ -207,7 +212,7 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	for _, idx := range spec.Indexes {
 		i, err := convertIndex(idx, t)
 		if err != nil {
-			return nil, err
+			return nil, withPos(idx.Range, err)
 		}
 		schemahcl.AppendPos(&i.Attrs, idx.Range)
 		t.AddIndexes(i)
@@ -215,7 +220,7 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	for _, c := range spec.Checks {
 		ck, err := convertCheck(c)
 		if err != nil {
-			return nil, err
+			return nil, withPos(c.Range, err)
 		}
 		schemahcl.AppendPos(&ck.Attrs, c.Range)
 		t.AddChecks(ck)
@@ -223,6 +228,9 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	if err := convertCommentFromSpec(spec, &t.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertExtFromSpec(spec, &t.Attrs); err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -239,6 +247,15 @@ func Column(spec *sqlspec.Column, conv ConvertTypeFunc) (*schema.Column, error)
 		return nil, err
 	}
 	out.Default = d
+	if a, ok := spec.Attr("logical_default"); ok {
+		x, err := Default(a.V)
+		if err != nil {
+			return nil, fmt.Errorf("logical default of column %q: %w", spec.Name, err)
+		}
+		if x != nil {
+			out.Attrs = append(out.Attrs, &schema.LogicalDefault{X: x})
+		}
+	}
 	ct, err := conv(spec)
 	if err != nil {
 		return nil, err
@@ -247,6 +264,12 @@ func Column(spec *sqlspec.Column, conv ConvertTypeFunc) (*schema.Column, error)
 	if err := convertCommentFromSpec(spec, &out.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertClassFromSpec(spec, out); err != nil {
+		return nil, err
+	}
+	if err := convertExtFromSpec(spec, &out.Attrs); err != nil {
+		return nil, err
+	}
 	return out, err
 }
 
@@ -336,23 +359,24 @@ func Index(spec *sqlspec.Index, parent *schema.Table, partFns ...func(*sqlspec.I
 			part := &schema.IndexPart{SeqNo: i, Desc: p.Desc}
 			switch {
 			case p.Column == nil && p.Expr == "":
-				return nil, fmt.Errorf(`"column" or "expr" are required for index %q at position %d`, spec.Name, i)
+				return nil, withPos(p.Range, fmt.Errorf(`"column" or "expr" are required for index %q at position %d`, spec.Name, i))
 			case p.Column != nil && p.Expr != "":
-				return nil, fmt.Errorf(`cannot use both "column" and "expr" in index %q at position %d`, spec.Name, i)
+				return nil, withPos(p.Range, fmt.Errorf(`cannot use both "column" and "expr" in index %q at position %d`, spec.Name, i))
 			case p.Expr != "":
 				part.X = &schema.RawExpr{X: p.Expr}
 			case p.Column != nil:
 				c, err := ColumnByRef(parent, p.Column)
 				if err != nil {
-					return nil, err
+					return nil, withPos(p.Range, err)
 				}
 				part.C = c
 			}
 			for _, f := range partFns {
 				if err := f(p, part); err != nil {
-					return nil, err
+					return nil, withPos(p.Range, err)
 				}
 			}
+			schemahcl.AppendPos(&part.Attrs, p.Range)
 			parts = append(parts, part)
 		}
 	}
@@ -365,6 +389,9 @@ func Index(spec *sqlspec.Index, parent *schema.Table, partFns ...func(*sqlspec.I
 	if err := convertCommentFromSpec(spec, &idx.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertExtFromSpec(spec, &idx.Attrs); err != nil {
+		return nil, err
+	}
 	for _, p := range idx.Parts {
 		if p.C != nil {
 			p.C.AddIndexes(idx)
@@ -402,6 +429,9 @@ func PrimaryKey(spec *sqlspec.PrimaryKey, parent *schema.Table) (*schema.Index,
 	if err := convertCommentFromSpec(spec, &pk.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertExtFromSpec(spec, &pk.Attrs); err != nil {
+		return nil, err
+	}
 	for _, p := range pk.Parts {
 		if p.C != nil {
 			p.C.AddIndexes(pk)
@@ -424,12 +454,12 @@ func linkForeignKeys(funcs *ScanFuncs, tbl *schema.Table, fks []*sqlspec.Foreign
 			fk.OnDelete = schema.ReferenceOption(FromVar(spec.OnDelete.V))
 		}
 		if n, m := len(spec.Columns), len(spec.RefColumns); n != m {
-			return fmt.Errorf("sqlspec: number of referencing and referenced columns do not match for foreign-key %q", fk.Symbol)
+			return withPos(spec.Range, fmt.Errorf("sqlspec: number of referencing and referenced columns do not match for foreign-key %q", fk.Symbol))
 		}
 		for _, ref := range spec.Columns {
 			c, err := ColumnByRef(tbl, ref)
 			if err != nil {
-				return err
+				return withPos(spec.Range, err)
 			}
 			fk.Columns = append(fk.Columns, c)
 		}
@@ -440,10 +470,10 @@ func linkForeignKeys(funcs *ScanFuncs, tbl *schema.Table, fks []*sqlspec.Foreign
 				c, err = ColumnByRef(fk.Table, ref)
 			}
 			if err != nil {
-				return err
+				return withPos(spec.Range, err)
 			}
 			if i > 0 && fk.RefTable != t {
-				return fmt.Errorf("sqlspec: more than 1 table was referenced for foreign-key %q", fk.Symbol)
+				return withPos(spec.Range, fmt.Errorf("sqlspec: more than 1 table was referenced for foreign-key %q", fk.Symbol))
 			}
 			fk.RefTable = t
 			fk.RefColumns = append(fk.RefColumns, c)
@@ -451,13 +481,31 @@ func linkForeignKeys(funcs *ScanFuncs, tbl *schema.Table, fks []*sqlspec.Foreign
 		tbl.ForeignKeys = append(tbl.ForeignKeys, fk)
 		if funcs.ForeignKey != nil {
 			if err := funcs.ForeignKey(spec, fk); err != nil {
-				return err
+				return withPos(spec.Range, err)
 			}
 		}
 	}
 	return nil
 }
 
+// rangePos returns the position of the given range, or nil if it was not recorded.
+func rangePos(r *hcl.Range) *schema.Pos {
+	if r == nil {
+		return nil
+	}
+	return schemahcl.RangeAsPos(r)
+}
+
+// withPos attaches the position of the given range to the error, unless the
+// error already carries a more specific position (e.g., of an index part).
+func withPos(r *hcl.Range, err error) error {
+	var pe *schema.PosError
+	if r == nil || errors.As(err, &pe) {
+		return err
+	}
+	return &schema.PosError{Pos: schemahcl.RangeAsPos(r), Err: err}
+}
+
 // FromSchema converts a schema.Schema into sqlspec.Schema and []sqlspec.Table.
 func FromSchema(s *schema.Schema, funcs *SchemaFuncs) (*SchemaSpec, error) {
 	spec := &SchemaSpec{
@@ -477,6 +525,7 @@ func FromSchema(s *schema.Schema, funcs *SchemaFuncs) (*SchemaSpec, error) {
 		spec.Tables = append(spec.Tables, table)
 	}
 	convertCommentFromSchema(s.Attrs, &spec.Schema.Extra.Attrs)
+	convertExtFromSchema(s.Attrs, &spec.Schema.Extra)
 	return spec, nil
 }
 
@@ -524,6 +573,7 @@ func FromTable(t *schema.Table, colFn TableColumnSpecFunc, pkFn PrimaryKeySpecFu
 		spec.Extra.Children = append(spec.Extra.Children, &schemahcl.Resource{Attrs: []*schemahcl.Attr{deps}})
 	}
 	convertCommentFromSchema(t.Attrs, &spec.Extra.Attrs)
+	convertExtFromSchema(t.Attrs, &spec.Extra)
 	return spec, nil
 }
 
@@ -661,7 +711,17 @@ func FromColumn(c *schema.Column, columnTypeSpec ColumnTypeSpecFunc) (*sqlspec.C
 		}
 		spec.Extra.Attrs = slices.Insert(spec.Extra.Attrs, 0, &schemahcl.Attr{K: "default", V: lv})
 	}
-	convertCommentFromSchema(c.Attrs, &spec.Extra.Attrs)
+	var ld schema.LogicalDefault
+	if sqlx.Has(c.Attrs, &ld) {
+		// Logical defaults are converted the same as database defaults.
+		lv, err := ColumnDefault(&schema.Column{Name: c.Name, Type: c.Type, Default: ld.X})
+		if err != nil {
+			return nil, err
+		}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, &schemahcl.Attr{K: "logical_default", V: lv})
+	}
+	convertClassFromSchema(c.Attrs, &spec.Extra.Attrs)
+	convertExtFromSchema(c.Attrs, &spec.Extra)
 	return spec, nil
 }
 
@@ -768,6 +828,7 @@ func ColumnDefault(c *schema.Column) (cty.Value, error) {
 func FromIndex(idx *schema.Index, partFns ...func(*schema.Index, *schema.IndexPart, *sqlspec.IndexPart) error) (*sqlspec.Index, error) {
 	spec := &sqlspec.Index{Name: idx.Name, Unique: idx.Unique}
 	convertCommentFromSchema(idx.Attrs, &spec.Extra.Attrs)
+	convertExtFromSchema(idx.Attrs, &spec.Extra)
 	spec.Parts = make([]*sqlspec.IndexPart, len(idx.Parts))
 	for i, p := range idx.Parts {
 		part := &sqlspec.IndexPart{Desc: p.Desc}
@@ -1045,6 +1106,134 @@ func convertCommentFromSchema(src []schema.Attr, target *[]*schemahcl.Attr) {
 	}
 }
 
+// convertClassFromSpec converts the "classification" attribute of a column spec to
+// a classification marker in the column comment. See schema.Classification for more info.
+func convertClassFromSpec(spec Attrer, c *schema.Column) error {
+	a, ok := spec.Attr("classification")
+	if !ok {
+		return nil
+	}
+	s, err := a.String()
+	if err != nil {
+		return err
+	}
+	if class := schema.Classification(s); !class.Valid() {
+		return fmt.Errorf("invalid classification %q for column %q", s, c.Name)
+	}
+	var cm schema.Comment
+	if sqlx.Has(c.Attrs, &cm) {
+		if _, class := schema.CommentClassification(cm.Text); class != "" {
+			return fmt.Errorf("column %q is classified by both its comment and the classification attribute", c.Name)
+		}
+	}
+	c.SetClassification(schema.Classification(s))
+	return nil
+}
+
+// convertClassFromSchema converts a column comment to the spec comment and
+// classification attributes, if the comment holds a classification marker.
+func convertClassFromSchema(src []schema.Attr, target *[]*schemahcl.Attr) {
+	var c schema.Comment
+	if !sqlx.Has(src, &c) {
+		return
+	}
+	text, class := schema.CommentClassification(c.Text)
+	if class == "" || text != "" {
+		*target = append(*target, schemahcl.StringAttr("comment", text))
+	}
+	if class != "" {
+		*target = append(*target, schemahcl.StringAttr("classification", string(class)))
+	}
+}
+
+// convertExtFromSpec converts the extension blocks of a spec (e.g., "x-sharding")
+// to schema element extension attributes. See schema.ExtAttr for more info.
+func convertExtFromSpec(spec schemahcl.Remainer, attrs *[]schema.Attr) error {
+	for _, r := range spec.Remain().Children {
+		if !strings.HasPrefix(r.Type, schema.ExtNamespacePrefix) || r.Name != "" {
+			continue
+		}
+		for _, a := range r.Attrs {
+			name := r.Type + "." + a.K
+			t, ok := schema.LookupExtAttr(name)
+			if !ok {
+				t = extType(a.V)
+			}
+			var (
+				v   any
+				err error
+			)
+			switch t {
+			case reflect.TypeOf(""):
+				v, err = a.String()
+			case reflect.TypeOf(false):
+				v, err = a.Bool()
+			case reflect.TypeOf(int64(0)):
+				v, err = a.Int64()
+			case reflect.TypeOf(float64(0)):
+				v, err = a.Float64()
+			case reflect.TypeOf([]string(nil)):
+				v, err = a.Strings()
+			default:
+				err = fmt.Errorf("unsupported value type %s", a.V.Type().FriendlyName())
+			}
+			if err != nil {
+				return fmt.Errorf("extension attribute %q: %w", name, err)
+			}
+			e, err := schema.NewExtAttr(name, v)
+			if err != nil {
+				return err
+			}
+			*attrs = append(*attrs, e)
+		}
+	}
+	return nil
+}
+
+// extType returns the Go type of unregistered extension attribute values.
+func extType(v cty.Value) reflect.Type {
+	switch t := v.Type(); {
+	case t == cty.String:
+		return reflect.TypeOf("")
+	case t == cty.Bool:
+		return reflect.TypeOf(false)
+	case t == cty.Number && v.IsKnown() && !v.IsNull():
+		if v.AsBigFloat().IsInt() {
+			return reflect.TypeOf(int64(0))
+		}
+		return reflect.TypeOf(float64(0))
+	case t.IsTupleType(), t.IsListType():
+		return reflect.TypeOf([]string(nil))
+	}
+	return nil
+}
+
+// convertExtFromSchema converts the extension attributes of a schema element
+// to spec blocks, one for each namespace, in order of their first appearance.
+func convertExtFromSchema(src []schema.Attr, target *schemahcl.Resource) {
+	byNS := make(map[string]*schemahcl.Resource)
+	for _, e := range schema.ExtAttrs(src) {
+		r, ok := byNS[e.Namespace()]
+		if !ok {
+			r = &schemahcl.Resource{Type: e.Namespace()}
+			byNS[e.Namespace()] = r
+			target.Children = append(target.Children, r)
+		}
+		switch v := e.V.(type) {
+		case string:
+			r.Attrs = append(r.Attrs, schemahcl.StringAttr(e.Key(), v))
+		case bool:
+			r.Attrs = append(r.Attrs, schemahcl.BoolAttr(e.Key(), v))
+		case int64:
+			r.Attrs = append(r.Attrs, schemahcl.Int64Attr(e.Key(), v))
+		case float64:
+			r.Attrs = append(r.Attrs, schemahcl.Float64Attr(e.Key(), v))
+		case []string:
+			r.Attrs = append(r.Attrs, schemahcl.StringsAttr(e.Key(), v...))
+		}
+	}
+}
+
 // ReferenceVars holds the HCL variables
 // for foreign keys' referential-actions.
 var ReferenceVars = []string{
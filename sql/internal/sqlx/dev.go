@@ -47,7 +47,7 @@ func (d *DevDriver) NormalizeRealm(ctx context.Context, r *schema.Realm) (nr *sc
 			Schemas: make([]string, 0, len(r.Schemas)),
 		}
 	)
-	name2pos := make(key2pos)
+	name2pos, name2ext := make(key2pos), make(key2ext)
 	for _, o := range r.Objects {
 		changes = append(changes, &schema.AddObject{
 			O: o,
@@ -59,6 +59,7 @@ func (d *DevDriver) NormalizeRealm(ctx context.Context, r *schema.Realm) (nr *sc
 	}
 	for _, s := range r.Schemas {
 		k, _ := name2pos.put(s.Attrs, keyS, s.Name)
+		name2ext.putSchema(s)
 		opts.Schemas = append(opts.Schemas, s.Name)
 		changes = append(changes, &schema.AddSchema{
 			S: s,
@@ -88,6 +89,11 @@ func (d *DevDriver) NormalizeRealm(ctx context.Context, r *schema.Realm) (nr *sc
 	if len(name2pos) > 0 {
 		name2pos.patchRealm(nr)
 	}
+	if len(name2ext) > 0 {
+		for _, s := range nr.Schemas {
+			name2ext.patchSchema(s, s.Name)
+		}
+	}
 	return nr, nil
 }
 
@@ -121,8 +127,9 @@ func (d *DevDriver) NormalizeSchema(ctx context.Context, s *schema.Schema) (*sch
 	}
 	prevName := s.Name
 	s.Name = dev.Name
-	name2pos := make(key2pos)
+	name2pos, name2ext := make(key2pos), make(key2ext)
 	k, _ := name2pos.put(s.Attrs, keyS, s.Name)
+	name2ext.putSchema(s)
 	for _, t := range s.Tables {
 		// If objects are not strongly connected.
 		if t.Schema != s {
@@ -158,12 +165,16 @@ func (d *DevDriver) NormalizeSchema(ctx context.Context, s *schema.Schema) (*sch
 	}
 	// Preserve the original schema name and attributes.
 	ns.Name = prevName
-	for _, a := range s.Attrs {
+	for _, a := range schema.RemoveAttr[*schema.ExtAttr](s.Attrs) {
 		schema.ReplaceOrAppend(&ns.Attrs, a)
 	}
 	if len(name2pos) > 0 {
 		name2pos.patchSchema(ns)
 	}
+	if len(name2ext) > 0 {
+		// Attributes were recorded with the dev schema name.
+		name2ext.patchSchema(ns, s.Name)
+	}
 	return ns, err
 }
 
@@ -281,3 +292,67 @@ func (k key2pos) patch(ps schema.PosSetter, typename ...string) (string, bool) {
 func poskey(typename ...string) string {
 	return strings.Join(typename, ".")
 }
+
+// key2ext holds the extension attributes of schema elements, as they are
+// not part of the database state and are lost by the dev inspection.
+type key2ext map[string][]schema.Attr
+
+func (k key2ext) put(attrs []schema.Attr, typename ...string) {
+	var ext []schema.Attr
+	for _, a := range attrs {
+		if _, ok := a.(*schema.ExtAttr); ok {
+			ext = append(ext, a)
+		}
+	}
+	if len(ext) > 0 {
+		k[poskey(typename...)] = ext
+	}
+}
+
+func (k key2ext) putSchema(s *schema.Schema) {
+	k.put(s.Attrs, keyS, s.Name)
+	for _, t := range s.Tables {
+		tk := poskey(keyS, s.Name, keyT, t.Name)
+		k.put(t.Attrs, tk)
+		for _, c := range t.Columns {
+			k.put(c.Attrs, tk, keyC, c.Name)
+		}
+		for _, i := range t.Indexes {
+			k.put(i.Attrs, tk, keyI, i.Name)
+		}
+		for _, f := range t.ForeignKeys {
+			k.put(f.Attrs, tk, keyF, f.Symbol)
+		}
+		if t.PrimaryKey != nil {
+			k.put(t.PrimaryKey.Attrs, tk, keyP)
+		}
+	}
+}
+
+// patchSchema patches the extension attributes of the normalized
+// schema ns, that were recorded under the given schema name.
+func (k key2ext) patchSchema(ns *schema.Schema, name string) {
+	k.patch(&ns.Attrs, keyS, name)
+	for _, t := range ns.Tables {
+		tk := poskey(keyS, name, keyT, t.Name)
+		k.patch(&t.Attrs, tk)
+		for _, c := range t.Columns {
+			k.patch(&c.Attrs, tk, keyC, c.Name)
+		}
+		for _, i := range t.Indexes {
+			k.patch(&i.Attrs, tk, keyI, i.Name)
+		}
+		for _, f := range t.ForeignKeys {
+			k.patch(&f.Attrs, tk, keyF, f.Symbol)
+		}
+		if t.PrimaryKey != nil {
+			k.patch(&t.PrimaryKey.Attrs, tk, keyP)
+		}
+	}
+}
+
+func (k key2ext) patch(attrs *[]schema.Attr, typename ...string) {
+	if ext, ok := k[poskey(typename...)]; ok {
+		*attrs = append(schema.RemoveAttr[*schema.ExtAttr](*attrs), ext...)
+	}
+}
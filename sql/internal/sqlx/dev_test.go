@@ -69,6 +69,14 @@ func TestDriver_NormalizeRealm(t *testing.T) {
 	require.Equal(t, schema.NewFilePos("schema.hcl").SetStart(hcl.Pos{Line: 2, Column: 2, Byte: 2}), p)
 	p = normal.Schemas[0].Tables[0].Columns[0].Pos()
 	require.Equal(t, schema.NewFilePos("schema.hcl").SetStart(hcl.Pos{Line: 3, Column: 3, Byte: 3}), p)
+
+	// Retain extension attributes.
+	key := &schema.ExtAttr{Name: "x-sharding.key", V: true}
+	r.Schemas[0].Tables[0].Columns[0].AddAttrs(key)
+	normal, err = dev.NormalizeRealm(context.Background(), r)
+	require.NoError(t, err)
+	require.Equal(t, []*schema.ExtAttr{key}, schema.ExtAttrs(normal.Schemas[0].Tables[0].Columns[0].Attrs))
+	require.Empty(t, schema.ExtAttrs(normal.Schemas[0].Tables[0].Attrs))
 }
 
 type mockDriver struct {
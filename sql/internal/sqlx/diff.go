@@ -6,6 +6,7 @@ package sqlx
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"slices"
 	"sort"
@@ -95,16 +96,57 @@ type (
 		FindTable(*schema.Schema, *schema.Table) (*schema.Table, error)
 	}
 
+	// TableRenamer is an optional interface that allows DiffDriver to report if two
+	// tables that were matched by the TableFinder, but with different names, should
+	// be renamed. For example, case-only renames on case-insensitive databases.
+	TableRenamer interface {
+		NeedsRename(from, to *schema.Table) bool
+	}
+
 	// ChangesAnnotator is an optional interface allows DiffDriver to annotate
 	// changes with additional driver-specific attributes before they are returned.
 	ChangesAnnotator interface {
 		AnnotateChanges([]schema.Change, *schema.DiffOptions) ([]schema.Change, error)
 	}
 
+	// ConversionClassifier is an optional interface allows DiffDriver to classify the
+	// type changes of columns. The classification is attached to the ModifyColumn changes.
+	ConversionClassifier interface {
+		TypeConversion(from, to schema.Type) schema.TypeConversion
+	}
+
+	// AttrChanger is an optional interface that can be implemented by driver-specific
+	// changes that modify the attributes of an element (e.g., a character-set conversion),
+	// allowing them to be filtered by the ignore rules of the diff options.
+	AttrChanger interface {
+		ChangedAttrs() []schema.Attr
+	}
+
+	// ViewChanger is an optional interface that allows DiffDriver to report
+	// if the driver-specific parts of a view were changed. e.g., the column
+	// names that are listed in its definition.
+	ViewChanger interface {
+		ViewChanged(from, to *schema.View) bool
+	}
+
+	// TriggerChanger is an optional interface that allows DiffDriver to report
+	// if the driver-specific parts of a trigger were changed. e.g., its WHEN clause.
+	TriggerChanger interface {
+		TriggerChanged(from, to *schema.Trigger) bool
+	}
+
+	// FuncChanger is an optional interface that allows DiffDriver to override the
+	// default comparison of functions. e.g., to ignore formatting changes in their bodies.
+	FuncChanger interface {
+		FuncChanged(from, to *schema.Func) bool
+	}
+
 	// ChangeSupporter wraps the single SupportChange method.
 	ChangeSupporter interface {
 		// SupportChange can be implemented to tell the Differ if they support
-		// a specific change type, or it should avoid suggesting it.
+		// a specific change type, or it should avoid suggesting it. Changes of
+		// triggers, views, functions and procedures are suggested only if their
+		// creation (e.g., *schema.AddView) is reported as supported.
 		SupportChange(schema.Change) bool
 	}
 )
@@ -152,6 +194,7 @@ func (d *Diff) RealmDiff(from, to *schema.Realm, options ...schema.DiffOption) (
 		for _, t := range s1.Tables {
 			changes = opts.AddOrSkip(changes, addTableChange(t)...)
 		}
+		changes = opts.AddOrSkip(changes, d.objectDiff(&schema.Schema{Name: s1.Name}, s1)...)
 	}
 	return d.mayAnnotate(changes, opts)
 }
@@ -194,6 +237,9 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 		case err != nil:
 			return nil, err
 		default:
+			if r, ok := d.DiffDriver.(TableRenamer); ok && t1.Name != t2.Name && r.NeedsRename(t1, t2) {
+				changes = opts.AddOrSkip(changes, &schema.RenameTable{From: t1, To: t2})
+			}
 			if change, err := d.tableDiff(t1, t2, opts); err != nil {
 				return nil, err
 			} else if len(change) > 0 {
@@ -201,7 +247,6 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 			}
 		}
 	}
-	changes = d.fixRenames(changes)
 	// Add tables.
 	for _, t1 := range to.Tables {
 		switch _, err := d.findTable(from, t1); {
@@ -211,10 +256,207 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 			return nil, err
 		}
 	}
-
+	if changes, err = d.fixRenames(changes, opts); err != nil {
+		return nil, err
+	}
+	changes = opts.AddOrSkip(changes, d.objectDiff(from, to)...)
 	return changes, nil
 }
 
+// objectDiff returns the changes for migrating the triggers, views, functions and procedures
+// of the schema. Each of them is diffed only if the driver supports planning its changes.
+func (d *Diff) objectDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	if d.supportObject((*schema.AddTrigger)(nil)) {
+		changes = append(changes, d.triggerDiffT(from, to)...)
+	}
+	if d.supportObject((*schema.AddView)(nil)) {
+		changes = append(changes, d.viewDiff(from, to)...)
+	}
+	if d.supportObject((*schema.AddFunc)(nil)) {
+		changes = append(changes, d.funcDiff(from, to)...)
+	}
+	if d.supportObject((*schema.AddProc)(nil)) {
+		changes = append(changes, d.procDiff(from, to)...)
+	}
+	return changes
+}
+
+// supportObject reports if the DiffDriver supports the changes of the object
+// described by the given change. Unlike other changes, the changes of views,
+// triggers, functions and procedures are not suggested by default, and the
+// driver must report their support explicitly.
+func (d *Diff) supportObject(c schema.Change) bool {
+	s, ok := d.DiffDriver.(ChangeSupporter)
+	return ok && s.SupportChange(c)
+}
+
+// triggerDiffT returns the changes for migrating the triggers defined on the schema tables.
+// Triggers of dropped tables are dropped with them, and triggers of added tables are created.
+func (d *Diff) triggerDiffT(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, t2 := range to.Tables {
+		var t1Triggers []*schema.Trigger
+		if t1, err := d.findTable(from, t2); err == nil {
+			t1Triggers = t1.Triggers
+		}
+		changes = append(changes, d.triggerDiff(t1Triggers, t2.Triggers)...)
+	}
+	return changes
+}
+
+// viewDiff returns the changes for migrating the schema views and the triggers defined on them.
+func (d *Diff) viewDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, v1 := range from.Views {
+		if _, ok := to.View(v1.Name); !ok {
+			changes = append(changes, &schema.DropView{V: v1})
+		}
+	}
+	for _, v2 := range to.Views {
+		v1, ok := from.View(v2.Name)
+		if !ok {
+			changes = append(changes, &schema.AddView{V: v2})
+			if d.supportObject((*schema.AddTrigger)(nil)) {
+				changes = append(changes, d.triggerDiff(nil, v2.Triggers)...)
+			}
+			continue
+		}
+		var attrs []schema.Change
+		if change := CommentDiff(v1.Attrs, v2.Attrs); change != nil {
+			attrs = append(attrs, change)
+		}
+		if len(attrs) > 0 || BodyDefChanged(v1.Def, v2.Def) || d.viewChanged(v1, v2) {
+			changes = append(changes, &schema.ModifyView{From: v1, To: v2, Changes: attrs})
+		}
+		if d.supportObject((*schema.AddTrigger)(nil)) {
+			changes = append(changes, d.triggerDiff(v1.Triggers, v2.Triggers)...)
+		}
+	}
+	return changes
+}
+
+// funcDiff returns the changes for migrating the schema functions.
+func (d *Diff) funcDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, f1 := range from.Funcs {
+		if _, ok := to.Func(f1.Name); !ok {
+			changes = append(changes, &schema.DropFunc{F: f1})
+		}
+	}
+	for _, f2 := range to.Funcs {
+		f1, ok := from.Func(f2.Name)
+		if !ok {
+			changes = append(changes, &schema.AddFunc{F: f2})
+			continue
+		}
+		var attrs []schema.Change
+		if change := CommentDiff(f1.Attrs, f2.Attrs); change != nil {
+			attrs = append(attrs, change)
+		}
+		if len(attrs) > 0 || d.funcChanged(f1, f2) {
+			changes = append(changes, &schema.ModifyFunc{From: f1, To: f2, Changes: attrs})
+		}
+	}
+	return changes
+}
+
+// procDiff returns the changes for migrating the schema procedures.
+func (d *Diff) procDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, p1 := range from.Procs {
+		if _, ok := to.Proc(p1.Name); !ok {
+			changes = append(changes, &schema.DropProc{P: p1})
+		}
+	}
+	for _, p2 := range to.Procs {
+		p1, ok := from.Proc(p2.Name)
+		if !ok {
+			changes = append(changes, &schema.AddProc{P: p2})
+			continue
+		}
+		var attrs []schema.Change
+		if change := CommentDiff(p1.Attrs, p2.Attrs); change != nil {
+			attrs = append(attrs, change)
+		}
+		if len(attrs) > 0 || funcChanged(p1.Args, p2.Args, p1.Body, p2.Body, p1.Lang, p2.Lang) {
+			changes = append(changes, &schema.ModifyProc{From: p1, To: p2, Changes: attrs})
+		}
+	}
+	return changes
+}
+
+// funcChanged reports if the definition of the function was changed.
+func (d *Diff) funcChanged(from, to *schema.Func) bool {
+	if c, ok := d.DiffDriver.(FuncChanger); ok {
+		return c.FuncChanged(from, to)
+	}
+	return funcChanged(from.Args, to.Args, from.Body, to.Body, from.Lang, to.Lang) || !reflect.DeepEqual(from.Ret, to.Ret)
+}
+
+// funcChanged reports if the signature, the body or the language of a function or procedure were changed.
+func funcChanged(args1, args2 []*schema.FuncArg, body1, body2, lang1, lang2 string) bool {
+	if !strings.EqualFold(lang1, lang2) || BodyDefChanged(body1, body2) || len(args1) != len(args2) {
+		return true
+	}
+	for i := range args1 {
+		a1, a2 := args1[i], args2[i]
+		if a1.Name != a2.Name || a1.Mode != a2.Mode || !reflect.DeepEqual(a1.Type, a2.Type) || !reflect.DeepEqual(a1.Default, a2.Default) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewChanged reports if the driver-specific parts of the view were changed.
+func (d *Diff) viewChanged(from, to *schema.View) bool {
+	c, ok := d.DiffDriver.(ViewChanger)
+	return ok && c.ViewChanged(from, to)
+}
+
+// triggerDiff returns the changes for migrating the triggers of a table or a view.
+func (d *Diff) triggerDiff(from, to []*schema.Trigger) []schema.Change {
+	var changes []schema.Change
+	for _, t1 := range from {
+		if !slices.ContainsFunc(to, func(t2 *schema.Trigger) bool { return t1.Name == t2.Name }) {
+			changes = append(changes, &schema.DropTrigger{T: t1})
+		}
+	}
+	for _, t2 := range to {
+		i := slices.IndexFunc(from, func(t1 *schema.Trigger) bool { return t1.Name == t2.Name })
+		if i == -1 {
+			changes = append(changes, &schema.AddTrigger{T: t2})
+			continue
+		}
+		if t1 := from[i]; d.triggerChanged(t1, t2) {
+			changes = append(changes, &schema.ModifyTrigger{From: t1, To: t2})
+		}
+	}
+	return changes
+}
+
+// triggerChanged reports if the definition of the trigger was changed.
+func (d *Diff) triggerChanged(t1, t2 *schema.Trigger) bool {
+	if t1.ActionTime != t2.ActionTime || t1.For != t2.For || BodyDefChanged(t1.Body, t2.Body) || len(t1.Events) != len(t2.Events) {
+		return true
+	}
+	if c, ok := d.DiffDriver.(TriggerChanger); ok && c.TriggerChanged(t1, t2) {
+		return true
+	}
+	for i := range t1.Events {
+		e1, e2 := t1.Events[i], t2.Events[i]
+		if e1.Name != e2.Name || len(e1.Columns) != len(e2.Columns) {
+			return true
+		}
+		for j := range e1.Columns {
+			if e1.Columns[j].Name != e2.Columns[j].Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // TableDiff implements the schema.TableDiffer interface and returns a list of
 // changes that need to be applied in order to move from one state to the other.
 func (d *Diff) TableDiff(from, to *schema.Table, options ...schema.DiffOption) ([]schema.Change, error) {
@@ -289,6 +531,9 @@ func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]sc
 }
 
 func (d *Diff) mayAnnotate(changes []schema.Change, opts *schema.DiffOptions) (_ []schema.Change, err error) {
+	if len(opts.IgnoreRules) > 0 {
+		changes = ignoreChanges(changes, opts)
+	}
 	r, ok := d.DiffDriver.(ChangesAnnotator)
 	if ok {
 		if changes, err = r.AnnotateChanges(changes, opts); err != nil {
@@ -298,6 +543,102 @@ func (d *Diff) mayAnnotate(changes []schema.Change, opts *schema.DiffOptions) (_
 	return changes, nil
 }
 
+// ignoreChanges removes the changes (or the parts of the changes)
+// that are described by the ignore rules of the diff options.
+func ignoreChanges(changes []schema.Change, opts *schema.DiffOptions) []schema.Change {
+	var (
+		kind = opts.IgnoredKind()
+		kept = make([]schema.Change, 0, len(changes))
+	)
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddAttr:
+			if opts.IgnoredAttr(c.A) {
+				continue
+			}
+		case *schema.DropAttr:
+			if opts.IgnoredAttr(c.A) {
+				continue
+			}
+		case *schema.ModifyAttr:
+			if opts.IgnoredAttr(c.From) || opts.IgnoredAttr(c.To) {
+				continue
+			}
+		case *schema.ModifySchema:
+			if c.Changes = ignoreChanges(c.Changes, opts); len(c.Changes) == 0 {
+				continue
+			}
+		case *schema.ModifyTable:
+			if c.Changes = ignoreChanges(c.Changes, opts); len(c.Changes) == 0 {
+				continue
+			}
+		case *schema.ModifyView:
+			if c.Changes = ignoreChanges(c.Changes, opts); len(c.Changes) == 0 && !BodyDefChanged(c.From.Def, c.To.Def) {
+				continue
+			}
+		case *schema.ModifyFunc:
+			f1, f2 := c.From, c.To
+			if c.Changes = ignoreChanges(c.Changes, opts); len(c.Changes) == 0 && !funcChanged(f1.Args, f2.Args, f1.Body, f2.Body, f1.Lang, f2.Lang) && reflect.DeepEqual(f1.Ret, f2.Ret) {
+				continue
+			}
+		case *schema.ModifyProc:
+			p1, p2 := c.From, c.To
+			if c.Changes = ignoreChanges(c.Changes, opts); len(c.Changes) == 0 && !funcChanged(p1.Args, p2.Args, p1.Body, p2.Body, p1.Lang, p2.Lang) {
+				continue
+			}
+		case *schema.ModifyColumn:
+			c.Change &^= kind
+			// Attribute changes are ignored if the column attributes
+			// are equal, except the ignored attributes and the ones
+			// that are reported by their own change kinds.
+			if c.Change.Is(schema.ChangeAttr) && schema.Equal(columnAttrs(c.From, opts), columnAttrs(c.To, opts), schema.IgnoreAttrOrder()) {
+				c.Change &^= schema.ChangeAttr
+			}
+			if c.Change == schema.NoChange {
+				continue
+			}
+		case *schema.AddIndex:
+			if opts.IgnoredIndex(c.I) {
+				continue
+			}
+		case *schema.DropIndex:
+			if opts.IgnoredIndex(c.I) {
+				continue
+			}
+		case *schema.ModifyIndex:
+			if c.Change &^= kind; c.Change == schema.NoChange || opts.IgnoredIndex(c.From) || opts.IgnoredIndex(c.To) {
+				continue
+			}
+		case *schema.RenameIndex:
+			if opts.IgnoredIndex(c.From) || opts.IgnoredIndex(c.To) {
+				continue
+			}
+		case AttrChanger:
+			if !slices.ContainsFunc(c.ChangedAttrs(), func(a schema.Attr) bool { return !opts.IgnoredAttr(a) }) {
+				continue
+			}
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// columnAttrs returns the column attributes that are compared
+// for detecting attribute changes after applying ignore rules.
+func columnAttrs(c *schema.Column, opts *schema.DiffOptions) []schema.Attr {
+	var attrs []schema.Attr
+	for _, a := range c.Attrs {
+		switch a.(type) {
+		case *schema.Comment, *schema.Charset, *schema.Collation, *schema.GeneratedExpr:
+		default:
+			if !opts.IgnoredAttr(a) {
+				attrs = append(attrs, a)
+			}
+		}
+	}
+	return attrs
+}
+
 // addTableChange returns the changeset for creating the table.
 func addTableChange(t *schema.Table) []schema.Change {
 	return []schema.Change{&schema.AddTable{T: t}}
@@ -317,6 +658,11 @@ func (d *Diff) columnDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]s
 		if err != nil {
 			return nil, err
 		}
+		if m, ok := change.(*schema.ModifyColumn); ok && m.Change.Is(schema.ChangeType) {
+			if cc, ok := d.DiffDriver.(ConversionClassifier); ok {
+				m.Conversion = cc.TypeConversion(c1.Type.Type, c2.Type.Type)
+			}
+		}
 		if change != NoChange {
 			all = append(all, change)
 		}
@@ -342,6 +688,209 @@ func (d *Diff) columnDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]s
 	return changes, nil
 }
 
+// ClassifyConversion classifies the conversion of column values from one type to
+// another using the rules that are common to the different dialects. The size
+// function returns the size of a type in the dialect: the storage size in bytes
+// for integer and floating-point types, and the maximum length for string and
+// binary types. It returns -1 for unbounded types (including decimal types with
+// unlimited precision), and 0 if the size is unknown.
+func ClassifyConversion(from, to schema.Type, size func(schema.Type) int64) schema.TypeConversion {
+	if from == nil || to == nil {
+		return schema.ConversionUnknown
+	}
+	if schema.Equal(from, to) {
+		return schema.ConversionSafe
+	}
+	// fits reports if the string representation
+	// of the "from" value with length n fits "to".
+	fits := func(n int64) schema.TypeConversion {
+		switch s := size(to); {
+		case s == -1 || n > 0 && s >= n:
+			return schema.ConversionSafe
+		case s == 0 || n == 0:
+			return schema.ConversionUnknown
+		default:
+			return schema.ConversionLossy
+		}
+	}
+	switch f := from.(type) {
+	case *schema.IntegerType:
+		fs := size(f)
+		switch t := to.(type) {
+		case *schema.IntegerType:
+			ts := size(t)
+			switch {
+			case fs <= 0 || ts <= 0:
+				return schema.ConversionUnknown
+			case !f.Unsigned && t.Unsigned:
+				return schema.ConversionLossy
+			case f.Unsigned == t.Unsigned && ts >= fs, f.Unsigned && ts > fs:
+				return schema.ConversionSafe
+			default:
+				return schema.ConversionLossy
+			}
+		case *schema.DecimalType:
+			if fs <= 0 {
+				return schema.ConversionUnknown
+			}
+			if size(t) == -1 || t.Precision-t.Scale >= intDigits(fs, f.Unsigned) {
+				return schema.ConversionSafe
+			}
+			return schema.ConversionLossy
+		case *schema.FloatType:
+			// Integers are represented exactly in floating-point types
+			// as long as they fit the significand (24 or 53 bits).
+			ts := size(t)
+			switch {
+			case fs <= 0 || ts <= 0:
+				return schema.ConversionUnknown
+			case ts >= 8 && fs <= 4, ts >= 4 && fs <= 2:
+				return schema.ConversionSafe
+			default:
+				return schema.ConversionLossy
+			}
+		case *schema.StringType:
+			if fs <= 0 {
+				return fits(0)
+			}
+			return fits(int64(intDigits(fs, f.Unsigned) + 1))
+		case *schema.BoolType:
+			return schema.ConversionLossy
+		}
+	case *schema.DecimalType:
+		switch t := to.(type) {
+		case *schema.DecimalType:
+			switch {
+			case size(t) == -1:
+				return schema.ConversionSafe
+			case size(f) == -1, !f.Unsigned && t.Unsigned:
+				return schema.ConversionLossy
+			case t.Precision-t.Scale >= f.Precision-f.Scale && t.Scale >= f.Scale:
+				return schema.ConversionSafe
+			default:
+				return schema.ConversionLossy
+			}
+		case *schema.IntegerType, *schema.FloatType:
+			return schema.ConversionLossy
+		case *schema.StringType:
+			if size(f) == -1 {
+				return fits(0)
+			}
+			// Sign and decimal point.
+			return fits(int64(f.Precision + 2))
+		}
+	case *schema.FloatType:
+		switch t := to.(type) {
+		case *schema.FloatType:
+			fs, ts := size(f), size(t)
+			switch {
+			case fs <= 0 || ts <= 0:
+				return schema.ConversionUnknown
+			case ts >= fs && (f.Unsigned || !t.Unsigned):
+				return schema.ConversionSafe
+			default:
+				return schema.ConversionLossy
+			}
+		case *schema.IntegerType, *schema.DecimalType:
+			return schema.ConversionLossy
+		case *schema.StringType:
+			return fits(0)
+		}
+	case *schema.BoolType:
+		switch to.(type) {
+		case *schema.BoolType, *schema.IntegerType:
+			return schema.ConversionSafe
+		case *schema.StringType:
+			return fits(int64(len("false")))
+		}
+	case *schema.StringType:
+		switch to.(type) {
+		case *schema.StringType:
+			return fits(size(f))
+		case *schema.EnumType:
+			return schema.ConversionLossy
+		}
+	case *schema.BinaryType:
+		if _, ok := to.(*schema.BinaryType); ok {
+			return fits(size(f))
+		}
+	case *schema.EnumType:
+		switch t := to.(type) {
+		case *schema.EnumType:
+			for _, v := range f.Values {
+				if !slices.Contains(t.Values, v) {
+					return schema.ConversionLossy
+				}
+			}
+			return schema.ConversionSafe
+		case *schema.StringType:
+			var n int
+			for _, v := range f.Values {
+				n = max(n, len(v))
+			}
+			return fits(int64(n))
+		}
+	case *schema.TimeType:
+		switch t := to.(type) {
+		case *schema.TimeType:
+			return timeConversion(f, t)
+		case *schema.StringType:
+			return fits(0)
+		}
+	case *schema.JSONType:
+		switch to.(type) {
+		case *schema.JSONType:
+			return schema.ConversionSafe
+		case *schema.StringType:
+			return fits(0)
+		}
+	default:
+		return schema.ConversionUnknown
+	}
+	return schema.ConversionIncompatible
+}
+
+// intDigits returns the number of decimal digits needed
+// to represent the values of an integer of n bytes.
+func intDigits(n int64, unsigned bool) int {
+	bits := n * 8
+	if !unsigned {
+		bits--
+	}
+	return int(float64(bits)*math.Log10(2)) + 1
+}
+
+// timeConversion classifies the conversion between two time types.
+func timeConversion(from, to *schema.TimeType) schema.TypeConversion {
+	kind := func(t string) string {
+		switch t = strings.ToLower(t); {
+		case t == "date", t == "year":
+			return t
+		case strings.HasPrefix(t, "timestamp"), strings.HasPrefix(t, "datetime"):
+			return "timestamp"
+		case strings.HasPrefix(t, "time"):
+			return "time"
+		default:
+			return ""
+		}
+	}
+	switch k1, k2 := kind(from.T), kind(to.T); {
+	case k1 == "" || k2 == "":
+		return schema.ConversionUnknown
+	case k1 == "date" && k2 == "timestamp":
+		return schema.ConversionSafe
+	case k1 == "timestamp" && (k2 == "date" || k2 == "time"):
+		return schema.ConversionLossy
+	case k1 != k2:
+		return schema.ConversionIncompatible
+	case from.Precision != nil && to.Precision != nil && *to.Precision < *from.Precision:
+		// Fractional seconds precision was reduced.
+		return schema.ConversionLossy
+	default:
+		return schema.ConversionSafe
+	}
+}
+
 // pkDiff returns the schema changes (if any) for migrating table
 // primary-key from current state to the desired state.
 func (d *Diff) pkDiff(from, to *schema.Table, opts *schema.DiffOptions) (changes []schema.Change) {
@@ -649,14 +1198,23 @@ func CommentDiff(from, to []schema.Attr) schema.Change {
 // CheckDiffMode is like CheckDiff, but compares also expressions
 // if the schema.DiffMode is equal to schema.DiffModeNormalized.
 func CheckDiffMode(from, to *schema.Table, mode schema.DiffMode, compare ...func(c1, c2 *schema.Check) bool) []schema.Change {
+	return CheckDiffModeFunc(from, to, mode, MayWrap, compare...)
+}
+
+// CheckDiffModeFunc is like CheckDiffMode, but allows drivers to provide
+// a function for normalizing the CHECK expressions before comparing them.
+func CheckDiffModeFunc(from, to *schema.Table, mode schema.DiffMode, norm func(string) string, compare ...func(c1, c2 *schema.Check) bool) []schema.Change {
+	equalExpr := func(x1, x2 string) bool {
+		return x1 == x2 || norm(x1) == norm(x2)
+	}
 	if !mode.Is(schema.DiffModeNormalized) {
-		return checksSimilarDiff(from, to, compare...)
+		return checksSimilarDiff(from, to, equalExpr, compare...)
 	}
 	return ChecksDiff(from, to, func(c1, c2 *schema.Check) bool {
 		if len(compare) == 1 && !compare[0](c1, c2) {
 			return false
 		}
-		return c1.Expr == c2.Expr || MayWrap(c1.Expr) == MayWrap(c2.Expr)
+		return equalExpr(c1.Expr, c2.Expr)
 	})
 }
 
@@ -698,15 +1256,55 @@ func ChecksDiff(from, to *schema.Table, compare func(c1, c2 *schema.Check) bool)
 	return changes
 }
 
+// ObjectsDiff computes the changes for migrating the objects of type T from one list
+// to the other, like the realm-level (database) objects that are defined by drivers.
+// Objects are matched using the match function (e.g., by their names), and matched
+// objects are modified if the changed function reports they were changed.
+func ObjectsDiff[T schema.Object](from, to []schema.Object, match, changed func(o1, o2 T) bool) []schema.Change {
+	var (
+		changes []schema.Change
+		fromO   = objects[T](from)
+		toO     = objects[T](to)
+		matchTo = func(o1 T) func(T) bool {
+			return func(o2 T) bool { return match(o1, o2) }
+		}
+	)
+	for _, o1 := range fromO {
+		switch idx := slices.IndexFunc(toO, matchTo(o1)); {
+		case idx == -1:
+			changes = append(changes, &schema.DropObject{O: o1})
+		case changed(o1, toO[idx]):
+			changes = append(changes, &schema.ModifyObject{From: o1, To: toO[idx]})
+		}
+	}
+	for _, o2 := range toO {
+		if !slices.ContainsFunc(fromO, matchTo(o2)) {
+			changes = append(changes, &schema.AddObject{O: o2})
+		}
+	}
+	return changes
+}
+
+// objects returns the objects of type T in the list.
+func objects[T schema.Object](objs []schema.Object) []T {
+	var l []T
+	for _, o := range objs {
+		if t, ok := o.(T); ok {
+			l = append(l, t)
+		}
+	}
+	return l
+}
+
 // checksSimilarDiff computes the change diff between the 2 tables.
 // Unlike ChecksDiff, it does not compare the constraint name, but
 // determines if there is any similar constraint by its expression.
 // This is an old implementation that is not used anymore by the CLI.
-func checksSimilarDiff(from, to *schema.Table, compare ...func(c1, c2 *schema.Check) bool) []schema.Change {
+func checksSimilarDiff(from, to *schema.Table, equalExpr func(x1, x2 string) bool, compare ...func(c1, c2 *schema.Check) bool) []schema.Change {
 	var changes []schema.Change
 	// Drop or modify checks.
 	for _, c1 := range checks(from.Attrs) {
-		switch c2, ok := similarCheck(to.Attrs, c1); {
+		switch c2, ok := similarCheck(to.Attrs, c1, equalExpr); {
 		case !ok:
 			changes = append(changes, &schema.DropCheck{
 				C: c1,
@@ -720,7 +1318,7 @@ func checksSimilarDiff(from, to *schema.Table, compare ...func(c1, c2 *schema.Ch
 	}
 	// Add checks.
 	for _, c1 := range checks(to.Attrs) {
-		if _, ok := similarCheck(from.Attrs, c1); !ok {
+		if _, ok := similarCheck(from.Attrs, c1, equalExpr); !ok {
 			changes = append(changes, &schema.AddCheck{
 				C: c1,
 			})
@@ -740,7 +1338,7 @@ func checks(attr []schema.Attr) (checks []*schema.Check) {
 }
 
 // similarCheck returns a CHECK by its constraints name or expression.
-func similarCheck(attrs []schema.Attr, c *schema.Check) (*schema.Check, bool) {
+func similarCheck(attrs []schema.Attr, c *schema.Check, equalExpr func(x1, x2 string) bool) (*schema.Check, bool) {
 	var byName, byExpr *schema.Check
 	for i := 0; i < len(attrs) && (byName == nil || byExpr == nil); i++ {
 		check, ok := attrs[i].(*schema.Check)
@@ -750,7 +1348,7 @@ func similarCheck(attrs []schema.Attr, c *schema.Check) (*schema.Check, bool) {
 		if check.Name != "" && check.Name == c.Name {
 			byName = check
 		}
-		if check.Expr == c.Expr || MayWrap(check.Expr) == MayWrap(c.Expr) {
+		if equalExpr(check.Expr, c.Expr) {
 			byExpr = check
 		}
 	}
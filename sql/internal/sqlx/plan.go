@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"slices"
 	"sort"
+	"strings"
+	"text/template"
 
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
@@ -296,6 +298,8 @@ func table(change schema.Change) (t string) {
 		t = change.T.Name
 	case *schema.ModifyTable:
 		t = change.T.Name
+	case *schema.DataChange:
+		t = change.T.Name
 	}
 	return
 }
@@ -394,7 +398,8 @@ func SortChanges(changes []schema.Change, opts *SortOptions) []schema.Change {
 	var drop, other []schema.Change
 	for _, c := range changes {
 		switch c.(type) {
-		case *schema.DropSchema, *schema.DropTable, *schema.DropObject:
+		case *schema.DropSchema, *schema.DropTable, *schema.DropObject,
+			*schema.DropView, *schema.DropFunc, *schema.DropProc, *schema.DropTrigger:
 			drop = append(drop, c)
 		default:
 			other = append(other, c)
@@ -498,6 +503,14 @@ func depOfDrop(o schema.Object, c schema.Change) bool {
 	switch c := c.(type) {
 	case *schema.DropTable:
 		deps = c.T.Deps
+	case *schema.DropView:
+		deps = c.V.Deps
+	case *schema.DropFunc:
+		deps = c.F.Deps
+	case *schema.DropProc:
+		deps = c.P.Deps
+	case *schema.DropTrigger:
+		deps = c.T.Deps
 	}
 	return slices.Contains(deps, o)
 }
@@ -516,6 +529,15 @@ func depOfAdd(refs []schema.Object, c schema.Change) bool {
 			t, ok := o.(*schema.Table)
 			return ok && SameTable(c.T, t)
 		})
+	case *schema.AddView:
+		return slices.ContainsFunc(refs, func(o schema.Object) bool {
+			v, ok := o.(*schema.View)
+			return ok && sameView(c.V, v)
+		})
+	case *schema.AddFunc:
+		o = c.F
+	case *schema.AddProc:
+		o = c.P
 	case *schema.AddObject:
 		o = c.O
 	default:
@@ -549,6 +571,14 @@ func SameTable(t1, t2 *schema.Table) bool {
 	return t1.Name == t2.Name && SameSchema(t1.Schema, t2.Schema)
 }
 
+// sameView reports if the given views are the same.
+func sameView(v1, v2 *schema.View) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+	return v1.Name == v2.Name && SameSchema(v1.Schema, v2.Schema)
+}
+
 // SameSchema reports if the given schemas are the same.
 // Objects can be different as they might reside in two
 // different states (current and desired).
@@ -558,3 +588,166 @@ func SameSchema(s1, s2 *schema.Schema) bool {
 	}
 	return s1.Name == s2.Name
 }
+
+// DataChanges returns the given ModifyTable without its data changes, and the data
+// changes it holds. The returned ModifyTable is the given one if it holds no data.
+func DataChanges(m *schema.ModifyTable) (*schema.ModifyTable, []*schema.DataChange) {
+	var (
+		data    []*schema.DataChange
+		changes = make([]schema.Change, 0, len(m.Changes))
+	)
+	for _, c := range m.Changes {
+		if d, ok := c.(*schema.DataChange); ok {
+			data = append(data, d)
+		} else {
+			changes = append(changes, c)
+		}
+	}
+	if len(data) == 0 {
+		return m, nil
+	}
+	return &schema.ModifyTable{T: m.T, Changes: changes}, data
+}
+
+// SplitBackfills splits ModifyTable changes that backfill a column that is set to NOT NULL
+// by the same change (i.e., a NOT NULL column without a default that is added, or a column
+// that is modified to NOT NULL) into 3 steps: planning the column as nullable, backfilling
+// it, and then setting it to NOT NULL. The changes are expected to be sorted.
+func SplitBackfills(changes []schema.Change) []schema.Change {
+	planned := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		m, ok := c.(*schema.ModifyTable)
+		if !ok {
+			planned = append(planned, c)
+			continue
+		}
+		filled := make(map[*schema.Column]bool)
+		for _, c := range m.Changes {
+			if d, ok := c.(*schema.DataChange); ok && d.C != nil {
+				filled[d.C] = true
+			}
+		}
+		var alter, data, setNull []schema.Change
+		for _, c := range m.Changes {
+			switch c := c.(type) {
+			case *schema.DataChange:
+				data = append(data, c)
+			case *schema.AddColumn:
+				if !filled[c.C] || c.C.Type.Null || c.C.Default != nil {
+					alter = append(alter, c)
+					break
+				}
+				nc := nullable(c.C)
+				alter = append(alter, &schema.AddColumn{C: nc})
+				setNull = append(setNull, &schema.ModifyColumn{From: nc, To: c.C, Change: schema.ChangeNull})
+			case *schema.ModifyColumn:
+				if !filled[c.To] || !c.Change.Is(schema.ChangeNull) || c.To.Type.Null {
+					alter = append(alter, c)
+					break
+				}
+				nc := nullable(c.To)
+				// Other changes of the column are planned before the backfill.
+				if k := c.Change &^ schema.ChangeNull; k != schema.NoChange {
+					alter = append(alter, &schema.ModifyColumn{From: c.From, To: nc, Change: k, Conversion: c.Conversion, Extra: c.Extra})
+				}
+				setNull = append(setNull, &schema.ModifyColumn{From: nc, To: c.To, Change: schema.ChangeNull})
+			default:
+				alter = append(alter, c)
+			}
+		}
+		if len(setNull) == 0 {
+			planned = append(planned, m)
+			continue
+		}
+		for _, cs := range [][]schema.Change{alter, data, setNull} {
+			if len(cs) > 0 {
+				planned = append(planned, &schema.ModifyTable{T: m.T, Changes: cs})
+			}
+		}
+	}
+	return planned
+}
+
+// nullable returns a nullable copy of the column.
+func nullable(c *schema.Column) *schema.Column {
+	nc, ct := *c, *c.Type
+	ct.Null = true
+	nc.Type = &ct
+	return &nc
+}
+
+// PlanDataChange returns the migration change of the given data change.
+// The builder is expected to be empty, and is used to write the statement.
+func PlanDataChange(b *Builder, d *schema.DataChange) (*migrate.Change, error) {
+	if d.T == nil {
+		return nil, errors.New("data change: missing table")
+	}
+	c := &migrate.Change{Source: d, Comment: d.Comment}
+	switch {
+	case d.Stmt != "":
+		t, err := template.New("data").Parse(d.Stmt)
+		if err != nil {
+			return nil, fmt.Errorf("data change of table %q: parse statement: %w", d.T.Name, err)
+		}
+		idents := struct{ Table, Column string }{
+			Table: (&Builder{QuoteOpening: b.QuoteOpening, QuoteClosing: b.QuoteClosing, Schema: b.Schema}).Table(d.T).String(),
+		}
+		if d.C != nil {
+			idents.Column = (&Builder{QuoteOpening: b.QuoteOpening, QuoteClosing: b.QuoteClosing}).Ident(d.C.Name).String()
+		}
+		var buf strings.Builder
+		if err := t.Execute(&buf, idents); err != nil {
+			return nil, fmt.Errorf("data change of table %q: execute statement: %w", d.T.Name, err)
+		}
+		c.Cmd = strings.TrimSpace(buf.String())
+		if c.Comment == "" {
+			c.Comment = fmt.Sprintf("migrate data of %q table", d.T.Name)
+		}
+	case d.C != nil && d.Expr != nil:
+		var x string
+		switch e := d.Expr.(type) {
+		case *schema.RawExpr:
+			x = e.X
+		case *schema.Literal:
+			x = e.V
+		default:
+			return nil, fmt.Errorf("data change of table %q: unexpected backfill expression %T", d.T.Name, d.Expr)
+		}
+		b.P("UPDATE").Table(d.T).P("SET").Ident(d.C.Name).P("=", x, "WHERE")
+		if d.Where != "" {
+			b.P(d.Where)
+		} else {
+			b.Ident(d.C.Name).P("IS NULL")
+		}
+		c.Cmd = b.String()
+		if c.Comment == "" {
+			c.Comment = fmt.Sprintf("backfill %q column of %q table", d.C.Name, d.T.Name)
+		}
+	default:
+		return nil, fmt.Errorf("data change of table %q: missing statement or backfill expression", d.T.Name)
+	}
+	return c, nil
+}
+
+// PosError attaches the position of the object that the change applies
+// to (e.g., a table defined in an HCL file) to the error, if it is known.
+func PosError(c schema.Change, err error) error {
+	var o any
+	switch c := c.(type) {
+	case *schema.AddTable:
+		o = c.T
+	case *schema.DropTable:
+		o = c.T
+	case *schema.ModifyTable:
+		o = c.T
+	case *schema.AddObject:
+		o = c.O
+	case *schema.ModifyObject:
+		o = c.To
+	}
+	var pe *schema.PosError
+	if ps, ok := o.(schema.PosSetter); ok && ps.Pos() != nil && !errors.As(err, &pe) {
+		return &schema.PosError{Pos: ps.Pos(), Err: err}
+	}
+	return err
+}
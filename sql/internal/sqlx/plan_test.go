@@ -181,3 +181,58 @@ func TestSortDropTables_WithFK(t *testing.T) {
 	changes = []schema.Change{&schema.DropTable{T: t1}, &schema.DropTable{T: t2}}
 	require.Equal(t, []schema.Change{changes[1], changes[0]}, SortChanges(changes, nil))
 }
+
+func TestSortChanges_Views(t *testing.T) {
+	s := schema.New("public")
+	t1 := schema.NewTable("t1").SetSchema(s)
+	v1 := schema.NewView("v1", "SELECT * FROM t1").AddDeps(t1)
+	s.AddViews(v1)
+	tr := schema.NewTrigger("tr", "SELECT 1")
+	v1.AddTriggers(tr)
+	changes := []schema.Change{&schema.AddTrigger{T: tr}, &schema.AddView{V: v1}, &schema.AddTable{T: t1}, &schema.AddSchema{S: s}}
+	require.Equal(t, []schema.Change{changes[3], changes[2], changes[1], changes[0]}, SortChanges(changes, nil))
+
+	// Views are dropped before the tables they depend on.
+	changes = []schema.Change{&schema.DropTable{T: t1}, &schema.DropView{V: v1}}
+	require.Equal(t, []schema.Change{changes[1], changes[0]}, SortChanges(changes, nil))
+
+	fn := schema.NewFunc("f", "SELECT 1")
+	s.AddFuncs(fn)
+	changes = []schema.Change{&schema.DropSchema{S: s}, &schema.DropFunc{F: fn}}
+	require.Equal(t, []schema.Change{changes[1], changes[0]}, SortChanges(changes, nil))
+}
+
+func TestSplitBackfills(t *testing.T) {
+	var (
+		from  = schema.NewNullIntColumn("c", "int")
+		to    = schema.NewIntColumn("c", "bigint")
+		users = schema.NewTable("users").AddColumns(to)
+		data  = &schema.DataChange{T: users, C: to, Expr: &schema.Literal{V: "0"}}
+		other = &schema.AddTable{T: schema.NewTable("pets")}
+	)
+	changes := SplitBackfills([]schema.Change{
+		other,
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: from, To: to, Change: schema.ChangeType | schema.ChangeNull},
+				data,
+			},
+		},
+	})
+	require.Len(t, changes, 4)
+	require.Equal(t, other, changes[0])
+	alter, fill, setNull := changes[1].(*schema.ModifyTable), changes[2].(*schema.ModifyTable), changes[3].(*schema.ModifyTable)
+	require.Len(t, alter.Changes, 1)
+	m := alter.Changes[0].(*schema.ModifyColumn)
+	require.Equal(t, schema.ChangeType, m.Change)
+	require.True(t, m.To.Type.Null)
+	require.False(t, to.Type.Null, "original column should not be modified")
+	require.Equal(t, []schema.Change{data}, fill.Changes)
+	require.Equal(t, []schema.Change{&schema.ModifyColumn{From: m.To, To: to, Change: schema.ChangeNull}}, setNull.Changes)
+
+	// Columns with default values are not split.
+	c := schema.NewIntColumn("c", "int").SetDefault(&schema.Literal{V: "0"})
+	modify := &schema.ModifyTable{T: users, Changes: []schema.Change{&schema.AddColumn{C: c}, &schema.DataChange{T: users, C: c, Expr: &schema.Literal{V: "1"}}}}
+	require.Equal(t, []schema.Change{modify}, SplitBackfills([]schema.Change{modify}))
+}
@@ -6,9 +6,11 @@ package sqlx
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -108,6 +110,35 @@ type FKAttrScanner struct {
 // TypedSchemaFKs is a version of SchemaFKs that allows to specify the type of
 // used to scan update and delete actions from the database.
 func TypedSchemaFKs[T ScanStringer](s *schema.Schema, rows *sql.Rows, attr ...*FKAttrScanner) error {
+	return typedFKs[T](rows, func(_, table string) (*schema.Schema, *schema.Table, error) {
+		t, ok := s.Table(table)
+		if !ok {
+			return nil, nil, fmt.Errorf("table %q was not found in schema", table)
+		}
+		return s, t, nil
+	}, attr...)
+}
+
+// RealmFKs scans the rows and adds the foreign-key to the realm tables. Unlike
+// SchemaFKs, rows of tables that do not exist in the realm are skipped, as the
+// queries of multiple schemas are usually not filtered by the table names.
+func RealmFKs(r *schema.Realm, rows *sql.Rows) error {
+	return typedFKs[*nullString](rows, func(schemaName, table string) (*schema.Schema, *schema.Table, error) {
+		s, ok := r.Schema(schemaName)
+		if !ok {
+			return nil, nil, nil
+		}
+		t, ok := s.Table(table)
+		if !ok {
+			return nil, nil, nil
+		}
+		return s, t, nil
+	})
+}
+
+// typedFKs scans the foreign-keys rows and adds them to the tables returned by the
+// lookup function. Rows are skipped in case the lookup function returns no table.
+func typedFKs[T ScanStringer](rows *sql.Rows, lookup func(string, string) (*schema.Schema, *schema.Table, error), attr ...*FKAttrScanner) error {
 	for rows.Next() {
 		var (
 			updateAction, deleteAction                                   = V(new(T)), V(new(T))
@@ -127,9 +158,12 @@ func TypedSchemaFKs[T ScanStringer](s *schema.Schema, rows *sql.Rows, attr ...*F
 		if err := rows.Scan(columns...); err != nil {
 			return err
 		}
-		t, ok := s.Table(table)
-		if !ok {
-			return fmt.Errorf("table %q was not found in schema", table)
+		s, t, err := lookup(tSchema, table)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			continue
 		}
 		fk, ok := t.ForeignKey(name)
 		if !ok {
@@ -671,6 +705,22 @@ func V[T any](p *T) (v T) {
 	return
 }
 
+// ErrorField returns the value of the named field of the first error in the
+// chain whose underlying struct defines it. It allows extracting error codes
+// from the database drivers without depending on their packages.
+func ErrorField(err error, name string) (reflect.Value, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.Indirect(reflect.ValueOf(err))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if f := v.FieldByName(name); f.IsValid() {
+			return f, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
 // IsUint reports whether the string represents an unsigned integer.
 func IsUint(s string) bool {
 	for _, r := range s {
@@ -681,16 +731,211 @@ func IsUint(s string) bool {
 	return true
 }
 
-func (*Diff) askForColumns(_ *schema.Table, changes []schema.Change, _ *schema.DiffOptions) ([]schema.Change, error) {
-	return changes, nil // unimplemented.
+// askForColumns detects probable column renames among the dropped and added columns
+// of the table, and replaces the confirmed ones with RenameColumn changes. Only columns
+// with identical definitions (except their names) are considered as renamed.
+func (d *Diff) askForColumns(t *schema.Table, changes []schema.Change, opts *schema.DiffOptions) ([]schema.Change, error) {
+	if opts == nil || opts.RenameResolver == nil {
+		return changes, nil
+	}
+	var cands []renameCand
+	for i, c := range changes {
+		drop, ok := c.(*schema.DropColumn)
+		if !ok {
+			continue
+		}
+		for j, c := range changes {
+			add, ok := c.(*schema.AddColumn)
+			if !ok {
+				continue
+			}
+			change, err := d.ColumnChange(t, drop.C, add.C, opts)
+			if err != nil {
+				return nil, err
+			}
+			if change == NoChange {
+				cands = append(cands, renameCand{
+					drop: i,
+					add:  j,
+					c:    &schema.RenameCandidate{From: drop.C, To: add.C, T: t, Score: 0.5 + 0.5*nameSimilarity(drop.C.Name, add.C.Name)},
+				})
+			}
+		}
+	}
+	confirmed, err := resolveRenames(cands, opts)
+	if err != nil || len(confirmed) == 0 {
+		return changes, err
+	}
+	added := make(map[int]bool)
+	for _, r := range confirmed {
+		changes[r.drop] = &schema.RenameColumn{From: r.c.From.(*schema.Column), To: r.c.To.(*schema.Column)}
+		added[r.add] = true
+	}
+	return removeAt(changes, added), nil
 }
 
 func (*Diff) askForIndexes(_ string, changes []schema.Change, _ *schema.DiffOptions) ([]schema.Change, error) {
 	return changes, nil // unimplemented.
 }
 
-func (*Diff) fixRenames(changes schema.Changes) schema.Changes {
-	return changes // unimplemented.
+// fixRenames detects probable table renames among the dropped and added tables of
+// the schema, and replaces the confirmed ones with RenameTable changes, followed by
+// the changes needed for migrating the renamed table to its desired state. Tables
+// are considered as renamed if at least half of their columns are identical.
+func (d *Diff) fixRenames(changes schema.Changes, opts *schema.DiffOptions) (schema.Changes, error) {
+	if opts == nil || opts.RenameResolver == nil {
+		return changes, nil
+	}
+	var cands []renameCand
+	for i, c := range changes {
+		drop, ok := c.(*schema.DropTable)
+		if !ok {
+			continue
+		}
+		for j, c := range changes {
+			add, ok := c.(*schema.AddTable)
+			if !ok {
+				continue
+			}
+			same, err := d.sameColumns(drop.T, add.T, opts)
+			if err != nil {
+				return nil, err
+			}
+			if same >= 0.5 {
+				cands = append(cands, renameCand{
+					drop: i,
+					add:  j,
+					c:    &schema.RenameCandidate{From: drop.T, To: add.T, Score: 0.7*same + 0.3*nameSimilarity(drop.T.Name, add.T.Name)},
+				})
+			}
+		}
+	}
+	confirmed, err := resolveRenames(cands, opts)
+	if err != nil || len(confirmed) == 0 {
+		return changes, err
+	}
+	var (
+		added  = make(map[int]bool)
+		modify = make(map[int]schema.Change)
+	)
+	for _, r := range confirmed {
+		from, to := r.c.From.(*schema.Table), r.c.To.(*schema.Table)
+		changes[r.drop] = &schema.RenameTable{From: from, To: to}
+		added[r.add] = true
+		change, err := d.tableDiff(from, to, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(change) > 0 {
+			modify[r.drop] = &schema.ModifyTable{T: to, Changes: change}
+		}
+	}
+	fixed := make(schema.Changes, 0, len(changes))
+	for i, c := range changes {
+		if added[i] {
+			continue
+		}
+		fixed = append(fixed, c)
+		if m, ok := modify[i]; ok {
+			fixed = append(fixed, m)
+		}
+	}
+	return fixed, nil
+}
+
+// sameColumns returns the ratio of the identical columns between the two tables.
+func (d *Diff) sameColumns(t1, t2 *schema.Table, opts *schema.DiffOptions) (float64, error) {
+	if len(t1.Columns) == 0 || len(t2.Columns) == 0 {
+		return 0, nil
+	}
+	var same int
+	for _, c1 := range t1.Columns {
+		c2, ok := t2.Column(c1.Name)
+		if !ok {
+			continue
+		}
+		change, err := d.ColumnChange(t1, c1, c2, opts)
+		if err != nil {
+			return 0, err
+		}
+		if change == NoChange {
+			same++
+		}
+	}
+	return float64(same) / float64(max(len(t1.Columns), len(t2.Columns))), nil
+}
+
+// renameCand is a rename candidate, along with the
+// positions of its drop and add changes in the changeset.
+type renameCand struct {
+	drop, add int
+	c         *schema.RenameCandidate
+}
+
+// resolveRenames passes the candidates to the resolver, ordered by their scores,
+// and returns the confirmed ones. Each element can be renamed at most once.
+func resolveRenames(cands []renameCand, opts *schema.DiffOptions) ([]renameCand, error) {
+	slices.SortStableFunc(cands, func(a, b renameCand) int {
+		return cmp.Compare(b.c.Score, a.c.Score)
+	})
+	var (
+		confirmed []renameCand
+		used      = make(map[int]bool)
+	)
+	for _, c := range cands {
+		if used[c.drop] || used[c.add] {
+			continue
+		}
+		ok, err := opts.RenameResolver(c.c)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			used[c.drop], used[c.add] = true, true
+			confirmed = append(confirmed, c)
+		}
+	}
+	return confirmed, nil
+}
+
+// removeAt returns the changes without the ones at the given positions.
+func removeAt(changes []schema.Change, at map[int]bool) []schema.Change {
+	kept := make([]schema.Change, 0, len(changes)-len(at))
+	for i, c := range changes {
+		if !at[i] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// nameSimilarity returns the similarity between the two names, between 0 and 1,
+// based on their case-insensitive Levenshtein distance.
+func nameSimilarity(a, b string) float64 {
+	r1, r2 := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	if n := max(len(r1), len(r2)); n > 0 {
+		return 1 - float64(levenshtein(r1, r2))/float64(n)
+	}
+	return 1
+}
+
+func levenshtein(r1, r2 []rune) int {
+	prev, cur := make([]int, len(r2)+1), make([]int, len(r2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(r1); i++ {
+		cur[0] = i
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(r2)]
 }
 
 // dependsOn reports if the given change depends on the other change.
@@ -711,6 +956,12 @@ func dependsOn(c1, c2 schema.Change, _ SortOptions) bool {
 				fk, ok := c.(*schema.DropForeignKey)
 				return ok && SameSchema(c1.S, fk.F.RefTable.Schema)
 			})
+		case *schema.DropView:
+			return SameSchema(c1.S, c2.V.Schema)
+		case *schema.DropFunc:
+			return SameSchema(c1.S, c2.F.Schema)
+		case *schema.DropProc:
+			return SameSchema(c1.S, c2.P.Schema)
 		}
 	case *schema.AddTable:
 		switch c2 := c2.(type) {
@@ -819,6 +1070,49 @@ func dependsOn(c1, c2 schema.Change, _ SortOptions) bool {
 			}
 		}
 		return depOfAdd(c1.T.Deps, c2)
+	case *schema.AddView:
+		if c2, ok := c2.(*schema.AddSchema); ok {
+			return SameSchema(c1.V.Schema, c2.S)
+		}
+		return depOfAdd(c1.V.Deps, c2)
+	case *schema.ModifyView:
+		return depOfAdd(c1.To.Deps, c2)
+	case *schema.AddFunc:
+		if c2, ok := c2.(*schema.AddSchema); ok {
+			return SameSchema(c1.F.Schema, c2.S)
+		}
+		return depOfAdd(c1.F.Deps, c2)
+	case *schema.ModifyFunc:
+		return depOfAdd(c1.To.Deps, c2)
+	case *schema.AddProc:
+		if c2, ok := c2.(*schema.AddSchema); ok {
+			return SameSchema(c1.P.Schema, c2.S)
+		}
+		return depOfAdd(c1.P.Deps, c2)
+	case *schema.ModifyProc:
+		return depOfAdd(c1.To.Deps, c2)
+	case *schema.AddTrigger:
+		// Triggers are created after the table (or the view) they are defined on.
+		switch c2 := c2.(type) {
+		case *schema.AddTable:
+			if c1.T.Table != nil && SameTable(c1.T.Table, c2.T) {
+				return true
+			}
+		case *schema.AddView:
+			if c1.T.View != nil && sameView(c1.T.View, c2.V) {
+				return true
+			}
+		}
+		return depOfAdd(c1.T.Deps, c2)
+	case *schema.ModifyTrigger:
+		return depOfAdd(c1.To.Deps, c2)
+	case *schema.DropView:
+		// Views are dropped after the objects that depend on them.
+		return depOfDrop(c1.V, c2)
+	case *schema.DropFunc:
+		return depOfDrop(c1.F, c2)
+	case *schema.DropProc:
+		return depOfDrop(c1.P, c2)
 	case *schema.DropObject:
 		t, ok := c1.O.(schema.Type)
 		if !ok {
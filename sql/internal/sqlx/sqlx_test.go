@@ -299,3 +299,30 @@ func TestBodyDefChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestNameSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, nameSimilarity("", ""))
+	require.Equal(t, 1.0, nameSimilarity("Users", "users"))
+	require.Equal(t, 0.0, nameSimilarity("abc", "xyz"))
+	require.InDelta(t, 0.8, nameSimilarity("user", "users"), 1e-9)
+	require.Equal(t, 3, levenshtein([]rune("kitten"), []rune("sitting")))
+}
+
+func TestObjectsDiff(t *testing.T) {
+	var (
+		e1     = &schema.EnumType{T: "status", Values: []string{"a"}}
+		e2     = &schema.EnumType{T: "status", Values: []string{"a", "b"}}
+		e3     = &schema.EnumType{T: "state", Values: []string{"a"}}
+		e4     = &schema.EnumType{T: "kind", Values: []string{"a"}}
+		other  = &schema.Table{Name: "t"}
+		byName = func(o1, o2 *schema.EnumType) bool { return o1.T == o2.T }
+		diff   = func(o1, o2 *schema.EnumType) bool { return !ValuesEqual(o1.Values, o2.Values) }
+	)
+	changes := ObjectsDiff([]schema.Object{e1, other, e3}, []schema.Object{e4, e2, other}, byName, diff)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: e1, To: e2},
+		&schema.DropObject{O: e3},
+		&schema.AddObject{O: e4},
+	}, changes)
+	require.Empty(t, ObjectsDiff([]schema.Object{e1}, []schema.Object{e1}, byName, diff))
+}
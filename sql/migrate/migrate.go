@@ -11,10 +11,12 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlerr"
 )
 
 type (
@@ -124,6 +126,9 @@ type (
 		// This is useful to indicate to the driver whether the context is a live database, an empty one, or the
 		// versioned migration workflow.
 		Mode PlanMode
+		// Policy, if set, is consulted before planning the changes, and may
+		// block destructive changes (e.g., dropping tables or columns).
+		Policy *schema.ChangePolicy
 	}
 
 	// PlanMode defines the plan mode to use.
@@ -315,6 +320,31 @@ func (r RevisionType) MarshalText() ([]byte, error) {
 	return []byte(r.String()), nil
 }
 
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (r *RevisionType) UnmarshalText(text []byte) error {
+	switch s := string(text); s {
+	case "baseline":
+		*r = RevisionTypeBaseline
+	case "applied":
+		*r = RevisionTypeExecute
+	case "manually set":
+		*r = RevisionTypeResolved
+	case "applied + manually set":
+		*r = RevisionTypeExecute | RevisionTypeResolved
+	default:
+		bits, ok := strings.CutPrefix(s, "unknown (")
+		if !ok || !strings.HasSuffix(bits, ")") {
+			return fmt.Errorf("sql/migrate: invalid revision type %q", s)
+		}
+		v, err := strconv.ParseUint(strings.TrimSuffix(bits, ")"), 2, 0)
+		if err != nil {
+			return fmt.Errorf("sql/migrate: invalid revision type %q", s)
+		}
+		*r = RevisionType(v)
+	}
+	return nil
+}
+
 // NewPlanner creates a new Planner.
 func NewPlanner(drv Driver, dir Dir, opts ...PlannerOption) *Planner {
 	p := &Planner{drv: drv, dir: dir, sum: true}
@@ -359,6 +389,16 @@ func PlanWithMode(m PlanMode) PlannerOption {
 	}
 }
 
+// PlanWithPolicy allows setting a change policy to be consulted
+// by the driver before planning the changes.
+func PlanWithPolicy(policy *schema.ChangePolicy) PlannerOption {
+	return func(p *Planner) {
+		p.planOpts = append(p.planOpts, func(o *PlanOptions) {
+			o.Policy = policy
+		})
+	}
+}
+
 // PlanWithDiffOptions allows setting custom diff options.
 func PlanWithDiffOptions(opts ...schema.DiffOption) PlannerOption {
 	return func(p *Planner) {
@@ -873,7 +913,7 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 			r.done()
 			r.ErrorStmt = stmt.Text
 			r.Error = err.Error()
-			return &StmtExecError{File: m, Stmt: stmt, Version: r.Version, Err: err}
+			return &StmtExecError{File: m, Stmt: stmt, Version: r.Version, Err: sqlerr.Classify(err)}
 		}
 		r.PartialHashes = append(r.PartialHashes, "h1:"+sums[r.Applied])
 		r.Applied++
@@ -1114,6 +1154,25 @@ type (
 		CheckClean(context.Context, *TableIdent) error
 	}
 
+	// CapabilityReporter wraps the single Capabilities method. It can be optionally implemented
+	// by drivers to allow tools built on top of Atlas to adapt their behavior to the connected
+	// database, without switching on the driver name or its version.
+	CapabilityReporter interface {
+		// Capabilities returns the capabilities of the connected database.
+		Capabilities() *Capabilities
+	}
+
+	// Capabilities describes the features supported by the connected database.
+	Capabilities struct {
+		TransactionalDDL bool // DDL statements can be executed (and rolled back) in a transaction.
+		Check            bool // CHECK constraints.
+		IndexExpr        bool // Index expressions (functional key parts).
+		IndexPredicate   bool // Partial indexes.
+		ForeignKey       bool // Foreign-key constraints.
+		RenameColumn     bool // Renaming columns without rebuilding their tables.
+		MaxIdentLen      int  // Maximum length of identifiers in bytes, or 0 if unknown.
+	}
+
 	// NotCleanError is returned when the connected dev-db is not in a clean state (aka it has schemas and tables).
 	// This check is done to ensure no data is lost by overriding it when working on the dev-db.
 	NotCleanError struct {
@@ -1126,7 +1185,7 @@ type (
 		File    File   // Migration file that failed.
 		Stmt    *Stmt  // Statement that failed.
 		Version string // Version of the file.
-		Err     error  // Underlying error during execution.
+		Err     error  // Underlying error during execution, classified by sqlerr if recognized.
 	}
 )
 
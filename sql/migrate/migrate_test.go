@@ -37,7 +37,13 @@ func TestRevisionType_MarshalText(t *testing.T) {
 		ac, err := tt.r.MarshalText()
 		require.NoError(t, err)
 		require.Equal(t, tt.ex, string(ac))
+		var r migrate.RevisionType
+		require.NoError(t, r.UnmarshalText(ac))
+		require.Equal(t, tt.r, r)
 	}
+	var r migrate.RevisionType
+	require.EqualError(t, r.UnmarshalText([]byte("unknown")), `sql/migrate: invalid revision type "unknown"`)
+	require.EqualError(t, r.UnmarshalText([]byte("unknown (12)")), `sql/migrate: invalid revision type "unknown (12)"`)
 }
 
 func TestPlanner_WritePlan(t *testing.T) {
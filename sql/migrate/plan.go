@@ -0,0 +1,195 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// planJSON is the JSON encoding of a Plan.
+	planJSON struct {
+		Version       string           `json:"Version,omitempty"`
+		Name          string           `json:"Name,omitempty"`
+		Reversible    bool             `json:"Reversible,omitempty"`
+		Transactional bool             `json:"Transactional,omitempty"`
+		Delimiter     string           `json:"Delimiter,omitempty"`
+		Directives    []string         `json:"Directives,omitempty"`
+		Changes       []changeJSON     `json:"Changes"`
+		Sources       *json.RawMessage `json:"Sources,omitempty"`
+	}
+
+	// changeJSON is the JSON encoding of a Change. The source
+	// is stored as an index into the sources of the plan.
+	changeJSON struct {
+		Cmd     string `json:"Cmd"`
+		Args    []any  `json:"Args,omitempty"`
+		Comment string `json:"Comment,omitempty"`
+		Reverse any    `json:"Reverse,omitempty"`
+		Source  *int   `json:"Source,omitempty"`
+	}
+)
+
+// MarshalPlan returns the JSON encoding of the plan, for storing a plan that was created
+// in one environment (e.g., CI) for human review, and applying it later in another one
+// without re-planning. The statements of the plan are encoded as-is, and the sources of
+// its changes are encoded using schema.MarshalChanges. Arguments of statements must be
+// nil, booleans, strings or numbers.
+func MarshalPlan(p *Plan) ([]byte, error) {
+	var (
+		sources []schema.Change
+		index   = make(map[schema.Change]int)
+		pj      = planJSON{
+			Version:       p.Version,
+			Name:          p.Name,
+			Reversible:    p.Reversible,
+			Transactional: p.Transactional,
+			Delimiter:     p.Delimiter,
+			Directives:    p.Directives,
+			Changes:       make([]changeJSON, len(p.Changes)),
+		}
+	)
+	for i, c := range p.Changes {
+		for _, a := range c.Args {
+			if !planArg(a) {
+				return nil, fmt.Errorf("migrate: unsupported argument type %T in statement %d of plan", a, i+1)
+			}
+		}
+		pj.Changes[i] = changeJSON{Cmd: c.Cmd, Args: c.Args, Comment: c.Comment, Reverse: c.Reverse}
+		if c.Source == nil {
+			continue
+		}
+		// Multiple statements can be created from the same source.
+		hashable := reflect.TypeOf(c.Source).Comparable()
+		idx, ok := index[c.Source]
+		if !hashable || !ok {
+			idx = len(sources)
+			sources = append(sources, c.Source)
+		}
+		if hashable {
+			index[c.Source] = idx
+		}
+		pj.Changes[i].Source = &idx
+	}
+	if len(sources) > 0 {
+		b, err := schema.MarshalChanges(sources)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(b)
+		pj.Sources = &raw
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(pj); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// UnmarshalPlan decodes the JSON encoding of a plan created by MarshalPlan. Integer
+// arguments are decoded as int64, and other numeric arguments are decoded as float64.
+func UnmarshalPlan(data []byte) (*Plan, error) {
+	var pj planJSON
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&pj); err != nil {
+		return nil, fmt.Errorf("migrate: decoding plan: %w", err)
+	}
+	var sources []schema.Change
+	if pj.Sources != nil {
+		var err error
+		if sources, err = schema.UnmarshalChanges(*pj.Sources); err != nil {
+			return nil, err
+		}
+	}
+	p := &Plan{
+		Version:       pj.Version,
+		Name:          pj.Name,
+		Reversible:    pj.Reversible,
+		Transactional: pj.Transactional,
+		Delimiter:     pj.Delimiter,
+		Directives:    pj.Directives,
+		Changes:       make([]*Change, len(pj.Changes)),
+	}
+	for i, c := range pj.Changes {
+		args, err := planArgs(c.Args)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: decoding arguments of statement %d of plan: %w", i+1, err)
+		}
+		reverse, err := planReverse(c.Reverse)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: decoding reverse of statement %d of plan: %w", i+1, err)
+		}
+		p.Changes[i] = &Change{Cmd: c.Cmd, Args: args, Comment: c.Comment, Reverse: reverse}
+		if c.Source != nil {
+			if *c.Source < 0 || *c.Source >= len(sources) {
+				return nil, fmt.Errorf("migrate: unknown source %d of statement %d of plan", *c.Source, i+1)
+			}
+			p.Changes[i].Source = sources[*c.Source]
+		}
+	}
+	return p, nil
+}
+
+// planArg reports if the statement argument can be encoded in a plan.
+func planArg(a any) bool {
+	if a == nil {
+		return true
+	}
+	switch reflect.TypeOf(a).Kind() {
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// planArgs converts the decoded JSON numbers to Go numbers.
+func planArgs(args []any) ([]any, error) {
+	for i, a := range args {
+		n, ok := a.(json.Number)
+		if !ok {
+			continue
+		}
+		if v, err := n.Int64(); err == nil {
+			args[i] = v
+			continue
+		}
+		v, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	return args, nil
+}
+
+// planReverse converts the decoded reverse statements to string or []string.
+func planReverse(r any) (any, error) {
+	switch r := r.(type) {
+	case nil, string:
+		return r, nil
+	case []any:
+		stmts := make([]string, len(r))
+		for i := range r {
+			s, ok := r[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected reverse statement type %T", r[i])
+			}
+			stmts[i] = s
+		}
+		return stmts, nil
+	default:
+		return nil, fmt.Errorf("unexpected reverse type %T", r)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type (
+	// A Change represents a database change that can be planned as MongoDB commands.
+	Change interface {
+		change()
+	}
+
+	// AddCollection describes a collection creation change.
+	AddCollection struct {
+		C *Collection
+	}
+
+	// DropCollection describes a collection removal change.
+	DropCollection struct {
+		C *Collection
+	}
+
+	// ModifyValidation describes a change of the validator, the
+	// validation level or the validation action of a collection.
+	ModifyValidation struct {
+		From, To *Collection
+	}
+
+	// ModifyCapped describes a change of the maximum size or
+	// the maximum number of documents of a capped collection.
+	ModifyCapped struct {
+		From, To *Collection
+	}
+
+	// AddIndex describes an index creation change.
+	AddIndex struct {
+		C *Collection
+		I *Index
+	}
+
+	// DropIndex describes an index removal change.
+	DropIndex struct {
+		C *Collection
+		I *Index
+	}
+
+	// ModifyIndex describes a change of the expiration (TTL) of an index.
+	// Other index changes are described as a DropIndex followed by an
+	// AddIndex, as MongoDB does not support modifying them.
+	ModifyIndex struct {
+		C        *Collection
+		From, To *Index
+	}
+)
+
+func (*AddCollection) change()    {}
+func (*DropCollection) change()   {}
+func (*ModifyValidation) change() {}
+func (*ModifyCapped) change()     {}
+func (*AddIndex) change()         {}
+func (*DropIndex) change()        {}
+func (*ModifyIndex) change()      {}
+
+// Diff returns the changes for migrating the database from one state to the other.
+// Collections that do not exist in the desired state are dropped.
+func Diff(from, to *Database) ([]Change, error) {
+	var changes []Change
+	for _, c1 := range from.Collections {
+		if _, ok := to.Collection(c1.Name); !ok {
+			changes = append(changes, &DropCollection{C: c1})
+		}
+	}
+	for _, c2 := range to.Collections {
+		c1, ok := from.Collection(c2.Name)
+		if !ok {
+			changes = append(changes, &AddCollection{C: c2})
+			continue
+		}
+		cs, err := CollectionDiff(c1, c2)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, cs...)
+	}
+	return changes, nil
+}
+
+// CollectionDiff returns the changes for migrating a collection from one state to the other.
+func CollectionDiff(from, to *Collection) ([]Change, error) {
+	var changes []Change
+	if from.Capped != to.Capped {
+		return nil, fmt.Errorf("mongodb: collection %q: changing a collection to (or from) capped is not supported", to.Name)
+	}
+	if from.Capped && (from.Size != to.Size || from.Max != to.Max) {
+		changes = append(changes, &ModifyCapped{From: from, To: to})
+	}
+	changed, err := validationChanged(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		changes = append(changes, &ModifyValidation{From: from, To: to})
+	}
+	for _, idx1 := range from.Indexes {
+		if _, ok := to.Index(idx1.Name); !ok {
+			changes = append(changes, &DropIndex{C: from, I: idx1})
+		}
+	}
+	for _, idx2 := range to.Indexes {
+		idx1, ok := from.Index(idx2.Name)
+		if !ok {
+			changes = append(changes, &AddIndex{C: to, I: idx2})
+			continue
+		}
+		changed, err := indexChanged(idx1, idx2)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case changed:
+			changes = append(changes, &DropIndex{C: from, I: idx1}, &AddIndex{C: to, I: idx2})
+		case ttl(idx1) != ttl(idx2):
+			changes = append(changes, &ModifyIndex{C: to, From: idx1, To: idx2})
+		}
+	}
+	return changes, nil
+}
+
+// validationChanged reports if the validation of the collection was changed.
+func validationChanged(from, to *Collection) (bool, error) {
+	if defaultString(from.ValidationLevel, "strict") != defaultString(to.ValidationLevel, "strict") ||
+		defaultString(from.ValidationAction, "error") != defaultString(to.ValidationAction, "error") {
+		return true, nil
+	}
+	return docChanged(from.Validator, to.Validator)
+}
+
+// indexChanged reports if the index definition was changed. The expiration
+// of the index is not compared, as it can be modified in place.
+func indexChanged(from, to *Index) (bool, error) {
+	if from.Unique != to.Unique || from.Sparse != to.Sparse || (from.ExpireAfterSeconds == nil) != (to.ExpireAfterSeconds == nil) {
+		return true, nil
+	}
+	if len(from.Keys) != len(to.Keys) {
+		return true, nil
+	}
+	for i := range from.Keys {
+		if *from.Keys[i] != *to.Keys[i] {
+			return true, nil
+		}
+	}
+	return docChanged(from.PartialFilter, to.PartialFilter)
+}
+
+// docChanged reports if the two documents are different. Both documents are
+// normalized using JSON encoding before comparing, as inspected documents and
+// documents defined in HCL may use different types for the same values.
+func docChanged(from, to map[string]any) (bool, error) {
+	if len(from) == 0 || len(to) == 0 {
+		return len(from) != len(to), nil
+	}
+	var n1, n2 any
+	for _, d := range []struct {
+		doc map[string]any
+		v   *any
+	}{{from, &n1}, {to, &n2}} {
+		b, err := json.Marshal(d.doc)
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(b, d.v); err != nil {
+			return false, err
+		}
+	}
+	return !reflect.DeepEqual(n1, n2), nil
+}
+
+// ttl returns the expiration of the index, or -1 if it is not set.
+func ttl(idx *Index) int64 {
+	if idx.ExpireAfterSeconds == nil {
+		return -1
+	}
+	return *idx.ExpireAfterSeconds
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	var (
+		ttl1, ttl2 = int64(60), int64(120)
+		from       = &Database{
+			Collections: []*Collection{
+				{Name: "logs"},
+				{
+					Name: "users",
+					// Inspected documents are decoded as float64.
+					Validator:       map[string]any{"$jsonSchema": map[string]any{"minProperties": float64(1)}},
+					ValidationLevel: "strict",
+					Indexes: []*Index{
+						{Name: "users_email", Keys: []*Key{{Field: "email"}}},
+						{Name: "users_name", Keys: []*Key{{Field: "name"}}},
+						{Name: "users_seen", Keys: []*Key{{Field: "seen"}}, ExpireAfterSeconds: &ttl1},
+					},
+				},
+				{Name: "events", Capped: true, Size: 1024},
+			},
+		}
+		to = &Database{
+			Collections: []*Collection{
+				{
+					Name:      "users",
+					Validator: map[string]any{"$jsonSchema": map[string]any{"minProperties": 1}},
+					Indexes: []*Index{
+						{Name: "users_email", Keys: []*Key{{Field: "email"}}, Unique: true},
+						{Name: "users_seen", Keys: []*Key{{Field: "seen"}}, ExpireAfterSeconds: &ttl2},
+						{Name: "users_bio", Keys: []*Key{{Field: "bio", Type: "text"}}},
+					},
+				},
+				{Name: "events", Capped: true, Size: 2048},
+				{Name: "orders"},
+			},
+		}
+	)
+	changes, err := Diff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []Change{
+		&DropCollection{C: from.Collections[0]},
+		&DropIndex{C: from.Collections[1], I: from.Collections[1].Indexes[1]},
+		&DropIndex{C: from.Collections[1], I: from.Collections[1].Indexes[0]},
+		&AddIndex{C: to.Collections[0], I: to.Collections[0].Indexes[0]},
+		&ModifyIndex{C: to.Collections[0], From: from.Collections[1].Indexes[2], To: to.Collections[0].Indexes[1]},
+		&AddIndex{C: to.Collections[0], I: to.Collections[0].Indexes[2]},
+		&ModifyCapped{From: from.Collections[2], To: to.Collections[1]},
+		&AddCollection{C: to.Collections[2]},
+	}, changes)
+
+	// Validation changes.
+	to.Collections[0].ValidationAction = "warn"
+	changes, err = CollectionDiff(from.Collections[1], to.Collections[0])
+	require.NoError(t, err)
+	require.Equal(t, &ModifyValidation{From: from.Collections[1], To: to.Collections[0]}, changes[0])
+	to.Collections[0].ValidationAction = "error"
+	to.Collections[0].Validator = nil
+	changes, err = CollectionDiff(from.Collections[1], to.Collections[0])
+	require.NoError(t, err)
+	require.Equal(t, &ModifyValidation{From: from.Collections[1], To: to.Collections[0]}, changes[0])
+
+	_, err = CollectionDiff(&Collection{Name: "logs"}, &Collection{Name: "logs", Capped: true, Size: 1024})
+	require.EqualError(t, err, `mongodb: collection "logs": changing a collection to (or from) capped is not supported`)
+}
@@ -0,0 +1,170 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InspectDatabase returns the collections of the managed database, including their validators,
+// indexes and options. System collections, views and the revisions collection are skipped.
+func (d *Driver) InspectDatabase(ctx context.Context) (*Database, error) {
+	docs, err := d.cursor(ctx, Command{
+		{Key: "listCollections", Value: 1},
+		{Key: "filter", Value: map[string]any{"type": "collection"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: list collections: %w", err)
+	}
+	db := &Database{Name: d.db}
+	for _, doc := range docs {
+		var info struct {
+			Name    string `json:"name"`
+			Options struct {
+				Validator        map[string]any `json:"validator"`
+				ValidationLevel  string         `json:"validationLevel"`
+				ValidationAction string         `json:"validationAction"`
+				Capped           bool           `json:"capped"`
+				// Numbers may be encoded as doubles (e.g., 1024.0).
+				Size float64 `json:"size"`
+				Max  float64 `json:"max"`
+			} `json:"options"`
+		}
+		if err := json.Unmarshal(doc, &info); err != nil {
+			return nil, fmt.Errorf("mongodb: decode collection: %w", err)
+		}
+		if strings.HasPrefix(info.Name, "system.") || info.Name == RevisionsCollection {
+			continue
+		}
+		c := &Collection{
+			Name:             info.Name,
+			Validator:        info.Options.Validator,
+			ValidationLevel:  info.Options.ValidationLevel,
+			ValidationAction: info.Options.ValidationAction,
+			Capped:           info.Options.Capped,
+			Size:             int64(info.Options.Size),
+			Max:              int64(info.Options.Max),
+		}
+		if c.Indexes, err = d.inspectIndexes(ctx, c.Name); err != nil {
+			return nil, err
+		}
+		db.Collections = append(db.Collections, c)
+	}
+	sort.Slice(db.Collections, func(i, j int) bool {
+		return db.Collections[i].Name < db.Collections[j].Name
+	})
+	return db, nil
+}
+
+// inspectIndexes returns the indexes of the given collection,
+// except the default index on the _id field.
+func (d *Driver) inspectIndexes(ctx context.Context, name string) ([]*Index, error) {
+	docs, err := d.cursor(ctx, Command{{Key: "listIndexes", Value: name}})
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: list indexes of collection %q: %w", name, err)
+	}
+	var indexes []*Index
+	for _, doc := range docs {
+		var info struct {
+			Index
+			Key json.RawMessage `json:"key"`
+			TTL *float64        `json:"expireAfterSeconds"`
+		}
+		if err := json.Unmarshal(doc, &info); err != nil {
+			return nil, fmt.Errorf("mongodb: decode index of collection %q: %w", name, err)
+		}
+		if info.Name == defaultIndex {
+			continue
+		}
+		idx := info.Index
+		if info.TTL != nil {
+			ttl := int64(*info.TTL)
+			idx.ExpireAfterSeconds = &ttl
+		}
+		if idx.Keys, err = decodeKeys(info.Key); err != nil {
+			return nil, fmt.Errorf("mongodb: decode keys of index %q: %w", idx.Name, err)
+		}
+		indexes = append(indexes, &idx)
+	}
+	return indexes, nil
+}
+
+// defaultIndex is the name of the index created on the _id field of every collection.
+const defaultIndex = "_id_"
+
+// decodeKeys decodes the key document of an index. The document is decoded
+// token by token, as the order of its fields defines the order of the keys.
+func decodeKeys(b json.RawMessage) ([]*Key, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("unexpected key document: %s", b)
+	}
+	var keys []*Key
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k := &Key{Field: t.(string)}
+		if t, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		switch v := t.(type) {
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			k.Desc = f < 0
+		case string:
+			k.Type = v
+		default:
+			return nil, fmt.Errorf("unexpected value %v for key %q", t, k.Field)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// cursor runs a command that returns a cursor, and returns all its documents.
+func (d *Driver) cursor(ctx context.Context, cmd Command) ([]json.RawMessage, error) {
+	type reply struct {
+		Cursor struct {
+			ID         json.Number       `json:"id"`
+			NS         string            `json:"ns"`
+			FirstBatch []json.RawMessage `json:"firstBatch"`
+			NextBatch  []json.RawMessage `json:"nextBatch"`
+		} `json:"cursor"`
+	}
+	var r reply
+	if err := d.run(ctx, cmd, &r); err != nil {
+		return nil, err
+	}
+	docs := r.Cursor.FirstBatch
+	// The namespace of the cursor is formatted as "<db>.<collection>".
+	coll := strings.TrimPrefix(r.Cursor.NS, d.db+".")
+	for r.Cursor.ID != "" && r.Cursor.ID != "0" {
+		id, err := r.Cursor.ID.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("unexpected cursor id %q: %w", r.Cursor.ID, err)
+		}
+		r = reply{}
+		if err := d.run(ctx, Command{
+			{Key: "getMore", Value: map[string]string{"$numberLong": strconv.FormatInt(id, 10)}},
+			{Key: "collection", Value: coll},
+		}, &r); err != nil {
+			return nil, err
+		}
+		docs = append(docs, r.Cursor.NextBatch...)
+	}
+	return docs, nil
+}
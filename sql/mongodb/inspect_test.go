@@ -0,0 +1,143 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectDatabase(t *testing.T) {
+	r := &mockRunner{t: t}
+	r.expect(`{"listCollections":1,"filter":{"type":"collection"}}`, `{
+  "cursor": {
+    "id": 42,
+    "ns": "app.$cmd.listCollections",
+    "firstBatch": [
+      {"name": "users", "type": "collection", "options": {"validator": {"$jsonSchema": {"bsonType": "object", "required": ["email"]}}, "validationLevel": "strict", "validationAction": "warn"}},
+      {"name": "system.views", "type": "collection", "options": {}}
+    ]
+  },
+  "ok": 1.0
+}`)
+	r.expect(`{"getMore":{"$numberLong":"42"},"collection":"$cmd.listCollections"}`, `{
+  "cursor": {
+    "id": 0,
+    "ns": "app.$cmd.listCollections",
+    "nextBatch": [
+      {"name": "events", "type": "collection", "options": {"capped": true, "size": 4096.0, "max": 100}},
+      {"name": "atlas_schema_revisions", "type": "collection", "options": {}}
+    ]
+  },
+  "ok": 1.0
+}`)
+	r.expect(`{"listIndexes":"users"}`, `{
+  "cursor": {
+    "id": 0,
+    "ns": "app.users",
+    "firstBatch": [
+      {"v": 2, "key": {"_id": 1}, "name": "_id_"},
+      {"v": 2, "key": {"email": 1, "created_at": -1}, "name": "users_email", "unique": true, "partialFilterExpression": {"active": true}},
+      {"v": 2, "key": {"bio": "text"}, "name": "users_bio", "sparse": true}
+    ]
+  },
+  "ok": 1
+}`)
+	r.expect(`{"listIndexes":"events"}`, `{
+  "cursor": {
+    "id": 0,
+    "ns": "app.events",
+    "firstBatch": [
+      {"v": 2, "key": {"_id": 1}, "name": "_id_"},
+      {"v": 2, "key": {"ts": 1}, "name": "events_ts", "expireAfterSeconds": 3600.0}
+    ]
+  },
+  "ok": 1
+}`)
+	drv, err := Open(r, "app")
+	require.NoError(t, err)
+	db, err := drv.InspectDatabase(context.Background())
+	require.NoError(t, err)
+	r.done()
+	ttl := int64(3600)
+	require.Equal(t, &Database{
+		Name: "app",
+		Collections: []*Collection{
+			{
+				Name:   "events",
+				Capped: true,
+				Size:   4096,
+				Max:    100,
+				Indexes: []*Index{
+					{Name: "events_ts", Keys: []*Key{{Field: "ts"}}, ExpireAfterSeconds: &ttl},
+				},
+			},
+			{
+				Name:             "users",
+				Validator:        map[string]any{"$jsonSchema": map[string]any{"bsonType": "object", "required": []any{"email"}}},
+				ValidationLevel:  "strict",
+				ValidationAction: "warn",
+				Indexes: []*Index{
+					{Name: "users_email", Keys: []*Key{{Field: "email"}, {Field: "created_at", Desc: true}}, Unique: true, PartialFilter: map[string]any{"active": true}},
+					{Name: "users_bio", Keys: []*Key{{Field: "bio", Type: "text"}}, Sparse: true},
+				},
+			},
+		},
+	}, db)
+}
+
+func TestDriver_InspectDatabaseError(t *testing.T) {
+	r := &mockRunner{t: t}
+	r.expect(`{"listCollections":1,"filter":{"type":"collection"}}`, `{"ok": 0, "errmsg": "not authorized on app to execute command", "code": 13}`)
+	drv, err := Open(r, "app")
+	require.NoError(t, err)
+	_, err = drv.InspectDatabase(context.Background())
+	require.EqualError(t, err, "mongodb: list collections: mongodb: not authorized on app to execute command (code 13)")
+	r.done()
+
+	_, err = Open(nil, "app")
+	require.EqualError(t, err, "mongodb: no runner given")
+	_, err = Open(r, "")
+	require.EqualError(t, err, "mongodb: no database given")
+}
+
+// mockRunner is a Runner that expects commands to be run in order.
+type mockRunner struct {
+	t        *testing.T
+	expected []expectedCmd
+}
+
+type expectedCmd struct {
+	cmd, reply string
+	prefix     bool // Match only the prefix of the command.
+}
+
+func (r *mockRunner) expect(cmd, reply string) {
+	r.expected = append(r.expected, expectedCmd{cmd: cmd, reply: reply})
+}
+
+func (r *mockRunner) expectPrefix(cmd, reply string) {
+	r.expected = append(r.expected, expectedCmd{cmd: cmd, reply: reply, prefix: true})
+}
+
+func (r *mockRunner) RunCommand(_ context.Context, db string, cmd []byte) ([]byte, error) {
+	require.Equal(r.t, "app", db)
+	require.NotEmpty(r.t, r.expected, "unexpected command: %s", cmd)
+	e := r.expected[0]
+	r.expected = r.expected[1:]
+	if e.prefix {
+		require.True(r.t, strings.HasPrefix(string(cmd), e.cmd), "unexpected command: %s", cmd)
+	} else {
+		require.Equal(r.t, e.cmd, string(cmd))
+	}
+	return []byte(e.reply), nil
+}
+
+func (r *mockRunner) done() {
+	require.Empty(r.t, r.expected, "expected commands were not run")
+}
@@ -0,0 +1,430 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// PlanChanges returns a migration plan for applying the given changes. Each change is
+// planned as a database command, encoded as a relaxed MongoDB Extended JSON document.
+func (d *Driver) PlanChanges(name string, changes []Change) (*migrate.Plan, error) {
+	p := &migrate.Plan{
+		Name:       name,
+		Reversible: true,
+		// MongoDB does not support running
+		// DDL commands in transactions.
+		Transactional: false,
+	}
+	for _, c := range changes {
+		var (
+			cmd, rev Command
+			comment  string
+		)
+		switch c := c.(type) {
+		case *AddCollection:
+			cmd, rev = createCmd(c.C), Command{{Key: "drop", Value: c.C.Name}}
+			comment = fmt.Sprintf("create %q collection", c.C.Name)
+		case *DropCollection:
+			cmd, rev = Command{{Key: "drop", Value: c.C.Name}}, createCmd(c.C)
+			comment = fmt.Sprintf("drop %q collection", c.C.Name)
+			// Documents of dropped collections cannot be restored.
+			p.Reversible = false
+		case *ModifyValidation:
+			cmd, rev = validationCmd(c.To), validationCmd(c.From)
+			comment = fmt.Sprintf("modify validation of %q collection", c.To.Name)
+		case *ModifyCapped:
+			cmd, rev = cappedCmd(c.To), cappedCmd(c.From)
+			comment = fmt.Sprintf("modify capped options of %q collection", c.To.Name)
+		case *AddIndex:
+			cmd, rev = createIndexesCmd(c.C.Name, c.I), dropIndexCmd(c.C.Name, c.I)
+			comment = fmt.Sprintf("create index %q to collection %q", c.I.Name, c.C.Name)
+		case *DropIndex:
+			cmd, rev = dropIndexCmd(c.C.Name, c.I), createIndexesCmd(c.C.Name, c.I)
+			comment = fmt.Sprintf("drop index %q from collection %q", c.I.Name, c.C.Name)
+		case *ModifyIndex:
+			if c.From.ExpireAfterSeconds == nil || c.To.ExpireAfterSeconds == nil {
+				return nil, fmt.Errorf("mongodb: index %q: only the expiration of TTL indexes can be modified", c.To.Name)
+			}
+			cmd, rev = ttlCmd(c.C.Name, c.To), ttlCmd(c.C.Name, c.From)
+			comment = fmt.Sprintf("modify expiration of index %q of collection %q", c.To.Name, c.C.Name)
+		default:
+			return nil, fmt.Errorf("mongodb: unsupported change %T", c)
+		}
+		b1, err := json.Marshal(cmd)
+		if err != nil {
+			return nil, err
+		}
+		b2, err := json.Marshal(rev)
+		if err != nil {
+			return nil, err
+		}
+		p.Changes = append(p.Changes, &migrate.Change{
+			Cmd:     string(b1),
+			Reverse: string(b2),
+			Comment: comment,
+		})
+	}
+	return p, nil
+}
+
+// ApplyChanges applies the changes on the managed database.
+func (d *Driver) ApplyChanges(ctx context.Context, changes []Change) error {
+	p, err := d.PlanChanges("apply", changes)
+	if err != nil {
+		return err
+	}
+	for _, c := range p.Changes {
+		if err := d.runJSON(ctx, []byte(c.Cmd), nil); err != nil {
+			return fmt.Errorf("%s: %w", c.Comment, err)
+		}
+	}
+	return nil
+}
+
+// WritePlan writes the given plan as a new migration file to the directory,
+// and updates its sum file. The files are formatted using the default formatter
+// of the sql/migrate package, one command per statement.
+func WritePlan(dir migrate.Dir, p *migrate.Plan) error {
+	return migrate.NewPlanner(nil, dir).WritePlan(p)
+}
+
+// createCmd returns the "create" command of the collection.
+func createCmd(c *Collection) Command {
+	cmd := Command{{Key: "create", Value: c.Name}}
+	if c.Capped {
+		cmd = append(cmd, Elem{Key: "capped", Value: true}, Elem{Key: "size", Value: c.Size})
+		if c.Max > 0 {
+			cmd = append(cmd, Elem{Key: "max", Value: c.Max})
+		}
+	}
+	if len(c.Validator) > 0 {
+		cmd = append(cmd, Elem{Key: "validator", Value: c.Validator})
+	}
+	if c.ValidationLevel != "" {
+		cmd = append(cmd, Elem{Key: "validationLevel", Value: c.ValidationLevel})
+	}
+	if c.ValidationAction != "" {
+		cmd = append(cmd, Elem{Key: "validationAction", Value: c.ValidationAction})
+	}
+	return cmd
+}
+
+// validationCmd returns the "collMod" command that sets the validation of the collection.
+func validationCmd(c *Collection) Command {
+	v := c.Validator
+	if v == nil {
+		// An empty validator removes the existing one.
+		v = map[string]any{}
+	}
+	return Command{
+		{Key: "collMod", Value: c.Name},
+		{Key: "validator", Value: v},
+		{Key: "validationLevel", Value: defaultString(c.ValidationLevel, "strict")},
+		{Key: "validationAction", Value: defaultString(c.ValidationAction, "error")},
+	}
+}
+
+// cappedCmd returns the "collMod" command that sets the capped options of the collection.
+func cappedCmd(c *Collection) Command {
+	return Command{
+		{Key: "collMod", Value: c.Name},
+		{Key: "cappedSize", Value: c.Size},
+		{Key: "cappedMax", Value: c.Max},
+	}
+}
+
+// createIndexesCmd returns the "createIndexes" command of the index.
+func createIndexesCmd(name string, idx *Index) Command {
+	keys := make(Command, len(idx.Keys))
+	for i, k := range idx.Keys {
+		var v any = 1
+		switch {
+		case k.Type != "":
+			v = k.Type
+		case k.Desc:
+			v = -1
+		}
+		keys[i] = Elem{Key: k.Field, Value: v}
+	}
+	spec := Command{{Key: "key", Value: keys}, {Key: "name", Value: idx.Name}}
+	if idx.Unique {
+		spec = append(spec, Elem{Key: "unique", Value: true})
+	}
+	if idx.Sparse {
+		spec = append(spec, Elem{Key: "sparse", Value: true})
+	}
+	if idx.ExpireAfterSeconds != nil {
+		spec = append(spec, Elem{Key: "expireAfterSeconds", Value: *idx.ExpireAfterSeconds})
+	}
+	if len(idx.PartialFilter) > 0 {
+		spec = append(spec, Elem{Key: "partialFilterExpression", Value: idx.PartialFilter})
+	}
+	return Command{
+		{Key: "createIndexes", Value: name},
+		{Key: "indexes", Value: []Command{spec}},
+	}
+}
+
+// dropIndexCmd returns the "dropIndexes" command of the index.
+func dropIndexCmd(name string, idx *Index) Command {
+	return Command{
+		{Key: "dropIndexes", Value: name},
+		{Key: "index", Value: idx.Name},
+	}
+}
+
+// ttlCmd returns the "collMod" command that sets the expiration of the index.
+func ttlCmd(name string, idx *Index) Command {
+	return Command{
+		{Key: "collMod", Value: name},
+		{Key: "index", Value: Command{
+			{Key: "name", Value: idx.Name},
+			{Key: "expireAfterSeconds", Value: *idx.ExpireAfterSeconds},
+		}},
+	}
+}
+
+// RevisionsCollection is the name of the collection that
+// stores the revisions of the executed migration files.
+const RevisionsCollection = "atlas_schema_revisions"
+
+// NewExecutor returns a migrate.Executor for executing the migration files of the given
+// directory on the managed database. The statements of the migration files are expected
+// to be database commands, as planned by PlanChanges, and the executed revisions are
+// stored in the RevisionsCollection of the database.
+func (d *Driver) NewExecutor(dir migrate.Dir, opts ...migrate.ExecutorOption) (*migrate.Executor, error) {
+	return migrate.NewExecutor(&migrateDriver{drv: d}, dir, &revisions{drv: d}, opts...)
+}
+
+// errUnsupported is returned by the migrate.Driver methods that are not supported by MongoDB.
+var errUnsupported = errors.New("mongodb: operation is not supported")
+
+// migrateDriver adapts the Driver to the migrate.Driver interface, which is used by the
+// migrate.Executor. Statements are executed as database commands, and the methods that
+// operate on SQL schemas are not supported.
+type migrateDriver struct{ drv *Driver }
+
+var _ migrate.Driver = (*migrateDriver)(nil)
+
+// ExecContext runs the given statement as a database command.
+func (m *migrateDriver) ExecContext(ctx context.Context, stmt string, args ...any) (sql.Result, error) {
+	if len(args) > 0 {
+		return nil, errors.New("mongodb: commands do not accept arguments")
+	}
+	// Statements of migration files are terminated by a semicolon.
+	stmt = strings.TrimSuffix(strings.TrimSpace(stmt), ";")
+	if err := m.drv.runJSON(ctx, []byte(stmt), nil); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+// CheckClean checks if the database does not contain any collections,
+// besides the system collections and the revisions collection.
+func (m *migrateDriver) CheckClean(ctx context.Context, _ *migrate.TableIdent) error {
+	db, err := m.drv.InspectDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	if len(db.Collections) > 0 {
+		return &migrate.NotCleanError{Reason: fmt.Sprintf("found collection %q in database %q", db.Collections[0].Name, db.Name)}
+	}
+	return nil
+}
+
+func (*migrateDriver) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) InspectSchema(context.Context, string, *schema.InspectOptions) (*schema.Schema, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) RealmDiff(*schema.Realm, *schema.Realm, ...schema.DiffOption) ([]schema.Change, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) SchemaDiff(*schema.Schema, *schema.Schema, ...schema.DiffOption) ([]schema.Change, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) TableDiff(*schema.Table, *schema.Table, ...schema.DiffOption) ([]schema.Change, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) PlanChanges(context.Context, string, []schema.Change, ...migrate.PlanOption) (*migrate.Plan, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) ApplyChanges(context.Context, []schema.Change, ...migrate.PlanOption) error {
+	return errUnsupported
+}
+
+func (*migrateDriver) Lock(context.Context, string, time.Duration) (schema.UnlockFunc, error) {
+	return nil, errUnsupported
+}
+
+func (*migrateDriver) Snapshot(context.Context) (migrate.RestoreFunc, error) {
+	return nil, errUnsupported
+}
+
+type (
+	// revisions implements the migrate.RevisionReadWriter interface
+	// by storing the revisions in the RevisionsCollection.
+	revisions struct{ drv *Driver }
+
+	// revisionDoc is the document that describes a revision.
+	revisionDoc struct {
+		Version         string   `json:"_id"`
+		Description     string   `json:"description"`
+		Type            uint     `json:"type"`
+		Applied         int      `json:"applied"`
+		Total           int      `json:"total"`
+		ExecutedAt      extDate  `json:"executed_at"`
+		ExecutionTime   int64    `json:"execution_time"`
+		Error           string   `json:"error,omitempty"`
+		ErrorStmt       string   `json:"error_stmt,omitempty"`
+		Hash            string   `json:"hash"`
+		PartialHashes   []string `json:"partial_hashes,omitempty"`
+		OperatorVersion string   `json:"operator_version"`
+	}
+
+	// extDate is an Extended JSON date. For example, {"$date": "2022-01-01T00:00:00Z"}.
+	extDate struct {
+		Date string `json:"$date"`
+	}
+)
+
+var _ migrate.RevisionReadWriter = (*revisions)(nil)
+
+// Ident returns the identifier of the revisions collection.
+func (r *revisions) Ident() *migrate.TableIdent {
+	return &migrate.TableIdent{Name: RevisionsCollection}
+}
+
+// ReadRevisions returns all revisions, ordered by version.
+func (r *revisions) ReadRevisions(ctx context.Context) ([]*migrate.Revision, error) {
+	return r.find(ctx, nil)
+}
+
+// ReadRevision returns a revision by version.
+func (r *revisions) ReadRevision(ctx context.Context, v string) (*migrate.Revision, error) {
+	revs, err := r.find(ctx, map[string]any{"_id": v})
+	if err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, migrate.ErrRevisionNotExist
+	}
+	return revs[0], nil
+}
+
+// WriteRevision saves the revision to the collection.
+func (r *revisions) WriteRevision(ctx context.Context, rev *migrate.Revision) error {
+	doc := revisionDoc{
+		Version:         rev.Version,
+		Description:     rev.Description,
+		Type:            uint(rev.Type),
+		Applied:         rev.Applied,
+		Total:           rev.Total,
+		ExecutedAt:      extDate{Date: rev.ExecutedAt.UTC().Format(time.RFC3339Nano)},
+		ExecutionTime:   int64(rev.ExecutionTime),
+		Error:           rev.Error,
+		ErrorStmt:       rev.ErrorStmt,
+		Hash:            rev.Hash,
+		PartialHashes:   rev.PartialHashes,
+		OperatorVersion: rev.OperatorVersion,
+	}
+	return r.write(ctx, Command{
+		{Key: "update", Value: RevisionsCollection},
+		{Key: "updates", Value: []Command{{
+			{Key: "q", Value: map[string]any{"_id": rev.Version}},
+			{Key: "u", Value: doc},
+			{Key: "upsert", Value: true},
+		}}},
+	})
+}
+
+// DeleteRevision deletes a revision by version from the collection.
+func (r *revisions) DeleteRevision(ctx context.Context, v string) error {
+	return r.write(ctx, Command{
+		{Key: "delete", Value: RevisionsCollection},
+		{Key: "deletes", Value: []Command{{
+			{Key: "q", Value: map[string]any{"_id": v}},
+			{Key: "limit", Value: 1},
+		}}},
+	})
+}
+
+// find returns the revisions that match the given filter, ordered by version.
+func (r *revisions) find(ctx context.Context, filter map[string]any) ([]*migrate.Revision, error) {
+	cmd := Command{{Key: "find", Value: RevisionsCollection}}
+	if filter != nil {
+		cmd = append(cmd, Elem{Key: "filter", Value: filter})
+	}
+	cmd = append(cmd, Elem{Key: "sort", Value: Command{{Key: "_id", Value: 1}}})
+	docs, err := r.drv.cursor(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: read revisions: %w", err)
+	}
+	revs := make([]*migrate.Revision, len(docs))
+	for i, b := range docs {
+		var doc revisionDoc
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("mongodb: decode revision: %w", err)
+		}
+		at, err := time.Parse(time.RFC3339Nano, doc.ExecutedAt.Date)
+		if err != nil {
+			return nil, fmt.Errorf("mongodb: decode revision %q: %w", doc.Version, err)
+		}
+		revs[i] = &migrate.Revision{
+			Version:         doc.Version,
+			Description:     doc.Description,
+			Type:            migrate.RevisionType(doc.Type),
+			Applied:         doc.Applied,
+			Total:           doc.Total,
+			ExecutedAt:      at,
+			ExecutionTime:   time.Duration(doc.ExecutionTime),
+			Error:           doc.Error,
+			ErrorStmt:       doc.ErrorStmt,
+			Hash:            doc.Hash,
+			PartialHashes:   doc.PartialHashes,
+			OperatorVersion: doc.OperatorVersion,
+		}
+	}
+	return revs, nil
+}
+
+// write runs a write command, and returns its first write error, if any.
+func (r *revisions) write(ctx context.Context, cmd Command) error {
+	var reply struct {
+		WriteErrors []struct {
+			Code   int    `json:"code"`
+			Errmsg string `json:"errmsg"`
+		} `json:"writeErrors"`
+	}
+	if err := r.drv.run(ctx, cmd, &reply); err != nil {
+		return fmt.Errorf("mongodb: write revision: %w", err)
+	}
+	if len(reply.WriteErrors) > 0 {
+		return fmt.Errorf("mongodb: write revision: %w", &CommandError{Code: reply.WriteErrors[0].Code, Message: reply.WriteErrors[0].Errmsg})
+	}
+	return nil
+}
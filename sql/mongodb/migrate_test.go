@@ -0,0 +1,137 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_PlanChanges(t *testing.T) {
+	var (
+		ttl1, ttl2 = int64(60), int64(120)
+		users      = &Collection{
+			Name:             "users",
+			Validator:        map[string]any{"$jsonSchema": map[string]any{"bsonType": "object", "required": []string{"email"}}},
+			ValidationAction: "warn",
+		}
+		email = &Index{Name: "users_email", Keys: []*Key{{Field: "email"}, {Field: "created_at", Desc: true}}, Unique: true, PartialFilter: map[string]any{"active": true}}
+		seen1 = &Index{Name: "users_seen", Keys: []*Key{{Field: "seen"}}, ExpireAfterSeconds: &ttl1}
+		seen2 = &Index{Name: "users_seen", Keys: []*Key{{Field: "seen"}}, ExpireAfterSeconds: &ttl2}
+		logs  = &Collection{Name: "logs", Capped: true, Size: 1024, Max: 10}
+	)
+	drv, err := Open(&mockRunner{t: t}, "app")
+	require.NoError(t, err)
+	plan, err := drv.PlanChanges("init", []Change{
+		&AddCollection{C: users},
+		&AddIndex{C: users, I: email},
+		&ModifyIndex{C: users, From: seen1, To: seen2},
+		&ModifyValidation{From: users, To: &Collection{Name: "users"}},
+		&ModifyCapped{From: logs, To: &Collection{Name: "logs", Capped: true, Size: 2048}},
+		&DropCollection{C: logs},
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Transactional)
+	require.False(t, plan.Reversible)
+	require.Equal(t, [][2]string{
+		{`{"create":"users","validator":{"$jsonSchema":{"bsonType":"object","required":["email"]}},"validationAction":"warn"}`, `{"drop":"users"}`},
+		{`{"createIndexes":"users","indexes":[{"key":{"email":1,"created_at":-1},"name":"users_email","unique":true,"partialFilterExpression":{"active":true}}]}`, `{"dropIndexes":"users","index":"users_email"}`},
+		{`{"collMod":"users","index":{"name":"users_seen","expireAfterSeconds":120}}`, `{"collMod":"users","index":{"name":"users_seen","expireAfterSeconds":60}}`},
+		{`{"collMod":"users","validator":{},"validationLevel":"strict","validationAction":"error"}`, `{"collMod":"users","validator":{"$jsonSchema":{"bsonType":"object","required":["email"]}},"validationLevel":"strict","validationAction":"warn"}`},
+		{`{"collMod":"logs","cappedSize":2048,"cappedMax":0}`, `{"collMod":"logs","cappedSize":1024,"cappedMax":10}`},
+		{`{"drop":"logs"}`, `{"create":"logs","capped":true,"size":1024,"max":10}`},
+	}, planCmds(plan))
+
+	_, err = drv.PlanChanges("ttl", []Change{&ModifyIndex{C: users, From: email, To: seen2}})
+	require.EqualError(t, err, `mongodb: index "users_seen": only the expiration of TTL indexes can be modified`)
+}
+
+func TestDriver_ApplyChanges(t *testing.T) {
+	r := &mockRunner{t: t}
+	r.expect(`{"create":"users"}`, `{"ok": 1}`)
+	r.expect(`{"createIndexes":"users","indexes":[{"key":{"email":1},"name":"users_email"}]}`, `{"ok": 0, "errmsg": "Index already exists with a different name", "code": 85}`)
+	drv, err := Open(r, "app")
+	require.NoError(t, err)
+	users := &Collection{Name: "users"}
+	err = drv.ApplyChanges(context.Background(), []Change{
+		&AddCollection{C: users},
+		&AddIndex{C: users, I: &Index{Name: "users_email", Keys: []*Key{{Field: "email"}}}},
+	})
+	require.EqualError(t, err, `create index "users_email" to collection "users": mongodb: Index already exists with a different name (code 85)`)
+	r.done()
+}
+
+func TestDriver_NewExecutor(t *testing.T) {
+	r := &mockRunner{t: t}
+	drv, err := Open(r, "app")
+	require.NoError(t, err)
+	plan, err := drv.PlanChanges("init", []Change{
+		&AddCollection{C: &Collection{Name: "users", Validator: map[string]any{"$jsonSchema": map[string]any{"description": "users; and -- admins"}}}},
+	})
+	require.NoError(t, err)
+	plan.Version = "1"
+	dir := &migrate.MemDir{}
+	require.NoError(t, WritePlan(dir, plan))
+	files, err := dir.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "-- Create \"users\" collection\n{\"create\":\"users\",\"validator\":{\"$jsonSchema\":{\"description\":\"users; and -- admins\"}}};\n", string(files[0].Bytes()))
+
+	ex, err := drv.NewExecutor(dir)
+	require.NoError(t, err)
+	noRevisions := `{"cursor": {"id": 0, "ns": "app.atlas_schema_revisions", "firstBatch": []}, "ok": 1}`
+	r.expect(`{"find":"atlas_schema_revisions","sort":{"_id":1}}`, noRevisions)
+	r.expect(`{"listCollections":1,"filter":{"type":"collection"}}`, `{"cursor": {"id": 0, "ns": "app.$cmd.listCollections", "firstBatch": []}, "ok": 1}`)
+	pending, err := ex.Pending(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	r.expect(`{"find":"atlas_schema_revisions","filter":{"_id":"1"},"sort":{"_id":1}}`, noRevisions)
+	r.expectPrefix(`{"update":"atlas_schema_revisions","updates":[{"q":{"_id":"1"},"u":{"_id":"1","description":"init","type":2,"applied":0,"total":1,`, `{"ok": 1, "n": 1}`)
+	r.expect(`{"create":"users","validator":{"$jsonSchema":{"description":"users; and -- admins"}}}`, `{"ok": 1}`)
+	r.expectPrefix(`{"update":"atlas_schema_revisions","updates":[{"q":{"_id":"1"},"u":{"_id":"1","description":"init","type":2,"applied":1,"total":1,`, `{"ok": 1, "n": 1}`)
+	r.expectPrefix(`{"update":"atlas_schema_revisions","updates":[{"q":{"_id":"1"},"u":{"_id":"1","description":"init","type":2,"applied":1,"total":1,`, `{"ok": 1, "n": 1, "writeErrors": [{"index": 0, "code": 11000, "errmsg": "duplicate key"}]}`)
+	err = ex.Execute(context.Background(), pending[0])
+	require.EqualError(t, err, "sql/migrate: write revision: mongodb: write revision: mongodb: duplicate key (code 11000)")
+	r.done()
+}
+
+func TestRevisions_ReadRevision(t *testing.T) {
+	r := &mockRunner{t: t}
+	r.expect(`{"find":"atlas_schema_revisions","filter":{"_id":"20220101"},"sort":{"_id":1}}`, `{
+  "cursor": {
+    "id": 0,
+    "ns": "app.atlas_schema_revisions",
+    "firstBatch": [
+      {"_id": "20220101", "description": "init", "type": 2, "applied": 1, "total": 2, "executed_at": {"$date": "2022-01-01T10:00:00Z"}, "execution_time": 1500000000, "error": "failed", "hash": "h", "partial_hashes": ["h1:a"], "operator_version": "v1"}
+    ]
+  },
+  "ok": 1
+}`)
+	drv, err := Open(r, "app")
+	require.NoError(t, err)
+	rev, err := (&revisions{drv: drv}).ReadRevision(context.Background(), "20220101")
+	require.NoError(t, err)
+	require.Equal(t, "20220101", rev.Version)
+	require.Equal(t, migrate.RevisionTypeExecute, rev.Type)
+	require.Equal(t, 1, rev.Applied)
+	require.Equal(t, 2, rev.Total)
+	require.Equal(t, "2022-01-01T10:00:00Z", rev.ExecutedAt.Format("2006-01-02T15:04:05Z07:00"))
+	require.Equal(t, "1.5s", rev.ExecutionTime.String())
+	require.Equal(t, []string{"h1:a"}, rev.PartialHashes)
+	r.done()
+}
+
+func planCmds(p *migrate.Plan) [][2]string {
+	cmds := make([][2]string, len(p.Changes))
+	for i, c := range p.Changes {
+		cmds[i] = [2]string{c.Cmd, c.Reverse.(string)}
+	}
+	return cmds
+}
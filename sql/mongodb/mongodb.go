@@ -0,0 +1,220 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package mongodb manages the collections of MongoDB databases declaratively. It inspects
+// their JSON-schema validators, indexes and options, diffs them against a desired state
+// defined in HCL or JSON, and plans the changes as database commands (e.g., collMod) that
+// can be applied directly, or written to a versioned migration directory and executed
+// using the sql/migrate package.
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Database describes a MongoDB database and its collections.
+	Database struct {
+		Name        string        `json:"name,omitempty"`
+		Collections []*Collection `json:"collections,omitempty"`
+	}
+
+	// Collection describes a MongoDB collection, its validator, indexes and options.
+	Collection struct {
+		Name string `json:"name"`
+		// Validator holds the validator document of the collection.
+		// For example, {"$jsonSchema": {"bsonType": "object"}}.
+		Validator        map[string]any `json:"validator,omitempty"`
+		ValidationLevel  string         `json:"validationLevel,omitempty"`  // off, strict (default) or moderate.
+		ValidationAction string         `json:"validationAction,omitempty"` // error (default) or warn.
+		Capped           bool           `json:"capped,omitempty"`
+		Size             int64          `json:"size,omitempty"` // Maximum size in bytes of capped collections.
+		Max              int64          `json:"max,omitempty"`  // Maximum number of documents of capped collections.
+		Indexes          []*Index       `json:"indexes,omitempty"`
+	}
+
+	// Index describes an index of a MongoDB collection.
+	Index struct {
+		Name               string         `json:"name"`
+		Keys               []*Key         `json:"keys"`
+		Unique             bool           `json:"unique,omitempty"`
+		Sparse             bool           `json:"sparse,omitempty"`
+		ExpireAfterSeconds *int64         `json:"expireAfterSeconds,omitempty"`
+		PartialFilter      map[string]any `json:"partialFilterExpression,omitempty"`
+	}
+
+	// Key describes a field of an index key. Keys without a type are
+	// ascending (or descending) keys. Otherwise, the type defines the
+	// special index to use. For example, "text", "2dsphere" or "hashed".
+	Key struct {
+		Field string `json:"field"`
+		Desc  bool   `json:"desc,omitempty"`
+		Type  string `json:"type,omitempty"`
+	}
+
+	// A Runner runs database commands on a MongoDB deployment. Commands and their replies
+	// are encoded as relaxed MongoDB Extended JSON documents, which allows adapting any
+	// MongoDB client without depending on it. For example, using the official Go driver:
+	//
+	//	mongodb.RunnerFunc(func(ctx context.Context, db string, cmd []byte) ([]byte, error) {
+	//		var c bson.D
+	//		if err := bson.UnmarshalExtJSON(cmd, false, &c); err != nil {
+	//			return nil, err
+	//		}
+	//		raw, err := client.Database(db).RunCommand(ctx, c).Raw()
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		return bson.MarshalExtJSON(raw, false, false)
+	//	})
+	Runner interface {
+		RunCommand(ctx context.Context, db string, cmd []byte) ([]byte, error)
+	}
+
+	// RunnerFunc allows using an ordinary function as a Runner.
+	RunnerFunc func(ctx context.Context, db string, cmd []byte) ([]byte, error)
+
+	// Driver manages the collections of a MongoDB database.
+	Driver struct {
+		Runner
+		// The name of the managed database.
+		db string
+	}
+)
+
+// RunCommand calls f(ctx, db, cmd).
+func (f RunnerFunc) RunCommand(ctx context.Context, db string, cmd []byte) ([]byte, error) {
+	return f(ctx, db, cmd)
+}
+
+// Open returns a new Driver for managing the given database.
+func Open(r Runner, db string) (*Driver, error) {
+	if r == nil {
+		return nil, errors.New("mongodb: no runner given")
+	}
+	if db == "" {
+		return nil, errors.New("mongodb: no database given")
+	}
+	return &Driver{Runner: r, db: db}, nil
+}
+
+// Collection returns the first collection that matched the given name.
+func (d *Database) Collection(name string) (*Collection, bool) {
+	for _, c := range d.Collections {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Index returns the first index that matched the given name.
+func (c *Collection) Index(name string) (*Index, bool) {
+	for _, idx := range c.Indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return nil, false
+}
+
+type (
+	// Elem is an element of a command document.
+	Elem struct {
+		Key   string
+		Value any
+	}
+
+	// Command is an ordered document that describes a database command.
+	// Commands are ordered, as MongoDB expects the command name to be
+	// the first key of the document.
+	Command []Elem
+)
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c Command) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, e := range c {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("mongodb: marshal %q: %w", e.Key, err)
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+// Name returns the name of the command.
+func (c Command) Name() string {
+	if len(c) == 0 {
+		return ""
+	}
+	return c[0].Key
+}
+
+// run runs the given command on the managed database and decodes its reply into v.
+func (d *Driver) run(ctx context.Context, cmd Command, v any) error {
+	b, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	return d.runJSON(ctx, b, v)
+}
+
+// runJSON runs the given encoded command and decodes its reply into v, if not nil.
+func (d *Driver) runJSON(ctx context.Context, cmd []byte, v any) error {
+	reply, err := d.RunCommand(ctx, d.db, cmd)
+	if err != nil {
+		return err
+	}
+	var r struct {
+		OK     float64 `json:"ok"`
+		Errmsg string  `json:"errmsg"`
+		Code   int     `json:"code"`
+	}
+	if err := json.Unmarshal(reply, &r); err != nil {
+		return fmt.Errorf("mongodb: decode reply: %w", err)
+	}
+	if r.OK != 1 {
+		if r.Errmsg == "" {
+			r.Errmsg = "command failed"
+		}
+		return &CommandError{Code: r.Code, Message: r.Errmsg}
+	}
+	if v == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(reply))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("mongodb: decode reply: %w", err)
+	}
+	return nil
+}
+
+// CommandError is returned when a command is replied with an error by the server.
+type CommandError struct {
+	Code    int    // Code of the error. For example, 26 (NamespaceNotFound).
+	Message string // Message of the error.
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("mongodb: %s (code %d)", e.Message, e.Code)
+}
@@ -0,0 +1,211 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"ariga.io/atlas/schemahcl"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+type (
+	// doc holds the HCL document of a MongoDB database. For example:
+	//
+	//	collection "users" {
+	//	  validator = jsonencode({
+	//	    "$jsonSchema" = {
+	//	      bsonType = "object"
+	//	      required = ["email"]
+	//	    }
+	//	  })
+	//	  validation_action = "warn"
+	//	  index "users_email" {
+	//	    unique = true
+	//	    on {
+	//	      field = "email"
+	//	    }
+	//	  }
+	//	}
+	doc struct {
+		Collections []*collectionSpec `spec:"collection"`
+	}
+
+	// collectionSpec holds a specification for a collection.
+	collectionSpec struct {
+		Name             string       `spec:",name"`
+		Validator        string       `spec:"validator,omitempty"` // JSON document.
+		ValidationLevel  string       `spec:"validation_level,omitempty"`
+		ValidationAction string       `spec:"validation_action,omitempty"`
+		Capped           bool         `spec:"capped,omitempty"`
+		Size             int64        `spec:"size,omitempty"`
+		Max              int64        `spec:"max,omitempty"`
+		Indexes          []*indexSpec `spec:"index"`
+		schemahcl.DefaultExtension
+	}
+
+	// indexSpec holds a specification for an index of a collection.
+	indexSpec struct {
+		Name          string     `spec:",name"`
+		Unique        bool       `spec:"unique,omitempty"`
+		Sparse        bool       `spec:"sparse,omitempty"`
+		PartialFilter string     `spec:"partial_filter,omitempty"` // JSON document.
+		Keys          []*keySpec `spec:"on"`
+		schemahcl.DefaultExtension
+	}
+
+	// keySpec holds a specification for a key of an index.
+	keySpec struct {
+		Field string `spec:"field"`
+		Desc  bool   `spec:"desc,omitempty"`
+		Type  string `spec:"type,omitempty"`
+		schemahcl.DefaultExtension
+	}
+)
+
+// state is the HCL state used for evaluating and marshaling MongoDB documents.
+var state = schemahcl.New()
+
+// EvalHCLBytes evaluates the HCL document of a database into db.
+func EvalHCLBytes(b []byte, db *Database, input map[string]cty.Value) error {
+	var d doc
+	if err := state.EvalBytes(b, &d, input); err != nil {
+		return err
+	}
+	for _, cs := range d.Collections {
+		c, err := convertCollection(cs)
+		if err != nil {
+			return err
+		}
+		db.Collections = append(db.Collections, c)
+	}
+	return validate(db)
+}
+
+// EvalJSONBytes decodes the JSON document of a database into db. For example:
+//
+//	{"collections": [{"name": "users", "validator": {"$jsonSchema": {"bsonType": "object"}}}]}
+func EvalJSONBytes(b []byte, db *Database) error {
+	if err := json.Unmarshal(b, db); err != nil {
+		return fmt.Errorf("mongodb: decode database: %w", err)
+	}
+	return validate(db)
+}
+
+// MarshalHCL marshals the database into an HCL document.
+func MarshalHCL(db *Database) ([]byte, error) {
+	d := &doc{}
+	for _, c := range db.Collections {
+		cs, err := collectionToSpec(c)
+		if err != nil {
+			return nil, err
+		}
+		d.Collections = append(d.Collections, cs)
+	}
+	return state.MarshalSpec(d)
+}
+
+// convertCollection converts a collectionSpec into a Collection.
+func convertCollection(spec *collectionSpec) (*Collection, error) {
+	c := &Collection{
+		Name:             spec.Name,
+		ValidationLevel:  spec.ValidationLevel,
+		ValidationAction: spec.ValidationAction,
+		Capped:           spec.Capped,
+		Size:             spec.Size,
+		Max:              spec.Max,
+	}
+	if spec.Validator != "" {
+		if err := json.Unmarshal([]byte(spec.Validator), &c.Validator); err != nil {
+			return nil, fmt.Errorf("mongodb: collection %q: decode validator: %w", spec.Name, err)
+		}
+	}
+	for _, is := range spec.Indexes {
+		idx := &Index{Name: is.Name, Unique: is.Unique, Sparse: is.Sparse}
+		if attr, ok := is.Attr("expire_after_seconds"); ok {
+			v, err := attr.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("mongodb: index %q: %w", is.Name, err)
+			}
+			idx.ExpireAfterSeconds = &v
+		}
+		if is.PartialFilter != "" {
+			if err := json.Unmarshal([]byte(is.PartialFilter), &idx.PartialFilter); err != nil {
+				return nil, fmt.Errorf("mongodb: index %q: decode partial_filter: %w", is.Name, err)
+			}
+		}
+		for _, k := range is.Keys {
+			idx.Keys = append(idx.Keys, &Key{Field: k.Field, Desc: k.Desc, Type: k.Type})
+		}
+		c.Indexes = append(c.Indexes, idx)
+	}
+	return c, nil
+}
+
+// collectionToSpec converts a Collection into a collectionSpec.
+func collectionToSpec(c *Collection) (*collectionSpec, error) {
+	spec := &collectionSpec{
+		Name:             c.Name,
+		ValidationLevel:  c.ValidationLevel,
+		ValidationAction: c.ValidationAction,
+		Capped:           c.Capped,
+		Size:             c.Size,
+		Max:              c.Max,
+	}
+	if len(c.Validator) > 0 {
+		b, err := json.Marshal(c.Validator)
+		if err != nil {
+			return nil, err
+		}
+		spec.Validator = string(b)
+	}
+	for _, idx := range c.Indexes {
+		is := &indexSpec{Name: idx.Name, Unique: idx.Unique, Sparse: idx.Sparse}
+		if len(idx.PartialFilter) > 0 {
+			b, err := json.Marshal(idx.PartialFilter)
+			if err != nil {
+				return nil, err
+			}
+			is.PartialFilter = string(b)
+		}
+		for _, k := range idx.Keys {
+			is.Keys = append(is.Keys, &keySpec{Field: k.Field, Desc: k.Desc, Type: k.Type})
+		}
+		if idx.ExpireAfterSeconds != nil {
+			is.Extra.Attrs = append(is.Extra.Attrs, schemahcl.Int64Attr("expire_after_seconds", *idx.ExpireAfterSeconds))
+		}
+		spec.Indexes = append(spec.Indexes, is)
+	}
+	return spec, nil
+}
+
+// validate checks that the collections and indexes of the database are named and unique.
+func validate(db *Database) error {
+	names := make(map[string]bool, len(db.Collections))
+	for _, c := range db.Collections {
+		switch {
+		case c.Name == "":
+			return fmt.Errorf("mongodb: missing collection name")
+		case names[c.Name]:
+			return fmt.Errorf("mongodb: collection %q was defined more than once", c.Name)
+		}
+		names[c.Name] = true
+		indexes := make(map[string]bool, len(c.Indexes))
+		for _, idx := range c.Indexes {
+			switch {
+			case idx.Name == "":
+				return fmt.Errorf("mongodb: collection %q: missing index name", c.Name)
+			case indexes[idx.Name]:
+				return fmt.Errorf("mongodb: collection %q: index %q was defined more than once", c.Name, idx.Name)
+			case len(idx.Keys) == 0:
+				return fmt.Errorf("mongodb: collection %q: index %q has no keys", c.Name, idx.Name)
+			}
+			indexes[idx.Name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalHCLBytes(t *testing.T) {
+	var db Database
+	err := EvalHCLBytes([]byte(`
+collection "users" {
+  validator = jsonencode({
+    "$jsonSchema" = {
+      bsonType = "object"
+      required = ["email"]
+    }
+  })
+  validation_action = "warn"
+  index "users_email" {
+    unique         = true
+    partial_filter = jsonencode({ active = true })
+    on {
+      field = "email"
+    }
+    on {
+      field = "created_at"
+      desc  = true
+    }
+  }
+  index "users_seen" {
+    expire_after_seconds = 3600
+    on {
+      field = "seen"
+    }
+  }
+}
+
+collection "events" {
+  capped = true
+  size   = 4096
+}
+`), &db, nil)
+	require.NoError(t, err)
+	ttl := int64(3600)
+	require.Equal(t, []*Collection{
+		{
+			Name:             "users",
+			Validator:        map[string]any{"$jsonSchema": map[string]any{"bsonType": "object", "required": []any{"email"}}},
+			ValidationAction: "warn",
+			Indexes: []*Index{
+				{Name: "users_email", Keys: []*Key{{Field: "email"}, {Field: "created_at", Desc: true}}, Unique: true, PartialFilter: map[string]any{"active": true}},
+				{Name: "users_seen", Keys: []*Key{{Field: "seen"}}, ExpireAfterSeconds: &ttl},
+			},
+		},
+		{Name: "events", Capped: true, Size: 4096},
+	}, db.Collections)
+
+	buf, err := MarshalHCL(&db)
+	require.NoError(t, err)
+	var got Database
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, db.Collections, got.Collections)
+
+	err = EvalHCLBytes([]byte(`
+collection "users" {
+  index "users_email" {}
+}
+`), &Database{}, nil)
+	require.EqualError(t, err, `mongodb: collection "users": index "users_email" has no keys`)
+}
+
+func TestEvalJSONBytes(t *testing.T) {
+	var db Database
+	err := EvalJSONBytes([]byte(`{"collections": [{"name": "users", "validator": {"$jsonSchema": {"bsonType": "object"}}, "indexes": [{"name": "users_bio", "keys": [{"field": "bio", "type": "text"}]}]}]}`), &db)
+	require.NoError(t, err)
+	require.Equal(t, []*Collection{
+		{
+			Name:      "users",
+			Validator: map[string]any{"$jsonSchema": map[string]any{"bsonType": "object"}},
+			Indexes:   []*Index{{Name: "users_bio", Keys: []*Key{{Field: "bio", Type: "text"}}}},
+		},
+	}, db.Collections)
+
+	err = EvalJSONBytes([]byte(`{"collections": [{"name": "users"}, {"name": "users"}]}`), &Database{})
+	require.EqualError(t, err, `mongodb: collection "users" was defined more than once`)
+}
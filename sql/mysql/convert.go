@@ -103,6 +103,13 @@ func FormatType(t schema.Type) (string, error) {
 		f = strings.ToLower(t.T)
 	case *NetworkType:
 		f = strings.ToLower(t.T)
+	case *VectorType:
+		f = strings.ToLower(t.T)
+		// The default number of dimensions in MySQL is DefaultVectorSize,
+		// but MariaDB requires it to be explicitly set.
+		if t.Size > 0 {
+			f = fmt.Sprintf("%s(%d)", f, t.Size)
+		}
 	case *schema.UnsupportedType:
 		// Do not accept unsupported types as we should cover all cases.
 		return "", fmt.Errorf("unsupported type %q", t.T)
@@ -250,6 +257,11 @@ func ParseType(raw string) (schema.Type, error) {
 		return &NetworkType{
 			T: t,
 		}, nil
+	case TypeVector:
+		return &VectorType{
+			T:    t,
+			Size: size,
+		}, nil
 	default:
 		return &schema.UnsupportedType{
 			T: t,
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,7 +37,8 @@ type diff struct {
 // SupportChange reports if the change is supported by the differ.
 func (*diff) SupportChange(c schema.Change) bool {
 	switch c.(type) {
-	case *schema.RenameConstraint:
+	case *schema.RenameConstraint,
+		*schema.AddTrigger, *schema.AddView, *schema.AddFunc, *schema.AddProc:
 		return false
 	}
 	return true
@@ -83,11 +85,17 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
 		changes = append(changes, change)
 	}
-	if change := d.charsetChange(from.Attrs, from.Schema.Attrs, to.Attrs); change != noChange {
-		changes = append(changes, change)
-	}
-	if change := d.collationChange(from.Attrs, from.Schema.Attrs, to.Attrs); change != noChange {
-		changes = append(changes, change)
+	charset, collate := d.charsetChange(from.Attrs, from.Schema.Attrs, to.Attrs), d.collationChange(from.Attrs, from.Schema.Attrs, to.Attrs)
+	switch c, ok := tableConvert(from, to, charset, collate); {
+	case ok:
+		changes = append(changes, c)
+	default:
+		if charset != noChange {
+			changes = append(changes, charset)
+		}
+		if collate != noChange {
+			changes = append(changes, collate)
+		}
 	}
 	if change := d.engineChange(from.Attrs, to.Attrs); change != noChange {
 		changes = append(changes, change)
@@ -103,7 +111,7 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	// also cannot be dropped using "DROP CONSTRAINTS", but can be modified and dropped
 	// using "MODIFY COLUMN".
 	var checks []schema.Change
-	for _, c := range sqlx.CheckDiffMode(from, to, opts.Mode, func(c1, c2 *schema.Check) bool {
+	for _, c := range sqlx.CheckDiffModeFunc(from, to, opts.Mode, normalizeCheck, func(c1, c2 *schema.Check) bool {
 		return enforced(c1.Attrs) == enforced(c2.Attrs)
 	}) {
 		drop, ok := c.(*schema.DropCheck)
@@ -167,6 +175,70 @@ func (d *diff) ColumnChange(fromT *schema.Table, from, to *schema.Column, _ *sch
 	}, nil
 }
 
+// AnnotateChanges implements the sqlx.ChangesAnnotator interface. It drops column
+// modifications that are covered by a table character-set conversion, as the
+// "CONVERT TO CHARACTER SET" clause rewrites all character columns of the table.
+func (d *diff) AnnotateChanges(changes []schema.Change, _ *schema.DiffOptions) ([]schema.Change, error) {
+	// TableDiff returns the changes of the table unwrapped,
+	// whereas SchemaDiff and RealmDiff nest them in ModifyTable.
+	changes, err := d.skipConverted(changes)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		if m, ok := c.(*schema.ModifyTable); ok {
+			if m.Changes, err = d.skipConverted(m.Changes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// skipConverted drops the column charset and collation modifications from
+// the table changes in case they are covered by a ConvertCharset change.
+func (d *diff) skipConverted(changes []schema.Change) ([]schema.Change, error) {
+	i := slices.IndexFunc(changes, func(c schema.Change) bool {
+		_, ok := c.(*ConvertCharset)
+		return ok
+	})
+	if i == -1 {
+		return changes, nil
+	}
+	convert := changes[i].(*ConvertCharset)
+	collate, err := d.convertCollate(convert)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(changes, func(c schema.Change) bool {
+		m, ok := c.(*schema.ModifyColumn)
+		if !ok || m.Change&^(schema.ChangeCharset|schema.ChangeCollate) != schema.NoChange {
+			return false
+		}
+		var (
+			cs schema.Charset
+			co schema.Collation
+		)
+		return (!sqlx.Has(m.To.Attrs, &cs) || cs.V == convert.To.V) &&
+			(!sqlx.Has(m.To.Attrs, &co) || co.V == collate)
+	}), nil
+}
+
+// convertCollate returns the collation that is used by the character columns
+// after the conversion. That is, the explicit one, or the charset default.
+func (d *diff) convertCollate(c *ConvertCharset) (string, error) {
+	if c.ToCollate != nil {
+		return c.ToCollate.V, nil
+	}
+	d.ch2co.Do(func() {
+		d.ch2co.v, d.ch2co.err = d.CharsetToCollate(d.ExecQuerier)
+	})
+	if d.ch2co.err != nil {
+		return "", d.ch2co.err
+	}
+	return d.ch2co.v[c.To.V], nil
+}
+
 // IsGeneratedIndexName reports if the index name was generated by the database.
 func (d *diff) IsGeneratedIndexName(_ *schema.Table, idx *schema.Index) bool {
 	// Auto-generated index names for functional/expression indexes. See.
@@ -204,7 +276,42 @@ func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 		fromP, toP     IndexParser
 		fromHas, toHas = sqlx.Has(from, &fromP), sqlx.Has(to, &toP)
 	)
-	return fromHas != toHas || (fromHas && fromP.P != toP.P)
+	if fromHas != toHas || (fromHas && fromP.P != toP.P) {
+		return true
+	}
+	// Similar to FULLTEXT options below, VECTOR options that were not
+	// set on the desired state are the server defaults, and rebuilding
+	// the index in order to reset them is too expensive.
+	var fromV, toV VectorOptions
+	sqlx.Has(from, &fromV)
+	if sqlx.Has(to, &toV) && (toV.M != 0 && toV.M != fromV.M || toV.Distance != "" && !strings.EqualFold(toV.Distance, fromV.Distance)) {
+		return true
+	}
+	// Stopword and ngram options are applied on FULLTEXT indexes when they are
+	// created. Options that were not set on the desired state are inherited from
+	// the server (or session), and therefore, are not compared.
+	var fromO, toO FullTextOptions
+	sqlx.Has(from, &fromO)
+	return sqlx.Has(to, &toO) &&
+		(toO.NoStopwords && !fromO.NoStopwords ||
+			toO.StopwordTable != "" && toO.StopwordTable != fromO.StopwordTable ||
+			toO.NgramTokenSize != 0 && toO.NgramTokenSize != ngramTokenSize(fromO))
+}
+
+// vectorSize returns the number of dimensions of the VECTOR type.
+func vectorSize(t *VectorType) int {
+	if t.Size == 0 {
+		return DefaultVectorSize
+	}
+	return t.Size
+}
+
+// ngramTokenSize returns the ngram token size of the FULLTEXT options.
+func ngramTokenSize(o FullTextOptions) int {
+	if o.NgramTokenSize == 0 {
+		return DefaultNgramTokenSize
+	}
+	return o.NgramTokenSize
 }
 
 // IndexPartAttrChanged reports if the index-part attributes (collation or prefix) were changed.
@@ -269,37 +376,33 @@ func (d *diff) Normalize(from, to *schema.Table, opts *schema.DiffOptions) error
 }
 
 // FindTable implements the DiffDriver.TableFinder method in order to provide
-// tables lookup that respect the "lower_case_table_names" system variable.
+// tables lookup that respect the identifier comparison mode of the driver, which
+// defaults to the "lower_case_table_names" system variable.
 func (d *diff) FindTable(s *schema.Schema, t1 *schema.Table) (*schema.Table, error) {
-	switch d.lcnames {
-	// In mode 0: tables are stored as specified, and comparisons are case-sensitive.
-	case 0:
+	fold, err := d.foldIdents()
+	if err != nil {
+		return nil, err
+	}
+	if !fold {
 		t2, ok := s.Table(t1.Name)
 		if !ok {
 			return nil, &schema.NotExistError{Err: fmt.Errorf("table %q was not found", t1.Name)}
 		}
 		return t2, nil
-	// In mode 1: the table are stored in lowercase, but they are still
-	// returned on inspection, because comparisons are not case-sensitive.
-	// In mode 2: the tables are stored as given but compared in lowercase.
-	// This option is not supported by Linux-based systems.
-	case 1, 2:
-		var matches []*schema.Table
-		for _, t2 := range s.Tables {
-			if strings.ToLower(t2.Name) == strings.ToLower(t1.Name) {
-				matches = append(matches, t2)
-			}
-		}
-		switch n := len(matches); n {
-		case 0:
-			return nil, &schema.NotExistError{Err: fmt.Errorf("table %q was not found", t1.Name)}
-		case 1:
-			return matches[0], nil
-		default:
-			return nil, fmt.Errorf("%d matches found for table %q", n, t1.Name)
+	}
+	var matches []*schema.Table
+	for _, t2 := range s.Tables {
+		if strings.EqualFold(t2.Name, t1.Name) {
+			matches = append(matches, t2)
 		}
+	}
+	switch n := len(matches); n {
+	case 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("table %q was not found", t1.Name)}
+	case 1:
+		return matches[0], nil
 	default:
-		return nil, fmt.Errorf("unsupported 'lower_case_table_names' mode: %d", d.lcnames)
+		return nil, fmt.Errorf("%d matches found for table %q", n, t1.Name)
 	}
 }
 
@@ -364,16 +467,41 @@ func (*diff) engineChange(from, to []schema.Attr) schema.Change {
 // systemVerChange returns the schema change for migrating the system versioning
 // attributes if it was changed.
 func (d *diff) systemVerChange(from, to []schema.Attr) schema.Change {
-	switch fromHas, toHas := sqlx.Has(from, &SystemVersioned{}), sqlx.Has(to, &SystemVersioned{}); {
+	var fromV, toV SystemVersioned
+	switch fromHas, toHas := sqlx.Has(from, &fromV), sqlx.Has(to, &toV); {
 	case fromHas && !toHas:
-		return &schema.DropAttr{A: &SystemVersioned{}}
+		return &schema.DropAttr{A: &fromV}
 	case !fromHas && toHas:
-		return &schema.AddAttr{A: &SystemVersioned{}}
+		return &schema.AddAttr{A: &toV}
+	case fromHas && toHas && (periodChanged(&fromV, &toV) || partitionChanged(fromV.Partition, toV.Partition)):
+		return &schema.ModifyAttr{From: &fromV, To: &toV}
 	default:
 		return noChange
 	}
 }
 
+// periodChanged reports if the period columns of a system-versioned table were changed.
+// Implicit period columns (i.e., not defined by the desired state) are ignored.
+func periodChanged(from, to *SystemVersioned) bool {
+	return to.Start != "" && (from.Start != to.Start || from.End != to.End)
+}
+
+// partitionChanged reports if the SYSTEM_TIME partitioning of a system-versioned table was changed.
+func partitionChanged(from, to *SystemTimePartition) bool {
+	switch {
+	case from == nil || to == nil:
+		return from != to
+	// The starting point of the interval is
+	// set by the server in case it is missing.
+	case to.Starts != "" && from.Starts != to.Starts:
+		return true
+	default:
+		return !strings.EqualFold(from.Interval, to.Interval) || from.Limit != to.Limit || from.Auto != to.Auto ||
+			// Partitions are added by the server in AUTO mode.
+			to.Partitions != 0 && !to.Auto && from.Partitions != to.Partitions
+	}
+}
+
 // charsetChange returns the schema change for migrating the collation if
 // it was changed, and it is not the default attribute inherited from its parent.
 func (*diff) charsetChange(from, top, to []schema.Attr) schema.Change {
@@ -403,6 +531,41 @@ func (*diff) charsetChange(from, top, to []schema.Attr) schema.Change {
 	return noChange
 }
 
+// tableConvert returns a ConvertCharset change in case the table character-set was
+// changed, and its existing character columns inherit it from the table. Note, changing
+// only the default CHARSET of the table does not affect existing columns. Hence, they
+// are expected to be converted along with the table to match the desired state.
+func tableConvert(from, to *schema.Table, charset, collate schema.Change) (*ConvertCharset, bool) {
+	m, ok := charset.(*schema.ModifyAttr)
+	if !ok {
+		return nil, false
+	}
+	c := &ConvertCharset{From: m.From.(*schema.Charset), To: m.To.(*schema.Charset)}
+	if m, ok := collate.(*schema.ModifyAttr); ok {
+		c.FromCollate, c.ToCollate = m.From.(*schema.Collation), m.To.(*schema.Collation)
+	}
+	var inherit bool
+	for _, c1 := range from.Columns {
+		if c1.Type == nil || !supportsCharset(c1.Type.Type) {
+			continue
+		}
+		c2, ok := to.Column(c1.Name)
+		if !ok {
+			continue
+		}
+		var cs1, cs2 schema.Charset
+		switch has1, has2 := sqlx.Has(c1.Attrs, &cs1), sqlx.Has(c2.Attrs, &cs2); {
+		// The conversion affects all character columns. Hence, it cannot be
+		// used in case a column is expected to keep a different charset.
+		case has2 && cs2.V != c.To.V:
+			return nil, false
+		case !has1 || cs1.V == c.From.V:
+			inherit = true
+		}
+	}
+	return c, inherit
+}
+
 // columnCharsetChange indicates if there is a change to the column charset.
 func (d *diff) columnCharsetChanged(fromT *schema.Table, from, to *schema.Column) (bool, error) {
 	if err := d.defaultCharset(&to.Attrs); err != nil {
@@ -487,6 +650,86 @@ func enforced(attr []schema.Attr) bool {
 // noChange describes a zero change.
 var noChange struct{ schema.Change }
 
+// TypeConversion implements the sqlx.ConversionClassifier interface.
+func (*diff) TypeConversion(from, to schema.Type) schema.TypeConversion {
+	return TypeConversion(from, to)
+}
+
+// TypeConversion classifies the conversion of column values from one MySQL type to
+// another. For example, changing INT to BIGINT is safe, VARCHAR(255) to VARCHAR(50)
+// is lossy, and JSON to INT is incompatible.
+func TypeConversion(from, to schema.Type) schema.TypeConversion {
+	if f, ok := from.(*schema.TimeType); ok && strings.EqualFold(f.T, TypeDateTime) {
+		// TIMESTAMP values are limited to the range of 1970 to 2038.
+		if t, ok := to.(*schema.TimeType); ok && strings.EqualFold(t.T, TypeTimestamp) {
+			return schema.ConversionLossy
+		}
+	}
+	return sqlx.ClassifyConversion(from, to, typeSize)
+}
+
+// typeSize returns the size of the type, as expected by sqlx.ClassifyConversion.
+func typeSize(t schema.Type) int64 {
+	name := func(t string) string {
+		t, _, _ = strings.Cut(strings.ToLower(t), "(")
+		return strings.TrimSpace(t)
+	}
+	switch t := t.(type) {
+	case *schema.IntegerType:
+		switch name(t.T) {
+		case TypeTinyInt:
+			return 1
+		case TypeSmallInt:
+			return 2
+		case TypeMediumInt:
+			return 3
+		case TypeInt, "integer":
+			return 4
+		case TypeBigInt:
+			return 8
+		}
+	case *schema.FloatType:
+		switch name(t.T) {
+		case TypeFloat:
+			if t.Precision > 24 {
+				return 8
+			}
+			return 4
+		case TypeDouble, TypeReal:
+			return 8
+		}
+	case *schema.StringType:
+		switch name(t.T) {
+		case TypeChar, TypeVarchar:
+			return int64(t.Size)
+		case TypeTinyText:
+			return 1<<8 - 1
+		case TypeText:
+			return 1<<16 - 1
+		case TypeMediumText:
+			return 1<<24 - 1
+		case TypeLongText:
+			return 1<<32 - 1
+		}
+	case *schema.BinaryType:
+		switch name(t.T) {
+		case TypeBinary, TypeVarBinary:
+			if t.Size != nil {
+				return int64(*t.Size)
+			}
+		case TypeTinyBlob:
+			return 1<<8 - 1
+		case TypeBlob:
+			return 1<<16 - 1
+		case TypeMediumBlob:
+			return 1<<24 - 1
+		case TypeLongBlob:
+			return 1<<32 - 1
+		}
+	}
+	return 0
+}
+
 func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	fromT, toT := from.Type.Type, to.Type.Type
 	if fromT == nil || toT == nil {
@@ -508,6 +751,9 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 			return false, err
 		}
 		changed = ft != tt
+	case *VectorType:
+		toT := toT.(*VectorType)
+		changed = !strings.EqualFold(fromT.T, toT.T) || vectorSize(fromT) != vectorSize(toT)
 	case *schema.EnumType:
 		toT := toT.(*schema.EnumType)
 		changed = !sqlx.ValuesEqual(fromT.Values, toT.Values)
@@ -733,3 +979,445 @@ func (d *diff) defaultCharset(attrs *[]schema.Attr) error {
 	return nil
 }
 
+// normalizeCheck returns the normalized form of a CHECK expression. It is used for
+// comparing the expressions defined by the user with the ones stored by the database,
+// as MySQL reformats the expressions. For example, "a>0.50 AND b<>\"x\"" is stored as
+// "((`a` > 0.50) and (`b` <> _utf8mb4'x'))", and both are normalized to "a > 0.5 and b <> 'x'".
+//
+// Identifiers, keywords and function names are lowercased, charset introducers are dropped,
+// string literals are single-quoted, numeric literals are trimmed, and parentheses are kept
+// only where they are required by operator precedence. Expressions that cannot be parsed
+// are normalized only at the token level.
+func normalizeCheck(x string) string {
+	toks, ok := checkTokens(x)
+	if !ok {
+		return sqlx.MayWrap(x)
+	}
+	p := &checkParser{toks: toks}
+	if n, _, ok := p.expr(0); ok && p.pos == len(toks) {
+		return n
+	}
+	var b strings.Builder
+	for i, t := range toks {
+		if i > 0 && t.v != ")" && t.v != "," && toks[i-1].v != "(" && (t.v != "(" || toks[i-1].k != tokWord) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.v)
+	}
+	return b.String()
+}
+
+// Token kinds of CHECK expressions.
+const (
+	tokWord   = iota // Keywords and unquoted identifiers.
+	tokIdent         // Quoted identifiers.
+	tokString        // String literals.
+	tokNumber        // Numeric literals.
+	tokOp            // Operators and punctuation.
+)
+
+type checkToken struct {
+	k int
+	v string
+}
+
+// checkOps holds the operators of CHECK expressions, ordered by length,
+// and the normalized form of the ones that have a synonym.
+var checkOps = []struct{ op, norm string }{
+	{"<=>", "<=>"}, {"->>", "->>"}, {"<=", "<="}, {">=", ">="}, {"<>", "<>"}, {"!=", "<>"},
+	{"<<", "<<"}, {">>", ">>"}, {"&&", "and"}, {"||", "or"}, {"->", "->"}, {":=", ":="},
+	{"=", "="}, {"<", "<"}, {">", ">"}, {"!", "!"}, {"+", "+"}, {"-", "-"}, {"*", "*"}, {"/", "/"},
+	{"%", "%"}, {"^", "^"}, {"&", "&"}, {"|", "|"}, {"~", "~"}, {"(", "("}, {")", ")"}, {",", ","}, {".", "."},
+}
+
+// checkTokens splits the CHECK expression into normalized tokens.
+func checkTokens(x string) ([]checkToken, bool) {
+	var toks []checkToken
+	for i := 0; i < len(x); {
+		switch c := x[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			s, n, ok := scanCheckString(x[i:])
+			if !ok {
+				return nil, false
+			}
+			toks = append(toks, checkToken{k: tokString, v: s})
+			i += n
+		case c == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(x) && (x[j] != '`' || j+1 < len(x) && x[j+1] == '`'); j++ {
+				if x[j] == '`' {
+					j++
+				}
+				b.WriteByte(x[j])
+			}
+			if j == len(x) {
+				return nil, false
+			}
+			toks = append(toks, checkToken{k: tokIdent, v: strings.ToLower(b.String())})
+			i = j + 1
+		case isDigit(c) || c == '.' && i+1 < len(x) && isDigit(x[i+1]):
+			j := i
+			for j < len(x) && (isWordChar(x[j]) || x[j] == '.' || (x[j] == '+' || x[j] == '-') && (x[j-1] == 'e' || x[j-1] == 'E') && !strings.ContainsAny(x[i:j], "xX")) {
+				j++
+			}
+			n, ok := normalizeNumber(x[i:j])
+			if !ok {
+				return nil, false
+			}
+			toks = append(toks, checkToken{k: tokNumber, v: n})
+			i = j
+		case isWordChar(c):
+			j := i
+			for j < len(x) && isWordChar(x[j]) {
+				j++
+			}
+			switch w := strings.ToLower(x[i:j]); {
+			// Charset introducers, e.g., _utf8mb4'a'.
+			case w[0] == '_' && j < len(x) && (x[j] == '\'' || x[j] == '"'):
+			// Hexadecimal and bit-value literals, e.g., X'1F' or b'01'.
+			case (w == "x" || w == "b") && j < len(x) && x[j] == '\'':
+				s, n, ok := scanCheckString(x[j:])
+				if !ok {
+					return nil, false
+				}
+				toks = append(toks, checkToken{k: tokNumber, v: w + strings.ToLower(s)})
+				j += n
+			default:
+				toks = append(toks, checkToken{k: tokWord, v: w})
+			}
+			i = j
+		default:
+			idx := slices.IndexFunc(checkOps, func(o struct{ op, norm string }) bool {
+				return strings.HasPrefix(x[i:], o.op)
+			})
+			if idx == -1 {
+				return nil, false
+			}
+			k := tokOp
+			if o := checkOps[idx]; o.norm == "and" || o.norm == "or" {
+				k = tokWord
+			}
+			toks = append(toks, checkToken{k: k, v: checkOps[idx].norm})
+			i += len(checkOps[idx].op)
+		}
+	}
+	return toks, true
+}
+
+// scanCheckString scans a quoted string from the beginning of s and returns its
+// single-quoted form, and the number of bytes scanned. Escape sequences that are
+// not related to quoting are kept as is.
+func scanCheckString(s string) (string, int, bool) {
+	var (
+		b strings.Builder
+		q = s[0]
+	)
+	b.WriteByte('\'')
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			if i++; s[i] == '\'' || s[i] == '"' {
+				c = s[i]
+			} else {
+				b.WriteByte('\\')
+				c = s[i]
+			}
+			if c == '\'' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		case c == q && i+1 < len(s) && s[i+1] == q:
+			i++
+			if c == '\'' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		case c == q:
+			b.WriteByte('\'')
+			return b.String(), i + 1, true
+		case c == '\'':
+			b.WriteString("\\'")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, false
+}
+
+// normalizeNumber trims the leading zeros of the integer part,
+// and the trailing zeros of the fractional part of a number.
+func normalizeNumber(n string) (string, bool) {
+	n = strings.ToLower(n)
+	if strings.HasPrefix(n, "0x") {
+		return n, len(n) > 2
+	}
+	var exp string
+	if i := strings.IndexByte(n, 'e'); i != -1 {
+		n, exp = n[:i], strings.TrimPrefix(n[i+1:], "+")
+		neg := strings.HasPrefix(exp, "-")
+		if exp = strings.TrimLeft(strings.TrimPrefix(exp, "-"), "0"); exp == "" {
+			exp = "0"
+		}
+		if neg {
+			exp = "-" + exp
+		}
+		exp = "e" + exp
+	}
+	i, f, _ := strings.Cut(n, ".")
+	if strings.Trim(i+f, "0123456789") != "" || i+f == "" {
+		return "", false
+	}
+	if i = strings.TrimLeft(i, "0"); i == "" {
+		i = "0"
+	}
+	if f = strings.TrimRight(f, "0"); f != "" {
+		i += "." + f
+	}
+	return i + exp, true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// Precedence of CHECK expression operators, from lowest to highest.
+// See: https://dev.mysql.com/doc/refman/8.0/en/operator-precedence.html
+const (
+	precOr = iota + 1
+	precXor
+	precAnd
+	precNot
+	precCompare
+	precBitOr
+	precBitAnd
+	precShift
+	precAdd
+	precMul
+	precBitXor
+	precUnary
+	precAtom
+)
+
+var checkBinaryOps = map[string]int{
+	"or": precOr, "xor": precXor, "and": precAnd,
+	"=": precCompare, "<=>": precCompare, ">=": precCompare, ">": precCompare, "<=": precCompare, "<": precCompare, "<>": precCompare,
+	"is": precCompare, "like": precCompare, "not like": precCompare, "regexp": precCompare, "not regexp": precCompare,
+	"in": precCompare, "not in": precCompare, "between": precCompare, "not between": precCompare,
+	"|": precBitOr, "&": precBitAnd, "<<": precShift, ">>": precShift, "+": precAdd, "-": precAdd,
+	"*": precMul, "/": precMul, "div": precMul, "%": precMul, "^": precBitXor,
+}
+
+// checkKeywords that cannot be used as an operand.
+var checkKeywords = map[string]bool{
+	"and": true, "or": true, "xor": true, "not": true, "is": true, "in": true, "like": true, "regexp": true,
+	"rlike": true, "between": true, "div": true, "mod": true, "case": true, "when": true, "then": true,
+	"else": true, "end": true, "interval": true, "collate": true, "binary": true, "exists": true, "select": true,
+}
+
+// checkParser is a minimal precedence-climbing parser for CHECK expressions that
+// prints the parsed expression back with the minimal number of parentheses.
+type checkParser struct {
+	toks []checkToken
+	pos  int
+}
+
+// expr parses an expression with operators of precedence higher than or equal to min,
+// and returns its normalized form and the precedence of its top-level operator.
+func (p *checkParser) expr(min int) (string, int, bool) {
+	x, xp, ok := p.unary()
+	if !ok {
+		return "", 0, false
+	}
+	for {
+		op, n := p.binaryOp()
+		prec, ok := checkBinaryOps[op]
+		if !ok || prec < min {
+			return x, xp, true
+		}
+		p.pos += n
+		// Negated operators are printed as "NOT (x op y)", as MySQL stores them.
+		base, neg := strings.CutPrefix(op, "not ")
+		x = paren(x, xp, prec) + " " + base + " "
+		switch base {
+		case "is":
+			if p.word("not") {
+				x += "not "
+			}
+			t, ok := p.next()
+			if !ok || t.k != tokWord || t.v != "null" && t.v != "true" && t.v != "false" && t.v != "unknown" {
+				return "", 0, false
+			}
+			x += t.v
+		case "in":
+			l, ok := p.list()
+			if !ok {
+				return "", 0, false
+			}
+			x += l
+		case "between":
+			lo, lp, ok := p.expr(prec + 1)
+			if !ok || !p.word("and") {
+				return "", 0, false
+			}
+			hi, hp, ok := p.expr(prec + 1)
+			if !ok {
+				return "", 0, false
+			}
+			x += paren(lo, lp, prec+1) + " and " + paren(hi, hp, prec+1)
+		default:
+			y, yp, ok := p.expr(prec + 1)
+			if !ok {
+				return "", 0, false
+			}
+			x += paren(y, yp, prec+1)
+		}
+		if xp = prec; neg {
+			x, xp = "not "+x, precNot
+		}
+	}
+}
+
+// unary parses an operand, optionally prefixed with a unary operator.
+func (p *checkParser) unary() (string, int, bool) {
+	t, ok := p.next()
+	if !ok {
+		return "", 0, false
+	}
+	switch {
+	case t.k == tokWord && t.v == "not":
+		x, xp, ok := p.expr(precNot)
+		return "not " + paren(x, xp, precNot), precNot, ok
+	case t.k == tokOp && (t.v == "-" || t.v == "+" || t.v == "~" || t.v == "!"):
+		x, xp, ok := p.expr(precUnary)
+		if t.v == "+" {
+			return x, xp, ok
+		}
+		return t.v + paren(x, xp, precUnary), precUnary, ok
+	case t.k == tokOp && t.v == "(":
+		x, xp, ok := p.expr(0)
+		if !ok {
+			return "", 0, false
+		}
+		// Row constructor, e.g., (a, b).
+		if p.op(",") {
+			p.pos--
+			l, ok := p.listTail(x)
+			return l, precAtom, ok
+		}
+		return x, xp, p.op(")")
+	case t.k == tokString, t.k == tokNumber, t.k == tokIdent:
+		return t.v, precAtom, true
+	case t.k == tokWord && !checkKeywords[t.v]:
+		x := t.v
+		// Qualified names, e.g., t.c.
+		for p.op(".") {
+			if t, ok = p.next(); !ok || t.k != tokWord && t.k != tokIdent {
+				return "", 0, false
+			}
+			x += "." + t.v
+		}
+		if p.op("(") {
+			p.pos--
+			l, ok := p.list()
+			return x + l, precAtom, ok
+		}
+		return x, precAtom, true
+	}
+	return "", 0, false
+}
+
+// binaryOp returns the binary operator at the current position,
+// and the number of tokens it spans.
+func (p *checkParser) binaryOp() (string, int) {
+	if p.pos >= len(p.toks) {
+		return "", 0
+	}
+	t := p.toks[p.pos]
+	switch {
+	case t.k == tokOp:
+		return t.v, 1
+	case t.k != tokWord:
+		return "", 0
+	case t.v == "not" && p.pos+1 < len(p.toks):
+		switch n := p.toks[p.pos+1]; n.v {
+		case "in", "like", "between", "regexp":
+			return "not " + n.v, 2
+		case "rlike":
+			return "not regexp", 2
+		}
+	case t.v == "rlike":
+		return "regexp", 1
+	case t.v == "mod":
+		return "%", 1
+	}
+	return t.v, 1
+}
+
+// list parses a parenthesized list of expressions. e.g., arguments or IN values.
+func (p *checkParser) list() (string, bool) {
+	if !p.op("(") {
+		return "", false
+	}
+	if p.op(")") {
+		return "()", true
+	}
+	x, _, ok := p.expr(0)
+	if !ok {
+		return "", false
+	}
+	return p.listTail(x)
+}
+
+// listTail parses the rest of a list that starts with x.
+func (p *checkParser) listTail(x string) (string, bool) {
+	items := []string{x}
+	for p.op(",") {
+		x, _, ok := p.expr(0)
+		if !ok {
+			return "", false
+		}
+		items = append(items, x)
+	}
+	return "(" + strings.Join(items, ", ") + ")", p.op(")")
+}
+
+func (p *checkParser) next() (checkToken, bool) {
+	if p.pos >= len(p.toks) {
+		return checkToken{}, false
+	}
+	p.pos++
+	return p.toks[p.pos-1], true
+}
+
+// op reports if the current token is the given operator, and consumes it.
+func (p *checkParser) op(v string) bool {
+	if p.pos < len(p.toks) && p.toks[p.pos].k == tokOp && p.toks[p.pos].v == v {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// word reports if the current token is the given keyword, and consumes it.
+func (p *checkParser) word(v string) bool {
+	if p.pos < len(p.toks) && p.toks[p.pos].k == tokWord && p.toks[p.pos].v == v {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// paren wraps the expression with parentheses if its precedence is lower than min.
+func paren(x string, xp, min int) string {
+	if xp < min {
+		return "(" + x + ")"
+	}
+	return x
+}
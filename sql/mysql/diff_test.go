@@ -7,6 +7,7 @@
 package mysql
 
 import (
+	"regexp"
 	"testing"
 
 	"ariga.io/atlas/sql/schema"
@@ -205,6 +206,25 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "normalized checks",
+			from: &schema.Table{Name: "t1", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{
+				&schema.Check{Name: "t1_chk_1", Expr: "((`c1` > 0.50) and (`c2` <> _utf8mb4'foo'))"},
+				&schema.Check{Name: "t1_chk_2", Expr: "(`c3` in (1,2))"},
+			}},
+			to: &schema.Table{Name: "t1", Attrs: []schema.Attr{
+				&schema.Check{Expr: "c1>0.5 AND c2 != \"foo\""},
+				&schema.Check{Expr: "C3 IN (1, 3)"},
+			}},
+			wantChanges: []schema.Change{
+				&schema.DropCheck{
+					C: &schema.Check{Name: "t1_chk_2", Expr: "(`c3` in (1,2))"},
+				},
+				&schema.AddCheck{
+					C: &schema.Check{Expr: "C3 IN (1, 3)"},
+				},
+			},
+		},
 		{
 			name: "add comment",
 			from: &schema.Table{Name: "t1", Schema: &schema.Schema{Name: "public"}},
@@ -358,6 +378,74 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			}
 		}(),
+		// Table CHARSET was changed, and its columns inherit it.
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8").
+					SetCollation("utf8_general_ci").
+					AddColumns(
+						schema.NewIntColumn("id", "int"),
+						schema.NewStringColumn("c1", "varchar").SetCharset("utf8").SetCollation("utf8_general_ci"),
+						schema.NewStringColumn("c2", "text").SetCharset("utf8").SetCollation("utf8_general_ci"),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8mb4").
+					AddColumns(
+						schema.NewIntColumn("id", "int"),
+						schema.NewStringColumn("c1", "varchar"),
+						schema.NewStringColumn("c2", "text").SetCharset("utf8mb4"),
+					)
+			)
+			return testcase{
+				name: "convert charset",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&ConvertCharset{
+						From:        &schema.Charset{V: "utf8"},
+						To:          &schema.Charset{V: "utf8mb4"},
+						FromCollate: &schema.Collation{V: "utf8_general_ci"},
+						ToCollate:   &schema.Collation{V: "utf8mb4_0900_ai_ci"},
+					},
+				},
+			}
+		}(),
+		// Table CHARSET was changed, but a column keeps its current charset.
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8").
+					SetCollation("utf8_general_ci").
+					AddColumns(
+						schema.NewStringColumn("c1", "varchar").SetCharset("utf8").SetCollation("utf8_general_ci"),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8mb4").
+					AddColumns(
+						schema.NewStringColumn("c1", "varchar").SetCharset("utf8"),
+					)
+			)
+			return testcase{
+				name: "no convert charset",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyAttr{
+						From: &schema.Charset{V: "utf8"},
+						To:   &schema.Charset{V: "utf8mb4"},
+					},
+					&schema.ModifyAttr{
+						From: &schema.Collation{V: "utf8_general_ci"},
+						To:   &schema.Collation{V: "utf8mb4_0900_ai_ci"},
+					},
+				},
+			}
+		}(),
 		// Nop CHARSET change.
 		func() testcase {
 			var (
@@ -521,6 +609,151 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			}
 		}(),
+		func() testcase {
+			from := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewStringColumn("c1", "text"), schema.NewStringColumn("c2", "text"))
+			from.AddIndexes(
+				schema.NewIndex("c1").AddColumns(from.Columns[0]).AddAttrs(&IndexType{T: IndexTypeFullText}, &FullTextOptions{NoStopwords: true}),
+				schema.NewIndex("c2").AddColumns(from.Columns[1]).AddAttrs(&IndexType{T: IndexTypeFullText}, &FullTextOptions{NoStopwords: true}),
+			)
+			to := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewStringColumn("c1", "text"), schema.NewStringColumn("c2", "text"))
+			to.AddIndexes(
+				// Options that are not set on the desired state are not compared.
+				schema.NewIndex("c1").AddColumns(to.Columns[0]).AddAttrs(&IndexType{T: IndexTypeFullText}),
+				schema.NewIndex("c2").AddColumns(to.Columns[1]).AddAttrs(&IndexType{T: IndexTypeFullText}, &FullTextOptions{StopwordTable: "test/stopwords"}),
+			)
+			return testcase{
+				name: "modify fulltext stopwords",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyIndex{
+						From:   from.Indexes[1],
+						To:     to.Indexes[1],
+						Change: schema.ChangeAttr,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			from := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewStringColumn("c1", "text"))
+			from.AddIndexes(schema.NewIndex("c1").AddColumns(from.Columns[0]).AddAttrs(&IndexType{T: IndexTypeFullText}, &IndexParser{P: IndexParserNGram}))
+			to := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(schema.NewStringColumn("c1", "text"))
+			to.AddIndexes(schema.NewIndex("c1").AddColumns(to.Columns[0]).AddAttrs(&IndexType{T: IndexTypeFullText}, &IndexParser{P: IndexParserNGram}, &FullTextOptions{NgramTokenSize: 3}))
+			return testcase{
+				name: "modify ngram token size",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyIndex{
+						From:   from.Indexes[0],
+						To:     to.Indexes[0],
+						Change: schema.ChangeAttr,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			from := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(
+					schema.NewColumn("c1").SetType(&VectorType{T: TypeVector, Size: DefaultVectorSize}),
+					schema.NewColumn("c2").SetType(&VectorType{T: TypeVector, Size: 1536}),
+				)
+			to := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(
+					// The number of dimensions defaults to 2048.
+					schema.NewColumn("c1").SetType(&VectorType{T: TypeVector}),
+					schema.NewColumn("c2").SetType(&VectorType{T: TypeVector, Size: 768}),
+				)
+			return testcase{
+				name: "modify vector size",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{
+						From:   from.Columns[1],
+						To:     to.Columns[1],
+						Change: schema.ChangeType,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			from := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(
+					schema.NewColumn("c1").SetType(&VectorType{T: TypeVector, Size: 3}),
+					schema.NewColumn("c2").SetType(&VectorType{T: TypeVector, Size: 3}),
+				)
+			from.AddIndexes(
+				schema.NewIndex("c1").AddColumns(from.Columns[0]).AddAttrs(&IndexType{T: IndexTypeVector}, &VectorOptions{M: 8, Distance: VectorDistanceCosine}),
+				schema.NewIndex("c2").AddColumns(from.Columns[1]).AddAttrs(&IndexType{T: IndexTypeVector}, &VectorOptions{M: 8}),
+			)
+			to := schema.NewTable("t1").
+				SetSchema(schema.New("test")).
+				AddColumns(
+					schema.NewColumn("c1").SetType(&VectorType{T: TypeVector, Size: 3}),
+					schema.NewColumn("c2").SetType(&VectorType{T: TypeVector, Size: 3}),
+				)
+			to.AddIndexes(
+				// Options that are not set on the desired state are not compared.
+				schema.NewIndex("c1").AddColumns(to.Columns[0]).AddAttrs(&IndexType{T: IndexTypeVector}),
+				schema.NewIndex("c2").AddColumns(to.Columns[1]).AddAttrs(&IndexType{T: IndexTypeVector}, &VectorOptions{Distance: VectorDistanceCosine}),
+			)
+			return testcase{
+				name: "modify vector index options",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyIndex{
+						From:   from.Indexes[1],
+						To:     to.Indexes[1],
+						Change: schema.ChangeAttr,
+					},
+				},
+			}
+		}(),
+		{
+			name: "system versioning partition",
+			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{
+				&SystemVersioned{Start: "start_ts", End: "end_ts", Partition: &SystemTimePartition{Interval: "1 MONTH", Starts: "TIMESTAMP'2024-01-01 00:00:00'", Auto: true, Partitions: 5}},
+			}},
+			to: &schema.Table{Name: "users", Attrs: []schema.Attr{
+				&SystemVersioned{Partition: &SystemTimePartition{Interval: "1 WEEK", Auto: true}},
+			}},
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &SystemVersioned{Start: "start_ts", End: "end_ts", Partition: &SystemTimePartition{Interval: "1 MONTH", Starts: "TIMESTAMP'2024-01-01 00:00:00'", Auto: true, Partitions: 5}},
+					To:   &SystemVersioned{Partition: &SystemTimePartition{Interval: "1 WEEK", Auto: true}},
+				},
+			},
+		},
+		{
+			name: "system versioning no changes",
+			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{
+				&SystemVersioned{Start: "start_ts", End: "end_ts", Partition: &SystemTimePartition{Interval: "1 MONTH", Starts: "TIMESTAMP'2024-01-01 00:00:00'", Auto: true, Partitions: 5}},
+			}},
+			to: &schema.Table{Name: "users", Attrs: []schema.Attr{
+				&SystemVersioned{Partition: &SystemTimePartition{Interval: "1 month", Auto: true, Partitions: 3}},
+			}},
+		},
+		{
+			name: "drop system versioning",
+			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{&SystemVersioned{}}},
+			to:   &schema.Table{Name: "users"},
+			wantChanges: []schema.Change{
+				&schema.DropAttr{A: &SystemVersioned{}},
+			},
+		},
 		func() testcase {
 			from := schema.NewTable("t1").
 				SetSchema(schema.New("test")).
@@ -611,6 +844,31 @@ func TestDiff_TableDiff(t *testing.T) {
 	}
 }
 
+func TestNormalizeCheck(t *testing.T) {
+	for _, tt := range []struct {
+		x1, x2 string
+		equal  bool
+	}{
+		{x1: "a>0.50 AND b<>\"x\"", x2: "((`a` > 0.50) and (`b` <> _utf8mb4'x'))", equal: true},
+		{x1: "a IN ('a', \"b\")", x2: "(`a` in (_utf8mb4'a',_utf8mb4'b'))", equal: true},
+		{x1: "a NOT BETWEEN 1 AND 10 OR b IS NOT NULL", x2: "((not((`a` between 1 and 10))) or (`b` is not null))", equal: true},
+		{x1: "CHAR_LENGTH(name)>0", x2: "(char_length(`name`) > 0)", equal: true},
+		{x1: "a != b && c", x2: "((`a` <> `b`) and `c`)", equal: true},
+		{x1: "a >= 010.0", x2: "(`a` >= 10)", equal: true},
+		{x1: "a < 1.5E+3", x2: "(`a` < 1.5e3)", equal: true},
+		{x1: "a = 'it''s'", x2: "(`a` = _utf8mb4'it\\'s')", equal: true},
+		{x1: "CAST(a AS CHAR)='x'", x2: "cast(`a` as char) = _utf8mb4'x'", equal: true},
+		{x1: "a - (b - c) > 0", x2: "((`a` - `b`) - `c`) > 0"},
+		{x1: "a * (b + c) > 0", x2: "((`a` * `b`) + `c`) > 0"},
+		{x1: "(a > 0 OR b > 0) AND c > 0", x2: "((`a` > 0) or ((`b` > 0) and (`c` > 0)))"},
+		{x1: "a = 'X'", x2: "(`a` = _utf8mb4'x')"},
+		{x1: "a > 0.5", x2: "(`a` > 5)"},
+	} {
+		require.Equal(t, tt.equal, normalizeCheck(tt.x1) == normalizeCheck(tt.x2), "%s, %s", tt.x1, tt.x2)
+	}
+	require.Equal(t, "a > 0.5 and b <> 'x'", normalizeCheck("((`a` > 0.50) and (`b` <> _utf8mb4'x'))"))
+}
+
 func TestDiff_UnsupportedChecks(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -677,6 +935,89 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	}, changes)
 }
 
+func TestDiff_DetectRenames(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("8.0.19")
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	var (
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewStringColumn("name", "varchar(255)"),
+			),
+			schema.NewTable("pets").AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("owner_id", "int"),
+				schema.NewStringColumn("name", "text"),
+			),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewStringColumn("full_name", "varchar(255)"),
+			),
+			schema.NewTable("animals").AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("owner_id", "int"),
+				schema.NewStringColumn("title", "text"),
+			),
+		)
+		cands []string
+	)
+	// Without the option, renames are not detected.
+	changes, err := drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.IsType(t, &schema.DropTable{}, changes[1])
+	require.IsType(t, &schema.AddTable{}, changes[2])
+
+	changes, err = drv.SchemaDiff(from, to, schema.DiffDetectRenames(func(c *schema.RenameCandidate) (bool, error) {
+		cands = append(cands, c.String())
+		return true, nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`rename column "name" to "full_name" on table "users"`,
+		`rename table "pets" to "animals"`,
+		`rename column "name" to "title" on table "animals"`,
+	}, cands)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyTable{T: to.Tables[0], Changes: []schema.Change{
+			&schema.RenameColumn{From: from.Tables[0].Columns[1], To: to.Tables[0].Columns[1]},
+		}},
+		&schema.RenameTable{From: from.Tables[1], To: to.Tables[1]},
+		&schema.ModifyTable{T: to.Tables[1], Changes: []schema.Change{
+			&schema.RenameColumn{From: from.Tables[1].Columns[2], To: to.Tables[1].Columns[2]},
+		}},
+	}, changes)
+
+	// Rejected candidates are kept as drop and add changes.
+	cands = nil
+	changes, err = drv.SchemaDiff(from, to, schema.DiffDetectRenames(func(c *schema.RenameCandidate) (bool, error) {
+		cands = append(cands, c.String())
+		return false, nil
+	}))
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Len(t, cands, 2)
+	require.IsType(t, &schema.DropColumn{}, changes[0].(*schema.ModifyTable).Changes[0])
+	require.IsType(t, &schema.AddColumn{}, changes[0].(*schema.ModifyTable).Changes[1])
+
+	// Columns with different definitions are not considered as renamed.
+	to.Tables[0].Columns[1].Type.Raw = "text"
+	to.Tables[0].Columns[1].Type.Type = &schema.StringType{T: "text"}
+	cands = nil
+	_, err = drv.SchemaDiff(from, to, schema.DiffDetectRenames(func(c *schema.RenameCandidate) (bool, error) {
+		cands = append(cands, c.String())
+		return false, nil
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{`rename table "pets" to "animals"`}, cands)
+}
+
 func TestDiff_LowerCaseMode(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -912,3 +1253,162 @@ func TestSkipChanges(t *testing.T) {
 		require.IsType(t, &schema.DropColumn{}, changes[0].(*schema.ModifyTable).Changes[0])
 	})
 }
+
+func TestTypeConversion(t *testing.T) {
+	for _, tt := range []struct {
+		from, to schema.Type
+		want     schema.TypeConversion
+	}{
+		{&schema.IntegerType{T: TypeInt}, &schema.IntegerType{T: TypeBigInt}, schema.ConversionSafe},
+		{&schema.IntegerType{T: "int(11)"}, &schema.IntegerType{T: TypeBigInt}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeBigInt}, &schema.IntegerType{T: TypeInt}, schema.ConversionLossy},
+		{&schema.IntegerType{T: TypeInt, Unsigned: true}, &schema.IntegerType{T: TypeInt}, schema.ConversionLossy},
+		{&schema.IntegerType{T: TypeInt, Unsigned: true}, &schema.IntegerType{T: TypeBigInt}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeInt}, &schema.IntegerType{T: TypeBigInt, Unsigned: true}, schema.ConversionLossy},
+		{&schema.IntegerType{T: TypeInt}, &schema.DecimalType{T: TypeDecimal, Precision: 10}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeBigInt}, &schema.DecimalType{T: TypeDecimal, Precision: 10}, schema.ConversionLossy},
+		{&schema.IntegerType{T: TypeInt}, &schema.FloatType{T: TypeDouble}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeBigInt}, &schema.FloatType{T: TypeDouble}, schema.ConversionLossy},
+		{&schema.IntegerType{T: TypeInt}, &schema.StringType{T: TypeVarchar, Size: 255}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeInt}, &schema.StringType{T: TypeVarchar, Size: 5}, schema.ConversionLossy},
+		{&schema.StringType{T: TypeVarchar, Size: 50}, &schema.StringType{T: TypeVarchar, Size: 255}, schema.ConversionSafe},
+		{&schema.StringType{T: TypeVarchar, Size: 255}, &schema.StringType{T: TypeVarchar, Size: 50}, schema.ConversionLossy},
+		{&schema.StringType{T: TypeVarchar, Size: 255}, &schema.StringType{T: TypeText}, schema.ConversionSafe},
+		{&schema.StringType{T: TypeLongText}, &schema.StringType{T: TypeText}, schema.ConversionLossy},
+		{&schema.StringType{T: TypeVarchar, Size: 255}, &schema.IntegerType{T: TypeInt}, schema.ConversionIncompatible},
+		{&schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}, &schema.DecimalType{T: TypeDecimal, Precision: 12, Scale: 2}, schema.ConversionSafe},
+		{&schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}, &schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 0}, schema.ConversionLossy},
+		{&schema.FloatType{T: TypeFloat}, &schema.FloatType{T: TypeDouble}, schema.ConversionSafe},
+		{&schema.FloatType{T: TypeDouble}, &schema.FloatType{T: TypeFloat}, schema.ConversionLossy},
+		{&schema.EnumType{Values: []string{"a"}}, &schema.EnumType{Values: []string{"a", "b"}}, schema.ConversionSafe},
+		{&schema.EnumType{Values: []string{"a", "b"}}, &schema.EnumType{Values: []string{"a"}}, schema.ConversionLossy},
+		{&schema.TimeType{T: TypeDate}, &schema.TimeType{T: TypeDateTime}, schema.ConversionSafe},
+		{&schema.TimeType{T: TypeDateTime}, &schema.TimeType{T: TypeDate}, schema.ConversionLossy},
+		{&schema.TimeType{T: TypeTimestamp}, &schema.TimeType{T: TypeDateTime}, schema.ConversionSafe},
+		{&schema.TimeType{T: TypeDateTime}, &schema.TimeType{T: TypeTimestamp}, schema.ConversionLossy},
+		{&schema.TimeType{T: TypeTime}, &schema.TimeType{T: TypeDateTime}, schema.ConversionIncompatible},
+		{&schema.JSONType{T: TypeJSON}, &schema.IntegerType{T: TypeInt}, schema.ConversionIncompatible},
+		{&schema.JSONType{T: TypeJSON}, &schema.StringType{T: TypeVarchar, Size: 255}, schema.ConversionUnknown},
+	} {
+		require.Equal(t, tt.want, TypeConversion(tt.from, tt.to), "%T(%v) -> %T(%v)", tt.from, tt.from, tt.to, tt.to)
+	}
+
+	var (
+		from = schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(
+			schema.NewIntColumn("id", TypeInt),
+			schema.NewStringColumn("name", TypeVarchar, schema.StringSize(255)),
+			schema.NewNullStringColumn("bio", TypeText),
+		)
+		to = schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewStringColumn("name", TypeVarchar, schema.StringSize(50)),
+			schema.NewStringColumn("bio", TypeText),
+		)
+	)
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType, Conversion: schema.ConversionSafe},
+		&schema.ModifyColumn{From: from.Columns[1], To: to.Columns[1], Change: schema.ChangeType, Conversion: schema.ConversionLossy},
+		// Conversion is classified only for type changes.
+		&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeNull},
+	}, changes)
+}
+
+func TestDiff_IgnoreRules(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		from = schema.NewTable("users").
+			SetSchema(s).
+			SetComment("a").
+			SetCharset("latin1").
+			AddAttrs(&AutoIncrement{V: 1}).
+			AddColumns(
+				schema.NewIntColumn("id", TypeInt),
+				schema.NewStringColumn("name", TypeVarchar, schema.StringSize(255)).SetComment("a").SetCharset("latin1"),
+			)
+		to = schema.NewTable("users").
+			SetSchema(s).
+			SetComment("b").
+			SetCharset("utf8mb4").
+			AddAttrs(&AutoIncrement{V: 100}).
+			AddColumns(
+				schema.NewIntColumn("id", TypeInt),
+				schema.NewStringColumn("name", TypeVarchar, schema.StringSize(255)).SetComment("b").SetCharset("utf8mb4"),
+			)
+	)
+	from.AddIndexes(schema.NewIndex("tmp_name").AddColumns(from.Columns[1]))
+	to.AddIndexes(schema.NewIndex("name").AddColumns(to.Columns[1]))
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 6)
+
+	changes, err = DefaultDiff.TableDiff(from, to, schema.DiffIgnoreComments(), schema.DiffIgnoreCharset())
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.IsType(t, &schema.ModifyAttr{}, changes[0])
+	require.IsType(t, &schema.DropIndex{}, changes[1])
+	require.IsType(t, &schema.AddIndex{}, changes[2])
+
+	changes, err = DefaultDiff.TableDiff(from, to,
+		schema.DiffIgnoreComments(),
+		schema.DiffIgnoreCharset(),
+		schema.DiffIgnoreAutoIncrement(),
+		schema.DiffIgnoreIndexes(regexp.MustCompile(`^tmp_`)),
+	)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "name", changes[0].(*schema.AddIndex).I.Name)
+
+	// Changes nested in schemas are filtered as well.
+	from.Schema, to.Schema = schema.New("public").AddTables(from), schema.New("public").AddTables(to)
+	changes, err = DefaultDiff.SchemaDiff(from.Schema, to.Schema,
+		schema.DiffIgnoreComments(),
+		schema.DiffIgnoreCharset(),
+		schema.DiffIgnoreAutoIncrement(),
+		schema.DiffIgnoreIndexes(regexp.MustCompile(`^(tmp_)?name$`)),
+	)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestDiff_IgnoreDefaults(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		from = schema.NewTable("users").
+			SetSchema(s).
+			AddColumns(
+				schema.NewIntColumn("id", TypeInt),
+				schema.NewStringColumn("status", TypeVarchar, schema.StringSize(32)).SetDefault(&schema.Literal{V: "'active'"}),
+				schema.NewStringColumn("name", TypeVarchar, schema.StringSize(32)).SetDefault(&schema.Literal{V: "'unknown'"}),
+			)
+		to = schema.NewTable("users").
+			SetSchema(s).
+			AddColumns(
+				schema.NewIntColumn("id", TypeInt),
+				// The default is managed by the application.
+				schema.NewStringColumn("status", TypeVarchar, schema.StringSize(32)).SetLogicalDefault(&schema.Literal{V: "'active'"}),
+				schema.NewStringColumn("name", TypeVarchar, schema.StringSize(64)),
+			)
+	)
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, schema.ChangeDefault, changes[0].(*schema.ModifyColumn).Change)
+	require.Equal(t, schema.ChangeType|schema.ChangeDefault, changes[1].(*schema.ModifyColumn).Change)
+
+	changes, err = DefaultDiff.TableDiff(from, to, schema.DiffIgnoreDefaults())
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, schema.ChangeType, changes[0].(*schema.ModifyColumn).Change)
+	require.Equal(t, "name", changes[0].(*schema.ModifyColumn).To.Name)
+
+	// Logical defaults are not reported as changes.
+	from.Columns[1].SetLogicalDefault(&schema.Literal{V: "'inactive'"})
+	changes, err = DefaultDiff.TableDiff(to, from)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	changes, err = DefaultDiff.TableDiff(to, from, schema.DiffIgnoreDefaults())
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+}
@@ -17,6 +17,7 @@ import (
 	"ariga.io/atlas/sql/mysql/internal/mysqlversion"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlerr"
 )
 
 type (
@@ -39,10 +40,17 @@ type (
 		collate string
 		charset string
 		lcnames int
+		// Identifier comparison mode, derived from lcnames by default.
+		identCompare IdentCompare
+		// Vitess-safe planning mode, if enabled.
+		vitess *Vitess
+		// Inspect tables using their 'SHOW CREATE TABLE' statements.
+		showCreate bool
 	}
 )
 
 var _ interface {
+	migrate.CapabilityReporter
 	migrate.StmtScanner
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
@@ -59,7 +67,7 @@ func init() {
 		opener(DriverName),
 		sqlclient.RegisterDriverOpener(Open),
 		sqlclient.RegisterCodec(codec, codec),
-		sqlclient.RegisterFlavours("mysql+unix"),
+		sqlclient.RegisterFlavours("mysql+unix", "singlestore", "memsql"),
 		sqlclient.RegisterURLParser(parser{}),
 	)
 	sqlclient.Register(
@@ -70,18 +78,48 @@ func init() {
 		sqlclient.RegisterFlavours("mariadb+unix", "maria", "maria+unix"),
 		sqlclient.RegisterURLParser(parser{}),
 	)
+	sqlerr.Register(DriverName, classifyError)
+	schema.RegisterJSONTypes(
+		DriverName,
+		(*AutoIncrement)(nil), (*TableStats)(nil), (*CreateOptions)(nil), (*CreateStmt)(nil),
+		(*Engine)(nil), (*SystemVersioned)(nil), (*OnUpdate)(nil), (*SubPart)(nil), (*Enforced)(nil),
+		(*DisplayWidth)(nil), (*ZeroFill)(nil), (*IndexType)(nil), (*IndexParser)(nil),
+		(*FullTextOptions)(nil), (*BitType)(nil), (*SetType)(nil), (*VectorOptions)(nil),
+		(*VectorType)(nil), (*NetworkType)(nil), (*TableStore)(nil), (*ShardKey)(nil), (*SortKey)(nil),
+		(*AutoRandom)(nil), (*Clustered)(nil), (*PlacementPolicy)(nil),
+	)
 }
 
 // Open opens a new MySQL driver.
 func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	return OpenWith(db)
+}
+
+// OpenWith opens a new MySQL driver with the given options.
+func OpenWith(db schema.ExecQuerier, opts ...Option) (migrate.Driver, error) {
 	c := &conn{ExecQuerier: db}
+	for _, opt := range opts {
+		opt(c)
+	}
 	rows, err := db.QueryContext(context.Background(), variablesQuery)
 	if err != nil {
 		return nil, fmt.Errorf("mysql: query system variables: %w", err)
 	}
-	if err := sqlx.ScanOne(rows, &c.V, &c.collate, &c.charset, &c.lcnames); err != nil {
+	var comment sql.NullString
+	if err := sqlx.ScanOne(rows, &c.V, &c.collate, &c.charset, &c.lcnames, &comment); err != nil {
 		return nil, fmt.Errorf("mysql: scan system variables: %w", err)
 	}
+	if isSingleStore(comment.String) {
+		if err := c.setSingleStoreVersion(); err != nil {
+			return nil, err
+		}
+		return &Driver{
+			conn:        c,
+			Differ:      &sqlx.Diff{DiffDriver: &s2diff{diff{conn: c}}},
+			Inspector:   &s2inspect{inspect{c}},
+			PlanApplier: &s2planApply{tplanApply{planApply{c}}},
+		}, nil
+	}
 	if c.TiDB() {
 		return &Driver{
 			conn:        c,
@@ -102,11 +140,20 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 func opener(name string) sqlclient.OpenerFunc {
 	return func(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
 		ur := parser{}.ParseURL(u)
+		opts, err := vitessOptions(u)
+		if err != nil {
+			return nil, err
+		}
+		idents, err := identOptions(u)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, idents...)
 		db, err := sql.Open(DriverName, ur.DSN)
 		if err != nil {
 			return nil, err
 		}
-		drv, err := Open(db)
+		drv, err := OpenWith(db, opts...)
 		if err != nil {
 			if cerr := db.Close(); cerr != nil {
 				err = fmt.Errorf("%w: %v", err, cerr)
@@ -257,6 +304,17 @@ func (d *Driver) Version() string {
 	return string(d.conn.V)
 }
 
+// Capabilities implements migrate.CapabilityReporter.
+func (d *Driver) Capabilities() *migrate.Capabilities {
+	return &migrate.Capabilities{
+		Check:        d.SupportsCheck(),
+		IndexExpr:    d.SupportsIndexExpr(),
+		ForeignKey:   !d.SingleStore(),
+		RenameColumn: d.SupportsRenameColumn(),
+		MaxIdentLen:  64,
+	}
+}
+
 // FormatType converts schema type to its column form in the database.
 func (*Driver) FormatType(t schema.Type) (string, error) {
 	return FormatType(t)
@@ -383,6 +441,10 @@ func dsn(u *url.URL) string {
 			b.WriteByte('/')
 		}
 	}
+	// Driver options are not passed to the database.
+	values.Del(paramVitess)
+	values.Del(paramVitessFKs)
+	values.Del(paramIdentCompare)
 	if p := values.Encode(); p != "" {
 		b.WriteByte('?')
 		b.WriteString(p)
@@ -452,6 +514,8 @@ const (
 
 	TypeInet4 = "inet4" // MariaDB type for storage of IPv4 addresses, from 10.10.0+.
 	TypeInet6 = "inet6" // MariaDB type for storage of IPv6 addresses, from 10.10.0+.
+
+	TypeVector = "vector" // MYSQL_TYPE_VECTOR. Supported by MySQL 9.0+ and MariaDB 11.7+.
 )
 
 // Additional common constants in MySQL.
@@ -460,10 +524,22 @@ const (
 	IndexTypeHash     = "HASH"
 	IndexTypeFullText = "FULLTEXT"
 	IndexTypeSpatial  = "SPATIAL"
+	IndexTypeVector   = "VECTOR" // Supported by MariaDB 11.7+.
+
+	VectorDistanceEuclidean = "euclidean"
+	VectorDistanceCosine    = "cosine"
 
 	IndexParserNGram = "ngram"
 	IndexParserMeCab = "mecab"
 
+	// DefaultNgramTokenSize is the default token size
+	// used by the ngram full-text parser.
+	DefaultNgramTokenSize = 2
+
+	// DefaultVectorSize is the number of dimensions used
+	// by MySQL for VECTOR columns that were defined without.
+	DefaultVectorSize = 2048
+
 	EngineInnoDB = "InnoDB"
 	EngineMyISAM = "MyISAM"
 	EngineMemory = "Memory"
@@ -477,6 +553,9 @@ const (
 	virtual    = "VIRTUAL"
 	stored     = "STORED"
 	persistent = "PERSISTENT"
+
+	// TABLE_TYPE of MariaDB system-versioned tables.
+	tableTypeSystemVersioned = "SYSTEM VERSIONED"
 )
 
 func (*inspect) tablesQuery(context.Context) string {
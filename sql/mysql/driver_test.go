@@ -213,6 +213,27 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "8.0.13", drv.(vr).Version())
 }
 
+func TestDriver_Capabilities(t *testing.T) {
+	for _, tt := range []struct {
+		version string
+		want    *migrate.Capabilities
+	}{
+		{version: "5.7.26", want: &migrate.Capabilities{ForeignKey: true, MaxIdentLen: 64}},
+		{version: "8.0.16", want: &migrate.Capabilities{Check: true, IndexExpr: true, ForeignKey: true, RenameColumn: true, MaxIdentLen: 64}},
+		{version: "10.5.8-MariaDB", want: &migrate.Capabilities{Check: true, ForeignKey: true, RenameColumn: true, MaxIdentLen: 64}},
+	} {
+		t.Run(tt.version, func(t *testing.T) {
+			db, m, err := sqlmock.New()
+			require.NoError(t, err)
+			mock{m}.version(tt.version)
+			drv, err := Open(db)
+			require.NoError(t, err)
+			require.Implements(t, (*migrate.CapabilityReporter)(nil), drv)
+			require.Equal(t, tt.want, drv.(migrate.CapabilityReporter).Capabilities())
+		})
+	}
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm  *schema.Realm
@@ -0,0 +1,136 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// IdentCompare describes how table identifiers are compared by the driver
+// in diffing and inspection.
+type IdentCompare uint8
+
+// List of identifier comparison modes.
+const (
+	// IdentCompareServer derives the comparison mode from the 'lower_case_table_names'
+	// system variable of the connected server. This is the default mode.
+	IdentCompareServer IdentCompare = iota
+	// IdentCompareSensitive compares table identifiers as case-sensitive, like
+	// Linux-based servers do by default (lower_case_table_names=0).
+	IdentCompareSensitive
+	// IdentCompareInsensitive compares table identifiers as case-insensitive, like
+	// Windows and macOS servers do by default (lower_case_table_names=1 or 2).
+	IdentCompareInsensitive
+)
+
+// WithIdentCompare returns an Option that sets the identifier comparison mode
+// of the driver. Use it to override the mode derived from the server, e.g. when
+// planning changes on a Linux-based dev-database for a macOS or Windows target.
+func WithIdentCompare(m IdentCompare) Option {
+	return func(c *conn) {
+		c.identCompare = m
+	}
+}
+
+// paramIdentCompare is the query parameter used to configure the identifier
+// comparison mode from the connection URL: "server", "sensitive" or "insensitive".
+const paramIdentCompare = "ident_compare"
+
+// identOptions returns the identifier comparison option that is set on the URL query.
+func identOptions(u *url.URL) ([]Option, error) {
+	switch m := u.Query().Get(paramIdentCompare); m {
+	case "", "server":
+		return nil, nil
+	case "sensitive":
+		return []Option{WithIdentCompare(IdentCompareSensitive)}, nil
+	case "insensitive":
+		return []Option{WithIdentCompare(IdentCompareInsensitive)}, nil
+	default:
+		return nil, fmt.Errorf("mysql: invalid %q parameter value: %q", paramIdentCompare, m)
+	}
+}
+
+// foldIdents reports if table identifiers are compared as case-insensitive.
+func (c *conn) foldIdents() (bool, error) {
+	switch c.identCompare {
+	case IdentCompareSensitive:
+		return false, nil
+	case IdentCompareInsensitive:
+		return true, nil
+	}
+	switch c.lcnames {
+	// In mode 0: tables are stored as specified, and comparisons are case-sensitive.
+	case 0:
+		return false, nil
+	// In mode 1: the table are stored in lowercase, but they are still
+	// returned on inspection, because comparisons are not case-sensitive.
+	// In mode 2: the tables are stored as given but compared in lowercase.
+	// This option is not supported by Linux-based systems.
+	case 1, 2:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported 'lower_case_table_names' mode: %d", c.lcnames)
+	}
+}
+
+// NeedsRename implements the sqlx.TableRenamer interface. Tables that were matched
+// case-insensitively, but differ in their case, are renamed only if the server stores
+// and compares names as given (lower_case_table_names=0), and the case-insensitive mode
+// was set explicitly. Otherwise, the server ignores the case of the table names, and a
+// case-only rename has no effect.
+func (d *diff) NeedsRename(from, to *schema.Table) bool {
+	return d.lcnames == 0 && strings.EqualFold(from.Name, to.Name)
+}
+
+// linkFoldTables links the foreign keys that reference tables by a name that
+// differs in case from the inspected one. For example, servers that are configured
+// with lower_case_table_names=2 return the referenced tables in lowercase.
+func (i *inspect) linkFoldTables(schemas []*schema.Schema) {
+	if fold, err := i.foldIdents(); err != nil || !fold {
+		return
+	}
+	for _, s := range schemas {
+		for _, t := range s.Tables {
+			for _, fk := range t.ForeignKeys {
+				if fk.RefTable.Schema == nil {
+					continue
+				}
+				// Already linked by the case-sensitive lookup.
+				if rs, ok := fk.RefTable.Schema.Table(fk.RefTable.Name); ok && rs == fk.RefTable {
+					continue
+				}
+				ref, ok := foldTable(schemas, fk.RefTable.Schema.Name, fk.RefTable.Name)
+				if !ok {
+					continue
+				}
+				fk.RefTable = ref
+				for i, c := range fk.RefColumns {
+					if rc, ok := ref.Column(c.Name); ok {
+						fk.RefColumns[i] = rc
+					}
+				}
+			}
+		}
+	}
+}
+
+// foldTable returns the table with the given name, compared case-insensitively.
+func foldTable(schemas []*schema.Schema, schemaName, name string) (*schema.Table, bool) {
+	for _, s := range schemas {
+		if s.Name != schemaName {
+			continue
+		}
+		for _, t := range s.Tables {
+			if strings.EqualFold(t.Name, name) {
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
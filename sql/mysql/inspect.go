@@ -37,10 +37,11 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 	)
 	if len(schemas) > 0 {
 		if mode.Is(schema.InspectTables) {
-			if err := i.inspectTables(ctx, r, nil); err != nil {
+			if err := i.inspectTables(ctx, r, nil, opts.Stats); err != nil {
 				return nil, err
 			}
 			sqlx.LinkSchemaTables(schemas)
+			i.linkFoldTables(schemas)
 		}
 	}
 	return schema.ExcludeRealm(r, opts.Exclude)
@@ -67,39 +68,114 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 		r    = schema.NewRealm(schemas...).SetCharset(i.charset).SetCollation(i.collate)
 	)
 	if mode.Is(schema.InspectTables) {
-		if err := i.inspectTables(ctx, r, opts); err != nil {
+		if err := i.inspectTables(ctx, r, opts, opts.Stats); err != nil {
 			return nil, err
 		}
 		sqlx.LinkSchemaTables(schemas)
+		i.linkFoldTables(schemas)
 	}
 	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
 }
 
-func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions, stats bool) error {
 	if err := i.tables(ctx, r, opts); err != nil {
 		return err
 	}
+	sc := &scope{}
 	for _, s := range r.Schemas {
-		if len(s.Tables) == 0 {
-			continue
-		}
-		if err := i.columns(ctx, s); err != nil {
-			return err
+		if len(s.Tables) > 0 {
+			sc.schemas = append(sc.schemas, s)
 		}
-		if err := i.indexes(ctx, s); err != nil {
+	}
+	switch len(sc.schemas) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		// Multiple schemas are inspected using one query per object
+		// type (columns, indexes, etc.), instead of one per schema.
+		sc.realm = r
+	}
+	if err := i.columns(ctx, sc); err != nil {
+		return err
+	}
+	if err := i.indexes(ctx, sc); err != nil {
+		return err
+	}
+	if err := i.fks(ctx, sc); err != nil {
+		return err
+	}
+	if err := i.checks(ctx, sc); err != nil {
+		return err
+	}
+	for _, s := range sc.schemas {
+		if err := i.showCreate(ctx, s); err != nil {
 			return err
 		}
-		if err := i.fks(ctx, s); err != nil {
+	}
+	if stats {
+		if err := i.tableStats(ctx, sc); err != nil {
 			return err
 		}
-		if err := i.checks(ctx, s); err != nil {
-			return err
+	}
+	return nil
+}
+
+// scope describes the scope of the inspection queries. A scope of a single schema
+// is queried by the schema and its tables, and a scope of multiple schemas (i.e., a
+// realm) is queried by the schema names, and the rows of non-inspected tables
+// (e.g., views or filtered tables) are skipped.
+type scope struct {
+	realm   *schema.Realm // Non-nil in case of multiple schemas.
+	schemas []*schema.Schema
+}
+
+// String returns the quoted names of the schemas in the scope.
+func (sc *scope) String() string {
+	names := make([]string, len(sc.schemas))
+	for i, s := range sc.schemas {
+		names[i] = strconv.Quote(s.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// row returns a new tableRow for scanning the rows of the scope queries.
+func (sc *scope) row() *tableRow {
+	return &tableRow{scope: sc}
+}
+
+// tableRow resolves the table of scanned rows. Rows of schema queries hold the
+// table name in their first column, and rows of realm queries hold the schema
+// name in their first column, followed by the table name.
+type tableRow struct {
+	*scope
+	sname, tname sql.NullString
+}
+
+// dest returns the scan destinations of the row, prefixed by the table identifier.
+func (r *tableRow) dest(dest ...any) []any {
+	if r.realm == nil {
+		return append([]any{&r.tname}, dest...)
+	}
+	return append([]any{&r.sname, &r.tname}, dest...)
+}
+
+// table returns the table of the last scanned row. A nil table is returned
+// for realm rows of tables that were not inspected, and should be skipped.
+func (r *tableRow) table() (*schema.Table, error) {
+	if r.realm == nil {
+		t, ok := r.schemas[0].Table(r.tname.String)
+		if !ok {
+			return nil, fmt.Errorf("table %q was not found in schema", r.tname.String)
 		}
-		if err := i.showCreate(ctx, s); err != nil {
-			return err
+		return t, nil
+	}
+	if s, ok := r.realm.Schema(r.sname.String); ok {
+		if t, ok := s.Table(r.tname.String); ok {
+			return t, nil
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 // schemas returns the list of the schemas in the database.
@@ -216,23 +292,31 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 				V: autoinc.Int64,
 			})
 		}
+		// Period columns and history partitioning of system-versioned
+		// tables are extracted from the 'SHOW CREATE TABLE' command.
+		if ttyp.String == tableTypeSystemVersioned {
+			v := &SystemVersioned{}
+			putShow(t).sysver = v
+			t.Attrs = append(t.Attrs, v)
+		}
 	}
 	return rows.Err()
 }
 
 // columns queries and appends the columns of the given table.
-func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
-	query := columnsQuery
+func (i *inspect) columns(ctx context.Context, sc *scope) error {
+	query, realmQ := columnsQuery, columnsRealmQuery
 	if i.SupportsGeneratedColumns() {
-		query = columnsExprQuery
+		query, realmQ = columnsExprQuery, columnsExprRealmQuery
 	}
-	rows, err := i.querySchema(ctx, query, s)
+	rows, err := i.queryScope(ctx, sc, query, realmQ)
 	if err != nil {
-		return fmt.Errorf("mysql: query schema %q columns: %w", s.Name, err)
+		return fmt.Errorf("mysql: query schema %s columns: %w", sc, err)
 	}
 	defer rows.Close()
+	tr := sc.row()
 	for rows.Next() {
-		if err := i.addColumn(s, rows); err != nil {
+		if err := i.addColumn(tr, rows); err != nil {
 			return fmt.Errorf("mysql: %w", err)
 		}
 	}
@@ -240,14 +324,14 @@ func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
 }
 
 // addColumn scans the current row and adds a new column from it to the table.
-func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
-	var table, name, typ, comment, nullable, key, defaults, extra, charset, collation, expr sql.NullString
-	if err := rows.Scan(&table, &name, &typ, &comment, &nullable, &key, &defaults, &extra, &charset, &collation, &expr); err != nil {
+func (i *inspect) addColumn(tr *tableRow, rows *sql.Rows) error {
+	var name, typ, comment, nullable, key, defaults, extra, charset, collation, expr sql.NullString
+	if err := rows.Scan(tr.dest(&name, &typ, &comment, &nullable, &key, &defaults, &extra, &charset, &collation, &expr)...); err != nil {
 		return err
 	}
-	t, ok := s.Table(table.String)
-	if !ok {
-		return fmt.Errorf("table %q was not found in schema", table.String)
+	t, err := tr.table()
+	if err != nil || t == nil {
+		return err
 	}
 	c := &schema.Column{
 		Name: name.String,
@@ -262,7 +346,12 @@ func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
 	}
 	c.Type.Type = ct
 	attr, err := parseExtra(extra.String)
-	if err != nil {
+	switch {
+	// TiDB extends the EXTRA column with its own attributes (e.g., AUTO_RANDOM),
+	// that are extracted from the 'CREATE TABLE' statement on inspection.
+	case err != nil && i.TiDB():
+		attr = &extraAttr{}
+	case err != nil:
 		return err
 	}
 	if attr.autoinc {
@@ -305,36 +394,42 @@ func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
 }
 
 // indexes queries and appends the indexes of the given table.
-func (i *inspect) indexes(ctx context.Context, s *schema.Schema) error {
-	query := i.indexQuery()
-	rows, err := i.querySchema(ctx, query, s)
+func (i *inspect) indexes(ctx context.Context, sc *scope) error {
+	query, realmQ := i.indexQuery()
+	rows, err := i.queryScope(ctx, sc, query, realmQ)
 	if err != nil {
-		return fmt.Errorf("mysql: query schema %q indexes: %w", s.Name, err)
+		return fmt.Errorf("mysql: query schema %s indexes: %w", sc, err)
 	}
 	defer rows.Close()
-	if err := i.addIndexes(s, rows); err != nil {
+	if err := i.addIndexes(sc.row(), rows); err != nil {
 		return err
 	}
 	return rows.Err()
 }
 
 // addIndexes scans the rows and adds the indexes to the table.
-func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
+func (i *inspect) addIndexes(tr *tableRow, rows *sql.Rows) error {
 	for rows.Next() {
 		var (
 			seqno                          int
-			table, name, indexType         string
+			name, indexType                string
 			nonuniq, desc                  sql.NullBool
 			column, subPart, expr, comment sql.NullString
 		)
-		if err := rows.Scan(&table, &name, &column, &nonuniq, &seqno, &indexType, &desc, &comment, &subPart, &expr); err != nil {
-			return fmt.Errorf("mysql: scanning indexes for schema %q: %w", s.Name, err)
+		if err := rows.Scan(tr.dest(&name, &column, &nonuniq, &seqno, &indexType, &desc, &comment, &subPart, &expr)...); err != nil {
+			return fmt.Errorf("mysql: scanning indexes for schema %s: %w", tr.scope, err)
 		}
-		t, ok := s.Table(table)
-		if !ok {
-			return fmt.Errorf("table %q was not found in schema", table)
+		t, err := tr.table()
+		if err != nil {
+			return err
 		}
-		var idx *schema.Index
+		if t == nil {
+			continue
+		}
+		var (
+			idx *schema.Index
+			ok  bool
+		)
 		switch {
 		// Primary key.
 		case name == "PRIMARY":
@@ -348,8 +443,11 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
 				idx = schema.NewIndex(name).
 					SetUnique(!nonuniq.Bool).
 					AddAttrs(&IndexType{T: indexType})
-				if indexType == IndexTypeFullText {
+				switch indexType {
+				case IndexTypeFullText:
 					putShow(t).addFullText(idx)
+				case IndexTypeVector:
+					putShow(t).addVector(idx)
 				}
 				if sqlx.ValidString(comment) {
 					idx.SetComment(comment.String)
@@ -387,37 +485,46 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
 }
 
 // fks queries and appends the foreign keys of the given table.
-func (i *inspect) fks(ctx context.Context, s *schema.Schema) error {
-	rows, err := i.querySchema(ctx, fksQuery, s)
+func (i *inspect) fks(ctx context.Context, sc *scope) error {
+	rows, err := i.queryScope(ctx, sc, fksQuery, fksRealmQuery)
 	if err != nil {
-		return fmt.Errorf("mysql: querying %q foreign keys: %w", s.Name, err)
+		return fmt.Errorf("mysql: querying %s foreign keys: %w", sc, err)
 	}
 	defer rows.Close()
-	if err := sqlx.SchemaFKs(s, rows); err != nil {
+	if sc.realm != nil {
+		err = sqlx.RealmFKs(sc.realm, rows)
+	} else {
+		err = sqlx.SchemaFKs(sc.schemas[0], rows)
+	}
+	if err != nil {
 		return fmt.Errorf("mysql: %w", err)
 	}
 	return rows.Err()
 }
 
 // checks queries and appends the check constraints of the given table.
-func (i *inspect) checks(ctx context.Context, s *schema.Schema) error {
-	query, ok := i.supportsCheck()
+func (i *inspect) checks(ctx context.Context, sc *scope) error {
+	query, realmQ, ok := i.supportsCheck()
 	if !ok {
 		return nil
 	}
-	rows, err := i.querySchema(ctx, query, s)
+	rows, err := i.queryScope(ctx, sc, query, realmQ)
 	if err != nil {
-		return fmt.Errorf("mysql: querying %q check constraints: %w", s.Name, err)
+		return fmt.Errorf("mysql: querying %s check constraints: %w", sc, err)
 	}
 	defer rows.Close()
+	tr := sc.row()
 	for rows.Next() {
-		var table, name, clause, enforced sql.NullString
-		if err := rows.Scan(&table, &name, &clause, &enforced); err != nil {
+		var name, clause, enforced sql.NullString
+		if err := rows.Scan(tr.dest(&name, &clause, &enforced)...); err != nil {
 			return fmt.Errorf("mysql: %w", err)
 		}
-		t, ok := s.Table(table.String)
-		if !ok {
-			return fmt.Errorf("table %q was not found in schema", table.String)
+		t, err := tr.table()
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			continue
 		}
 		check := &schema.Check{
 			Name: name.String,
@@ -450,26 +557,26 @@ func (i *inspect) checks(ctx context.Context, s *schema.Schema) error {
 	return rows.Err()
 }
 
-// supportsCheck reports if the connected database supports
-// the CHECK clause, and return the querying for getting them.
-func (i *inspect) supportsCheck() (string, bool) {
-	q := myChecksQuery
+// supportsCheck reports if the connected database supports the CHECK
+// clause, and return the schema and realm queries for getting them.
+func (i *inspect) supportsCheck() (string, string, bool) {
+	q, rq := myChecksQuery, myChecksRealmQuery
 	if i.Maria() {
-		q = marChecksQuery
+		q, rq = marChecksQuery, marChecksRealmQuery
 	}
-	return q, i.SupportsCheck()
+	return q, rq, i.SupportsCheck()
 }
 
-// indexQuery returns the query to retrieve the indexes of the given table.
-func (i *inspect) indexQuery() string {
-	query := indexesNoCommentQuery
+// indexQuery returns the schema and realm queries to retrieve the indexes of tables.
+func (i *inspect) indexQuery() (string, string) {
+	query, realmQ := indexesNoCommentQuery, indexesNoCommentRealmQuery
 	if i.SupportsIndexComment() {
-		query = indexesQuery
+		query, realmQ = indexesQuery, indexesRealmQuery
 	}
 	if i.SupportsIndexExpr() {
-		query = indexesExprQuery
+		query, realmQ = indexesExprQuery, indexesExprRealmQuery
 	}
-	return query
+	return query, realmQ
 }
 
 // extraAttr is a parsed version of the information_schema EXTRA column.
@@ -478,11 +585,13 @@ type extraAttr struct {
 	onUpdate         string
 	generatedType    string
 	defaultGenerated bool
+	rowPeriod        string
 }
 
 var (
 	reGenerateType = regexp.MustCompile(`(?i)^(stored|persistent|virtual) generated$`)
 	reTimeOnUpdate = regexp.MustCompile(`(?i)^(?:default_generated )?on update (current_timestamp(?:\(\d?\))?)$`)
+	reRowPeriod    = regexp.MustCompile(`(?i)^(?:invisible )?row (start|end)$`)
 )
 
 // parseExtra returns a parsed version of the EXTRA column
@@ -501,32 +610,131 @@ func parseExtra(extra string) (*extraAttr, error) {
 		attr.onUpdate = reTimeOnUpdate.FindStringSubmatch(extra)[1]
 	case reGenerateType.MatchString(extra):
 		attr.generatedType = reGenerateType.FindStringSubmatch(extra)[1]
+	case reRowPeriod.MatchString(extra):
+		// The period columns of MariaDB system-versioned tables
+		// are set on the table attribute in inspect.showCreate.
+		attr.rowPeriod = reRowPeriod.FindStringSubmatch(extra)[1]
 	default:
 		return nil, fmt.Errorf("unknown extra column attribute %q", extra)
 	}
 	return attr, nil
 }
 
+// tableStats sets the statistics of the schema tables.
+func (i *inspect) tableStats(ctx context.Context, sc *scope) error {
+	rows, err := i.queryScope(ctx, sc, tableStatsQuery, tableStatsRealmQuery)
+	if err != nil {
+		return fmt.Errorf("mysql: querying %s table statistics: %w", sc, err)
+	}
+	defer rows.Close()
+	tr := sc.row()
+	for rows.Next() {
+		var trows, data, index, avgRow, free sql.NullInt64
+		if err := rows.Scan(tr.dest(&trows, &data, &index, &avgRow, &free)...); err != nil {
+			return fmt.Errorf("mysql: scanning table statistics: %w", err)
+		}
+		t, err := tr.table()
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			continue
+		}
+		t.AddAttrs(&TableStats{
+			Rows:         trows.Int64,
+			DataLength:   data.Int64,
+			IndexLength:  index.Int64,
+			AvgRowLength: avgRow.Int64,
+			DataFree:     free.Int64,
+		})
+	}
+	return rows.Err()
+}
+
 // showCreate sets and fixes schema elements that require information from
 // the 'SHOW CREATE' command.
 func (i *inspect) showCreate(ctx context.Context, s *schema.Schema) error {
+	var (
+		err    error
+		ftOpts *FullTextOptions
+	)
 	for _, t := range s.Tables {
+		if i.conn.showCreate {
+			if err := i.patchCreateTable(ctx, t); err != nil {
+				return err
+			}
+		}
 		st, ok := popShow(t)
 		if !ok {
 			continue
 		}
-		c, err := i.createStmt(ctx, t)
-		if err != nil {
-			return err
+		c := &CreateStmt{}
+		if !sqlx.Has(t.Attrs, c) {
+			if c, err = i.createStmt(ctx, t); err != nil {
+				return err
+			}
 		}
 		st.setIndexParser(c)
+		if err := st.setVectorOptions(c); err != nil {
+			return err
+		}
 		if err := st.setAutoInc(t, c); err != nil {
 			return err
 		}
+		if err := st.setSystemVersioned(c); err != nil {
+			return err
+		}
+		// The stopword and ngram options are InnoDB-specific.
+		if len(st.idxs) > 0 && innoDB(t) {
+			if ftOpts == nil {
+				if ftOpts, err = i.fullTextOptions(ctx); err != nil {
+					return err
+				}
+			}
+			st.setFullTextOptions(ftOpts)
+		}
 	}
 	return nil
 }
 
+// fullTextOptions queries the server options that affect the FULLTEXT indexes.
+// The stopword options are returned only if the session overrides the server
+// defaults, as the server configuration applies to all indexes alike.
+func (i *inspect) fullTextOptions(ctx context.Context) (*FullTextOptions, error) {
+	query := fullTextOptionsQuery
+	if i.Maria() || i.LT("5.7.6") {
+		query = fullTextOptionsNoNgramQuery
+	}
+	rows, err := i.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("mysql: querying fulltext options: %w", err)
+	}
+	var (
+		opts                       FullTextOptions
+		ngram                      sql.NullInt64
+		stopword, globalStopword   sql.NullBool
+		userTable, globalUserTable sql.NullString
+	)
+	if err := sqlx.ScanOne(rows, &ngram, &stopword, &globalStopword, &userTable, &globalUserTable); err != nil {
+		return nil, fmt.Errorf("mysql: scanning fulltext options: %w", err)
+	}
+	if ngram.Valid && ngram.Int64 != DefaultNgramTokenSize {
+		opts.NgramTokenSize = int(ngram.Int64)
+	}
+	opts.NoStopwords = stopword.Valid && !stopword.Bool && (!globalStopword.Valid || globalStopword.Bool)
+	if sqlx.ValidString(userTable) && userTable.String != globalUserTable.String {
+		opts.StopwordTable = userTable.String
+	}
+	return &opts, nil
+}
+
+// innoDB reports if the table uses the InnoDB storage engine, which
+// is also the default engine in case the table engine is unknown.
+func innoDB(t *schema.Table) bool {
+	var e Engine
+	return !sqlx.Has(t.Attrs, &e) || strings.EqualFold(e.V, EngineInnoDB)
+}
+
 var reAutoinc = regexp.MustCompile(`(?i)\s*AUTO_INCREMENT\s*=\s*(\d+)\s*`)
 
 // createStmt loads the CREATE TABLE statement for the table.
@@ -660,11 +868,34 @@ func (i *inspect) querySchema(ctx context.Context, query string, s *schema.Schem
 	return i.QueryContext(ctx, fmt.Sprintf(query, nArgs(len(s.Tables))), args...)
 }
 
+// queryScope executes the given schema query or realm query, based on the scope type.
+func (i *inspect) queryScope(ctx context.Context, sc *scope, schemaQ, realmQ string) (*sql.Rows, error) {
+	if sc.realm == nil {
+		return i.querySchema(ctx, schemaQ, sc.schemas[0])
+	}
+	args := make([]any, len(sc.schemas))
+	for i, s := range sc.schemas {
+		args[i] = s.Name
+	}
+	return i.QueryContext(ctx, fmt.Sprintf(realmQ, nArgs(len(sc.schemas))), args...)
+}
+
 func nArgs(n int) string { return strings.Repeat("?, ", n-1) + "?" }
 
 const (
 	// Query to list system variables.
-	variablesQuery = "SELECT @@version, @@collation_server, @@character_set_server, @@lower_case_table_names"
+	variablesQuery = "SELECT @@version, @@collation_server, @@character_set_server, @@lower_case_table_names, @@version_comment"
+
+	// Query to get the SingleStore release, which differs from its MySQL-compatible version.
+	singleStoreVersionQuery = "SELECT @@memsql_version"
+
+	// Query to list the server options that affect FULLTEXT indexes.
+	fullTextOptionsQuery        = "SELECT @@ngram_token_size, @@SESSION.innodb_ft_enable_stopword, @@GLOBAL.innodb_ft_enable_stopword, @@SESSION.innodb_ft_user_stopword_table, @@GLOBAL.innodb_ft_user_stopword_table"
+	fullTextOptionsNoNgramQuery = "SELECT NULL, @@SESSION.innodb_ft_enable_stopword, @@GLOBAL.innodb_ft_enable_stopword, @@SESSION.innodb_ft_user_stopword_table, @@GLOBAL.innodb_ft_user_stopword_table"
+
+	// Query to list the statistics of the tables.
+	tableStatsQuery      = "SELECT `TABLE_NAME`, `TABLE_ROWS`, `DATA_LENGTH`, `INDEX_LENGTH`, `AVG_ROW_LENGTH`, `DATA_FREE` FROM `INFORMATION_SCHEMA`.`TABLES` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s)"
+	tableStatsRealmQuery = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `TABLE_ROWS`, `DATA_LENGTH`, `INDEX_LENGTH`, `AVG_ROW_LENGTH`, `DATA_FREE` FROM `INFORMATION_SCHEMA`.`TABLES` WHERE `TABLE_SCHEMA` IN (%s)"
 
 	// Query to list database schemas.
 	schemasQuery = "SELECT `SCHEMA_NAME`, `DEFAULT_CHARACTER_SET_NAME`, `DEFAULT_COLLATION_NAME` from `INFORMATION_SCHEMA`.`SCHEMATA` WHERE `SCHEMA_NAME` NOT IN ('information_schema','innodb','mysql','performance_schema','sys') ORDER BY `SCHEMA_NAME`"
@@ -681,6 +912,13 @@ const (
 	indexesExprQuery      = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
 	indexesNoCommentQuery = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, NULL AS `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
 
+	// Queries to list the columns and indexes of all tables in multiple schemas.
+	columnsRealmQuery          = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `COLUMN_NAME`, `COLUMN_TYPE`, `COLUMN_COMMENT`, `IS_NULLABLE`, `COLUMN_KEY`, `COLUMN_DEFAULT`, `EXTRA`, `CHARACTER_SET_NAME`, `COLLATION_NAME`, NULL AS `GENERATION_EXPRESSION` FROM `INFORMATION_SCHEMA`.`COLUMNS` WHERE `TABLE_SCHEMA` IN (%s) ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`, `ORDINAL_POSITION`"
+	columnsExprRealmQuery      = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `COLUMN_NAME`, `COLUMN_TYPE`, `COLUMN_COMMENT`, `IS_NULLABLE`, `COLUMN_KEY`, `COLUMN_DEFAULT`, `EXTRA`, `CHARACTER_SET_NAME`, `COLLATION_NAME`, `GENERATION_EXPRESSION` FROM `INFORMATION_SCHEMA`.`COLUMNS` WHERE `TABLE_SCHEMA` IN (%s) ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`, `ORDINAL_POSITION`"
+	indexesRealmQuery          = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` IN (%s) ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`, `index_name`, `seq_in_index`"
+	indexesExprRealmQuery      = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` IN (%s) ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`, `index_name`, `seq_in_index`"
+	indexesNoCommentRealmQuery = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, NULL AS `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` IN (%s) ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`, `index_name`, `seq_in_index`"
+
 	tablesQuery = `
 SELECT
 	t1.TABLE_SCHEMA,
@@ -701,7 +939,7 @@ FROM
 	ON t1.ENGINE = t3.ENGINE
 WHERE
 	TABLE_SCHEMA IN (%s)
-	AND TABLE_TYPE = 'BASE TABLE'
+	AND TABLE_TYPE IN ('BASE TABLE', 'SYSTEM VERSIONED')
 ORDER BY
 	TABLE_SCHEMA, TABLE_NAME`
 
@@ -726,7 +964,7 @@ FROM
 WHERE
 	TABLE_SCHEMA IN (%s)
 	AND TABLE_NAME IN (%s)
-	AND TABLE_TYPE = 'BASE TABLE'
+	AND TABLE_TYPE IN ('BASE TABLE', 'SYSTEM VERSIONED')
 ORDER BY
 	TABLE_SCHEMA, TABLE_NAME`
 
@@ -764,6 +1002,41 @@ WHERE
 ORDER BY
 	TABLE_NAME, CONSTRAINT_NAME
 `
+
+	// Queries to list the check constraints of all tables in multiple schemas.
+	myChecksRealmQuery = `
+SELECT
+	t1.TABLE_SCHEMA,
+	t1.TABLE_NAME,
+	t1.CONSTRAINT_NAME,
+	t2.CHECK_CLAUSE,
+	t1.ENFORCED
+FROM
+	INFORMATION_SCHEMA.TABLE_CONSTRAINTS AS t1
+	JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS AS t2
+	ON t1.CONSTRAINT_NAME = t2.CONSTRAINT_NAME
+	AND t1.CONSTRAINT_SCHEMA = t2.CONSTRAINT_SCHEMA
+WHERE
+	t1.CONSTRAINT_TYPE = 'CHECK'
+	AND t1.TABLE_SCHEMA IN (%s)
+ORDER BY
+	t1.TABLE_SCHEMA, t1.TABLE_NAME, t1.CONSTRAINT_NAME
+`
+
+	marChecksRealmQuery = `
+SELECT
+	CONSTRAINT_SCHEMA,
+	TABLE_NAME,
+	CONSTRAINT_NAME,
+	CHECK_CLAUSE,
+	"YES" AS ENFORCED
+FROM
+	INFORMATION_SCHEMA.CHECK_CONSTRAINTS
+WHERE
+	CONSTRAINT_SCHEMA IN (%s)
+ORDER BY
+	CONSTRAINT_SCHEMA, TABLE_NAME, CONSTRAINT_NAME
+`
 	// Query to list table foreign keys.
 	fksQuery = `
 SELECT
@@ -789,6 +1062,32 @@ ORDER BY
 	BINARY t1.TABLE_NAME,
 	BINARY t1.CONSTRAINT_NAME,
 	t1.ORDINAL_POSITION`
+
+	// Query to list the foreign keys of all tables in multiple schemas.
+	fksRealmQuery = `
+SELECT
+	t1.CONSTRAINT_NAME,
+	t1.TABLE_NAME,
+	t1.COLUMN_NAME,
+	t1.TABLE_SCHEMA,
+	t1.REFERENCED_TABLE_NAME,
+	t1.REFERENCED_COLUMN_NAME,
+	t1.REFERENCED_TABLE_SCHEMA,
+	t2.UPDATE_RULE,
+	t2.DELETE_RULE
+FROM
+	INFORMATION_SCHEMA.KEY_COLUMN_USAGE AS t1
+	JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS AS t2
+	ON t1.CONSTRAINT_NAME = t2.CONSTRAINT_NAME
+	AND BINARY t1.CONSTRAINT_SCHEMA = BINARY t2.CONSTRAINT_SCHEMA
+WHERE
+	t1.REFERENCED_COLUMN_NAME IS NOT NULL
+	AND BINARY t1.TABLE_SCHEMA IN (%s)
+ORDER BY
+	BINARY t1.TABLE_SCHEMA,
+	BINARY t1.TABLE_NAME,
+	BINARY t1.CONSTRAINT_NAME,
+	t1.ORDINAL_POSITION`
 )
 
 type (
@@ -799,6 +1098,18 @@ type (
 		V int64
 	}
 
+	// TableStats describes the table statistics, as reported by INFORMATION_SCHEMA.TABLES.
+	// It is returned by inspection only if the Stats inspect option is enabled. Note,
+	// the values are estimations for InnoDB tables, and may differ from the actual ones.
+	TableStats struct {
+		schema.Attr
+		Rows         int64 // Number of rows.
+		DataLength   int64 // Size of the data (clustered index for InnoDB) in bytes.
+		IndexLength  int64 // Size of the secondary indexes in bytes.
+		AvgRowLength int64 // Average row length in bytes.
+		DataFree     int64 // Allocated but unused bytes.
+	}
+
 	// CreateOptions attribute for describing extra options used with CREATE TABLE.
 	CreateOptions struct {
 		schema.Attr
@@ -822,6 +1133,22 @@ type (
 	// system versioned. See: https://mariadb.com/kb/en/system-versioned-tables
 	SystemVersioned struct {
 		schema.Attr
+		// Start and End hold the names of the period columns in case they were
+		// defined explicitly. Empty values indicate the implicit (and invisible)
+		// ROW_START and ROW_END columns.
+		Start, End string
+		// Partition describes the partitioning of the table history, if defined.
+		Partition *SystemTimePartition
+	}
+
+	// SystemTimePartition describes the partitioning of system-versioned tables
+	// by SYSTEM_TIME, used for limiting (or rotating) the stored history.
+	SystemTimePartition struct {
+		Interval   string // Interval of history partitions, e.g. "1 MONTH".
+		Starts     string // Starting point of the interval, e.g. TIMESTAMP'2024-01-01 00:00:00'.
+		Limit      int64  // Number of rows in history partitions.
+		Auto       bool   // History partitions are created automatically.
+		Partitions int    // Number of partitions, including the current one.
 	}
 
 	// OnUpdate attribute for columns with "ON UPDATE CURRENT_TIMESTAMP" as a default.
@@ -868,6 +1195,19 @@ type (
 		P string // Name of the parser plugin. e.g., ngram or mecab.
 	}
 
+	// FullTextOptions describes the server options that affect the tokenization of
+	// InnoDB FULLTEXT indexes. On inspection, the values are read from the server
+	// (and the current session), not from the index, as MySQL does not record the
+	// options an index was built with. Therefore, the stopword options are set only
+	// if the session overrides the server defaults. On creation, the options are
+	// applied on the session before the index is built.
+	FullTextOptions struct {
+		schema.Attr
+		NgramTokenSize int    // The "ngram_token_size" used by the ngram parser. Zero means the default.
+		NoStopwords    bool   // Stopword filtering is disabled. i.e., "innodb_ft_enable_stopword" is OFF.
+		StopwordTable  string // Custom stopword table in the "db_name/table_name" format.
+	}
+
 	// BitType represents the type bit.
 	BitType struct {
 		schema.Type
@@ -881,6 +1221,22 @@ type (
 		Values []string
 	}
 
+	// VectorOptions describes the options of a VECTOR index.
+	// Zero values mean the server defaults are used.
+	VectorOptions struct {
+		schema.Attr
+		M        int    // The M option of the HNSW algorithm. i.e., the number of neighbors.
+		Distance string // The distance function. i.e., euclidean or cosine.
+	}
+
+	// VectorType represents the VECTOR(N) type, storing
+	// a fixed-length array of N single-precision floats.
+	VectorType struct {
+		schema.Type
+		T    string
+		Size int // The number of dimensions.
+	}
+
 	// NetworkType stores an IPv4 or IPv6 address.
 	NetworkType struct {
 		schema.Type
@@ -896,6 +1252,10 @@ type (
 		auto *AutoIncrement
 		// FULLTEXT indexes that might have custom parser.
 		idxs []*schema.Index
+		// VECTOR indexes that might have custom options.
+		vidxs []*schema.Index
+		// System versioning options of MariaDB tables.
+		sysver *SystemVersioned
 	}
 )
 
@@ -910,13 +1270,23 @@ func (s *showTable) addFullText(idx *schema.Index) {
 	s.idxs = append(s.idxs, idx)
 }
 
+// addVector adds a VECTOR index to the list of indexes
+// that needs further processing.
+func (s *showTable) addVector(idx *schema.Index) {
+	s.vidxs = append(s.vidxs, idx)
+}
+
 // setAutoInc extracts the updated AUTO_INCREMENT from CREATE TABLE.
 func (s *showTable) setAutoInc(t *schema.Table, c *CreateStmt) error {
 	if s.auto == nil {
 		return nil
 	}
-	if sqlx.Has(t.Attrs, &AutoIncrement{}) {
-		return fmt.Errorf("unexpected AUTO_INCREMENT attributes for table: %q", t.Name)
+	// The value was already extracted from the statement (see patchCreateTable).
+	for _, a := range t.Attrs {
+		if a, ok := a.(*AutoIncrement); ok {
+			s.auto.V = a.V
+			return nil
+		}
 	}
 	matches := reAutoinc.FindStringSubmatch(c.S)
 	if len(matches) != 2 {
@@ -931,36 +1301,134 @@ func (s *showTable) setAutoInc(t *schema.Table, c *CreateStmt) error {
 	return nil
 }
 
+// setFullTextOptions sets the server options that affect the FULLTEXT
+// indexes of an InnoDB table, in case they are not the defaults.
+func (s *showTable) setFullTextOptions(opts *FullTextOptions) {
+	if opts == nil {
+		return
+	}
+	for _, idx := range s.idxs {
+		o := *opts
+		if p := (IndexParser{}); !sqlx.Has(idx.Attrs, &p) || !strings.EqualFold(p.P, IndexParserNGram) {
+			o.NgramTokenSize = 0
+		}
+		if o != (FullTextOptions{}) {
+			idx.AddAttrs(&o)
+		}
+	}
+}
+
+var (
+	// rePeriod matches the period definition of system-versioned tables.
+	rePeriod = regexp.MustCompile("(?i)PERIOD FOR SYSTEM_TIME\\s*\\(`((?:[^`]|``)+)`,\\s*`((?:[^`]|``)+)`\\)")
+	// reSystemTimePart matches the SYSTEM_TIME partitioning of system-versioned tables.
+	reSystemTimePart = regexp.MustCompile(`(?i)PARTITION BY SYSTEM_TIME(?:\s+INTERVAL\s+(\d+\s+\w+)(?:\s+STARTS\s+((?:TIMESTAMP\s*)?'[^']*'))?|\s+LIMIT\s+(\d+))?(\s+AUTO)?(?:\s+PARTITIONS\s+(\d+))?`)
+	// reSystemTimeParts matches the explicit partitions definitions.
+	reSystemTimeParts = regexp.MustCompile(`(?i)PARTITION\s+\S+\s+(?:HISTORY|CURRENT)\b`)
+)
+
+// setSystemVersioned updates the system versioning options from CREATE TABLE statement.
+func (s *showTable) setSystemVersioned(c *CreateStmt) error {
+	if s.sysver == nil {
+		return nil
+	}
+	if m := rePeriod.FindStringSubmatch(c.S); len(m) == 3 {
+		s.sysver.Start, s.sysver.End = strings.ReplaceAll(m[1], "``", "`"), strings.ReplaceAll(m[2], "``", "`")
+	}
+	m := reSystemTimePart.FindStringSubmatch(c.S)
+	if len(m) != 6 {
+		return nil
+	}
+	p := &SystemTimePartition{Interval: strings.ToUpper(m[1]), Starts: m[2], Auto: m[4] != ""}
+	if m[3] != "" {
+		v, err := strconv.ParseInt(m[3], 10, 64)
+		if err != nil {
+			return err
+		}
+		p.Limit = v
+	}
+	if m[5] != "" {
+		v, err := strconv.Atoi(m[5])
+		if err != nil {
+			return err
+		}
+		p.Partitions = v
+	} else {
+		p.Partitions = len(reSystemTimeParts.FindAllString(c.S, -1))
+	}
+	s.sysver.Partition = p
+	return nil
+}
+
 // reIndexParser matches the parser name from the index definition.
 var reIndexParser = regexp.MustCompile("/\\*!50100 WITH PARSER `([^`]+)` \\*/")
 
 // setIndexParser updates the FULLTEXT parser from CREATE TABLE statement.
 func (s *showTable) setIndexParser(c *CreateStmt) {
-	b := (&sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}).P("FULLTEXT KEY")
 	for _, idx := range s.idxs {
-		bi := b.Clone().Ident(idx.Name).Wrap(func(b *sqlx.Builder) {
-			b.MapComma(idx.Parts, func(i int, b *sqlx.Builder) {
-				// We expect column names only, as functional
-				// fulltext indexes are not supported by MySQL.
-				if idx.Parts[i].C != nil {
-					b.Ident(idx.Parts[i].C.Name)
-				}
-			})
-		})
-		i := strings.Index(c.S, bi.String())
-		if i == -1 || i+bi.Len() >= len(c.S) {
+		opts, ok := indexOptions(c, IndexTypeFullText, idx)
+		if !ok {
 			continue
 		}
-		i += bi.Len()
-		j := strings.Index(c.S[i:], "\n")
-		if j == -1 {
+		if matches := reIndexParser.FindStringSubmatch(opts); len(matches) == 2 {
+			idx.AddAttrs(&IndexParser{P: matches[1]})
+		}
+	}
+}
+
+var (
+	// reVectorM and reVectorDistance match the options of a VECTOR index.
+	reVectorM        = regexp.MustCompile("(?i)`?\\bM`?\\s*=\\s*(\\d+)")
+	reVectorDistance = regexp.MustCompile("(?i)`?\\bDISTANCE`?\\s*=\\s*`?(\\w+)`?")
+)
+
+// setVectorOptions updates the VECTOR index options from CREATE TABLE statement.
+func (s *showTable) setVectorOptions(c *CreateStmt) error {
+	for _, idx := range s.vidxs {
+		opts, ok := indexOptions(c, IndexTypeVector, idx)
+		if !ok {
 			continue
 		}
-		// The rest of the line holds index, algorithm and lock options.
-		if matches := reIndexParser.FindStringSubmatch(c.S[i : i+j]); len(matches) == 2 {
-			idx.AddAttrs(&IndexParser{P: matches[1]})
+		var v VectorOptions
+		if matches := reVectorM.FindStringSubmatch(opts); len(matches) == 2 {
+			m, err := strconv.Atoi(matches[1])
+			if err != nil {
+				return fmt.Errorf("mysql: parse M option of vector index %q: %w", idx.Name, err)
+			}
+			v.M = m
+		}
+		if matches := reVectorDistance.FindStringSubmatch(opts); len(matches) == 2 {
+			v.Distance = strings.ToLower(matches[1])
+		}
+		if v != (VectorOptions{}) {
+			idx.AddAttrs(&v)
 		}
 	}
+	return nil
+}
+
+// indexOptions returns the options of the index (the rest of its definition
+// line) from the CREATE TABLE statement. e.g., index, algorithm and lock options.
+func indexOptions(c *CreateStmt, typ string, idx *schema.Index) (string, bool) {
+	b := (&sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}).P(typ, "KEY").Ident(idx.Name).Wrap(func(b *sqlx.Builder) {
+		b.MapComma(idx.Parts, func(i int, b *sqlx.Builder) {
+			// We expect column names only, as functional
+			// fulltext and vector indexes are not supported.
+			if idx.Parts[i].C != nil {
+				b.Ident(idx.Parts[i].C.Name)
+			}
+		})
+	})
+	i := strings.Index(c.S, b.String())
+	if i == -1 || i+b.Len() >= len(c.S) {
+		return "", false
+	}
+	i += b.Len()
+	j := strings.Index(c.S[i:], "\n")
+	if j == -1 {
+		return "", false
+	}
+	return c.S[i : i+j], true
 }
 
 func putShow(t *schema.Table) *showTable {
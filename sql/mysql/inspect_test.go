@@ -745,6 +745,240 @@ func TestDriver_InspectTable(t *testing.T) {
 				}, t.Attrs)
 			},
 		},
+		{
+			name:    "system versioned",
+			version: "10.11.2-MariaDB",
+			before: func(m mock) {
+				m.ExpectQuery(queryTable).
+					WithArgs("public").
+					WillReturnRows(sqlmock.NewRows([]string{"table_schema", "table_name", "table_collation", "character_set", "auto_increment", "table_comment", "create_options", "engine", "default_engine", "table_type"}).
+						AddRow("public", "users", nil, nil, nil, nil, "partitioned", nil, nil, "SYSTEM VERSIONED"))
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| TABLE_NAME  | COLUMN_NAME | COLUMN_TYPE  | COLUMN_COMMENT | IS_NULLABLE | COLUMN_KEY | COLUMN_DEFAULT | EXTRA          | CHARACTER_SET_NAME | COLLATION_NAME     | GENERATION_EXPRESSION     |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| users       | id          | int(11)      |                | NO          |            | NULL           |                | NULL               | NULL               | NULL                      |
+| users       | start_ts    | timestamp(6) |                | NO          |            | NULL           | ROW START      | NULL               | NULL               | NULL                      |
+| users       | end_ts      | timestamp(6) |                | NO          |            | NULL           | ROW END        | NULL               | NULL               | NULL                      |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+`))
+				m.ExpectQuery(queryIndexes).
+					WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "column_name", "non_unique", "key_part", "expression"}))
+				m.noFKs()
+				m.ExpectQuery(queryMarChecks).
+					WithArgs("public", "users").
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "CONSTRAINT_NAME", "CHECK_CLAUSE", "ENFORCED"}))
+				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+					WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+						AddRow("users", "CREATE TABLE `users` (\n  `id` int(11) NOT NULL,\n  `start_ts` timestamp(6) GENERATED ALWAYS AS ROW START,\n  `end_ts` timestamp(6) GENERATED ALWAYS AS ROW END,\n  PERIOD FOR SYSTEM_TIME (`start_ts`, `end_ts`)\n) ENGINE=InnoDB DEFAULT CHARSET=latin1 WITH SYSTEM VERSIONING\n PARTITION BY SYSTEM_TIME INTERVAL 1 MONTH STARTS TIMESTAMP'2024-01-01 00:00:00'\n(PARTITION `p0` HISTORY ENGINE = InnoDB,\n PARTITION `p1` HISTORY ENGINE = InnoDB,\n PARTITION `pn` CURRENT ENGINE = InnoDB)"))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Columns, 3)
+				v := &SystemVersioned{}
+				require.True(sqlx.Has(t.Attrs, v))
+				require.Equal(&SystemVersioned{
+					Start: "start_ts",
+					End:   "end_ts",
+					Partition: &SystemTimePartition{
+						Interval:   "1 MONTH",
+						Starts:     "TIMESTAMP'2024-01-01 00:00:00'",
+						Partitions: 3,
+					},
+				}, v)
+			},
+		},
+		{
+			name:    "vector index",
+			version: "11.7.2-MariaDB",
+			before: func(m mock) {
+				m.tableExists("public", "users", true)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| TABLE_NAME  | COLUMN_NAME | COLUMN_TYPE  | COLUMN_COMMENT | IS_NULLABLE | COLUMN_KEY | COLUMN_DEFAULT | EXTRA          | CHARACTER_SET_NAME | COLLATION_NAME     | GENERATION_EXPRESSION     |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| users       | v1          | vector(3)    |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
+| users       | v2          | vector(3)    |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+`))
+				m.ExpectQuery(queryIndexes).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| users              | v1           | v1          |          1 |            1 | VECTOR       | 0        |              |       NULL |      NULL        |
+| users              | v2           | v2          |          1 |            1 | VECTOR       | 0        |              |       NULL |      NULL        |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+`))
+				m.noFKs()
+				m.ExpectQuery(queryMarChecks).
+					WithArgs("public", "users").
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "CONSTRAINT_NAME", "CHECK_CLAUSE", "ENFORCED"}))
+				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+					WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+						AddRow("users", "CREATE TABLE `users` (\n  `v1` vector(3) NOT NULL,\n  `v2` vector(3) NOT NULL,\n  VECTOR KEY `v1` (`v1`),\n  VECTOR KEY `v2` (`v2`) `M`=8 `DISTANCE`=cosine\n) ENGINE=InnoDB"))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.EqualValues(&VectorType{T: TypeVector, Size: 3}, t.Columns[0].Type.Type)
+				require.Len(t.Indexes, 2)
+				require.EqualValues([]schema.Attr{
+					&IndexType{T: IndexTypeVector},
+				}, t.Indexes[0].Attrs)
+				require.EqualValues([]schema.Attr{
+					&IndexType{T: IndexTypeVector},
+					&VectorOptions{M: 8, Distance: VectorDistanceCosine},
+				}, t.Indexes[1].Attrs)
+			},
+		},
+		{
+			name: "fulltext options",
+			before: func(m mock) {
+				m.tableExists("public", "users", true)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| TABLE_NAME  | COLUMN_NAME | COLUMN_TYPE  | COLUMN_COMMENT | IS_NULLABLE | COLUMN_KEY | COLUMN_DEFAULT | EXTRA          | CHARACTER_SET_NAME | COLLATION_NAME     | GENERATION_EXPRESSION     |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| users       | c1          | text         |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
+| users       | c2          | text         |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+`))
+				m.ExpectQuery(queryIndexesExpr).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| users              | c1           | c1          |          1 |            1 | FULLTEXT     | 0        |              |       NULL |      NULL        |
+| users              | c2           | c2          |          1 |            1 | FULLTEXT     | 0        | comment      |       NULL |      NULL        |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+`))
+				m.noFKs()
+				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+					WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+						AddRow("users", "CREATE TABLE `users` (\n  `c1` text NOT NULL,\n  `c2` text NOT NULL,\n  FULLTEXT KEY `c1` (`c1`),\n  FULLTEXT KEY `c2` (`c2`) /*!50100 WITH PARSER `ngram` */ COMMENT 'comment'\n) ENGINE=InnoDB"))
+				m.ExpectQuery(sqltest.Escape(fullTextOptionsQuery)).
+					WillReturnRows(sqltest.Rows(`
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+| @@ngram_token_size | @@SESSION.innodb_ft_enable_stopword | @@GLOBAL.innodb_ft_enable_stopword | @@SESSION.innodb_ft_user_stopword_table | @@GLOBAL.innodb_ft_user_stopword_table |
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+| 3                  | 1                                   | 1                                  | db/stopwords                          | NULL                                 |
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+`))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Indexes, 2)
+				require.EqualValues([]schema.Attr{
+					&IndexType{T: IndexTypeFullText},
+					&FullTextOptions{StopwordTable: "db/stopwords"},
+				}, t.Indexes[0].Attrs)
+				require.EqualValues([]schema.Attr{
+					&IndexType{T: IndexTypeFullText},
+					&schema.Comment{Text: "comment"},
+					&IndexParser{P: IndexParserNGram},
+					&FullTextOptions{NgramTokenSize: 3, StopwordTable: "db/stopwords"},
+				}, t.Indexes[1].Attrs)
+			},
+		},
+		{
+			name: "fulltext options default",
+			before: func(m mock) {
+				m.tableExists("public", "users", true)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| TABLE_NAME  | COLUMN_NAME | COLUMN_TYPE  | COLUMN_COMMENT | IS_NULLABLE | COLUMN_KEY | COLUMN_DEFAULT | EXTRA          | CHARACTER_SET_NAME | COLLATION_NAME     | GENERATION_EXPRESSION     |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| users       | c1          | text         |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+`))
+				m.ExpectQuery(queryIndexesExpr).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| users              | c1           | c1          |          1 |            1 | FULLTEXT     | 0        |              |       NULL |      NULL        |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+`))
+				m.noFKs()
+				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+					WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+						AddRow("users", "CREATE TABLE `users` (\n  `c1` text NOT NULL,\n  FULLTEXT KEY `c1` (`c1`)\n) ENGINE=InnoDB"))
+				// The session does not override the server configuration.
+				m.ExpectQuery(sqltest.Escape(fullTextOptionsQuery)).
+					WillReturnRows(sqltest.Rows(`
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+| @@ngram_token_size | @@SESSION.innodb_ft_enable_stopword | @@GLOBAL.innodb_ft_enable_stopword | @@SESSION.innodb_ft_user_stopword_table | @@GLOBAL.innodb_ft_user_stopword_table |
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+| 2                  | 0                                   | 0                                  | db/stopwords                          | db/stopwords                         |
++--------------------+-------------------------------------+------------------------------------+---------------------------------------+--------------------------------------+
+`))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Indexes, 1)
+				require.EqualValues([]schema.Attr{&IndexType{T: IndexTypeFullText}}, t.Indexes[0].Attrs)
+				// No changes are planned for the default-configured index.
+				to := schema.NewTable("users").
+					SetSchema(schema.New("public")).
+					AddColumns(schema.NewStringColumn("c1", "text"))
+				to.AddIndexes(schema.NewIndex("c1").AddColumns(to.Columns[0]).AddAttrs(&IndexType{T: IndexTypeFullText}))
+				for _, c := range [][2]*schema.Table{{t, to}, {to, t}} {
+					changes, err := DefaultDiff.TableDiff(c[0], c[1])
+					require.NoError(err)
+					require.Empty(changes)
+				}
+			},
+		},
+		{
+			name: "fulltext options myisam",
+			before: func(m mock) {
+				m.ExpectQuery(queryTable).
+					WithArgs("public").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"table_schema", "table_name", "table_collation", "character_set", "auto_increment", "table_comment", "create_options", "engine", "default_engine", "table_type"}).
+							AddRow("public", "users", nil, nil, nil, nil, nil, "MyISAM", false, "BASE TABLE"),
+					)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| TABLE_NAME  | COLUMN_NAME | COLUMN_TYPE  | COLUMN_COMMENT | IS_NULLABLE | COLUMN_KEY | COLUMN_DEFAULT | EXTRA          | CHARACTER_SET_NAME | COLLATION_NAME     | GENERATION_EXPRESSION     |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+| users       | c1          | text         |                | NO          | MUL        | NULL           |                | NULL               | NULL               | NULL                      |
++-------------+-------------+--------------+----------------+-------------+------------+----------------+----------------+--------------------+--------------------+---------------------------+
+`))
+				m.ExpectQuery(queryIndexesExpr).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+| users              | c1           | c1          |          1 |            1 | FULLTEXT     | 0        |              |       NULL |      NULL        |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
+`))
+				m.noFKs()
+				// InnoDB options are not queried for MyISAM tables.
+				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+					WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+						AddRow("users", "CREATE TABLE `users` (\n  `c1` text NOT NULL,\n  FULLTEXT KEY `c1` (`c1`)\n) ENGINE=MyISAM"))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Indexes, 1)
+				require.EqualValues([]schema.Attr{&IndexType{T: IndexTypeFullText}}, t.Indexes[0].Attrs)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1010,6 +1244,65 @@ func TestDriver_Realm(t *testing.T) {
 	}(), realm)
 }
 
+func TestDriver_InspectRealmBatch(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("8.0.16")
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "IN (?, ?)"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME", "DEFAULT_CHARACTER_SET_NAME", "DEFAULT_COLLATION_NAME"}).
+			AddRow("a", "utf8mb4", "utf8mb4_unicode_ci").
+			AddRow("b", "utf8mb4", "utf8mb4_unicode_ci"))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "?, ?"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"schema", "table", "charset", "collate", "inc", "comment", "options", "engine", "default_engine", "table_type"}).
+			AddRow("a", "users", nil, nil, nil, nil, nil, nil, nil, nil).
+			AddRow("b", "pets", nil, nil, nil, nil, nil, nil, nil, nil))
+	// Queries of multiple schemas are not filtered by table names,
+	// and rows of non-inspected tables (e.g., views) are skipped.
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsExprRealmQuery, "?, ?"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "COLUMN_NAME", "COLUMN_TYPE", "COLUMN_COMMENT", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "CHARACTER_SET_NAME", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+			AddRow("a", "users", "id", "int", "", "NO", "PRI", nil, "", nil, nil, nil).
+			AddRow("a", "users_view", "id", "int", "", "NO", "", nil, "", nil, nil, nil).
+			AddRow("b", "pets", "id", "int", "", "NO", "PRI", nil, "", nil, nil, nil).
+			AddRow("b", "pets", "owner_id", "int", "", "YES", "MUL", nil, "", nil, nil, nil))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesExprRealmQuery, "?, ?"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "SEQ_IN_INDEX", "INDEX_TYPE", "DESC", "INDEX_COMMENT", "SUB_PART", "EXPRESSION"}).
+			AddRow("a", "users", "PRIMARY", "id", "0", "1", "BTREE", "0", "", nil, nil).
+			AddRow("b", "pets", "PRIMARY", "id", "0", "1", "BTREE", "0", "", nil, nil))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksRealmQuery, "?, ?"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "TABLE_NAME", "COLUMN_NAME", "TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "REFERENCED_TABLE_SCHEMA", "UPDATE_RULE", "DELETE_RULE"}).
+			AddRow("owner", "pets", "owner_id", "b", "users", "id", "a", "NO ACTION", "CASCADE"))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(myChecksRealmQuery, "?, ?"))).
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "CONSTRAINT_NAME", "CHECK_CLAUSE", "ENFORCED"}).
+			AddRow("b", "pets", "pets_chk", "(`id` > 0)", "YES"))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	realm, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode:    ^schema.InspectViews,
+		Schemas: []string{"a", "b"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	require.Len(t, realm.Schemas, 2)
+	users, ok := realm.Schemas[0].Table("users")
+	require.True(t, ok)
+	require.Len(t, users.Columns, 1)
+	require.NotNil(t, users.PrimaryKey)
+	pets, ok := realm.Schemas[1].Table("pets")
+	require.True(t, ok)
+	require.Len(t, pets.Columns, 2)
+	require.Len(t, pets.ForeignKeys, 1)
+	require.Equal(t, users, pets.ForeignKeys[0].RefTable)
+	require.Equal(t, schema.Cascade, pets.ForeignKeys[0].OnDelete)
+	require.Equal(t, []schema.Attr{&schema.Check{Name: "pets_chk", Expr: "(`id` > 0)"}}, pets.Attrs)
+}
+
 func TestInspectMode_InspectRealm(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -1057,25 +1350,73 @@ type mock struct {
 	sqlmock.Sqlmock
 }
 
+func TestDriver_InspectStats(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("8.0.13")
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= SCHEMA()"))).
+		WillReturnRows(sqltest.Rows(`
++-------------+----------------------------+------------------------+
+| SCHEMA_NAME | DEFAULT_CHARACTER_SET_NAME | DEFAULT_COLLATION_NAME |
++-------------+----------------------------+------------------------+
+| public      | utf8mb4                    | utf8mb4_unicode_ci     |
++-------------+----------------------------+------------------------+
+`))
+	mk.tableExists("public", "users", true)
+	mk.ExpectQuery(queryColumns).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+| table_name | column_name | column_type | column_comment | is_nullable | column_key | column_default | extra | character_set_name | collation_name | generation_expression |
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+| users      | id          | int         |                | NO          |            | NULL           |       | NULL               | NULL           | NULL                  |
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+`))
+	mk.noIndexes()
+	mk.noFKs()
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(tableStatsQuery, "?"))).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
++------------+------------+-------------+--------------+----------------+-----------+
+| TABLE_NAME | TABLE_ROWS | DATA_LENGTH | INDEX_LENGTH | AVG_ROW_LENGTH | DATA_FREE |
++------------+------------+-------------+--------------+----------------+-----------+
+| users      | 12000000   | 805306368   | 16384        | 67             | 4194304   |
++------------+------------+-------------+--------------+----------------+-----------+
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode:  schema.InspectTables,
+		Stats: true,
+	})
+	require.NoError(t, err)
+	tbl, ok := s.Table("users")
+	require.True(t, ok)
+	var stats TableStats
+	require.True(t, sqlx.Has(tbl.Attrs, &stats))
+	require.Equal(t, TableStats{Rows: 12000000, DataLength: 805306368, IndexLength: 16384, AvgRowLength: 67, DataFree: 4194304}, stats)
+}
+
 func (m mock) version(version string) {
 	m.ExpectQuery(sqltest.Escape(variablesQuery)).
 		WillReturnRows(sqltest.Rows(`
-+-----------------+--------------------+------------------------+--------------------------+ 
-| @@version       | @@collation_server | @@character_set_server | @@lower_case_table_names | 
-+-----------------+--------------------+------------------------+--------------------------+ 
-| ` + version + ` | utf8_general_ci    | utf8                   | 0                        | 
-+-----------------+--------------------+------------------------+--------------------------+ 
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
+| @@version       | @@collation_server | @@character_set_server | @@lower_case_table_names | @@version_comment            | 
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
+| ` + version + ` | utf8_general_ci    | utf8                   | 0                        | MySQL Community Server (GPL) | 
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
 `))
 }
 
 func (m mock) lcmode(version, mode string) {
 	m.ExpectQuery(sqltest.Escape(variablesQuery)).
 		WillReturnRows(sqltest.Rows(`
-+-----------------+--------------------+------------------------+--------------------------+ 
-| @@version       | @@collation_server | @@character_set_server | @@lower_case_table_names | 
-+-----------------+--------------------+------------------------+--------------------------+ 
-| ` + version + ` | utf8_general_ci    | utf8                   | ` + mode + `             |
-+-----------------+--------------------+------------------------+--------------------------+ 
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
+| @@version       | @@collation_server | @@character_set_server | @@lower_case_table_names | @@version_comment            | 
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
+| ` + version + ` | utf8_general_ci    | utf8                   | ` + mode + `             | MySQL Community Server (GPL) |
++-----------------+--------------------+------------------------+--------------------------+------------------------------+ 
 `))
 }
 
@@ -94,6 +94,17 @@ func (v V) SupportsViewUsage() bool {
 	return !v.Maria() && v.GTE("8.0.13")
 }
 
+// SupportsLargeIndexPrefix reports if the version supports index key
+// prefixes up to 3072 bytes for InnoDB tables by default. Older versions
+// limit column prefixes to 767 bytes, unless "innodb_large_prefix" is set.
+func (v V) SupportsLargeIndexPrefix() bool {
+	u := "5.7.7"
+	if v.Maria() {
+		u = "10.2.2"
+	}
+	return v.GTE(u)
+}
+
 // CharsetToCollate returns the mapping from charset to its default collation.
 func (v V) CharsetToCollate(conn schema.ExecQuerier) (map[string]string, error) {
 	name := "is/charset2collate"
@@ -154,6 +165,22 @@ func (v V) TiDB() bool {
 	return strings.Index(string(v), "TiDB") > 0
 }
 
+// SingleStore reports if the MySQL version is SingleStore (formerly MemSQL). The release
+// of SingleStore is appended to the MySQL-compatible version of the server when the driver
+// is opened. For example, "5.7.32-SingleStore-8.5.7".
+func (v V) SingleStore() bool {
+	return strings.Index(string(v), "SingleStore") > 0
+}
+
+// SingleStoreGTE reports if the version is SingleStore, and its release is >= w.
+func (v V) SingleStoreGTE(w string) bool {
+	idx := strings.Index(string(v), "SingleStore-")
+	if idx <= 0 {
+		return false
+	}
+	return semver.Compare("v"+string(v)[idx+len("SingleStore-"):], "v"+w) >= 0
+}
+
 // Compare returns an integer comparing two versions according to
 // semantic version precedence.
 func (v V) Compare(w string) int {
@@ -49,3 +49,24 @@ func TestV_CollateToCharset(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "unknown", c2c["custom"])
 }
+
+func TestV_SingleStore(t *testing.T) {
+	tests := []struct {
+		v       string
+		s2, gte bool
+	}{
+		{v: "5.7.32"},
+		{v: "8.0.0-TiDB-v6.1.0"},
+		{v: "5.7.32-SingleStore-7.1.13", s2: true},
+		{v: "5.7.32-SingleStore-7.3.0", s2: true, gte: true},
+		{v: "5.7.32-SingleStore-8.5.7", s2: true, gte: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			v := mysqlversion.V(tt.v)
+			require.Equal(t, tt.s2, v.SingleStore())
+			require.Equal(t, tt.gte, v.SingleStoreGTE("7.3"))
+			require.True(t, v.GTE("5.7"))
+		})
+	}
+}
@@ -6,8 +6,11 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -69,11 +72,20 @@ type state struct {
 // plan builds the migration plan for applying the
 // given changes on the attached connection.
 func (s *state) plan(changes []schema.Change) error {
+	if err := s.Policy.Check(changes); err != nil {
+		return err
+	}
 	if s.SchemaQualifier != nil {
 		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
 			return err
 		}
 	}
+	if s.vitess != nil {
+		var err error
+		if changes, err = s.vitessChanges(changes); err != nil {
+			return err
+		}
+	}
 	planned, err := s.topLevel(changes)
 	if err != nil {
 		return err
@@ -85,23 +97,34 @@ func (s *state) plan(changes []schema.Change) error {
 		}
 		planned = sqlx.SortChanges(planned, nil)
 	}
-	for _, c := range planned {
+	for _, c := range sqlx.SplitBackfills(planned) {
 		switch c := c.(type) {
 		case *schema.AddTable:
 			err = s.addTable(c)
 		case *schema.DropTable:
 			err = s.dropTable(c)
 		case *schema.ModifyTable:
-			err = s.modifyTable(c)
+			m, data := sqlx.DataChanges(c)
+			if len(data) == 0 || len(m.Changes) > 0 {
+				err = s.modifyTable(m)
+			}
+			if err == nil {
+				err = s.dataChanges(data...)
+			}
+		case *schema.DataChange:
+			err = s.dataChanges(c)
 		case *schema.RenameTable:
 			s.renameTable(c)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
 		if err != nil {
-			return err
+			return sqlx.PosError(c, err)
 		}
 	}
+	if s.vitess != nil {
+		s.vitessStrategy()
+	}
 	return nil
 }
 
@@ -218,8 +241,12 @@ func (s *state) modifySchema(modify *schema.ModifySchema) error {
 func (s *state) addTable(add *schema.AddTable) error {
 	var (
 		errs []string
-		b    = s.Build("CREATE TABLE")
+		b    = s.Build("CREATE")
 	)
+	if s.SingleStore() {
+		s.s2TableKind(b, add.T)
+	}
+	b.P("TABLE")
 	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
 		b.P("IF NOT EXISTS")
 	}
@@ -227,6 +254,13 @@ func (s *state) addTable(add *schema.AddTable) error {
 	if len(add.T.Columns) == 0 {
 		return fmt.Errorf("table %q has no columns", add.T.Name)
 	}
+	if err := s.checkIndexes(add.T.Indexes); err != nil {
+		return fmt.Errorf("create table %q: %w", add.T.Name, err)
+	}
+	set, reset, err := s.fullTextSession(add.T.Indexes)
+	if err != nil {
+		return fmt.Errorf("create table %q: %w", add.T.Name, err)
+	}
 	b.WrapIndent(func(b *sqlx.Builder) {
 		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
 			if err := s.column(b, add.T, add.T.Columns[i]); err != nil {
@@ -244,6 +278,11 @@ func (s *state) addTable(add *schema.AddTable) error {
 			idx := add.T.Indexes[i]
 			index(b, idx)
 		})
+		if s.SingleStore() {
+			if err := s.s2TableKeys(b, add.T); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
 		if len(add.T.ForeignKeys) > 0 {
 			b.Comma()
 			if err := s.fks(b.MapIndentErr, add.T.ForeignKeys...); err != nil {
@@ -256,17 +295,27 @@ func (s *state) addTable(add *schema.AddTable) error {
 				s.check(b, c)
 			}
 		}
+		if v := (SystemVersioned{}); sqlx.Has(add.T.Attrs, &v) && v.Start != "" {
+			b.Comma().NL()
+			period(b, &v)
+		}
 	})
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
 	}
 	s.tableAttrs(b, add, add.T.Attrs...)
+	// Partitioning options must follow the table options.
+	if v := (SystemVersioned{}); sqlx.Has(add.T.Attrs, &v) && v.Partition != nil {
+		systemTimePartition(b, v.Partition)
+	}
+	s.mayAppend(set)
 	s.append(&migrate.Change{
 		Cmd:     b.String(),
 		Source:  add,
 		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
 		Comment: fmt.Sprintf("create %q table", add.T.Name),
 	})
+	s.mayAppend(reset)
 	return nil
 }
 
@@ -282,10 +331,20 @@ func (s *state) dropTable(drop *schema.DropTable) error {
 		b.P("IF EXISTS")
 	}
 	b.Table(drop.T)
+	// The reverse command is the CREATE TABLE statement, which
+	// may be wrapped with FULLTEXT session configuration.
+	var reverse any = rs.Changes[0].Cmd
+	if len(rs.Changes) > 1 {
+		cmds := make([]string, len(rs.Changes))
+		for i, c := range rs.Changes {
+			cmds[i] = c.Cmd
+		}
+		reverse = cmds
+	}
 	s.append(&migrate.Change{
 		Cmd:     b.String(),
 		Source:  drop,
-		Reverse: rs.Changes[0].Cmd,
+		Reverse: reverse,
 		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
 	})
 	return nil
@@ -294,10 +353,16 @@ func (s *state) dropTable(drop *schema.DropTable) error {
 // modifyTable builds and appends the migration changes for
 // bringing the table into its modified state.
 func (s *state) modifyTable(modify *schema.ModifyTable) error {
-	var changes [2][]schema.Change
+	var (
+		changes    [2][]schema.Change
+		fromP, toP *SystemTimePartition
+	)
 	if len(modify.T.Columns) == 0 {
 		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
 	}
+	if err := s.checkCharsetChanges(modify); err != nil {
+		return err
+	}
 	for _, change := range skipAutoChanges(modify.Changes) {
 		switch change := change.(type) {
 		// Foreign-key modification is translated into 2 steps.
@@ -328,10 +393,53 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			changes[1] = append(changes[1], &schema.AddIndex{
 				I: change.To,
 			})
+		// The SYSTEM_TIME partitioning of system-versioned
+		// tables is modified in a separate statement.
+		case *schema.ModifyAttr:
+			fromV, ok1 := change.From.(*SystemVersioned)
+			toV, ok2 := change.To.(*SystemVersioned)
+			if !ok1 || !ok2 {
+				changes[1] = append(changes[1], change)
+				break
+			}
+			if periodChanged(fromV, toV) {
+				return fmt.Errorf("alter table %q: changing the period columns of system-versioned tables is not supported", modify.T.Name)
+			}
+			fromP, toP = fromV.Partition, toV.Partition
+		case *schema.AddAttr:
+			if v, ok := change.A.(*SystemVersioned); ok {
+				toP = v.Partition
+			}
+			changes[1] = append(changes[1], change)
+		case *schema.DropAttr:
+			if v, ok := change.A.(*SystemVersioned); ok {
+				fromP = v.Partition
+			}
+			changes[1] = append(changes[1], change)
 		default:
 			changes[1] = append(changes[1], change)
 		}
 	}
+	var idxs []*schema.Index
+	for _, c := range changes[1] {
+		if c, ok := c.(*schema.AddIndex); ok {
+			idxs = append(idxs, c.I)
+		}
+	}
+	if err := s.checkIndexes(idxs); err != nil {
+		return fmt.Errorf("alter table %q: %w", modify.T.Name, err)
+	}
+	set, reset, err := s.fullTextSession(idxs)
+	if err != nil {
+		return fmt.Errorf("alter table %q: %w", modify.T.Name, err)
+	}
+	s.mayAppend(set)
+	// Partitioning must be removed before the system versioning is dropped,
+	// and can be added only after the system versioning was added.
+	part := s.partitionChange(modify.T, fromP, toP)
+	if toP == nil {
+		s.mayAppend(part)
+	}
 	for i := range changes {
 		if len(changes[i]) > 0 {
 			if err := s.alterTable(modify.T, changes[i]); err != nil {
@@ -339,9 +447,163 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			}
 		}
 	}
+	if toP != nil {
+		s.mayAppend(part)
+	}
+	s.mayAppend(reset)
+	return nil
+}
+
+// dataChanges plans the given data changes.
+func (s *state) dataChanges(data ...*schema.DataChange) error {
+	for _, d := range data {
+		c, err := sqlx.PlanDataChange(s.Build(), d)
+		if err != nil {
+			return err
+		}
+		s.append(c)
+	}
+	return nil
+}
+
+// partitionChange returns the change for modifying the SYSTEM_TIME
+// partitioning of a system-versioned table, or nil if it was not changed.
+func (s *state) partitionChange(t *schema.Table, from, to *SystemTimePartition) *migrate.Change {
+	if !partitionChanged(from, to) {
+		return nil
+	}
+	build := func(p *SystemTimePartition) string {
+		b := s.Build("ALTER TABLE").Table(t)
+		if p == nil {
+			return b.P("REMOVE PARTITIONING").String()
+		}
+		systemTimePartition(b, p)
+		return b.String()
+	}
+	return &migrate.Change{
+		Cmd:     build(to),
+		Reverse: build(from),
+		Comment: fmt.Sprintf("modify the history partitioning of %q table", t.Name),
+	}
+}
+
+// checkIndexes checks that the indexes to be created are supported by the server.
+func (s *state) checkIndexes(idxs []*schema.Index) error {
+	for _, idx := range idxs {
+		if indexType(idx.Attrs).T == IndexTypeVector && (!s.Maria() || s.LT("11.7")) {
+			return fmt.Errorf("VECTOR index %q is supported only by MariaDB 11.7 and above", idx.Name)
+		}
+	}
+	return s.checkNgramTokenSize(idxs)
+}
+
+// checkNgramTokenSize returns an error in case an ngram FULLTEXT index requires a token
+// size that is different from the one configured on the server, as the index is built
+// using the server option, and it can be configured only on server startup.
+func (s *state) checkNgramTokenSize(idxs []*schema.Index) error {
+	var size int
+	for _, idx := range idxs {
+		var (
+			o FullTextOptions
+			p IndexParser
+		)
+		if !sqlx.Has(idx.Attrs, &o) || o.NgramTokenSize == 0 || !sqlx.Has(idx.Attrs, &p) || !strings.EqualFold(p.P, IndexParserNGram) {
+			continue
+		}
+		if size == 0 {
+			rows, err := s.QueryContext(context.Background(), "SELECT @@ngram_token_size")
+			if err == nil {
+				err = sqlx.ScanOne(rows, &size)
+			}
+			switch {
+			// Planning without a database connection.
+			case errors.Is(err, sql.ErrNoRows):
+				return nil
+			case err != nil:
+				return fmt.Errorf("querying ngram_token_size: %w", err)
+			}
+		}
+		if o.NgramTokenSize != size {
+			return fmt.Errorf("index %q requires ngram_token_size=%d, but %d is configured on the server. The option can be set only on server startup", idx.Name, o.NgramTokenSize, size)
+		}
+	}
 	return nil
 }
 
+// fullTextSession returns the statements for configuring the session stopword options
+// before creating the given FULLTEXT indexes, and for restoring them afterwards.
+func (s *state) fullTextSession(idxs []*schema.Index) (set, reset *migrate.Change, err error) {
+	var (
+		opts *FullTextOptions
+		name string
+	)
+	for _, idx := range idxs {
+		o := &FullTextOptions{}
+		if !sqlx.Has(idx.Attrs, o) || !o.NoStopwords && o.StopwordTable == "" {
+			continue
+		}
+		if opts != nil && (opts.NoStopwords != o.NoStopwords || opts.StopwordTable != o.StopwordTable) {
+			return nil, nil, fmt.Errorf("mismatched stopword options for FULLTEXT indexes %q and %q", name, idx.Name)
+		}
+		opts, name = o, idx.Name
+	}
+	if opts == nil {
+		return nil, nil, nil
+	}
+	var vars [][2]string
+	if opts.NoStopwords {
+		vars = append(vars, [2]string{"innodb_ft_enable_stopword", "OFF"})
+	}
+	if opts.StopwordTable != "" {
+		vars = append(vars, [2]string{"innodb_ft_user_stopword_table", quote(opts.StopwordTable)})
+	}
+	// The current session values are saved in user-defined variables before they
+	// are changed, and restored afterwards. Note, assignments in SET statements
+	// are evaluated from left to right.
+	var (
+		setC = s.Build("SET").MapComma(vars, func(i int, b *sqlx.Builder) {
+			b.P("@atlas_"+vars[i][0], "=", "@@SESSION."+vars[i][0])
+		}).Comma().P("SESSION").MapComma(vars, func(i int, b *sqlx.Builder) {
+			b.P(vars[i][0], "=", vars[i][1])
+		}).String()
+		resetC = s.Build("SET SESSION").MapComma(vars, func(i int, b *sqlx.Builder) {
+			b.P(vars[i][0], "=", "@atlas_"+vars[i][0])
+		}).String()
+	)
+	set = &migrate.Change{
+		Cmd:     setC,
+		Reverse: resetC,
+		Comment: "set stopword options for creating FULLTEXT indexes",
+	}
+	reset = &migrate.Change{
+		Cmd:     resetC,
+		Reverse: setC,
+		Comment: "restore stopword options",
+	}
+	return set, reset, nil
+}
+
+// ConvertCharset describes a table conversion to a new character-set (and collation).
+// Unlike changing the default CHARSET of a table, which affects only columns that are
+// added later, the conversion rewrites the data of all existing character columns.
+type ConvertCharset struct {
+	schema.Change
+	From, To               *schema.Charset
+	FromCollate, ToCollate *schema.Collation // Optional.
+}
+
+// ChangedAttrs implements the sqlx.AttrChanger interface.
+func (c *ConvertCharset) ChangedAttrs() []schema.Attr {
+	attrs := []schema.Attr{c.From, c.To}
+	if c.FromCollate != nil {
+		attrs = append(attrs, c.FromCollate)
+	}
+	if c.ToCollate != nil {
+		attrs = append(attrs, c.ToCollate)
+	}
+	return attrs
+}
+
 // alterTable modifies the given table by executing on it a list of
 // changes in one SQL statement.
 func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
@@ -360,6 +622,21 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 				// will refer to the new table name.
 				name = change.To.Name
 				reverse = append(reverse, &schema.RenameTable{From: change.To, To: change.From})
+			case *ConvertCharset:
+				b.P("CONVERT TO CHARACTER SET", change.To.V)
+				if change.ToCollate != nil {
+					b.P("COLLATE", change.ToCollate.V)
+				}
+				// Converting the data back to its original character-set is lossy (or fails),
+				// unless the original is "utf8mb4", as any character can be represented in it.
+				if reversible = reversible && change.From != nil && change.From.V == "utf8mb4"; reversible {
+					reverse = append(reverse, &ConvertCharset{
+						From:        change.To,
+						To:          change.From,
+						FromCollate: change.ToCollate,
+						ToCollate:   change.FromCollate,
+					})
+				}
 			case *schema.AddColumn:
 				b.P("ADD COLUMN")
 				if err := s.column(b, t, change.C); err != nil {
@@ -429,6 +706,9 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 				reverse = append(reverse, &schema.AddForeignKey{F: change.F})
 			case *schema.AddAttr:
 				s.tableAttrs(b, change, change.A)
+				if _, ok := change.A.(*SystemVersioned); ok {
+					reverse = append(reverse, &schema.DropAttr{A: change.A})
+				}
 			case *schema.DropAttr:
 				s.tableAttrs(b, change, change.A)
 			case *schema.ModifyAttr:
@@ -531,8 +811,12 @@ func (s *state) column(b *sqlx.Builder, t *schema.Table, c *schema.Column) error
 		x   schema.GeneratedExpr
 		asX = sqlx.Has(c.Attrs, &x)
 	)
-	if asX {
+	switch p := rowPeriod(t, c); {
+	case asX:
 		b.P("AS", sqlx.MayWrap(x.Expr), x.Type)
+	case p != "":
+		b.P("GENERATED ALWAYS AS ROW", p)
+		asX = true
 	}
 	// MariaDB does not accept [NOT NULL | NULL]
 	// as part of the generated columns' syntax.
@@ -571,6 +855,15 @@ func (s *state) column(b *sqlx.Builder, t *schema.Table, c *schema.Column) error
 			if a.V > 0 && !sqlx.Has(t.Attrs, &AutoIncrement{}) {
 				t.Attrs = append(t.Attrs, a)
 			}
+		case *AutoRandom:
+			switch {
+			case a.RangeBits > 0:
+				b.P(fmt.Sprintf("AUTO_RANDOM(%d, %d)", autoRandomBits(*a)[0], a.RangeBits))
+			case a.ShardBits > 0:
+				b.P(fmt.Sprintf("AUTO_RANDOM(%d)", a.ShardBits))
+			default:
+				b.P("AUTO_RANDOM")
+			}
 		default:
 			s.attr(b, a)
 		}
@@ -587,11 +880,19 @@ func index(b *sqlx.Builder, idx *schema.Index) {
 			defer func() { b.P("WITH PARSER").Ident(p.P) }()
 		}
 		b.P(t.T)
-	case t.T == IndexTypeSpatial:
+	case t.T == IndexTypeSpatial, t.T == IndexTypeVector:
 		b.P(t.T)
 	}
 	b.P("INDEX").Ident(idx.Name)
 	indexTypeParts(b, idx)
+	if v := (VectorOptions{}); sqlx.Has(idx.Attrs, &v) {
+		if v.M > 0 {
+			b.P("M=" + strconv.Itoa(v.M))
+		}
+		if v.Distance != "" {
+			b.P("DISTANCE=" + strings.ToLower(v.Distance))
+		}
+	}
 	if c := (schema.Comment{}); sqlx.Has(idx.Attrs, &c) {
 		b.P("COMMENT", quote(c.Text))
 	}
@@ -619,6 +920,14 @@ func indexTypeParts(b *sqlx.Builder, idx *schema.Index) {
 			}
 		})
 	})
+	// TiDB clustered index option.
+	if c := (&Clustered{}); sqlx.Has(idx.Attrs, c) {
+		if c.V {
+			b.P("CLUSTERED")
+		} else {
+			b.P("NONCLUSTERED")
+		}
+	}
 }
 
 func (s *state) fks(commaF func(any, func(int, *sqlx.Builder) error) error, fks ...*schema.ForeignKey) error {
@@ -673,6 +982,25 @@ func (s *state) tableAttrs(b *sqlx.Builder, c schema.Change, attrs ...schema.Att
 			if _, ok := c.(*schema.ModifyAttr); ok || !a.Default {
 				b.P("ENGINE", a.V)
 			}
+		case *SystemVersioned:
+			switch c.(type) {
+			case *schema.AddTable:
+				b.P("WITH SYSTEM VERSIONING")
+			case *schema.AddAttr:
+				if a.Start != "" {
+					period(b.P("ADD"), a)
+					b.Comma()
+				}
+				b.P("ADD SYSTEM VERSIONING")
+			case *schema.DropAttr:
+				b.P("DROP SYSTEM VERSIONING")
+			}
+		case *PlacementPolicy:
+			if _, ok := c.(*schema.DropAttr); ok {
+				b.P("PLACEMENT POLICY = DEFAULT")
+			} else {
+				b.P("PLACEMENT POLICY =").Ident(a.V)
+			}
 		case *schema.Check:
 			// Ignore CHECK constraints as they are not real attributes,
 			// and handled on CREATE or ALTER.
@@ -710,6 +1038,12 @@ func (s *state) append(c *migrate.Change) {
 	s.Changes = append(s.Changes, c)
 }
 
+func (s *state) mayAppend(c *migrate.Change) {
+	if c != nil {
+		s.append(c)
+	}
+}
+
 func (*state) attr(b *sqlx.Builder, attrs ...schema.Attr) {
 	for _, a := range attrs {
 		switch a := a.(type) {
@@ -721,6 +1055,49 @@ func (*state) attr(b *sqlx.Builder, attrs ...schema.Attr) {
 	}
 }
 
+// rowPeriod returns the period ("START" or "END") of the given
+// column, in case it is a period column of a system-versioned table.
+func rowPeriod(t *schema.Table, c *schema.Column) string {
+	var v SystemVersioned
+	switch {
+	case !sqlx.Has(t.Attrs, &v):
+		return ""
+	case v.Start != "" && v.Start == c.Name:
+		return "START"
+	case v.End != "" && v.End == c.Name:
+		return "END"
+	default:
+		return ""
+	}
+}
+
+// period writes the SYSTEM_TIME period definition to the builder.
+func period(b *sqlx.Builder, v *SystemVersioned) {
+	b.P("PERIOD FOR SYSTEM_TIME").Wrap(func(b *sqlx.Builder) {
+		b.Ident(v.Start).Comma().Ident(v.End)
+	})
+}
+
+// systemTimePartition writes the SYSTEM_TIME partitioning options to the builder.
+func systemTimePartition(b *sqlx.Builder, p *SystemTimePartition) {
+	b.P("PARTITION BY SYSTEM_TIME")
+	switch {
+	case p.Interval != "":
+		b.P("INTERVAL", p.Interval)
+		if p.Starts != "" {
+			b.P("STARTS", p.Starts)
+		}
+	case p.Limit > 0:
+		b.P("LIMIT", strconv.FormatInt(p.Limit, 10))
+	}
+	if p.Auto {
+		b.P("AUTO")
+	}
+	if p.Partitions > 0 {
+		b.P("PARTITIONS", strconv.Itoa(p.Partitions))
+	}
+}
+
 // columnDefault writes the default value of column to the builder.
 func (s *state) columnDefault(b *sqlx.Builder, c *schema.Column) {
 	switch x := c.Default.(type) {
@@ -803,6 +1180,150 @@ func supportsCharset(t schema.Type) bool {
 	}
 }
 
+// checkCharsetChanges checks that the table indexes do not exceed the maximum key
+// length supported by the storage engine after changing the character-set of the
+// table or its columns, as a character may take up to 4 bytes (e.g., in utf8mb4).
+// If exceeded, a KeyLengthError that proposes prefix lengths to fit the limits is returned.
+func (s *state) checkCharsetChanges(modify *schema.ModifyTable) error {
+	if s.TiDB() || !slices.ContainsFunc(modify.Changes, func(c schema.Change) bool {
+		switch c := c.(type) {
+		case *ConvertCharset:
+			return true
+		case *schema.ModifyColumn:
+			return c.Change.Is(schema.ChangeCharset)
+		}
+		return false
+	}) {
+		return nil
+	}
+	t := modify.T
+	indexes := t.Indexes
+	if t.PrimaryKey != nil {
+		indexes = append([]*schema.Index{t.PrimaryKey}, indexes...)
+	}
+	partMax, keyMax := s.keyLimits(t)
+	for _, idx := range indexes {
+		if t := indexType(idx.Attrs).T; t == IndexTypeFullText || t == IndexTypeSpatial || t == IndexTypeVector {
+			continue
+		}
+		var (
+			sum, max  int
+			prefixes  = make(map[int]int)
+			lens, mbs = make([]int, len(idx.Parts)), make([]int, len(idx.Parts))
+		)
+		for i, p := range idx.Parts {
+			lens[i], mbs[i] = s.partLen(t, p)
+			if lens[i]*mbs[i] > partMax {
+				prefixes[i] = partMax / mbs[i]
+				lens[i] = prefixes[i]
+			}
+			if sum += lens[i] * mbs[i]; lens[i]*mbs[i] > lens[max]*mbs[max] {
+				max = i
+			}
+		}
+		if sum > keyMax {
+			if n := (keyMax - sum + lens[max]*mbs[max]) / mbs[max]; n > 0 {
+				prefixes[max] = n
+			}
+		}
+		if len(prefixes) == 0 && sum <= keyMax {
+			continue
+		}
+		err := &KeyLengthError{T: t, I: idx, PartMax: partMax, KeyMax: keyMax, Prefixes: make(map[string]int, len(prefixes))}
+		for i, n := range prefixes {
+			err.Prefixes[idx.Parts[i].C.Name] = n
+		}
+		return err
+	}
+	return nil
+}
+
+// KeyLengthError is returned by the planner in case an index exceeds the maximum key
+// length after a character-set change. The Prefixes field holds the proposed prefix
+// lengths (in characters) of the index columns, if the index can be fixed this way.
+type KeyLengthError struct {
+	T               *schema.Table
+	I               *schema.Index
+	PartMax, KeyMax int            // Limits in bytes.
+	Prefixes        map[string]int // Column name to prefix length.
+}
+
+// Error implements the error interface.
+func (e *KeyLengthError) Error() string {
+	name := e.I.Name
+	if name == "" && e.I == e.T.PrimaryKey {
+		name = "PRIMARY"
+	}
+	msg := fmt.Sprintf("index %q of table %q exceeds the maximum key length (%d bytes per column and %d bytes per key) after character-set change.", name, e.T.Name, e.PartMax, e.KeyMax)
+	fixes := make([]string, 0, len(e.Prefixes))
+	for _, p := range e.I.Parts {
+		// Prefix lengths are not proposed for functional key parts.
+		if p.C == nil {
+			continue
+		}
+		if n, ok := e.Prefixes[p.C.Name]; ok {
+			fixes = append(fixes, fmt.Sprintf("%s(%d)", p.C.Name, n))
+		}
+	}
+	if len(fixes) == 0 {
+		return msg + " Consider removing columns from the index, as prefix lengths on a single column cannot fit it"
+	}
+	return msg + " Consider using prefix lengths: " + strings.Join(fixes, ", ")
+}
+
+// keyLimits returns the maximum length in bytes of an index column
+// prefix and an index key, based on the table engine and row format.
+func (s *state) keyLimits(t *schema.Table) (part int, key int) {
+	if e := (Engine{}); sqlx.Has(t.Attrs, &e) && strings.EqualFold(e.V, EngineMyISAM) {
+		return 1000, 1000
+	}
+	part, key = 3072, 3072
+	var opts CreateOptions
+	if !s.SupportsLargeIndexPrefix() || sqlx.Has(t.Attrs, &opts) && compactRowFormat.MatchString(opts.V) {
+		part = 767
+	}
+	return part, key
+}
+
+// compactRowFormat matches the InnoDB row formats that limit index
+// column prefixes to 767 bytes. i.e., COMPACT and REDUNDANT.
+var compactRowFormat = regexp.MustCompile(`(?i)row_format\s*=\s*(compact|redundant)\b`)
+
+// partLen returns the length in characters of the given index part, and the maximum
+// number of bytes per character of its column charset. Zeros are returned for parts
+// that are not character-based, or their length cannot be determined.
+func (s *state) partLen(t *schema.Table, p *schema.IndexPart) (int, int) {
+	if p.C == nil || p.C.Type == nil {
+		return 0, 0
+	}
+	st, ok := p.C.Type.Type.(*schema.StringType)
+	if !ok {
+		return 0, 0
+	}
+	n := st.Size
+	if sub := (SubPart{}); sqlx.Has(p.Attrs, &sub) {
+		n = sub.Len
+	} else if st.T != TypeChar && st.T != TypeVarchar {
+		return 0, 0
+	}
+	cs := s.character(t)
+	if c := (schema.Charset{}); sqlx.Has(p.C.Attrs, &c) {
+		cs = c.V
+	}
+	return n, charsetMaxLen[cs]
+}
+
+// charsetMaxLen holds the maximum length in bytes of a character for
+// the built-in character-sets. See, INFORMATION_SCHEMA.CHARACTER_SETS.
+var charsetMaxLen = map[string]int{
+	"armscii8": 1, "ascii": 1, "big5": 2, "binary": 1, "cp1250": 1, "cp1251": 1, "cp1256": 1,
+	"cp1257": 1, "cp850": 1, "cp852": 1, "cp866": 1, "cp932": 2, "dec8": 1, "eucjpms": 3,
+	"euckr": 2, "gb18030": 4, "gb2312": 2, "gbk": 2, "geostd8": 1, "greek": 1, "hebrew": 1,
+	"hp8": 1, "keybcs2": 1, "koi8r": 1, "koi8u": 1, "latin1": 1, "latin2": 1, "latin5": 1,
+	"latin7": 1, "macce": 1, "macroman": 1, "sjis": 2, "swe7": 1, "tis620": 1, "ucs2": 2,
+	"ujis": 3, "utf16": 4, "utf16le": 4, "utf32": 4, "utf8": 3, "utf8mb3": 3, "utf8mb4": 4,
+}
+
 // checkChangeGenerated checks if the change of a generated column is valid.
 func checkChangeGenerated(from, to *schema.Column) error {
 	var fromX, toX schema.GeneratedExpr
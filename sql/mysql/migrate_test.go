@@ -880,6 +880,137 @@ func TestPlanChanges(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			version: "11.7.2-MariaDB",
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: func() *schema.Table {
+						t := schema.NewTable("docs").
+							AddColumns(
+								schema.NewIntColumn("id", "bigint"),
+								schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Size: 1536}),
+							)
+						return t.AddIndexes(
+							schema.NewIndex("embedding").
+								AddColumns(t.Columns[1]).
+								AddAttrs(&IndexType{T: IndexTypeVector}, &VectorOptions{M: 8, Distance: VectorDistanceCosine}),
+						)
+					}(),
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `docs` (`id` bigint NOT NULL, `embedding` vector(1536) NOT NULL, VECTOR INDEX `embedding` (`embedding`) M=8 DISTANCE=cosine)",
+						Reverse: "DROP TABLE `docs`",
+					},
+				},
+			},
+		},
+		// Stopword options are set on the session before creating FULLTEXT indexes.
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("posts").AddColumns(schema.NewStringColumn("text", "text")),
+					Changes: []schema.Change{
+						&schema.AddIndex{
+							I: schema.NewIndex("text").
+								AddColumns(schema.NewStringColumn("text", "text")).
+								AddAttrs(&IndexType{T: IndexTypeFullText}, &FullTextOptions{NoStopwords: true, StopwordTable: "test/stopwords"}),
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "SET @atlas_innodb_ft_enable_stopword = @@SESSION.innodb_ft_enable_stopword, @atlas_innodb_ft_user_stopword_table = @@SESSION.innodb_ft_user_stopword_table, SESSION innodb_ft_enable_stopword = OFF, innodb_ft_user_stopword_table = \"test/stopwords\"",
+						Reverse: "SET SESSION innodb_ft_enable_stopword = @atlas_innodb_ft_enable_stopword, innodb_ft_user_stopword_table = @atlas_innodb_ft_user_stopword_table",
+					},
+					{
+						Cmd:     "ALTER TABLE `posts` ADD FULLTEXT INDEX `text` (`text`)",
+						Reverse: "ALTER TABLE `posts` DROP INDEX `text`",
+					},
+					{
+						Cmd:     "SET SESSION innodb_ft_enable_stopword = @atlas_innodb_ft_enable_stopword, innodb_ft_user_stopword_table = @atlas_innodb_ft_user_stopword_table",
+						Reverse: "SET @atlas_innodb_ft_enable_stopword = @@SESSION.innodb_ft_enable_stopword, @atlas_innodb_ft_user_stopword_table = @@SESSION.innodb_ft_user_stopword_table, SESSION innodb_ft_enable_stopword = OFF, innodb_ft_user_stopword_table = \"test/stopwords\"",
+					},
+				},
+			},
+		},
+		// Convert the table and its columns to a new charset.
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: func() *schema.Table {
+						t := schema.NewTable("users").
+							SetCharset("utf8mb4").
+							AddColumns(schema.NewStringColumn("name", "varchar", schema.StringSize(191)))
+						return t.AddIndexes(schema.NewUniqueIndex("name").AddColumns(t.Columns[0]))
+					}(),
+					Changes: []schema.Change{
+						&ConvertCharset{
+							From:        &schema.Charset{V: "utf8"},
+							To:          &schema.Charset{V: "utf8mb4"},
+							FromCollate: &schema.Collation{V: "utf8_general_ci"},
+							ToCollate:   &schema.Collation{V: "utf8mb4_0900_ai_ci"},
+						},
+					},
+				},
+			},
+			// Converting utf8mb4 back to utf8 is lossy.
+			wantPlan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{
+						Cmd: "ALTER TABLE `users` CONVERT TO CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci",
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("users").
+						SetCharset("latin1").
+						AddColumns(schema.NewStringColumn("name", "varchar", schema.StringSize(191))),
+					Changes: []schema.Change{
+						&ConvertCharset{
+							From: &schema.Charset{V: "utf8mb4"},
+							To:   &schema.Charset{V: "latin1"},
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` CONVERT TO CHARACTER SET latin1",
+						Reverse: "ALTER TABLE `users` CONVERT TO CHARACTER SET utf8mb4",
+					},
+				},
+			},
+		},
+		// Index exceeds the 767 bytes column prefix limit after conversion.
+		{
+			version: "5.6.35",
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: func() *schema.Table {
+						t := schema.NewTable("users").
+							SetCharset("utf8mb4").
+							AddColumns(schema.NewStringColumn("name", "varchar", schema.StringSize(255)))
+						return t.AddIndexes(schema.NewUniqueIndex("name").AddColumns(t.Columns[0]))
+					}(),
+					Changes: []schema.Change{
+						&ConvertCharset{From: &schema.Charset{V: "utf8"}, To: &schema.Charset{V: "utf8mb4"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		// Changing a regular column to a VIRTUAL generated column is not allowed.
 		{
 			changes: []schema.Change{
@@ -1172,6 +1303,277 @@ func TestPlanChanges(t *testing.T) {
 	}
 }
 
+func TestPlanChanges_KeyLength(t *testing.T) {
+	tbl := schema.NewTable("users").
+		SetCharset("latin1").
+		AddColumns(
+			schema.NewStringColumn("first", "varchar", schema.StringSize(500)),
+			schema.NewStringColumn("last", "varchar", schema.StringSize(700)).SetCharset("utf8mb4"),
+		)
+	tbl.AddIndexes(schema.NewIndex("full_name").AddColumns(tbl.Columns...))
+	for v, want := range map[string]string{
+		"8.0.31":              `index "full_name" of table "users" exceeds the maximum key length (3072 bytes per column and 3072 bytes per key) after character-set change. Consider using prefix lengths: last(643)`,
+		"5.7.6":               `index "full_name" of table "users" exceeds the maximum key length (767 bytes per column and 3072 bytes per key) after character-set change. Consider using prefix lengths: last(191)`,
+		"10.2.1-MariaDB-1:10": `index "full_name" of table "users" exceeds the maximum key length (767 bytes per column and 3072 bytes per key) after character-set change. Consider using prefix lengths: last(191)`,
+	} {
+		db, _, err := newMigrate(v)
+		require.NoError(t, err)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{
+				T: tbl,
+				Changes: []schema.Change{
+					&schema.ModifyColumn{
+						From:   schema.NewStringColumn("last", "varchar", schema.StringSize(700)),
+						To:     tbl.Columns[1],
+						Change: schema.ChangeCharset,
+					},
+				},
+			},
+		})
+		require.EqualError(t, err, want, v)
+		var kerr *KeyLengthError
+		require.ErrorAs(t, err, &kerr)
+		require.Contains(t, kerr.Prefixes, "last", v)
+	}
+	t.Run("Key", func(t *testing.T) {
+		tbl := schema.NewTable("users").
+			AddColumns(
+				schema.NewStringColumn("first", "varchar", schema.StringSize(700)).SetCharset("utf8mb4"),
+				schema.NewStringColumn("last", "varchar", schema.StringSize(100)).SetCharset("utf8mb4"),
+			)
+		tbl.AddIndexes(schema.NewIndex("full_name").AddColumns(tbl.Columns...))
+		db, _, err := newMigrate("8.0.31")
+		require.NoError(t, err)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{
+				T: tbl,
+				Changes: []schema.Change{
+					&ConvertCharset{From: &schema.Charset{V: "latin1"}, To: &schema.Charset{V: "utf8mb4"}},
+				},
+			},
+		})
+		require.EqualError(t, err, `index "full_name" of table "users" exceeds the maximum key length (3072 bytes per column and 3072 bytes per key) after character-set change. Consider using prefix lengths: first(668)`)
+	})
+	t.Run("Expr", func(t *testing.T) {
+		tbl := schema.NewTable("users").
+			AddColumns(
+				schema.NewStringColumn("first", "varchar", schema.StringSize(1000)).SetCharset("utf8mb4"),
+				schema.NewStringColumn("last", "varchar", schema.StringSize(100)).SetCharset("utf8mb4"),
+			)
+		tbl.AddIndexes(
+			schema.NewIndex("full_name").
+				AddParts(
+					schema.NewExprPart(&schema.RawExpr{X: "(lower(`last`))"}),
+					schema.NewColumnPart(tbl.Columns[0]).AddAttrs(&SubPart{Len: 900}),
+				),
+		)
+		db, _, err := newMigrate("8.0.31")
+		require.NoError(t, err)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{
+				T: tbl,
+				Changes: []schema.Change{
+					&ConvertCharset{From: &schema.Charset{V: "latin1"}, To: &schema.Charset{V: "utf8mb4"}},
+				},
+			},
+		})
+		require.EqualError(t, err, `index "full_name" of table "users" exceeds the maximum key length (3072 bytes per column and 3072 bytes per key) after character-set change. Consider using prefix lengths: first(768)`)
+	})
+	t.Run("NoProposal", func(t *testing.T) {
+		tbl := schema.NewTable("users").
+			AddColumns(
+				schema.NewStringColumn("a", "varchar", schema.StringSize(700)).SetCharset("utf8mb4"),
+				schema.NewStringColumn("b", "varchar", schema.StringSize(700)).SetCharset("utf8mb4"),
+				schema.NewStringColumn("c", "varchar", schema.StringSize(700)).SetCharset("utf8mb4"),
+			)
+		tbl.AddIndexes(schema.NewIndex("abc").AddColumns(tbl.Columns...))
+		db, _, err := newMigrate("8.0.31")
+		require.NoError(t, err)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{
+				T: tbl,
+				Changes: []schema.Change{
+					&ConvertCharset{From: &schema.Charset{V: "latin1"}, To: &schema.Charset{V: "utf8mb4"}},
+				},
+			},
+		})
+		require.EqualError(t, err, `index "abc" of table "users" exceeds the maximum key length (3072 bytes per column and 3072 bytes per key) after character-set change. Consider removing columns from the index, as prefix lengths on a single column cannot fit it`)
+		var kerr *KeyLengthError
+		require.ErrorAs(t, err, &kerr)
+		require.Empty(t, kerr.Prefixes)
+	})
+}
+
+func TestPlanChanges_NgramTokenSize(t *testing.T) {
+	tbl := schema.NewTable("posts").AddColumns(schema.NewStringColumn("text", "text"))
+	tbl.AddIndexes(
+		schema.NewIndex("text").
+			AddColumns(tbl.Columns[0]).
+			AddAttrs(&IndexType{T: IndexTypeFullText}, &IndexParser{P: IndexParserNGram}, &FullTextOptions{NgramTokenSize: 3}),
+	)
+	for size, wantErr := range map[string]string{
+		"3": "",
+		"2": `create table "posts": index "text" requires ngram_token_size=3, but 2 is configured on the server. The option can be set only on server startup`,
+	} {
+		db, m, err := newMigrate("8.0.31")
+		require.NoError(t, err)
+		m.ExpectQuery(sqltest.Escape("SELECT @@ngram_token_size")).
+			WillReturnRows(sqlmock.NewRows([]string{"@@ngram_token_size"}).AddRow(size))
+		plan, err := db.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+		if wantErr != "" {
+			require.EqualError(t, err, wantErr)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, "CREATE TABLE `posts` (`text` text NOT NULL, FULLTEXT INDEX `text` (`text`) WITH PARSER `ngram`)", plan.Changes[0].Cmd)
+	}
+
+	// Offline planning.
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+}
+
+func TestPlanChanges_VectorIndex(t *testing.T) {
+	tbl := schema.NewTable("docs").AddColumns(schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Size: 3}))
+	tbl.AddIndexes(schema.NewIndex("embedding").AddColumns(tbl.Columns[0]).AddAttrs(&IndexType{T: IndexTypeVector}))
+	for _, v := range []string{"9.0.1", "11.6.2-MariaDB"} {
+		db, _, err := newMigrate(v)
+		require.NoError(t, err)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+		require.EqualError(t, err, `create table "docs": VECTOR index "embedding" is supported only by MariaDB 11.7 and above`, v)
+		_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+			&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.AddIndex{I: tbl.Indexes[0]}}},
+		})
+		require.EqualError(t, err, `alter table "docs": VECTOR index "embedding" is supported only by MariaDB 11.7 and above`, v)
+	}
+}
+
+func TestPlanChanges_SystemVersioned(t *testing.T) {
+	db, _, err := newMigrate("10.11.2-MariaDB")
+	require.NoError(t, err)
+	tbl := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewTimeColumn("start_ts", "timestamp", schema.TimePrecision(6)),
+			schema.NewTimeColumn("end_ts", "timestamp", schema.TimePrecision(6)),
+		).
+		AddAttrs(&SystemVersioned{Start: "start_ts", End: "end_ts", Partition: &SystemTimePartition{Interval: "1 MONTH", Auto: true}})
+	plan, err := db.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "CREATE TABLE `users` (`id` int NOT NULL, `start_ts` timestamp(6) GENERATED ALWAYS AS ROW START, `end_ts` timestamp(6) GENERATED ALWAYS AS ROW END, PERIOD FOR SYSTEM_TIME (`start_ts`, `end_ts`)) WITH SYSTEM VERSIONING PARTITION BY SYSTEM_TIME INTERVAL 1 MONTH AUTO", plan.Changes[0].Cmd)
+
+	plan, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+			&schema.AddColumn{C: tbl.Columns[1]},
+			&schema.AddColumn{C: tbl.Columns[2]},
+			&schema.AddAttr{A: tbl.Attrs[0]},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "ALTER TABLE `users` ADD COLUMN `start_ts` timestamp(6) GENERATED ALWAYS AS ROW START, ADD COLUMN `end_ts` timestamp(6) GENERATED ALWAYS AS ROW END, ADD PERIOD FOR SYSTEM_TIME (`start_ts`, `end_ts`), ADD SYSTEM VERSIONING", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE `users` DROP SYSTEM VERSIONING, DROP COLUMN `end_ts`, DROP COLUMN `start_ts`", plan.Changes[0].Reverse)
+	require.Equal(t, "ALTER TABLE `users` PARTITION BY SYSTEM_TIME INTERVAL 1 MONTH AUTO", plan.Changes[1].Cmd)
+	require.Equal(t, "ALTER TABLE `users` REMOVE PARTITIONING", plan.Changes[1].Reverse)
+
+	plan, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.DropAttr{A: tbl.Attrs[0]}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "ALTER TABLE `users` REMOVE PARTITIONING", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE `users` DROP SYSTEM VERSIONING", plan.Changes[1].Cmd)
+
+	plan, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.ModifyAttr{
+			From: &SystemVersioned{Partition: &SystemTimePartition{Limit: 1000, Partitions: 3}},
+			To:   &SystemVersioned{Partition: &SystemTimePartition{Interval: "1 WEEK"}},
+		}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "ALTER TABLE `users` PARTITION BY SYSTEM_TIME INTERVAL 1 WEEK", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE `users` PARTITION BY SYSTEM_TIME LIMIT 1000 PARTITIONS 3", plan.Changes[0].Reverse)
+
+	_, err = db.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.ModifyAttr{
+			From: &SystemVersioned{},
+			To:   &SystemVersioned{Start: "start_ts", End: "end_ts"},
+		}}},
+	})
+	require.EqualError(t, err, `alter table "users": changing the period columns of system-versioned tables is not supported`)
+}
+
+func TestPlanChanges_DropFullText(t *testing.T) {
+	tbl := schema.NewTable("posts").AddColumns(schema.NewStringColumn("text", "text"))
+	tbl.AddIndexes(
+		schema.NewIndex("text").
+			AddColumns(tbl.Columns[0]).
+			AddAttrs(&IndexType{T: IndexTypeFullText}, &FullTextOptions{NoStopwords: true}),
+	)
+	db, _, err := newMigrate("8.0.31")
+	require.NoError(t, err)
+	plan, err := db.PlanChanges(context.Background(), "plan", []schema.Change{&schema.DropTable{T: tbl}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "DROP TABLE `posts`", plan.Changes[0].Cmd)
+	require.Equal(t, []string{
+		"SET @atlas_innodb_ft_enable_stopword = @@SESSION.innodb_ft_enable_stopword, SESSION innodb_ft_enable_stopword = OFF",
+		"CREATE TABLE `posts` (`text` text NOT NULL, FULLTEXT INDEX `text` (`text`))",
+		"SET SESSION innodb_ft_enable_stopword = @atlas_innodb_ft_enable_stopword",
+	}, plan.Changes[0].Reverse)
+}
+
+func TestPlanChanges_Policy(t *testing.T) {
+	var (
+		users   = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		changes = []schema.Change{&schema.DropTable{T: users}}
+		policy  = &schema.ChangePolicy{
+			Rules: map[schema.PolicyKind]schema.PolicyAction{schema.PolicyDropTable: schema.PolicyBlock},
+		}
+	)
+	_, err := DefaultPlan.PlanChanges(context.Background(), "plan", changes, func(o *migrate.PlanOptions) {
+		o.Policy = policy
+	})
+	require.EqualError(t, err, `sql/schema: change policy blocked 1 change(s): drop table "users"`)
+
+	var warned []*schema.PolicyViolation
+	policy.Rules[schema.PolicyDropTable] = schema.PolicyWarn
+	policy.Warn = func(v *schema.PolicyViolation) { warned = append(warned, v) }
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", changes, func(o *migrate.PlanOptions) {
+		o.Policy = policy
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "DROP TABLE `users`", plan.Changes[0].Cmd)
+	require.Len(t, warned, 1)
+	require.Equal(t, changes[0], warned[0].Change)
+}
+
+func TestPlanChanges_DataChange(t *testing.T) {
+	var (
+		name  = schema.NewStringColumn("name", "varchar(255)")
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), name)
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: name},
+				&schema.DataChange{T: users, C: name, Expr: &schema.Literal{V: "''"}, Where: "`id` > 0"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 3)
+	require.Equal(t, "ALTER TABLE `users` ADD COLUMN `name` varchar(255) NULL", plan.Changes[0].Cmd)
+	require.Equal(t, "UPDATE `users` SET `name` = '' WHERE `id` > 0", plan.Changes[1].Cmd)
+	require.Equal(t, "ALTER TABLE `users` MODIFY COLUMN `name` varchar(255) NOT NULL", plan.Changes[2].Cmd)
+	require.IsType(t, (*schema.DataChange)(nil), plan.Changes[1].Source)
+}
+
 func TestDefaultPlan(t *testing.T) {
 	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
 		&schema.AddTable{T: schema.NewTable("t1").SetSchema(schema.New("s1")).AddColumns(schema.NewIntColumn("a", "int"))},
@@ -16,6 +16,7 @@ import (
 	"ariga.io/atlas/sql/mysql"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/classified"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
@@ -222,7 +223,11 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc, sqlcheck.AnalyzerFunc(inlineRefs)}, nil
+	cl, err := classified.New(r)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Analyzer{ds, dd, cd, bc, cl, sqlcheck.AnalyzerFunc(inlineRefs)}, nil
 }
 
 func init() {
@@ -0,0 +1,422 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// WithShowCreate returns an Option that enables the 'SHOW CREATE TABLE' command as an
+// additional inspection source. When enabled, the statement of each inspected table is
+// parsed and used to recover details that are omitted or mangled by information_schema,
+// such as the exact text of expression defaults, the order of columns, the parsers of
+// FULLTEXT indexes and the (uncached) table options.
+func WithShowCreate() Option {
+	return func(c *conn) {
+		c.showCreate = true
+	}
+}
+
+type (
+	// createTable represents a parsed 'CREATE TABLE' statement, as returned by
+	// the 'SHOW CREATE TABLE' command. Note, the parser is not a general-purpose
+	// SQL parser, and it relies on the canonical format printed by the server.
+	createTable struct {
+		Name    string
+		Columns []*createColumn
+		Indexes []*createIndex
+		Options []*createOption
+	}
+
+	// createColumn represents a column definition.
+	createColumn struct {
+		Name    string
+		Type    string // Type definition, e.g. "varchar(255)".
+		Default string // Exact text of the DEFAULT clause, if exists.
+	}
+
+	// createIndex represents an index definition.
+	createIndex struct {
+		Name   string // Empty for primary keys.
+		Type   string // PRIMARY, UNIQUE, FULLTEXT, SPATIAL, VECTOR or empty.
+		Parts  []string
+		Parser string // FULLTEXT parser, if exists.
+	}
+
+	// createOption represents a table option, e.g. ENGINE=InnoDB.
+	createOption struct {
+		K, V string
+	}
+)
+
+// Option returns the value of the given table option, if exists.
+func (c *createTable) Option(k string) (string, bool) {
+	for _, o := range c.Options {
+		if strings.EqualFold(o.K, k) {
+			return o.V, true
+		}
+	}
+	return "", false
+}
+
+// parseCreateTable parses the output of the 'SHOW CREATE TABLE' command.
+func parseCreateTable(stmt string) (*createTable, error) {
+	stmt = strings.TrimSpace(stmt)
+	start, end := strings.IndexByte(stmt, '('), strings.LastIndex(stmt, "\n)")
+	if !strings.HasPrefix(strings.ToUpper(stmt), "CREATE TABLE") || start == -1 || end < start {
+		return nil, fmt.Errorf("mysql: unexpected CREATE TABLE statement: %q", stmt)
+	}
+	name, err := unquoteIdent(strings.TrimSpace(stmt[len("CREATE TABLE"):start]))
+	if err != nil {
+		return nil, err
+	}
+	c := &createTable{Name: name}
+	// Each definition is printed in a separate line.
+	for _, line := range strings.Split(stmt[start+1:end], "\n") {
+		def := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if def == "" {
+			continue
+		}
+		tks, err := scanTokens(def)
+		if err != nil {
+			return nil, fmt.Errorf("mysql: scan definition %q: %w", def, err)
+		}
+		switch first := strings.ToUpper(tks[0]); {
+		case strings.HasPrefix(tks[0], "`"):
+			col, err := parseCreateColumn(tks)
+			if err != nil {
+				return nil, err
+			}
+			c.Columns = append(c.Columns, col)
+		case first == "CONSTRAINT", first == "PERIOD":
+			// Foreign keys and checks are inspected from information_schema.
+		default:
+			idx, err := parseCreateIndex(tks)
+			if err != nil {
+				return nil, err
+			}
+			c.Indexes = append(c.Indexes, idx)
+		}
+	}
+	c.Options, err = parseCreateOptions(stmt[end+2:])
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseCreateColumn parses the tokens of a column definition.
+func parseCreateColumn(tks []string) (*createColumn, error) {
+	name, err := unquoteIdent(tks[0])
+	if err != nil {
+		return nil, err
+	}
+	c := &createColumn{Name: name}
+	// The type definition ends with the first token that is not
+	// a type argument or modifier, e.g. NOT NULL or DEFAULT.
+	i := 2
+	for i < len(tks) && (strings.HasPrefix(tks[i], "(") || isTypeModifier(tks[i])) {
+		i++
+	}
+	c.Type = strings.ReplaceAll(strings.Join(tks[1:min(i, len(tks))], " "), " (", "(")
+	for ; i < len(tks); i++ {
+		if strings.EqualFold(tks[i], "DEFAULT") && i+1 < len(tks) {
+			c.Default = tks[i+1]
+			// Functions are printed without a space before their
+			// arguments, e.g. "CURRENT_TIMESTAMP(6)".
+			if i+2 < len(tks) && strings.HasPrefix(tks[i+2], "(") && !strings.HasPrefix(c.Default, "(") {
+				c.Default += tks[i+2]
+			}
+			break
+		}
+	}
+	return c, nil
+}
+
+// isTypeModifier reports if the token is a modifier of a numeric type.
+func isTypeModifier(t string) bool {
+	switch strings.ToUpper(t) {
+	case "UNSIGNED", "SIGNED", "ZEROFILL":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseCreateIndex parses the tokens of an index definition.
+func parseCreateIndex(tks []string) (*createIndex, error) {
+	idx := &createIndex{}
+	i := 0
+	switch t := strings.ToUpper(tks[0]); t {
+	case "PRIMARY", "UNIQUE", "FULLTEXT", "SPATIAL", "VECTOR":
+		idx.Type, i = t, 1
+	}
+	if i >= len(tks) || !strings.EqualFold(tks[i], "KEY") && !strings.EqualFold(tks[i], "INDEX") {
+		return nil, fmt.Errorf("mysql: unexpected index definition: %q", strings.Join(tks, " "))
+	}
+	i++
+	if idx.Type != "PRIMARY" && i < len(tks) && strings.HasPrefix(tks[i], "`") {
+		name, err := unquoteIdent(tks[i])
+		if err != nil {
+			return nil, err
+		}
+		idx.Name = name
+		i++
+	}
+	if i >= len(tks) || !strings.HasPrefix(tks[i], "(") {
+		return nil, fmt.Errorf("mysql: missing parts for index definition: %q", strings.Join(tks, " "))
+	}
+	parts, err := scanTokens(tks[i][1 : len(tks[i])-1])
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range splitComma(parts) {
+		idx.Parts = append(idx.Parts, strings.ReplaceAll(strings.Join(p, " "), " (", "("))
+	}
+	for i++; i < len(tks); i++ {
+		// e.g. /*!50100 WITH PARSER `ngram` */
+		if strings.HasPrefix(tks[i], "/*") {
+			if m := reIndexParser.FindStringSubmatch(tks[i]); len(m) == 2 {
+				idx.Parser = m[1]
+			}
+		}
+	}
+	return idx, nil
+}
+
+// parseCreateOptions parses the table options that follow the table definitions.
+func parseCreateOptions(s string) ([]*createOption, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	tks, err := scanTokens(s)
+	if err != nil {
+		return nil, fmt.Errorf("mysql: scan table options %q: %w", s, err)
+	}
+	var opts []*createOption
+	for i := 0; i < len(tks); i++ {
+		switch k := strings.ToUpper(tks[i]); {
+		// Partitioning options and versioned comments (e.g. TiDB)
+		// are not table options, and are not part of the parsing.
+		case k == "PARTITION", strings.HasPrefix(k, "/*"):
+			return opts, nil
+		case k == "DEFAULT":
+		case i+2 < len(tks) && tks[i+1] == "=":
+			// Multi-words options are joined by the parser, and
+			// the DEFAULT prefix (e.g. DEFAULT CHARSET) is omitted.
+			opts = append(opts, &createOption{K: k, V: unquoteOption(tks[i+2])})
+			i += 2
+		default:
+			// Options without values, e.g. WITH SYSTEM VERSIONING.
+			opts = append(opts, &createOption{K: k})
+		}
+	}
+	return opts, nil
+}
+
+// scanTokens splits the given definition into tokens. Quoted strings, quoted
+// identifiers and parenthesized expressions are returned as single tokens, as
+// well as comments. The "=" sign is always returned as a separate token.
+func scanTokens(s string) ([]string, error) {
+	var tks []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == ',' || c == '=':
+			tks = append(tks, string(c))
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			j, err := skipQuoted(s, i)
+			if err != nil {
+				return nil, err
+			}
+			// Literals prefixed with a charset introducer (e.g. _utf8mb4'a')
+			// or a type (e.g. b'01' or TIMESTAMP'...') are a single token.
+			if n := len(tks); n > 0 && i > 0 && c == '\'' && isWordChar(s[i-1]) {
+				tks[n-1] += s[i:j]
+			} else {
+				tks = append(tks, s[i:j])
+			}
+			i = j
+		case c == '(':
+			j, err := skipParens(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tks = append(tks, s[i:j])
+			i = j
+		case strings.HasPrefix(s[i:], "/*"):
+			j := strings.Index(s[i:], "*/")
+			if j == -1 {
+				return nil, fmt.Errorf("unclosed comment at position %d", i)
+			}
+			tks = append(tks, s[i:i+j+2])
+			i += j + 2
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\r,=('\"`", rune(s[j])) && !strings.HasPrefix(s[j:], "/*") {
+				j++
+			}
+			tks = append(tks, s[i:j])
+			i = j
+		}
+	}
+	if len(tks) == 0 {
+		return nil, fmt.Errorf("empty definition")
+	}
+	return tks, nil
+}
+
+// skipQuoted returns the position after the quoted string that starts at i.
+func skipQuoted(s string, i int) (int, error) {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && q != '`':
+			j++
+		case s[j] == q && j+1 < len(s) && s[j+1] == q:
+			j++
+		case s[j] == q:
+			return j + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unclosed quote at position %d", i)
+}
+
+// skipParens returns the position after the parenthesized expression that starts at i.
+func skipParens(s string, i int) (int, error) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\'', '"', '`':
+			k, err := skipQuoted(s, j)
+			if err != nil {
+				return 0, err
+			}
+			j = k - 1
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return j + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed parentheses at position %d", i)
+}
+
+// splitComma splits the tokens by commas.
+func splitComma(tks []string) [][]string {
+	var (
+		parts [][]string
+		curr  []string
+	)
+	for _, t := range tks {
+		if t == "," {
+			parts = append(parts, curr)
+			curr = nil
+			continue
+		}
+		curr = append(curr, t)
+	}
+	return append(parts, curr)
+}
+
+// unquoteIdent unquotes a backtick-quoted identifier.
+func unquoteIdent(s string) (string, error) {
+	if !sqlx.IsQuoted(s, '`') {
+		return "", fmt.Errorf("mysql: unexpected identifier: %q", s)
+	}
+	return strings.ReplaceAll(s[1:len(s)-1], "``", "`"), nil
+}
+
+// unquoteOption unquotes the value of a table option, if it is quoted.
+func unquoteOption(s string) string {
+	switch {
+	case sqlx.IsQuoted(s, '`'):
+		return strings.ReplaceAll(s[1:len(s)-1], "``", "`")
+	case sqlx.IsQuoted(s, '\''):
+		if u, err := sqlx.Unquote(s); err == nil {
+			return unescape(u)
+		}
+	}
+	return s
+}
+
+// patchCreateTable updates the inspected table from its parsed 'CREATE TABLE' statement.
+func (i *inspect) patchCreateTable(ctx context.Context, t *schema.Table) error {
+	var c CreateStmt
+	if !sqlx.Has(t.Attrs, &c) {
+		stmt, err := i.createStmt(ctx, t)
+		if err != nil {
+			return err
+		}
+		c = *stmt
+	}
+	ct, err := parseCreateTable(c.S)
+	if err != nil {
+		return fmt.Errorf("mysql: parse CREATE TABLE %q: %w", t.Name, err)
+	}
+	columns := make([]*schema.Column, 0, len(t.Columns))
+	for _, cc := range ct.Columns {
+		col, ok := t.Column(cc.Name)
+		// Invisible columns (e.g. the implicit period columns of
+		// system-versioned tables) are not part of the inspection.
+		if !ok {
+			continue
+		}
+		columns = append(columns, col)
+		// Expression defaults are kept as written in the CREATE statement,
+		// as information_schema returns them without their parentheses and
+		// with escaped quotes.
+		if strings.HasPrefix(cc.Default, "(") {
+			col.Default = &schema.RawExpr{X: cc.Default}
+		}
+	}
+	// Keep columns that were not found in the statement (unexpected).
+	for _, col := range t.Columns {
+		if _, ok := ct.column(col.Name); !ok {
+			columns = append(columns, col)
+		}
+	}
+	t.Columns = columns
+	for _, ci := range ct.Indexes {
+		if ci.Name == "" || ci.Parser == "" {
+			continue
+		}
+		idx, ok := t.Index(ci.Name)
+		if !ok {
+			continue
+		}
+		schema.ReplaceOrAppend(&idx.Attrs, &IndexParser{P: ci.Parser})
+	}
+	// The AUTO_INCREMENT value in information_schema may be cached (see
+	// information_schema_stats_expiry), unlike the one in the statement.
+	if v, ok := ct.Option("AUTO_INCREMENT"); ok {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("mysql: parse AUTO_INCREMENT of table %q: %w", t.Name, err)
+		}
+		schema.ReplaceOrAppend(&t.Attrs, &AutoIncrement{V: n})
+	}
+	return nil
+}
+
+// column returns the column definition with the given name, if exists.
+func (c *createTable) column(name string) (*createColumn, bool) {
+	for _, cc := range c.Columns {
+		if cc.Name == name {
+			return cc, true
+		}
+	}
+	return nil, false
+}
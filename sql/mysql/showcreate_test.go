@@ -0,0 +1,184 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	tests := []struct {
+		stmt    string
+		want    *createTable
+		wantErr bool
+	}{
+		{
+			stmt: "CREATE TABLE `users` (\n" +
+				"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+				"  `name` varchar(255) COLLATE utf8mb4_bin DEFAULT 'a''b, (c)',\n" +
+				"  `tags` json DEFAULT (json_array(_utf8mb4'a',_utf8mb4'b')),\n" +
+				"  `created_at` timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),\n" +
+				"  `d` decimal(10,2) unsigned zerofill NOT NULL,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `name` (`name`(10),`id` DESC),\n" +
+				"  KEY `expr` ((lower(`name`))),\n" +
+				"  FULLTEXT KEY `ft` (`name`) /*!50100 WITH PARSER `ngram` */ ,\n" +
+				"  CONSTRAINT `users_chk_1` CHECK ((`id` > 0))\n" +
+				") ENGINE=InnoDB AUTO_INCREMENT=100 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci STATS_PERSISTENT=0 COMMENT='it''s = a comment'\n" +
+				"/*!50100 PARTITION BY HASH (`id`)\nPARTITIONS 4 */",
+			want: &createTable{
+				Name: "users",
+				Columns: []*createColumn{
+					{Name: "id", Type: "bigint unsigned"},
+					{Name: "name", Type: "varchar(255)", Default: "'a''b, (c)'"},
+					{Name: "tags", Type: "json", Default: "(json_array(_utf8mb4'a',_utf8mb4'b'))"},
+					{Name: "created_at", Type: "timestamp(6)", Default: "CURRENT_TIMESTAMP(6)"},
+					{Name: "d", Type: "decimal(10,2) unsigned zerofill"},
+				},
+				Indexes: []*createIndex{
+					{Type: "PRIMARY", Parts: []string{"`id`"}},
+					{Type: "UNIQUE", Name: "name", Parts: []string{"`name`(10)", "`id` DESC"}},
+					{Name: "expr", Parts: []string{"(lower(`name`))"}},
+					{Type: "FULLTEXT", Name: "ft", Parts: []string{"`name`"}, Parser: "ngram"},
+				},
+				Options: []*createOption{
+					{K: "ENGINE", V: "InnoDB"},
+					{K: "AUTO_INCREMENT", V: "100"},
+					{K: "CHARSET", V: "utf8mb4"},
+					{K: "COLLATE", V: "utf8mb4_0900_ai_ci"},
+					{K: "STATS_PERSISTENT", V: "0"},
+					{K: "COMMENT", V: "it's = a comment"},
+				},
+			},
+		},
+		{
+			stmt: "CREATE TABLE `t` (\n  `c` int DEFAULT NULL\n)",
+			want: &createTable{
+				Name:    "t",
+				Columns: []*createColumn{{Name: "c", Type: "int", Default: "NULL"}},
+			},
+		},
+		{
+			stmt:    "CREATE VIEW `v` AS SELECT 1",
+			wantErr: true,
+		},
+		{
+			stmt:    "CREATE TABLE `t` (\n  `c` varchar(10) DEFAULT 'a\n)",
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			c, err := parseCreateTable(tt.stmt)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestDriver_InspectShowCreate(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("8.0.13")
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= SCHEMA()"))).
+		WillReturnRows(sqltest.Rows(`
++-------------+----------------------------+------------------------+
+| SCHEMA_NAME | DEFAULT_CHARACTER_SET_NAME | DEFAULT_COLLATION_NAME |
++-------------+----------------------------+------------------------+
+| public      | utf8mb4                    | utf8mb4_unicode_ci     |
++-------------+----------------------------+------------------------+
+`))
+	mk.tableExists("public", "users", true)
+	mk.ExpectQuery(queryColumns).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
++------------+-------------+-------------+----------------+-------------+------------+---------------------------+-------------------+--------------------+----------------+-----------------------+
+| table_name | column_name | column_type | column_comment | is_nullable | column_key | column_default            | extra             | character_set_name | collation_name | generation_expression |
++------------+-------------+-------------+----------------+-------------+------------+---------------------------+-------------------+--------------------+----------------+-----------------------+
+| users      | tags        | json        |                | YES         |            | json_array(_utf8mb4\'a\') | DEFAULT_GENERATED | NULL               | NULL           | NULL                  |
+| users      | id          | int         |                | NO          |            | NULL                      |                   | NULL               | NULL           | NULL                  |
++------------+-------------+-------------+----------------+-------------+------------+---------------------------+-------------------+--------------------+----------------+-----------------------+
+`))
+	mk.noIndexes()
+	mk.noFKs()
+	mk.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+			AddRow("users", "CREATE TABLE `users` (\n  `id` int NOT NULL,\n  `tags` json DEFAULT (json_array(_utf8mb4'a'))\n) ENGINE=InnoDB AUTO_INCREMENT=10 DEFAULT CHARSET=utf8mb4"))
+	drv, err := OpenWith(db, WithShowCreate())
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode: schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	tbl, ok := s.Table("users")
+	require.True(t, ok)
+	require.Len(t, tbl.Columns, 2)
+	require.Equal(t, "id", tbl.Columns[0].Name)
+	require.Equal(t, "tags", tbl.Columns[1].Name)
+	require.Equal(t, &schema.RawExpr{X: "(json_array(_utf8mb4'a'))"}, tbl.Columns[1].Default)
+	var inc AutoIncrement
+	require.True(t, sqlx.Has(tbl.Attrs, &inc))
+	require.Equal(t, int64(10), inc.V)
+}
+
+func TestDriver_InspectShowCreate_AutoIncrement(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("8.0.13")
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= SCHEMA()"))).
+		WillReturnRows(sqltest.Rows(`
++-------------+----------------------------+------------------------+
+| SCHEMA_NAME | DEFAULT_CHARACTER_SET_NAME | DEFAULT_COLLATION_NAME |
++-------------+----------------------------+------------------------+
+| public      | utf8mb4                    | utf8mb4_unicode_ci     |
++-------------+----------------------------+------------------------+
+`))
+	// AUTO_INCREMENT is reported as NULL by information_schema.
+	mk.tableExists("public", "users", true)
+	mk.ExpectQuery(queryColumns).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
++------------+-------------+-------------+----------------+-------------+------------+----------------+----------------+--------------------+----------------+-----------------------+
+| table_name | column_name | column_type | column_comment | is_nullable | column_key | column_default | extra          | character_set_name | collation_name | generation_expression |
++------------+-------------+-------------+----------------+-------------+------------+----------------+----------------+--------------------+----------------+-----------------------+
+| users      | id          | int         |                | NO          | PRI        | NULL           | auto_increment | NULL               | NULL           | NULL                  |
++------------+-------------+-------------+----------------+-------------+------------+----------------+----------------+--------------------+----------------+-----------------------+
+`))
+	mk.noIndexes()
+	mk.noFKs()
+	mk.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
+		WillReturnRows(sqlmock.NewRows([]string{"Table", "Create Table"}).
+			AddRow("users", "CREATE TABLE `users` (\n  `id` int NOT NULL AUTO_INCREMENT,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4"))
+	drv, err := OpenWith(db, WithShowCreate())
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode: schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	tbl, ok := s.Table("users")
+	require.True(t, ok)
+	var inc AutoIncrement
+	require.True(t, sqlx.Has(tbl.Attrs, &inc))
+	require.Equal(t, int64(42), inc.V)
+	require.True(t, sqlx.Has(tbl.Columns[0].Attrs, &inc))
+	require.Equal(t, int64(42), inc.V)
+}
@@ -0,0 +1,418 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql/internal/mysqlversion"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// s2planApply decorates the TiDB planApply. Like TiDB, SingleStore restricts the
+	// operations that can be combined in a single ALTER statement, and therefore, its
+	// changes are planned one per statement.
+	s2planApply struct{ tplanApply }
+	// s2diff decorates MySQL diff.
+	s2diff struct{ diff }
+	// s2inspect decorates MySQL inspect.
+	s2inspect struct{ inspect }
+
+	// TableStore describes the storage type of a SingleStore table. COLUMNSTORE or ROWSTORE.
+	// Tables without this attribute use the server default (default_table_type).
+	TableStore struct {
+		schema.Attr
+		V string
+	}
+
+	// ShardKey describes the SHARD KEY of a SingleStore table, which defines how its rows
+	// are distributed across the partitions of the database. An empty list of columns
+	// describes a keyless sharded table, and tables without this attribute are sharded
+	// by their primary key.
+	ShardKey struct {
+		schema.Attr
+		Columns []*schema.Column
+	}
+
+	// SortKey describes the SORT KEY of a SingleStore columnstore
+	// table, which defines the order its rows are stored on disk.
+	SortKey struct {
+		schema.Attr
+		Columns []*schema.Column
+	}
+)
+
+// List of SingleStore table types.
+const (
+	StoreColumnstore = "COLUMNSTORE"
+	StoreRowstore    = "ROWSTORE"
+)
+
+// singleStoreV73 is the SingleStore release in which columnstore became the
+// default table type, and the ROWSTORE and SORT KEY clauses were introduced.
+// Older releases create columnstore tables using a CLUSTERED COLUMNSTORE key.
+const singleStoreV73 = "7.3"
+
+// isSingleStore reports if the server version comment belongs to SingleStore (formerly MemSQL).
+func isSingleStore(comment string) bool {
+	return strings.Contains(comment, "SingleStore") || strings.Contains(comment, "MemSQL")
+}
+
+// setSingleStoreVersion appends the SingleStore release to the MySQL-compatible
+// version of the server. For example, "5.7.32-SingleStore-8.5.7".
+func (c *conn) setSingleStoreVersion() error {
+	rows, err := c.QueryContext(context.Background(), singleStoreVersionQuery)
+	if err != nil {
+		return fmt.Errorf("mysql: query singlestore version: %w", err)
+	}
+	var v string
+	if err := sqlx.ScanOne(rows, &v); err != nil {
+		return fmt.Errorf("mysql: scan singlestore version: %w", err)
+	}
+	c.V = mysqlversion.V(fmt.Sprintf("%s-SingleStore-%s", c.V, strings.TrimSpace(v)))
+	return nil
+}
+
+// tableStore returns the storage type of the table, if it was set explicitly.
+func tableStore(t *schema.Table) string {
+	var s TableStore
+	if sqlx.Has(t.Attrs, &s) {
+		return strings.ToUpper(s.V)
+	}
+	return ""
+}
+
+// s2TableKind writes the table type of SingleStore tables to the CREATE command.
+func (s *state) s2TableKind(b *sqlx.Builder, t *schema.Table) {
+	// Before v7.3, rowstore is the default table type.
+	if tableStore(t) == StoreRowstore && s.SingleStoreGTE(singleStoreV73) {
+		b.P(StoreRowstore)
+	}
+}
+
+// s2TableKeys writes the shard key and the sort key of SingleStore tables to the CREATE command.
+func (s *state) s2TableKeys(b *sqlx.Builder, t *schema.Table) error {
+	if k := (ShardKey{}); sqlx.Has(t.Attrs, &k) {
+		b.Comma().NL().P("SHARD KEY")
+		s2KeyColumns(b, k.Columns)
+	}
+	var k SortKey
+	switch hasK, store := sqlx.Has(t.Attrs, &k), tableStore(t); {
+	case hasK && store == StoreRowstore:
+		return fmt.Errorf("sort keys are supported only by columnstore tables")
+	case !hasK && store != StoreColumnstore:
+	case s.SingleStoreGTE(singleStoreV73):
+		// An empty SORT KEY creates a columnstore
+		// table, even if it is not the server default.
+		b.Comma().NL().P("SORT KEY")
+		s2KeyColumns(b, k.Columns)
+	default:
+		b.Comma().NL().P("KEY")
+		s2KeyColumns(b, k.Columns)
+		b.P("USING CLUSTERED COLUMNSTORE")
+	}
+	return nil
+}
+
+// s2KeyColumns writes the columns of a shard or a sort key.
+func s2KeyColumns(b *sqlx.Builder, columns []*schema.Column) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(columns, func(i int, b *sqlx.Builder) {
+			b.Ident(columns[i].Name)
+		})
+	})
+}
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *s2planApply) PlanChanges(ctx context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	if err := checkS2Changes(changes); err != nil {
+		return nil, err
+	}
+	return p.tplanApply.PlanChanges(ctx, name, changes, opts...)
+}
+
+func (p *s2planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// checkS2Changes reports an error for changes that cannot be applied on SingleStore,
+// instead of failing in the middle of the migration. SingleStore does not enforce
+// foreign keys, and its table type, shard key, sort key and primary key are fixed
+// on table creation.
+func checkS2Changes(changes []schema.Change) error {
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			if len(c.T.ForeignKeys) > 0 {
+				return fmt.Errorf("singlestore: create table %q: foreign keys are not supported", c.T.Name)
+			}
+		case *schema.ModifyTable:
+			for _, c1 := range c.Changes {
+				if err := checkS2TableChange(c1); err != nil {
+					return fmt.Errorf("singlestore: modify table %q: %w", c.T.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkS2TableChange reports an error if the table change cannot be applied on SingleStore.
+func checkS2TableChange(c schema.Change) error {
+	switch c := c.(type) {
+	case *schema.AddForeignKey, *schema.ModifyForeignKey:
+		return fmt.Errorf("foreign keys are not supported")
+	case *schema.AddPrimaryKey, *schema.DropPrimaryKey, *schema.ModifyPrimaryKey:
+		return fmt.Errorf("primary key cannot be changed after the table was created")
+	case *schema.AddIndex:
+		if c.I.Unique {
+			return fmt.Errorf("unique index %q cannot be added after the table was created", c.I.Name)
+		}
+	case *schema.AddAttr:
+		return checkS2Attr(c.A)
+	case *schema.ModifyAttr:
+		return checkS2Attr(c.To)
+	case *schema.DropAttr:
+		return checkS2Attr(c.A)
+	}
+	return nil
+}
+
+// checkS2Attr reports an error if the attribute is one of the
+// table attributes that cannot be changed after table creation.
+func checkS2Attr(a schema.Attr) error {
+	var name string
+	switch a.(type) {
+	case *TableStore:
+		name = "table type"
+	case *ShardKey:
+		name = "shard key"
+	case *SortKey:
+		name = "sort key"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s cannot be changed after the table was created", name)
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+// The SingleStore attributes are compared only if they were set explicitly in the desired state.
+func (d *s2diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	changes, err := d.diff.TableAttrDiff(from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	var fromS, toS TableStore
+	if sqlx.Has(to.Attrs, &toS) {
+		// Inspected tables without an explicit type use the default type of their release.
+		if fromS.V = tableStore(from); fromS.V == "" {
+			fromS.V = StoreRowstore
+			if d.SingleStoreGTE(singleStoreV73) {
+				fromS.V = StoreColumnstore
+			}
+		}
+		if !strings.EqualFold(fromS.V, toS.V) {
+			changes = append(changes, &schema.ModifyAttr{From: &fromS, To: &toS})
+		}
+	}
+	var fromK, toK ShardKey
+	if sqlx.Has(to.Attrs, &toK) {
+		if !sqlx.Has(from.Attrs, &fromK) && from.PrimaryKey != nil {
+			for _, p := range from.PrimaryKey.Parts {
+				fromK.Columns = append(fromK.Columns, p.C)
+			}
+		}
+		if !sameColumns(fromK.Columns, toK.Columns) {
+			changes = append(changes, &schema.ModifyAttr{From: &fromK, To: &toK})
+		}
+	}
+	var fromO, toO SortKey
+	// A missing sort key is equivalent to an empty one.
+	if sqlx.Has(to.Attrs, &toO) {
+		sqlx.Has(from.Attrs, &fromO)
+		if !sameColumns(fromO.Columns, toO.Columns) {
+			changes = append(changes, &schema.ModifyAttr{From: &fromO, To: &toO})
+		}
+	}
+	return changes, nil
+}
+
+// sameColumns reports if the two lists hold the same columns, by name and order.
+func sameColumns(c1, c2 []*schema.Column) bool {
+	if len(c1) != len(c2) {
+		return false
+	}
+	for i := range c1 {
+		if c1[i] == nil || c2[i] == nil || c1[i].Name != c2[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *s2inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	s, err := i.inspect.InspectSchema(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return i.patchSchema(ctx, s)
+}
+
+func (i *s2inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	r, err := i.inspect.InspectRealm(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range r.Schemas {
+		if _, err := i.patchSchema(ctx, s); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (i *s2inspect) patchSchema(ctx context.Context, s *schema.Schema) (*schema.Schema, error) {
+	for _, t := range s.Tables {
+		var c CreateStmt
+		if !sqlx.Has(t.Attrs, &c) {
+			stmt, err := i.createStmt(ctx, t)
+			if err != nil {
+				return nil, err
+			}
+			c = *stmt
+		}
+		// SingleStore does not have pluggable storage engines,
+		// and its table types are derived from the statement.
+		t.Attrs = schema.RemoveAttr[*Engine](t.Attrs)
+		if err := setS2Attrs(t, c.S); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+var (
+	// e.g. CREATE ROWSTORE TABLE `t` (
+	reS2Rowstore = regexp.MustCompile(`^\s*CREATE\s+ROWSTORE\s`)
+	// e.g. SHARD KEY `__SHARDKEY` (`id`), SORT KEY `__UNORDERED` (`ts` DESC), or
+	// KEY `ts` (`ts`) USING CLUSTERED COLUMNSTORE in releases before v7.3.
+	reS2Key = regexp.MustCompile("(?m)^\\s*(SHARD KEY|SORT KEY|KEY)(?: `((?:[^`]|``)*)`)? \\(([^)]*)\\)( USING CLUSTERED COLUMNSTORE)?")
+)
+
+// setS2Attrs extracts the SingleStore-specific attributes from the CREATE TABLE statement.
+func setS2Attrs(t *schema.Table, stmt string) error {
+	if reS2Rowstore.MatchString(stmt) {
+		schema.ReplaceOrAppend(&t.Attrs, &TableStore{V: StoreRowstore})
+	}
+	for _, m := range reS2Key.FindAllStringSubmatch(stmt, -1) {
+		if m[1] == "KEY" && m[4] == "" {
+			continue
+		}
+		var columns []*schema.Column
+		for _, p := range strings.Split(m[3], ",") {
+			if p = strings.TrimSpace(p); p == "" {
+				continue
+			}
+			p = strings.TrimSuffix(strings.TrimSuffix(p, " DESC"), " ASC")
+			name := strings.ReplaceAll(strings.Trim(p, "`"), "``", "`")
+			c, ok := t.Column(name)
+			if !ok {
+				return fmt.Errorf("column %q of %s was not found in table %q", name, strings.ToLower(m[1]), t.Name)
+			}
+			columns = append(columns, c)
+		}
+		// Keys are reported by the information schema as regular
+		// indexes on some releases, and therefore, removed from them.
+		if name := strings.ReplaceAll(m[2], "``", "`"); name != "" {
+			for j, idx := range t.Indexes {
+				if idx.Name == name {
+					t.Indexes = append(t.Indexes[:j], t.Indexes[j+1:]...)
+					break
+				}
+			}
+		}
+		if m[1] == "SHARD KEY" {
+			schema.ReplaceOrAppend(&t.Attrs, &ShardKey{Columns: columns})
+		} else {
+			schema.ReplaceOrAppend(&t.Attrs, &TableStore{V: StoreColumnstore})
+			schema.ReplaceOrAppend(&t.Attrs, &SortKey{Columns: columns})
+		}
+	}
+	return nil
+}
+
+// s2TableAttrsSpec appends the SingleStore attributes to the table spec, if needed.
+func s2TableAttrsSpec(t *schema.Table, spec *sqlspec.Table) {
+	if s := tableStore(t); s != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("store", s))
+	}
+	if k := (ShardKey{}); sqlx.Has(t.Attrs, &k) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefsAttr("shard_key", s2KeyRefs(k.Columns)...))
+	}
+	if k := (SortKey{}); sqlx.Has(t.Attrs, &k) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefsAttr("sort_key", s2KeyRefs(k.Columns)...))
+	}
+}
+
+// s2KeyRefs returns the column references of a shard or a sort key.
+func s2KeyRefs(columns []*schema.Column) []*schemahcl.Ref {
+	refs := make([]*schemahcl.Ref, 0, len(columns))
+	for _, c := range columns {
+		refs = append(refs, specutil.ColumnRef(c.Name))
+	}
+	return refs
+}
+
+// convertS2TableAttrs converts the SingleStore attributes of the table spec, if exist.
+func convertS2TableAttrs(spec *sqlspec.Table, t *schema.Table) error {
+	if attr, ok := spec.Attr("store"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return err
+		}
+		t.AddAttrs(&TableStore{V: v})
+	}
+	if attr, ok := spec.Attr("shard_key"); ok {
+		columns, err := columnsByRefs(attr, t)
+		if err != nil {
+			return fmt.Errorf("table %q: shard_key: %w", t.Name, err)
+		}
+		t.AddAttrs(&ShardKey{Columns: columns})
+	}
+	if attr, ok := spec.Attr("sort_key"); ok {
+		columns, err := columnsByRefs(attr, t)
+		if err != nil {
+			return fmt.Errorf("table %q: sort_key: %w", t.Name, err)
+		}
+		t.AddAttrs(&SortKey{Columns: columns})
+	}
+	return nil
+}
+
+// columnsByRefs returns the table columns of the given references. e.g., a shard or a sort key.
+func columnsByRefs(attr *schemahcl.Attr, t *schema.Table) ([]*schema.Column, error) {
+	refs, err := attr.Refs()
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]*schema.Column, 0, len(refs))
+	for _, r := range refs {
+		c, err := specutil.ColumnByRef(t, r)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleStore_Open(t *testing.T) {
+	drv, err := newSingleStore(t, "8.5.7")
+	require.NoError(t, err)
+	require.True(t, drv.SingleStore())
+	require.True(t, drv.SingleStoreGTE("7.3"))
+	require.True(t, drv.GTE("5.7"))
+	require.IsType(t, &s2planApply{}, drv.PlanApplier)
+	require.IsType(t, &s2inspect{}, drv.Inspector)
+}
+
+func TestSingleStore_SetAttrs(t *testing.T) {
+	tbl := schema.NewTable("events").
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewIntColumn("ts", TypeBigInt),
+		)
+	tbl.AddIndexes(schema.NewIndex("__SHARDKEY").AddColumns(tbl.Columns[0]))
+	err := setS2Attrs(tbl, "CREATE TABLE `events` (\n  `id` bigint(20) NOT NULL,\n  `ts` bigint(20) NOT NULL,\n  SHARD KEY `__SHARDKEY` (`id`),\n  SORT KEY `__UNORDERED` (`ts` DESC)\n) AUTOSTATS_CDF_MODE=INCREMENTAL SQL_MODE='STRICT_ALL_TABLES'")
+	require.NoError(t, err)
+	require.Empty(t, tbl.Indexes)
+	require.Equal(t, []schema.Attr{
+		&ShardKey{Columns: tbl.Columns[:1]},
+		&TableStore{V: StoreColumnstore},
+		&SortKey{Columns: tbl.Columns[1:]},
+	}, tbl.Attrs)
+
+	tbl = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", TypeBigInt))
+	err = setS2Attrs(tbl, "CREATE ROWSTORE TABLE `users` (\n  `id` bigint(20) NOT NULL,\n  SHARD KEY ()\n)")
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&TableStore{V: StoreRowstore}, &ShardKey{}}, tbl.Attrs)
+
+	// Columnstore tables before v7.3.
+	tbl = schema.NewTable("logs").AddColumns(schema.NewIntColumn("ts", TypeBigInt))
+	err = setS2Attrs(tbl, "CREATE TABLE `logs` (\n  `ts` bigint(20) NOT NULL,\n  KEY `ts` (`ts`) USING CLUSTERED COLUMNSTORE\n)")
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&TableStore{V: StoreColumnstore}, &SortKey{Columns: tbl.Columns}}, tbl.Attrs)
+
+	err = setS2Attrs(tbl, "CREATE TABLE `logs` (\n  `ts` bigint(20) NOT NULL,\n  SHARD KEY (`id`)\n)")
+	require.EqualError(t, err, `column "id" of shard key was not found in table "logs"`)
+}
+
+func TestSingleStore_PlanChanges(t *testing.T) {
+	drv, err := newSingleStore(t, "8.5.7")
+	require.NoError(t, err)
+	events := schema.NewTable("events").
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewIntColumn("ts", TypeBigInt),
+		)
+	events.SetPrimaryKey(schema.NewPrimaryKey(events.Columns...))
+	events.AddAttrs(
+		&ShardKey{Columns: events.Columns[:1]},
+		&SortKey{Columns: events.Columns[1:]},
+	)
+	users := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", TypeBigInt)).
+		AddAttrs(&TableStore{V: StoreRowstore}, &ShardKey{})
+	plan, err := drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.AddTable{T: events},
+		&schema.AddTable{T: users},
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Transactional)
+	require.Equal(t, []string{
+		"CREATE TABLE `events` (`id` bigint NOT NULL, `ts` bigint NOT NULL, PRIMARY KEY (`id`, `ts`), SHARD KEY (`id`), SORT KEY (`ts`))",
+		"CREATE ROWSTORE TABLE `users` (`id` bigint NOT NULL, SHARD KEY ())",
+	}, s2Cmds(plan))
+
+	// Changes are planned one per statement.
+	plan, err = drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.ModifyTable{T: events, Changes: []schema.Change{
+			&schema.AddColumn{C: schema.NewNullIntColumn("v", TypeInt)},
+			&schema.AddIndex{I: schema.NewIndex("events_ts").AddColumns(events.Columns[1])},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"ALTER TABLE `events` ADD COLUMN `v` int NULL",
+		"ALTER TABLE `events` ADD INDEX `events_ts` (`ts`)",
+	}, s2Cmds(plan))
+
+	for _, tt := range []struct {
+		change schema.Change
+		err    string
+	}{
+		{
+			change: &schema.ModifyTable{T: events, Changes: []schema.Change{&schema.ModifyAttr{From: &ShardKey{}, To: &ShardKey{Columns: events.Columns[1:]}}}},
+			err:    `singlestore: modify table "events": shard key cannot be changed after the table was created`,
+		},
+		{
+			change: &schema.ModifyTable{T: events, Changes: []schema.Change{&schema.ModifyAttr{From: &TableStore{V: StoreColumnstore}, To: &TableStore{V: StoreRowstore}}}},
+			err:    `singlestore: modify table "events": table type cannot be changed after the table was created`,
+		},
+		{
+			change: &schema.ModifyTable{T: events, Changes: []schema.Change{&schema.AddIndex{I: schema.NewUniqueIndex("events_id").AddColumns(events.Columns[0])}}},
+			err:    `singlestore: modify table "events": unique index "events_id" cannot be added after the table was created`,
+		},
+		{
+			change: &schema.ModifyTable{T: events, Changes: []schema.Change{&schema.AddForeignKey{F: schema.NewForeignKey("events_users")}}},
+			err:    `singlestore: modify table "events": foreign keys are not supported`,
+		},
+		{
+			change: &schema.AddTable{T: schema.NewTable("logs").AddColumns(schema.NewIntColumn("id", TypeInt)).AddAttrs(&TableStore{V: StoreRowstore}, &SortKey{})},
+			err:    `create table "logs": sort keys are supported only by columnstore tables`,
+		},
+	} {
+		_, err = drv.PlanChanges(context.Background(), "", []schema.Change{tt.change})
+		require.EqualError(t, err, tt.err)
+	}
+}
+
+func TestSingleStore_PlanChangesBeforeV73(t *testing.T) {
+	drv, err := newSingleStore(t, "7.1.13")
+	require.NoError(t, err)
+	logs := schema.NewTable("logs").
+		AddColumns(schema.NewIntColumn("ts", TypeBigInt)).
+		AddAttrs(&TableStore{V: StoreColumnstore})
+	logs.AddAttrs(&SortKey{Columns: logs.Columns})
+	users := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", TypeBigInt)).
+		AddAttrs(&TableStore{V: StoreRowstore})
+	plan, err := drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.AddTable{T: logs},
+		&schema.AddTable{T: users},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"CREATE TABLE `logs` (`ts` bigint NOT NULL, KEY (`ts`) USING CLUSTERED COLUMNSTORE)",
+		"CREATE TABLE `users` (`id` bigint NOT NULL)",
+	}, s2Cmds(plan))
+}
+
+func TestSingleStore_Diff(t *testing.T) {
+	d := &s2diff{diff{conn: &conn{V: "5.7.32-SingleStore-8.5.7"}}}
+	from := schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", TypeBigInt))
+	from.SetPrimaryKey(schema.NewPrimaryKey(from.Columns...))
+	to := schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", TypeBigInt))
+	to.AddAttrs(
+		// Tables are sharded by their primary key by default,
+		// and columnstore is the default type since v7.3.
+		&ShardKey{Columns: to.Columns},
+		&TableStore{V: StoreColumnstore},
+		&SortKey{},
+	)
+	changes, err := d.TableAttrDiff(from, to, &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	to.Attrs = []schema.Attr{&ShardKey{}, &TableStore{V: StoreRowstore}, &SortKey{Columns: to.Columns}}
+	changes, err = d.TableAttrDiff(from, to, &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, &schema.ModifyAttr{From: &TableStore{V: StoreColumnstore}, To: &TableStore{V: StoreRowstore}}, changes[0])
+	require.Equal(t, &schema.ModifyAttr{From: &ShardKey{Columns: from.Columns}, To: &ShardKey{}}, changes[1])
+	require.Equal(t, &schema.ModifyAttr{From: &SortKey{}, To: &SortKey{Columns: to.Columns}}, changes[2])
+}
+
+func TestSingleStore_MarshalSpec(t *testing.T) {
+	s := schema.New("test")
+	tbl := schema.NewTable("events").
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewIntColumn("ts", TypeBigInt),
+		)
+	tbl.AddAttrs(
+		&TableStore{V: StoreColumnstore},
+		&ShardKey{Columns: tbl.Columns[:1]},
+		&SortKey{Columns: tbl.Columns[1:]},
+	)
+	s.AddTables(tbl)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema    = schema.test
+  store     = COLUMNSTORE
+  shard_key = [column.id]
+  sort_key  = [column.ts]
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "ts" {
+    null = false
+    type = bigint
+  }
+}
+schema "test" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	gt, ok := got.Table("events")
+	require.True(t, ok)
+	s1, k1, k2 := &TableStore{}, &ShardKey{}, &SortKey{}
+	require.True(t, sqlx.Has(gt.Attrs, s1))
+	require.True(t, sqlx.Has(gt.Attrs, k1))
+	require.True(t, sqlx.Has(gt.Attrs, k2))
+	require.Equal(t, StoreColumnstore, s1.V)
+	require.Equal(t, gt.Columns[:1], k1.Columns)
+	require.Equal(t, gt.Columns[1:], k2.Columns)
+}
+
+// newSingleStore opens a SingleStore driver with the given release.
+func newSingleStore(t *testing.T, release string) (*Driver, error) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(variablesQuery)).
+		WillReturnRows(sqltest.Rows(`
++-----------+--------------------+------------------------+--------------------------+-------------------+
+| @@version | @@collation_server | @@character_set_server | @@lower_case_table_names | @@version_comment |
++-----------+--------------------+------------------------+--------------------------+-------------------+
+| 5.7.32    | utf8_general_ci    | utf8                   | 0                        | SingleStore       |
++-----------+--------------------+------------------------+--------------------------+-------------------+
+`))
+	m.ExpectQuery(sqltest.Escape(singleStoreVersionQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"@@memsql_version"}).AddRow(release))
+	drv, err := Open(db)
+	if err != nil {
+		return nil, err
+	}
+	return drv.(*Driver), nil
+}
+
+func s2Cmds(p *migrate.Plan) []string {
+	cmds := make([]string, len(p.Changes))
+	for i, c := range p.Changes {
+		cmds[i] = c.Cmd
+	}
+	return cmds
+}
@@ -0,0 +1,61 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"reflect"
+	"strconv"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/sqlerr"
+)
+
+// errCategories maps the MySQL (and MariaDB) server and client error codes to their categories.
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var errCategories = map[uint64]sqlerr.Category{
+	1049: sqlerr.NotExist,         // ER_BAD_DB_ERROR
+	1051: sqlerr.NotExist,         // ER_BAD_TABLE_ERROR
+	1054: sqlerr.NotExist,         // ER_BAD_FIELD_ERROR
+	1091: sqlerr.NotExist,         // ER_CANT_DROP_FIELD_OR_KEY
+	1146: sqlerr.NotExist,         // ER_NO_SUCH_TABLE
+	1007: sqlerr.Duplicate,        // ER_DB_CREATE_EXISTS
+	1050: sqlerr.Duplicate,        // ER_TABLE_EXISTS_ERROR
+	1060: sqlerr.Duplicate,        // ER_DUP_FIELDNAME
+	1061: sqlerr.Duplicate,        // ER_DUP_KEYNAME
+	1062: sqlerr.Duplicate,        // ER_DUP_ENTRY
+	1826: sqlerr.Duplicate,        // ER_FK_DUP_NAME
+	3822: sqlerr.Duplicate,        // ER_CHECK_CONSTRAINT_DUP_NAME
+	1099: sqlerr.Locked,           // ER_TABLE_NOT_LOCKED_FOR_WRITE
+	1100: sqlerr.Locked,           // ER_TABLE_NOT_LOCKED
+	3572: sqlerr.Locked,           // ER_LOCK_NOWAIT
+	1044: sqlerr.PermissionDenied, // ER_DBACCESS_DENIED_ERROR
+	1045: sqlerr.PermissionDenied, // ER_ACCESS_DENIED_ERROR
+	1142: sqlerr.PermissionDenied, // ER_TABLEACCESS_DENIED_ERROR
+	1143: sqlerr.PermissionDenied, // ER_COLUMNACCESS_DENIED_ERROR
+	1227: sqlerr.PermissionDenied, // ER_SPECIFIC_ACCESS_DENIED_ERROR
+	1064: sqlerr.SyntaxError,      // ER_PARSE_ERROR
+	1149: sqlerr.SyntaxError,      // ER_SYNTAX_ERROR
+	1040: sqlerr.Retryable,        // ER_CON_COUNT_ERROR
+	1205: sqlerr.Retryable,        // ER_LOCK_WAIT_TIMEOUT
+	1213: sqlerr.Retryable,        // ER_LOCK_DEADLOCK
+	2006: sqlerr.Retryable,        // CR_SERVER_GONE_ERROR
+	2013: sqlerr.Retryable,        // CR_SERVER_LOST
+}
+
+// classifyError implements sqlerr.Classifier for the errors returned by the MySQL
+// driver. The error code is read from the Number field of the driver error, as
+// defined by github.com/go-sql-driver/mysql.MySQLError.
+func classifyError(err error) (sqlerr.Category, string, bool) {
+	f, ok := sqlx.ErrorField(err, "Number")
+	if !ok {
+		return sqlerr.Unknown, "", false
+	}
+	switch f.Kind() {
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return errCategories[f.Uint()], strconv.FormatUint(f.Uint(), 10), true
+	default:
+		return sqlerr.Unknown, "", false
+	}
+}
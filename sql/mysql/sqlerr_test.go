@@ -0,0 +1,49 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/sqlerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mysqlError mimics the error type of github.com/go-sql-driver/mysql.
+type mysqlError struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d (%s): %s", e.Number, e.SQLState, e.Message)
+}
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want sqlerr.Category
+		code string
+	}{
+		{err: &mysqlError{Number: 1146, Message: "Table 't' doesn't exist"}, want: sqlerr.NotExist, code: "1146"},
+		{err: &mysqlError{Number: 1050, Message: "Table 't' already exists"}, want: sqlerr.Duplicate, code: "1050"},
+		{err: &mysqlError{Number: 1142, Message: "CREATE command denied"}, want: sqlerr.PermissionDenied, code: "1142"},
+		{err: &mysqlError{Number: 1064, Message: "You have an error in your SQL syntax"}, want: sqlerr.SyntaxError, code: "1064"},
+		{err: fmt.Errorf("exec: %w", &mysqlError{Number: 1213, Message: "Deadlock found"}), want: sqlerr.Retryable, code: "1213"},
+		{err: &mysqlError{Number: 1292, Message: "Incorrect datetime value"}, want: sqlerr.Unknown, code: "1292"},
+	} {
+		c, code, ok := classifyError(tt.err)
+		require.True(t, ok)
+		require.Equal(t, tt.want, c)
+		require.Equal(t, tt.code, code)
+		require.True(t, sqlerr.Is(tt.err, tt.want))
+	}
+	_, _, ok := classifyError(errors.New("error"))
+	require.False(t, ok)
+}
@@ -80,6 +80,12 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 	return nil
 }
 
+// JSONSchema returns the JSON Schema of the Atlas DDL documents of the driver.
+// See schemahcl.State.JSONSchema for more info.
+func (c *Codec) JSONSchema() ([]byte, error) {
+	return c.State.JSONSchema(&specutil.Doc{})
+}
+
 // MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
 func (c *Codec) MarshalSpec(v any) ([]byte, error) {
 	return specutil.Marshal(v, c.State, specutil.RealmFuncs{
@@ -91,9 +97,29 @@ var (
 	registrySpecs     = TypeRegistry.Specs()
 	sharedSpecOptions = []schemahcl.Option{
 		schemahcl.WithTypes("table.column.type", registrySpecs),
+		schemahcl.WithSQLQuoting(schemahcl.SQLQuoting{
+			// Backslashes are escape characters in string literals,
+			// unless the NO_BACKSLASH_ESCAPES mode is enabled.
+			Literal: func(s string) string {
+				return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+			},
+			Ident: func(s string) string {
+				return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+			},
+		}),
+		schemahcl.WithTypeHints("mysql", map[string]string{
+			"string":      TypeVarchar,
+			"int4":        TypeInt,
+			"int8":        TypeBigInt,
+			"timestamptz": TypeTimestamp,
+			"bytea":       TypeBlob,
+			"jsonb":       TypeJSON,
+		}),
 		schemahcl.WithScopedEnums("table.engine", EngineInnoDB, EngineMyISAM, EngineMemory, EngineCSV, EngineNDB),
-		schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial),
+		schemahcl.WithScopedEnums("table.store", StoreColumnstore, StoreRowstore),
+		schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial, IndexTypeVector),
 		schemahcl.WithScopedEnums("table.index.parser", IndexParserNGram, IndexParserMeCab),
+		schemahcl.WithScopedEnums("table.index.distance", VectorDistanceEuclidean, VectorDistanceCosine),
 		schemahcl.WithScopedEnums("table.primary_key.type", IndexTypeBTree, IndexTypeHash, IndexTypeFullText, IndexTypeSpatial),
 		schemahcl.WithScopedEnums("table.column.as.type", stored, persistent, virtual),
 		schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
@@ -161,16 +187,101 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 		}
 		t.AddAttrs(&Engine{V: v})
 	}
+	if err := convertS2TableAttrs(spec, t); err != nil {
+		return nil, err
+	}
+	if err := convertSystemVersioned(spec, t); err != nil {
+		return nil, err
+	}
+	// TiDB placement policy.
+	if attr, ok := spec.Attr("placement_policy"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return nil, err
+		}
+		t.AddAttrs(&PlacementPolicy{V: v})
+	}
 	return t, nil
 }
 
+// convertSystemVersioned converts the system versioning attributes of MariaDB tables, if exist.
+func convertSystemVersioned(spec *sqlspec.Table, t *schema.Table) error {
+	attr, ok := spec.Attr("system_versioned")
+	if !ok {
+		return nil
+	}
+	switch b, err := attr.Bool(); {
+	case err != nil:
+		return err
+	case !b:
+		return nil
+	}
+	v := &SystemVersioned{}
+	if attr, ok := spec.Attr("period"); ok {
+		columns, err := columnsByRefs(attr, t)
+		if err != nil {
+			return fmt.Errorf("table %q: period: %w", t.Name, err)
+		}
+		if len(columns) != 2 {
+			return fmt.Errorf("table %q: period: expect 2 columns (start and end), got %d", t.Name, len(columns))
+		}
+		v.Start, v.End = columns[0].Name, columns[1].Name
+	}
+	if r, ok := spec.Extra.Resource("history_partition"); ok {
+		var (
+			err error
+			p   SystemTimePartition
+		)
+		if a, ok := r.Attr("interval"); ok {
+			if p.Interval, err = a.String(); err != nil {
+				return err
+			}
+		}
+		if a, ok := r.Attr("starts"); ok {
+			if p.Starts, err = a.String(); err != nil {
+				return err
+			}
+		}
+		if a, ok := r.Attr("limit"); ok {
+			if p.Limit, err = a.Int64(); err != nil {
+				return err
+			}
+		}
+		if a, ok := r.Attr("auto"); ok {
+			if p.Auto, err = a.Bool(); err != nil {
+				return err
+			}
+		}
+		if a, ok := r.Attr("partitions"); ok {
+			if p.Partitions, err = a.Int(); err != nil {
+				return err
+			}
+		}
+		v.Partition = &p
+	}
+	t.AddAttrs(v)
+	return nil
+}
+
 // convertPK converts a sqlspec.PrimaryKey into a schema.Index.
 func convertPK(spec *sqlspec.PrimaryKey, parent *schema.Table) (*schema.Index, error) {
-	return convertIndex(&sqlspec.Index{
+	idx, err := convertIndex(&sqlspec.Index{
 		Parts:            spec.Parts,
 		Columns:          spec.Columns,
 		DefaultExtension: spec.DefaultExtension,
 	}, parent)
+	if err != nil {
+		return nil, err
+	}
+	// TiDB CLUSTERED (or NONCLUSTERED) option.
+	if attr, ok := spec.Attr("clustered"); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return nil, err
+		}
+		idx.AddAttrs(&Clustered{V: b})
+	}
+	return idx, nil
 }
 
 // convertIndex converts a sqlspec.Index into a schema.Index.
@@ -185,6 +296,12 @@ func convertIndex(spec *sqlspec.Index, parent *schema.Table) (*schema.Index, err
 	if err := convertIndexParser(spec, idx); err != nil {
 		return nil, err
 	}
+	if err := convertFullTextOptions(spec, idx); err != nil {
+		return nil, err
+	}
+	if err := convertVectorOptions(spec, idx); err != nil {
+		return nil, err
+	}
 	return idx, nil
 }
 
@@ -210,6 +327,55 @@ func convertIndexParser(spec specutil.Attrer, idx *schema.Index) error {
 	return nil
 }
 
+func convertFullTextOptions(spec specutil.Attrer, idx *schema.Index) error {
+	var (
+		err  error
+		opts FullTextOptions
+	)
+	if attr, ok := spec.Attr("ngram_token_size"); ok {
+		if opts.NgramTokenSize, err = attr.Int(); err != nil {
+			return err
+		}
+	}
+	if attr, ok := spec.Attr("stopwords"); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return err
+		}
+		opts.NoStopwords = !b
+	}
+	if attr, ok := spec.Attr("stopword_table"); ok {
+		if opts.StopwordTable, err = attr.String(); err != nil {
+			return err
+		}
+	}
+	if opts != (FullTextOptions{}) {
+		idx.AddAttrs(&opts)
+	}
+	return nil
+}
+
+func convertVectorOptions(spec specutil.Attrer, idx *schema.Index) error {
+	var (
+		err  error
+		opts VectorOptions
+	)
+	if attr, ok := spec.Attr("m"); ok {
+		if opts.M, err = attr.Int(); err != nil {
+			return err
+		}
+	}
+	if attr, ok := spec.Attr("distance"); ok {
+		if opts.Distance, err = attr.String(); err != nil {
+			return err
+		}
+	}
+	if opts != (VectorOptions{}) {
+		idx.AddAttrs(&opts)
+	}
+	return nil
+}
+
 func convertPart(spec *sqlspec.IndexPart, part *schema.IndexPart) error {
 	if attr, ok := spec.Attr("prefix"); ok {
 		if part.X != nil {
@@ -265,12 +431,46 @@ func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error
 			c.AddAttrs(&AutoIncrement{})
 		}
 	}
+	if err := convertAutoRandom(spec, c); err != nil {
+		return nil, err
+	}
 	if err := specutil.ConvertGenExpr(spec.Remain(), c, storedOrVirtual); err != nil {
 		return nil, err
 	}
 	return c, err
 }
 
+// convertAutoRandom converts the TiDB AUTO_RANDOM attribute of a column, if exists.
+func convertAutoRandom(spec *sqlspec.Column, c *schema.Column) error {
+	attr, ok := spec.Attr("auto_random")
+	if !ok {
+		return nil
+	}
+	switch b, err := attr.Bool(); {
+	case err != nil:
+		return err
+	case !b:
+		return nil
+	}
+	a := &AutoRandom{}
+	if attr, ok := spec.Attr("auto_random_shard_bits"); ok {
+		v, err := attr.Int()
+		if err != nil {
+			return err
+		}
+		a.ShardBits = v
+	}
+	if attr, ok := spec.Attr("auto_random_range_bits"); ok {
+		v, err := attr.Int()
+		if err != nil {
+			return err
+		}
+		a.RangeBits = v
+	}
+	c.AddAttrs(a)
+	return nil
+}
+
 // convertColumnType converts a sqlspec.Column into a concrete MySQL schema.Type.
 func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
 	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
@@ -321,9 +521,45 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 		}
 		ts.Extra.Attrs = append(ts.Extra.Attrs, attr)
 	}
+	s2TableAttrsSpec(t, ts)
+	systemVersionedSpec(t, ts)
+	if p := (PlacementPolicy{}); sqlx.Has(t.Attrs, &p) && p.V != "" {
+		ts.Extra.Attrs = append(ts.Extra.Attrs, schemahcl.StringAttr("placement_policy", p.V))
+	}
 	return ts, nil
 }
 
+// systemVersionedSpec converts the system versioning attributes of MariaDB tables into spec.
+func systemVersionedSpec(t *schema.Table, spec *sqlspec.Table) {
+	var v SystemVersioned
+	if !sqlx.Has(t.Attrs, &v) {
+		return
+	}
+	spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("system_versioned", true))
+	if v.Start != "" && v.End != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefsAttr("period", specutil.ColumnRef(v.Start), specutil.ColumnRef(v.End)))
+	}
+	if p := v.Partition; p != nil {
+		r := &schemahcl.Resource{Type: "history_partition"}
+		if p.Interval != "" {
+			r.Attrs = append(r.Attrs, schemahcl.StringAttr("interval", p.Interval))
+		}
+		if p.Starts != "" {
+			r.Attrs = append(r.Attrs, schemahcl.StringAttr("starts", p.Starts))
+		}
+		if p.Limit > 0 {
+			r.Attrs = append(r.Attrs, schemahcl.Int64Attr("limit", p.Limit))
+		}
+		if p.Auto {
+			r.Attrs = append(r.Attrs, schemahcl.BoolAttr("auto", true))
+		}
+		if p.Partitions > 0 {
+			r.Attrs = append(r.Attrs, schemahcl.IntAttr("partitions", p.Partitions))
+		}
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+}
+
 func pkSpec(idx *schema.Index) (*sqlspec.PrimaryKey, error) {
 	spec, err := indexSpec(idx)
 	if err != nil {
@@ -334,6 +570,9 @@ func pkSpec(idx *schema.Index) (*sqlspec.PrimaryKey, error) {
 			return nil, fmt.Errorf("primary key %q cannot have functional part", idx.Name)
 		}
 	}
+	if c := (Clustered{}); sqlx.Has(idx.Attrs, &c) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("clustered", c.V))
+	}
 	return &sqlspec.PrimaryKey{Parts: spec.Parts, Columns: spec.Columns, DefaultExtension: spec.DefaultExtension}, nil
 }
 
@@ -362,6 +601,29 @@ func indexTypeSpec(idx *schema.Index, attrs []*schemahcl.Attr) []*schemahcl.Attr
 		}
 		attrs = append(attrs, attr)
 	}
+	if o := (FullTextOptions{}); sqlx.Has(idx.Attrs, &o) {
+		if o.NgramTokenSize > 0 {
+			attrs = append(attrs, schemahcl.IntAttr("ngram_token_size", o.NgramTokenSize))
+		}
+		if o.NoStopwords {
+			attrs = append(attrs, schemahcl.BoolAttr("stopwords", false))
+		}
+		if o.StopwordTable != "" {
+			attrs = append(attrs, schemahcl.StringAttr("stopword_table", o.StopwordTable))
+		}
+	}
+	if v := (VectorOptions{}); sqlx.Has(idx.Attrs, &v) {
+		if v.M > 0 {
+			attrs = append(attrs, schemahcl.IntAttr("m", v.M))
+		}
+		switch d := strings.ToLower(v.Distance); d {
+		case "":
+		case VectorDistanceEuclidean, VectorDistanceCosine:
+			attrs = append(attrs, specutil.VarAttr("distance", d))
+		default:
+			attrs = append(attrs, schemahcl.StringAttr("distance", v.Distance))
+		}
+	}
 	return attrs
 }
 
@@ -390,6 +652,17 @@ func columnSpec(c *schema.Column, t *schema.Table) (*sqlspec.Column, error) {
 	if sqlx.Has(c.Attrs, &AutoIncrement{}) {
 		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("auto_increment", true))
 	}
+	// Print the AUTO_RANDOM bits only if they are not the defaults.
+	if a := (AutoRandom{}); sqlx.Has(c.Attrs, &a) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("auto_random", true))
+		bits := autoRandomBits(a)
+		if bits[0] != 5 {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.IntAttr("auto_random_shard_bits", bits[0]))
+		}
+		if bits[1] != 64 {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.IntAttr("auto_random_range_bits", bits[1]))
+		}
+	}
 	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
 		spec.Extra.Children = append(spec.Extra.Children, specutil.FromGenExpr(x, storedOrVirtual))
 	}
@@ -542,6 +815,7 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec(TypeGeometryCollection),
 		schemahcl.NewTypeSpec(TypeInet4),
 		schemahcl.NewTypeSpec(TypeInet6),
+		schemahcl.NewTypeSpec(TypeVector, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
 	),
 )
 
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"ariga.io/atlas/sql/internal/spectest"
+	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
 	"github.com/stretchr/testify/require"
 )
@@ -493,6 +494,64 @@ schema "test" {
 	require.EqualValues(t, expected, string(buf))
 }
 
+func TestSpec_ExtAttrs(t *testing.T) {
+	const f = `schema "test" {
+  x-owner {
+    team = "payments"
+  }
+}
+table "users" {
+  schema = schema.test
+  column "id" {
+    null = false
+    type = bigint
+    x-sharding {
+      key    = true
+      weight = 2
+    }
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "idx" {
+    columns = [column.id]
+    x-sharding {
+      regions = ["eu", "us"]
+    }
+  }
+  x-sharding {
+    ratio = 0.5
+  }
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Equal(t, []*schema.ExtAttr{{Name: "x-owner.team", V: "payments"}}, schema.ExtAttrs(s.Attrs))
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	require.Equal(t, []*schema.ExtAttr{{Name: "x-sharding.ratio", V: 0.5}}, schema.ExtAttrs(users.Attrs))
+	require.Equal(t, []*schema.ExtAttr{{Name: "x-sharding.key", V: true}, {Name: "x-sharding.weight", V: int64(2)}}, schema.ExtAttrs(users.Columns[0].Attrs))
+	require.Equal(t, []*schema.ExtAttr{{Name: "x-sharding.regions", V: []string{"eu", "us"}}}, schema.ExtAttrs(users.Indexes[0].Attrs))
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	users2, ok := s2.Table("users")
+	require.True(t, ok)
+	require.Equal(t, schema.ExtAttrs(s.Attrs), schema.ExtAttrs(s2.Attrs))
+	require.Equal(t, schema.ExtAttrs(users.Attrs), schema.ExtAttrs(users2.Attrs))
+	require.Equal(t, schema.ExtAttrs(users.Columns[0].Attrs), schema.ExtAttrs(users2.Columns[0].Attrs))
+	require.Equal(t, schema.ExtAttrs(users.Indexes[0].Attrs), schema.ExtAttrs(users2.Indexes[0].Attrs))
+
+	// Extension attributes are not reported as changes.
+	users2.Attrs = schema.RemoveAttr[*schema.ExtAttr](users2.Attrs)
+	users2.Columns[0].Attrs = schema.RemoveAttr[*schema.ExtAttr](users2.Columns[0].Attrs)
+	changes, err := DefaultDiff.TableDiff(users, users2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
 func TestMarshalSpec_AutoIncrement(t *testing.T) {
 	s := &schema.Schema{
 		Name: "test",
@@ -675,6 +734,32 @@ schema "a8m" {}
 	}
 }
 
+func TestUnmarshalSpec_SQLQuoting(t *testing.T) {
+	var (
+		s schema.Schema
+		f = `
+variable "status" {
+  type    = string
+  default = "it's \\active"
+}
+table "users" {
+  schema = schema.a8m
+  column "status" {
+    type    = varchar(32)
+    default = sql(sqlliteral(var.status))
+  }
+  check "status" {
+    expr = "${sqlident("status")} <> ${sqlliteral(var.status)}"
+  }
+}
+schema "a8m" {}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Equal(t, &schema.RawExpr{X: `'it''s \\active'`}, s.Tables[0].Columns[0].Default)
+	require.Equal(t, "`status` <> 'it''s \\\\active'", s.Tables[0].Attrs[0].(*schema.Check).Expr)
+}
+
 func TestUnmarshalSpec_IndexParts(t *testing.T) {
 	var (
 		s schema.Schema
@@ -717,6 +802,93 @@ table "users" {
 	require.EqualValues(t, exp, &s)
 }
 
+func TestMarshalSpec_SystemVersioned(t *testing.T) {
+	s := schema.New("public").
+		AddTables(
+			schema.NewTable("users").
+				AddColumns(schema.NewIntColumn("id", TypeBigInt)).
+				AddAttrs(&SystemVersioned{}),
+			schema.NewTable("posts").
+				AddColumns(
+					schema.NewIntColumn("id", TypeBigInt),
+					schema.NewTimeColumn("row_start", TypeTimestamp),
+					schema.NewTimeColumn("row_end", TypeTimestamp),
+				).
+				AddAttrs(&SystemVersioned{
+					Start: "row_start",
+					End:   "row_end",
+					Partition: &SystemTimePartition{
+						Interval:   "1 MONTH",
+						Starts:     "'2024-01-01 00:00:00'",
+						Auto:       true,
+						Partitions: 3,
+					},
+				}),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "users" {
+  schema           = schema.public
+  system_versioned = true
+  column "id" {
+    null = false
+    type = bigint
+  }
+}
+table "posts" {
+  schema           = schema.public
+  system_versioned = true
+  period           = [column.row_start, column.row_end]
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "row_start" {
+    null = false
+    type = timestamp
+  }
+  column "row_end" {
+    null = false
+    type = timestamp
+  }
+  history_partition {
+    interval   = "1 MONTH"
+    starts     = "'2024-01-01 00:00:00'"
+    auto       = true
+    partitions = 3
+  }
+}
+schema "public" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	for i, tt := range s.Tables {
+		var v1, v2 SystemVersioned
+		require.True(t, sqlx.Has(tt.Attrs, &v1))
+		require.True(t, sqlx.Has(got.Tables[i].Attrs, &v2))
+		require.Equal(t, v1, v2)
+	}
+	// No changes are planned for the round-tripped
+	// state, and the versioning is not dropped.
+	changes, err := DefaultDiff.SchemaDiff(s, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "t" {
+  schema           = schema.public
+  system_versioned = true
+  period           = [column.a]
+  column "a" {
+    type = timestamp
+  }
+}
+`), &got, nil)
+	require.EqualError(t, err, `cannot convert table "t": table "t": period: expect 2 columns (start and end), got 1`)
+}
+
 func TestMarshalSpec_IndexParser(t *testing.T) {
 	s := schema.New("test").
 		AddTables(
@@ -809,6 +981,106 @@ schema "test" {
 	require.EqualValues(t, exp, &s)
 }
 
+func TestMarshalSpec_FullTextOptions(t *testing.T) {
+	c := schema.NewStringColumn("text", "text")
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("posts").
+				AddColumns(c).
+				AddIndexes(
+					schema.NewIndex("idx1").
+						AddColumns(c).
+						SetComment("ngram index").
+						AddAttrs(
+							&IndexType{T: IndexTypeFullText},
+							&IndexParser{P: IndexParserNGram},
+							&FullTextOptions{NgramTokenSize: 3, NoStopwords: true},
+						),
+					schema.NewIndex("idx2").
+						AddColumns(c).
+						AddAttrs(
+							&IndexType{T: IndexTypeFullText},
+							&FullTextOptions{StopwordTable: "test/stopwords"},
+						),
+				),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "posts" {
+  schema = schema.test
+  column "text" {
+    null = false
+    type = text
+  }
+  index "idx1" {
+    columns          = [column.text]
+    comment          = "ngram index"
+    type             = FULLTEXT
+    parser           = ngram
+    ngram_token_size = 3
+    stopwords        = false
+  }
+  index "idx2" {
+    columns        = [column.text]
+    type           = FULLTEXT
+    stopword_table = "test/stopwords"
+  }
+}
+schema "test" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.EqualValues(t, s.Tables[0].Indexes[0].Attrs, got.Tables[0].Indexes[0].Attrs)
+	require.EqualValues(t, s.Tables[0].Indexes[1].Attrs, got.Tables[0].Indexes[1].Attrs)
+}
+
+func TestMarshalSpec_VectorIndex(t *testing.T) {
+	c := schema.NewColumn("embedding").SetType(&VectorType{T: TypeVector, Size: 3})
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("docs").
+				AddColumns(c).
+				AddIndexes(
+					schema.NewIndex("idx1").
+						AddColumns(c).
+						AddAttrs(&IndexType{T: IndexTypeVector}, &VectorOptions{M: 8, Distance: VectorDistanceCosine}),
+					schema.NewIndex("idx2").
+						AddColumns(c).
+						AddAttrs(&IndexType{T: IndexTypeVector}),
+				),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "docs" {
+  schema = schema.test
+  column "embedding" {
+    null = false
+    type = vector(3)
+  }
+  index "idx1" {
+    columns  = [column.embedding]
+    type     = VECTOR
+    m        = 8
+    distance = cosine
+  }
+  index "idx2" {
+    columns = [column.embedding]
+    type    = VECTOR
+  }
+}
+schema "test" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.EqualValues(t, s.Tables[0].Columns[0].Type.Type, got.Tables[0].Columns[0].Type.Type)
+	require.EqualValues(t, s.Tables[0].Indexes[0].Attrs, got.Tables[0].Indexes[0].Attrs)
+	require.EqualValues(t, s.Tables[0].Indexes[1].Attrs, got.Tables[0].Indexes[1].Attrs)
+}
+
 func TestMarshalSpec_PrimaryKeyType(t *testing.T) {
 	s := schema.New("test").
 		AddTables(
@@ -1494,6 +1766,14 @@ func TestTypes(t *testing.T) {
 			typeExpr: "inet6",
 			expected: &NetworkType{T: TypeInet6},
 		},
+		{
+			typeExpr: "vector",
+			expected: &VectorType{T: TypeVector},
+		},
+		{
+			typeExpr: "vector(1536)",
+			expected: &VectorType{T: TypeVector, Size: 1536},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.typeExpr, func(t *testing.T) {
@@ -1796,3 +2076,75 @@ schema "nati.schema" {
 }
 `, string(got))
 }
+
+func TestSpec_Classification(t *testing.T) {
+	const f = `table "users" {
+  schema = schema.test
+  column "email" {
+    null           = false
+    type           = varchar(255)
+    comment        = "user email"
+    classification = "pii"
+  }
+  column "token" {
+    null           = false
+    type           = varchar(255)
+    classification = "secret"
+  }
+}
+schema "test" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user email [classification:pii]"}}, users.Columns[0].Attrs)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "[classification:secret]"}}, users.Columns[1].Attrs)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+
+	err = EvalHCLBytes([]byte(`schema "test" {}
+table "users" {
+  schema = schema.test
+  column "email" {
+    type           = text
+    classification = "PII"
+  }
+}`), &s, nil)
+	require.ErrorContains(t, err, `invalid classification "PII" for column "email"`)
+}
+
+func TestSpec_LogicalDefault(t *testing.T) {
+	const f = `table "users" {
+  schema = schema.test
+  column "status" {
+    null            = false
+    type            = varchar(32)
+    default         = "active"
+    logical_default = "active"
+  }
+  column "created_at" {
+    null            = false
+    type            = timestamp
+    logical_default = sql("now()")
+  }
+}
+schema "test" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	require.Equal(t, &schema.Literal{V: "active"}, users.Columns[0].Default)
+	require.Equal(t, []schema.Attr{&schema.LogicalDefault{X: &schema.Literal{V: "active"}}}, users.Columns[0].Attrs)
+	require.Nil(t, users.Columns[1].Default)
+	require.Equal(t, []schema.Attr{&schema.LogicalDefault{X: &schema.RawExpr{X: "now()"}}}, users.Columns[1].Attrs)
+
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+}
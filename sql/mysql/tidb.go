@@ -25,6 +25,27 @@ type (
 	tdiff struct{ diff }
 	// tinspect decorates MySQL inspect.
 	tinspect struct{ inspect }
+
+	// AutoRandom describes the TiDB AUTO_RANDOM column attribute, used for
+	// generating random (and unique) values for BIGINT primary keys.
+	AutoRandom struct {
+		schema.Attr
+		ShardBits int // Number of shard bits. Zero means the default (5).
+		RangeBits int // Number of range bits. Zero means the default (64).
+	}
+
+	// Clustered describes the TiDB CLUSTERED (or NONCLUSTERED) option of primary keys.
+	// Tables without this attribute use the server default (tidb_enable_clustered_index).
+	Clustered struct {
+		schema.Attr
+		V bool
+	}
+
+	// PlacementPolicy describes the TiDB placement policy attached to a table.
+	PlacementPolicy struct {
+		schema.Attr
+		V string
+	}
 )
 
 // priority computes the priority of each change.
@@ -153,6 +174,9 @@ func (i *tinspect) patchSchema(ctx context.Context, s *schema.Schema) (*schema.S
 		if err := i.setAutoIncrement(t); err != nil {
 			return nil, err
 		}
+		if err := i.setTiDBAttrs(t); err != nil {
+			return nil, err
+		}
 		for _, c := range t.Columns {
 			i.patchColumn(ctx, c)
 		}
@@ -224,3 +248,96 @@ func (i *tinspect) setAutoIncrement(t *schema.Table) error {
 	schema.ReplaceOrAppend(&t.Attrs, ai)
 	return nil
 }
+
+var (
+	// e.g. `id` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(5, 54) */,
+	reAutoRandom = regexp.MustCompile("(?m)^\\s*`((?:[^`]|``)+)` .*/\\*T!\\[auto_rand] AUTO_RANDOM\\((\\d+)(?:,\\s*(\\d+))?\\) \\*/")
+	// e.g. PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */
+	reClustered = regexp.MustCompile(`(?m)^\s*PRIMARY KEY \(.+\) /\*T!\[clustered_index] (CLUSTERED|NONCLUSTERED) \*/`)
+	// e.g. /*T![placement] PLACEMENT POLICY=`p1` */
+	rePlacement = regexp.MustCompile("/\\*T!\\[placement] PLACEMENT POLICY=`?([^`\\s]+)`? \\*/")
+)
+
+// setTiDBAttrs extracts the TiDB-specific attributes from the CREATE TABLE statement.
+func (i *tinspect) setTiDBAttrs(t *schema.Table) error {
+	var c CreateStmt
+	if !sqlx.Has(t.Attrs, &c) {
+		return fmt.Errorf("missing CREATE TABLE statement in attributes for %q", t.Name)
+	}
+	for _, m := range reAutoRandom.FindAllStringSubmatch(c.S, -1) {
+		col, ok := t.Column(strings.ReplaceAll(m[1], "``", "`"))
+		if !ok {
+			return fmt.Errorf("column %q of AUTO_RANDOM attribute was not found in table %q", m[1], t.Name)
+		}
+		a := &AutoRandom{}
+		a.ShardBits, _ = strconv.Atoi(m[2])
+		if m[3] != "" {
+			a.RangeBits, _ = strconv.Atoi(m[3])
+		}
+		schema.ReplaceOrAppend(&col.Attrs, a)
+	}
+	if m := reClustered.FindStringSubmatch(c.S); len(m) == 2 && t.PrimaryKey != nil {
+		schema.ReplaceOrAppend(&t.PrimaryKey.Attrs, &Clustered{V: m[1] == "CLUSTERED"})
+	}
+	if m := rePlacement.FindStringSubmatch(c.S); len(m) == 2 {
+		schema.ReplaceOrAppend(&t.Attrs, &PlacementPolicy{V: m[1]})
+	}
+	return nil
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *tdiff) ColumnChange(fromT *schema.Table, from, to *schema.Column, opts *schema.DiffOptions) (schema.Change, error) {
+	change, err := d.diff.ColumnChange(fromT, from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	var fromA, toA AutoRandom
+	if sqlx.Has(from.Attrs, &fromA) == sqlx.Has(to.Attrs, &toA) && autoRandomBits(fromA) == autoRandomBits(toA) {
+		return change, nil
+	}
+	if m, ok := change.(*schema.ModifyColumn); ok {
+		m.Change |= schema.ChangeAttr
+		return m, nil
+	}
+	return &schema.ModifyColumn{From: from, To: to, Change: schema.ChangeAttr}, nil
+}
+
+// autoRandomBits returns the shard and range bits of the AUTO_RANDOM attribute.
+func autoRandomBits(a AutoRandom) [2]int {
+	bits := [2]int{a.ShardBits, a.RangeBits}
+	if bits[0] == 0 {
+		bits[0] = 5
+	}
+	if bits[1] == 0 {
+		bits[1] = 64
+	}
+	return bits
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (d *tdiff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	changes, err := d.diff.TableAttrDiff(from, to, opts)
+	if err != nil {
+		return nil, err
+	}
+	var fromP, toP PlacementPolicy
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &fromP), sqlx.Has(to.Attrs, &toP); {
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &toP})
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &fromP})
+	case fromHas && toHas && !strings.EqualFold(fromP.V, toP.V):
+		changes = append(changes, &schema.ModifyAttr{From: &fromP, To: &toP})
+	}
+	return changes, nil
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+// The CLUSTERED option is compared only if it was set explicitly.
+func (d *tdiff) IndexAttrChanged(from, to []schema.Attr) bool {
+	var fromC, toC Clustered
+	if sqlx.Has(to, &toC) && (!sqlx.Has(from, &fromC) || fromC.V != toC.V) {
+		return true
+	}
+	return d.diff.IndexAttrChanged(from, to)
+}
@@ -0,0 +1,146 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiDB_SetAttrs(t *testing.T) {
+	tbl := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeBigInt}}},
+			{Name: "name", Type: &schema.ColumnType{Type: &schema.StringType{T: TypeVarchar, Size: 255}}},
+		},
+		Attrs: []schema.Attr{
+			&CreateStmt{S: "CREATE TABLE `users` (\n  `id` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(6, 54) */,\n  `name` varchar(255) DEFAULT NULL,\n  PRIMARY KEY (`id`) /*T![clustered_index] NONCLUSTERED */\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T![auto_rand_base] AUTO_RANDOM_BASE=30001 */ /*T![placement] PLACEMENT POLICY=`p1` */"},
+		},
+	}
+	tbl.PrimaryKey = &schema.Index{Table: tbl, Unique: true, Parts: []*schema.IndexPart{{C: tbl.Columns[0]}}}
+	err := (&tinspect{}).setTiDBAttrs(tbl)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Attr{&AutoRandom{ShardBits: 6, RangeBits: 54}}, tbl.Columns[0].Attrs)
+	require.Empty(t, tbl.Columns[1].Attrs)
+	require.Equal(t, []schema.Attr{&Clustered{V: false}}, tbl.PrimaryKey.Attrs)
+	p := &PlacementPolicy{}
+	require.True(t, sqlx.Has(tbl.Attrs, p))
+	require.Equal(t, "p1", p.V)
+}
+
+func TestTiDB_PlanChanges(t *testing.T) {
+	drv, _, err := newMigrate("5.7.25-TiDB-v6.5.0")
+	require.NoError(t, err)
+	users := &schema.Table{
+		Name: "users",
+		Columns: []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: TypeBigInt}}, Attrs: []schema.Attr{&AutoRandom{ShardBits: 6}}},
+		},
+		Attrs: []schema.Attr{&PlacementPolicy{V: "p1"}},
+	}
+	users.PrimaryKey = &schema.Index{Table: users, Parts: []*schema.IndexPart{{C: users.Columns[0]}}, Attrs: []schema.Attr{&Clustered{V: true}}}
+	plan, err := drv.PlanChanges(context.Background(), "", []schema.Change{&schema.AddTable{T: users}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "CREATE TABLE `users` (`id` bigint NOT NULL AUTO_RANDOM(6), PRIMARY KEY (`id`) CLUSTERED) PLACEMENT POLICY = `p1`", plan.Changes[0].Cmd)
+
+	plan, err = drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropAttr{A: &PlacementPolicy{V: "p1"}}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "ALTER TABLE `users` PLACEMENT POLICY = DEFAULT", plan.Changes[0].Cmd)
+}
+
+func TestTiDB_Diff(t *testing.T) {
+	d := &tdiff{diff{conn: &conn{}}}
+	from := &schema.Table{Name: "users", Schema: &schema.Schema{}, Attrs: []schema.Attr{&PlacementPolicy{V: "p1"}}}
+	to := &schema.Table{Name: "users", Schema: &schema.Schema{}, Attrs: []schema.Attr{&PlacementPolicy{V: "p2"}}}
+	changes, err := d.TableAttrDiff(from, to, &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyAttr{From: &PlacementPolicy{V: "p1"}, To: &PlacementPolicy{V: "p2"}}}, changes)
+
+	changes, err = d.TableAttrDiff(from, &schema.Table{Name: "users", Schema: &schema.Schema{}}, &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.DropAttr{A: &PlacementPolicy{V: "p1"}}}, changes)
+
+	require.False(t, d.IndexAttrChanged([]schema.Attr{&Clustered{V: true}}, nil))
+	require.True(t, d.IndexAttrChanged(nil, []schema.Attr{&Clustered{V: true}}))
+	require.True(t, d.IndexAttrChanged([]schema.Attr{&Clustered{V: false}}, []schema.Attr{&Clustered{V: true}}))
+
+	c := &schema.ColumnType{Type: &schema.IntegerType{T: TypeBigInt}}
+	change, err := d.ColumnChange(
+		&schema.Table{Name: "users"},
+		&schema.Column{Name: "id", Type: c, Attrs: []schema.Attr{&AutoRandom{}}},
+		&schema.Column{Name: "id", Type: c, Attrs: []schema.Attr{&AutoRandom{ShardBits: 5, RangeBits: 64}}},
+		&schema.DiffOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, sqlx.NoChange, change)
+	change, err = d.ColumnChange(
+		&schema.Table{Name: "users"},
+		&schema.Column{Name: "id", Type: c},
+		&schema.Column{Name: "id", Type: c, Attrs: []schema.Attr{&AutoRandom{}}},
+		&schema.DiffOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, schema.ChangeAttr, change.(*schema.ModifyColumn).Change)
+}
+
+func TestTiDB_MarshalSpec(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt).AddAttrs(&AutoRandom{ShardBits: 5}),
+			schema.NewIntColumn("oid", TypeBigInt).AddAttrs(&AutoRandom{ShardBits: 6, RangeBits: 54}),
+		).
+		AddAttrs(&PlacementPolicy{V: "p1"})
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]).AddAttrs(&Clustered{V: false}))
+	s := schema.New("test").AddTables(users)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "users" {
+  schema           = schema.test
+  placement_policy = "p1"
+  column "id" {
+    null        = false
+    type        = bigint
+    auto_random = true
+  }
+  column "oid" {
+    null                   = false
+    type                   = bigint
+    auto_random            = true
+    auto_random_shard_bits = 6
+    auto_random_range_bits = 54
+  }
+  primary_key {
+    columns   = [column.id]
+    clustered = false
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, []schema.Attr{&AutoRandom{}}, got.Tables[0].Columns[0].Attrs)
+	require.Equal(t, []schema.Attr{&AutoRandom{ShardBits: 6, RangeBits: 54}}, got.Tables[0].Columns[1].Attrs)
+	require.Equal(t, []schema.Attr{&Clustered{V: false}}, got.Tables[0].PrimaryKey.Attrs)
+	p := &PlacementPolicy{}
+	require.True(t, sqlx.Has(got.Tables[0].Attrs, p))
+	require.Equal(t, "p1", p.V)
+
+	// No changes are planned for the round-tripped state.
+	d := &tdiff{diff{conn: &conn{ExecQuerier: sqlx.NoRows}}}
+	changes, err := (&sqlx.Diff{DiffDriver: d}).SchemaDiff(s, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
@@ -0,0 +1,141 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mysql
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// Option configures the driver on Open.
+	Option func(*conn)
+
+	// Vitess configures the driver to plan changes that are safe to run
+	// on Vitess (or PlanetScale) targets.
+	Vitess struct {
+		// Sharded indicates the target keyspace is sharded. Schema changes on
+		// sharded keyspaces are not executed as direct ALTER statements, but are
+		// submitted to Vitess as online schema migrations using the DDLStrategy.
+		Sharded bool
+		// DDLStrategy used for sharded keyspaces. Defaults to "vitess".
+		DDLStrategy string
+		// ForeignKeys allows planning foreign key constraints. By default, foreign
+		// keys are omitted from the plan, as Vitess supports them only in unsharded
+		// keyspaces that are configured with foreign_key_mode=managed.
+		ForeignKeys bool
+	}
+)
+
+// WithVitess returns an Option that enables the Vitess-safe planning mode.
+func WithVitess(v Vitess) Option {
+	return func(c *conn) {
+		c.vitess = &v
+	}
+}
+
+// Query parameters used to configure the Vitess-safe
+// planning mode from the connection URL.
+const (
+	paramVitess    = "vitess"     // "true", "unsharded" or "sharded".
+	paramVitessFKs = "vitess_fks" // Allow foreign keys.
+)
+
+// vitessOptions returns the driver options that are set on the URL query.
+func vitessOptions(u *url.URL) ([]Option, error) {
+	q := u.Query()
+	mode := q.Get(paramVitess)
+	if mode == "" {
+		if q.Has(paramVitessFKs) {
+			return nil, fmt.Errorf("mysql: parameter %q requires %q to be set", paramVitessFKs, paramVitess)
+		}
+		return nil, nil
+	}
+	var v Vitess
+	switch mode {
+	case "sharded":
+		v.Sharded = true
+	case "unsharded":
+	default:
+		on, err := strconv.ParseBool(mode)
+		if err != nil {
+			return nil, fmt.Errorf("mysql: invalid %q parameter value: %q", paramVitess, mode)
+		}
+		if !on {
+			return nil, nil
+		}
+	}
+	if s := q.Get(paramVitessFKs); s != "" {
+		fks, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("mysql: invalid %q parameter value: %q", paramVitessFKs, s)
+		}
+		v.ForeignKeys = fks
+	}
+	return []Option{WithVitess(v)}, nil
+}
+
+// vitessChanges returns the changes that can be planned on Vitess targets.
+func (s *state) vitessChanges(changes []schema.Change) ([]schema.Change, error) {
+	planned := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddSchema, *schema.DropSchema, *schema.ModifySchema:
+			// Keyspaces are managed by the Vitess topology, and
+			// not by CREATE DATABASE or ALTER DATABASE statements.
+			return nil, fmt.Errorf("vitess: changing keyspaces (%T) is not supported", c)
+		case *schema.AddTable:
+			if len(c.T.ForeignKeys) > 0 && !s.vitess.ForeignKeys {
+				t := *c.T
+				t.ForeignKeys = nil
+				c = &schema.AddTable{T: &t, Extra: c.Extra}
+			}
+			planned = append(planned, c)
+		case *schema.ModifyTable:
+			if s.vitess.ForeignKeys {
+				planned = append(planned, c)
+				break
+			}
+			m := &schema.ModifyTable{T: c.T}
+			for _, tc := range c.Changes {
+				switch tc.(type) {
+				case *schema.AddForeignKey, *schema.DropForeignKey, *schema.ModifyForeignKey:
+				default:
+					m.Changes = append(m.Changes, tc)
+				}
+			}
+			if len(m.Changes) > 0 {
+				planned = append(planned, m)
+			}
+		default:
+			planned = append(planned, c)
+		}
+	}
+	return planned, nil
+}
+
+// vitessStrategy prepends the statement that sets the DDL strategy of the
+// session, in case the plan targets a sharded keyspace.
+func (s *state) vitessStrategy() {
+	if !s.vitess.Sharded || len(s.Changes) == 0 {
+		return
+	}
+	strategy := s.vitess.DDLStrategy
+	if strategy == "" {
+		strategy = "vitess"
+	}
+	// Setting the strategy is scoped to the session,
+	// and therefore, it is also its own reverse.
+	set := fmt.Sprintf("SET @@ddl_strategy = %s", quote(strategy))
+	s.Changes = append([]*migrate.Change{{
+		Cmd:     set,
+		Reverse: set,
+		Comment: fmt.Sprintf("use the %q DDL strategy for applying the schema changes", strategy),
+	}}, s.Changes...)
+}
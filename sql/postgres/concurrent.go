@@ -0,0 +1,136 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// txModeNone is the file directive that instructs Atlas
+// to execute a migration file outside a transaction block.
+const txModeNone = "-- atlas:txmode none"
+
+// WithConcurrentIndexes configures the driver to create and drop the indexes of existing
+// tables concurrently, using the "CREATE INDEX CONCURRENTLY" and "DROP INDEX CONCURRENTLY"
+// commands. Since these commands cannot be executed inside a transaction block, plans
+// that contain them are not transactional and are marked with the "atlas:txmode none"
+// directive. Use SplitConcurrentIndexes to run the rest of the changes in transactions.
+//
+// Indexes of tables that are created by the plan, and indexes that back constraints
+// (e.g. UNIQUE or EXCLUDE) are not created concurrently, as there is no need to.
+func WithConcurrentIndexes() Option {
+	return func(c *conn) {
+		c.concurrentIndexes = true
+	}
+}
+
+// concurrentIndexes returns a copy of the given changes, in which the index changes
+// of existing tables are marked as concurrent. The given changes are not modified.
+func concurrentIndexes(changes []schema.Change) []schema.Change {
+	copied := make([]schema.Change, len(changes))
+	for i, c := range changes {
+		m, ok := c.(*schema.ModifyTable)
+		if !ok {
+			copied[i] = c
+			continue
+		}
+		m1 := *m
+		m1.Changes = make([]schema.Change, len(m.Changes))
+		for j, c := range m.Changes {
+			switch c := c.(type) {
+			case *schema.AddIndex:
+				if !constIndex(c.I) && !sqlx.Has(c.Extra, &Concurrently{}) {
+					c1 := *c
+					c1.Extra = append(slices.Clone(c.Extra), &Concurrently{})
+					m1.Changes[j] = &c1
+					continue
+				}
+			case *schema.DropIndex:
+				if !constIndex(c.I) && !sqlx.Has(c.Extra, &Concurrently{}) {
+					c1 := *c
+					c1.Extra = append(slices.Clone(c.Extra), &Concurrently{})
+					m1.Changes[j] = &c1
+					continue
+				}
+			}
+			m1.Changes[j] = c
+		}
+		copied[i] = &m1
+	}
+	return copied
+}
+
+// reConcurrentIndex matches the commands that cannot be executed inside a transaction block.
+var reConcurrentIndex = regexp.MustCompile(`^(?:CREATE (?:UNIQUE )?INDEX|DROP INDEX) CONCURRENTLY `)
+
+// SplitConcurrentIndexes splits the given plan into multiple plans (i.e. migration files),
+// such that only the concurrent index changes are executed outside a transaction block:
+//
+//  1. Consecutive concurrent index changes are grouped into a non-transactional plan,
+//     marked with the "atlas:txmode none" directive.
+//  2. The rest of the changes are grouped into transactional plans.
+//
+// A plan that does not contain concurrent index changes is returned as is. The versions
+// of the returned plans are incremented by one second, starting from the version of the
+// given plan, or from the current time if the plan has no version.
+func SplitConcurrentIndexes(p *migrate.Plan) ([]*migrate.Plan, error) {
+	var (
+		plans []*migrate.Plan
+		last  bool
+	)
+	for i, c := range p.Changes {
+		concurrent := reConcurrentIndex.MatchString(c.Cmd)
+		if i == 0 || concurrent != last {
+			plans = append(plans, &migrate.Plan{
+				Name:          p.Name,
+				Reversible:    p.Reversible,
+				Transactional: !concurrent,
+				Delimiter:     p.Delimiter,
+				// The transaction mode is set per plan below.
+				Directives: slices.DeleteFunc(slices.Clone(p.Directives), func(d string) bool {
+					return d == txModeNone
+				}),
+			})
+			if concurrent {
+				plans[len(plans)-1].AddDirectiveOnce(txModeNone)
+			}
+		}
+		plans[len(plans)-1].Changes = append(plans[len(plans)-1].Changes, c)
+		last = concurrent
+	}
+	switch {
+	case len(plans) == 0:
+		return []*migrate.Plan{p}, nil
+	case len(plans) == 1:
+		plans[0].Version = p.Version
+		return plans, nil
+	}
+	v := p.Version
+	if v == "" {
+		v = migrate.NewVersion()
+	}
+	base, err := time.Parse("20060102150405", v)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: cannot split plan with version %q: %w", v, err)
+	}
+	for i := range plans {
+		plans[i].Version = base.Add(time.Duration(i) * time.Second).Format("20060102150405")
+	}
+	return plans, nil
+}
+
+// constIndex reports if the index is backed by a constraint.
+func constIndex(idx *schema.Index) bool {
+	_, okU := uniqueConst(idx.Attrs)
+	_, okE := excludeConst(idx.Attrs)
+	return okU || okE
+}
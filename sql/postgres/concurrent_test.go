@@ -0,0 +1,129 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_ConcurrentIndexes(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := OpenWith(db, WithConcurrentIndexes())
+	require.NoError(t, err)
+	var (
+		s     = schema.New("public")
+		id    = schema.NewIntColumn("id", "int")
+		name  = schema.NewStringColumn("name", "text")
+		users = schema.NewTable("users").SetSchema(s).AddColumns(id, name)
+		pets  = schema.NewTable("pets").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+	)
+	pets.AddIndexes(schema.NewIndex("pets_id").AddColumns(pets.Columns[0]))
+	changes := []schema.Change{
+		&schema.AddTable{T: pets},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: schema.NewIntColumn("age", "int")},
+				&schema.AddIndex{I: schema.NewIndex("users_name").AddColumns(name)},
+				&schema.DropIndex{I: schema.NewIndex("users_id").AddColumns(id)},
+				&schema.AddIndex{I: schema.NewUniqueIndex("users_id_key").AddColumns(id).AddAttrs(UniqueConstraint("users_id_key"))},
+			},
+		},
+	}
+	plan, err := drv.PlanChanges(context.Background(), "add_indexes", changes, func(o *migrate.PlanOptions) {
+		o.SchemaQualifier = new(string)
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Transactional)
+	require.Equal(t, []string{"-- atlas:txmode none"}, plan.Directives)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "pets" ("id" integer NOT NULL)`, `DROP TABLE "pets"`},
+		{`CREATE INDEX "pets_id" ON "pets" ("id")`, `DROP INDEX "pets_id"`},
+		{`DROP INDEX CONCURRENTLY "users_id"`, `CREATE INDEX CONCURRENTLY "users_id" ON "users" ("id")`},
+		{`ALTER TABLE "users" ADD COLUMN "age" integer NOT NULL, ADD CONSTRAINT "users_id_key" UNIQUE ("id")`, `ALTER TABLE "users" DROP CONSTRAINT "users_id_key", DROP COLUMN "age"`},
+		{`CREATE INDEX CONCURRENTLY "users_name" ON "users" ("name")`, `DROP INDEX CONCURRENTLY "users_name"`},
+	}, planCmds(plan))
+	// The given changes are not modified.
+	for _, c := range changes[1].(*schema.ModifyTable).Changes {
+		switch c := c.(type) {
+		case *schema.AddIndex:
+			require.Empty(t, c.Extra)
+		case *schema.DropIndex:
+			require.Empty(t, c.Extra)
+		}
+	}
+
+	// Concurrent changes are split from the rest, but the order of the plan is preserved.
+	plans, err := SplitConcurrentIndexes(plan)
+	require.NoError(t, err)
+	require.Len(t, plans, 4)
+	for i, p := range plans {
+		require.NoError(t, migrate.CheckVersion(p.Version))
+		require.Equal(t, "add_indexes", p.Name)
+		require.Equal(t, i%2 == 0, p.Transactional)
+		if i > 0 {
+			require.Less(t, plans[i-1].Version, p.Version)
+		}
+	}
+	require.Empty(t, plans[0].Directives)
+	require.Len(t, plans[0].Changes, 2)
+	require.Equal(t, []string{"-- atlas:txmode none"}, plans[1].Directives)
+	require.Equal(t, `DROP INDEX CONCURRENTLY "users_id"`, plans[1].Changes[0].Cmd)
+	require.Empty(t, plans[2].Directives)
+	require.Len(t, plans[2].Changes, 1)
+	require.Equal(t, []string{"-- atlas:txmode none"}, plans[3].Directives)
+	require.Equal(t, `CREATE INDEX CONCURRENTLY "users_name" ON "users" ("name")`, plans[3].Changes[0].Cmd)
+
+	// Without the option, indexes are not created concurrently.
+	mock{m}.version("130000")
+	drv, err = Open(db)
+	require.NoError(t, err)
+	plan, err = drv.PlanChanges(context.Background(), "add_indexes", changes)
+	require.NoError(t, err)
+	require.True(t, plan.Transactional)
+	require.Empty(t, plan.Directives)
+	require.Equal(t, `CREATE INDEX "users_name" ON "public"."users" ("name")`, plan.Changes[len(plan.Changes)-1].Cmd)
+}
+
+func TestSplitConcurrentIndexes(t *testing.T) {
+	p := &migrate.Plan{
+		Version:       "20230101000000",
+		Name:          "plan",
+		Transactional: true,
+		Changes:       []*migrate.Change{{Cmd: `CREATE TABLE "t" ("id" integer)`}},
+	}
+	plans, err := SplitConcurrentIndexes(p)
+	require.NoError(t, err)
+	require.Equal(t, []*migrate.Plan{p}, plans, "no concurrent changes")
+
+	p.Changes = []*migrate.Change{{Cmd: `CREATE UNIQUE INDEX CONCURRENTLY "i" ON "t" ("id")`}}
+	plans, err = SplitConcurrentIndexes(p)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	require.False(t, plans[0].Transactional)
+	require.Equal(t, "20230101000000", plans[0].Version)
+	require.Equal(t, []string{"-- atlas:txmode none"}, plans[0].Directives)
+
+	p.Changes = append(p.Changes, &migrate.Change{Cmd: `ALTER TABLE "t" ADD COLUMN "c" integer`}, &migrate.Change{Cmd: `DROP INDEX CONCURRENTLY "j"`})
+	plans, err = SplitConcurrentIndexes(p)
+	require.NoError(t, err)
+	require.Len(t, plans, 3)
+	require.Equal(t, []string{"20230101000000", "20230101000001", "20230101000002"}, []string{plans[0].Version, plans[1].Version, plans[2].Version})
+	require.Equal(t, []bool{false, true, false}, []bool{plans[0].Transactional, plans[1].Transactional, plans[2].Transactional})
+	require.Empty(t, plans[1].Directives)
+
+	p.Version = "v1"
+	_, err = SplitConcurrentIndexes(p)
+	require.ErrorContains(t, err, `postgres: cannot split plan with version "v1"`)
+}
@@ -52,6 +52,16 @@ func FormatType(t schema.Type) (string, error) {
 			return "", errors.New("postgres: missing domain type name")
 		}
 		f = t.T
+	case *CustomRangeType:
+		if t.T == "" {
+			return "", errors.New("postgres: missing range type name")
+		}
+		f = t.T
+	case *CustomMultirangeType:
+		if t.T == "" {
+			return "", errors.New("postgres: missing multirange type name")
+		}
+		f = t.T
 	case *schema.EnumType:
 		if t.T == "" {
 			return "", errors.New("postgres: missing enum type name")
@@ -178,6 +188,11 @@ func FormatType(t schema.Type) (string, error) {
 		}
 	case *UserDefinedType:
 		f = t.T
+	case *VectorType:
+		f = t.T
+		if t.Dim > 0 {
+			f = fmt.Sprintf("%s(%d)", f, t.Dim)
+		}
 	case *XMLType:
 		f = strings.ToLower(t.T)
 	case *PseudoType:
@@ -303,7 +318,10 @@ func columnType(c *columnDesc) (schema.Type, error) {
 		if ft == "" {
 			ft = t
 		}
-		typ = &UserDefinedType{T: ft, C: c.typtype}
+		var err error
+		if typ, err = extensionType(ft, c.typtype); err != nil {
+			return nil, err
+		}
 	}
 	switch c.typtype {
 	case "d", "e":
@@ -313,6 +331,81 @@ func columnType(c *columnDesc) (schema.Type, error) {
 	return typ, nil
 }
 
+// extensionType returns the schema.Type of well-known types that are defined by
+// extensions (e.g., PostGIS or pgvector), or a user-defined type otherwise.
+func extensionType(ft, typtype string) (schema.Type, error) {
+	name, mod := extTypeName(ft)
+	switch name {
+	case TypeGeometry, TypeGeography:
+		return &schema.SpatialType{T: ft}, nil
+	case TypeVector, TypeHalfVec, TypeSparseVec:
+		v := &VectorType{T: strings.TrimSuffix(ft, mod)}
+		if mod = strings.Trim(mod, "() "); mod != "" {
+			d, err := strconv.Atoi(mod)
+			if err != nil {
+				return nil, fmt.Errorf("postgres: parse %s dimensions %q: %w", name, mod, err)
+			}
+			v.Dim = d
+		}
+		return v, nil
+	}
+	return &UserDefinedType{T: ft, C: typtype}, nil
+}
+
+// extTypeName returns the unqualified name of the given formatted
+// type and its modifier, if exists. e.g. "public.vector(3)" returns
+// "vector" and "(3)".
+func extTypeName(ft string) (name, mod string) {
+	_, name, mod = splitFmtType(ft)
+	return strings.ToLower(name), mod
+}
+
+// splitFmtType splits the given formatted type into its schema
+// qualifier, name and modifier. e.g. "public.geometry(Point,4326)".
+func splitFmtType(ft string) (ns, name, mod string) {
+	if i := strings.IndexByte(ft, '('); i > 0 && strings.HasSuffix(ft, ")") {
+		ft, mod = ft[:i], ft[i:]
+	}
+	ns, name = parseFmtType(ft)
+	return ns, name, mod
+}
+
+// typeExtensions maps well-known extension types to the extensions that define them.
+var typeExtensions = map[string]string{
+	TypeGeometry:  "postgis",
+	TypeGeography: "postgis",
+	TypeCIText:    "citext",
+	TypeHStore:    "hstore",
+	TypeLTree:     "ltree",
+	TypeCube:      "cube",
+	TypeVector:    "vector",
+	TypeHalfVec:   "vector",
+	TypeSparseVec: "vector",
+}
+
+// TypeExtension returns the name of the extension that defines the given
+// type, if it is one of the well-known extension types (e.g., citext).
+func TypeExtension(t schema.Type) (string, bool) {
+	var name string
+	switch t := t.(type) {
+	case *ArrayType:
+		if t.Type == nil {
+			return "", false
+		}
+		return TypeExtension(t.Type)
+	case *schema.SpatialType:
+		name, _ = extTypeName(t.T)
+	case *VectorType:
+		name, _ = extTypeName(t.T)
+	case *UserDefinedType:
+		name, _ = extTypeName(t.T)
+	default:
+		return "", false
+	}
+	ext, ok := typeExtensions[name]
+	return ext, ok
+}
+
 // reArray parses array declaration. See: https://postgresql.org/docs/current/arrays.html.
 var reArray = regexp.MustCompile(`(?i)(.+?)(( +ARRAY( *\[[ \d]*] *)*)+|( *\[[ \d]*] *)+)$`)
 
@@ -7,13 +7,19 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
 )
 
 type (
@@ -26,13 +32,69 @@ type (
 	noLockDriver struct {
 		noLocker
 	}
+
+	// IndexHashSharded describes a CockroachDB hash-sharded index. The index
+	// is stored in Buckets shards, computed by a hidden virtual column. A zero
+	// Buckets means the default bucket count of the database is used.
+	IndexHashSharded struct {
+		schema.Attr
+		Buckets int64
+	}
+
+	// Locality describes the locality of a table in a CockroachDB
+	// multi-region database. For example:
+	//
+	//	GLOBAL
+	//	REGIONAL BY TABLE IN "us-east1"
+	//	REGIONAL BY ROW AS "region"
+	//
+	Locality struct {
+		schema.Attr
+		Kind   string // GLOBAL, REGIONAL BY TABLE or REGIONAL BY ROW.
+		Region string // Region of REGIONAL BY TABLE tables. Empty means the primary region.
+		Column string // Region column of REGIONAL BY ROW tables. Empty means crdb_region.
+	}
+
+	// RowTTL describes the row-level TTL storage parameters of a CockroachDB table.
+	RowTTL struct {
+		schema.Attr
+		ExpireAfter    string // ttl_expire_after.
+		ExpirationExpr string // ttl_expiration_expression.
+		JobCron        string // ttl_job_cron.
+	}
 )
 
+// CockroachDB locality kinds.
+const (
+	LocalityGlobal          = "GLOBAL"
+	LocalityRegionalByTable = "REGIONAL BY TABLE"
+	LocalityRegionalByRow   = "REGIONAL BY ROW"
+)
+
+// CockroachDB storage parameters of row-level TTL.
+const (
+	ttlParam               = "ttl"
+	ttlParamExpireAfter    = "ttl_expire_after"
+	ttlParamExpirationExpr = "ttl_expiration_expression"
+	ttlParamJobCron        = "ttl_job_cron"
+)
+
+// Default bucket count of hash-sharded indexes.
+const defaultBucketCount = 16
+
 var _ sqlx.DiffDriver = (*crdbDiff)(nil)
 
-// pathSchema fixes: https://github.com/cockroachdb/cockroach/issues/82040.
+// pathSchema fixes: https://github.com/cockroachdb/cockroach/issues/82040,
+// and drops the hidden shard columns (and their checks) of hash-sharded indexes.
 func (i *crdbInspect) patchSchema(s *schema.Schema) {
 	for _, t := range s.Tables {
+		t.Columns = slices.DeleteFunc(t.Columns, func(c *schema.Column) bool {
+			return reShardColumn.MatchString(c.Name)
+		})
+		t.Attrs = slices.DeleteFunc(t.Attrs, func(a schema.Attr) bool {
+			c, ok := a.(*schema.Check)
+			return ok && reShardColumn.MatchString(strings.TrimPrefix(c.Name, "check_"))
+		})
 		for _, c := range t.Columns {
 			id, ok := identity(c.Attrs)
 			if !ok {
@@ -55,6 +117,9 @@ func (i *crdbInspect) InspectSchema(ctx context.Context, name string, opts *sche
 		return nil, err
 	}
 	i.patchSchema(s)
+	if err := i.inspectTableAttrs(ctx, s); err != nil {
+		return nil, err
+	}
 	return s, err
 }
 
@@ -65,10 +130,493 @@ func (i *crdbInspect) InspectRealm(ctx context.Context, opts *schema.InspectReal
 	}
 	for _, s := range r.Schemas {
 		i.patchSchema(s)
+		if err := i.inspectTableAttrs(ctx, s); err != nil {
+			return nil, err
+		}
 	}
 	return r, nil
 }
 
+// inspectTableAttrs sets the locality and the row-level TTL of the inspected
+// tables. Table localities were introduced in CockroachDB v21.1.
+func (i *crdbInspect) inspectTableAttrs(ctx context.Context, s *schema.Schema) error {
+	if len(s.Tables) == 0 || !i.supports(featLocality) {
+		return nil
+	}
+	rows, err := i.querySchema(ctx, crdbTableAttrsQuery, s)
+	if err != nil {
+		return fmt.Errorf("cockroach: querying schema %q table attributes: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name              string
+			locality, options sql.NullString
+		)
+		if err := rows.Scan(&name, &locality, &options); err != nil {
+			return fmt.Errorf("cockroach: scanning table attributes: %w", err)
+		}
+		t, ok := s.Table(name)
+		if !ok {
+			continue
+		}
+		if sqlx.ValidString(locality) {
+			l, err := parseLocality(locality.String)
+			if err != nil {
+				return fmt.Errorf("cockroach: table %q: %w", name, err)
+			}
+			// The default locality is not recorded.
+			if !l.isDefault() {
+				t.AddAttrs(l)
+			}
+		}
+		if sqlx.ValidString(options) {
+			var opts []string
+			if err := json.Unmarshal([]byte(options.String), &opts); err != nil {
+				return fmt.Errorf("cockroach: parsing table %q options: %w", name, err)
+			}
+			if ttl := rowTTL(opts); ttl != nil {
+				t.AddAttrs(ttl)
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// crdbVersion parses the crdb_version setting (e.g. "CockroachDB CCL v23.1.2 (...)")
+// into the server_version_num format (e.g. 230102). Zero is returned if the
+// version cannot be parsed.
+func crdbVersion(s string) int {
+	m := reCRDBVersion.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	var v int
+	for _, p := range m[1:] {
+		n, _ := strconv.Atoi(p)
+		v = v*100 + n
+	}
+	return v
+}
+
+// parseLocality parses the locality clause of a table.
+func parseLocality(s string) (*Locality, error) {
+	m := reLocality.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("unexpected table locality: %q", s)
+	}
+	l := &Locality{Kind: LocalityGlobal}
+	switch kind, in := strings.ToUpper(m[2]), strings.ToUpper(m[3]); {
+	case strings.ToUpper(m[1]) == LocalityGlobal:
+		if m[3] != "" {
+			return nil, fmt.Errorf("unexpected table locality: %q", s)
+		}
+	case kind == "ROW":
+		if l.Kind = LocalityRegionalByRow; in == "IN" {
+			return nil, fmt.Errorf("unexpected table locality: %q", s)
+		}
+		if in == "AS" {
+			l.Column = unquoteIdent(m[4])
+		}
+	default:
+		if l.Kind = LocalityRegionalByTable; in == "AS" {
+			return nil, fmt.Errorf("unexpected table locality: %q", s)
+		}
+		if in == "IN" && !strings.EqualFold(strings.Join(strings.Fields(m[4]), " "), "PRIMARY REGION") {
+			l.Region = unquoteIdent(m[4])
+		}
+	}
+	return l, nil
+}
+
+// String returns the locality clause of the table.
+func (l *Locality) String() string {
+	switch strings.ToUpper(l.Kind) {
+	case LocalityGlobal:
+		return LocalityGlobal
+	case LocalityRegionalByRow:
+		if l.Column == "" || l.Column == "crdb_region" {
+			return LocalityRegionalByRow
+		}
+		return fmt.Sprintf("%s AS %s", LocalityRegionalByRow, quoteIdent(l.Column))
+	default:
+		if l.Region == "" {
+			return LocalityRegionalByTable + " IN PRIMARY REGION"
+		}
+		return fmt.Sprintf("%s IN %s", LocalityRegionalByTable, quoteIdent(l.Region))
+	}
+}
+
+// isDefault reports if the locality is the default one (REGIONAL BY TABLE IN PRIMARY REGION).
+func (l *Locality) isDefault() bool {
+	return l.String() == (&Locality{}).String()
+}
+
+// quoteIdent quotes the given identifier with double quotes.
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// unquoteIdent unquotes the given identifier, if it is quoted.
+func unquoteIdent(s string) string {
+	if s = strings.TrimSpace(s); sqlx.IsQuoted(s, '"') {
+		return strings.ReplaceAll(s[1:len(s)-1], `""`, `"`)
+	}
+	return s
+}
+
+// rowTTL returns the row-level TTL from the table storage parameters, if exists.
+func rowTTL(opts []string) *RowTTL {
+	var (
+		ok  bool
+		ttl RowTTL
+	)
+	for _, o := range opts {
+		k, v, _ := strings.Cut(o, "=")
+		switch k {
+		case ttlParamExpireAfter:
+			ttl.ExpireAfter, ok = v, true
+		case ttlParamExpirationExpr:
+			ttl.ExpirationExpr, ok = v, true
+		case ttlParamJobCron:
+			ttl.JobCron, ok = v, true
+		}
+	}
+	if !ok {
+		return nil
+	}
+	return &ttl
+}
+
+// params returns the storage parameters of the row-level TTL.
+func (t *RowTTL) params() [][2]string {
+	var ps [][2]string
+	for _, p := range [][2]string{
+		{ttlParamExpireAfter, t.ExpireAfter},
+		{ttlParamExpirationExpr, t.ExpirationExpr},
+		{ttlParamJobCron, t.JobCron},
+	} {
+		if p[1] != "" {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// rowTTLParams writes the given row-level TTL parameters (e.g. "ttl_expire_after = '1 day'").
+func rowTTLParams(b *sqlx.Builder, ps [][2]string) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(ps, func(i int, b *sqlx.Builder) {
+			b.P(ps[i][0], "=", quote(ps[i][1]))
+		})
+	})
+}
+
+// rowTTLChange writes the row-level TTL change to the ALTER TABLE command.
+func rowTTLChange(b *sqlx.Builder, from, to *RowTTL) {
+	ps := to.params()
+	if len(ps) == 0 {
+		b.P("RESET").Wrap(func(b *sqlx.Builder) {
+			b.P(ttlParam)
+		})
+		return
+	}
+	b.P("SET")
+	rowTTLParams(b, ps)
+	var reset []string
+	for _, p := range from.params() {
+		if !slices.ContainsFunc(ps, func(p2 [2]string) bool { return p[0] == p2[0] }) {
+			reset = append(reset, p[0])
+		}
+	}
+	if len(reset) > 0 {
+		b.Comma().P("RESET").Wrap(func(b *sqlx.Builder) {
+			b.P(strings.Join(reset, ", "))
+		})
+	}
+}
+
+// crdbTableAttrDiff returns the changes of the CockroachDB table attributes.
+func crdbTableAttrDiff(from, to *schema.Table) []schema.Change {
+	var (
+		changes []schema.Change
+		l1, l2  Locality
+		t1, t2  RowTTL
+	)
+	sqlx.Has(from.Attrs, &l1)
+	sqlx.Has(to.Attrs, &l2)
+	if l1.String() != l2.String() {
+		changes = append(changes, &schema.ModifyAttr{From: &l1, To: &l2})
+	}
+	sqlx.Has(from.Attrs, &t1)
+	sqlx.Has(to.Attrs, &t2)
+	if !slices.Equal(t1.params(), t2.params()) {
+		changes = append(changes, &schema.ModifyAttr{From: &t1, To: &t2})
+	}
+	return changes
+}
+
+// addCRDBTableAttrs writes the row-level TTL and the locality of the table to its CREATE command.
+func addCRDBTableAttrs(b *sqlx.Builder, t *schema.Table) {
+	if ttl := (RowTTL{}); sqlx.Has(t.Attrs, &ttl) && len(ttl.params()) > 0 {
+		b.P("WITH")
+		rowTTLParams(b, ttl.params())
+	}
+	if l := (Locality{}); sqlx.Has(t.Attrs, &l) && !l.isDefault() {
+		b.P("LOCALITY", l.String())
+	}
+}
+
+// hashSharded returns the hash-sharded attribute of the index, if exists.
+func hashSharded(attrs []schema.Attr) (*IndexHashSharded, bool) {
+	h := &IndexHashSharded{}
+	if !sqlx.Has(attrs, h) {
+		return nil, false
+	}
+	return h, true
+}
+
+// hashShardedChanged reports if the index was changed from or to a hash-sharded
+// index, or if its bucket count was changed.
+func hashShardedChanged(from, to []schema.Attr) bool {
+	h1, ok1 := hashSharded(from)
+	h2, ok2 := hashSharded(to)
+	if ok1 != ok2 {
+		return true
+	}
+	return ok1 && h1.buckets() != h2.buckets()
+}
+
+// buckets returns the bucket count of the index.
+func (h *IndexHashSharded) buckets() int64 {
+	if h.Buckets == 0 {
+		return defaultBucketCount
+	}
+	return h.Buckets
+}
+
+// convertHashSharded converts the "hash_sharded" and "bucket_count" attributes of an index, if exist.
+func convertHashSharded(spec specutil.Attrer, idx *schema.Index) error {
+	var h IndexHashSharded
+	attr, ok := spec.Attr("hash_sharded")
+	if ok {
+		if ok, err := attr.Bool(); err != nil || !ok {
+			return err
+		}
+	}
+	if attr, found := spec.Attr("bucket_count"); found {
+		if !ok {
+			return fmt.Errorf("bucket_count requires hash_sharded to be set for index %q", idx.Name)
+		}
+		n, err := attr.Int64()
+		if err != nil {
+			return err
+		}
+		h.Buckets = n
+	}
+	if ok {
+		idx.AddAttrs(&h)
+	}
+	return nil
+}
+
+// hashShardedSpec appends the hash-sharded attributes to the index spec, if needed.
+func hashShardedSpec(idx *schema.Index, attrs []*schemahcl.Attr) []*schemahcl.Attr {
+	if h, ok := hashSharded(idx.Attrs); ok {
+		attrs = append(attrs, schemahcl.BoolAttr("hash_sharded", true))
+		if h.Buckets > 0 {
+			attrs = append(attrs, schemahcl.Int64Attr("bucket_count", h.Buckets))
+		}
+	}
+	return attrs
+}
+
+// convertCRDBTableAttrs converts the "locality" attribute and the "row_ttl" block of the table spec, if exist.
+func convertCRDBTableAttrs(spec *sqlspec.Table, t *schema.Table) error {
+	if attr, ok := spec.Attr("locality"); ok {
+		s, err := attr.String()
+		if err != nil {
+			return err
+		}
+		l, err := parseLocality(s)
+		if err != nil {
+			return fmt.Errorf("parsing %s.locality: %w", t.Name, err)
+		}
+		t.AddAttrs(l)
+	}
+	r, ok := spec.Extra.Resource("row_ttl")
+	if !ok {
+		return nil
+	}
+	var ttl struct {
+		ExpireAfter    string `spec:"expire_after"`
+		ExpirationExpr string `spec:"expiration_expr"`
+		JobCron        string `spec:"job_cron"`
+	}
+	if err := r.As(&ttl); err != nil {
+		return fmt.Errorf("parsing %s.row_ttl: %w", t.Name, err)
+	}
+	if ttl.ExpireAfter == "" && ttl.ExpirationExpr == "" {
+		return fmt.Errorf("missing attribute %s.row_ttl.expire_after or %[1]s.row_ttl.expiration_expr", t.Name)
+	}
+	t.AddAttrs(&RowTTL{ExpireAfter: ttl.ExpireAfter, ExpirationExpr: ttl.ExpirationExpr, JobCron: ttl.JobCron})
+	return nil
+}
+
+// crdbTableAttrsSpec appends the "locality" attribute and the "row_ttl" block to the table spec, if needed.
+func crdbTableAttrsSpec(t *schema.Table, spec *sqlspec.Table) {
+	if l := (Locality{}); sqlx.Has(t.Attrs, &l) && !l.isDefault() {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("locality", l.String()))
+	}
+	if ttl := (RowTTL{}); sqlx.Has(t.Attrs, &ttl) && len(ttl.params()) > 0 {
+		r := &schemahcl.Resource{Type: "row_ttl"}
+		for _, a := range []struct{ n, v string }{
+			{"expire_after", ttl.ExpireAfter},
+			{"expiration_expr", ttl.ExpirationExpr},
+			{"job_cron", ttl.JobCron},
+		} {
+			if a.v != "" {
+				r.SetAttr(schemahcl.StringAttr(a.n, a.v))
+			}
+		}
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+}
+
+// objectFeature returns the feature that gates the given object, if any.
+func objectFeature(o schema.Object) (feature, bool) {
+	switch o.(type) {
+	case *EventTrigger:
+		return featEventTriggers, true
+	case *Publication:
+		return featPublications, true
+	case *ForeignServer:
+		return featForeignServers, true
+	case *ForeignTable:
+		return featForeignTables, true
+	}
+	return 0, false
+}
+
+// checkDialect returns an error if the given changes use features that
+// are not supported by the connected database, instead of producing
+// invalid DDL. For example, partitioned tables on CockroachDB, column
+// type changes on old YugabyteDB releases, or hash-sharded indexes
+// on PostgreSQL.
+func (s *state) checkDialect(changes []schema.Change) error {
+	for _, c := range changes {
+		var (
+			t     *schema.Table
+			attrs [][]schema.Attr
+		)
+		switch c := c.(type) {
+		case *schema.AddTable:
+			t, attrs = c.T, append(attrs, c.T.Attrs)
+			if c.T.PrimaryKey != nil {
+				attrs = append(attrs, c.T.PrimaryKey.Attrs)
+			}
+			for _, idx := range c.T.Indexes {
+				attrs = append(attrs, idx.Attrs)
+			}
+		case *schema.ModifyTable:
+			t = c.T
+			for _, c := range c.Changes {
+				switch c := c.(type) {
+				case *schema.AddIndex:
+					attrs = append(attrs, c.I.Attrs)
+				case *schema.ModifyIndex:
+					attrs = append(attrs, c.To.Attrs)
+				case *schema.AddPrimaryKey:
+					attrs = append(attrs, c.P.Attrs)
+				case *schema.ModifyPrimaryKey:
+					attrs = append(attrs, c.To.Attrs)
+				case *schema.AddCheck:
+					attrs = append(attrs, c.C.Attrs)
+				case *schema.ModifyCheck:
+					attrs = append(attrs, c.To.Attrs)
+				case *schema.AddAttr:
+					attrs = append(attrs, []schema.Attr{c.A})
+				case *schema.ModifyAttr:
+					attrs = append(attrs, []schema.Attr{c.To})
+				case *schema.ModifyColumn:
+					if !c.Change.Is(schema.ChangeType) {
+						break
+					}
+					if err := s.checkFeature(featAlterColumnType); err != nil {
+						return fmt.Errorf("table %q: column %q: %w", t.Name, c.To.Name, err)
+					}
+				}
+			}
+		case *schema.AddObject:
+			if f, ok := objectFeature(c.O); ok {
+				if err := s.checkFeature(f); err != nil {
+					return err
+				}
+			}
+		case *schema.ModifyObject:
+			if f, ok := objectFeature(c.To); ok {
+				if err := s.checkFeature(f); err != nil {
+					return err
+				}
+			}
+		}
+		for _, as := range attrs {
+			if err := s.checkAttrs(as); err != nil {
+				return fmt.Errorf("table %q: %w", t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkAttrs returns an error if one of the attributes
+// is not supported by the connected database.
+func (s *state) checkAttrs(attrs []schema.Attr) error {
+	for _, a := range attrs {
+		var f feature
+		switch a := a.(type) {
+		case *IndexHashSharded:
+			f = featHashSharded
+		case *Locality:
+			f = featLocality
+		case *RowTTL:
+			f = featRowTTL
+		case *Colocation:
+			f = featColocation
+		case *TabletSplit:
+			f = featTabletSplit
+		case *Partition:
+			f = featPartitions
+		case *NoInherit:
+			f = featNoInherit
+		case *Constraint:
+			if !a.IsExclude() {
+				continue
+			}
+			f = featExcludeConstraints
+		case *IndexType:
+			// CockroachDB supports only a subset of the index methods.
+			switch t := strings.ToUpper(a.T); {
+			case !s.crdb, t == IndexTypeBTree, t == IndexTypeGIN, t == IndexTypeGiST:
+				continue
+			default:
+				return fmt.Errorf("cockroach: %s indexes are not supported", t)
+			}
+		case *schema.Check:
+			if err := s.checkAttrs(a.Attrs); err != nil {
+				return err
+			}
+			continue
+		default:
+			continue
+		}
+		if err := s.checkFeature(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Normalize implements the sqlx.Normalizer.
 func (cd *crdbDiff) Normalize(from, to *schema.Table, _ *schema.DiffOptions) error {
 	cd.normalize(from)
@@ -187,7 +735,13 @@ func (i *inspect) crdbIndexes(ctx context.Context, s *schema.Schema) error {
 	return rows.Err()
 }
 
-var reIndexType = regexp.MustCompile("(?i)USING (BTREE|GIN|GIST)")
+var (
+	reIndexType   = regexp.MustCompile("(?i)USING (BTREE|GIN|GIST)")
+	reHashSharded = regexp.MustCompile(`(?i)USING HASH(?:\s+WITH\s*\(\s*bucket_count\s*=\s*(\d+)\s*\))?`)
+	reShardColumn = regexp.MustCompile(`^crdb_internal_.+_shard_\d+$`)
+	reCRDBVersion = regexp.MustCompile(`v(\d+)\.(\d+)\.(\d+)`)
+	reLocality    = regexp.MustCompile(`(?is)^(GLOBAL|REGIONAL(?:\s+BY\s+(TABLE|ROW))?)(?:\s+(IN|AS)\s+(.+))?$`)
+)
 
 func (i *inspect) crdbAddIndexes(s *schema.Schema, rows *sql.Rows) error {
 	// Unlike Postgres, Cockroach may have duplicate index names.
@@ -218,6 +772,13 @@ func (i *inspect) crdbAddIndexes(s *schema.Schema, rows *sql.Rows) error {
 			if parts := reIndexType.FindStringSubmatch(createStmt); len(parts) > 0 {
 				idx.Attrs = append(idx.Attrs, &IndexType{T: parts[1]})
 			}
+			if parts := reHashSharded.FindStringSubmatch(createStmt); len(parts) > 0 {
+				h := &IndexHashSharded{}
+				if parts[1] != "" {
+					h.Buckets, _ = strconv.ParseInt(parts[1], 10, 64)
+				}
+				idx.Attrs = append(idx.Attrs, h)
+			}
 			if sqlx.ValidString(comment) {
 				idx.Attrs = append(idx.Attrs, &schema.Comment{Text: comment.String})
 			}
@@ -234,6 +795,10 @@ func (i *inspect) crdbAddIndexes(s *schema.Schema, rows *sql.Rows) error {
 				t.Indexes = append(t.Indexes, idx)
 			}
 		}
+		// The hidden shard column is maintained by the database.
+		if _, ok := hashSharded(idx.Attrs); ok && sqlx.ValidString(column) && reShardColumn.MatchString(column.String) {
+			continue
+		}
 		part := &schema.IndexPart{SeqNo: len(idx.Parts) + 1, Desc: strings.Contains(createStmt, "DESC")}
 		switch {
 		case sqlx.ValidString(column):
@@ -296,6 +861,25 @@ ORDER BY
 	table_name, index_name, idx.ord
 `
 
+	// CockroachDB query for getting the locality and the storage parameters of the schema tables.
+	crdbTableAttrsQuery = `
+SELECT
+	t.name AS table_name,
+	t.locality,
+	array_to_json(c.reloptions) AS options
+FROM
+	crdb_internal.tables AS t
+	JOIN pg_catalog.pg_namespace AS n ON n.nspname = t.schema_name
+	JOIN pg_catalog.pg_class AS c ON c.relnamespace = n.oid AND c.relname = t.name
+WHERE
+	t.database_name = current_database()
+	AND t.state = 'PUBLIC'
+	AND t.schema_name = $1
+	AND t.name IN (%s)
+ORDER BY
+	t.name
+`
+
 	crdbColumnsQuery = `
 SELECT
 	t1.table_name,
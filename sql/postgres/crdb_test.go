@@ -0,0 +1,334 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectCRDBTableAttrs(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  version       |  am  | crdb | desc
+----------------|------|-------------------------------------------|-----
+ 130000         | heap | CockroachDB CCL v23.1.2 (x86_64-pc-linux-gnu) | PostgreSQL 13.0
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.tableExists("public", "events", true)
+	mk.ExpectQuery(queryCRDBColumns).
+		WithArgs("public", "events").
+		WillReturnRows(sqltest.Rows(`
+table_name  | column_name               | data_type | formatted | is_nullable | column_default | character_maximum_length | numeric_precision | datetime_precision | numeric_scale | interval_type | character_set_name | collation_name | is_identity | identity_start | identity_increment | identity_last | identity_generation | generation_expression | comment | typtype | typelem | oid | attnum
+------------+---------------------------+-----------+-----------+-------------+----------------+--------------------------+-------------------+--------------------+---------------+---------------+--------------------+----------------+-------------+----------------+--------------------+---------------+---------------------+-----------------------+---------+---------+---------+-----+--------
+events      | id                        | bigint    | bigint    | NO          |                |                          |                64 |                    |             0 |               |                    |                | NO          |                |                    |               |                     |                       |         | b       |         | 20  |
+events      | ts                        | bigint    | bigint    | NO          |                |                          |                64 |                    |             0 |               |                    |                | NO          |                |                    |               |                     |                       |         | b       |         | 20  |
+events      | crdb_internal_ts_shard_8  | bigint    | bigint    | NO          |                |                          |                64 |                    |             0 |               |                    |                | NO          |                |                    |               |                     |                       |         | b       |         | 20  |
+`))
+	mk.ExpectQuery(queryCRDBIndexes).
+		WithArgs("public", "events").
+		WillReturnRows(sqltest.Rows(`
+table_name  | index_name  | column_name              | primary | unique | constraint_type | create_stmt                                                                                                                     | predicate | expression | comment
+------------+-------------+--------------------------+---------+--------+-----------------+---------------------------------------------------------------------------------------------------------------------------------+-----------+------------+---------
+events      | events_pkey | id                       | true    | true   | p               | CREATE UNIQUE INDEX events_pkey ON defaultdb.public.events USING btree (id ASC)                                                 |           | id         |
+events      | events_ts   | crdb_internal_ts_shard_8 | false   | false  |                 | CREATE INDEX events_ts ON defaultdb.public.events USING btree (crdb_internal_ts_shard_8 ASC, ts ASC) USING HASH WITH (bucket_count=8) |     | crdb_internal_ts_shard_8 |
+events      | events_ts   | ts                       | false   | false  |                 | CREATE INDEX events_ts ON defaultdb.public.events USING btree (crdb_internal_ts_shard_8 ASC, ts ASC) USING HASH WITH (bucket_count=8) |     | ts         |
+`))
+	mk.noFKs()
+	mk.noChecks()
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(crdbTableAttrsQuery, "$2"))).
+		WithArgs("public", "events").
+		WillReturnRows(sqltest.Rows(`
+ table_name | locality                        | options
+------------+---------------------------------+--------------------------------------------------------------
+ events     | REGIONAL BY TABLE IN "us-east1" | ["ttl='on'", "ttl_expire_after=3 days", "ttl_job_cron=@daily"]
+`))
+	s, err := drv.InspectSchema(context.Background(), "public", &schema.InspectOptions{
+		Mode: schema.InspectSchemas | schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	tbl, ok := s.Table("events")
+	require.True(t, ok)
+	require.Len(t, tbl.Columns, 2, "shard column should be dropped")
+	idx, ok := tbl.Index("events_ts")
+	require.True(t, ok)
+	require.Len(t, idx.Parts, 1)
+	require.Equal(t, "ts", idx.Parts[0].C.Name)
+	h, ok := hashSharded(idx.Attrs)
+	require.True(t, ok)
+	require.EqualValues(t, 8, h.Buckets)
+	var l Locality
+	require.True(t, sqlx.Has(tbl.Attrs, &l))
+	require.Equal(t, Locality{Kind: LocalityRegionalByTable, Region: "us-east1"}, l)
+	var ttl RowTTL
+	require.True(t, sqlx.Has(tbl.Attrs, &ttl))
+	require.Equal(t, RowTTL{ExpireAfter: "3 days", JobCron: "@daily"}, ttl)
+}
+
+func TestParseLocality(t *testing.T) {
+	for _, tt := range []struct {
+		in, out string
+		wantErr bool
+	}{
+		{in: "GLOBAL", out: "GLOBAL"},
+		{in: "global", out: "GLOBAL"},
+		{in: "REGIONAL", out: "REGIONAL BY TABLE IN PRIMARY REGION"},
+		{in: "REGIONAL BY TABLE", out: "REGIONAL BY TABLE IN PRIMARY REGION"},
+		{in: "REGIONAL BY TABLE IN PRIMARY REGION", out: "REGIONAL BY TABLE IN PRIMARY REGION"},
+		{in: `REGIONAL BY TABLE IN "us-east1"`, out: `REGIONAL BY TABLE IN "us-east1"`},
+		{in: `REGIONAL IN "us-east1"`, out: `REGIONAL BY TABLE IN "us-east1"`},
+		{in: "REGIONAL BY ROW", out: "REGIONAL BY ROW"},
+		{in: "REGIONAL BY ROW AS crdb_region", out: "REGIONAL BY ROW"},
+		{in: `REGIONAL BY ROW AS "region"`, out: `REGIONAL BY ROW AS "region"`},
+		{in: "GLOBAL IN x", wantErr: true},
+		{in: "REGIONAL BY ROW IN x", wantErr: true},
+		{in: "LOCAL", wantErr: true},
+	} {
+		l, err := parseLocality(tt.in)
+		if tt.wantErr {
+			require.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		require.Equal(t, tt.out, l.String())
+	}
+	require.Equal(t, 230102, crdbVersion("CockroachDB CCL v23.1.2 (x86_64-pc-linux-gnu, built 2023/05/25)"))
+	require.Zero(t, crdbVersion("cockroach"))
+}
+
+func TestDiff_CRDBAttrs(t *testing.T) {
+	var (
+		d    = &crdbDiff{diff{&conn{ExecQuerier: sqlx.NoRows, version: 13_00_00, crdb: true, crdbVersion: 23_01_02}}}
+		from = schema.NewTable("t")
+		to   = schema.NewTable("t").AddAttrs(
+			&Locality{Kind: LocalityGlobal},
+			&RowTTL{ExpireAfter: "1 day"},
+		)
+	)
+	changes, err := d.TableAttrDiff(from, to, &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyAttr{From: &Locality{}, To: &Locality{Kind: LocalityGlobal}},
+		&schema.ModifyAttr{From: &RowTTL{}, To: &RowTTL{ExpireAfter: "1 day"}},
+	}, changes)
+
+	// The default locality is equal to no locality.
+	changes, err = d.TableAttrDiff(from, schema.NewTable("t").AddAttrs(&Locality{Kind: LocalityRegionalByTable}), &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	require.False(t, d.IndexAttrChanged([]schema.Attr{&IndexHashSharded{}}, []schema.Attr{&IndexHashSharded{Buckets: defaultBucketCount}}))
+	require.True(t, d.IndexAttrChanged([]schema.Attr{&IndexHashSharded{}}, []schema.Attr{&IndexHashSharded{Buckets: 8}}))
+	require.True(t, d.IndexAttrChanged(nil, []schema.Attr{&IndexHashSharded{}}))
+}
+
+func TestPlanChanges_CRDB(t *testing.T) {
+	var (
+		crdb = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows, version: 13_00_00, crdb: true, crdbVersion: 23_01_02}}
+		s    = schema.New("public")
+		tbl  = schema.NewTable("events").
+			AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("ts", "bigint")).
+			AddAttrs(
+				&Locality{Kind: LocalityRegionalByRow},
+				&RowTTL{ExpireAfter: "3 days", JobCron: "@daily"},
+			)
+	)
+	s.AddTables(tbl)
+	tbl.SetPrimaryKey(schema.NewPrimaryKey(tbl.Columns[0]).AddAttrs(&IndexHashSharded{}))
+	tbl.AddIndexes(schema.NewIndex("events_ts").AddColumns(tbl.Columns[1]).AddAttrs(&IndexHashSharded{Buckets: 8}))
+	plan, err := crdb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "public"."events" ("id" bigint NOT NULL, "ts" bigint NOT NULL, PRIMARY KEY ("id") USING HASH) WITH (ttl_expire_after = '3 days', ttl_job_cron = '@daily') LOCALITY REGIONAL BY ROW`, `DROP TABLE "public"."events"`},
+		{`CREATE INDEX "events_ts" ON "public"."events" ("ts") USING HASH WITH (bucket_count = 8)`, `DROP INDEX "public"."events_ts"`},
+	}, planCmds(plan))
+
+	plan, err = crdb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+			&schema.ModifyAttr{From: &Locality{Kind: LocalityRegionalByRow}, To: &Locality{Kind: LocalityRegionalByTable, Region: "us-east1"}},
+			&schema.ModifyAttr{From: &RowTTL{ExpireAfter: "3 days", JobCron: "@daily"}, To: &RowTTL{ExpireAfter: "1 day"}},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{
+			`ALTER TABLE "public"."events" SET LOCALITY REGIONAL BY TABLE IN "us-east1", SET (ttl_expire_after = '1 day'), RESET (ttl_job_cron)`,
+			`ALTER TABLE "public"."events" SET (ttl_expire_after = '3 days', ttl_job_cron = '@daily'), SET LOCALITY REGIONAL BY ROW`,
+		},
+	}, planCmds(plan))
+
+	plan, err = crdb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+			&schema.ModifyAttr{From: &RowTTL{ExpireAfter: "1 day"}, To: &RowTTL{}},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`ALTER TABLE "public"."events" RESET (ttl)`, `ALTER TABLE "public"."events" SET (ttl_expire_after = '1 day')`},
+	}, planCmds(plan))
+
+	// Unsupported features are reported instead of producing invalid DDL.
+	for _, c := range []struct {
+		changes []schema.Change
+		err     string
+	}{
+		{
+			changes: []schema.Change{&schema.AddTable{T: schema.NewTable("logs").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int")).AddAttrs(&Partition{T: PartitionTypeRange})}},
+			err:     `table "logs": cockroach: table partitions are not supported`,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.AddIndex{I: schema.NewIndex("events_brin").AddColumns(tbl.Columns[1]).AddAttrs(&IndexType{T: IndexTypeBRIN})}}}},
+			err:     `table "events": cockroach: BRIN indexes are not supported`,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.AddCheck{C: schema.NewCheck().SetName("positive").SetExpr("id > 0").AddAttrs(&NoInherit{})}}}},
+			err:     `table "events": cockroach: NO INHERIT constraints are not supported`,
+		},
+		{
+			changes: []schema.Change{&schema.AddObject{O: &Publication{Name: "pub"}}},
+			err:     `cockroach: publications are not supported`,
+		},
+	} {
+		_, err = crdb.PlanChanges(context.Background(), "plan", c.changes)
+		require.EqualError(t, err, c.err)
+	}
+
+	// CockroachDB features are rejected by PostgreSQL.
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.EqualError(t, err, `table "events": postgres: table localities are supported only by CockroachDB`)
+
+	// Features are gated by the CockroachDB version.
+	crdb.crdbVersion = 22_01_00
+	_, err = crdb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.EqualError(t, err, `table "events": cockroach: row-level TTL are supported only by CockroachDB v22.2 and above`)
+}
+
+func TestConn_Supports(t *testing.T) {
+	var (
+		pg10 = &conn{version: 10_00_00}
+		pg9  = &conn{version: 9_06_00}
+		crdb = &conn{version: 13_00_00, crdb: true, crdbVersion: 21_01_00}
+	)
+	for _, f := range []feature{featRangeTypes, featMatViews, featSequences, featPublications, featRoles, featPartitions} {
+		require.True(t, pg10.supports(f), features[f].name)
+		require.False(t, crdb.supports(f), features[f].name)
+	}
+	for _, f := range []feature{featHashSharded, featLocality} {
+		require.False(t, pg10.supports(f), features[f].name)
+		require.True(t, crdb.supports(f), features[f].name)
+	}
+	require.False(t, pg9.supports(featSequences))
+	require.False(t, crdb.supports(featRowTTL))
+	require.False(t, crdb.supports(feature(255)), "unknown features are not supported")
+
+	require.NoError(t, pg10.checkFeature(featPartitions))
+	require.EqualError(t, pg9.checkFeature(featSequences), "postgres: sequences are supported only by PostgreSQL 10 and above")
+	require.EqualError(t, pg10.checkFeature(featRowTTL), "postgres: row-level TTL are supported only by CockroachDB")
+	require.EqualError(t, crdb.checkFeature(featPartitions), "cockroach: table partitions are not supported")
+	require.EqualError(t, crdb.checkFeature(featRowTTL), "cockroach: row-level TTL are supported only by CockroachDB v22.2 and above")
+}
+
+func TestOpenCockroach(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	_, err = OpenCockroach(db)
+	require.EqualError(t, err, "cockroach: connected server is not CockroachDB")
+
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  version       |  am  | crdb | desc
+----------------|------|-------------------------------------------|-----
+ 130000         | heap | CockroachDB CCL v23.1.2 (x86_64-pc-linux-gnu) | PostgreSQL 13.0
+`))
+	drv, err := OpenCockroach(db)
+	require.NoError(t, err)
+	require.IsType(t, noLockDriver{}, drv)
+	require.NoError(t, m.ExpectationsWereMet())
+
+	u, err := url.Parse("cockroach://root@localhost:26257/defaultdb?sslmode=disable&search_path=public")
+	require.NoError(t, err)
+	ur := crdbParser{}.ParseURL(u)
+	require.Equal(t, "postgres://root@localhost:26257/defaultdb?sslmode=disable&search_path=public", ur.DSN)
+	require.Equal(t, "public", ur.Schema)
+	require.Equal(t, "cockroach", ur.Scheme)
+}
+
+func TestMarshalSpec_CRDBAttrs(t *testing.T) {
+	s := schema.New("public")
+	tbl := schema.NewTable("events").
+		AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("ts", "bigint")).
+		AddAttrs(
+			&Locality{Kind: LocalityGlobal},
+			&RowTTL{ExpireAfter: "3 days"},
+		)
+	s.AddTables(tbl)
+	tbl.SetPrimaryKey(schema.NewPrimaryKey(tbl.Columns[0]))
+	tbl.AddIndexes(schema.NewIndex("events_ts").AddColumns(tbl.Columns[1]).AddAttrs(&IndexHashSharded{Buckets: 8}))
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema   = schema.public
+  locality = "GLOBAL"
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "ts" {
+    null = false
+    type = bigint
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "events_ts" {
+    columns      = [column.ts]
+    hash_sharded = true
+    bucket_count = 8
+  }
+  row_ttl {
+    expire_after = "3 days"
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	gt, ok := got.Table("events")
+	require.True(t, ok)
+	require.Empty(t, crdbTableAttrDiff(tbl, gt))
+	idx, ok := gt.Index("events_ts")
+	require.True(t, ok)
+	require.False(t, hashShardedChanged(tbl.Indexes[0].Attrs, idx.Attrs))
+}
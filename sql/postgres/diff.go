@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode"
@@ -29,7 +30,8 @@ type diff struct{ *conn }
 // SupportChange reports if the change is supported by the differ.
 func (*diff) SupportChange(c schema.Change) bool {
 	switch c.(type) {
-	case *schema.RenameConstraint:
+	case *schema.RenameConstraint,
+		*schema.AddTrigger, *schema.AddView, *schema.AddProc:
 		return false
 	}
 	return true
@@ -52,7 +54,7 @@ func (d *diff) SchemaAttrDiff(from, to *schema.Schema) []schema.Change {
 	if change := sqlx.CommentDiff(fromA, toA); change != nil {
 		changes = append(changes, change)
 	}
-	return changes
+	return append(changes, ownerDiff(from.Attrs, to.Attrs)...)
 }
 
 func skipDefaultComment(s *schema.Schema, public string) []schema.Attr {
@@ -78,7 +80,7 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	}
 	changes = append(changes, change...)
 	return append(changes, sqlx.CheckDiffMode(from, to, opts.Mode, func(c1, c2 *schema.Check) bool {
-		return sqlx.Has(c1.Attrs, &NoInherit{}) == sqlx.Has(c2.Attrs, &NoInherit{})
+		return sqlx.Has(c1.Attrs, &NoInherit{}) == sqlx.Has(c2.Attrs, &NoInherit{}) && sqlx.CommentChange(c1.Attrs, c2.Attrs) == schema.NoChange
 	})...), nil
 }
 
@@ -101,7 +103,7 @@ func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.
 	if changed {
 		change |= schema.ChangeDefault
 	}
-	if identityChanged(from.Attrs, to.Attrs) {
+	if identityChanged(from, to) {
 		change |= schema.ChangeAttr
 	}
 	if changed, err = d.generatedChanged(from, to); err != nil {
@@ -221,14 +223,14 @@ func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 	if indexNullsDistinct(to) != indexNullsDistinct(from) {
 		return true
 	}
-	if uniqueConstChanged(from, to) || excludeConstChanged(from, to) {
+	if uniqueConstChanged(from, to) || excludeConstChanged(from, to) || deferrableChanged(from, to) {
 		return true
 	}
 	var p1, p2 IndexPredicate
-	if sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || (p1.P != p2.P && p1.P != sqlx.MayWrap(p2.P)) {
+	if sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || normalizePredicate(p1.P) != normalizePredicate(p2.P) {
 		return true
 	}
-	if indexIncludeChanged(from, to) {
+	if indexIncludeChanged(from, to) || hashShardedChanged(from, to) {
 		return true
 	}
 	s1, ok1 := indexStorageParams(from)
@@ -285,8 +287,14 @@ func (*diff) ReferenceChanged(from, to schema.ReferenceOption) bool {
 }
 
 // ForeignKeyAttrChanged reports if any of the foreign-key attributes were changed.
-func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
-	return false
+func (*diff) ForeignKeyAttrChanged(from, to []schema.Attr) bool {
+	return deferrableChanged(from, to) || sqlx.CommentChange(from, to) != schema.NoChange
+}
+
+// deferrableChanged reports if the DEFERRABLE attribute of a constraint was changed.
+func deferrableChanged(from, to []schema.Attr) bool {
+	var d1, d2 Deferrable
+	return sqlx.Has(from, &d1) != sqlx.Has(to, &d2) || d1.InitiallyDeferred != d2.InitiallyDeferred
 }
 
 // DiffOptions defines PostgreSQL specific schema diffing process.
@@ -334,6 +342,81 @@ func (*diff) AnnotateChanges(changes []schema.Change, opts *schema.DiffOptions)
 	return changes, nil
 }
 
+// TypeConversion implements the sqlx.ConversionClassifier interface.
+func (*diff) TypeConversion(from, to schema.Type) schema.TypeConversion {
+	return TypeConversion(from, to)
+}
+
+// TypeConversion classifies the conversion of column values from one PostgreSQL type
+// to another. For example, changing integer to bigint is safe, varchar(255) to
+// varchar(50) is lossy, and jsonb to integer is incompatible.
+func TypeConversion(from, to schema.Type) schema.TypeConversion {
+	// Serial types are converted as their underlying integer types.
+	if s, ok := from.(*SerialType); ok {
+		from = s.IntegerType()
+	}
+	if s, ok := to.(*SerialType); ok {
+		to = s.IntegerType()
+	}
+	// There are no implicit casts between different enum types.
+	if f, ok := from.(*schema.EnumType); ok {
+		if t, ok := to.(*schema.EnumType); ok && (f.T != t.T || f.Schema != nil && t.Schema != nil && f.Schema.Name != t.Schema.Name) {
+			return schema.ConversionIncompatible
+		}
+	}
+	return sqlx.ClassifyConversion(from, to, typeSize)
+}
+
+// typeSize returns the size of the type, as expected by sqlx.ClassifyConversion.
+func typeSize(t schema.Type) int64 {
+	switch t := t.(type) {
+	case *schema.IntegerType:
+		switch strings.ToLower(t.T) {
+		case TypeSmallInt, TypeInt2:
+			return 2
+		case TypeInteger, TypeInt, TypeInt4:
+			return 4
+		case TypeBigInt, TypeInt8:
+			return 8
+		}
+	case *schema.FloatType:
+		switch strings.ToLower(t.T) {
+		case TypeReal, TypeFloat4:
+			return 4
+		case TypeDouble, TypeFloat8:
+			return 8
+		case TypeFloat:
+			if t.Precision > 0 && t.Precision <= 24 {
+				return 4
+			}
+			return 8
+		}
+	case *schema.DecimalType:
+		// Numeric columns without a precision can store
+		// values of any precision (up to the implementation limit).
+		if t.Precision == 0 {
+			return -1
+		}
+	case *schema.StringType:
+		switch strings.ToLower(t.T) {
+		case TypeText:
+			return -1
+		case TypeVarChar, TypeCharVar, TypeBPChar:
+			if t.Size == 0 {
+				return -1
+			}
+			return int64(t.Size)
+		case TypeChar, TypeCharacter:
+			return int64(max(t.Size, 1))
+		}
+	case *schema.BinaryType:
+		if strings.EqualFold(t.T, TypeBytea) {
+			return -1
+		}
+	}
+	return 0
+}
+
 func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	return typeChanged(from, to, d.conn.schema)
 }
@@ -349,7 +432,7 @@ func typeChanged(from, to *schema.Column, ns string) (bool, error) {
 	var changed bool
 	switch fromT := fromT.(type) {
 	case *schema.BinaryType, *BitType, *schema.BoolType, *schema.DecimalType, *schema.FloatType, *IntervalType,
-		*schema.IntegerType, *schema.JSONType, *OIDType, *RangeType, *SerialType, *schema.SpatialType,
+		*schema.IntegerType, *schema.JSONType, *OIDType, *RangeType, *SerialType,
 		*schema.StringType, *PseudoType, *schema.TimeType, *TextSearchType, *NetworkType, *schema.UUIDType:
 		t1, err := FormatType(toT)
 		if err != nil {
@@ -360,12 +443,16 @@ func typeChanged(from, to *schema.Column, ns string) (bool, error) {
 			return false, err
 		}
 		changed = t1 != t2
-	case *UserDefinedType:
-		toT := toT.(*UserDefinedType)
-		changed = toT.T != fromT.T &&
-			// In case the type is defined with schema qualifier, but returned without
-			// (inspecting a schema scope), or vice versa, remove before comparing.
-			ns != "" && trimSchema(toT.T, ns) != trimSchema(toT.T, ns)
+	case *UserDefinedType, *VectorType, *schema.SpatialType:
+		t1, err := FormatType(toT)
+		if err != nil {
+			return false, err
+		}
+		t2, err := FormatType(fromT)
+		if err != nil {
+			return false, err
+		}
+		changed = !udtEqual(t1, t2, ns)
 	case *CompositeType:
 		toT := toT.(*CompositeType)
 		changed = toT.T != fromT.T ||
@@ -374,6 +461,14 @@ func typeChanged(from, to *schema.Column, ns string) (bool, error) {
 		toT := toT.(*DomainType)
 		changed = toT.T != fromT.T ||
 			(toT.Schema != nil && fromT.Schema != nil && toT.Schema.Name != fromT.Schema.Name)
+	case *CustomRangeType:
+		toT := toT.(*CustomRangeType)
+		changed = toT.T != fromT.T ||
+			(toT.Schema != nil && fromT.Schema != nil && toT.Schema.Name != fromT.Schema.Name)
+	case *CustomMultirangeType:
+		toT := toT.(*CustomMultirangeType)
+		changed = toT.T != fromT.T ||
+			(toT.Range != nil && fromT.Range != nil && toT.Range.Schema != nil && fromT.Range.Schema != nil && toT.Range.Schema.Name != fromT.Range.Schema.Name)
 	case *schema.EnumType:
 		toT := toT.(*schema.EnumType)
 		// Column type was changed if the underlying enum type was changed.
@@ -399,7 +494,7 @@ func typeChanged(from, to *schema.Column, ns string) (bool, error) {
 				return false, err
 			}
 			// Same underlying type.
-			changed = t1 != t2
+			changed = !udtEqual(t1, t2, ns)
 		}
 	default:
 		return false, &sqlx.UnsupportedTypeError{Type: fromT}
@@ -407,6 +502,19 @@ func typeChanged(from, to *schema.Column, ns string) (bool, error) {
 	return changed, nil
 }
 
+// udtEqual reports if the two formatted types are equal. In case one of the types
+// is defined with a schema qualifier, but the other is returned without it (e.g.,
+// inspecting a schema scope, or extension types that reside in the search_path),
+// the qualifier is ignored.
+func udtEqual(t1, t2, ns string) bool {
+	if t1 == t2 || ns != "" && trimSchema(t1, ns) == trimSchema(t2, ns) {
+		return true
+	}
+	s1, n1, m1 := splitFmtType(t1)
+	s2, n2, m2 := splitFmtType(t2)
+	return n1 == n2 && m1 == m2 && (s1 == "" || s2 == "" || s1 == s2)
+}
+
 // trimSchema returns the given type without the schema qualifier.
 func trimSchema(t string, ns string) string {
 	if strings.HasPrefix(t, `"`) {
@@ -454,13 +562,34 @@ const (
 )
 
 // identityChanged reports if one of the identity attributes was changed.
-func identityChanged(from, to []schema.Attr) bool {
-	i1, ok1 := identity(from)
-	i2, ok2 := identity(to)
+func identityChanged(from, to *schema.Column) bool {
+	i1, ok1 := identity(from.Attrs)
+	i2, ok2 := identity(to.Attrs)
 	if !ok1 && !ok2 || ok1 != ok2 {
 		return ok1 != ok2
 	}
-	return i1.Generation != i2.Generation || i1.Sequence.Start != i2.Sequence.Start || i1.Sequence.Increment != i2.Sequence.Increment
+	o1, d1 := identityOptions(i1, from)
+	o2, d2 := identityOptions(i2, to)
+	return i1.Generation != i2.Generation || i1.Sequence.Start != i2.Sequence.Start || i1.Sequence.Increment != i2.Sequence.Increment ||
+		o1.cache != o2.cache || o1.cycle != o2.cycle || boundChanged(o1.min, o2.min, d1.min, d2.min) || boundChanged(o1.max, o2.max, d1.max, d2.max)
+}
+
+// identityOptions returns the normalized options of the sequence that backs the
+// identity column, and the options PostgreSQL uses by default for its type.
+func identityOptions(i *Identity, c *schema.Column) (*seqOptions, *seqOptions) {
+	s := *i.Sequence
+	if s.Type == nil && c.Type != nil {
+		s.Type = c.Type.Type
+	}
+	o := s.options()
+	return o, o.defaults()
+}
+
+// boundChanged reports if the MINVALUE or MAXVALUE of a sequence was changed. Bounds
+// that are set to the defaults of their types are considered equal, as they follow the
+// type of the sequence (e.g. on column type change).
+func boundChanged(v1, v2, d1, d2 int64) bool {
+	return v1 != v2 && (v1 != d1 || v2 != d2)
 }
 
 func identity(attrs []schema.Attr) (*Identity, bool) {
@@ -518,7 +647,51 @@ func indexStorageParams(attrs []schema.Attr) (*IndexStorageParams, bool) {
 	if !sqlx.Has(attrs, s) {
 		return nil, false
 	}
-	if !s.AutoSummarize && (s.PagesPerRange == 0 || s.PagesPerRange == defaultPagesPerRange) {
+	// Reset parameters that are set to their default values,
+	// as they are not reported by the database in some cases.
+	t := &IndexType{T: IndexTypeBTree}
+	sqlx.Has(attrs, t)
+	switch strings.ToUpper(t.T) {
+	case IndexTypeBTree, IndexTypeGiST:
+		if s.FillFactor == defaultBtreeFill {
+			s.FillFactor = 0
+		}
+	case IndexTypeHash:
+		if s.FillFactor == defaultHashFill {
+			s.FillFactor = 0
+		}
+	case IndexTypeSPGiST:
+		if s.FillFactor == defaultSPGiSTFill {
+			s.FillFactor = 0
+		}
+	case IndexTypeIVFFlat:
+		if s.Lists == defaultIVFFlatLists {
+			s.Lists = 0
+		}
+	case IndexTypeHNSW:
+		if s.M == defaultHNSWM {
+			s.M = 0
+		}
+		if s.EfConstruction == defaultHNSWEf {
+			s.EfConstruction = 0
+		}
+	}
+	if s.PagesPerRange == defaultPagesPerRange {
+		s.PagesPerRange = 0
+	}
+	if s.ListLimit == defaultListLimit {
+		s.ListLimit = 0
+	}
+	if s.Deduplicate = strings.ToUpper(s.Deduplicate); s.Deduplicate == storageParamOn {
+		s.Deduplicate = ""
+	}
+	if s.FastUpdate = strings.ToUpper(s.FastUpdate); s.FastUpdate == storageParamOn {
+		s.FastUpdate = ""
+	}
+	if s.Buffering = strings.ToUpper(s.Buffering); s.Buffering == bufferingAuto {
+		s.Buffering = ""
+	}
+	if *s == (IndexStorageParams{}) {
 		return nil, false
 	}
 	return s, true
@@ -538,6 +711,43 @@ func indexIncludeChanged(from, to []schema.Attr) bool {
 	return false
 }
 
+// reLiteralCast matches type casts of literals (e.g. 'a'::text or NULL::integer)
+// that are added by PostgreSQL to the predicate of partial indexes.
+var reLiteralCast = regexp.MustCompile(`('(?:[^']|'')*'|\bNULL\b|\b\d+(?:\.\d+)?\b)::(?:"[^"]+"|\w+)(?:\s+varying|\s+with(?:out)?\s+time\s+zone|\s+precision)?(?:\[])*`)
+
+// normalizePredicate normalizes the predicate of partial indexes for comparison.
+// It strips the outer parentheses and the literal casts, and collapses whitespaces.
+func normalizePredicate(p string) string {
+	p = strings.Join(strings.Fields(p), " ")
+	for n := len(p) - 1; n > 0 && p[0] == '(' && p[n] == ')' && balanced(p[1:n]); n = len(p) - 1 {
+		p = strings.TrimSpace(p[1:n])
+	}
+	return reLiteralCast.ReplaceAllString(p, "$1")
+}
+
+// balanced reports if the parentheses of the given expression are balanced.
+func balanced(p string) bool {
+	var depth int
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		case '\'', '"':
+			for j := i + 1; j < len(p); j++ {
+				if p[j] == p[i] {
+					i = j
+					break
+				}
+			}
+		}
+	}
+	return depth == 0
+}
+
 // indexNullsDistinct returns the NULLS [NOT] DISTINCT value from the index attributes.
 func indexNullsDistinct(attrs []schema.Attr) bool {
 	if i := (IndexNullsDistinct{}); sqlx.Has(attrs, &i) {
@@ -6,6 +6,7 @@ package postgres
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/schemahcl"
@@ -58,6 +59,33 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "qualified extension types",
+			from: schema.NewTable("users").AddColumns(
+				schema.NewColumn("name").SetType(&UserDefinedType{T: "public.citext"}),
+				schema.NewColumn("tags").SetType(&ArrayType{T: "public.citext[]", Type: &UserDefinedType{T: "public.citext"}}),
+				schema.NewColumn("loc").SetType(&schema.SpatialType{T: "public.geometry(Point,4326)"}),
+				schema.NewColumn("emb").SetType(&VectorType{T: "public.vector", Dim: 3}),
+			),
+			to: schema.NewTable("users").AddColumns(
+				schema.NewColumn("name").SetType(&UserDefinedType{T: "citext"}),
+				schema.NewColumn("tags").SetType(&ArrayType{T: "citext[]", Type: &UserDefinedType{T: "citext"}}),
+				schema.NewColumn("loc").SetType(&schema.SpatialType{T: "geometry(point,4326)"}),
+				schema.NewColumn("emb").SetType(&VectorType{T: "vector", Dim: 3}),
+			),
+		},
+		{
+			name: "change user-defined type",
+			from: schema.NewTable("users").AddColumns(schema.NewColumn("name").SetType(&UserDefinedType{T: "citext"})),
+			to:   schema.NewTable("users").AddColumns(schema.NewColumn("name").SetType(&UserDefinedType{T: "ltree"})),
+			wantChanges: []schema.Change{
+				&schema.ModifyColumn{
+					From:   schema.NewColumn("name").SetType(&UserDefinedType{T: "citext"}),
+					To:     schema.NewColumn("name").SetType(&UserDefinedType{T: "ltree"}),
+					Change: schema.ChangeType,
+				},
+			},
+		},
 		{
 			name: "drop partition key",
 			from: schema.NewTable("logs").
@@ -264,8 +292,8 @@ func TestDiff_TableDiff(t *testing.T) {
 				from: from,
 				to:   to,
 				wantChanges: []schema.Change{
-					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType},
-					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeType},
+					&schema.ModifyColumn{From: from.Columns[0], To: to.Columns[0], Change: schema.ChangeType, Conversion: schema.ConversionIncompatible},
+					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeType, Conversion: schema.ConversionIncompatible},
 				},
 			}
 		}(),
@@ -469,6 +497,32 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			}
 		}(),
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("ref_id", "int"))
+				to   = schema.NewTable("t1").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("ref_id", "int"))
+			)
+			from.AddIndexes(
+				schema.NewUniqueIndex("u").AddColumns(from.Columns[0]).AddAttrs(UniqueConstraint("u")),
+				schema.NewIndex("e").AddParts(schema.NewColumnPart(from.Columns[0]).AddAttrs(&Operator{Name: "="})).AddAttrs(ExcludeConstraint("e")),
+			)
+			from.AddForeignKeys(schema.NewForeignKey("fk").AddColumns(from.Columns[1]).SetRefTable(from).AddRefColumns(from.Columns[0]))
+			to.AddIndexes(
+				schema.NewUniqueIndex("u").AddColumns(to.Columns[0]).AddAttrs(UniqueConstraint("u"), &Deferrable{}),
+				schema.NewIndex("e").AddParts(schema.NewColumnPart(to.Columns[0]).AddAttrs(&Operator{Name: "="})),
+			)
+			to.AddForeignKeys(schema.NewForeignKey("fk").AddColumns(to.Columns[1]).SetRefTable(to).AddRefColumns(to.Columns[0]).AddAttrs(&Deferrable{InitiallyDeferred: true}))
+			return testcase{
+				name: "deferrable and exclude constraints",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyIndex{From: from.Indexes[0], To: to.Indexes[0], Change: schema.ChangeAttr},
+					&schema.ModifyIndex{From: from.Indexes[1], To: to.Indexes[1], Change: schema.ChangeAttr},
+					&schema.ModifyForeignKey{From: from.ForeignKeys[0], To: to.ForeignKeys[0], Change: schema.ChangeAttr},
+				},
+			}
+		}(),
 	}
 	for _, tt := range tests {
 		db, m, err := sqlmock.New()
@@ -660,3 +714,161 @@ concurrent_index {
 	require.Equal(t, `CREATE INDEX CONCURRENTLY "users_pkey_new" ON "public"."users" ("id")`, plan.Changes[1].Cmd)
 	require.Equal(t, `DROP INDEX CONCURRENTLY "public"."users_pkey_new"`, plan.Changes[1].Reverse)
 }
+
+func TestDiff_IndexStorageParamsAndPredicate(t *testing.T) {
+	attrsChanged := func(from, to []schema.Attr) bool {
+		return (&diff{}).IndexAttrChanged(from, to)
+	}
+	for _, tt := range []struct {
+		from, to []schema.Attr
+		changed  bool
+	}{
+		{from: nil, to: []schema.Attr{&IndexStorageParams{FillFactor: 90}}},
+		{from: nil, to: []schema.Attr{&IndexStorageParams{FillFactor: 80}}, changed: true},
+		{from: []schema.Attr{&IndexType{T: "hash"}}, to: []schema.Attr{&IndexType{T: IndexTypeHash}, &IndexStorageParams{FillFactor: 75}}},
+		{from: []schema.Attr{&IndexStorageParams{Deduplicate: "on"}}, to: nil},
+		{from: []schema.Attr{&IndexStorageParams{Deduplicate: "off"}}, to: nil, changed: true},
+		{from: []schema.Attr{&IndexType{T: IndexTypeGiST}, &IndexStorageParams{Buffering: "auto"}}, to: []schema.Attr{&IndexType{T: IndexTypeGiST}}},
+		{from: []schema.Attr{&IndexType{T: IndexTypeGIN}, &IndexStorageParams{FastUpdate: "OFF", ListLimit: 4096}}, to: []schema.Attr{&IndexType{T: IndexTypeGIN}, &IndexStorageParams{FastUpdate: "off"}}},
+		{from: []schema.Attr{&IndexType{T: IndexTypeGIN}, &IndexStorageParams{ListLimit: 1024}}, to: []schema.Attr{&IndexType{T: IndexTypeGIN}}, changed: true},
+		{from: []schema.Attr{&IndexStorageParams{PagesPerRange: 128}}, to: nil},
+		{from: []schema.Attr{&IndexType{T: "ivfflat"}, &IndexStorageParams{Lists: 100}}, to: []schema.Attr{&IndexType{T: IndexTypeIVFFlat}}},
+		{from: []schema.Attr{&IndexType{T: "ivfflat"}, &IndexStorageParams{Lists: 100}}, to: []schema.Attr{&IndexType{T: IndexTypeIVFFlat}, &IndexStorageParams{Lists: 50}}, changed: true},
+		{from: []schema.Attr{&IndexType{T: "hnsw"}, &IndexStorageParams{M: 16, EfConstruction: 64}}, to: []schema.Attr{&IndexType{T: IndexTypeHNSW}}},
+		{from: []schema.Attr{&IndexType{T: "hnsw"}, &IndexStorageParams{M: 16, EfConstruction: 64}}, to: []schema.Attr{&IndexType{T: IndexTypeHNSW}, &IndexStorageParams{EfConstruction: 128}}, changed: true},
+		{from: []schema.Attr{&IndexType{T: "hnsw"}}, to: []schema.Attr{&IndexType{T: IndexTypeIVFFlat}}, changed: true},
+		{from: []schema.Attr{&IndexPredicate{P: "(status <> 'deleted'::text)"}}, to: []schema.Attr{&IndexPredicate{P: "status <>  'deleted'"}}},
+		{from: []schema.Attr{&IndexPredicate{P: "((id <> NULL::integer))"}}, to: []schema.Attr{&IndexPredicate{P: "id <> NULL"}}},
+		{from: []schema.Attr{&IndexPredicate{P: "(a > 1) AND (b > 2)"}}, to: []schema.Attr{&IndexPredicate{P: "a > 1 AND b > 2"}}, changed: true},
+		{from: []schema.Attr{&IndexPredicate{P: "(name)::text <> ''::text"}}, to: []schema.Attr{&IndexPredicate{P: "(name)::text <> ''"}}},
+	} {
+		require.Equal(t, tt.changed, attrsChanged(tt.from, tt.to), "from: %v, to: %v", tt.from, tt.to)
+	}
+}
+
+func TestDiff_ConstraintComments(t *testing.T) {
+	d := &diff{}
+	require.False(t, d.ForeignKeyAttrChanged(nil, nil))
+	require.True(t, d.ForeignKeyAttrChanged(nil, []schema.Attr{&schema.Comment{Text: "c"}}))
+	require.True(t, d.ForeignKeyAttrChanged([]schema.Attr{&schema.Comment{Text: "a"}}, []schema.Attr{&schema.Comment{Text: "b"}}))
+	require.False(t, d.ForeignKeyAttrChanged([]schema.Attr{&schema.Comment{Text: "a"}}, []schema.Attr{&schema.Comment{Text: "a"}}))
+
+	from := schema.NewTable("t").AddChecks(schema.NewCheck().SetName("c").SetExpr("a > 0"))
+	to := schema.NewTable("t").AddChecks(schema.NewCheck().SetName("c").SetExpr("a > 0").AddAttrs(&schema.Comment{Text: "positive"}))
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.IsType(t, &schema.ModifyCheck{}, changes[0])
+}
+
+func TestDiff_IdentityOptions(t *testing.T) {
+	col := func(typ string, id *Identity) *schema.Column {
+		return schema.NewIntColumn("id", typ).AddAttrs(id)
+	}
+	i64 := func(v int64) *int64 { return &v }
+	for _, tt := range []struct {
+		from, to *schema.Column
+		changed  bool
+	}{
+		{from: col("bigint", &Identity{}), to: col("bigint", &Identity{Generation: "BY DEFAULT", Sequence: &Sequence{Start: 1, Increment: 1, Cache: 1}})},
+		{from: col("bigint", &Identity{}), to: col("bigint", &Identity{Sequence: &Sequence{Cache: 10}}), changed: true},
+		{from: col("bigint", &Identity{}), to: col("bigint", &Identity{Sequence: &Sequence{Cycle: true}}), changed: true},
+		{from: col("bigint", &Identity{}), to: col("bigint", &Identity{Sequence: &Sequence{Max: i64(1000)}}), changed: true},
+		{from: col("bigint", &Identity{}), to: col("bigint", &Identity{Sequence: &Sequence{Min: i64(1)}})},
+		{from: col("integer", &Identity{}), to: col("integer", &Identity{Sequence: &Sequence{Max: i64(2147483647)}})},
+		// Default bounds follow the column type.
+		{from: col("integer", &Identity{}), to: col("bigint", &Identity{})},
+		{from: col("integer", &Identity{Sequence: &Sequence{Max: i64(1000)}}), to: col("bigint", &Identity{}), changed: true},
+		{from: col("bigint", &Identity{}), to: schema.NewIntColumn("id", "bigint"), changed: true},
+	} {
+		require.Equal(t, tt.changed, identityChanged(tt.from, tt.to))
+		require.Equal(t, tt.changed, identityChanged(tt.to, tt.from))
+	}
+}
+
+func TestTypeConversion(t *testing.T) {
+	for _, tt := range []struct {
+		from, to schema.Type
+		want     schema.TypeConversion
+	}{
+		{&schema.IntegerType{T: TypeInteger}, &schema.IntegerType{T: TypeBigInt}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeInt8}, &schema.IntegerType{T: TypeInt4}, schema.ConversionLossy},
+		{&SerialType{T: TypeSerial}, &SerialType{T: TypeBigSerial}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeBigInt}, &schema.DecimalType{T: TypeNumeric}, schema.ConversionSafe},
+		{&schema.IntegerType{T: TypeInteger}, &schema.StringType{T: TypeText}, schema.ConversionSafe},
+		{&schema.StringType{T: TypeVarChar, Size: 255}, &schema.StringType{T: TypeVarChar, Size: 50}, schema.ConversionLossy},
+		{&schema.StringType{T: TypeVarChar, Size: 255}, &schema.StringType{T: TypeText}, schema.ConversionSafe},
+		{&schema.StringType{T: TypeText}, &schema.StringType{T: TypeVarChar, Size: 255}, schema.ConversionLossy},
+		{&schema.StringType{T: TypeText}, &schema.IntegerType{T: TypeInteger}, schema.ConversionIncompatible},
+		{&schema.DecimalType{T: TypeNumeric}, &schema.DecimalType{T: TypeNumeric, Precision: 10}, schema.ConversionLossy},
+		{&schema.FloatType{T: TypeReal}, &schema.FloatType{T: TypeDouble}, schema.ConversionSafe},
+		{&schema.JSONType{T: TypeJSON}, &schema.JSONType{T: TypeJSONB}, schema.ConversionSafe},
+		{&schema.JSONType{T: TypeJSONB}, &schema.IntegerType{T: TypeInteger}, schema.ConversionIncompatible},
+		{&schema.TimeType{T: TypeTimestamp}, &schema.TimeType{T: TypeTimestampTZ}, schema.ConversionSafe},
+		{&schema.EnumType{T: "status", Values: []string{"a"}}, &schema.EnumType{T: "state", Values: []string{"a"}}, schema.ConversionIncompatible},
+	} {
+		require.Equal(t, tt.want, TypeConversion(tt.from, tt.to), "%T(%v) -> %T(%v)", tt.from, tt.from, tt.to, tt.to)
+	}
+}
+
+func TestDefaultDialect(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", TypeInteger), schema.NewStringColumn("user", TypeText))
+	users.AddIndexes(schema.NewIndex("users_user").AddColumns(users.Columns[1]))
+	logs := schema.NewTable(strings.Repeat("l", 64)).
+		AddColumns(schema.NewIntColumn("user_id", TypeBigInt), schema.NewStringColumn("user_name", TypeText))
+	logs.AddIndexes(schema.NewIndex("users_user").AddColumns(logs.Columns[1]))
+	logs.AddForeignKeys(
+		schema.NewForeignKey("id_fk").AddColumns(logs.Columns[0]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+		schema.NewForeignKey("name_fk").AddColumns(logs.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+	)
+	diags := schema.Validate(schema.NewRealm(schema.New("public").AddTables(users, logs)), DefaultDialect)
+	require.Len(t, diags, 4)
+	require.Equal(t, schema.DiagReservedWord, diags[0].Code)
+	require.Equal(t, "schema.public.table.users.column.user", diags[0].Path)
+	require.Equal(t, schema.DiagIdentLength, diags[1].Code)
+	require.Equal(t, schema.DiagDuplicateName, diags[2].Code)
+	// Integer columns of different sizes can be referenced.
+	require.Equal(t, schema.DiagForeignKeyMismatch, diags[3].Code)
+	require.Equal(t, "schema.public.table."+logs.Name+".foreign_key.name_fk", diags[3].Path)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", TypeInteger), schema.NewStringColumn("name", TypeVarChar, schema.StringSize(100)))
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	r42 := schema.NewRealm(schema.New("public").AddTables(users))
+	r43 := r42.Clone()
+	u43 := r43.Schemas[0].Tables[0]
+	u43.Columns[1].Type.Type = &schema.StringType{T: TypeVarChar, Size: 255}
+	u43.AddColumns(schema.NewNullStringColumn("email", TypeText)).
+		AddIndexes(schema.NewUniqueIndex("users_email").AddColumns(u43.Columns[2]))
+	r43.Schemas[0].AddTables(schema.NewTable("posts").AddColumns(schema.NewIntColumn("id", TypeInteger)))
+
+	b42, err := schema.MarshalJSON(r42)
+	require.NoError(t, err)
+	b43, err := schema.MarshalJSON(r43)
+	require.NoError(t, err)
+	changes, err := schema.DiffSnapshots(DefaultDiff, b42, b43)
+	require.NoError(t, err)
+	live, err := DefaultDiff.RealmDiff(r42, r43)
+	require.NoError(t, err)
+	p1, err := DefaultPlan.PlanChanges(context.Background(), "snapshots", changes)
+	require.NoError(t, err)
+	p2, err := DefaultPlan.PlanChanges(context.Background(), "live", live)
+	require.NoError(t, err)
+	require.Len(t, p1.Changes, 3)
+	require.Len(t, p2.Changes, 3)
+	for i := range p2.Changes {
+		require.Equal(t, p2.Changes[i].Cmd, p1.Changes[i].Cmd)
+	}
+	changes, err = schema.DiffSnapshots(DefaultDiff, b43, b43)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// Only realm snapshots are supported.
+	bt, err := schema.MarshalJSON(users)
+	require.NoError(t, err)
+	_, err = schema.DiffSnapshots(DefaultDiff, bt, b43)
+	require.EqualError(t, err, `sql/schema: from snapshot is not a realm: unexpected key "Columns"`)
+}
@@ -7,6 +7,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"math/rand"
@@ -20,6 +21,7 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlerr"
 	"ariga.io/atlas/sql/sqlspec"
 )
 
@@ -43,10 +45,24 @@ type (
 		// System variables that are set on `Open`.
 		version int
 		crdb    bool
+		// The CockroachDB version in the same format as the
+		// server_version_num (e.g. 230102), if it can be parsed.
+		crdbVersion int
+		yb          bool
+		// The YugabyteDB release in the same format as the
+		// server_version_num (e.g. 22001), if it can be parsed.
+		ybVersion int
+		// Create and drop the indexes of existing tables
+		// concurrently. See WithConcurrentIndexes.
+		concurrentIndexes bool
 	}
+
+	// Option allows configuring the driver using functional arguments.
+	Option func(*conn)
 )
 
 var _ interface {
+	migrate.CapabilityReporter
 	migrate.StmtScanner
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
@@ -54,6 +70,9 @@ var _ interface {
 // DriverName holds the name used for registration.
 const DriverName = "postgres"
 
+// CockroachDriverName holds the name used for registering the CockroachDB driver.
+const CockroachDriverName = "cockroach"
+
 func init() {
 	sqlclient.Register(
 		DriverName,
@@ -63,15 +82,51 @@ func init() {
 		sqlclient.RegisterCodec(codec, codec),
 		sqlclient.RegisterURLParser(parser{}),
 	)
+	sqlclient.Register(
+		CockroachDriverName,
+		sqlclient.OpenerFunc(crdbOpener),
+		sqlclient.RegisterDriverOpener(OpenCockroach),
+		sqlclient.RegisterFlavours("cockroachdb", "crdb"),
+		sqlclient.RegisterCodec(codec, codec),
+		sqlclient.RegisterURLParser(crdbParser{}),
+	)
+	sqlerr.Register(DriverName, classifyError)
+	schema.RegisterJSONTypes(
+		DriverName,
+		(*IndexHashSharded)(nil), (*Locality)(nil), (*RowTTL)(nil), (*EventTrigger)(nil),
+		(*ForeignServer)(nil), (*ForeignTable)(nil), (*UserDefinedType)(nil), (*RowType)(nil),
+		(*PseudoType)(nil), (*OID)(nil), (*ArrayType)(nil), (*VectorType)(nil), (*BitType)(nil),
+		(*DomainType)(nil), (*CompositeType)(nil), (*IntervalType)(nil), (*NetworkType)(nil),
+		(*CurrencyType)(nil), (*RangeType)(nil), (*SerialType)(nil), (*TextSearchType)(nil),
+		(*OIDType)(nil), (*XMLType)(nil), (*Constraint)(nil), (*Deferrable)(nil), (*Operator)(nil),
+		(*Sequence)(nil), (*Identity)(nil), (*IndexType)(nil), (*IndexPredicate)(nil),
+		(*IndexColumnProperty)(nil), (*IndexStorageParams)(nil), (*IndexInclude)(nil),
+		(*IndexOpClass)(nil), (*IndexNullsDistinct)(nil), (*NoInherit)(nil), (*CheckColumns)(nil),
+		(*Partition)(nil), (*MaterializedView)(nil), (*PartitionPolicy)(nil), (*Policy)(nil),
+		(*RowSecurity)(nil), (*Publication)(nil), (*CustomRangeType)(nil), (*CustomMultirangeType)(nil),
+		(*Role)(nil), (*Owner)(nil), (*DefaultPrivilege)(nil), (*TableStats)(nil), (*IndexStats)(nil),
+		(*Colocation)(nil), (*TabletSplit)(nil),
+		// Clauses.
+		(*ConvertUsing)(nil), (*Concurrently)(nil), (*NotValid)(nil), (*Cascade)(nil),
+	)
 }
 
 func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
-	ur := parser{}.ParseURL(u)
+	return openClient(DriverName, parser{}.ParseURL(u), Open)
+}
+
+func crdbOpener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+	return openClient(CockroachDriverName, crdbParser{}.ParseURL(u), OpenCockroach)
+}
+
+// openClient opens a client for the given URL using the
+// PostgreSQL wire protocol, and the given driver opener.
+func openClient(name string, ur *sqlclient.URL, open func(schema.ExecQuerier) (migrate.Driver, error)) (*sqlclient.Client, error) {
 	db, err := sql.Open(DriverName, ur.DSN)
 	if err != nil {
 		return nil, err
 	}
-	drv, err := Open(db)
+	drv, err := open(db)
 	if err != nil {
 		if cerr := db.Close(); cerr != nil {
 			err = fmt.Errorf("%w: %v", err, cerr)
@@ -85,7 +140,7 @@ func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
 		drv.noLocker.(*Driver).schema = ur.Schema
 	}
 	return &sqlclient.Client{
-		Name:   DriverName,
+		Name:   name,
 		DB:     db,
 		URL:    ur,
 		Driver: drv,
@@ -94,13 +149,21 @@ func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
 
 // Open opens a new PostgreSQL driver.
 func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	return OpenWith(db)
+}
+
+// OpenWith opens a new PostgreSQL driver with the given options.
+func OpenWith(db schema.ExecQuerier, opts ...Option) (migrate.Driver, error) {
 	c := &conn{ExecQuerier: db}
+	for _, opt := range opts {
+		opt(c)
+	}
 	rows, err := db.QueryContext(context.Background(), paramsQuery)
 	if err != nil {
 		return nil, fmt.Errorf("postgres: scanning system variables: %w", err)
 	}
-	var ver, am, crdb sql.NullString
-	if err := sqlx.ScanOne(rows, &ver, &am, &crdb); err != nil {
+	var ver, am, crdb, desc sql.NullString
+	if err := sqlx.ScanOne(rows, &ver, &am, &crdb, &desc); err != nil {
 		return nil, fmt.Errorf("postgres: scanning system variables: %w", err)
 	}
 	if c.version, err = strconv.Atoi(ver.String); err != nil {
@@ -110,7 +173,9 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 		return nil, fmt.Errorf("postgres: unsupported postgres version: %d", c.version)
 	}
 	c.accessMethod = am.String
+	c.ybVersion, c.yb = ybVersion(desc.String)
 	if c.crdb = sqlx.ValidString(crdb); c.crdb {
+		c.crdbVersion = crdbVersion(crdb.String)
 		return noLockDriver{
 			&Driver{
 				conn:        c,
@@ -128,6 +193,19 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	}, nil
 }
 
+// OpenCockroach opens a new CockroachDB driver. Unlike Open, which detects the
+// flavor of the connected server, an error is returned if it is not CockroachDB.
+func OpenCockroach(db schema.ExecQuerier) (migrate.Driver, error) {
+	drv, err := Open(db)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := drv.(noLockDriver); !ok {
+		return nil, errors.New("cockroach: connected server is not CockroachDB")
+	}
+	return drv, nil
+}
+
 func (d *Driver) dev() *sqlx.DevDriver {
 	return &sqlx.DevDriver{
 		Driver: d,
@@ -333,6 +411,24 @@ func (d *Driver) Version() string {
 	return strconv.Itoa(d.conn.version)
 }
 
+// Capabilities implements migrate.CapabilityReporter. Note, CockroachDB and YugabyteDB
+// accept DDL statements in transactions, but they are not executed atomically.
+func (d *Driver) Capabilities() *migrate.Capabilities {
+	c := &migrate.Capabilities{
+		TransactionalDDL: !d.crdb && !d.yb,
+		Check:            true,
+		IndexExpr:        true,
+		IndexPredicate:   true,
+		ForeignKey:       true,
+		RenameColumn:     true,
+	}
+	// CockroachDB does not limit the length of identifiers.
+	if !d.crdb {
+		c.MaxIdentLen = 63
+	}
+	return c
+}
+
 // FormatType converts schema type to its column form in the database.
 func (*Driver) FormatType(t schema.Type) (string, error) {
 	return FormatType(t)
@@ -405,12 +501,26 @@ func (c *conn) supportsIndexInclude() bool {
 	return c.version >= 11_00_00
 }
 
+// supportsPublishTruncate reports if the server supports publishing TRUNCATE operations.
+func (c *conn) supportsPublishTruncate() bool {
+	return c.version >= 11_00_00
+}
+
+// supportsSequences reports if the server exposes the sequence options in the pg_sequence
+// catalog, which was added in PostgreSQL 10.
+func (c *conn) supportsSequences() bool {
+	return c.version >= 10_00_00
+}
+
 // supportsIndexNullsDistinct reports if the server supports the NULLS [NOT] DISTINCT clause.
 func (c *conn) supportsIndexNullsDistinct() bool {
 	return c.version >= 15_00_00
 }
 
-type parser struct{}
+type (
+	parser     struct{}
+	crdbParser struct{ parser }
+)
 
 // ParseURL implements the sqlclient.URLParser interface.
 func (parser) ParseURL(u *url.URL) *sqlclient.URL {
@@ -426,6 +536,16 @@ func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
 	return &nu
 }
 
+// ParseURL implements the sqlclient.URLParser interface. CockroachDB URLs
+// are connected using the PostgreSQL wire protocol. For example:
+//
+//	cockroach://root@localhost:26257/defaultdb?sslmode=disable
+func (crdbParser) ParseURL(u *url.URL) *sqlclient.URL {
+	u1 := *u
+	u1.Scheme = DriverName
+	return &sqlclient.URL{URL: u, DSN: u1.String(), Schema: u.Query().Get("search_path")}
+}
+
 // Standard column types (and their aliases) as defined in
 // PostgreSQL codebase/website.
 const (
@@ -517,6 +637,16 @@ const (
 	TypeDateRange      = "daterange"
 	TypeDateMultiRange = "datemultirange"
 
+	// Well-known types that are defined by extensions.
+	TypeGeography = "geography" // postgis.
+	TypeCIText    = "citext"
+	TypeHStore    = "hstore"
+	TypeLTree     = "ltree"
+	TypeCube      = "cube"
+	TypeVector    = "vector" // pgvector.
+	TypeHalfVec   = "halfvec"
+	TypeSparseVec = "sparsevec"
+
 	// PostgreSQL internal object types and their aliases.
 	typeOID           = "oid"
 	typeRegClass      = "regclass"
@@ -553,9 +683,16 @@ const (
 	IndexTypeGIN         = "GIN"
 	IndexTypeGiST        = "GIST"
 	IndexTypeSPGiST      = "SPGIST"
+	IndexTypeIVFFlat     = "IVFFLAT" // pgvector.
+	IndexTypeHNSW        = "HNSW"    // pgvector.
 	defaultPagesPerRange = 128
 	defaultListLimit     = 4 * 1024
 	defaultBtreeFill     = 90
+	defaultHashFill      = 75
+	defaultSPGiSTFill    = 80
+	defaultIVFFlatLists  = 100
+	defaultHNSWM         = 16
+	defaultHNSWEf        = 64
 )
 
 const (
@@ -566,6 +703,9 @@ const (
 	storageParamListLimit  = "gin_pending_list_limit"
 	storageParamPagesRange = "pages_per_range"
 	storageParamAutoSum    = "autosummarize"
+	storageParamLists      = "lists"
+	storageParamM          = "m"
+	storageParamEf         = "ef_construction"
 )
 
 const (
@@ -582,6 +722,12 @@ const (
 	GeneratedTypeByDefault = "BY_DEFAULT" // BY DEFAULT.
 )
 
+// List of DEFERRABLE modes of constraints.
+const (
+	DeferrableInitiallyImmediate = "INITIALLY_IMMEDIATE"
+	DeferrableInitiallyDeferred  = "INITIALLY_DEFERRED"
+)
+
 // List of PARTITION KEY types.
 const (
 	PartitionTypeRange = "RANGE"
@@ -595,34 +741,68 @@ var (
 		Table: tableSpec,
 	}
 	scanFuncs = &specutil.ScanFuncs{
-		Table: convertTable,
+		Table:      convertTable,
+		ForeignKey: convertFK,
 	}
 )
 
-func tableAttrsSpec(*schema.Table, *sqlspec.Table) {
-	// unimplemented.
+func tableAttrsSpec(t *schema.Table, spec *sqlspec.Table) {
+	rowSecuritySpec(t, spec)
+	crdbTableAttrsSpec(t, spec)
+	ybTableAttrsSpec(t, spec)
+	if a, ok := ownerSpec(t.Attrs); ok {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, a)
+	}
 }
 
-func convertTableAttrs(*sqlspec.Table, *schema.Table) error {
-	return nil // unimplemented.
+func convertTableAttrs(spec *sqlspec.Table, t *schema.Table) error {
+	if err := convertOwner(spec, &t.Attrs); err != nil {
+		return err
+	}
+	if err := convertCRDBTableAttrs(spec, t); err != nil {
+		return err
+	}
+	if err := convertYBTableAttrs(spec, t); err != nil {
+		return err
+	}
+	return convertRowSecurity(spec, t)
 }
 
 // tableAttrDiff allows extending table attributes diffing with build-specific logic.
-func (*diff) tableAttrDiff(_, _ *schema.Table) ([]schema.Change, error) {
-	return nil, nil // unimplemented.
+func (*diff) tableAttrDiff(from, to *schema.Table) ([]schema.Change, error) {
+	changes := append(rlsDiff(from, to), ownerDiff(from.Attrs, to.Attrs)...)
+	return append(changes, crdbTableAttrDiff(from, to)...), nil
 }
 
 // addTableAttrs allows extending table attributes creation with build-specific logic.
-func (*state) addTableAttrs(_ *schema.AddTable) {
-	// unimplemented.
+func (s *state) addTableAttrs(add *schema.AddTable) {
+	s.addTableOwner(add)
+	s.addRowSecurity(add)
+	s.addDefaultPartition(add)
 }
 
 // alterTableAttr allows extending table attributes alteration with build-specific logic.
-func (s *state) alterTableAttr(*sqlx.Builder, *schema.ModifyAttr) {
-	// unimplemented.
+func (s *state) alterTableAttr(b *sqlx.Builder, change *schema.ModifyAttr) {
+	switch from := change.From.(type) {
+	case *RowSecurity:
+		if to, ok := change.To.(*RowSecurity); ok {
+			rlsChange(b, from, to)
+		}
+	case *Owner:
+		if to, ok := change.To.(*Owner); ok {
+			b.P("OWNER TO").Ident(to.Name)
+		}
+	case *Locality:
+		if to, ok := change.To.(*Locality); ok {
+			b.P("SET LOCALITY", to.String())
+		}
+	case *RowTTL:
+		if to, ok := change.To.(*RowTTL); ok {
+			rowTTLChange(b, from, to)
+		}
+	}
 }
 
-
 func (s *state) addObject(add *schema.AddObject) error {
 	switch o := add.O.(type) {
 	case *schema.EnumType:
@@ -633,6 +813,27 @@ func (s *state) addObject(add *schema.AddObject) error {
 			Reverse: drop,
 			Comment: fmt.Sprintf("create enum type %q", o.T),
 		})
+		if c := (schema.Comment{}); sqlx.Has(o.Attrs, &c) && c.Text != "" {
+			s.append(s.enumComment(add, o, c.Text, ""))
+		}
+	case *MaterializedView:
+		return s.addMatView(add, o)
+	case *Publication:
+		s.addPublication(add, o)
+	case *EventTrigger:
+		s.addEventTrigger(add, o)
+	case *Role:
+		s.addRole(add, o)
+	case *DefaultPrivilege:
+		s.addDefaultPrivilege(add, o)
+	case *Sequence:
+		s.addSequence(add, o)
+	case *ForeignServer:
+		s.addForeignServer(add, o)
+	case *ForeignTable:
+		return s.addForeignTable(add, o)
+	case *CustomRangeType:
+		return s.addRange(add, o)
 	default:
 		// unsupported object type.
 	}
@@ -649,6 +850,24 @@ func (s *state) dropObject(drop *schema.DropObject) error {
 			Reverse: create,
 			Comment: fmt.Sprintf("drop enum type %q", o.T),
 		})
+	case *MaterializedView:
+		s.dropMatView(drop, o)
+	case *Publication:
+		s.dropPublication(drop, o)
+	case *EventTrigger:
+		s.dropEventTrigger(drop, o)
+	case *Role:
+		s.dropRole(drop, o)
+	case *DefaultPrivilege:
+		s.dropDefaultPrivilege(drop, o)
+	case *Sequence:
+		s.dropSequence(drop, o)
+	case *ForeignServer:
+		s.dropForeignServer(drop, o)
+	case *ForeignTable:
+		return s.dropForeignTable(drop, o)
+	case *CustomRangeType:
+		return s.dropRange(drop, o)
 	default:
 		// unsupported object type.
 	}
@@ -656,8 +875,63 @@ func (s *state) dropObject(drop *schema.DropObject) error {
 }
 
 func (s *state) modifyObject(modify *schema.ModifyObject) error {
-	if _, ok := modify.From.(*schema.EnumType); ok {
+	switch from := modify.From.(type) {
+	case *schema.EnumType:
 		return s.alterEnum(modify)
+	case *MaterializedView:
+		to, ok := modify.To.(*MaterializedView)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched materialized view change: %T", modify.To)
+		}
+		return s.modifyMatView(modify, from, to)
+	case *Publication:
+		to, ok := modify.To.(*Publication)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched publication change: %T", modify.To)
+		}
+		s.modifyPublication(modify, from, to)
+	case *EventTrigger:
+		to, ok := modify.To.(*EventTrigger)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched event trigger change: %T", modify.To)
+		}
+		s.modifyEventTrigger(modify, from, to)
+	case *Role:
+		to, ok := modify.To.(*Role)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched role change: %T", modify.To)
+		}
+		s.modifyRole(modify, from, to)
+	case *DefaultPrivilege:
+		to, ok := modify.To.(*DefaultPrivilege)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched default privileges change: %T", modify.To)
+		}
+		s.modifyDefaultPrivilege(modify, from, to)
+	case *Sequence:
+		to, ok := modify.To.(*Sequence)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched sequence change: %T", modify.To)
+		}
+		s.modifySequence(modify, from, to)
+	case *ForeignServer:
+		to, ok := modify.To.(*ForeignServer)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched foreign server change: %T", modify.To)
+		}
+		s.modifyForeignServer(modify, from, to)
+	case *ForeignTable:
+		to, ok := modify.To.(*ForeignTable)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched foreign table change: %T", modify.To)
+		}
+		return s.modifyForeignTable(modify, from, to)
+	case *CustomRangeType:
+		to, ok := modify.To.(*CustomRangeType)
+		if !ok {
+			return fmt.Errorf("postgres: mismatched range type change: %T", modify.To)
+		}
+		return s.modifyRange(modify, from, to)
 	}
 	return nil // unimplemented.
 }
@@ -665,13 +939,17 @@ func (s *state) modifyObject(modify *schema.ModifyObject) error {
 
 // RealmObjectDiff returns a changeset for migrating realm (database) objects
 // from one state to the other. For example, adding extensions or users.
-func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
-	return nil, nil // unimplemented.
+func (*diff) RealmObjectDiff(from, to *schema.Realm) ([]schema.Change, error) {
+	changes := roleDiff(from, to)
+	changes = append(changes, defaultPrivilegeDiff(from, to)...)
+	changes = append(changes, publicationDiff(from, to)...)
+	changes = append(changes, foreignServerDiff(from, to)...)
+	return append(changes, eventTriggerDiff(from, to)...), nil
 }
 
 // SchemaObjectDiff returns a changeset for migrating schema objects from
 // one state to the other.
-func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+func (d *diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
 	var changes []schema.Change
 	// Drop or modify enums.
 	for _, o1 := range from.Objects {
@@ -687,7 +965,7 @@ func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([
 			changes = append(changes, &schema.DropObject{O: o1})
 			continue
 		}
-		if e2 := o2.(*schema.EnumType); !sqlx.ValuesEqual(e1.Values, e2.Values) {
+		if e2 := o2.(*schema.EnumType); !sqlx.ValuesEqual(e1.Values, e2.Values) || sqlx.CommentChange(e1.Attrs, e2.Attrs) != schema.NoChange {
 			changes = append(changes, &schema.ModifyObject{From: e1, To: e2})
 		}
 	}
@@ -704,7 +982,22 @@ func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([
 			changes = append(changes, &schema.AddObject{O: e1})
 		}
 	}
-	return changes, nil
+	ranges, err := rangeObjectDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, ranges...)
+	changes = append(changes, sequenceObjectDiff(from, to)...)
+	views, err := d.matViewObjectDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, views...)
+	foreign, err := d.foreignTableObjectDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, foreign...), nil
 }
 
 func convertDomains(_ []*sqlspec.Table, domains []*domain, _ *schema.Realm) error {
@@ -721,18 +1014,8 @@ func convertAggregate(d *doc, _ *schema.Realm) error {
 	return nil
 }
 
-func convertSequences(_ []*sqlspec.Table, seqs []*sqlspec.Sequence, _ *schema.Realm) error {
-	if len(seqs) > 0 {
-		return fmt.Errorf("postgres: sequences are not supported by this version. Use: https://atlasgo.io/getting-started")
-	}
-	return nil
-}
-
-func convertPolicies(_ []*sqlspec.Table, ps []*policy, _ *schema.Realm) error {
-	if len(ps) > 0 {
-		return fmt.Errorf("postgres: policies are not supported by this version. Use: https://atlasgo.io/getting-started")
-	}
-	return nil
+func convertPolicies(_ []*sqlspec.Table, ps []*policy, r *schema.Realm) error {
+	return convertPolicy(ps, r)
 }
 
 func convertExtensions(exs []*extension, _ *schema.Realm) error {
@@ -742,28 +1025,54 @@ func convertExtensions(exs []*extension, _ *schema.Realm) error {
 	return nil
 }
 
-func convertEventTriggers(evs []*eventTrigger, _ *schema.Realm) error {
-	if len(evs) > 0 {
-		return fmt.Errorf("postgres: event triggers are not supported by this version. Use: https://atlasgo.io/getting-started")
-	}
-	return nil
-}
-
 func normalizeRealm(*schema.Realm) error {
 	return nil
 }
 
 // objectSpec converts from a concrete schema objects into specs.
 func objectSpec(d *doc, spec *specutil.SchemaSpec, s *schema.Schema) error {
+	d.Policies = append(d.Policies, policySpecs(s.Tables)...)
 	for _, o := range s.Objects {
-		if e, ok := o.(*schema.EnumType); ok {
-			d.Enums = append(d.Enums, &enum{
-				Name:   e.T,
-				Values: e.Values,
+		switch o := o.(type) {
+		case *schema.EnumType:
+			e := &enum{
+				Name:   o.T,
+				Values: o.Values,
 				Schema: specutil.SchemaRef(spec.Schema.Name),
-			})
+			}
+			if c := (schema.Comment{}); sqlx.Has(o.Attrs, &c) && c.Text != "" {
+				e.Extra.Attrs = append(e.Extra.Attrs, schemahcl.StringAttr("comment", c.Text))
+			}
+			d.Enums = append(d.Enums, e)
+		case *MaterializedView:
+			v, err := matViewSpec(o)
+			if err != nil {
+				return err
+			}
+			d.Materialized = append(d.Materialized, v)
+		case *Sequence:
+			seq, err := sequenceSpec(o)
+			if err != nil {
+				return err
+			}
+			d.Sequences = append(d.Sequences, seq)
+		case *ForeignTable:
+			t, err := foreignTableSpec(o)
+			if err != nil {
+				return err
+			}
+			d.ForeignTables = append(d.ForeignTables, t)
+		case *CustomRangeType:
+			rt, err := rangeSpec(o)
+			if err != nil {
+				return err
+			}
+			d.Ranges = append(d.Ranges, rt)
 		}
 	}
+	for _, f := range s.Funcs {
+		d.Funcs = append(d.Funcs, triggerFuncSpec(f))
+	}
 	return nil
 }
 
@@ -787,6 +1096,13 @@ func convertTypes(d *doc, r *schema.Realm) error {
 			return fmt.Errorf("schema %q defined on enum %q was not found in realm", ns, e.Name)
 		}
 		e1 := &schema.EnumType{T: e.Name, Schema: es, Values: e.Values}
+		if a, ok := e.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing enum %q attribute \"comment\": %w", e.Name, err)
+			}
+			e1.Attrs = append(e1.Attrs, &schema.Comment{Text: c})
+		}
 		es.AddObjects(e1)
 		byName[e.Name] = e1
 	}
@@ -849,14 +1165,41 @@ func uniqueConstChanged(_, _ []schema.Attr) bool {
 	return false
 }
 
-func excludeConstChanged(_, _ []schema.Attr) bool {
-	// Unsupported change in package mode (ariga.io/sql/postgres)
-	// to keep BC with old versions.
-	return false
+// excludeConstChanged reports if an index was changed from or to an EXCLUDE constraint.
+func excludeConstChanged(from, to []schema.Attr) bool {
+	_, ok1 := excludeConst(from)
+	_, ok2 := excludeConst(to)
+	return ok1 != ok2
 }
 
-func convertExclude(schemahcl.Resource, *schema.Table) error {
-	return nil // unimplemented.
+// convertExclude converts the exclude constraints into indexes.
+func convertExclude(spec schemahcl.Resource, t *schema.Table) error {
+	for _, r := range spec.Resources("exclude") {
+		var sx sqlspec.Index
+		if err := r.As(&sx); err != nil {
+			return fmt.Errorf("parse %s.exclude constraint: %w", t.Name, err)
+		}
+		if len(sx.Columns) > 0 {
+			return fmt.Errorf(`%s.exclude constraint %q must define its parts and operators using "on" blocks`, t.Name, sx.Name)
+		}
+		idx, err := convertIndex(&sx, t)
+		if err != nil {
+			return err
+		}
+		for i, p := range sx.Parts {
+			a, ok := p.Attr("op")
+			if !ok {
+				return fmt.Errorf("missing operator for %s.exclude constraint %q at position %d", t.Name, sx.Name, i)
+			}
+			op, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parse %s.exclude constraint %q operator: %w", t.Name, sx.Name, err)
+			}
+			idx.Parts[i].AddAttrs(&Operator{Name: op})
+		}
+		t.AddIndexes(idx.AddAttrs(ExcludeConstraint(sx.Name)))
+	}
+	return nil
 }
 
 func (*state) sortChanges(changes []schema.Change) []schema.Change {
@@ -867,13 +1210,22 @@ func (*state) detachCycles(changes []schema.Change) ([]schema.Change, error) {
 	return sqlx.DetachCycles(changes)
 }
 
-func excludeSpec(*sqlspec.Table, *sqlspec.Index, *schema.Index, *Constraint) error {
-	return nil // unimplemented.
+// excludeSpec appends the exclude constraint spec to the table spec.
+func excludeSpec(spec *sqlspec.Table, idx *sqlspec.Index, _ *schema.Index, c *Constraint) error {
+	r := &schemahcl.Resource{}
+	if err := r.Scan(idx); err != nil {
+		return err
+	}
+	r.Type = "exclude"
+	if c.N != "" {
+		r.Name = c.N
+	}
+	spec.Extra.Children = append(spec.Extra.Children, r)
+	return nil
 }
 
 const (
 	// Query to list tables information.
-	// Note, 'attrs' are not supported in this version.
 	tablesQuery = `
 SELECT
 	t3.oid,
@@ -883,7 +1235,7 @@ SELECT
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
 	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
-	'{}' AS attrs
+	json_build_object('rls', t3.relrowsecurity, 'force_rls', t3.relforcerowsecurity, 'policies', (SELECT count(*) FROM pg_catalog.pg_policy AS p WHERE p.polrelid = t3.oid)) AS attrs
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
@@ -899,7 +1251,6 @@ ORDER BY
 	t1.table_schema, t1.table_name
 `
 	// Query to list tables by their names.
-	// Note, 'attrs' are not supported in this version.
 	tablesQueryArgs = `
 SELECT
 	t3.oid,
@@ -909,7 +1260,7 @@ SELECT
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
 	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
-	'{}' AS attrs
+	json_build_object('rls', t3.relrowsecurity, 'force_rls', t3.relforcerowsecurity, 'policies', (SELECT count(*) FROM pg_catalog.pg_policy AS p WHERE p.polrelid = t3.oid)) AS attrs
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
@@ -167,6 +167,31 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "130000", drv.(vr).Version())
 }
 
+func TestDriver_Capabilities(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	require.Implements(t, (*migrate.CapabilityReporter)(nil), drv)
+	require.Equal(t, &migrate.Capabilities{
+		TransactionalDDL: true,
+		Check:            true,
+		IndexExpr:        true,
+		IndexPredicate:   true,
+		ForeignKey:       true,
+		RenameColumn:     true,
+		MaxIdentLen:      63,
+	}, drv.(migrate.CapabilityReporter).Capabilities())
+
+	c := (&Driver{conn: &conn{crdb: true}}).Capabilities()
+	require.False(t, c.TransactionalDDL)
+	require.Zero(t, c.MaxIdentLen)
+	c = (&Driver{conn: &conn{yb: true}}).Capabilities()
+	require.False(t, c.TransactionalDDL)
+	require.Equal(t, 63, c.MaxIdentLen)
+}
+
 func TestDriver_RealmRestoreFunc(t *testing.T) {
 	var (
 		apply   = &mockPlanApplier{}
@@ -254,3 +279,25 @@ func (m *mockPlanApplier) ApplyChanges(_ context.Context, applied []schema.Chang
 	m.applied = append(m.applied, applied...)
 	return nil
 }
+
+func TestDriver_JSON(t *testing.T) {
+	seq := &Sequence{Name: "users_id_seq", Start: 1, Increment: 1}
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "bigint").AddAttrs(&Identity{Generation: "ALWAYS", Sequence: seq}),
+			schema.NewColumn("tags").SetType(&ArrayType{Type: &schema.StringType{T: "text"}, T: "text[]"}),
+		).
+		AddIndexes(schema.NewIndex("tags_idx").AddColumns(schema.NewColumn("tags")).AddAttrs(&IndexType{T: "GIN"}))
+	users.Indexes[0].Parts[0].C = users.Columns[1]
+	users.AddAttrs(&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: users.Columns[0]}}})
+	r := schema.NewRealm(schema.New("public").AddTables(users))
+	b, err := schema.MarshalJSON(r)
+	require.NoError(t, err)
+	var got schema.Realm
+	require.NoError(t, schema.UnmarshalJSON(b, &got))
+	require.True(t, schema.Equal(r, &got))
+	gu := got.Schemas[0].Tables[0]
+	require.Equal(t, "ALWAYS", gu.Columns[0].Attrs[0].(*Identity).Generation)
+	require.Same(t, gu.Columns[0], gu.Attrs[0].(*Partition).Parts[0].C)
+	require.Same(t, gu.Columns[1], gu.Indexes[0].Parts[0].C)
+}
@@ -0,0 +1,408 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// EventTrigger defines an event trigger. Event triggers are realm-level
+// objects that execute a function when DDL events occur in the database.
+// https://www.postgresql.org/docs/current/sql-createeventtrigger.html
+type EventTrigger struct {
+	schema.Object
+	Name  string
+	Event string   // The event that fires the trigger, e.g. ddl_command_start.
+	Tags  []string // Command tags to filter on, e.g. CREATE TABLE. An empty list means all tags.
+	// Func is the (optionally schema-qualified) name of the trigger
+	// function, e.g. "audit.log_ddl". The function is either managed
+	// as a schema.Func of the realm, or expected to exist before the trigger.
+	Func string
+	// Enabled holds the firing mode of the trigger, as set by the
+	// ALTER EVENT TRIGGER command. An empty string means ORIGIN.
+	Enabled string
+	Attrs   []schema.Attr
+}
+
+// List of events that fire event triggers.
+const (
+	EventDDLCommandStart = "ddl_command_start"
+	EventDDLCommandEnd   = "ddl_command_end"
+	EventSQLDrop         = "sql_drop"
+	EventTableRewrite    = "table_rewrite"
+	EventLogin           = "login" // PostgreSQL 17 and above.
+)
+
+// List of event trigger firing modes.
+const (
+	EventTriggerOrigin   = "ORIGIN" // Default.
+	EventTriggerReplica  = "REPLICA"
+	EventTriggerAlways   = "ALWAYS"
+	EventTriggerDisabled = "DISABLED"
+)
+
+// SpecType returns the type of the event trigger.
+func (*EventTrigger) SpecType() string { return "event_trigger" }
+
+// SpecName returns the name of the event trigger.
+func (e *EventTrigger) SpecName() string { return e.Name }
+
+// SetComment sets or updates the comment of the event trigger.
+func (e *EventTrigger) SetComment(c string) *EventTrigger {
+	schema.ReplaceOrAppend(&e.Attrs, &schema.Comment{Text: c})
+	return e
+}
+
+// inspectEventTriggers queries and appends the event triggers of the realm.
+// Event triggers that are members of extensions are managed by their extension
+// and therefore are not inspected.
+func (i *inspect) inspectEventTriggers(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, eventTriggersQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying event triggers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name, event, ns, fn, enabled string
+			tags, comment                sql.NullString
+		)
+		if err := rows.Scan(&name, &event, &ns, &fn, &enabled, &tags, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning event trigger: %w", err)
+		}
+		e := &EventTrigger{
+			Name:    name,
+			Event:   event,
+			Func:    ns + "." + fn,
+			Enabled: eventTriggerEnabled(enabled),
+		}
+		if sqlx.ValidString(tags) {
+			if err := json.Unmarshal([]byte(tags.String), &e.Tags); err != nil {
+				return fmt.Errorf("postgres: parsing tags of event trigger %q: %w", name, err)
+			}
+		}
+		if sqlx.ValidString(comment) {
+			e.SetComment(comment.String)
+		}
+		r.AddObjects(e)
+	}
+	return rows.Err()
+}
+
+// eventTriggerEnabled maps the pg_event_trigger.evtenabled value to its firing mode.
+func eventTriggerEnabled(c string) string {
+	switch c {
+	case "R":
+		return EventTriggerReplica
+	case "A":
+		return EventTriggerAlways
+	case "D":
+		return EventTriggerDisabled
+	default:
+		return ""
+	}
+}
+
+// eventTriggerDiff returns the changes for migrating the event triggers of the realm.
+func eventTriggerDiff(from, to *schema.Realm) []schema.Change {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		e1, ok := o1.(*EventTrigger)
+		if !ok {
+			continue
+		}
+		e2, ok := realmEventTrigger(to, e1.Name)
+		switch {
+		case !ok:
+			changes = append(changes, &schema.DropObject{O: e1})
+		// The trigger is dropped before its function is dropped,
+		// and created after the function is created again.
+		case eventTriggerFuncRecreated(from, to, e1, e2):
+			changes = append(changes, &schema.DropObject{O: e1}, &schema.AddObject{O: e2})
+		case eventTriggerRecreate(e1, e2) || eventTriggerMode(e1) != eventTriggerMode(e2) || eventTriggerComment(e1) != eventTriggerComment(e2):
+			changes = append(changes, &schema.ModifyObject{From: e1, To: e2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if e2, ok := o2.(*EventTrigger); ok {
+			if _, ok := realmEventTrigger(from, e2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: e2})
+			}
+		}
+	}
+	return changes
+}
+
+// realmEventTrigger returns the event trigger with the given name from the realm.
+func realmEventTrigger(r *schema.Realm, name string) (*EventTrigger, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		e, ok := o.(*EventTrigger)
+		return ok && e.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*EventTrigger), true
+}
+
+// eventTriggerRecreate reports if the event trigger definition was changed.
+// PostgreSQL does not support altering the event, tags or function of an
+// event trigger, and therefore, such changes require recreating it.
+func eventTriggerRecreate(from, to *EventTrigger) bool {
+	if !strings.EqualFold(from.Event, to.Event) || !eventTriggerFuncEqual(from.Func, to.Func) {
+		return true
+	}
+	return !slices.Equal(eventTriggerTags(from), eventTriggerTags(to))
+}
+
+// eventTriggerFuncEqual reports if the two function names are equal. Unqualified
+// names are resolved using the search_path, and hence, are compared by name only.
+func eventTriggerFuncEqual(f1, f2 string) bool {
+	ns1, n1 := eventTriggerFunc(f1)
+	ns2, n2 := eventTriggerFunc(f2)
+	return n1 == n2 && (ns1 == "" || ns2 == "" || ns1 == ns2)
+}
+
+// eventTriggerFunc splits the function name into its schema and name parts.
+func eventTriggerFunc(f string) (string, string) {
+	f = strings.TrimSuffix(f, "()")
+	if ns, name, ok := strings.Cut(f, "."); ok {
+		return ns, name
+	}
+	return "", f
+}
+
+// eventTriggerTags returns the filter tags in their canonical form.
+func eventTriggerTags(e *EventTrigger) []string {
+	tags := make([]string, len(e.Tags))
+	for i, t := range e.Tags {
+		tags[i] = strings.ToUpper(strings.Join(strings.Fields(t), " "))
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// eventTriggerMode returns the firing mode of the event trigger.
+func eventTriggerMode(e *EventTrigger) string {
+	if e.Enabled == "" {
+		return EventTriggerOrigin
+	}
+	return strings.ToUpper(e.Enabled)
+}
+
+func eventTriggerComment(e *EventTrigger) string {
+	var c schema.Comment
+	sqlx.Has(e.Attrs, &c)
+	return c.Text
+}
+
+// addEventTrigger plans the creation of an event trigger.
+func (s *state) addEventTrigger(src schema.Change, e *EventTrigger) {
+	create, drop := s.createDropEventTrigger(e)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create event trigger %q", e.Name),
+	})
+	if m := eventTriggerMode(e); m != EventTriggerOrigin {
+		s.append(s.eventTriggerMode(src, e, m, EventTriggerOrigin))
+	}
+	if c := eventTriggerComment(e); c != "" {
+		s.append(s.eventTriggerComment(src, e, c, ""))
+	}
+}
+
+// dropEventTrigger plans the removal of an event trigger.
+func (s *state) dropEventTrigger(src schema.Change, e *EventTrigger) {
+	create, drop := s.createDropEventTrigger(e)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop event trigger %q", e.Name),
+	})
+}
+
+// modifyEventTrigger plans the changes of an event trigger.
+func (s *state) modifyEventTrigger(src schema.Change, from, to *EventTrigger) {
+	if eventTriggerRecreate(from, to) {
+		s.dropEventTrigger(src, from)
+		s.addEventTrigger(src, to)
+		return
+	}
+	if m1, m2 := eventTriggerMode(from), eventTriggerMode(to); m1 != m2 {
+		s.append(s.eventTriggerMode(src, to, m2, m1))
+	}
+	if c1, c2 := eventTriggerComment(from), eventTriggerComment(to); c1 != c2 {
+		s.append(s.eventTriggerComment(src, to, c2, c1))
+	}
+}
+
+func (s *state) createDropEventTrigger(e *EventTrigger) (string, string) {
+	b := s.Build("CREATE EVENT TRIGGER").Ident(e.Name).P("ON", strings.ToLower(e.Event))
+	if tags := eventTriggerTags(e); len(tags) > 0 {
+		b.P("WHEN TAG IN").Wrap(func(b *sqlx.Builder) {
+			b.MapComma(tags, func(i int, b *sqlx.Builder) {
+				b.P(quote(tags[i]))
+			})
+		})
+	}
+	// EXECUTE FUNCTION was added in PostgreSQL 11, and is
+	// preferred over the deprecated EXECUTE PROCEDURE syntax.
+	b.P("EXECUTE")
+	if s.conn.version >= 11_00_00 {
+		b.P("FUNCTION")
+	} else {
+		b.P("PROCEDURE")
+	}
+	ns, name := eventTriggerFunc(e.Func)
+	if ns != "" {
+		b.P(fmt.Sprintf("%q.%q()", ns, name))
+	} else {
+		b.P(strconv.Quote(name) + "()")
+	}
+	return b.String(), s.Build("DROP EVENT TRIGGER").Ident(e.Name).String()
+}
+
+func (s *state) eventTriggerMode(src schema.Change, e *EventTrigger, to, from string) *migrate.Change {
+	mode := func(m string) string {
+		b := s.Build("ALTER EVENT TRIGGER").Ident(e.Name)
+		switch m {
+		case EventTriggerDisabled:
+			b.P("DISABLE")
+		case EventTriggerOrigin:
+			b.P("ENABLE")
+		default:
+			b.P("ENABLE", m)
+		}
+		return b.String()
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     mode(to),
+		Reverse: mode(from),
+		Comment: fmt.Sprintf("set firing mode of event trigger %q", e.Name),
+	}
+}
+
+func (s *state) eventTriggerComment(src schema.Change, e *EventTrigger, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON EVENT TRIGGER").Ident(e.Name).P("IS")
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Reverse: b.Clone().P(quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to event trigger: %q", e.Name),
+	}
+}
+
+// convertEventTriggers converts the event trigger specs and adds them to the realm.
+func convertEventTriggers(specs []*eventTrigger, r *schema.Realm) error {
+	for _, spec := range specs {
+		e := &EventTrigger{Name: spec.Name}
+		for _, a := range []struct {
+			name string
+			v    *string
+		}{
+			{name: "on", v: &e.Event},
+			{name: "execute", v: &e.Func},
+			{name: "enabled", v: &e.Enabled},
+		} {
+			attr, ok := spec.Attr(a.name)
+			if !ok {
+				continue
+			}
+			v, err := attr.String()
+			if err != nil {
+				return fmt.Errorf("parsing event trigger %q attribute %q: %w", spec.Name, a.name, err)
+			}
+			*a.v = v
+		}
+		switch {
+		case e.Event == "":
+			return fmt.Errorf("missing attribute event_trigger.%s.on", spec.Name)
+		case e.Func == "":
+			return fmt.Errorf("missing attribute event_trigger.%s.execute", spec.Name)
+		}
+		if a, ok := spec.Attr("tags"); ok {
+			tags, err := a.Strings()
+			if err != nil {
+				return fmt.Errorf("parsing event trigger %q attribute \"tags\": %w", spec.Name, err)
+			}
+			e.Tags = tags
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing event trigger %q attribute \"comment\": %w", spec.Name, err)
+			}
+			e.SetComment(c)
+		}
+		r.AddObjects(e)
+	}
+	return nil
+}
+
+// eventTriggerSpecs returns the event trigger specs of the realm.
+func eventTriggerSpecs(r *schema.Realm) []*eventTrigger {
+	var specs []*eventTrigger
+	for _, o := range r.Objects {
+		e, ok := o.(*EventTrigger)
+		if !ok {
+			continue
+		}
+		spec := &eventTrigger{Name: e.Name}
+		spec.Extra.Attrs = append(spec.Extra.Attrs,
+			specutil.VarAttr("on", strings.ToLower(e.Event)),
+			schemahcl.StringAttr("execute", e.Func),
+		)
+		if len(e.Tags) > 0 {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringsAttr("tags", eventTriggerTags(e)...))
+		}
+		if m := eventTriggerMode(e); m != EventTriggerOrigin {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("enabled", m))
+		}
+		if c := eventTriggerComment(e); c != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// Query to list the event triggers of the database, excluding the ones created by extensions.
+const eventTriggersQuery = `
+SELECT
+  e.evtname,
+  e.evtevent,
+  n.nspname,
+  p.proname,
+  e.evtenabled,
+  array_to_json(e.evttags) AS tags,
+  obj_description(e.oid, 'pg_event_trigger') AS comment
+FROM
+  pg_catalog.pg_event_trigger AS e
+  JOIN pg_catalog.pg_proc AS p ON p.oid = e.evtfoid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+WHERE
+  NOT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_depend AS d
+    WHERE d.classid = 'pg_catalog.pg_event_trigger'::regclass AND d.objid = e.oid AND d.deptype = 'e'
+  )
+ORDER BY
+  e.evtname
+`
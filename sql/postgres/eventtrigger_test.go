@@ -0,0 +1,193 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectEventTriggers(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "sequence_name", "sequence_type", "seqstart", "seqincrement", "seqmin", "seqmax", "seqcache", "seqcycle", "owner_table", "owner_column", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "table_name", "server_name", "options", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(publicationsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"pubname", "puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate", "tables"}))
+	mk.ExpectQuery(sqltest.Escape(eventTriggersQuery)).
+		WillReturnRows(sqltest.Rows(`
+ evtname   |     evtevent      | nspname |   proname   | evtenabled |              tags               | comment
+-----------+-------------------+---------+-------------+------------+---------------------------------+---------
+ audit_ddl | ddl_command_end   | audit   | log_ddl     | O          | ["CREATE TABLE","ALTER TABLE"]  | audit
+ no_drops  | sql_drop          | public  | deny_drops  | D          | nil                             | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(foreignServersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"srvname", "fdwname", "srvtype", "srvversion", "options", "mappings", "comment"}))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectObjects,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	require.Equal(t, []schema.Object{
+		(&EventTrigger{Name: "audit_ddl", Event: EventDDLCommandEnd, Tags: []string{"CREATE TABLE", "ALTER TABLE"}, Func: "audit.log_ddl"}).SetComment("audit"),
+		&EventTrigger{Name: "no_drops", Event: EventSQLDrop, Func: "public.deny_drops", Enabled: EventTriggerDisabled},
+	}, r.Objects)
+}
+
+func TestDiff_EventTriggers(t *testing.T) {
+	var (
+		from = schema.NewRealm(schema.New("public"))
+		to   = schema.NewRealm(schema.New("public"))
+		e1   = &EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Tags: []string{"create table", "ALTER  TABLE"}, Func: "public.log_ddl"}
+		e2   = &EventTrigger{Name: "audit", Event: "DDL_COMMAND_END", Tags: []string{"ALTER TABLE", "CREATE TABLE"}, Func: "log_ddl", Enabled: EventTriggerOrigin}
+	)
+	from.AddObjects(e1)
+	to.AddObjects(e2)
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes, "event triggers are compared normalized")
+
+	e2.Enabled = EventTriggerAlways
+	changes, err = DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, schema.Changes{&schema.ModifyObject{From: e1, To: e2}}, schema.Changes(changes))
+
+	to.Objects = nil
+	changes, err = DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, schema.Changes{&schema.DropObject{O: e1}}, schema.Changes(changes))
+
+	changes, err = DefaultDiff.RealmDiff(to, from)
+	require.NoError(t, err)
+	require.Equal(t, schema.Changes{&schema.AddObject{O: e1}}, schema.Changes(changes))
+}
+
+func TestPlanChanges_EventTriggers(t *testing.T) {
+	var (
+		e1 = &EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Tags: []string{"CREATE TABLE"}, Func: "audit.log_ddl"}
+		e2 = (&EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Tags: []string{"CREATE TABLE"}, Func: "audit.log_ddl", Enabled: EventTriggerReplica}).SetComment("c")
+	)
+	tests := []struct {
+		version string
+		changes []schema.Change
+		want    [][2]string
+	}{
+		{
+			version: "130000",
+			changes: []schema.Change{&schema.AddObject{O: e1}},
+			want: [][2]string{
+				{`CREATE EVENT TRIGGER "audit" ON ddl_command_end WHEN TAG IN ('CREATE TABLE') EXECUTE FUNCTION "audit"."log_ddl"()`, `DROP EVENT TRIGGER "audit"`},
+			},
+		},
+		{
+			version: "100000",
+			changes: []schema.Change{&schema.AddObject{O: &EventTrigger{Name: "t", Event: EventSQLDrop, Func: "f"}}},
+			want: [][2]string{
+				{`CREATE EVENT TRIGGER "t" ON sql_drop EXECUTE PROCEDURE "f"()`, `DROP EVENT TRIGGER "t"`},
+			},
+		},
+		{
+			version: "130000",
+			changes: []schema.Change{&schema.DropObject{O: e2}},
+			want: [][2]string{
+				{`DROP EVENT TRIGGER "audit"`, `CREATE EVENT TRIGGER "audit" ON ddl_command_end WHEN TAG IN ('CREATE TABLE') EXECUTE FUNCTION "audit"."log_ddl"()`},
+			},
+		},
+		{
+			version: "130000",
+			changes: []schema.Change{&schema.ModifyObject{From: e1, To: e2}},
+			want: [][2]string{
+				{`ALTER EVENT TRIGGER "audit" ENABLE REPLICA`, `ALTER EVENT TRIGGER "audit" ENABLE`},
+				{`COMMENT ON EVENT TRIGGER "audit" IS 'c'`, `COMMENT ON EVENT TRIGGER "audit" IS ''`},
+			},
+		},
+		{
+			version: "130000",
+			changes: []schema.Change{&schema.ModifyObject{From: e1, To: &EventTrigger{Name: "audit", Event: EventDDLCommandStart, Func: "audit.log_ddl", Enabled: EventTriggerDisabled}}},
+			want: [][2]string{
+				{`DROP EVENT TRIGGER "audit"`, `CREATE EVENT TRIGGER "audit" ON ddl_command_end WHEN TAG IN ('CREATE TABLE') EXECUTE FUNCTION "audit"."log_ddl"()`},
+				{`CREATE EVENT TRIGGER "audit" ON ddl_command_start EXECUTE FUNCTION "audit"."log_ddl"()`, `DROP EVENT TRIGGER "audit"`},
+				{`ALTER EVENT TRIGGER "audit" DISABLE`, `ALTER EVENT TRIGGER "audit" ENABLE`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		db, mk, err := sqlmock.New()
+		require.NoError(t, err)
+		mock{mk}.version(tt.version)
+		drv, err := Open(db)
+		require.NoError(t, err)
+		plan, err := drv.PlanChanges(context.Background(), "plan", tt.changes)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, planCmds(plan))
+	}
+}
+
+func TestMarshalSpec_EventTriggers(t *testing.T) {
+	r := schema.NewRealm(schema.New("public"))
+	r.AddObjects(
+		(&EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Tags: []string{"CREATE TABLE", "ALTER TABLE"}, Func: "audit.log_ddl"}).SetComment("audit"),
+		&EventTrigger{Name: "no_drops", Event: EventSQLDrop, Func: "public.deny_drops", Enabled: EventTriggerDisabled},
+	)
+	buf, err := MarshalHCL(r)
+	require.NoError(t, err)
+	require.Equal(t, `event_trigger "audit" {
+  on      = ddl_command_end
+  execute = "audit.log_ddl"
+  tags    = ["ALTER TABLE", "CREATE TABLE"]
+  comment = "audit"
+}
+event_trigger "no_drops" {
+  on      = sql_drop
+  execute = "public.deny_drops"
+  enabled = DISABLED
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Realm
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	changes, err := DefaultDiff.RealmDiff(r, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	err = EvalHCLBytes([]byte(`
+event_trigger "audit" {
+  on = ddl_command_end
+}
+`), &got, nil)
+	require.EqualError(t, err, `missing attribute event_trigger.audit.execute`)
+}
@@ -0,0 +1,1060 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+type (
+	// ForeignServer defines a foreign server, that is, the connection information a
+	// foreign-data wrapper uses to access an external data source. Foreign servers
+	// are realm-level objects, and their user mappings are managed along with them.
+	// https://www.postgresql.org/docs/current/sql-createserver.html
+	ForeignServer struct {
+		schema.Object
+		Name     string
+		Wrapper  string            // The foreign-data wrapper, e.g. postgres_fdw.
+		Type     string            // Optional server type.
+		Version  string            // Optional server version.
+		Options  map[string]string // Wrapper-specific options, e.g. host or dbname.
+		Mappings []*UserMapping
+		Attrs    []schema.Attr // Attributes, like comments.
+	}
+
+	// UserMapping defines the mapping of a user to a foreign server. Note, the
+	// "password" option is never inspected, and it is ignored when comparing
+	// two mappings, to avoid exposing secrets in inspection results.
+	// https://www.postgresql.org/docs/current/sql-createusermapping.html
+	UserMapping struct {
+		User    string // The mapped role, or PUBLIC.
+		Options map[string]string
+	}
+
+	// ForeignTable defines a table that is stored on a foreign server. Foreign
+	// tables hold no local data, and therefore, changes to their definition are
+	// planned by recreating them.
+	// https://www.postgresql.org/docs/current/sql-createforeigntable.html
+	ForeignTable struct {
+		schema.Object
+		Name    string
+		Schema  *schema.Schema
+		Server  *ForeignServer
+		Columns []*schema.Column
+		Options map[string]string // Wrapper-specific options, e.g. table_name.
+		Attrs   []schema.Attr     // Attributes, like comments.
+	}
+)
+
+// SpecType returns the type of the foreign server.
+func (*ForeignServer) SpecType() string { return "foreign_server" }
+
+// SpecName returns the name of the foreign server.
+func (s *ForeignServer) SpecName() string { return s.Name }
+
+// SetComment sets or updates the comment of the foreign server.
+func (s *ForeignServer) SetComment(c string) *ForeignServer {
+	schema.ReplaceOrAppend(&s.Attrs, &schema.Comment{Text: c})
+	return s
+}
+
+// Mapping returns the user mapping of the given user, if exists.
+func (s *ForeignServer) Mapping(user string) (*UserMapping, bool) {
+	for _, m := range s.Mappings {
+		if mappingUser(m.User) == mappingUser(user) {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// SpecType returns the type of the foreign table.
+func (*ForeignTable) SpecType() string { return "foreign_table" }
+
+// SpecName returns the name of the foreign table.
+func (t *ForeignTable) SpecName() string { return t.Name }
+
+// SetComment sets or updates the comment of the foreign table.
+func (t *ForeignTable) SetComment(c string) *ForeignTable {
+	schema.ReplaceOrAppend(&t.Attrs, &schema.Comment{Text: c})
+	return t
+}
+
+// Column returns the column with the given name.
+func (t *ForeignTable) Column(name string) (*schema.Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// DependsOn implements the sqlx.Depender interface. A foreign
+// table can be created only after its server was created.
+func (t *ForeignTable) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); ok || t.Server == nil {
+		return false
+	}
+	a, ok := other.(*schema.AddObject)
+	if !ok {
+		return false
+	}
+	s, ok := a.O.(*ForeignServer)
+	return ok && s.Name == t.Server.Name
+}
+
+// DependencyOf implements the sqlx.Depender interface. A foreign
+// table is dropped before its server is dropped.
+func (t *ForeignTable) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok || t.Server == nil {
+		return false
+	}
+	d, ok := other.(*schema.DropObject)
+	if !ok {
+		return false
+	}
+	s, ok := d.O.(*ForeignServer)
+	return ok && s.Name == t.Server.Name
+}
+
+// table returns a table representation of the foreign table,
+// used for sharing the column logic with regular tables.
+func (t *ForeignTable) table() *schema.Table {
+	return &schema.Table{
+		Name:    t.Name,
+		Schema:  t.Schema,
+		Columns: t.Columns,
+		Attrs:   t.Attrs,
+	}
+}
+
+// mappingUser returns the canonical form of a mapped user.
+func mappingUser(u string) string {
+	if strings.EqualFold(u, "public") {
+		return "PUBLIC"
+	}
+	return u
+}
+
+// fdwComment returns the comment of a foreign server or a foreign table.
+func fdwComment(attrs []schema.Attr) string {
+	var c schema.Comment
+	sqlx.Has(attrs, &c)
+	return c.Text
+}
+
+// parseOptions parses the options of foreign-data objects, as stored in
+// the catalog. i.e. a JSON array of "key=value" strings.
+func parseOptions(s sql.NullString) (map[string]string, error) {
+	if !sqlx.ValidString(s) {
+		return nil, nil
+	}
+	var kvs []string
+	if err := json.Unmarshal([]byte(s.String), &kvs); err != nil {
+		return nil, err
+	}
+	opts := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		k, v, _ := strings.Cut(kv, "=")
+		opts[k] = v
+	}
+	return opts, nil
+}
+
+// secretOption reports if the given option holds a secret.
+func secretOption(k string) bool {
+	return k == "password"
+}
+
+// withoutSecrets returns a copy of the options without their secrets.
+func withoutSecrets(opts map[string]string) map[string]string {
+	opts = maps.Clone(opts)
+	maps.DeleteFunc(opts, func(k, _ string) bool {
+		return secretOption(k)
+	})
+	return opts
+}
+
+// inspectForeignServers queries and appends the foreign servers of the realm, along
+// with their user mappings. Foreign tables that were inspected before are linked to
+// their servers.
+func (i *inspect) inspectForeignServers(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, foreignServersQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying foreign servers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name, wrapper                         string
+			typ, version, opts, mappings, comment sql.NullString
+		)
+		if err := rows.Scan(&name, &wrapper, &typ, &version, &opts, &mappings, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning foreign server: %w", err)
+		}
+		s := &ForeignServer{Name: name, Wrapper: wrapper, Type: typ.String, Version: version.String}
+		if s.Options, err = parseOptions(opts); err != nil {
+			return fmt.Errorf("postgres: parsing options of foreign server %q: %w", name, err)
+		}
+		if sqlx.ValidString(mappings) {
+			var ms []struct {
+				User    string   `json:"user"`
+				Options []string `json:"options"`
+			}
+			if err := json.Unmarshal([]byte(mappings.String), &ms); err != nil {
+				return fmt.Errorf("postgres: parsing user mappings of foreign server %q: %w", name, err)
+			}
+			for _, m := range ms {
+				um := &UserMapping{User: mappingUser(m.User)}
+				for _, kv := range m.Options {
+					if k, v, _ := strings.Cut(kv, "="); !secretOption(k) {
+						if um.Options == nil {
+							um.Options = make(map[string]string)
+						}
+						um.Options[k] = v
+					}
+				}
+				s.Mappings = append(s.Mappings, um)
+			}
+		}
+		if sqlx.ValidString(comment) {
+			s.SetComment(comment.String)
+		}
+		r.AddObjects(s)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, ns := range r.Schemas {
+		for _, o := range ns.Objects {
+			if t, ok := o.(*ForeignTable); ok && t.Server != nil {
+				if s, ok := realmForeignServer(r, t.Server.Name); ok {
+					t.Server = s
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// inspectForeignTables queries and appends the foreign tables of the given realm.
+// Foreign servers are realm-level objects, and therefore, the inspected tables are
+// linked to server references that are resolved by inspectForeignServers.
+func (i *inspect) inspectForeignTables(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(foreignTablesQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying foreign tables: %w", err)
+	}
+	defer rows.Close()
+	tables := make(map[*schema.Schema][]*ForeignTable)
+	for rows.Next() {
+		var (
+			ns, name, server string
+			opts, comment    sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &server, &opts, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning foreign table: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for foreign table %q was not found in realm", ns, name)
+		}
+		t := &ForeignTable{Name: name, Schema: s, Server: &ForeignServer{Name: server}}
+		if t.Options, err = parseOptions(opts); err != nil {
+			return fmt.Errorf("postgres: parsing options of foreign table %q: %w", name, err)
+		}
+		if sqlx.ValidString(comment) {
+			t.SetComment(comment.String)
+		}
+		s.AddObjects(t)
+		tables[s] = append(tables[s], t)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(tables[s]) == 0 {
+			continue
+		}
+		if err := i.foreignTableColumns(ctx, s, tables[s]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foreignTableColumns queries and appends the columns of the given foreign tables.
+func (i *inspect) foreignTableColumns(ctx context.Context, s *schema.Schema, tables []*ForeignTable) error {
+	args := []any{s.Name}
+	for _, t := range tables {
+		args = append(args, t.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(foreignTableColumnsQuery, nArgs(1, len(tables))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q foreign table columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			notnull           bool
+			table, name, typ  string
+			defaultE, comment sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &typ, &notnull, &defaultE, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning foreign table columns: %w", err)
+		}
+		idx := slices.IndexFunc(tables, func(t *ForeignTable) bool { return t.Name == table })
+		if idx == -1 {
+			return fmt.Errorf("postgres: foreign table %q was not found in schema", table)
+		}
+		t, err := i.parseType(s, typ)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing column %q type in foreign table %q: %w", name, table, err)
+		}
+		c := &schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{Raw: typ, Type: t, Null: !notnull},
+		}
+		if sqlx.ValidString(defaultE) {
+			c.Default = defaultExpr(t, defaultE.String)
+		}
+		if sqlx.ValidString(comment) {
+			c.SetComment(comment.String)
+		}
+		tables[idx].Columns = append(tables[idx].Columns, c)
+	}
+	return rows.Err()
+}
+
+// realmForeignServer returns the foreign server with the given name from the realm.
+func realmForeignServer(r *schema.Realm, name string) (*ForeignServer, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		s, ok := o.(*ForeignServer)
+		return ok && s.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*ForeignServer), true
+}
+
+// foreignServerDiff returns the changes for migrating the foreign servers of the realm.
+func foreignServerDiff(from, to *schema.Realm) []schema.Change {
+	return sqlx.ObjectsDiff(from.Objects, to.Objects, func(s1, s2 *ForeignServer) bool {
+		return s1.Name == s2.Name
+	}, foreignServerChanged)
+}
+
+// foreignServerChanged reports if the foreign server or its user mappings were changed.
+func foreignServerChanged(from, to *ForeignServer) bool {
+	if from.Wrapper != to.Wrapper || from.Type != to.Type || from.Version != to.Version ||
+		!maps.Equal(from.Options, to.Options) || fdwComment(from.Attrs) != fdwComment(to.Attrs) {
+		return true
+	}
+	adds, drops, modifies := mappingChanges(from, to)
+	return len(adds)+len(drops)+len(modifies) > 0
+}
+
+// mappingChanges returns the user mappings that should be added, dropped or modified.
+// Modified mappings are returned as pairs of their current and desired states.
+func mappingChanges(from, to *ForeignServer) (adds, drops []*UserMapping, modifies [][2]*UserMapping) {
+	for _, m2 := range to.Mappings {
+		switch m1, ok := from.Mapping(m2.User); {
+		case !ok:
+			adds = append(adds, m2)
+		case !maps.Equal(withoutSecrets(m1.Options), withoutSecrets(m2.Options)):
+			modifies = append(modifies, [2]*UserMapping{m1, m2})
+		}
+	}
+	for _, m1 := range from.Mappings {
+		if _, ok := to.Mapping(m1.User); !ok {
+			drops = append(drops, m1)
+		}
+	}
+	return adds, drops, modifies
+}
+
+// foreignTableChange describes the changes between two states of a foreign table.
+type foreignTableChange struct {
+	recreate bool // Server or columns were changed.
+	options  bool // Options were changed.
+	comment  bool // Comment was changed.
+	columns  bool // Column comments were changed.
+}
+
+// foreignTableDiff returns the changes between two states of a foreign table.
+func (d *diff) foreignTableDiff(from, to *ForeignTable) (*foreignTableChange, error) {
+	c := &foreignTableChange{
+		recreate: from.Server == nil || to.Server == nil || from.Server.Name != to.Server.Name || len(from.Columns) != len(to.Columns),
+		options:  !maps.Equal(from.Options, to.Options),
+		comment:  fdwComment(from.Attrs) != fdwComment(to.Attrs),
+	}
+	for i := 0; i < len(from.Columns) && !c.recreate; i++ {
+		c1, c2 := from.Columns[i], to.Columns[i]
+		if c1.Name != c2.Name || c1.Type.Null != c2.Type.Null {
+			c.recreate = true
+			break
+		}
+		changed, err := d.typeChanged(c1, c2)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			if changed, err = d.defaultChanged(c1, c2); err != nil {
+				return nil, err
+			}
+		}
+		c.recreate = changed
+		c.columns = c.columns || sqlx.CommentChange(c1.Attrs, c2.Attrs) != schema.NoChange
+	}
+	return c, nil
+}
+
+// foreignTableObjectDiff returns the changes for migrating the foreign tables.
+func (d *diff) foreignTableObjectDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		t1, ok := o1.(*ForeignTable)
+		if !ok {
+			continue
+		}
+		t2, ok := schemaForeignTable(to, t1.Name)
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: t1})
+			continue
+		}
+		c, err := d.foreignTableDiff(t1, t2)
+		if err != nil {
+			return nil, err
+		}
+		if c.recreate || c.options || c.comment || c.columns {
+			changes = append(changes, &schema.ModifyObject{From: t1, To: t2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if t2, ok := o2.(*ForeignTable); ok {
+			if _, ok := schemaForeignTable(from, t2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: t2})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// schemaForeignTable returns the foreign table with the given name from the schema.
+func schemaForeignTable(s *schema.Schema, name string) (*ForeignTable, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		t, ok := o.(*ForeignTable)
+		return ok && t.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*ForeignTable), true
+}
+
+// addForeignServer plans the creation of a foreign server and its user mappings.
+func (s *state) addForeignServer(src schema.Change, fs *ForeignServer) {
+	create, drop := s.createDropForeignServer(fs)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create foreign server %q", fs.Name),
+	})
+	for _, m := range fs.Mappings {
+		s.addUserMapping(src, fs, m)
+	}
+	if c := fdwComment(fs.Attrs); c != "" {
+		s.append(s.foreignServerComment(src, fs, c, ""))
+	}
+}
+
+// dropForeignServer plans the removal of a foreign server. User mappings
+// are dropped first, as they prevent the server from being dropped.
+func (s *state) dropForeignServer(src schema.Change, fs *ForeignServer) {
+	for _, m := range fs.Mappings {
+		s.dropUserMapping(src, fs, m)
+	}
+	create, drop := s.createDropForeignServer(fs)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop foreign server %q", fs.Name),
+	})
+}
+
+// modifyForeignServer plans the changes of a foreign server. Changing the
+// wrapper or the type of the server requires recreating it.
+func (s *state) modifyForeignServer(src schema.Change, from, to *ForeignServer) {
+	if from.Wrapper != to.Wrapper || from.Type != to.Type {
+		s.dropForeignServer(src, from)
+		s.addForeignServer(src, to)
+		return
+	}
+	alter := s.Build("ALTER SERVER").Ident(to.Name)
+	if from.Version != to.Version {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     alter.Clone().P("VERSION", quote(to.Version)).String(),
+			Reverse: alter.Clone().P("VERSION", quote(from.Version)).String(),
+			Comment: fmt.Sprintf("set version of foreign server %q", to.Name),
+		})
+	}
+	if !maps.Equal(from.Options, to.Options) {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     alterOptions(alter.Clone(), from.Options, to.Options).String(),
+			Reverse: alterOptions(alter.Clone(), to.Options, from.Options).String(),
+			Comment: fmt.Sprintf("set options of foreign server %q", to.Name),
+		})
+	}
+	adds, drops, modifies := mappingChanges(from, to)
+	for _, m := range drops {
+		s.dropUserMapping(src, from, m)
+	}
+	for _, m := range modifies {
+		b := s.Build("ALTER USER MAPPING FOR")
+		mappingFor(b, m[1]).P("SERVER").Ident(to.Name)
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     alterOptions(b.Clone(), withoutSecrets(m[0].Options), withoutSecrets(m[1].Options)).String(),
+			Reverse: alterOptions(b.Clone(), withoutSecrets(m[1].Options), withoutSecrets(m[0].Options)).String(),
+			Comment: fmt.Sprintf("set options of user mapping for %q on foreign server %q", m[1].User, to.Name),
+		})
+	}
+	for _, m := range adds {
+		s.addUserMapping(src, to, m)
+	}
+	if c1, c2 := fdwComment(from.Attrs), fdwComment(to.Attrs); c1 != c2 {
+		s.append(s.foreignServerComment(src, to, c2, c1))
+	}
+}
+
+func (s *state) addUserMapping(src schema.Change, fs *ForeignServer, m *UserMapping) {
+	create, drop := s.createDropUserMapping(fs, m)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create user mapping for %q on foreign server %q", m.User, fs.Name),
+	})
+}
+
+func (s *state) dropUserMapping(src schema.Change, fs *ForeignServer, m *UserMapping) {
+	create, drop := s.createDropUserMapping(fs, m)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop user mapping for %q on foreign server %q", m.User, fs.Name),
+	})
+}
+
+func (s *state) createDropForeignServer(fs *ForeignServer) (string, string) {
+	b := s.Build("CREATE SERVER").Ident(fs.Name)
+	if fs.Type != "" {
+		b.P("TYPE", quote(fs.Type))
+	}
+	if fs.Version != "" {
+		b.P("VERSION", quote(fs.Version))
+	}
+	b.P("FOREIGN DATA WRAPPER").Ident(fs.Wrapper)
+	return withOptions(b, fs.Options).String(), s.Build("DROP SERVER").Ident(fs.Name).String()
+}
+
+func (s *state) createDropUserMapping(fs *ForeignServer, m *UserMapping) (string, string) {
+	create, drop := s.Build("CREATE USER MAPPING FOR"), s.Build("DROP USER MAPPING FOR")
+	mappingFor(create, m).P("SERVER").Ident(fs.Name)
+	mappingFor(drop, m).P("SERVER").Ident(fs.Name)
+	return withOptions(create, m.Options).String(), drop.String()
+}
+
+func (s *state) foreignServerComment(src schema.Change, fs *ForeignServer, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON SERVER").Ident(fs.Name).P("IS")
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Reverse: b.Clone().P(quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to foreign server: %q", fs.Name),
+	}
+}
+
+// addForeignTable plans the creation of a foreign table and its comments.
+func (s *state) addForeignTable(src schema.Change, t *ForeignTable) error {
+	create, drop, err := s.createDropForeignTable(t)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create foreign table %q", t.Name),
+	})
+	if c := fdwComment(t.Attrs); c != "" {
+		s.append(s.foreignTableComment(src, t, c, ""))
+	}
+	tt := t.table()
+	for _, c := range t.Columns {
+		if cm := fdwComment(c.Attrs); cm != "" {
+			s.append(s.columnComment(src, tt, c, cm, ""))
+		}
+	}
+	return nil
+}
+
+// dropForeignTable plans the removal of a foreign table.
+func (s *state) dropForeignTable(src schema.Change, t *ForeignTable) error {
+	create, drop, err := s.createDropForeignTable(t)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop foreign table %q", t.Name),
+	})
+	return nil
+}
+
+// modifyForeignTable plans the changes between two states of a foreign table.
+// As foreign tables hold no local data, changing their server or columns is
+// planned by recreating them.
+func (s *state) modifyForeignTable(modify *schema.ModifyObject, from, to *ForeignTable) error {
+	c, err := (&diff{s.conn}).foreignTableDiff(from, to)
+	if err != nil {
+		return err
+	}
+	if c.recreate {
+		if err := s.dropForeignTable(modify, from); err != nil {
+			return err
+		}
+		return s.addForeignTable(modify, to)
+	}
+	if c.options {
+		alter := s.Build("ALTER FOREIGN TABLE").P(s.typeIdent(to.Schema, to.Name))
+		s.append(&migrate.Change{
+			Source:  modify,
+			Cmd:     alterOptions(alter.Clone(), from.Options, to.Options).String(),
+			Reverse: alterOptions(alter.Clone(), to.Options, from.Options).String(),
+			Comment: fmt.Sprintf("set options of foreign table %q", to.Name),
+		})
+	}
+	if c.comment {
+		s.append(s.foreignTableComment(modify, to, fdwComment(to.Attrs), fdwComment(from.Attrs)))
+	}
+	if c.columns {
+		tt := to.table()
+		for i, c2 := range to.Columns {
+			if c1, c2 := fdwComment(from.Columns[i].Attrs), fdwComment(c2.Attrs); c1 != c2 {
+				s.append(s.columnComment(modify, tt, to.Columns[i], c2, c1))
+			}
+		}
+	}
+	return nil
+}
+
+func (s *state) createDropForeignTable(t *ForeignTable) (string, string, error) {
+	if t.Server == nil {
+		return "", "", fmt.Errorf("missing server for foreign table %q", t.Name)
+	}
+	name := s.typeIdent(t.Schema, t.Name)
+	b := s.Build("CREATE FOREIGN TABLE").P(name)
+	var err error
+	b.WrapIndent(func(b *sqlx.Builder) {
+		err = b.MapIndentErr(t.Columns, func(i int, b *sqlx.Builder) error {
+			return s.column(b, t.Columns[i])
+		})
+	})
+	if err != nil {
+		return "", "", err
+	}
+	b.P("SERVER").Ident(t.Server.Name)
+	return withOptions(b, t.Options).String(), s.Build("DROP FOREIGN TABLE").P(name).String(), nil
+}
+
+func (s *state) foreignTableComment(src schema.Change, t *ForeignTable, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON FOREIGN TABLE").P(s.typeIdent(t.Schema, t.Name)).P("IS")
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Reverse: b.Clone().P(quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to foreign table: %q", t.Name),
+	}
+}
+
+// mappingFor writes the user of a user mapping.
+func mappingFor(b *sqlx.Builder, m *UserMapping) *sqlx.Builder {
+	if u := mappingUser(m.User); u == "PUBLIC" {
+		return b.P(u)
+	}
+	return b.Ident(m.User)
+}
+
+// withOptions writes the OPTIONS clause of foreign-data objects, if needed.
+func withOptions(b *sqlx.Builder, opts map[string]string) *sqlx.Builder {
+	if len(opts) == 0 {
+		return b
+	}
+	keys := slices.Sorted(maps.Keys(opts))
+	return b.P("OPTIONS").Wrap(func(b *sqlx.Builder) {
+		b.MapComma(keys, func(i int, b *sqlx.Builder) {
+			optionName(b, keys[i]).P(quote(opts[keys[i]]))
+		})
+	})
+}
+
+// alterOptions writes the OPTIONS clause for migrating the options of
+// foreign-data objects from one state to the other.
+func alterOptions(b *sqlx.Builder, from, to map[string]string) *sqlx.Builder {
+	var ops [][2]string
+	for _, k := range slices.Sorted(maps.Keys(from)) {
+		if _, ok := to[k]; !ok {
+			ops = append(ops, [2]string{"DROP", k})
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(to)) {
+		switch v, ok := from[k]; {
+		case !ok:
+			ops = append(ops, [2]string{"ADD", k})
+		case v != to[k]:
+			ops = append(ops, [2]string{"SET", k})
+		}
+	}
+	return b.P("OPTIONS").Wrap(func(b *sqlx.Builder) {
+		b.MapComma(ops, func(i int, b *sqlx.Builder) {
+			optionName(b.P(ops[i][0]), ops[i][1])
+			if ops[i][0] != "DROP" {
+				b.P(quote(to[ops[i][1]]))
+			}
+		})
+	})
+}
+
+var reOptionName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// optionName writes the option name, and quotes it if needed.
+func optionName(b *sqlx.Builder, k string) *sqlx.Builder {
+	if reOptionName.MatchString(k) {
+		return b.P(k)
+	}
+	return b.Ident(k)
+}
+
+// foreignServer holds a specification for a foreign server.
+// Note, foreign server names are unique within a realm (database).
+type foreignServer struct {
+	Name string `spec:",name"`
+	// The wrapper, type, version and comment attributes, and
+	// the options and user_mapping blocks are added to the
+	// server definition if set.
+	schemahcl.DefaultExtension
+}
+
+// foreignTable holds a specification for a foreign table.
+type foreignTable struct {
+	Name      string            `spec:",name"`
+	Qualifier string            `spec:",qualifier"`
+	Schema    *schemahcl.Ref    `spec:"schema"`
+	Server    *schemahcl.Ref    `spec:"server"`
+	Columns   []*sqlspec.Column `spec:"column"`
+	// The "options" block and the "comment" attribute
+	// are conditionally added to the table definition.
+	schemahcl.DefaultExtension
+}
+
+// Label returns the defaults label used for the foreign table resource.
+func (t *foreignTable) Label() string { return t.Name }
+
+// QualifierLabel returns the qualifier label used for the foreign table resource, if any.
+func (t *foreignTable) QualifierLabel() string { return t.Qualifier }
+
+// SetQualifier sets the qualifier label used for the foreign table resource.
+func (t *foreignTable) SetQualifier(q string) { t.Qualifier = q }
+
+// SchemaRef returns the schema reference for the foreign table.
+func (t *foreignTable) SchemaRef() *schemahcl.Ref { return t.Schema }
+
+// convertForeignServers converts the foreign server specs and adds them to the realm.
+func convertForeignServers(specs []*foreignServer, r *schema.Realm) error {
+	for _, spec := range specs {
+		s := &ForeignServer{Name: spec.Name}
+		for _, a := range []struct {
+			name string
+			v    *string
+		}{
+			{name: "wrapper", v: &s.Wrapper},
+			{name: "type", v: &s.Type},
+			{name: "version", v: &s.Version},
+		} {
+			attr, ok := spec.Attr(a.name)
+			if !ok {
+				continue
+			}
+			v, err := attr.String()
+			if err != nil {
+				return fmt.Errorf("parsing foreign server %q attribute %q: %w", spec.Name, a.name, err)
+			}
+			*a.v = v
+		}
+		if s.Wrapper == "" {
+			return fmt.Errorf("missing attribute foreign_server.%s.wrapper", spec.Name)
+		}
+		opts, err := convertOptions(&spec.DefaultExtension.Extra)
+		if err != nil {
+			return fmt.Errorf("parsing foreign server %q options: %w", spec.Name, err)
+		}
+		s.Options = opts
+		for _, ms := range spec.Extra.Resources("user_mapping") {
+			if ms.Name == "" {
+				return fmt.Errorf("missing user name for user mapping of foreign server %q", spec.Name)
+			}
+			if _, ok := s.Mapping(ms.Name); ok {
+				return fmt.Errorf("duplicate user mapping %q for foreign server %q", ms.Name, spec.Name)
+			}
+			opts, err := convertOptions(ms)
+			if err != nil {
+				return fmt.Errorf("parsing user mapping %q options of foreign server %q: %w", ms.Name, spec.Name, err)
+			}
+			s.Mappings = append(s.Mappings, &UserMapping{User: mappingUser(ms.Name), Options: opts})
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing foreign server %q attribute \"comment\": %w", spec.Name, err)
+			}
+			s.SetComment(c)
+		}
+		r.AddObjects(s)
+	}
+	return nil
+}
+
+// convertForeignTables converts the foreign table specs into schema objects. Servers
+// that are not defined in the document (e.g., in schema scope) are referenced by name.
+func convertForeignTables(specs []*foreignTable, r *schema.Realm) error {
+	for _, spec := range specs {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from foreign table %q reference: %w", spec.Name, err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on foreign table %q was not found in realm", ns, spec.Name)
+		}
+		if spec.Server == nil {
+			return fmt.Errorf("missing attribute foreign_table.%s.server", spec.Name)
+		}
+		names, err := spec.Server.ByType("foreign_server")
+		if err != nil || len(names) != 1 {
+			return fmt.Errorf("unexpected server reference %q for foreign table %q", spec.Server.V, spec.Name)
+		}
+		server, ok := realmForeignServer(r, names[0])
+		if !ok {
+			server = &ForeignServer{Name: names[0]}
+		}
+		t := &ForeignTable{Name: spec.Name, Schema: s, Server: server}
+		for _, cs := range spec.Columns {
+			c, err := convertColumn(cs, nil)
+			if err != nil {
+				return err
+			}
+			t.Columns = append(t.Columns, c)
+		}
+		if t.Options, err = convertOptions(&spec.DefaultExtension.Extra); err != nil {
+			return fmt.Errorf("parsing foreign table %q options: %w", spec.Name, err)
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing foreign table %q attribute \"comment\": %w", spec.Name, err)
+			}
+			t.SetComment(c)
+		}
+		s.AddObjects(t)
+	}
+	return nil
+}
+
+// convertOptions converts the "options" block of the given resource. Option values
+// are stored as strings, and therefore, numbers and booleans are converted to strings.
+func convertOptions(r *schemahcl.Resource) (map[string]string, error) {
+	block, ok := r.Resource("options")
+	if !ok {
+		return nil, nil
+	}
+	opts := make(map[string]string, len(block.Attrs))
+	for _, a := range block.Attrs {
+		v, err := convert.Convert(a.V, cty.String)
+		if err != nil || v.IsNull() {
+			return nil, fmt.Errorf("unexpected value for option %q", a.K)
+		}
+		opts[a.K] = v.AsString()
+	}
+	return opts, nil
+}
+
+// optionsSpec returns the "options" block of the given options, if needed.
+func optionsSpec(opts map[string]string) (*schemahcl.Resource, bool) {
+	if len(opts) == 0 {
+		return nil, false
+	}
+	r := &schemahcl.Resource{Type: "options"}
+	for _, k := range slices.Sorted(maps.Keys(opts)) {
+		r.Attrs = append(r.Attrs, schemahcl.StringAttr(k, opts[k]))
+	}
+	return r, true
+}
+
+// foreignServerSpecs returns the foreign server specs of the realm.
+// Note, secrets of user mappings are not exported.
+func foreignServerSpecs(r *schema.Realm) []*foreignServer {
+	var specs []*foreignServer
+	for _, o := range r.Objects {
+		s, ok := o.(*ForeignServer)
+		if !ok {
+			continue
+		}
+		spec := &foreignServer{Name: s.Name}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("wrapper", s.Wrapper))
+		if s.Type != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("type", s.Type))
+		}
+		if s.Version != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("version", s.Version))
+		}
+		if c := fdwComment(s.Attrs); c != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+		}
+		if o, ok := optionsSpec(s.Options); ok {
+			spec.Extra.Children = append(spec.Extra.Children, o)
+		}
+		for _, m := range s.Mappings {
+			ms := &schemahcl.Resource{Type: "user_mapping", Name: mappingUser(m.User)}
+			if o, ok := optionsSpec(withoutSecrets(m.Options)); ok {
+				ms.Children = append(ms.Children, o)
+			}
+			spec.Extra.Children = append(spec.Extra.Children, ms)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// foreignTableSpec converts a foreign table into its spec.
+func foreignTableSpec(t *ForeignTable) (*foreignTable, error) {
+	spec := &foreignTable{
+		Name:   t.Name,
+		Schema: specutil.SchemaRef(t.Schema.Name),
+	}
+	if t.Server != nil {
+		spec.Server = specutil.ObjectRef(nil, t.Server)
+	}
+	tt := t.table()
+	for _, c := range t.Columns {
+		cs, err := tableColumnSpec(c, tt)
+		if err != nil {
+			return nil, err
+		}
+		spec.Columns = append(spec.Columns, cs)
+	}
+	if c := fdwComment(t.Attrs); c != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+	}
+	if o, ok := optionsSpec(t.Options); ok {
+		spec.Extra.Children = append(spec.Extra.Children, o)
+	}
+	return spec, nil
+}
+
+const (
+	// Query to list the foreign servers of the database and their user mappings.
+	// Note, the pg_user_mappings view hides the options of mappings the current
+	// user does not own, and secrets are filtered out by the inspection.
+	foreignServersQuery = `
+SELECT
+  s.srvname,
+  w.fdwname,
+  s.srvtype,
+  s.srvversion,
+  array_to_json(s.srvoptions) AS options,
+  (
+    SELECT json_agg(json_build_object('user', CASE WHEN um.umuser = 0 THEN 'PUBLIC' ELSE um.usename END, 'options', um.umoptions) ORDER BY um.usename)
+    FROM pg_catalog.pg_user_mappings AS um
+    WHERE um.srvid = s.oid
+  ) AS mappings,
+  obj_description(s.oid, 'pg_foreign_server') AS comment
+FROM
+  pg_catalog.pg_foreign_server AS s
+  JOIN pg_catalog.pg_foreign_data_wrapper AS w ON w.oid = s.srvfdw
+ORDER BY
+  s.srvname
+`
+	// Query to list the foreign tables of the given schemas.
+	foreignTablesQuery = `
+SELECT
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  s.srvname AS server_name,
+  array_to_json(ft.ftoptions) AS options,
+  obj_description(c.oid, 'pg_class') AS comment
+FROM
+  pg_catalog.pg_foreign_table AS ft
+  JOIN pg_catalog.pg_class AS c ON c.oid = ft.ftrelid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  JOIN pg_catalog.pg_foreign_server AS s ON s.oid = ft.ftserver
+WHERE
+  n.nspname IN (%s)
+ORDER BY
+  n.nspname, c.relname
+`
+	// Query to list the columns of foreign tables.
+	foreignTableColumnsQuery = `
+SELECT
+  c.relname AS table_name,
+  a.attname AS column_name,
+  format_type(a.atttypid, a.atttypmod) AS data_type,
+  a.attnotnull AS not_null,
+  pg_get_expr(d.adbin, d.adrelid) AS column_default,
+  col_description(a.attrelid, a.attnum) AS comment
+FROM
+  pg_catalog.pg_attribute AS a
+  JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  LEFT JOIN pg_catalog.pg_attrdef AS d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE
+  n.nspname = $1
+  AND c.relname IN (%s)
+  AND a.attnum > 0
+  AND NOT a.attisdropped
+ORDER BY
+  c.relname, a.attnum
+`
+)
@@ -0,0 +1,266 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectForeignData(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "sequence_name", "sequence_type", "seqstart", "seqincrement", "seqmin", "seqmax", "seqcache", "seqcycle", "owner_table", "owner_column", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | server_name | options                                        | comment
+-------------+------------+-------------+------------------------------------------------+---------
+ public      | events     | analytics   | ["schema_name=public", "table_name=events"]    | remote events
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTableColumnsQuery, "$2"))).
+		WithArgs("public", "events").
+		WillReturnRows(sqltest.Rows(`
+ table_name | column_name | data_type | not_null | column_default | comment
+------------+-------------+-----------+----------+----------------+---------
+ events     | id          | bigint    | t        | nil            | nil
+ events     | payload     | text      | f        | nil            | body
+`))
+	mk.ExpectQuery(sqltest.Escape(publicationsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"pubname", "puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate", "tables"}))
+	mk.ExpectQuery(sqltest.Escape(eventTriggersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"evtname", "evtevent", "nspname", "proname", "evtenabled", "tags", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(foreignServersQuery)).
+		WillReturnRows(sqltest.Rows(`
+ srvname   | fdwname      | srvtype | srvversion | options                               | mappings                                                                                                          | comment
+-----------+--------------+---------+------------+---------------------------------------+-------------------------------------------------------------------------------------------------------------------+---------
+ analytics | postgres_fdw | nil     | nil        | ["host=db.internal", "dbname=events"] | [{"user": "PUBLIC", "options": null}, {"user": "reader", "options": ["user=ro", "password=secret"]}]              | nil
+`))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectObjects,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	server := &ForeignServer{
+		Name:    "analytics",
+		Wrapper: "postgres_fdw",
+		Options: map[string]string{"host": "db.internal", "dbname": "events"},
+		Mappings: []*UserMapping{
+			{User: "PUBLIC"},
+			{User: "reader", Options: map[string]string{"user": "ro"}},
+		},
+	}
+	require.Equal(t, []schema.Object{server}, r.Objects)
+	s := r.Schemas[0]
+	require.Len(t, s.Objects, 1)
+	ft := s.Objects[0].(*ForeignTable)
+	require.Same(t, r.Objects[0], ft.Server, "foreign tables are linked to their servers")
+	require.Equal(t, "remote events", fdwComment(ft.Attrs))
+	require.Equal(t, map[string]string{"schema_name": "public", "table_name": "events"}, ft.Options)
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: "bigint"}}},
+		{Name: "payload", Type: &schema.ColumnType{Raw: "text", Type: &schema.StringType{T: "text"}, Null: true}, Attrs: []schema.Attr{&schema.Comment{Text: "body"}}},
+	}, ft.Columns)
+}
+
+func TestDiff_ForeignData(t *testing.T) {
+	var (
+		s1 = &ForeignServer{Name: "s", Wrapper: "postgres_fdw", Options: map[string]string{"host": "a"}, Mappings: []*UserMapping{{User: "public"}, {User: "u", Options: map[string]string{"user": "x"}}}}
+		s2 = &ForeignServer{Name: "s", Wrapper: "postgres_fdw", Options: map[string]string{"host": "a"}, Mappings: []*UserMapping{{User: "PUBLIC"}, {User: "u", Options: map[string]string{"user": "x", "password": "secret"}}}}
+	)
+	require.False(t, foreignServerChanged(s1, s2), "secrets are ignored and users are compared normalized")
+	s2.Options = map[string]string{"host": "b"}
+	require.True(t, foreignServerChanged(s1, s2))
+
+	from, to := schema.NewRealm(schema.New("public")), schema.NewRealm(schema.New("public"))
+	from.AddObjects(s1, &ForeignServer{Name: "dropped", Wrapper: "file_fdw"})
+	to.AddObjects(s2, &ForeignServer{Name: "added", Wrapper: "file_fdw"})
+	from.Schemas[0].AddObjects(
+		&ForeignTable{Name: "same", Server: s1, Columns: []*schema.Column{schema.NewIntColumn("id", "int")}},
+		&ForeignTable{Name: "options", Server: s1, Options: map[string]string{"table_name": "a"}},
+		&ForeignTable{Name: "dropped", Server: s1},
+	)
+	to.Schemas[0].AddObjects(
+		&ForeignTable{Name: "same", Server: s2, Columns: []*schema.Column{schema.NewIntColumn("id", "int")}},
+		&ForeignTable{Name: "options", Server: s2, Options: map[string]string{"table_name": "b"}},
+		&ForeignTable{Name: "added", Server: s2},
+	)
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 6)
+	require.Equal(t, &schema.ModifyObject{From: s1, To: s2}, changes[0])
+	require.Equal(t, &schema.DropObject{O: from.Objects[1]}, changes[1])
+	require.Equal(t, &schema.AddObject{O: to.Objects[1]}, changes[2])
+	require.Equal(t, &schema.ModifyObject{From: from.Schemas[0].Objects[1], To: to.Schemas[0].Objects[1]}, changes[3])
+	require.Equal(t, &schema.DropObject{O: from.Schemas[0].Objects[2]}, changes[4])
+	require.Equal(t, &schema.AddObject{O: to.Schemas[0].Objects[2]}, changes[5])
+}
+
+func TestPlanChanges_ForeignData(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		fs = &ForeignServer{
+			Name:     "analytics",
+			Wrapper:  "postgres_fdw",
+			Options:  map[string]string{"host": "db.internal", "dbname": "events"},
+			Mappings: []*UserMapping{{User: "PUBLIC", Options: map[string]string{"user": "ro", "password": "secret"}}},
+		}
+		ft = (&ForeignTable{
+			Name:    "events",
+			Schema:  s,
+			Server:  fs,
+			Columns: []*schema.Column{schema.NewIntColumn("id", "bigint")},
+			Options: map[string]string{"table_name": "events"},
+		}).SetComment("remote events")
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: ft},
+		&schema.AddObject{O: fs},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE SERVER "analytics" FOREIGN DATA WRAPPER "postgres_fdw" OPTIONS (dbname 'events', host 'db.internal')`, `DROP SERVER "analytics"`},
+		{`CREATE USER MAPPING FOR PUBLIC SERVER "analytics" OPTIONS (password 'secret', user 'ro')`, `DROP USER MAPPING FOR PUBLIC SERVER "analytics"`},
+		{`CREATE FOREIGN TABLE "public"."events" ("id" bigint NOT NULL) SERVER "analytics" OPTIONS (table_name 'events')`, `DROP FOREIGN TABLE "public"."events"`},
+		{`COMMENT ON FOREIGN TABLE "public"."events" IS 'remote events'`, `COMMENT ON FOREIGN TABLE "public"."events" IS ''`},
+	}, planCmds(plan))
+
+	// Foreign tables are dropped before their servers, and user mappings before the servers.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropObject{O: fs},
+		&schema.DropObject{O: ft},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`DROP FOREIGN TABLE "public"."events"`, `CREATE FOREIGN TABLE "public"."events" ("id" bigint NOT NULL) SERVER "analytics" OPTIONS (table_name 'events')`},
+		{`DROP USER MAPPING FOR PUBLIC SERVER "analytics"`, `CREATE USER MAPPING FOR PUBLIC SERVER "analytics" OPTIONS (password 'secret', user 'ro')`},
+		{`DROP SERVER "analytics"`, `CREATE SERVER "analytics" FOREIGN DATA WRAPPER "postgres_fdw" OPTIONS (dbname 'events', host 'db.internal')`},
+	}, planCmds(plan))
+
+	to := &ForeignServer{
+		Name:     "analytics",
+		Wrapper:  "postgres_fdw",
+		Version:  "15",
+		Options:  map[string]string{"host": "replica.internal", "port": "5432"},
+		Mappings: []*UserMapping{{User: "PUBLIC", Options: map[string]string{"user": "reader"}}, {User: "etl"}},
+	}
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: fs, To: to},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`ALTER SERVER "analytics" VERSION '15'`, `ALTER SERVER "analytics" VERSION ''`},
+		{`ALTER SERVER "analytics" OPTIONS (DROP dbname, SET host 'replica.internal', ADD port '5432')`, `ALTER SERVER "analytics" OPTIONS (DROP port, ADD dbname 'events', SET host 'db.internal')`},
+		{`ALTER USER MAPPING FOR PUBLIC SERVER "analytics" OPTIONS (SET user 'reader')`, `ALTER USER MAPPING FOR PUBLIC SERVER "analytics" OPTIONS (SET user 'ro')`},
+		{`CREATE USER MAPPING FOR "etl" SERVER "analytics"`, `DROP USER MAPPING FOR "etl" SERVER "analytics"`},
+	}, planCmds(plan))
+
+	// Changing the columns recreates the table.
+	ft2 := &ForeignTable{Name: "events", Schema: s, Server: fs, Columns: []*schema.Column{schema.NewIntColumn("id", "int")}, Options: map[string]string{"table_name": "events"}}
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: ft, To: ft2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`DROP FOREIGN TABLE "public"."events"`, `CREATE FOREIGN TABLE "public"."events" ("id" bigint NOT NULL) SERVER "analytics" OPTIONS (table_name 'events')`},
+		{`CREATE FOREIGN TABLE "public"."events" ("id" integer NOT NULL) SERVER "analytics" OPTIONS (table_name 'events')`, `DROP FOREIGN TABLE "public"."events"`},
+	}, planCmds(plan))
+}
+
+func TestMarshalSpec_ForeignData(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		r  = schema.NewRealm(s)
+		fs = &ForeignServer{
+			Name:     "analytics",
+			Wrapper:  "postgres_fdw",
+			Options:  map[string]string{"host": "db.internal", "dbname": "events"},
+			Mappings: []*UserMapping{{User: "PUBLIC", Options: map[string]string{"user": "ro", "password": "secret"}}},
+		}
+	)
+	r.AddObjects(fs)
+	s.AddObjects((&ForeignTable{
+		Name:    "events",
+		Schema:  s,
+		Server:  fs,
+		Columns: []*schema.Column{schema.NewIntColumn("id", "bigint")},
+		Options: map[string]string{"table_name": "events"},
+	}).SetComment("remote events"))
+	buf, err := MarshalHCL(r)
+	require.NoError(t, err)
+	require.Equal(t, `foreign_server "analytics" {
+  wrapper = "postgres_fdw"
+  options {
+    dbname = "events"
+    host   = "db.internal"
+  }
+  user_mapping "PUBLIC" {
+    options {
+      user = "ro"
+    }
+  }
+}
+foreign_table "events" {
+  schema  = schema.public
+  server  = foreign_server.analytics
+  comment = "remote events"
+  column "id" {
+    null = false
+    type = bigint
+  }
+  options {
+    table_name = "events"
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	got := schema.NewRealm()
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	// Secrets are not exported.
+	fs.Mappings[0].Options = map[string]string{"user": "ro"}
+	changes, err := DefaultDiff.RealmDiff(r, got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	require.Same(t, got.Objects[0], got.Schemas[0].Objects[0].(*ForeignTable).Server)
+
+	err = EvalHCLBytes([]byte(`
+foreign_server "s" {
+  options {
+    port = 5432
+  }
+}
+`), got, nil)
+	require.EqualError(t, err, `missing attribute foreign_server.s.wrapper`)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import "fmt"
+
+// A feature describes a database capability whose support depends on the flavor
+// of the connected server (PostgreSQL, CockroachDB or YugabyteDB) and its version.
+type feature uint8
+
+// List of features that are gated by the capability matrix below.
+const (
+	featRangeTypes         feature = iota // Range and multirange types.
+	featStats                             // Extended statistics.
+	featMatViews                          // Materialized views.
+	featTriggerFuncs                      // Trigger functions.
+	featSequences                         // Sequences, exposed by the pg_sequence catalog.
+	featIdentitySequences                 // Options of sequences backing identity columns.
+	featForeignTables                     // Foreign tables.
+	featPublications                      // Publications.
+	featEventTriggers                     // Event triggers.
+	featForeignServers                    // Foreign servers.
+	featRoles                             // Roles, ownership and default privileges.
+	featSearchPath                        // Resetting the session search_path on realm inspection.
+	featServerSettings                    // Server settings (pg_settings).
+	featExtensions                        // Extensions for column types (e.g., citext).
+	featPartitions                        // Table partitions.
+	featNoInherit                         // NO INHERIT constraints.
+	featExcludeConstraints                // Exclusion constraints.
+	featHashSharded                       // Hash-sharded indexes.
+	featLocality                          // Table localities in multi-region databases.
+	featRowTTL                            // Row-level TTL.
+	featAlterColumnType                   // Changing column types, which may require table rewrites.
+	featColocation                        // Colocated tables.
+	featTabletSplit                       // Pre-splitting tables and indexes into tablets.
+)
+
+// unsupported marks a feature as not supported by a flavor.
+const unsupported = -1
+
+// features is the capability matrix of the supported flavors. Each entry holds
+// the name of the feature, and the minimum server version in which it is supported
+// by PostgreSQL, CockroachDB and YugabyteDB, or unsupported. Versions are written in
+// the server_version_num format (e.g., 22.1 is 22_01_00), and YugabyteDB versions
+// refer to its own release (e.g., 2.20), rather than its PostgreSQL compatibility.
+var features = map[feature]struct {
+	name         string
+	pg, crdb, yb int
+}{
+	featRangeTypes:         {"range types", 0, unsupported, 0},
+	featStats:              {"extended statistics", 0, unsupported, 0},
+	featMatViews:           {"materialized views", 0, unsupported, 0},
+	featTriggerFuncs:       {"trigger functions", 0, unsupported, 0},
+	featSequences:          {"sequences", 10_00_00, unsupported, 0},
+	featIdentitySequences:  {"identity sequences", 0, unsupported, 0},
+	featForeignTables:      {"foreign tables", 0, unsupported, 0},
+	featPublications:       {"publications", 0, unsupported, 0},
+	featEventTriggers:      {"event triggers", 0, unsupported, 0},
+	featForeignServers:     {"foreign servers", 0, unsupported, 0},
+	featRoles:              {"roles", 0, unsupported, 0},
+	featSearchPath:         {"search_path", 0, unsupported, 0},
+	featServerSettings:     {"server settings", 0, unsupported, 0},
+	featExtensions:         {"extensions", 0, unsupported, 0},
+	featPartitions:         {"table partitions", 0, unsupported, 0},
+	featNoInherit:          {"NO INHERIT constraints", 0, unsupported, 0},
+	featExcludeConstraints: {"exclusion constraints", 0, unsupported, unsupported},
+	featHashSharded:        {"hash-sharded indexes", unsupported, 0, unsupported},
+	featLocality:           {"table localities", unsupported, 21_01_00, unsupported},
+	featRowTTL:             {"row-level TTL", unsupported, 22_02_00, unsupported},
+	featAlterColumnType:    {"column type changes", 0, 0, 2_20_00},
+	featColocation:         {"colocated tables", unsupported, unsupported, 0},
+	featTabletSplit:        {"tablet splitting clauses", unsupported, unsupported, 0},
+}
+
+// supports reports if the connected server supports the given feature.
+func (c *conn) supports(f feature) bool {
+	m, ok := features[f]
+	switch {
+	case !ok:
+		return false
+	case c.crdb:
+		return m.crdb != unsupported && c.crdbVersion >= m.crdb
+	case c.yb:
+		return m.yb != unsupported && c.ybVersion >= m.yb
+	default:
+		return m.pg != unsupported && c.version >= m.pg
+	}
+}
+
+// checkFeature returns an error if the given feature
+// is not supported by the connected server.
+func (c *conn) checkFeature(f feature) error {
+	if c.supports(f) {
+		return nil
+	}
+	m := features[f]
+	switch {
+	case c.crdb && m.crdb == unsupported:
+		return fmt.Errorf("cockroach: %s are not supported", m.name)
+	case c.crdb:
+		return fmt.Errorf("cockroach: %s are supported only by CockroachDB v%d.%d and above", m.name, m.crdb/1_00_00, m.crdb/100%100)
+	case c.yb && m.yb == unsupported:
+		return fmt.Errorf("yugabyte: %s are not supported", m.name)
+	case c.yb:
+		return fmt.Errorf("yugabyte: %s are supported only by YugabyteDB v%d.%d and above", m.name, m.yb/1_00_00, m.yb/100%100)
+	case m.pg == unsupported && m.crdb == unsupported:
+		return fmt.Errorf("postgres: %s are supported only by YugabyteDB", m.name)
+	case m.pg == unsupported:
+		return fmt.Errorf("postgres: %s are supported only by CockroachDB", m.name)
+	default:
+		return fmt.Errorf("postgres: %s are supported only by PostgreSQL %d and above", m.name, m.pg/1_00_00)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -51,12 +52,80 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			if err := i.inspectEnums(ctx, r); err != nil {
 				return nil, err
 			}
+			if i.supports(featRangeTypes) {
+				if err := i.inspectRanges(ctx, r); err != nil {
+					return nil, err
+				}
+			}
 		}
 		if mode.Is(schema.InspectTables) {
 			if err := i.inspectTables(ctx, r, nil); err != nil {
 				return nil, err
 			}
 			sqlx.LinkSchemaTables(schemas)
+			if opts.Stats && i.supports(featStats) {
+				if err := i.inspectStats(ctx, r); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if mode.Is(schema.InspectViews) && i.supports(featMatViews) {
+			if err := i.inspectMatViews(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		if mode.Is(schema.InspectFuncs) && i.supports(featTriggerFuncs) {
+			if err := i.inspectTriggerFuncs(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		// Sequences are inspected after tables, to link them to their owner columns.
+		if mode.Is(schema.InspectObjects) && i.supports(featSequences) {
+			if err := i.inspectSequences(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		if mode.Is(schema.InspectObjects) && i.supports(featForeignTables) {
+			if err := i.inspectForeignTables(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Publications, event triggers and foreign servers are database-wide, and publications
+	// may list tables from any schema. Hence, they are skipped in case the inspection is limited to specific schemas.
+	if mode.Is(schema.InspectObjects) && len(opts.Schemas) == 0 {
+		if i.supports(featPublications) {
+			if err := i.inspectPublications(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		if i.supports(featEventTriggers) {
+			if err := i.inspectEventTriggers(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		if i.supports(featForeignServers) {
+			if err := i.inspectForeignServers(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Roles, ownership and default privileges are inspected only if requested explicitly.
+	if opts.Roles && i.supports(featRoles) {
+		if len(schemas) > 0 {
+			if err := i.inspectOwners(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+		// Roles are cluster-wide, and hence, skipped in case
+		// the inspection is limited to specific schemas.
+		if len(opts.Schemas) == 0 {
+			if err := i.inspectRoles(ctx, r); err != nil {
+				return nil, err
+			}
+			if err := i.inspectDefaultPrivileges(ctx, r); err != nil {
+				return nil, err
+			}
 		}
 	}
 	return schema.ExcludeRealm(r, opts.Exclude)
@@ -66,8 +135,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 // referenced objects in the public schema (or any other default search_path) are returned
 // qualified in the inspection.
 func (i *inspect) noSearchPath(ctx context.Context) (func() error, error) {
-	if i.crdb {
-		// Skip logic for CockroachDB.
+	if !i.supports(featSearchPath) {
 		return func() error { return nil }, nil
 	}
 	rows, err := i.QueryContext(ctx, "SELECT current_setting('search_path'), set_config('search_path', '', false)")
@@ -121,12 +189,52 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 		if err := i.inspectEnums(ctx, r); err != nil {
 			return nil, err
 		}
+		if i.supports(featRangeTypes) {
+			if err := i.inspectRanges(ctx, r); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if mode.Is(schema.InspectTables) {
 		if err := i.inspectTables(ctx, r, opts); err != nil {
 			return nil, err
 		}
 		sqlx.LinkSchemaTables(schemas)
+		if opts.Stats && i.supports(featStats) {
+			if err := i.inspectStats(ctx, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Materialized views are skipped in case the inspection is limited to specific tables.
+	if mode.Is(schema.InspectViews) && i.supports(featMatViews) && len(opts.Tables) == 0 {
+		if err := i.inspectMatViews(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	// Trigger functions are skipped in case the inspection is limited to specific tables.
+	if mode.Is(schema.InspectFuncs) && i.supports(featTriggerFuncs) && len(opts.Tables) == 0 {
+		if err := i.inspectTriggerFuncs(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	// Sequences are skipped in case the inspection is limited to specific tables.
+	if mode.Is(schema.InspectObjects) && i.supports(featSequences) && len(opts.Tables) == 0 {
+		if err := i.inspectSequences(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	// Foreign tables are skipped in case the inspection is limited to specific tables.
+	// Their servers are realm-level objects, and are referenced by name.
+	if mode.Is(schema.InspectObjects) && i.supports(featForeignTables) && len(opts.Tables) == 0 {
+		if err := i.inspectForeignTables(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Roles && i.supports(featRoles) {
+		if err := i.inspectOwners(ctx, r); err != nil {
+			return nil, err
+		}
 	}
 	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
 }
@@ -142,6 +250,9 @@ func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *sche
 		if err := i.columns(ctx, s); err != nil {
 			return err
 		}
+		if err := i.identitySequences(ctx, s); err != nil {
+			return err
+		}
 		if err := i.indexes(ctx, s); err != nil {
 			return err
 		}
@@ -154,6 +265,21 @@ func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *sche
 		if err := i.checks(ctx, s); err != nil {
 			return err
 		}
+		if err := i.constraintComments(ctx, s); err != nil {
+			return err
+		}
+		var withP []*schema.Table
+		for _, t := range s.Tables {
+			if sqlx.Has(t.Attrs, &hasPolicies{}) {
+				t.Attrs = schema.RemoveAttr[*hasPolicies](t.Attrs)
+				withP = append(withP, t)
+			}
+		}
+		if len(withP) > 0 {
+			if err := i.policies(ctx, s, withP); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -208,10 +334,33 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 				exprs: partexprs.String,
 			})
 		}
+		if sqlx.ValidString(extra) {
+			var attrs tableAttrs
+			if err := json.Unmarshal([]byte(extra.String), &attrs); err != nil {
+				return fmt.Errorf("postgres: unmarshaling table %q attributes: %w", t.Name, err)
+			}
+			if attrs.RLS || attrs.ForceRLS {
+				t.AddAttrs(&RowSecurity{Enabled: attrs.RLS, Enforced: attrs.ForceRLS})
+			}
+			if attrs.Policies > 0 {
+				t.AddAttrs(&hasPolicies{})
+			}
+		}
 	}
 	return rows.Err()
 }
 
+// tableAttrs holds the extra table attributes returned by the tables query.
+type tableAttrs struct {
+	RLS      bool `json:"rls"`
+	ForceRLS bool `json:"force_rls"`
+	Policies int  `json:"policies"`
+}
+
+// hasPolicies is a temporary attribute that marks tables with policies
+// to be inspected. It is removed after the policies are inspected.
+type hasPolicies struct{ schema.Attr }
+
 // columns queries and appends the columns of the given table.
 func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
 	query := columnsQuery
@@ -366,6 +515,11 @@ func (i *inspect) underlyingType(s *schema.Schema, u *UserDefinedType) schema.Ty
 				return d
 			} else if c, ok := o.(*CompositeType); ok && c.T == name {
 				return c
+			} else if r, ok := o.(*CustomRangeType); ok && (r.T == name || r.MultirangeName() == name) {
+				if r.T == name {
+					return r
+				}
+				return r.MultirangeType()
 			}
 		}
 	}
@@ -394,13 +548,17 @@ func (i *inspect) inspectEnums(ctx context.Context, r *schema.Realm) error {
 		var (
 			id       int64
 			ns, n, v string
+			comment  sql.NullString
 		)
-		if err := rows.Scan(&ns, &id, &n, &v); err != nil {
+		if err := rows.Scan(&ns, &id, &n, &v, &comment); err != nil {
 			return fmt.Errorf("postgres: scanning enum label: %w", err)
 		}
 		e, ok := ids[id]
 		if !ok {
 			e = &schema.EnumType{T: n}
+			if sqlx.ValidString(comment) {
+				e.Attrs = append(e.Attrs, &schema.Comment{Text: comment.String})
+			}
 			ids[id] = e
 		}
 		if e.Schema == nil {
@@ -508,12 +666,19 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows, scope queryScope)
 				idx.Attrs = append(idx.Attrs, &schema.Comment{Text: comment.String})
 			}
 			if sqlx.ValidString(constraints) {
-				var m map[string]string
+				var m map[string]struct {
+					T          string `json:"type"`
+					Deferrable bool   `json:"deferrable"`
+					Deferred   bool   `json:"deferred"`
+				}
 				if err := json.Unmarshal([]byte(constraints.String), &m); err != nil {
 					return fmt.Errorf("postgres: unmarshaling index constraints: %w", err)
 				}
-				for n, t := range m {
-					idx.AddAttrs(&Constraint{N: n, T: t})
+				for n, c := range m {
+					idx.AddAttrs(&Constraint{N: n, T: c.T})
+					if c.Deferrable {
+						idx.AddAttrs(&Deferrable{InitiallyDeferred: c.Deferred})
+					}
 				}
 			}
 			if sqlx.ValidString(pred) {
@@ -665,7 +830,18 @@ func (i *inspect) fks(ctx context.Context, s *schema.Schema) error {
 		return fmt.Errorf("postgres: querying schema %q foreign keys: %w", s.Name, err)
 	}
 	defer rows.Close()
-	if err := sqlx.TypedSchemaFKs[*ReferenceOption](s, rows); err != nil {
+	if err := sqlx.TypedSchemaFKs[*ReferenceOption](s, rows, &sqlx.FKAttrScanner{
+		Columns: func() []any {
+			return []any{new(bool), new(bool)}
+		},
+		// Foreign keys are scanned row per column.
+		ScanFunc: func(fk *schema.ForeignKey, vs []any) error {
+			if *vs[0].(*bool) && !sqlx.Has(fk.Attrs, &Deferrable{}) {
+				fk.Attrs = append(fk.Attrs, &Deferrable{InitiallyDeferred: *vs[1].(*bool)})
+			}
+			return nil
+		},
+	}); err != nil {
 		return fmt.Errorf("postgres: %w", err)
 	}
 	return rows.Err()
@@ -684,6 +860,102 @@ func (i *inspect) checks(ctx context.Context, s *schema.Schema) error {
 	return rows.Err()
 }
 
+// constraintComments queries and sets the comments of the foreign-key and check
+// constraints of the schema tables. The query is skipped in case the tables have no
+// such constraints. Note, comments of unique and exclusion constraints are set on
+// their underlying indexes, and are inspected along with them.
+func (i *inspect) constraintComments(ctx context.Context, s *schema.Schema) error {
+	if !slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		return len(t.ForeignKeys) > 0 || slices.ContainsFunc(t.Attrs, func(a schema.Attr) bool {
+			_, ok := a.(*schema.Check)
+			return ok
+		})
+	}) {
+		return nil
+	}
+	rows, err := i.querySchema(ctx, constraintCommentsQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q constraint comments: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, typ, comment string
+		if err := rows.Scan(&table, &name, &typ, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning constraint comment: %w", err)
+		}
+		t, ok := s.Table(table)
+		if !ok {
+			continue
+		}
+		switch typ {
+		case "f":
+			if fk, ok := t.ForeignKey(name); ok {
+				fk.Attrs = append(fk.Attrs, &schema.Comment{Text: comment})
+			}
+		case "c":
+			for _, a := range t.Attrs {
+				if c, ok := a.(*schema.Check); ok && c.Name == name {
+					c.Attrs = append(c.Attrs, &schema.Comment{Text: comment})
+				}
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// identitySequences queries and sets the options of the sequences that back the
+// identity columns of the schema. Options that are set to the defaults of their
+// types are left unset.
+func (i *inspect) identitySequences(ctx context.Context, s *schema.Schema) error {
+	if !i.supports(featIdentitySequences) || !slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		return slices.ContainsFunc(t.Columns, func(c *schema.Column) bool {
+			return sqlx.Has(c.Attrs, &Identity{})
+		})
+	}) {
+		return nil
+	}
+	rows, err := i.querySchema(ctx, identitySequencesQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q identity sequences: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			table, column string
+			lo, hi, cache int64
+			cycle         bool
+		)
+		if err := rows.Scan(&table, &column, &lo, &hi, &cache, &cycle); err != nil {
+			return fmt.Errorf("postgres: scanning identity sequence: %w", err)
+		}
+		t, ok := s.Table(table)
+		if !ok {
+			continue
+		}
+		c, ok := t.Column(column)
+		if !ok {
+			continue
+		}
+		id := &Identity{}
+		if !sqlx.Has(c.Attrs, id) || id.Sequence == nil {
+			continue
+		}
+		seq := id.Sequence
+		_, d := identityOptions(&Identity{Sequence: &Sequence{Increment: seq.Increment}}, c)
+		if lo != d.min {
+			seq.Min = &lo
+		}
+		if hi != d.max {
+			seq.Max = &hi
+		}
+		if cache != d.cache {
+			seq.Cache = cache
+		}
+		seq.Cycle = cycle
+	}
+	return rows.Err()
+}
+
 // addChecks scans the rows and adds the checks to the table.
 func (i *inspect) addChecks(s *schema.Schema, rows *sql.Rows) error {
 	type tc struct{ t, n string }
@@ -882,6 +1154,14 @@ type (
 		T           string // Formatted type (e.g. int[]).
 	}
 
+	// VectorType defines a vector type provided by the pgvector extension.
+	// https://github.com/pgvector/pgvector
+	VectorType struct {
+		schema.Type
+		T   string // vector, halfvec or sparsevec.
+		Dim int    // Optional number of dimensions.
+	}
+
 	// BitType defines a bit type.
 	// https://postgresql.org/docs/current/datatype-bit.html
 	BitType struct {
@@ -996,6 +1276,13 @@ type (
 		T string // c, f, p, u, t, x.
 	}
 
+	// Deferrable describes the DEFERRABLE attribute of UNIQUE, EXCLUDE and
+	// FOREIGN KEY constraints. Constraints without it are NOT DEFERRABLE.
+	Deferrable struct {
+		schema.Attr
+		InitiallyDeferred bool // INITIALLY DEFERRED. Defaults to INITIALLY IMMEDIATE.
+	}
+
 	// Operator describes an operator.
 	// https://www.postgresql.org/docs/current/sql-createoperator.html
 	Operator struct {
@@ -1077,6 +1364,30 @@ type (
 		// PagesPerRange defines pages_per_range storage
 		// parameter for BRIN indexes. Defaults to 128.
 		PagesPerRange int64
+		// FillFactor defines the fillfactor storage parameter for B-tree, hash,
+		// GiST and SP-GiST indexes. Zero means the default of the index type.
+		FillFactor int64
+		// Deduplicate defines the deduplicate_items storage parameter
+		// for B-tree indexes. Can be ON (default) or OFF.
+		Deduplicate string
+		// Buffering defines the buffering storage parameter for
+		// GiST indexes. Can be ON, OFF or AUTO (default).
+		Buffering string
+		// FastUpdate defines the fastupdate storage parameter
+		// for GIN indexes. Can be ON (default) or OFF.
+		FastUpdate string
+		// ListLimit defines the gin_pending_list_limit storage parameter
+		// for GIN indexes in kilobytes. Zero means the server default.
+		ListLimit int64
+		// Lists defines the lists storage parameter for pgvector
+		// IVFFlat indexes. Defaults to 100.
+		Lists int64
+		// M defines the m storage parameter for pgvector HNSW indexes,
+		// that is, the max number of connections per layer. Defaults to 16.
+		M int64
+		// EfConstruction defines the ef_construction storage parameter
+		// for pgvector HNSW indexes. Defaults to 64.
+		EfConstruction int64
 	}
 
 	// IndexInclude describes the INCLUDE clause allows specifying
@@ -1403,23 +1714,77 @@ func newIndexStorage(opts string) (*IndexStorageParams, error) {
 			return nil, fmt.Errorf("invalid index storage parameter: %s", p)
 		}
 		switch kv[0] {
-		case "autosummarize":
-			b, err := strconv.ParseBool(kv[1])
+		case storageParamAutoSum:
+			b, err := parseStorageBool(kv[1])
 			if err != nil {
 				return nil, fmt.Errorf("failed parsing autosummarize %q: %w", kv[1], err)
 			}
 			params.AutoSummarize = b
-		case "pages_per_range":
+		case storageParamPagesRange:
 			i, err := strconv.ParseInt(kv[1], 10, 64)
 			if err != nil {
 				return nil, fmt.Errorf("failed parsing pages_per_range %q: %w", kv[1], err)
 			}
 			params.PagesPerRange = i
+		case storageParamFillFactor:
+			i, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing fillfactor %q: %w", kv[1], err)
+			}
+			params.FillFactor = i
+		case storageParamListLimit:
+			i, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing gin_pending_list_limit %q: %w", kv[1], err)
+			}
+			params.ListLimit = i
+		case storageParamLists, storageParamM, storageParamEf:
+			i, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing %s %q: %w", kv[0], kv[1], err)
+			}
+			switch kv[0] {
+			case storageParamLists:
+				params.Lists = i
+			case storageParamM:
+				params.M = i
+			default:
+				params.EfConstruction = i
+			}
+		case storageParamDedup, storageParamFastUpdate:
+			b, err := parseStorageBool(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("failed parsing %s %q: %w", kv[0], kv[1], err)
+			}
+			v := storageParamOff
+			if b {
+				v = storageParamOn
+			}
+			if kv[0] == storageParamDedup {
+				params.Deduplicate = v
+			} else {
+				params.FastUpdate = v
+			}
+		case storageParamBuffering:
+			params.Buffering = strings.ToUpper(kv[1])
 		}
 	}
 	return params, nil
 }
 
+// parseStorageBool parses a boolean storage parameter. PostgreSQL accepts
+// on/off in addition to the values that are supported by strconv.ParseBool.
+func parseStorageBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return strconv.ParseBool(s)
+	}
+}
+
 // reFmtType extracts the formatted type and an option schema qualifier.
 var reFmtType = regexp.MustCompile(`^(?:(".+"|\w+)\.)?(".+"|\w+)$`)
 
@@ -1446,7 +1811,7 @@ func parseFmtType(t string) (s, n string) {
 
 const (
 	// Query to list runtime parameters.
-	paramsQuery = `SELECT current_setting('server_version_num'), current_setting('default_table_access_method', true), current_setting('crdb_version', true)`
+	paramsQuery = `SELECT current_setting('server_version_num'), current_setting('default_table_access_method', true), current_setting('crdb_version', true), version()`
 
 	// Query to list database schemas.
 	schemasQuery = `
@@ -1521,7 +1886,8 @@ SELECT
 	n.nspname AS schema_name,
 	e.enumtypid AS enum_id,
 	t.typname AS enum_name,
-	e.enumlabel AS enum_value
+	e.enumlabel AS enum_value,
+	obj_description(t.oid, 'pg_type') AS comment
 FROM
 	pg_enum e
 	JOIN pg_type t ON e.enumtypid = t.oid
@@ -1542,7 +1908,9 @@ SELECT
     a2.attname AS referenced_column_name,
     fk.referenced_schema_name,
     fk.confupdtype,
-    fk.confdeltype
+    fk.confdeltype,
+    fk.condeferrable,
+    fk.condeferred
 	FROM 
 	    (
 	    	SELECT
@@ -1557,7 +1925,9 @@ SELECT
 	      		unnest(con.conkey) AS conkey,
 	      		unnest(con.confkey) AS confkey,
 	      		con.confupdtype,
-	      		con.confdeltype
+	      		con.confdeltype,
+	      		con.condeferrable,
+	      		con.condeferred
 	    	FROM pg_constraint con
 	    	JOIN pg_class t1 ON t1.oid = con.conrelid
 	    	JOIN pg_class t2 ON t2.oid = con.confrelid
@@ -1598,6 +1968,55 @@ WHERE
 ORDER BY
 	t1.conname, array_position(t1.conkey, t2.attnum)
 `
+
+	// Query to list the options of the sequences that back identity columns.
+	identitySequencesQuery = `
+SELECT
+	rel.relname AS table_name,
+	att.attname AS column_name,
+	seq.seqmin,
+	seq.seqmax,
+	seq.seqcache,
+	seq.seqcycle
+FROM
+	pg_catalog.pg_attribute att
+	JOIN pg_catalog.pg_class rel
+	ON rel.oid = att.attrelid
+	JOIN pg_catalog.pg_namespace nsp
+	ON nsp.oid = rel.relnamespace
+	JOIN pg_catalog.pg_depend dep
+	ON dep.refobjid = rel.oid AND dep.refobjsubid = att.attnum AND dep.classid = 'pg_catalog.pg_class'::regclass AND dep.deptype = 'i'
+	JOIN pg_catalog.pg_sequence seq
+	ON seq.seqrelid = dep.objid
+WHERE
+	att.attidentity <> ''
+	AND nsp.nspname = $1
+	AND rel.relname IN (%s)
+ORDER BY
+	rel.relname, att.attnum
+`
+
+	// Query to list the comments of foreign-key and check constraints.
+	constraintCommentsQuery = `
+SELECT
+	rel.relname AS table_name,
+	con.conname AS constraint_name,
+	con.contype AS constraint_type,
+	obj_description(con.oid, 'pg_constraint') AS comment
+FROM
+	pg_constraint con
+	JOIN pg_class rel
+	ON rel.oid = con.conrelid
+	JOIN pg_namespace nsp
+	ON nsp.oid = con.connamespace
+WHERE
+	con.contype IN ('f', 'c')
+	AND nsp.nspname = $1
+	AND rel.relname IN (%s)
+	AND obj_description(con.oid, 'pg_constraint') IS NOT NULL
+ORDER BY
+	rel.relname, con.conname
+`
 )
 
 var (
@@ -1639,7 +2058,7 @@ FROM
 	JOIN pg_class t ON t.oid = idx.indrelid
 	JOIN pg_namespace n ON n.oid = t.relnamespace
 	LEFT JOIN (
-	    select conindid, jsonb_object_agg(conname, contype) AS nametypes
+	    select conindid, jsonb_object_agg(conname, jsonb_build_object('type', contype, 'deferrable', condeferrable, 'deferred', condeferred)) AS nametypes
 	    from pg_constraint
 	    group by conindid
 	) con ON con.conindid = idx.indexrelid
@@ -20,14 +20,17 @@ import (
 
 // Single table queries used by the different tests.
 var (
-	queryFKs         = sqltest.Escape(fmt.Sprintf(fksQuery, "$2"))
-	queryEnums       = sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))
-	queryTables      = sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))
-	queryChecks      = sqltest.Escape(fmt.Sprintf(checksQuery, "$2"))
-	queryColumns     = sqltest.Escape(fmt.Sprintf(columnsQuery, "$2"))
-	queryCRDBColumns = sqltest.Escape(fmt.Sprintf(crdbColumnsQuery, "$2"))
-	queryIndexes     = sqltest.Escape(fmt.Sprintf(indexesAbove15, "$2"))
-	queryCRDBIndexes = sqltest.Escape(fmt.Sprintf(crdbIndexesQuery, "$2"))
+	queryFKs                = sqltest.Escape(fmt.Sprintf(fksQuery, "$2"))
+	queryEnums              = sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))
+	queryTables             = sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))
+	queryChecks             = sqltest.Escape(fmt.Sprintf(checksQuery, "$2"))
+	queryColumns            = sqltest.Escape(fmt.Sprintf(columnsQuery, "$2"))
+	queryCRDBColumns        = sqltest.Escape(fmt.Sprintf(crdbColumnsQuery, "$2"))
+	queryIndexes            = sqltest.Escape(fmt.Sprintf(indexesAbove15, "$2"))
+	queryCRDBIndexes        = sqltest.Escape(fmt.Sprintf(crdbIndexesQuery, "$2"))
+	queryConstraintComments = sqltest.Escape(fmt.Sprintf(constraintCommentsQuery, "$2"))
+	queryIdentitySequences  = sqltest.Escape(fmt.Sprintf(identitySequencesQuery, "$2"))
+	queryRanges             = sqltest.Escape(fmt.Sprintf(rangesQuery, "$1"))
 )
 
 func TestDriver_InspectTable(t *testing.T) {
@@ -42,12 +45,13 @@ func TestDriver_InspectTable(t *testing.T) {
 				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(enumsQuery, "$1"))).
 					WithArgs("public").
 					WillReturnRows(sqltest.Rows(`
- schema_name | enum_id | type    | enum_value
--------------+---------+---------+------------
- public      |   16774 |  state  | on
- public      |   16774 |  state  | off
- public      |   16775 |  status | unknown
+ schema_name | enum_id | type    | enum_value | comment
+-------------+---------+---------+------------+---------
+ public      |   16774 |  state  | on         | nil
+ public      |   16774 |  state  | off        | nil
+ public      |   16775 |  status | unknown    | unknown status
 `))
+				m.noRanges()
 				m.tableExists("public", "users", true)
 				m.ExpectQuery(queryColumns).
 					WithArgs("public", "users").
@@ -98,6 +102,13 @@ func TestDriver_InspectTable(t *testing.T) {
  users       |  c40         | smallint                    | int4                | NO          | nextval('"Users_c40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |    23 |  
  users       |  c41         | smallint                    | int4                | NO          | nextval('foo."T_C40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |    23 |  
  users       |  c42         | smallint                    | int4                | NO          | nextval('"F"."T_C40_seq"'::regclass)   |                          |                32 |                    |             0 |                     |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |    23 |  
+`))
+				m.ExpectQuery(queryIdentitySequences).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
+ table_name | column_name | seqmin |       seqmax        | seqcache | seqcycle
+------------+-------------+--------+---------------------+----------+----------
+ users      | id          |      1 | 9223372036854775807 |       10 | f
 `))
 				m.noIndexes()
 				m.noFKs()
@@ -107,9 +118,9 @@ func TestDriver_InspectTable(t *testing.T) {
 				require.NoError(err)
 				require.Equal("users", t.Name)
 				stateE := &schema.EnumType{T: "state", Values: []string{"on", "off"}, Schema: t.Schema}
-				statusE := &schema.EnumType{T: "status", Values: []string{"unknown"}, Schema: t.Schema}
+				statusE := &schema.EnumType{T: "status", Values: []string{"unknown"}, Schema: t.Schema, Attrs: []schema.Attr{&schema.Comment{Text: "unknown status"}}}
 				expected := []*schema.Column{
-					{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: "bigint"}}, Attrs: []schema.Attr{&Identity{Generation: "BY DEFAULT", Sequence: &Sequence{Start: 100, Increment: 1, Last: 1}}}},
+					{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: "bigint"}}, Attrs: []schema.Attr{&Identity{Generation: "BY DEFAULT", Sequence: &Sequence{Start: 100, Increment: 1, Last: 1, Cache: 10}}}},
 					{Name: "rank", Type: &schema.ColumnType{Raw: "integer", Null: true, Type: &schema.IntegerType{T: "integer"}}, Attrs: []schema.Attr{&schema.Comment{Text: "rank"}}},
 					{Name: "c1", Type: &schema.ColumnType{Raw: "smallint", Type: &schema.IntegerType{T: "smallint"}}, Default: &schema.Literal{V: "1000"}},
 					{Name: "c2", Type: &schema.ColumnType{Raw: "bit", Type: &BitType{T: "bit", Len: 1}}},
@@ -164,6 +175,7 @@ func TestDriver_InspectTable(t *testing.T) {
 			name: "table indexes",
 			before: func(m mock) {
 				m.noEnums()
+				m.noRanges()
 				m.tableExists("public", "users", true)
 				m.ExpectQuery(queryColumns).
 					WithArgs("public", "users").
@@ -182,8 +194,8 @@ users      | ts          | tsvector            | tsvector  |  NO          |
 ----------------+-----------------+-------------+-------------+----------+---------+--------+--------+-----------------+-----------------------+---------------------------+------+-------------+------------+-----------+---------------------------------------+-------------------+-------------------+-----------------+----------------+---------------------
 users           | idx             | hash        |             | f        | f       | f      |        |                 |                       | "left"((c11)::text, 100)  | t    | t           | f          | boring    |                                       |     int4_ops      |     public        |        t        |                | f
 users           | idx1            | btree       |             | f        | f       | f      |        |                 | (id <> NULL::integer) | "left"((c11)::text, 100)  | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | t1_c1_key       | btree       | c1          | f        | f       | t      |        | {"name": "u"}   |                       | c1                        | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | t1_pkey         | btree       | id          | f        | t       | t      |        | {"t_pkey": "p"} |                       | id                        | t    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
+users           | t1_c1_key       | btree       | c1          | f        | f       | t      |        | {"name": {"type": "u", "deferrable": true, "deferred": false}}   |                       | c1                        | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
+users           | t1_pkey         | btree       | id          | f        | t       | t      |        | {"t_pkey": {"type": "p", "deferrable": false, "deferred": false}} |                       | id                        | t    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
 users           | idx4            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
 users           | idx4            | btree       | id          | f        | f       | t      |        |                 |                       | id                        | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f
 users           | idx5            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
@@ -212,7 +224,7 @@ users           | tsx             | gist        | ts          | f        | f
 				indexes := []*schema.Index{
 					{Name: "idx", Table: t, Attrs: []schema.Attr{&IndexType{T: "hash"}, &schema.Comment{Text: "boring"}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `"left"((c11)::text, 100)`}, Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
 					{Name: "idx1", Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &IndexPredicate{P: `(id <> NULL::integer)`}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `"left"((c11)::text, 100)`}, Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
-					{Name: "t1_c1_key", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &Constraint{N: "name", T: "u"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1], Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
+					{Name: "t1_c1_key", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &Constraint{N: "name", T: "u"}, &Deferrable{}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1], Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
 					{Name: "idx4", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
 					{Name: "idx5", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, X: &schema.RawExpr{X: `coalesce(parent_id, 0)`}}}},
 					{Name: "idx6", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "brin"}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}}},
@@ -239,6 +251,7 @@ users           | tsx             | gist        | ts          | f        | f
 			name: "fks",
 			before: func(m mock) {
 				m.noEnums()
+				m.noRanges()
 				m.tableExists("public", "users", true)
 				m.ExpectQuery(queryColumns).
 					WithArgs("public", "users").
@@ -253,15 +266,22 @@ users      | uid         | integer             | int       | NO          |
 				m.ExpectQuery(queryFKs).
 					WithArgs("public", "users").
 					WillReturnRows(sqltest.Rows(`
-constraint_name | table_name | column_name | table_schema | referenced_table_name | referenced_column_name | referenced_schema_name | confupdtype | condeltype
------------------+------------+-------------+--------------+-----------------------+------------------------+------------------------+-------------+-------------
-multi_column    | users      | id          | public       | t1                    | gid                    | public                 | a            | c
-multi_column    | users      | id          | public       | t1                    | xid                    | public                 | a            | c
-multi_column    | users      | oid         | public       | t1                    | gid                    | public                 | a            | c
-multi_column    | users      | oid         | public       | t1                    | xid                    | public                 | a            | c
-self_reference  | users      | uid         | public       | users                 | id                     | public                 | a            | c
+constraint_name | table_name | column_name | table_schema | referenced_table_name | referenced_column_name | referenced_schema_name | confupdtype | condeltype | condeferrable | condeferred
+-----------------+------------+-------------+--------------+-----------------------+------------------------+------------------------+-------------+------------+---------------+-------------
+multi_column    | users      | id          | public       | t1                    | gid                    | public                 | a            | c          | f             | f
+multi_column    | users      | id          | public       | t1                    | xid                    | public                 | a            | c          | f             | f
+multi_column    | users      | oid         | public       | t1                    | gid                    | public                 | a            | c          | f             | f
+multi_column    | users      | oid         | public       | t1                    | xid                    | public                 | a            | c          | f             | f
+self_reference  | users      | uid         | public       | users                 | id                     | public                 | a            | c          | t             | t
 `))
 				m.noChecks()
+				m.ExpectQuery(queryConstraintComments).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
+table_name | constraint_name | constraint_type | comment
+-----------+-----------------+-----------------+---------
+users      | self_reference  | f               | parent user
+`))
 			},
 			expect: func(require *require.Assertions, t *schema.Table, err error) {
 				require.NoError(err)
@@ -269,7 +289,7 @@ self_reference  | users      | uid         | public       | users
 				require.Equal("public", t.Schema.Name)
 				fks := []*schema.ForeignKey{
 					{Symbol: "multi_column", Table: t, OnUpdate: schema.NoAction, OnDelete: schema.Cascade, RefTable: &schema.Table{Name: "t1", Schema: t.Schema}, RefColumns: []*schema.Column{{Name: "gid"}, {Name: "xid"}}},
-					{Symbol: "self_reference", Table: t, OnUpdate: schema.NoAction, OnDelete: schema.Cascade, RefTable: t},
+					{Symbol: "self_reference", Table: t, OnUpdate: schema.NoAction, OnDelete: schema.Cascade, RefTable: t, Attrs: []schema.Attr{&Deferrable{InitiallyDeferred: true}, &schema.Comment{Text: "parent user"}}},
 				}
 				columns := []*schema.Column{
 					{Name: "id", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: "integer"}}, ForeignKeys: fks[0:1]},
@@ -287,6 +307,7 @@ self_reference  | users      | uid         | public       | users
 			name: "check",
 			before: func(m mock) {
 				m.noEnums()
+				m.noRanges()
 				m.tableExists("public", "users", true)
 				m.ExpectQuery(queryColumns).
 					WithArgs("public", "users").
@@ -312,6 +333,13 @@ users        | users_check        | ((c2 + c1) > 2)         | c1          | {2,1
 users        | users_check1       | (((c2 + c1) + c3) > 10) | c2          | {2,1,3}        | f
 users        | users_check1       | (((c2 + c1) + c3) > 10) | c1          | {2,1,3}        | f
 users        | users_check1       | (((c2 + c1) + c3) > 10) | c3          | {2,1,3}        | f
+`))
+				m.ExpectQuery(queryConstraintComments).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
+table_name | constraint_name | constraint_type | comment
+-----------+-----------------+-----------------+---------
+users      | boring          | c               | not so boring
 `))
 			},
 			expect: func(require *require.Assertions, t *schema.Table, err error) {
@@ -319,7 +347,7 @@ users        | users_check1       | (((c2 + c1) + c3) > 10) | c3          | {2,1
 				require.Equal("users", t.Name)
 				require.Equal("public", t.Schema.Name)
 				checks := []schema.Attr{
-					&schema.Check{Name: "boring", Expr: "(c1 > 1)", Attrs: []schema.Attr{&CheckColumns{Columns: []string{"c1"}}, &NoInherit{}}},
+					&schema.Check{Name: "boring", Expr: "(c1 > 1)", Attrs: []schema.Attr{&CheckColumns{Columns: []string{"c1"}}, &NoInherit{}, &schema.Comment{Text: "not so boring"}}},
 					&schema.Check{Name: "users_c2_check", Expr: "(c2 > 0)", Attrs: []schema.Attr{&CheckColumns{Columns: []string{"c2"}}}},
 					&schema.Check{Name: "users_c2_check1", Expr: "(c2 > 0)", Attrs: []schema.Attr{&CheckColumns{Columns: []string{"c2"}}}},
 					&schema.Check{Name: "users_check", Expr: "((c2 + c1) > 2)", Attrs: []schema.Attr{&CheckColumns{Columns: []string{"c2", "c1"}}}},
@@ -375,6 +403,7 @@ func TestDriver_InspectPartitionedTable(t *testing.T) {
  public      | nil
 `))
 	mk.noEnums()
+	mk.noRanges()
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))).
 		WithArgs("public").
 		WillReturnRows(sqltest.Rows(`
@@ -438,9 +467,9 @@ func TestDriver_InspectCRDBSchema(t *testing.T) {
 	mk := mock{m}
 	mk.ExpectQuery(sqltest.Escape(paramsQuery)).
 		WillReturnRows(sqltest.Rows(`
-  version       |  am  | crdb
-----------------|------|-----
- 130000         | heap | cockroach
+  version       |  am  | crdb | desc
+----------------|------|-----|-----
+ 130000         | heap | cockroach | PostgreSQL 13.0
 `))
 	drv, err := Open(db)
 	require.NoError(t, err)
@@ -717,9 +746,9 @@ type mock struct {
 func (m mock) version(version string) {
 	m.ExpectQuery(sqltest.Escape(paramsQuery)).
 		WillReturnRows(sqltest.Rows(`
-  setting       |  am  | crdb
-----------------|------|-----
- ` + version + `| heap | NULL
+  setting       |  am  | crdb | desc
+----------------|------|-----|-----
+ ` + version + `| heap | NULL | PostgreSQL 13.0 on x86_64-pc-linux-gnu
 `))
 }
 
@@ -752,3 +781,24 @@ func (m mock) noEnums() {
 	m.ExpectQuery(queryEnums).
 		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
 }
+
+func (m mock) noRanges() {
+	m.ExpectQuery(queryRanges).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "type_name", "subtype", "subtype_opclass", "collation", "canonical", "subtype_diff", "multirange_name", "comment"}))
+}
+
+func TestIndexStorageParams(t *testing.T) {
+	p, err := newIndexStorage("{fillfactor=70,deduplicate_items=off,buffering=auto,fastupdate=on,gin_pending_list_limit=1024,autosummarize=on,pages_per_range=16}")
+	require.NoError(t, err)
+	require.Equal(t, &IndexStorageParams{FillFactor: 70, Deduplicate: "OFF", Buffering: "AUTO", FastUpdate: "ON", ListLimit: 1024, AutoSummarize: true, PagesPerRange: 16}, p)
+	p, err = newIndexStorage("{lists=50}")
+	require.NoError(t, err)
+	require.Equal(t, &IndexStorageParams{Lists: 50}, p)
+	p, err = newIndexStorage("{m=32,ef_construction=128}")
+	require.NoError(t, err)
+	require.Equal(t, &IndexStorageParams{M: 32, EfConstruction: 128}, p)
+	_, err = newIndexStorage("{fillfactor=high}")
+	require.Error(t, err)
+	_, err = newIndexStorage("{fastupdate}")
+	require.EqualError(t, err, "invalid index storage parameter: fastupdate")
+}
@@ -0,0 +1,662 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+// MaterializedView represents a PostgreSQL materialized view. Unlike regular views,
+// materialized views persist their result set, can be indexed, and must be refreshed
+// to reflect changes in the underlying tables.
+type MaterializedView struct {
+	schema.Object
+	Name    string
+	Schema  *schema.Schema
+	Def     string // The query (SELECT statement) of the view.
+	Columns []*schema.Column
+	Indexes []*schema.Index
+	NoData  bool          // The view was created (or refreshed) WITH NO DATA.
+	Attrs   []schema.Attr // Attributes, like comments.
+}
+
+// Column returns the column with the given name.
+func (v *MaterializedView) Column(name string) (*schema.Column, bool) {
+	for _, c := range v.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Index returns the index with the given name.
+func (v *MaterializedView) Index(name string) (*schema.Index, bool) {
+	for _, idx := range v.Indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return nil, false
+}
+
+// SetComment sets or updates the comment of the view.
+func (v *MaterializedView) SetComment(c string) *MaterializedView {
+	schema.ReplaceOrAppend(&v.Attrs, &schema.Comment{Text: c})
+	return v
+}
+
+// RefreshConcurrently reports if the view can be refreshed using the
+// "REFRESH MATERIALIZED VIEW CONCURRENTLY" command. That is, the view is
+// populated, and it has at least one UNIQUE index that uses only column
+// names and covers all rows (i.e., has no WHERE clause).
+func (v *MaterializedView) RefreshConcurrently() bool {
+	if v.NoData {
+		return false
+	}
+	for _, idx := range v.Indexes {
+		if !idx.Unique || len(idx.Parts) == 0 || sqlx.Has(idx.Attrs, &IndexPredicate{}) {
+			continue
+		}
+		colsOnly := true
+		for _, p := range idx.Parts {
+			colsOnly = colsOnly && p.C != nil
+		}
+		if colsOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// SpecType returns the type of the view used in HCL and exclude patterns.
+func (*MaterializedView) SpecType() string { return "materialized" }
+
+// SpecName returns the name of the view used in HCL and exclude patterns.
+func (v *MaterializedView) SpecName() string { return v.Name }
+
+// DependsOn implements the sqlx.Depender interface. The definition of a materialized
+// view is opaque to the planner, therefore, its creation or modification is planned
+// after all table changes in the same schema.
+func (v *MaterializedView) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddTable:
+		return sqlx.SameSchema(o.T.Schema, v.Schema)
+	case *schema.ModifyTable:
+		return sqlx.SameSchema(o.T.Schema, v.Schema)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. Dropping a materialized view
+// is planned before dropping or modifying the tables in the same schema.
+func (v *MaterializedView) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.DropTable:
+		return sqlx.SameSchema(o.T.Schema, v.Schema)
+	case *schema.ModifyTable:
+		return sqlx.SameSchema(o.T.Schema, v.Schema)
+	}
+	return false
+}
+
+// table returns a table representation of the view, used for
+// sharing the index and column logic with regular tables.
+func (v *MaterializedView) table() *schema.Table {
+	return &schema.Table{
+		Name:    v.Name,
+		Schema:  v.Schema,
+		Columns: v.Columns,
+		Indexes: v.Indexes,
+		Attrs:   v.Attrs,
+	}
+}
+
+// inspectMatViews queries and appends the materialized views of the given realm.
+func (i *inspect) inspectMatViews(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(matViewsQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying materialized views: %w", err)
+	}
+	defer rows.Close()
+	views := make(map[*schema.Schema][]*MaterializedView)
+	for rows.Next() {
+		var (
+			populated              bool
+			ns, name, def, comment sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &def, &populated, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning materialized view: %w", err)
+		}
+		s, ok := r.Schema(ns.String)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for materialized view %q was not found in realm", ns.String, name.String)
+		}
+		v := &MaterializedView{Name: name.String, Schema: s, Def: def.String, NoData: !populated}
+		if sqlx.ValidString(comment) {
+			v.SetComment(comment.String)
+		}
+		s.AddObjects(v)
+		views[s] = append(views[s], v)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(views[s]) == 0 {
+			continue
+		}
+		if err := i.matViewColumns(ctx, s, views[s]); err != nil {
+			return err
+		}
+		if err := i.matViewIndexes(ctx, s, views[s]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matViewColumns queries and appends the columns of the given views.
+func (i *inspect) matViewColumns(ctx context.Context, s *schema.Schema, views []*MaterializedView) error {
+	rows, err := i.queryMatViews(ctx, matViewColumnsQuery, s, views)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q materialized view columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var view, name, typ, comment sql.NullString
+		if err := rows.Scan(&view, &name, &typ, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning materialized view columns: %w", err)
+		}
+		v, ok := matViewByName(views, view.String)
+		if !ok {
+			return fmt.Errorf("postgres: materialized view %q was not found in schema", view.String)
+		}
+		t, err := i.parseType(s, typ.String)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing column %q type in materialized view %q: %w", name.String, v.Name, err)
+		}
+		c := &schema.Column{
+			Name: name.String,
+			Type: &schema.ColumnType{Raw: typ.String, Type: t, Null: true},
+		}
+		if sqlx.ValidString(comment) {
+			c.SetComment(comment.String)
+		}
+		v.Columns = append(v.Columns, c)
+	}
+	return rows.Err()
+}
+
+// matViewIndexes queries and appends the indexes of the given views.
+func (i *inspect) matViewIndexes(ctx context.Context, s *schema.Schema, views []*MaterializedView) error {
+	rows, err := i.queryMatViews(ctx, i.indexesQuery(), s, views)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q materialized view indexes: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := i.addIndexes(s, rows, queryScope{
+		hasT: func(tv string) bool {
+			_, ok := matViewByName(views, tv)
+			return ok
+		},
+		setPK: func(tv string, _ *schema.Index) error {
+			return fmt.Errorf("postgres: unexpected primary key on materialized view %q", tv)
+		},
+		addIndex: func(tv string, idx *schema.Index) error {
+			v, ok := matViewByName(views, tv)
+			if !ok {
+				return fmt.Errorf("postgres: materialized view %q for index was not found in schema", tv)
+			}
+			v.Indexes = append(v.Indexes, idx)
+			return nil
+		},
+		column: func(tv, name string) (*schema.Column, bool) {
+			if v, ok := matViewByName(views, tv); ok {
+				return v.Column(name)
+			}
+			return nil, false
+		},
+	}); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+func (i *inspect) queryMatViews(ctx context.Context, query string, s *schema.Schema, views []*MaterializedView) (*sql.Rows, error) {
+	args := []any{s.Name}
+	for _, v := range views {
+		args = append(args, v.Name)
+	}
+	return i.QueryContext(ctx, fmt.Sprintf(query, nArgs(1, len(views))), args...)
+}
+
+func matViewByName(views []*MaterializedView, name string) (*MaterializedView, bool) {
+	for _, v := range views {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// matViewChange describes the changes between two states of a materialized view.
+type matViewChange struct {
+	recreate bool            // Definition or columns were changed.
+	refresh  bool            // Population state was changed.
+	comment  bool            // Comment was changed.
+	indexes  []schema.Change // Index changes.
+}
+
+func (c *matViewChange) empty() bool {
+	return !c.recreate && !c.refresh && !c.comment && len(c.indexes) == 0
+}
+
+// matViewDiff returns the changes between two states of a materialized view.
+func (d *diff) matViewDiff(from, to *MaterializedView) (*matViewChange, error) {
+	c := &matViewChange{
+		recreate: matViewDef(from.Def) != matViewDef(to.Def),
+		refresh:  from.NoData != to.NoData,
+		comment:  matViewComment(from) != matViewComment(to),
+	}
+	// Columns are derived from the view definition. Therefore, they are
+	// compared only if they were defined on both states.
+	if !c.recreate && len(from.Columns) > 0 && len(to.Columns) > 0 {
+		if len(from.Columns) != len(to.Columns) {
+			c.recreate = true
+		}
+		for i := 0; i < len(from.Columns) && !c.recreate; i++ {
+			if from.Columns[i].Name != to.Columns[i].Name {
+				c.recreate = true
+				break
+			}
+			changed, err := d.typeChanged(from.Columns[i], to.Columns[i])
+			if err != nil {
+				return nil, err
+			}
+			c.recreate = changed
+		}
+	}
+	// Recreating the view recreates its indexes as well.
+	if c.recreate {
+		return c, nil
+	}
+	changes, err := (&sqlx.Diff{DiffDriver: d}).TableDiff(from.table(), to.table())
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range changes {
+		switch ch.(type) {
+		case *schema.AddIndex, *schema.DropIndex, *schema.ModifyIndex:
+			c.indexes = append(c.indexes, ch)
+		}
+	}
+	return c, nil
+}
+
+// matViewDef returns the normalized form of a view definition.
+func matViewDef(s string) string {
+	return strings.Join(strings.Fields(strings.TrimSuffix(strings.TrimSpace(s), ";")), " ")
+}
+
+func matViewComment(v *MaterializedView) string {
+	var c schema.Comment
+	sqlx.Has(v.Attrs, &c)
+	return c.Text
+}
+
+// matViewObjectDiff returns the changes for migrating the materialized views.
+func (d *diff) matViewObjectDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		v1, ok := o1.(*MaterializedView)
+		if !ok {
+			continue
+		}
+		o2, ok := to.Object(func(o schema.Object) bool {
+			v2, ok := o.(*MaterializedView)
+			return ok && v1.Name == v2.Name
+		})
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: v1})
+			continue
+		}
+		c, err := d.matViewDiff(v1, o2.(*MaterializedView))
+		if err != nil {
+			return nil, err
+		}
+		if !c.empty() {
+			changes = append(changes, &schema.ModifyObject{From: v1, To: o2})
+		}
+	}
+	for _, o1 := range to.Objects {
+		v1, ok := o1.(*MaterializedView)
+		if !ok {
+			continue
+		}
+		if _, ok := from.Object(func(o schema.Object) bool {
+			v2, ok := o.(*MaterializedView)
+			return ok && v1.Name == v2.Name
+		}); !ok {
+			changes = append(changes, &schema.AddObject{O: v1})
+		}
+	}
+	return changes, nil
+}
+
+// addMatView plans the creation of a materialized view, its indexes and comments.
+func (s *state) addMatView(src schema.Change, v *MaterializedView) error {
+	create, drop := s.createDropMatView(v)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create materialized view %q", v.Name),
+	})
+	t := v.table()
+	adds := make([]*schema.AddIndex, len(v.Indexes))
+	for i := range v.Indexes {
+		adds[i] = &schema.AddIndex{I: v.Indexes[i]}
+	}
+	if err := s.addIndexes(src, t, adds...); err != nil {
+		return err
+	}
+	if c := matViewComment(v); c != "" {
+		s.append(s.matViewComment(src, v, c, ""))
+	}
+	var c schema.Comment
+	for i := range v.Columns {
+		if sqlx.Has(v.Columns[i].Attrs, &c) && c.Text != "" {
+			s.append(s.columnComment(src, t, v.Columns[i], c.Text, ""))
+		}
+	}
+	for i := range v.Indexes {
+		if sqlx.Has(v.Indexes[i].Attrs, &c) && c.Text != "" {
+			s.append(s.indexComment(src, t, v.Indexes[i], c.Text, ""))
+		}
+	}
+	return nil
+}
+
+// dropMatView plans the removal of a materialized view.
+func (s *state) dropMatView(src schema.Change, v *MaterializedView) {
+	create, drop := s.createDropMatView(v)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop materialized view %q", v.Name),
+	})
+}
+
+// modifyMatView plans the changes between two states of a materialized view. Changes
+// to the definition recreate the view, and changes to its population state refresh it.
+// Note, "REFRESH MATERIALIZED VIEW CONCURRENTLY" is not used by the planner, because it
+// is not allowed on views that are not populated. Callers that refresh views out of band
+// can use RefreshConcurrently on both sides of the change to detect whether the change
+// removes the unique index the concurrent refresh relies on.
+func (s *state) modifyMatView(modify *schema.ModifyObject, from, to *MaterializedView) error {
+	c, err := (&diff{s.conn}).matViewDiff(from, to)
+	if err != nil {
+		return err
+	}
+	if c.recreate {
+		s.dropMatView(modify, from)
+		return s.addMatView(modify, to)
+	}
+	if c.refresh {
+		b := s.Build("REFRESH MATERIALIZED VIEW").P(s.typeIdent(to.Schema, to.Name))
+		withData, noData := b.Clone().String(), b.Clone().P("WITH NO DATA").String()
+		change := &migrate.Change{
+			Source:  modify,
+			Cmd:     withData,
+			Reverse: noData,
+			Comment: fmt.Sprintf("populate materialized view %q", to.Name),
+		}
+		if to.NoData {
+			change.Cmd, change.Reverse = noData, withData
+			change.Comment = fmt.Sprintf("truncate materialized view %q", to.Name)
+		}
+		s.append(change)
+	}
+	if len(c.indexes) > 0 {
+		if err := s.alterMatViewIndexes(modify, to, c.indexes); err != nil {
+			return err
+		}
+	}
+	if c.comment {
+		s.append(s.matViewComment(modify, to, matViewComment(to), matViewComment(from)))
+	}
+	return nil
+}
+
+// alterMatViewIndexes plans the index changes of a materialized view.
+func (s *state) alterMatViewIndexes(modify *schema.ModifyObject, to *MaterializedView, changes []schema.Change) error {
+	var (
+		drops []*schema.DropIndex
+		adds  []*schema.AddIndex
+		t     = to.table()
+	)
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddIndex:
+			adds = append(adds, c)
+		case *schema.DropIndex:
+			drops = append(drops, c)
+		case *schema.ModifyIndex:
+			if k := c.Change; k.Is(schema.ChangeComment) {
+				fromC, toC, err := commentChange(sqlx.CommentDiff(c.From.Attrs, c.To.Attrs))
+				if err != nil {
+					return err
+				}
+				s.append(s.indexComment(modify, t, c.To, toC, fromC))
+				// If only the comment of the index was changed.
+				if k &= ^schema.ChangeComment; k.Is(schema.NoChange) {
+					continue
+				}
+			}
+			// Index modification requires rebuilding the index.
+			drops = append(drops, &schema.DropIndex{I: c.From})
+			adds = append(adds, &schema.AddIndex{I: c.To})
+		}
+	}
+	if err := s.dropIndexes(modify, t, drops...); err != nil {
+		return err
+	}
+	return s.addIndexes(modify, t, adds...)
+}
+
+func (s *state) createDropMatView(v *MaterializedView) (string, string) {
+	name := s.typeIdent(v.Schema, v.Name)
+	b := s.Build("CREATE MATERIALIZED VIEW").P(name)
+	if len(v.Columns) > 0 {
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(v.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(v.Columns[i].Name)
+			})
+		})
+	}
+	b.P("AS", strings.TrimSuffix(strings.TrimSpace(v.Def), ";"))
+	if v.NoData {
+		b.P("WITH NO DATA")
+	}
+	return b.String(), s.Build("DROP MATERIALIZED VIEW").P(name).String()
+}
+
+func (s *state) matViewComment(src schema.Change, v *MaterializedView, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON MATERIALIZED VIEW").P(s.typeIdent(v.Schema, v.Name)).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to materialized view: %q", v.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+// materialized holds a specification for a materialized view.
+type materialized struct {
+	Name      string            `spec:",name"`
+	Qualifier string            `spec:",qualifier"`
+	Schema    *schemahcl.Ref    `spec:"schema"`
+	Columns   []*sqlspec.Column `spec:"column"`
+	As        string            `spec:"as"`
+	Indexes   []*sqlspec.Index  `spec:"index"`
+	// The "populate" and "comment" attributes are
+	// conditionally added to the view definition.
+	schemahcl.DefaultExtension
+}
+
+// Label returns the defaults label used for the materialized view resource.
+func (m *materialized) Label() string { return m.Name }
+
+// QualifierLabel returns the qualifier label used for the materialized view resource, if any.
+func (m *materialized) QualifierLabel() string { return m.Qualifier }
+
+// SetQualifier sets the qualifier label used for the materialized view resource.
+func (m *materialized) SetQualifier(q string) { m.Qualifier = q }
+
+// SchemaRef returns the schema reference for the materialized view.
+func (m *materialized) SchemaRef() *schemahcl.Ref { return m.Schema }
+
+// convertMatViews converts the materialized view specs into schema objects.
+func convertMatViews(d *doc, r *schema.Realm) error {
+	for _, m := range d.Materialized {
+		ns, err := specutil.SchemaName(m.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from materialized view %q reference: %w", m.Name, err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on materialized view %q was not found in realm", ns, m.Name)
+		}
+		if m.As == "" {
+			return fmt.Errorf("missing definition (as) for materialized view %q", m.Name)
+		}
+		v := &MaterializedView{Name: m.Name, Schema: s, Def: m.As}
+		for _, cs := range m.Columns {
+			c, err := convertColumn(cs, nil)
+			if err != nil {
+				return err
+			}
+			v.Columns = append(v.Columns, c)
+		}
+		t := v.table()
+		for _, is := range m.Indexes {
+			idx, err := convertIndex(is, t)
+			if err != nil {
+				return err
+			}
+			v.Indexes = append(v.Indexes, idx)
+		}
+		if a, ok := m.Attr("populate"); ok {
+			b, err := a.Bool()
+			if err != nil {
+				return err
+			}
+			v.NoData = !b
+		}
+		if a, ok := m.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return err
+			}
+			v.SetComment(c)
+		}
+		s.AddObjects(v)
+	}
+	return nil
+}
+
+// matViewSpec converts a materialized view into its spec.
+func matViewSpec(v *MaterializedView) (*materialized, error) {
+	spec := &materialized{
+		Name:   v.Name,
+		Schema: specutil.SchemaRef(v.Schema.Name),
+		As:     v.Def,
+	}
+	t := v.table()
+	for _, c := range v.Columns {
+		cs, err := tableColumnSpec(c, t)
+		if err != nil {
+			return nil, err
+		}
+		spec.Columns = append(spec.Columns, cs)
+	}
+	for _, idx := range v.Indexes {
+		is, err := indexSpec(idx)
+		if err != nil {
+			return nil, err
+		}
+		spec.Indexes = append(spec.Indexes, is)
+	}
+	if v.NoData {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("populate", false))
+	}
+	if c := matViewComment(v); c != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+	}
+	return spec, nil
+}
+
+const (
+	// Query to list the materialized views of the given schemas.
+	matViewsQuery = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS view_name,
+	pg_get_viewdef(c.oid) AS definition,
+	c.relispopulated AS populated,
+	obj_description(c.oid, 'pg_class') AS comment
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	LEFT JOIN pg_depend AS d ON d.classid = 'pg_catalog.pg_class'::regclass::oid AND d.objid = c.oid AND d.deptype = 'e'
+WHERE
+	c.relkind = 'm'
+	AND n.nspname IN (%s)
+	AND d.objid IS NULL
+ORDER BY
+	n.nspname, c.relname
+`
+	// Query to list the columns of materialized views.
+	matViewColumnsQuery = `
+SELECT
+	c.relname AS view_name,
+	a.attname AS column_name,
+	format_type(a.atttypid, a.atttypmod) AS data_type,
+	col_description(a.attrelid, a.attnum) AS comment
+FROM
+	pg_catalog.pg_attribute AS a
+	JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE
+	n.nspname = $1
+	AND c.relname IN (%s)
+	AND a.attnum > 0
+	AND NOT a.attisdropped
+ORDER BY
+	c.relname, a.attnum
+`
+)
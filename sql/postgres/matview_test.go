@@ -0,0 +1,269 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaterializedView_RefreshConcurrently(t *testing.T) {
+	id := schema.NewIntColumn("id", "int")
+	v := &MaterializedView{Name: "v", Columns: []*schema.Column{id}}
+	require.False(t, v.RefreshConcurrently())
+	v.Indexes = []*schema.Index{schema.NewIndex("v_id").AddColumns(id)}
+	require.False(t, v.RefreshConcurrently(), "non-unique index")
+	v.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddParts(schema.NewExprPart(&schema.RawExpr{X: "lower(id)"}))}
+	require.False(t, v.RefreshConcurrently(), "expression index")
+	v.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddColumns(id).AddAttrs(&IndexPredicate{P: "id > 0"})}
+	require.False(t, v.RefreshConcurrently(), "partial index")
+	v.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddColumns(id)}
+	require.True(t, v.RefreshConcurrently())
+	v.NoData = true
+	require.False(t, v.RefreshConcurrently(), "unpopulated view")
+}
+
+func TestDriver_InspectMatViews(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= CURRENT_SCHEMA()"))).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(matViewsQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | view_name |     definition      | populated | comment
+-------------+-----------+---------------------+-----------+---------
+ public      | totals    |  SELECT 1 AS id;    | t         | summary
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(matViewColumnsQuery, "$2"))).
+		WithArgs("public", "totals").
+		WillReturnRows(sqltest.Rows(`
+ view_name | column_name | data_type | comment
+-----------+-------------+-----------+---------
+ totals    | id          | integer   | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesAbove11, "$2"))).
+		WithArgs("public", "totals").
+		WillReturnRows(sqltest.Rows(`
+ table_name | index_name | index_type | column_name | included | primary | unique | excoper | constraints | predicate | expression | desc | nulls_first | nulls_last | comment | options | opcname | opcschema | opcdefault | opcparams | indnullsnotdistinct
+------------+------------+------------+-------------+----------+---------+--------+---------+-------------+-----------+------------+------+-------------+------------+---------+---------+---------+-----------+------------+-----------+---------------------
+ totals     | totals_id  | btree      | id          | f        | f       | t      |         |             |           | id         | f    | f           | t          |         |         | int4_ops | pg_catalog | t         |           | f
+`))
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode: schema.InspectSchemas | schema.InspectViews,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	require.Len(t, s.Objects, 1)
+	v, ok := s.Objects[0].(*MaterializedView)
+	require.True(t, ok)
+	require.Equal(t, "totals", v.Name)
+	require.Equal(t, "SELECT 1 AS id;", v.Def)
+	require.False(t, v.NoData)
+	require.Equal(t, "summary", matViewComment(v))
+	require.Len(t, v.Columns, 1)
+	require.Equal(t, &schema.IntegerType{T: TypeInteger}, v.Columns[0].Type.Type)
+	require.Len(t, v.Indexes, 1)
+	require.True(t, v.Indexes[0].Unique)
+	require.Equal(t, v.Columns[0], v.Indexes[0].Parts[0].C)
+	require.True(t, v.RefreshConcurrently())
+}
+
+func TestDriver_MatViewDiff(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	var (
+		from = schema.New("public")
+		to   = schema.New("public")
+		id1  = schema.NewIntColumn("id", TypeInteger)
+		id2  = schema.NewIntColumn("id", TypeInteger)
+		v1   = &MaterializedView{Name: "v", Schema: from, Def: " SELECT 1 AS id;", Columns: []*schema.Column{id1}}
+		v2   = &MaterializedView{Name: "v", Schema: to, Def: "SELECT 1 AS id", Columns: []*schema.Column{id2}}
+	)
+	from.AddObjects(v1)
+	to.AddObjects(v2)
+	changes, err := drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes, "definitions are compared normalized")
+
+	v2.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddColumns(id2)}
+	changes, err = drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyObject{From: v1, To: v2}}, changes)
+
+	to.Objects = nil
+	changes, err = drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.DropObject{O: v1}}, changes)
+
+	changes, err = drv.SchemaDiff(to, from)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.AddObject{O: v1}}, changes)
+}
+
+func TestPlanChanges_MatView(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		id1 = schema.NewIntColumn("id", TypeInteger)
+		id2 = schema.NewIntColumn("id", TypeInteger)
+		v1  = &MaterializedView{Name: "v", Schema: s, Def: "SELECT id FROM t", Columns: []*schema.Column{id1}, NoData: true}
+		v2  = &MaterializedView{Name: "v", Schema: s, Def: "SELECT id FROM t", Columns: []*schema.Column{id2}}
+	)
+	v1.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddColumns(id1)}
+	v2.SetComment("c")
+	tests := []struct {
+		changes []schema.Change
+		want    [][2]string
+	}{
+		{
+			changes: []schema.Change{&schema.AddObject{O: v1}},
+			want: [][2]string{
+				{`CREATE MATERIALIZED VIEW "public"."v" ("id") AS SELECT id FROM t WITH NO DATA`, `DROP MATERIALIZED VIEW "public"."v"`},
+				{`CREATE UNIQUE INDEX "v_id" ON "public"."v" ("id")`, `DROP INDEX "public"."v_id"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropObject{O: v2}},
+			want: [][2]string{
+				{`DROP MATERIALIZED VIEW "public"."v"`, `CREATE MATERIALIZED VIEW "public"."v" ("id") AS SELECT id FROM t`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyObject{From: v1, To: v2}},
+			want: [][2]string{
+				{`REFRESH MATERIALIZED VIEW "public"."v"`, `REFRESH MATERIALIZED VIEW "public"."v" WITH NO DATA`},
+				{`DROP INDEX "public"."v_id"`, `CREATE UNIQUE INDEX "v_id" ON "public"."v" ("id")`},
+				{`COMMENT ON MATERIALIZED VIEW "public"."v" IS 'c'`, `COMMENT ON MATERIALIZED VIEW "public"."v" IS ''`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyObject{From: v2, To: &MaterializedView{Name: "v", Schema: s, Def: "SELECT id + 1 AS id FROM t", Attrs: v2.Attrs}}},
+			want: [][2]string{
+				{`DROP MATERIALIZED VIEW "public"."v"`, `CREATE MATERIALIZED VIEW "public"."v" ("id") AS SELECT id FROM t`},
+				{`CREATE MATERIALIZED VIEW "public"."v" AS SELECT id + 1 AS id FROM t`, `DROP MATERIALIZED VIEW "public"."v"`},
+				{`COMMENT ON MATERIALIZED VIEW "public"."v" IS 'c'`, `COMMENT ON MATERIALIZED VIEW "public"."v" IS ''`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		db, mk, err := sqlmock.New()
+		require.NoError(t, err)
+		mock{mk}.version("130000")
+		drv, err := Open(db)
+		require.NoError(t, err)
+		plan, err := drv.PlanChanges(context.Background(), "plan", tt.changes)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, len(tt.want))
+		for i, c := range plan.Changes {
+			require.Equal(t, tt.want[i][0], c.Cmd)
+			require.Equal(t, tt.want[i][1], c.Reverse)
+		}
+	}
+}
+
+func TestPlanChanges_MatViewRefreshConcurrently(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		id1 = schema.NewIntColumn("id", TypeInteger)
+		id2 = schema.NewIntColumn("id", TypeInteger)
+		v1  = &MaterializedView{Name: "v", Schema: s, Def: "SELECT id FROM t", Columns: []*schema.Column{id1}}
+		v2  = &MaterializedView{Name: "v", Schema: s, Def: "SELECT id FROM t", Columns: []*schema.Column{id2}}
+	)
+	v1.Indexes = []*schema.Index{schema.NewUniqueIndex("v_id").AddColumns(id1)}
+	modify := &schema.ModifyObject{From: v1, To: v2}
+	// Dropping the only unique index of the view disables its concurrent refresh.
+	require.True(t, modify.From.(*MaterializedView).RefreshConcurrently())
+	require.False(t, modify.To.(*MaterializedView).RefreshConcurrently())
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{modify})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `DROP INDEX "public"."v_id"`, plan.Changes[0].Cmd)
+	require.Equal(t, `drop index "v_id" from table: "v"`, plan.Changes[0].Comment)
+}
+
+func TestPlanChanges_MatViewOrder(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		t1 = schema.NewTable("t").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+		v  = &MaterializedView{Name: "v", Schema: s, Def: "SELECT id FROM t"}
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: v},
+		&schema.AddTable{T: t1},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `CREATE TABLE "public"."t" ("id" integer NOT NULL)`, plan.Changes[0].Cmd)
+	require.Equal(t, `CREATE MATERIALIZED VIEW "public"."v" AS SELECT id FROM t`, plan.Changes[1].Cmd)
+
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropTable{T: t1},
+		&schema.DropObject{O: v},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `DROP MATERIALIZED VIEW "public"."v"`, plan.Changes[0].Cmd)
+	require.Equal(t, `DROP TABLE "public"."t"`, plan.Changes[1].Cmd)
+}
+
+func TestMarshalSpec_MatView(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		id = schema.NewIntColumn("id", TypeInteger)
+		v  = &MaterializedView{Name: "totals", Schema: s, Def: "SELECT 1 AS id", Columns: []*schema.Column{id}, NoData: true}
+	)
+	v.Indexes = []*schema.Index{schema.NewUniqueIndex("totals_id").AddColumns(id)}
+	v.SetComment("summary")
+	s.AddObjects(v)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `materialized "totals" {
+  schema   = schema.public
+  as       = "SELECT 1 AS id"
+  populate = false
+  comment  = "summary"
+  column "id" {
+    null = false
+    type = integer
+  }
+  index "totals_id" {
+    unique  = true
+    columns = [column.id]
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Objects, 1)
+	gv, ok := got.Objects[0].(*MaterializedView)
+	require.True(t, ok)
+	require.Equal(t, "totals", gv.Name)
+	require.Equal(t, "SELECT 1 AS id", gv.Def)
+	require.True(t, gv.NoData)
+	require.Equal(t, "summary", matViewComment(gv))
+	require.Len(t, gv.Indexes, 1)
+	require.Equal(t, gv.Columns[0], gv.Indexes[0].Parts[0].C)
+}
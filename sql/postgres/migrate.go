@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -37,20 +38,29 @@ func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema
 	for _, o := range opts {
 		o(&s.PlanOptions)
 	}
+	if p.concurrentIndexes {
+		changes = concurrentIndexes(changes)
+	}
 	if err := s.plan(changes); err != nil {
 		return nil, err
 	}
 	if err := sqlx.SetReversible(&s.Plan); err != nil {
 		return nil, err
 	}
+	// Concurrent index commands cannot be executed inside a transaction block.
+	if p.concurrentIndexes && slices.ContainsFunc(s.Changes, func(c *migrate.Change) bool { return reConcurrentIndex.MatchString(c.Cmd) }) {
+		s.Transactional = false
+		s.AddDirectiveOnce(txModeNone)
+	}
 	return &s.Plan, nil
 }
 
 // ApplyChanges applies the changes on the database. An error is returned
-// if the driver is unable to produce a plan to do so, or one of the statements
-// is failed or unsupported.
+// if the driver is unable to produce a plan to do so, the server does not
+// meet the requirements of the plan, or one of the statements is failed or
+// unsupported.
 func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
-	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+	return sqlx.ApplyChanges(ctx, changes, &requirePlanner{p}, opts...)
 }
 
 // state represents the state of a planning. It is not part of
@@ -65,6 +75,9 @@ type state struct {
 // Exec executes the changes on the database. An error is returned
 // if one of the operations fail, or a change is not supported.
 func (s *state) plan(changes []schema.Change) error {
+	if err := s.Policy.Check(changes); err != nil {
+		return err
+	}
 	if s.SchemaQualifier != nil {
 		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
 			return err
@@ -74,18 +87,30 @@ func (s *state) plan(changes []schema.Change) error {
 	if err != nil {
 		return err
 	}
+	if err := s.checkDialect(planned); err != nil {
+		return err
+	}
 	if s.PlanOptions.Mode != migrate.PlanModeUnsortedDump {
 		if planned, err = s.detachCycles(planned); err != nil {
 			return err
 		}
 		planned = s.sortChanges(planned)
 	}
-	for _, c := range planned {
+	s.typeExtensions(planned)
+	for _, c := range sqlx.SplitBackfills(planned) {
 		switch c := c.(type) {
 		case *schema.AddTable:
 			err = s.addTable(c)
 		case *schema.ModifyTable:
-			err = s.modifyTable(c)
+			m, data := sqlx.DataChanges(c)
+			if len(data) == 0 || len(m.Changes) > 0 {
+				err = s.modifyTable(m)
+			}
+			if err == nil {
+				err = s.dataChanges(data...)
+			}
+		case *schema.DataChange:
+			err = s.dataChanges(c)
 		case *schema.RenameTable:
 			s.renameTable(c)
 		case *schema.DropTable:
@@ -96,13 +121,20 @@ func (s *state) plan(changes []schema.Change) error {
 			err = s.modifyObject(c)
 		case *schema.DropObject:
 			err = s.dropObject(c)
+		case *schema.AddFunc:
+			err = s.addTriggerFunc(c, c.F)
+		case *schema.ModifyFunc:
+			err = s.modifyTriggerFunc(c, c.From, c.To)
+		case *schema.DropFunc:
+			err = s.dropTriggerFunc(c, c.F)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
 		if err != nil {
-			return err
+			return sqlx.PosError(c, err)
 		}
 	}
+	s.sequenceOwners(planned)
 	return nil
 }
 
@@ -130,6 +162,9 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 			if cm := (schema.Comment{}); sqlx.Has(c.S.Attrs, &cm) {
 				s.append(s.schemaComment(c, c.S, cm.Text, ""))
 			}
+			if o := (Owner{}); sqlx.Has(c.S.Attrs, &o) {
+				s.append(s.ownerChange(c, fmt.Sprintf("schema %q", c.S.Name), s.Build("ALTER SCHEMA").Ident(c.S.Name), "", o.Name))
+			}
 		case *schema.ModifySchema:
 			for i := range c.Changes {
 				switch change := c.Changes[i].(type) {
@@ -142,6 +177,14 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 					}
 					s.append(s.schemaComment(c, c.S, a.Text, ""))
 				case *schema.ModifyAttr:
+					if from, ok := change.From.(*Owner); ok {
+						to, ok := change.To.(*Owner)
+						if !ok {
+							return nil, fmt.Errorf("unexpected schema ModifyAttr: (%T, %T)", change.To, change.From)
+						}
+						s.append(s.ownerChange(c, fmt.Sprintf("schema %q", c.S.Name), s.Build("ALTER SCHEMA").Ident(c.S.Name), from.Name, to.Name))
+						continue
+					}
 					to, ok1 := change.To.(*schema.Comment)
 					from, ok2 := change.From.(*schema.Comment)
 					if !ok1 || !ok2 {
@@ -182,6 +225,55 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 	return planned, nil
 }
 
+// typeExtensions creates the extensions that define the column types used by
+// the given changes (e.g., citext or vector), before any table is created or
+// modified. Extensions are not dropped on reverse, as other objects in the
+// database may depend on them.
+func (s *state) typeExtensions(changes []schema.Change) {
+	if !s.supports(featExtensions) {
+		return
+	}
+	var (
+		exts []string
+		srcs = make(map[string]schema.Change)
+	)
+	add := func(src schema.Change, cs ...*schema.Column) {
+		for _, c := range cs {
+			if c.Type == nil {
+				continue
+			}
+			if ext, ok := TypeExtension(c.Type.Type); ok && srcs[ext] == nil {
+				exts = append(exts, ext)
+				srcs[ext] = src
+			}
+		}
+	}
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			add(c, c.T.Columns...)
+		case *schema.ModifyTable:
+			for _, change := range c.Changes {
+				switch change := change.(type) {
+				case *schema.AddColumn:
+					add(c, change.C)
+				case *schema.ModifyColumn:
+					if change.Change.Is(schema.ChangeType) {
+						add(c, change.To)
+					}
+				}
+			}
+		}
+	}
+	for _, ext := range exts {
+		s.append(&migrate.Change{
+			Source:  srcs[ext],
+			Cmd:     s.Build("CREATE EXTENSION IF NOT EXISTS").Ident(ext).String(),
+			Comment: fmt.Sprintf("create extension %q required by column types", ext),
+		})
+	}
+}
+
 // addTable builds and executes the query for creating a table in a schema.
 func (s *state) addTable(add *schema.AddTable) error {
 	var (
@@ -232,6 +324,8 @@ func (s *state) addTable(add *schema.AddTable) error {
 		}
 		b.P(s)
 	}
+	addCRDBTableAttrs(b, add.T)
+	addYBTableAttrs(b, add.T)
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
 	}
@@ -298,12 +392,17 @@ func (s *state) dropTable(drop *schema.DropTable) error {
 // modifyTable builds the statements that bring the table into its modified state.
 func (s *state) modifyTable(modify *schema.ModifyTable) error {
 	var (
-		alter   []schema.Change
-		addI    []*schema.AddIndex
-		dropI   []*schema.DropIndex
-		changes []*migrate.Change
+		alter    []schema.Change
+		addI     []*schema.AddIndex
+		dropI    []*schema.DropIndex
+		policies []schema.Change
+		changes  []*migrate.Change
 	)
 	for _, change := range skipAutoChanges(modify.Changes) {
+		if isPolicyChange(change) {
+			policies = append(policies, change)
+			continue
+		}
 		switch change := change.(type) {
 		case *schema.ModifyAttr:
 			if _, ok := change.From.(*schema.Comment); !ok {
@@ -396,6 +495,14 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 				Reverse: s.Build("ALTER INDEX").SchemaResource(modify.T.Schema, change.To.Name).P("RENAME TO").Ident(change.From.Name).String(),
 			})
 		case *schema.ModifyForeignKey:
+			c1, c2 := constraintComment(change.From.Attrs), constraintComment(change.To.Attrs)
+			if c1 != c2 && change.To.Symbol != "" {
+				changes = append(changes, s.constraintComment(modify, modify.T, change.To.Symbol, c2, c1))
+			}
+			// Comment changes do not require recreating the constraint.
+			if change.Change == schema.ChangeAttr && !deferrableChanged(change.From.Attrs, change.To.Attrs) {
+				continue
+			}
 			// Foreign-key modification is translated into 2 steps.
 			// Dropping the current foreign key and creating a new one.
 			alter = append(alter, &schema.DropForeignKey{
@@ -403,6 +510,27 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			}, &schema.AddForeignKey{
 				F: change.To,
 			})
+		case *schema.AddForeignKey:
+			if c := constraintComment(change.F.Attrs); c != "" && change.F.Symbol != "" {
+				changes = append(changes, s.constraintComment(modify, modify.T, change.F.Symbol, c, ""))
+			}
+			alter = append(alter, change)
+		case *schema.AddCheck:
+			if c := constraintComment(change.C.Attrs); c != "" && change.C.Name != "" {
+				changes = append(changes, s.constraintComment(modify, modify.T, change.C.Name, c, ""))
+			}
+			alter = append(alter, change)
+		case *schema.ModifyCheck:
+			c1, c2 := constraintComment(change.From.Attrs), constraintComment(change.To.Attrs)
+			if c1 != c2 && change.To.Name != "" {
+				changes = append(changes, s.constraintComment(modify, modify.T, change.To.Name, c2, c1))
+			}
+			// Comment changes do not require recreating the constraint.
+			if change.From.Name == change.To.Name && change.From.Expr == change.To.Expr &&
+				sqlx.Has(change.From.Attrs, &NoInherit{}) == sqlx.Has(change.To.Attrs, &NoInherit{}) {
+				continue
+			}
+			alter = append(alter, change)
 		case *schema.AddColumn:
 			if c := (schema.Comment{}); sqlx.Has(change.C.Attrs, &c) {
 				changes = append(changes, s.columnComment(modify, modify.T, change.C, c.Text, ""))
@@ -436,6 +564,8 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			alter = append(alter, change)
 		}
 	}
+	before, after := s.policyChanges(modify, modify.T, policies)
+	s.append(before...)
 	if err := s.dropIndexes(modify, modify.T, dropI...); err != nil {
 		return err
 	}
@@ -448,6 +578,7 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 		return err
 	}
 	s.append(changes...)
+	s.append(after...)
 	return nil
 }
 
@@ -668,17 +799,26 @@ func (s *state) alterColumn(b *sqlx.Builder, alter *changeGroup, t *schema.Table
 			s.columnDefault(b.P("SET"), c.To)
 			k &= ^schema.ChangeDefault
 		case k.Is(schema.ChangeAttr):
-			toI, ok := identity(c.To.Attrs)
-			if !ok {
+			fromI, fromOK := identity(c.From.Attrs)
+			toI, toOK := identity(c.To.Attrs)
+			switch {
+			case !fromOK && !toOK:
 				return fmt.Errorf("unexpected attribute change (expect IDENTITY): %v", c.To.Attrs)
-			}
-			// The syntax for altering identity columns is identical to sequence_options.
-			// https://www.postgresql.org/docs/current/sql-altersequence.html
-			b.P("SET GENERATED", toI.Generation, "SET START WITH", strconv.FormatInt(toI.Sequence.Start, 10), "SET INCREMENT BY", strconv.FormatInt(toI.Sequence.Increment, 10))
-			// Skip SEQUENCE RESTART in case the "start value" is less than the "current value" in one
-			// of the states (inspected and desired), because this function is used for both UP and DOWN.
-			if fromI, ok := identity(c.From.Attrs); (!ok || fromI.Sequence.Last < toI.Sequence.Start) && toI.Sequence.Last < toI.Sequence.Start {
-				b.P("RESTART")
+			case !toOK:
+				b.P("DROP IDENTITY")
+			case !fromOK:
+				b.P("ADD GENERATED", toI.Generation, "AS IDENTITY")
+				s.identityOptions(b, toI, c.To)
+			default:
+				// The syntax for altering identity columns is identical to sequence_options.
+				// https://www.postgresql.org/docs/current/sql-altersequence.html
+				b.P("SET GENERATED", toI.Generation, "SET START WITH", strconv.FormatInt(toI.Sequence.Start, 10), "SET INCREMENT BY", strconv.FormatInt(toI.Sequence.Increment, 10))
+				s.alterIdentityOptions(b, fromI, toI, c)
+				// Skip SEQUENCE RESTART in case the "start value" is less than the "current value" in one
+				// of the states (inspected and desired), because this function is used for both UP and DOWN.
+				if fromI.Sequence.Last < toI.Sequence.Start && toI.Sequence.Last < toI.Sequence.Start {
+					b.P("RESTART")
+				}
 			}
 			k &= ^schema.ChangeAttr
 		case k.Is(schema.ChangeGenerated):
@@ -697,6 +837,69 @@ func (s *state) alterColumn(b *sqlx.Builder, alter *changeGroup, t *schema.Table
 	return nil
 }
 
+// identityOptions writes the sequence options of the identity column
+// that are not set to the defaults of its type.
+func (s *state) identityOptions(b *sqlx.Builder, id *Identity, c *schema.Column) {
+	o, d := identityOptions(id, c)
+	if id.Sequence.Start == defaultSeqStart && id.Sequence.Increment == defaultSeqIncrement &&
+		o.min == d.min && o.max == d.max && o.cache == d.cache && !o.cycle {
+		return
+	}
+	b.Wrap(func(b *sqlx.Builder) {
+		if id.Sequence.Start != defaultSeqStart {
+			b.P("START WITH", strconv.FormatInt(id.Sequence.Start, 10))
+		}
+		if id.Sequence.Increment != defaultSeqIncrement {
+			b.P("INCREMENT BY", strconv.FormatInt(id.Sequence.Increment, 10))
+		}
+		if o.min != d.min {
+			b.P("MINVALUE").Int64(o.min)
+		}
+		if o.max != d.max {
+			b.P("MAXVALUE").Int64(o.max)
+		}
+		if o.cache != d.cache {
+			b.P("CACHE").Int64(o.cache)
+		}
+		if o.cycle {
+			b.P("CYCLE")
+		}
+	})
+}
+
+// alterIdentityOptions writes the SET clauses for the sequence options of the
+// identity column that were changed, except for START and INCREMENT that are
+// always set.
+func (s *state) alterIdentityOptions(b *sqlx.Builder, from, to *Identity, c *schema.ModifyColumn) {
+	o1, d1 := identityOptions(from, c.From)
+	o2, d2 := identityOptions(to, c.To)
+	for _, v := range []struct {
+		name           string
+		v1, v2, d1, d2 int64
+	}{
+		{name: "MINVALUE", v1: o1.min, v2: o2.min, d1: d1.min, d2: d2.min},
+		{name: "MAXVALUE", v1: o1.max, v2: o2.max, d1: d1.max, d2: d2.max},
+	} {
+		switch {
+		case !boundChanged(v.v1, v.v2, v.d1, v.d2):
+		case v.v2 == v.d2:
+			b.P("SET NO", v.name)
+		default:
+			b.P("SET", v.name).Int64(v.v2)
+		}
+	}
+	if o1.cache != o2.cache {
+		b.P("SET CACHE").Int64(o2.cache)
+	}
+	if o1.cycle != o2.cycle {
+		if o2.cycle {
+			b.P("SET CYCLE")
+		} else {
+			b.P("SET NO CYCLE")
+		}
+	}
+}
+
 // alterType appends the clause(s) to alter the column type and assuming the
 // "ALTER COLUMN <Name>" was called before by the alterColumn function.
 func (s *state) alterType(b *sqlx.Builder, alter *changeGroup, t *schema.Table, c *schema.ModifyColumn) error {
@@ -788,6 +991,18 @@ func (s *state) alterType(b *sqlx.Builder, alter *changeGroup, t *schema.Table,
 	return nil
 }
 
+// dataChanges plans the given data changes.
+func (s *state) dataChanges(data ...*schema.DataChange) error {
+	for _, d := range data {
+		c, err := sqlx.PlanDataChange(s.Build(), d)
+		if err != nil {
+			return err
+		}
+		s.append(c)
+	}
+	return nil
+}
+
 func (s *state) renameTable(c *schema.RenameTable) {
 	s.append(&migrate.Change{
 		Source:  c,
@@ -812,6 +1027,18 @@ func (s *state) addComments(src schema.Change, t *schema.Table) {
 			s.append(s.indexComment(src, t, t.Indexes[i], c.Text, ""))
 		}
 	}
+	for _, fk := range t.ForeignKeys {
+		if c := constraintComment(fk.Attrs); c != "" && fk.Symbol != "" {
+			s.append(s.constraintComment(src, t, fk.Symbol, c, ""))
+		}
+	}
+	for _, a := range t.Attrs {
+		if ck, ok := a.(*schema.Check); ok && ck.Name != "" {
+			if c := constraintComment(ck.Attrs); c != "" {
+				s.append(s.constraintComment(src, t, ck.Name, c, ""))
+			}
+		}
+	}
 }
 
 func (s *state) schemaComment(src schema.Change, sc *schema.Schema, to, from string) *migrate.Change {
@@ -855,6 +1082,33 @@ func (s *state) indexComment(src schema.Change, t *schema.Table, idx *schema.Ind
 	}
 }
 
+func (s *state) constraintComment(src schema.Change, t *schema.Table, name, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON CONSTRAINT").Ident(name).P("ON").Table(t).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to constraint: %q on table: %q", name, t.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) enumComment(src schema.Change, e *schema.EnumType, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON TYPE").P(s.enumIdent(e)).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to enum type: %q", e.T),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+// constraintComment returns the comment of a constraint, if exists.
+func constraintComment(attrs []schema.Attr) string {
+	var c schema.Comment
+	sqlx.Has(attrs, &c)
+	return c.Text
+}
+
 func (s *state) dropIndexes(src schema.Change, t *schema.Table, drops ...*schema.DropIndex) error {
 	adds := make([]*schema.AddIndex, len(drops))
 	for i, d := range drops {
@@ -917,6 +1171,9 @@ func (s *state) alterEnum(modify *schema.ModifyObject) error {
 			return fmt.Errorf("reordering enum %q value %q is not supported", from.T, v)
 		}
 	}
+	if c1, c2 := constraintComment(from.Attrs), constraintComment(to.Attrs); c1 != c2 {
+		s.append(s.enumComment(modify, to, c2, c1))
+	}
 	return nil
 }
 
@@ -937,6 +1194,7 @@ func (s *state) addIndexes(src schema.Change, t *schema.Table, adds ...*schema.A
 		if err := s.index(b, idx); err != nil {
 			return err
 		}
+		tabletSplit(b, idx.Attrs)
 		s.append(&migrate.Change{
 			Cmd:     b.String(),
 			Source:  src,
@@ -983,16 +1241,7 @@ func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
 	case hasI:
 		id, _ := identity(c.Attrs)
 		b.P("GENERATED", id.Generation, "AS IDENTITY")
-		if id.Sequence.Start != defaultSeqStart || id.Sequence.Increment != defaultSeqIncrement {
-			b.Wrap(func(b *sqlx.Builder) {
-				if id.Sequence.Start != defaultSeqStart {
-					b.P("START WITH", strconv.FormatInt(id.Sequence.Start, 10))
-				}
-				if id.Sequence.Increment != defaultSeqIncrement {
-					b.P("INCREMENT BY", strconv.FormatInt(id.Sequence.Increment, 10))
-				}
-			})
-		}
+		s.identityOptions(b, id, c)
 	case hasX:
 		x := &schema.GeneratedExpr{}
 		sqlx.Has(c.Attrs, x)
@@ -1091,6 +1340,10 @@ func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 	if err := s.indexParts(b, idx); err != nil {
 		return err
 	}
+	h, sharded := hashSharded(idx.Attrs)
+	if sharded {
+		b.P("USING HASH")
+	}
 	if c := (IndexInclude{}); sqlx.Has(idx.Attrs, &c) {
 		b.P("INCLUDE")
 		b.Wrap(func(b *sqlx.Builder) {
@@ -1103,21 +1356,57 @@ func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 	if _, ok := uniqueConst(idx.Attrs); !ok {
 		nullsNotDistinct(b, idx)
 	}
-	if p, ok := indexStorageParams(idx.Attrs); ok {
+	if p, ok := indexStorageParams(idx.Attrs); ok || sharded && h.Buckets > 0 {
+		if p == nil {
+			p = &IndexStorageParams{}
+		}
 		b.P("WITH")
 		b.Wrap(func(b *sqlx.Builder) {
 			var parts []string
+			if sharded && h.Buckets > 0 {
+				parts = append(parts, fmt.Sprintf("bucket_count = %d", h.Buckets))
+			}
 			if p.AutoSummarize {
 				parts = append(parts, "autosummarize = true")
 			}
-			if p.PagesPerRange != 0 && p.PagesPerRange != defaultPagesPerRange {
+			if p.PagesPerRange != 0 {
 				parts = append(parts, fmt.Sprintf("pages_per_range = %d", p.PagesPerRange))
 			}
+			if p.FillFactor != 0 {
+				parts = append(parts, fmt.Sprintf("%s = %d", storageParamFillFactor, p.FillFactor))
+			}
+			if p.Deduplicate != "" {
+				parts = append(parts, fmt.Sprintf("%s = %s", storageParamDedup, strings.ToLower(p.Deduplicate)))
+			}
+			if p.Buffering != "" {
+				parts = append(parts, fmt.Sprintf("%s = %s", storageParamBuffering, strings.ToLower(p.Buffering)))
+			}
+			if p.FastUpdate != "" {
+				parts = append(parts, fmt.Sprintf("%s = %s", storageParamFastUpdate, strings.ToLower(p.FastUpdate)))
+			}
+			if p.ListLimit != 0 {
+				parts = append(parts, fmt.Sprintf("%s = %d", storageParamListLimit, p.ListLimit))
+			}
+			if p.Lists != 0 {
+				parts = append(parts, fmt.Sprintf("%s = %d", storageParamLists, p.Lists))
+			}
+			if p.M != 0 {
+				parts = append(parts, fmt.Sprintf("%s = %d", storageParamM, p.M))
+			}
+			if p.EfConstruction != 0 {
+				parts = append(parts, fmt.Sprintf("%s = %d", storageParamEf, p.EfConstruction))
+			}
 			b.WriteString(strings.Join(parts, ", "))
 		})
 	}
 	if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) {
-		b.P("WHERE").P(p.P)
+		b.P("WHERE")
+		// The predicate of EXCLUDE constraints must be wrapped with parentheses.
+		if _, isE := excludeConst(idx.Attrs); isE {
+			b.P(sqlx.MayWrap(p.P))
+		} else {
+			b.P(p.P)
+		}
 	}
 	return nil
 }
@@ -1146,9 +1435,20 @@ func (s *state) fks(b *sqlx.Builder, fks ...*schema.ForeignKey) {
 		if fk.OnDelete != "" {
 			b.P("ON DELETE", string(fk.OnDelete))
 		}
+		deferrable(b, fk.Attrs)
 	})
 }
 
+// deferrable writes the DEFERRABLE clause of the constraint, if defined.
+func deferrable(b *sqlx.Builder, attrs []schema.Attr) {
+	if d := (Deferrable{}); sqlx.Has(attrs, &d) {
+		b.P("DEFERRABLE")
+		if d.InitiallyDeferred {
+			b.P("INITIALLY DEFERRED")
+		}
+	}
+}
+
 func (s *state) constraint(b *sqlx.Builder, idx *schema.Index) error {
 	if _, isU := uniqueConst(idx.Attrs); isU {
 		return s.unique(b, idx)
@@ -1172,7 +1472,11 @@ func (s *state) unique(b *sqlx.Builder, idx *schema.Index) error {
 	// In UNIQUE constraints, the NULLS [NOT] DISTINCT
 	// clause is written before the index parts.
 	nullsNotDistinct(b, idx)
-	return s.index(b, idx)
+	if err := s.index(b, idx); err != nil {
+		return err
+	}
+	deferrable(b, idx.Attrs)
+	return nil
 }
 
 func (s *state) exclude(b *sqlx.Builder, idx *schema.Index) error {
@@ -1185,7 +1489,11 @@ func (s *state) exclude(b *sqlx.Builder, idx *schema.Index) error {
 		name = idx.Name
 	}
 	b.P("CONSTRAINT").Ident(name).P("EXCLUDE")
-	return s.index(b, idx)
+	if err := s.index(b, idx); err != nil {
+		return err
+	}
+	deferrable(b, idx.Attrs)
+	return nil
 }
 
 func (s *state) append(c ...*migrate.Change) {
@@ -1339,6 +1647,10 @@ func (s *state) formatType(t schema.Type) (string, error) {
 		return s.domainIdent(t), nil
 	case *CompositeType:
 		return s.compositeIdent(t), nil
+	case *CustomRangeType:
+		return s.rangeIdent(t), nil
+	case *CustomMultirangeType:
+		return s.multirangeIdent(t), nil
 	case *ArrayType:
 		switch t := t.Type.(type) {
 		case *schema.EnumType:
@@ -1347,6 +1659,10 @@ func (s *state) formatType(t schema.Type) (string, error) {
 			return s.domainIdent(t) + "[]", nil
 		case *CompositeType:
 			return s.compositeIdent(t) + "[]", nil
+		case *CustomRangeType:
+			return s.rangeIdent(t) + "[]", nil
+		case *CustomMultirangeType:
+			return s.multirangeIdent(t) + "[]", nil
 		}
 	}
 	return FormatType(t)
@@ -1845,6 +1845,43 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Extensions of column types are created before the tables.
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("docs").SetSchema(schema.New("public")).AddColumns(
+						schema.NewColumn("title").SetType(&UserDefinedType{T: TypeCIText}),
+						schema.NewColumn("tags").SetType(&ArrayType{T: "citext[]", Type: &UserDefinedType{T: TypeCIText}}),
+					),
+				},
+				&schema.ModifyTable{
+					T: schema.NewTable("items").SetSchema(schema.New("public")),
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewColumn("emb").SetType(&VectorType{T: TypeVector, Dim: 3})},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    false,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd: `CREATE EXTENSION IF NOT EXISTS "citext"`,
+					},
+					{
+						Cmd: `CREATE EXTENSION IF NOT EXISTS "vector"`,
+					},
+					{
+						Cmd:     `CREATE TABLE "public"."docs" ("title" citext NOT NULL, "tags" citext[] NOT NULL)`,
+						Reverse: `DROP TABLE "public"."docs"`,
+					},
+					{
+						Cmd:     `ALTER TABLE "public"."items" ADD COLUMN "emb" vector(3) NOT NULL`,
+						Reverse: `ALTER TABLE "public"."items" DROP COLUMN "emb"`,
+					},
+				},
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
@@ -2000,3 +2037,288 @@ func TestIndentedPlan(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanChanges_ExcludeDeferrable(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		id   = schema.NewIntColumn("id", "int")
+		room = schema.NewIntColumn("room", "int")
+		at   = schema.NewColumn("during").SetType(&RangeType{T: TypeTSRange})
+		t1   = schema.NewTable("rooms").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+		t2   = schema.NewTable("bookings").SetSchema(s).AddColumns(id, room, at)
+		ex   = schema.NewIndex("no_overlap").
+			AddParts(
+				schema.NewColumnPart(room).AddAttrs(&Operator{Name: "="}),
+				schema.NewColumnPart(at).AddAttrs(&Operator{Name: "&&"}),
+			).
+			AddAttrs(&IndexType{T: IndexTypeGiST}, &IndexPredicate{P: "id > 0"}, ExcludeConstraint("no_overlap"), &Deferrable{InitiallyDeferred: true})
+		fk = schema.NewForeignKey("room_fk").AddColumns(room).SetRefTable(t1).AddRefColumns(t1.Columns[0]).AddAttrs(&Deferrable{})
+	)
+	t2.AddIndexes(schema.NewUniqueIndex("bookings_id").AddColumns(id).AddAttrs(UniqueConstraint("bookings_id"), &Deferrable{}), ex)
+	t2.AddForeignKeys(fk)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: t2}})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `CREATE TABLE "public"."bookings" ("id" integer NOT NULL, "room" integer NOT NULL, "during" tsrange NOT NULL, CONSTRAINT "bookings_id" UNIQUE ("id") DEFERRABLE, CONSTRAINT "no_overlap" EXCLUDE USING GIST ("room" WITH =, "during" WITH &&) WHERE (id > 0) DEFERRABLE INITIALLY DEFERRED, CONSTRAINT "room_fk" FOREIGN KEY ("room") REFERENCES "public"."rooms" ("id") DEFERRABLE)`, plan.Changes[0].Cmd)
+
+	fk2 := schema.NewForeignKey("room_fk").SetTable(t2).AddColumns(room).SetRefTable(t1).AddRefColumns(t1.Columns[0]).AddAttrs(&Deferrable{InitiallyDeferred: true})
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: t2, Changes: []schema.Change{&schema.ModifyForeignKey{From: fk, To: fk2, Change: schema.ChangeAttr}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `ALTER TABLE "public"."bookings" DROP CONSTRAINT "room_fk", ADD CONSTRAINT "room_fk" FOREIGN KEY ("room") REFERENCES "public"."rooms" ("id") DEFERRABLE INITIALLY DEFERRED`, plan.Changes[0].Cmd)
+}
+
+func TestPlanChanges_IndexStorageParams(t *testing.T) {
+	var (
+		c   = schema.NewIntColumn("id", "int")
+		tbl = schema.NewTable("t").SetSchema(schema.New("public")).AddColumns(c)
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: tbl,
+			Changes: []schema.Change{
+				&schema.AddIndex{I: schema.NewIndex("t_btree").AddColumns(c).AddAttrs(&IndexStorageParams{FillFactor: 70, Deduplicate: "OFF"})},
+				&schema.AddIndex{I: schema.NewIndex("t_gist").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeGiST}, &IndexStorageParams{FillFactor: 90, Buffering: bufferingOn})},
+				&schema.AddIndex{I: schema.NewIndex("t_gin").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeGIN}, &IndexStorageParams{FastUpdate: "OFF", ListLimit: 1024})},
+				&schema.AddIndex{I: schema.NewIndex("t_default").AddColumns(c).AddAttrs(&IndexStorageParams{FillFactor: 90, Deduplicate: "ON"})},
+				&schema.AddIndex{I: schema.NewIndex("t_ivfflat").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeIVFFlat}, &IndexStorageParams{Lists: 50})},
+				&schema.AddIndex{I: schema.NewIndex("t_hnsw").AddColumns(c).AddAttrs(&IndexType{T: "hnsw"}, &IndexStorageParams{M: 32, EfConstruction: 64})},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE INDEX "t_btree" ON "public"."t" ("id") WITH (fillfactor = 70, deduplicate_items = off)`, `DROP INDEX "public"."t_btree"`},
+		{`CREATE INDEX "t_gist" ON "public"."t" USING GIST ("id") WITH (buffering = on)`, `DROP INDEX "public"."t_gist"`},
+		{`CREATE INDEX "t_gin" ON "public"."t" USING GIN ("id") WITH (fastupdate = off, gin_pending_list_limit = 1024)`, `DROP INDEX "public"."t_gin"`},
+		{`CREATE INDEX "t_default" ON "public"."t" ("id")`, `DROP INDEX "public"."t_default"`},
+		{`CREATE INDEX "t_ivfflat" ON "public"."t" USING IVFFLAT ("id") WITH (lists = 50)`, `DROP INDEX "public"."t_ivfflat"`},
+		{`CREATE INDEX "t_hnsw" ON "public"."t" USING hnsw ("id") WITH (m = 32)`, `DROP INDEX "public"."t_hnsw"`},
+	}, planCmds(plan))
+}
+
+func TestPlanChanges_ConstraintComments(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		c  = schema.NewIntColumn("id", "int")
+		t1 = schema.NewTable("t1").SetSchema(s).AddColumns(c)
+		fk = schema.NewForeignKey("t1_id_fkey").SetTable(t1).AddColumns(c).SetRefTable(t1).AddRefColumns(c)
+		ck = schema.NewCheck().SetName("t1_id_check").SetExpr("id > 0")
+	)
+	fk.AddAttrs(&schema.Comment{Text: "self reference"})
+	t1.AddForeignKeys(fk).AddChecks(ck.AddAttrs(&schema.Comment{Text: "positive"}))
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: t1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "public"."t1" ("id" integer NOT NULL, CONSTRAINT "t1_id_fkey" FOREIGN KEY ("id") REFERENCES "public"."t1" ("id"), CONSTRAINT "t1_id_check" CHECK (id > 0))`, `DROP TABLE "public"."t1"`},
+		{`COMMENT ON CONSTRAINT "t1_id_fkey" ON "public"."t1" IS 'self reference'`, `COMMENT ON CONSTRAINT "t1_id_fkey" ON "public"."t1" IS ''`},
+		{`COMMENT ON CONSTRAINT "t1_id_check" ON "public"."t1" IS 'positive'`, `COMMENT ON CONSTRAINT "t1_id_check" ON "public"."t1" IS ''`},
+	}, planCmds(plan))
+
+	// Comment changes do not recreate the constraints.
+	fk2 := schema.NewForeignKey("t1_id_fkey").SetTable(t1).AddColumns(c).SetRefTable(t1).AddRefColumns(c)
+	fk2.AddAttrs(&schema.Comment{Text: "parent"})
+	ck2 := schema.NewCheck().SetName("t1_id_check").SetExpr("id > 0")
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: t1,
+			Changes: []schema.Change{
+				&schema.ModifyForeignKey{From: fk, To: fk2, Change: schema.ChangeAttr},
+				&schema.ModifyCheck{From: ck, To: ck2},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`COMMENT ON CONSTRAINT "t1_id_fkey" ON "public"."t1" IS 'parent'`, `COMMENT ON CONSTRAINT "t1_id_fkey" ON "public"."t1" IS 'self reference'`},
+		{`COMMENT ON CONSTRAINT "t1_id_check" ON "public"."t1" IS ''`, `COMMENT ON CONSTRAINT "t1_id_check" ON "public"."t1" IS 'positive'`},
+	}, planCmds(plan))
+
+	// Enum comments.
+	e1 := &schema.EnumType{T: "status", Schema: s, Values: []string{"on"}}
+	e2 := &schema.EnumType{T: "status", Schema: s, Values: []string{"on", "off"}, Attrs: []schema.Attr{&schema.Comment{Text: "state"}}}
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: e1, To: e2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`ALTER TYPE "public"."status" ADD VALUE 'off'`, ``},
+		{`COMMENT ON TYPE "public"."status" IS 'state'`, `COMMENT ON TYPE "public"."status" IS ''`},
+	}, planCmds(plan))
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: e2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TYPE "public"."status" AS ENUM ('on', 'off')`, `DROP TYPE "public"."status"`},
+		{`COMMENT ON TYPE "public"."status" IS 'state'`, `COMMENT ON TYPE "public"."status" IS ''`},
+	}, planCmds(plan))
+}
+
+func TestPlanChanges_DataChange(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		name   = schema.NewStringColumn("name", "text")
+		email  = schema.NewNullStringColumn("email", "text")
+		users  = schema.NewTable("users").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"), name, email)
+		email2 = schema.NewStringColumn("email", "text")
+	)
+	// Add a NOT NULL column with a backfill.
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: name},
+				&schema.DataChange{T: users, C: name, Expr: &schema.RawExpr{X: "'unknown'"}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`ALTER TABLE "public"."users" ADD COLUMN "name" text NULL`, `ALTER TABLE "public"."users" DROP COLUMN "name"`},
+		{`UPDATE "public"."users" SET "name" = 'unknown' WHERE "name" IS NULL`, ``},
+		{`ALTER TABLE "public"."users" ALTER COLUMN "name" SET NOT NULL`, `ALTER TABLE "public"."users" ALTER COLUMN "name" DROP NOT NULL`},
+	}, planCmds(plan))
+	require.Equal(t, `backfill "name" column of "users" table`, plan.Changes[1].Comment)
+	require.False(t, plan.Reversible)
+
+	// Modify a column to NOT NULL with a backfill.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: email, To: email2, Change: schema.ChangeNull},
+				&schema.DataChange{T: users, C: email2, Stmt: `UPDATE {{ .Table }} SET {{ .Column }} = concat("id", '@example.com') WHERE {{ .Column }} IS NULL`, Comment: "fill missing emails"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`UPDATE "public"."users" SET "email" = concat("id", '@example.com') WHERE "email" IS NULL`, ``},
+		{`ALTER TABLE "public"."users" ALTER COLUMN "email" SET NOT NULL`, `ALTER TABLE "public"."users" ALTER COLUMN "email" DROP NOT NULL`},
+	}, planCmds(plan))
+	require.Equal(t, "fill missing emails", plan.Changes[0].Comment)
+
+	// Top-level data changes.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DataChange{T: users, Stmt: "DELETE FROM {{ .Table }} WHERE id < 0"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{{`DELETE FROM "public"."users" WHERE id < 0`, ``}}, planCmds(plan))
+	require.Equal(t, `migrate data of "users" table`, plan.Changes[0].Comment)
+
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DataChange{T: users, C: name},
+	})
+	require.EqualError(t, err, `data change of table "users": missing statement or backfill expression`)
+}
+
+func TestPlanChanges_Identity(t *testing.T) {
+	var (
+		i64 = func(v int64) *int64 { return &v }
+		c1  = schema.NewIntColumn("id", "integer").AddAttrs(&Identity{Generation: GeneratedTypeAlways, Sequence: &Sequence{Start: 10, Max: i64(1000), Cache: 20, Cycle: true}})
+		t1  = schema.NewTable("t1").SetSchema(schema.New("public")).AddColumns(c1)
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: t1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "public"."t1" ("id" integer NOT NULL GENERATED ALWAYS AS IDENTITY (START WITH 10 MAXVALUE 1000 CACHE 20 CYCLE))`, `DROP TABLE "public"."t1"`},
+	}, planCmds(plan))
+
+	// Identity options are altered in place.
+	c2 := schema.NewIntColumn("id", "integer").AddAttrs(&Identity{Generation: "BY DEFAULT", Sequence: &Sequence{Start: 10, Cache: 20, Min: i64(5)}})
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: t1,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: c1, To: c2, Change: schema.ChangeAttr},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{
+			`ALTER TABLE "public"."t1" ALTER COLUMN "id" SET GENERATED BY DEFAULT SET START WITH 10 SET INCREMENT BY 1 SET MINVALUE 5 SET NO MAXVALUE SET NO CYCLE RESTART`,
+			`ALTER TABLE "public"."t1" ALTER COLUMN "id" SET GENERATED ALWAYS SET START WITH 10 SET INCREMENT BY 1 SET NO MINVALUE SET MAXVALUE 1000 SET CYCLE RESTART`,
+		},
+	}, planCmds(plan))
+
+	// Identity is added to or dropped from existing columns.
+	c3 := schema.NewIntColumn("id", "integer")
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: t1,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: c3, To: c1, Change: schema.ChangeAttr},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{
+			`ALTER TABLE "public"."t1" ALTER COLUMN "id" ADD GENERATED ALWAYS AS IDENTITY (START WITH 10 MAXVALUE 1000 CACHE 20 CYCLE)`,
+			`ALTER TABLE "public"."t1" ALTER COLUMN "id" DROP IDENTITY`,
+		},
+	}, planCmds(plan))
+}
+
+func TestPlanChanges_Replay(t *testing.T) {
+	from := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", TypeInteger), schema.NewStringColumn("name", TypeVarChar, schema.StringSize(100)))
+	from.SetPrimaryKey(schema.NewPrimaryKey(from.Columns[0]))
+	from.AddIndexes(schema.NewIndex("users_name").AddColumns(from.Columns[1]))
+	to := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", TypeBigInt),
+			schema.NewStringColumn("name", TypeVarChar, schema.StringSize(255)),
+			schema.NewNullStringColumn("email", TypeText).SetComment("contact email"),
+		)
+	to.SetPrimaryKey(schema.NewPrimaryKey(to.Columns[0]))
+	to.AddIndexes(schema.NewUniqueIndex("users_email").AddColumns(to.Columns[2]))
+	posts := schema.NewTable("posts").AddColumns(schema.NewIntColumn("id", TypeInteger), schema.NewIntColumn("author_id", TypeBigInt))
+	posts.AddForeignKeys(schema.NewForeignKey("author").AddColumns(posts.Columns[1]).SetRefTable(to).AddRefColumns(to.Columns[0]).SetOnDelete(schema.Cascade))
+	changes, err := DefaultDiff.RealmDiff(
+		schema.NewRealm(schema.New("public").AddTables(from)),
+		schema.NewRealm(schema.New("public").AddTables(to, posts)),
+	)
+	require.NoError(t, err)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "replay", changes)
+	require.NoError(t, err)
+
+	// Changes decoded from JSON are planned the same.
+	b, err := schema.MarshalChanges(changes)
+	require.NoError(t, err)
+	decoded, err := schema.UnmarshalChanges(b)
+	require.NoError(t, err)
+	replay, err := DefaultPlan.PlanChanges(context.Background(), "replay", decoded)
+	require.NoError(t, err)
+	require.Equal(t, len(plan.Changes), len(replay.Changes))
+	for i := range plan.Changes {
+		require.Equal(t, plan.Changes[i].Cmd, replay.Changes[i].Cmd)
+		require.Equal(t, plan.Changes[i].Reverse, replay.Changes[i].Reverse)
+	}
+
+	// Plans are decoded with their sources.
+	b, err = migrate.MarshalPlan(plan)
+	require.NoError(t, err)
+	got, err := migrate.UnmarshalPlan(b)
+	require.NoError(t, err)
+	require.Equal(t, plan.Name, got.Name)
+	require.Equal(t, plan.Transactional, got.Transactional)
+	require.Len(t, got.Changes, len(plan.Changes))
+	for i := range plan.Changes {
+		require.Equal(t, plan.Changes[i].Cmd, got.Changes[i].Cmd)
+		require.Equal(t, plan.Changes[i].Comment, got.Changes[i].Comment)
+		require.Equal(t, plan.Changes[i].Reverse, got.Changes[i].Reverse)
+		require.IsType(t, plan.Changes[i].Source, got.Changes[i].Source)
+	}
+	b2, err := migrate.MarshalPlan(got)
+	require.NoError(t, err)
+	require.Equal(t, string(b), string(b2))
+}
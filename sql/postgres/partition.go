@@ -0,0 +1,291 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// PartitionPolicy describes how the partitions of a partitioned table are
+// maintained. The policy has no representation in the database, it is not
+// inspected nor diffed, and is used only by the partition maintenance helpers
+// (e.g., Driver.PlanPartitions) and when creating new partitioned tables.
+type PartitionPolicy struct {
+	schema.Attr
+	// Interval defines the range of each partition of a RANGE partitioned
+	// table. Can be one of: hour, day, week, month or year.
+	Interval string
+	// Premake defines the number of partitions to create ahead,
+	// in addition to the one that holds the current time.
+	Premake int
+	// Default indicates that a DEFAULT partition is maintained
+	// for rows that do not fit into any other partition.
+	Default bool
+	// Concurrently indicates that partitions are detached
+	// with the CONCURRENTLY option (PostgreSQL 14 and above).
+	Concurrently bool
+}
+
+// List of partition policy intervals.
+const (
+	PartitionIntervalHour  = "hour"
+	PartitionIntervalDay   = "day"
+	PartitionIntervalWeek  = "week"
+	PartitionIntervalMonth = "month"
+	PartitionIntervalYear  = "year"
+)
+
+// PlanPartitions returns a migration plan for creating the partitions of the given table,
+// according to its PartitionPolicy: the DEFAULT partition (if configured), the RANGE partition
+// holding the given time, and the next Premake partitions. Partitions are created with the
+// IF NOT EXISTS clause, so the plan can be executed periodically. Partition bounds are
+// computed in the location of the given time.
+func (d *Driver) PlanPartitions(t *schema.Table, now time.Time, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := d.partitionState(fmt.Sprintf("partitions_%s", t.Name), opts...)
+	p, pp, err := partitionPolicy(t)
+	if err != nil {
+		return nil, err
+	}
+	if pp.Default {
+		s.append(s.defaultPartition(nil, t, true))
+	}
+	if pp.Interval == "" {
+		return &s.Plan, nil
+	}
+	if strings.ToUpper(p.T) != PartitionTypeRange || len(p.Parts) != 1 {
+		return nil, fmt.Errorf("postgres: partition interval of table %q requires a RANGE partition key with a single part", t.Name)
+	}
+	start, err := intervalStart(pp.Interval, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i <= pp.Premake; i++ {
+		end := intervalNext(pp.Interval, start)
+		name := fmt.Sprintf("%s_p%s", t.Name, intervalSuffix(pp.Interval, start))
+		b := s.Build("CREATE TABLE IF NOT EXISTS").Table(partitionOf(t, name)).P("PARTITION OF").Table(t).
+			P("FOR VALUES FROM").Wrap(func(b *sqlx.Builder) { b.P(quote(start.Format(time.DateTime))) }).
+			P("TO").Wrap(func(b *sqlx.Builder) { b.P(quote(end.Format(time.DateTime))) })
+		s.append(&migrate.Change{
+			Cmd:     b.String(),
+			Reverse: s.Build("DROP TABLE IF EXISTS").Table(partitionOf(t, name)).String(),
+			Comment: fmt.Sprintf("create partition %q of table %q", name, t.Name),
+		})
+		start = end
+	}
+	return &s.Plan, nil
+}
+
+// PlanAttachPartition returns a migration plan for attaching the given table as a partition
+// of the parent table with the given bound (e.g. "FOR VALUES IN (1, 2)" or "DEFAULT"). Note,
+// PostgreSQL does not support attaching partitions concurrently, but starting with version 12,
+// ATTACH PARTITION takes only a SHARE UPDATE EXCLUSIVE lock on the parent table.
+func (d *Driver) PlanAttachPartition(parent, child *schema.Table, bound string, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	if _, _, err := partitionPolicy(parent); err != nil {
+		return nil, err
+	}
+	if bound = strings.TrimSpace(bound); bound == "" {
+		return nil, fmt.Errorf("postgres: missing partition bound for %q", child.Name)
+	}
+	s := d.partitionState(fmt.Sprintf("attach_%s", child.Name), opts...)
+	s.append(&migrate.Change{
+		Cmd:     s.Build("ALTER TABLE").Table(parent).P("ATTACH PARTITION").Table(child).P(bound).String(),
+		Reverse: s.Build("ALTER TABLE").Table(parent).P("DETACH PARTITION").Table(child).String(),
+		Comment: fmt.Sprintf("attach partition %q to table %q", child.Name, parent.Name),
+	})
+	return &s.Plan, nil
+}
+
+// PlanDetachPartition returns a migration plan for detaching the given partition from its
+// parent table. In case the PartitionPolicy of the parent table is configured to detach
+// partitions concurrently, the plan is not transactional, as DETACH PARTITION CONCURRENTLY
+// cannot be executed inside a transaction block. The plan is not reversible, because the
+// partition bound of the detached table is unknown to the planner.
+func (d *Driver) PlanDetachPartition(parent, child *schema.Table, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	_, pp, err := partitionPolicy(parent)
+	if err != nil {
+		return nil, err
+	}
+	s := d.partitionState(fmt.Sprintf("detach_%s", child.Name), opts...)
+	b := s.Build("ALTER TABLE").Table(parent).P("DETACH PARTITION").Table(child)
+	if pp.Concurrently {
+		switch {
+		case d.version < 14_00_00:
+			return nil, fmt.Errorf("postgres: DETACH PARTITION CONCURRENTLY is not supported by version %d", d.version)
+		case pp.Default:
+			return nil, fmt.Errorf("postgres: DETACH PARTITION CONCURRENTLY cannot be used when table %q has a default partition", parent.Name)
+		}
+		b.P("CONCURRENTLY")
+		s.Transactional = false
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Comment: fmt.Sprintf("detach partition %q from table %q", child.Name, parent.Name),
+	})
+	return &s.Plan, nil
+}
+
+// partitionState returns a new planning state for the partition helpers.
+func (d *Driver) partitionState(name string, opts ...migrate.PlanOption) *state {
+	s := &state{
+		conn: d.conn,
+		Plan: migrate.Plan{
+			Name:          name,
+			Transactional: true,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	return s
+}
+
+// addDefaultPartition creates the DEFAULT partition of new
+// tables in case it is configured by their partition policy.
+func (s *state) addDefaultPartition(add *schema.AddTable) {
+	if pp := (PartitionPolicy{}); sqlx.Has(add.T.Attrs, &pp) && pp.Default && sqlx.Has(add.T.Attrs, &Partition{}) {
+		s.append(s.defaultPartition(add, add.T, false))
+	}
+}
+
+func (s *state) defaultPartition(src schema.Change, t *schema.Table, ifNotExists bool) *migrate.Change {
+	name := partitionOf(t, fmt.Sprintf("%s_default", t.Name))
+	b := s.Build("CREATE TABLE")
+	if ifNotExists {
+		b.P("IF NOT EXISTS")
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Table(name).P("PARTITION OF").Table(t).P("DEFAULT").String(),
+		Reverse: s.Build("DROP TABLE").Table(name).String(),
+		Comment: fmt.Sprintf("create default partition %q of table %q", name.Name, t.Name),
+	}
+}
+
+// partitionPolicy returns the partition key and policy of the given table.
+func partitionPolicy(t *schema.Table) (*Partition, *PartitionPolicy, error) {
+	var (
+		p  Partition
+		pp PartitionPolicy
+	)
+	if !sqlx.Has(t.Attrs, &p) {
+		return nil, nil, fmt.Errorf("postgres: table %q is not partitioned", t.Name)
+	}
+	sqlx.Has(t.Attrs, &pp)
+	return &p, &pp, nil
+}
+
+// partitionOf returns a table that represents a partition of the given table.
+func partitionOf(t *schema.Table, name string) *schema.Table {
+	return &schema.Table{Name: name, Schema: t.Schema}
+}
+
+// intervalStart returns the start of the interval that holds the given time.
+func intervalStart(interval string, t time.Time) (time.Time, error) {
+	y, m, d := t.Date()
+	switch strings.ToLower(interval) {
+	case PartitionIntervalHour:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location()), nil
+	case PartitionIntervalDay:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location()), nil
+	case PartitionIntervalWeek:
+		// Weeks start on Monday, according to ISO 8601.
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location()), nil
+	case PartitionIntervalMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location()), nil
+	case PartitionIntervalYear:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location()), nil
+	default:
+		return time.Time{}, fmt.Errorf("postgres: unknown partition interval: %q", interval)
+	}
+}
+
+// intervalNext returns the start of the interval that follows the given one.
+func intervalNext(interval string, t time.Time) time.Time {
+	switch strings.ToLower(interval) {
+	case PartitionIntervalHour:
+		return t.Add(time.Hour)
+	case PartitionIntervalDay:
+		return t.AddDate(0, 0, 1)
+	case PartitionIntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case PartitionIntervalMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(1, 0, 0)
+	}
+}
+
+// intervalSuffix returns the partition name suffix of the interval starting at the given time.
+func intervalSuffix(interval string, t time.Time) string {
+	switch strings.ToLower(interval) {
+	case PartitionIntervalHour:
+		return t.Format("2006010215")
+	case PartitionIntervalDay, PartitionIntervalWeek:
+		return t.Format("20060102")
+	case PartitionIntervalMonth:
+		return t.Format("200601")
+	default:
+		return t.Format("2006")
+	}
+}
+
+// convertPartitionPolicy converts the "policy" block of the partition spec.
+func convertPartitionPolicy(r *schemahcl.Resource, t *schema.Table) error {
+	var spec struct {
+		Interval     string `spec:"interval"`
+		Premake      int    `spec:"premake"`
+		Default      bool   `spec:"default"`
+		Concurrently bool   `spec:"concurrently"`
+	}
+	if err := r.As(&spec); err != nil {
+		return fmt.Errorf("parsing %s.partition.policy: %w", t.Name, err)
+	}
+	if spec.Interval != "" {
+		if _, err := intervalStart(spec.Interval, time.Time{}); err != nil {
+			return fmt.Errorf("parsing %s.partition.policy: %w", t.Name, err)
+		}
+	}
+	if spec.Premake < 0 {
+		return errors.New("partition policy premake must be a non-negative number")
+	}
+	t.AddAttrs(&PartitionPolicy{
+		Interval:     strings.ToLower(spec.Interval),
+		Premake:      spec.Premake,
+		Default:      spec.Default,
+		Concurrently: spec.Concurrently,
+	})
+	return nil
+}
+
+// partitionPolicySpec returns the "policy" block of the partition spec, if configured.
+func partitionPolicySpec(t *schema.Table) (*schemahcl.Resource, bool) {
+	var pp PartitionPolicy
+	if !sqlx.Has(t.Attrs, &pp) {
+		return nil, false
+	}
+	r := &schemahcl.Resource{Type: "policy"}
+	if pp.Interval != "" {
+		r.SetAttr(specutil.VarAttr("interval", pp.Interval))
+	}
+	if pp.Premake > 0 {
+		r.SetAttr(schemahcl.IntAttr("premake", pp.Premake))
+	}
+	if pp.Default {
+		r.SetAttr(schemahcl.BoolAttr("default", true))
+	}
+	if pp.Concurrently {
+		r.SetAttr(schemahcl.BoolAttr("concurrently", true))
+	}
+	return r, true
+}
@@ -0,0 +1,197 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_PlanPartitions(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("140000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	var (
+		s   = schema.New("public")
+		c   = schema.NewTimeColumn("created_at", TypeTimestamp)
+		tbl = schema.NewTable("logs").SetSchema(s).AddColumns(c)
+		now = time.Date(2023, time.February, 15, 10, 30, 0, 0, time.UTC)
+	)
+	_, err = drv.(*Driver).PlanPartitions(tbl, now)
+	require.EqualError(t, err, `postgres: table "logs" is not partitioned`)
+
+	tbl.AddAttrs(&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: c}}})
+	plan, err := drv.(*Driver).PlanPartitions(tbl, now)
+	require.NoError(t, err)
+	require.Empty(t, plan.Changes, "no policy")
+
+	tbl.AddAttrs(&PartitionPolicy{Interval: PartitionIntervalMonth, Premake: 2, Default: true})
+	plan, err = drv.(*Driver).PlanPartitions(tbl, now)
+	require.NoError(t, err)
+	require.True(t, plan.Transactional)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE IF NOT EXISTS "public"."logs_default" PARTITION OF "public"."logs" DEFAULT`, `DROP TABLE "public"."logs_default"`},
+		{`CREATE TABLE IF NOT EXISTS "public"."logs_p202302" PARTITION OF "public"."logs" FOR VALUES FROM ('2023-02-01 00:00:00') TO ('2023-03-01 00:00:00')`, `DROP TABLE IF EXISTS "public"."logs_p202302"`},
+		{`CREATE TABLE IF NOT EXISTS "public"."logs_p202303" PARTITION OF "public"."logs" FOR VALUES FROM ('2023-03-01 00:00:00') TO ('2023-04-01 00:00:00')`, `DROP TABLE IF EXISTS "public"."logs_p202303"`},
+		{`CREATE TABLE IF NOT EXISTS "public"."logs_p202304" PARTITION OF "public"."logs" FOR VALUES FROM ('2023-04-01 00:00:00') TO ('2023-05-01 00:00:00')`, `DROP TABLE IF EXISTS "public"."logs_p202304"`},
+	}, planCmds(plan))
+
+	tbl.Attrs[len(tbl.Attrs)-1] = &PartitionPolicy{Interval: PartitionIntervalWeek}
+	plan, err = drv.(*Driver).PlanPartitions(tbl, now, func(o *migrate.PlanOptions) { o.SchemaQualifier = new(string) })
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE IF NOT EXISTS "logs_p20230213" PARTITION OF "logs" FOR VALUES FROM ('2023-02-13 00:00:00') TO ('2023-02-20 00:00:00')`, `DROP TABLE IF EXISTS "logs_p20230213"`},
+	}, planCmds(plan))
+
+	tbl.Attrs[len(tbl.Attrs)-1] = &PartitionPolicy{Interval: "decade"}
+	_, err = drv.(*Driver).PlanPartitions(tbl, now)
+	require.EqualError(t, err, `postgres: unknown partition interval: "decade"`)
+
+	tbl.Attrs = []schema.Attr{&Partition{T: PartitionTypeList, Parts: []*PartitionPart{{C: c}}}, &PartitionPolicy{Interval: PartitionIntervalDay}}
+	_, err = drv.(*Driver).PlanPartitions(tbl, now)
+	require.EqualError(t, err, `postgres: partition interval of table "logs" requires a RANGE partition key with a single part`)
+}
+
+func TestDriver_PlanAttachDetachPartition(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		c      = schema.NewIntColumn("id", TypeInteger)
+		parent = schema.NewTable("t").SetSchema(s).AddColumns(c).AddAttrs(&Partition{T: PartitionTypeList, Parts: []*PartitionPart{{C: c}}})
+		child  = schema.NewTable("t_1").SetSchema(s)
+	)
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	d := drv.(*Driver)
+
+	_, err = d.PlanAttachPartition(parent, child, " ")
+	require.EqualError(t, err, `postgres: missing partition bound for "t_1"`)
+	_, err = d.PlanAttachPartition(child, parent, "DEFAULT")
+	require.EqualError(t, err, `postgres: table "t_1" is not partitioned`)
+	plan, err := d.PlanAttachPartition(parent, child, "FOR VALUES IN (1, 2)")
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`ALTER TABLE "public"."t" ATTACH PARTITION "public"."t_1" FOR VALUES IN (1, 2)`, `ALTER TABLE "public"."t" DETACH PARTITION "public"."t_1"`},
+	}, planCmds(plan))
+
+	plan, err = d.PlanDetachPartition(parent, child)
+	require.NoError(t, err)
+	require.True(t, plan.Transactional)
+	require.Equal(t, [][2]string{
+		{`ALTER TABLE "public"."t" DETACH PARTITION "public"."t_1"`, ""},
+	}, planCmds(plan))
+
+	parent.AddAttrs(&PartitionPolicy{Concurrently: true})
+	_, err = d.PlanDetachPartition(parent, child)
+	require.EqualError(t, err, `postgres: DETACH PARTITION CONCURRENTLY is not supported by version 130000`)
+
+	d.conn.version = 14_00_00
+	plan, err = d.PlanDetachPartition(parent, child)
+	require.NoError(t, err)
+	require.False(t, plan.Transactional)
+	require.Equal(t, [][2]string{
+		{`ALTER TABLE "public"."t" DETACH PARTITION "public"."t_1" CONCURRENTLY`, ""},
+	}, planCmds(plan))
+
+	parent.Attrs[len(parent.Attrs)-1] = &PartitionPolicy{Concurrently: true, Default: true}
+	_, err = d.PlanDetachPartition(parent, child)
+	require.EqualError(t, err, `postgres: DETACH PARTITION CONCURRENTLY cannot be used when table "t" has a default partition`)
+}
+
+func TestPlanChanges_DefaultPartition(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		c   = schema.NewIntColumn("id", TypeInteger)
+		tbl = schema.NewTable("t").SetSchema(s).AddColumns(c).
+			AddAttrs(&Partition{T: PartitionTypeList, Parts: []*PartitionPart{{C: c}}}, &PartitionPolicy{Default: true})
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: tbl}})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "public"."t" ("id" integer NOT NULL) PARTITION BY LIST ("id")`, `DROP TABLE "public"."t"`},
+		{`CREATE TABLE "public"."t_default" PARTITION OF "public"."t" DEFAULT`, `DROP TABLE "public"."t_default"`},
+	}, planCmds(plan))
+}
+
+func TestMarshalSpec_PartitionPolicy(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		c   = schema.NewTimeColumn("created_at", TypeTimestamp)
+		tbl = schema.NewTable("logs").SetSchema(s).AddColumns(c).
+			AddAttrs(
+				&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: c}}},
+				&PartitionPolicy{Interval: PartitionIntervalDay, Premake: 3, Default: true, Concurrently: true},
+			)
+	)
+	s.AddTables(tbl)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "logs" {
+  schema = schema.public
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  partition {
+    type    = RANGE
+    columns = [column.created_at]
+    policy {
+      interval     = day
+      premake      = 3
+      default      = true
+      concurrently = true
+    }
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	var pp PartitionPolicy
+	require.True(t, sqlx.Has(got.Tables[0].Attrs, &pp))
+	require.Equal(t, PartitionPolicy{Interval: PartitionIntervalDay, Premake: 3, Default: true, Concurrently: true}, pp)
+
+	err = EvalHCLBytes([]byte(`
+table "logs" {
+  schema = schema.public
+  column "created_at" {
+    type = timestamp
+  }
+  partition {
+    type    = RANGE
+    columns = [column.created_at]
+    policy {
+      interval = "decade"
+    }
+  }
+}
+schema "public" {
+}
+`), &got, nil)
+	require.EqualError(t, err, `cannot convert table "logs": parsing logs.partition.policy: postgres: unknown partition interval: "decade"`)
+}
+
+func planCmds(p *migrate.Plan) [][2]string {
+	cmds := make([][2]string, 0, len(p.Changes))
+	for _, c := range p.Changes {
+		r, _ := c.Reverse.(string)
+		cmds = append(cmds, [2]string{c.Cmd, r})
+	}
+	return cmds
+}
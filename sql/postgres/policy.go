@@ -0,0 +1,456 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// Policy defines a row-level security policy of a table.
+	// https://www.postgresql.org/docs/current/sql-createpolicy.html
+	Policy struct {
+		schema.Attr
+		Name  string
+		Table *schema.Table
+		As    string   // PERMISSIVE (default) or RESTRICTIVE.
+		For   string   // ALL (default), SELECT, INSERT, UPDATE or DELETE.
+		To    []string // Roles the policy applies to. An empty list means PUBLIC.
+		Using string   // Optional USING expression.
+		Check string   // Optional WITH CHECK expression.
+	}
+
+	// RowSecurity describes the row-level security state of a table.
+	// https://www.postgresql.org/docs/current/ddl-rowsecurity.html
+	RowSecurity struct {
+		schema.Attr
+		Enabled  bool // ENABLE ROW LEVEL SECURITY.
+		Enforced bool // FORCE ROW LEVEL SECURITY, applies to the table owner as well.
+	}
+)
+
+// List of policy commands and kinds.
+const (
+	PolicyAsPermissive  = "PERMISSIVE"
+	PolicyAsRestrictive = "RESTRICTIVE"
+	PolicyForAll        = "ALL"
+	PolicyForSelect     = "SELECT"
+	PolicyForInsert     = "INSERT"
+	PolicyForUpdate     = "UPDATE"
+	PolicyForDelete     = "DELETE"
+)
+
+// policyRoles lists the role specifications that are keywords, and not identifiers.
+var policyRoles = []string{"PUBLIC", "CURRENT_ROLE", "CURRENT_USER", "SESSION_USER"}
+
+// tablePolicies returns the policies defined on the table.
+func tablePolicies(t *schema.Table) []*Policy {
+	var ps []*Policy
+	for _, a := range t.Attrs {
+		if p, ok := a.(*Policy); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// policies queries and appends the policies of the given tables.
+func (i *inspect) policies(ctx context.Context, s *schema.Schema, tables []*schema.Table) error {
+	args := []any{s.Name}
+	for _, t := range tables {
+		args = append(args, t.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(policiesQuery, nArgs(1, len(tables))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q policies: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, as, cmd, roles, using, check sql.NullString
+		if err := rows.Scan(&table, &name, &as, &cmd, &roles, &using, &check); err != nil {
+			return fmt.Errorf("postgres: scanning policies: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			return fmt.Errorf("postgres: table %q for policy %q was not found in schema", table.String, name.String)
+		}
+		p := &Policy{Name: name.String, Table: t, As: as.String, For: cmd.String, Using: using.String, Check: check.String}
+		if sqlx.ValidString(roles) {
+			if err := json.Unmarshal([]byte(roles.String), &p.To); err != nil {
+				return fmt.Errorf("postgres: unmarshaling policy %q roles: %w", p.Name, err)
+			}
+		}
+		for j, r := range p.To {
+			// The PUBLIC pseudo-role is returned in lowercase.
+			if r == "public" {
+				p.To[j] = "PUBLIC"
+			}
+		}
+		t.AddAttrs(p)
+	}
+	return rows.Err()
+}
+
+// rlsDiff returns the row-level security changes between the two tables.
+func rlsDiff(from, to *schema.Table) []schema.Change {
+	var (
+		changes  []schema.Change
+		rs1, rs2 RowSecurity
+	)
+	sqlx.Has(from.Attrs, &rs1)
+	sqlx.Has(to.Attrs, &rs2)
+	if rs1.Enabled != rs2.Enabled || rs1.Enforced != rs2.Enforced {
+		changes = append(changes, &schema.ModifyAttr{From: &rs1, To: &rs2})
+	}
+	ps1, ps2 := tablePolicies(from), tablePolicies(to)
+	for _, p1 := range ps1 {
+		switch i := slices.IndexFunc(ps2, func(p2 *Policy) bool { return p1.Name == p2.Name }); {
+		case i == -1:
+			changes = append(changes, &schema.DropAttr{A: p1})
+		case policyChanged(p1, ps2[i]):
+			changes = append(changes, &schema.ModifyAttr{From: p1, To: ps2[i]})
+		}
+	}
+	for _, p2 := range ps2 {
+		if !slices.ContainsFunc(ps1, func(p1 *Policy) bool { return p1.Name == p2.Name }) {
+			changes = append(changes, &schema.AddAttr{A: p2})
+		}
+	}
+	return changes
+}
+
+// policyChanged reports if the policy was changed.
+func policyChanged(p1, p2 *Policy) bool {
+	return policyAs(p1) != policyAs(p2) || policyFor(p1) != policyFor(p2) ||
+		!slices.Equal(policyTo(p1), policyTo(p2)) ||
+		policyExpr(p1.Using) != policyExpr(p2.Using) || policyExpr(p1.Check) != policyExpr(p2.Check)
+}
+
+func policyAs(p *Policy) string {
+	if p.As == "" {
+		return PolicyAsPermissive
+	}
+	return strings.ToUpper(p.As)
+}
+
+func policyFor(p *Policy) string {
+	if p.For == "" {
+		return PolicyForAll
+	}
+	return strings.ToUpper(p.For)
+}
+
+func policyTo(p *Policy) []string {
+	if len(p.To) == 0 {
+		return []string{"PUBLIC"}
+	}
+	to := make([]string, len(p.To))
+	for i, r := range p.To {
+		if slices.Contains(policyRoles, strings.ToUpper(r)) {
+			r = strings.ToUpper(r)
+		}
+		to[i] = r
+	}
+	slices.Sort(to)
+	return to
+}
+
+// policyExpr returns the normalized form of a policy expression.
+func policyExpr(x string) string {
+	if x = strings.TrimSpace(x); x == "" {
+		return ""
+	}
+	return sqlx.MayWrap(x)
+}
+
+// rlsChange writes the row-level security changes to the ALTER TABLE builder.
+func rlsChange(b *sqlx.Builder, from, to *RowSecurity) {
+	switch {
+	case from.Enabled == to.Enabled:
+	case to.Enabled:
+		b.P("ENABLE ROW LEVEL SECURITY")
+	default:
+		b.P("DISABLE ROW LEVEL SECURITY")
+	}
+	if from.Enabled != to.Enabled && from.Enforced != to.Enforced {
+		b.Comma()
+	}
+	switch {
+	case from.Enforced == to.Enforced:
+	case to.Enforced:
+		b.P("FORCE ROW LEVEL SECURITY")
+	default:
+		b.P("NO FORCE ROW LEVEL SECURITY")
+	}
+}
+
+// addRowSecurity plans the row-level security state and the policies of a new table.
+func (s *state) addRowSecurity(add *schema.AddTable) {
+	if rs := (RowSecurity{}); sqlx.Has(add.T.Attrs, &rs) && (rs.Enabled || rs.Enforced) {
+		b, r := s.Build("ALTER TABLE").Table(add.T), s.Build("ALTER TABLE").Table(add.T)
+		rlsChange(b, &RowSecurity{}, &rs)
+		rlsChange(r, &rs, &RowSecurity{})
+		s.append(&migrate.Change{
+			Source:  add,
+			Cmd:     b.String(),
+			Reverse: r.String(),
+			Comment: fmt.Sprintf("enable row-level security for %q table", add.T.Name),
+		})
+	}
+	for _, p := range tablePolicies(add.T) {
+		s.append(s.createPolicy(add, add.T, p))
+	}
+}
+
+// isPolicyChange reports if the given table change is a policy change.
+func isPolicyChange(c schema.Change) bool {
+	switch c := c.(type) {
+	case *schema.AddAttr:
+		_, ok := c.A.(*Policy)
+		return ok
+	case *schema.DropAttr:
+		_, ok := c.A.(*Policy)
+		return ok
+	case *schema.ModifyAttr:
+		_, ok := c.From.(*Policy)
+		return ok
+	}
+	return false
+}
+
+// policyChanges plans the policy changes of the given table. Policies are dropped before the
+// table is altered (as they might depend on its columns), and created or altered after.
+func (s *state) policyChanges(src schema.Change, t *schema.Table, changes []schema.Change) (before, after []*migrate.Change) {
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddAttr:
+			after = append(after, s.createPolicy(src, t, c.A.(*Policy)))
+		case *schema.DropAttr:
+			before = append(before, s.dropPolicy(src, t, c.A.(*Policy)))
+		case *schema.ModifyAttr:
+			from, to := c.From.(*Policy), c.To.(*Policy)
+			// The kind and the command of a policy cannot be altered, and the
+			// expressions cannot be removed. Therefore, the policy is recreated.
+			if policyAs(from) != policyAs(to) || policyFor(from) != policyFor(to) ||
+				policyExpr(to.Using) == "" && policyExpr(from.Using) != "" ||
+				policyExpr(to.Check) == "" && policyExpr(from.Check) != "" {
+				before = append(before, s.dropPolicy(src, t, from))
+				after = append(after, s.createPolicy(src, t, to))
+				continue
+			}
+			after = append(after, &migrate.Change{
+				Source:  src,
+				Cmd:     s.alterPolicy(t, from, to),
+				Reverse: s.alterPolicy(t, to, from),
+				Comment: fmt.Sprintf("modify policy %q on table %q", to.Name, t.Name),
+			})
+		}
+	}
+	return before, after
+}
+
+func (s *state) createPolicy(src schema.Change, t *schema.Table, p *Policy) *migrate.Change {
+	b := s.Build("CREATE POLICY").Ident(p.Name).P("ON").Table(t)
+	if as := policyAs(p); as != PolicyAsPermissive {
+		b.P("AS", as)
+	}
+	if cmd := policyFor(p); cmd != PolicyForAll {
+		b.P("FOR", cmd)
+	}
+	if len(p.To) > 0 {
+		s.policyRoles(b.P("TO"), p.To)
+	}
+	if x := policyExpr(p.Using); x != "" {
+		b.P("USING", x)
+	}
+	if x := policyExpr(p.Check); x != "" {
+		b.P("WITH CHECK", x)
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.String(),
+		Reverse: s.Build("DROP POLICY").Ident(p.Name).P("ON").Table(t).String(),
+		Comment: fmt.Sprintf("create policy %q on table %q", p.Name, t.Name),
+	}
+}
+
+func (s *state) dropPolicy(src schema.Change, t *schema.Table, p *Policy) *migrate.Change {
+	c := s.createPolicy(src, t, p)
+	c.Cmd, c.Reverse = c.Reverse.(string), c.Cmd
+	c.Comment = fmt.Sprintf("drop policy %q from table %q", p.Name, t.Name)
+	return c
+}
+
+func (s *state) alterPolicy(t *schema.Table, from, to *Policy) string {
+	b := s.Build("ALTER POLICY").Ident(to.Name).P("ON").Table(t)
+	if !slices.Equal(policyTo(from), policyTo(to)) {
+		s.policyRoles(b.P("TO"), policyTo(to))
+	}
+	if x := policyExpr(to.Using); x != policyExpr(from.Using) {
+		b.P("USING", x)
+	}
+	if x := policyExpr(to.Check); x != policyExpr(from.Check) {
+		b.P("WITH CHECK", x)
+	}
+	return b.String()
+}
+
+func (s *state) policyRoles(b *sqlx.Builder, roles []string) {
+	b.MapComma(roles, func(i int, b *sqlx.Builder) {
+		if r := strings.ToUpper(roles[i]); slices.Contains(policyRoles, r) {
+			b.P(r)
+		} else {
+			b.Ident(roles[i])
+		}
+	})
+}
+
+// convertRowSecurity converts the "row_security" block of the table spec, if exists.
+func convertRowSecurity(spec *sqlspec.Table, t *schema.Table) error {
+	r, ok := spec.Extra.Resource("row_security")
+	if !ok {
+		return nil
+	}
+	var rs struct {
+		Enabled  bool `spec:"enabled"`
+		Enforced bool `spec:"enforced"`
+	}
+	if err := r.As(&rs); err != nil {
+		return fmt.Errorf("parsing %s.row_security: %w", t.Name, err)
+	}
+	t.AddAttrs(&RowSecurity{Enabled: rs.Enabled, Enforced: rs.Enforced})
+	return nil
+}
+
+// rowSecuritySpec appends the "row_security" block to the table spec, if needed.
+func rowSecuritySpec(t *schema.Table, spec *sqlspec.Table) {
+	if rs := (RowSecurity{}); sqlx.Has(t.Attrs, &rs) && (rs.Enabled || rs.Enforced) {
+		r := &schemahcl.Resource{Type: "row_security"}
+		r.SetAttr(schemahcl.BoolAttr("enabled", rs.Enabled))
+		if rs.Enforced {
+			r.SetAttr(schemahcl.BoolAttr("enforced", rs.Enforced))
+		}
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+}
+
+// convertPolicy converts the policy specs and attaches them to their tables.
+func convertPolicy(ps []*policy, r *schema.Realm) error {
+	for _, spec := range ps {
+		if spec.On == nil {
+			return fmt.Errorf("missing 'on' attribute for policy %q", spec.Name)
+		}
+		t, err := policyTable(r, spec.On)
+		if err != nil {
+			return fmt.Errorf("find table for policy %q: %w", spec.Name, err)
+		}
+		p := &Policy{Name: spec.Name, Table: t}
+		for k, v := range map[string]*string{"as": &p.As, "for": &p.For, "using": &p.Using, "check": &p.Check} {
+			if a, ok := spec.Attr(k); ok {
+				if *v, err = a.String(); err != nil {
+					return fmt.Errorf("parsing policy %q attribute %q: %w", spec.Name, k, err)
+				}
+			}
+		}
+		if a, ok := spec.Attr("to"); ok {
+			if p.To, err = a.Strings(); err != nil {
+				return fmt.Errorf("parsing policy %q attribute \"to\": %w", spec.Name, err)
+			}
+		}
+		t.AddAttrs(p)
+	}
+	return nil
+}
+
+// policyTable returns the table referenced by the policy.
+func policyTable(r *schema.Realm, ref *schemahcl.Ref) (*schema.Table, error) {
+	q, name, err := specutil.TableName(ref)
+	if err != nil {
+		return nil, err
+	}
+	var found []*schema.Table
+	for _, s := range r.Schemas {
+		if q != "" && s.Name != q {
+			continue
+		}
+		if t, ok := s.Table(name); ok {
+			found = append(found, t)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("table %q was not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("multiple tables found for %q, use a qualified reference", name)
+	}
+}
+
+// policySpecs returns the policy specs of the given tables.
+func policySpecs(tables []*schema.Table) []*policy {
+	var specs []*policy
+	for _, t := range tables {
+		for _, p := range tablePolicies(t) {
+			spec := &policy{Name: p.Name, On: specutil.TableSpecRef(t)}
+			if as := policyAs(p); as != PolicyAsPermissive {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("as", as))
+			}
+			if cmd := policyFor(p); cmd != PolicyForAll {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("for", cmd))
+			}
+			if len(p.To) > 0 {
+				roles := make([]*schemahcl.EnumString, len(p.To))
+				for i, r := range p.To {
+					if u := strings.ToUpper(r); slices.Contains(policyRoles, u) {
+						roles[i] = &schemahcl.EnumString{E: u}
+					} else {
+						roles[i] = &schemahcl.EnumString{S: r}
+					}
+				}
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringEnumsAttr("to", roles...))
+			}
+			if p.Using != "" {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("using", p.Using))
+			}
+			if p.Check != "" {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("check", p.Check))
+			}
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// Query to list the policies of the given tables.
+const policiesQuery = `
+SELECT
+	p.tablename AS table_name,
+	p.policyname AS policy_name,
+	p.permissive AS permissive,
+	p.cmd AS command,
+	array_to_json(p.roles) AS roles,
+	p.qual AS using_expr,
+	p.with_check AS check_expr
+FROM
+	pg_catalog.pg_policies AS p
+WHERE
+	p.schemaname = $1
+	AND p.tablename IN (%s)
+ORDER BY
+	p.tablename, p.policyname
+`
@@ -0,0 +1,210 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectPolicies(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= $1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	m.ExpectQuery(queryTables).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"oid", "table_schema", "table_name", "comment", "partition_attrs", "partition_strategy", "partition_exprs", "attrs"}).
+			AddRow(nil, "public", "users", nil, nil, nil, nil, `{"rls": true, "force_rls": false, "policies": 1}`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "$2"))).
+		WillReturnRows(sqltest.Rows(`
+table_name |column_name | data_type | formatted | is_nullable | column_default | character_maximum_length | numeric_precision | datetime_precision | numeric_scale | interval_type | character_set_name | collation_name | is_identity | identity_start | identity_increment |   identity_last  | identity_generation | generation_expression | comment | typtype | typelem |  oid |  attnum
+-----------+------------+-----------+-----------+-------------+----------------+--------------------------+-------------------+--------------------+---------------+---------------+--------------------+----------------+-------------+----------------+--------------------+------------------+---------------------+-----------------------+---------+---------+---------+------+--------
+users      | tenant     | integer   | int4      | NO          |                |                          |                32 |                    |             0 |               |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |   23 |
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesAbove11, "$2"))).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "column_name", "primary", "unique", "constraint_type", "predicate", "expression", "options", "indnullsnotdistinct"}))
+	mk.noFKs()
+	mk.noChecks()
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(policiesQuery, "$2"))).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
+ table_name | policy_name | permissive  | command |        roles        |         using_expr          | check_expr
+------------+-------------+-------------+---------+---------------------+-----------------------------+------------
+ users      | tenant      | RESTRICTIVE | SELECT  | ["public","reader"] | (tenant = current_tenant()) | nil
+`))
+	s, err := drv.InspectSchema(context.Background(), "public", &schema.InspectOptions{
+		Mode: schema.InspectSchemas | schema.InspectTables,
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+	tbl, ok := s.Table("users")
+	require.True(t, ok)
+	var rs RowSecurity
+	require.True(t, tablePoliciesHas(tbl, &rs))
+	require.Equal(t, RowSecurity{Enabled: true}, rs)
+	require.Equal(t, []*Policy{
+		{Name: "tenant", Table: tbl, As: PolicyAsRestrictive, For: PolicyForSelect, To: []string{"PUBLIC", "reader"}, Using: "(tenant = current_tenant())"},
+	}, tablePolicies(tbl))
+	require.False(t, tablePoliciesHas(tbl, &hasPolicies{}))
+}
+
+func tablePoliciesHas(t *schema.Table, a schema.Attr) bool {
+	for _, a1 := range t.Attrs {
+		switch a1 := a1.(type) {
+		case *RowSecurity:
+			if rs, ok := a.(*RowSecurity); ok {
+				*rs = *a1
+				return true
+			}
+		case *hasPolicies:
+			if _, ok := a.(*hasPolicies); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDiff_Policies(t *testing.T) {
+	var (
+		from = schema.NewTable("users").SetSchema(schema.New("public"))
+		to   = schema.NewTable("users").SetSchema(schema.New("public"))
+	)
+	from.AddAttrs(
+		&Policy{Name: "p1", Table: from, Using: "(a = 1)"},
+		&Policy{Name: "p2", Table: from, To: []string{"public"}},
+	)
+	to.AddAttrs(
+		&RowSecurity{Enabled: true},
+		&Policy{Name: "p1", Table: to, Using: "a = 1", For: "all", To: []string{"PUBLIC"}},
+		&Policy{Name: "p3", Table: to, Check: "true"},
+	)
+	changes := rlsDiff(from, to)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyAttr{From: &RowSecurity{}, To: &RowSecurity{Enabled: true}},
+		&schema.DropAttr{A: from.Attrs[1]},
+		&schema.AddAttr{A: to.Attrs[2]},
+	}, changes)
+	require.Empty(t, rlsDiff(to, to))
+}
+
+func TestPlanChanges_Policies(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		tbl = schema.NewTable("users").SetSchema(s).AddColumns(schema.NewIntColumn("tenant", "int"))
+		p1  = &Policy{Name: "p1", Table: tbl, As: PolicyAsRestrictive, For: PolicyForSelect, To: []string{"PUBLIC", "reader"}, Using: "tenant = 1"}
+		p2  = &Policy{Name: "p1", Table: tbl, As: PolicyAsRestrictive, For: PolicyForSelect, To: []string{"reader"}, Using: "tenant = 2"}
+		p3  = &Policy{Name: "p1", Table: tbl, For: PolicyForInsert, Check: "tenant > 0"}
+	)
+	tbl.AddAttrs(&RowSecurity{Enabled: true, Enforced: true}, p1)
+	tests := []struct {
+		changes []schema.Change
+		want    [][2]string
+	}{
+		{
+			changes: []schema.Change{&schema.AddTable{T: tbl}},
+			want: [][2]string{
+				{`CREATE TABLE "public"."users" ("tenant" integer NOT NULL)`, `DROP TABLE "public"."users"`},
+				{`ALTER TABLE "public"."users" ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY`, `ALTER TABLE "public"."users" DISABLE ROW LEVEL SECURITY, NO FORCE ROW LEVEL SECURITY`},
+				{`CREATE POLICY "p1" ON "public"."users" AS RESTRICTIVE FOR SELECT TO PUBLIC, "reader" USING (tenant = 1)`, `DROP POLICY "p1" ON "public"."users"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+				&schema.ModifyAttr{From: &RowSecurity{}, To: &RowSecurity{Enabled: true}},
+				&schema.ModifyAttr{From: p1, To: p2},
+			}}},
+			want: [][2]string{
+				{`ALTER TABLE "public"."users" ENABLE ROW LEVEL SECURITY`, `ALTER TABLE "public"."users" DISABLE ROW LEVEL SECURITY`},
+				{`ALTER POLICY "p1" ON "public"."users" TO "reader" USING (tenant = 2)`, `ALTER POLICY "p1" ON "public"."users" TO PUBLIC, "reader" USING (tenant = 1)`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+				&schema.ModifyAttr{From: p1, To: p3},
+			}}},
+			want: [][2]string{
+				{`DROP POLICY "p1" ON "public"."users"`, `CREATE POLICY "p1" ON "public"."users" AS RESTRICTIVE FOR SELECT TO PUBLIC, "reader" USING (tenant = 1)`},
+				{`CREATE POLICY "p1" ON "public"."users" FOR INSERT WITH CHECK (tenant > 0)`, `DROP POLICY "p1" ON "public"."users"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		db, mk, err := sqlmock.New()
+		require.NoError(t, err)
+		mock{mk}.version("130000")
+		drv, err := Open(db)
+		require.NoError(t, err)
+		plan, err := drv.PlanChanges(context.Background(), "plan", tt.changes)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, len(tt.want))
+		for i, c := range plan.Changes {
+			require.Equal(t, tt.want[i][0], c.Cmd)
+			require.Equal(t, tt.want[i][1], c.Reverse)
+		}
+	}
+}
+
+func TestMarshalSpec_Policies(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		tbl = schema.NewTable("users").AddColumns(schema.NewIntColumn("tenant", TypeInteger))
+	)
+	s.AddTables(tbl)
+	tbl.AddAttrs(
+		&RowSecurity{Enabled: true},
+		&Policy{Name: "p1", Table: tbl, As: PolicyAsRestrictive, For: PolicyForSelect, To: []string{"PUBLIC", "reader"}, Using: "(tenant = 1)"},
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "users" {
+  schema = schema.public
+  column "tenant" {
+    null = false
+    type = integer
+  }
+  row_security {
+    enabled = true
+  }
+}
+policy "p1" {
+  on    = table.users
+  as    = RESTRICTIVE
+  for   = SELECT
+  to    = [PUBLIC, "reader"]
+  using = "(tenant = 1)"
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	gt, ok := got.Table("users")
+	require.True(t, ok)
+	var rs RowSecurity
+	require.True(t, tablePoliciesHas(gt, &rs))
+	require.True(t, rs.Enabled)
+	require.Equal(t, []*Policy{
+		{Name: "p1", Table: gt, As: PolicyAsRestrictive, For: PolicyForSelect, To: []string{"PUBLIC", "reader"}, Using: "(tenant = 1)"},
+	}, tablePolicies(gt))
+}
@@ -10,6 +10,7 @@ import (
 	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/classified"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
@@ -51,9 +52,14 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc}, nil
+	cl, err := classified.New(r)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Analyzer{ds, dd, cd, bc, cl}, nil
 }
 
 func init() {
 	sqlcheck.Register(postgres.DriverName, analyzers)
+	sqlcheck.Register(postgres.CockroachDriverName, analyzers)
 }
@@ -0,0 +1,201 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Requirement describes a server setting that must be configured on the
+// database before a plan is applied. For example, "wal_level = logical" for
+// plans that manage publications.
+type Requirement struct {
+	Name  string // Setting name, e.g. max_locks_per_transaction.
+	Op    string // Either "=" or ">=". The latter is supported only for numeric settings.
+	Value string // Required value, in the unit of the setting (see pg_settings.unit).
+}
+
+// Supported requirement operators.
+const (
+	RequireEQ  = "="
+	RequireGTE = ">="
+)
+
+// directiveRequire is the plan directive that declares a required server setting.
+const directiveRequire = "-- atlas:require"
+
+var reRequirement = regexp.MustCompile(`^(\w+)\s*(>=|=)\s*(\S+)$`)
+
+// String implements the fmt.Stringer interface.
+func (r *Requirement) String() string {
+	return fmt.Sprintf("%s %s %s", r.Name, r.Op, r.Value)
+}
+
+// Require declares that the given server setting must be configured before
+// the plan is applied. The requirement is recorded as an "atlas:require"
+// directive of the plan, and therefore, it is also written to its migration file.
+//
+//	Require(plan, "wal_level", RequireEQ, "logical")
+//	Require(plan, "max_locks_per_transaction", RequireGTE, "256")
+func Require(p *migrate.Plan, name, op, value string) {
+	p.AddDirectiveOnce(fmt.Sprintf("%s %s", directiveRequire, &Requirement{Name: name, Op: op, Value: value}))
+}
+
+// PlanRequirements returns the server requirements declared by the plan directives.
+func PlanRequirements(p *migrate.Plan) ([]*Requirement, error) {
+	var reqs []*Requirement
+	for _, d := range p.Directives {
+		if !strings.HasPrefix(d, directiveRequire+" ") {
+			continue
+		}
+		r, err := ParseRequirement(strings.TrimPrefix(d, directiveRequire))
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, r)
+	}
+	return reqs, nil
+}
+
+// ParseRequirement parses a requirement in the format of "<name> <op> <value>".
+func ParseRequirement(s string) (*Requirement, error) {
+	m := reRequirement.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("postgres: invalid server requirement %q", s)
+	}
+	return &Requirement{Name: strings.ToLower(m[1]), Op: m[2], Value: m[3]}, nil
+}
+
+// CheckRequirements verifies the given requirements against the server
+// settings, and returns an error that describes how to configure each
+// setting that does not meet its requirement.
+func (d *Driver) CheckRequirements(ctx context.Context, reqs []*Requirement) error {
+	return d.conn.checkRequirements(ctx, reqs)
+}
+
+func (c *conn) checkRequirements(ctx context.Context, reqs []*Requirement) error {
+	// CockroachDB does not expose the PostgreSQL server settings.
+	if len(reqs) == 0 || !c.supports(featServerSettings) {
+		return nil
+	}
+	args := make([]any, 0, len(reqs))
+	for _, r := range reqs {
+		args = append(args, r.Name)
+	}
+	rows, err := c.QueryContext(ctx, fmt.Sprintf(settingsQuery, nArgs(0, len(reqs))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying server settings: %w", err)
+	}
+	defer rows.Close()
+	type setting struct{ value, vartype, context string }
+	settings := make(map[string]setting)
+	for rows.Next() {
+		var name string
+		var s setting
+		if err := rows.Scan(&name, &s.value, &s.vartype, &s.context); err != nil {
+			return fmt.Errorf("postgres: scanning server settings: %w", err)
+		}
+		settings[name] = s
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range reqs {
+		s, ok := settings[r.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown server setting %q", r.Name))
+			continue
+		}
+		met, err := r.met(s.value, s.vartype)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !met {
+			errs = append(errs, fmt.Errorf("server setting %s is %q, but the plan requires %s. %s", r.Name, s.value, r, settingHint(r, s.context)))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("postgres: unmet server requirements: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// met reports if the current value of the setting meets the requirement.
+func (r *Requirement) met(current, vartype string) (bool, error) {
+	switch {
+	case vartype == "integer" || vartype == "real":
+		v1, err1 := strconv.ParseFloat(current, 64)
+		v2, err2 := strconv.ParseFloat(r.Value, 64)
+		if err := errors.Join(err1, err2); err != nil {
+			return false, fmt.Errorf("invalid numeric value for server setting %q: %w", r.Name, err)
+		}
+		if r.Op == RequireGTE {
+			return v1 >= v2, nil
+		}
+		return v1 == v2, nil
+	case r.Op == RequireGTE:
+		return false, fmt.Errorf("operator %s is not supported for server setting %q of type %s", r.Op, r.Name, vartype)
+	case vartype == "bool":
+		v1, err1 := parseStorageBool(current)
+		v2, err2 := parseStorageBool(r.Value)
+		if err := errors.Join(err1, err2); err != nil {
+			return false, fmt.Errorf("invalid boolean value for server setting %q: %w", r.Name, err)
+		}
+		return v1 == v2, nil
+	default:
+		return strings.EqualFold(current, r.Value), nil
+	}
+}
+
+// settingHint returns an actionable hint for configuring the setting,
+// based on its context, as reported by the pg_settings view.
+func settingHint(r *Requirement, context string) string {
+	set := fmt.Sprintf("%s = %s", r.Name, quote(r.Value))
+	switch context {
+	case "internal":
+		return "The setting cannot be changed on a running server"
+	case "postmaster":
+		return fmt.Sprintf("Run ALTER SYSTEM SET %s and restart the server", set)
+	case "sighup":
+		return fmt.Sprintf("Run ALTER SYSTEM SET %s and reload the configuration using pg_reload_conf()", set)
+	default:
+		return fmt.Sprintf("Run ALTER DATABASE ... SET %s, or set it on the connection", set)
+	}
+}
+
+// requirePlanner wraps the planApply and verifies the requirements of the plan
+// before it is applied.
+type requirePlanner struct {
+	*planApply
+}
+
+// PlanChanges implements the migrate.PlanApplier interface.
+func (p *requirePlanner) PlanChanges(ctx context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	plan, err := p.planApply.PlanChanges(ctx, name, changes, opts...)
+	if err != nil {
+		return nil, err
+	}
+	reqs, err := PlanRequirements(plan)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkRequirements(ctx, reqs); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Query to list the server settings.
+const settingsQuery = `SELECT name, setting, vartype, context FROM pg_catalog.pg_settings WHERE name IN (%s)`
@@ -0,0 +1,114 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequire(t *testing.T) {
+	p := &migrate.Plan{Directives: []string{txModeNone}}
+	Require(p, "wal_level", RequireEQ, "logical")
+	Require(p, "max_locks_per_transaction", RequireGTE, "256")
+	Require(p, "wal_level", RequireEQ, "logical")
+	require.Equal(t, []string{
+		txModeNone,
+		"-- atlas:require wal_level = logical",
+		"-- atlas:require max_locks_per_transaction >= 256",
+	}, p.Directives)
+	reqs, err := PlanRequirements(p)
+	require.NoError(t, err)
+	require.Equal(t, []*Requirement{
+		{Name: "wal_level", Op: RequireEQ, Value: "logical"},
+		{Name: "max_locks_per_transaction", Op: RequireGTE, Value: "256"},
+	}, reqs)
+
+	r, err := ParseRequirement("Max_Connections>=100")
+	require.NoError(t, err)
+	require.Equal(t, &Requirement{Name: "max_connections", Op: RequireGTE, Value: "100"}, r)
+	_, err = ParseRequirement("wal_level != logical")
+	require.EqualError(t, err, `postgres: invalid server requirement "wal_level != logical"`)
+	_, err = PlanRequirements(&migrate.Plan{Directives: []string{"-- atlas:require wal_level"}})
+	require.Error(t, err)
+}
+
+func TestDriver_CheckRequirements(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	reqs := []*Requirement{
+		{Name: "wal_level", Op: RequireEQ, Value: "logical"},
+		{Name: "max_locks_per_transaction", Op: RequireGTE, Value: "256"},
+		{Name: "statement_timeout", Op: RequireEQ, Value: "0"},
+		{Name: "hot_standby", Op: RequireEQ, Value: "on"},
+	}
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(settingsQuery, "$1, $2, $3, $4"))).
+		WithArgs("wal_level", "max_locks_per_transaction", "statement_timeout", "hot_standby").
+		WillReturnRows(sqltest.Rows(`
+ name                      | setting | vartype | context
+---------------------------+---------+---------+------------
+ wal_level                 | replica | enum    | postmaster
+ max_locks_per_transaction | 64      | integer | postmaster
+ statement_timeout         | 0       | integer | user
+ hot_standby               | on      | bool    | postmaster
+`))
+	err = drv.(*Driver).CheckRequirements(context.Background(), reqs)
+	require.EqualError(t, err, `postgres: unmet server requirements: server setting wal_level is "replica", but the plan requires wal_level = logical. Run ALTER SYSTEM SET wal_level = 'logical' and restart the server
+server setting max_locks_per_transaction is "64", but the plan requires max_locks_per_transaction >= 256. Run ALTER SYSTEM SET max_locks_per_transaction = '256' and restart the server`)
+
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(settingsQuery, "$1, $2"))).
+		WithArgs("wal_level", "unknown_setting").
+		WillReturnRows(sqltest.Rows(`
+ name      | setting | vartype | context
+-----------+---------+---------+------------
+ wal_level | logical | enum    | postmaster
+`))
+	err = drv.(*Driver).CheckRequirements(context.Background(), []*Requirement{
+		{Name: "wal_level", Op: RequireEQ, Value: "LOGICAL"},
+		{Name: "unknown_setting", Op: RequireEQ, Value: "on"},
+	})
+	require.EqualError(t, err, `postgres: unmet server requirements: unknown server setting "unknown_setting"`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestPlanChanges_Requirements(t *testing.T) {
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: &Publication{Name: "pub", AllTables: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"-- atlas:require wal_level = logical"}, plan.Directives)
+
+	// Requirements are verified before the changes are applied.
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(settingsQuery, "$1"))).
+		WithArgs("wal_level").
+		WillReturnRows(sqltest.Rows(`
+ name      | setting | vartype | context
+-----------+---------+---------+------------
+ wal_level | replica | enum    | postmaster
+`))
+	err = drv.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddObject{O: &Publication{Name: "pub", AllTables: true}},
+	})
+	require.ErrorContains(t, err, `server setting wal_level is "replica", but the plan requires wal_level = logical`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
@@ -0,0 +1,403 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Publication defines a logical replication publication. Publications
+// are realm-level objects, and their tables may reside in any schema.
+// https://www.postgresql.org/docs/current/sql-createpublication.html
+type Publication struct {
+	schema.Object
+	Name      string
+	AllTables bool            // FOR ALL TABLES.
+	Tables    []*schema.Table // FOR TABLE list. Ignored if AllTables is set.
+	Publish   []string        // Published operations. An empty list means all operations.
+	Attrs     []schema.Attr
+}
+
+// List of operations that can be published.
+const (
+	PublishInsert   = "INSERT"
+	PublishUpdate   = "UPDATE"
+	PublishDelete   = "DELETE"
+	PublishTruncate = "TRUNCATE"
+)
+
+// publishOps lists the publication operations in their canonical order.
+var publishOps = []string{PublishInsert, PublishUpdate, PublishDelete, PublishTruncate}
+
+// SpecType returns the type of the publication.
+func (*Publication) SpecType() string { return "publication" }
+
+// SpecName returns the name of the publication.
+func (p *Publication) SpecName() string { return p.Name }
+
+// HasTable reports if the given table is explicitly listed in the publication.
+func (p *Publication) HasTable(t *schema.Table) bool {
+	return slices.ContainsFunc(p.Tables, func(t1 *schema.Table) bool {
+		return sqlx.SameTable(t, t1)
+	})
+}
+
+// DependsOn implements the sqlx.Depender interface. Tables must
+// exist before they are added to a publication.
+func (p *Publication) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); ok {
+		return false
+	}
+	if a, ok := other.(*schema.AddTable); ok {
+		return p.HasTable(a.T)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. Tables are removed
+// from a publication (or the publication is dropped) before they are dropped.
+func (p *Publication) DependencyOf(change, other schema.Change) bool {
+	d, ok := other.(*schema.DropTable)
+	if !ok {
+		return false
+	}
+	switch c := change.(type) {
+	case *schema.DropObject:
+		return p.HasTable(d.T)
+	case *schema.ModifyObject:
+		from, ok := c.From.(*Publication)
+		return ok && from.HasTable(d.T)
+	}
+	return false
+}
+
+// inspectPublications queries and appends the publications of the realm.
+func (i *inspect) inspectPublications(ctx context.Context, r *schema.Realm) error {
+	query := publicationsQuery
+	if !i.supportsPublishTruncate() {
+		query = publicationsQueryBelow11
+	}
+	rows, err := i.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("postgres: querying publications: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name                   string
+			tables                 sql.NullString
+			all, ins, upd, del, tr bool
+		)
+		if err := rows.Scan(&name, &all, &ins, &upd, &del, &tr, &tables); err != nil {
+			return fmt.Errorf("postgres: scanning publication: %w", err)
+		}
+		p := &Publication{Name: name, AllTables: all}
+		// Keep the publish list empty in case all operations are published.
+		if ops := []bool{ins, upd, del, tr}; slices.Contains(ops, false) {
+			for j, ok := range ops {
+				if ok {
+					p.Publish = append(p.Publish, publishOps[j])
+				}
+			}
+		}
+		if sqlx.ValidString(tables) {
+			var names [][2]string
+			if err := json.Unmarshal([]byte(tables.String), &names); err != nil {
+				return fmt.Errorf("postgres: parsing tables of publication %q: %w", name, err)
+			}
+			for _, n := range names {
+				p.Tables = append(p.Tables, publicationTable(r, n[0], n[1]))
+			}
+		}
+		r.AddObjects(p)
+	}
+	return rows.Err()
+}
+
+// publicationTable returns the table from the realm, or a reference to
+// it, in case the table or its schema were not included in the inspection.
+func publicationTable(r *schema.Realm, ns, name string) *schema.Table {
+	s, ok := r.Schema(ns)
+	if !ok {
+		s = schema.New(ns)
+	}
+	if t, ok := s.Table(name); ok {
+		return t
+	}
+	return schema.NewTable(name).SetSchema(s)
+}
+
+// publicationDiff returns the changes for migrating the publications of the realm.
+func publicationDiff(from, to *schema.Realm) []schema.Change {
+	return sqlx.ObjectsDiff(from.Objects, to.Objects, func(p1, p2 *Publication) bool {
+		return p1.Name == p2.Name
+	}, publicationChanged)
+}
+
+// publicationChanged reports if the publication was changed.
+func publicationChanged(from, to *Publication) bool {
+	if from.AllTables != to.AllTables || !slices.Equal(publishList(from), publishList(to)) {
+		return true
+	}
+	if from.AllTables {
+		return false
+	}
+	adds, drops := publicationTables(from, to)
+	return len(adds) > 0 || len(drops) > 0
+}
+
+// publicationTables returns the tables that should be added to and dropped from the publication.
+func publicationTables(from, to *Publication) (adds, drops []*schema.Table) {
+	for _, t := range to.Tables {
+		if !from.HasTable(t) {
+			adds = append(adds, t)
+		}
+	}
+	for _, t := range from.Tables {
+		if !to.HasTable(t) {
+			drops = append(drops, t)
+		}
+	}
+	return adds, drops
+}
+
+// publishList returns the published operations in their canonical form.
+func publishList(p *Publication) []string {
+	if len(p.Publish) == 0 {
+		return publishOps
+	}
+	ops := make([]string, 0, len(p.Publish))
+	for _, op := range publishOps {
+		if slices.ContainsFunc(p.Publish, func(s string) bool { return strings.EqualFold(s, op) }) {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// addPublication plans the creation of a publication.
+func (s *state) addPublication(src schema.Change, p *Publication) {
+	s.requireLogical()
+	create, drop := s.createDropPublication(p)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create publication %q", p.Name),
+	})
+}
+
+// requireLogical declares that publications require logical decoding
+// to be enabled on the server in order to publish changes.
+func (s *state) requireLogical() {
+	Require(&s.Plan, "wal_level", RequireEQ, "logical")
+}
+
+// dropPublication plans the removal of a publication.
+func (s *state) dropPublication(src schema.Change, p *Publication) {
+	create, drop := s.createDropPublication(p)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop publication %q", p.Name),
+	})
+}
+
+// modifyPublication plans the changes of a publication. Switching between
+// FOR ALL TABLES and an explicit list of tables requires recreating it.
+func (s *state) modifyPublication(src schema.Change, from, to *Publication) {
+	s.requireLogical()
+	if from.AllTables != to.AllTables {
+		s.dropPublication(src, from)
+		s.addPublication(src, to)
+		return
+	}
+	adds, drops := publicationTables(from, to)
+	if to.AllTables {
+		adds, drops = nil, nil
+	}
+	alter := func(b *sqlx.Builder) *sqlx.Builder {
+		return b.P("ALTER PUBLICATION").Ident(to.Name)
+	}
+	if len(adds) > 0 {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     s.publicationTables(alter(s.Build()).P("ADD TABLE"), adds).String(),
+			Reverse: s.publicationTables(alter(s.Build()).P("DROP TABLE"), adds).String(),
+			Comment: fmt.Sprintf("add tables to publication %q", to.Name),
+		})
+	}
+	if len(drops) > 0 {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     s.publicationTables(alter(s.Build()).P("DROP TABLE"), drops).String(),
+			Reverse: s.publicationTables(alter(s.Build()).P("ADD TABLE"), drops).String(),
+			Comment: fmt.Sprintf("drop tables from publication %q", to.Name),
+		})
+	}
+	if !slices.Equal(publishList(from), publishList(to)) {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     alter(s.Build()).P("SET").Wrap(publishOption(to)).String(),
+			Reverse: alter(s.Build()).P("SET").Wrap(publishOption(from)).String(),
+			Comment: fmt.Sprintf("set published operations of publication %q", to.Name),
+		})
+	}
+}
+
+func (s *state) createDropPublication(p *Publication) (string, string) {
+	b := s.Build("CREATE PUBLICATION").Ident(p.Name)
+	switch {
+	case p.AllTables:
+		b.P("FOR ALL TABLES")
+	case len(p.Tables) > 0:
+		s.publicationTables(b.P("FOR TABLE"), p.Tables)
+	}
+	if !slices.Equal(publishList(p), publishOps) {
+		b.P("WITH").Wrap(publishOption(p))
+	}
+	return b.String(), s.Build("DROP PUBLICATION").Ident(p.Name).String()
+}
+
+func (s *state) publicationTables(b *sqlx.Builder, tables []*schema.Table) *sqlx.Builder {
+	return b.MapComma(tables, func(i int, b *sqlx.Builder) {
+		b.Table(tables[i])
+	})
+}
+
+// publishOption returns a function that writes the publish option of the publication.
+func publishOption(p *Publication) func(*sqlx.Builder) {
+	return func(b *sqlx.Builder) {
+		b.P("publish =", quote(strings.ToLower(strings.Join(publishList(p), ", "))))
+	}
+}
+
+// publication holds a specification for a publication.
+// Note, publication names are unique within a realm (database).
+type publication struct {
+	Name   string           `spec:",name"`
+	Tables []*schemahcl.Ref `spec:"tables"`
+	// The all_tables and publish attributes are
+	// added to the publication definition if set.
+	schemahcl.DefaultExtension
+}
+
+// convertPublications converts the publication specs and adds them to the realm.
+func convertPublications(specs []*publication, r *schema.Realm) error {
+	for _, spec := range specs {
+		p := &Publication{Name: spec.Name}
+		if a, ok := spec.Attr("all_tables"); ok {
+			b, err := a.Bool()
+			if err != nil {
+				return fmt.Errorf("parsing publication %q attribute \"all_tables\": %w", spec.Name, err)
+			}
+			p.AllTables = b
+		}
+		if a, ok := spec.Attr("publish"); ok {
+			ops, err := a.Strings()
+			if err != nil {
+				return fmt.Errorf("parsing publication %q attribute \"publish\": %w", spec.Name, err)
+			}
+			p.Publish = ops
+		}
+		if p.AllTables && len(spec.Tables) > 0 {
+			return fmt.Errorf("publication %q cannot define both \"tables\" and \"all_tables\"", spec.Name)
+		}
+		for _, ref := range spec.Tables {
+			t, err := policyTable(r, ref)
+			if err != nil {
+				return fmt.Errorf("find table for publication %q: %w", spec.Name, err)
+			}
+			p.Tables = append(p.Tables, t)
+		}
+		r.AddObjects(p)
+	}
+	return nil
+}
+
+// publicationSpecs returns the publication specs of the realm.
+func publicationSpecs(r *schema.Realm) []*publication {
+	var specs []*publication
+	for _, o := range r.Objects {
+		p, ok := o.(*Publication)
+		if !ok {
+			continue
+		}
+		spec := &publication{Name: p.Name}
+		if p.AllTables {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("all_tables", true))
+		}
+		for _, t := range p.Tables {
+			spec.Tables = append(spec.Tables, specutil.TableSpecRef(t))
+		}
+		if ops := publishList(p); !slices.Equal(ops, publishOps) {
+			vs := make([]*schemahcl.EnumString, len(ops))
+			for i := range ops {
+				vs[i] = &schemahcl.EnumString{E: ops[i]}
+			}
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringEnumsAttr("publish", vs...))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+const (
+	// Query to list the publications of the database and their explicitly listed tables.
+	publicationsQuery = `
+SELECT
+  p.pubname,
+  p.puballtables,
+  p.pubinsert,
+  p.pubupdate,
+  p.pubdelete,
+  p.pubtruncate,
+  (
+    SELECT json_agg(json_build_array(n.nspname, c.relname) ORDER BY n.nspname, c.relname)
+    FROM pg_catalog.pg_publication_rel AS pr
+    JOIN pg_catalog.pg_class AS c ON c.oid = pr.prrelid
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    WHERE pr.prpubid = p.oid
+  ) AS tables
+FROM
+  pg_catalog.pg_publication AS p
+ORDER BY
+  p.pubname
+`
+	// Publishing TRUNCATE operations was added in PostgreSQL 11. Older versions
+	// report it as published to keep "all operations" as the default state.
+	publicationsQueryBelow11 = `
+SELECT
+  p.pubname,
+  p.puballtables,
+  p.pubinsert,
+  p.pubupdate,
+  p.pubdelete,
+  true AS pubtruncate,
+  (
+    SELECT json_agg(json_build_array(n.nspname, c.relname) ORDER BY n.nspname, c.relname)
+    FROM pg_catalog.pg_publication_rel AS pr
+    JOIN pg_catalog.pg_class AS c ON c.oid = pr.prrelid
+    JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+    WHERE pr.prpubid = p.oid
+  ) AS tables
+FROM
+  pg_catalog.pg_publication AS p
+ORDER BY
+  p.pubname
+`
+)
@@ -0,0 +1,205 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectPublications(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "sequence_name", "sequence_type", "seqstart", "seqincrement", "seqmin", "seqmax", "seqcache", "seqcycle", "owner_table", "owner_column", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "table_name", "server_name", "options", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(publicationsQuery)).
+		WillReturnRows(sqltest.Rows(`
+ pubname | puballtables | pubinsert | pubupdate | pubdelete | pubtruncate |                 tables
+---------+--------------+-----------+-----------+-----------+-------------+-----------------------------------------
+ all     | t            | t         | t         | t         | t           | nil
+ orders  | f            | t         | f         | f         | f           | [["public","orders"],["sales","items"]]
+`))
+	mk.ExpectQuery(sqltest.Escape(eventTriggersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"evtname", "evtevent", "nspname", "proname", "evtenabled", "tags", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(foreignServersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"srvname", "fdwname", "srvtype", "srvversion", "options", "mappings", "comment"}))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectObjects,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	require.Len(t, r.Objects, 2)
+	require.Equal(t, &Publication{Name: "all", AllTables: true}, r.Objects[0])
+	p := r.Objects[1].(*Publication)
+	require.Equal(t, "orders", p.Name)
+	require.Equal(t, []string{PublishInsert}, p.Publish)
+	require.Len(t, p.Tables, 2)
+	require.Equal(t, "orders", p.Tables[0].Name)
+	require.Equal(t, r.Schemas[0], p.Tables[0].Schema)
+	require.Equal(t, "items", p.Tables[1].Name)
+	require.Equal(t, "sales", p.Tables[1].Schema.Name)
+}
+
+func TestDiff_Publications(t *testing.T) {
+	var (
+		from = schema.NewRealm(schema.New("public").AddTables(schema.NewTable("t1"), schema.NewTable("t2")))
+		to   = schema.NewRealm(schema.New("public").AddTables(schema.NewTable("t1"), schema.NewTable("t2")))
+		t1   = from.Schemas[0].Tables[0]
+		t2   = to.Schemas[0].Tables[1]
+		p1   = &Publication{Name: "p", Tables: []*schema.Table{t1}, Publish: []string{"insert", "update", "delete", "truncate"}}
+		p2   = &Publication{Name: "p", Tables: []*schema.Table{to.Schemas[0].Tables[0]}}
+	)
+	from.AddObjects(p1)
+	to.AddObjects(p2)
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes, "tables and operations are compared normalized")
+
+	p2.Tables = append(p2.Tables, t2)
+	changes, err = DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyObject{From: p1, To: p2}}, changes)
+
+	to.Objects = nil
+	changes = publicationDiff(from, to)
+	require.Equal(t, []schema.Change{&schema.DropObject{O: p1}}, changes)
+	changes = publicationDiff(to, from)
+	require.Equal(t, []schema.Change{&schema.AddObject{O: p1}}, changes)
+}
+
+func TestPlanChanges_Publications(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		t1 = schema.NewTable("t1").SetSchema(s).AddColumns(schema.NewIntColumn("id", TypeInteger))
+		t2 = schema.NewTable("t2").SetSchema(s).AddColumns(schema.NewIntColumn("id", TypeInteger))
+		p1 = &Publication{Name: "p", Tables: []*schema.Table{t1}}
+		p2 = &Publication{Name: "p", Tables: []*schema.Table{t2}, Publish: []string{PublishInsert, PublishDelete}}
+	)
+	tests := []struct {
+		changes []schema.Change
+		want    [][2]string
+	}{
+		{
+			changes: []schema.Change{&schema.AddObject{O: p1}, &schema.AddTable{T: t1}},
+			want: [][2]string{
+				{`CREATE TABLE "public"."t1" ("id" integer NOT NULL)`, `DROP TABLE "public"."t1"`},
+				{`CREATE PUBLICATION "p" FOR TABLE "public"."t1"`, `DROP PUBLICATION "p"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropTable{T: t1}, &schema.DropObject{O: p1}},
+			want: [][2]string{
+				{`DROP PUBLICATION "p"`, `CREATE PUBLICATION "p" FOR TABLE "public"."t1"`},
+				{`DROP TABLE "public"."t1"`, `CREATE TABLE "public"."t1" ("id" integer NOT NULL)`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropTable{T: t1}, &schema.ModifyObject{From: p1, To: p2}},
+			want: [][2]string{
+				{`ALTER PUBLICATION "p" ADD TABLE "public"."t2"`, `ALTER PUBLICATION "p" DROP TABLE "public"."t2"`},
+				{`ALTER PUBLICATION "p" DROP TABLE "public"."t1"`, `ALTER PUBLICATION "p" ADD TABLE "public"."t1"`},
+				{`ALTER PUBLICATION "p" SET (publish = 'insert, delete')`, `ALTER PUBLICATION "p" SET (publish = 'insert, update, delete, truncate')`},
+				{`DROP TABLE "public"."t1"`, `CREATE TABLE "public"."t1" ("id" integer NOT NULL)`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyObject{From: p2, To: &Publication{Name: "p", AllTables: true}}},
+			want: [][2]string{
+				{`DROP PUBLICATION "p"`, `CREATE PUBLICATION "p" FOR TABLE "public"."t2" WITH (publish = 'insert, delete')`},
+				{`CREATE PUBLICATION "p" FOR ALL TABLES`, `DROP PUBLICATION "p"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes)
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, len(tt.want))
+		for i, c := range plan.Changes {
+			require.Equal(t, tt.want[i][0], c.Cmd)
+			require.Equal(t, tt.want[i][1], c.Reverse)
+		}
+	}
+}
+
+func TestMarshalSpec_Publications(t *testing.T) {
+	var (
+		s = schema.New("public").AddTables(
+			schema.NewTable("t1").AddColumns(schema.NewIntColumn("id", TypeInteger)),
+		)
+		r = schema.NewRealm(s)
+	)
+	r.AddObjects(
+		&Publication{Name: "all", AllTables: true},
+		&Publication{Name: "p", Tables: s.Tables, Publish: []string{PublishInsert, PublishUpdate}},
+	)
+	buf, err := MarshalHCL(r)
+	require.NoError(t, err)
+	require.Equal(t, `table "t1" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = integer
+  }
+}
+publication "all" {
+  all_tables = true
+}
+publication "p" {
+  tables  = [table.t1]
+  publish = [INSERT, UPDATE]
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Realm
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Objects, 2)
+	require.Equal(t, &Publication{Name: "all", AllTables: true}, got.Objects[0])
+	require.Equal(t, &Publication{Name: "p", Tables: got.Schemas[0].Tables, Publish: []string{PublishInsert, PublishUpdate}}, got.Objects[1])
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "t1" {
+  schema = schema.public
+  column "id" { type = int }
+}
+publication "p" {
+  tables     = [table.t1]
+  all_tables = true
+}
+`), &got, nil)
+	require.EqualError(t, err, `publication "p" cannot define both "tables" and "all_tables"`)
+}
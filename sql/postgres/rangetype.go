@@ -0,0 +1,579 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// CustomRangeType defines a user-defined range type. In PostgreSQL 14 and above,
+	// the creation of a range type also creates its multirange type.
+	// https://www.postgresql.org/docs/current/rangetypes.html#RANGETYPES-DEFINING
+	CustomRangeType struct {
+		schema.Type
+		schema.Object
+		T           string         // Type name.
+		Schema      *schema.Schema // Optional schema.
+		Subtype     schema.Type    // Element type of the range.
+		OpClass     string         // Optional B-tree operator class of the subtype, if not the default.
+		Collation   string         // Optional collation of the subtype, if not the default.
+		Canonical   string         // Optional canonical function.
+		SubtypeDiff string         // Optional subtype difference function.
+		Multirange  string         // Optional name of the multirange type, if not the default.
+		Attrs       []schema.Attr  // Extra attributes, such as comment.
+	}
+
+	// CustomMultirangeType defines the multirange type of a user-defined range type.
+	CustomMultirangeType struct {
+		schema.Type
+		T     string           // Type name.
+		Range *CustomRangeType // The range type that defines the multirange.
+	}
+)
+
+// SpecType returns the type of the range type used in HCL and exclude patterns.
+func (*CustomRangeType) SpecType() string { return "range" }
+
+// SpecName returns the name of the range type used in HCL and exclude patterns.
+func (r *CustomRangeType) SpecName() string { return r.T }
+
+// SetComment sets or updates the comment of the range type.
+func (r *CustomRangeType) SetComment(c string) *CustomRangeType {
+	schema.ReplaceOrAppend(&r.Attrs, &schema.Comment{Text: c})
+	return r
+}
+
+// MultirangeName returns the name of the multirange type of the range type. By default,
+// PostgreSQL derives it from the range name by replacing its "range" substring with
+// "multirange", or by appending the "_multirange" suffix to it.
+func (r *CustomRangeType) MultirangeName() string {
+	switch {
+	case r.Multirange != "":
+		return r.Multirange
+	case strings.Contains(r.T, "range"):
+		return strings.Replace(r.T, "range", "multirange", 1)
+	default:
+		return r.T + "_multirange"
+	}
+}
+
+// MultirangeType returns the multirange type of the range type.
+func (r *CustomRangeType) MultirangeType() *CustomMultirangeType {
+	return &CustomMultirangeType{T: r.MultirangeName(), Range: r}
+}
+
+// DependsOn implements the sqlx.Depender interface. A range
+// type can be created only after its subtype was created.
+func (r *CustomRangeType) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.AddObject); !ok || r.Subtype == nil {
+		return false
+	}
+	a, ok := other.(*schema.AddObject)
+	if !ok {
+		return false
+	}
+	t, ok := a.O.(schema.Type)
+	return ok && schema.IsType(r.Subtype, t)
+}
+
+// DependencyOf implements the sqlx.Depender interface. A range
+// type is dropped before its subtype is dropped.
+func (r *CustomRangeType) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok || r.Subtype == nil {
+		return false
+	}
+	d, ok := other.(*schema.DropObject)
+	if !ok {
+		return false
+	}
+	t, ok := d.O.(schema.Type)
+	return ok && schema.IsType(r.Subtype, t)
+}
+
+// Underlying returns the range type of the multirange, used
+// for ordering changes of columns that depend on the range.
+func (m *CustomMultirangeType) Underlying() schema.Type {
+	return m.Range
+}
+
+func rangeComment(r *CustomRangeType) string {
+	var c schema.Comment
+	sqlx.Has(r.Attrs, &c)
+	return c.Text
+}
+
+// inspectRanges queries and appends the user-defined range types of the realm.
+// Range types that are members of extensions are managed by their extension,
+// and are therefore skipped.
+func (i *inspect) inspectRanges(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	query := rangesQuery
+	if i.version < 14_00_00 {
+		query = rangesQueryBelow14
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(query, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying range types: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name, subtype                                   string
+			opclass, collation, canonical, diff, multi, comment sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &subtype, &opclass, &collation, &canonical, &diff, &multi, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning range type: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for range type %q was not found in realm", ns, name)
+		}
+		st, err := ParseType(subtype)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing subtype %q of range type %q: %w", subtype, name, err)
+		}
+		if u, ok := st.(*UserDefinedType); ok {
+			st = i.underlyingType(s, u)
+		}
+		rt := &CustomRangeType{
+			T:         name,
+			Schema:    s,
+			Subtype:   st,
+			OpClass:   opclass.String,
+			Collation: collation.String,
+			// Functions that reside in the schema of the range are not qualified,
+			// as the search_path is cleared during inspection.
+			Canonical:   strings.TrimPrefix(canonical.String, s.Name+"."),
+			SubtypeDiff: strings.TrimPrefix(diff.String, s.Name+"."),
+		}
+		if sqlx.ValidString(multi) && multi.String != rt.MultirangeName() {
+			rt.Multirange = multi.String
+		}
+		if sqlx.ValidString(comment) {
+			rt.SetComment(comment.String)
+		}
+		s.AddObjects(rt)
+	}
+	return rows.Err()
+}
+
+// rangeObjectDiff returns the changes for migrating the range types of the schema.
+func rangeObjectDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		r1, ok := o1.(*CustomRangeType)
+		if !ok {
+			continue
+		}
+		r2, ok := schemaRange(to, r1.T)
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: r1})
+			continue
+		}
+		changed, err := rangeChanged(r1, r2)
+		if err != nil {
+			return nil, err
+		}
+		if changed || rangeComment(r1) != rangeComment(r2) {
+			changes = append(changes, &schema.ModifyObject{From: r1, To: r2})
+		}
+	}
+	for _, o1 := range to.Objects {
+		r1, ok := o1.(*CustomRangeType)
+		if !ok {
+			continue
+		}
+		if _, ok := schemaRange(from, r1.T); !ok {
+			changes = append(changes, &schema.AddObject{O: r1})
+		}
+	}
+	return changes, nil
+}
+
+// rangeChanged reports if the definition of the range type was changed.
+func rangeChanged(from, to *CustomRangeType) (bool, error) {
+	if from.Subtype == nil || to.Subtype == nil {
+		return false, fmt.Errorf("postgres: missing subtype for range type %q", from.T)
+	}
+	changed, err := typeChanged(&schema.Column{Name: from.T, Type: &schema.ColumnType{Type: from.Subtype}}, &schema.Column{Type: &schema.ColumnType{Type: to.Subtype}}, "")
+	if err != nil {
+		return false, err
+	}
+	return changed || from.OpClass != to.OpClass || from.Collation != to.Collation || from.Canonical != to.Canonical ||
+		from.SubtypeDiff != to.SubtypeDiff || from.MultirangeName() != to.MultirangeName(), nil
+}
+
+func schemaRange(s *schema.Schema, name string) (*CustomRangeType, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		r, ok := o.(*CustomRangeType)
+		return ok && r.T == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*CustomRangeType), true
+}
+
+func (s *state) addRange(src schema.Change, r *CustomRangeType) error {
+	create, drop, err := s.createDropRange(r)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create range type %q", r.T),
+	})
+	if c := rangeComment(r); c != "" {
+		s.append(s.rangeComment(src, r, c, ""))
+	}
+	return nil
+}
+
+func (s *state) dropRange(src schema.Change, r *CustomRangeType) error {
+	create, drop, err := s.createDropRange(r)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop range type %q", r.T),
+	})
+	return nil
+}
+
+// modifyRange plans the changes between two states of a range type. PostgreSQL does not
+// support altering the definition of range types, and recreating them requires dropping
+// the columns that use them. Hence, only comment changes are supported.
+func (s *state) modifyRange(src schema.Change, from, to *CustomRangeType) error {
+	changed, err := rangeChanged(from, to)
+	if err != nil {
+		return err
+	}
+	if changed {
+		return fmt.Errorf("changing the definition of range type %q is not supported", to.T)
+	}
+	if c1, c2 := rangeComment(from), rangeComment(to); c1 != c2 {
+		s.append(s.rangeComment(src, to, c2, c1))
+	}
+	return nil
+}
+
+func (s *state) createDropRange(r *CustomRangeType) (string, string, error) {
+	if r.Subtype == nil {
+		return "", "", fmt.Errorf("missing subtype for range type %q", r.T)
+	}
+	st, err := s.formatType(r.Subtype)
+	if err != nil {
+		return "", "", fmt.Errorf("format subtype of range type %q: %w", r.T, err)
+	}
+	opts := []string{"SUBTYPE = " + st}
+	if r.OpClass != "" {
+		opts = append(opts, "SUBTYPE_OPCLASS = "+r.OpClass)
+	}
+	if r.Collation != "" {
+		opts = append(opts, "COLLATION = "+s.Build().Ident(r.Collation).String())
+	}
+	if r.Canonical != "" {
+		opts = append(opts, "CANONICAL = "+r.Canonical)
+	}
+	if r.SubtypeDiff != "" {
+		opts = append(opts, "SUBTYPE_DIFF = "+r.SubtypeDiff)
+	}
+	if r.Multirange != "" {
+		opts = append(opts, "MULTIRANGE_TYPE_NAME = "+s.typeIdent(r.Schema, r.Multirange))
+	}
+	name := s.rangeIdent(r)
+	return s.Build("CREATE TYPE").
+			P(name, "AS RANGE").
+			Wrap(func(b *sqlx.Builder) {
+				b.MapComma(opts, func(i int, b *sqlx.Builder) {
+					b.WriteString(opts[i])
+				})
+			}).
+			String(),
+		s.Build("DROP TYPE").P(name).String(), nil
+}
+
+func (s *state) rangeComment(src schema.Change, r *CustomRangeType, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON TYPE").P(s.rangeIdent(r)).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to range type: %q", r.T),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) rangeIdent(r *CustomRangeType) string {
+	return s.typeIdent(r.Schema, r.T)
+}
+
+func (s *state) multirangeIdent(m *CustomMultirangeType) string {
+	if m.Range == nil {
+		return s.typeIdent(nil, m.T)
+	}
+	return s.typeIdent(m.Range.Schema, m.T)
+}
+
+// rangeType holds a specification for a user-defined range type.
+type rangeType struct {
+	Name      string          `spec:",name"`
+	Qualifier string          `spec:",qualifier"`
+	Schema    *schemahcl.Ref  `spec:"schema"`
+	Subtype   *schemahcl.Type `spec:"subtype"`
+	// The subtype_opclass, collation, canonical, subtype_diff,
+	// multirange and comment attributes are conditionally
+	// added to the range definition.
+	schemahcl.DefaultExtension
+}
+
+// Label returns the defaults label used for the range resource.
+func (r *rangeType) Label() string { return r.Name }
+
+// QualifierLabel returns the qualifier label used for the range resource, if any.
+func (r *rangeType) QualifierLabel() string { return r.Qualifier }
+
+// SetQualifier sets the qualifier label used for the range resource.
+func (r *rangeType) SetQualifier(q string) { r.Qualifier = q }
+
+// SchemaRef returns the schema reference for the range.
+func (r *rangeType) SchemaRef() *schemahcl.Ref { return r.Schema }
+
+// convertRanges converts the range type specs, adds them to the realm, and
+// sets them on the table columns that reference them or their multiranges.
+func convertRanges(d *doc, r *schema.Realm) error {
+	if len(d.Ranges) == 0 {
+		return nil
+	}
+	for _, spec := range d.Ranges {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from range %q reference: %w", spec.Name, err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on range %q was not found in realm", ns, spec.Name)
+		}
+		if _, ok := schemaRange(s, spec.Name); ok {
+			return fmt.Errorf("duplicate range %q in schema %q", spec.Name, s.Name)
+		}
+		if spec.Subtype == nil {
+			return fmt.Errorf("missing attribute range.%s.subtype", spec.Name)
+		}
+		rt := &CustomRangeType{T: spec.Name, Schema: s}
+		if spec.Subtype.IsRefTo("enum") {
+			n, err := enumName(spec.Subtype)
+			if err != nil {
+				return err
+			}
+			e, ok := realmEnum(r, s, n)
+			if !ok {
+				return fmt.Errorf("enum %q used by range %q was not found in realm", n, spec.Name)
+			}
+			rt.Subtype = e
+		} else if rt.Subtype, err = TypeRegistry.Type(spec.Subtype, nil); err != nil {
+			return fmt.Errorf("converting range %q subtype: %w", spec.Name, err)
+		}
+		for _, a := range []struct {
+			name string
+			v    *string
+		}{
+			{name: "subtype_opclass", v: &rt.OpClass},
+			{name: "collation", v: &rt.Collation},
+			{name: "canonical", v: &rt.Canonical},
+			{name: "subtype_diff", v: &rt.SubtypeDiff},
+			{name: "multirange", v: &rt.Multirange},
+		} {
+			attr, ok := spec.Attr(a.name)
+			if !ok {
+				continue
+			}
+			if *a.v, err = attr.String(); err != nil {
+				return fmt.Errorf("parsing range %q attribute %q: %w", spec.Name, a.name, err)
+			}
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing range %q attribute \"comment\": %w", spec.Name, err)
+			}
+			rt.SetComment(c)
+		}
+		s.AddObjects(rt)
+	}
+	for _, ts := range d.Tables {
+		ns, err := specutil.SchemaName(ts.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from table reference: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q not found in realm for table %q", ns, ts.Name)
+		}
+		t, ok := s.Table(ts.Name)
+		if !ok {
+			return fmt.Errorf("table %q not found in schema %q", ts.Name, s.Name)
+		}
+		for _, cs := range ts.Columns {
+			c, ok := t.Column(cs.Name)
+			if !ok {
+				return fmt.Errorf("column %q not found in table %q", cs.Name, t.Name)
+			}
+			if cs.Type.IsRefTo("range") {
+				path, err := (&schemahcl.Ref{V: cs.Type.T}).ByType("range")
+				if err != nil || len(path) == 0 {
+					return fmt.Errorf("unexpected range reference %q for column %q", cs.Type.T, cs.Name)
+				}
+				rs := s
+				if len(path) > 1 {
+					if rs, ok = r.Schema(path[0]); !ok {
+						return fmt.Errorf("schema %q of range %q was not found in realm", path[0], path[len(path)-1])
+					}
+				}
+				rt, ok := schemaRange(rs, path[len(path)-1])
+				if !ok {
+					return fmt.Errorf("range %q was not found in schema %q", path[len(path)-1], rs.Name)
+				}
+				c.Type.Type = rt
+				continue
+			}
+			// Multirange types are referenced by their names.
+			if u, ok := c.Type.Type.(*UserDefinedType); ok {
+				if m, ok := realmMultirange(r, s, u.T); ok {
+					c.Type.Type = m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// realmEnum returns the enum with the given name. The schema of the
+// referencing object is searched first, and then the rest of the realm.
+func realmEnum(r *schema.Realm, s *schema.Schema, name string) (*schema.EnumType, bool) {
+	for _, s := range append([]*schema.Schema{s}, r.Schemas...) {
+		if o, ok := s.Object(func(o schema.Object) bool {
+			e, ok := o.(*schema.EnumType)
+			return ok && e.T == name
+		}); ok {
+			return o.(*schema.EnumType), true
+		}
+	}
+	return nil, false
+}
+
+// realmMultirange returns the multirange type with the given (optionally qualified)
+// name. In case the name is not qualified, the given schema is searched first.
+func realmMultirange(r *schema.Realm, s *schema.Schema, name string) (*CustomMultirangeType, bool) {
+	sr := append([]*schema.Schema{s}, r.Schemas...)
+	if ns, n := parseFmtType(name); ns != "" {
+		s1, ok := r.Schema(ns)
+		if !ok {
+			return nil, false
+		}
+		sr, name = []*schema.Schema{s1}, n
+	}
+	for _, s := range sr {
+		if o, ok := s.Object(func(o schema.Object) bool {
+			rt, ok := o.(*CustomRangeType)
+			return ok && rt.MultirangeName() == name
+		}); ok {
+			return o.(*CustomRangeType).MultirangeType(), true
+		}
+	}
+	return nil, false
+}
+
+// rangeSpec converts a range type into its spec.
+func rangeSpec(r *CustomRangeType) (*rangeType, error) {
+	if r.Subtype == nil {
+		return nil, fmt.Errorf("missing subtype for range type %q", r.T)
+	}
+	spec := &rangeType{
+		Name:   r.T,
+		Schema: specutil.SchemaRef(r.Schema.Name),
+	}
+	st, err := columnTypeSpec(r.Subtype)
+	if err != nil {
+		return nil, err
+	}
+	spec.Subtype = st.Type
+	for _, a := range []struct {
+		name, v string
+	}{
+		{name: "subtype_opclass", v: r.OpClass},
+		{name: "collation", v: r.Collation},
+		{name: "canonical", v: r.Canonical},
+		{name: "subtype_diff", v: r.SubtypeDiff},
+		{name: "multirange", v: r.Multirange},
+		{name: "comment", v: rangeComment(r)},
+	} {
+		if a.v != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr(a.name, a.v))
+		}
+	}
+	return spec, nil
+}
+
+// multirangeSpec returns the column spec of a multirange type. Multirange types
+// are referenced by their names, as they are not defined by a dedicated block.
+func multirangeSpec(m *CustomMultirangeType) *sqlspec.Column {
+	return &sqlspec.Column{Type: &schemahcl.Type{T: m.T}}
+}
+
+// Query to list the user-defined range types of the given schemas.
+const rangesQueryTmpl = `
+SELECT
+  n.nspname AS schema_name,
+  t.typname AS type_name,
+  format_type(r.rngsubtype, NULL) AS subtype,
+  CASE WHEN opc.opcdefault THEN NULL ELSE opc.opcname END AS subtype_opclass,
+  CASE WHEN r.rngcollation = st.typcollation THEN NULL ELSE co.collname END AS collation,
+  NULLIF(r.rngcanonical::text, '-') AS canonical,
+  NULLIF(r.rngsubdiff::text, '-') AS subtype_diff,
+  %s AS multirange_name,
+  obj_description(t.oid, 'pg_type') AS comment
+FROM
+  pg_catalog.pg_range AS r
+  JOIN pg_catalog.pg_type AS t ON t.oid = r.rngtypid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = t.typnamespace
+  JOIN pg_catalog.pg_type AS st ON st.oid = r.rngsubtype
+  JOIN pg_catalog.pg_opclass AS opc ON opc.oid = r.rngsubopc
+  LEFT JOIN pg_catalog.pg_collation AS co ON co.oid = r.rngcollation
+WHERE
+  n.nspname IN (%s)
+  AND NOT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_depend AS e
+    WHERE e.classid = 'pg_catalog.pg_type'::regclass AND e.objid = t.oid AND e.deptype = 'e'
+  )
+ORDER BY
+  n.nspname, t.typname
+`
+
+var (
+	rangesQuery        = fmt.Sprintf(rangesQueryTmpl, "(SELECT mt.typname FROM pg_catalog.pg_type AS mt WHERE mt.oid = r.rngmultitypid)", "%s")
+	rangesQueryBelow14 = fmt.Sprintf(rangesQueryTmpl, "NULL", "%s")
+)
@@ -0,0 +1,234 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomRangeType_MultirangeName(t *testing.T) {
+	require.Equal(t, "floatmultirange", (&CustomRangeType{T: "floatrange"}).MultirangeName())
+	require.Equal(t, "timespan_multirange", (&CustomRangeType{T: "timespan"}).MultirangeName())
+	require.Equal(t, "spans", (&CustomRangeType{T: "timespan", Multirange: "spans"}).MultirangeName())
+}
+
+func TestDriver_InspectRanges(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(queryEnums).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | enum_id | type    | enum_value | comment
+-------------+---------+---------+------------+---------
+ public      |   16774 |  level  | low        | nil
+ public      |   16774 |  level  | high       | nil
+`))
+	mk.ExpectQuery(queryRanges).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | type_name  | subtype          | subtype_opclass | collation | canonical | subtype_diff        | multirange_name     | comment
+-------------+------------+------------------+-----------------+-----------+-----------+---------------------+---------------------+---------
+ public      | floatrange | double precision | nil             | nil       | nil       | float8mi            | floatmultirange     | nil
+ public      | levels     | level            | nil             | nil       | nil       | nil                 | levels_multirange   | levels
+ public      | textrange  | text             | text_pattern_ops| C         | nil       | public.text_diff    | textranges          | nil
+`))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectTypes,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	s := r.Schemas[0]
+	require.Len(t, s.Objects, 4)
+	e := s.Objects[0].(*schema.EnumType)
+	require.Equal(t, []schema.Object{
+		e,
+		&CustomRangeType{T: "floatrange", Schema: s, Subtype: &schema.FloatType{T: TypeDouble}, SubtypeDiff: "float8mi"},
+		(&CustomRangeType{T: "levels", Schema: s, Subtype: e}).SetComment("levels"),
+		&CustomRangeType{T: "textrange", Schema: s, Subtype: &schema.StringType{T: TypeText}, OpClass: "text_pattern_ops", Collation: "C", SubtypeDiff: "text_diff", Multirange: "textranges"},
+	}, s.Objects)
+}
+
+func TestDiff_Ranges(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	from, to := schema.New("public"), schema.New("public")
+	from.AddObjects(
+		&CustomRangeType{T: "same", Subtype: &schema.IntegerType{T: TypeInteger}, Multirange: "same_multirange"},
+		&CustomRangeType{T: "changed", Subtype: &schema.IntegerType{T: TypeInteger}},
+		&CustomRangeType{T: "commented", Subtype: &schema.TimeType{T: TypeDate}},
+		&CustomRangeType{T: "dropped", Subtype: &schema.IntegerType{T: TypeInteger}},
+	)
+	to.AddObjects(
+		// Explicit default multirange name is equal to an unset one.
+		&CustomRangeType{T: "same", Subtype: &schema.IntegerType{T: TypeInteger}},
+		&CustomRangeType{T: "changed", Subtype: &schema.IntegerType{T: TypeBigInt}},
+		(&CustomRangeType{T: "commented", Subtype: &schema.TimeType{T: TypeDate}}).SetComment("dates"),
+		&CustomRangeType{T: "added", Subtype: &schema.IntegerType{T: TypeInteger}},
+	)
+	changes, err := drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]},
+		&schema.ModifyObject{From: from.Objects[2], To: to.Objects[2]},
+		&schema.DropObject{O: from.Objects[3]},
+		&schema.AddObject{O: to.Objects[3]},
+	}, changes)
+}
+
+func TestPlanChanges_Ranges(t *testing.T) {
+	s := schema.New("public")
+	e := &schema.EnumType{T: "level", Values: []string{"low", "high"}, Schema: s}
+	r1 := (&CustomRangeType{T: "levelrange", Schema: s, Subtype: e}).SetComment("levels")
+	r2 := &CustomRangeType{T: "textrange", Schema: s, Subtype: &schema.StringType{T: TypeText}, OpClass: "text_pattern_ops", Collation: "C", Multirange: "textranges"}
+	tt := schema.NewTable("t").
+		AddColumns(
+			schema.NewColumn("l").SetType(r1),
+			schema.NewColumn("m").SetType(r2.MultirangeType()),
+		)
+	s.AddTables(tt)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tt},
+		&schema.AddObject{O: r2},
+		&schema.AddObject{O: r1},
+		&schema.AddObject{O: e},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TYPE "public"."textrange" AS RANGE (SUBTYPE = text, SUBTYPE_OPCLASS = text_pattern_ops, COLLATION = "C", MULTIRANGE_TYPE_NAME = "public"."textranges")`, `DROP TYPE "public"."textrange"`},
+		{`CREATE TYPE "public"."level" AS ENUM ('low', 'high')`, `DROP TYPE "public"."level"`},
+		{`CREATE TYPE "public"."levelrange" AS RANGE (SUBTYPE = "public"."level")`, `DROP TYPE "public"."levelrange"`},
+		{`COMMENT ON TYPE "public"."levelrange" IS 'levels'`, `COMMENT ON TYPE "public"."levelrange" IS ''`},
+		{`CREATE TABLE "public"."t" ("l" "public"."levelrange" NOT NULL, "m" "public"."textranges" NOT NULL)`, `DROP TABLE "public"."t"`},
+	}, planCmds(plan))
+
+	// Range types are dropped after the tables that use them, and before their subtypes.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropObject{O: e},
+		&schema.DropObject{O: r1},
+		&schema.DropObject{O: r2},
+		&schema.DropTable{T: tt},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`DROP TABLE "public"."t"`, `CREATE TABLE "public"."t" ("l" "public"."levelrange" NOT NULL, "m" "public"."textranges" NOT NULL)`},
+		{`DROP TYPE "public"."levelrange"`, `CREATE TYPE "public"."levelrange" AS RANGE (SUBTYPE = "public"."level")`},
+		{`DROP TYPE "public"."level"`, `CREATE TYPE "public"."level" AS ENUM ('low', 'high')`},
+		{`DROP TYPE "public"."textrange"`, `CREATE TYPE "public"."textrange" AS RANGE (SUBTYPE = text, SUBTYPE_OPCLASS = text_pattern_ops, COLLATION = "C", MULTIRANGE_TYPE_NAME = "public"."textranges")`},
+	}, planCmds(plan))
+
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: r1, To: (&CustomRangeType{T: "levelrange", Schema: s, Subtype: e}).SetComment("all levels")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`COMMENT ON TYPE "public"."levelrange" IS 'all levels'`, `COMMENT ON TYPE "public"."levelrange" IS 'levels'`},
+	}, planCmds(plan))
+
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: r2, To: &CustomRangeType{T: "textrange", Schema: s, Subtype: &schema.StringType{T: TypeText}}},
+	})
+	require.EqualError(t, err, `changing the definition of range type "textrange" is not supported`)
+}
+
+func TestMarshalSpec_Ranges(t *testing.T) {
+	s := schema.New("public")
+	e := &schema.EnumType{T: "level", Values: []string{"low", "high"}, Schema: s}
+	r1 := (&CustomRangeType{T: "levelrange", Schema: s, Subtype: e}).SetComment("levels")
+	r2 := &CustomRangeType{T: "textrange", Schema: s, Subtype: &schema.StringType{T: TypeText}, OpClass: "text_pattern_ops", Collation: "C", SubtypeDiff: "text_diff", Multirange: "textranges"}
+	s.AddObjects(e, r1, r2)
+	s.AddTables(
+		schema.NewTable("t").
+			AddColumns(
+				schema.NewColumn("l").SetType(r1),
+				schema.NewColumn("m").SetType(r2.MultirangeType()),
+			),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  column "l" {
+    null = false
+    type = range.levelrange
+  }
+  column "m" {
+    null = false
+    type = sql("textranges")
+  }
+}
+enum "level" {
+  schema = schema.public
+  values = ["low", "high"]
+}
+range "levelrange" {
+  schema  = schema.public
+  subtype = enum.level
+  comment = "levels"
+}
+range "textrange" {
+  schema          = schema.public
+  subtype         = text
+  subtype_opclass = "text_pattern_ops"
+  collation       = "C"
+  subtype_diff    = "text_diff"
+  multirange      = "textranges"
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	g1, ok := schemaRange(&got, "levelrange")
+	require.True(t, ok)
+	require.Equal(t, "levels", rangeComment(g1))
+	require.Equal(t, "level", g1.Subtype.(*schema.EnumType).T)
+	g2, ok := schemaRange(&got, "textrange")
+	require.True(t, ok)
+	require.Equal(t, r2.Subtype, g2.Subtype)
+	require.Equal(t, []string{r2.OpClass, r2.Collation, r2.SubtypeDiff, r2.Multirange}, []string{g2.OpClass, g2.Collation, g2.SubtypeDiff, g2.Multirange})
+	tt, ok := got.Table("t")
+	require.True(t, ok)
+	require.Equal(t, g1, tt.Columns[0].Type.Type)
+	require.Equal(t, g2.MultirangeType(), tt.Columns[1].Type.Type)
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+range "r" {
+  schema = schema.public
+}
+`), &got, nil)
+	require.EqualError(t, err, `missing attribute range.r.subtype`)
+}
@@ -0,0 +1,791 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// Role defines a database role. Roles are realm-level objects that are
+	// inspected only if the Roles inspect option is enabled.
+	// https://www.postgresql.org/docs/current/sql-createrole.html
+	Role struct {
+		schema.Object
+		Name        string
+		Superuser   bool
+		CreateDB    bool
+		CreateRole  bool
+		Login       bool
+		Replication bool
+		BypassRLS   bool
+		// NoInherit indicates the role does not inherit the
+		// privileges of the roles it is a member of.
+		NoInherit bool
+		// ConnLimit holds the maximum number of concurrent
+		// connections of the role. Nil means no limit.
+		ConnLimit *int
+		// MemberOf lists the names of the roles this role is a member of.
+		MemberOf []string
+		Attrs    []schema.Attr
+	}
+
+	// Owner describes the owner role of a schema or a table. Ownership is
+	// inspected only if the Roles inspect option is enabled.
+	Owner struct {
+		schema.Attr
+		Name string
+	}
+
+	// DefaultPrivilege describes the privileges that are granted to a role (the grantee)
+	// on objects that will be created in the future by another role. Default privileges are
+	// inspected only if the Roles inspect option is enabled.
+	// https://www.postgresql.org/docs/current/sql-alterdefaultprivileges.html
+	DefaultPrivilege struct {
+		schema.Object
+		Role       string         // The role that creates the objects (FOR ROLE).
+		Schema     *schema.Schema // Optional schema (IN SCHEMA). Nil means all schemas.
+		On         string         // The object type, e.g. TABLES.
+		Grantee    string         // The role that is granted the privileges, or PUBLIC.
+		Privileges []string       // The granted privileges, e.g. SELECT.
+	}
+)
+
+// List of object types that default privileges can be defined on.
+const (
+	DefaultPrivilegesOnTables    = "TABLES"
+	DefaultPrivilegesOnSequences = "SEQUENCES"
+	DefaultPrivilegesOnFunctions = "FUNCTIONS"
+	DefaultPrivilegesOnTypes     = "TYPES"
+	DefaultPrivilegesOnSchemas   = "SCHEMAS"
+)
+
+// SpecType returns the type of the role.
+func (*Role) SpecType() string { return "role" }
+
+// SpecName returns the name of the role.
+func (r *Role) SpecName() string { return r.Name }
+
+// SetComment sets or updates the comment of the role.
+func (r *Role) SetComment(c string) *Role {
+	schema.ReplaceOrAppend(&r.Attrs, &schema.Comment{Text: c})
+	return r
+}
+
+// DependsOn implements the sqlx.Depender interface. Schemas and roles
+// must exist before default privileges are defined on (or for) them.
+func (p *DefaultPrivilege) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddSchema:
+		return p.Schema != nil && p.Schema.Name == o.S.Name
+	case *schema.AddObject:
+		r, ok := o.O.(*Role)
+		return ok && (r.Name == p.Role || r.Name == p.Grantee)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. Default privileges
+// are revoked before their schema or roles are dropped.
+func (p *DefaultPrivilege) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.DropSchema:
+		return p.Schema != nil && p.Schema.Name == o.S.Name
+	case *schema.DropObject:
+		r, ok := o.O.(*Role)
+		return ok && (r.Name == p.Role || r.Name == p.Grantee)
+	}
+	return false
+}
+
+// inspectRoles queries and appends the roles of the realm. Predefined
+// roles (pg_*) and the bootstrap superuser are not inspected.
+func (i *inspect) inspectRoles(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, rolesQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying roles: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			role            = &Role{}
+			limit           int
+			inherit         bool
+			member, comment sql.NullString
+		)
+		if err := rows.Scan(
+			&role.Name, &role.Superuser, &inherit, &role.CreateRole, &role.CreateDB, &role.Login,
+			&role.Replication, &role.BypassRLS, &limit, &member, &comment,
+		); err != nil {
+			return fmt.Errorf("postgres: scanning role: %w", err)
+		}
+		role.NoInherit = !inherit
+		if limit >= 0 {
+			role.ConnLimit = &limit
+		}
+		if sqlx.ValidString(member) {
+			if err := json.Unmarshal([]byte(member.String), &role.MemberOf); err != nil {
+				return fmt.Errorf("postgres: parsing memberships of role %q: %w", role.Name, err)
+			}
+		}
+		if sqlx.ValidString(comment) {
+			role.SetComment(comment.String)
+		}
+		r.AddObjects(role)
+	}
+	return rows.Err()
+}
+
+// inspectDefaultPrivileges queries and appends the default privileges of the realm.
+func (i *inspect) inspectDefaultPrivileges(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, defaultPrivilegesQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying default privileges: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			role, on, grantee, privs string
+			ns                       sql.NullString
+		)
+		if err := rows.Scan(&role, &ns, &on, &grantee, &privs); err != nil {
+			return fmt.Errorf("postgres: scanning default privileges: %w", err)
+		}
+		p := &DefaultPrivilege{Role: role, On: defaultPrivilegesOn(on), Grantee: grantee}
+		if err := json.Unmarshal([]byte(privs), &p.Privileges); err != nil {
+			return fmt.Errorf("postgres: parsing default privileges of role %q: %w", role, err)
+		}
+		if sqlx.ValidString(ns) {
+			s, ok := r.Schema(ns.String)
+			if !ok {
+				continue // Schema was excluded from inspection.
+			}
+			p.Schema = s
+		}
+		if !implicitPrivilege(p) {
+			r.AddObjects(p)
+		}
+	}
+	return rows.Err()
+}
+
+// implicitPrivilege reports if the default privilege is implied by PostgreSQL. Global default
+// privileges are stored with the privileges that are implicitly granted to their owner and to
+// PUBLIC (on functions and types). These are skipped, as they are not set by the user.
+func implicitPrivilege(p *DefaultPrivilege) bool {
+	if p.Grantee == p.Role {
+		return true
+	}
+	return p.Schema == nil && p.Grantee == "PUBLIC" && len(p.Privileges) == 1 &&
+		(p.On == DefaultPrivilegesOnFunctions && p.Privileges[0] == "EXECUTE" || p.On == DefaultPrivilegesOnTypes && p.Privileges[0] == "USAGE")
+}
+
+// defaultPrivilegesOn maps the pg_default_acl.defaclobjtype value to its object type.
+func defaultPrivilegesOn(c string) string {
+	switch c {
+	case "S":
+		return DefaultPrivilegesOnSequences
+	case "f":
+		return DefaultPrivilegesOnFunctions
+	case "T":
+		return DefaultPrivilegesOnTypes
+	case "n":
+		return DefaultPrivilegesOnSchemas
+	default:
+		return DefaultPrivilegesOnTables
+	}
+}
+
+// inspectOwners queries and sets the owners of the schemas and tables of the realm.
+func (i *inspect) inspectOwners(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(ownersQuery, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying owners: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, owner string
+			name      sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &owner); err != nil {
+			return fmt.Errorf("postgres: scanning owner: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			continue
+		}
+		if !sqlx.ValidString(name) {
+			schema.ReplaceOrAppend(&s.Attrs, &Owner{Name: owner})
+		} else if t, ok := s.Table(name.String); ok {
+			schema.ReplaceOrAppend(&t.Attrs, &Owner{Name: owner})
+		}
+	}
+	return rows.Err()
+}
+
+// ownerDiff returns the ownership change between the two attribute lists. Ownership
+// is compared only if it is known in both states. i.e., the current state was
+// inspected with the Roles inspect option and the desired state defines it.
+func ownerDiff(from, to []schema.Attr) []schema.Change {
+	var o1, o2 Owner
+	if !sqlx.Has(from, &o1) || !sqlx.Has(to, &o2) || o1.Name == o2.Name {
+		return nil
+	}
+	return []schema.Change{&schema.ModifyAttr{From: &o1, To: &o2}}
+}
+
+// roleDiff returns the changes for migrating the roles of the realm.
+func roleDiff(from, to *schema.Realm) []schema.Change {
+	return sqlx.ObjectsDiff(from.Objects, to.Objects, func(r1, r2 *Role) bool {
+		return r1.Name == r2.Name
+	}, func(r1, r2 *Role) bool {
+		return !slices.Equal(roleOptions(r1, nil), roleOptions(r2, nil)) || !slices.Equal(roleMembers(r1), roleMembers(r2)) || roleComment(r1) != roleComment(r2)
+	})
+}
+
+// roleOptions returns the options of the role, as used by the CREATE and ALTER ROLE
+// commands. If "from" is nil, all options are returned. Otherwise, only the options
+// that were changed are returned.
+func roleOptions(r, from *Role) []string {
+	var opts []string
+	for _, o := range []struct {
+		v, f bool
+		name string
+	}{
+		{r.Superuser, from != nil && from.Superuser, "SUPERUSER"},
+		{r.CreateDB, from != nil && from.CreateDB, "CREATEDB"},
+		{r.CreateRole, from != nil && from.CreateRole, "CREATEROLE"},
+		{!r.NoInherit, from == nil || !from.NoInherit, "INHERIT"},
+		{r.Login, from != nil && from.Login, "LOGIN"},
+		{r.Replication, from != nil && from.Replication, "REPLICATION"},
+		{r.BypassRLS, from != nil && from.BypassRLS, "BYPASSRLS"},
+	} {
+		switch {
+		case from != nil && o.v == o.f:
+		case o.v:
+			opts = append(opts, o.name)
+		default:
+			opts = append(opts, "NO"+o.name)
+		}
+	}
+	if l1, l2 := roleConnLimit(r), roleConnLimit(from); from == nil || l1 != l2 {
+		opts = append(opts, "CONNECTION LIMIT "+strconv.Itoa(l1))
+	}
+	return opts
+}
+
+// roleConnLimit returns the connection limit of the role, or -1 if there is no limit.
+func roleConnLimit(r *Role) int {
+	if r == nil || r.ConnLimit == nil {
+		return -1
+	}
+	return *r.ConnLimit
+}
+
+// roleMembers returns the role memberships in their canonical form.
+func roleMembers(r *Role) []string {
+	ms := slices.Clone(r.MemberOf)
+	slices.Sort(ms)
+	return slices.Compact(ms)
+}
+
+func roleComment(r *Role) string {
+	var c schema.Comment
+	sqlx.Has(r.Attrs, &c)
+	return c.Text
+}
+
+// defaultPrivilegeDiff returns the changes for migrating the default privileges of the realm.
+func defaultPrivilegeDiff(from, to *schema.Realm) []schema.Change {
+	return sqlx.ObjectsDiff(from.Objects, to.Objects, sameDefaultPrivilege, func(p1, p2 *DefaultPrivilege) bool {
+		return !slices.Equal(privileges(p1), privileges(p2))
+	})
+}
+
+// sameDefaultPrivilege reports if the two default privileges are defined
+// by the same role, on the same objects and grantee.
+func sameDefaultPrivilege(p1, p2 *DefaultPrivilege) bool {
+	return p1.Role == p2.Role && p1.Grantee == p2.Grantee &&
+		strings.EqualFold(p1.On, p2.On) && privilegeSchema(p1) == privilegeSchema(p2)
+}
+
+func privilegeSchema(p *DefaultPrivilege) string {
+	if p.Schema == nil {
+		return ""
+	}
+	return p.Schema.Name
+}
+
+// privileges returns the privileges in their canonical form.
+func privileges(p *DefaultPrivilege) []string {
+	ps := make([]string, len(p.Privileges))
+	for i := range p.Privileges {
+		ps[i] = strings.ToUpper(p.Privileges[i])
+	}
+	slices.Sort(ps)
+	return slices.Compact(ps)
+}
+
+// addRole plans the creation of a role.
+func (s *state) addRole(src schema.Change, r *Role) {
+	create, drop := s.createDropRole(r)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create role %q", r.Name),
+	})
+	for _, m := range roleMembers(r) {
+		s.append(s.roleMember(src, r, m, true))
+	}
+	if c := roleComment(r); c != "" {
+		s.append(s.roleComment(src, r, c, ""))
+	}
+}
+
+// dropRole plans the removal of a role.
+func (s *state) dropRole(src schema.Change, r *Role) {
+	create, drop := s.createDropRole(r)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop role %q", r.Name),
+	})
+}
+
+// modifyRole plans the changes of a role.
+func (s *state) modifyRole(src schema.Change, from, to *Role) {
+	if opts := roleOptions(to, from); len(opts) > 0 {
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     s.Build("ALTER ROLE").Ident(to.Name).P("WITH").P(opts...).String(),
+			Reverse: s.Build("ALTER ROLE").Ident(to.Name).P("WITH").P(roleOptions(from, to)...).String(),
+			Comment: fmt.Sprintf("modify role %q", to.Name),
+		})
+	}
+	m1, m2 := roleMembers(from), roleMembers(to)
+	for _, m := range m1 {
+		if !slices.Contains(m2, m) {
+			s.append(s.roleMember(src, to, m, false))
+		}
+	}
+	for _, m := range m2 {
+		if !slices.Contains(m1, m) {
+			s.append(s.roleMember(src, to, m, true))
+		}
+	}
+	if c1, c2 := roleComment(from), roleComment(to); c1 != c2 {
+		s.append(s.roleComment(src, to, c2, c1))
+	}
+}
+
+func (s *state) createDropRole(r *Role) (string, string) {
+	b := s.Build("CREATE ROLE").Ident(r.Name)
+	// Options that are set to their default values are omitted.
+	if opts := roleOptions(r, &Role{}); len(opts) > 0 {
+		b.P("WITH").P(opts...)
+	}
+	return b.String(), s.Build("DROP ROLE").Ident(r.Name).String()
+}
+
+func (s *state) roleMember(src schema.Change, r *Role, group string, grant bool) *migrate.Change {
+	g, v := s.Build("GRANT").Ident(group).P("TO").Ident(r.Name), s.Build("REVOKE").Ident(group).P("FROM").Ident(r.Name)
+	if !grant {
+		g, v = v, g
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     g.String(),
+		Reverse: v.String(),
+		Comment: fmt.Sprintf("set membership of role %q in %q", r.Name, group),
+	}
+}
+
+func (s *state) roleComment(src schema.Change, r *Role, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON ROLE").Ident(r.Name).P("IS")
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Reverse: b.Clone().P(quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to role: %q", r.Name),
+	}
+}
+
+// addDefaultPrivilege plans the creation of default privileges.
+func (s *state) addDefaultPrivilege(src schema.Change, p *DefaultPrivilege) {
+	s.append(s.defaultPrivilege(src, p, privileges(p), true))
+}
+
+// dropDefaultPrivilege plans the removal of default privileges.
+func (s *state) dropDefaultPrivilege(src schema.Change, p *DefaultPrivilege) {
+	s.append(s.defaultPrivilege(src, p, privileges(p), false))
+}
+
+// modifyDefaultPrivilege plans the changes of default privileges.
+func (s *state) modifyDefaultPrivilege(src schema.Change, from, to *DefaultPrivilege) {
+	var (
+		grant, revoke []string
+		p1, p2        = privileges(from), privileges(to)
+	)
+	for _, p := range p1 {
+		if !slices.Contains(p2, p) {
+			revoke = append(revoke, p)
+		}
+	}
+	for _, p := range p2 {
+		if !slices.Contains(p1, p) {
+			grant = append(grant, p)
+		}
+	}
+	if len(revoke) > 0 {
+		s.append(s.defaultPrivilege(src, to, revoke, false))
+	}
+	if len(grant) > 0 {
+		s.append(s.defaultPrivilege(src, to, grant, true))
+	}
+}
+
+func (s *state) defaultPrivilege(src schema.Change, p *DefaultPrivilege, privs []string, grant bool) *migrate.Change {
+	stmt := func(grant bool) string {
+		b := s.Build("ALTER DEFAULT PRIVILEGES FOR ROLE").Ident(p.Role)
+		if p.Schema != nil {
+			b.P("IN SCHEMA").Ident(p.Schema.Name)
+		}
+		if grant {
+			b.P("GRANT")
+		} else {
+			b.P("REVOKE")
+		}
+		b.P(strings.Join(privs, ", "), "ON", strings.ToUpper(p.On))
+		if grant {
+			b.P("TO")
+		} else {
+			b.P("FROM")
+		}
+		if strings.EqualFold(p.Grantee, "PUBLIC") {
+			b.P("PUBLIC")
+		} else {
+			b.Ident(p.Grantee)
+		}
+		return b.String()
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     stmt(grant),
+		Reverse: stmt(!grant),
+		Comment: fmt.Sprintf("set default privileges of role %q on %s for %q", p.Role, strings.ToLower(p.On), p.Grantee),
+	}
+}
+
+// ownerChange plans the ownership change of a schema or a table.
+func (s *state) ownerChange(src schema.Change, typ string, b *sqlx.Builder, from, to string) *migrate.Change {
+	r := b.Clone().P("OWNER TO")
+	if from != "" {
+		r.Ident(from)
+	} else {
+		// Objects are owned by the role that created them.
+		r.P("CURRENT_USER")
+	}
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P("OWNER TO").Ident(to).String(),
+		Reverse: r.String(),
+		Comment: fmt.Sprintf("set owner of %s to %q", typ, to),
+	}
+}
+
+// addTableOwner plans the ownership of a new table.
+func (s *state) addTableOwner(add *schema.AddTable) {
+	if o := (Owner{}); sqlx.Has(add.T.Attrs, &o) {
+		s.append(s.ownerChange(add, fmt.Sprintf("table %q", add.T.Name), s.Build("ALTER TABLE").Table(add.T), "", o.Name))
+	}
+}
+
+// convertRoles converts the role specs and adds them to the realm.
+func convertRoles(specs []*role, r *schema.Realm) error {
+	for _, spec := range specs {
+		role := &Role{Name: spec.Name}
+		for _, a := range []struct {
+			name string
+			v    *bool
+		}{
+			{name: "superuser", v: &role.Superuser},
+			{name: "createdb", v: &role.CreateDB},
+			{name: "createrole", v: &role.CreateRole},
+			{name: "login", v: &role.Login},
+			{name: "replication", v: &role.Replication},
+			{name: "bypassrls", v: &role.BypassRLS},
+		} {
+			if attr, ok := spec.Attr(a.name); ok {
+				b, err := attr.Bool()
+				if err != nil {
+					return fmt.Errorf("parsing role %q attribute %q: %w", spec.Name, a.name, err)
+				}
+				*a.v = b
+			}
+		}
+		if attr, ok := spec.Attr("inherit"); ok {
+			b, err := attr.Bool()
+			if err != nil {
+				return fmt.Errorf("parsing role %q attribute \"inherit\": %w", spec.Name, err)
+			}
+			role.NoInherit = !b
+		}
+		if attr, ok := spec.Attr("connection_limit"); ok {
+			i, err := attr.Int()
+			if err != nil {
+				return fmt.Errorf("parsing role %q attribute \"connection_limit\": %w", spec.Name, err)
+			}
+			if i >= 0 {
+				role.ConnLimit = &i
+			}
+		}
+		if attr, ok := spec.Attr("member_of"); ok {
+			ms, err := attr.Strings()
+			if err != nil {
+				return fmt.Errorf("parsing role %q attribute \"member_of\": %w", spec.Name, err)
+			}
+			role.MemberOf = ms
+		}
+		if attr, ok := spec.Attr("comment"); ok {
+			c, err := attr.String()
+			if err != nil {
+				return fmt.Errorf("parsing role %q attribute \"comment\": %w", spec.Name, err)
+			}
+			role.SetComment(c)
+		}
+		r.AddObjects(role)
+	}
+	return nil
+}
+
+// roleSpecs returns the role specs of the realm.
+func roleSpecs(r *schema.Realm) []*role {
+	var specs []*role
+	for _, o := range r.Objects {
+		r, ok := o.(*Role)
+		if !ok {
+			continue
+		}
+		spec := &role{Name: r.Name}
+		for _, a := range []struct {
+			name string
+			v    bool
+		}{
+			{name: "superuser", v: r.Superuser},
+			{name: "createdb", v: r.CreateDB},
+			{name: "createrole", v: r.CreateRole},
+			{name: "login", v: r.Login},
+			{name: "replication", v: r.Replication},
+			{name: "bypassrls", v: r.BypassRLS},
+		} {
+			if a.v {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr(a.name, true))
+			}
+		}
+		if r.NoInherit {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("inherit", false))
+		}
+		if r.ConnLimit != nil {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.IntAttr("connection_limit", *r.ConnLimit))
+		}
+		if ms := roleMembers(r); len(ms) > 0 {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringsAttr("member_of", ms...))
+		}
+		if c := roleComment(r); c != "" {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// convertDefaultPrivileges converts the default privileges specs and adds them to the realm.
+func convertDefaultPrivileges(specs []*defaultPrivilege, r *schema.Realm) error {
+	for _, spec := range specs {
+		p := &DefaultPrivilege{Role: spec.Role, On: spec.On, Grantee: spec.To, Privileges: spec.Privileges}
+		switch {
+		case p.Role == "":
+			return fmt.Errorf("missing attribute default_privileges.role")
+		case p.Grantee == "":
+			return fmt.Errorf("missing attribute default_privileges.to for role %q", p.Role)
+		case p.On == "":
+			return fmt.Errorf("missing attribute default_privileges.on for role %q", p.Role)
+		case len(p.Privileges) == 0:
+			return fmt.Errorf("missing attribute default_privileges.privileges for role %q", p.Role)
+		}
+		if spec.Schema != nil {
+			name, err := specutil.SchemaName(spec.Schema)
+			if err != nil {
+				return fmt.Errorf("parsing default privileges schema of role %q: %w", p.Role, err)
+			}
+			s, ok := r.Schema(name)
+			if !ok {
+				return fmt.Errorf("schema %q was not found for default privileges of role %q", name, p.Role)
+			}
+			p.Schema = s
+		}
+		r.AddObjects(p)
+	}
+	return nil
+}
+
+// defaultPrivilegeSpecs returns the default privileges specs of the realm.
+func defaultPrivilegeSpecs(r *schema.Realm) []*defaultPrivilege {
+	var specs []*defaultPrivilege
+	for _, o := range r.Objects {
+		p, ok := o.(*DefaultPrivilege)
+		if !ok {
+			continue
+		}
+		spec := &defaultPrivilege{
+			Role:       p.Role,
+			On:         strings.ToUpper(p.On),
+			To:         p.Grantee,
+			Privileges: privileges(p),
+		}
+		if p.Schema != nil {
+			spec.Schema = specutil.SchemaRef(p.Schema.Name)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// convertOwner converts the "owner" attribute of a schema or a table, if exists.
+func convertOwner(spec specutil.Attrer, attrs *[]schema.Attr) error {
+	attr, ok := spec.Attr("owner")
+	if !ok {
+		return nil
+	}
+	o, err := attr.String()
+	if err != nil {
+		return fmt.Errorf("parsing attribute \"owner\": %w", err)
+	}
+	schema.ReplaceOrAppend(attrs, &Owner{Name: o})
+	return nil
+}
+
+// convertSchemaOwners converts the "owner" attribute of the schema specs.
+func convertSchemaOwners(specs []*sqlspec.Schema, r *schema.Realm) error {
+	for _, spec := range specs {
+		s, ok := r.Schema(spec.Name)
+		if !ok {
+			continue
+		}
+		if err := convertOwner(spec, &s.Attrs); err != nil {
+			return fmt.Errorf("schema %q: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// ownerSpec returns the "owner" attribute of a schema or a table, if exists.
+func ownerSpec(attrs []schema.Attr) (*schemahcl.Attr, bool) {
+	if o := (Owner{}); sqlx.Has(attrs, &o) {
+		return schemahcl.StringAttr("owner", o.Name), true
+	}
+	return nil, false
+}
+
+const (
+	// Query to list the roles of the database, excluding the predefined
+	// roles and the ones that were created during initialization.
+	rolesQuery = `
+SELECT
+  r.rolname,
+  r.rolsuper,
+  r.rolinherit,
+  r.rolcreaterole,
+  r.rolcreatedb,
+  r.rolcanlogin,
+  r.rolreplication,
+  r.rolbypassrls,
+  r.rolconnlimit,
+  (
+    SELECT json_agg(g.rolname ORDER BY g.rolname)
+    FROM pg_catalog.pg_auth_members AS m
+    JOIN pg_catalog.pg_roles AS g ON g.oid = m.roleid
+    WHERE m.member = r.oid
+  ) AS member_of,
+  pg_catalog.shobj_description(r.oid, 'pg_authid') AS comment
+FROM
+  pg_catalog.pg_roles AS r
+WHERE
+  r.oid >= 16384
+  AND r.rolname !~ '^pg_'
+ORDER BY
+  r.rolname
+`
+
+	// Query to list the default privileges of the database.
+	defaultPrivilegesQuery = `
+SELECT
+  pg_catalog.pg_get_userbyid(d.defaclrole) AS role,
+  n.nspname,
+  d.defaclobjtype,
+  CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE pg_catalog.pg_get_userbyid(a.grantee) END AS grantee,
+  json_agg(a.privilege_type ORDER BY a.privilege_type) AS privileges
+FROM
+  pg_catalog.pg_default_acl AS d
+  LEFT JOIN pg_catalog.pg_namespace AS n ON n.oid = d.defaclnamespace
+  CROSS JOIN LATERAL pg_catalog.aclexplode(d.defaclacl) AS a
+GROUP BY
+  1, 2, 3, 4
+ORDER BY
+  1, 2, 3, 4
+`
+
+	// Query to list the owners of the schemas and their tables. The placeholders
+	// are shared by the two queries, as they are bound to the same schema names.
+	ownersQuery = `
+SELECT
+  n.nspname,
+  NULL AS relname,
+  pg_catalog.pg_get_userbyid(n.nspowner) AS owner
+FROM
+  pg_catalog.pg_namespace AS n
+WHERE
+  n.nspname IN (%[1]s)
+UNION ALL
+SELECT
+  n.nspname,
+  c.relname,
+  pg_catalog.pg_get_userbyid(c.relowner) AS owner
+FROM
+  pg_catalog.pg_class AS c
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE
+  c.relkind IN ('r', 'p')
+  AND n.nspname IN (%[1]s)
+ORDER BY
+  1, 2 NULLS FIRST
+`
+)
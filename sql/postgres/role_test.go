@@ -0,0 +1,272 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectRoles(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(ownersQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ nspname | relname | owner
+---------+---------+-------
+ public  | nil     | admin
+`))
+	mk.ExpectQuery(sqltest.Escape(rolesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ rolname | rolsuper | rolinherit | rolcreaterole | rolcreatedb | rolcanlogin | rolreplication | rolbypassrls | rolconnlimit | member_of   | comment
+---------+----------+------------+---------------+-------------+-------------+----------------+--------------+--------------+-------------+---------
+ admin   | t        | t          | t             | t           | t           | f              | f            | -1           | nil         | nil
+ app     | f        | f          | f             | f           | t           | f              | f            | 10           | ["readers"] | app user
+ readers | f        | t          | f             | f           | f           | f              | f            | -1           | nil         | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(defaultPrivilegesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ role  | nspname | defaclobjtype | grantee | privileges
+-------+---------+---------------+---------+---------------------------
+ admin | nil     | f             | PUBLIC  | ["EXECUTE"]
+ admin | nil     | r             | admin   | ["DELETE","INSERT","SELECT"]
+ admin | nil     | r             | readers | ["SELECT"]
+ admin | public  | S             | app     | ["SELECT","USAGE"]
+ admin | other   | r             | app     | ["SELECT"]
+`))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode:  schema.InspectSchemas,
+		Roles: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	limit := 10
+	require.Equal(t, []schema.Object{
+		&Role{Name: "admin", Superuser: true, CreateRole: true, CreateDB: true, Login: true},
+		(&Role{Name: "app", NoInherit: true, Login: true, ConnLimit: &limit, MemberOf: []string{"readers"}}).SetComment("app user"),
+		&Role{Name: "readers"},
+		&DefaultPrivilege{Role: "admin", On: DefaultPrivilegesOnTables, Grantee: "readers", Privileges: []string{"SELECT"}},
+		&DefaultPrivilege{Role: "admin", Schema: r.Schemas[0], On: DefaultPrivilegesOnSequences, Grantee: "app", Privileges: []string{"SELECT", "USAGE"}},
+	}, r.Objects)
+	var o Owner
+	require.True(t, sqlx.Has(r.Schemas[0].Attrs, &o))
+	require.Equal(t, "admin", o.Name)
+}
+
+func TestDiff_Roles(t *testing.T) {
+	var (
+		limit = 5
+		from  = schema.NewRealm(schema.New("public").AddAttrs(&Owner{Name: "admin"}))
+		to    = schema.NewRealm(schema.New("public").AddAttrs(&Owner{Name: "admin"}))
+		r1    = &Role{Name: "app", Login: true, MemberOf: []string{"b", "a"}}
+		r2    = &Role{Name: "app", Login: true, MemberOf: []string{"a", "b", "a"}}
+		p1    = &DefaultPrivilege{Role: "admin", On: "tables", Grantee: "app", Privileges: []string{"select", "INSERT"}}
+		p2    = &DefaultPrivilege{Role: "admin", On: DefaultPrivilegesOnTables, Grantee: "app", Privileges: []string{"INSERT", "SELECT"}}
+	)
+	from.AddObjects(r1, p1)
+	to.AddObjects(r2, p2)
+	changes, err := DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes, "roles and privileges are compared normalized")
+
+	r2.ConnLimit = &limit
+	p2.Privileges = []string{"SELECT"}
+	to.Schemas[0].Attrs = []schema.Attr{&Owner{Name: "app"}}
+	changes, err = DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, schema.Changes{
+		&schema.ModifyObject{From: r1, To: r2},
+		&schema.ModifyObject{From: p1, To: p2},
+		&schema.ModifySchema{S: to.Schemas[0], Changes: schema.Changes{&schema.ModifyAttr{From: &Owner{Name: "admin"}, To: &Owner{Name: "app"}}}},
+	}, schema.Changes(changes))
+
+	// Ownership is ignored if it is unknown in one of the states.
+	to.Schemas[0].Attrs = nil
+	to.Objects = nil
+	changes, err = DefaultDiff.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, schema.Changes{&schema.DropObject{O: r1}, &schema.DropObject{O: p1}}, schema.Changes(changes))
+
+	t1 := schema.NewTable("t").AddAttrs(&Owner{Name: "admin"})
+	t2 := schema.NewTable("t").AddAttrs(&Owner{Name: "app"})
+	changes, err = DefaultDiff.TableDiff(t1, t2)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyAttr{From: &Owner{Name: "admin"}, To: &Owner{Name: "app"}}}, changes)
+}
+
+func TestPlanChanges_Roles(t *testing.T) {
+	var (
+		limit = 5
+		s     = schema.New("public")
+		tbl   = schema.NewTable("t").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+		r1    = &Role{Name: "app", Login: true, MemberOf: []string{"readers"}}
+		r2    = (&Role{Name: "app", Login: true, CreateDB: true, NoInherit: true, ConnLimit: &limit, MemberOf: []string{"writers"}}).SetComment("c")
+		p1    = &DefaultPrivilege{Role: "admin", Schema: s, On: DefaultPrivilegesOnTables, Grantee: "app", Privileges: []string{"SELECT", "INSERT"}}
+		p2    = &DefaultPrivilege{Role: "admin", Schema: s, On: DefaultPrivilegesOnTables, Grantee: "app", Privileges: []string{"SELECT", "UPDATE"}}
+	)
+	tests := []struct {
+		changes []schema.Change
+		want    [][2]string
+	}{
+		{
+			changes: []schema.Change{&schema.AddObject{O: r1}},
+			want: [][2]string{
+				{`CREATE ROLE "app" WITH LOGIN`, `DROP ROLE "app"`},
+				{`GRANT "readers" TO "app"`, `REVOKE "readers" FROM "app"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropObject{O: r2}},
+			want: [][2]string{
+				{`DROP ROLE "app"`, `CREATE ROLE "app" WITH CREATEDB NOINHERIT LOGIN CONNECTION LIMIT 5`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyObject{From: r1, To: r2}},
+			want: [][2]string{
+				{`ALTER ROLE "app" WITH CREATEDB NOINHERIT CONNECTION LIMIT 5`, `ALTER ROLE "app" WITH NOCREATEDB INHERIT CONNECTION LIMIT -1`},
+				{`REVOKE "readers" FROM "app"`, `GRANT "readers" TO "app"`},
+				{`GRANT "writers" TO "app"`, `REVOKE "writers" FROM "app"`},
+				{`COMMENT ON ROLE "app" IS 'c'`, `COMMENT ON ROLE "app" IS ''`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.AddObject{O: p1}},
+			want: [][2]string{
+				{`ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" GRANT INSERT, SELECT ON TABLES TO "app"`, `ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" REVOKE INSERT, SELECT ON TABLES FROM "app"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyObject{From: p1, To: p2}},
+			want: [][2]string{
+				{`ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" REVOKE INSERT ON TABLES FROM "app"`, `ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" GRANT INSERT ON TABLES TO "app"`},
+				{`ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" GRANT UPDATE ON TABLES TO "app"`, `ALTER DEFAULT PRIVILEGES FOR ROLE "admin" IN SCHEMA "public" REVOKE UPDATE ON TABLES FROM "app"`},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropObject{O: &DefaultPrivilege{Role: "admin", On: DefaultPrivilegesOnFunctions, Grantee: "PUBLIC", Privileges: []string{"EXECUTE"}}}},
+			want: [][2]string{
+				{`ALTER DEFAULT PRIVILEGES FOR ROLE "admin" REVOKE EXECUTE ON FUNCTIONS FROM PUBLIC`, `ALTER DEFAULT PRIVILEGES FOR ROLE "admin" GRANT EXECUTE ON FUNCTIONS TO PUBLIC`},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("t2").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int")).AddAttrs(&Owner{Name: "app"})},
+				&schema.ModifyTable{T: tbl, Changes: []schema.Change{&schema.ModifyAttr{From: &Owner{Name: "admin"}, To: &Owner{Name: "app"}}}},
+				&schema.ModifySchema{S: s, Changes: []schema.Change{&schema.ModifyAttr{From: &Owner{Name: "admin"}, To: &Owner{Name: "app"}}}},
+			},
+			want: [][2]string{
+				{`ALTER SCHEMA "public" OWNER TO "app"`, `ALTER SCHEMA "public" OWNER TO "admin"`},
+				{`CREATE TABLE "public"."t2" ("id" integer NOT NULL)`, `DROP TABLE "public"."t2"`},
+				{`ALTER TABLE "public"."t2" OWNER TO "app"`, `ALTER TABLE "public"."t2" OWNER TO CURRENT_USER`},
+				{`ALTER TABLE "public"."t" OWNER TO "app"`, `ALTER TABLE "public"."t" OWNER TO "admin"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, planCmds(plan))
+	}
+}
+
+func TestMarshalSpec_Roles(t *testing.T) {
+	var (
+		limit = 10
+		s     = schema.New("public").AddAttrs(&Owner{Name: "admin"})
+		r     = schema.NewRealm(s)
+	)
+	s.AddTables(schema.NewTable("t").AddColumns(schema.NewIntColumn("id", "int")).AddAttrs(&Owner{Name: "app"}))
+	r.AddObjects(
+		(&Role{Name: "app", Login: true, NoInherit: true, ConnLimit: &limit, MemberOf: []string{"readers"}}).SetComment("app user"),
+		&Role{Name: "readers"},
+		&DefaultPrivilege{Role: "admin", Schema: s, On: DefaultPrivilegesOnTables, Grantee: "readers", Privileges: []string{"SELECT"}},
+		&DefaultPrivilege{Role: "admin", On: DefaultPrivilegesOnSequences, Grantee: "app", Privileges: []string{"USAGE", "SELECT"}},
+	)
+	buf, err := MarshalHCL(r)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  owner  = "app"
+  column "id" {
+    null = false
+    type = int
+  }
+}
+role "app" {
+  login            = true
+  inherit          = false
+  connection_limit = 10
+  member_of        = ["readers"]
+  comment          = "app user"
+}
+role "readers" {
+}
+default_privileges {
+  role       = "admin"
+  schema     = schema.public
+  on         = "TABLES"
+  to         = "readers"
+  privileges = ["SELECT"]
+}
+default_privileges {
+  role       = "admin"
+  on         = "SEQUENCES"
+  to         = "app"
+  privileges = ["SELECT", "USAGE"]
+}
+schema "public" {
+  owner = "admin"
+}
+`, string(buf))
+
+	var got schema.Realm
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	changes, err := DefaultDiff.RealmDiff(r, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	var o Owner
+	require.True(t, sqlx.Has(got.Schemas[0].Attrs, &o))
+	require.Equal(t, "admin", o.Name)
+	require.True(t, sqlx.Has(got.Schemas[0].Tables[0].Attrs, &o))
+	require.Equal(t, "app", o.Name)
+
+	err = EvalHCLBytes([]byte(`
+default_privileges {
+  role = "admin"
+  on   = "TABLES"
+  to   = "app"
+}
+`), &got, nil)
+	require.EqualError(t, err, `missing attribute default_privileges.privileges for role "admin"`)
+}
@@ -0,0 +1,564 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+// SpecType returns the type of the sequence used in HCL and exclude patterns.
+func (*Sequence) SpecType() string { return "sequence" }
+
+// SpecName returns the name of the sequence used in HCL and exclude patterns.
+func (s *Sequence) SpecName() string { return s.Name }
+
+// SetComment sets or updates the comment of the sequence.
+func (s *Sequence) SetComment(c string) *Sequence {
+	schema.ReplaceOrAppend(&s.Attrs, &schema.Comment{Text: c})
+	return s
+}
+
+// seqOptions holds the normalized options of a sequence, with
+// the defaults of PostgreSQL applied to the unset options.
+type seqOptions struct {
+	typ                         string
+	start, inc, min, max, cache int64
+	cycle                       bool
+}
+
+// options returns the normalized options of the sequence.
+func (s *Sequence) options() *seqOptions {
+	o := &seqOptions{typ: TypeBigInt, inc: s.Increment, cache: s.Cache, cycle: s.Cycle}
+	if s.Type != nil {
+		if t, err := FormatType(s.Type); err == nil {
+			o.typ = t
+		}
+	}
+	if o.inc == 0 {
+		o.inc = defaultSeqIncrement
+	}
+	lo, hi := seqBounds(o.typ)
+	switch {
+	case s.Min != nil:
+		o.min = *s.Min
+	case o.inc > 0:
+		o.min = 1
+	default:
+		o.min = lo
+	}
+	switch {
+	case s.Max != nil:
+		o.max = *s.Max
+	case o.inc > 0:
+		o.max = hi
+	default:
+		o.max = -1
+	}
+	switch {
+	case s.Start != 0:
+		o.start = s.Start
+	case o.inc > 0:
+		o.start = o.min
+	default:
+		o.start = o.max
+	}
+	if o.cache == 0 {
+		o.cache = 1
+	}
+	return o
+}
+
+// defaults returns the options PostgreSQL uses for a new sequence
+// that was created with the type and the increment of o.
+func (o *seqOptions) defaults() *seqOptions {
+	d := (&Sequence{Type: &schema.IntegerType{T: o.typ}, Increment: o.inc}).options()
+	// Type and increment are not set on creation.
+	d.typ, d.inc = TypeBigInt, defaultSeqIncrement
+	return d
+}
+
+// seqBounds returns the range of values the sequence type can hold.
+func seqBounds(t string) (int64, int64) {
+	switch t {
+	case TypeSmallInt:
+		return math.MinInt16, math.MaxInt16
+	case TypeInteger:
+		return math.MinInt32, math.MaxInt32
+	default:
+		return math.MinInt64, math.MaxInt64
+	}
+}
+
+// seqOwner returns the qualified name of the column that owns the sequence, if any.
+func seqOwner(s *Sequence) string {
+	if s.Owner.T == nil || s.Owner.C == nil {
+		return ""
+	}
+	return s.Owner.T.Name + "." + s.Owner.C.Name
+}
+
+func seqComment(s *Sequence) string {
+	var c schema.Comment
+	sqlx.Has(s.Attrs, &c)
+	return c.Text
+}
+
+// inspectSequences queries and appends the standalone sequences of the realm. Sequences
+// that back IDENTITY or serial columns are managed by their columns, and sequences that
+// are members of extensions are managed by their extension, hence, both are skipped.
+func (i *inspect) inspectSequences(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(sequencesQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying sequences: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cycle                                 bool
+			start, inc, minV, maxV, cache         int64
+			ns, name, typ, table, column, comment sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &typ, &start, &inc, &minV, &maxV, &cache, &cycle, &table, &column, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning sequence: %w", err)
+		}
+		s, ok := r.Schema(ns.String)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for sequence %q was not found in realm", ns.String, name.String)
+		}
+		if serialSequence(s, name.String) {
+			continue
+		}
+		seq := &Sequence{
+			Name:      name.String,
+			Schema:    s,
+			Type:      &schema.IntegerType{T: typ.String},
+			Start:     start,
+			Increment: inc,
+			Cache:     cache,
+			Cycle:     cycle,
+		}
+		// Store the bounds only if they differ from the defaults.
+		if d := seq.options(); d.min != minV {
+			seq.Min = &minV
+		}
+		if d := seq.options(); d.max != maxV {
+			seq.Max = &maxV
+		}
+		if sqlx.ValidString(table) && sqlx.ValidString(column) {
+			t, ok := s.Table(table.String)
+			if !ok {
+				return fmt.Errorf("postgres: owner table %q of sequence %q was not found in schema %q", table.String, name.String, s.Name)
+			}
+			c, ok := t.Column(column.String)
+			if !ok {
+				return fmt.Errorf("postgres: owner column %q.%q of sequence %q was not found", table.String, column.String, name.String)
+			}
+			seq.Owner.T, seq.Owner.C = t, c
+		}
+		if sqlx.ValidString(comment) {
+			seq.SetComment(comment.String)
+		}
+		s.AddObjects(seq)
+	}
+	return rows.Err()
+}
+
+// serialSequence reports if the sequence backs a serial column of the schema.
+func serialSequence(s *schema.Schema, name string) bool {
+	for _, t := range s.Tables {
+		for _, c := range t.Columns {
+			if st, ok := c.Type.Type.(*SerialType); ok && st.sequence(t, c) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sequenceObjectDiff returns the changes for migrating the standalone sequences
+// of the schema. Sequences are altered in place, rather than being recreated, to
+// preserve their current values.
+func sequenceObjectDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		s1, ok := o1.(*Sequence)
+		if !ok {
+			continue
+		}
+		s2, ok := schemaSequence(to, s1.Name)
+		switch {
+		// A sequence owned by a dropped table or column is dropped along with it.
+		case !ok && !seqOwnerExists(s1, to):
+		case !ok:
+			changes = append(changes, &schema.DropObject{O: s1})
+		case sequenceChanged(s1, s2):
+			changes = append(changes, &schema.ModifyObject{From: s1, To: s2})
+		}
+	}
+	for _, o1 := range to.Objects {
+		s1, ok := o1.(*Sequence)
+		if !ok {
+			continue
+		}
+		if _, ok := schemaSequence(from, s1.Name); !ok {
+			changes = append(changes, &schema.AddObject{O: s1})
+		}
+	}
+	return changes
+}
+
+func schemaSequence(s *schema.Schema, name string) (*Sequence, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		s, ok := o.(*Sequence)
+		return ok && s.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Sequence), true
+}
+
+// seqOwnerExists reports if the owner of the sequence (if any) exists in the given schema.
+func seqOwnerExists(s *Sequence, to *schema.Schema) bool {
+	if s.Owner.T == nil || s.Owner.C == nil {
+		return true
+	}
+	t, ok := to.Table(s.Owner.T.Name)
+	if !ok {
+		return false
+	}
+	_, ok = t.Column(s.Owner.C.Name)
+	return ok
+}
+
+// sequenceChanged reports if the sequence options, owner or comment were changed.
+func sequenceChanged(from, to *Sequence) bool {
+	return *from.options() != *to.options() || seqOwner(from) != seqOwner(to) || seqComment(from) != seqComment(to)
+}
+
+// addSequence plans the creation of a sequence. Note, the OWNED BY clause is set by
+// sequenceOwners after all changes were planned, as the owner table might be created
+// after the sequence (e.g., if it is used by the column default).
+func (s *state) addSequence(src schema.Change, seq *Sequence) {
+	create, drop := s.createDropSequence(seq)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create sequence %q", seq.Name),
+	})
+	if c := seqComment(seq); c != "" {
+		s.append(s.sequenceComment(src, seq, c, ""))
+	}
+}
+
+func (s *state) dropSequence(src schema.Change, seq *Sequence) {
+	create, drop := s.createDropSequence(seq)
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     drop,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop sequence %q", seq.Name),
+	})
+}
+
+// modifySequence plans the changes between two states of a sequence using the ALTER
+// SEQUENCE command. The RESTART clause is never used, and therefore, the current value
+// of the sequence is preserved, even if its START value was changed.
+func (s *state) modifySequence(src schema.Change, from, to *Sequence) {
+	o1, o2 := from.options(), to.options()
+	if *o1 != *o2 || seqOwner(from) != seqOwner(to) && seqOwner(to) == "" {
+		b := s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name)
+		cmd, reverse := b.Clone(), b.Clone()
+		s.seqOptions(cmd, o1, o2)
+		s.seqOptions(reverse, o2, o1)
+		// Owners are set after all changes were planned, but
+		// removing an owner can be done in the same command.
+		if seqOwner(from) != "" && seqOwner(to) == "" {
+			cmd.P("OWNED BY NONE")
+			s.seqOwnedBy(reverse, from)
+		}
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     cmd.String(),
+			Reverse: reverse.String(),
+			Comment: fmt.Sprintf("modify sequence %q", to.Name),
+		})
+	}
+	if c1, c2 := seqComment(from), seqComment(to); c1 != c2 {
+		s.append(s.sequenceComment(src, to, c2, c1))
+	}
+}
+
+func (s *state) createDropSequence(seq *Sequence) (string, string) {
+	b := s.Build("CREATE SEQUENCE").SchemaResource(seq.Schema, seq.Name)
+	o := seq.options()
+	s.seqOptions(b, o.defaults(), o)
+	return b.String(), s.Build("DROP SEQUENCE").SchemaResource(seq.Schema, seq.Name).String()
+}
+
+// seqOptions writes the clauses for changing the sequence options from o1 to o2.
+func (s *state) seqOptions(b *sqlx.Builder, o1, o2 *seqOptions) {
+	if o1.typ != o2.typ {
+		b.P("AS", o2.typ)
+	}
+	if o1.inc != o2.inc {
+		b.P("INCREMENT BY").Int64(o2.inc)
+	}
+	if o1.min != o2.min {
+		b.P("MINVALUE").Int64(o2.min)
+	}
+	if o1.max != o2.max {
+		b.P("MAXVALUE").Int64(o2.max)
+	}
+	if o1.start != o2.start {
+		b.P("START WITH").Int64(o2.start)
+	}
+	if o1.cache != o2.cache {
+		b.P("CACHE").Int64(o2.cache)
+	}
+	if o1.cycle != o2.cycle {
+		if o2.cycle {
+			b.P("CYCLE")
+		} else {
+			b.P("NO CYCLE")
+		}
+	}
+}
+
+// seqOwnedBy writes the OWNED BY clause of the sequence.
+func (s *state) seqOwnedBy(b *sqlx.Builder, seq *Sequence) {
+	b.P("OWNED BY")
+	if seqOwner(seq) == "" {
+		b.P("NONE")
+		return
+	}
+	b.TableColumn(seq.Owner.T, seq.Owner.C)
+}
+
+// sequenceOwners sets the owners of the sequences that were created or modified by
+// the given changes. It is called after all changes were planned, to ensure the owner
+// tables and columns exist.
+func (s *state) sequenceOwners(changes []schema.Change) {
+	for _, c := range changes {
+		var from, to *Sequence
+		switch c := c.(type) {
+		case *schema.AddObject:
+			to, _ = c.O.(*Sequence)
+			from = &Sequence{}
+		case *schema.ModifyObject:
+			from, _ = c.From.(*Sequence)
+			to, _ = c.To.(*Sequence)
+		}
+		if from == nil || to == nil || seqOwner(to) == "" || seqOwner(from) == seqOwner(to) {
+			continue
+		}
+		b := s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name)
+		cmd, reverse := b.Clone(), b.Clone()
+		s.seqOwnedBy(cmd, to)
+		s.seqOwnedBy(reverse, from)
+		s.append(&migrate.Change{
+			Source:  c,
+			Cmd:     cmd.String(),
+			Reverse: reverse.String(),
+			Comment: fmt.Sprintf("set owner of sequence %q", to.Name),
+		})
+	}
+}
+
+func (s *state) sequenceComment(src schema.Change, seq *Sequence, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON SEQUENCE").SchemaResource(seq.Schema, seq.Name).P("IS")
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Reverse: b.Clone().P(quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to sequence: %q", seq.Name),
+	}
+}
+
+// convertSequences converts the sequence specs and adds them to their schemas.
+func convertSequences(_ []*sqlspec.Table, seqs []*sqlspec.Sequence, r *schema.Realm) error {
+	for _, spec := range seqs {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from sequence %q reference: %w", spec.Name, err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on sequence %q was not found in realm", ns, spec.Name)
+		}
+		seq := &Sequence{Name: spec.Name, Schema: s}
+		if a, ok := spec.Attr("type"); ok {
+			t, err := a.Type()
+			if err != nil {
+				return fmt.Errorf("parsing sequence %q attribute \"type\": %w", spec.Name, err)
+			}
+			if seq.Type, err = TypeRegistry.Type(t, nil); err != nil {
+				return fmt.Errorf("converting sequence %q type: %w", spec.Name, err)
+			}
+			if _, ok := seq.Type.(*schema.IntegerType); !ok {
+				return fmt.Errorf("unexpected type %q for sequence %q, expect smallint, integer or bigint", t.T, spec.Name)
+			}
+		}
+		for _, a := range []struct {
+			name string
+			v    *int64
+		}{
+			{name: "start", v: &seq.Start},
+			{name: "increment", v: &seq.Increment},
+			{name: "cache", v: &seq.Cache},
+		} {
+			attr, ok := spec.Attr(a.name)
+			if !ok {
+				continue
+			}
+			if *a.v, err = attr.Int64(); err != nil {
+				return fmt.Errorf("parsing sequence %q attribute %q: %w", spec.Name, a.name, err)
+			}
+		}
+		for _, a := range []struct {
+			name string
+			v    **int64
+		}{
+			{name: "min_value", v: &seq.Min},
+			{name: "max_value", v: &seq.Max},
+		} {
+			attr, ok := spec.Attr(a.name)
+			if !ok {
+				continue
+			}
+			v, err := attr.Int64()
+			if err != nil {
+				return fmt.Errorf("parsing sequence %q attribute %q: %w", spec.Name, a.name, err)
+			}
+			*a.v = &v
+		}
+		if a, ok := spec.Attr("cycle"); ok {
+			if seq.Cycle, err = a.Bool(); err != nil {
+				return fmt.Errorf("parsing sequence %q attribute \"cycle\": %w", spec.Name, err)
+			}
+		}
+		if a, ok := spec.Attr("owner"); ok {
+			v, err := a.Ref()
+			if err != nil {
+				return fmt.Errorf("parsing sequence %q attribute \"owner\": %w", spec.Name, err)
+			}
+			ref := &schemahcl.Ref{V: v}
+			_, name, err := specutil.TableName(ref)
+			if err != nil {
+				return fmt.Errorf("sequence %q: parsing owner reference: %w", spec.Name, err)
+			}
+			// The owner table must reside in the same schema as the sequence.
+			t, ok := s.Table(name)
+			if !ok {
+				return fmt.Errorf("sequence %q: owner table %q was not found in schema %q", spec.Name, name, s.Name)
+			}
+			c, err := specutil.ColumnByRef(t, ref)
+			if err != nil {
+				return fmt.Errorf("sequence %q: %w", spec.Name, err)
+			}
+			seq.Owner.T, seq.Owner.C = t, c
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing sequence %q attribute \"comment\": %w", spec.Name, err)
+			}
+			seq.SetComment(c)
+		}
+		s.AddObjects(seq)
+	}
+	return nil
+}
+
+// sequenceSpec converts a sequence into its spec. Options
+// that are set to their default values are omitted.
+func sequenceSpec(seq *Sequence) (*sqlspec.Sequence, error) {
+	spec := &sqlspec.Sequence{
+		Name:   seq.Name,
+		Schema: specutil.SchemaRef(seq.Schema.Name),
+	}
+	o := seq.options()
+	d := o.defaults()
+	if o.typ != d.typ {
+		t, err := TypeRegistry.Convert(&schema.IntegerType{T: o.typ})
+		if err != nil {
+			return nil, err
+		}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, &schemahcl.Attr{K: "type", V: schemahcl.TypeValue(t)})
+	}
+	for _, a := range []struct {
+		name   string
+		v1, v2 int64
+	}{
+		{name: "start", v1: d.start, v2: o.start},
+		{name: "increment", v1: d.inc, v2: o.inc},
+		{name: "min_value", v1: d.min, v2: o.min},
+		{name: "max_value", v1: d.max, v2: o.max},
+		{name: "cache", v1: d.cache, v2: o.cache},
+	} {
+		if a.v1 != a.v2 {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.Int64Attr(a.name, a.v2))
+		}
+	}
+	if o.cycle {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("cycle", true))
+	}
+	if seqOwner(seq) != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefAttr("owner", specutil.ExternalColumnRef(seq.Owner.C.Name, seq.Owner.T.Name)))
+	}
+	if c := seqComment(seq); c != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+	}
+	return spec, nil
+}
+
+// Query to list the standalone sequences of the given schemas. Sequences that back
+// IDENTITY columns (deptype 'i') or that are members of extensions are excluded.
+const sequencesQuery = `
+SELECT
+  n.nspname AS schema_name,
+  c.relname AS sequence_name,
+  format_type(s.seqtypid, NULL) AS sequence_type,
+  s.seqstart,
+  s.seqincrement,
+  s.seqmin,
+  s.seqmax,
+  s.seqcache,
+  s.seqcycle,
+  t.relname AS owner_table,
+  a.attname AS owner_column,
+  obj_description(c.oid, 'pg_class') AS comment
+FROM
+  pg_catalog.pg_sequence AS s
+  JOIN pg_catalog.pg_class AS c ON c.oid = s.seqrelid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  LEFT JOIN pg_catalog.pg_depend AS d ON d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.refclassid = 'pg_catalog.pg_class'::regclass AND d.deptype IN ('a', 'i')
+  LEFT JOIN pg_catalog.pg_class AS t ON t.oid = d.refobjid
+  LEFT JOIN pg_catalog.pg_attribute AS a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+WHERE
+  n.nspname IN (%s)
+  AND (d.deptype IS NULL OR d.deptype = 'a')
+  AND NOT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_depend AS e
+    WHERE e.classid = 'pg_catalog.pg_class'::regclass AND e.objid = c.oid AND e.deptype = 'e'
+  )
+ORDER BY
+  n.nspname, c.relname
+`
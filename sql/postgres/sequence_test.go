@@ -0,0 +1,222 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSequences(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | sequence_name | sequence_type | seqstart | seqincrement | seqmin | seqmax              | seqcache | seqcycle | owner_table | owner_column | comment
+-------------+---------------+---------------+----------+--------------+--------+---------------------+----------+----------+-------------+--------------+---------
+ public      | s1            | bigint        | 1        | 1            | 1      | 9223372036854775807 | 1        | f        | nil         | nil          | nil
+ public      | s2            | integer       | 100      | 10           | 100    | 1000                | 20       | t        | nil         | nil          | counter
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(foreignTablesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "table_name", "server_name", "options", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(publicationsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"pubname", "puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate", "tables"}))
+	mk.ExpectQuery(sqltest.Escape(eventTriggersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"evtname", "evtevent", "nspname", "proname", "evtenabled", "tags", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(foreignServersQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"srvname", "fdwname", "srvtype", "srvversion", "options", "mappings", "comment"}))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectObjects,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	s := r.Schemas[0]
+	minV, maxV := int64(100), int64(1000)
+	require.Equal(t, []schema.Object{
+		&Sequence{Name: "s1", Schema: s, Type: &schema.IntegerType{T: "bigint"}, Start: 1, Increment: 1, Cache: 1},
+		(&Sequence{Name: "s2", Schema: s, Type: &schema.IntegerType{T: "integer"}, Start: 100, Increment: 10, Cache: 20, Cycle: true, Min: &minV, Max: &maxV}).SetComment("counter"),
+	}, s.Objects)
+}
+
+func TestSerialSequence(t *testing.T) {
+	s := schema.New("public").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewColumn("id").SetType(&SerialType{T: TypeSerial})),
+		schema.NewTable("posts").AddColumns(schema.NewColumn("id").SetType(&SerialType{T: TypeSerial, SequenceName: "posts_seq"})),
+	)
+	require.True(t, serialSequence(s, "users_id_seq"))
+	require.True(t, serialSequence(s, "posts_seq"))
+	require.False(t, serialSequence(s, "posts_id_seq"))
+	require.False(t, serialSequence(s, "other"))
+}
+
+func TestDiff_Sequences(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	var (
+		from = schema.New("public").AddTables(schema.NewTable("t").AddColumns(schema.NewIntColumn("c", "bigint")))
+		to   = schema.New("public").AddTables(schema.NewTable("t").AddColumns(schema.NewIntColumn("c", "bigint")))
+	)
+	from.AddObjects(
+		&Sequence{Name: "same", Type: &schema.IntegerType{T: "bigint"}, Start: 1, Increment: 1, Cache: 1},
+		&Sequence{Name: "changed", Start: 10},
+		&Sequence{Name: "dropped"},
+	)
+	to.AddObjects(
+		// Explicit defaults are equal to unset options.
+		&Sequence{Name: "same"},
+		&Sequence{Name: "changed", Start: 10, Increment: 5},
+		&Sequence{Name: "added"},
+	)
+	changes, err := drv.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, &schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]}, changes[0])
+	require.Equal(t, &schema.DropObject{O: from.Objects[2]}, changes[1])
+	require.Equal(t, &schema.AddObject{O: to.Objects[2]}, changes[2])
+
+	// Sequences owned by dropped tables are dropped along with them.
+	seq := &Sequence{Name: "owned"}
+	seq.Owner.T, seq.Owner.C = from.Tables[0], from.Tables[0].Columns[0]
+	from = schema.New("public").AddTables(from.Tables[0]).AddObjects(seq)
+	changes, err = drv.SchemaDiff(from, schema.New("public"))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.IsType(t, &schema.DropTable{}, changes[0])
+}
+
+func TestPlanChanges_Sequences(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	s := schema.New("public")
+	tt := schema.NewTable("t").AddColumns(schema.NewIntColumn("c", "bigint"))
+	s.AddTables(tt)
+	added := &Sequence{Name: "s1", Schema: s, Type: &schema.IntegerType{T: "integer"}, Increment: -1, Cache: 10}
+	added.Owner.T, added.Owner.C = tt, tt.Columns[0]
+	added.SetComment("counter")
+	plan, err := drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.AddObject{O: added},
+		&schema.AddTable{T: tt},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE SEQUENCE "public"."s1" AS integer INCREMENT BY -1 CACHE 10`, `DROP SEQUENCE "public"."s1"`},
+		{`COMMENT ON SEQUENCE "public"."s1" IS 'counter'`, `COMMENT ON SEQUENCE "public"."s1" IS ''`},
+		{`CREATE TABLE "public"."t" ("c" bigint NOT NULL)`, `DROP TABLE "public"."t"`},
+		{`ALTER SEQUENCE "public"."s1" OWNED BY "public"."t"."c"`, `ALTER SEQUENCE "public"."s1" OWNED BY NONE`},
+	}, planCmds(plan))
+
+	// Changing the options does not restart the sequence.
+	from := &Sequence{Name: "s1", Schema: s, Start: 10}
+	from.Owner.T, from.Owner.C = tt, tt.Columns[0]
+	to := &Sequence{Name: "s1", Schema: s, Type: &schema.IntegerType{T: "integer"}, Start: 100, Cycle: true}
+	plan, err = drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.ModifyObject{From: from, To: to},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{
+			`ALTER SEQUENCE "public"."s1" AS integer MAXVALUE 2147483647 START WITH 100 CYCLE OWNED BY NONE`,
+			`ALTER SEQUENCE "public"."s1" AS bigint MAXVALUE 9223372036854775807 START WITH 10 NO CYCLE OWNED BY "public"."t"."c"`,
+		},
+	}, planCmds(plan))
+
+	plan, err = drv.PlanChanges(context.Background(), "", []schema.Change{
+		&schema.DropObject{O: &Sequence{Name: "s2", Schema: s, Min: new(int64)}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`DROP SEQUENCE "public"."s2"`, `CREATE SEQUENCE "public"."s2" MINVALUE 0 START WITH 0`},
+	}, planCmds(plan))
+}
+
+func TestMarshalSpec_Sequences(t *testing.T) {
+	s := schema.New("public")
+	tt := schema.NewTable("t").AddColumns(schema.NewIntColumn("c", "bigint"))
+	s.AddTables(tt)
+	maxV := int64(1000)
+	seq := &Sequence{Name: "s1", Schema: s, Type: &schema.IntegerType{T: "integer"}, Start: 100, Increment: 10, Max: &maxV, Cache: 1, Cycle: true}
+	seq.Owner.T, seq.Owner.C = tt, tt.Columns[0]
+	seq.SetComment("counter")
+	s.AddObjects(seq, &Sequence{Name: "s2", Schema: s, Type: &schema.IntegerType{T: "bigint"}, Start: 1, Increment: 1, Cache: 1})
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  column "c" {
+    null = false
+    type = bigint
+  }
+}
+sequence "s1" {
+  schema    = schema.public
+  type      = integer
+  start     = 100
+  increment = 10
+  max_value = 1000
+  cycle     = true
+  owner     = table.t.column.c
+  comment   = "counter"
+}
+sequence "s2" {
+  schema = schema.public
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Objects, 2)
+	s1 := got.Objects[0].(*Sequence)
+	require.Equal(t, "t.c", seqOwner(s1))
+	require.Equal(t, "counter", seqComment(s1))
+	require.Equal(t, *seq.options(), *s1.options())
+	require.Equal(t, *(&Sequence{}).options(), *got.Objects[1].(*Sequence).options())
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+sequence "s" {
+  schema = schema.public
+  type   = text
+}
+`), &got, nil)
+	require.EqualError(t, err, `unexpected type "text" for sequence "s", expect smallint, integer or bigint`)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"errors"
+	"reflect"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/sqlerr"
+)
+
+// errCategories maps the SQLSTATE codes to their categories. The codes are
+// shared by PostgreSQL, CockroachDB and YugabyteDB.
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+var errCategories = map[string]sqlerr.Category{
+	"3D000": sqlerr.NotExist,         // invalid_catalog_name
+	"3F000": sqlerr.NotExist,         // invalid_schema_name
+	"42P01": sqlerr.NotExist,         // undefined_table
+	"42703": sqlerr.NotExist,         // undefined_column
+	"42704": sqlerr.NotExist,         // undefined_object
+	"42883": sqlerr.NotExist,         // undefined_function
+	"42P04": sqlerr.Duplicate,        // duplicate_database
+	"42P06": sqlerr.Duplicate,        // duplicate_schema
+	"42P07": sqlerr.Duplicate,        // duplicate_table
+	"42701": sqlerr.Duplicate,        // duplicate_column
+	"42710": sqlerr.Duplicate,        // duplicate_object
+	"42723": sqlerr.Duplicate,        // duplicate_function
+	"23505": sqlerr.Duplicate,        // unique_violation
+	"55P03": sqlerr.Locked,           // lock_not_available
+	"55006": sqlerr.Locked,           // object_in_use
+	"42501": sqlerr.PermissionDenied, // insufficient_privilege
+	"28000": sqlerr.PermissionDenied, // invalid_authorization_specification
+	"28P01": sqlerr.PermissionDenied, // invalid_password
+	"42601": sqlerr.SyntaxError,      // syntax_error
+	"40001": sqlerr.Retryable,        // serialization_failure
+	"40P01": sqlerr.Retryable,        // deadlock_detected
+	"53300": sqlerr.Retryable,        // too_many_connections
+	"57P01": sqlerr.Retryable,        // admin_shutdown
+}
+
+// classifyError implements sqlerr.Classifier for the errors returned by the PostgreSQL
+// drivers. The SQLSTATE code is read using the SQLState method implemented by pgx and
+// lib/pq, or from the Code field of the driver error.
+func classifyError(err error) (sqlerr.Category, string, bool) {
+	var code string
+	if e := (interface{ SQLState() string })(nil); errors.As(err, &e) {
+		code = e.SQLState()
+	} else if f, ok := sqlx.ErrorField(err, "Code"); ok && f.Kind() == reflect.String {
+		code = f.String()
+	}
+	if len(code) != 5 {
+		return sqlerr.Unknown, "", false
+	}
+	c, ok := errCategories[code]
+	// Connection exceptions are transient.
+	if !ok && code[:2] == "08" {
+		c = sqlerr.Retryable
+	}
+	return c, code, true
+}
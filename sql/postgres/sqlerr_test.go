@@ -0,0 +1,58 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/sqlerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// pgError mimics the error type of github.com/jackc/pgx/v5/pgconn.
+	pgError struct {
+		Code    string
+		Message string
+	}
+	// pqError mimics the error type of github.com/lib/pq.
+	pqError struct {
+		Code    pqErrorCode
+		Message string
+	}
+	pqErrorCode string
+)
+
+func (e *pgError) Error() string    { return e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+func (e *pqError) Error() string    { return "pq: " + e.Message }
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want sqlerr.Category
+		code string
+	}{
+		{err: &pgError{Code: "42P01", Message: `relation "t" does not exist`}, want: sqlerr.NotExist, code: "42P01"},
+		{err: &pgError{Code: "42P07", Message: `relation "t" already exists`}, want: sqlerr.Duplicate, code: "42P07"},
+		{err: &pgError{Code: "55P03", Message: "could not obtain lock"}, want: sqlerr.Locked, code: "55P03"},
+		{err: &pqError{Code: "42501", Message: "permission denied for schema public"}, want: sqlerr.PermissionDenied, code: "42501"},
+		{err: &pqError{Code: "42601", Message: `syntax error at or near "TABL"`}, want: sqlerr.SyntaxError, code: "42601"},
+		{err: fmt.Errorf("exec: %w", &pgError{Code: "40001", Message: "restart transaction"}), want: sqlerr.Retryable, code: "40001"},
+		{err: &pgError{Code: "08006", Message: "connection failure"}, want: sqlerr.Retryable, code: "08006"},
+		{err: &pgError{Code: "22P02", Message: "invalid input syntax"}, want: sqlerr.Unknown, code: "22P02"},
+	} {
+		c, code, ok := classifyError(tt.err)
+		require.True(t, ok)
+		require.Equal(t, tt.want, c)
+		require.Equal(t, tt.code, code)
+		require.True(t, sqlerr.Is(tt.err, tt.want))
+	}
+	_, _, ok := classifyError(errors.New("error"))
+	require.False(t, ok)
+}
@@ -27,11 +27,19 @@ type (
 		Enums         []*enum             `spec:"enum"`
 		Domains       []*domain           `spec:"domain"`
 		Composites    []*composite        `spec:"composite"`
+		Ranges        []*rangeType        `spec:"range"`
 		Sequences     []*sqlspec.Sequence `spec:"sequence"`
 		Aggregates    []*aggregate        `spec:"aggregate"`
+		Funcs         []*triggerFunc      `spec:"function"`
 		Policies      []*policy           `spec:"policy"`
 		EventTriggers []*eventTrigger     `spec:"event_trigger"`
 		Extensions    []*extension        `spec:"extension"`
+		Materialized  []*materialized     `spec:"materialized"`
+		Publications  []*publication      `spec:"publication"`
+		Servers       []*foreignServer    `spec:"foreign_server"`
+		ForeignTables []*foreignTable     `spec:"foreign_table"`
+		Roles         []*role             `spec:"role"`
+		DefaultPrivs  []*defaultPrivilege `spec:"default_privileges"`
 		Schemas       []*sqlspec.Schema   `spec:"schema"`
 	}
 
@@ -86,11 +94,30 @@ type (
 	// Note, event trigger names are unique within a realm (database).
 	eventTrigger struct {
 		Name string `spec:",name"`
-		// Schema, version and comment are conditionally
-		// added to the extension definition.
+		// The event, function and rest of the attributes
+		// are added to the event trigger definition.
+		schemahcl.DefaultExtension
+	}
+
+	// role holds a specification for a postgres role.
+	// Note, role names are unique within a cluster.
+	role struct {
+		Name string `spec:",name"`
+		// The role options and memberships are
+		// added to the role definition.
 		schemahcl.DefaultExtension
 	}
 
+	// defaultPrivilege holds a specification for the default
+	// privileges that are granted by a role to another role.
+	defaultPrivilege struct {
+		Role       string         `spec:"role"`
+		Schema     *schemahcl.Ref `spec:"schema"`
+		On         string         `spec:"on"`
+		To         string         `spec:"to"`
+		Privileges []string       `spec:"privileges"`
+	}
+
 	// aggregate holds the specification for an aggregation function.
 	aggregate struct {
 		Name      string         `spec:",name"`
@@ -126,12 +153,20 @@ func (d *doc) merge(d1 *doc) {
 	d.Tables = append(d.Tables, d1.Tables...)
 	d.Domains = append(d.Domains, d1.Domains...)
 	d.Composites = append(d.Composites, d1.Composites...)
+	d.Ranges = append(d.Ranges, d1.Ranges...)
 	d.Schemas = append(d.Schemas, d1.Schemas...)
 	d.Aggregates = append(d.Aggregates, d1.Aggregates...)
+	d.Funcs = append(d.Funcs, d1.Funcs...)
 	d.Sequences = append(d.Sequences, d1.Sequences...)
 	d.Extensions = append(d.Extensions, d1.Extensions...)
 	d.Policies = append(d.Policies, d1.Policies...)
 	d.EventTriggers = append(d.EventTriggers, d1.EventTriggers...)
+	d.Materialized = append(d.Materialized, d1.Materialized...)
+	d.Publications = append(d.Publications, d1.Publications...)
+	d.Servers = append(d.Servers, d1.Servers...)
+	d.ForeignTables = append(d.ForeignTables, d1.ForeignTables...)
+	d.Roles = append(d.Roles, d1.Roles...)
+	d.DefaultPrivs = append(d.DefaultPrivs, d1.DefaultPrivs...)
 }
 
 func (d *doc) ScanDoc() *specutil.ScanDoc {
@@ -194,9 +229,15 @@ func init() {
 	schemahcl.Register("domain", &domain{})
 	schemahcl.Register("policy", &policy{})
 	schemahcl.Register("composite", &composite{})
+	schemahcl.Register("range", &rangeType{})
 	schemahcl.Register("aggregate", &aggregate{})
+	schemahcl.Register("function", &triggerFunc{})
 	schemahcl.Register("extension", &extension{})
 	schemahcl.Register("event_trigger", &eventTrigger{})
+	schemahcl.Register("materialized", &materialized{})
+	schemahcl.Register("publication", &publication{})
+	schemahcl.Register("role", &role{})
+	schemahcl.Register("default_privileges", &defaultPrivilege{})
 }
 
 // Codec for schemahcl.
@@ -223,21 +264,48 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		if err := convertTypes(&d, v); err != nil {
 			return err
 		}
+		if err := convertRanges(&d, v); err != nil {
+			return err
+		}
 		if err := convertAggregate(&d, v); err != nil {
 			return err
 		}
+		if err := convertTriggerFuncs(&d, v); err != nil {
+			return err
+		}
 		if err := convertSequences(d.Tables, d.Sequences, v); err != nil {
 			return err
 		}
 		if err := convertPolicies(d.Tables, d.Policies, v); err != nil {
 			return err
 		}
+		if err := convertMatViews(&d, v); err != nil {
+			return err
+		}
 		if err := convertExtensions(d.Extensions, v); err != nil {
 			return err
 		}
 		if err := convertEventTriggers(d.EventTriggers, v); err != nil {
 			return err
 		}
+		if err := convertPublications(d.Publications, v); err != nil {
+			return err
+		}
+		if err := convertForeignServers(d.Servers, v); err != nil {
+			return err
+		}
+		if err := convertForeignTables(d.ForeignTables, v); err != nil {
+			return err
+		}
+		if err := convertRoles(d.Roles, v); err != nil {
+			return err
+		}
+		if err := convertDefaultPrivileges(d.DefaultPrivs, v); err != nil {
+			return err
+		}
+		if err := convertSchemaOwners(d.Schemas, v); err != nil {
+			return err
+		}
 		if err := normalizeRealm(v); err != nil {
 			return err
 		}
@@ -256,16 +324,32 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		if err := convertTypes(&d, r); err != nil {
 			return err
 		}
+		if err := convertRanges(&d, r); err != nil {
+			return err
+		}
 		if err := convertAggregate(&d, r); err != nil {
 			return err
 		}
+		if err := convertTriggerFuncs(&d, r); err != nil {
+			return err
+		}
 		if err := convertSequences(d.Tables, d.Sequences, r); err != nil {
 			return err
 		}
 		if err := convertPolicies(d.Tables, d.Policies, r); err != nil {
 			return err
 		}
-		// Extensions are skipped in schema scope.
+		if err := convertMatViews(&d, r); err != nil {
+			return err
+		}
+		if err := convertForeignTables(d.ForeignTables, r); err != nil {
+			return err
+		}
+		if err := convertSchemaOwners(d.Schemas, r); err != nil {
+			return err
+		}
+		// Extensions, event triggers, publications, foreign servers,
+		// roles and default privileges are skipped in schema scope.
 		if err := normalizeRealm(r); err != nil {
 			return err
 		}
@@ -278,6 +362,12 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 	return nil
 }
 
+// JSONSchema returns the JSON Schema of the Atlas DDL documents of the driver.
+// See schemahcl.State.JSONSchema for more info.
+func (c *Codec) JSONSchema() ([]byte, error) {
+	return c.State.JSONSchema(&doc{})
+}
+
 // MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
 func (c *Codec) MarshalSpec(v any) ([]byte, error) {
 	var d doc
@@ -302,6 +392,9 @@ func (c *Codec) MarshalSpec(v any) ([]byte, error) {
 		if err := specutil.QualifyObjects(d.Aggregates); err != nil {
 			return nil, err
 		}
+		if err := specutil.QualifyObjects(d.Funcs); err != nil {
+			return nil, err
+		}
 		if err := specutil.QualifyObjects(d.Enums); err != nil {
 			return nil, err
 		}
@@ -311,12 +404,26 @@ func (c *Codec) MarshalSpec(v any) ([]byte, error) {
 		if err := specutil.QualifyObjects(d.Composites); err != nil {
 			return nil, err
 		}
+		if err := specutil.QualifyObjects(d.Ranges); err != nil {
+			return nil, err
+		}
 		if err := specutil.QualifyObjects(d.Sequences); err != nil {
 			return nil, err
 		}
+		if err := specutil.QualifyObjects(d.Materialized); err != nil {
+			return nil, err
+		}
+		if err := specutil.QualifyObjects(d.ForeignTables); err != nil {
+			return nil, err
+		}
 		if err := specutil.QualifyReferences(d.Tables, rv); err != nil {
 			return nil, err
 		}
+		d.Publications = append(d.Publications, publicationSpecs(rv)...)
+		d.EventTriggers = append(d.EventTriggers, eventTriggerSpecs(rv)...)
+		d.Servers = append(d.Servers, foreignServerSpecs(rv)...)
+		d.Roles = append(d.Roles, roleSpecs(rv)...)
+		d.DefaultPrivs = append(d.DefaultPrivs, defaultPrivilegeSpecs(rv)...)
 	default:
 		return nil, fmt.Errorf("specutil: failed marshaling spec. %T is not supported", v)
 	}
@@ -327,12 +434,40 @@ var (
 	codec = &Codec{
 		State: schemahcl.New(append(specOptions,
 			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
-			schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST"),
+			schemahcl.WithTypes("materialized.column.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("sequence.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("range.subtype", TypeRegistry.Specs()),
+			schemahcl.WithTypes("foreign_table.column.type", TypeRegistry.Specs()),
+			schemahcl.WithTypeHints("postgres", map[string]string{
+				"text":      TypeVarChar,
+				"longtext":  TypeText,
+				"datetime":  TypeTimestamp,
+				"tinyint":   TypeSmallInt,
+				"mediumint": TypeInteger,
+				"blob":      TypeBytea,
+				"double":    "double_precision",
+			}),
+			schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST", IndexTypeIVFFlat, IndexTypeHNSW),
+			schemahcl.WithScopedEnums("materialized.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST", IndexTypeIVFFlat, IndexTypeHNSW),
 			schemahcl.WithScopedEnums("table.partition.type", PartitionTypeRange, PartitionTypeList, PartitionTypeHash),
+			schemahcl.WithScopedEnums("table.partition.policy.interval", PartitionIntervalHour, PartitionIntervalDay, PartitionIntervalWeek, PartitionIntervalMonth, PartitionIntervalYear),
 			schemahcl.WithScopedEnums("table.column.identity.generated", GeneratedTypeAlways, GeneratedTypeByDefault),
 			schemahcl.WithScopedEnums("table.column.as.type", "STORED"),
+			schemahcl.WithScopedEnums("policy.as", PolicyAsPermissive, PolicyAsRestrictive),
+			schemahcl.WithScopedEnums("policy.for", PolicyForAll, PolicyForSelect, PolicyForInsert, PolicyForUpdate, PolicyForDelete),
+			schemahcl.WithScopedEnums("policy.to", policyRoles...),
+			schemahcl.WithScopedEnums("publication.publish", publishOps...),
+			schemahcl.WithScopedEnums("event_trigger.on", EventDDLCommandStart, EventDDLCommandEnd, EventSQLDrop, EventTableRewrite, EventLogin),
+			schemahcl.WithScopedEnums("event_trigger.enabled", EventTriggerOrigin, EventTriggerReplica, EventTriggerAlways, EventTriggerDisabled),
+			schemahcl.WithScopedEnums("function.return", typeTrigger, typeEventTrigger),
 			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
 			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.foreign_key.deferrable", DeferrableInitiallyImmediate, DeferrableInitiallyDeferred),
+			schemahcl.WithScopedEnums("table.unique.deferrable", DeferrableInitiallyImmediate, DeferrableInitiallyDeferred),
+			schemahcl.WithScopedEnums("table.exclude.deferrable", DeferrableInitiallyImmediate, DeferrableInitiallyDeferred),
+			schemahcl.WithScopedEnums("table.index.buffering", bufferingOn, bufferingOff, bufferingAuto),
+			schemahcl.WithScopedEnums("table.exclude.buffering", bufferingOn, bufferingOff, bufferingAuto),
+			schemahcl.WithScopedEnums("table.exclude.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST"),
 			schemahcl.WithScopedEnums("table.index.on.ops", func() (ops []string) {
 				for _, op := range postgresop.Classes {
 					ops = append(ops, op.Name)
@@ -354,7 +489,7 @@ var (
 // ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
 // at this point. Instead, the linking is done by the convertSchema function.
 func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
-	t, err := specutil.Table(spec, parent, convertColumn, convertPK, convertIndex, specutil.Check)
+	t, err := specutil.Table(spec, parent, convertColumn, convertPK, convertIndex, convertCheck)
 	if err != nil {
 		return nil, err
 	}
@@ -444,6 +579,9 @@ func convertPartition(spec schemahcl.Resource, table *schema.Table) error {
 		}
 	}
 	table.AddAttrs(key)
+	if r, ok := r.Resource("policy"); ok {
+		return convertPartitionPolicy(r, table)
+	}
 	return nil
 }
 
@@ -509,17 +647,36 @@ func convertIdentity(r *schemahcl.Resource) (*Identity, error) {
 		Generation string `spec:"generated"`
 		Start      int64  `spec:"start"`
 		Increment  int64  `spec:"increment"`
+		Cache      int64  `spec:"cache"`
+		Cycle      bool   `spec:"cycle"`
 	}
 	if err := r.As(&spec); err != nil {
 		return nil, err
 	}
-	id := &Identity{Generation: specutil.FromVar(spec.Generation), Sequence: &Sequence{}}
+	id := &Identity{Generation: specutil.FromVar(spec.Generation), Sequence: &Sequence{Cache: spec.Cache, Cycle: spec.Cycle}}
 	if spec.Start != 0 {
 		id.Sequence.Start = spec.Start
 	}
 	if spec.Increment != 0 {
 		id.Sequence.Increment = spec.Increment
 	}
+	for _, a := range []struct {
+		name string
+		v    **int64
+	}{
+		{name: "min_value", v: &id.Sequence.Min},
+		{name: "max_value", v: &id.Sequence.Max},
+	} {
+		attr, ok := r.Attr(a.name)
+		if !ok {
+			continue
+		}
+		v, err := attr.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("parsing identity attribute %q: %w", a.name, err)
+		}
+		*a.v = &v
+	}
 	return id, nil
 }
 
@@ -581,6 +738,12 @@ func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
 		}
 		idx.Attrs = append(idx.Attrs, &IndexNullsDistinct{V: v})
 	}
+	if err := convertTabletSplit(spec, &idx.Attrs); err != nil {
+		return nil, err
+	}
+	if err := convertDeferrable(spec, &idx.Attrs); err != nil {
+		return nil, err
+	}
 	if err := convertIndexPK(spec, t, idx); err != nil {
 		return nil, err
 	}
@@ -589,12 +752,11 @@ func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
 
 // convertIndexPK converts the index parameters shared between primary and secondary indexes.
 func convertIndexPK(spec specutil.Attrer, t *schema.Table, idx *schema.Index) error {
-	if attr, ok := spec.Attr("page_per_range"); ok {
-		p, err := attr.Int64()
-		if err != nil {
-			return err
-		}
-		idx.Attrs = append(idx.Attrs, &IndexStorageParams{PagesPerRange: p})
+	if err := convertStorageParams(spec, idx); err != nil {
+		return err
+	}
+	if err := convertHashSharded(spec, idx); err != nil {
+		return err
 	}
 	if attr, ok := spec.Attr("include"); ok {
 		refs, err := attr.Refs()
@@ -702,6 +864,9 @@ func schemaSpec(s *schema.Schema) (*doc, error) {
 	if err != nil {
 		return nil, err
 	}
+	if a, ok := ownerSpec(s.Attrs); ok {
+		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, a)
+	}
 	d := &doc{
 		Tables:     spec.Tables,
 		Schemas:    []*sqlspec.Schema{spec.Schema},
@@ -722,8 +887,8 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 		tableColumnSpec,
 		pkSpec,
 		indexSpec,
-		specutil.FromForeignKey,
-		specutil.FromCheck,
+		fkSpec,
+		checkSpec,
 	)
 	if err != nil {
 		return nil, err
@@ -750,7 +915,11 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 	}
 	spec.Indexes = idxs
 	if p := (Partition{}); sqlx.Has(t.Attrs, &p) {
-		spec.Extra.Children = append(spec.Extra.Children, fromPartition(p))
+		key := fromPartition(p)
+		if r, ok := partitionPolicySpec(t); ok {
+			key.Children = append(key.Children, r)
+		}
+		spec.Extra.Children = append(spec.Extra.Children, key)
 	}
 	tableAttrsSpec(t, spec)
 	return spec, nil
@@ -774,7 +943,7 @@ func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 	if i := (IndexType{}); sqlx.Has(idx.Attrs, &i) && strings.ToUpper(i.T) != IndexTypeBTree {
 		var attr *schemahcl.Attr
 		switch strings.ToUpper(i.T) {
-		case IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, IndexTypeSPGiST:
+		case IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, IndexTypeSPGiST, IndexTypeIVFFlat, IndexTypeHNSW:
 			attr = specutil.VarAttr("type", strings.ToUpper(i.T))
 		default:
 			attr = schemahcl.StringAttr("type", i.T)
@@ -787,6 +956,10 @@ func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 	if i := (IndexNullsDistinct{}); sqlx.Has(idx.Attrs, &i) && !i.V {
 		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("nulls_distinct", i.V))
 	}
+	if a, ok := deferrableSpec(idx.Attrs); ok {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, a)
+	}
+	spec.Extra.Attrs = tabletSplitSpec(idx.Attrs, spec.Extra.Attrs)
 	spec.Extra.Attrs = indexPKSpec(idx, spec.Extra.Attrs)
 	return spec, nil
 }
@@ -800,9 +973,100 @@ func indexPKSpec(idx *schema.Index, attrs []*schemahcl.Attr) []*schemahcl.Attr {
 		attrs = append(attrs, schemahcl.RefsAttr("include", refs...))
 	}
 	if p, ok := indexStorageParams(idx.Attrs); ok {
-		attrs = append(attrs, schemahcl.Int64Attr("page_per_range", p.PagesPerRange))
+		if p.PagesPerRange != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr("page_per_range", p.PagesPerRange))
+		}
+		if p.AutoSummarize {
+			attrs = append(attrs, schemahcl.BoolAttr(storageParamAutoSum, true))
+		}
+		if p.FillFactor != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr(storageParamFillFactor, p.FillFactor))
+		}
+		if p.Deduplicate != "" {
+			attrs = append(attrs, schemahcl.BoolAttr(storageParamDedup, p.Deduplicate == storageParamOn))
+		}
+		if p.Buffering != "" {
+			attrs = append(attrs, specutil.VarAttr(storageParamBuffering, p.Buffering))
+		}
+		if p.FastUpdate != "" {
+			attrs = append(attrs, schemahcl.BoolAttr(storageParamFastUpdate, p.FastUpdate == storageParamOn))
+		}
+		if p.ListLimit != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr(storageParamListLimit, p.ListLimit))
+		}
+		if p.Lists != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr(storageParamLists, p.Lists))
+		}
+		if p.M != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr(storageParamM, p.M))
+		}
+		if p.EfConstruction != 0 {
+			attrs = append(attrs, schemahcl.Int64Attr(storageParamEf, p.EfConstruction))
+		}
+	}
+	return hashShardedSpec(idx, attrs)
+}
+
+// convertStorageParams converts the index storage parameters.
+func convertStorageParams(spec specutil.Attrer, idx *schema.Index) error {
+	var (
+		found  bool
+		params IndexStorageParams
+	)
+	for n, v := range map[string]*int64{
+		"page_per_range":       &params.PagesPerRange,
+		storageParamFillFactor: &params.FillFactor,
+		storageParamListLimit:  &params.ListLimit,
+		storageParamLists:      &params.Lists,
+		storageParamM:          &params.M,
+		storageParamEf:         &params.EfConstruction,
+	} {
+		if attr, ok := spec.Attr(n); ok {
+			i, err := attr.Int64()
+			if err != nil {
+				return err
+			}
+			*v, found = i, true
+		}
+	}
+	if attr, ok := spec.Attr(storageParamAutoSum); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return err
+		}
+		params.AutoSummarize, found = b, true
+	}
+	for n, v := range map[string]*string{
+		storageParamDedup:      &params.Deduplicate,
+		storageParamFastUpdate: &params.FastUpdate,
+	} {
+		if attr, ok := spec.Attr(n); ok {
+			b, err := attr.Bool()
+			if err != nil {
+				return err
+			}
+			*v, found = storageParamOff, true
+			if b {
+				*v = storageParamOn
+			}
+		}
 	}
-	return attrs
+	if attr, ok := spec.Attr(storageParamBuffering); ok {
+		s, err := attr.String()
+		if err != nil {
+			return err
+		}
+		switch s = strings.ToUpper(s); s {
+		case bufferingOn, bufferingOff, bufferingAuto:
+			params.Buffering, found = s, true
+		default:
+			return fmt.Errorf("unexpected buffering value for index %q: %q", idx.Name, s)
+		}
+	}
+	if found {
+		idx.Attrs = append(idx.Attrs, &params)
+	}
+	return nil
 }
 
 func partAttr(idx *schema.Index, part *schema.IndexPart, spec *sqlspec.IndexPart) error {
@@ -827,9 +1091,104 @@ func partAttr(idx *schema.Index, part *schema.IndexPart, spec *sqlspec.IndexPart
 			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("nulls_first", true))
 		}
 	}
+	// Operators are defined only for parts of EXCLUDE constraints.
+	if op := (Operator{}); sqlx.Has(part.Attrs, &op) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("op", op.Name))
+	}
 	return nil
 }
 
+// fkSpec converts from a schema.ForeignKey into a sqlspec.ForeignKey.
+func fkSpec(fk *schema.ForeignKey) (*sqlspec.ForeignKey, error) {
+	spec, err := specutil.FromForeignKey(fk)
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := deferrableSpec(fk.Attrs); ok {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, a)
+	}
+	if c := constraintComment(fk.Attrs); c != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+	}
+	return spec, nil
+}
+
+// convertFK converts the extra attributes of a sqlspec.ForeignKey.
+func convertFK(spec *sqlspec.ForeignKey, fk *schema.ForeignKey) error {
+	if err := convertDeferrable(spec, &fk.Attrs); err != nil {
+		return err
+	}
+	return convertConstraintComment(spec, &fk.Attrs)
+}
+
+// checkSpec converts from a schema.Check into a sqlspec.Check.
+func checkSpec(c *schema.Check) *sqlspec.Check {
+	spec := specutil.FromCheck(c)
+	if t := constraintComment(c.Attrs); t != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", t))
+	}
+	return spec
+}
+
+// convertCheck converts a sqlspec.Check into a schema.Check.
+func convertCheck(spec *sqlspec.Check) (*schema.Check, error) {
+	c, err := specutil.Check(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertConstraintComment(spec, &c.Attrs); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// convertConstraintComment converts the "comment" attribute of constraints.
+func convertConstraintComment(spec specutil.Attrer, attrs *[]schema.Attr) error {
+	a, ok := spec.Attr("comment")
+	if !ok {
+		return nil
+	}
+	c, err := a.String()
+	if err != nil {
+		return fmt.Errorf("parse comment attribute: %w", err)
+	}
+	*attrs = append(*attrs, &schema.Comment{Text: c})
+	return nil
+}
+
+// convertDeferrable converts the "deferrable" attribute of constraints.
+func convertDeferrable(spec specutil.Attrer, attrs *[]schema.Attr) error {
+	a, ok := spec.Attr("deferrable")
+	if !ok {
+		return nil
+	}
+	v, err := a.String()
+	if err != nil {
+		return fmt.Errorf("parse deferrable attribute: %w", err)
+	}
+	switch strings.ToUpper(v) {
+	case DeferrableInitiallyImmediate:
+		*attrs = append(*attrs, &Deferrable{})
+	case DeferrableInitiallyDeferred:
+		*attrs = append(*attrs, &Deferrable{InitiallyDeferred: true})
+	default:
+		return fmt.Errorf("unexpected deferrable attribute value: %q", v)
+	}
+	return nil
+}
+
+// deferrableSpec returns the "deferrable" attribute of constraints, if defined.
+func deferrableSpec(attrs []schema.Attr) (*schemahcl.Attr, bool) {
+	var d Deferrable
+	if !sqlx.Has(attrs, &d) {
+		return nil, false
+	}
+	if d.InitiallyDeferred {
+		return specutil.VarAttr("deferrable", DeferrableInitiallyDeferred), true
+	}
+	return specutil.VarAttr("deferrable", DeferrableInitiallyImmediate), true
+}
+
 // tableColumnSpec converts from a concrete Postgres schema.Column into a sqlspec.Column.
 func tableColumnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
 	s, err := specutil.FromColumn(c, columnTypeSpec)
@@ -837,7 +1196,7 @@ func tableColumnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error)
 		return nil, err
 	}
 	if i := (&Identity{}); sqlx.Has(c.Attrs, i) {
-		s.Extra.Children = append(s.Extra.Children, fromIdentity(i))
+		s.Extra.Children = append(s.Extra.Children, fromIdentity(i, c))
 	}
 	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
 		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, generatedType))
@@ -846,7 +1205,8 @@ func tableColumnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error)
 }
 
 // fromIdentity returns the resource spec for representing the identity attributes.
-func fromIdentity(i *Identity) *schemahcl.Resource {
+// Sequence options that are set to the defaults of the column type are omitted.
+func fromIdentity(i *Identity, c *schema.Column) *schemahcl.Resource {
 	id := &schemahcl.Resource{
 		Type: "identity",
 		Attrs: []*schemahcl.Attr{
@@ -854,12 +1214,25 @@ func fromIdentity(i *Identity) *schemahcl.Resource {
 		},
 	}
 	if s := i.Sequence; s != nil {
-		if s.Start != 1 {
+		if s.Start != 0 && s.Start != defaultSeqStart {
 			id.Attrs = append(id.Attrs, schemahcl.Int64Attr("start", s.Start))
 		}
-		if s.Increment != 1 {
+		if s.Increment != 0 && s.Increment != defaultSeqIncrement {
 			id.Attrs = append(id.Attrs, schemahcl.Int64Attr("increment", s.Increment))
 		}
+		o, d := identityOptions(i, c)
+		if o.min != d.min {
+			id.Attrs = append(id.Attrs, schemahcl.Int64Attr("min_value", o.min))
+		}
+		if o.max != d.max {
+			id.Attrs = append(id.Attrs, schemahcl.Int64Attr("max_value", o.max))
+		}
+		if o.cache != d.cache {
+			id.Attrs = append(id.Attrs, schemahcl.Int64Attr("cache", o.cache))
+		}
+		if o.cycle {
+			id.Attrs = append(id.Attrs, schemahcl.BoolAttr("cycle", true))
+		}
 	}
 	return id
 }
@@ -885,6 +1258,14 @@ func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
 				IsRef: true,
 				T:     specutil.ObjectRef(o.Schema, o).V},
 		}, nil
+	case *CustomRangeType:
+		return &sqlspec.Column{
+			Type: &schemahcl.Type{
+				IsRef: true,
+				T:     specutil.ObjectRef(o.Schema, o).V},
+		}, nil
+	case *CustomMultirangeType:
+		return multirangeSpec(o), nil
 	default:
 		st, err := TypeRegistry.Convert(t)
 		if err != nil {
@@ -965,7 +1346,15 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec(TypeTSTZMultiRange),
 		schemahcl.NewTypeSpec(TypeDateRange),
 		schemahcl.NewTypeSpec(TypeDateMultiRange),
-		schemahcl.NewTypeSpec("hstore"),
+		schemahcl.NewTypeSpec(TypeHStore),
+		schemahcl.NewTypeSpec(TypeCIText),
+		schemahcl.NewTypeSpec(TypeLTree),
+		schemahcl.NewTypeSpec(TypeCube),
+		schemahcl.NewTypeSpec(TypeGeometry),
+		schemahcl.NewTypeSpec(TypeGeography),
+		schemahcl.NewTypeSpec(TypeVector, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int})),
+		schemahcl.NewTypeSpec(TypeHalfVec, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int})),
+		schemahcl.NewTypeSpec(TypeSparseVec, schemahcl.WithAttributes(&schemahcl.TypeAttr{Name: "dim", Kind: reflect.Int})),
 		schemahcl.NewTypeSpec(TypeXID),
 		schemahcl.NewTypeSpec(TypeXID8),
 	),
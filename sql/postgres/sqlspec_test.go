@@ -5,13 +5,18 @@
 package postgres
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/internal/spectest"
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/stretchr/testify/require"
 )
 
@@ -325,6 +330,86 @@ schema "public" {
 	require.Equal(t, "schema comment", s.Attrs[0].(*schema.Comment).Text)
 }
 
+func TestUnmarshalSpec_TypeHints(t *testing.T) {
+	var (
+		r schema.Realm
+		f = `
+schema "public" {}
+table "t" {
+  schema = schema.public
+  column "c" {
+    type = text(255)
+  }
+}
+`
+	)
+	err := EvalHCLBytes([]byte(f), &r, nil)
+	require.EqualError(t, err, `:6,12-16: Call to unknown function; "text(255)" is invalid for postgres; did you mean "varchar(255)"?`)
+}
+
+func TestUnmarshalSpec_PosError(t *testing.T) {
+	var (
+		r schema.Realm
+		p = hclparse.NewParser()
+		f = `
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "id" {
+    type = int
+  }
+  index "idx" {
+    on {
+      column = column.id
+    }
+    on {
+      desc = true
+    }
+  }
+}
+`
+	)
+	_, diags := p.ParseHCL([]byte(f), "schema.hcl")
+	require.False(t, diags.HasErrors())
+	err := codec.EvalOptions(p, &r, &schemahcl.EvalOptions{RecordPos: true})
+	var pe *schema.PosError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, 12, pe.Pos.Start.Line)
+	require.EqualError(t, err, `specutil: failed converting to *schema.Realm: cannot convert table "users": schema.hcl:12:5: "column" or "expr" are required for index "idx" at position 1`)
+
+	// Without recorded positions, errors are reported as before.
+	err = EvalHCLBytes([]byte(f), &r, nil)
+	require.False(t, errors.As(err, &pe))
+	require.EqualError(t, err, `specutil: failed converting to *schema.Realm: cannot convert table "users": "column" or "expr" are required for index "idx" at position 1`)
+}
+
+func TestUnmarshalSpec_When(t *testing.T) {
+	var (
+		s schema.Schema
+		f = `
+schema "public" {}
+table "t" {
+  schema = schema.public
+  column "c" {
+    type = text
+  }
+  index "idx_mysql" {
+    when    = dialect() == "mysql"
+    columns = [column.c]
+  }
+  index "idx_postgres" {
+    when    = dialect() == "postgres"
+    type    = GIN
+    columns = [column.c]
+  }
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Len(t, s.Tables[0].Indexes, 1)
+	require.Equal(t, "idx_postgres", s.Tables[0].Indexes[0].Name)
+}
+
 func TestUnmarshalSpec_IndexType(t *testing.T) {
 	f := `
 schema "s" {}
@@ -376,8 +461,9 @@ func TestMarshalSpec_IndexType(t *testing.T) {
 				).
 				AddIndexes(
 					schema.NewIndex("i1").AddAttrs(&IndexType{T: IndexTypeHash}),
-					schema.NewIndex("i2").AddAttrs(&IndexType{T: "HNSW"}),
+					schema.NewIndex("i2").AddAttrs(&IndexType{T: "RUM"}),
 					schema.NewIndex("i2").AddAttrs(&IndexType{T: IndexTypeBTree}), // Default.
+					schema.NewIndex("i3").AddAttrs(&IndexType{T: "hnsw"}, &IndexStorageParams{M: 32, EfConstruction: 64}),
 				),
 		)
 	buf, err := MarshalHCL(s)
@@ -392,10 +478,14 @@ func TestMarshalSpec_IndexType(t *testing.T) {
     type = HASH
   }
   index "i2" {
-    type = "HNSW"
+    type = "RUM"
   }
   index "i2" {
   }
+  index "i3" {
+    type = HNSW
+    m    = 32
+  }
 }
 schema "public" {
 }
@@ -1073,6 +1163,38 @@ table "t" {
 		require.EqualValues(t, 10, id.Sequence.Start)
 		require.Zero(t, id.Sequence.Increment)
 	})
+	t.Run("Options", func(t *testing.T) {
+		var (
+			s schema.Schema
+			f = `table "t" {
+  schema = schema.s
+  column "c" {
+    null = false
+    type = integer
+    identity {
+      generated = ALWAYS
+      start     = 10
+      max_value = 1000
+      cache     = 20
+      cycle     = true
+    }
+  }
+}
+schema "s" {
+}
+`
+		)
+		err := EvalHCLBytes([]byte(f), &s, nil)
+		require.NoError(t, err)
+		id := s.Tables[0].Columns[0].Attrs[0].(*Identity)
+		require.EqualValues(t, 1000, *id.Sequence.Max)
+		require.Nil(t, id.Sequence.Min)
+		require.EqualValues(t, 20, id.Sequence.Cache)
+		require.True(t, id.Sequence.Cycle)
+		buf, err := MarshalHCL(&s)
+		require.NoError(t, err)
+		require.Equal(t, f, string(buf))
+	})
 }
 
 func TestUnmarshalSpec_IndexInclude(t *testing.T) {
@@ -1442,6 +1564,22 @@ func TestTypes(t *testing.T) {
 			typeExpr: `hstore`,
 			expected: &UserDefinedType{T: "hstore"},
 		},
+		{
+			typeExpr: "citext",
+			expected: &UserDefinedType{T: TypeCIText},
+		},
+		{
+			typeExpr: "geography",
+			expected: &schema.SpatialType{T: TypeGeography},
+		},
+		{
+			typeExpr: "vector(3)",
+			expected: &VectorType{T: TypeVector, Dim: 3},
+		},
+		{
+			typeExpr: "halfvec",
+			expected: &VectorType{T: TypeHalfVec},
+		},
 		{
 			typeExpr: "bit_varying(10)",
 			expected: &BitType{T: TypeBitVar, Len: 10},
@@ -2252,3 +2390,307 @@ schema "public" {
 	require.Equal(t, &IndexInclude{Columns: []*schema.Column{s.Tables[0].Columns[1]}}, u3.Attrs[0])
 	require.Equal(t, UniqueConstraint("u3"), u3.Attrs[1].(*Constraint))
 }
+
+func TestMarshalSpec_ExcludeDeferrable(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		id   = schema.NewIntColumn("id", "int")
+		room = schema.NewIntColumn("room", "int")
+		at   = schema.NewColumn("during").SetType(&RangeType{T: TypeTSRange})
+		t1   = schema.NewTable("rooms").AddColumns(schema.NewIntColumn("id", "int"))
+		t2   = schema.NewTable("bookings").AddColumns(id, room, at)
+	)
+	t1.SetPrimaryKey(schema.NewPrimaryKey(t1.Columns...))
+	t2.AddIndexes(
+		schema.NewUniqueIndex("bookings_id").AddColumns(id).AddAttrs(UniqueConstraint("bookings_id"), &Deferrable{}),
+		schema.NewIndex("no_overlap").
+			AddParts(
+				schema.NewColumnPart(room).AddAttrs(&Operator{Name: "="}),
+				schema.NewColumnPart(at).AddAttrs(&Operator{Name: "&&"}),
+			).
+			AddAttrs(&IndexType{T: IndexTypeGiST}, &IndexPredicate{P: "id > 0"}, ExcludeConstraint("no_overlap"), &Deferrable{InitiallyDeferred: true}),
+	)
+	t2.AddForeignKeys(schema.NewForeignKey("room_fk").AddColumns(room).SetRefTable(t1).AddRefColumns(t1.Columns[0]).AddAttrs(&Deferrable{InitiallyDeferred: true}))
+	s.AddTables(t1, t2)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "rooms" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = int
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "bookings" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = int
+  }
+  column "room" {
+    null = false
+    type = int
+  }
+  column "during" {
+    null = false
+    type = tsrange
+  }
+  foreign_key "room_fk" {
+    columns     = [column.room]
+    ref_columns = [table.rooms.column.id]
+    deferrable  = INITIALLY_DEFERRED
+  }
+  unique "bookings_id" {
+    columns    = [column.id]
+    deferrable = INITIALLY_IMMEDIATE
+  }
+  exclude "no_overlap" {
+    type       = GIST
+    where      = "id > 0"
+    deferrable = INITIALLY_DEFERRED
+    on {
+      column = column.room
+      op     = "="
+    }
+    on {
+      column = column.during
+      op     = "&&"
+    }
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	changes, err := DefaultDiff.SchemaDiff(s, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	bookings, ok := got.Table("bookings")
+	require.True(t, ok)
+	ex, ok := bookings.Index("no_overlap")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Operator{Name: "&&"}}, ex.Parts[1].Attrs)
+	require.True(t, sqlx.Has(ex.Attrs, &Deferrable{}))
+
+	err = EvalHCLBytes([]byte(`
+table "t" {
+  schema = schema.public
+  column "c" {
+    type = int
+  }
+  exclude "c_excl" {
+    on {
+      column = column.c
+    }
+  }
+}
+schema "public" {
+}
+`), &got, nil)
+	require.EqualError(t, err, `cannot convert table "t": missing operator for t.exclude constraint "c_excl" at position 0`)
+}
+
+func TestParseType_Extension(t *testing.T) {
+	for _, tt := range []struct {
+		typ      string
+		expected schema.Type
+		ext      string
+	}{
+		{typ: "citext", expected: &UserDefinedType{T: "citext"}, ext: "citext"},
+		{typ: "public.citext", expected: &UserDefinedType{T: "public.citext"}, ext: "citext"},
+		{typ: "citext[]", expected: &ArrayType{T: "citext[]", Type: &UserDefinedType{T: "citext"}}, ext: "citext"},
+		{typ: "geometry(Point,4326)", expected: &schema.SpatialType{T: "geometry(Point,4326)"}, ext: "postgis"},
+		{typ: "public.geography(Point)", expected: &schema.SpatialType{T: "public.geography(Point)"}, ext: "postgis"},
+		{typ: "vector(1536)", expected: &VectorType{T: "vector", Dim: 1536}, ext: "vector"},
+		{typ: "extensions.sparsevec(5)", expected: &VectorType{T: "extensions.sparsevec", Dim: 5}, ext: "vector"},
+		{typ: "my_type", expected: &UserDefinedType{T: "my_type"}},
+	} {
+		typ, err := ParseType(tt.typ)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, typ)
+		ext, ok := TypeExtension(typ)
+		require.Equal(t, tt.ext != "", ok)
+		require.Equal(t, tt.ext, ext)
+	}
+	_, err := ParseType("vector(x)")
+	require.Error(t, err)
+
+	s := schema.New("public").AddTables(
+		schema.NewTable("t").AddColumns(
+			schema.NewColumn("v").SetType(&VectorType{T: TypeVector, Dim: 3}),
+			schema.NewColumn("g").SetType(&schema.SpatialType{T: TypeGeometry}),
+		),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  column "v" {
+    null = false
+    type = vector(3)
+  }
+  column "g" {
+    null = false
+    type = geometry
+  }
+}
+schema "public" {
+}
+`, string(buf))
+}
+
+func TestMarshalSpec_IndexStorageParams(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		c  = schema.NewIntColumn("id", "int")
+		t1 = schema.NewTable("t").AddColumns(c)
+	)
+	t1.AddIndexes(
+		schema.NewIndex("t_btree").AddColumns(c).AddAttrs(&IndexStorageParams{FillFactor: 70, Deduplicate: "OFF"}),
+		schema.NewIndex("t_gist").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeGiST}, &IndexStorageParams{Buffering: bufferingOff}),
+		schema.NewIndex("t_gin").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeGIN}, &IndexStorageParams{FastUpdate: "OFF", ListLimit: 1024}),
+		schema.NewIndex("t_brin").AddColumns(c).AddAttrs(&IndexType{T: IndexTypeBRIN}, &IndexStorageParams{AutoSummarize: true}),
+		schema.NewIndex("t_ivfflat").AddColumns(c).AddAttrs(&IndexType{T: "ivfflat"}, &IndexStorageParams{Lists: 50}),
+		schema.NewIndex("t_hnsw").AddColumns(c).AddAttrs(&IndexType{T: "hnsw"}, &IndexStorageParams{M: 32, EfConstruction: 128}),
+	)
+	s.AddTables(t1)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = int
+  }
+  index "t_btree" {
+    columns           = [column.id]
+    fillfactor        = 70
+    deduplicate_items = false
+  }
+  index "t_gist" {
+    columns   = [column.id]
+    type      = GIST
+    buffering = OFF
+  }
+  index "t_gin" {
+    columns                = [column.id]
+    type                   = GIN
+    fastupdate             = false
+    gin_pending_list_limit = 1024
+  }
+  index "t_brin" {
+    columns       = [column.id]
+    type          = BRIN
+    autosummarize = true
+  }
+  index "t_ivfflat" {
+    columns = [column.id]
+    type    = IVFFLAT
+    lists   = 50
+  }
+  index "t_hnsw" {
+    columns         = [column.id]
+    type            = HNSW
+    m               = 32
+    ef_construction = 128
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	got := schema.New("public")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	changes, err := DefaultDiff.SchemaDiff(s, got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	err = EvalHCLBytes([]byte(`
+table "t" {
+  schema = schema.public
+  column "id" {
+    type = int
+  }
+  index "t_gist" {
+    columns   = [column.id]
+    type      = GIST
+    buffering = "sometimes"
+  }
+}
+schema "public" {
+}
+`), got, nil)
+	require.EqualError(t, err, `cannot convert table "t": unexpected buffering value for index "t_gist": "SOMETIMES"`)
+}
+
+func TestMarshalSpec_ConstraintComments(t *testing.T) {
+	var (
+		s = schema.New("public")
+		c = schema.NewIntColumn("id", "int")
+		e = &schema.EnumType{T: "status", Schema: s, Values: []string{"on"}, Attrs: []schema.Attr{&schema.Comment{Text: "state"}}}
+		u = schema.NewTable("t").AddColumns(c, schema.NewEnumColumn("status", schema.EnumName("status"), schema.EnumValues("on")))
+	)
+	u.Columns[1].Type.Type = e
+	u.AddForeignKeys(schema.NewForeignKey("t_id_fkey").AddColumns(c).SetRefTable(u).AddRefColumns(c).AddAttrs(&schema.Comment{Text: "self reference"}))
+	u.AddChecks(schema.NewCheck().SetName("t_id_check").SetExpr("(id > 0)").AddAttrs(&schema.Comment{Text: "positive"}))
+	s.AddTables(u).AddObjects(e)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = int
+  }
+  column "status" {
+    null = false
+    type = enum.status
+  }
+  foreign_key "t_id_fkey" {
+    columns     = [column.id]
+    ref_columns = [column.id]
+    comment     = "self reference"
+  }
+  check "t_id_check" {
+    expr    = "(id > 0)"
+    comment = "positive"
+  }
+}
+enum "status" {
+  schema  = schema.public
+  values  = ["on"]
+  comment = "state"
+}
+schema "public" {
+}
+`, string(buf))
+
+	got := schema.New("public")
+	require.NoError(t, EvalHCLBytes(buf, got, nil))
+	changes, err := DefaultDiff.SchemaDiff(s, got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestCodec_JSONSchema(t *testing.T) {
+	b, err := codec.JSONSchema()
+	require.NoError(t, err)
+	var js struct {
+		Properties map[string]struct {
+			AdditionalProperties struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"additionalProperties"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(b, &js))
+	for _, k := range []string{"schema", "table", "enum", "domain", "function", "materialized", "role"} {
+		require.Contains(t, js.Properties, k)
+	}
+	require.Contains(t, js.Properties["table"].AdditionalProperties.Properties, "column")
+	require.Contains(t, js.Properties["enum"].AdditionalProperties.Properties, "values")
+}
@@ -0,0 +1,167 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// TableStats describes the table statistics, as reported by the pg_class catalog
+	// and the pg_stat_user_tables view. It is returned by inspection only if the
+	// Stats inspect option is enabled. Note, the number of rows and the bloat
+	// are estimations, and may differ from the actual ones.
+	TableStats struct {
+		schema.Attr
+		Rows        int64 // Estimated number of rows.
+		TableSize   int64 // Size of the table (including TOAST) in bytes.
+		IndexesSize int64 // Size of the table indexes in bytes.
+		TotalSize   int64 // Total size of the table, its indexes and TOAST in bytes.
+		LiveRows    int64 // Number of live rows, as reported by the statistics collector.
+		DeadRows    int64 // Number of dead rows, as reported by the statistics collector.
+		// Estimated bloat of the table in bytes, that is, the space
+		// occupied by dead rows that was not reclaimed by VACUUM.
+		Bloat int64
+	}
+
+	// IndexStats describes the index statistics, as reported by the pg_stat_user_indexes
+	// view. It is returned by inspection only if the Stats inspect option is enabled.
+	IndexStats struct {
+		schema.Attr
+		Size  int64 // Size of the index in bytes.
+		Scans int64 // Number of index scans initiated on this index.
+	}
+)
+
+// tableBloat estimates the table bloat from the ratio of its dead rows.
+func tableBloat(size, live, dead int64) int64 {
+	if dead <= 0 || live+dead <= 0 {
+		return 0
+	}
+	return size * dead / (live + dead)
+}
+
+// inspectStats sets the statistics of the inspected tables and their indexes.
+func (i *inspect) inspectStats(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(tableStatsQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying table statistics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name                         string
+			trows, tsize, isize, total, live sql.NullInt64
+			dead                             sql.NullInt64
+		)
+		if err := rows.Scan(&ns, &name, &trows, &tsize, &isize, &total, &live, &dead); err != nil {
+			return fmt.Errorf("postgres: scanning table statistics: %w", err)
+		}
+		t, ok := realmTable(r, ns, name)
+		if !ok {
+			continue // Table was not inspected.
+		}
+		t.AddAttrs(&TableStats{
+			Rows:        trows.Int64,
+			TableSize:   tsize.Int64,
+			IndexesSize: isize.Int64,
+			TotalSize:   total.Int64,
+			LiveRows:    live.Int64,
+			DeadRows:    dead.Int64,
+			Bloat:       tableBloat(tsize.Int64, live.Int64, dead.Int64),
+		})
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	rows, err = i.QueryContext(ctx, fmt.Sprintf(indexStatsQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying index statistics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, table, name string
+			size, scans     sql.NullInt64
+		)
+		if err := rows.Scan(&ns, &table, &name, &size, &scans); err != nil {
+			return fmt.Errorf("postgres: scanning index statistics: %w", err)
+		}
+		t, ok := realmTable(r, ns, table)
+		if !ok {
+			continue
+		}
+		idx, ok := t.Index(name)
+		if !ok && t.PrimaryKey != nil && t.PrimaryKey.Name == name {
+			idx, ok = t.PrimaryKey, true
+		}
+		if ok {
+			idx.AddAttrs(&IndexStats{Size: size.Int64, Scans: scans.Int64})
+		}
+	}
+	return rows.Err()
+}
+
+// realmTable returns the table with the given schema and name from the realm, if exists.
+func realmTable(r *schema.Realm, ns, name string) (*schema.Table, bool) {
+	s, ok := r.Schema(ns)
+	if !ok {
+		return nil, false
+	}
+	return s.Table(name)
+}
+
+const (
+	// Query to list the statistics of the tables in the given schemas.
+	tableStatsQuery = `
+SELECT
+  n.nspname AS schema_name,
+  c.relname AS table_name,
+  GREATEST(c.reltuples, 0)::bigint AS rows,
+  pg_catalog.pg_table_size(c.oid) AS table_size,
+  pg_catalog.pg_indexes_size(c.oid) AS indexes_size,
+  pg_catalog.pg_total_relation_size(c.oid) AS total_size,
+  s.n_live_tup,
+  s.n_dead_tup
+FROM
+  pg_catalog.pg_class AS c
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+  LEFT JOIN pg_catalog.pg_stat_user_tables AS s ON s.relid = c.oid
+WHERE
+  c.relkind IN ('r', 'p')
+  AND n.nspname IN (%s)
+ORDER BY
+  n.nspname, c.relname
+`
+
+	// Query to list the statistics of the table indexes in the given schemas.
+	indexStatsQuery = `
+SELECT
+  n.nspname AS schema_name,
+  t.relname AS table_name,
+  i.relname AS index_name,
+  pg_catalog.pg_relation_size(i.oid) AS index_size,
+  s.idx_scan
+FROM
+  pg_catalog.pg_index AS x
+  JOIN pg_catalog.pg_class AS i ON i.oid = x.indexrelid
+  JOIN pg_catalog.pg_class AS t ON t.oid = x.indrelid
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = t.relnamespace
+  LEFT JOIN pg_catalog.pg_stat_user_indexes AS s ON s.indexrelid = i.oid
+WHERE
+  t.relkind IN ('r', 'p')
+  AND n.nspname IN (%s)
+ORDER BY
+  n.nspname, t.relname, i.relname
+`
+)
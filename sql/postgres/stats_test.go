@@ -0,0 +1,95 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectStats(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= CURRENT_SCHEMA()"))).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.tableExists("public", "users", true)
+	mk.ExpectQuery(queryColumns).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
+table_name |column_name | data_type | formatted | is_nullable | column_default | character_maximum_length | numeric_precision | datetime_precision | numeric_scale | interval_type | character_set_name | collation_name | is_identity | identity_start | identity_increment |   identity_last  | identity_generation | generation_expression | comment | typtype | typelem |  oid |  attnum
+-----------+------------+-----------+-----------+-------------+----------------+--------------------------+-------------------+--------------------+---------------+---------------+--------------------+----------------+-------------+----------------+--------------------+------------------+---------------------+-----------------------+---------+---------+---------+------+--------
+users      | id         | integer   | integer   | NO          |                |                          |                32 |                    |             0 |               |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |   23 |
+`))
+	mk.ExpectQuery(queryIndexes).
+		WithArgs("public", "users").
+		WillReturnRows(sqltest.Rows(`
+ table_name | index_name | index_type | column_name | included | primary | unique | opexpr | constraints                                                         | predicate | expression | desc | nulls_first | nulls_last | comment | options | opclass_name | opclass_schema | opclass_default | opclass_params | indnullsnotdistinct
+------------+------------+------------+-------------+----------+---------+--------+--------+---------------------------------------------------------------------+-----------+------------+------+-------------+------------+---------+---------+--------------+----------------+-----------------+----------------+---------------------
+ users      | users_pkey | btree      | id          | f        | t       | t      |        | {"users_pkey": {"type": "p", "deferrable": false, "deferred": false}} |           |            | f    | f           | f          |         |         | int4_ops     | pg_catalog     | t               |                | f
+`))
+	mk.noFKs()
+	mk.noChecks()
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(tableStatsQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | rows     | table_size   | indexes_size | total_size   | n_live_tup | n_dead_tup
+-------------+------------+----------+--------------+--------------+--------------+------------+------------
+ public      | users      | 12000000 | 214748364800 | 1073741824   | 215822106624 | 9000000    | 3000000
+ public      | other      | 0        | 8192         | 0            | 8192         | nil        | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexStatsQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | index_name | index_size | idx_scan
+-------------+------------+------------+------------+----------
+ public      | users      | users_pkey | 1073741824 | 42
+`))
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
+		Mode:  schema.InspectSchemas | schema.InspectTables,
+		Stats: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	var ts TableStats
+	require.True(t, sqlx.Has(users.Attrs, &ts))
+	require.Equal(t, TableStats{
+		Rows:        12000000,
+		TableSize:   214748364800,
+		IndexesSize: 1073741824,
+		TotalSize:   215822106624,
+		LiveRows:    9000000,
+		DeadRows:    3000000,
+		Bloat:       53687091200,
+	}, ts)
+	require.NotNil(t, users.PrimaryKey)
+	var is IndexStats
+	require.True(t, sqlx.Has(users.PrimaryKey.Attrs, &is))
+	require.Equal(t, IndexStats{Size: 1073741824, Scans: 42}, is)
+}
+
+func TestTableBloat(t *testing.T) {
+	require.Zero(t, tableBloat(8192, 0, 0))
+	require.Zero(t, tableBloat(8192, 10, 0))
+	require.Equal(t, int64(4096), tableBloat(8192, 10, 10))
+	require.Equal(t, int64(8192), tableBloat(8192, 0, 10))
+}
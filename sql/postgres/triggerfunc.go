@@ -0,0 +1,459 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+// Trigger functions are represented as schema.Func objects. That is, functions without
+// arguments that return either the "trigger" or the "event_trigger" pseudo-type, and are
+// executed by (event) triggers.
+// https://www.postgresql.org/docs/current/plpgsql-trigger.html
+
+// DependsOn implements the sqlx.Depender interface. An event trigger
+// can be (re)created only after the function it executes was created.
+func (e *EventTrigger) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+	default:
+		return false
+	}
+	var f *schema.Func
+	switch o := other.(type) {
+	case *schema.AddFunc:
+		f = o.F
+	case *schema.ModifyFunc:
+		f = o.To
+	}
+	return f != nil && eventTriggerFuncEqual(e.Func, triggerFuncName(f))
+}
+
+// DependencyOf implements the sqlx.Depender interface. An event trigger is
+// dropped (or recreated) before the function it executes is dropped or recreated.
+func (e *EventTrigger) DependencyOf(change, other schema.Change) bool {
+	switch c := change.(type) {
+	case *schema.DropObject:
+	case *schema.ModifyObject:
+		if e1, ok := c.From.(*EventTrigger); ok {
+			e = e1
+		}
+	default:
+		return false
+	}
+	var f *schema.Func
+	switch o := other.(type) {
+	case *schema.DropFunc:
+		f = o.F
+	case *schema.ModifyFunc:
+		if triggerFuncRecreate(o.From, o.To) {
+			f = o.From
+		}
+	}
+	return f != nil && eventTriggerFuncEqual(e.Func, triggerFuncName(f))
+}
+
+// triggerFuncName returns the qualified name of the trigger function.
+func triggerFuncName(f *schema.Func) string {
+	if f.Schema == nil || f.Schema.Name == "" {
+		return f.Name
+	}
+	return f.Schema.Name + "." + f.Name
+}
+
+// triggerFuncReturns returns the return type of the trigger function.
+func triggerFuncReturns(f *schema.Func) string {
+	if t, ok := f.Ret.(*PseudoType); ok {
+		return strings.ToLower(t.T)
+	}
+	return ""
+}
+
+func triggerFuncComment(f *schema.Func) string {
+	var c schema.Comment
+	sqlx.Has(f.Attrs, &c)
+	return c.Text
+}
+
+// inspectTriggerFuncs queries and appends the trigger functions of the realm.
+// Functions that are members of extensions are managed by their extension,
+// and are therefore skipped.
+func (i *inspect) inspectTriggerFuncs(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(triggerFuncsQuery, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying trigger functions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name, returns, lang, body string
+			comment                       sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &returns, &lang, &body, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning trigger function: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("postgres: schema %q for trigger function %q was not found in realm", ns, name)
+		}
+		f := schema.NewFunc(name, body).SetLang(lang).SetRet(&PseudoType{T: returns})
+		if sqlx.ValidString(comment) {
+			f.SetComment(comment.String)
+		}
+		s.AddFuncs(f)
+	}
+	return rows.Err()
+}
+
+// FuncChanged implements the sqlx.FuncChanger interface. Trigger functions
+// are compared by their return type, language and normalized body.
+func (*diff) FuncChanged(from, to *schema.Func) bool {
+	return triggerFuncRecreate(from, to) || triggerFuncChanged(from, to)
+}
+
+// triggerFuncRecreate reports if the function must be dropped and created again.
+// The return type of a function cannot be changed by CREATE OR REPLACE, and
+// hence, the function and its dependent triggers are recreated.
+func triggerFuncRecreate(from, to *schema.Func) bool {
+	return triggerFuncReturns(from) != triggerFuncReturns(to)
+}
+
+// triggerFuncChanged reports if the language or the body of the function were changed.
+// The bodies are compared after they are normalized, to avoid reporting changes in case
+// only the formatting was changed, or comments were added or removed.
+func triggerFuncChanged(from, to *schema.Func) bool {
+	return !strings.EqualFold(from.Lang, to.Lang) || normalizeFuncBody(from.Body) != normalizeFuncBody(to.Body)
+}
+
+// realmTriggerFunc returns the trigger function with the given (optionally qualified)
+// name from the realm. Unqualified names are searched in all schemas of the realm.
+func realmTriggerFunc(r *schema.Realm, name string) (*schema.Func, bool) {
+	ns, name := eventTriggerFunc(name)
+	for _, s := range r.Schemas {
+		if ns != "" && s.Name != ns {
+			continue
+		}
+		if f, ok := s.Func(name); ok {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// eventTriggerFuncRecreated reports if the function that is executed
+// by the event trigger is recreated, and therefore, the trigger as well.
+func eventTriggerFuncRecreated(from, to *schema.Realm, e1, e2 *EventTrigger) bool {
+	if !eventTriggerFuncEqual(e1.Func, e2.Func) {
+		return false
+	}
+	f1, ok1 := realmTriggerFunc(from, e1.Func)
+	f2, ok2 := realmTriggerFunc(to, e2.Func)
+	return ok1 && ok2 && triggerFuncRecreate(f1, f2)
+}
+
+// reDollarTag matches the opening tag of dollar-quoted strings.
+var reDollarTag = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z_0-9]*)?\$`)
+
+// normalizeFuncBody normalizes the body of functions for comparison. Comments are
+// stripped and whitespaces are collapsed, except in quoted strings and identifiers.
+func normalizeFuncBody(body string) string {
+	var (
+		b     strings.Builder
+		space bool
+	)
+	write := func(s string) {
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteString(s)
+	}
+	for i := 0; i < len(body); {
+		switch c := body[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			space, i = true, i+1
+		case strings.HasPrefix(body[i:], "--"):
+			j := strings.IndexByte(body[i:], '\n')
+			if j == -1 {
+				j = len(body) - i
+			}
+			space, i = true, i+j
+		case strings.HasPrefix(body[i:], "/*"):
+			// Block comments can be nested.
+			j := i + 2
+			for depth := 1; j < len(body) && depth > 0; j++ {
+				switch {
+				case strings.HasPrefix(body[j:], "/*"):
+					depth, j = depth+1, j+1
+				case strings.HasPrefix(body[j:], "*/"):
+					depth, j = depth-1, j+1
+				}
+			}
+			space, i = true, j
+		case c == '\'' || c == '"':
+			j := i + 1
+			for ; j < len(body); j++ {
+				if body[j] != c {
+					continue
+				}
+				// Doubled quotes are escaped quotes.
+				if j+1 < len(body) && body[j+1] == c {
+					j++
+					continue
+				}
+				break
+			}
+			j = min(j+1, len(body))
+			write(body[i:j])
+			i = j
+		case c == '$' && reDollarTag.MatchString(body[i:]):
+			tag := reDollarTag.FindString(body[i:])
+			j := len(body)
+			if k := strings.Index(body[i+len(tag):], tag); k != -1 {
+				j = i + len(tag) + k + len(tag)
+			}
+			write(body[i:j])
+			i = j
+		default:
+			write(body[i : i+1])
+			i++
+		}
+	}
+	// The semicolon after the final END of PL/pgSQL blocks is optional.
+	return strings.TrimRight(b.String(), ";")
+}
+
+// addTriggerFunc plans the creation of a trigger function.
+func (s *state) addTriggerFunc(src schema.Change, f *schema.Func) error {
+	create, err := s.createTriggerFunc("CREATE FUNCTION", f)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     create,
+		Reverse: s.dropTriggerFuncCmd(f),
+		Comment: fmt.Sprintf("create trigger function %q", f.Name),
+	})
+	if c := triggerFuncComment(f); c != "" {
+		s.append(s.triggerFuncComment(src, f, c, ""))
+	}
+	return nil
+}
+
+// dropTriggerFunc plans the removal of a trigger function.
+func (s *state) dropTriggerFunc(src schema.Change, f *schema.Func) error {
+	create, err := s.createTriggerFunc("CREATE FUNCTION", f)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  src,
+		Cmd:     s.dropTriggerFuncCmd(f),
+		Reverse: create,
+		Comment: fmt.Sprintf("drop trigger function %q", f.Name),
+	})
+	return nil
+}
+
+// modifyTriggerFunc plans the changes of a trigger function. Changes to its
+// definition are applied using CREATE OR REPLACE, which keeps the dependent
+// triggers attached to the function.
+func (s *state) modifyTriggerFunc(src schema.Change, from, to *schema.Func) error {
+	if triggerFuncRecreate(from, to) {
+		if err := s.dropTriggerFunc(src, from); err != nil {
+			return err
+		}
+		return s.addTriggerFunc(src, to)
+	}
+	if triggerFuncChanged(from, to) {
+		replace, err := s.createTriggerFunc("CREATE OR REPLACE FUNCTION", to)
+		if err != nil {
+			return err
+		}
+		reverse, err := s.createTriggerFunc("CREATE OR REPLACE FUNCTION", from)
+		if err != nil {
+			return err
+		}
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     replace,
+			Reverse: reverse,
+			Comment: fmt.Sprintf("replace trigger function %q", to.Name),
+		})
+	}
+	if c1, c2 := triggerFuncComment(from), triggerFuncComment(to); c1 != c2 {
+		s.append(s.triggerFuncComment(src, to, c2, c1))
+	}
+	return nil
+}
+
+func (s *state) createTriggerFunc(cmd string, f *schema.Func) (string, error) {
+	switch r := triggerFuncReturns(f); {
+	case f.Ret == nil:
+		return "", fmt.Errorf("missing return type for trigger function %q", f.Name)
+	case r != typeTrigger && r != typeEventTrigger:
+		return "", fmt.Errorf("unsupported function %q: only functions that return %s or %s are supported", f.Name, typeTrigger, typeEventTrigger)
+	case len(f.Args) > 0:
+		return "", fmt.Errorf("unsupported function %q: trigger functions cannot declare arguments", f.Name)
+	case f.Lang == "":
+		return "", fmt.Errorf("missing language for trigger function %q", f.Name)
+	}
+	return s.Build(cmd).
+		P(s.triggerFuncIdent(f), "RETURNS", triggerFuncReturns(f), "LANGUAGE", strings.ToLower(f.Lang), "AS", dollarQuote(f.Body)).
+		String(), nil
+}
+
+func (s *state) dropTriggerFuncCmd(f *schema.Func) string {
+	return s.Build("DROP FUNCTION").P(s.triggerFuncIdent(f)).String()
+}
+
+func (s *state) triggerFuncComment(src schema.Change, f *schema.Func, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON FUNCTION").P(s.triggerFuncIdent(f)).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to trigger function: %q", f.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) triggerFuncIdent(f *schema.Func) string {
+	return s.typeIdent(f.Schema, f.Name) + "()"
+}
+
+// dollarQuote returns the function body wrapped with a dollar-quote
+// tag that does not appear in it.
+func dollarQuote(body string) string {
+	tag := "$$"
+	for i := 0; strings.Contains(body, tag); i++ {
+		tag = fmt.Sprintf("$body%d$", i)
+	}
+	return tag + body + tag
+}
+
+// triggerFunc holds a specification for a trigger function.
+type triggerFunc struct {
+	Name      string         `spec:",name"`
+	Qualifier string         `spec:",qualifier"`
+	Schema    *schemahcl.Ref `spec:"schema"`
+	Lang      string         `spec:"lang"`
+	As        string         `spec:"as"`
+	// The return type and the comment are
+	// added to the function definition.
+	schemahcl.DefaultExtension
+}
+
+// Label returns the defaults label used for the function resource.
+func (f *triggerFunc) Label() string { return f.Name }
+
+// QualifierLabel returns the qualifier label used for the function resource, if any.
+func (f *triggerFunc) QualifierLabel() string { return f.Qualifier }
+
+// SetQualifier sets the qualifier label used for the function resource.
+func (f *triggerFunc) SetQualifier(q string) { f.Qualifier = q }
+
+// SchemaRef returns the schema reference for the function.
+func (f *triggerFunc) SchemaRef() *schemahcl.Ref { return f.Schema }
+
+// convertTriggerFuncs converts the trigger function specs and adds them to the realm.
+func convertTriggerFuncs(d *doc, r *schema.Realm) error {
+	for _, spec := range d.Funcs {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from function %q reference: %w", spec.Name, err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on function %q was not found in realm", ns, spec.Name)
+		}
+		if _, ok := s.Func(spec.Name); ok {
+			return fmt.Errorf("duplicate function %q in schema %q", spec.Name, s.Name)
+		}
+		var returns string
+		if a, ok := spec.Attr("return"); ok {
+			if returns, err = a.String(); err != nil {
+				return fmt.Errorf("parsing function %q attribute \"return\": %w", spec.Name, err)
+			}
+		}
+		f := schema.NewFunc(spec.Name, spec.As).SetLang(spec.Lang).SetRet(&PseudoType{T: returns})
+		switch {
+		case returns != typeTrigger && returns != typeEventTrigger:
+			return fmt.Errorf("unexpected return type %q for function %q, expect %s or %s", returns, spec.Name, typeTrigger, typeEventTrigger)
+		case f.Lang == "":
+			return fmt.Errorf("missing attribute function.%s.lang", spec.Name)
+		case f.Body == "":
+			return fmt.Errorf("missing attribute function.%s.as", spec.Name)
+		}
+		if a, ok := spec.Attr("comment"); ok {
+			c, err := a.String()
+			if err != nil {
+				return fmt.Errorf("parsing function %q attribute \"comment\": %w", spec.Name, err)
+			}
+			f.SetComment(c)
+		}
+		s.AddFuncs(f)
+	}
+	return nil
+}
+
+// triggerFuncSpec converts a trigger function into its spec.
+func triggerFuncSpec(f *schema.Func) *triggerFunc {
+	spec := &triggerFunc{
+		Name:   f.Name,
+		Schema: specutil.SchemaRef(f.Schema.Name),
+		Lang:   strings.ToLower(f.Lang),
+		As:     sqlspec.MightHeredoc(f.Body),
+	}
+	spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("return", triggerFuncReturns(f)))
+	if c := triggerFuncComment(f); c != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c))
+	}
+	return spec
+}
+
+// Query to list the trigger functions of the given schemas, excluding the ones created by extensions.
+const triggerFuncsQuery = `
+SELECT
+  n.nspname AS schema_name,
+  p.proname AS func_name,
+  t.typname AS returns,
+  l.lanname AS lang,
+  p.prosrc AS body,
+  obj_description(p.oid, 'pg_proc') AS comment
+FROM
+  pg_catalog.pg_proc AS p
+  JOIN pg_catalog.pg_namespace AS n ON n.oid = p.pronamespace
+  JOIN pg_catalog.pg_type AS t ON t.oid = p.prorettype
+  JOIN pg_catalog.pg_language AS l ON l.oid = p.prolang
+WHERE
+  n.nspname IN (%s)
+  AND p.pronargs = 0
+  AND t.typname IN ('trigger', 'event_trigger')
+  AND NOT EXISTS (
+    SELECT 1 FROM pg_catalog.pg_depend AS d
+    WHERE d.classid = 'pg_catalog.pg_proc'::regclass AND d.objid = p.oid AND d.deptype = 'e'
+  )
+ORDER BY
+  n.nspname, p.proname
+`
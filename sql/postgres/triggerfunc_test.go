@@ -0,0 +1,231 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeFuncBody(t *testing.T) {
+	for _, tt := range []struct {
+		body, expected string
+	}{
+		{body: "BEGIN RETURN NEW; END;", expected: "BEGIN RETURN NEW; END"},
+		{body: "\n  BEGIN\n\t-- Return the new row.\n\tRETURN NEW;\n  END;\n", expected: "BEGIN RETURN NEW; END"},
+		{body: "BEGIN /* outer /* nested */ comment */ RETURN NEW; END", expected: "BEGIN RETURN NEW; END"},
+		{body: "BEGIN RAISE NOTICE 'a  -- b'; END", expected: "BEGIN RAISE NOTICE 'a  -- b'; END"},
+		{body: "BEGIN RAISE NOTICE 'it''s  /* */'; END", expected: "BEGIN RAISE NOTICE 'it''s  /* */'; END"},
+		{body: `BEGIN SELECT "a  b" FROM t; END`, expected: `BEGIN SELECT "a  b" FROM t; END`},
+		{body: "BEGIN EXECUTE $q$SELECT  1 -- x$q$; END", expected: "BEGIN EXECUTE $q$SELECT  1 -- x$q$; END"},
+		{body: "BEGIN PERFORM f($1,  $2); END", expected: "BEGIN PERFORM f($1, $2); END"},
+	} {
+		require.Equal(t, tt.expected, normalizeFuncBody(tt.body), tt.body)
+	}
+}
+
+func TestDriver_InspectTriggerFuncs(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', '', false)")).
+		WillReturnRows(sqltest.Rows(`
+ current_setting | set_config
+-----------------+------------
+ public          |
+`))
+	mk.ExpectQuery(sqltest.Escape(schemasQuery)).
+		WillReturnRows(sqltest.Rows(`
+ schema_name | comment
+-------------+---------
+ public      | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(triggerFuncsQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | func_name  | returns       | lang    | body                         | comment
+-------------+------------+---------------+---------+------------------------------+---------
+ public      | deny_drops | event_trigger | plpgsql | BEGIN RAISE EXCEPTION 'no'; END | nil
+ public      | touch      | trigger       | plpgsql | BEGIN RETURN NEW; END        | touch rows
+`))
+	mk.ExpectQuery(sqltest.Escape("SELECT set_config('search_path', $1, false)")).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"set_config"}).AddRow(""))
+	r, err := drv.InspectRealm(context.Background(), &schema.InspectRealmOption{
+		Mode: schema.InspectSchemas | schema.InspectFuncs,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mk.ExpectationsWereMet())
+	s := r.Schemas[0]
+	require.Equal(t, []*schema.Func{
+		{Name: "deny_drops", Schema: s, Ret: &PseudoType{T: typeEventTrigger}, Lang: "plpgsql", Body: "BEGIN RAISE EXCEPTION 'no'; END"},
+		{Name: "touch", Schema: s, Ret: &PseudoType{T: typeTrigger}, Lang: "plpgsql", Body: "BEGIN RETURN NEW; END", Attrs: []schema.Attr{&schema.Comment{Text: "touch rows"}}},
+	}, s.Funcs)
+	require.Empty(t, s.Objects)
+}
+
+func TestDiff_TriggerFuncs(t *testing.T) {
+	var (
+		from = schema.New("public")
+		to   = schema.New("public")
+	)
+	from.AddFuncs(
+		schema.NewFunc("same", "\nBEGIN\n  RETURN NEW;\nEND;\n").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+		schema.NewFunc("changed", "BEGIN RETURN NEW; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+		schema.NewFunc("recreated", "BEGIN RETURN NULL; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+		schema.NewFunc("dropped", "BEGIN RETURN NEW; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+	)
+	to.AddFuncs(
+		// Formatting and comments are ignored.
+		schema.NewFunc("same", "BEGIN -- Keep the row.\n RETURN NEW; END").SetLang("PLpgSQL").SetRet(&PseudoType{T: typeTrigger}),
+		schema.NewFunc("changed", "BEGIN RETURN OLD; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+		schema.NewFunc("recreated", "BEGIN RETURN NULL; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeEventTrigger}),
+		schema.NewFunc("added", "BEGIN RETURN NEW; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}),
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.DropFunc{F: from.Funcs[3]},
+		&schema.ModifyFunc{From: from.Funcs[1], To: to.Funcs[1]},
+		&schema.ModifyFunc{From: from.Funcs[2], To: to.Funcs[2]},
+		&schema.AddFunc{F: to.Funcs[3]},
+	}, changes)
+}
+
+func TestPlanChanges_TriggerFuncs(t *testing.T) {
+	s := schema.New("public")
+	f := schema.NewFunc("touch", "BEGIN RETURN NEW; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}).SetComment("touch rows")
+	s.AddFuncs(f)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddFunc{F: f},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE FUNCTION "public"."touch"() RETURNS trigger LANGUAGE plpgsql AS $$BEGIN RETURN NEW; END$$`, `DROP FUNCTION "public"."touch"()`},
+		{`COMMENT ON FUNCTION "public"."touch"() IS 'touch rows'`, `COMMENT ON FUNCTION "public"."touch"() IS ''`},
+	}, planCmds(plan))
+
+	// Body changes are applied in place, and bodies that contain
+	// the default dollar-quote tag are quoted with a unique tag.
+	to := &schema.Func{Name: "touch", Schema: s, Ret: &PseudoType{T: typeTrigger}, Lang: "plpgsql", Body: "BEGIN EXECUTE $$SELECT 1$$; RETURN NEW; END"}
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyFunc{From: f, To: to},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{
+			`CREATE OR REPLACE FUNCTION "public"."touch"() RETURNS trigger LANGUAGE plpgsql AS $body0$BEGIN EXECUTE $$SELECT 1$$; RETURN NEW; END$body0$`,
+			`CREATE OR REPLACE FUNCTION "public"."touch"() RETURNS trigger LANGUAGE plpgsql AS $$BEGIN RETURN NEW; END$$`,
+		},
+		{`COMMENT ON FUNCTION "public"."touch"() IS ''`, `COMMENT ON FUNCTION "public"."touch"() IS 'touch rows'`},
+	}, planCmds(plan))
+
+	// Only trigger functions are supported.
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddFunc{F: &schema.Func{Name: "f", Schema: s, Ret: &schema.IntegerType{T: "int"}, Lang: "sql", Body: "SELECT 1"}},
+	})
+	require.EqualError(t, err, `unsupported function "f": only functions that return trigger or event_trigger are supported`)
+}
+
+func TestPlanChanges_TriggerFuncRecreate(t *testing.T) {
+	var (
+		from = schema.NewRealm(schema.New("public"))
+		to   = schema.NewRealm(schema.New("public"))
+		f1   = schema.NewFunc("log_ddl", "BEGIN END").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger})
+		f2   = schema.NewFunc("log_ddl", "BEGIN END").SetLang("plpgsql").SetRet(&PseudoType{T: typeEventTrigger})
+		e1   = &EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Func: "public.log_ddl"}
+		e2   = &EventTrigger{Name: "audit", Event: EventDDLCommandEnd, Func: "log_ddl"}
+	)
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	from.Schemas[0].AddFuncs(f1)
+	from.AddObjects(e1)
+	to.Schemas[0].AddFuncs(f2)
+	to.AddObjects(e2)
+	changes, err := drv.RealmDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	plan, err := drv.PlanChanges(context.Background(), "plan", changes)
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`DROP EVENT TRIGGER "audit"`, `CREATE EVENT TRIGGER "audit" ON ddl_command_end EXECUTE FUNCTION "public"."log_ddl"()`},
+		{`DROP FUNCTION "public"."log_ddl"()`, `CREATE FUNCTION "public"."log_ddl"() RETURNS trigger LANGUAGE plpgsql AS $$BEGIN END$$`},
+		{`CREATE FUNCTION "public"."log_ddl"() RETURNS event_trigger LANGUAGE plpgsql AS $$BEGIN END$$`, `DROP FUNCTION "public"."log_ddl"()`},
+		{`CREATE EVENT TRIGGER "audit" ON ddl_command_end EXECUTE FUNCTION "log_ddl"()`, `DROP EVENT TRIGGER "audit"`},
+	}, planCmds(plan))
+
+	// New functions are created before the triggers that execute them.
+	plan, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: e2},
+		&schema.AddFunc{F: f2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE FUNCTION "public"."log_ddl"() RETURNS event_trigger LANGUAGE plpgsql AS $$BEGIN END$$`, `DROP FUNCTION "public"."log_ddl"()`},
+		{`CREATE EVENT TRIGGER "audit" ON ddl_command_end EXECUTE FUNCTION "log_ddl"()`, `DROP EVENT TRIGGER "audit"`},
+	}, planCmds(plan))
+}
+
+func TestMarshalSpec_TriggerFuncs(t *testing.T) {
+	s := schema.New("public")
+	s.AddFuncs(
+		schema.NewFunc("touch", "\nBEGIN\n  NEW.updated_at = now();\n  RETURN NEW;\nEND;\n").SetLang("plpgsql").SetRet(&PseudoType{T: typeTrigger}).SetComment("touch rows"),
+		schema.NewFunc("deny_drops", "BEGIN RAISE EXCEPTION 'no'; END").SetLang("plpgsql").SetRet(&PseudoType{T: typeEventTrigger}),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `function "touch" {
+  schema  = schema.public
+  lang    = "plpgsql"
+  as      = <<-SQL
+  BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+  END;
+  SQL
+  return  = trigger
+  comment = "touch rows"
+}
+function "deny_drops" {
+  schema = schema.public
+  lang   = "plpgsql"
+  as     = "BEGIN RAISE EXCEPTION 'no'; END"
+  return = event_trigger
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Funcs, 2)
+	changes, err := DefaultDiff.SchemaDiff(s, &got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+function "f" {
+  schema = schema.public
+  lang   = "sql"
+  as     = "SELECT 1"
+  return = "int"
+}
+`), &got, nil)
+	require.EqualError(t, err, `unexpected return type "int" for function "f", expect trigger or event_trigger`)
+}
@@ -0,0 +1,48 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDialect describes the PostgreSQL rules that are checked by schema.Validate.
+var DefaultDialect = &schema.Dialect{
+	Name:        "postgres",
+	MaxIdentLen: 63,
+	Reserved: func(s string) bool {
+		return reserved[strings.ToUpper(s)]
+	},
+	// Indexes share the namespace of relations in their schema.
+	SchemaScoped: func(kind string) bool {
+		return kind == "index"
+	},
+	// Foreign keys require the types to be comparable, e.g., integer and bigint.
+	FKTypes: func(from, to schema.Type) bool {
+		return TypeConversion(from, to) != schema.ConversionIncompatible &&
+			TypeConversion(to, from) != schema.ConversionIncompatible
+	},
+}
+
+// reserved holds the reserved keywords of PostgreSQL, including the
+// keywords that are reserved only as function or type names.
+var reserved = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		ALL ANALYSE ANALYZE AND ANY ARRAY AS ASC ASYMMETRIC BOTH CASE CAST CHECK COLLATE COLUMN CONSTRAINT
+		CREATE CURRENT_CATALOG CURRENT_DATE CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER DEFAULT
+		DEFERRABLE DESC DISTINCT DO ELSE END EXCEPT FALSE FETCH FOR FOREIGN FROM GRANT GROUP HAVING IN
+		INITIALLY INTERSECT INTO LATERAL LEADING LIMIT LOCALTIME LOCALTIMESTAMP NOT NULL OFFSET ON ONLY OR
+		ORDER PLACING PRIMARY REFERENCES RETURNING SELECT SESSION_USER SOME SYMMETRIC SYSTEM_USER TABLE THEN
+		TO TRAILING TRUE UNION UNIQUE USER USING VARIADIC WHEN WHERE WINDOW WITH
+		AUTHORIZATION BINARY COLLATION CONCURRENTLY CROSS CURRENT_SCHEMA FREEZE FULL ILIKE INNER IS ISNULL
+		JOIN LEFT LIKE NATURAL NOTNULL OUTER OVERLAPS RIGHT SIMILAR TABLESAMPLE VERBOSE
+	`) {
+		m[w] = true
+	}
+	return m
+}()
@@ -0,0 +1,152 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+)
+
+type (
+	// Colocation describes if a YugabyteDB table is colocated with the other
+	// tables of a colocated database, that is, stored in a single tablet. For
+	// example, WITH (colocation = false) opts a table out of the colocation.
+	//
+	// Colocation can be set only on table creation, and therefore,
+	// it is not diffed against the inspected state.
+	Colocation struct {
+		schema.Attr
+		V bool
+	}
+
+	// TabletSplit describes the pre-splitting of a YugabyteDB table or index
+	// into tablets. Either Tablets or At is set. For example:
+	//
+	//	SPLIT INTO 8 TABLETS
+	//	SPLIT AT VALUES ((100), (200))
+	//
+	// Tablets can be split only on creation, and therefore,
+	// it is not diffed against the inspected state.
+	TabletSplit struct {
+		schema.Attr
+		Tablets int64
+		At      []string // Split points. For example, "(100)".
+	}
+)
+
+// reYBVersion matches the YugabyteDB release in the output of version().
+// For example, "PostgreSQL 11.2-YB-2.20.1.0-b0 on x86_64-pc-linux-gnu".
+var reYBVersion = regexp.MustCompile(`-YB-(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ybVersion reports if the version() output belongs to YugabyteDB, and parses
+// its release into the server_version_num format (e.g. 22001). Zero is returned
+// if the release cannot be parsed.
+func ybVersion(s string) (int, bool) {
+	if !strings.Contains(s, "-YB-") {
+		return 0, false
+	}
+	m := reYBVersion.FindStringSubmatch(s)
+	if m == nil {
+		return 0, true
+	}
+	var v int
+	for _, p := range m[1:] {
+		n, _ := strconv.Atoi(p)
+		v = v*100 + n
+	}
+	return v, true
+}
+
+// addYBTableAttrs writes the colocation and the tablet splitting of the table to its CREATE command.
+func addYBTableAttrs(b *sqlx.Builder, t *schema.Table) {
+	if c := (Colocation{}); sqlx.Has(t.Attrs, &c) {
+		b.P(fmt.Sprintf("WITH (colocation = %t)", c.V))
+	}
+	tabletSplit(b, t.Attrs)
+}
+
+// tabletSplit writes the SPLIT clause of a table or an index, if exists.
+func tabletSplit(b *sqlx.Builder, attrs []schema.Attr) {
+	var s TabletSplit
+	switch {
+	case !sqlx.Has(attrs, &s):
+	case s.Tablets > 0:
+		b.P(fmt.Sprintf("SPLIT INTO %d TABLETS", s.Tablets))
+	case len(s.At) > 0:
+		b.P("SPLIT AT VALUES").Wrap(func(b *sqlx.Builder) {
+			b.WriteString(strings.Join(s.At, ", "))
+		})
+	}
+}
+
+// ybTableAttrsSpec appends the "colocation" and the tablet splitting attributes to the table spec, if needed.
+func ybTableAttrsSpec(t *schema.Table, spec *sqlspec.Table) {
+	if c := (Colocation{}); sqlx.Has(t.Attrs, &c) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("colocation", c.V))
+	}
+	spec.Extra.Attrs = tabletSplitSpec(t.Attrs, spec.Extra.Attrs)
+}
+
+// tabletSplitSpec appends the "split_tablets" or "split_at" attribute to the given attributes, if needed.
+func tabletSplitSpec(attrs []schema.Attr, spec []*schemahcl.Attr) []*schemahcl.Attr {
+	var s TabletSplit
+	switch {
+	case !sqlx.Has(attrs, &s):
+	case s.Tablets > 0:
+		spec = append(spec, schemahcl.Int64Attr("split_tablets", s.Tablets))
+	case len(s.At) > 0:
+		spec = append(spec, schemahcl.StringsAttr("split_at", s.At...))
+	}
+	return spec
+}
+
+// convertYBTableAttrs converts the "colocation" and the tablet splitting attributes of the table spec, if exist.
+func convertYBTableAttrs(spec *sqlspec.Table, t *schema.Table) error {
+	if attr, ok := spec.Attr("colocation"); ok {
+		v, err := attr.Bool()
+		if err != nil {
+			return err
+		}
+		t.AddAttrs(&Colocation{V: v})
+	}
+	return convertTabletSplit(spec, &t.Attrs)
+}
+
+// convertTabletSplit converts the "split_tablets" or "split_at" attribute, if exists.
+func convertTabletSplit(spec specutil.Attrer, attrs *[]schema.Attr) error {
+	tablets, ok1 := spec.Attr("split_tablets")
+	at, ok2 := spec.Attr("split_at")
+	switch {
+	case ok1 && ok2:
+		return fmt.Errorf("split_tablets and split_at cannot be set together")
+	case ok1:
+		n, err := tablets.Int64()
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return fmt.Errorf("split_tablets must be a positive number, got: %d", n)
+		}
+		*attrs = append(*attrs, &TabletSplit{Tablets: n})
+	case ok2:
+		points, err := at.Strings()
+		if err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			return fmt.Errorf("split_at must list at least one split point")
+		}
+		*attrs = append(*attrs, &TabletSplit{At: points})
+	}
+	return nil
+}
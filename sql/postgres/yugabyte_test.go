@@ -0,0 +1,144 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_Yugabyte(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(paramsQuery)).
+		WillReturnRows(sqltest.Rows(`
+  version       |  am  | crdb | desc
+----------------|------|------|------------------------------------------------------
+ 110002         | heap | NULL | PostgreSQL 11.2-YB-2.20.1.0-b0 on x86_64-pc-linux-gnu
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+	c := drv.(*Driver).conn
+	require.True(t, c.yb)
+	require.False(t, c.crdb)
+	require.Equal(t, 2_20_01, c.ybVersion)
+
+	v, ok := ybVersion("PostgreSQL 11.2-YB-2.18.0.0-b0 on x86_64-pc-linux-gnu")
+	require.True(t, ok)
+	require.Equal(t, 2_18_00, v)
+	v, ok = ybVersion("PostgreSQL 11.2-YB")
+	require.False(t, ok)
+	require.Zero(t, v)
+	_, ok = ybVersion("PostgreSQL 15.4 on x86_64-pc-linux-gnu")
+	require.False(t, ok)
+}
+
+func TestPlanChanges_Yugabyte(t *testing.T) {
+	var (
+		yb  = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows, version: 11_00_02, yb: true, ybVersion: 2_18_00}}
+		s   = schema.New("public")
+		tbl = schema.NewTable("events").
+			SetSchema(s).
+			AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("ts", "bigint")).
+			AddAttrs(&Colocation{V: false}, &TabletSplit{Tablets: 4})
+	)
+	tbl.SetPrimaryKey(schema.NewPrimaryKey(tbl.Columns[0]))
+	tbl.AddIndexes(schema.NewIndex("events_ts").AddColumns(tbl.Columns[1]).AddAttrs(&TabletSplit{At: []string{"(100)", "(200)"}}))
+	plan, err := yb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{
+		{`CREATE TABLE "public"."events" ("id" bigint NOT NULL, "ts" bigint NOT NULL, PRIMARY KEY ("id")) WITH (colocation = false) SPLIT INTO 4 TABLETS`, `DROP TABLE "public"."events"`},
+		{`CREATE INDEX "events_ts" ON "public"."events" ("ts") SPLIT AT VALUES ((100), (200))`, `DROP INDEX "public"."events_ts"`},
+	}, planCmds(plan))
+
+	// Column type changes require a table rewrite, which is supported since v2.20.
+	changes := []schema.Change{
+		&schema.ModifyTable{T: tbl, Changes: []schema.Change{
+			&schema.ModifyColumn{From: tbl.Columns[1], To: schema.NewStringColumn("ts", "text"), Change: schema.ChangeType},
+		}},
+	}
+	_, err = yb.PlanChanges(context.Background(), "plan", changes)
+	require.EqualError(t, err, `table "events": column "ts": yugabyte: column type changes are supported only by YugabyteDB v2.20 and above`)
+	yb.ybVersion = 2_20_01
+	plan, err = yb.PlanChanges(context.Background(), "plan", changes)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+
+	// Unsupported features are reported instead of producing invalid DDL.
+	_, err = yb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: schema.NewTable("logs").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int")).AddAttrs(&Locality{Kind: LocalityGlobal})},
+	})
+	require.EqualError(t, err, `table "logs": yugabyte: table localities are not supported`)
+
+	// YugabyteDB features are rejected by PostgreSQL and CockroachDB.
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.EqualError(t, err, `table "events": postgres: colocated tables are supported only by YugabyteDB`)
+	crdb := &planApply{conn: &conn{ExecQuerier: sqlx.NoRows, version: 13_00_00, crdb: true, crdbVersion: 23_01_02}}
+	_, err = crdb.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: tbl},
+	})
+	require.EqualError(t, err, `table "events": cockroach: colocated tables are not supported`)
+}
+
+func TestMarshalSpec_YugabyteAttrs(t *testing.T) {
+	s := schema.New("public")
+	tbl := schema.NewTable("events").
+		AddColumns(schema.NewIntColumn("id", "bigint")).
+		AddAttrs(&Colocation{V: false}, &TabletSplit{Tablets: 4})
+	s.AddTables(tbl)
+	tbl.AddIndexes(schema.NewIndex("events_id").AddColumns(tbl.Columns[0]).AddAttrs(&TabletSplit{At: []string{"(100)", "(200)"}}))
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema        = schema.public
+  colocation    = false
+  split_tablets = 4
+  column "id" {
+    null = false
+    type = bigint
+  }
+  index "events_id" {
+    columns  = [column.id]
+    split_at = ["(100)", "(200)"]
+  }
+}
+schema "public" {
+}
+`, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	gt, ok := got.Table("events")
+	require.True(t, ok)
+	require.Equal(t, tbl.Attrs, gt.Attrs)
+	idx, ok := gt.Index("events_id")
+	require.True(t, ok)
+	require.Equal(t, tbl.Indexes[0].Attrs, idx.Attrs)
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "t" {
+  schema        = schema.public
+  split_tablets = 4
+  split_at      = ["(1)"]
+  column "id" {
+    type = int
+  }
+}
+`), &got, nil)
+	require.ErrorContains(t, err, "split_tablets and split_at cannot be set together")
+}
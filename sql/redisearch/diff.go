@@ -0,0 +1,128 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"slices"
+	"strings"
+)
+
+type (
+	// A Change represents a change of the search indexes that can be planned as Redis commands.
+	Change interface {
+		change()
+	}
+
+	// AddIndex describes an index creation change.
+	AddIndex struct {
+		I *Index
+	}
+
+	// DropIndex describes an index removal change. Dropping an
+	// index does not delete the documents it was built on.
+	DropIndex struct {
+		I *Index
+	}
+
+	// AddFields describes the addition of fields to an existing index, using FT.ALTER.
+	// Other index changes are described as a DropIndex followed by an AddIndex, as
+	// RediSearch does not support modifying them.
+	AddFields struct {
+		From, To *Index
+		Fields   []*Field
+	}
+)
+
+func (*AddIndex) change()  {}
+func (*DropIndex) change() {}
+func (*AddFields) change() {}
+
+// Diff returns the changes for migrating the indexes from one state to the other.
+// Indexes that do not exist in the desired state are dropped.
+func Diff(from, to *Database) ([]Change, error) {
+	var changes []Change
+	for _, idx1 := range from.Indexes {
+		if _, ok := to.Index(idx1.Name); !ok {
+			changes = append(changes, &DropIndex{I: idx1})
+		}
+	}
+	for _, idx2 := range to.Indexes {
+		idx1, ok := from.Index(idx2.Name)
+		if !ok {
+			changes = append(changes, &AddIndex{I: idx2})
+			continue
+		}
+		changes = append(changes, IndexDiff(idx1, idx2)...)
+	}
+	return changes, nil
+}
+
+// IndexDiff returns the changes for migrating an index from one state to the other.
+// Fields appended to the end of the schema are added in place, and any other change
+// recreates the index.
+func IndexDiff(from, to *Index) []Change {
+	if definitionChanged(from, to) || len(to.Fields) < len(from.Fields) {
+		return []Change{&DropIndex{I: from}, &AddIndex{I: to}}
+	}
+	for i := range from.Fields {
+		if fieldChanged(from.Fields[i], to.Fields[i]) {
+			return []Change{&DropIndex{I: from}, &AddIndex{I: to}}
+		}
+	}
+	if len(to.Fields) == len(from.Fields) {
+		return nil
+	}
+	return []Change{&AddFields{From: from, To: to, Fields: to.Fields[len(from.Fields):]}}
+}
+
+// definitionChanged reports if the definition (e.g., key type or prefixes) of the index was changed.
+func definitionChanged(from, to *Index) bool {
+	return !strings.EqualFold(defaultString(from.On, OnHash), defaultString(to.On, OnHash)) ||
+		!slices.Equal(from.Prefixes, to.Prefixes) ||
+		from.Filter != to.Filter ||
+		!strings.EqualFold(defaultString(from.Language, "english"), defaultString(to.Language, "english"))
+}
+
+// fieldChanged reports if the field definition was changed.
+func fieldChanged(from, to *Field) bool {
+	switch {
+	case from.Name != to.Name, defaultString(from.Alias, from.Name) != defaultString(to.Alias, to.Name),
+		!strings.EqualFold(from.Type, to.Type), from.Sortable != to.Sortable, from.NoIndex != to.NoIndex:
+		return true
+	}
+	switch strings.ToUpper(to.Type) {
+	case TypeText:
+		return from.NoStem != to.NoStem || weight(from) != weight(to)
+	case TypeTag:
+		return from.CaseSensitive != to.CaseSensitive || defaultString(from.Separator, ",") != defaultString(to.Separator, ",")
+	case TypeVector:
+		v1, v2 := vectorOptions(from), vectorOptions(to)
+		return !strings.EqualFold(v1.Algorithm, v2.Algorithm) || !strings.EqualFold(v1.Type, v2.Type) ||
+			!strings.EqualFold(v1.Distance, v2.Distance) || v1.Dim != v2.Dim
+	}
+	return false
+}
+
+// weight returns the weight of a TEXT field.
+func weight(f *Field) float64 {
+	if f.Weight == 0 {
+		return 1
+	}
+	return f.Weight
+}
+
+func vectorOptions(f *Field) VectorOptions {
+	if f.Vector == nil {
+		return VectorOptions{}
+	}
+	return *f.Vector
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,114 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	var (
+		users = &Index{
+			Name:     "idx:users",
+			On:       OnHash,
+			Prefixes: []string{"user:"},
+			Fields: []*Field{
+				{Name: "name", Type: TypeText, Weight: 1},
+				{Name: "tags", Type: TypeTag},
+			},
+		}
+		docs = &Index{Name: "idx:docs", On: OnJSON, Fields: []*Field{{Name: "$.title", Alias: "title", Type: TypeText}}}
+	)
+	tests := []struct {
+		name     string
+		from, to *Database
+		want     []Change
+	}{
+		{
+			name: "no changes",
+			from: &Database{Indexes: []*Index{users}},
+			to: &Database{Indexes: []*Index{{
+				Name:     "idx:users",
+				Prefixes: []string{"user:"},
+				Language: "english",
+				Fields: []*Field{
+					{Name: "name", Alias: "name", Type: "text"},
+					{Name: "tags", Type: TypeTag, Separator: ","},
+				},
+			}}},
+		},
+		{
+			name: "add and drop indexes",
+			from: &Database{Indexes: []*Index{users}},
+			to:   &Database{Indexes: []*Index{docs}},
+			want: []Change{&DropIndex{I: users}, &AddIndex{I: docs}},
+		},
+		{
+			name: "add fields",
+			from: &Database{Indexes: []*Index{users}},
+			to: &Database{Indexes: []*Index{{
+				Name:     "idx:users",
+				Prefixes: []string{"user:"},
+				Fields: []*Field{
+					users.Fields[0],
+					users.Fields[1],
+					{Name: "age", Type: TypeNumeric},
+				},
+			}}},
+		},
+	}
+	tests[2].want = []Change{&AddFields{From: users, To: tests[2].to.Indexes[0], Fields: tests[2].to.Indexes[0].Fields[2:]}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(tt.from, tt.to)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, changes)
+		})
+	}
+}
+
+func TestIndexDiff_Recreate(t *testing.T) {
+	from := &Index{
+		Name:     "idx",
+		Prefixes: []string{"a:"},
+		Fields: []*Field{
+			{Name: "title", Type: TypeText},
+			{Name: "tags", Type: TypeTag},
+			{Name: "v", Type: TypeVector, Vector: &VectorOptions{Algorithm: "FLAT", Type: "FLOAT32", Dim: 4, Distance: "L2"}},
+		},
+	}
+	for name, modify := range map[string]func(*Index){
+		"key type":     func(i *Index) { i.On = OnJSON },
+		"prefixes":     func(i *Index) { i.Prefixes = []string{"b:"} },
+		"filter":       func(i *Index) { i.Filter = "@x > 1" },
+		"language":     func(i *Index) { i.Language = "german" },
+		"drop field":   func(i *Index) { i.Fields = i.Fields[1:] },
+		"field order":  func(i *Index) { i.Fields[0], i.Fields[1] = i.Fields[1], i.Fields[0] },
+		"alias":        func(i *Index) { i.Fields[0].Alias = "t" },
+		"type":         func(i *Index) { i.Fields[0].Type = TypeTag },
+		"sortable":     func(i *Index) { i.Fields[0].Sortable = true },
+		"weight":       func(i *Index) { i.Fields[0].Weight = 2 },
+		"nostem":       func(i *Index) { i.Fields[0].NoStem = true },
+		"separator":    func(i *Index) { i.Fields[1].Separator = ";" },
+		"casesensitve": func(i *Index) { i.Fields[1].CaseSensitive = true },
+		"vector dim":   func(i *Index) { i.Fields[2].Vector.Dim = 8 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			to := &Index{Name: from.Name, Prefixes: from.Prefixes}
+			for _, f := range from.Fields {
+				c := *f
+				if f.Vector != nil {
+					v := *f.Vector
+					c.Vector = &v
+				}
+				to.Fields = append(to.Fields, &c)
+			}
+			modify(to)
+			require.Equal(t, []Change{&DropIndex{I: from}, &AddIndex{I: to}}, IndexDiff(from, to))
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InspectDatabase returns the search indexes of the deployment, as reported by FT._LIST and FT.INFO.
+func (d *Driver) InspectDatabase(ctx context.Context) (*Database, error) {
+	reply, err := d.Do(ctx, "FT._LIST")
+	if err != nil {
+		return nil, fmt.Errorf("redisearch: list indexes: %w", err)
+	}
+	names, err := stringsReply(reply)
+	if err != nil {
+		return nil, fmt.Errorf("redisearch: list indexes: %w", err)
+	}
+	sort.Strings(names)
+	db := &Database{}
+	for _, name := range names {
+		idx, err := d.InspectIndex(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		db.Indexes = append(db.Indexes, idx)
+	}
+	return db, nil
+}
+
+// InspectIndex returns the definition of the given index, as reported by FT.INFO.
+func (d *Driver) InspectIndex(ctx context.Context, name string) (*Index, error) {
+	reply, err := d.Do(ctx, "FT.INFO", name)
+	if err != nil {
+		return nil, fmt.Errorf("redisearch: inspect index %q: %w", name, err)
+	}
+	idx, err := decodeInfo(reply)
+	if err != nil {
+		return nil, fmt.Errorf("redisearch: decode index %q: %w", name, err)
+	}
+	if idx.Name == "" {
+		idx.Name = name
+	}
+	return idx, nil
+}
+
+// decodeInfo decodes the reply of FT.INFO into an Index.
+func decodeInfo(reply any) (*Index, error) {
+	info, err := mapReply(reply)
+	if err != nil {
+		return nil, err
+	}
+	idx := &Index{}
+	if v, ok := info["index_name"]; ok {
+		if idx.Name, err = stringReply(v); err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := info["index_definition"]; ok {
+		if err := decodeDefinition(idx, v); err != nil {
+			return nil, err
+		}
+	}
+	// Older versions of RediSearch report the fields under "fields".
+	attrs, ok := info["attributes"]
+	if !ok {
+		attrs = info["fields"]
+	}
+	if attrs == nil {
+		return idx, nil
+	}
+	list, ok := attrs.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected attributes reply: %T", attrs)
+	}
+	for _, a := range list {
+		f, err := decodeField(a)
+		if err != nil {
+			return nil, err
+		}
+		idx.Fields = append(idx.Fields, f)
+	}
+	return idx, nil
+}
+
+// decodeDefinition decodes the "index_definition" section of FT.INFO.
+func decodeDefinition(idx *Index, reply any) error {
+	def, err := mapReply(reply)
+	if err != nil {
+		return err
+	}
+	for k, v := range def {
+		switch k {
+		case "key_type":
+			if idx.On, err = stringReply(v); err != nil {
+				return err
+			}
+		case "prefixes":
+			if idx.Prefixes, err = stringsReply(v); err != nil {
+				return err
+			}
+		case "filter":
+			if idx.Filter, err = stringReply(v); err != nil {
+				return err
+			}
+		case "default_language":
+			if idx.Language, err = stringReply(v); err != nil {
+				return err
+			}
+		}
+	}
+	// An empty prefix matches all keys, and it is the default.
+	if len(idx.Prefixes) == 1 && idx.Prefixes[0] == "" {
+		idx.Prefixes = nil
+	}
+	return nil
+}
+
+// fieldFlags lists the field options that are reported by FT.INFO without a value.
+var fieldFlags = map[string]bool{
+	"SORTABLE":       true,
+	"UNF":            true,
+	"NOINDEX":        true,
+	"NOSTEM":         true,
+	"CASESENSITIVE":  true,
+	"WITHSUFFIXTRIE": true,
+	"INDEXEMPTY":     true,
+	"INDEXMISSING":   true,
+}
+
+// decodeField decodes a field (attribute) reported by FT.INFO. A field is reported as a flat list of
+// key-value pairs, followed by its flags. For example:
+//
+//	identifier $.title attribute title type TEXT WEIGHT 1 SORTABLE
+func decodeField(reply any) (*Field, error) {
+	list, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected attribute reply: %T", reply)
+	}
+	f := &Field{}
+	for i := 0; i < len(list); i++ {
+		k, err := stringReply(list[i])
+		if err != nil {
+			return nil, err
+		}
+		if fieldFlags[strings.ToUpper(k)] {
+			switch strings.ToUpper(k) {
+			case "SORTABLE":
+				f.Sortable = true
+			case "NOINDEX":
+				f.NoIndex = true
+			case "NOSTEM":
+				f.NoStem = true
+			case "CASESENSITIVE":
+				f.CaseSensitive = true
+			}
+			continue
+		}
+		if i++; i == len(list) {
+			return nil, fmt.Errorf("missing value for attribute option %q", k)
+		}
+		v, err := stringReply(list[i])
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(k) {
+		case "identifier":
+			f.Name = v
+		case "attribute":
+			f.Alias = v
+		case "type":
+			f.Type = strings.ToUpper(v)
+		case "weight":
+			if f.Weight, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("unexpected weight %q: %w", v, err)
+			}
+		case "separator":
+			f.Separator = v
+		case "algorithm":
+			f.vector().Algorithm = strings.ToUpper(v)
+		case "data_type":
+			f.vector().Type = strings.ToUpper(v)
+		case "distance_metric":
+			f.vector().Distance = strings.ToUpper(v)
+		case "dim":
+			if f.vector().Dim, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("unexpected dim %q: %w", v, err)
+			}
+		}
+	}
+	// The attribute name equals the identifier if no alias was set.
+	if f.Alias == f.Name {
+		f.Alias = ""
+	}
+	return f, nil
+}
+
+// vector returns the vector options of the field, and creates them if needed.
+func (f *Field) vector() *VectorOptions {
+	if f.Vector == nil {
+		f.Vector = &VectorOptions{}
+	}
+	return f.Vector
+}
+
+// mapReply converts a reply of key-value pairs into a map with lower-cased keys.
+// Both RESP2 arrays and RESP3 maps are accepted.
+func mapReply(reply any) (map[string]any, error) {
+	switch r := reply.(type) {
+	case []any:
+		if len(r)%2 != 0 {
+			return nil, fmt.Errorf("unexpected odd number of elements in map reply: %d", len(r))
+		}
+		m := make(map[string]any, len(r)/2)
+		for i := 0; i < len(r); i += 2 {
+			k, err := stringReply(r[i])
+			if err != nil {
+				return nil, err
+			}
+			m[strings.ToLower(k)] = r[i+1]
+		}
+		return m, nil
+	case map[string]any:
+		m := make(map[string]any, len(r))
+		for k, v := range r {
+			m[strings.ToLower(k)] = v
+		}
+		return m, nil
+	case map[any]any:
+		m := make(map[string]any, len(r))
+		for k, v := range r {
+			s, err := stringReply(k)
+			if err != nil {
+				return nil, err
+			}
+			m[strings.ToLower(s)] = v
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unexpected map reply: %T", reply)
+	}
+}
+
+// stringsReply converts an array reply into a list of strings.
+func stringsReply(reply any) ([]string, error) {
+	if reply == nil {
+		return nil, nil
+	}
+	list, ok := reply.([]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected array reply: %T", reply)
+	}
+	ss := make([]string, 0, len(list))
+	for _, v := range list {
+		s, err := stringReply(v)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, s)
+	}
+	return ss, nil
+}
+
+// stringReply converts a string (or number) reply into a string.
+func stringReply(reply any) (string, error) {
+	switch r := reply.(type) {
+	case string:
+		return r, nil
+	case []byte:
+		return string(r), nil
+	case int64:
+		return strconv.FormatInt(r, 10), nil
+	case float64:
+		return strconv.FormatFloat(r, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unexpected string reply: %T", reply)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockRunner is a Runner that expects a sequence of commands, and replies with their results.
+type mockRunner struct {
+	t     *testing.T
+	calls []mockCall
+}
+
+type mockCall struct {
+	args  string
+	reply any
+	err   error
+}
+
+func (m *mockRunner) expect(args string, reply any) *mockRunner {
+	m.calls = append(m.calls, mockCall{args: args, reply: reply})
+	return m
+}
+
+func (m *mockRunner) expectErr(args string, err error) *mockRunner {
+	m.calls = append(m.calls, mockCall{args: args, err: err})
+	return m
+}
+
+func (m *mockRunner) Do(_ context.Context, args ...any) (any, error) {
+	m.t.Helper()
+	cmd := make(Command, len(args))
+	for i, a := range args {
+		cmd[i] = fmt.Sprint(a)
+	}
+	require.NotEmpty(m.t, m.calls, "unexpected command: %s", cmd)
+	c := m.calls[0]
+	m.calls = m.calls[1:]
+	require.Equal(m.t, c.args, cmd.String())
+	return c.reply, c.err
+}
+
+func (m *mockRunner) done() {
+	require.Empty(m.t, m.calls, "expected commands were not executed")
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open(nil)
+	require.EqualError(t, err, "redisearch: no runner given")
+	drv, err := Open(RunnerFunc(func(context.Context, ...any) (any, error) { return "OK", nil }))
+	require.NoError(t, err)
+	r, err := drv.Do(context.Background(), "PING")
+	require.NoError(t, err)
+	require.Equal(t, "OK", r)
+}
+
+func TestDriver_InspectDatabase(t *testing.T) {
+	m := &mockRunner{t: t}
+	m.expect("FT._LIST", []any{"idx:users", "idx:docs"}).
+		expect("FT.INFO idx:docs", []any{
+			"index_name", "idx:docs",
+			"index_options", []any{},
+			"index_definition", []any{
+				"key_type", "JSON",
+				"prefixes", []any{"doc:", "post:"},
+				"filter", "@year > 2000",
+				"default_score", "1",
+			},
+			"attributes", []any{
+				[]any{"identifier", "$.title", "attribute", "title", "type", "TEXT", "WEIGHT", "2", "NOSTEM", "SORTABLE"},
+				[]any{"identifier", "$.embedding", "attribute", "embedding", "type", "VECTOR", "algorithm", "HNSW", "data_type", "FLOAT32", "dim", int64(128), "distance_metric", "COSINE", "M", int64(16)},
+			},
+			"num_docs", "10",
+		}).
+		expect("FT.INFO idx:users", []any{
+			"index_name", []byte("idx:users"),
+			"index_definition", []any{"key_type", "HASH", "prefixes", []any{""}, "default_score", "1"},
+			"attributes", []any{
+				[]any{"identifier", "name", "attribute", "name", "type", "TEXT", "WEIGHT", "1"},
+				[]any{"identifier", "tags", "attribute", "tags", "type", "TAG", "SEPARATOR", ";", "CASESENSITIVE"},
+				[]any{"identifier", "age", "attribute", "age", "type", "NUMERIC", "SORTABLE", "UNF", "NOINDEX"},
+			},
+		})
+	drv, err := Open(m)
+	require.NoError(t, err)
+	db, err := drv.InspectDatabase(context.Background())
+	require.NoError(t, err)
+	m.done()
+	require.Equal(t, &Database{
+		Indexes: []*Index{
+			{
+				Name:     "idx:docs",
+				On:       OnJSON,
+				Prefixes: []string{"doc:", "post:"},
+				Filter:   "@year > 2000",
+				Fields: []*Field{
+					{Name: "$.title", Alias: "title", Type: TypeText, Weight: 2, NoStem: true, Sortable: true},
+					{Name: "$.embedding", Alias: "embedding", Type: TypeVector, Vector: &VectorOptions{Algorithm: "HNSW", Type: "FLOAT32", Dim: 128, Distance: "COSINE"}},
+				},
+			},
+			{
+				Name: "idx:users",
+				On:   OnHash,
+				Fields: []*Field{
+					{Name: "name", Type: TypeText, Weight: 1},
+					{Name: "tags", Type: TypeTag, Separator: ";", CaseSensitive: true},
+					{Name: "age", Type: TypeNumeric, Sortable: true, NoIndex: true},
+				},
+			},
+		},
+	}, db)
+}
+
+func TestDriver_InspectIndex(t *testing.T) {
+	m := &mockRunner{t: t}
+	m.expect("FT.INFO idx", map[any]any{
+		"index_name":       "idx",
+		"index_definition": map[any]any{"key_type": "HASH", "prefixes": []any{"a:"}},
+		"attributes": []any{
+			[]any{"identifier", "title", "attribute", "t", "type", "TEXT"},
+		},
+	}).
+		expectErr("FT.INFO unknown", errors.New("Unknown index name")).
+		expect("FT.INFO broken", []any{"index_name"})
+	drv, err := Open(m)
+	require.NoError(t, err)
+	idx, err := drv.InspectIndex(context.Background(), "idx")
+	require.NoError(t, err)
+	require.Equal(t, &Index{
+		Name:     "idx",
+		On:       OnHash,
+		Prefixes: []string{"a:"},
+		Fields:   []*Field{{Name: "title", Alias: "t", Type: TypeText}},
+	}, idx)
+	_, err = drv.InspectIndex(context.Background(), "unknown")
+	require.EqualError(t, err, `redisearch: inspect index "unknown": Unknown index name`)
+	_, err = drv.InspectIndex(context.Background(), "broken")
+	require.EqualError(t, err, `redisearch: decode index "broken": unexpected odd number of elements in map reply: 1`)
+	m.done()
+}
@@ -0,0 +1,228 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+)
+
+// Command holds the arguments of a Redis command. For example:
+//
+//	Command{"FT.DROPINDEX", "idx:users"}
+type Command []string
+
+// String formats the command as a single line, as accepted by redis-cli. Arguments
+// that contain whitespace, quotes, or characters that may be parsed as statement
+// delimiters or comments in migration files, are double-quoted.
+func (c Command) String() string {
+	args := make([]string, len(c))
+	for i, a := range c {
+		if a == "" || strings.ContainsAny(a, " \t\r\n\"'\\;#") || strings.Contains(a, "--") || strings.Contains(a, "/*") {
+			a = strconv.Quote(a)
+		}
+		args[i] = a
+	}
+	return strings.Join(args, " ")
+}
+
+// planned is a planned change, along with its command and the commands that reverse it.
+type planned struct {
+	cmd     Command
+	rev     []Command
+	comment string
+}
+
+// PlanChanges returns a migration plan for applying the given changes. Each change is
+// planned as a single Redis command, formatted as it is accepted by redis-cli.
+// As dropping an index does not delete its documents, all plans are reversible.
+func (d *Driver) PlanChanges(name string, changes []Change) (*migrate.Plan, error) {
+	ps, err := plan(changes)
+	if err != nil {
+		return nil, err
+	}
+	p := &migrate.Plan{
+		Name:       name,
+		Reversible: true,
+		// Redis commands are not executed in transactions.
+		Transactional: false,
+	}
+	for _, c := range ps {
+		mc := &migrate.Change{Cmd: c.cmd.String(), Comment: c.comment}
+		if len(c.rev) == 1 {
+			mc.Reverse = c.rev[0].String()
+		} else {
+			rev := make([]string, len(c.rev))
+			for i := range c.rev {
+				rev[i] = c.rev[i].String()
+			}
+			mc.Reverse = rev
+		}
+		p.Changes = append(p.Changes, mc)
+	}
+	return p, nil
+}
+
+// ApplyChanges applies the changes on the Redis deployment.
+func (d *Driver) ApplyChanges(ctx context.Context, changes []Change) error {
+	ps, err := plan(changes)
+	if err != nil {
+		return err
+	}
+	for _, c := range ps {
+		args := make([]any, len(c.cmd))
+		for i, a := range c.cmd {
+			args[i] = a
+		}
+		if _, err := d.Do(ctx, args...); err != nil {
+			return fmt.Errorf("%s: %w", c.comment, err)
+		}
+	}
+	return nil
+}
+
+// WritePlan writes the given plan as a new migration file to the directory,
+// and updates its sum file. The files are formatted using the default formatter
+// of the sql/migrate package, one command per statement.
+func WritePlan(dir migrate.Dir, p *migrate.Plan) error {
+	return migrate.NewPlanner(nil, dir).WritePlan(p)
+}
+
+// plan returns the commands of the given changes.
+func plan(changes []Change) ([]*planned, error) {
+	ps := make([]*planned, 0, len(changes))
+	for _, c := range changes {
+		var p *planned
+		switch c := c.(type) {
+		case *AddIndex:
+			create, err := createCmd(c.I)
+			if err != nil {
+				return nil, err
+			}
+			p = &planned{
+				cmd:     create,
+				rev:     []Command{dropCmd(c.I)},
+				comment: fmt.Sprintf("create index %q", c.I.Name),
+			}
+		case *DropIndex:
+			create, err := createCmd(c.I)
+			if err != nil {
+				return nil, err
+			}
+			p = &planned{
+				cmd:     dropCmd(c.I),
+				rev:     []Command{create},
+				comment: fmt.Sprintf("drop index %q", c.I.Name),
+			}
+		case *AddFields:
+			alter := Command{"FT.ALTER", c.To.Name, "SCHEMA", "ADD"}
+			for _, f := range c.Fields {
+				args, err := fieldArgs(f)
+				if err != nil {
+					return nil, fmt.Errorf("redisearch: index %q: %w", c.To.Name, err)
+				}
+				alter = append(alter, args...)
+			}
+			// Fields cannot be removed from an index. Hence,
+			// the reverse of FT.ALTER recreates the index.
+			create, err := createCmd(c.From)
+			if err != nil {
+				return nil, err
+			}
+			p = &planned{
+				cmd:     alter,
+				rev:     []Command{dropCmd(c.From), create},
+				comment: fmt.Sprintf("add fields to index %q", c.To.Name),
+			}
+		default:
+			return nil, fmt.Errorf("redisearch: unsupported change %T", c)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+// createCmd returns the FT.CREATE command of the index.
+func createCmd(idx *Index) (Command, error) {
+	if len(idx.Fields) == 0 {
+		return nil, fmt.Errorf("redisearch: index %q has no fields", idx.Name)
+	}
+	cmd := Command{"FT.CREATE", idx.Name, "ON", strings.ToUpper(defaultString(idx.On, OnHash))}
+	if len(idx.Prefixes) > 0 {
+		cmd = append(cmd, "PREFIX", strconv.Itoa(len(idx.Prefixes)))
+		cmd = append(cmd, idx.Prefixes...)
+	}
+	if idx.Filter != "" {
+		cmd = append(cmd, "FILTER", idx.Filter)
+	}
+	if idx.Language != "" {
+		cmd = append(cmd, "LANGUAGE", idx.Language)
+	}
+	cmd = append(cmd, "SCHEMA")
+	for _, f := range idx.Fields {
+		args, err := fieldArgs(f)
+		if err != nil {
+			return nil, fmt.Errorf("redisearch: index %q: %w", idx.Name, err)
+		}
+		cmd = append(cmd, args...)
+	}
+	return cmd, nil
+}
+
+// dropCmd returns the FT.DROPINDEX command of the index. The documents
+// of the index are kept, as the DD option is not used.
+func dropCmd(idx *Index) Command {
+	return Command{"FT.DROPINDEX", idx.Name}
+}
+
+// fieldArgs returns the arguments that define the field in FT.CREATE and FT.ALTER.
+func fieldArgs(f *Field) (Command, error) {
+	args := Command{f.Name}
+	if f.Alias != "" && f.Alias != f.Name {
+		args = append(args, "AS", f.Alias)
+	}
+	t := strings.ToUpper(f.Type)
+	args = append(args, t)
+	switch t {
+	case TypeText:
+		if f.NoStem {
+			args = append(args, "NOSTEM")
+		}
+		if f.Weight != 0 && f.Weight != 1 {
+			args = append(args, "WEIGHT", strconv.FormatFloat(f.Weight, 'f', -1, 64))
+		}
+	case TypeTag:
+		if f.Separator != "" && f.Separator != "," {
+			args = append(args, "SEPARATOR", f.Separator)
+		}
+		if f.CaseSensitive {
+			args = append(args, "CASESENSITIVE")
+		}
+	case TypeVector:
+		v := f.Vector
+		if v == nil || v.Algorithm == "" || v.Type == "" || v.Dim <= 0 || v.Distance == "" {
+			return nil, fmt.Errorf("vector field %q requires an algorithm, type, dim and distance", f.Name)
+		}
+		args = append(args, strings.ToUpper(v.Algorithm), "6",
+			"TYPE", strings.ToUpper(v.Type),
+			"DIM", strconv.Itoa(v.Dim),
+			"DISTANCE_METRIC", strings.ToUpper(v.Distance),
+		)
+	case TypeNumeric, TypeGeo, TypeGeoShape:
+	default:
+		return nil, fmt.Errorf("unknown type %q for field %q", f.Type, f.Name)
+	}
+	if f.Sortable {
+		args = append(args, "SORTABLE")
+	}
+	if f.NoIndex {
+		args = append(args, "NOINDEX")
+	}
+	return args, nil
+}
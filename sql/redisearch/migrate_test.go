@@ -0,0 +1,114 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_String(t *testing.T) {
+	require.Equal(t, `FT.CREATE idx FILTER "@age > 18" SEPARATOR ";" "" "a\"b" "--" "#"`, Command{"FT.CREATE", "idx", "FILTER", "@age > 18", "SEPARATOR", ";", "", `a"b`, "--", "#"}.String())
+}
+
+func TestDriver_PlanChanges(t *testing.T) {
+	users := &Index{
+		Name:     "idx:users",
+		Prefixes: []string{"user:", "member:"},
+		Filter:   "@age > 18",
+		Fields: []*Field{
+			{Name: "name", Type: TypeText, Weight: 2, NoStem: true, Sortable: true},
+			{Name: "tags", Type: TypeTag, Separator: ";", CaseSensitive: true},
+			{Name: "age", Type: TypeNumeric, NoIndex: true},
+		},
+	}
+	docs := &Index{
+		Name:     "idx:docs",
+		On:       OnJSON,
+		Language: "german",
+		Fields: []*Field{
+			{Name: "$.title", Alias: "title", Type: TypeText},
+			{Name: "$.embedding", Alias: "embedding", Type: TypeVector, Vector: &VectorOptions{Algorithm: "hnsw", Type: "float32", Dim: 128, Distance: "cosine"}},
+		},
+	}
+	added := &Index{Name: users.Name, Prefixes: users.Prefixes, Filter: users.Filter, Fields: append(users.Fields[:3:3], &Field{Name: "loc", Type: TypeGeo})}
+	drv, err := Open(&mockRunner{t: t})
+	require.NoError(t, err)
+	p, err := drv.PlanChanges("plan", []Change{
+		&AddIndex{I: users},
+		&DropIndex{I: docs},
+		&AddFields{From: users, To: added, Fields: added.Fields[3:]},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "plan", p.Name)
+	require.True(t, p.Reversible)
+	require.False(t, p.Transactional)
+	createUsers := `FT.CREATE idx:users ON HASH PREFIX 2 user: member: FILTER "@age > 18" SCHEMA name TEXT NOSTEM WEIGHT 2 SORTABLE tags TAG SEPARATOR ";" CASESENSITIVE age NUMERIC NOINDEX`
+	require.Equal(t, []*migrate.Change{
+		{
+			Cmd:     createUsers,
+			Reverse: "FT.DROPINDEX idx:users",
+			Comment: `create index "idx:users"`,
+		},
+		{
+			Cmd:     "FT.DROPINDEX idx:docs",
+			Reverse: "FT.CREATE idx:docs ON JSON LANGUAGE german SCHEMA $.title AS title TEXT $.embedding AS embedding VECTOR HNSW 6 TYPE FLOAT32 DIM 128 DISTANCE_METRIC COSINE",
+			Comment: `drop index "idx:docs"`,
+		},
+		{
+			Cmd:     "FT.ALTER idx:users SCHEMA ADD loc GEO",
+			Reverse: []string{"FT.DROPINDEX idx:users", createUsers},
+			Comment: `add fields to index "idx:users"`,
+		},
+	}, p.Changes)
+
+	_, err = drv.PlanChanges("plan", []Change{&AddIndex{I: &Index{Name: "empty"}}})
+	require.EqualError(t, err, `redisearch: index "empty" has no fields`)
+	_, err = drv.PlanChanges("plan", []Change{&AddIndex{I: &Index{Name: "idx", Fields: []*Field{{Name: "f", Type: "UNKNOWN"}}}}})
+	require.EqualError(t, err, `redisearch: index "idx": unknown type "UNKNOWN" for field "f"`)
+	_, err = drv.PlanChanges("plan", []Change{&AddIndex{I: &Index{Name: "idx", Fields: []*Field{{Name: "v", Type: TypeVector}}}}})
+	require.EqualError(t, err, `redisearch: index "idx": vector field "v" requires an algorithm, type, dim and distance`)
+}
+
+func TestDriver_ApplyChanges(t *testing.T) {
+	idx := &Index{Name: "idx", Prefixes: []string{"doc:"}, Fields: []*Field{{Name: "title", Type: TypeText}}}
+	m := &mockRunner{t: t}
+	m.expect("FT.DROPINDEX old", "OK").
+		expect("FT.CREATE idx ON HASH PREFIX 1 doc: SCHEMA title TEXT", "OK").
+		expectErr("FT.ALTER idx SCHEMA ADD body TEXT", errors.New("Index already exists"))
+	drv, err := Open(m)
+	require.NoError(t, err)
+	err = drv.ApplyChanges(context.Background(), []Change{
+		&DropIndex{I: &Index{Name: "old", Fields: []*Field{{Name: "a", Type: TypeTag}}}},
+		&AddIndex{I: idx},
+		&AddFields{From: idx, To: idx, Fields: []*Field{{Name: "body", Type: TypeText}}},
+	})
+	require.EqualError(t, err, `add fields to index "idx": Index already exists`)
+	m.done()
+}
+
+func TestWritePlan(t *testing.T) {
+	dir, err := migrate.NewLocalDir(t.TempDir())
+	require.NoError(t, err)
+	drv, err := Open(&mockRunner{t: t})
+	require.NoError(t, err)
+	p, err := drv.PlanChanges("init", []Change{
+		&AddIndex{I: &Index{Name: "idx", Filter: "@a == 'b'", Fields: []*Field{{Name: "a", Type: TypeTag, Separator: ";"}}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, WritePlan(dir, p))
+	files, err := dir.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, "-- Create index \"idx\"\nFT.CREATE idx ON HASH FILTER \"@a == 'b'\" SCHEMA a TAG SEPARATOR \";\";\n", string(files[0].Bytes()))
+	stmts, err := files[0].Stmts()
+	require.NoError(t, err)
+	require.Equal(t, []string{"FT.CREATE idx ON HASH FILTER \"@a == 'b'\" SCHEMA a TAG SEPARATOR \";\";"}, stmts)
+}
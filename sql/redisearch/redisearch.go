@@ -0,0 +1,122 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package redisearch manages RediSearch index definitions of Redis (or Valkey) deployments
+// declaratively. It inspects the existing indexes, diffs them against a desired state defined
+// in HCL, and plans the changes as FT.CREATE, FT.ALTER and FT.DROPINDEX commands.
+package redisearch
+
+import (
+	"context"
+	"errors"
+)
+
+type (
+	// Database describes the search indexes of a Redis deployment.
+	Database struct {
+		Indexes []*Index
+	}
+
+	// Index describes a search index, as defined by FT.CREATE.
+	Index struct {
+		Name     string
+		On       string   // HASH (default) or JSON.
+		Prefixes []string // Key prefixes to index.
+		Filter   string   // Filter expression. For example, "@age > 18".
+		Language string   // Default language of documents. For example, "english".
+		Fields   []*Field
+	}
+
+	// Field describes a field of the index schema.
+	Field struct {
+		// Name of the field. For JSON indexes, a JSONPath
+		// expression. For example, "$.user.name".
+		Name          string
+		Alias         string // Attribute name (AS).
+		Type          string // TEXT, TAG, NUMERIC, GEO, GEOSHAPE or VECTOR.
+		Sortable      bool
+		NoIndex       bool
+		NoStem        bool    // TEXT fields only.
+		Weight        float64 // TEXT fields only. Zero means the default (1).
+		Separator     string  // TAG fields only. Empty means the default (",").
+		CaseSensitive bool    // TAG fields only.
+		Vector        *VectorOptions
+	}
+
+	// VectorOptions describes the options of VECTOR fields.
+	VectorOptions struct {
+		Algorithm string // FLAT or HNSW.
+		Type      string // Data type. For example, FLOAT32.
+		Dim       int    // Number of dimensions.
+		Distance  string // Distance metric. L2, IP or COSINE.
+	}
+
+	// A Runner runs Redis commands and returns their replies. Replies are expected in their RESP2
+	// form: strings as string (or []byte), integers as int64, arrays as []any and null replies as
+	// nil. For example, using go-redis configured with protocol version 2:
+	//
+	//	redisearch.RunnerFunc(func(ctx context.Context, args ...any) (any, error) {
+	//		return client.Do(ctx, args...).Result()
+	//	})
+	Runner interface {
+		Do(ctx context.Context, args ...any) (any, error)
+	}
+
+	// RunnerFunc allows using an ordinary function as a Runner.
+	RunnerFunc func(ctx context.Context, args ...any) (any, error)
+
+	// Driver manages the search indexes of a Redis deployment.
+	Driver struct {
+		Runner
+	}
+)
+
+// List of index types.
+const (
+	OnHash = "HASH"
+	OnJSON = "JSON"
+)
+
+// List of field types.
+const (
+	TypeText     = "TEXT"
+	TypeTag      = "TAG"
+	TypeNumeric  = "NUMERIC"
+	TypeGeo      = "GEO"
+	TypeGeoShape = "GEOSHAPE"
+	TypeVector   = "VECTOR"
+)
+
+// Do calls f(ctx, args...).
+func (f RunnerFunc) Do(ctx context.Context, args ...any) (any, error) {
+	return f(ctx, args...)
+}
+
+// Open returns a new Driver that runs its commands using the given Runner.
+func Open(r Runner) (*Driver, error) {
+	if r == nil {
+		return nil, errors.New("redisearch: no runner given")
+	}
+	return &Driver{Runner: r}, nil
+}
+
+// Index returns the first index that matched the given name.
+func (d *Database) Index(name string) (*Index, bool) {
+	for _, idx := range d.Indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return nil, false
+}
+
+// Field returns the first field that matched the given name.
+func (i *Index) Field(name string) (*Field, bool) {
+	for _, f := range i.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,250 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+type (
+	// doc holds the HCL document of the search indexes. For example:
+	//
+	//	index "idx:users" {
+	//	  on     = HASH
+	//	  prefix = ["user:"]
+	//	  field "name" {
+	//	    type     = TEXT
+	//	    sortable = true
+	//	  }
+	//	  field "tags" {
+	//	    type      = TAG
+	//	    separator = ";"
+	//	  }
+	//	  field "embedding" {
+	//	    type = VECTOR
+	//	    vector {
+	//	      algorithm = HNSW
+	//	      type      = FLOAT32
+	//	      dim       = 128
+	//	      distance  = COSINE
+	//	    }
+	//	  }
+	//	}
+	doc struct {
+		Indexes []*indexSpec `spec:"index"`
+	}
+
+	// indexSpec holds a specification for a search index.
+	indexSpec struct {
+		Name     string       `spec:",name"`
+		Prefixes []string     `spec:"prefix,omitempty"`
+		Filter   string       `spec:"filter,omitempty"`
+		Language string       `spec:"language,omitempty"`
+		Fields   []*fieldSpec `spec:"field"`
+		schemahcl.DefaultExtension
+	}
+
+	// fieldSpec holds a specification for a field of an index.
+	fieldSpec struct {
+		Name          string        `spec:",name"`
+		Alias         string        `spec:"as,omitempty"`
+		Sortable      bool          `spec:"sortable,omitempty"`
+		NoIndex       bool          `spec:"no_index,omitempty"`
+		NoStem        bool          `spec:"no_stem,omitempty"`
+		Separator     string        `spec:"separator,omitempty"`
+		CaseSensitive bool          `spec:"case_sensitive,omitempty"`
+		Vector        []*vectorSpec `spec:"vector"`
+		schemahcl.DefaultExtension
+	}
+
+	// vectorSpec holds a specification for the options of a vector field.
+	vectorSpec struct {
+		Dim int `spec:"dim"`
+		schemahcl.DefaultExtension
+	}
+)
+
+// state is the HCL state used for evaluating and marshaling search indexes documents.
+var state = schemahcl.New(
+	schemahcl.WithScopedEnums("index.on", OnHash, OnJSON),
+	schemahcl.WithScopedEnums("index.field.type", TypeText, TypeTag, TypeNumeric, TypeGeo, TypeGeoShape, TypeVector),
+	schemahcl.WithScopedEnums("index.field.vector.algorithm", "FLAT", "HNSW"),
+	schemahcl.WithScopedEnums("index.field.vector.type", "FLOAT16", "FLOAT32", "FLOAT64", "BFLOAT16"),
+	schemahcl.WithScopedEnums("index.field.vector.distance", "L2", "IP", "COSINE"),
+)
+
+// EvalHCLBytes evaluates the HCL document of the search indexes into db.
+func EvalHCLBytes(b []byte, db *Database, input map[string]cty.Value) error {
+	var d doc
+	if err := state.EvalBytes(b, &d, input); err != nil {
+		return err
+	}
+	for _, is := range d.Indexes {
+		idx, err := convertIndex(is)
+		if err != nil {
+			return err
+		}
+		db.Indexes = append(db.Indexes, idx)
+	}
+	return validate(db)
+}
+
+// EvalJSONBytes decodes the JSON document of the search indexes into db. For example:
+//
+//	{"indexes": [{"name": "idx:users", "fields": [{"name": "name", "type": "TEXT"}]}]}
+func EvalJSONBytes(b []byte, db *Database) error {
+	var v struct {
+		Indexes []*Index `json:"indexes"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("redisearch: decode indexes: %w", err)
+	}
+	db.Indexes = append(db.Indexes, v.Indexes...)
+	return validate(db)
+}
+
+// MarshalHCL marshals the search indexes into an HCL document.
+func MarshalHCL(db *Database) ([]byte, error) {
+	d := &doc{}
+	for _, idx := range db.Indexes {
+		d.Indexes = append(d.Indexes, indexToSpec(idx))
+	}
+	return state.MarshalSpec(d)
+}
+
+// convertIndex converts an indexSpec into an Index.
+func convertIndex(spec *indexSpec) (*Index, error) {
+	idx := &Index{
+		Name:     spec.Name,
+		Prefixes: spec.Prefixes,
+		Filter:   spec.Filter,
+		Language: spec.Language,
+	}
+	var err error
+	if idx.On, err = enumAttr(&spec.DefaultExtension, "on"); err != nil {
+		return nil, fmt.Errorf("redisearch: index %q: %w", spec.Name, err)
+	}
+	for _, fs := range spec.Fields {
+		f := &Field{
+			Name:          fs.Name,
+			Alias:         fs.Alias,
+			Sortable:      fs.Sortable,
+			NoIndex:       fs.NoIndex,
+			NoStem:        fs.NoStem,
+			Separator:     fs.Separator,
+			CaseSensitive: fs.CaseSensitive,
+		}
+		if f.Type, err = enumAttr(&fs.DefaultExtension, "type"); err != nil {
+			return nil, fmt.Errorf("redisearch: index %q: field %q: %w", spec.Name, fs.Name, err)
+		}
+		if a, ok := fs.Attr("weight"); ok {
+			if f.Weight, err = a.Float64(); err != nil {
+				return nil, fmt.Errorf("redisearch: index %q: field %q: %w", spec.Name, fs.Name, err)
+			}
+		}
+		switch n := len(fs.Vector); {
+		case n > 1:
+			return nil, fmt.Errorf("redisearch: index %q: field %q: multiple vector blocks", spec.Name, fs.Name)
+		case n == 1:
+			vs := fs.Vector[0]
+			f.Vector = &VectorOptions{Dim: vs.Dim}
+			for k, v := range map[string]*string{"algorithm": &f.Vector.Algorithm, "type": &f.Vector.Type, "distance": &f.Vector.Distance} {
+				if *v, err = enumAttr(&vs.DefaultExtension, k); err != nil {
+					return nil, fmt.Errorf("redisearch: index %q: field %q: vector: %w", spec.Name, fs.Name, err)
+				}
+			}
+		}
+		idx.Fields = append(idx.Fields, f)
+	}
+	return idx, nil
+}
+
+// indexToSpec converts an Index into an indexSpec.
+func indexToSpec(idx *Index) *indexSpec {
+	spec := &indexSpec{
+		Name:     idx.Name,
+		Prefixes: idx.Prefixes,
+		Filter:   idx.Filter,
+		Language: idx.Language,
+	}
+	if idx.On != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("on", strings.ToUpper(idx.On)))
+	}
+	for _, f := range idx.Fields {
+		fs := &fieldSpec{
+			Name:          f.Name,
+			Alias:         f.Alias,
+			Sortable:      f.Sortable,
+			NoIndex:       f.NoIndex,
+			NoStem:        f.NoStem,
+			Separator:     f.Separator,
+			CaseSensitive: f.CaseSensitive,
+		}
+		fs.Extra.Attrs = append(fs.Extra.Attrs, specutil.VarAttr("type", strings.ToUpper(f.Type)))
+		if f.Weight != 0 && f.Weight != 1 {
+			fs.Extra.Attrs = append(fs.Extra.Attrs, schemahcl.Float64Attr("weight", f.Weight))
+		}
+		if v := f.Vector; v != nil {
+			vs := &vectorSpec{Dim: v.Dim}
+			vs.Extra.Attrs = append(vs.Extra.Attrs,
+				specutil.VarAttr("algorithm", strings.ToUpper(v.Algorithm)),
+				specutil.VarAttr("type", strings.ToUpper(v.Type)),
+				specutil.VarAttr("distance", strings.ToUpper(v.Distance)),
+			)
+			fs.Vector = append(fs.Vector, vs)
+		}
+		spec.Fields = append(spec.Fields, fs)
+	}
+	return spec
+}
+
+// enumAttr returns the value of an enum attribute, or an empty string if it is not set.
+func enumAttr(ext *schemahcl.DefaultExtension, name string) (string, error) {
+	a, ok := ext.Attr(name)
+	if !ok {
+		return "", nil
+	}
+	s, err := a.String()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(s), nil
+}
+
+// validate checks that the indexes and their fields are named and unique.
+func validate(db *Database) error {
+	names := make(map[string]bool, len(db.Indexes))
+	for _, idx := range db.Indexes {
+		switch {
+		case idx.Name == "":
+			return fmt.Errorf("redisearch: missing index name")
+		case names[idx.Name]:
+			return fmt.Errorf("redisearch: index %q was defined more than once", idx.Name)
+		case len(idx.Fields) == 0:
+			return fmt.Errorf("redisearch: index %q has no fields", idx.Name)
+		}
+		names[idx.Name] = true
+		fields := make(map[string]bool, len(idx.Fields))
+		for _, f := range idx.Fields {
+			switch {
+			case f.Name == "":
+				return fmt.Errorf("redisearch: index %q: missing field name", idx.Name)
+			case fields[f.Name]:
+				return fmt.Errorf("redisearch: index %q: field %q was defined more than once", idx.Name, f.Name)
+			case f.Type == "":
+				return fmt.Errorf("redisearch: index %q: field %q has no type", idx.Name, f.Name)
+			}
+			fields[f.Name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package redisearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalHCLBytes(t *testing.T) {
+	var db Database
+	err := EvalHCLBytes([]byte(`
+index "idx:users" {
+  prefix = ["user:"]
+  filter = "@age > 18"
+  field "name" {
+    type     = TEXT
+    sortable = true
+    weight   = 2.5
+    no_stem  = true
+  }
+  field "tags" {
+    type           = TAG
+    separator      = ";"
+    case_sensitive = true
+  }
+}
+
+index "idx:docs" {
+  on       = JSON
+  language = "german"
+  field "$.title" {
+    as   = "title"
+    type = TEXT
+  }
+  field "$.embedding" {
+    as   = "embedding"
+    type = VECTOR
+    vector {
+      algorithm = HNSW
+      type      = FLOAT32
+      dim       = 128
+      distance  = COSINE
+    }
+  }
+}
+`), &db, nil)
+	require.NoError(t, err)
+	require.Equal(t, Database{
+		Indexes: []*Index{
+			{
+				Name:     "idx:users",
+				Prefixes: []string{"user:"},
+				Filter:   "@age > 18",
+				Fields: []*Field{
+					{Name: "name", Type: TypeText, Sortable: true, Weight: 2.5, NoStem: true},
+					{Name: "tags", Type: TypeTag, Separator: ";", CaseSensitive: true},
+				},
+			},
+			{
+				Name:     "idx:docs",
+				On:       OnJSON,
+				Language: "german",
+				Fields: []*Field{
+					{Name: "$.title", Alias: "title", Type: TypeText},
+					{Name: "$.embedding", Alias: "embedding", Type: TypeVector, Vector: &VectorOptions{Algorithm: "HNSW", Type: "FLOAT32", Dim: 128, Distance: "COSINE"}},
+				},
+			},
+		},
+	}, db)
+
+	for src, msg := range map[string]string{
+		`index "a" {}`: `redisearch: index "a" has no fields`,
+		`index "a" {
+  field "f" {}
+}`: `redisearch: index "a": field "f" has no type`,
+		`index "a" {
+  field "f" {
+    type = TAG
+  }
+  field "f" {
+    type = TAG
+  }
+}`: `redisearch: index "a": field "f" was defined more than once`,
+	} {
+		require.EqualError(t, EvalHCLBytes([]byte(src), &Database{}, nil), msg)
+	}
+}
+
+func TestEvalJSONBytes(t *testing.T) {
+	var db Database
+	err := EvalJSONBytes([]byte(`{"indexes": [{"name": "idx", "on": "JSON", "prefixes": ["doc:"], "fields": [{"name": "$.title", "alias": "title", "type": "TEXT", "sortable": true}]}]}`), &db)
+	require.NoError(t, err)
+	require.Equal(t, Database{
+		Indexes: []*Index{{
+			Name:     "idx",
+			On:       OnJSON,
+			Prefixes: []string{"doc:"},
+			Fields:   []*Field{{Name: "$.title", Alias: "title", Type: TypeText, Sortable: true}},
+		}},
+	}, db)
+	require.EqualError(t, EvalJSONBytes([]byte(`{"indexes": [{"name": "idx"}, {"name": "idx"}]}`), &Database{}), `redisearch: index "idx" has no fields`)
+	require.Error(t, EvalJSONBytes([]byte(`{`), &Database{}))
+}
+
+func TestMarshalHCL(t *testing.T) {
+	db := &Database{
+		Indexes: []*Index{
+			{
+				Name:     "idx:docs",
+				On:       OnJSON,
+				Prefixes: []string{"doc:"},
+				Fields: []*Field{
+					{Name: "$.title", Alias: "title", Type: TypeText, Weight: 2, Sortable: true},
+					{Name: "$.tags", Type: TypeTag, Separator: ";"},
+					{Name: "$.v", Type: TypeVector, Vector: &VectorOptions{Algorithm: "FLAT", Type: "FLOAT32", Dim: 4, Distance: "L2"}},
+				},
+			},
+		},
+	}
+	b, err := MarshalHCL(db)
+	require.NoError(t, err)
+	require.Equal(t, `index "idx:docs" {
+  prefix = ["doc:"]
+  on     = JSON
+  field "$.title" {
+    as       = "title"
+    sortable = true
+    type     = TEXT
+    weight   = 2
+  }
+  field "$.tags" {
+    separator = ";"
+    type      = TAG
+  }
+  field "$.v" {
+    type = VECTOR
+    vector {
+      dim       = 4
+      algorithm = FLAT
+      type      = FLOAT32
+      distance  = L2
+    }
+  }
+}
+`, string(b))
+	var got Database
+	require.NoError(t, EvalHCLBytes(b, &got, nil))
+	require.Equal(t, db, &got)
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// A Classification describes the sensitivity of the data that is stored in a column.
+// Custom classifications are supported as long as they are lowercase identifiers.
+type Classification string
+
+// List of common data classifications.
+const (
+	ClassPublic Classification = "public" // Data that can be exposed publicly.
+	ClassPII    Classification = "pii"    // Personally identifiable information.
+	ClassSecret Classification = "secret" // Credentials, tokens and keys.
+)
+
+// Classifications are stored in the column comments, as a trailing marker, to
+// allow them to survive inspection. For example, "email [classification:pii]".
+var (
+	classNameRe   = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	classMarkerRe = regexp.MustCompile(`(?:^|\s+)\[classification:([a-z][a-z0-9_-]*)\]$`)
+)
+
+// Valid reports if the classification is a valid classification name.
+func (c Classification) Valid() bool {
+	return classNameRe.MatchString(string(c))
+}
+
+// ClassifiedComment returns the text of a comment that holds the given
+// text and classification. An empty classification returns the text.
+func ClassifiedComment(text string, c Classification) string {
+	if c == "" {
+		return text
+	}
+	m := fmt.Sprintf("[classification:%s]", c)
+	if text == "" {
+		return m
+	}
+	return text + " " + m
+}
+
+// CommentClassification splits the comment into its text and its classification. If
+// the comment does not hold a classification, the text is returned as-is.
+func CommentClassification(comment string) (string, Classification) {
+	loc := classMarkerRe.FindStringSubmatchIndex(comment)
+	if loc == nil {
+		return comment, ""
+	}
+	return comment[:loc[0]], Classification(comment[loc[2]:loc[3]])
+}
+
+// ColumnClassification returns the classification of the column, if it was classified.
+func ColumnClassification(c *Column) (Classification, bool) {
+	var cm Comment
+	for _, a := range c.Attrs {
+		if a, ok := a.(*Comment); ok {
+			cm = *a
+		}
+	}
+	_, class := CommentClassification(cm.Text)
+	return class, class != ""
+}
+
+// SetClassification sets the classification of the column, by
+// setting or appending its comment with the classification marker.
+// An empty classification removes the marker from the comment.
+func (c *Column) SetClassification(class Classification) *Column {
+	var text string
+	for _, a := range c.Attrs {
+		if a, ok := a.(*Comment); ok {
+			text, _ = CommentClassification(a.Text)
+		}
+	}
+	if text == "" && class == "" {
+		c.Attrs = RemoveAttr[*Comment](c.Attrs)
+		return c
+	}
+	return c.SetComment(ClassifiedComment(text, class))
+}
+
+// ClassifiedColumns returns the columns of the table
+// that are classified with one of the given classes.
+func ClassifiedColumns(t *Table, classes ...Classification) []*Column {
+	var cs []*Column
+	for _, c := range t.Columns {
+		if class, ok := ColumnClassification(c); ok && (len(classes) == 0 || slices.Contains(classes, class)) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
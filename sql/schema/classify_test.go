@@ -0,0 +1,57 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassification(t *testing.T) {
+	require.True(t, schema.ClassPII.Valid())
+	require.True(t, schema.Classification("financial_data").Valid())
+	require.False(t, schema.Classification("PII").Valid())
+	require.False(t, schema.Classification("").Valid())
+
+	require.Equal(t, "email [classification:pii]", schema.ClassifiedComment("email", schema.ClassPII))
+	require.Equal(t, "[classification:secret]", schema.ClassifiedComment("", schema.ClassSecret))
+	require.Equal(t, "email", schema.ClassifiedComment("email", ""))
+	text, class := schema.CommentClassification("user email [classification:pii]")
+	require.Equal(t, "user email", text)
+	require.Equal(t, schema.ClassPII, class)
+	text, class = schema.CommentClassification("[classification:secret]")
+	require.Empty(t, text)
+	require.Equal(t, schema.ClassSecret, class)
+	text, class = schema.CommentClassification("see [classification:pii] docs")
+	require.Equal(t, "see [classification:pii] docs", text)
+	require.Empty(t, class)
+
+	email := schema.NewStringColumn("email", "text").SetComment("user email")
+	_, ok := schema.ColumnClassification(email)
+	require.False(t, ok)
+	email.SetClassification(schema.ClassPII)
+	class, ok = schema.ColumnClassification(email)
+	require.True(t, ok)
+	require.Equal(t, schema.ClassPII, class)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user email [classification:pii]"}}, email.Attrs)
+	email.SetClassification(schema.ClassSecret)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user email [classification:secret]"}}, email.Attrs)
+	email.SetClassification("")
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user email"}}, email.Attrs)
+	token := schema.NewStringColumn("token", "text").SetClassification(schema.ClassSecret)
+	token.SetClassification("")
+	require.Empty(t, token.Attrs)
+
+	users := schema.NewTable("users").AddColumns(
+		schema.NewIntColumn("id", "int"),
+		email.SetClassification(schema.ClassPII),
+		token.SetClassification(schema.ClassSecret),
+	)
+	require.Equal(t, []*schema.Column{email, token}, schema.ClassifiedColumns(users))
+	require.Equal(t, []*schema.Column{token}, schema.ClassifiedColumns(users, schema.ClassSecret))
+}
@@ -0,0 +1,129 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Clone returns a deep copy of the realm. All references between the
+// elements of the realm (e.g., foreign keys, dependencies or back-references
+// to schemas and tables) are set to point to their copies.
+func (r *Realm) Clone() *Realm {
+	if r == nil {
+		return nil
+	}
+	return (&cloner{}).value(reflect.ValueOf(r)).Interface().(*Realm)
+}
+
+// Clone returns a deep copy of the table, including its columns, indexes,
+// foreign keys, triggers and attributes. The copy keeps pointing to the schema
+// of the table, but it is not added to its tables. References to other objects,
+// like the tables referenced by foreign keys, point to the original objects.
+func (t *Table) Clone() *Table {
+	if t == nil {
+		return nil
+	}
+	c := &cloner{
+		keep: func(v reflect.Value) bool {
+			switch o := v.Interface().(type) {
+			case *Realm, *Schema:
+				return true
+			case *Index, *ForeignKey, *Check, *NamedDefault, *Trigger:
+				return false
+			case *Table:
+				return o != t
+			case *Column:
+				return !slices.Contains(t.Columns, o)
+			case Object:
+				return true
+			default:
+				return false
+			}
+		},
+	}
+	return c.value(reflect.ValueOf(t)).Interface().(*Table)
+}
+
+// cloner deep copies values using reflection. Pointers are copied
+// once, and cycles in the graph are kept between the copies.
+type cloner struct {
+	seen map[ptrKey]reflect.Value
+	// keep reports if the pointer should be kept as is, without copying it.
+	keep func(reflect.Value) bool
+}
+
+type ptrKey struct {
+	t reflect.Type
+	p uintptr
+}
+
+func (c *cloner) value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		k := ptrKey{t: v.Type(), p: v.Pointer()}
+		if n, ok := c.seen[k]; ok {
+			return n
+		}
+		if c.keep != nil && c.keep(v) {
+			return v
+		}
+		if c.seen == nil {
+			c.seen = make(map[ptrKey]reflect.Value)
+		}
+		n := reflect.New(v.Type().Elem())
+		c.seen[k] = n
+		n.Elem().Set(c.value(v.Elem()))
+		return n
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.New(v.Type()).Elem()
+		n.Set(c.value(v.Elem()))
+		return n
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.value(v.Index(i)))
+		}
+		return n
+	case reflect.Array:
+		n := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(c.value(v.Index(i)))
+		}
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			n.SetMapIndex(c.value(it.Key()), c.value(it.Value()))
+		}
+		return n
+	case reflect.Struct:
+		// Unexported fields are copied as is (shallow),
+		// and the exported ones are deep copied.
+		n := reflect.New(v.Type()).Elem()
+		n.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				n.Field(i).Set(c.value(v.Field(i)))
+			}
+		}
+		return n
+	default:
+		return v
+	}
+}
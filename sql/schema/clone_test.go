@@ -0,0 +1,107 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealm_Clone(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewStringColumn("name", "text"),
+		).
+		SetComment("users table")
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	posts := schema.NewTable("posts").
+		AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("author_id", "int"))
+	posts.AddForeignKeys(schema.NewForeignKey("author").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	posts.AddIndexes(schema.NewIndex("author_idx").AddColumns(posts.Columns[1]))
+	v := schema.NewView("names", "SELECT name FROM users").AddDeps(users)
+	r := schema.NewRealm(schema.New("public").AddTables(users, posts).AddViews(v))
+
+	c := r.Clone()
+	require.True(t, schema.Equal(r, c))
+	cs := c.Schemas[0]
+	require.NotSame(t, r.Schemas[0], cs)
+	require.Same(t, c, cs.Realm)
+	cu, cp := cs.Tables[0], cs.Tables[1]
+	require.NotSame(t, users, cu)
+	require.Same(t, cs, cu.Schema)
+	require.Same(t, cu, cp.ForeignKeys[0].RefTable)
+	require.Same(t, cu.Columns[0], cp.ForeignKeys[0].RefColumns[0])
+	require.Same(t, cp.Columns[1], cp.ForeignKeys[0].Columns[0])
+	require.Same(t, cp.Columns[1], cp.Indexes[0].Parts[0].C)
+	require.Same(t, cp, cp.Indexes[0].Table)
+	require.Same(t, cu, cs.Views[0].Deps[0])
+	require.Same(t, cs.Views[0], cu.Refs[0])
+
+	// Changes to the copy do not affect the original.
+	cu.SetComment("changed")
+	cu.Columns[1].Name = "full_name"
+	require.Equal(t, "name", users.Columns[1].Name)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "users table"}}, users.Attrs)
+	require.False(t, schema.Equal(r, c))
+	require.Nil(t, (*schema.Realm)(nil).Clone())
+}
+
+func TestTable_Clone(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+	posts := schema.NewTable("posts").
+		AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("parent_id", "int"), schema.NewIntColumn("author_id", "int"))
+	posts.AddForeignKeys(
+		schema.NewForeignKey("parent").AddColumns(posts.Columns[1]).SetRefTable(posts).AddRefColumns(posts.Columns[0]),
+		schema.NewForeignKey("author").AddColumns(posts.Columns[2]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+	)
+	posts.AddTriggers(schema.NewTrigger("audit", "SELECT 1"))
+	s := schema.New("public").AddTables(users, posts)
+
+	c := posts.Clone()
+	require.True(t, schema.Equal(posts, c))
+	require.Same(t, s, c.Schema)
+	require.Len(t, s.Tables, 2)
+	require.Same(t, c, c.ForeignKeys[0].RefTable, "self-reference points to the copy")
+	require.Same(t, c.Columns[0], c.ForeignKeys[0].RefColumns[0])
+	require.Same(t, users, c.ForeignKeys[1].RefTable, "references to other tables are kept")
+	require.Same(t, users.Columns[0], c.ForeignKeys[1].RefColumns[0])
+	require.NotSame(t, posts.Triggers[0], c.Triggers[0])
+	require.Same(t, c, c.Triggers[0].Table)
+}
+
+func TestEqual(t *testing.T) {
+	t1 := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", "int")).
+		SetComment("users").
+		SetCharset("utf8mb4")
+	t2 := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", "int")).
+		SetCharset("utf8mb4")
+	require.False(t, schema.Equal(t1, t2))
+	require.True(t, schema.Equal(t1, t2, schema.IgnoreComments()))
+
+	t2.SetComment("users")
+	require.False(t, schema.Equal(t1, t2))
+	require.True(t, schema.Equal(t1, t2, schema.IgnoreAttrOrder()))
+	t2.Attrs = []schema.Attr{t2.Attrs[1], t2.Attrs[0]}
+	require.True(t, schema.Equal(t1, t2))
+
+	// Tables are compared regardless of the realm they reside in,
+	// and nil and empty lists are considered equal.
+	schema.New("public").AddTables(t1)
+	schema.New("public").AddTables(t2)
+	t2.Columns[0].Attrs = []schema.Attr{}
+	require.True(t, schema.Equal(t1, t2, schema.IgnoreAttrOrder()))
+	t2.Schema.Name = "other"
+	require.False(t, schema.Equal(t1, t2, schema.IgnoreAttrOrder()))
+
+	require.True(t, schema.Equal(nil, nil))
+	require.False(t, schema.Equal(t1, nil))
+	require.False(t, schema.Equal(t1, t1.Columns[0]))
+}
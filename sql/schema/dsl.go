@@ -81,6 +81,24 @@ func (s *Schema) AddViews(views ...*View) *Schema {
 	return s
 }
 
+// AddFuncs adds the given functions to the schema.
+func (s *Schema) AddFuncs(funcs ...*Func) *Schema {
+	for _, f := range funcs {
+		f.Schema = s
+	}
+	s.Funcs = append(s.Funcs, funcs...)
+	return s
+}
+
+// AddProcs adds the given procedures to the schema.
+func (s *Schema) AddProcs(procs ...*Proc) *Schema {
+	for _, p := range procs {
+		p.Schema = s
+	}
+	s.Procs = append(s.Procs, procs...)
+	return s
+}
+
 // AddObjects adds the given objects to the schema.
 func (s *Schema) AddObjects(objs ...Object) *Schema {
 	s.Objects = append(s.Objects, objs...)
@@ -162,9 +180,132 @@ func (v *View) AddColumns(columns ...*Column) *View {
 // AddDeps adds the given dependencies to the view.
 func (v *View) AddDeps(deps ...Object) *View {
 	v.Deps = append(v.Deps, deps...)
+	addRefs(v, deps)
 	return v
 }
 
+// AddTriggers adds and links the given triggers to the view.
+func (v *View) AddTriggers(triggers ...*Trigger) *View {
+	for _, t := range triggers {
+		t.Table, t.View = nil, v
+	}
+	v.Triggers = append(v.Triggers, triggers...)
+	return v
+}
+
+// NewFunc creates a new Func with the given name and body.
+func NewFunc(name, body string) *Func {
+	return &Func{Name: name, Body: body}
+}
+
+// SetLang sets the implementation language of the function.
+func (f *Func) SetLang(l string) *Func {
+	f.Lang = l
+	return f
+}
+
+// SetRet sets the return type of the function.
+func (f *Func) SetRet(t Type) *Func {
+	f.Ret = t
+	return f
+}
+
+// AddArgs adds the given arguments to the function.
+func (f *Func) AddArgs(args ...*FuncArg) *Func {
+	f.Args = append(f.Args, args...)
+	return f
+}
+
+// SetComment sets or appends the Comment attribute to the function with the given value.
+func (f *Func) SetComment(c string) *Func {
+	ReplaceOrAppend(&f.Attrs, &Comment{Text: c})
+	return f
+}
+
+// AddDeps adds the given dependencies to the function.
+func (f *Func) AddDeps(deps ...Object) *Func {
+	f.Deps = append(f.Deps, deps...)
+	addRefs(f, deps)
+	return f
+}
+
+// NewProc creates a new Proc with the given name and body.
+func NewProc(name, body string) *Proc {
+	return &Proc{Name: name, Body: body}
+}
+
+// SetLang sets the implementation language of the procedure.
+func (p *Proc) SetLang(l string) *Proc {
+	p.Lang = l
+	return p
+}
+
+// AddArgs adds the given arguments to the procedure.
+func (p *Proc) AddArgs(args ...*FuncArg) *Proc {
+	p.Args = append(p.Args, args...)
+	return p
+}
+
+// SetComment sets or appends the Comment attribute to the procedure with the given value.
+func (p *Proc) SetComment(c string) *Proc {
+	ReplaceOrAppend(&p.Attrs, &Comment{Text: c})
+	return p
+}
+
+// AddDeps adds the given dependencies to the procedure.
+func (p *Proc) AddDeps(deps ...Object) *Proc {
+	p.Deps = append(p.Deps, deps...)
+	addRefs(p, deps)
+	return p
+}
+
+// NewFuncArg creates a new FuncArg with the given name and type.
+func NewFuncArg(name string, t Type) *FuncArg {
+	return &FuncArg{Name: name, Type: t}
+}
+
+// SetMode sets the mode of the argument.
+func (a *FuncArg) SetMode(m FuncArgMode) *FuncArg {
+	a.Mode = m
+	return a
+}
+
+// SetDefault sets the default value of the argument.
+func (a *FuncArg) SetDefault(x Expr) *FuncArg {
+	a.Default = x
+	return a
+}
+
+// NewTrigger creates a new Trigger with the given name and body.
+func NewTrigger(name, body string) *Trigger {
+	return &Trigger{Name: name, Body: body}
+}
+
+// SetActionTime sets the action time of the trigger.
+func (t *Trigger) SetActionTime(at TriggerTime) *Trigger {
+	t.ActionTime = at
+	return t
+}
+
+// AddEvents adds the given events to the trigger.
+func (t *Trigger) AddEvents(events ...TriggerEvent) *Trigger {
+	t.Events = append(t.Events, events...)
+	return t
+}
+
+// SetFor sets the FOR EACH spec of the trigger.
+func (t *Trigger) SetFor(f TriggerFor) *Trigger {
+	t.For = f
+	return t
+}
+
+// AddDeps adds the given dependencies to the trigger.
+func (t *Trigger) AddDeps(deps ...Object) *Trigger {
+	t.Deps = append(t.Deps, deps...)
+	addRefs(t, deps)
+	return t
+}
+
 // SetCharset sets or appends the Charset attribute
 // to the table with the given value.
 func (t *Table) SetCharset(v string) *Table {
@@ -206,6 +347,32 @@ func (t *Table) AddChecks(checks ...*Check) *Table {
 	return t
 }
 
+// AddCheck appends a check constraint with the given name and expression to the table.
+// If a check with the same name already exists, it is replaced.
+func (t *Table) AddCheck(name, expr string) *Table {
+	c := NewCheck().SetName(name).SetExpr(expr)
+	for i, a := range t.Attrs {
+		if ck, ok := a.(*Check); ok && name != "" && ck.Name == name {
+			t.Attrs[i] = c
+			return t
+		}
+	}
+	return t.AddChecks(c)
+}
+
+// RemoveChecks removes the check constraints with the given names from the table.
+func (t *Table) RemoveChecks(names ...string) *Table {
+	attrs := make([]Attr, 0, len(t.Attrs))
+	for _, a := range t.Attrs {
+		if ck, ok := a.(*Check); ok && slices.Contains(names, ck.Name) {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	t.Attrs = attrs
+	return t
+}
+
 // SetSchema sets the schema (named-database) of the table.
 func (t *Table) SetSchema(s *Schema) *Table {
 	t.Schema = s
@@ -227,6 +394,12 @@ func (t *Table) SetPrimaryKey(pk *Index) *Table {
 	return t
 }
 
+// SetPrimaryKeyColumns sets the primary-key of the table to the given columns. For
+// composite keys, the order of the columns defines the order of the key parts.
+func (t *Table) SetPrimaryKeyColumns(columns ...*Column) *Table {
+	return t.SetPrimaryKey(NewPrimaryKey(columns...))
+}
+
 // AddColumns appends the given columns to the table column list.
 func (t *Table) AddColumns(columns ...*Column) *Table {
 	t.Columns = append(t.Columns, columns...)
@@ -251,6 +424,27 @@ func (t *Table) AddForeignKeys(fks ...*ForeignKey) *Table {
 	return t
 }
 
+// AddForeignKey appends a foreign-key with the given symbol to the table, from the
+// given columns to the referenced columns of the parent table. For example:
+//
+//	posts.AddForeignKey("author_fk", []*Column{authorID}, users, []*Column{id}, ForeignKeyOnDelete(Cascade))
+func (t *Table) AddForeignKey(symbol string, columns []*Column, ref *Table, refColumns []*Column, opts ...ForeignKeyOption) *Table {
+	fk := NewForeignKey(symbol).AddColumns(columns...).SetRefTable(ref).AddRefColumns(refColumns...)
+	for _, opt := range opts {
+		opt(fk)
+	}
+	return t.AddForeignKeys(fk)
+}
+
+// AddTriggers adds and links the given triggers to the table.
+func (t *Table) AddTriggers(triggers ...*Trigger) *Table {
+	for _, tr := range triggers {
+		tr.Table, tr.View = t, nil
+	}
+	t.Triggers = append(t.Triggers, triggers...)
+	return t
+}
+
 // AddAttrs adds and additional attributes to the table.
 func (t *Table) AddAttrs(attrs ...Attr) *Table {
 	t.Attrs = append(t.Attrs, attrs...)
@@ -310,6 +504,21 @@ func SortRefs(refs []Object) {
 	})
 }
 
+// AddRefs adds references to the view.
+func (v *View) AddRefs(refs ...Object) {
+	v.Refs = append(v.Refs, refs...)
+}
+
+// AddRefs adds references to the function.
+func (f *Func) AddRefs(refs ...Object) {
+	f.Refs = append(f.Refs, refs...)
+}
+
+// AddRefs adds references to the procedure.
+func (p *Proc) AddRefs(refs ...Object) {
+	p.Refs = append(p.Refs, refs...)
+}
+
 // AddRefs adds references to the table.
 func (t *Table) AddRefs(refs ...Object) {
 	t.Refs = append(t.Refs, refs...)
@@ -643,6 +852,17 @@ func (c *Column) SetComment(v string) *Column {
 	return c
 }
 
+// SetLogicalDefault sets or appends the LogicalDefault attribute of the
+// column with the given expression. A nil expression removes the attribute.
+func (c *Column) SetLogicalDefault(x Expr) *Column {
+	if x == nil {
+		del(&c.Attrs, &LogicalDefault{})
+		return c
+	}
+	ReplaceOrAppend(&c.Attrs, &LogicalDefault{X: x})
+	return c
+}
+
 // SetGeneratedExpr sets or appends the GeneratedExpr attribute.
 func (c *Column) SetGeneratedExpr(x *GeneratedExpr) *Column {
 	ReplaceOrAppend(&c.Attrs, x)
@@ -763,6 +983,14 @@ func (i *Index) AddExprs(exprs ...Expr) *Index {
 	return i
 }
 
+// AddRawExprs adds the raw expressions to index parts. e.g., "lower(name)".
+func (i *Index) AddRawExprs(exprs ...string) *Index {
+	for _, x := range exprs {
+		i.AddExprs(&RawExpr{X: x})
+	}
+	return i
+}
+
 // AddParts appends the given parts.
 func (i *Index) AddParts(parts ...*IndexPart) *Index {
 	for _, p := range parts {
@@ -814,6 +1042,23 @@ func NewForeignKey(symbol string) *ForeignKey {
 	return &ForeignKey{Symbol: symbol}
 }
 
+// ForeignKeyOption allows configuring ForeignKey using functional options.
+type ForeignKeyOption func(*ForeignKey)
+
+// ForeignKeyOnUpdate configures the ON UPDATE action of the foreign-key.
+func ForeignKeyOnUpdate(o ReferenceOption) ForeignKeyOption {
+	return func(f *ForeignKey) {
+		f.OnUpdate = o
+	}
+}
+
+// ForeignKeyOnDelete configures the ON DELETE action of the foreign-key.
+func ForeignKeyOnDelete(o ReferenceOption) ForeignKeyOption {
+	return func(f *ForeignKey) {
+		f.OnDelete = o
+	}
+}
+
 // SetTable configures the table that holds the foreign-key (child table).
 func (f *ForeignKey) SetTable(t *Table) *ForeignKey {
 	f.Table = t
@@ -852,6 +1097,12 @@ func (f *ForeignKey) AddRefColumns(columns ...*Column) *ForeignKey {
 	return f
 }
 
+// References configures the referenced/parent table and appends the given
+// columns to the parent-table columns.
+func (f *ForeignKey) References(t *Table, columns ...*Column) *ForeignKey {
+	return f.SetRefTable(t).AddRefColumns(columns...)
+}
+
 // SetOnUpdate sets the ON UPDATE constraint action.
 func (f *ForeignKey) SetOnUpdate(o ReferenceOption) *ForeignKey {
 	f.OnUpdate = o
@@ -232,3 +232,100 @@ func TestRemoveAttr(t *testing.T) {
 	require.Len(t, u.Attrs, 1)
 	require.Equal(t, &schema.Charset{V: "charset"}, u.Attrs[0])
 }
+
+func TestSchema_AddFuncs(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+	fn := schema.NewFunc("count_users", "SELECT count(*) FROM users").
+		SetLang("SQL").
+		SetRet(&schema.IntegerType{T: "int"}).
+		AddArgs(schema.NewFuncArg("min", &schema.IntegerType{T: "int"}).SetMode(schema.FuncArgModeIn)).
+		SetComment("count users").
+		AddDeps(users)
+	proc := schema.NewProc("reset", "DELETE FROM users")
+	s := schema.New("public").AddTables(users).AddFuncs(fn).AddProcs(proc)
+	require.Equal(t, s, fn.Schema)
+	require.Equal(t, s, proc.Schema)
+	require.Equal(t, []schema.Object{fn}, users.Refs)
+	f, ok := s.Func("count_users")
+	require.True(t, ok)
+	require.Equal(t, fn, f)
+	require.Equal(t, schema.FuncArgModeIn, f.Args[0].Mode)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "count users"}}, f.Attrs)
+	p, ok := s.Proc("reset")
+	require.True(t, ok)
+	require.Equal(t, proc, p)
+	_, ok = s.Func("reset")
+	require.False(t, ok)
+}
+
+func TestTable_AddTriggers(t *testing.T) {
+	name := schema.NewStringColumn("name", "text")
+	users := schema.NewTable("users").AddColumns(name)
+	tr := schema.NewTrigger("users_audit", "INSERT INTO audit VALUES (NEW.name)").
+		SetActionTime(schema.TriggerTimeAfter).
+		AddEvents(schema.TriggerEventInsert, schema.TriggerEventUpdateOf(name)).
+		SetFor(schema.TriggerForRow)
+	users.AddTriggers(tr)
+	require.Equal(t, users, tr.Table)
+	require.Nil(t, tr.View)
+	got, ok := users.Trigger("users_audit")
+	require.True(t, ok)
+	require.Equal(t, tr, got)
+	require.Equal(t, []*schema.Column{name}, got.Events[1].Columns)
+
+	v := schema.NewView("users_v", "SELECT * FROM users").AddTriggers(tr)
+	require.Equal(t, v, tr.View)
+	require.Nil(t, tr.Table)
+	_, ok = v.Trigger("users_audit")
+	require.True(t, ok)
+}
+
+func TestTable_Constraints(t *testing.T) {
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("tenant_id", "int"),
+			schema.NewIntColumn("id", "int"),
+			schema.NewStringColumn("email", "varchar"),
+		)
+	users.SetPrimaryKeyColumns(users.Columns[0], users.Columns[1]).
+		AddIndexes(schema.NewUniqueIndex("users_email").AddRawExprs("lower(email)"))
+	require.Equal(t, users, users.PrimaryKey.Table)
+	require.True(t, users.PrimaryKey.Unique)
+	require.Len(t, users.PrimaryKey.Parts, 2)
+	require.Equal(t, users.Columns[1], users.PrimaryKey.Parts[1].C)
+	require.Equal(t, 1, users.PrimaryKey.Parts[1].SeqNo)
+	idx, ok := users.Index("users_email")
+	require.True(t, ok)
+	require.Equal(t, &schema.RawExpr{X: "lower(email)"}, idx.Parts[0].X)
+
+	posts := schema.NewTable("posts").
+		AddColumns(
+			schema.NewIntColumn("tenant_id", "int"),
+			schema.NewIntColumn("author_id", "int"),
+		)
+	posts.AddForeignKey("author_fk", posts.Columns, users, users.Columns[:2],
+		schema.ForeignKeyOnDelete(schema.Cascade), schema.ForeignKeyOnUpdate(schema.NoAction)).
+		AddCheck("author_positive", "author_id > 0").
+		AddCheck("tenant_positive", "tenant_id > 0")
+	fk, ok := posts.ForeignKey("author_fk")
+	require.True(t, ok)
+	require.Equal(t, posts, fk.Table)
+	require.Equal(t, users, fk.RefTable)
+	require.Equal(t, schema.Cascade, fk.OnDelete)
+	require.Equal(t, schema.NoAction, fk.OnUpdate)
+	require.Equal(t, []*schema.ForeignKey{fk}, fk.Columns[0].ForeignKeys)
+	fk = schema.NewForeignKey("fk").References(users, users.Columns[0], users.Columns[1])
+	require.Equal(t, users, fk.RefTable)
+	require.Len(t, fk.RefColumns, 2)
+
+	// Checks are replaced by name.
+	posts.AddCheck("author_positive", "author_id >= 1")
+	require.Len(t, posts.Checks(), 2)
+	ck, ok := posts.Check("author_positive")
+	require.True(t, ok)
+	require.Equal(t, "author_id >= 1", ck.Expr)
+	posts.RemoveChecks("author_positive")
+	_, ok = posts.Check("author_positive")
+	require.False(t, ok)
+	require.Len(t, posts.Checks(), 1)
+}
@@ -0,0 +1,231 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"reflect"
+	"slices"
+)
+
+type (
+	// EqualOption allows configuring the normalization done by Equal.
+	EqualOption func(*equalOptions)
+
+	equalOptions struct {
+		ignoreComments  bool
+		ignoreAttrOrder bool
+	}
+)
+
+// IgnoreComments instructs Equal to ignore the Comment attributes of the compared elements.
+func IgnoreComments() EqualOption {
+	return func(o *equalOptions) { o.ignoreComments = true }
+}
+
+// IgnoreAttrOrder instructs Equal to compare attribute lists regardless of their order.
+func IgnoreAttrOrder() EqualOption {
+	return func(o *equalOptions) { o.ignoreAttrOrder = true }
+}
+
+// Equal reports if the two schema elements (e.g., realms, schemas, tables or columns) are
+// structurally equal. Unlike reflect.DeepEqual, nil and empty lists are considered equal,
+// and references to other objects (e.g., the schema of a table, the referenced table of a
+// foreign key or the dependencies of a view) are compared by their names and not by their
+// content. Hence, two tables are equal even if they reside in two different realms.
+func Equal(a, b any, opts ...EqualOption) bool {
+	e := &equaler{visited: make(map[[2]ptrKey]bool)}
+	for _, opt := range opts {
+		opt(&e.equalOptions)
+	}
+	return e.equal(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// refFields lists the struct fields that hold references to other
+// objects, and are compared by their names instead of their content.
+var refFields = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Schema{}):     {"Realm": true},
+	reflect.TypeOf(Table{}):      {"Schema": true, "Deps": true, "Refs": true},
+	reflect.TypeOf(View{}):       {"Schema": true, "Deps": true, "Refs": true},
+	reflect.TypeOf(Func{}):       {"Schema": true, "Deps": true, "Refs": true},
+	reflect.TypeOf(Proc{}):       {"Schema": true, "Deps": true, "Refs": true},
+	reflect.TypeOf(Trigger{}):    {"Table": true, "View": true, "Deps": true},
+	reflect.TypeOf(Index{}):      {"Table": true},
+	reflect.TypeOf(ForeignKey{}): {"Table": true, "RefTable": true, "RefColumns": true},
+}
+
+var attrsT = reflect.TypeOf([]Attr(nil))
+
+type equaler struct {
+	equalOptions
+	visited map[[2]ptrKey]bool
+}
+
+func (e *equaler) equal(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+		// Pairs that are already being compared (i.e., cycles) are
+		// assumed to be equal, and the comparison continues with the
+		// rest of the elements.
+		k := [2]ptrKey{{t: a.Type(), p: a.Pointer()}, {t: b.Type(), p: b.Pointer()}}
+		if e.visited[k] {
+			return true
+		}
+		e.visited[k] = true
+		defer delete(e.visited, k)
+		return e.equal(a.Elem(), b.Elem())
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return e.equal(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.Type() == attrsT {
+			return e.attrs(a, b)
+		}
+		fallthrough
+	case reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !e.equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for it := a.MapRange(); it.Next(); {
+			v := b.MapIndex(it.Key())
+			if !v.IsValid() || !e.equal(it.Value(), v) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		refs := refFields[a.Type()]
+		for i := 0; i < a.NumField(); i++ {
+			if refs[a.Type().Field(i).Name] {
+				if !refsEqual(a.Field(i), b.Field(i)) {
+					return false
+				}
+			} else if !e.equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return a.IsNil() == b.IsNil()
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	default:
+		return false
+	}
+}
+
+// attrs compares two attribute lists according to the options.
+func (e *equaler) attrs(a, b reflect.Value) bool {
+	var as, bs []reflect.Value
+	for _, l := range []struct {
+		v   reflect.Value
+		out *[]reflect.Value
+	}{{a, &as}, {b, &bs}} {
+		for i := 0; i < l.v.Len(); i++ {
+			x := l.v.Index(i)
+			if e.ignoreComments && !x.IsNil() && x.Elem().Type() == reflect.TypeOf(&Comment{}) {
+				continue
+			}
+			*l.out = append(*l.out, x)
+		}
+	}
+	if len(as) != len(bs) {
+		return false
+	}
+	if !e.ignoreAttrOrder {
+		for i := range as {
+			if !e.equal(as[i], bs[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	matched := make([]bool, len(bs))
+Attrs:
+	for _, x := range as {
+		for i, y := range bs {
+			if !matched[i] && e.equal(x, y) {
+				matched[i] = true
+				continue Attrs
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// refsEqual compares two references (or lists of references) by their names.
+func refsEqual(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice {
+		if a.Len() != b.Len() {
+			return false
+		}
+		names := make([]string, 0, a.Len())
+		for i := 0; i < a.Len(); i++ {
+			names = append(names, refName(a.Index(i)))
+		}
+		for i := 0; i < b.Len(); i++ {
+			j := slices.Index(names, refName(b.Index(i)))
+			if j == -1 {
+				return false
+			}
+			names = slices.Delete(names, j, j+1)
+		}
+		return true
+	}
+	return refName(a) == refName(b)
+}
+
+// refName returns the name of the referenced element.
+func refName(v reflect.Value) string {
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+	switch r := v.Interface().(type) {
+	case *Realm:
+		return "realm"
+	case *Schema:
+		return "schema " + r.Name
+	case *Column:
+		return "column " + r.Name
+	case Object:
+		return objName(r)
+	default:
+		return ""
+	}
+}
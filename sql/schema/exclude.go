@@ -12,7 +12,14 @@ import (
 	"strings"
 )
 
-// ExcludeRealm filters resources in the realm based on the given patterns.
+// ExcludeRealm filters resources in the realm based on the given patterns. Each part of
+// a pattern is either a glob or a regular expression wrapped with slashes, and can end
+// with a type selector that limits the types of resources it matches. For example:
+//
+//	*.*.tmp_*[type=index] // exclude all indexes named "tmp_*".
+//	*.*.*[type=trigger]   // exclude all triggers.
+//	*.*[type=view]        // exclude all views.
+//	s1./^audit_\d+$/      // exclude the resources of "s1" matching the regular expression.
 func ExcludeRealm(r *Realm, patterns []string) (*Realm, error) {
 	if len(patterns) == 0 {
 		return r, nil
@@ -38,7 +45,7 @@ Filter:
 				return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
 			}
 			if globS, exclude := excludeType(typeS, g[0]); exclude {
-				match, err := filepath.Match(globS, s.Name)
+				match, err := match(globS, s.Name)
 				if err != nil {
 					return nil, err
 				}
@@ -106,7 +113,7 @@ func excludeS(s *Schema, glob []string) (err error) {
 	if globT, exclude := excludeType(typeT, glob[0]); exclude {
 		var tables []*Table
 		for _, t := range s.Tables {
-			match, err := filepath.Match(globT, t.Name)
+			match, err := match(globT, t.Name)
 			if err != nil {
 				return err
 			}
@@ -126,6 +133,51 @@ func excludeS(s *Schema, glob []string) (err error) {
 		}
 		s.Tables = tables
 	}
+	if globV, exclude := excludeType(typeV, glob[0]); exclude {
+		var views []*View
+		for _, v := range s.Views {
+			match, err := match(globV, v.Name)
+			if err != nil {
+				return err
+			}
+			if match {
+				if len(glob) == 1 {
+					detachObject(v, v.Refs)
+					continue
+				}
+				if v.Triggers, err = excludeTriggers(v.Triggers, glob[1]); err != nil {
+					return err
+				}
+			}
+			views = append(views, v)
+		}
+		s.Views = views
+	}
+	if len(glob) > 1 {
+		return nil
+	}
+	if globF, exclude := excludeType(typeFn, glob[0]); exclude {
+		if s.Funcs, err = filter(s.Funcs, func(f *Func) (bool, error) {
+			match, err := match(globF, f.Name)
+			if match {
+				detachObject(f, f.Refs)
+			}
+			return match, err
+		}); err != nil {
+			return err
+		}
+	}
+	if globP, exclude := excludeType(typeP, glob[0]); exclude {
+		if s.Procs, err = filter(s.Procs, func(p *Proc) (bool, error) {
+			match, err := match(globP, p.Name)
+			if match {
+				detachObject(p, p.Refs)
+			}
+			return match, err
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -134,7 +186,7 @@ func excludeT(t *Table, pattern string) (err error) {
 	ef := make(map[*ForeignKey]struct{})
 	if p, exclude := excludeType(typeC, pattern); exclude {
 		t.Columns, err = filter(t.Columns, func(c *Column) (bool, error) {
-			match, err := filepath.Match(p, c.Name)
+			match, err := match(p, c.Name)
 			if !match || err != nil {
 				return false, err
 			}
@@ -152,7 +204,7 @@ func excludeT(t *Table, pattern string) (err error) {
 			if _, ok := ex[idx]; ok {
 				return true, nil
 			}
-			return filepath.Match(p, idx.Name)
+			return match(p, idx.Name)
 		})
 	}
 	if p, exclude := excludeType(typeF, pattern); exclude {
@@ -160,7 +212,7 @@ func excludeT(t *Table, pattern string) (err error) {
 			if _, ok := ef[fk]; ok {
 				return true, nil
 			}
-			return filepath.Match(p, fk.Symbol)
+			return match(p, fk.Symbol)
 		})
 	}
 	if p, exclude := excludeType(typeK, pattern); exclude {
@@ -169,14 +221,28 @@ func excludeT(t *Table, pattern string) (err error) {
 			if !ok {
 				return false, nil
 			}
-			match, err := filepath.Match(p, c.Name)
+			match, err := match(p, c.Name)
 			if !match || err != nil {
 				return false, err
 			}
 			return true, nil
 		})
 	}
-	return
+	if err != nil {
+		return err
+	}
+	t.Triggers, err = excludeTriggers(t.Triggers, pattern)
+	return err
+}
+
+func excludeTriggers(triggers []*Trigger, pattern string) ([]*Trigger, error) {
+	p, exclude := excludeType(typeTr, pattern)
+	if !exclude {
+		return triggers, nil
+	}
+	return filter(triggers, func(tr *Trigger) (bool, error) {
+		return match(p, tr.Name)
+	})
 }
 
 // SpecTypeNamer is an interface that allows to get the spec type and name of the object.
@@ -205,7 +271,7 @@ func excludeObjects(all []Object, glob []string) ([]Object, error) {
 			t2glob[nt.SpecType()] = cache
 		}
 		if cache.exclude {
-			match, err := filepath.Match(cache.glob, nt.SpecName())
+			match, err := match(cache.glob, nt.SpecName())
 			if err != nil {
 				return nil, err
 			}
@@ -221,14 +287,33 @@ func excludeObjects(all []Object, glob []string) ([]Object, error) {
 }
 
 const (
-	typeT = "table"
-	typeS = "schema"
-	typeC = "column"
-	typeI = "index"
-	typeF = "fk"
-	typeK = "check"
+	typeT  = "table"
+	typeS  = "schema"
+	typeC  = "column"
+	typeI  = "index"
+	typeF  = "fk"
+	typeK  = "check"
+	typeV  = "view"
+	typeFn = "function"
+	typeP  = "procedure"
+	typeTr = "trigger"
 )
 
+// match reports whether the name matches the pattern. Patterns wrapped with slashes
+// are regular expressions, e.g. /^tmp_\d+$/. Other patterns are globs, using the
+// syntax of filepath.Match. Note, patterns that contain dots must be quoted, as dots
+// are used to separate the parts of the pattern. e.g. s."/^t.+$/".
+func match(pattern, name string) (bool, error) {
+	if len(pattern) < 2 || pattern[0] != '/' || pattern[len(pattern)-1] != '/' {
+		return filepath.Match(pattern, name)
+	}
+	re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return re.MatchString(name), nil
+}
+
 var reType = regexp.MustCompile(`\[type=([a-z|_]+)+\]$`)
 
 func excludeType(t, v string) (string, bool) {
@@ -270,12 +355,227 @@ func detachObject(o Object, refs []Object) {
 	}
 }
 
-// IncludeRealm is a no-op for the community version.
-func IncludeRealm(r *Realm, _ []string) (*Realm, error) {
-	return r, nil // Unimplemented.
+// IncludeRealm filters the realm to contain only the resources matching at least one of
+// the given patterns. The patterns use the same syntax as ExcludeRealm, including regular
+// expressions and type selectors. For example:
+//
+//	s1                         // include schema "s1" with all its resources.
+//	s1.t*                      // include only the resources of "s1" that their names start with "t".
+//	*./^app_/[type=table|view] // include only tables and views that their names start with "app_".
+//	s1.t1.c*                   // include only table "t1" of "s1", with its resources named "c*".
+func IncludeRealm(r *Realm, patterns []string) (*Realm, error) {
+	if len(patterns) == 0 {
+		return r, nil
+	}
+	globs, err := split(patterns)
+	if err != nil {
+		return nil, err
+	}
+	for i, g := range globs {
+		if len(g) > 3 {
+			return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
+		}
+	}
+	if r.Objects, err = includeObjects(r.Objects, globs); err != nil {
+		return nil, err
+	}
+	schemas := make([]*Schema, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		sub, all, err := includeMatch(typeS, s.Name, globs)
+		switch {
+		case err != nil:
+			return nil, err
+		case all:
+		case len(sub) > 0:
+			if err := includeS(s, sub); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		schemas = append(schemas, s)
+	}
+	r.Schemas = schemas
+	return r, nil
+}
+
+// IncludeSchema filters the schema to contain only the resources matching at least one
+// of the given patterns. The patterns are relative to the schema, like in ExcludeSchema.
+func IncludeSchema(s *Schema, patterns []string) (*Schema, error) {
+	if len(patterns) == 0 {
+		return s, nil
+	}
+	globs, err := split(patterns)
+	if err != nil {
+		return nil, err
+	}
+	for i, g := range globs {
+		if len(g) > 2 {
+			return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
+		}
+	}
+	if err := includeS(s, globs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// includeMatch matches the resource against the first part of the globs. It
+// reports if one of the globs matches the resource with all its children, and
+// returns the rest of the matched globs that filter its children.
+func includeMatch(typ, name string, globs [][]string) (sub [][]string, all bool, err error) {
+	for _, g := range globs {
+		p, ok := excludeType(typ, g[0])
+		if !ok {
+			continue
+		}
+		switch m, err := match(p, name); {
+		case err != nil:
+			return nil, false, err
+		case !m:
+		case len(g) == 1:
+			all = true
+		default:
+			sub = append(sub, g[1:])
+		}
+	}
+	return sub, all, nil
+}
+
+func includeS(s *Schema, globs [][]string) (err error) {
+	if s.Objects, err = includeObjects(s.Objects, globs); err != nil {
+		return err
+	}
+	var tables []*Table
+	for _, t := range s.Tables {
+		sub, all, err := includeMatch(typeT, t.Name, globs)
+		switch {
+		case err != nil:
+			return err
+		case all:
+		case len(sub) > 0:
+			if err := includeT(t, sub); err != nil {
+				return err
+			}
+		default:
+			detachObject(t, t.Refs)
+			continue
+		}
+		tables = append(tables, t)
+	}
+	s.Tables = tables
+	var views []*View
+	for _, v := range s.Views {
+		sub, all, err := includeMatch(typeV, v.Name, globs)
+		switch {
+		case err != nil:
+			return err
+		case all:
+		case len(sub) > 0:
+			if v.Triggers, err = filter(v.Triggers, func(tr *Trigger) (bool, error) {
+				return includeNone(typeTr, tr.Name, sub)
+			}); err != nil {
+				return err
+			}
+		default:
+			detachObject(v, v.Refs)
+			continue
+		}
+		views = append(views, v)
+	}
+	s.Views = views
+	if s.Funcs, err = filter(s.Funcs, func(f *Func) (bool, error) {
+		drop, err := includeNone(typeFn, f.Name, globs)
+		if drop {
+			detachObject(f, f.Refs)
+		}
+		return drop, err
+	}); err != nil {
+		return err
+	}
+	s.Procs, err = filter(s.Procs, func(p *Proc) (bool, error) {
+		drop, err := includeNone(typeP, p.Name, globs)
+		if drop {
+			detachObject(p, p.Refs)
+		}
+		return drop, err
+	})
+	return err
 }
 
-// IncludeSchema is a no-op for the community version.
-func IncludeSchema(s *Schema, _ []string) (*Schema, error) {
-	return s, nil // Unimplemented.
+func includeT(t *Table, globs [][]string) (err error) {
+	ex := make(map[*Index]struct{})
+	ef := make(map[*ForeignKey]struct{})
+	if t.Columns, err = filter(t.Columns, func(c *Column) (bool, error) {
+		drop, err := includeNone(typeC, c.Name, globs)
+		if !drop || err != nil {
+			return false, err
+		}
+		for _, idx := range c.Indexes {
+			ex[idx] = struct{}{}
+		}
+		for _, fk := range c.ForeignKeys {
+			ef[fk] = struct{}{}
+		}
+		return true, nil
+	}); err != nil {
+		return err
+	}
+	if t.Indexes, err = filter(t.Indexes, func(idx *Index) (bool, error) {
+		if _, ok := ex[idx]; ok {
+			return true, nil
+		}
+		return includeNone(typeI, idx.Name, globs)
+	}); err != nil {
+		return err
+	}
+	if t.ForeignKeys, err = filter(t.ForeignKeys, func(fk *ForeignKey) (bool, error) {
+		if _, ok := ef[fk]; ok {
+			return true, nil
+		}
+		return includeNone(typeF, fk.Symbol, globs)
+	}); err != nil {
+		return err
+	}
+	if t.Attrs, err = filter(t.Attrs, func(a Attr) (bool, error) {
+		if c, ok := a.(*Check); ok {
+			return includeNone(typeK, c.Name, globs)
+		}
+		return false, nil
+	}); err != nil {
+		return err
+	}
+	t.Triggers, err = filter(t.Triggers, func(tr *Trigger) (bool, error) {
+		return includeNone(typeTr, tr.Name, globs)
+	})
+	return err
+}
+
+// includeNone reports if none of the single-part globs matches the resource.
+func includeNone(typ, name string, globs [][]string) (bool, error) {
+	for _, g := range globs {
+		if len(g) != 1 {
+			continue
+		}
+		p, ok := excludeType(typ, g[0])
+		if !ok {
+			continue
+		}
+		if m, err := match(p, name); m || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// includeObjects filters the objects that can be matched by their names. Objects
+// without names (i.e., not implementing SpecTypeNamer) are kept as is.
+func includeObjects(all []Object, globs [][]string) ([]Object, error) {
+	return filter(all, func(o Object) (bool, error) {
+		nt, ok := o.(SpecTypeNamer)
+		if !ok {
+			return false, nil
+		}
+		return includeNone(nt.SpecType(), nt.SpecName(), globs)
+	})
 }
@@ -0,0 +1,115 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludeRealm_Selectors(t *testing.T) {
+	realm := func() *schema.Realm {
+		var (
+			users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("tmp_a", "int"))
+			audit = schema.NewTable("audit_1").AddColumns(schema.NewIntColumn("id", "int"))
+			v     = schema.NewView("users_v", "SELECT id FROM users").AddDeps(users)
+		)
+		users.AddIndexes(
+			schema.NewIndex("tmp_idx").AddColumns(users.Columns[0]),
+			schema.NewIndex("users_id").AddColumns(users.Columns[0]),
+		)
+		users.AddTriggers(schema.NewTrigger("trg1", "BEGIN END"))
+		v.AddTriggers(schema.NewTrigger("trg2", "BEGIN END"))
+		s := schema.New("public").
+			AddTables(users, audit).
+			AddViews(v).
+			AddFuncs(schema.NewFunc("f1", "SELECT 1"))
+		return schema.NewRealm(s)
+	}
+
+	// Type selectors.
+	r, err := schema.ExcludeRealm(realm(), []string{"*.*.tmp_*[type=index]", "*.*.*[type=trigger]"})
+	require.NoError(t, err)
+	users, ok := r.Schemas[0].Table("users")
+	require.True(t, ok)
+	require.Len(t, users.Columns, 2, "columns are not matched by the selector")
+	require.Len(t, users.Indexes, 1)
+	require.Equal(t, "users_id", users.Indexes[0].Name)
+	require.Empty(t, users.Triggers)
+	require.Empty(t, r.Schemas[0].Views[0].Triggers)
+
+	r, err = schema.ExcludeRealm(realm(), []string{"*.*[type=view|function]"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas[0].Tables, 2)
+	require.Empty(t, r.Schemas[0].Views)
+	require.Empty(t, r.Schemas[0].Funcs)
+
+	// Regular expressions.
+	r, err = schema.ExcludeRealm(realm(), []string{`public./^audit_\d+$/`, `*.users./^tmp_/`})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	users = r.Schemas[0].Tables[0]
+	require.Equal(t, "users", users.Name)
+	require.Len(t, users.Columns, 1)
+	require.Len(t, users.Indexes, 1)
+	_, err = schema.ExcludeRealm(realm(), []string{`*./[a-/`})
+	require.Error(t, err)
+}
+
+func TestIncludeRealm(t *testing.T) {
+	realm := func() *schema.Realm {
+		var (
+			users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("name", "int"))
+			posts = schema.NewTable("posts").AddColumns(schema.NewIntColumn("id", "int"))
+		)
+		users.AddIndexes(schema.NewIndex("users_name").AddColumns(users.Columns[1]))
+		users.AddTriggers(schema.NewTrigger("trg1", "BEGIN END"))
+		return schema.NewRealm(
+			schema.New("public").
+				AddTables(users, posts).
+				AddViews(schema.NewView("users_v", "SELECT id FROM users")).
+				AddFuncs(schema.NewFunc("f1", "SELECT 1")),
+			schema.New("other").AddTables(schema.NewTable("t")),
+		)
+	}
+	r, err := schema.IncludeRealm(realm(), nil)
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+
+	r, err = schema.IncludeRealm(realm(), []string{"public"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 1)
+	require.Len(t, r.Schemas[0].Tables, 2)
+	require.Len(t, r.Schemas[0].Views, 1)
+
+	r, err = schema.IncludeRealm(realm(), []string{"*./^users/[type=table|view]"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	require.Equal(t, "users", r.Schemas[0].Tables[0].Name)
+	require.Len(t, r.Schemas[0].Views, 1)
+	require.Empty(t, r.Schemas[0].Funcs)
+	require.Empty(t, r.Schemas[1].Tables)
+
+	r, err = schema.IncludeRealm(realm(), []string{"public.users.id", "public.users.*[type=trigger]"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	users := r.Schemas[0].Tables[0]
+	require.Len(t, users.Columns, 1)
+	require.Empty(t, users.Indexes, "indexes of excluded columns are excluded")
+	require.Len(t, users.Triggers, 1)
+
+	s, err := schema.IncludeSchema(realm().Schemas[0], []string{"f*[type=function]", "posts"})
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 1)
+	require.Equal(t, "posts", s.Tables[0].Name)
+	require.Empty(t, s.Views)
+	require.Len(t, s.Funcs, 1)
+	_, err = schema.IncludeSchema(realm().Schemas[0], []string{"a.b.c"})
+	require.EqualError(t, err, `too many parts in pattern: "a.b.c"`)
+}
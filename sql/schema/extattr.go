@@ -0,0 +1,143 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ExtAttr is an attribute that is attached to schema elements by third-party drivers
+// and tools, under a namespace of their own (e.g., "x-sharding.key"). Extension attributes
+// do not describe a database state, and therefore, they are retained as-is by the dev-database
+// normalization, are not reported as changes by the diff, and are converted to and from HCL
+// as blocks named after their namespace. For example:
+//
+//	column "id" {
+//	  type = int
+//	  x-sharding {
+//	    key = true
+//	  }
+//	}
+type ExtAttr struct {
+	// Name is the namespaced name of the attribute, in the
+	// form of "x-<namespace>.<key>". e.g., "x-sharding.key".
+	Name string
+	// V holds the attribute value. Supported values are
+	// string, bool, int64, float64 and []string.
+	V any
+}
+
+// ExtNamespacePrefix is the prefix of all extension attribute namespaces.
+const ExtNamespacePrefix = "x-"
+
+var (
+	extNameRe = regexp.MustCompile(`^x-[a-z][a-z0-9_-]*\.[a-zA-Z_][a-zA-Z0-9_]*$`)
+	extKinds  = []reflect.Type{
+		reflect.TypeOf(""), reflect.TypeOf(false), reflect.TypeOf(int64(0)),
+		reflect.TypeOf(float64(0)), reflect.TypeOf([]string(nil)),
+	}
+	extAttrs = struct {
+		sync.RWMutex
+		byName map[string]reflect.Type
+	}{
+		byName: make(map[string]reflect.Type),
+	}
+)
+
+func init() {
+	RegisterJSONType("schema.ExtAttr", (*ExtAttr)(nil))
+	for _, t := range extKinds {
+		RegisterJSONType("ext."+t.String(), reflect.Zero(t).Interface())
+	}
+}
+
+// RegisterExtAttr registers the extension attribute with the given name, and the type
+// of its value. Values of registered attributes are validated by NewExtAttr, and are
+// converted to the registered type when loaded from HCL. It panics if the name is not
+// a valid extension attribute name, if the type is not supported, or if the attribute
+// was already registered. For example:
+//
+//	schema.RegisterExtAttr("x-sharding.key", false)
+func RegisterExtAttr(name string, v any) {
+	if !extNameRe.MatchString(name) {
+		panic(fmt.Sprintf("sql/schema: invalid extension attribute name %q", name))
+	}
+	t := reflect.TypeOf(v)
+	if !extKind(t) {
+		panic(fmt.Sprintf("sql/schema: unsupported extension attribute type %v for %q", t, name))
+	}
+	extAttrs.Lock()
+	defer extAttrs.Unlock()
+	if _, ok := extAttrs.byName[name]; ok {
+		panic("sql/schema: RegisterExtAttr called twice for " + name)
+	}
+	extAttrs.byName[name] = t
+}
+
+// LookupExtAttr returns the value type of the registered
+// extension attribute, if it was registered.
+func LookupExtAttr(name string) (reflect.Type, bool) {
+	extAttrs.RLock()
+	defer extAttrs.RUnlock()
+	t, ok := extAttrs.byName[name]
+	return t, ok
+}
+
+// NewExtAttr returns a new extension attribute with the given name and value. Integer
+// values are stored as int64. If the attribute was registered, the value must match
+// its registered type.
+func NewExtAttr(name string, v any) (*ExtAttr, error) {
+	if !extNameRe.MatchString(name) {
+		return nil, fmt.Errorf("sql/schema: invalid extension attribute name %q", name)
+	}
+	if i, ok := v.(int); ok {
+		v = int64(i)
+	}
+	t := reflect.TypeOf(v)
+	if !extKind(t) {
+		return nil, fmt.Errorf("sql/schema: unsupported value %T for extension attribute %q", v, name)
+	}
+	if rt, ok := LookupExtAttr(name); ok && rt != t {
+		return nil, fmt.Errorf("sql/schema: extension attribute %q expects value of type %s, got %s", name, rt, t)
+	}
+	return &ExtAttr{Name: name, V: v}, nil
+}
+
+// Namespace returns the namespace of the attribute. e.g., "x-sharding".
+func (a *ExtAttr) Namespace() string {
+	ns, _, _ := strings.Cut(a.Name, ".")
+	return ns
+}
+
+// Key returns the key of the attribute within its namespace. e.g., "key".
+func (a *ExtAttr) Key() string {
+	_, k, _ := strings.Cut(a.Name, ".")
+	return k
+}
+
+// ExtAttrs returns the extension attributes in the given attributes list.
+func ExtAttrs(attrs []Attr) []*ExtAttr {
+	var ext []*ExtAttr
+	for _, a := range attrs {
+		if e, ok := a.(*ExtAttr); ok {
+			ext = append(ext, e)
+		}
+	}
+	return ext
+}
+
+// extKind reports if t is a supported type for extension attribute values.
+func extKind(t reflect.Type) bool {
+	for _, k := range extKinds {
+		if t == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtAttr(t *testing.T) {
+	schema.RegisterExtAttr("x-test.shard_key", false)
+	require.Panics(t, func() { schema.RegisterExtAttr("x-test.shard_key", false) })
+	require.Panics(t, func() { schema.RegisterExtAttr("sharding.key", "") })
+	require.Panics(t, func() { schema.RegisterExtAttr("x-test.count", 1) })
+	rt, ok := schema.LookupExtAttr("x-test.shard_key")
+	require.True(t, ok)
+	require.Equal(t, reflect.TypeOf(false), rt)
+	_, ok = schema.LookupExtAttr("x-test.unknown")
+	require.False(t, ok)
+
+	a, err := schema.NewExtAttr("x-test.shard_key", true)
+	require.NoError(t, err)
+	require.Equal(t, "x-test", a.Namespace())
+	require.Equal(t, "shard_key", a.Key())
+	_, err = schema.NewExtAttr("x-test.shard_key", "id")
+	require.EqualError(t, err, `sql/schema: extension attribute "x-test.shard_key" expects value of type bool, got string`)
+	_, err = schema.NewExtAttr("x-test", "id")
+	require.EqualError(t, err, `sql/schema: invalid extension attribute name "x-test"`)
+	_, err = schema.NewExtAttr("x-test.map", map[string]string{})
+	require.EqualError(t, err, `sql/schema: unsupported value map[string]string for extension attribute "x-test.map"`)
+
+	// Integers are stored as int64.
+	b, err := schema.NewExtAttr("x-test.weight", 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), b.V)
+	c, err := schema.NewExtAttr("x-test.regions", []string{"eu", "us"})
+	require.NoError(t, err)
+
+	users := schema.NewTable("users").
+		AddAttrs(a, &schema.Comment{Text: "users"}, b).
+		AddColumns(schema.NewIntColumn("id", "int").AddAttrs(c))
+	require.Equal(t, []*schema.ExtAttr{a, b}, schema.ExtAttrs(users.Attrs))
+	require.Equal(t, []*schema.ExtAttr{c}, schema.ExtAttrs(users.Columns[0].Attrs))
+
+	// Extension attributes are retained by cloning and JSON encoding.
+	r := schema.NewRealm(schema.New("public").AddTables(users))
+	require.Equal(t, schema.ExtAttrs(users.Attrs), schema.ExtAttrs(r.Clone().Schemas[0].Tables[0].Attrs))
+	buf, err := schema.MarshalJSON(r)
+	require.NoError(t, err)
+	var r2 schema.Realm
+	require.NoError(t, schema.UnmarshalJSON(buf, &r2))
+	require.Equal(t, schema.ExtAttrs(users.Attrs), schema.ExtAttrs(r2.Schemas[0].Tables[0].Attrs))
+	require.Equal(t, schema.ExtAttrs(users.Columns[0].Attrs), schema.ExtAttrs(r2.Schemas[0].Tables[0].Columns[0].Attrs))
+}
@@ -0,0 +1,181 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FingerprintOptions configures the computation of a schema fingerprint.
+type FingerprintOptions struct {
+	// Comments indicates if comments are part of the fingerprint.
+	// By default, comments are considered cosmetic and are ignored.
+	Comments bool
+
+	// SkipAttr reports if an attribute should be excluded from the
+	// fingerprint. For example, attributes that hold a state that
+	// changes without a schema change, like the AUTO_INCREMENT value.
+	SkipAttr func(Attr) bool
+}
+
+// Fingerprint returns a stable hash of the realm that can be used to detect
+// if a schema was changed, without computing a full diff. The fingerprint is
+// independent of the order of the elements in the realm (e.g., the order of
+// schemas, tables, columns or attributes), and ignores cosmetic attributes,
+// like comments and the raw representation of column types. Elements whose
+// order is meaningful, like the parts of an index, or the columns of a
+// foreign key, are hashed in their order. A nil options is valid.
+func Fingerprint(r *Realm, opts *FingerprintOptions) string {
+	if opts == nil {
+		opts = &FingerprintOptions{}
+	}
+	f := &fingerprinter{opts: opts, owned: make(map[ptrKey]bool)}
+	f.own(reflect.ValueOf(r))
+	h := sha256.Sum256([]byte(f.value(reflect.ValueOf(r), true)))
+	return hex.EncodeToString(h[:])
+}
+
+type fingerprinter struct {
+	opts *FingerprintOptions
+	// owned holds the pointers that are owned by the realm.
+	owned map[ptrKey]bool
+}
+
+// own records the elements that are owned by the realm.
+func (f *fingerprinter) own(v reflect.Value) {
+	own(v, f.owned)
+}
+
+// value returns the canonical encoding of v.
+func (f *fingerprinter) value(v reflect.Value, owned bool) string {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return "nil"
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return v.Elem().Type().String() + f.value(v.Elem(), owned)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "nil"
+		}
+		if ref, ok := f.ref(v, owned); ok {
+			return ref
+		}
+		return f.value(v.Elem(), owned)
+	case reflect.Struct:
+		var (
+			b      strings.Builder
+			fields = jsonOwned[v.Type()]
+		)
+		b.WriteByte('{')
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() || v.Field(i).IsZero() || v.Type() == reflect.TypeOf(ColumnType{}) && sf.Name == "Raw" {
+				continue
+			}
+			// Empty lists are considered as zero values.
+			x := f.value(v.Field(i), fields[sf.Name])
+			if x == "[]" {
+				continue
+			}
+			b.WriteString(sf.Name)
+			b.WriteByte(':')
+			b.WriteString(x)
+			b.WriteByte(';')
+		}
+		b.WriteByte('}')
+		return b.String()
+	case reflect.Slice, reflect.Array:
+		l := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			x := v.Index(i)
+			if a, ok := x.Interface().(Attr); ok && v.Type() == attrsT && f.skip(a) {
+				continue
+			}
+			l = append(l, f.value(x, owned))
+		}
+		if f.unordered(v.Type(), owned) {
+			slices.Sort(l)
+		}
+		return "[" + strings.Join(l, ",") + "]"
+	case reflect.Map:
+		l := make([]string, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			l = append(l, f.value(it.Key(), false)+":"+f.value(it.Value(), false))
+		}
+		slices.Sort(l)
+		return "map[" + strings.Join(l, ",") + "]"
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return ""
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// skip reports if the attribute should be excluded from the fingerprint.
+func (f *fingerprinter) skip(a Attr) bool {
+	if _, ok := a.(*Comment); ok && !f.opts.Comments {
+		return true
+	}
+	return f.opts.SkipAttr != nil && f.opts.SkipAttr(a)
+}
+
+// unordered reports if the order of the slice elements is not meaningful.
+func (f *fingerprinter) unordered(t reflect.Type, owned bool) bool {
+	switch {
+	case owned, t == attrsT:
+		return true
+	case t.Kind() != reflect.Slice:
+		return false
+	}
+	// Dependencies and back-references.
+	switch t.Elem() {
+	case reflect.TypeOf((*Object)(nil)).Elem(), reflect.TypeOf((*Index)(nil)), reflect.TypeOf((*ForeignKey)(nil)):
+		return true
+	}
+	return false
+}
+
+// ref returns the encoding of a pointer that is not owned by its current position.
+func (f *fingerprinter) ref(v reflect.Value, owned bool) (string, bool) {
+	if owned {
+		return "", false
+	}
+	switch o := v.Interface().(type) {
+	case *Realm:
+		return "&realm", true
+	case *Schema:
+		return "&schema " + strconv.Quote(o.Name), true
+	case *Table, *View, *Func, *Proc, *Trigger:
+		return "&" + objName(o.(Object)), true
+	case *Column:
+		return "&column " + strconv.Quote(o.Name), true
+	case *Index:
+		return "&index " + strconv.Quote(o.Name), true
+	case *ForeignKey:
+		return "&foreign key " + strconv.Quote(o.Symbol), true
+	case Object:
+		// Objects that are not owned by the realm, like
+		// inline enum types, are encoded by their content.
+		if !f.owned[ptrKey{t: v.Type(), p: v.Pointer()}] {
+			break
+		}
+		if e, ok := o.(*EnumType); ok {
+			return "&enum " + strconv.Quote(qualified(e.Schema, e.T)), true
+		}
+		return "&" + objName(o), true
+	}
+	return "", false
+}
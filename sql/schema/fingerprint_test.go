@@ -0,0 +1,76 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprint(t *testing.T) {
+	build := func(reverse bool, comment string) *schema.Realm {
+		var (
+			id    = schema.NewIntColumn("id", "int")
+			name  = schema.NewStringColumn("name", "varchar", schema.StringSize(255)).SetComment(comment)
+			users = schema.NewTable("users")
+			posts = schema.NewTable("posts")
+			owner = schema.NewIntColumn("owner_id", "int")
+		)
+		users.AddColumns(id, name).SetPrimaryKey(schema.NewPrimaryKey(id)).AddIndexes(schema.NewIndex("users_name").AddColumns(name))
+		posts.AddColumns(schema.NewIntColumn("id", "int"), owner).
+			AddForeignKeys(schema.NewForeignKey("owner").AddColumns(owner).SetRefTable(users).AddRefColumns(id))
+		tables := []*schema.Table{users, posts}
+		if reverse {
+			tables = []*schema.Table{posts, users}
+			users.Columns[0], users.Columns[1] = users.Columns[1], users.Columns[0]
+		}
+		return schema.NewRealm(schema.New("public").AddTables(tables...))
+	}
+	fp := schema.Fingerprint(build(false, "a"), nil)
+	require.Len(t, fp, 64)
+	require.Equal(t, fp, schema.Fingerprint(build(false, "a"), nil), "stable")
+	require.Equal(t, fp, schema.Fingerprint(build(true, "a"), nil), "independent of ordering")
+	require.Equal(t, fp, schema.Fingerprint(build(false, "b"), nil), "comments are cosmetic")
+	require.NotEqual(t,
+		schema.Fingerprint(build(false, "a"), &schema.FingerprintOptions{Comments: true}),
+		schema.Fingerprint(build(false, "b"), &schema.FingerprintOptions{Comments: true}),
+	)
+
+	// Raw type representations are ignored.
+	r := build(false, "a")
+	r.Schemas[0].Tables[0].Columns[0].Type.Raw = "int(11)"
+	require.Equal(t, fp, schema.Fingerprint(r, nil))
+
+	// Structural changes.
+	r = build(false, "a")
+	r.Schemas[0].Tables[0].Columns[1].Type.Type.(*schema.StringType).Size = 50
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+	r = build(false, "a")
+	r.Schemas[0].Tables[0].Columns[1].Type.Null = true
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+	r = build(false, "a")
+	r.Schemas[0].Tables[1].ForeignKeys[0].OnDelete = schema.Cascade
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+	r = build(false, "a")
+	r.Schemas[0].Tables[0].Indexes[0].Unique = true
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+	r = build(false, "a")
+	r.Schemas[0].Tables[1].Name = "post"
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+
+	// Skipped attributes.
+	r = build(false, "a")
+	r.Schemas[0].Tables[0].AddAttrs(&schema.Charset{V: "utf8mb4"})
+	require.NotEqual(t, fp, schema.Fingerprint(r, nil))
+	require.Equal(t, fp, schema.Fingerprint(r, &schema.FingerprintOptions{
+		SkipAttr: func(a schema.Attr) bool {
+			_, ok := a.(*schema.Charset)
+			return ok
+		},
+	}))
+}
@@ -0,0 +1,318 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// A Graph holds the dependencies between the objects of a realm. An edge
+// from object A to object B means that A depends on B, and therefore, B must
+// be created before A and dropped after it.
+type Graph struct {
+	nodes []Object
+	deps  map[Object][]Object // Objects a node depends on.
+	refs  map[Object][]Object // Objects that depend on a node.
+}
+
+// Dependencies returns the dependency graph of the objects in the realm. The
+// graph is built from the foreign keys of the tables, the types used by table
+// columns, the trigger targets, and the explicit dependencies (Deps) of tables,
+// views, functions, procedures and triggers. Self-references (e.g., a foreign
+// key that references its own table) are not recorded as dependencies.
+//
+// Dependencies between columns of the same table, like generated columns, are
+// not represented in the graph. Use GeneratedDeps to get them.
+func Dependencies(r *Realm) *Graph {
+	g := &Graph{deps: make(map[Object][]Object), refs: make(map[Object][]Object)}
+	for _, o := range r.Objects {
+		g.add(o)
+	}
+	for _, s := range r.Schemas {
+		for _, o := range s.Objects {
+			g.add(o)
+		}
+		for _, t := range s.Tables {
+			g.add(t)
+		}
+		for _, v := range s.Views {
+			g.add(v)
+		}
+		for _, f := range s.Funcs {
+			g.add(f)
+		}
+		for _, p := range s.Procs {
+			g.add(p)
+		}
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			for _, fk := range t.ForeignKeys {
+				if fk.RefTable != nil {
+					g.addEdge(t, fk.RefTable)
+				}
+			}
+			for _, c := range t.Columns {
+				if c.Type == nil {
+					continue
+				}
+				if o, ok := UnderlyingType(c.Type.Type).(Object); ok && g.has(o) {
+					g.addEdge(t, o)
+				}
+			}
+			g.addEdges(t, t.Deps)
+			for _, tr := range t.Triggers {
+				g.add(tr)
+				g.addEdge(tr, t)
+				g.addEdges(tr, tr.Deps)
+			}
+		}
+		for _, v := range s.Views {
+			g.addEdges(v, v.Deps)
+			for _, tr := range v.Triggers {
+				g.add(tr)
+				g.addEdge(tr, v)
+				g.addEdges(tr, tr.Deps)
+			}
+		}
+		for _, f := range s.Funcs {
+			g.addEdges(f, f.Deps)
+		}
+		for _, p := range s.Procs {
+			g.addEdges(p, p.Deps)
+		}
+	}
+	return g
+}
+
+// Objects returns all objects in the graph, in the order they were added.
+func (g *Graph) Objects() []Object {
+	return slices.Clone(g.nodes)
+}
+
+// DependsOn returns the objects that the given object directly depends on.
+func (g *Graph) DependsOn(o Object) []Object {
+	return slices.Clone(g.deps[o])
+}
+
+// DependentsOf returns the objects that directly depend on the given object.
+func (g *Graph) DependentsOf(o Object) []Object {
+	return slices.Clone(g.refs[o])
+}
+
+// Impact returns all objects that directly or indirectly depend on the given
+// object, i.e., the objects that might be affected by changing or dropping it.
+func (g *Graph) Impact(o Object) []Object {
+	var (
+		impact []Object
+		seen   = map[Object]bool{o: true}
+		queue  = g.refs[o]
+	)
+	for len(queue) > 0 {
+		r := queue[0]
+		queue = queue[1:]
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		impact = append(impact, r)
+		queue = append(queue, g.refs[r]...)
+	}
+	return impact
+}
+
+// Sort returns the objects of the graph sorted topologically, where each object
+// comes after all objects it depends on. i.e., the creation order. The reverse
+// order can be used for dropping them. Objects without dependencies between them
+// keep their order in the graph. A CycleError is returned if the graph contains a
+// cycle, for example, two tables that reference each other using foreign keys.
+func (g *Graph) Sort() ([]Object, error) {
+	var (
+		sorted   = make([]Object, 0, len(g.nodes))
+		indegree = make(map[Object]int, len(g.nodes))
+		done     = make(map[Object]bool, len(g.nodes))
+	)
+	for _, n := range g.nodes {
+		indegree[n] = len(g.deps[n])
+	}
+	for len(sorted) < len(g.nodes) {
+		i := slices.IndexFunc(g.nodes, func(n Object) bool {
+			return !done[n] && indegree[n] == 0
+		})
+		if i == -1 {
+			return nil, &CycleError{Objects: g.cycle(done)}
+		}
+		n := g.nodes[i]
+		done[n] = true
+		sorted = append(sorted, n)
+		for _, r := range g.refs[n] {
+			indegree[r]--
+		}
+	}
+	return sorted, nil
+}
+
+// cycle returns the unsorted objects that are part of a cycle. i.e., excluding
+// objects that only depend on a cycle without being part of it.
+func (g *Graph) cycle(done map[Object]bool) []Object {
+	left := make(map[Object]bool)
+	for _, n := range g.nodes {
+		if !done[n] {
+			left[n] = true
+		}
+	}
+	for pruned := true; pruned; {
+		pruned = false
+		for n := range left {
+			if !slices.ContainsFunc(g.refs[n], func(r Object) bool { return left[r] }) {
+				delete(left, n)
+				pruned = true
+			}
+		}
+	}
+	var cycle []Object
+	for _, n := range g.nodes {
+		if left[n] {
+			cycle = append(cycle, n)
+		}
+	}
+	return cycle
+}
+
+// CycleError is returned by Graph.Sort when the
+// graph contains a cycle between its objects.
+type CycleError struct {
+	Objects []Object // Objects that are part of the cycle.
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	names := make([]string, len(e.Objects))
+	for i, o := range e.Objects {
+		names[i] = objName(o)
+	}
+	return fmt.Sprintf("sql/schema: dependency cycle between objects: %s", strings.Join(names, ", "))
+}
+
+func (g *Graph) has(o Object) bool {
+	_, ok := g.deps[o]
+	return ok
+}
+
+func (g *Graph) add(o Object) {
+	if !g.has(o) {
+		g.nodes = append(g.nodes, o)
+		g.deps[o] = nil
+	}
+}
+
+func (g *Graph) addEdges(from Object, to []Object) {
+	for _, o := range to {
+		g.addEdge(from, o)
+	}
+}
+
+func (g *Graph) addEdge(from, to Object) {
+	if from == to || slices.Contains(g.deps[from], to) {
+		return
+	}
+	// Dependencies on objects that reside outside
+	// the realm are added to the graph as well.
+	g.add(to)
+	g.deps[from] = append(g.deps[from], to)
+	g.refs[to] = append(g.refs[to], from)
+}
+
+// objName returns a human-readable name of the object.
+func objName(o Object) string {
+	switch o := o.(type) {
+	case *Table:
+		return "table " + qualified(o.Schema, o.Name)
+	case *View:
+		return "view " + qualified(o.Schema, o.Name)
+	case *Func:
+		return "function " + qualified(o.Schema, o.Name)
+	case *Proc:
+		return "procedure " + qualified(o.Schema, o.Name)
+	case *Trigger:
+		return "trigger " + o.Name
+	case SpecTypeNamer:
+		return o.SpecType() + " " + o.SpecName()
+	default:
+		return fmt.Sprintf("%T", o)
+	}
+}
+
+func qualified(s *Schema, name string) string {
+	if s == nil || s.Name == "" {
+		return name
+	}
+	return s.Name + "." + name
+}
+
+// GeneratedDeps returns the columns of the table that the generated column "c"
+// depends on, based on the identifiers that appear in its generation expression.
+func GeneratedDeps(t *Table, c *Column) []*Column {
+	i := slices.IndexFunc(c.Attrs, func(a Attr) bool {
+		_, ok := a.(*GeneratedExpr)
+		return ok
+	})
+	if i == -1 {
+		return nil
+	}
+	var (
+		deps []*Column
+		x    = c.Attrs[i].(*GeneratedExpr)
+	)
+	for _, id := range exprIdents(x.Expr) {
+		if d, ok := t.Column(id); ok && d != c && !slices.Contains(deps, d) {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+// exprIdents returns the identifiers that appear in the given expression,
+// unquoted. String literals (single-quoted) are skipped.
+func exprIdents(expr string) []string {
+	var (
+		ids []string
+		rs  = []rune(expr)
+	)
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; {
+		case r == '\'':
+			for i++; i < len(rs) && rs[i] != '\''; i++ {
+			}
+		case r == '"' || r == '`' || r == '[':
+			end := r
+			if r == '[' {
+				end = ']'
+			}
+			j := i + 1
+			for j < len(rs) && rs[j] != end {
+				j++
+			}
+			ids = append(ids, string(rs[i+1:min(j, len(rs))]))
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i + 1
+			for j < len(rs) && (rs[j] == '_' || rs[j] == '$' || unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			ids = append(ids, string(rs[i:j]))
+			i = j - 1
+		case unicode.IsDigit(r):
+			// Skip numeric literals, like 1e10.
+			for i+1 < len(rs) && (unicode.IsLetter(rs[i+1]) || unicode.IsDigit(rs[i+1]) || rs[i+1] == '.') {
+				i++
+			}
+		}
+	}
+	return ids
+}
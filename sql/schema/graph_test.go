@@ -0,0 +1,80 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencies(t *testing.T) {
+	var (
+		status = &schema.EnumType{T: "status", Values: []string{"active", "inactive"}}
+		users  = schema.NewTable("users").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewEnumColumn("status", schema.EnumName("status"), schema.EnumValues("active", "inactive")),
+			)
+		posts = schema.NewTable("posts").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("author_id", "int"),
+				schema.NewIntColumn("parent_id", "int"),
+			)
+		active = schema.NewView("active_users", "SELECT * FROM users WHERE status = 'active'")
+		count  = schema.NewFunc("count_posts", "SELECT count(*) FROM posts")
+		audit  = schema.NewTrigger("posts_audit", "SELECT 1")
+	)
+	users.Columns[1].Type.Type = status
+	posts.AddForeignKeys(
+		schema.NewForeignKey("author").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+		schema.NewForeignKey("parent").AddColumns(posts.Columns[2]).SetRefTable(posts).AddRefColumns(posts.Columns[0]),
+	)
+	posts.AddTriggers(audit)
+	active.AddDeps(users)
+	count.AddDeps(posts)
+	s := schema.New("public").
+		AddObjects(status).
+		AddTables(posts, users).
+		AddViews(active).
+		AddFuncs(count)
+	g := schema.Dependencies(schema.NewRealm(s))
+	require.Equal(t, []schema.Object{status, posts, users, active, count, audit}, g.Objects())
+	require.Equal(t, []schema.Object{users}, g.DependsOn(posts), "self-references are ignored")
+	require.Equal(t, []schema.Object{status}, g.DependsOn(users))
+	require.Equal(t, []schema.Object{posts}, g.DependsOn(audit))
+	require.Equal(t, []schema.Object{posts, active}, g.DependentsOf(users))
+	require.Equal(t, []schema.Object{posts, active, audit, count}, g.Impact(users))
+	require.Empty(t, g.Impact(count))
+
+	sorted, err := g.Sort()
+	require.NoError(t, err)
+	require.Equal(t, []schema.Object{status, users, posts, active, count, audit}, sorted)
+
+	// Mutual foreign keys create a cycle.
+	users.AddColumns(schema.NewIntColumn("pinned_id", "int"))
+	users.AddForeignKeys(schema.NewForeignKey("pinned").AddColumns(users.Columns[2]).SetRefTable(posts).AddRefColumns(posts.Columns[0]))
+	_, err = schema.Dependencies(schema.NewRealm(s)).Sort()
+	var cerr *schema.CycleError
+	require.ErrorAs(t, err, &cerr)
+	require.Equal(t, []schema.Object{posts, users}, cerr.Objects, "dependents of the cycle are excluded")
+	require.EqualError(t, err, "sql/schema: dependency cycle between objects: table public.posts, table public.users")
+}
+
+func TestGeneratedDeps(t *testing.T) {
+	tbl := schema.NewTable("t").
+		AddColumns(
+			schema.NewIntColumn("a", "int"),
+			schema.NewIntColumn("b", "int"),
+			schema.NewStringColumn("c", "text"),
+			schema.NewIntColumn("d", "int"),
+		)
+	tbl.Columns[3].SetGeneratedExpr(&schema.GeneratedExpr{Expr: "`a` + \"b\" * 2 + length('c') + a"})
+	require.Equal(t, []*schema.Column{tbl.Columns[0], tbl.Columns[1]}, schema.GeneratedDeps(tbl, tbl.Columns[3]))
+	require.Nil(t, schema.GeneratedDeps(tbl, tbl.Columns[0]))
+}
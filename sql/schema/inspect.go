@@ -74,6 +74,15 @@ type (
 		// Tables to inspect. Empty means all tables in the schema.
 		Tables []string
 
+		// Stats enables the inspection of table statistics, like row counts and data
+		// sizes, on drivers that support it. Unlike the Mode, it is never enabled by
+		// default, and must be requested explicitly.
+		Stats bool
+
+		// Roles enables the inspection of object ownership, on drivers that
+		// support it. Like Stats, it must be requested explicitly.
+		Roles bool
+
 		// Include defines a list of glob patterns used to filter resources for inspection.
 		// If non-empty, only resources matching at least one of the patterns are considered.
 		// After applying inclusion, the Exclude list is used to filter out resources.
@@ -86,6 +95,9 @@ type (
 		//	*.c // the last item defines the filtering; all resources named 'c' are included in all tables.
 		//	*.* // the last item defines the filtering; all resources under all tables are included.
 		//
+		// Items can also be regular expressions wrapped with slashes, and can end with
+		// type selectors (e.g., /^tmp_/[type=index|trigger]). See IncludeRealm for details.
+		//
 		// If Include is empty, all resources are considered unless excluded.
 		Include []string
 
@@ -99,6 +111,8 @@ type (
 		//	*.c // the last item defines the filtering; all resourced named 'c' are excluded in all tables.
 		//	*.* // the last item defines the filtering; all resourced under all tables are excluded.
 		//
+		// Items can also be regular expressions wrapped with slashes, and can end with
+		// type selectors (e.g., /^tmp_/[type=index|trigger]). See ExcludeRealm for details.
 		Exclude []string
 	}
 
@@ -111,6 +125,15 @@ type (
 		// Schemas to inspect. Empty means all schemas in the realm.
 		Schemas []string
 
+		// Stats enables the inspection of table statistics, like row counts and data
+		// sizes, on drivers that support it. Unlike the Mode, it is never enabled by
+		// default, and must be requested explicitly.
+		Stats bool
+
+		// Roles enables the inspection of database roles, object ownership and default
+		// privileges, on drivers that support it. Like Stats, it must be requested explicitly.
+		Roles bool
+
 		// Include defines a list of glob patterns used to filter resources for inspection.
 		// If non-empty, only resources matching at least one of the patterns are considered.
 		// After applying inclusion, the Exclude list is used to filter out resources.
@@ -125,6 +148,9 @@ type (
 		//	*.*.c   // the last item defines the filtering; all resources named 'c' are included in all tables.
 		//	*.*.*   // the last item defines the filtering; all resources are included in all tables.
 		//
+		// Items can also be regular expressions wrapped with slashes, and can end with
+		// type selectors (e.g., /^tmp_/[type=index|trigger]). See IncludeRealm for details.
+		//
 		// If Include is empty, all resources are considered unless excluded.
 		Include []string
 
@@ -140,6 +166,8 @@ type (
 		//	*.*.c // the last item defines the filtering; all resourced named 'c' are excluded in all tables.
 		//	*.*.* // the last item defines the filtering; all resources are excluded in all tables.
 		//
+		// Items can also be regular expressions wrapped with slashes, and can end with
+		// type selectors (e.g., /^tmp_/[type=index|trigger]). See ExcludeRealm for details.
 		Exclude []string
 	}
 
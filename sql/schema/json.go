@@ -0,0 +1,723 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// The JSON encoding of the schema graph is a tree of JSON objects, where each
+// Go struct is encoded as an object keyed by its exported field names. Zero
+// fields are omitted, and object keys are sorted, which makes the encoding
+// stable and suitable for caching and diffing. Three special forms are used:
+//
+//   - Values stored in interfaces (e.g., attributes, types and expressions) are
+//     encoded as {"type": "<tag>", "value": <value>}, where the tag is the one
+//     registered for the concrete Go type using RegisterJSONType.
+//
+//   - Elements owned by the encoded graph (schemas, tables, columns, indexes,
+//     foreign-keys, views, functions, procedures, triggers and schema objects)
+//     are encoded in place with an "$id" key, and references to them from other
+//     elements are encoded as {"$ref": "<id>"}.
+//
+//   - Changes (e.g., AddTable or ModifyColumn) own the elements they hold, and
+//     elements that are held by multiple changes are encoded once, in place of
+//     their first occurrence.
+//
+//   - References to elements that reside outside the encoded graph (e.g., the
+//     schema of an encoded table) are encoded as {"$ext": "<kind>", ...} with the
+//     names that identify them, and are decoded as detached stubs.
+//
+// Note that unexported struct fields are not encoded.
+
+var jsonTypes = struct {
+	sync.RWMutex
+	byTag  map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byTag:  make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// RegisterJSONType registers the Go type of v with the given tag, to allow encoding
+// and decoding values of this type that are stored in interfaces (e.g., schema.Attr
+// or schema.Type) to and from JSON. It panics if the tag or the type were already
+// registered. For example:
+//
+//	schema.RegisterJSONType("postgres.Identity", (*postgres.Identity)(nil))
+func RegisterJSONType(tag string, v any) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		panic("sql/schema: RegisterJSONType called with nil value")
+	}
+	jsonTypes.Lock()
+	defer jsonTypes.Unlock()
+	if _, ok := jsonTypes.byTag[tag]; ok {
+		panic("sql/schema: RegisterJSONType called twice for tag " + tag)
+	}
+	if _, ok := jsonTypes.byType[t]; ok {
+		panic("sql/schema: RegisterJSONType called twice for type " + t.String())
+	}
+	jsonTypes.byTag[tag] = t
+	jsonTypes.byType[t] = tag
+}
+
+// RegisterJSONTypes registers the Go types of the given values with
+// tags in the form of "<prefix>.<TypeName>". e.g., "mysql.Engine".
+func RegisterJSONTypes(prefix string, vs ...any) {
+	for _, v := range vs {
+		t := reflect.TypeOf(v)
+		if t == nil {
+			panic("sql/schema: RegisterJSONTypes called with nil value")
+		}
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		RegisterJSONType(prefix+"."+t.Name(), v)
+	}
+}
+
+func init() {
+	RegisterJSONTypes(
+		"schema",
+		// Objects.
+		(*Table)(nil), (*View)(nil), (*Func)(nil), (*Proc)(nil), (*Trigger)(nil),
+		(*Index)(nil), (*ForeignKey)(nil), (*NamedDefault)(nil),
+		// Types.
+		(*BoolType)(nil), (*EnumType)(nil), (*TimeType)(nil), (*JSONType)(nil),
+		(*FloatType)(nil), (*StringType)(nil), (*BinaryType)(nil), (*SpatialType)(nil),
+		(*UUIDType)(nil), (*IntegerType)(nil), (*DecimalType)(nil), (*UnsupportedType)(nil),
+		// Expressions.
+		(*Literal)(nil), (*RawExpr)(nil),
+		// Attributes.
+		(*Pos)(nil), (*Check)(nil), (*Comment)(nil), (*Charset)(nil),
+		(*Collation)(nil), (*GeneratedExpr)(nil), (*LogicalDefault)(nil),
+		// Clauses.
+		(*IfExists)(nil), (*IfNotExists)(nil),
+	)
+	RegisterJSONTypes("schema", jsonChanges...)
+	// Changes own the elements they hold, and the changes they group.
+	for _, c := range jsonChanges {
+		t := reflect.TypeOf(c).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); jsonChangeField(f.Type) {
+				if jsonOwned[t] == nil {
+					jsonOwned[t] = make(map[string]bool)
+				}
+				jsonOwned[t][f.Name] = true
+			}
+		}
+	}
+}
+
+// jsonChanges lists the changes that can be encoded to JSON.
+var jsonChanges = []any{
+	(*AddSchema)(nil), (*DropSchema)(nil), (*ModifySchema)(nil),
+	(*AddTable)(nil), (*DropTable)(nil), (*ModifyTable)(nil), (*RenameTable)(nil),
+	(*AddView)(nil), (*DropView)(nil), (*ModifyView)(nil), (*RenameView)(nil),
+	(*AddFunc)(nil), (*DropFunc)(nil), (*ModifyFunc)(nil), (*RenameFunc)(nil),
+	(*AddProc)(nil), (*DropProc)(nil), (*ModifyProc)(nil), (*RenameProc)(nil),
+	(*AddTrigger)(nil), (*DropTrigger)(nil), (*ModifyTrigger)(nil), (*RenameTrigger)(nil),
+	(*AddObject)(nil), (*DropObject)(nil), (*ModifyObject)(nil), (*RenameObject)(nil),
+	(*AddColumn)(nil), (*DropColumn)(nil), (*ModifyColumn)(nil), (*RenameColumn)(nil),
+	(*AddIndex)(nil), (*DropIndex)(nil), (*ModifyIndex)(nil), (*RenameIndex)(nil),
+	(*AddPrimaryKey)(nil), (*DropPrimaryKey)(nil), (*ModifyPrimaryKey)(nil),
+	(*AddForeignKey)(nil), (*DropForeignKey)(nil), (*ModifyForeignKey)(nil),
+	(*AddCheck)(nil), (*DropCheck)(nil), (*ModifyCheck)(nil), (*RenameConstraint)(nil),
+	(*AddAttr)(nil), (*DropAttr)(nil), (*ModifyAttr)(nil), (*DataChange)(nil),
+}
+
+// jsonChangeField reports if a change field of type t owns its value,
+// i.e., it holds an element (e.g., the added table) or nested changes.
+func jsonChangeField(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf((*Object)(nil)).Elem(), reflect.TypeOf([]Change(nil)):
+		return true
+	}
+	if t.Kind() != reflect.Pointer {
+		return false
+	}
+	switch t.Elem() {
+	case reflect.TypeOf(Schema{}), reflect.TypeOf(Table{}), reflect.TypeOf(View{}), reflect.TypeOf(Func{}),
+		reflect.TypeOf(Proc{}), reflect.TypeOf(Trigger{}), reflect.TypeOf(Column{}), reflect.TypeOf(Index{}),
+		reflect.TypeOf(ForeignKey{}):
+		return true
+	}
+	return false
+}
+
+// jsonOwned lists the struct fields that own the elements they hold. Pointers to
+// elements held by other fields are references, and are encoded as such.
+var jsonOwned = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Realm{}):  {"Schemas": true, "Objects": true},
+	reflect.TypeOf(Schema{}): {"Tables": true, "Views": true, "Funcs": true, "Procs": true, "Objects": true},
+	reflect.TypeOf(Table{}):  {"Columns": true, "Indexes": true, "PrimaryKey": true, "ForeignKeys": true, "Triggers": true},
+	reflect.TypeOf(View{}):   {"Columns": true, "Triggers": true},
+	// Changes are added on init.
+	reflect.TypeOf(changeSet{}): {"Changes": true},
+}
+
+// own records the elements that are owned by v (e.g., a realm), including v.
+func own(v reflect.Value, owned map[ptrKey]bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	k := ptrKey{t: v.Type(), p: v.Pointer()}
+	if owned[k] {
+		return
+	}
+	owned[k] = true
+	if v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	for name := range jsonOwned[v.Elem().Type()] {
+		switch fv := v.Elem().FieldByName(name); fv.Kind() {
+		case reflect.Slice:
+			for i := 0; i < fv.Len(); i++ {
+				own(fv.Index(i), owned)
+			}
+		default:
+			own(fv, owned)
+		}
+	}
+}
+
+// MarshalJSON returns the JSON encoding of v, which is a pointer to
+// a schema element. Usually, a *Realm, a *Schema or a *Table.
+func MarshalJSON(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, fmt.Errorf("sql/schema: MarshalJSON expects a non-nil pointer, got %T", v)
+	}
+	e := &jsonEncoder{
+		ids:     make(map[ptrKey]string),
+		emitted: make(map[ptrKey]bool),
+		pending: make(map[ptrKey]bool),
+		owners:  make(map[*Column]*Table),
+	}
+	e.assign(rv, "$")
+	x, err := e.encode(rv, true)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(x); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// MarshalChanges returns the JSON encoding of the given changes, for storing a change-set
+// for review and replaying it later. The elements held by the changes (e.g., the added
+// tables and the modified columns) are encoded in full, and elements that are shared by
+// multiple changes are encoded once, at their first position. References to elements
+// outside the change-set (e.g., the schema of a modified table) are encoded by name.
+func MarshalChanges(changes []Change) ([]byte, error) {
+	return MarshalJSON(&changeSet{Changes: changes})
+}
+
+// UnmarshalChanges decodes the JSON encoding of changes created by MarshalChanges.
+func UnmarshalChanges(data []byte) ([]Change, error) {
+	var s changeSet
+	if err := UnmarshalJSON(data, &s); err != nil {
+		return nil, err
+	}
+	return s.Changes, nil
+}
+
+// changeSet is the root of the JSON encoding of changes.
+type changeSet struct {
+	Changes []Change
+}
+
+// UnmarshalJSON decodes the JSON encoding of a schema element created
+// by MarshalJSON into v, which is a pointer to an element of the same type.
+func UnmarshalJSON(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("sql/schema: UnmarshalJSON expects a non-nil pointer, got %T", v)
+	}
+	var x any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&x); err != nil {
+		return fmt.Errorf("sql/schema: decoding JSON: %w", err)
+	}
+	d := &jsonDecoder{
+		ids:   map[string]reflect.Value{"$": rv},
+		stubs: make(map[string]reflect.Value),
+	}
+	if err := d.decode(rv.Elem(), x); err != nil {
+		return err
+	}
+	return d.resolve()
+}
+
+type jsonEncoder struct {
+	ids     map[ptrKey]string  // Identifiers of the owned elements.
+	emitted map[ptrKey]bool    // Owned elements that were encoded.
+	pending map[ptrKey]bool    // Elements that are being encoded.
+	owners  map[*Column]*Table // Owners of the external columns.
+}
+
+// assign assigns identifiers to the element and all elements it owns.
+func (e *jsonEncoder) assign(v reflect.Value, id string) {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	k := ptrKey{t: v.Type(), p: v.Pointer()}
+	// Elements that are owned by multiple fields (e.g., a column of
+	// a modified table that is also held by an AddColumn change) are
+	// identified by their first position. Changes are not referenced.
+	if _, ok := e.ids[k]; ok {
+		return
+	}
+	if _, ok := v.Interface().(Change); !ok {
+		e.ids[k] = id
+	}
+	if v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	// Fields are visited in order, as they are encoded.
+	owned := jsonOwned[v.Elem().Type()]
+	for i := 0; i < v.Elem().NumField(); i++ {
+		f := v.Elem().Type().Field(i).Name
+		if !owned[f] {
+			continue
+		}
+		switch fv := v.Elem().Field(i); fv.Kind() {
+		case reflect.Slice:
+			for i := 0; i < fv.Len(); i++ {
+				e.assign(fv.Index(i), fmt.Sprintf("%s.%s[%d]", id, f, i))
+			}
+		default:
+			e.assign(fv, id+"."+f)
+		}
+	}
+}
+
+func (e *jsonEncoder) encode(v reflect.Value, owned bool) (any, error) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		x := v.Elem()
+		if ref, ok := e.ref(x, owned); ok {
+			return ref, nil
+		}
+		jsonTypes.RLock()
+		tag, ok := jsonTypes.byType[x.Type()]
+		jsonTypes.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("sql/schema: type %s is not registered for JSON encoding", x.Type())
+		}
+		value, err := e.encode(x, owned)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": tag, "value": value}, nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if ref, ok := e.ref(v, owned); ok {
+			return ref, nil
+		}
+		k := ptrKey{t: v.Type(), p: v.Pointer()}
+		if e.pending[k] {
+			return nil, fmt.Errorf("sql/schema: cyclic reference to %s that is not part of the encoded graph", v.Type())
+		}
+		e.pending[k] = true
+		defer delete(e.pending, k)
+		id, hasID := e.ids[k]
+		if hasID {
+			e.emitted[k] = true
+		}
+		x, err := e.encode(v.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		if hasID {
+			if m, ok := x.(map[string]any); ok {
+				m["$id"] = id
+			}
+		}
+		return x, nil
+	case reflect.Struct:
+		m := make(map[string]any)
+		owned := jsonOwned[v.Type()]
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			x, err := e.encode(v.Field(i), owned[f.Name])
+			if err != nil {
+				return nil, err
+			}
+			if x != nil {
+				m[f.Name] = x
+			}
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		l := make([]any, v.Len())
+		for i := range l {
+			x, err := e.encode(v.Index(i), owned)
+			if err != nil {
+				return nil, err
+			}
+			l[i] = x
+		}
+		return l, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("sql/schema: unsupported map key type %s", v.Type().Key())
+		}
+		m := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			x, err := e.encode(it.Value(), false)
+			if err != nil {
+				return nil, err
+			}
+			m[it.Key().String()] = x
+		}
+		return m, nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.String:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("sql/schema: unsupported JSON kind %s", v.Kind())
+	}
+}
+
+// ref returns the reference encoding of the pointer v, if it is not owned by its current
+// position, or if it is owned by multiple positions and was already encoded.
+func (e *jsonEncoder) ref(v reflect.Value, owned bool) (map[string]any, bool) {
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, false
+	}
+	k := ptrKey{t: v.Type(), p: v.Pointer()}
+	if owned && !e.emitted[k] {
+		return nil, false
+	}
+	if id, ok := e.ids[k]; ok {
+		return map[string]any{"$ref": id}, true
+	}
+	if owned {
+		return nil, false
+	}
+	sname := func(s *Schema) string {
+		if s == nil {
+			return ""
+		}
+		return s.Name
+	}
+	tname := func(t *Table) (string, string) {
+		if t == nil {
+			return "", ""
+		}
+		return sname(t.Schema), t.Name
+	}
+	ext := func(kind, schema, table, name string) map[string]any {
+		m := map[string]any{"$ext": kind}
+		for k, v := range map[string]string{"schema": schema, "table": table, "name": name} {
+			if v != "" {
+				m[k] = v
+			}
+		}
+		return m
+	}
+	switch o := v.Interface().(type) {
+	case *Realm:
+		return ext("realm", "", "", ""), true
+	case *Schema:
+		return ext("schema", "", "", o.Name), true
+	case *Table:
+		for _, c := range o.Columns {
+			e.owners[c] = o
+		}
+		return ext("table", sname(o.Schema), "", o.Name), true
+	case *View:
+		return ext("view", sname(o.Schema), "", o.Name), true
+	case *Func:
+		return ext("func", sname(o.Schema), "", o.Name), true
+	case *Proc:
+		return ext("proc", sname(o.Schema), "", o.Name), true
+	case *Column:
+		s, t := tname(e.owners[o])
+		return ext("column", s, t, o.Name), true
+	case *Index:
+		s, t := tname(o.Table)
+		return ext("index", s, t, o.Name), true
+	case *ForeignKey:
+		s, t := tname(o.Table)
+		return ext("fk", s, t, o.Symbol), true
+	}
+	return nil, false
+}
+
+type (
+	jsonDecoder struct {
+		ids   map[string]reflect.Value // Decoded owned elements.
+		stubs map[string]reflect.Value // Stubs of external elements.
+		fixes []jsonFix                // References to resolve.
+	}
+	jsonFix struct {
+		dst reflect.Value
+		id  string
+	}
+)
+
+func (d *jsonDecoder) decode(dst reflect.Value, x any) error {
+	if x == nil {
+		return nil
+	}
+	if m, ok := x.(map[string]any); ok {
+		if id, ok := m["$ref"].(string); ok {
+			d.fixes = append(d.fixes, jsonFix{dst: dst, id: id})
+			return nil
+		}
+		if kind, ok := m["$ext"].(string); ok {
+			return d.setExt(dst, kind, m)
+		}
+	}
+	switch dst.Kind() {
+	case reflect.Interface:
+		m, ok := x.(map[string]any)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect typed JSON object for %s, got %T", dst.Type(), x)
+		}
+		tag, _ := m["type"].(string)
+		jsonTypes.RLock()
+		t, ok := jsonTypes.byTag[tag]
+		jsonTypes.RUnlock()
+		if !ok {
+			return fmt.Errorf("sql/schema: JSON type %q is not registered", tag)
+		}
+		if !t.AssignableTo(dst.Type()) {
+			return fmt.Errorf("sql/schema: JSON type %q does not implement %s", tag, dst.Type())
+		}
+		v := reflect.New(t).Elem()
+		if err := d.decode(v, m["value"]); err != nil {
+			return err
+		}
+		dst.Set(v)
+	case reflect.Pointer:
+		p := dst
+		if p.IsNil() {
+			p = reflect.New(dst.Type().Elem())
+		}
+		if m, ok := x.(map[string]any); ok {
+			if id, ok := m["$id"].(string); ok {
+				d.ids[id] = p
+			}
+		}
+		if err := d.decode(p.Elem(), x); err != nil {
+			return err
+		}
+		dst.Set(p)
+	case reflect.Struct:
+		m, ok := x.(map[string]any)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON object for %s, got %T", dst.Type(), x)
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			f := dst.Type().Field(i)
+			if fx, ok := m[f.Name]; ok && f.IsExported() {
+				if err := d.decode(dst.Field(i), fx); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		l, ok := x.([]any)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON array for %s, got %T", dst.Type(), x)
+		}
+		if dst.Kind() == reflect.Slice {
+			dst.Set(reflect.MakeSlice(dst.Type(), len(l), len(l)))
+		} else if len(l) != dst.Len() {
+			return fmt.Errorf("sql/schema: expect JSON array of length %d for %s, got %d", dst.Len(), dst.Type(), len(l))
+		}
+		for i := range l {
+			if err := d.decode(dst.Index(i), l[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := x.(map[string]any)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON object for %s, got %T", dst.Type(), x)
+		}
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), len(m)))
+		for k, mx := range m {
+			v := reflect.New(dst.Type().Elem()).Elem()
+			if err := d.decode(v, mx); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), v)
+		}
+	case reflect.Bool:
+		b, ok := x.(bool)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON boolean for %s, got %T", dst.Type(), x)
+		}
+		dst.SetBool(b)
+	case reflect.String:
+		s, ok := x.(string)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON string for %s, got %T", dst.Type(), x)
+		}
+		dst.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, ok := x.(json.Number)
+		if !ok {
+			return fmt.Errorf("sql/schema: expect JSON number for %s, got %T", dst.Type(), x)
+		}
+		return setNumber(dst, n)
+	default:
+		return fmt.Errorf("sql/schema: unsupported JSON kind %s", dst.Kind())
+	}
+	return nil
+}
+
+func setNumber(dst reflect.Value, n json.Number) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := n.Float64()
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+	default:
+		i, err := n.Int64()
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	}
+	return nil
+}
+
+// setExt sets the destination to the stub of the external element.
+func (d *jsonDecoder) setExt(dst reflect.Value, kind string, m map[string]any) error {
+	str := func(k string) string {
+		s, _ := m[k].(string)
+		return s
+	}
+	stub := d.extStub(kind, str("schema"), str("table"), str("name"))
+	if !stub.IsValid() {
+		return fmt.Errorf("sql/schema: unknown external JSON reference %q", kind)
+	}
+	if !stub.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("sql/schema: external %s reference cannot be assigned to %s", kind, dst.Type())
+	}
+	dst.Set(stub)
+	return nil
+}
+
+func (d *jsonDecoder) extStub(kind, sname, tname, name string) reflect.Value {
+	key := kind + ":" + strconv.Quote(sname) + "." + strconv.Quote(tname) + "." + strconv.Quote(name)
+	if v, ok := d.stubs[key]; ok {
+		return v
+	}
+	var (
+		stub any
+		s    = func() *Schema {
+			if sname == "" {
+				return nil
+			}
+			return d.extStub("schema", "", "", sname).Interface().(*Schema)
+		}
+		t = func() *Table {
+			if tname == "" {
+				return nil
+			}
+			return d.extStub("table", sname, "", tname).Interface().(*Table)
+		}
+	)
+	switch kind {
+	case "realm":
+		stub = &Realm{}
+	case "schema":
+		stub = &Schema{Name: name}
+	case "table":
+		stub = &Table{Name: name, Schema: s()}
+	case "view":
+		stub = &View{Name: name, Schema: s()}
+	case "func":
+		stub = &Func{Name: name, Schema: s()}
+	case "proc":
+		stub = &Proc{Name: name, Schema: s()}
+	case "column":
+		c := &Column{Name: name}
+		if t := t(); t != nil {
+			t.Columns = append(t.Columns, c)
+		}
+		stub = c
+	case "index":
+		stub = &Index{Name: name, Table: t()}
+	case "fk":
+		stub = &ForeignKey{Symbol: name, Table: t()}
+	default:
+		return reflect.Value{}
+	}
+	v := reflect.ValueOf(stub)
+	d.stubs[key] = v
+	return v
+}
+
+// resolve sets all references to their decoded elements.
+func (d *jsonDecoder) resolve() error {
+	for _, f := range d.fixes {
+		v, ok := d.ids[f.id]
+		if !ok {
+			return fmt.Errorf("sql/schema: unknown JSON reference %q", f.id)
+		}
+		if !v.Type().AssignableTo(f.dst.Type()) {
+			return fmt.Errorf("sql/schema: JSON reference %q (%s) cannot be assigned to %s", f.id, v.Type(), f.dst.Type())
+		}
+		f.dst.Set(v)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON_Realm(t *testing.T) {
+	status := &schema.EnumType{T: "status", Values: []string{"active", "inactive"}}
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewStringColumn("name", "varchar", schema.StringSize(255)).SetComment("full name"),
+			schema.NewColumn("status").SetType(status).SetDefault(&schema.Literal{V: "'active'"}),
+			schema.NewTimeColumn("created_at", "timestamp", schema.TimePrecision(6)).SetNull(true),
+		).
+		SetComment("users table").
+		AddChecks(schema.NewCheck().SetName("name_len").SetExpr("length(name) > 0"))
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	users.AddIndexes(schema.NewUniqueIndex("name_idx").AddColumns(users.Columns[1]).AddExprs(&schema.RawExpr{X: "lower(name)"}))
+	posts := schema.NewTable("posts").
+		AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("author_id", "int"))
+	posts.AddForeignKeys(
+		schema.NewForeignKey("author").
+			AddColumns(posts.Columns[1]).
+			SetRefTable(users).
+			AddRefColumns(users.Columns[0]).
+			SetOnDelete(schema.Cascade),
+	)
+	posts.AddTriggers(
+		schema.NewTrigger("audit", "INSERT INTO audit VALUES (NEW.id)").
+			SetActionTime(schema.TriggerTimeAfter).
+			AddEvents(schema.TriggerEventUpdateOf(posts.Columns[1])),
+	)
+	v := schema.NewView("names", "SELECT name FROM users").AddDeps(users).AddColumns(schema.NewStringColumn("name", "varchar"))
+	other := schema.New("other").AddTables(schema.NewTable("t").AddColumns(schema.NewIntColumn("id", "int")).AddDeps(posts))
+	r := schema.NewRealm(
+		schema.New("public").
+			SetCharset("utf8mb4").
+			AddObjects(status).
+			AddTables(users, posts).
+			AddViews(v).
+			AddFuncs(schema.NewFunc("f", "SELECT 1").SetRet(&schema.IntegerType{T: "int"}).AddArgs(schema.NewFuncArg("a", &schema.IntegerType{T: "int"}))),
+		other,
+	)
+
+	b1, err := schema.MarshalJSON(r)
+	require.NoError(t, err)
+	var got schema.Realm
+	require.NoError(t, schema.UnmarshalJSON(b1, &got))
+	require.True(t, schema.Equal(r, &got))
+
+	// Encoding is stable.
+	b2, err := schema.MarshalJSON(&got)
+	require.NoError(t, err)
+	require.Equal(t, string(b1), string(b2))
+
+	// References are restored.
+	gs := got.Schemas[0]
+	gu, gp := gs.Tables[0], gs.Tables[1]
+	require.Same(t, &got, gs.Realm)
+	require.Same(t, gs, gu.Schema)
+	require.Same(t, gu, gp.ForeignKeys[0].RefTable)
+	require.Same(t, gu.Columns[0], gp.ForeignKeys[0].RefColumns[0])
+	require.Same(t, gp.Columns[1], gp.ForeignKeys[0].Columns[0])
+	require.Equal(t, []*schema.ForeignKey{gp.ForeignKeys[0]}, gp.Columns[1].ForeignKeys)
+	require.Same(t, gu.PrimaryKey, gu.Columns[0].Indexes[0])
+	require.Same(t, gu, gu.Indexes[0].Table)
+	require.Same(t, gu.Columns[1], gu.Indexes[0].Parts[0].C)
+	require.Same(t, gs.Objects[0], gu.Columns[2].Type.Type)
+	require.Same(t, gp, gp.Triggers[0].Table)
+	require.Same(t, gp.Columns[1], gp.Triggers[0].Events[0].Columns[0])
+	require.Same(t, gu, gs.Views[0].Deps[0])
+	require.Same(t, gs.Views[0], gu.Refs[0])
+	require.Same(t, gp, got.Schemas[1].Tables[0].Deps[0])
+}
+
+func TestMarshalJSON_Table(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+	posts := schema.NewTable("posts").AddColumns(schema.NewIntColumn("author_id", "int"))
+	posts.AddForeignKeys(schema.NewForeignKey("author").AddColumns(posts.Columns[0]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	schema.New("public").AddTables(users, posts)
+
+	b, err := schema.MarshalJSON(posts)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "$id": "$",
+  "Name": "posts",
+  "Schema": {"$ext": "schema", "name": "public"},
+  "Columns": [
+    {
+      "$id": "$.Columns[0]",
+      "Name": "author_id",
+      "Type": {"Type": {"type": "schema.IntegerType", "value": {"T": "int"}}},
+      "ForeignKeys": [{"$ref": "$.ForeignKeys[0]"}]
+    }
+  ],
+  "ForeignKeys": [
+    {
+      "$id": "$.ForeignKeys[0]",
+      "Symbol": "author",
+      "Table": {"$ref": "$"},
+      "Columns": [{"$ref": "$.Columns[0]"}],
+      "RefTable": {"$ext": "table", "schema": "public", "name": "users"},
+      "RefColumns": [{"$ext": "column", "schema": "public", "table": "users", "name": "id"}]
+    }
+  ]
+}`, string(b))
+
+	var got schema.Table
+	require.NoError(t, schema.UnmarshalJSON(b, &got))
+	require.True(t, schema.Equal(posts, &got))
+	require.Equal(t, "public", got.Schema.Name)
+	ref := got.ForeignKeys[0].RefTable
+	require.Equal(t, "users", ref.Name)
+	require.Same(t, got.Schema, ref.Schema)
+	require.Same(t, ref.Columns[0], got.ForeignKeys[0].RefColumns[0])
+}
+
+type unregisteredAttr struct{ schema.Attr }
+
+func TestMarshalJSON_Errors(t *testing.T) {
+	_, err := schema.MarshalJSON(schema.NewTable("t").AddAttrs(&unregisteredAttr{}))
+	require.EqualError(t, err, "sql/schema: type *schema_test.unregisteredAttr is not registered for JSON encoding")
+	_, err = schema.MarshalJSON(schema.Table{})
+	require.Error(t, err)
+
+	var tt schema.Table
+	require.EqualError(t, schema.UnmarshalJSON([]byte(`{"Attrs": [{"type": "unknown"}]}`), &tt), `sql/schema: JSON type "unknown" is not registered`)
+	require.EqualError(t, schema.UnmarshalJSON([]byte(`{"Columns": [{"Indexes": [{"$ref": "$.Indexes[0]"}]}]}`), &tt), `sql/schema: unknown JSON reference "$.Indexes[0]"`)
+
+	schema.RegisterJSONType("schema_test.unregisteredAttr", (*unregisteredAttr)(nil))
+	require.Panics(t, func() { schema.RegisterJSONType("schema_test.unregisteredAttr", (*unregisteredAttr)(nil)) })
+	_, err = schema.MarshalJSON(schema.NewTable("t").AddAttrs(&unregisteredAttr{}))
+	require.NoError(t, err)
+}
+
+func TestMarshalChanges(t *testing.T) {
+	from := schema.NewTable("users").
+		AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "varchar", schema.StringSize(100)))
+	from.AddIndexes(schema.NewIndex("name_idx").AddColumns(from.Columns[1]))
+	schema.New("public").AddTables(from)
+	to := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewStringColumn("name", "varchar", schema.StringSize(255)),
+			schema.NewNullStringColumn("email", "text"),
+		)
+	posts := schema.NewTable("posts").AddColumns(schema.NewIntColumn("author_id", "int"))
+	posts.AddForeignKeys(schema.NewForeignKey("author").AddColumns(posts.Columns[0]).SetRefTable(to).AddRefColumns(to.Columns[0]))
+	schema.New("public").AddTables(to, posts)
+	changes := []schema.Change{
+		&schema.ModifyTable{
+			T: to,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: to.Columns[2]},
+				&schema.ModifyColumn{From: from.Columns[1], To: to.Columns[1], Change: schema.ChangeType, Conversion: schema.ConversionSafe},
+				&schema.DropIndex{I: from.Indexes[0]},
+			},
+		},
+		&schema.AddTable{T: posts, Extra: []schema.Clause{&schema.IfNotExists{}}},
+	}
+	b1, err := schema.MarshalChanges(changes)
+	require.NoError(t, err)
+	got, err := schema.UnmarshalChanges(b1)
+	require.NoError(t, err)
+
+	// Encoding is stable.
+	b2, err := schema.MarshalChanges(got)
+	require.NoError(t, err)
+	require.Equal(t, string(b1), string(b2))
+
+	require.Len(t, got, 2)
+	m, ok := got[0].(*schema.ModifyTable)
+	require.True(t, ok)
+	require.True(t, schema.Equal(to, m.T))
+	require.Equal(t, "public", m.T.Schema.Name)
+	// Elements shared by changes are decoded once.
+	require.Same(t, m.T.Columns[2], m.Changes[0].(*schema.AddColumn).C)
+	mc := m.Changes[1].(*schema.ModifyColumn)
+	require.Same(t, m.T.Columns[1], mc.To)
+	require.Equal(t, 100, mc.From.Type.Type.(*schema.StringType).Size)
+	require.Equal(t, schema.ChangeType, mc.Change)
+	require.Equal(t, schema.ConversionSafe, mc.Conversion)
+	di := m.Changes[2].(*schema.DropIndex)
+	require.Equal(t, "name_idx", di.I.Name)
+	require.Equal(t, "name", di.I.Parts[0].C.Name)
+	add := got[1].(*schema.AddTable)
+	require.Equal(t, []schema.Clause{&schema.IfNotExists{}}, add.Extra)
+	require.Same(t, m.T, add.T.ForeignKeys[0].RefTable)
+	require.Same(t, m.T.Columns[0], add.T.ForeignKeys[0].RefColumns[0])
+	require.Same(t, m.T.Schema, add.T.Schema)
+}
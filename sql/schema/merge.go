@@ -0,0 +1,377 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type (
+	// MergeConflict describes an element of an overlay realm that
+	// conflicts with an element that was already merged.
+	MergeConflict struct {
+		// Overlay is the index of the conflicting overlay in the MergeRealms call.
+		Overlay int
+		// Name describes the conflicting element. e.g., `table "public.users"`.
+		Name string
+	}
+
+	// MergeError is returned by MergeRealms in case
+	// one or more elements of the overlays conflict.
+	MergeError struct {
+		Conflicts []*MergeConflict
+	}
+)
+
+// MergeRealms returns a new realm that is composed of the base realm and the given
+// overlays. It allows assembling the desired state from multiple sources, like a core
+// schema and the schemas of plugins that extend it. None of the given realms is modified.
+//
+// Schemas with the same name are merged into one schema, and the elements of the overlays
+// (e.g., tables, views, functions or objects) are added to them. An element that is already
+// defined by the base realm or by a previous overlay is merged only if the two definitions are
+// equal, which allows overlays to declare the elements they reference (e.g., a table that is
+// referenced by a foreign key). References to such elements are set to point to the merged ones.
+// Otherwise, the element is reported as a conflict, and a MergeError is returned.
+func MergeRealms(base *Realm, overlays ...*Realm) (*Realm, error) {
+	m := &merger{c: &cloner{}, r: NewRealm(), owned: make(map[ptrKey]bool)}
+	// Elements that are not owned by the merged realms (e.g., dependents defined
+	// in other overlays) are not copied, and are linked after all realms are merged.
+	m.c.keep = func(v reflect.Value) bool {
+		switch v.Interface().(type) {
+		case *Realm, *Schema, *Table, *View, *Func, *Proc, *Trigger, *Column, *Index, *ForeignKey:
+			return !m.owned[ptrKey{t: v.Type(), p: v.Pointer()}]
+		}
+		return false
+	}
+	if base != nil {
+		own(reflect.ValueOf(base), m.owned)
+		m.r = m.c.value(reflect.ValueOf(base)).Interface().(*Realm)
+	}
+	for i, o := range overlays {
+		if o != nil {
+			m.merge(i, o)
+		}
+	}
+	if len(m.conflicts) > 0 {
+		return nil, &MergeError{Conflicts: m.conflicts}
+	}
+	m.link()
+	return m.r, nil
+}
+
+type merger struct {
+	c         *cloner
+	r         *Realm
+	owned     map[ptrKey]bool // elements of the merged realms
+	conflicts []*MergeConflict
+	// merged holds the overlay elements that were merged
+	// with existing elements, and the elements they merged to.
+	merged [][2]Object
+}
+
+// merge merges the overlay realm o into the result realm.
+func (m *merger) merge(idx int, o *Realm) {
+	var (
+		added   []func()
+		reports = func(name string) {
+			m.conflicts = append(m.conflicts, &MergeConflict{Overlay: idx, Name: name})
+		}
+	)
+	own(reflect.ValueOf(o), m.owned)
+	m.c.set(o, m.r)
+	m.mergeAttrs(&m.r.Attrs, o.Attrs, "realm", reports)
+	// The first pass records the overlay elements that are merged with existing
+	// elements, to ensure that the references to them are set to the merged ones
+	// when the new elements are copied in the second pass.
+	for _, s := range o.Schemas {
+		ms, ok := m.r.Schema(s.Name)
+		if !ok {
+			ms = &Schema{Name: s.Name, Realm: m.r}
+			m.r.Schemas = append(m.r.Schemas, ms)
+		}
+		m.c.set(s, ms)
+		m.mergeAttrs(&ms.Attrs, s.Attrs, fmt.Sprintf("schema %q", s.Name), reports)
+		for _, t := range s.Tables {
+			switch mt, ok := ms.Table(t.Name); {
+			case !ok:
+				added = append(added, func() { ms.Tables = append(ms.Tables, m.clone(t).(*Table)) })
+			case sameDef(t, mt):
+				m.mergeTable(t, mt)
+			default:
+				reports(objName(t))
+			}
+		}
+		for _, v := range s.Views {
+			switch mv, ok := ms.View(v.Name); {
+			case !ok:
+				added = append(added, func() { ms.Views = append(ms.Views, m.clone(v).(*View)) })
+			case sameDef(v, mv):
+				m.mergeView(v, mv)
+			default:
+				reports(objName(v))
+			}
+		}
+		for _, f := range s.Funcs {
+			switch mf, ok := ms.Func(f.Name); {
+			case !ok:
+				added = append(added, func() { ms.Funcs = append(ms.Funcs, m.clone(f).(*Func)) })
+			case sameDef(f, mf):
+				m.mergeObj(f, mf)
+			default:
+				reports(objName(f))
+			}
+		}
+		for _, p := range s.Procs {
+			switch mp, ok := ms.Proc(p.Name); {
+			case !ok:
+				added = append(added, func() { ms.Procs = append(ms.Procs, m.clone(p).(*Proc)) })
+			case sameDef(p, mp):
+				m.mergeObj(p, mp)
+			default:
+				reports(objName(p))
+			}
+		}
+		added = append(added, m.mergeObjects(&ms.Objects, s.Objects, reports)...)
+	}
+	added = append(added, m.mergeObjects(&m.r.Objects, o.Objects, reports)...)
+	for _, add := range added {
+		add()
+	}
+	// Dependents of merged elements are added to their references.
+	for _, mo := range m.merged {
+		for _, ref := range refsOf(mo[0]) {
+			ref := m.clone(ref)
+			if a, ok := mo[1].(RefsAdder); ok && !slices.Contains(refsOf(mo[1]), ref) {
+				a.AddRefs(ref)
+			}
+		}
+	}
+	m.merged = nil
+}
+
+// link sets the references to elements that were not copied when
+// their referencing elements were merged, to point to their copies.
+func (m *merger) link() {
+	copies := make(map[ptrKey]bool, len(m.c.seen))
+	for _, v := range m.c.seen {
+		copies[ptrKey{t: v.Type(), p: v.Pointer()}] = true
+	}
+	var (
+		visit   func(reflect.Value)
+		visited = make(map[ptrKey]bool)
+		remap   = func(v reflect.Value) {
+			x := v
+			if x.Kind() == reflect.Interface {
+				x = x.Elem()
+			}
+			if x.Kind() == reflect.Pointer && !x.IsNil() {
+				if n, ok := m.c.seen[ptrKey{t: x.Type(), p: x.Pointer()}]; ok {
+					v.Set(n)
+				}
+			}
+		}
+	)
+	visit = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface:
+			if !v.IsNil() {
+				visit(v.Elem())
+			}
+		case reflect.Pointer:
+			k := ptrKey{t: v.Type(), p: v.Pointer()}
+			// Skip elements that are not part of the merged realm.
+			if v.IsNil() || visited[k] || !copies[k] && v.Pointer() != reflect.ValueOf(m.r).Pointer() {
+				return
+			}
+			visited[k] = true
+			visit(v.Elem())
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if f := v.Field(i); v.Type().Field(i).IsExported() {
+					if f.Kind() == reflect.Pointer || f.Kind() == reflect.Interface {
+						remap(f)
+					}
+					visit(f)
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				if e := v.Index(i); e.Kind() == reflect.Pointer || e.Kind() == reflect.Interface {
+					remap(e)
+				}
+				visit(v.Index(i))
+			}
+		}
+	}
+	visit(reflect.ValueOf(m.r))
+}
+
+// mergeObjects merges the overlay objects into the given list. Objects are matched
+// by their spec type and name, and objects that cannot be matched are always added.
+func (m *merger) mergeObjects(to *[]Object, objs []Object, reports func(string)) []func() {
+	var added []func()
+	for _, o := range objs {
+		i := -1
+		if _, ok := o.(SpecTypeNamer); ok {
+			i = slices.IndexFunc(*to, func(mo Object) bool {
+				_, ok := mo.(SpecTypeNamer)
+				return ok && objName(mo) == objName(o)
+			})
+		}
+		switch {
+		case i == -1:
+			added = append(added, func() { *to = append(*to, m.clone(o)) })
+		case sameDef(o, (*to)[i]):
+			m.mergeObj(o, (*to)[i])
+		default:
+			reports(objName(o))
+		}
+	}
+	return added
+}
+
+// mergeAttrs merges the overlay attributes into the given list. Attributes of the same kind
+// must be equal. Positions are skipped, as they describe the location of the base element.
+func (m *merger) mergeAttrs(to *[]Attr, attrs []Attr, owner string, reports func(string)) {
+	for _, a := range attrs {
+		if _, ok := a.(*Pos); ok {
+			continue
+		}
+		i := slices.IndexFunc(*to, func(ma Attr) bool {
+			return attrKey(ma) == attrKey(a)
+		})
+		switch {
+		case i == -1:
+			*to = append(*to, m.c.value(reflect.ValueOf(&a).Elem()).Interface().(Attr))
+		case !Equal(a, (*to)[i]):
+			reports(fmt.Sprintf("attribute %s of %s", strings.TrimPrefix(attrKey(a), "*schema."), owner))
+		}
+	}
+}
+
+// mergeTable records the overlay table and its children as merged with the table mt.
+func (m *merger) mergeTable(t, mt *Table) {
+	m.mergeObj(t, mt)
+	for _, c := range t.Columns {
+		if mc, ok := mt.Column(c.Name); ok {
+			m.c.set(c, mc)
+		}
+	}
+	for _, idx := range t.Indexes {
+		if mi, ok := mt.Index(idx.Name); ok {
+			m.c.set(idx, mi)
+		}
+	}
+	if t.PrimaryKey != nil && mt.PrimaryKey != nil {
+		m.c.set(t.PrimaryKey, mt.PrimaryKey)
+	}
+	for _, fk := range t.ForeignKeys {
+		if mf, ok := mt.ForeignKey(fk.Symbol); ok {
+			m.c.set(fk, mf)
+		}
+	}
+	for _, tr := range t.Triggers {
+		if mtr, ok := mt.Trigger(tr.Name); ok {
+			m.c.set(tr, mtr)
+		}
+	}
+}
+
+// mergeView records the overlay view and its children as merged with the view mv.
+func (m *merger) mergeView(v, mv *View) {
+	m.mergeObj(v, mv)
+	for _, c := range v.Columns {
+		if mc, ok := mv.Column(c.Name); ok {
+			m.c.set(c, mc)
+		}
+	}
+	for _, tr := range v.Triggers {
+		if mtr, ok := mv.Trigger(tr.Name); ok {
+			m.c.set(tr, mtr)
+		}
+	}
+}
+
+// mergeObj records the overlay object o as merged with the object mo.
+func (m *merger) mergeObj(o, mo Object) {
+	m.c.set(o, mo)
+	m.merged = append(m.merged, [2]Object{o, mo})
+}
+
+// clone returns the copy of the given overlay object.
+func (m *merger) clone(o Object) Object {
+	return m.c.value(reflect.ValueOf(&o).Elem()).Interface().(Object)
+}
+
+// set records the given value as the copy of the given pointer.
+func (c *cloner) set(from, to any) {
+	if c.seen == nil {
+		c.seen = make(map[ptrKey]reflect.Value)
+	}
+	v := reflect.ValueOf(from)
+	c.seen[ptrKey{t: v.Type(), p: v.Pointer()}] = reflect.ValueOf(to)
+}
+
+// sameDef reports if the two objects have the same definition. The objects that
+// depend on them are not compared, as each realm holds its own dependents.
+func sameDef(a, b Object) bool {
+	switch a := a.(type) {
+	case *Table:
+		a1, b1 := *a, *b.(*Table)
+		a1.Refs, b1.Refs = nil, nil
+		return Equal(&a1, &b1)
+	case *View:
+		a1, b1 := *a, *b.(*View)
+		a1.Refs, b1.Refs = nil, nil
+		return Equal(&a1, &b1)
+	case *Func:
+		a1, b1 := *a, *b.(*Func)
+		a1.Refs, b1.Refs = nil, nil
+		return Equal(&a1, &b1)
+	case *Proc:
+		a1, b1 := *a, *b.(*Proc)
+		a1.Refs, b1.Refs = nil, nil
+		return Equal(&a1, &b1)
+	default:
+		return Equal(a, b)
+	}
+}
+
+// refsOf returns the objects that depend on the given object.
+func refsOf(o Object) []Object {
+	switch o := o.(type) {
+	case *Table:
+		return o.Refs
+	case *View:
+		return o.Refs
+	case *Func:
+		return o.Refs
+	case *Proc:
+		return o.Refs
+	}
+	return nil
+}
+
+// attrKey returns the key that identifies the kind of the attribute.
+func attrKey(a Attr) string {
+	k := reflect.TypeOf(a).String()
+	if e, ok := a.(*ExtAttr); ok {
+		k += " " + e.Name
+	}
+	return k
+}
+
+// Error implements the error interface.
+func (e *MergeError) Error() string {
+	cs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		cs[i] = fmt.Sprintf("%s (overlay %d)", c.Name, c.Overlay)
+	}
+	return fmt.Sprintf("sql/schema: merge realms: %d conflict(s): %s", len(cs), strings.Join(cs, ", "))
+}
@@ -0,0 +1,87 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRealms(t *testing.T) {
+	var (
+		core = func() *schema.Realm {
+			users := schema.NewTable("users").
+				AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text"))
+			users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+			return schema.NewRealm(schema.New("public").SetCharset("utf8mb4").AddTables(users))
+		}
+		base = core()
+		// The billing plugin declares the core table it references.
+		billing = func() *schema.Realm {
+			r := core()
+			users, _ := r.Schemas[0].Table("users")
+			invoices := schema.NewTable("invoices").
+				AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("user_id", "int"))
+			invoices.AddForeignKeys(
+				schema.NewForeignKey("user_fk").AddColumns(invoices.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+			)
+			r.Schemas[0].AddTables(invoices)
+			r.AddSchemas(schema.New("billing").AddTables(schema.NewTable("plans").AddColumns(schema.NewIntColumn("id", "int"))))
+			return r
+		}()
+	)
+	// The audit plugin references the base table directly.
+	baseUsers, _ := base.Schemas[0].Table("users")
+	logs := schema.NewTable("logs").AddColumns(schema.NewIntColumn("user_id", "int"))
+	logs.AddDeps(baseUsers)
+	audit := schema.NewRealm(schema.New("public").AddTables(logs))
+
+	r, err := schema.MergeRealms(base, billing, audit)
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+	public, plans := r.Schemas[0], r.Schemas[1]
+	require.Equal(t, "billing", plans.Name)
+	require.Same(t, r, plans.Realm)
+	require.Len(t, public.Tables, 3)
+	users, invoices, logs2 := public.Tables[0], public.Tables[1], public.Tables[2]
+	require.Equal(t, []string{"users", "invoices", "logs"}, []string{users.Name, invoices.Name, logs2.Name})
+	for _, t1 := range public.Tables {
+		require.Same(t, public, t1.Schema)
+	}
+	// References are set to the merged elements.
+	require.Same(t, users, invoices.ForeignKeys[0].RefTable)
+	require.Same(t, users.Columns[0], invoices.ForeignKeys[0].RefColumns[0])
+	require.Len(t, logs2.Deps, 1)
+	require.Same(t, users, logs2.Deps[0])
+	require.Len(t, users.Refs, 1)
+	require.Same(t, logs2, users.Refs[0])
+
+	// Inputs are not modified.
+	require.Len(t, base.Schemas, 1)
+	require.Len(t, base.Schemas[0].Tables, 1)
+	require.Len(t, baseUsers.Refs, 1)
+	require.Same(t, logs, baseUsers.Refs[0])
+	require.NotSame(t, logs, logs2)
+
+	// Conflicting definitions.
+	other := schema.NewRealm(
+		schema.New("public").
+			SetCharset("latin1").
+			AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"))),
+	)
+	_, err = schema.MergeRealms(base, audit, other)
+	require.EqualError(t, err, `sql/schema: merge realms: 2 conflict(s): attribute Charset of schema "public" (overlay 1), table public.users (overlay 1)`)
+	var merr *schema.MergeError
+	require.ErrorAs(t, err, &merr)
+	require.Len(t, merr.Conflicts, 2)
+
+	// Merging without a base realm.
+	r, err = schema.MergeRealms(nil, base)
+	require.NoError(t, err)
+	require.True(t, schema.Equal(base, r))
+}
@@ -7,7 +7,9 @@ package schema
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
 	"time"
 )
 
@@ -87,6 +89,100 @@ type (
 		From, To *Table
 	}
 
+	// AddView describes a view creation change.
+	AddView struct {
+		V     *View
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropView describes a view removal change.
+	DropView struct {
+		V     *View
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyView describes a view modification change. The definition
+	// of the view was changed if it differs between From and To, and
+	// the Changes hold the changes to its attributes (e.g., comment).
+	ModifyView struct {
+		From, To *View
+		Changes  []Change
+	}
+
+	// RenameView describes a view rename change.
+	RenameView struct {
+		From, To *View
+	}
+
+	// AddFunc describes a function creation change.
+	AddFunc struct {
+		F     *Func
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropFunc describes a function removal change.
+	DropFunc struct {
+		F     *Func
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyFunc describes a function modification change.
+	ModifyFunc struct {
+		From, To *Func
+		Changes  []Change
+	}
+
+	// RenameFunc describes a function rename change.
+	RenameFunc struct {
+		From, To *Func
+	}
+
+	// AddProc describes a procedure creation change.
+	AddProc struct {
+		P     *Proc
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropProc describes a procedure removal change.
+	DropProc struct {
+		P     *Proc
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyProc describes a procedure modification change.
+	ModifyProc struct {
+		From, To *Proc
+		Changes  []Change
+	}
+
+	// RenameProc describes a procedure rename change.
+	RenameProc struct {
+		From, To *Proc
+	}
+
+	// AddTrigger describes a trigger creation change.
+	AddTrigger struct {
+		T     *Trigger
+		Extra []Clause // Extra clauses and options.
+	}
+
+	// DropTrigger describes a trigger removal change.
+	DropTrigger struct {
+		T     *Trigger
+		Extra []Clause // Extra clauses.
+	}
+
+	// ModifyTrigger describes a trigger modification change.
+	ModifyTrigger struct {
+		From, To *Trigger
+		Changes  []Change
+	}
+
+	// RenameTrigger describes a trigger rename change.
+	RenameTrigger struct {
+		From, To *Trigger
+	}
+
 	// AddObject describes a generic object creation change.
 	AddObject struct {
 		O     Object
@@ -125,7 +221,10 @@ type (
 	ModifyColumn struct {
 		From, To *Column
 		Change   ChangeKind
-		Extra    []Clause // Extra clauses and options.
+		// Conversion classifies the type change of the column, if
+		// it was changed and the dialect supports classifying it.
+		Conversion TypeConversion
+		Extra      []Clause // Extra clauses and options.
 	}
 
 	// RenameColumn describes a column rename change.
@@ -213,6 +312,32 @@ type (
 		From, To Object // PK, FK, Unique, Check, etc.
 	}
 
+	// DataChange describes a data migration that is attached to structural changes.
+	// For example, backfilling the values of a column before it is set to NOT NULL.
+	// A DataChange can be planned as a top-level change, or as a change of a ModifyTable,
+	// in which case it is planned after the structural changes of the table. A backfill
+	// of a NOT NULL column that is added in the same ModifyTable is planned by adding the
+	// column as nullable, backfilling it, and then setting it to NOT NULL.
+	DataChange struct {
+		T *Table
+		// C is the backfilled column. Required if Expr is set.
+		C *Column
+		// Expr is the backfill expression of the column. It is planned as:
+		// UPDATE <T> SET <C> = <Expr> WHERE <Where>.
+		Expr Expr
+		// Where is an optional raw filter for the backfill. Defaults
+		// to "<C> IS NULL", to fill only the rows that are missing values.
+		Where string
+		// Stmt is an optional statement template that is planned instead of
+		// the backfill. The template is executed with the quoted identifiers
+		// of the table and the column as ".Table" and ".Column". For example:
+		//
+		//	UPDATE {{ .Table }} SET {{ .Column }} = lower({{ .Column }})
+		Stmt string
+		// Comment describes the data change in the migration file.
+		Comment string
+	}
+
 	// AddAttr describes an attribute addition.
 	AddAttr struct {
 		A Attr
@@ -294,6 +419,34 @@ const (
 	ChangeDeleteAction
 )
 
+// A TypeConversion classifies the safety of converting the
+// values of a column from one type to another.
+type TypeConversion uint8
+
+// List of type conversions.
+const (
+	ConversionUnknown      TypeConversion = iota // Conversion was not classified.
+	ConversionSafe                               // All values are preserved, e.g. INT to BIGINT.
+	ConversionLossy                              // Values may be truncated or lose precision, e.g. VARCHAR(255) to VARCHAR(50).
+	ConversionIncompatible                       // Values cannot be converted implicitly, e.g. JSON to INT.
+)
+
+// String implements the fmt.Stringer interface.
+func (c TypeConversion) String() string {
+	switch c {
+	case ConversionUnknown:
+		return "unknown"
+	case ConversionSafe:
+		return "safe"
+	case ConversionLossy:
+		return "lossy"
+	case ConversionIncompatible:
+		return "incompatible"
+	default:
+		return fmt.Sprintf("TypeConversion(%d)", c)
+	}
+}
+
 // List of diff modes.
 const (
 	DiffModeUnset         DiffMode = 1 << iota // Default, backwards compatability.
@@ -346,6 +499,43 @@ type (
 		// AskFunc can be implemented by the caller to
 		// make diff process interactive.
 		AskFunc func(string, []string) (string, error)
+
+		// RenameResolver, if set, enables the detection of probable renames of tables
+		// and columns. Each detected candidate is passed to the resolver, and confirmed
+		// candidates are returned as rename changes instead of pairs of drop and add.
+		RenameResolver func(*RenameCandidate) (bool, error)
+
+		// IgnoreRules holds the classes of differences that are ignored
+		// by the Differ. e.g., comments or charset and collation changes.
+		IgnoreRules []*IgnoreRule
+	}
+
+	// An IgnoreRule describes a class of differences that are ignored by the Differ.
+	// Rules are evaluated on the computed changes, and can be composed by passing
+	// multiple options. For example:
+	//
+	//	NewDiffOptions(DiffIgnoreComments(), DiffIgnoreIndexes(regexp.MustCompile(`^tmp_`)))
+	IgnoreRule struct {
+		// Name of the rule. e.g., "comments".
+		Name string
+		// Attr reports if changes to the given attribute are ignored.
+		Attr func(Attr) bool
+		// Kind holds the change kinds that are ignored in modification
+		// changes. e.g., ChangeComment for column or index comments.
+		Kind ChangeKind
+		// Index reports if changes to the given index are ignored.
+		Index func(*Index) bool
+	}
+
+	// RenameCandidate describes a probable rename of a table or a column that
+	// was detected by the Differ, and can be confirmed by the RenameResolver.
+	RenameCandidate struct {
+		// From and To hold the renamed elements. Either *Table or *Column.
+		From, To any
+		// T is the table of the renamed column. Nil for table renames.
+		T *Table
+		// Score reports the confidence of the detection, between 0 and 1.
+		Score float64
 	}
 
 	// DiffOption allows configuring the DiffOptions using functional options.
@@ -386,6 +576,139 @@ func DiffNormalized() DiffOption {
 	}
 }
 
+// DiffDetectRenames returns a DiffOption that enables the detection of probable
+// renames, using the given function to resolve (confirm or reject) them. Tables are
+// considered as renamed if most of their columns are identical, and columns if their
+// definitions are identical. For example, to accept all renames with a high score:
+//
+//	DiffDetectRenames(func(c *RenameCandidate) (bool, error) {
+//		return c.Score >= 0.8, nil
+//	})
+func DiffDetectRenames(resolve func(*RenameCandidate) (bool, error)) DiffOption {
+	return func(o *DiffOptions) {
+		o.RenameResolver = resolve
+	}
+}
+
+// DiffIgnore returns a DiffOption that ignores the differences described by the given rules.
+func DiffIgnore(rules ...*IgnoreRule) DiffOption {
+	return func(o *DiffOptions) {
+		o.IgnoreRules = append(o.IgnoreRules, rules...)
+	}
+}
+
+// DiffIgnoreComments returns a DiffOption for ignoring comment changes of all schema elements.
+func DiffIgnoreComments() DiffOption {
+	return DiffIgnore(&IgnoreRule{
+		Name: "comments",
+		Attr: func(a Attr) bool {
+			_, ok := a.(*Comment)
+			return ok
+		},
+		Kind: ChangeComment,
+	})
+}
+
+// DiffIgnoreCharset returns a DiffOption for ignoring character-set and collation changes.
+func DiffIgnoreCharset() DiffOption {
+	return DiffIgnore(&IgnoreRule{
+		Name: "charset",
+		Attr: func(a Attr) bool {
+			switch a.(type) {
+			case *Charset, *Collation:
+				return true
+			}
+			return false
+		},
+		Kind: ChangeCharset | ChangeCollate,
+	})
+}
+
+// DiffIgnoreAutoIncrement returns a DiffOption for ignoring changes to the AUTO_INCREMENT
+// attributes of tables and columns, which are defined by the drivers as AutoIncrement
+// types (e.g., mysql.AutoIncrement or sqlite.AutoIncrement).
+func DiffIgnoreAutoIncrement() DiffOption {
+	return DiffIgnore(&IgnoreRule{
+		Name: "auto_increment",
+		Attr: func(a Attr) bool {
+			t := reflect.TypeOf(a)
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			return t.Name() == "AutoIncrement"
+		},
+	})
+}
+
+// DiffIgnoreDefaults returns a DiffOption for ignoring changes to the database defaults of
+// columns, which is common when defaults are managed by the application (e.g., an ORM) and
+// described using LogicalDefault. Inspected defaults are kept in Column.Default, and added
+// columns are still created with the defaults of the desired state.
+func DiffIgnoreDefaults() DiffOption {
+	return DiffIgnore(&IgnoreRule{
+		Name: "defaults",
+		Kind: ChangeDefault,
+	})
+}
+
+// DiffIgnoreIndexes returns a DiffOption for ignoring changes to indexes with names matching the pattern.
+func DiffIgnoreIndexes(pattern *regexp.Regexp) DiffOption {
+	return DiffIgnore(&IgnoreRule{
+		Name: "indexes",
+		Index: func(idx *Index) bool {
+			return pattern.MatchString(idx.Name)
+		},
+	})
+}
+
+// IgnoredAttr reports whether changes to the given attribute should be ignored.
+func (o *DiffOptions) IgnoredAttr(a Attr) bool {
+	for _, r := range o.IgnoreRules {
+		if r.Attr != nil && a != nil && r.Attr(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// IgnoredKind returns the change kinds that should be ignored in modification changes.
+func (o *DiffOptions) IgnoredKind() ChangeKind {
+	var k ChangeKind
+	for _, r := range o.IgnoreRules {
+		k |= r.Kind
+	}
+	return k
+}
+
+// IgnoredIndex reports whether changes to the given index should be ignored.
+func (o *DiffOptions) IgnoredIndex(idx *Index) bool {
+	for _, r := range o.IgnoreRules {
+		if r.Index != nil && idx != nil && r.Index(idx) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a human-readable description of the candidate. e.g., for prompts.
+func (c *RenameCandidate) String() string {
+	switch from := c.From.(type) {
+	case *Table:
+		if to, ok := c.To.(*Table); ok {
+			return fmt.Sprintf("rename table %q to %q", from.Name, to.Name)
+		}
+	case *Column:
+		to, ok := c.To.(*Column)
+		switch {
+		case ok && c.T != nil:
+			return fmt.Sprintf("rename column %q to %q on table %q", from.Name, to.Name, c.T.Name)
+		case ok:
+			return fmt.Sprintf("rename column %q to %q", from.Name, to.Name)
+		}
+	}
+	return fmt.Sprintf("rename %T to %T", c.From, c.To)
+}
+
 // Skipped reports whether the given change should be skipped.
 func (o *DiffOptions) Skipped(c Change) bool {
 	for _, s := range o.SkipChanges {
@@ -567,6 +890,22 @@ func (*AddTable) change()         {}
 func (*DropTable) change()        {}
 func (*ModifyTable) change()      {}
 func (*RenameTable) change()      {}
+func (*AddView) change()          {}
+func (*DropView) change()         {}
+func (*ModifyView) change()       {}
+func (*RenameView) change()       {}
+func (*AddFunc) change()          {}
+func (*DropFunc) change()         {}
+func (*ModifyFunc) change()       {}
+func (*RenameFunc) change()       {}
+func (*AddProc) change()          {}
+func (*DropProc) change()         {}
+func (*ModifyProc) change()       {}
+func (*RenameProc) change()       {}
+func (*AddTrigger) change()       {}
+func (*DropTrigger) change()      {}
+func (*ModifyTrigger) change()    {}
+func (*RenameTrigger) change()    {}
 func (*AddObject) change()        {}
 func (*DropObject) change()       {}
 func (*ModifyObject) change()     {}
@@ -589,6 +928,7 @@ func (*AddForeignKey) change()    {}
 func (*DropForeignKey) change()   {}
 func (*ModifyForeignKey) change() {}
 func (*RenameConstraint) change() {}
+func (*DataChange) change()       {}
 
 // clauses.
 func (*IfExists) clause()    {}
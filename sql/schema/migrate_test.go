@@ -7,6 +7,7 @@ package schema_test
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -124,3 +125,34 @@ func ExampleChanges_RemoveIndex() {
 	// *schema.AddColumn(created_at)
 	// *schema.RenameColumn(old_name -> new_name)
 }
+
+type AutoIncrement struct {
+	schema.Attr
+	V int64
+}
+
+func TestDiffOptions_Ignore(t *testing.T) {
+	opts := schema.NewDiffOptions()
+	require.False(t, opts.IgnoredAttr(&schema.Comment{}))
+	require.Equal(t, schema.NoChange, opts.IgnoredKind())
+
+	opts = schema.NewDiffOptions(
+		schema.DiffIgnoreComments(),
+		schema.DiffIgnoreCharset(),
+		schema.DiffIgnoreAutoIncrement(),
+		schema.DiffIgnoreIndexes(regexp.MustCompile(`^tmp_`)),
+	)
+	require.Len(t, opts.IgnoreRules, 4)
+	require.True(t, opts.IgnoredAttr(&schema.Comment{}))
+	require.True(t, opts.IgnoredAttr(&schema.Charset{}))
+	require.True(t, opts.IgnoredAttr(&schema.Collation{}))
+	require.True(t, opts.IgnoredAttr(&AutoIncrement{}))
+	require.False(t, opts.IgnoredAttr(&schema.Check{}))
+	require.Equal(t, schema.ChangeComment|schema.ChangeCharset|schema.ChangeCollate, opts.IgnoredKind())
+	require.True(t, opts.IgnoredIndex(schema.NewIndex("tmp_idx")))
+	require.False(t, opts.IgnoredIndex(schema.NewIndex("idx")))
+
+	opts = schema.NewDiffOptions(schema.DiffIgnoreDefaults())
+	require.Equal(t, schema.ChangeDefault, opts.IgnoredKind())
+	require.False(t, opts.IgnoredAttr(&schema.LogicalDefault{}))
+}
@@ -0,0 +1,215 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// A ChangePolicy defines which kinds of changes are allowed to be planned. Planners
+	// consult the policy before planning a changeset, and fail if one of its changes is
+	// blocked. This allows blocking destructive operations (e.g., dropping tables or
+	// columns) regardless of the command or the workflow that produced the changes.
+	ChangePolicy struct {
+		// Rules maps a kind of change to its action. Kinds
+		// that are not set in the map are allowed.
+		Rules map[PolicyKind]PolicyAction
+
+		// Lossy reports if changing the type of a column from its
+		// "from" definition to its "to" definition may lose data.
+		// If nil, type changes that are not classified as safe by
+		// the dialect (see ModifyColumn.Conversion) are lossy.
+		Lossy func(from, to *Column) bool
+
+		// Warn is called for each change that its action is PolicyWarn.
+		Warn func(*PolicyViolation)
+	}
+
+	// PolicyKind identifies a kind of change that can be controlled by a ChangePolicy.
+	PolicyKind string
+
+	// PolicyAction describes the outcome of a ChangePolicy for a change.
+	PolicyAction uint8
+
+	// PolicyViolation describes a change that matched
+	// a non-allowed rule of a ChangePolicy.
+	PolicyViolation struct {
+		Kind   PolicyKind
+		Action PolicyAction
+		Change Change
+		// T is the table the change belongs to,
+		// for changes nested in a ModifyTable.
+		T *Table
+	}
+
+	// PolicyError is returned by ChangePolicy.Check
+	// in case one or more changes were blocked.
+	PolicyError struct {
+		Violations []*PolicyViolation
+	}
+)
+
+// List of policy actions.
+const (
+	PolicyAllow PolicyAction = iota // Change is planned.
+	PolicyWarn                      // Change is planned, and reported to ChangePolicy.Warn.
+	PolicyBlock                     // Planning fails.
+)
+
+// List of change kinds supported by ChangePolicy.
+const (
+	PolicyDropSchema      PolicyKind = "drop_schema"
+	PolicyDropTable       PolicyKind = "drop_table"
+	PolicyDropColumn      PolicyKind = "drop_column"
+	PolicyDropIndex       PolicyKind = "drop_index"
+	PolicyDropForeignKey  PolicyKind = "drop_foreign_key"
+	PolicyDropView        PolicyKind = "drop_view"
+	PolicyDropFunc        PolicyKind = "drop_func"
+	PolicyDropProc        PolicyKind = "drop_proc"
+	PolicyDropTrigger     PolicyKind = "drop_trigger"
+	PolicyDropObject      PolicyKind = "drop_object"
+	PolicyModifyTypeLossy PolicyKind = "modify_type_lossy"
+)
+
+// String implements the fmt.Stringer interface.
+func (a PolicyAction) String() string {
+	switch a {
+	case PolicyAllow:
+		return "allow"
+	case PolicyWarn:
+		return "warn"
+	case PolicyBlock:
+		return "block"
+	default:
+		return fmt.Sprintf("PolicyAction(%d)", a)
+	}
+}
+
+// Check applies the policy on the given changes. Changes that their action is
+// PolicyWarn are passed to the Warn function, and a PolicyError is returned
+// if one or more changes are blocked. A nil policy allows all changes.
+func (p *ChangePolicy) Check(changes []Change) error {
+	if p == nil {
+		return nil
+	}
+	var blocked []*PolicyViolation
+	for _, v := range p.violations(changes, nil) {
+		switch v.Action {
+		case PolicyWarn:
+			if p.Warn != nil {
+				p.Warn(v)
+			}
+		case PolicyBlock:
+			blocked = append(blocked, v)
+		}
+	}
+	if len(blocked) > 0 {
+		return &PolicyError{Violations: blocked}
+	}
+	return nil
+}
+
+// violations returns the changes that matched a non-allowed rule.
+func (p *ChangePolicy) violations(changes []Change, t *Table) []*PolicyViolation {
+	var vs []*PolicyViolation
+	for _, c := range changes {
+		if m, ok := c.(*ModifyTable); ok {
+			vs = append(vs, p.violations(m.Changes, m.T)...)
+			continue
+		}
+		k, ok := p.kind(c)
+		if !ok {
+			continue
+		}
+		if a := p.Rules[k]; a != PolicyAllow {
+			vs = append(vs, &PolicyViolation{Kind: k, Action: a, Change: c, T: t})
+		}
+	}
+	return vs
+}
+
+// kind returns the policy kind of the change, if it has one.
+func (p *ChangePolicy) kind(c Change) (PolicyKind, bool) {
+	switch c := c.(type) {
+	case *DropSchema:
+		return PolicyDropSchema, true
+	case *DropTable:
+		return PolicyDropTable, true
+	case *DropColumn:
+		return PolicyDropColumn, true
+	case *DropIndex:
+		return PolicyDropIndex, true
+	case *DropForeignKey:
+		return PolicyDropForeignKey, true
+	case *DropView:
+		return PolicyDropView, true
+	case *DropFunc:
+		return PolicyDropFunc, true
+	case *DropProc:
+		return PolicyDropProc, true
+	case *DropTrigger:
+		return PolicyDropTrigger, true
+	case *DropObject:
+		return PolicyDropObject, true
+	case *ModifyColumn:
+		if !c.Change.Is(ChangeType) {
+			break
+		}
+		lossy := c.Conversion != ConversionSafe
+		if p.Lossy != nil {
+			lossy = p.Lossy(c.From, c.To)
+		}
+		if lossy {
+			return PolicyModifyTypeLossy, true
+		}
+	}
+	return "", false
+}
+
+// String implements the fmt.Stringer interface.
+func (v *PolicyViolation) String() string {
+	var s string
+	switch c := v.Change.(type) {
+	case *DropSchema:
+		s = fmt.Sprintf("drop schema %q", c.S.Name)
+	case *DropTable:
+		s = fmt.Sprintf("drop table %q", c.T.Name)
+	case *DropColumn:
+		s = fmt.Sprintf("drop column %q", c.C.Name)
+	case *DropIndex:
+		s = fmt.Sprintf("drop index %q", c.I.Name)
+	case *DropForeignKey:
+		s = fmt.Sprintf("drop foreign key %q", c.F.Symbol)
+	case *DropView:
+		s = fmt.Sprintf("drop view %q", c.V.Name)
+	case *DropFunc:
+		s = fmt.Sprintf("drop function %q", c.F.Name)
+	case *DropProc:
+		s = fmt.Sprintf("drop procedure %q", c.P.Name)
+	case *DropTrigger:
+		s = fmt.Sprintf("drop trigger %q", c.T.Name)
+	case *DropObject:
+		s = "drop " + objName(c.O)
+	case *ModifyColumn:
+		s = fmt.Sprintf("modify type of column %q", c.To.Name)
+	default:
+		s = fmt.Sprintf("%T", c)
+	}
+	if v.T != nil {
+		s += fmt.Sprintf(" of table %q", v.T.Name)
+	}
+	return s
+}
+
+// Error implements the error interface.
+func (e *PolicyError) Error() string {
+	vs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		vs[i] = v.String()
+	}
+	return fmt.Sprintf("sql/schema: change policy blocked %d change(s): %s", len(vs), strings.Join(vs, ", "))
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePolicy_Check(t *testing.T) {
+	var (
+		name  = schema.NewStringColumn("name", "text")
+		age   = schema.NewIntColumn("age", "int")
+		users = schema.NewTable("users").AddColumns(name, age)
+		posts = schema.NewTable("posts")
+	)
+	changes := []schema.Change{
+		&schema.DropTable{T: posts},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.DropColumn{C: name},
+				&schema.ModifyColumn{From: age, To: schema.NewIntColumn("age", "bigint"), Change: schema.ChangeType},
+			},
+		},
+	}
+	var p *schema.ChangePolicy
+	require.NoError(t, p.Check(changes), "nil policy allows all changes")
+	require.NoError(t, (&schema.ChangePolicy{}).Check(changes), "empty policy allows all changes")
+
+	var warned []string
+	p = &schema.ChangePolicy{
+		Rules: map[schema.PolicyKind]schema.PolicyAction{
+			schema.PolicyDropTable:       schema.PolicyWarn,
+			schema.PolicyDropColumn:      schema.PolicyBlock,
+			schema.PolicyModifyTypeLossy: schema.PolicyBlock,
+		},
+		Warn: func(v *schema.PolicyViolation) {
+			warned = append(warned, v.String())
+		},
+	}
+	err := p.Check(changes)
+	require.EqualError(t, err, `sql/schema: change policy blocked 2 change(s): drop column "name" of table "users", modify type of column "age" of table "users"`)
+	var perr *schema.PolicyError
+	require.ErrorAs(t, err, &perr)
+	require.Len(t, perr.Violations, 2)
+	require.Equal(t, schema.PolicyDropColumn, perr.Violations[0].Kind)
+	require.Equal(t, users, perr.Violations[0].T)
+	require.Equal(t, []string{`drop table "posts"`}, warned)
+
+	// Type changes that were classified as safe are allowed.
+	changes[1].(*schema.ModifyTable).Changes[1].(*schema.ModifyColumn).Conversion = schema.ConversionSafe
+	err = p.Check(changes)
+	require.EqualError(t, err, `sql/schema: change policy blocked 1 change(s): drop column "name" of table "users"`)
+	changes[1].(*schema.ModifyTable).Changes[1].(*schema.ModifyColumn).Conversion = schema.ConversionUnknown
+
+	// Lossy overrides the classification of the dialect.
+	p.Lossy = func(from, to *schema.Column) bool {
+		return from.Type.Type.(*schema.IntegerType).T != "int" || to.Type.Type.(*schema.IntegerType).T != "bigint"
+	}
+	err = p.Check(changes)
+	require.EqualError(t, err, `sql/schema: change policy blocked 1 change(s): drop column "name" of table "users"`)
+}
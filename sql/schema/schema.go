@@ -5,6 +5,8 @@
 package schema
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -25,6 +27,8 @@ type (
 		Realm   *Realm
 		Tables  []*Table
 		Views   []*View
+		Funcs   []*Func
+		Procs   []*Proc
 		Attrs   []Attr   // Attrs and options.
 		Objects []Object // Schema-level objects (e.g., types or sequences).
 	}
@@ -44,6 +48,7 @@ type (
 		Indexes     []*Index
 		PrimaryKey  *Index
 		ForeignKeys []*ForeignKey
+		Triggers    []*Trigger
 		Attrs       []Attr   // Attrs, constraints and options.
 		Deps        []Object // Objects this table depends on.
 		Refs        []Object // Objects that depends on this table.
@@ -51,12 +56,78 @@ type (
 
 	// A View represents a view definition.
 	View struct {
-		Name    string
-		Schema  *Schema
-		Def     string
-		Columns []*Column
-		Attrs   []Attr
-		Deps    []Object // Objects this view depends on.
+		Name     string
+		Schema   *Schema
+		Def      string
+		Columns  []*Column
+		Triggers []*Trigger
+		Attrs    []Attr
+		Deps     []Object // Objects this view depends on.
+		Refs     []Object // Objects that depends on this view.
+	}
+
+	// A Func represents a function definition.
+	Func struct {
+		Name   string
+		Schema *Schema
+		Args   []*FuncArg
+		Ret    Type     // Return type. e.g., int, text or a driver-specific type.
+		Body   string   // Function body only.
+		Lang   string   // Implementation language. e.g., SQL or PL/pgSQL.
+		Attrs  []Attr   // Extra driver-specific attributes.
+		Deps   []Object // Objects this function depends on.
+		Refs   []Object // Objects that depends on this function.
+	}
+
+	// A Proc represents a procedure definition.
+	Proc struct {
+		Name   string
+		Schema *Schema
+		Args   []*FuncArg
+		Body   string   // Procedure body only.
+		Lang   string   // Implementation language. e.g., SQL or PL/pgSQL.
+		Attrs  []Attr   // Extra driver-specific attributes.
+		Deps   []Object // Objects this procedure depends on.
+		Refs   []Object // Objects that depends on this procedure.
+	}
+
+	// A FuncArg represents a single function or procedure argument.
+	FuncArg struct {
+		Name    string      // Optional name.
+		Type    Type        // Argument type.
+		Default Expr        // Default value, if exists.
+		Mode    FuncArgMode // Argument mode. e.g., IN or OUT.
+		Attrs   []Attr      // Extra driver-specific attributes.
+	}
+
+	// FuncArgMode represents the mode of a function or procedure argument.
+	FuncArgMode string
+
+	// A Trigger represents a trigger definition.
+	Trigger struct {
+		Name string
+		// The table or the view the trigger is defined on.
+		// Exactly one of them is set.
+		Table      *Table
+		View       *View
+		ActionTime TriggerTime    // BEFORE, AFTER or INSTEAD OF.
+		Events     []TriggerEvent // INSERT, UPDATE, DELETE, etc.
+		For        TriggerFor     // FOR EACH ROW or FOR EACH STATEMENT.
+		Body       string         // Trigger body only.
+		Attrs      []Attr         // Extra driver-specific attributes. e.g., WHEN clause.
+		Deps       []Object       // Objects this trigger depends on.
+	}
+
+	// TriggerTime represents the trigger action time.
+	TriggerTime string
+
+	// TriggerFor represents the trigger FOR EACH spec.
+	TriggerFor string
+
+	// TriggerEvent represents a trigger event.
+	TriggerEvent struct {
+		Name    string    // INSERT, UPDATE, DELETE, etc.
+		Columns []*Column // Columns of the UPDATE OF event, if set.
 	}
 
 	// A Column represents a column definition.
@@ -177,6 +248,36 @@ func (s *Schema) Table(name string) (*Table, bool) {
 	return nil, false
 }
 
+// View returns the first view that matched the given name.
+func (s *Schema) View(name string) (*View, bool) {
+	for _, v := range s.Views {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// Func returns the first function that matched the given name.
+func (s *Schema) Func(name string) (*Func, bool) {
+	for _, f := range s.Funcs {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Proc returns the first procedure that matched the given name.
+func (s *Schema) Proc(name string) (*Proc, bool) {
+	for _, p := range s.Procs {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
 // Object returns the first object that matched the given predicate.
 func (s *Schema) Object(f func(Object) bool) (Object, bool) {
 	for _, o := range s.Objects {
@@ -227,6 +328,35 @@ func (t *Table) ForeignKey(symbol string) (*ForeignKey, bool) {
 	return nil, false
 }
 
+// Trigger returns the first trigger that matched the given name.
+func (t *Table) Trigger(name string) (*Trigger, bool) {
+	return triggerByName(t.Triggers, name)
+}
+
+// Column returns the first column that matched the given name.
+func (v *View) Column(name string) (*Column, bool) {
+	for _, c := range v.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Trigger returns the first trigger that matched the given name.
+func (v *View) Trigger(name string) (*Trigger, bool) {
+	return triggerByName(v.Triggers, name)
+}
+
+func triggerByName(triggers []*Trigger, name string) (*Trigger, bool) {
+	for _, t := range triggers {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
 // Checks of the table.
 func (t *Table) Checks() (ck []*Check) {
 	for _, a := range t.Attrs {
@@ -237,6 +367,16 @@ func (t *Table) Checks() (ck []*Check) {
 	return ck
 }
 
+// Check returns the first check constraint that matched the given name.
+func (t *Table) Check(name string) (*Check, bool) {
+	for _, c := range t.Checks() {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
 // SetPos sets the position of the schema.
 func (s *Schema) SetPos(p *Pos) {
 	ReplaceOrAppend(&s.Attrs, p)
@@ -347,6 +487,40 @@ func (f *ForeignKey) RefColumn(name string) (*Column, bool) {
 	return nil, false
 }
 
+// List of function argument modes.
+const (
+	FuncArgModeIn       FuncArgMode = "IN"
+	FuncArgModeOut      FuncArgMode = "OUT"
+	FuncArgModeInOut    FuncArgMode = "INOUT"
+	FuncArgModeVariadic FuncArgMode = "VARIADIC"
+)
+
+// List of trigger action times.
+const (
+	TriggerTimeBefore  TriggerTime = "BEFORE"
+	TriggerTimeAfter   TriggerTime = "AFTER"
+	TriggerTimeInstead TriggerTime = "INSTEAD OF"
+)
+
+// List of trigger FOR EACH specs.
+const (
+	TriggerForRow  TriggerFor = "ROW"
+	TriggerForStmt TriggerFor = "STATEMENT"
+)
+
+// List of the common trigger events.
+var (
+	TriggerEventInsert   = TriggerEvent{Name: "INSERT"}
+	TriggerEventUpdate   = TriggerEvent{Name: "UPDATE"}
+	TriggerEventDelete   = TriggerEvent{Name: "DELETE"}
+	TriggerEventTruncate = TriggerEvent{Name: "TRUNCATE"}
+)
+
+// TriggerEventUpdateOf returns an UPDATE OF trigger event.
+func TriggerEventUpdateOf(columns ...*Column) TriggerEvent {
+	return TriggerEvent{Name: "UPDATE", Columns: columns}
+}
+
 // ReferenceOption for constraint actions.
 type ReferenceOption string
 
@@ -541,6 +715,14 @@ type (
 		Type string // Optional type. e.g. STORED or VIRTUAL.
 	}
 
+	// LogicalDefault describes the default value of a column that is set by the
+	// application (e.g., an ORM) when rows are created, and not by the database.
+	// Unlike Column.Default, which describes the DEFAULT clause of the column, it
+	// is not part of the database schema, and therefore it is never planned.
+	LogicalDefault struct {
+		X Expr
+	}
+
 	// Pos is an attribute that holds the position of a schema element.
 	Pos struct {
 		// Filename is the name (or full path) of the file which loaded the schema element.
@@ -576,9 +758,47 @@ func (p *Pos) String() string {
 	return b.String()
 }
 
+// PosError is an error that is attached to the position of the
+// schema element that caused it (e.g., a column in an HCL file).
+type PosError struct {
+	Pos *Pos
+	Err error
+}
+
+// Error implements the error interface.
+func (e *PosError) Error() string {
+	return e.Pos.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *PosError) Unwrap() error {
+	return e.Err
+}
+
+// WrapPos wraps the error with the given message and position, if it is not nil. If the
+// error already holds a position, it is only wrapped with the message, as the innermost
+// position is the most precise one. e.g., "cannot convert table: schema.hcl:4:3: ...".
+func WrapPos(p *Pos, err error, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	var pe *PosError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &pe):
+		return fmt.Errorf("%s: %w", msg, err)
+	case p != nil:
+		return &PosError{Pos: p, Err: fmt.Errorf("%s: %w", msg, err)}
+	default:
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+}
+
 // objects.
 func (*Table) obj()    {}
 func (*View) obj()     {}
+func (*Func) obj()     {}
+func (*Proc) obj()     {}
+func (*Trigger) obj()  {}
 func (*EnumType) obj() {}
 
 // constraints are objects.
@@ -612,6 +832,8 @@ func (*Comment) attr()         {}
 func (*Charset) attr()         {}
 func (*Collation) attr()       {}
 func (*GeneratedExpr) attr()   {}
+func (*LogicalDefault) attr()  {}
+func (*ExtAttr) attr()         {}
 
 // SpecType returns the type of the spec.
 func (e *EnumType) SpecType() string { return "enum" }
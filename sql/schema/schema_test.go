@@ -0,0 +1,35 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapPos(t *testing.T) {
+	p1, p2 := &schema.Pos{Filename: "schema.hcl"}, &schema.Pos{Filename: "schema.hcl"}
+	p1.Start.Line, p2.Start.Line = 2, 4
+	require.NoError(t, schema.WrapPos(p1, nil, "cannot convert table"))
+	require.EqualError(t, schema.WrapPos(nil, errors.New("error"), "cannot convert table"), "cannot convert table: error")
+
+	err := schema.WrapPos(p1, errors.New("error"), "cannot convert table %q", "t")
+	require.EqualError(t, err, `schema.hcl:2: cannot convert table "t": error`)
+	var pe *schema.PosError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, p1, pe.Pos)
+
+	// The innermost position is kept, along with all messages wrapping it.
+	inner := &schema.PosError{Pos: p2, Err: errors.New("missing column")}
+	err = schema.WrapPos(p1, fmt.Errorf("index %q: %w", "idx", inner), "cannot convert table %q", "t")
+	require.EqualError(t, err, `cannot convert table "t": index "idx": schema.hcl:4: missing column`)
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, p2, pe.Pos)
+}
@@ -0,0 +1,52 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DiffSnapshots returns the changes for migrating a realm from the "from" snapshot to
+// the "to" snapshot, where both snapshots are realms encoded by MarshalJSON (e.g., the
+// stored states of two releases). The snapshots are decoded and diffed by the given
+// differ, which is expected to be the differ of the driver that inspected them (e.g.,
+// postgres.DefaultDiff). Therefore, the changes are the same as the changes returned
+// by diffing the realms when they were inspected, and no database access is required.
+func DiffSnapshots(d Differ, from, to []byte, opts ...DiffOption) ([]Change, error) {
+	r1, err := unmarshalSnapshot("from", from)
+	if err != nil {
+		return nil, err
+	}
+	r2, err := unmarshalSnapshot("to", to)
+	if err != nil {
+		return nil, err
+	}
+	return d.RealmDiff(r1, r2, opts...)
+}
+
+// unmarshalSnapshot decodes a realm snapshot.
+func unmarshalSnapshot(name string, data []byte) (*Realm, error) {
+	// Snapshots of other elements (e.g., a schema) would be
+	// decoded to an empty realm, and therefore are rejected.
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("sql/schema: decoding %s snapshot: %w", name, err)
+	}
+	for _, k := range slices.Sorted(maps.Keys(keys)) {
+		switch k {
+		case "$id", "Schemas", "Attrs", "Objects":
+		default:
+			return nil, fmt.Errorf("sql/schema: %s snapshot is not a realm: unexpected key %q", name, k)
+		}
+	}
+	var r Realm
+	if err := UnmarshalJSON(data, &r); err != nil {
+		return nil, fmt.Errorf("sql/schema: decoding %s snapshot: %w", name, err)
+	}
+	return &r, nil
+}
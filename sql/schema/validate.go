@@ -0,0 +1,223 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// A Dialect describes the rules of a database dialect that are checked by Validate.
+	// Drivers expose the dialect they implement. e.g., mysql.DefaultDialect.
+	Dialect struct {
+		// Name of the dialect. e.g., "mysql" or "postgres".
+		Name string
+
+		// MaxIdentLen is the maximum length of identifiers
+		// in bytes, or 0 if the length is not limited.
+		MaxIdentLen int
+
+		// Reserved reports if the given word is reserved by the dialect. Reserved words can
+		// be used as identifiers only when quoted, which makes them error-prone to use in raw
+		// statements, like view definitions or data migrations.
+		Reserved func(string) bool
+
+		// SchemaScoped reports if the names of the given constraint kind must be unique in
+		// their schema, and not only in their table. The kinds are "index", "foreign_key"
+		// and "check". For example, index names are schema-scoped in PostgreSQL.
+		SchemaScoped func(kind string) bool
+
+		// FKTypes reports if a foreign-key column of type "from" can reference a
+		// column of type "to". If nil, the types must be the same, except for the
+		// size of string types.
+		FKTypes func(from, to Type) bool
+	}
+
+	// A Diagnostic describes a problem found by Validate.
+	Diagnostic struct {
+		// Code identifies the check that reported the diagnostic.
+		Code DiagnosticCode
+		// Path is the path of the object, in the form of its HCL
+		// reference. e.g., "schema.public.table.users.column.id".
+		Path string
+		// Text describes the problem.
+		Text string
+	}
+
+	// DiagnosticCode identifies the check that reported a diagnostic.
+	DiagnosticCode string
+)
+
+// List of diagnostic codes reported by Validate.
+const (
+	DiagIdentLength        DiagnosticCode = "ident_length"
+	DiagReservedWord       DiagnosticCode = "reserved_word"
+	DiagDuplicateName      DiagnosticCode = "duplicate_name"
+	DiagForeignKeyMismatch DiagnosticCode = "fk_type_mismatch"
+)
+
+// String implements the fmt.Stringer interface.
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Path, d.Text, d.Code)
+}
+
+// Validate checks the realm against the rules of the given dialect, and returns the
+// diagnostics it found, ordered by the realm elements. It checks that identifiers do
+// not exceed the maximum length of the dialect and are not reserved words, that names
+// of constraints are unique in their scope, and that the columns of foreign keys match
+// the types of their referenced columns. Validate is expected to be called before
+// planning, to report problems in the desired state early, with their object paths.
+func Validate(r *Realm, d *Dialect) []*Diagnostic {
+	v := &validator{d: d}
+	for _, s := range r.Schemas {
+		v.schema(s)
+	}
+	return v.diags
+}
+
+type validator struct {
+	d     *Dialect
+	diags []*Diagnostic
+}
+
+func (v *validator) schema(s *Schema) {
+	sp := "schema." + s.Name
+	v.ident(sp, "schema", s.Name)
+	// Constraint names by kind, for schema-scoped kinds.
+	scoped := make(map[string]map[string]string)
+	for _, t := range s.Tables {
+		tp := sp + ".table." + t.Name
+		v.ident(tp, "table", t.Name)
+		for _, c := range t.Columns {
+			v.ident(tp+".column."+c.Name, "column", c.Name)
+		}
+		named := make(map[string]map[string]bool)
+		constraint := func(kind, path, name string) {
+			if name == "" {
+				return
+			}
+			v.ident(path, strings.ReplaceAll(kind, "_", " "), name)
+			if named[kind] == nil {
+				named[kind] = make(map[string]bool)
+			}
+			if named[kind][name] {
+				v.report(DiagDuplicateName, path, "%s name %q is used more than once in table %q", strings.ReplaceAll(kind, "_", " "), name, t.Name)
+				return
+			}
+			named[kind][name] = true
+			if v.d.SchemaScoped == nil || !v.d.SchemaScoped(kind) {
+				return
+			}
+			if scoped[kind] == nil {
+				scoped[kind] = make(map[string]string)
+			}
+			if other, ok := scoped[kind][name]; ok {
+				v.report(DiagDuplicateName, path, "%s name %q is already used by table %q in schema %q", strings.ReplaceAll(kind, "_", " "), name, other, s.Name)
+				return
+			}
+			scoped[kind][name] = t.Name
+		}
+		if pk := t.PrimaryKey; pk != nil {
+			constraint("index", tp+".primary_key", pk.Name)
+		}
+		for _, idx := range t.Indexes {
+			constraint("index", tp+".index."+idx.Name, idx.Name)
+		}
+		for _, fk := range t.ForeignKeys {
+			fp := tp + ".foreign_key." + fk.Symbol
+			constraint("foreign_key", fp, fk.Symbol)
+			v.fkTypes(fp, fk)
+		}
+		for _, c := range t.Checks() {
+			constraint("check", tp+".check."+c.Name, c.Name)
+		}
+		for _, tr := range t.Triggers {
+			v.ident(tp+".trigger."+tr.Name, "trigger", tr.Name)
+		}
+	}
+	for _, vw := range s.Views {
+		p := sp + ".view." + vw.Name
+		v.ident(p, "view", vw.Name)
+		for _, tr := range vw.Triggers {
+			v.ident(p+".trigger."+tr.Name, "trigger", tr.Name)
+		}
+	}
+	for _, f := range s.Funcs {
+		v.ident(sp+".function."+f.Name, "function", f.Name)
+	}
+	for _, p := range s.Procs {
+		v.ident(sp+".procedure."+p.Name, "procedure", p.Name)
+	}
+}
+
+// ident checks the length of the identifier, and that it is not a reserved word.
+func (v *validator) ident(path, kind, name string) {
+	if v.d.MaxIdentLen > 0 && len(name) > v.d.MaxIdentLen {
+		v.report(DiagIdentLength, path, "%s name %q exceeds the maximum identifier length of %s (%d > %d bytes)", kind, name, v.d.Name, len(name), v.d.MaxIdentLen)
+	}
+	if v.d.Reserved != nil && v.d.Reserved(name) {
+		v.report(DiagReservedWord, path, "%s name %q is a reserved word in %s", kind, name, v.d.Name)
+	}
+}
+
+// fkTypes checks that the columns of the foreign key match their referenced columns.
+func (v *validator) fkTypes(path string, fk *ForeignKey) {
+	match := v.d.FKTypes
+	if match == nil {
+		match = SameFKTypes
+	}
+	for i, c := range fk.Columns {
+		if i >= len(fk.RefColumns) {
+			break
+		}
+		rc := fk.RefColumns[i]
+		if c.Type == nil || rc.Type == nil || c.Type.Type == nil || rc.Type.Type == nil {
+			continue
+		}
+		if !match(c.Type.Type, rc.Type.Type) {
+			v.report(DiagForeignKeyMismatch, path, "column %q of type %s does not match the type %s of the referenced column %q", c.Name, typeName(c.Type), typeName(rc.Type), rc.Name)
+		}
+	}
+}
+
+func (v *validator) report(code DiagnosticCode, path, format string, args ...any) {
+	v.diags = append(v.diags, &Diagnostic{Code: code, Path: path, Text: fmt.Sprintf(format, args...)})
+}
+
+// SameFKTypes is the default rule of foreign-key column types. It reports if the
+// two types are the same, ignoring the size of string types, as most databases
+// allow string columns of different sizes to be referenced.
+func SameFKTypes(from, to Type) bool {
+	from, to = UnderlyingType(from), UnderlyingType(to)
+	if reflect.TypeOf(from) != reflect.TypeOf(to) {
+		return false
+	}
+	switch f := from.(type) {
+	case *IntegerType:
+		t := to.(*IntegerType)
+		return strings.EqualFold(f.T, t.T) && f.Unsigned == t.Unsigned
+	case *StringType:
+		return strings.EqualFold(f.T, to.(*StringType).T)
+	case *BinaryType:
+		return strings.EqualFold(f.T, to.(*BinaryType).T)
+	default:
+		return Equal(from, to)
+	}
+}
+
+// typeName returns a printable name of the column type.
+func typeName(ct *ColumnType) string {
+	if ct.Raw != "" {
+		return ct.Raw
+	}
+	if t := reflect.Indirect(reflect.ValueOf(ct.Type)); t.Kind() == reflect.Struct {
+		if f := t.FieldByName("T"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			return f.String()
+		}
+	}
+	return fmt.Sprintf("%T", ct.Type)
+}
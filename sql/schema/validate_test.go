@@ -0,0 +1,79 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	d := &schema.Dialect{
+		Name:        "test",
+		MaxIdentLen: 10,
+		Reserved: func(s string) bool {
+			return strings.EqualFold(s, "order")
+		},
+		SchemaScoped: func(kind string) bool {
+			return kind == "index"
+		},
+	}
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewStringColumn("name", "varchar"),
+		)
+	users.AddIndexes(schema.NewIndex("name").AddColumns(users.Columns[1]))
+	orders := schema.NewTable("order").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewIntColumn("user_id", "bigint"),
+			schema.NewStringColumn("user_name", "varchar", schema.StringSize(100)),
+			schema.NewStringColumn("description_text", "text"),
+		)
+	orders.AddIndexes(schema.NewIndex("name").AddColumns(orders.Columns[2]))
+	orders.AddForeignKeys(
+		schema.NewForeignKey("fk_id").AddColumns(orders.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]),
+		schema.NewForeignKey("fk_name").AddColumns(orders.Columns[2]).SetRefTable(users).AddRefColumns(users.Columns[1]),
+		schema.NewForeignKey("fk_name").AddColumns(orders.Columns[2]).SetRefTable(users).AddRefColumns(users.Columns[1]),
+	)
+	r := schema.NewRealm(schema.New("public").AddTables(users, orders))
+
+	diags := schema.Validate(r, d)
+	lines := make([]string, len(diags))
+	for i, d := range diags {
+		lines[i] = d.String()
+	}
+	require.Equal(t, []string{
+		`schema.public.table.order: table name "order" is a reserved word in test (reserved_word)`,
+		`schema.public.table.order.column.description_text: column name "description_text" exceeds the maximum identifier length of test (16 > 10 bytes) (ident_length)`,
+		`schema.public.table.order.index.name: index name "name" is already used by table "users" in schema "public" (duplicate_name)`,
+		`schema.public.table.order.foreign_key.fk_id: column "user_id" of type bigint does not match the type int of the referenced column "id" (fk_type_mismatch)`,
+		`schema.public.table.order.foreign_key.fk_name: foreign key name "fk_name" is used more than once in table "order" (duplicate_name)`,
+	}, lines)
+	require.Equal(t, schema.DiagReservedWord, diags[0].Code)
+	require.Equal(t, "schema.public.table.order.column.description_text", diags[1].Path)
+
+	// Custom foreign-key type rules.
+	d.FKTypes = func(from, to schema.Type) bool {
+		_, ok1 := from.(*schema.IntegerType)
+		_, ok2 := to.(*schema.IntegerType)
+		return ok1 == ok2
+	}
+	diags = schema.Validate(r, d)
+	require.Len(t, diags, 4)
+	for _, d := range diags {
+		require.NotEqual(t, schema.DiagForeignKeyMismatch, d.Code)
+	}
+
+	// String sizes and integer aliases.
+	require.True(t, schema.SameFKTypes(&schema.StringType{T: "varchar", Size: 10}, &schema.StringType{T: "VARCHAR", Size: 255}))
+	require.False(t, schema.SameFKTypes(&schema.IntegerType{T: "int"}, &schema.IntegerType{T: "int", Unsigned: true}))
+	require.False(t, schema.SameFKTypes(&schema.IntegerType{T: "int"}, &schema.StringType{T: "varchar"}))
+}
@@ -0,0 +1,145 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package classified implements an analyzer that checks for changes that remove
+// classified data, like dropping columns that hold personally identifiable information.
+// Columns are classified using their comments. See schema.Classification for more info.
+package classified
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// Analyzer checks for changes that drop classified columns.
+type Analyzer struct {
+	sqlcheck.Options
+	// Classes that require a retention review before being
+	// dropped. Defaults to DefaultClasses. Configured as:
+	//
+	//	classified {
+	//	  classes = ["pii", "secret", "financial"]
+	//	}
+	Classes []schema.Classification
+}
+
+// DefaultClasses are the classes that are checked by default.
+var DefaultClasses = []schema.Classification{schema.ClassPII, schema.ClassSecret}
+
+// New creates a new classified data Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{Classes: DefaultClasses}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing classified check options: %w", err)
+		}
+		if a, ok := az.Extra.Attr("classes"); ok {
+			vs, err := a.Strings()
+			if err != nil {
+				return nil, fmt.Errorf("sql/sqlcheck: parsing classified check classes: %w", err)
+			}
+			az.Classes = make([]schema.Classification, len(vs))
+			for i, v := range vs {
+				if az.Classes[i] = schema.Classification(v); !az.Classes[i].Valid() {
+					return nil, fmt.Errorf("sql/sqlcheck: invalid classification %q", v)
+				}
+			}
+		}
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeDropC = sqlcheck.Code("CL101")
+	codeDropT = sqlcheck.Code("CL102")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "classified"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.DropTable:
+				if p.File.TableSpan(c.T) == sqlcheck.SpanTemporary {
+					continue
+				}
+				if cs := schema.ClassifiedColumns(c.T, a.Classes...); len(cs) > 0 {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Code: codeDropT,
+						Pos:  sc.Stmt.Pos,
+						Text: fmt.Sprintf("Dropping table %q with %s requires a retention review", c.T.Name, describe(cs)),
+					})
+				}
+			case *schema.ModifyTable:
+				var cs []*schema.Column
+				for i := range c.Changes {
+					d, ok := c.Changes[i].(*schema.DropColumn)
+					if !ok || p.File.ColumnSpan(c.T, d.C) == sqlcheck.SpanTemporary {
+						continue
+					}
+					if class, ok := schema.ColumnClassification(d.C); ok && slices.Contains(a.Classes, class) {
+						cs = append(cs, d.C)
+					}
+				}
+				if len(cs) > 0 {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Code: codeDropC,
+						Pos:  sc.Stmt.Pos,
+						Text: fmt.Sprintf("Dropping %s requires a retention review", describe(cs)),
+					})
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "classified data deletion detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// describe returns the description of the classified columns. For example:
+// `pii column "email"` or `pii columns "email" and "phone", and secret column "token"`.
+func describe(cs []*schema.Column) string {
+	var (
+		order  []schema.Classification
+		byName = make(map[schema.Classification][]string)
+	)
+	for _, c := range cs {
+		class, _ := schema.ColumnClassification(c)
+		if _, ok := byName[class]; !ok {
+			order = append(order, class)
+		}
+		byName[class] = append(byName[class], strconv.Quote(c.Name))
+	}
+	parts := make([]string, len(order))
+	for i, class := range order {
+		switch names := byName[class]; len(names) {
+		case 1:
+			parts[i] = fmt.Sprintf("%s column %s", class, names[0])
+		default:
+			parts[i] = fmt.Sprintf("%s columns %s and %s", class, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+		}
+	}
+	return strings.Join(parts, ", and ")
+}
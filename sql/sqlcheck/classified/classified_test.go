@@ -0,0 +1,102 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package classified_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/classified"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_DropClassified(t *testing.T) {
+	var (
+		report sqlcheck.Report
+		users  = schema.NewTable("users").
+			SetSchema(schema.New("test")).
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewStringColumn("email", "text").SetClassification(schema.ClassPII),
+				schema.NewStringColumn("phone", "text").SetComment("mobile").SetClassification(schema.ClassPII),
+				schema.NewStringColumn("token", "text").SetClassification(schema.ClassSecret),
+				schema.NewStringColumn("bio", "text").SetClassification(schema.ClassPublic),
+			)
+		pass = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{Name: "mysql"},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{Text: "ALTER TABLE `users`"},
+						Changes: []schema.Change{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.DropColumn{C: users.Columns[1]},
+									&schema.DropColumn{C: users.Columns[2]},
+									&schema.DropColumn{C: users.Columns[3]},
+									&schema.DropColumn{C: users.Columns[4]},
+								},
+							},
+						},
+					},
+					{
+						Stmt:    &migrate.Stmt{Text: "DROP TABLE `users`", Pos: 1},
+						Changes: []schema.Change{&schema.DropTable{T: users}},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = r
+			}),
+		}
+	)
+	az, err := classified.New(&schemahcl.Resource{})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, "classified data deletion detected", report.Text)
+	require.Len(t, report.Diagnostics, 2)
+	require.Equal(t, `Dropping pii columns "email" and "phone", and secret column "token" requires a retention review`, report.Diagnostics[0].Text)
+	require.Equal(t, `Dropping table "users" with pii columns "email" and "phone", and secret column "token" requires a retention review`, report.Diagnostics[1].Text)
+
+	// Configured classes.
+	az, err = classified.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "classified",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+					schemahcl.StringsAttr("classes", "public"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.EqualError(t, az.Analyze(context.Background(), pass), "classified data deletion detected")
+	require.Equal(t, `Dropping public column "bio" requires a retention review`, report.Diagnostics[0].Text)
+
+	_, err = classified.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "classified", Attrs: []*schemahcl.Attr{schemahcl.StringsAttr("classes", "PII")}},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: invalid classification "PII"`)
+}
+
+type testFile struct {
+	name string
+	migrate.File
+}
+
+func (t testFile) Name() string {
+	return t.name
+}
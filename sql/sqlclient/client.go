@@ -251,9 +251,10 @@ type (
 
 	driver struct {
 		Opener
-		name     string
-		parser   URLParser
-		txOpener TxOpener
+		name      string
+		parser    URLParser
+		txOpener  TxOpener
+		evaluator schemahcl.Evaluator
 	}
 )
 
@@ -310,6 +311,17 @@ func HasDriver(scheme string) bool {
 	return ok
 }
 
+// LookupEvaluator returns the static HCL evaluator (codec) that was registered
+// for the given driver name or flavour, if there is any. e.g., "mysql".
+func LookupEvaluator(scheme string) (schemahcl.Evaluator, bool) {
+	v, ok := drivers.Load(scheme)
+	if !ok {
+		return nil, false
+	}
+	e := v.(*driver).evaluator
+	return e, e != nil
+}
+
 // OpenURL opens an Atlas client by its provided url.URL.
 func OpenURL(ctx context.Context, u *url.URL, opts ...OpenOption) (*Client, error) {
 	cfg := &openOptions{}
@@ -520,7 +532,7 @@ func Register(name string, opener Opener, opts ...RegisterOption) {
 			return c, err
 		})
 	}
-	drv := &driver{Opener: opener, name: name, parser: opt.parser, txOpener: opt.txOpener}
+	drv := &driver{Opener: opener, name: name, parser: opt.parser, txOpener: opt.txOpener, evaluator: opt.Evaluator}
 	for _, f := range append(opt.flavours, name) {
 		if _, ok := drivers.Load(f); ok {
 			panic("sql/sqlclient: Register called twice for " + f)
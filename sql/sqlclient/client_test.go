@@ -11,10 +11,13 @@ import (
 	"net/url"
 	"testing"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 
 	"ariga.io/atlas/sql/sqlclient"
 )
@@ -59,6 +62,25 @@ func TestRegisterOpen(t *testing.T) {
 
 	c1, err = sqlclient.Open(context.Background(), "postgres://:3306")
 	require.EqualError(t, err, `sql/sqlclient: unknown driver "postgres". See: https://atlasgo.io/url`)
+
+	_, ok := sqlclient.LookupEvaluator("mysql")
+	require.False(t, ok)
+	_, ok = sqlclient.LookupEvaluator("postgres")
+	require.False(t, ok)
+	ev := schemahcl.EvalFunc(func(*hclparse.Parser, any, map[string]cty.Value) error { return nil })
+	sqlclient.Register(
+		"hcl",
+		sqlclient.OpenerFunc(func(context.Context, *url.URL) (*sqlclient.Client, error) {
+			return c, nil
+		}),
+		sqlclient.RegisterFlavours("hcl+flavour"),
+		sqlclient.RegisterCodec(schemahcl.MarshalerFunc(func(any) ([]byte, error) { return nil, nil }), ev),
+	)
+	for _, name := range []string{"hcl", "hcl+flavour"} {
+		e, ok := sqlclient.LookupEvaluator(name)
+		require.True(t, ok)
+		require.NotNil(t, e)
+	}
 }
 
 func TestOpen_Errors(t *testing.T) {
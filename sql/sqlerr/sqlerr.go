@@ -0,0 +1,123 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package sqlerr provides a shared classification of the errors returned by the
+// database drivers. Drivers register a Classifier that maps their error codes to
+// a Category, and callers use Classify or Is to handle errors uniformly, without
+// depending on the driver-specific error types.
+package sqlerr
+
+import (
+	"errors"
+	"sync"
+)
+
+// A Category describes the class of a database error.
+type Category uint8
+
+// List of error categories.
+const (
+	Unknown          Category = iota // Error is not classified.
+	NotExist                         // Object (e.g., schema, table or column) does not exist.
+	Duplicate                        // Object or value already exists.
+	Locked                           // Object is locked, or a lock could not be acquired.
+	PermissionDenied                 // Insufficient privileges or failed authentication.
+	SyntaxError                      // Statement is malformed.
+	Retryable                        // Transient failure, and the operation can be retried.
+)
+
+// String implements fmt.Stringer.
+func (c Category) String() string {
+	switch c {
+	case NotExist:
+		return "not exist"
+	case Duplicate:
+		return "duplicate"
+	case Locked:
+		return "locked"
+	case PermissionDenied:
+		return "permission denied"
+	case SyntaxError:
+		return "syntax error"
+	case Retryable:
+		return "retryable"
+	default:
+		return "unknown"
+	}
+}
+
+// Error wraps a driver error with its classification.
+type Error struct {
+	Category Category // Category of the error.
+	Driver   string   // Name of the driver that classified the error.
+	Code     string   // Driver-specific code. e.g., "1146" in MySQL or "42P01" in PostgreSQL.
+	Err      error    // Original error returned by the driver.
+}
+
+// Error implements the error interface. The message of the original error is kept as-is.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// A Classifier reports the category and the code of a driver error. The
+// last return value is false if the error was not returned by the driver.
+type Classifier func(error) (c Category, code string, ok bool)
+
+var classifiers struct {
+	sync.RWMutex
+	names []string
+	funcs []Classifier
+}
+
+// Register registers the error classifier of a driver. Classifiers are called
+// in their registration order, and the first one that recognizes an error wins.
+func Register(name string, c Classifier) {
+	if c == nil {
+		panic("sql/sqlerr: Register classifier is nil")
+	}
+	classifiers.Lock()
+	defer classifiers.Unlock()
+	for _, n := range classifiers.names {
+		if n == name {
+			panic("sql/sqlerr: Register called twice for " + name)
+		}
+	}
+	classifiers.names = append(classifiers.names, name)
+	classifiers.funcs = append(classifiers.funcs, c)
+}
+
+// Classify returns the given error wrapped with its classification, or the error as-is
+// if it is nil, already classified, or was not recognized by any of the registered drivers.
+func Classify(err error) error {
+	if err == nil || errors.As(err, new(*Error)) {
+		return err
+	}
+	classifiers.RLock()
+	defer classifiers.RUnlock()
+	for i, f := range classifiers.funcs {
+		if c, code, ok := f(err); ok {
+			return &Error{Category: c, Driver: classifiers.names[i], Code: code, Err: err}
+		}
+	}
+	return err
+}
+
+// CategoryOf returns the category of the given error, or Unknown if it cannot be classified.
+func CategoryOf(err error) Category {
+	var e *Error
+	if errors.As(Classify(err), &e) {
+		return e.Category
+	}
+	return Unknown
+}
+
+// Is reports if the given error belongs to the category.
+func Is(err error, c Category) bool {
+	return err != nil && CategoryOf(err) == c
+}
@@ -0,0 +1,68 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/sqlerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testError struct{ code int }
+
+func (e *testError) Error() string { return fmt.Sprintf("test error %d", e.code) }
+
+func init() {
+	sqlerr.Register("test", func(err error) (sqlerr.Category, string, bool) {
+		var e *testError
+		if !errors.As(err, &e) {
+			return sqlerr.Unknown, "", false
+		}
+		c := sqlerr.Unknown
+		if e.code == 1 {
+			c = sqlerr.Retryable
+		}
+		return c, fmt.Sprint(e.code), true
+	})
+}
+
+func TestClassify(t *testing.T) {
+	require.NoError(t, sqlerr.Classify(nil))
+
+	err := errors.New("unknown")
+	require.Equal(t, err, sqlerr.Classify(err))
+	require.Equal(t, sqlerr.Unknown, sqlerr.CategoryOf(err))
+
+	orig := fmt.Errorf("exec: %w", &testError{code: 1})
+	err = sqlerr.Classify(orig)
+	var e *sqlerr.Error
+	require.ErrorAs(t, err, &e)
+	require.Equal(t, &sqlerr.Error{Category: sqlerr.Retryable, Driver: "test", Code: "1", Err: orig}, e)
+	require.Equal(t, orig.Error(), err.Error())
+	require.ErrorIs(t, err, orig)
+	// Classified errors are not wrapped twice.
+	require.Equal(t, err, sqlerr.Classify(err))
+
+	require.True(t, sqlerr.Is(orig, sqlerr.Retryable))
+	require.True(t, sqlerr.Is(fmt.Errorf("wrap: %w", err), sqlerr.Retryable))
+	require.False(t, sqlerr.Is(&testError{code: 2}, sqlerr.Retryable))
+	require.Equal(t, sqlerr.Unknown, sqlerr.CategoryOf(&testError{code: 2}))
+	require.False(t, sqlerr.Is(nil, sqlerr.Unknown))
+}
+
+func TestRegister(t *testing.T) {
+	require.Panics(t, func() { sqlerr.Register("test", func(error) (sqlerr.Category, string, bool) { return 0, "", false }) })
+	require.Panics(t, func() { sqlerr.Register("nil", nil) })
+}
+
+func TestCategory_String(t *testing.T) {
+	require.Equal(t, "not exist", sqlerr.NotExist.String())
+	require.Equal(t, "permission denied", sqlerr.PermissionDenied.String())
+	require.Equal(t, "unknown", sqlerr.Category(100).String())
+}
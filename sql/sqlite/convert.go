@@ -103,6 +103,8 @@ func ParseType(c string) (schema.Type, error) {
 		return &schema.TimeType{T: t}, nil
 	case "uuid":
 		return &schema.UUIDType{T: t}, nil
+	case TypeAny:
+		return &UserDefinedType{T: t}, nil
 	default:
 		return &UserDefinedType{T: c}, nil
 	}
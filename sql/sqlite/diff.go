@@ -7,6 +7,7 @@ package sqlite
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -23,9 +24,31 @@ var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{}}
 type diff struct{}
 
 // SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
-func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
-	// No special schema attribute diffing for SQLite.
-	return nil
+// Database-level pragmas are managed only if they are set on the desired state.
+func (*diff) SchemaAttrDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	if t := (JournalMode{}); sqlx.Has(to.Attrs, &t) {
+		f := JournalMode{V: "delete"}
+		sqlx.Has(from.Attrs, &f)
+		if !strings.EqualFold(f.V, t.V) {
+			changes = append(changes, &schema.ModifyAttr{From: &f, To: &t})
+		}
+	}
+	if t := (UserVersion{}); sqlx.Has(to.Attrs, &t) {
+		f := UserVersion{}
+		sqlx.Has(from.Attrs, &f)
+		if f.V != t.V {
+			changes = append(changes, &schema.ModifyAttr{From: &f, To: &t})
+		}
+	}
+	if t := (ApplicationID{}); sqlx.Has(to.Attrs, &t) {
+		f := ApplicationID{}
+		sqlx.Has(from.Attrs, &f)
+		if f.V != t.V {
+			changes = append(changes, &schema.ModifyAttr{From: &f, To: &t})
+		}
+	}
+	return changes
 }
 
 // RealmObjectDiff returns a changeset for migrating realm (database) objects
@@ -55,6 +78,18 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 			})
 		}
 	}
+	var (
+		fromV, toV     VirtualTable
+		fromHas, toHas = sqlx.Has(from.Attrs, &fromV), sqlx.Has(to.Attrs, &toV)
+	)
+	switch {
+	case fromHas && !toHas:
+		changes = append(changes, &schema.DropAttr{A: &fromV})
+	case !fromHas && toHas:
+		changes = append(changes, &schema.AddAttr{A: &toV})
+	case fromHas && toHas && (fromV.M != toV.M || !slices.Equal(fromV.A, toV.A)):
+		changes = append(changes, &schema.ModifyAttr{From: &fromV, To: &toV})
+	}
 	return append(changes, sqlx.CheckDiffMode(from, to, opts.Mode)...), nil
 }
 
@@ -88,6 +123,28 @@ func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.
 	}, nil
 }
 
+// TypeConversion implements the sqlx.ConversionClassifier interface.
+func (*diff) TypeConversion(from, to schema.Type) schema.TypeConversion {
+	return TypeConversion(from, to)
+}
+
+// TypeConversion classifies the conversion of column values from one SQLite type to
+// another. Since SQLite does not enforce type lengths, and all values of the same
+// type affinity share the same storage class, changing VARCHAR(255) to VARCHAR(50)
+// is safe, whereas changing REAL to INTEGER is lossy.
+func TypeConversion(from, to schema.Type) schema.TypeConversion {
+	return sqlx.ClassifyConversion(from, to, func(t schema.Type) int64 {
+		switch t.(type) {
+		case *schema.IntegerType, *schema.FloatType:
+			return 8
+		case *schema.StringType, *schema.BinaryType, *schema.DecimalType:
+			return -1
+		default:
+			return 0
+		}
+	})
+}
+
 // typeChanged reports if the column type was changed.
 func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	fromT, toT := from.Type.Type, to.Type.Type
@@ -152,7 +209,45 @@ func (d *diff) FindGeneratedIndex(t *schema.Table, idx *schema.Index) (*schema.I
 // IndexAttrChanged reports if the index attributes were changed.
 func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
 	var p1, p2 IndexPredicate
-	return sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || (p1.P != p2.P && p1.P != sqlx.MayWrap(p2.P))
+	sqlx.Has(from, &p1)
+	sqlx.Has(to, &p2)
+	return normalizePredicate(p1.P) != normalizePredicate(p2.P)
+}
+
+// normalizePredicate normalizes the index predicate for comparison by trimming
+// its wrapping parentheses and collapsing whitespace outside of quoted strings.
+func normalizePredicate(p string) string {
+	var (
+		b     strings.Builder
+		space bool
+	)
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case ' ', '\t', '\n', '\r':
+			space = b.Len() > 0
+		default:
+			if space {
+				b.WriteByte(' ')
+				space = false
+			}
+			j := -1
+			if c == '\'' || c == '"' || c == '`' {
+				j = strings.IndexByte(p[i+1:], c)
+			}
+			if j == -1 {
+				b.WriteByte(c)
+				continue
+			}
+			// Quoted strings and identifiers are kept as-is.
+			b.WriteString(p[i : i+j+2])
+			i += j + 1
+		}
+	}
+	p = strings.TrimSpace(strings.TrimSuffix(b.String(), ";"))
+	for len(p) > 1 && p[0] == '(' && scanExpr(p) == p {
+		p = strings.TrimSpace(p[1 : len(p)-1])
+	}
+	return p
 }
 
 // IndexPartAttrChanged reports if the index-part attributes were changed.
@@ -180,6 +275,13 @@ func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
 
 // Normalize implements the sqlx.Normalizer interface.
 func (d *diff) Normalize(from, to *schema.Table, _ *schema.DiffOptions) error {
+	// Columns of virtual tables are derived from the module arguments,
+	// and therefore, can be omitted from the desired state.
+	if sqlx.Has(from.Attrs, &VirtualTable{}) && sqlx.Has(to.Attrs, &VirtualTable{}) && len(to.Columns) == 0 {
+		for _, c := range from.Columns {
+			to.AddColumns(&schema.Column{Name: c.Name, Type: c.Type, Attrs: c.Attrs})
+		}
+	}
 	used := make([]bool, len(to.ForeignKeys))
 	// In SQLite, there is no easy way to get the foreign-key constraint
 	// name, except for parsing the CREATE statement. Therefore, we check
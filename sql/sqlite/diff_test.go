@@ -5,8 +5,10 @@
 package sqlite
 
 import (
+	"context"
 	"testing"
 
+	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -52,6 +54,21 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "modify virtual table",
+			from: schema.NewTable("docs").
+				AddColumns(schema.NewColumn("title").SetType(&schema.BinaryType{T: "blob"})).
+				AddAttrs(&VirtualTable{M: "fts5", A: []string{"title"}}),
+			// Columns of virtual tables can be omitted from the desired state.
+			to: schema.NewTable("docs").
+				AddAttrs(&VirtualTable{M: "fts5", A: []string{"title", "tokenize = 'porter'"}}),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &VirtualTable{M: "fts5", A: []string{"title"}},
+					To:   &VirtualTable{M: "fts5", A: []string{"title", "tokenize = 'porter'"}},
+				},
+			},
+		},
 		{
 			name: "add check",
 			from: &schema.Table{Name: "t1"},
@@ -200,6 +217,8 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c3_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 				{Name: "c3_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: to.Columns[1]}}},
 				{Name: "c4_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "(c4 <> NULL)"}}},
+				{Name: "c5_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "c5 <> NULL  AND\n c5 <> 'a  b'"}}},
+				{Name: "c6_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 			}
 			to.Indexes = []*schema.Index{
 				{Name: "c1_index", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[0]}}},
@@ -207,6 +226,9 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c3_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "c3 <> NULL"}}},
 				{Name: "c3_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, Desc: true, C: to.Columns[1]}}},
 				{Name: "c4_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "c4 <> NULL"}}},
+				// Equivalent predicates with different formatting.
+				{Name: "c5_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{P: "((c5 <> NULL AND c5 <> 'a  b'))"}}},
+				{Name: "c6_predicate", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}, Attrs: []schema.Attr{&IndexPredicate{}}},
 			}
 			return testcase{
 				name: "indexes",
@@ -346,6 +368,143 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	}, changes)
 }
 
+func TestDiff_SchemaAttrDiff(t *testing.T) {
+	from := schema.New("main").AddAttrs(&File{Name: "main.db"}, &UserVersion{V: 1})
+	to := schema.New("main").AddAttrs(&JournalMode{V: "WAL"}, &UserVersion{V: 2})
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifySchema{S: to, Changes: []schema.Change{
+			&schema.ModifyAttr{From: &JournalMode{V: "delete"}, To: &JournalMode{V: "WAL"}},
+			&schema.ModifyAttr{From: &UserVersion{V: 1}, To: &UserVersion{V: 2}},
+		}},
+	}, changes)
+
+	// Pragmas that are not set on the desired state are not managed.
+	from.AddAttrs(&JournalMode{V: "wal"}, &ApplicationID{V: 1})
+	to.Attrs = []schema.Attr{&JournalMode{V: "wal"}, &UserVersion{V: 1}}
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestDiff_ViewsTriggers(t *testing.T) {
+	var (
+		from = schema.New("main").
+			AddTables(
+				schema.NewTable("t").AddTriggers(
+					&schema.Trigger{Name: "t1", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "SELECT 1;"},
+					&schema.Trigger{Name: "t3", Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "SELECT 1;", Attrs: []schema.Attr{&TriggerWhen{P: "old.a  > 1"}}},
+				),
+			).
+			AddViews(
+				schema.NewView("v1", "SELECT 1"),
+				schema.NewView("v2", "SELECT 1").AddColumns(&schema.Column{Name: "a"}),
+			)
+		to = schema.New("main").
+			AddTables(
+				schema.NewTable("t").AddTriggers(
+					&schema.Trigger{Name: "t1", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "SELECT 1;"},
+					&schema.Trigger{Name: "t2", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "SELECT 1;"},
+					&schema.Trigger{Name: "t3", Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "SELECT 1;", Attrs: []schema.Attr{&TriggerWhen{P: "old.a > 2"}}},
+				),
+			).
+			AddViews(
+				schema.NewView("v2", "SELECT 1").AddColumns(&schema.Column{Name: "b"}),
+			)
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.AddTrigger{T: to.Tables[0].Triggers[1]},
+		&schema.ModifyTrigger{From: from.Tables[0].Triggers[1], To: to.Tables[0].Triggers[2]},
+		&schema.DropView{V: from.Views[0]},
+		&schema.ModifyView{From: from.Views[1], To: to.Views[0]},
+	}, changes)
+
+	// Columns are compared only if they are defined on both states,
+	// and WHEN clauses are compared after normalizing their spaces.
+	to.Views[0].Columns = nil
+	to.Tables[0].Triggers[2].Attrs = []schema.Attr{&TriggerWhen{P: "old.a > 1"}}
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+}
+
+func TestDiff_CoreObjects(t *testing.T) {
+	var (
+		from = schema.New("main").
+			AddTables(
+				schema.NewTable("t").AddTriggers(
+					schema.NewTrigger("t1", "SELECT 1"),
+					schema.NewTrigger("t2", "SELECT 1").SetActionTime(schema.TriggerTimeBefore),
+				),
+			).
+			AddViews(
+				schema.NewView("v1", "SELECT 1"),
+				schema.NewView("v2", "SELECT 1;"),
+				schema.NewView("v3", "SELECT 1"),
+			).
+			AddFuncs(
+				schema.NewFunc("f1", "SELECT 1"),
+				schema.NewFunc("f2", "SELECT 1"),
+			)
+		to = schema.New("main").
+			AddTables(
+				schema.NewTable("t").AddTriggers(
+					schema.NewTrigger("t2", "SELECT 1").SetActionTime(schema.TriggerTimeAfter),
+					schema.NewTrigger("t3", "SELECT 1"),
+				),
+			).
+			AddViews(
+				schema.NewView("v2", "SELECT 1"),
+				schema.NewView("v3", "SELECT 2"),
+				schema.NewView("v4", "SELECT 1"),
+			).
+			AddFuncs(
+				schema.NewFunc("f2", "SELECT 1").SetComment("c"),
+				schema.NewFunc("f3", "SELECT 1"),
+			)
+	)
+	expected := []schema.Change{
+		&schema.DropTrigger{T: from.Tables[0].Triggers[0]},
+		&schema.ModifyTrigger{From: from.Tables[0].Triggers[1], To: to.Tables[0].Triggers[0]},
+		&schema.AddTrigger{T: to.Tables[0].Triggers[1]},
+		&schema.DropView{V: from.Views[0]},
+		&schema.ModifyView{From: from.Views[2], To: to.Views[1]},
+		&schema.AddView{V: to.Views[2]},
+		&schema.DropFunc{F: from.Funcs[0]},
+		&schema.ModifyFunc{From: from.Funcs[1], To: to.Funcs[0], Changes: []schema.Change{&schema.AddAttr{A: &schema.Comment{Text: "c"}}}},
+		&schema.AddFunc{F: to.Funcs[1]},
+	}
+	changes, err := (&sqlx.Diff{DiffDriver: objectsDiff{&diff{}}}).SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, expected, changes)
+
+	// Changes are not suggested, unless the driver supports planning them.
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, expected[:6], changes)
+
+	// Schemas with views and functions can be planned.
+	s := schema.New("main").
+		AddTables(schema.NewTable("t").AddColumns(schema.NewIntColumn("a", "int"))).
+		AddViews(schema.NewView("v", "SELECT a FROM t")).
+		AddFuncs(schema.NewFunc("f", "SELECT 1"))
+	changes, err = DefaultDiff.SchemaDiff(schema.New("main"), s)
+	require.NoError(t, err)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", changes)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, "CREATE TABLE `t` (`a` int NOT NULL)", plan.Changes[0].Cmd)
+	require.Equal(t, "CREATE VIEW `v` AS SELECT a FROM t", plan.Changes[1].Cmd)
+}
+
+// objectsDiff is a diff driver that supports all changes.
+type objectsDiff struct{ *diff }
+
+func (objectsDiff) SupportChange(schema.Change) bool { return true }
+
 func TestDefaultDiff(t *testing.T) {
 	changes, err := DefaultDiff.SchemaDiff(
 		schema.New("main").
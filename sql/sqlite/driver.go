@@ -23,6 +23,7 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlerr"
 )
 
 type (
@@ -39,10 +40,14 @@ type (
 	conn struct {
 		schema.ExecQuerier
 		url *sqlclient.URL
+		// libsql indicates the connection is to a libSQL (Turso) database
+		// that extends SQLite with additional ALTER TABLE capabilities.
+		libsql bool
 	}
 )
 
 var _ interface {
+	migrate.CapabilityReporter
 	migrate.StmtScanner
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
@@ -62,25 +67,55 @@ func init() {
 	)
 	sqlclient.Register(
 		"libsql",
-		sqlclient.DriverOpener(Open),
+		sqlclient.DriverOpener(OpenLibSQL),
 		sqlclient.RegisterTxOpener(OpenTx),
 		sqlclient.RegisterCodec(codec, codec),
-		sqlclient.RegisterFlavours("libsql+ws", "libsql+wss", "libsql+file"),
-		sqlclient.RegisterURLParser(sqlclient.URLParserFunc(func(u *url.URL) *sqlclient.URL {
-			dsn := strings.TrimPrefix(u.String(), "libsql+")
-			if strings.HasPrefix(dsn, "file://") {
-				dsn = strings.Replace(dsn, "file://", "file:", 1)
-			}
-			return &sqlclient.URL{URL: u, DSN: dsn, Schema: mainFile}
-		})),
+		sqlclient.RegisterFlavours("libsql+ws", "libsql+wss", "libsql+http", "libsql+https", "libsql+file"),
+		sqlclient.RegisterURLParser(sqlclient.URLParserFunc(parseLibSQL)),
+	)
+	sqlerr.Register(DriverName, classifyError)
+	schema.RegisterJSONTypes(
+		DriverName,
+		(*File)(nil), (*JournalMode)(nil), (*UserVersion)(nil), (*ApplicationID)(nil),
+		(*CreateStmt)(nil), (*AutoIncrement)(nil), (*WithoutRowID)(nil), (*Strict)(nil),
+		(*VirtualTable)(nil), (*IndexPredicate)(nil), (*IndexOrigin)(nil), (*UserDefinedType)(nil),
+		(*TriggerWhen)(nil),
 	)
 }
 
+// parseLibSQL parses libSQL URLs. The "libsql+" prefix selects the protocol
+// used by the client to connect to the database. For example:
+//
+//	libsql://db-org.turso.io?authToken=<token>
+//	libsql+https://db-org.turso.io?authToken=<token>
+//	libsql+file://local.db
+func parseLibSQL(u *url.URL) *sqlclient.URL {
+	dsn := strings.TrimPrefix(u.String(), "libsql+")
+	if strings.HasPrefix(dsn, "file://") {
+		dsn = strings.Replace(dsn, "file://", "file:", 1)
+	}
+	return &sqlclient.URL{URL: u, DSN: dsn, Schema: mainFile}
+}
+
 type urlparse struct{}
 
+// attachParam is the URL query parameter for attaching additional databases
+// to the connection. Its format is "<name>:<file>". For example:
+//
+//	sqlite://main.db?attach=logs:logs.db&attach=cache:cache.db
+const attachParam = "attach"
+
 // ParseURL implements the sqlclient.URLParser interface.
 func (urlparse) ParseURL(u *url.URL) *sqlclient.URL {
 	uc := &sqlclient.URL{URL: u, DSN: strings.TrimPrefix(u.String(), u.Scheme+"://"), Schema: mainFile}
+	if q := u.Query(); q.Has(attachParam) {
+		// The attached databases are not part of the connection DSN, and
+		// connections to multiple databases are bound to the realm scope.
+		q.Del(attachParam)
+		u1 := *u
+		u1.RawQuery = q.Encode()
+		uc.DSN, uc.Schema = strings.TrimSuffix(strings.TrimPrefix(u1.String(), u.Scheme+"://"), "?"), ""
+	}
 	if mode := u.Query().Get("mode"); mode == "memory" {
 		// The "file:" prefix is mandatory for memory modes.
 		uc.DSN = "file:" + uc.DSN
@@ -88,12 +123,76 @@ func (urlparse) ParseURL(u *url.URL) *sqlclient.URL {
 	return uc
 }
 
-func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+// attachments returns the databases to attach from the URL, ordered by their appearance.
+func attachments(u *url.URL) ([][2]string, error) {
+	var dbs [][2]string
+	for _, v := range u.Query()[attachParam] {
+		name, file, ok := strings.Cut(v, ":")
+		if !ok || name == "" || file == "" || strings.ContainsRune(name, '`') {
+			return nil, fmt.Errorf("sql/sqlite: invalid attach parameter %q, expect <name>:<file>", v)
+		}
+		if name == mainFile || name == "temp" {
+			return nil, fmt.Errorf("sql/sqlite: cannot attach database as reserved name %q", name)
+		}
+		dbs = append(dbs, [2]string{name, file})
+	}
+	return dbs, nil
+}
+
+// attach attaches the given databases to the connection. Since attached
+// databases are scoped to a connection, the pool is limited to one.
+func attach(ctx context.Context, db *sql.DB, dbs [][2]string) error {
+	if len(dbs) == 0 {
+		return nil
+	}
+	db.SetMaxOpenConns(1)
+	for _, d := range dbs {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ATTACH DATABASE ? AS `%s`", d[0]), d[1]); err != nil {
+			return fmt.Errorf("sql/sqlite: attaching database %q: %w", d[0], err)
+		}
+	}
+	return nil
+}
+
+// inMemory reports if the URL points to an in-memory database. For example:
+//
+//	sqlite://file::memory:?cache=shared
+//	sqlite://dev?mode=memory
+func inMemory(ur *sqlclient.URL) bool {
+	name, _, _ := strings.Cut(strings.TrimPrefix(ur.DSN, "file:"), "?")
+	return name == ":memory:" || ur.Query().Get("mode") == "memory"
+}
+
+// memoryPool limits the pool of in-memory databases to a single connection that
+// is never closed. Each connection to a private in-memory database opens a new
+// and empty database, and shared-cache databases are deleted once their last
+// connection is closed.
+func memoryPool(db *sql.DB) {
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxIdleTime(0)
+	db.SetConnMaxLifetime(0)
+}
+
+func opener(ctx context.Context, u *url.URL) (*sqlclient.Client, error) {
 	ur := urlparse{}.ParseURL(u)
+	dbs, err := attachments(u)
+	if err != nil {
+		return nil, err
+	}
 	db, err := sql.Open("sqlite3", ur.DSN)
 	if err != nil {
 		return nil, err
 	}
+	if inMemory(ur) {
+		memoryPool(db)
+	}
+	if err := attach(ctx, db, dbs); err != nil {
+		if cerr := db.Close(); cerr != nil {
+			err = fmt.Errorf("%w: %v", err, cerr)
+		}
+		return nil, err
+	}
 	drv, err := Open(db)
 	if err != nil {
 		if cerr := db.Close(); cerr != nil {
@@ -123,6 +222,18 @@ func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	}, nil
 }
 
+// OpenLibSQL opens a new SQLite driver for libSQL databases. Unlike SQLite,
+// libSQL supports altering columns in place using the ALTER COLUMN extension,
+// and therefore, tables are not rebuilt on every column modification.
+func OpenLibSQL(db schema.ExecQuerier) (migrate.Driver, error) {
+	drv, err := Open(db)
+	if err != nil {
+		return nil, err
+	}
+	drv.(*Driver).libsql = true
+	return drv, nil
+}
+
 // Snapshot implements migrate.Snapshoter.
 func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	r, err := d.InspectRealm(ctx, nil)
@@ -147,6 +258,135 @@ func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	}, nil
 }
 
+// SaveSnapshot writes a copy of the connected database to the given file using
+// "VACUUM INTO". Together with LoadSnapshot, it allows callers that replay the
+// same migration directory many times to restore its state without replaying it.
+func (d *Driver) SaveSnapshot(ctx context.Context, path string) error {
+	if _, err := d.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("sql/sqlite: saving snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotName is the name used to attach the snapshot file.
+const snapshotName = "atlas_snapshot"
+
+// LoadSnapshot restores a snapshot created by SaveSnapshot into the connected database,
+// which is expected to be clean. Snapshots that contain virtual tables are not supported,
+// as their shadow tables are managed by their modules and cannot be copied directly.
+func (d *Driver) LoadSnapshot(ctx context.Context, path string) (err error) {
+	if err := d.CheckClean(ctx, nil); err != nil {
+		return err
+	}
+	db, ok := d.ExecQuerier.(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	})
+	if !ok {
+		return fmt.Errorf("sql/sqlite: unexpected connection type %T for loading snapshot", d.ExecQuerier)
+	}
+	// ATTACH is scoped to the connection, and
+	// cannot be executed inside a transaction.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("ATTACH DATABASE ? AS `%s`", snapshotName), path); err != nil {
+		return fmt.Errorf("sql/sqlite: attaching snapshot: %w", err)
+	}
+	defer func() {
+		if _, err2 := conn.ExecContext(ctx, fmt.Sprintf("DETACH DATABASE `%s`", snapshotName)); err2 != nil {
+			err = errors.Join(err, err2)
+		}
+	}()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := loadSnapshot(ctx, tx); err != nil {
+		return errors.Join(fmt.Errorf("sql/sqlite: loading snapshot: %w", err), tx.Rollback())
+	}
+	return tx.Commit()
+}
+
+// loadSnapshot copies the tables and their rows from the attached snapshot, and then
+// creates the rest of the objects (indexes, views and triggers) in their original order.
+// Triggers are created last to ensure they are not fired by the copied rows.
+func loadSnapshot(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		"SELECT `type`, `name`, `sql` FROM `%s`.sqlite_master WHERE `sql` IS NOT NULL AND (`name` NOT LIKE 'sqlite_%%' OR `name` = 'sqlite_sequence') ORDER BY `rowid`",
+		snapshotName,
+	))
+	if err != nil {
+		return err
+	}
+	var tables, others [][2]string
+	for rows.Next() {
+		var typ, name, stmt string
+		if err := rows.Scan(&typ, &name, &stmt); err != nil {
+			return errors.Join(err, rows.Close())
+		}
+		switch {
+		case typ != "table":
+			others = append(others, [2]string{name, stmt})
+		case reVirtual.MatchString(stmt):
+			return errors.Join(fmt.Errorf("virtual table %q is not supported", name), rows.Close())
+		default:
+			tables = append(tables, [2]string{name, stmt})
+		}
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return err
+	}
+	// Foreign keys are checked on commit.
+	if _, err := tx.ExecContext(ctx, "PRAGMA defer_foreign_keys = on"); err != nil {
+		return err
+	}
+	for _, t := range tables {
+		stmt := t[1]
+		// The sqlite_sequence table is created along with the first AUTOINCREMENT
+		// table, and its rows are updated by the copied rows of these tables.
+		if t[0] == "sqlite_sequence" {
+			stmt = "DELETE FROM `main`.`sqlite_sequence`"
+		}
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create table %q: %w", t[0], err)
+		}
+		columns, err := snapshotColumns(ctx, tx, t[0])
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO `main`.`%s` (%s) SELECT %[2]s FROM `%s`.`%[1]s`", t[0], columns, snapshotName)); err != nil {
+			return fmt.Errorf("copy rows of table %q: %w", t[0], err)
+		}
+	}
+	for _, o := range others {
+		if _, err := tx.ExecContext(ctx, o[1]); err != nil {
+			return fmt.Errorf("create %q: %w", o[0], err)
+		}
+	}
+	return nil
+}
+
+// snapshotColumns returns the comma-separated list of the
+// non-generated columns of the given table in the snapshot.
+func snapshotColumns(ctx context.Context, tx *sql.Tx, table string) (string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT `name` FROM pragma_table_xinfo(?, ?) WHERE `hidden` = 0", table, snapshotName)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return "", err
+		}
+		columns = append(columns, "`"+name+"`")
+	}
+	return strings.Join(columns, ", "), rows.Err()
+}
+
 // CheckClean implements migrate.CleanChecker.
 func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
 	r, err := d.InspectRealm(ctx, nil)
@@ -192,6 +432,18 @@ func (d *Driver) Lock(_ context.Context, name string, timeout time.Duration) (sc
 	return acquireLock(path, timeout)
 }
 
+// Capabilities implements migrate.CapabilityReporter.
+func (*Driver) Capabilities() *migrate.Capabilities {
+	return &migrate.Capabilities{
+		TransactionalDDL: true,
+		Check:            true,
+		IndexExpr:        true,
+		IndexPredicate:   true,
+		ForeignKey:       true,
+		RenameColumn:     true,
+	}
+}
+
 // FormatType converts schema type to its column form in the database.
 func (*Driver) FormatType(t schema.Type) (string, error) {
 	return FormatType(t)
@@ -381,8 +633,13 @@ const (
 	TypeReal    = "real"    // SQLITE_TYPE_REAL
 	TypeText    = "text"    // SQLITE_TYPE_TEXT
 	TypeBlob    = "blob"    // SQLITE_TYPE_BLOB
+	TypeAny     = "any"     // Allowed only in STRICT tables.
 )
 
+// strictTypes are the column types allowed in STRICT tables.
+// See: https://www.sqlite.org/stricttables.html.
+var strictTypes = []string{"int", TypeInteger, TypeReal, TypeText, TypeBlob, TypeAny}
+
 // SQLite generated columns types.
 const (
 	virtual = "VIRTUAL"
@@ -395,9 +652,6 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 	if err != nil {
 		return nil, err
 	}
-	if len(schemas) > 1 {
-		return nil, fmt.Errorf("sqlite: multiple database files are not supported by the driver. got: %d", len(schemas))
-	}
 	if opts == nil {
 		opts = &schema.InspectRealmOption{}
 	}
@@ -407,7 +661,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 	)
 	if mode.Is(schema.InspectTables) {
 		for _, s := range schemas {
-			tables, err := i.tables(ctx, nil)
+			tables, err := i.tables(ctx, s, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -420,6 +674,13 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 		}
 		sqlx.LinkSchemaTables(r.Schemas)
 	}
+	if mode.Is(schema.InspectViews) || mode.Is(schema.InspectTriggers) {
+		for _, s := range schemas {
+			if err := i.inspectViews(ctx, s, mode); err != nil {
+				return nil, err
+			}
+		}
+	}
 	return schema.ExcludeRealm(r, opts.Exclude)
 }
 
@@ -448,7 +709,7 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 		mode = sqlx.ModeInspectSchema(opts)
 	)
 	if mode.Is(schema.InspectTables) {
-		tables, err := i.tables(ctx, opts)
+		tables, err := i.tables(ctx, r.Schemas[0], opts)
 		if err != nil {
 			return nil, err
 		}
@@ -460,6 +721,12 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 		}
 		sqlx.LinkSchemaTables(schemas)
 	}
+	// Views and triggers are skipped in case the inspection is limited to specific tables.
+	if (mode.Is(schema.InspectViews) || mode.Is(schema.InspectTriggers)) && len(opts.Tables) == 0 {
+		if err := i.inspectViews(ctx, r.Schemas[0], mode); err != nil {
+			return nil, err
+		}
+	}
 	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
 }
 
@@ -473,7 +740,7 @@ var (
 // SupportChange reports if the change is supported by the differ.
 func (*diff) SupportChange(c schema.Change) bool {
 	switch c.(type) {
-	case *schema.RenameConstraint:
+	case *schema.RenameConstraint, *schema.AddFunc, *schema.AddProc:
 		return false
 	}
 	return true
@@ -7,12 +7,15 @@ package sqlite
 import (
 	"context"
 	"database/sql/driver"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"testing"
 	"time"
 
+	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"github.com/DATA-DOG/go-sqlmock"
@@ -33,6 +36,73 @@ func (m *mockDriver) Open(name string) (driver.Conn, error) {
 	return db.Driver().Open(name)
 }
 
+func TestParseURL_Attach(t *testing.T) {
+	u, err := url.Parse("sqlite://main.db?_fk=1&attach=logs:logs.db&attach=cache:/tmp/cache.db")
+	require.NoError(t, err)
+	ur := urlparse{}.ParseURL(u)
+	require.Equal(t, "main.db?_fk=1", ur.DSN)
+	require.Empty(t, ur.Schema, "attached databases are bound to realm scope")
+	dbs, err := attachments(u)
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{{"logs", "logs.db"}, {"cache", "/tmp/cache.db"}}, dbs)
+
+	u, err = url.Parse("sqlite://main.db?_fk=1")
+	require.NoError(t, err)
+	ur = urlparse{}.ParseURL(u)
+	require.Equal(t, "main.db?_fk=1", ur.DSN)
+	require.Equal(t, "main", ur.Schema)
+
+	for _, v := range []string{"logs", ":logs.db", "main:other.db"} {
+		_, err = attachments(&url.URL{RawQuery: url.Values{"attach": {v}}.Encode()})
+		require.Error(t, err)
+	}
+}
+
+func TestParseURL_LibSQL(t *testing.T) {
+	for u, dsn := range map[string]string{
+		"libsql://db-org.turso.io?authToken=t":       "libsql://db-org.turso.io?authToken=t",
+		"libsql+wss://db-org.turso.io?authToken=t":   "wss://db-org.turso.io?authToken=t",
+		"libsql+https://db-org.turso.io?authToken=t": "https://db-org.turso.io?authToken=t",
+		"libsql+http://127.0.0.1:8080":               "http://127.0.0.1:8080",
+		"libsql+file://local.db":                     "file:local.db",
+	} {
+		pu, err := url.Parse(u)
+		require.NoError(t, err)
+		ur := parseLibSQL(pu)
+		require.Equal(t, dsn, ur.DSN)
+		require.Equal(t, "main", ur.Schema)
+	}
+}
+
+func TestParseURL_Memory(t *testing.T) {
+	for u, memory := range map[string]bool{
+		"sqlite://file::memory:?cache=shared": true,
+		"sqlite://:memory:":                   true,
+		"sqlite://dev?mode=memory&_fk=1":      true,
+		"sqlite://file.db?cache=shared":       false,
+		"sqlite://memory.db":                  false,
+	} {
+		pu, err := url.Parse(u)
+		require.NoError(t, err)
+		require.Equal(t, memory, inMemory(urlparse{}.ParseURL(pu)), u)
+	}
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	memoryPool(db)
+	require.Equal(t, 1, db.Stats().MaxOpenConnections)
+}
+
+func TestAttach(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectExec(sqltest.Escape("ATTACH DATABASE ? AS `logs`")).
+		WithArgs("logs.db").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, attach(context.Background(), db, [][2]string{{"logs", "logs.db"}}))
+	require.Equal(t, 1, db.Stats().MaxOpenConnections)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
 func TestDriver_LockAcquired(t *testing.T) {
 	drv := &Driver{conn: &conn{}}
 
@@ -91,6 +161,76 @@ func TestDriver_CheckClean(t *testing.T) {
 	require.EqualError(t, err, `sql/migrate: connected database is not clean: found multiple tables: 2`)
 }
 
+func TestDriver_Snapshot(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv := &Driver{conn: &conn{ExecQuerier: db}, Inspector: &mockInspector{realm: schema.NewRealm()}}
+	m.ExpectExec(sqltest.Escape("VACUUM INTO ?")).
+		WithArgs("snapshot.db").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, drv.SaveSnapshot(context.Background(), "snapshot.db"))
+
+	m.ExpectExec(sqltest.Escape("ATTACH DATABASE ? AS `atlas_snapshot`")).
+		WithArgs("snapshot.db").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectBegin()
+	m.ExpectQuery(regexp.QuoteMeta("SELECT `type`, `name`, `sql` FROM `atlas_snapshot`.sqlite_master")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"type", "name", "sql"}).
+				AddRow("table", "users", "CREATE TABLE `users` (`id` integer PRIMARY KEY AUTOINCREMENT, `name` text)").
+				AddRow("table", "sqlite_sequence", "CREATE TABLE sqlite_sequence(name,seq)").
+				AddRow("index", "users_name", "CREATE INDEX `users_name` ON `users` (`name`)").
+				AddRow("trigger", "users_insert", "CREATE TRIGGER `users_insert` AFTER INSERT ON `users` BEGIN SELECT 1; END"),
+		)
+	m.ExpectExec(sqltest.Escape("PRAGMA defer_foreign_keys = on")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("CREATE TABLE `users` (`id` integer PRIMARY KEY AUTOINCREMENT, `name` text)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectQuery(sqltest.Escape("SELECT `name` FROM pragma_table_xinfo(?, ?) WHERE `hidden` = 0")).
+		WithArgs("users", "atlas_snapshot").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("id").AddRow("name"))
+	m.ExpectExec(sqltest.Escape("INSERT INTO `main`.`users` (`id`, `name`) SELECT `id`, `name` FROM `atlas_snapshot`.`users`")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	m.ExpectExec(sqltest.Escape("DELETE FROM `main`.`sqlite_sequence`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectQuery(sqltest.Escape("SELECT `name` FROM pragma_table_xinfo(?, ?) WHERE `hidden` = 0")).
+		WithArgs("sqlite_sequence", "atlas_snapshot").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name").AddRow("seq"))
+	m.ExpectExec(sqltest.Escape("INSERT INTO `main`.`sqlite_sequence` (`name`, `seq`) SELECT `name`, `seq` FROM `atlas_snapshot`.`sqlite_sequence`")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(sqltest.Escape("CREATE INDEX `users_name` ON `users` (`name`)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape("CREATE TRIGGER `users_insert` AFTER INSERT ON `users` BEGIN SELECT 1; END")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+	m.ExpectExec(sqltest.Escape("DETACH DATABASE `atlas_snapshot`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, drv.LoadSnapshot(context.Background(), "snapshot.db"))
+
+	// Virtual tables are not supported.
+	m.ExpectExec(sqltest.Escape("ATTACH DATABASE ? AS `atlas_snapshot`")).
+		WithArgs("snapshot.db").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectBegin()
+	m.ExpectQuery(regexp.QuoteMeta("SELECT `type`, `name`, `sql` FROM `atlas_snapshot`.sqlite_master")).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"type", "name", "sql"}).
+				AddRow("table", "docs", "CREATE VIRTUAL TABLE docs USING fts5(body)"),
+		)
+	m.ExpectRollback()
+	m.ExpectExec(sqltest.Escape("DETACH DATABASE `atlas_snapshot`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = drv.LoadSnapshot(context.Background(), "snapshot.db")
+	require.EqualError(t, err, `sql/sqlite: loading snapshot: virtual table "docs" is not supported`)
+	require.NoError(t, m.ExpectationsWereMet())
+
+	// Snapshots are loaded only into clean databases.
+	r := schema.NewRealm(schema.New("main").AddTables(schema.NewTable("users")))
+	drv.Inspector = &mockInspector{realm: r}
+	err = drv.LoadSnapshot(context.Background(), "snapshot.db")
+	require.EqualError(t, err, `sql/migrate: connected database is not clean: found table "users"`)
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm *schema.Realm
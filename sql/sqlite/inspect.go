@@ -25,6 +25,11 @@ func (i *inspect) inspectTable(ctx context.Context, t *schema.Table) error {
 	if err := i.columns(ctx, t); err != nil {
 		return err
 	}
+	// Virtual tables do not support indexes and constraints, and
+	// their columns are derived from the module arguments.
+	if sqlx.Has(t.Attrs, &VirtualTable{}) {
+		return nil
+	}
 	if err := i.indexes(ctx, t); err != nil {
 		return err
 	}
@@ -36,7 +41,7 @@ func (i *inspect) inspectTable(ctx context.Context, t *schema.Table) error {
 
 // columns queries and appends the columns of the given table.
 func (i *inspect) columns(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, t.Name, t.Schema.Name))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q columns: %w", t.Name, err)
 	}
@@ -74,8 +79,12 @@ func (i *inspect) addColumn(t *schema.Table, rows *sql.Rows) error {
 	if defaults.Valid {
 		c.Default = defaultExpr(defaults.String)
 	}
-	// The hidden flag is set to 2 for VIRTUAL columns, and to
-	// 3 for STORED columns. See: sqlite/pragma.c#sqlite3Pragma.
+	// The hidden flag is set to 1 for HIDDEN columns of virtual tables (e.g., the
+	// "rank" column of FTS5), 2 for VIRTUAL columns, and to 3 for STORED columns.
+	// See: sqlite/pragma.c#sqlite3Pragma.
+	if hidden.Int64 == 1 {
+		return nil
+	}
 	if hidden.Int64 >= 2 {
 		if err := setGenExpr(t, c, hidden.Int64); err != nil {
 			return err
@@ -101,7 +110,7 @@ func (i *inspect) addColumn(t *schema.Table, rows *sql.Rows) error {
 
 // indexes queries and appends the indexes of the given table.
 func (i *inspect) indexes(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(indexesQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(indexesQuery, t.Name, t.Schema.Name))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q indexes: %w", t.Name, err)
 	}
@@ -140,13 +149,11 @@ func (i *inspect) addIndexes(t *schema.Table, rows *sql.Rows) error {
 			},
 		}
 		if partial {
-			i := strings.Index(stmt.String, "WHERE")
-			if i == -1 {
+			p, ok := indexPredicate(stmt.String)
+			if !ok {
 				return fmt.Errorf("missing partial WHERE clause in: %s", stmt.String)
 			}
-			idx.Attrs = append(idx.Attrs, &IndexPredicate{
-				P: strings.TrimSpace(stmt.String[i+5:]),
-			})
+			idx.Attrs = append(idx.Attrs, &IndexPredicate{P: p})
 		}
 		t.Indexes = append(t.Indexes, idx)
 	}
@@ -162,7 +169,7 @@ var (
 func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.Index) error {
 	var (
 		hasExpr   bool
-		rows, err = i.QueryContext(ctx, fmt.Sprintf(indexColumnsQuery, idx.Name))
+		rows, err = i.QueryContext(ctx, fmt.Sprintf(indexColumnsQuery, idx.Name, t.Schema.Name))
 	)
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q indexes: %w", t.Name, err)
@@ -223,7 +230,7 @@ func (i *inspect) indexInfo(ctx context.Context, t *schema.Table, idx *schema.In
 
 // fks queries and appends the foreign-keys of the given table.
 func (i *inspect) fks(ctx context.Context, t *schema.Table) error {
-	rows, err := i.QueryContext(ctx, fmt.Sprintf(fksQuery, t.Name))
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(fksQuery, t.Name, t.Schema.Name))
 	if err != nil {
 		return fmt.Errorf("sqlite: querying %q foreign-keys: %w", t.Name, err)
 	}
@@ -286,10 +293,10 @@ func (i *inspect) addFKs(t *schema.Table, rows *sql.Rows) error {
 }
 
 // tableNames returns a list of all tables exist in the schema.
-func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*schema.Table, error) {
+func (i *inspect) tables(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) ([]*schema.Table, error) {
 	var (
 		args  []any
-		query = tablesQuery
+		query = fmt.Sprintf(tablesQuery, s.Name)
 	)
 	if opts != nil && len(opts.Tables) > 0 {
 		query += " AND sqlite_master.name IN (" + strings.Repeat("?, ", len(opts.Tables)-1) + "?)"
@@ -299,7 +306,7 @@ func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*s
 	}
 	rows, err := i.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("sqlite: querying schema tables: %w", err)
+		return nil, fmt.Errorf("sqlite: querying schema %q tables: %w", s.Name, err)
 	}
 	defer rows.Close()
 	var tables []*schema.Table
@@ -324,6 +331,9 @@ func (i *inspect) tables(ctx context.Context, opts *schema.InspectOptions) ([]*s
 		if strict.Bool {
 			t.Attrs = append(t.Attrs, &Strict{})
 		}
+		if v, ok := parseVirtualTable(stmt); ok {
+			t.Attrs = append(t.Attrs, v)
+		}
 		tables = append(tables, t)
 	}
 	return tables, nil
@@ -362,9 +372,65 @@ func (i *inspect) databases(ctx context.Context, opts *schema.InspectRealmOption
 			Attrs: []schema.Attr{&File{Name: file.String}},
 		})
 	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		if err := i.pragmas(ctx, s); err != nil {
+			return nil, err
+		}
+	}
 	return schemas, nil
 }
 
+// pragmas queries the database-level pragmas of the given schema (database), and
+// records the ones that are set to a non-default value as schema attributes.
+func (i *inspect) pragmas(ctx context.Context, s *schema.Schema) error {
+	for _, p := range []string{pragmaJournalMode, pragmaUserVersion, pragmaAppID} {
+		rows, err := i.QueryContext(ctx, fmt.Sprintf("PRAGMA `%s`.%s", s.Name, p))
+		if err != nil {
+			return fmt.Errorf("sqlite: querying %s of %q: %w", p, s.Name, err)
+		}
+		var v string
+		if err := sqlx.ScanOne(rows, &v); err != nil {
+			return fmt.Errorf("sqlite: scanning %s of %q: %w", p, s.Name, err)
+		}
+		a, err := pragmaAttr(p, v)
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			s.AddAttrs(a)
+		}
+	}
+	return nil
+}
+
+// pragmaAttr returns the schema attribute of the given pragma value,
+// or nil if the value is the default one of the database.
+func pragmaAttr(p, v string) (schema.Attr, error) {
+	switch v = strings.ToLower(v); p {
+	// In-memory databases use the "memory" mode by default.
+	case pragmaJournalMode:
+		if v != "delete" && v != "memory" {
+			return &JournalMode{V: v}, nil
+		}
+	case pragmaUserVersion, pragmaAppID:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite: parsing %s value %q: %w", p, v, err)
+		}
+		switch {
+		case n == 0:
+		case p == pragmaUserVersion:
+			return &UserVersion{V: n}, nil
+		default:
+			return &ApplicationID{V: n}, nil
+		}
+	}
+	return nil, nil
+}
+
 type (
 	// File describes a database file.
 	File struct {
@@ -372,6 +438,29 @@ type (
 		Name string
 	}
 
+	// JournalMode describes the journal mode of a database. Note, changing
+	// the mode to WAL is not allowed within a transaction, and therefore,
+	// such changes should be applied with transactions disabled.
+	// https://www.sqlite.org/pragma.html#pragma_journal_mode
+	JournalMode struct {
+		schema.Attr
+		V string
+	}
+
+	// UserVersion describes the user-version integer stored in the database header.
+	// https://www.sqlite.org/pragma.html#pragma_user_version
+	UserVersion struct {
+		schema.Attr
+		V int64
+	}
+
+	// ApplicationID describes the application ID stored in the database header.
+	// https://www.sqlite.org/pragma.html#pragma_application_id
+	ApplicationID struct {
+		schema.Attr
+		V int64
+	}
+
 	// CreateStmt describes the SQL statement used to create a resource.
 	CreateStmt struct {
 		schema.Attr
@@ -403,6 +492,15 @@ type (
 		schema.Attr
 	}
 
+	// VirtualTable describes a virtual table, its module and the arguments
+	// passed to it. For example, FTS5 or R-Tree tables.
+	// See: https://www.sqlite.org/vtab.html
+	VirtualTable struct {
+		schema.Attr
+		M string   // Module name. e.g., fts5.
+		A []string // Module arguments.
+	}
+
 	// IndexPredicate describes a partial index predicate.
 	// See: https://www.sqlite.org/partialindex.html
 	IndexPredicate struct {
@@ -622,6 +720,77 @@ func fillChecks(t *schema.Table) error {
 
 // scanExpr scans the expression string (wrapped with parens)
 // until its end in the given string. e.g. "(a+1), c int ...".
+var (
+	reIdxOn    = regexp.MustCompile(`(?is)\sON\s+.+?\(`)
+	reIdxWhere = regexp.MustCompile(`(?is)^WHERE\s+(.+?)\s*;?$`)
+)
+
+// indexPredicate extracts the WHERE clause of a partial index from its CREATE statement.
+// The clause is searched after the indexed parts to avoid matching WHERE keywords that
+// appear in identifiers or in expressions.
+func indexPredicate(stmt string) (string, bool) {
+	loc := reIdxOn.FindStringIndex(stmt)
+	if loc == nil {
+		return "", false
+	}
+	parts := scanExpr(stmt[loc[1]-1:])
+	if parts == "" {
+		return "", false
+	}
+	matches := reIdxWhere.FindStringSubmatch(strings.TrimSpace(stmt[loc[1]-1+len(parts):]))
+	if len(matches) != 2 {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// reVirtual extracts the module and arguments of a CREATE VIRTUAL TABLE statement.
+var reVirtual = regexp.MustCompile(`(?is)^CREATE\s+VIRTUAL\s+TABLE\s+.+?\s+USING\s+(\w+)\s*(\(.*\))?\s*;?$`)
+
+// parseVirtualTable parses the module and arguments of a virtual table from its CREATE statement.
+func parseVirtualTable(stmt string) (*VirtualTable, bool) {
+	matches := reVirtual.FindStringSubmatch(stmt)
+	if len(matches) != 3 {
+		return nil, false
+	}
+	v := &VirtualTable{M: strings.ToLower(matches[1])}
+	if args := scanExpr(matches[2]); len(args) > 2 {
+		v.A = splitArgs(args[1 : len(args)-1])
+	}
+	return v, true
+}
+
+// splitArgs splits the module arguments by top-level commas.
+func splitArgs(s string) []string {
+	var (
+		args  []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '\'', '"', '`':
+			// Skip quoted strings and identifiers.
+			if j := strings.IndexByte(s[i+1:], s[i]); j != -1 {
+				i += j + 1
+			}
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if a := strings.TrimSpace(s[start:]); a != "" {
+		args = append(args, a)
+	}
+	return args
+}
+
 func scanExpr(expr string) string {
 	var r, l int
 	for i := 0; i < len(expr); i++ {
@@ -647,6 +816,10 @@ func scanExpr(expr string) string {
 const (
 	// Name of main database file.
 	mainFile = "main"
+	// Database-level pragmas that are managed as schema attributes.
+	pragmaJournalMode = "journal_mode"
+	pragmaUserVersion = "user_version"
+	pragmaAppID       = "application_id"
 	// Query to list attached database files.
 	databasesQuery     = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` <> 'temp'"
 	databasesQueryArgs = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` IN (%s)"
@@ -655,19 +828,21 @@ const (
 SELECT
 	sqlite_master.name, sqlite_master.sql, wr, strict
 FROM
-	sqlite_master
+	` + "`%[1]s`" + `.sqlite_master
 	JOIN pragma_table_list(sqlite_master.name)
 WHERE
 	sqlite_master.type = 'table'
-	AND sqlite_master.name NOT LIKE 'sqlite_%'
-	AND sqlite_master.name NOT LIKE 'libsql_%'
+	AND pragma_table_list.schema = '%[1]s'
+	AND pragma_table_list.type <> 'shadow'
+	AND sqlite_master.name NOT LIKE 'sqlite_%%'
+	AND sqlite_master.name NOT LIKE 'libsql_%%'
 `
 	// Query to list table information.
-	columnsQuery = "SELECT `name`, `type`, (not `notnull`) AS `nullable`, `dflt_value`, (`pk` <> 0) AS `pk`, `hidden` FROM pragma_table_xinfo('%s') ORDER BY `cid`"
+	columnsQuery = "SELECT `name`, `type`, (not `notnull`) AS `nullable`, `dflt_value`, (`pk` <> 0) AS `pk`, `hidden` FROM pragma_table_xinfo('%s', '%s') ORDER BY `cid`"
 	// Query to list table indexes.
-	indexesQuery = "SELECT `il`.`name`, `il`.`unique`, `il`.`origin`, `il`.`partial`, `m`.`sql` FROM pragma_index_list('%s') AS il JOIN sqlite_master AS m ON il.name = m.name"
+	indexesQuery = "SELECT `il`.`name`, `il`.`unique`, `il`.`origin`, `il`.`partial`, `m`.`sql` FROM pragma_index_list('%[1]s', '%[2]s') AS il JOIN `%[2]s`.sqlite_master AS m ON il.name = m.name"
 	// Query to list index columns.
-	indexColumnsQuery = "SELECT name, desc FROM pragma_index_xinfo('%s') WHERE key = 1 ORDER BY seqno"
+	indexColumnsQuery = "SELECT name, desc FROM pragma_index_xinfo('%s', '%s') WHERE key = 1 ORDER BY seqno"
 	// Query to list table foreign-keys.
-	fksQuery = "SELECT `id`, `from`, `to`, `table`, `on_update`, `on_delete` FROM pragma_foreign_key_list('%s') ORDER BY id, seq"
+	fksQuery = "SELECT `id`, `from`, `to`, `table`, `on_update`, `on_delete` FROM pragma_foreign_key_list('%s', '%s') ORDER BY id, seq"
 )
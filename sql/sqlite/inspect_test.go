@@ -26,7 +26,7 @@ func TestDriver_InspectTable(t *testing.T) {
 			name: "table columns",
 			before: func(m mock) {
 				m.tableExists("users", true, "CREATE TABLE users(id INTEGER PRIMARY KEY AUTOINCREMENT, w INT GENERATED ALWAYS AS (a*10), x TEXT AS (typeof(c)) STORED, y TEXT AS (substr(b,a,a+2)))")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -84,7 +84,7 @@ func TestDriver_InspectTable(t *testing.T) {
 			name: "table indexes",
 			before: func(m mock) {
 				m.tableExists("users", true, "CREATE TABLE users(id INTEGER PRIMARY KEY)")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -92,7 +92,7 @@ func TestDriver_InspectTable(t *testing.T) {
  c2   | integer       |  0      |             |  0       |  0
  c3   | json          |  0      |             |  0       |  0
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   unique     | origin | partial  |                      sql 
 -------+--------------+--------+----------+-------------------------------------------------------
@@ -101,27 +101,27 @@ func TestDriver_InspectTable(t *testing.T) {
  c1_x  |  0           |  c     |  0       | CREATE INDEX c1_x ON users (f(c1))
  c3_x  |  0           |  c     |  0       | CREATE INDEX c3_x ON users (json_extract(c3, '$.x') desc, json_extract(c3, '$.y') desc)
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1u"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1u", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   desc |
 -------+--------+
  c1   |  1      |
  c2   |  0      |
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_c2"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_c2", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   desc |     
 -------+--------+     
  c1    |  0     |     
  nil   |  0     |     
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_x"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c1_x", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   desc |
 -------+--------+
  nil   |  0     |
 `))
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c3_x"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexColumnsQuery, "c3_x", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name  |   desc |
 -------+--------+
@@ -205,7 +205,7 @@ CREATE TABLE users(
 	CONSTRAINT "id_nonzero" CHECK (id <> 0)
 )
 `)
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -214,7 +214,7 @@ CREATE TABLE users(
  c3   | integer       |  0      |             |  0       |  0
 `))
 				m.noIndexes("users")
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  id |   from    | to | table  | on_update   | on_delete   
 ----+-----------+-------------+-------------+-----------
@@ -257,12 +257,13 @@ CREATE TABLE users(
 ------+-----------
  main |
 `))
+				m.pragmas("main")
 				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
 				rows.AddRow("users", "CREATE TABLE users(id INTEGER PRIMARY KEY) without rowid, strict", 1, 1)
-				m.ExpectQuery(sqltest.Escape(tablesQuery + " AND sqlite_master.name IN (?)")).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
 					WithArgs("users").
 					WillReturnRows(rows)
-				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users"))).
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
 					WillReturnRows(sqltest.Rows(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -278,6 +279,76 @@ CREATE TABLE users(
 				require.Equal(t.Attrs[2], &Strict{})
 			},
 		},
+		{
+			name: "virtual table",
+			before: func(m mock) {
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+					WithArgs("main").
+					WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main |
+`))
+				m.pragmas("main")
+				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
+				rows.AddRow("users", "CREATE VIRTUAL TABLE users USING fts5(name, bio, tokenize = 'porter ascii')", 0, 0)
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
+					WithArgs("users").
+					WillReturnRows(rows)
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
+					WillReturnRows(sqltest.Rows(`
+ name  |   type       | nullable | dflt_value  | primary  | hidden
+-------+--------------+----------+ ------------+----------+----------
+ name  |              |  1       |             |  0       |  0
+ bio   |              |  1       |             |  0       |  0
+ users |              |  1       |             |  0       |  1
+ rank  |              |  1       |             |  0       |  1
+`))
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Equal(&VirtualTable{M: "fts5", A: []string{"name", "bio", "tokenize = 'porter ascii'"}}, t.Attrs[1])
+				require.Len(t.Columns, 2)
+				require.Equal("name", t.Columns[0].Name)
+				require.Equal("bio", t.Columns[1].Name)
+			},
+		},
+		{
+			name: "strict table with generated columns",
+			before: func(m mock) {
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+					WithArgs("main").
+					WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main |
+`))
+				m.pragmas("main")
+				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
+				rows.AddRow("users", "CREATE TABLE users(a int, b int AS (a * 2) STORED, c ANY GENERATED ALWAYS AS (a || 'c')) STRICT", 0, 1)
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
+					WithArgs("users").
+					WillReturnRows(rows)
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "users", "main"))).
+					WillReturnRows(sqltest.Rows(`
+ name |   type       | nullable | dflt_value  | primary  | hidden
+------+--------------+----------+ ------------+----------+----------
+ a    | int          |  1       |             |  0       |  0
+ b    | int          |  1       |             |  0       |  3
+ c    | ANY          |  1       |             |  0       |  2
+`))
+				m.noIndexes("users")
+				m.noFKs("users")
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Attrs, 2)
+				require.Equal(t.Attrs[1], &Strict{})
+				require.Equal([]schema.Attr{&schema.GeneratedExpr{Expr: "(a * 2)", Type: "STORED"}}, t.Columns[1].Attrs)
+				require.Equal(&UserDefinedType{T: TypeAny}, t.Columns[2].Type.Type)
+				require.Equal([]schema.Attr{&schema.GeneratedExpr{Expr: "(a || 'c')", Type: "VIRTUAL"}}, t.Columns[2].Attrs)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -297,6 +368,171 @@ CREATE TABLE users(
 	}
 }
 
+func TestDriver_InspectRealm(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(databasesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main | /tmp/main.db
+ logs | /tmp/logs.db
+`))
+	mock{m}.pragmas("main")
+	mock{m}.pragmas("logs")
+	for _, s := range []string{"main", "logs"} {
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, s))).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "sql", "wr", "strict"}).AddRow("events", "CREATE TABLE events(id int)", nil, nil))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "events", s))).
+			WillReturnRows(sqltest.Rows(`
+ name |   type       | nullable | dflt_value  | primary  | hidden
+------+--------------+----------+ ------------+----------+----------
+ id   | int          |  1       |             |  0       |  0
+`))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, "events", s))).
+			WillReturnRows(sqlmock.NewRows([]string{"name", "unique", "origin", "partial", "sql"}))
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, "events", s))).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "from", "to", "table", "on_update", "on_delete"}))
+	}
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(viewsQuery, "main"))).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "name", "tbl_name", "sql"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(viewsQuery, "logs"))).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"type", "name", "tbl_name", "sql"}).
+				AddRow("view", "recent", "recent", "CREATE VIEW recent(id) AS SELECT id FROM events ORDER BY id DESC LIMIT 10").
+				AddRow("trigger", "events_insert", "events", "CREATE TRIGGER events_insert AFTER INSERT ON events BEGIN SELECT 1; END"),
+		)
+	drv, err := Open(db)
+	require.NoError(t, err)
+	r, err := drv.InspectRealm(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+	require.Len(t, r.Schemas, 2)
+	for i, s := range []string{"main", "logs"} {
+		require.Equal(t, s, r.Schemas[i].Name)
+		require.Equal(t, []schema.Attr{&File{Name: "/tmp/" + s + ".db"}}, r.Schemas[i].Attrs)
+		require.Len(t, r.Schemas[i].Tables, 1)
+		require.Equal(t, r.Schemas[i], r.Schemas[i].Tables[0].Schema)
+	}
+	require.Empty(t, r.Schemas[0].Views)
+	require.Empty(t, r.Schemas[0].Tables[0].Triggers)
+	require.Len(t, r.Schemas[1].Views, 1)
+	v := r.Schemas[1].Views[0]
+	require.Equal(t, "recent", v.Name)
+	require.Equal(t, r.Schemas[1], v.Schema)
+	require.Equal(t, []*schema.Column{{Name: "id"}}, v.Columns)
+	require.Equal(t, "SELECT id FROM events ORDER BY id DESC LIMIT 10", v.Def)
+	tb := r.Schemas[1].Tables[0]
+	require.Len(t, tb.Triggers, 1)
+	tr := tb.Triggers[0]
+	require.Equal(t, "events_insert", tr.Name)
+	require.Equal(t, tb, tr.Table)
+	require.Equal(t, schema.TriggerTimeAfter, tr.ActionTime)
+	require.Equal(t, []schema.TriggerEvent{schema.TriggerEventInsert}, tr.Events)
+	require.Equal(t, schema.TriggerForRow, tr.For)
+	require.Equal(t, "SELECT 1;", tr.Body)
+}
+
+func TestParseViewTrigger(t *testing.T) {
+	for stmt, v := range map[string]*schema.View{
+		"CREATE VIEW v AS SELECT 1":                                   {Def: "SELECT 1"},
+		"CREATE VIEW `main`.`v` (`a`, \"b\") AS\n  SELECT 1, 2;":      {Columns: []*schema.Column{{Name: "a"}, {Name: "b"}}, Def: "SELECT 1, 2"},
+		"create temp view if not exists [v] as select * from t as t1": {Def: "select * from t as t1"},
+	} {
+		got, err := parseView(stmt)
+		require.NoError(t, err)
+		require.Equal(t, v, got)
+	}
+	_, err := parseView("CREATE VIEW v")
+	require.Error(t, err)
+
+	for stmt, expected := range map[string]struct {
+		on string
+		t  *schema.Trigger
+	}{
+		"CREATE TRIGGER tr AFTER INSERT ON t BEGIN SELECT 1; END": {
+			on: "t",
+			t:  &schema.Trigger{ActionTime: schema.TriggerTimeAfter, For: schema.TriggerForRow, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, Body: "SELECT 1;"},
+		},
+		"CREATE TRIGGER `main`.`tr` UPDATE OF a, `b` ON `main`.`users` BEGIN\n  SELECT 1;\n  SELECT 2;\nEND": {
+			on: "users",
+			t:  &schema.Trigger{ActionTime: schema.TriggerTimeBefore, For: schema.TriggerForRow, Events: []schema.TriggerEvent{schema.TriggerEventUpdateOf(&schema.Column{Name: "a"}, &schema.Column{Name: "b"})}, Body: "SELECT 1;\n  SELECT 2;"},
+		},
+		"create trigger tr instead  of delete on \"my view\" for each row when old.id > 1 begin select 1; end": {
+			on: "my view",
+			t:  &schema.Trigger{ActionTime: schema.TriggerTimeInstead, For: schema.TriggerForRow, Events: []schema.TriggerEvent{schema.TriggerEventDelete}, Body: "select 1;", Attrs: []schema.Attr{&TriggerWhen{P: "old.id > 1"}}},
+		},
+	} {
+		tr, on, err := parseTrigger(stmt)
+		require.NoError(t, err)
+		require.Equal(t, expected.on, on)
+		require.Equal(t, expected.t, tr)
+	}
+	_, _, err = parseTrigger("CREATE TRIGGER tr AFTER INSERT BEGIN SELECT 1; END")
+	require.Error(t, err)
+}
+
+func TestDriver_InspectPragmas(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(databasesQueryArgs, "?"))).
+		WithArgs("main").
+		WillReturnRows(sqltest.Rows(`
+ name |   file
+------+-----------
+ main | /tmp/main.db
+`))
+	for _, p := range [][2]string{{"journal_mode", "wal"}, {"user_version", "3"}, {"application_id", "1095"}} {
+		m.ExpectQuery(sqltest.Escape("PRAGMA `main`." + p[0])).
+			WillReturnRows(sqlmock.NewRows([]string{p[0]}).AddRow(p[1]))
+	}
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{Mode: schema.InspectSchemas})
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+	require.Equal(t, []schema.Attr{
+		&File{Name: "/tmp/main.db"},
+		&JournalMode{V: "wal"},
+		&UserVersion{V: 3},
+		&ApplicationID{V: 1095},
+	}, s.Attrs)
+}
+
+func TestIndexPredicate(t *testing.T) {
+	for _, tt := range []struct {
+		stmt string
+		p    string
+	}{
+		{stmt: "CREATE INDEX i ON t(a)"},
+		{stmt: "CREATE INDEX i ON t(a) WHERE a > 0", p: "a > 0"},
+		{stmt: "create index i on t (a, lower(b)) where (a > 0);", p: "(a > 0)"},
+		{stmt: "CREATE INDEX `where` ON `where`(`WHERE`) WHERE `WHERE` IS NOT NULL", p: "`WHERE` IS NOT NULL"},
+		{stmt: "CREATE UNIQUE INDEX i ON t(a)\nWHERE\n  a IN ('(', ')')", p: "a IN ('(', ')')"},
+	} {
+		p, ok := indexPredicate(tt.stmt)
+		require.Equal(t, tt.p != "", ok, tt.stmt)
+		require.Equal(t, tt.p, p)
+	}
+}
+
+func TestParseVirtualTable(t *testing.T) {
+	for _, tt := range []struct {
+		stmt string
+		v    *VirtualTable
+	}{
+		{stmt: "CREATE TABLE t(a int)"},
+		{stmt: "CREATE VIRTUAL TABLE t USING fts5(a, b)", v: &VirtualTable{M: "fts5", A: []string{"a", "b"}}},
+		{stmt: "create virtual table if not exists `my t` using RTREE(id, minX, maxX);", v: &VirtualTable{M: "rtree", A: []string{"id", "minX", "maxX"}}},
+		{stmt: "CREATE VIRTUAL TABLE t USING fts5(a, content='', tokenize = \"unicode61 remove_diacritics 2, x\")", v: &VirtualTable{M: "fts5", A: []string{"a", "content=''", `tokenize = "unicode61 remove_diacritics 2, x"`}}},
+		{stmt: "CREATE VIRTUAL TABLE t USING dbstat", v: &VirtualTable{M: "dbstat"}},
+	} {
+		v, ok := parseVirtualTable(tt.stmt)
+		require.Equal(t, tt.v != nil, ok)
+		require.Equal(t, tt.v, v)
+	}
+}
+
 func TestRegex_TableFK(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -534,7 +770,7 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 		require.NoError(t, err)
 		mk := mock{m}
 		mk.tableExists(name, true, tt.input)
-		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, name))).
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, name, "main"))).
 			WillReturnRows(sqltest.Rows(fmt.Sprintf(`
  name |   type       | nullable | dflt_value  | primary  | hidden
 ------+--------------+----------+ ------------+----------+----------
@@ -565,26 +801,34 @@ func (m mock) tableExists(table string, exists bool, stmt ...string) {
 ------+-----------
  main |   
 `))
+	m.pragmas("main")
 	rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
 	if exists {
 		rows.AddRow(table, stmt[0], nil, nil)
 	}
-	m.ExpectQuery(sqltest.Escape(tablesQuery + " AND sqlite_master.name IN (?)")).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "main") + " AND sqlite_master.name IN (?)")).
 		WithArgs(table).
 		WillReturnRows(rows)
 }
 
+func (m mock) pragmas(s string) {
+	for _, p := range [][2]string{{"journal_mode", "delete"}, {"user_version", "0"}, {"application_id", "0"}} {
+		m.ExpectQuery(sqltest.Escape(fmt.Sprintf("PRAGMA `%s`.%s", s, p[0]))).
+			WillReturnRows(sqlmock.NewRows([]string{p[0]}).AddRow(p[1]))
+	}
+}
+
 func (m mock) noColumns(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt_value", "primary"}))
 }
 
 func (m mock) noIndexes(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"name", "unique", "origin", "partial", "sql"}))
 }
 
 func (m mock) noFKs(table string) {
-	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, table))).
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, table, "main"))).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "from", "to", "table", "on_update", "on_delete"}))
 }
@@ -6,13 +6,19 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
+
+	"golang.org/x/mod/semver"
 )
 
 // DefaultPlan provides basic planning capabilities for SQLite dialects.
@@ -31,11 +37,11 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 			Name:          name,
 			Transactional: true,
 		},
-		PlanOptions: migrate.PlanOptions{
-			// Currently, the driver does not support attached
-			// schemas and assumed the connected schema is "main".
-			SchemaQualifier: new(string),
-		},
+	}
+	// Unless other databases were attached to the connection,
+	// the connected schema is assumed to be "main".
+	if p.url == nil || p.url.Schema != "" {
+		s.SchemaQualifier = new(string)
 	}
 	for _, o := range opts {
 		o(&s.PlanOptions)
@@ -52,6 +58,13 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 		// Callers should note that these 2 pragmas are no-op in transactions,
 		// See: https://sqlite.org/pragma.html#pragma_foreign_keys.
 		s.Changes = append([]*migrate.Change{{Cmd: "PRAGMA foreign_keys = off", Comment: "disable the enforcement of foreign-keys constraints"}}, s.Changes...)
+		// Verify the foreign-keys of the rebuilt tables before enforcing them back.
+		for _, t := range s.fkChecks {
+			s.append(&migrate.Change{
+				Cmd:     fmt.Sprintf("%s(%s)", pragmaFKCheck, s.Build().Ident(t).String()),
+				Comment: fmt.Sprintf("check foreign-keys constraints of the rebuilt %q table", t),
+			})
+		}
 		s.append(&migrate.Change{Cmd: "PRAGMA foreign_keys = on", Comment: "enable back the enforcement of foreign-keys constraints"})
 	}
 	return &s.Plan, nil
@@ -61,7 +74,40 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 // if the driver is unable to produce a plan to it, or one of the statements
 // is failed or unsupported.
 func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
-	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+	return sqlx.ApplyChanges(ctx, changes, &fkCheckApply{planApply: p}, opts...)
+}
+
+// pragmaFKCheck is the pragma that is used to verify the foreign-keys
+// constraints of tables that were rebuilt by the plan.
+const pragmaFKCheck = "PRAGMA foreign_key_check"
+
+// fkCheckApply wraps the planApply, and fails the foreign-keys checks
+// of the plan in case they report violations. Note, the result rows of
+// the pragma are ignored when it is executed as a regular statement.
+type fkCheckApply struct{ *planApply }
+
+// ExecContext implements the schema.ExecQuerier interface.
+func (p *fkCheckApply) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if !strings.HasPrefix(query, pragmaFKCheck) {
+		return p.planApply.ExecContext(ctx, query, args...)
+	}
+	rows, err := p.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		var (
+			tbl, ref string
+			row      sql.NullInt64
+			idx      int
+		)
+		if err := rows.Scan(&tbl, &row, &ref, &idx); err != nil {
+			return nil, fmt.Errorf("scanning foreign-keys violations: %w", err)
+		}
+		return nil, fmt.Errorf("foreign-key constraint violation: row %d of table %q references a missing row in table %q", row.Int64, tbl, ref)
+	}
+	return driver.RowsAffected(0), rows.Err()
 }
 
 // state represents the state of a planning. It's not part of
@@ -71,26 +117,53 @@ type state struct {
 	*conn
 	migrate.Plan
 	migrate.PlanOptions
-	skipFKs bool
+	skipFKs  bool
+	fkChecks []string // Rebuilt tables with foreign-keys.
+	triggers []string // Triggers that are created by the plan.
+	version  string   // Lazily loaded SQLite version.
 }
 
 // Exec executes the changes on the database. An error is returned
 // if one of the operations fail, or a change is not supported.
 func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
-	for _, c := range changes {
+	if err := s.Policy.Check(changes); err != nil {
+		return err
+	}
+	drops, changes, adds := s.splitObjects(changes)
+	for _, c := range drops {
+		if err := s.dropObject(c); err != nil {
+			return err
+		}
+	}
+	for _, c := range sqlx.SplitBackfills(changes) {
 		switch c := c.(type) {
 		case *schema.AddTable:
 			err = s.addTable(ctx, c)
 		case *schema.DropTable:
 			err = s.dropTable(ctx, c)
 		case *schema.ModifyTable:
-			err = s.modifyTable(ctx, c)
+			m, data := sqlx.DataChanges(c)
+			if len(data) == 0 || len(m.Changes) > 0 {
+				err = s.modifyTable(ctx, m)
+			}
+			if err == nil {
+				err = s.dataChanges(data...)
+			}
+		case *schema.DataChange:
+			err = s.dataChanges(c)
 		case *schema.RenameTable:
 			s.renameTable(c)
+		case *schema.ModifySchema:
+			err = s.modifySchema(c)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
 		if err != nil {
+			return sqlx.PosError(c, err)
+		}
+	}
+	for _, c := range adds {
+		if err := s.addObject(c); err != nil {
 			return err
 		}
 	}
@@ -99,6 +172,10 @@ func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
 
 // addTable builds and executes the query for creating a table in a schema.
 func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
+	if v := (VirtualTable{}); sqlx.Has(add.T.Attrs, &v) {
+		s.addVirtualTable(add, &v)
+		return nil
+	}
 	var (
 		errs []string
 		b    = s.Build("CREATE TABLE").Table(add.T)
@@ -106,9 +183,14 @@ func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
 		b.P("IF NOT EXISTS")
 	}
+	strict := sqlx.Has(add.T.Attrs, &Strict{})
 	b.WrapIndent(func(b *sqlx.Builder) {
 		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
-			if err := s.column(b, add.T.Columns[i]); err != nil {
+			err := s.column(b, add.T.Columns[i])
+			if err == nil && strict {
+				err = strictType(add.T.Columns[i])
+			}
+			if err != nil {
 				errs = append(errs, err.Error())
 			}
 		})
@@ -128,14 +210,18 @@ func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 			}
 		}
 	})
+	wr := sqlx.Has(add.T.Attrs, &WithoutRowID{})
+	if pk := add.T.PrimaryKey; wr && pk != nil && autoincPK(pk) {
+		errs = append(errs, "AUTOINCREMENT is not allowed on WITHOUT ROWID tables")
+	}
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
 	}
 	var options []string
-	if sqlx.Has(add.T.Attrs, &WithoutRowID{}) {
+	if wr {
 		options = append(options, "WITHOUT ROWID")
 	}
-	if sqlx.Has(add.T.Attrs, &Strict{}) {
+	if strict {
 		options = append(options, "STRICT")
 	}
 	b.MapComma(options, func(i int, b *sqlx.Builder) {
@@ -153,6 +239,25 @@ func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 	return s.addIndexes(add.T, add.T.Indexes...)
 }
 
+// addVirtualTable builds and executes the query for creating a virtual table.
+func (s *state) addVirtualTable(add *schema.AddTable, v *VirtualTable) {
+	b := s.Build("CREATE VIRTUAL TABLE")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	m := v.M
+	if len(v.A) > 0 {
+		m += "(" + strings.Join(v.A, ", ") + ")"
+	}
+	b.Table(add.T).P("USING", m)
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q virtual table", add.T.Name),
+	})
+}
+
 // dropTable builds and executes the query for dropping a table from a schema.
 func (s *state) dropTable(ctx context.Context, drop *schema.DropTable) error {
 	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
@@ -164,7 +269,7 @@ func (s *state) dropTable(ctx context.Context, drop *schema.DropTable) error {
 	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
 		b.P("IF EXISTS")
 	}
-	b.Ident(drop.T.Name)
+	b.Table(drop.T)
 	s.append(&migrate.Change{
 		Cmd:     b.String(),
 		Source:  drop,
@@ -190,7 +295,7 @@ func (s *state) dropTable(ctx context.Context, drop *schema.DropTable) error {
 // addition, the changes are applied using a temporary table following the procedure mentioned
 // in: https://www.sqlite.org/lang_altertable.html#making_other_kinds_of_table_schema_changes.
 func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) error {
-	if alterable(modify) {
+	if s.alterable(ctx, modify) {
 		return s.alterTable(modify)
 	}
 	s.skipFKs = true
@@ -206,9 +311,16 @@ func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) err
 	if err != nil {
 		return err
 	}
+	// Renaming a table validates the views and triggers of the schema, which
+	// fail in case they reference the dropped table. The legacy behavior skips
+	// this validation, as they are valid after the new table is renamed.
+	legacy := modify.T.Schema != nil && hasViewsOrTriggers(modify.T.Schema)
+	if legacy {
+		s.append(&migrate.Change{Cmd: "PRAGMA legacy_alter_table = on", Comment: "disable the validation of views and triggers on table renaming"})
+	}
 	// Drop the current table, and rename the new one to its real name.
 	s.append(&migrate.Change{
-		Cmd:    s.Build("DROP TABLE").Ident(modify.T.Name).String(),
+		Cmd:    s.Build("DROP TABLE").Table(modify.T).String(),
 		Source: modify,
 		Comment: fmt.Sprintf("drop %q table %s", modify.T.Name, func() string {
 			if copied {
@@ -218,19 +330,76 @@ func (s *state) modifyTable(ctx context.Context, modify *schema.ModifyTable) err
 		}()),
 	})
 	s.append(&migrate.Change{
-		Cmd:     s.Build("ALTER TABLE").Ident(newT.Name).P("RENAME TO").Ident(modify.T.Name).String(),
+		Cmd:     s.Build("ALTER TABLE").Table(&newT).P("RENAME TO").Ident(modify.T.Name).String(),
 		Source:  modify,
 		Comment: fmt.Sprintf("rename temporary table %q to %q", newT.Name, modify.T.Name),
 	})
+	if legacy {
+		s.append(&migrate.Change{Cmd: "PRAGMA legacy_alter_table = off", Comment: "enable back the validation of views and triggers on table renaming"})
+	}
+	if err := s.recreateTriggers(modify); err != nil {
+		return err
+	}
+	if len(modify.T.ForeignKeys) > 0 && !slices.Contains(s.fkChecks, modify.T.Name) {
+		s.fkChecks = append(s.fkChecks, modify.T.Name)
+	}
 	return s.addIndexes(modify.T, indexes...)
 }
 
+// modifySchema builds and appends the migrate.Changes for setting
+// the database-level pragmas of the schema (database).
+func (s *state) modifySchema(modify *schema.ModifySchema) error {
+	for _, c := range modify.Changes {
+		m, ok := c.(*schema.ModifyAttr)
+		if !ok {
+			return fmt.Errorf("unsupported ModifySchema change %T", c)
+		}
+		name, to, ok1 := pragmaValue(m.To)
+		prev, from, ok2 := pragmaValue(m.From)
+		if !ok1 || !ok2 || name != prev {
+			return fmt.Errorf("unexpected schema attribute change: %T to %T", m.From, m.To)
+		}
+		s.append(&migrate.Change{
+			Cmd:     s.Build("PRAGMA").SchemaResource(modify.S, name).P("=", to).String(),
+			Reverse: s.Build("PRAGMA").SchemaResource(modify.S, name).P("=", from).String(),
+			Source:  modify,
+			Comment: fmt.Sprintf("set %s of %q schema", name, modify.S.Name),
+		})
+	}
+	return nil
+}
+
+// pragmaValue returns the pragma name and value of the given schema attribute.
+func pragmaValue(a schema.Attr) (string, string, bool) {
+	switch a := a.(type) {
+	case *JournalMode:
+		return pragmaJournalMode, a.V, true
+	case *UserVersion:
+		return pragmaUserVersion, strconv.FormatInt(a.V, 10), true
+	case *ApplicationID:
+		return pragmaAppID, strconv.FormatInt(a.V, 10), true
+	}
+	return "", "", false
+}
+
+// dataChanges plans the given data changes.
+func (s *state) dataChanges(data ...*schema.DataChange) error {
+	for _, d := range data {
+		c, err := sqlx.PlanDataChange(s.Build(), d)
+		if err != nil {
+			return err
+		}
+		s.append(c)
+	}
+	return nil
+}
+
 func (s *state) renameTable(c *schema.RenameTable) {
 	s.append(&migrate.Change{
 		Source:  c,
 		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
-		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Table(c.To).String(),
-		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Table(c.From).String(),
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Ident(c.To.Name).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Ident(c.From.Name).String(),
 	})
 }
 
@@ -265,7 +434,7 @@ func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
 }
 
 func (s *state) dropIndexes(t *schema.Table, indexes ...*schema.Index) error {
-	rs := &state{conn: s.conn}
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
 	if err := rs.addIndexes(t, indexes...); err != nil {
 		return err
 	}
@@ -290,17 +459,19 @@ func (s *state) addIndexes(t *schema.Table, indexes ...*schema.Index) error {
 		}
 		b.P("INDEX")
 		if idx.Name != "" {
-			b.Ident(idx.Name)
+			b.SchemaResource(t.Schema, idx.Name)
 		}
+		// The indexed table cannot be qualified, as it is
+		// expected to be in the same schema of the index.
 		b.P("ON").Ident(t.Name)
 		s.indexParts(b, idx.Parts)
-		if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) {
+		if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) && p.P != "" {
 			b.P("WHERE").P(p.P)
 		}
 		s.append(&migrate.Change{
 			Cmd:     b.String(),
 			Source:  &schema.AddIndex{I: idx},
-			Reverse: s.Build("DROP INDEX").Ident(idx.Name).String(),
+			Reverse: s.Build("DROP INDEX").SchemaResource(t.Schema, idx.Name).String(),
 			Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
 		})
 	}
@@ -446,8 +617,8 @@ func (s *state) copyRows(from *schema.Table, to *schema.Table, changes []schema.
 	if insert {
 		s.append(&migrate.Change{
 			Cmd: fmt.Sprintf(
-				"INSERT INTO `%s` (%s) SELECT %s FROM `%s`",
-				to.Name, identComma(toC), identComma(fromC), from.Name,
+				"INSERT INTO %s (%s) SELECT %s FROM %s",
+				s.Build().Table(to), identComma(toC), identComma(fromC), s.Build().Table(from),
 			),
 			Comment: fmt.Sprintf("copy rows from old table %q to new temporary table %q", from.Name, to.Name),
 		})
@@ -475,7 +646,7 @@ func (s *state) alterTable(modify *schema.ModifyTable) error {
 				return err
 			}
 		case *schema.AddColumn:
-			b := s.Build("ALTER TABLE").Ident(modify.T.Name)
+			b := s.Build("ALTER TABLE").Table(modify.T)
 			r := b.Clone()
 			if err := s.column(b.P("ADD COLUMN"), change.C); err != nil {
 				return err
@@ -486,8 +657,35 @@ func (s *state) alterTable(modify *schema.ModifyTable) error {
 				Reverse: r.P("DROP COLUMN").Ident(change.C.Name).String(),
 				Comment: fmt.Sprintf("add column %q to table: %q", change.C.Name, modify.T.Name),
 			})
+		case *schema.DropColumn:
+			b := s.Build("ALTER TABLE").Table(modify.T)
+			r := b.Clone()
+			if err := s.column(r.P("ADD COLUMN"), change.C); err != nil {
+				return err
+			}
+			s.append(&migrate.Change{
+				Source:  change,
+				Cmd:     b.P("DROP COLUMN").Ident(change.C.Name).String(),
+				Reverse: r.String(),
+				Comment: fmt.Sprintf("drop column %q from table: %q", change.C.Name, modify.T.Name),
+			})
+		case *schema.ModifyColumn:
+			b := s.Build("ALTER TABLE").Table(modify.T).P("ALTER COLUMN").Ident(change.To.Name).P("TO")
+			r := b.Clone()
+			if err := s.column(b, change.To); err != nil {
+				return err
+			}
+			if err := s.column(r, change.From); err != nil {
+				return err
+			}
+			s.append(&migrate.Change{
+				Source:  change,
+				Cmd:     b.String(),
+				Reverse: r.String(),
+				Comment: fmt.Sprintf("modify %q column of table: %q", change.To.Name, modify.T.Name),
+			})
 		case *schema.RenameColumn:
-			b := s.Build("ALTER TABLE").Ident(modify.T.Name).P("RENAME COLUMN")
+			b := s.Build("ALTER TABLE").Table(modify.T).P("RENAME COLUMN")
 			r := b.Clone()
 			s.append(&migrate.Change{
 				Source:  change,
@@ -538,10 +736,24 @@ func (s *state) append(c *migrate.Change) {
 	s.Changes = append(s.Changes, c)
 }
 
-func alterable(modify *schema.ModifyTable) bool {
+// alterable reports if the table changes can be applied using the ALTER TABLE command.
+// Otherwise, the table is rebuilt by creating a new table and copying the rows to it.
+func (s *state) alterable(ctx context.Context, modify *schema.ModifyTable) bool {
+	// Virtual tables cannot be altered, except for being renamed.
+	if sqlx.Has(modify.T.Attrs, &VirtualTable{}) {
+		return false
+	}
 	for _, change := range modify.Changes {
 		switch change := change.(type) {
 		case *schema.RenameColumn, *schema.RenameIndex, *schema.DropIndex, *schema.AddIndex:
+		case *schema.DropColumn:
+			if !droppable(modify, change.C) || !s.supportsDropColumn(ctx) {
+				return false
+			}
+		case *schema.ModifyColumn:
+			if !s.libsql || !alterableColumn(modify.T, change) {
+				return false
+			}
 		case *schema.AddColumn:
 			if len(change.C.Indexes) > 0 || len(change.C.ForeignKeys) > 0 {
 				return false
@@ -567,6 +779,92 @@ func alterable(modify *schema.ModifyTable) bool {
 	return true
 }
 
+// alterableColumn reports if the column modification can be applied using the libSQL
+// ALTER COLUMN extension. Note, libSQL does not validate or convert existing rows when
+// a column is altered. Hence, changes that tighten the column constraints, or change its
+// generated expression, still require the table to be rebuilt.
+// See: https://github.com/tursodatabase/libsql/blob/main/libsql-sqlite3/doc/libsql_extensions.md#altering-columns
+func alterableColumn(t *schema.Table, m *schema.ModifyColumn) bool {
+	switch {
+	case m.From.Name != m.To.Name, m.Change.Is(schema.ChangeGenerated), m.Change.Is(schema.ChangeNull) && !m.To.Type.Null:
+		return false
+	case t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool {
+		return p.C != nil && p.C.Name == m.To.Name
+	}):
+		return false
+	default:
+		return !sqlx.Has(m.To.Attrs, &schema.GeneratedExpr{})
+	}
+}
+
+// dropColumnVersion is the first version that supports the ALTER TABLE DROP COLUMN command.
+const dropColumnVersion = "v3.35.0"
+
+// supportsDropColumn reports if the connected database supports the ALTER TABLE DROP COLUMN
+// command. Databases that their version cannot be queried (e.g., DefaultPlan), fall back to
+// the table rebuild procedure that is supported by all versions.
+func (s *state) supportsDropColumn(ctx context.Context) bool {
+	if s.version == "" {
+		s.version = "unknown"
+		if rows, err := s.QueryContext(ctx, "SELECT sqlite_version()"); err == nil {
+			var v string
+			if err := sqlx.ScanOne(rows, &v); err == nil {
+				s.version = "v" + v
+			}
+		}
+	}
+	return semver.IsValid(s.version) && semver.Compare(s.version, dropColumnVersion) >= 0
+}
+
+// droppable reports if the column can be dropped using the ALTER TABLE DROP COLUMN command.
+// Columns that are part of keys, indexes, or referenced by other parts of the table cannot
+// be dropped, and their table is rebuilt instead. Note, views and triggers are not checked.
+// See: https://www.sqlite.org/lang_altertable.html#alter_table_drop_column.
+func droppable(modify *schema.ModifyTable, c *schema.Column) bool {
+	if len(c.Indexes) > 0 || len(c.ForeignKeys) > 0 {
+		return false
+	}
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) && storedOrVirtual(x.Type) == stored {
+		return false
+	}
+	used := regexp.MustCompile("(?i)(^|[^\\w$])[\"`\\[]?" + regexp.QuoteMeta(c.Name) + "[\"`\\]]?($|[^\\w$])").MatchString
+	for _, change := range modify.Changes {
+		if d, ok := change.(*schema.DropIndex); ok && slices.ContainsFunc(d.I.Parts, func(p *schema.IndexPart) bool {
+			return p.C != nil && p.C.Name == c.Name
+		}) {
+			return false
+		}
+	}
+	for _, a := range modify.T.Attrs {
+		if ck, ok := a.(*schema.Check); ok && used(ck.Expr) {
+			return false
+		}
+	}
+	for _, tc := range modify.T.Columns {
+		if x := (schema.GeneratedExpr{}); sqlx.Has(tc.Attrs, &x) && used(x.Expr) {
+			return false
+		}
+	}
+	for _, idx := range modify.T.Indexes {
+		if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) && used(p.P) {
+			return false
+		}
+	}
+	return true
+}
+
+// strictType reports an error if the column type is not allowed in STRICT tables.
+func strictType(c *schema.Column) error {
+	t, err := FormatType(c.Type.Type)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(strictTypes, strings.ToLower(t)) {
+		return fmt.Errorf("column %q of type %q is not allowed in STRICT tables, expected one of: %s", c.Name, t, strings.Join(strictTypes, ", "))
+	}
+	return nil
+}
+
 // checks writes the CHECK constraint to the builder.
 func check(b *sqlx.Builder, c *schema.Check) {
 	expr := c.Expr
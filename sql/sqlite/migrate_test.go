@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"ariga.io/atlas/sql/internal/sqltest"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/require"
@@ -456,6 +458,157 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Drop column using ALTER TABLE on supported versions.
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+					Changes: []schema.Change{
+						&schema.DropColumn{C: schema.NewStringColumn("name", "text")},
+					},
+				},
+			},
+			mock: func(m mock) {
+				m.ExpectQuery(sqltest.Escape("SELECT sqlite_version()")).
+					WillReturnRows(sqlmock.NewRows([]string{"sqlite_version()"}).AddRow("3.45.1"))
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "ALTER TABLE `users` DROP COLUMN `name`", Reverse: "ALTER TABLE `users` ADD COLUMN `name` text NOT NULL"},
+				},
+			},
+		},
+		// Columns that are used by the table cannot be dropped using ALTER TABLE.
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("users").
+						AddColumns(schema.NewIntColumn("id", "int")).
+						AddChecks(schema.NewCheck().SetExpr("(`name` <> '')")),
+					Changes: []schema.Change{
+						&schema.DropColumn{C: schema.NewStringColumn("name", "text")},
+					},
+				},
+			},
+			mock: func(m mock) {
+				m.ExpectQuery(sqltest.Escape("SELECT sqlite_version()")).
+					WillReturnRows(sqlmock.NewRows([]string{"sqlite_version()"}).AddRow("3.45.1"))
+			},
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE TABLE `new_users` (`id` int NOT NULL, CHECK (`name` <> ''))", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`) SELECT `id` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// Old versions rebuild the table, and check its foreign-keys before enforcing them back.
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					id := schema.NewIntColumn("id", "int")
+					pid := schema.NewIntColumn("parent_id", "int")
+					users := schema.NewTable("users").AddColumns(id, pid)
+					users.AddForeignKeys(schema.NewForeignKey("parent").AddColumns(pid).SetRefTable(users).AddRefColumns(id))
+					return &schema.ModifyTable{
+						T: users,
+						Changes: []schema.Change{
+							&schema.DropColumn{C: schema.NewStringColumn("name", "text")},
+						},
+					}
+				}(),
+			},
+			mock: func(m mock) {
+				m.ExpectQuery(sqltest.Escape("SELECT sqlite_version()")).
+					WillReturnRows(sqlmock.NewRows([]string{"sqlite_version()"}).AddRow("3.31.1"))
+			},
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE TABLE `new_users` (`id` int NOT NULL, `parent_id` int NOT NULL, CONSTRAINT `parent` FOREIGN KEY (`parent_id`) REFERENCES `users` (`id`))", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`, `parent_id`) SELECT `id`, `parent_id` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "PRAGMA foreign_key_check(`users`)"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// Virtual tables.
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("docs").AddAttrs(&VirtualTable{M: "fts5", A: []string{"title", "body", "tokenize = 'porter'"}}),
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE VIRTUAL TABLE `docs` USING fts5(title, body, tokenize = 'porter')", Reverse: "DROP TABLE `docs`"},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					docs := schema.NewTable("docs").
+						AddColumns(
+							schema.NewColumn("title").SetType(&schema.BinaryType{T: "blob"}),
+							schema.NewColumn("body").SetType(&schema.BinaryType{T: "blob"}),
+						).
+						AddAttrs(&VirtualTable{M: "fts5", A: []string{"title", "body", "tokenize = 'porter'"}})
+					return &schema.ModifyTable{
+						T: docs,
+						Changes: []schema.Change{
+							&schema.ModifyAttr{
+								From: &VirtualTable{M: "fts5", A: []string{"title", "body"}},
+								To:   &VirtualTable{M: "fts5", A: []string{"title", "body", "tokenize = 'porter'"}},
+							},
+						},
+					}
+				}(),
+			},
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE VIRTUAL TABLE `new_docs` USING fts5(title, body, tokenize = 'porter')", Reverse: "DROP TABLE `new_docs`"},
+					{Cmd: "INSERT INTO `new_docs` (`title`, `body`) SELECT `title`, `body` FROM `docs`"},
+					{Cmd: "DROP TABLE `docs`"},
+					{Cmd: "ALTER TABLE `new_docs` RENAME TO `docs`"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
+		// STRICT tables with generated columns.
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("t").
+						AddColumns(
+							schema.NewIntColumn("a", "integer"),
+							schema.NewIntColumn("b", "int").SetGeneratedExpr(&schema.GeneratedExpr{Expr: "a * 2", Type: "STORED"}),
+							schema.NewColumn("c").SetType(&UserDefinedType{T: TypeAny}),
+						).
+						AddAttrs(&Strict{}),
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "CREATE TABLE `t` (`a` integer NOT NULL, `b` int NOT NULL AS (a * 2) STORED, `c` any NOT NULL) STRICT", Reverse: "DROP TABLE `t`"},
+				},
+			},
+		},
 		// Custom qualifier.
 		{
 			changes: []schema.Change{
@@ -501,6 +654,183 @@ func TestPlanChanges(t *testing.T) {
 	}
 }
 
+func TestPlanChanges_Attached(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+	// Connections with attached databases are bound to the realm scope.
+	drv.(*Driver).url = &sqlclient.URL{}
+	id, name := schema.NewIntColumn("id", "int"), schema.NewStringColumn("name", "text")
+	users := schema.NewTable("users").
+		SetSchema(schema.New("logs")).
+		AddColumns(id, name)
+	users.AddIndexes(schema.NewIndex("users_name").AddColumns(name))
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: users},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: schema.NewNullIntColumn("id", "int"), To: id, Change: schema.ChangeNull},
+			},
+		},
+		&schema.RenameTable{From: users, To: schema.NewTable("events").SetSchema(users.Schema)},
+	})
+	require.NoError(t, err)
+	cmds := make([]string, len(plan.Changes))
+	for i, c := range plan.Changes {
+		cmds[i] = c.Cmd
+	}
+	require.Equal(t, []string{
+		"PRAGMA foreign_keys = off",
+		"CREATE TABLE `logs`.`users` (`id` int NOT NULL, `name` text NOT NULL)",
+		"CREATE INDEX `logs`.`users_name` ON `users` (`name`)",
+		"CREATE TABLE `logs`.`new_users` (`id` int NOT NULL, `name` text NOT NULL)",
+		"INSERT INTO `logs`.`new_users` (`id`, `name`) SELECT `id`, `name` FROM `logs`.`users`",
+		"DROP TABLE `logs`.`users`",
+		"ALTER TABLE `logs`.`new_users` RENAME TO `users`",
+		"CREATE INDEX `logs`.`users_name` ON `users` (`name`)",
+		"ALTER TABLE `logs`.`users` RENAME TO `events`",
+		"PRAGMA foreign_keys = on",
+	}, cmds)
+}
+
+func TestPlanChanges_LibSQL(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := OpenLibSQL(db)
+	require.NoError(t, err)
+	users := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewNullStringColumn("name", "text").SetDefault(&schema.Literal{V: "'a8m'"}),
+			schema.NewIntColumn("age", "int"),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	// Columns are altered in place, unless their constraints are tightened.
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: schema.NewStringColumn("name", "varchar"), To: users.Columns[1], Change: schema.ChangeNull | schema.ChangeType | schema.ChangeDefault},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.True(t, plan.Reversible)
+	require.Equal(t, "ALTER TABLE `users` ALTER COLUMN `name` TO `name` text NULL DEFAULT 'a8m'", plan.Changes[0].Cmd)
+	require.Equal(t, "ALTER TABLE `users` ALTER COLUMN `name` TO `name` varchar NOT NULL", plan.Changes[0].Reverse)
+
+	plan, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: schema.NewNullIntColumn("age", "int"), To: users.Columns[2], Change: schema.ChangeNull},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "CREATE TABLE `new_users` (`id` int NOT NULL, `name` text NULL DEFAULT 'a8m', `age` int NOT NULL, PRIMARY KEY (`id`))", plan.Changes[1].Cmd)
+
+	// Regular SQLite connections always rebuild the table.
+	drv, err = Open(db)
+	require.NoError(t, err)
+	plan, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.ModifyColumn{From: schema.NewStringColumn("name", "varchar"), To: users.Columns[1], Change: schema.ChangeNull | schema.ChangeType | schema.ChangeDefault},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "PRAGMA foreign_keys = off", plan.Changes[0].Cmd)
+}
+
+func TestPlanChanges_WithoutRowID(t *testing.T) {
+	id := schema.NewIntColumn("id", "integer").AddAttrs(&AutoIncrement{})
+	_, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{
+			T: schema.NewTable("t").
+				AddColumns(id).
+				SetPrimaryKey(schema.NewPrimaryKey(id)).
+				AddAttrs(&WithoutRowID{}),
+		},
+	})
+	require.EqualError(t, err, `create table "t": AUTOINCREMENT is not allowed on WITHOUT ROWID tables`)
+}
+
+func TestPlanChanges_Pragmas(t *testing.T) {
+	s := schema.New("main")
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifySchema{S: s, Changes: []schema.Change{
+			&schema.ModifyAttr{From: &JournalMode{V: "delete"}, To: &JournalMode{V: "wal"}},
+			&schema.ModifyAttr{From: &UserVersion{V: 1}, To: &UserVersion{V: 2}},
+			&schema.ModifyAttr{From: &ApplicationID{}, To: &ApplicationID{V: 1095}},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 3)
+	for i, c := range []struct{ cmd, reverse string }{
+		{"PRAGMA `journal_mode` = wal", "PRAGMA `journal_mode` = delete"},
+		{"PRAGMA `user_version` = 2", "PRAGMA `user_version` = 1"},
+		{"PRAGMA `application_id` = 1095", "PRAGMA `application_id` = 0"},
+	} {
+		require.Equal(t, c.cmd, plan.Changes[i].Cmd)
+		require.Equal(t, c.reverse, plan.Changes[i].Reverse)
+	}
+
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifySchema{S: s, Changes: []schema.Change{
+			&schema.ModifyAttr{From: &UserVersion{}, To: &ApplicationID{V: 1}},
+		}},
+	})
+	require.EqualError(t, err, "unexpected schema attribute change: *sqlite.UserVersion to *sqlite.ApplicationID")
+}
+
+func TestPlanChanges_StrictTypes(t *testing.T) {
+	_, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{
+			T: schema.NewTable("t").
+				AddColumns(
+					schema.NewIntColumn("a", "integer"),
+					schema.NewStringColumn("b", "varchar(255)"),
+				).
+				AddAttrs(&Strict{}),
+		},
+	})
+	require.EqualError(t, err, `create table "t": column "b" of type "varchar(255)" is not allowed in STRICT tables, expected one of: int, integer, real, text, blob, any`)
+}
+
+func TestPlanApply_ForeignKeyCheck(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	drv, err := Open(db)
+	require.NoError(t, err)
+	id, pid := schema.NewIntColumn("id", "int"), schema.NewIntColumn("parent_id", "int")
+	users := schema.NewTable("users").AddColumns(id, pid)
+	users.AddForeignKeys(schema.NewForeignKey("parent").AddColumns(pid).SetRefTable(users).AddRefColumns(id))
+	changes := []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.DropColumn{C: schema.NewStringColumn("name", "text")},
+			},
+		},
+	}
+	m.ExpectQuery(sqltest.Escape("SELECT sqlite_version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"sqlite_version()"}).AddRow("3.31.1"))
+	for _, q := range []string{"PRAGMA foreign_keys = off", "CREATE TABLE `new_users`", "INSERT INTO `new_users`", "DROP TABLE `users`", "ALTER TABLE `new_users` RENAME TO `users`"} {
+		m.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	m.ExpectQuery(sqltest.Escape("PRAGMA foreign_key_check(`users`)")).
+		WillReturnRows(sqlmock.NewRows([]string{"table", "rowid", "parent", "fkid"}).AddRow("users", 2, "users", 0))
+	err = drv.ApplyChanges(context.Background(), changes)
+	require.EqualError(t, err, `check foreign-keys constraints of the rebuilt "users" table: foreign-key constraint violation: row 2 of table "users" references a missing row in table "users"`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
 func TestDefaultPlan(t *testing.T) {
 	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
 		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", "int"))},
@@ -639,3 +969,55 @@ func TestIndentedPlan(t *testing.T) {
 }
 
 func join(lines ...string) string { return strings.Join(lines, "\n") }
+
+func TestPlanChanges_ViewsTriggers(t *testing.T) {
+	var (
+		s  = schema.New("main")
+		a  = schema.NewIntColumn("a", "int")
+		b  = schema.NewStringColumn("b", "text")
+		tb = schema.NewTable("t").AddColumns(a, b)
+		v1 = schema.NewView("v1", "SELECT a FROM t")
+		v2 = schema.NewView("v2", "SELECT b FROM t").AddColumns(&schema.Column{Name: "x"})
+		v3 = schema.NewView("v3", "SELECT 1")
+		t1 = &schema.Trigger{Name: "t1", ActionTime: schema.TriggerTimeAfter, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, For: schema.TriggerForRow, Body: "SELECT 1;"}
+		t2 = &schema.Trigger{Name: "t2", ActionTime: schema.TriggerTimeInstead, Events: []schema.TriggerEvent{schema.TriggerEventInsert}, For: schema.TriggerForRow, Body: "SELECT 1;"}
+		t3 = &schema.Trigger{Name: "t3", ActionTime: schema.TriggerTimeInstead, Events: []schema.TriggerEvent{schema.TriggerEventDelete}, For: schema.TriggerForRow, Body: "SELECT 1;"}
+	)
+	tb.AddTriggers(t1)
+	v2.AddTriggers(t2)
+	v3.AddTriggers(t3)
+	s.AddTables(tb).AddViews(v1, v2)
+	v3.Schema = s
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTrigger{T: t2},
+		&schema.AddView{V: v2},
+		&schema.ModifyView{From: &schema.View{Name: "v1", Schema: s, Def: "SELECT 1"}, To: v1},
+		&schema.DropView{V: v3},
+		&schema.DropTrigger{T: t3},
+		&schema.ModifyTable{T: tb, Changes: []schema.Change{
+			&schema.ModifyColumn{From: schema.NewIntColumn("a", "text"), To: a, Change: schema.ChangeType},
+		}},
+	})
+	require.NoError(t, err)
+	var cmds []string
+	for _, c := range plan.Changes {
+		cmds = append(cmds, c.Cmd)
+	}
+	require.Equal(t, []string{
+		"PRAGMA foreign_keys = off",
+		"DROP TRIGGER `t3`",
+		"DROP VIEW `v1`",
+		"DROP VIEW `v3`",
+		"CREATE TABLE `new_t` (`a` int NOT NULL, `b` text NOT NULL)",
+		"INSERT INTO `new_t` (`a`, `b`) SELECT `a`, `b` FROM `t`",
+		"PRAGMA legacy_alter_table = on",
+		"DROP TABLE `t`",
+		"ALTER TABLE `new_t` RENAME TO `t`",
+		"PRAGMA legacy_alter_table = off",
+		"CREATE TRIGGER `t1` AFTER INSERT ON `t` BEGIN SELECT 1; END",
+		"CREATE VIEW `v2` (`x`) AS SELECT b FROM t",
+		"CREATE VIEW `v1` AS SELECT a FROM t",
+		"CREATE TRIGGER `t2` INSTEAD OF INSERT ON `v2` BEGIN SELECT 1; END",
+		"PRAGMA foreign_keys = on",
+	}, cmds)
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/sqlerr"
+)
+
+// errCategories maps the SQLite primary result codes to their categories.
+// https://www.sqlite.org/rescode.html
+var errCategories = map[int]sqlerr.Category{
+	3:  sqlerr.PermissionDenied, // SQLITE_PERM
+	5:  sqlerr.Retryable,        // SQLITE_BUSY
+	6:  sqlerr.Locked,           // SQLITE_LOCKED
+	8:  sqlerr.PermissionDenied, // SQLITE_READONLY
+	23: sqlerr.PermissionDenied, // SQLITE_AUTH
+}
+
+// classifyError implements sqlerr.Classifier for the errors returned by the SQLite drivers.
+// The result code is read using the Code method implemented by modernc.org/sqlite, or from
+// the ExtendedCode field of github.com/mattn/go-sqlite3.Error.
+func classifyError(err error) (sqlerr.Category, string, bool) {
+	var code int
+	if e := (interface{ Code() int })(nil); errors.As(err, &e) {
+		code = e.Code()
+	} else if f, ok := sqlx.ErrorField(err, "ExtendedCode"); ok && f.CanInt() {
+		code = int(f.Int())
+	} else {
+		return sqlerr.Unknown, "", false
+	}
+	c := errCategories[code&0xff]
+	switch {
+	// SQLITE_CONSTRAINT_PRIMARYKEY and SQLITE_CONSTRAINT_UNIQUE.
+	case code == 1555 || code == 2067:
+		c = sqlerr.Duplicate
+	// SQLITE_ERROR is a generic code, and the error is classified by its message.
+	case code == 1:
+		switch msg := err.Error(); {
+		case strings.Contains(msg, "no such "):
+			c = sqlerr.NotExist
+		case strings.Contains(msg, "already exists"), strings.Contains(msg, "duplicate column name"):
+			c = sqlerr.Duplicate
+		case strings.Contains(msg, "syntax error"):
+			c = sqlerr.SyntaxError
+		}
+	}
+	return c, strconv.Itoa(code), true
+}
@@ -0,0 +1,60 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/sqlerr"
+
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// sqliteError mimics the error type of github.com/mattn/go-sqlite3.
+	sqliteError struct {
+		Code         errNo
+		ExtendedCode errNoExtended
+		err          string
+	}
+	errNo         int
+	errNoExtended int
+	// moderncError mimics the error type of modernc.org/sqlite.
+	moderncError struct {
+		code int
+		msg  string
+	}
+)
+
+func (e sqliteError) Error() string   { return e.err }
+func (e *moderncError) Error() string { return e.msg }
+func (e *moderncError) Code() int     { return e.code }
+
+func TestClassifyError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want sqlerr.Category
+		code string
+	}{
+		{err: sqliteError{Code: 1, ExtendedCode: 1, err: "no such table: t"}, want: sqlerr.NotExist, code: "1"},
+		{err: sqliteError{Code: 1, ExtendedCode: 1, err: "table t already exists"}, want: sqlerr.Duplicate, code: "1"},
+		{err: sqliteError{Code: 1, ExtendedCode: 1, err: "duplicate column name: id"}, want: sqlerr.Duplicate, code: "1"},
+		{err: sqliteError{Code: 1, ExtendedCode: 1, err: `near "TABL": syntax error`}, want: sqlerr.SyntaxError, code: "1"},
+		{err: sqliteError{Code: 19, ExtendedCode: 1555, err: "UNIQUE constraint failed: t.id"}, want: sqlerr.Duplicate, code: "1555"},
+		{err: sqliteError{Code: 19, ExtendedCode: 787, err: "FOREIGN KEY constraint failed"}, want: sqlerr.Unknown, code: "787"},
+		{err: fmt.Errorf("exec: %w", sqliteError{Code: 5, ExtendedCode: 261, err: "database is locked"}), want: sqlerr.Retryable, code: "261"},
+		{err: &moderncError{code: 6, msg: "database table is locked"}, want: sqlerr.Locked, code: "6"},
+		{err: &moderncError{code: 8, msg: "attempt to write a readonly database"}, want: sqlerr.PermissionDenied, code: "8"},
+	} {
+		c, code, ok := classifyError(tt.err)
+		require.True(t, ok)
+		require.Equal(t, tt.want, c, tt.err.Error())
+		require.Equal(t, tt.code, code, tt.err.Error())
+	}
+	_, _, ok := classifyError(errors.New("error"))
+	require.False(t, ok)
+}
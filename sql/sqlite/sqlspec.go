@@ -20,8 +20,10 @@ import (
 )
 
 type doc struct {
-	Tables  []*sqlspec.Table  `spec:"table"`
-	Schemas []*sqlspec.Schema `spec:"schema"`
+	Tables   []*sqlspec.Table  `spec:"table"`
+	Views    []*view           `spec:"view"`
+	Triggers []*trigger        `spec:"trigger"`
+	Schemas  []*sqlspec.Schema `spec:"schema"`
 }
 
 // Codec for schemahcl.
@@ -48,6 +50,18 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		); err != nil {
 			return fmt.Errorf("sqlite: failed converting to *schema.Realm: %w", err)
 		}
+		for _, spec := range d.Schemas {
+			s, ok := v.Schema(spec.Name)
+			if !ok {
+				return fmt.Errorf("sqlite: could not find schema: %q", spec.Name)
+			}
+			if err := convertPragmas(spec, s); err != nil {
+				return err
+			}
+		}
+		if err := convertViews(&d, v); err != nil {
+			return fmt.Errorf("sqlite: failed converting to *schema.Realm: %w", err)
+		}
 	case *schema.Schema:
 		var d doc
 		if err := c.State.EvalOptions(p, &d, opts); err != nil {
@@ -63,6 +77,12 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		); err != nil {
 			return err
 		}
+		if err := convertPragmas(d.Schemas[0], r.Schemas[0]); err != nil {
+			return err
+		}
+		if err := convertViews(&d, r); err != nil {
+			return err
+		}
 		*v = *r.Schemas[0]
 	case schema.Schema, schema.Realm:
 		return fmt.Errorf("sqlite: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
@@ -72,11 +92,52 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 	return nil
 }
 
+// JSONSchema returns the JSON Schema of the Atlas DDL documents of the driver.
+// See schemahcl.State.JSONSchema for more info.
+func (c *Codec) JSONSchema() ([]byte, error) {
+	return c.State.JSONSchema(&doc{})
+}
+
 // MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
 func (c *Codec) MarshalSpec(v any) ([]byte, error) {
-	return specutil.Marshal(v, c.State, specutil.RealmFuncs{
-		Schema: schemaSpec,
-	})
+	var (
+		d       = &doc{}
+		schemas []*schema.Schema
+	)
+	switch v := v.(type) {
+	case *schema.Schema:
+		schemas = []*schema.Schema{v}
+	case *schema.Realm:
+		schemas = v.Schemas
+	default:
+		return nil, fmt.Errorf("sqlite: failed marshaling spec. %T is not supported", v)
+	}
+	for _, s := range schemas {
+		spec, err := schemaSpec(s)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite: failed converting schema to spec: %w", err)
+		}
+		d.Tables = append(d.Tables, spec.Tables...)
+		d.Schemas = append(d.Schemas, spec.Schema)
+		if err := viewSpecs(d, s); err != nil {
+			return nil, fmt.Errorf("sqlite: failed converting views to spec: %w", err)
+		}
+	}
+	if r, ok := v.(*schema.Realm); ok {
+		if err := specutil.QualifyObjects(d.Tables); err != nil {
+			return nil, err
+		}
+		if err := specutil.QualifyObjects(d.Views); err != nil {
+			return nil, err
+		}
+		if err := specutil.QualifyObjects(d.Triggers); err != nil {
+			return nil, err
+		}
+		if err := specutil.QualifyReferences(d.Tables, r); err != nil {
+			return nil, err
+		}
+	}
+	return c.State.MarshalSpec(d)
 }
 
 // convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
@@ -105,6 +166,18 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 			t.AddAttrs(&Strict{})
 		}
 	}
+	if attr, ok := spec.Attr("module"); ok {
+		v := &VirtualTable{}
+		if v.M, err = attr.String(); err != nil {
+			return nil, err
+		}
+		if attr, ok := spec.Attr("module_args"); ok {
+			if v.A, err = attr.Strings(); err != nil {
+				return nil, err
+			}
+		}
+		t.AddAttrs(v)
+	}
 	return t, nil
 }
 
@@ -119,7 +192,9 @@ func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
 		if err != nil {
 			return nil, err
 		}
-		idx.Attrs = append(idx.Attrs, &IndexPredicate{P: p})
+		if p != "" {
+			idx.Attrs = append(idx.Attrs, &IndexPredicate{P: p})
+		}
 	}
 	return idx, nil
 }
@@ -152,9 +227,48 @@ func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
 
 // schemaSpec converts from a concrete SQLite schema to Atlas specification.
 func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
-	return specutil.FromSchema(s, &specutil.SchemaFuncs{
+	spec, err := specutil.FromSchema(s, &specutil.SchemaFuncs{
 		Table: tableSpec,
 	})
+	if err != nil {
+		return nil, err
+	}
+	if m := (JournalMode{}); sqlx.Has(s.Attrs, &m) {
+		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, schemahcl.StringAttr(pragmaJournalMode, m.V))
+	}
+	if v := (UserVersion{}); sqlx.Has(s.Attrs, &v) {
+		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, schemahcl.Int64Attr(pragmaUserVersion, v.V))
+	}
+	if id := (ApplicationID{}); sqlx.Has(s.Attrs, &id) {
+		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, schemahcl.Int64Attr(pragmaAppID, id.V))
+	}
+	return spec, nil
+}
+
+// convertPragmas converts the database-level pragmas of the schema spec into schema attributes.
+func convertPragmas(spec *sqlspec.Schema, s *schema.Schema) error {
+	if attr, ok := spec.Attr(pragmaJournalMode); ok {
+		v, err := attr.String()
+		if err != nil {
+			return err
+		}
+		s.AddAttrs(&JournalMode{V: strings.ToLower(v)})
+	}
+	if attr, ok := spec.Attr(pragmaUserVersion); ok {
+		v, err := attr.Int64()
+		if err != nil {
+			return err
+		}
+		s.AddAttrs(&UserVersion{V: v})
+	}
+	if attr, ok := spec.Attr(pragmaAppID); ok {
+		v, err := attr.Int64()
+		if err != nil {
+			return err
+		}
+		s.AddAttrs(&ApplicationID{V: v})
+	}
+	return nil
 }
 
 // tableSpec converts from a concrete SQLite sqlspec.Table to a schema.Table.
@@ -177,6 +291,12 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 	if sqlx.Has(t.Attrs, &Strict{}) {
 		options.SetAttr(schemahcl.BoolAttr("strict", true))
 	}
+	if v := (VirtualTable{}); sqlx.Has(t.Attrs, &v) {
+		options.SetAttr(schemahcl.StringAttr("module", v.M))
+		if len(v.A) > 0 {
+			options.SetAttr(schemahcl.StringsAttr("module_args", v.A...))
+		}
+	}
 	if options != nil {
 		spec.Extra.Children = append(spec.Extra.Children, options)
 	}
@@ -255,6 +375,7 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec("json"),
 		schemahcl.NewTypeSpec("uuid"),
 		schemahcl.NewTypeSpec("jsonb"),
+		schemahcl.NewTypeSpec(TypeAny),
 	),
 )
 
@@ -262,6 +383,7 @@ var (
 	codec = &Codec{
 		State: schemahcl.New(append(
 			specOptions,
+			schemahcl.WithDialect("sqlite"),
 			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
 			schemahcl.WithScopedEnums("table.column.as.type", stored, virtual),
 			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
@@ -6,6 +6,7 @@ package sqlite
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/internal/spectest"
@@ -274,6 +275,11 @@ schema "test" {
 }
 `
 	require.EqualValues(t, expected, string(buf))
+
+	// Empty predicates are ignored.
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(strings.Replace(expected, `"id <> 0"`, `""`, 1)), &got, nil))
+	require.Empty(t, got.Tables[0].Indexes[0].Attrs)
 }
 
 func TestTypes(t *testing.T) {
@@ -293,6 +299,10 @@ func TestTypes(t *testing.T) {
 			typeExpr: `sql("custom")`,
 			expected: &UserDefinedType{T: "custom"},
 		},
+		{
+			typeExpr: "any",
+			expected: &UserDefinedType{T: "any"},
+		},
 		{
 			typeExpr: "tinyint(10)",
 			expected: &schema.IntegerType{T: "tinyint"},
@@ -442,6 +452,9 @@ func TestMarshalSpec_TableOptions(t *testing.T) {
 			schema.NewTable("users").
 				AddColumns(
 					schema.NewIntColumn("id", "int"),
+					schema.NewIntColumn("double_id", "integer").
+						SetGeneratedExpr(&schema.GeneratedExpr{Expr: "id * 2", Type: "STORED"}),
+					schema.NewColumn("data").SetType(&UserDefinedType{T: TypeAny}),
 				).
 				AddAttrs(
 					&WithoutRowID{},
@@ -457,6 +470,18 @@ func TestMarshalSpec_TableOptions(t *testing.T) {
     null = false
     type = int
   }
+  column "double_id" {
+    null = false
+    type = integer
+    as {
+      expr = "id * 2"
+      type = STORED
+    }
+  }
+  column "data" {
+    null = false
+    type = any
+  }
   without_rowid = true
   strict        = true
 }
@@ -464,6 +489,113 @@ schema "test" {
 }
 `
 	require.EqualValues(t, expected, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, []schema.Attr{&WithoutRowID{}, &Strict{}}, got.Tables[0].Attrs)
+	require.Equal(t, []schema.Attr{&schema.GeneratedExpr{Expr: "id * 2", Type: "STORED"}}, got.Tables[0].Columns[1].Attrs)
+	require.Equal(t, &UserDefinedType{T: TypeAny}, got.Tables[0].Columns[2].Type.Type)
+}
+
+func TestMarshalSpec_VirtualTable(t *testing.T) {
+	s := schema.New("main").
+		AddTables(
+			schema.NewTable("docs").
+				AddColumns(
+					schema.NewColumn("title").SetType(&schema.BinaryType{T: "blob"}),
+				).
+				AddAttrs(&VirtualTable{M: "fts5", A: []string{"title", "tokenize = 'porter'"}}),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "docs" {
+  schema = schema.main
+  column "title" {
+    null = false
+    type = blob
+  }
+  module      = "fts5"
+  module_args = ["title", "tokenize = 'porter'"]
+}
+schema "main" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, []schema.Attr{&VirtualTable{M: "fts5", A: []string{"title", "tokenize = 'porter'"}}}, got.Tables[0].Attrs)
+}
+
+func TestMarshalSpec_Pragmas(t *testing.T) {
+	s := schema.New("main").AddAttrs(&JournalMode{V: "wal"}, &UserVersion{V: 3}, &ApplicationID{V: 1095})
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `schema "main" {
+  journal_mode   = "wal"
+  user_version   = 3
+  application_id = 1095
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Equal(t, s.Attrs, got.Attrs)
+}
+
+func TestMarshalSpec_ViewsTriggers(t *testing.T) {
+	tb := schema.NewTable("t").AddColumns(schema.NewIntColumn("a", "int"))
+	tb.AddTriggers(&schema.Trigger{
+		Name:       "tr",
+		ActionTime: schema.TriggerTimeAfter,
+		Events:     []schema.TriggerEvent{schema.TriggerEventInsert},
+		For:        schema.TriggerForRow,
+		Body:       "SELECT 1;",
+	})
+	s := schema.New("main").AddTables(tb).AddViews(
+		schema.NewView("v", "SELECT a FROM t").AddColumns(&schema.Column{Name: "x"}),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.main
+  column "a" {
+    null = false
+    type = int
+  }
+}
+view "v" {
+  schema  = schema.main
+  as      = "SELECT a FROM t"
+  columns = ["x"]
+}
+trigger "tr" {
+  schema = schema.main
+  as     = "AFTER INSERT ON `+"`t`"+` BEGIN SELECT 1; END"
+}
+schema "main" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	require.Len(t, got.Views, 1)
+	require.Equal(t, "v", got.Views[0].Name)
+	require.Equal(t, "SELECT a FROM t", got.Views[0].Def)
+	require.Equal(t, []*schema.Column{{Name: "x"}}, got.Views[0].Columns)
+	require.Len(t, got.Tables, 1)
+	require.Len(t, got.Tables[0].Triggers, 1)
+	tr := got.Tables[0].Triggers[0]
+	require.Equal(t, "tr", tr.Name)
+	require.Equal(t, got.Tables[0], tr.Table)
+	require.Equal(t, schema.TriggerTimeAfter, tr.ActionTime)
+	require.Equal(t, []schema.TriggerEvent{schema.TriggerEventInsert}, tr.Events)
+	require.Equal(t, "SELECT 1;", tr.Body)
+
+	err = EvalHCLBytes([]byte(`
+schema "main" {}
+trigger "tr" {
+  schema = schema.main
+  as     = "AFTER INSERT BEGIN SELECT 1; END"
+}
+`), &got, nil)
+	require.EqualError(t, err, `missing table (ON clause) in the definition (as) of trigger "tr"`)
 }
 
 func TestInputVars(t *testing.T) {
@@ -0,0 +1,43 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDialect describes the SQLite rules that are checked by schema.Validate.
+// SQLite does not limit the length of identifiers.
+var DefaultDialect = &schema.Dialect{
+	Name: "sqlite",
+	Reserved: func(s string) bool {
+		return reserved[strings.ToUpper(s)]
+	},
+	// Index names are unique per database (schema).
+	SchemaScoped: func(kind string) bool {
+		return kind == "index"
+	},
+	// Values are compared by their type affinity.
+	FKTypes: func(from, to schema.Type) bool {
+		return TypeConversion(from, to) != schema.ConversionIncompatible &&
+			TypeConversion(to, from) != schema.ConversionIncompatible
+	},
+}
+
+// reserved holds the SQLite keywords that cannot be used as unquoted identifiers.
+var reserved = func() map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(`
+		ADD ALL ALTER AND AS AUTOINCREMENT BETWEEN CASE CHECK COLLATE COMMIT CONSTRAINT CREATE DEFAULT
+		DEFERRABLE DELETE DISTINCT DROP ELSE ESCAPE EXCEPT EXISTS FOREIGN FROM GROUP HAVING IN INDEX
+		INSERT INTERSECT INTO IS ISNULL JOIN LIMIT NOT NOTNULL NULL ON OR ORDER PRIMARY REFERENCES
+		SELECT SET TABLE THEN TO TRANSACTION UNION UNIQUE UPDATE USING VALUES WHEN WHERE
+	`) {
+		m[w] = true
+	}
+	return m
+}()
@@ -0,0 +1,530 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// TriggerWhen describes the WHEN clause of a trigger. If set, the trigger
+// statements are executed only for rows the condition is true for.
+// See: https://www.sqlite.org/lang_createtrigger.html
+type TriggerWhen struct {
+	schema.Attr
+	P string
+}
+
+// inspectViews queries and appends the views and triggers of the given
+// schema, according to the inspection mode. Both are stored in the
+// sqlite_master table and are inspected using a single query.
+func (i *inspect) inspectViews(ctx context.Context, s *schema.Schema, mode schema.InspectMode) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(viewsQuery, s.Name))
+	if err != nil {
+		return fmt.Errorf("sqlite: querying schema %q views and triggers: %w", s.Name, err)
+	}
+	defer rows.Close()
+	var triggers [][3]string
+	for rows.Next() {
+		var typ, name, table, stmt string
+		if err := rows.Scan(&typ, &name, &table, &stmt); err != nil {
+			return fmt.Errorf("sqlite: scanning views and triggers: %w", err)
+		}
+		switch {
+		case typ == "view" && mode.Is(schema.InspectViews):
+			v, err := parseView(stmt)
+			if err != nil {
+				return err
+			}
+			v.Name = name
+			s.AddViews(v)
+		case typ == "trigger" && mode.Is(schema.InspectTriggers):
+			triggers = append(triggers, [3]string{name, table, stmt})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// Triggers are linked to their tables (or views) after all views were inspected.
+	// Triggers of tables or views that were not inspected are skipped.
+	for _, tr := range triggers {
+		t, _, err := parseTrigger(tr[2])
+		if err != nil {
+			return err
+		}
+		t.Name = tr[0]
+		setTriggerOn(s, t, tr[1])
+	}
+	return nil
+}
+
+var (
+	// identExpr matches a quoted or an unquoted SQLite identifier.
+	identExpr = "(?:\"[^\"]+\"|`[^`]+`|\\[[^\\]]+\\]|[\\w$]+)"
+	// qualifiedExpr matches an optionally schema-qualified identifier.
+	qualifiedExpr = identExpr + `(?:\s*\.\s*` + identExpr + `)?`
+	reView        = regexp.MustCompile(`(?is)^CREATE\s+(?:TEMP(?:ORARY)?\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?` + qualifiedExpr + `\s*(\([^)]*\))?\s*AS\s+(.+?)\s*;?$`)
+	reTrigger     = regexp.MustCompile(`(?is)^CREATE\s+(?:TEMP(?:ORARY)?\s+)?TRIGGER\s+(?:IF\s+NOT\s+EXISTS\s+)?` + qualifiedExpr + `\s+(.+?)\s*;?$`)
+	reTriggerDef  = regexp.MustCompile(`(?is)^(?:(BEFORE|AFTER|INSTEAD\s+OF)\s+)?(DELETE|INSERT|UPDATE)(?:\s+OF\s+(.+?))?\s+ON\s+(?:` + identExpr + `\s*\.\s*)?(` + identExpr + `)(?:\s+FOR\s+EACH\s+ROW)?(?:\s+WHEN\s+(.+?))?\s+BEGIN\s+(.+?)\s*END$`)
+)
+
+// parseView parses the columns and the query of a view from its CREATE statement.
+func parseView(stmt string) (*schema.View, error) {
+	matches := reView.FindStringSubmatch(strings.TrimSpace(stmt))
+	if len(matches) != 3 {
+		return nil, fmt.Errorf("sqlite: unexpected view definition: %q", stmt)
+	}
+	v := &schema.View{Def: matches[2]}
+	if cols := matches[1]; cols != "" {
+		for _, c := range splitArgs(cols[1 : len(cols)-1]) {
+			v.Columns = append(v.Columns, &schema.Column{Name: unquoteIdent(c)})
+		}
+	}
+	return v, nil
+}
+
+// parseTrigger parses a trigger from its CREATE statement, and returns
+// it along with the name of the table (or view) it is defined on.
+func parseTrigger(stmt string) (*schema.Trigger, string, error) {
+	matches := reTrigger.FindStringSubmatch(strings.TrimSpace(stmt))
+	if len(matches) != 2 {
+		return nil, "", fmt.Errorf("sqlite: unexpected trigger definition: %q", stmt)
+	}
+	return parseTriggerDef(matches[1])
+}
+
+// parseTriggerDef parses the definition of a trigger that follows its name in the
+// CREATE TRIGGER statement. For example, "AFTER INSERT ON t BEGIN ... END". The
+// columns of UPDATE OF events hold only their names, until the trigger is linked
+// to its table.
+func parseTriggerDef(def string) (*schema.Trigger, string, error) {
+	matches := reTriggerDef.FindStringSubmatch(strings.TrimSpace(def))
+	if len(matches) != 7 {
+		return nil, "", fmt.Errorf("sqlite: unexpected trigger definition: %q", def)
+	}
+	t := &schema.Trigger{
+		// BEFORE is the default action time, and
+		// FOR EACH ROW is the only supported one.
+		ActionTime: schema.TriggerTimeBefore,
+		For:        schema.TriggerForRow,
+		Body:       matches[6],
+	}
+	if at := strings.ToUpper(strings.Join(strings.Fields(matches[1]), " ")); at != "" {
+		t.ActionTime = schema.TriggerTime(at)
+	}
+	e := schema.TriggerEvent{Name: strings.ToUpper(matches[2])}
+	if cols := matches[3]; cols != "" {
+		for _, c := range splitArgs(cols) {
+			e.Columns = append(e.Columns, &schema.Column{Name: unquoteIdent(strings.TrimSpace(c))})
+		}
+	}
+	t.Events = append(t.Events, e)
+	if w := matches[5]; w != "" {
+		t.Attrs = append(t.Attrs, &TriggerWhen{P: w})
+	}
+	return t, unquoteIdent(matches[4]), nil
+}
+
+// setTriggerOn links the trigger to the table or view with the given name, and
+// reports if it was found. The columns of UPDATE OF events are linked as well.
+func setTriggerOn(s *schema.Schema, t *schema.Trigger, name string) bool {
+	if v, ok := s.View(name); ok {
+		v.AddTriggers(t)
+		return true
+	}
+	tb, ok := s.Table(name)
+	if !ok {
+		return false
+	}
+	tb.AddTriggers(t)
+	for _, e := range t.Events {
+		for i, c := range e.Columns {
+			if c1, ok := tb.Column(c.Name); ok {
+				e.Columns[i] = c1
+			}
+		}
+	}
+	return true
+}
+
+// triggerOn returns the schema and the name of the table or view the trigger is defined on.
+func triggerOn(t *schema.Trigger) (*schema.Schema, string) {
+	switch {
+	case t.Table != nil:
+		return t.Table.Schema, t.Table.Name
+	case t.View != nil:
+		return t.View.Schema, t.View.Name
+	}
+	return nil, ""
+}
+
+// unquoteIdent removes the quotes that wrap the given identifier, if exist.
+func unquoteIdent(s string) string {
+	if len(s) > 1 && (s[0] == '"' && s[len(s)-1] == '"' || s[0] == '`' && s[len(s)-1] == '`' || s[0] == '[' && s[len(s)-1] == ']') {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// ViewChanged reports if the column names that are listed in the definition of the view were
+// changed. Columns are compared only if they were defined on both states. Note, SQLite does
+// not support altering views and triggers, and therefore, changes to their definitions are
+// planned by dropping and recreating them.
+func (*diff) ViewChanged(from, to *schema.View) bool {
+	return len(from.Columns) > 0 && len(to.Columns) > 0 && !slices.EqualFunc(from.Columns, to.Columns, func(c1, c2 *schema.Column) bool {
+		return c1.Name == c2.Name
+	})
+}
+
+// TriggerChanged reports if the WHEN clause of the trigger was changed.
+func (*diff) TriggerChanged(from, to *schema.Trigger) bool {
+	var w1, w2 TriggerWhen
+	sqlx.Has(from.Attrs, &w1)
+	sqlx.Has(to.Attrs, &w2)
+	return normalizePredicate(w1.P) != normalizePredicate(w2.P)
+}
+
+// hasViewsOrTriggers reports if the schema has views or triggers.
+func hasViewsOrTriggers(s *schema.Schema) bool {
+	return len(s.Views) > 0 || slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		return len(t.Triggers) > 0
+	})
+}
+
+// splitObjects splits the view and trigger changes from the rest of the changes. Views and
+// triggers are dropped before the table changes, and created after them, as SQLite validates
+// the views and triggers of the schema when columns are dropped or tables are renamed. Also,
+// views are created before triggers, as INSTEAD OF triggers are defined on views.
+func (s *state) splitObjects(changes []schema.Change) (drops, others, adds []schema.Change) {
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddView:
+			adds = append(adds, c)
+		case *schema.AddTrigger:
+			adds = append(adds, c)
+			s.triggers = append(s.triggers, c.T.Name)
+		case *schema.DropView, *schema.DropTrigger:
+			drops = append(drops, c)
+		case *schema.ModifyView:
+			drops, adds = append(drops, c), append(adds, c)
+		case *schema.ModifyTrigger:
+			drops, adds = append(drops, c), append(adds, c)
+			s.triggers = append(s.triggers, c.To.Name)
+		default:
+			others = append(others, c)
+		}
+	}
+	slices.SortStableFunc(drops, func(c1, c2 schema.Change) int {
+		return objectOrder(c2) - objectOrder(c1)
+	})
+	slices.SortStableFunc(adds, func(c1, c2 schema.Change) int {
+		return objectOrder(c1) - objectOrder(c2)
+	})
+	return drops, others, adds
+}
+
+// objectOrder returns the creation order of the object of the given change.
+func objectOrder(c schema.Change) int {
+	switch c.(type) {
+	case *schema.AddTrigger, *schema.DropTrigger, *schema.ModifyTrigger:
+		return 1
+	}
+	return 0
+}
+
+// dropObject plans the removal of a view or trigger, or the first
+// part of its modification, that is, dropping its current definition.
+func (s *state) dropObject(c schema.Change) error {
+	switch c := c.(type) {
+	case *schema.DropView:
+		return s.dropView(c, c.V)
+	case *schema.ModifyView:
+		return s.dropView(c, c.From)
+	case *schema.DropTrigger:
+		return s.dropTrigger(c, c.T)
+	case *schema.ModifyTrigger:
+		return s.dropTrigger(c, c.From)
+	}
+	return fmt.Errorf("unsupported change %T", c)
+}
+
+// addObject plans the creation of a view or trigger, or the second
+// part of its modification, that is, creating its new definition.
+func (s *state) addObject(c schema.Change) error {
+	switch c := c.(type) {
+	case *schema.AddView:
+		return s.addView(c, c.V)
+	case *schema.ModifyView:
+		return s.addView(c, c.To)
+	case *schema.AddTrigger:
+		return s.addTrigger(c, c.T)
+	case *schema.ModifyTrigger:
+		return s.addTrigger(c, c.To)
+	}
+	return fmt.Errorf("unsupported change %T", c)
+}
+
+func (s *state) addView(c schema.Change, v *schema.View) error {
+	create, drop := s.createDropView(v)
+	s.append(&migrate.Change{
+		Cmd:     create,
+		Source:  c,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create %q view", v.Name),
+	})
+	return nil
+}
+
+func (s *state) dropView(c schema.Change, v *schema.View) error {
+	create, drop := s.createDropView(v)
+	s.append(&migrate.Change{
+		Cmd:     drop,
+		Source:  c,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop %q view", v.Name),
+	})
+	return nil
+}
+
+func (s *state) addTrigger(c schema.Change, t *schema.Trigger) error {
+	create, drop, err := s.createDropTrigger(t)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Cmd:     create,
+		Source:  c,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create %q trigger", t.Name),
+	})
+	return nil
+}
+
+func (s *state) dropTrigger(c schema.Change, t *schema.Trigger) error {
+	create, drop, err := s.createDropTrigger(t)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Cmd:     drop,
+		Source:  c,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop %q trigger", t.Name),
+	})
+	return nil
+}
+
+// recreateTriggers recreates the triggers of a rebuilt table, as they are dropped
+// along with the table. Triggers that are created by the plan are skipped.
+func (s *state) recreateTriggers(modify *schema.ModifyTable) error {
+	for _, t := range modify.T.Triggers {
+		if slices.Contains(s.triggers, t.Name) {
+			continue
+		}
+		create, drop, err := s.createDropTrigger(t)
+		if err != nil {
+			return err
+		}
+		s.append(&migrate.Change{
+			Cmd:     create,
+			Source:  modify,
+			Reverse: drop,
+			Comment: fmt.Sprintf("recreate %q trigger of the rebuilt %q table", t.Name, modify.T.Name),
+		})
+	}
+	return nil
+}
+
+func (s *state) createDropView(v *schema.View) (string, string) {
+	b := s.Build("CREATE VIEW").SchemaResource(v.Schema, v.Name)
+	if len(v.Columns) > 0 {
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(v.Columns, func(i int, b *sqlx.Builder) {
+				b.Ident(v.Columns[i].Name)
+			})
+		})
+	}
+	b.P("AS", strings.TrimSuffix(strings.TrimSpace(v.Def), ";"))
+	return b.String(), s.Build("DROP VIEW").SchemaResource(v.Schema, v.Name).String()
+}
+
+func (s *state) createDropTrigger(t *schema.Trigger) (string, string, error) {
+	ns, _ := triggerOn(t)
+	create := s.Build("CREATE TRIGGER").SchemaResource(ns, t.Name)
+	if err := triggerDef(create, t); err != nil {
+		return "", "", err
+	}
+	return create.String(), s.Build("DROP TRIGGER").SchemaResource(ns, t.Name).String(), nil
+}
+
+// triggerDef writes the definition of the trigger that follows its name in the CREATE TRIGGER
+// statement. Note, the table (or view) of the trigger cannot be qualified with its schema name.
+func triggerDef(b *sqlx.Builder, t *schema.Trigger) error {
+	_, on := triggerOn(t)
+	switch {
+	case on == "":
+		return fmt.Errorf("sqlite: missing table or view for trigger %q", t.Name)
+	case len(t.Events) != 1:
+		return fmt.Errorf("sqlite: trigger %q must have exactly one event, got %d", t.Name, len(t.Events))
+	case t.For != "" && t.For != schema.TriggerForRow:
+		return fmt.Errorf("sqlite: unsupported FOR EACH %s in trigger %q", t.For, t.Name)
+	}
+	if t.ActionTime != "" {
+		b.P(string(t.ActionTime))
+	}
+	e := t.Events[0]
+	b.P(e.Name)
+	if len(e.Columns) > 0 {
+		b.P("OF").MapComma(e.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(e.Columns[i].Name)
+		})
+	}
+	b.P("ON").Ident(on)
+	if w := (TriggerWhen{}); sqlx.Has(t.Attrs, &w) && w.P != "" {
+		b.P("WHEN", w.P)
+	}
+	body := strings.TrimSpace(t.Body)
+	if !strings.HasSuffix(body, ";") {
+		body += ";"
+	}
+	b.P("BEGIN", body, "END")
+	return nil
+}
+
+type (
+	// view holds a specification for a view.
+	view struct {
+		Name      string         `spec:",name"`
+		Qualifier string         `spec:",qualifier"`
+		Schema    *schemahcl.Ref `spec:"schema"`
+		As        string         `spec:"as"`
+		// The "columns" attribute is
+		// conditionally added to the view.
+		schemahcl.DefaultExtension
+	}
+	// trigger holds a specification for a trigger.
+	trigger struct {
+		Name      string         `spec:",name"`
+		Qualifier string         `spec:",qualifier"`
+		Schema    *schemahcl.Ref `spec:"schema"`
+		As        string         `spec:"as"`
+	}
+)
+
+// Label returns the defaults label used for the view resource.
+func (v *view) Label() string { return v.Name }
+
+// QualifierLabel returns the qualifier label used for the view resource, if any.
+func (v *view) QualifierLabel() string { return v.Qualifier }
+
+// SetQualifier sets the qualifier label used for the view resource.
+func (v *view) SetQualifier(q string) { v.Qualifier = q }
+
+// SchemaRef returns the schema reference for the view.
+func (v *view) SchemaRef() *schemahcl.Ref { return v.Schema }
+
+// Label returns the defaults label used for the trigger resource.
+func (t *trigger) Label() string { return t.Name }
+
+// QualifierLabel returns the qualifier label used for the trigger resource, if any.
+func (t *trigger) QualifierLabel() string { return t.Qualifier }
+
+// SetQualifier sets the qualifier label used for the trigger resource.
+func (t *trigger) SetQualifier(q string) { t.Qualifier = q }
+
+// SchemaRef returns the schema reference for the trigger.
+func (t *trigger) SchemaRef() *schemahcl.Ref { return t.Schema }
+
+// convertViews converts the view and trigger specs into schema views and triggers.
+func convertViews(d *doc, r *schema.Realm) error {
+	for _, spec := range d.Views {
+		s, err := specSchema(r, spec.Schema, "view", spec.Name)
+		if err != nil {
+			return err
+		}
+		if spec.As == "" {
+			return fmt.Errorf("missing definition (as) for view %q", spec.Name)
+		}
+		v := schema.NewView(spec.Name, spec.As)
+		if a, ok := spec.Attr("columns"); ok {
+			names, err := a.Strings()
+			if err != nil {
+				return err
+			}
+			for _, n := range names {
+				v.AddColumns(&schema.Column{Name: n})
+			}
+		}
+		s.AddViews(v)
+	}
+	for _, spec := range d.Triggers {
+		s, err := specSchema(r, spec.Schema, "trigger", spec.Name)
+		if err != nil {
+			return err
+		}
+		t, on, err := parseTriggerDef(spec.As)
+		if err != nil {
+			return fmt.Errorf("missing table (ON clause) in the definition (as) of trigger %q", spec.Name)
+		}
+		t.Name = spec.Name
+		if !setTriggerOn(s, t, on) {
+			return fmt.Errorf("table or view %q of trigger %q was not found in schema %q", on, spec.Name, s.Name)
+		}
+	}
+	return nil
+}
+
+// specSchema returns the schema referenced by the given spec.
+func specSchema(r *schema.Realm, ref *schemahcl.Ref, typ, name string) (*schema.Schema, error) {
+	ns, err := specutil.SchemaName(ref)
+	if err != nil {
+		return nil, fmt.Errorf("extract schema name from %s %q reference: %w", typ, name, err)
+	}
+	s, ok := r.Schema(ns)
+	if !ok {
+		return nil, fmt.Errorf("schema %q defined on %s %q was not found in realm", ns, typ, name)
+	}
+	return s, nil
+}
+
+// viewSpecs appends the view and trigger specs of the schema to the document.
+func viewSpecs(d *doc, s *schema.Schema) error {
+	var triggers []*schema.Trigger
+	for _, t := range s.Tables {
+		triggers = append(triggers, t.Triggers...)
+	}
+	for _, v := range s.Views {
+		spec := &view{Name: v.Name, Schema: specutil.SchemaRef(s.Name), As: v.Def}
+		if len(v.Columns) > 0 {
+			names := make([]string, len(v.Columns))
+			for i, c := range v.Columns {
+				names[i] = c.Name
+			}
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringsAttr("columns", names...))
+		}
+		d.Views = append(d.Views, spec)
+		triggers = append(triggers, v.Triggers...)
+	}
+	for _, t := range triggers {
+		b := (*Driver).StmtBuilder(nil, migrate.PlanOptions{})
+		if err := triggerDef(b, t); err != nil {
+			return err
+		}
+		d.Triggers = append(d.Triggers, &trigger{Name: t.Name, Schema: specutil.SchemaRef(s.Name), As: b.String()})
+	}
+	return nil
+}
+
+// Query to list the views and triggers of a schema.
+const viewsQuery = "SELECT `type`, `name`, `tbl_name`, `sql` FROM `%s`.sqlite_master WHERE `type` IN ('view', 'trigger') AND `sql` IS NOT NULL ORDER BY `rowid`"
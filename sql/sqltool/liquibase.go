@@ -0,0 +1,259 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqltool
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LiquibaseFile wraps migrate.LocalFile and holds the statements of a Liquibase changelog
+// written in XML or YAML. Only the "sql" changes of the changelog are supported.
+type LiquibaseFile struct {
+	*migrate.LocalFile
+	stmts []*migrate.Stmt
+}
+
+// Files implements Scanner.Files. It looks for all Liquibase formatted SQL files and XML or YAML changelogs,
+// and orders them by filename. Changelogs without change sets (e.g., a root changelog that only includes
+// other changelogs) are skipped.
+func (d *LiquibaseDir) Files() ([]migrate.File, error) {
+	var names []string
+	for _, p := range []string{"*.sql", "*.xml", "*.yaml", "*.yml"} {
+		m, err := fs.Glob(d, p)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, m...)
+	}
+	// Sort files lexicographically.
+	sort.Strings(names)
+	ret := make([]migrate.File, 0, len(names))
+	for _, n := range names {
+		b, err := fs.ReadFile(d, n)
+		if err != nil {
+			return nil, fmt.Errorf("sql/migrate: read file %q: %w", n, err)
+		}
+		if filepath.Ext(n) == ".sql" {
+			ret = append(ret, migrate.NewLocalFile(n, b))
+			continue
+		}
+		sets, err := liquibaseChangeSets(n, b)
+		if err != nil {
+			return nil, err
+		}
+		if len(sets) == 0 {
+			continue
+		}
+		f := &LiquibaseFile{LocalFile: migrate.NewLocalFile(n, b)}
+		for _, s := range sets {
+			stmts, err := s.stmts()
+			if err != nil {
+				return nil, fmt.Errorf("sql/sqltool: liquibase: %s: changeset %q: %w", n, s.ID, err)
+			}
+			f.stmts = append(f.stmts, stmts...)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+// Checksum implements Dir.Checksum. By default, it calls Files() and creates a checksum from them.
+func (d *LiquibaseDir) Checksum() (migrate.HashFile, error) {
+	files, err := d.Files()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewHashFile(files)
+}
+
+// Desc implements File.Desc.
+func (f *LiquibaseFile) Desc() string {
+	parts := strings.SplitN(strings.TrimSuffix(f.Name(), filepath.Ext(f.Name())), "_", 2)
+	if len(parts) == 1 {
+		return ""
+	}
+	return parts[1]
+}
+
+// Version implements File.Version.
+func (f *LiquibaseFile) Version() string {
+	return strings.SplitN(strings.TrimSuffix(f.Name(), filepath.Ext(f.Name())), "_", 2)[0]
+}
+
+// StmtDecls returns the statements of the "sql" changes defined in the changelog.
+func (f *LiquibaseFile) StmtDecls() ([]*migrate.Stmt, error) {
+	return f.stmts, nil
+}
+
+// Stmts returns the statements of the "sql" changes defined in the changelog.
+func (f *LiquibaseFile) Stmts() ([]string, error) {
+	stmts := make([]string, len(f.stmts))
+	for i := range f.stmts {
+		stmts[i] = f.stmts[i].Text
+	}
+	return stmts, nil
+}
+
+type (
+	// liquibaseChangeSet is the format-agnostic representation of a Liquibase change set.
+	liquibaseChangeSet struct {
+		ID, Author string
+		Changes    []*liquibaseChange
+	}
+	// liquibaseChange is a change of a Liquibase change set. Only the
+	// fields of the "sql" change are populated, and used, by Atlas.
+	liquibaseChange struct {
+		Type         string // e.g., sql, createTable.
+		SQL          string
+		Split        bool
+		EndDelimiter string
+	}
+)
+
+// liquibaseSkipped lists the elements of a change set that are not changes
+// and do not affect the schema. Hence, they are ignored when importing.
+var liquibaseSkipped = []string{"comment", "preConditions", "rollback", "validCheckSum"}
+
+// liquibaseChangeSets parses the change sets of the given XML or YAML changelog.
+func liquibaseChangeSets(name string, b []byte) ([]*liquibaseChangeSet, error) {
+	var (
+		sets []*liquibaseChangeSet
+		err  error
+	)
+	switch ext := filepath.Ext(name); ext {
+	case ".xml":
+		sets, err = liquibaseXML(b)
+	case ".yaml", ".yml":
+		sets, err = liquibaseYAML(b)
+	default:
+		err = fmt.Errorf("unexpected changelog extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sql/sqltool: liquibase: %s: %w", name, err)
+	}
+	return sets, nil
+}
+
+func liquibaseXML(b []byte) ([]*liquibaseChangeSet, error) {
+	var doc struct {
+		ChangeSets []struct {
+			ID      string `xml:"id,attr"`
+			Author  string `xml:"author,attr"`
+			Changes []struct {
+				XMLName      xml.Name
+				Text         string `xml:",chardata"`
+				Split        string `xml:"splitStatements,attr"`
+				EndDelimiter string `xml:"endDelimiter,attr"`
+			} `xml:",any"`
+		} `xml:"changeSet"`
+	}
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	sets := make([]*liquibaseChangeSet, len(doc.ChangeSets))
+	for i, s := range doc.ChangeSets {
+		sets[i] = &liquibaseChangeSet{ID: s.ID, Author: s.Author}
+		for _, c := range s.Changes {
+			split := true
+			if c.Split != "" {
+				v, err := strconv.ParseBool(c.Split)
+				if err != nil {
+					return nil, fmt.Errorf("changeset %q: invalid splitStatements value %q", s.ID, c.Split)
+				}
+				split = v
+			}
+			sets[i].Changes = append(sets[i].Changes, &liquibaseChange{
+				Type:         c.XMLName.Local,
+				SQL:          c.Text,
+				Split:        split,
+				EndDelimiter: c.EndDelimiter,
+			})
+		}
+	}
+	return sets, nil
+}
+
+func liquibaseYAML(b []byte) ([]*liquibaseChangeSet, error) {
+	var doc struct {
+		ChangeLog []struct {
+			ChangeSet *struct {
+				ID      string `yaml:"id"`
+				Author  string `yaml:"author"`
+				Changes []map[string]struct {
+					SQL          string `yaml:"sql"`
+					Split        *bool  `yaml:"splitStatements"`
+					EndDelimiter string `yaml:"endDelimiter"`
+				} `yaml:"changes"`
+			} `yaml:"changeSet"`
+		} `yaml:"databaseChangeLog"`
+	}
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	var sets []*liquibaseChangeSet
+	for _, e := range doc.ChangeLog {
+		if e.ChangeSet == nil {
+			continue
+		}
+		s := &liquibaseChangeSet{ID: e.ChangeSet.ID, Author: e.ChangeSet.Author}
+		for _, m := range e.ChangeSet.Changes {
+			for t, c := range m {
+				s.Changes = append(s.Changes, &liquibaseChange{
+					Type:         t,
+					SQL:          c.SQL,
+					Split:        c.Split == nil || *c.Split,
+					EndDelimiter: c.EndDelimiter,
+				})
+			}
+		}
+		sets = append(sets, s)
+	}
+	return sets, nil
+}
+
+// stmts returns the statements of the change set. The first statement is
+// annotated with the change set identifier, similar to formatted SQL files.
+func (s *liquibaseChangeSet) stmts() ([]*migrate.Stmt, error) {
+	var stmts []*migrate.Stmt
+	for _, c := range s.Changes {
+		switch {
+		case c.Type == "sql":
+		case slices.Contains(liquibaseSkipped, c.Type):
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported change type %q", c.Type)
+		}
+		text := strings.TrimSpace(c.SQL)
+		switch {
+		case text == "":
+		case !c.Split:
+			stmts = append(stmts, &migrate.Stmt{Text: text})
+		default:
+			if d := c.EndDelimiter; d != "" && d != ";" {
+				text = "-- atlas:delimiter " + d + "\n" + text
+			}
+			ss, err := migrate.Stmts(text)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, ss...)
+		}
+	}
+	if len(stmts) > 0 {
+		stmts[0].Comments = append([]string{fmt.Sprintf("--changeset %s:%s", s.Author, s.ID)}, stmts[0].Comments...)
+	}
+	return stmts, nil
+}
@@ -98,11 +98,20 @@ func (d *GolangMigrateDir) Path() string {
 }
 
 // Files implements Scanner.Files. It looks for all files with up.sql suffix and orders them by filename.
+// An error is returned in case the directory contains two up files with the same version, or a down file
+// without its up pair.
 func (d *GolangMigrateDir) Files() ([]migrate.File, error) {
 	names, err := fs.Glob(d, "*.up.sql")
 	if err != nil {
 		return nil, err
 	}
+	downs, err := fs.Glob(d, "*.down.sql")
+	if err != nil {
+		return nil, err
+	}
+	if err := golangMigratePairs(names, downs); err != nil {
+		return nil, err
+	}
 	// Sort files lexicographically.
 	sort.Slice(names, func(i, j int) bool {
 		return names[i] < names[j]
@@ -140,6 +149,30 @@ func (f *GolangMigrateFile) Desc() string {
 	return strings.TrimSuffix(f.LocalFile.Desc(), ".up")
 }
 
+// golangMigratePairs validates the given up and down file names form
+// unique pairs of versions, as expected by golang-migrate/migrate.
+func golangMigratePairs(ups, downs []string) error {
+	// Titles are optional. e.g., "1_init.up.sql" or "1.up.sql".
+	version := func(n string) string {
+		n = strings.TrimSuffix(strings.TrimSuffix(n, ".up.sql"), ".down.sql")
+		return strings.SplitN(n, "_", 2)[0]
+	}
+	vs := make(map[string]string, len(ups))
+	for _, n := range ups {
+		v := version(n)
+		if prev, ok := vs[v]; ok {
+			return fmt.Errorf("sql/sqltool: golang-migrate: duplicate up migrations for version %q: %q and %q", v, prev, n)
+		}
+		vs[v] = n
+	}
+	for _, n := range downs {
+		if _, ok := vs[version(n)]; !ok {
+			return fmt.Errorf("sql/sqltool: golang-migrate: down migration %q has no matching up migration", n)
+		}
+	}
+	return nil
+}
+
 type (
 	// GooseDir wraps migrate.LocalDir and provides a migrate.Scanner implementation able to understand files
 	// generated by the GooseFormatter for migration directory replaying.
@@ -455,6 +488,36 @@ func SetRepeatableVersion(ff []migrate.File) {
 	}
 }
 
+// ReplaceFlywayPlaceholders iterates over the migration files and replaces the Flyway placeholders they
+// reference (e.g., ${name}) with the given values. Besides the user-defined placeholders, the built-in
+// ${flyway:filename} placeholder is supported. Like Flyway, an error is returned in case a file references
+// a placeholder that has no value.
+func ReplaceFlywayPlaceholders(ff []migrate.File, values map[string]string) error {
+	for i, f := range ff {
+		var (
+			err error
+			b   = reFlywayPlaceholder.ReplaceAllFunc(f.Bytes(), func(m []byte) []byte {
+				name := string(m[2 : len(m)-1])
+				if v, ok := values[name]; ok {
+					return []byte(v)
+				}
+				if name == "flyway:filename" {
+					return []byte(filepath.Base(f.Name()))
+				}
+				if err == nil {
+					err = fmt.Errorf("sql/sqltool: flyway: %s: no value provided for placeholder %q", f.Name(), name)
+				}
+				return m
+			})
+		)
+		if err != nil {
+			return err
+		}
+		ff[i] = &FlywayFile{migrate.NewLocalFile(f.Name(), b)}
+	}
+	return nil
+}
+
 // LiquibaseDir wraps migrate.LocalDir and provides a migrate.Scanner implementation able to understand files
 // generated by the LiquibaseFormatter for migration directory replaying.
 type LiquibaseDir struct{ *migrate.LocalDir }
@@ -480,6 +543,8 @@ const (
 var (
 	reGoosePragma  = regexp.MustCompile(regexp.QuoteMeta(goosePragma) + " Up|Down|StatementBegin|StatementEnd")
 	reDBMatePragma = regexp.MustCompile(dbmatePragma + "up|down")
+	// reFlywayPlaceholder matches Flyway placeholders. e.g., ${name} or ${flyway:filename}.
+	reFlywayPlaceholder = regexp.MustCompile(`\$\{[\w.:-]+}`)
 )
 
 // flywayFiles retrieves flyway migration files by calls to add(). It will only keep the latest baseline and ignore
@@ -226,8 +226,8 @@ func TestScanners(t *testing.T) {
 				require.NoError(t, err)
 				return d
 			}(),
-			versions:     []string{"1", "2"},
-			descriptions: []string{"initial", "second_migration"},
+			versions:     []string{"1", "2", "3", "4"},
+			descriptions: []string{"initial", "second_migration", "third_migration", "fourth_migration"},
 			stmts: [][]string{
 				{
 					"CREATE TABLE post\n(\n    id    int NOT NULL,\n    title text,\n    body  text,\n    PRIMARY KEY (id)\n);",
@@ -235,6 +235,12 @@ func TestScanners(t *testing.T) {
 					"INSERT INTO post (title) VALUES (\n'This is\nmy multiline\n\nvalue');",
 				},
 				{"CREATE TABLE tbl_2 (col INT);"},
+				{
+					"CREATE TABLE tbl_3 (col INT);",
+					"ALTER TABLE tbl_3 ADD col_2 INT;",
+					"INSERT INTO tbl_3 (col) VALUES (1); INSERT INTO tbl_3 (col) VALUES (2);",
+				},
+				{"CREATE TABLE tbl_4 (col INT);"},
 			},
 		},
 		{
@@ -274,6 +280,85 @@ func TestScanners(t *testing.T) {
 	}
 }
 
+func TestLiquibaseDir_Files(t *testing.T) {
+	d, err := sqltool.NewLiquibaseDir("testdata/liquibase")
+	require.NoError(t, err)
+	files, err := d.Files()
+	require.NoError(t, err)
+	stmts, err := files[2].StmtDecls()
+	require.NoError(t, err)
+	require.Len(t, stmts, 3)
+	require.Equal(t, []string{"--changeset atlas:3-1"}, stmts[0].Comments)
+	require.Empty(t, stmts[1].Comments)
+	require.Equal(t, []string{"--changeset atlas:3-2"}, stmts[2].Comments)
+
+	d, err = sqltool.NewLiquibaseDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, d.WriteFile("1_initial.yaml", []byte(`
+databaseChangeLog:
+  - changeSet:
+      id: 1
+      author: atlas
+      changes:
+        - createTable:
+            tableName: t
+`)))
+	_, err = d.Files()
+	require.EqualError(t, err, `sql/sqltool: liquibase: 1_initial.yaml: changeset "1": unsupported change type "createTable"`)
+}
+
+func TestGolangMigrateDir_Files(t *testing.T) {
+	d := &sqltool.GolangMigrateDir{FS: fstest.MapFS{
+		"1_initial.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE t1(c int);")},
+		"1_initial.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE t1;")},
+		"2_second.down.sql":  &fstest.MapFile{Data: []byte("DROP TABLE t2;")},
+	}}
+	_, err := d.Files()
+	require.EqualError(t, err, `sql/sqltool: golang-migrate: down migration "2_second.down.sql" has no matching up migration`)
+
+	d = &sqltool.GolangMigrateDir{FS: fstest.MapFS{
+		"1_initial.up.sql": &fstest.MapFile{Data: []byte("CREATE TABLE t1(c int);")},
+		"1_other.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE t2(c int);")},
+	}}
+	_, err = d.Files()
+	require.EqualError(t, err, `sql/sqltool: golang-migrate: duplicate up migrations for version "1": "1_initial.up.sql" and "1_other.up.sql"`)
+
+	// Files without titles.
+	d = &sqltool.GolangMigrateDir{FS: fstest.MapFS{
+		"1.up.sql":          &fstest.MapFile{Data: []byte("CREATE TABLE t1(c int);")},
+		"1.down.sql":        &fstest.MapFile{Data: []byte("DROP TABLE t1;")},
+		"2_second.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE t2(c int);")},
+		"2_second.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE t2;")},
+	}}
+	files, err := d.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "1.up.sql", files[0].Name())
+	require.Equal(t, "2_second.up.sql", files[1].Name())
+
+	d = &sqltool.GolangMigrateDir{FS: fstest.MapFS{
+		"1.up.sql":   &fstest.MapFile{Data: []byte("CREATE TABLE t1(c int);")},
+		"2.down.sql": &fstest.MapFile{Data: []byte("DROP TABLE t2;")},
+	}}
+	_, err = d.Files()
+	require.EqualError(t, err, `sql/sqltool: golang-migrate: down migration "2.down.sql" has no matching up migration`)
+}
+
+func TestReplaceFlywayPlaceholders(t *testing.T) {
+	d := &sqltool.FlywayDir{FS: fstest.MapFS{
+		"V1__initial.sql": &fstest.MapFile{Data: []byte("CREATE TABLE ${schema}.t1(c int);\n-- ${flyway:filename}\n")},
+		"V2__second.sql":  &fstest.MapFile{Data: []byte("CREATE TABLE t2(c int);")},
+	}}
+	files, err := d.Files()
+	require.NoError(t, err)
+	require.EqualError(t, sqltool.ReplaceFlywayPlaceholders(files, nil), `sql/sqltool: flyway: V1__initial.sql: no value provided for placeholder "schema"`)
+
+	require.NoError(t, sqltool.ReplaceFlywayPlaceholders(files, map[string]string{"schema": "public"}))
+	require.Equal(t, "CREATE TABLE public.t1(c int);\n-- V1__initial.sql\n", string(files[0].Bytes()))
+	require.Equal(t, "1", files[0].Version())
+	require.Equal(t, "CREATE TABLE t2(c int);", string(files[1].Bytes()))
+}
+
 func TestChecksum(t *testing.T) {
 	for _, tt := range []struct {
 		name  string
@@ -391,6 +476,8 @@ func TestChecksum(t *testing.T) {
 			files: []string{
 				"1_initial.sql",
 				"2_second_migration.sql",
+				"3_third_migration.xml",
+				"4_fourth_migration.yaml",
 			},
 		},
 		{
@@ -0,0 +1,149 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// CompositeType describes the structural types of Trino: array(T), map(K, V) and row(...).
+	// The type definition is kept as-is, as reported by the database.
+	CompositeType struct {
+		schema.Type
+		T string
+	}
+
+	// Property describes a table property of the catalog connector, defined in
+	// the WITH clause of CREATE TABLE. For example, "format" or "partitioning"
+	// of Iceberg tables. The value is a raw SQL expression, such as 'PARQUET'
+	// or ARRAY['day(ts)'].
+	Property struct {
+		schema.Attr
+		K, V string
+	}
+)
+
+// FormatType converts schema type to its column form in the database.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = strings.ToLower(t.T)
+	case *schema.IntegerType:
+		f = strings.ToLower(t.T)
+	case *schema.FloatType:
+		f = strings.ToLower(t.T)
+	case *schema.DecimalType:
+		f = strings.ToLower(t.T)
+		if t.Precision > 0 {
+			f = fmt.Sprintf("%s(%d,%d)", f, t.Precision, t.Scale)
+		}
+	case *schema.StringType:
+		f = strings.ToLower(t.T)
+		if t.Size > 0 {
+			f = fmt.Sprintf("%s(%d)", f, t.Size)
+		}
+	case *schema.BinaryType:
+		f = strings.ToLower(t.T)
+	case *schema.JSONType:
+		f = strings.ToLower(t.T)
+	case *schema.UUIDType:
+		f = strings.ToLower(t.T)
+	case *schema.TimeType:
+		f = strings.ToLower(t.T)
+		// The precision comes before the time zone. e.g., timestamp(6) with time zone.
+		if t.Precision != nil && f != TypeDate {
+			name, tz, _ := strings.Cut(f, " ")
+			f = fmt.Sprintf("%s(%d)", name, *t.Precision)
+			if tz != "" {
+				f += " " + tz
+			}
+		}
+	case *CompositeType:
+		f = t.T
+	case *schema.UnsupportedType:
+		f = t.T
+	default:
+		return "", fmt.Errorf("trino: invalid schema type: %T", t)
+	}
+	return f, nil
+}
+
+// reTime matches the time and timestamp types, with an optional precision and time zone.
+var reTime = regexp.MustCompile(`^(time|timestamp)(?:\s*\(\s*(\d+)\s*\))?(\s+with(?:out)?\s+time\s+zone)?$`)
+
+// ParseType returns the schema.Type value represented by the given raw type.
+func ParseType(raw string) (schema.Type, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	name, args := s, ""
+	if i := strings.IndexByte(s, '('); i > 0 && strings.HasSuffix(s, ")") {
+		name, args = strings.TrimSpace(s[:i]), s[i+1:len(s)-1]
+	}
+	switch name {
+	case TypeBoolean:
+		return &schema.BoolType{T: name}, nil
+	case TypeTinyInt, TypeSmallInt, TypeInteger, "int", TypeBigInt:
+		return &schema.IntegerType{T: name}, nil
+	case TypeReal, TypeDouble, "double precision":
+		return &schema.FloatType{T: name}, nil
+	case TypeDecimal:
+		t := &schema.DecimalType{T: name}
+		if args != "" {
+			p, sc, _ := strings.Cut(args, ",")
+			v, err := strconv.Atoi(strings.TrimSpace(p))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse precision %q: %w", p, err)
+			}
+			t.Precision = v
+			if sc != "" {
+				if t.Scale, err = strconv.Atoi(strings.TrimSpace(sc)); err != nil {
+					return nil, fmt.Errorf("trino: parse scale %q: %w", sc, err)
+				}
+			}
+		}
+		return t, nil
+	case TypeVarchar, TypeChar:
+		t := &schema.StringType{T: name}
+		if args != "" {
+			v, err := strconv.Atoi(strings.TrimSpace(args))
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse size %q: %w", args, err)
+			}
+			t.Size = v
+		}
+		return t, nil
+	case TypeVarbinary:
+		return &schema.BinaryType{T: name}, nil
+	case TypeJSON:
+		return &schema.JSONType{T: name}, nil
+	case TypeUUID:
+		return &schema.UUIDType{T: name}, nil
+	case TypeDate:
+		return &schema.TimeType{T: name}, nil
+	case TypeArray, TypeMap, TypeRow:
+		return &CompositeType{T: s}, nil
+	}
+	if m := reTime.FindStringSubmatch(s); m != nil {
+		t := &schema.TimeType{T: m[1]}
+		if tz := strings.Join(strings.Fields(m[3]), " "); tz == "with time zone" {
+			t.T += " " + tz
+		}
+		if m[2] != "" {
+			p, err := strconv.Atoi(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("trino: parse precision %q: %w", m[2], err)
+			}
+			t.Precision = &p
+		}
+		return t, nil
+	}
+	return &schema.UnsupportedType{T: s}, nil
+}
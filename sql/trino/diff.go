@@ -0,0 +1,179 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDiff provides basic diffing capabilities for Trino dialects.
+// Note, it is recommended to call Open, create a new Driver and use its
+// Differ when a database connection is available.
+var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{}}
+
+// A diff provides a Trino implementation for sqlx.DiffDriver.
+type diff struct{}
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	return nil
+}
+
+// RealmObjectDiff returns a changeset for migrating realm (database) objects
+// from one state to the other. For example, adding extensions or users.
+func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+// Table properties are compared only if they are defined in the desired state, as connectors
+// report the properties they set by default (e.g., the location of Iceberg tables).
+func (*diff) TableAttrDiff(from, to *schema.Table, _ *schema.DiffOptions) ([]schema.Change, error) {
+	var changes []schema.Change
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	for _, p2 := range properties(to.Attrs) {
+		switch p1, ok := property(from.Attrs, p2.K); {
+		case !ok:
+			changes = append(changes, &schema.AddAttr{A: p2})
+		case normalizeValue(p1.V) != normalizeValue(p2.V):
+			changes = append(changes, &schema.ModifyAttr{From: p1, To: p2})
+		}
+	}
+	return changes, nil
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+// Column defaults are ignored, as they are not supported by most of the connectors.
+func (*diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.DiffOptions) (schema.Change, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	if from.Type.Null != to.Type.Null {
+		change |= schema.ChangeNull
+	}
+	changed, err := typeChanged(from, to)
+	if err != nil {
+		return sqlx.NoChange, err
+	}
+	if changed {
+		change |= schema.ChangeType
+	}
+	if change.Is(schema.NoChange) {
+		return sqlx.NoChange, nil
+	}
+	return &schema.ModifyColumn{Change: change, From: from, To: to}, nil
+}
+
+// typeChanged reports if the column type was changed.
+func typeChanged(from, to *schema.Column) (bool, error) {
+	fromT, toT := from.Type.Type, to.Type.Type
+	if fromT == nil || toT == nil {
+		return false, fmt.Errorf("trino: missing type information for column %q", from.Name)
+	}
+	f1, err := FormatType(fromT)
+	if err != nil {
+		return false, err
+	}
+	f2, err := FormatType(toT)
+	if err != nil {
+		return false, err
+	}
+	return normalizeType(f1) != normalizeType(f2), nil
+}
+
+// normalizeType normalizes the formatted type for comparison. Trino reports
+// integer as "integer" and the precision of timestamps, if they were omitted.
+func normalizeType(t string) string {
+	t = strings.Join(strings.Fields(strings.ToLower(t)), "")
+	switch t {
+	case "int":
+		return TypeInteger
+	case "doubleprecision":
+		return TypeDouble
+	case "timestamp", "timestampwithtimezone":
+		// The default precision of timestamps is 3 (milliseconds).
+		return strings.Replace(t, "timestamp", "timestamp(3)", 1)
+	case "time", "timewithtimezone":
+		return strings.Replace(t, "time", "time(3)", 1)
+	}
+	return t
+}
+
+// normalizeValue normalizes the SQL expression of a property for comparison.
+func normalizeValue(v string) string {
+	var (
+		b     strings.Builder
+		quote bool
+	)
+	for _, r := range strings.TrimSpace(v) {
+		switch {
+		case r == '\'':
+			quote = !quote
+		case quote:
+		case r == ' ' || r == '\t' || r == '\n':
+			continue
+		default:
+			r = []rune(strings.ToUpper(string(r)))[0]
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(_, _ []schema.Attr) bool {
+	return false
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false
+}
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, _ *schema.Index) bool {
+	return false
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(_, _ schema.ReferenceOption) bool {
+	return false
+}
+
+// ForeignKeyAttrChanged reports if any of the foreign-key attributes were changed.
+func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
+	return false
+}
+
+// properties returns the table properties of the given attributes, in their order.
+func properties(attrs []schema.Attr) []*Property {
+	var ps []*Property
+	for _, a := range attrs {
+		if p, ok := a.(*Property); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// property returns the table property with the given name, if exists.
+func property(attrs []schema.Attr, k string) (*Property, bool) {
+	for _, p := range properties(attrs) {
+		if strings.EqualFold(p.K, k) {
+			return p, true
+		}
+	}
+	return nil, false
+}
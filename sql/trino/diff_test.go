@@ -0,0 +1,55 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_TableDiff(t *testing.T) {
+	var (
+		p3   = 3
+		from = schema.NewTable("events").
+			SetSchema(schema.New("analytics")).
+			AddColumns(
+				schema.NewIntColumn("id", TypeInteger),
+				schema.NewTimeColumn("ts", TypeTimestamp, schema.TimePrecision(p3)),
+			).
+			AddAttrs(
+				&Property{K: "format", V: "'PARQUET'"},
+				// Properties set by the connector are ignored
+				// if they are not defined in the desired state.
+				&Property{K: "location", V: "'s3://bucket/events'"},
+			)
+		to = schema.NewTable("events").
+			SetSchema(schema.New("analytics")).
+			AddColumns(
+				schema.NewIntColumn("id", TypeBigInt),
+				schema.NewTimeColumn("ts", TypeTimestamp),
+			).
+			AddAttrs(
+				&Property{K: "format", V: "'parquet'"},
+				&Property{K: "partitioning", V: "ARRAY['day(ts)']"},
+			)
+	)
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, &schema.ModifyAttr{From: from.Attrs[0], To: to.Attrs[0]}, changes[0])
+	require.Equal(t, &schema.AddAttr{A: to.Attrs[1]}, changes[1])
+	m, ok := changes[2].(*schema.ModifyColumn)
+	require.True(t, ok)
+	require.Equal(t, "id", m.To.Name)
+	require.Equal(t, schema.ChangeType, m.Change)
+}
+
+func TestNormalizeValue(t *testing.T) {
+	require.Equal(t, "ARRAY['day(ts)','bucket(id, 16)']", normalizeValue("array[ 'day(ts)',\n 'bucket(id, 16)' ]"))
+	require.Equal(t, "'Parquet'", normalizeValue("'Parquet'"))
+}
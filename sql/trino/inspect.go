@@ -0,0 +1,404 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// A inspect provides a Trino implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all resources in the given realm.
+// The realm is the catalog that was selected by the connection.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	var (
+		r    = schema.NewRealm(schemas...)
+		mode = sqlx.ModeInspectRealm(opts)
+	)
+	if len(schemas) == 0 || !mode.Is(schema.InspectTables) {
+		return schema.ExcludeRealm(r, opts.Exclude)
+	}
+	if err := i.inspectTables(ctx, r, nil); err != nil {
+		return nil, err
+	}
+	return schema.ExcludeRealm(r, opts.Exclude)
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// If the schema name is empty, the schema of the connection is used.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (s *schema.Schema, err error) {
+	schemas, err := i.schemas(ctx, &schema.InspectRealmOption{Schemas: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("trino: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("trino: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	r := schema.NewRealm(schemas...)
+	if sqlx.ModeInspectSchema(opts).Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+	}
+	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
+}
+
+// schemas returns the list of the schemas of the catalog.
+func (i *inspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = schemasQuery
+	)
+	if opts != nil {
+		switch n := len(opts.Schemas); {
+		case n == 1 && opts.Schemas[0] == "":
+			query = fmt.Sprintf(schemasQueryArgs, "= current_schema")
+		case n == 1 && opts.Schemas[0] != "":
+			query = fmt.Sprintf(schemasQueryArgs, "= ?")
+			args = append(args, opts.Schemas[0])
+		case n > 0:
+			query = fmt.Sprintf(schemasQueryArgs, "IN ("+nArgs(n)+")")
+			for _, s := range opts.Schemas {
+				args = append(args, s)
+			}
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("trino: querying schemas: %w", err)
+	}
+	defer rows.Close()
+	var schemas []*schema.Schema
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema.New(name))
+	}
+	return schemas, rows.Err()
+}
+
+// inspectTables inspects the tables of the realm schemas, and their columns.
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, r, opts); err != nil {
+		return err
+	}
+	if err := i.columns(ctx, r); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			if err := i.showCreate(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// tables returns the tables of the realm schemas.
+func (i *inspect) tables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, nArgs(len(r.Schemas)))
+	)
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		query = fmt.Sprintf(tablesQueryArgs, nArgs(len(r.Schemas)), nArgs(len(opts.Tables)))
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("trino: querying tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sName, tName string
+		if err := rows.Scan(&sName, &tName); err != nil {
+			return fmt.Errorf("trino: scanning table: %w", err)
+		}
+		s, ok := r.Schema(sName)
+		if !ok {
+			return fmt.Errorf("trino: schema %q was not found in realm", sName)
+		}
+		s.AddTables(schema.NewTable(tName))
+	}
+	return rows.Err()
+}
+
+// columns queries and appends the columns of the realm tables.
+func (i *inspect) columns(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(columnsQuery, nArgs(len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("trino: querying columns: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sName, tName, name, typ, nullable string
+		if err := rows.Scan(&sName, &tName, &name, &typ, &nullable); err != nil {
+			return fmt.Errorf("trino: scanning column: %w", err)
+		}
+		s, ok := r.Schema(sName)
+		if !ok {
+			continue
+		}
+		// Columns of views and of tables that
+		// were filtered out are skipped.
+		t, ok := s.Table(tName)
+		if !ok {
+			continue
+		}
+		ct, err := ParseType(typ)
+		if err != nil {
+			return err
+		}
+		t.AddColumns(&schema.Column{
+			Name: name,
+			Type: &schema.ColumnType{
+				Raw:  typ,
+				Type: ct,
+				Null: strings.EqualFold(nullable, "YES"),
+			},
+		})
+	}
+	return rows.Err()
+}
+
+// showCreate extracts the comments and the properties of the
+// table from its definition, as returned by SHOW CREATE TABLE.
+func (i *inspect) showCreate(ctx context.Context, t *schema.Table) error {
+	b := &sqlx.Builder{QuoteOpening: '"', QuoteClosing: '"'}
+	rows, err := i.QueryContext(ctx, b.P("SHOW CREATE TABLE").Table(t).String())
+	if err != nil {
+		return fmt.Errorf("trino: show create table %q: %w", t.Name, err)
+	}
+	var stmt sql.NullString
+	if err := sqlx.ScanOne(rows, &stmt); err != nil {
+		return fmt.Errorf("trino: scan create table %q: %w", t.Name, err)
+	}
+	def, err := parseCreate(stmt.String)
+	if err != nil {
+		return fmt.Errorf("trino: parse create table %q: %w", t.Name, err)
+	}
+	if def.comment != "" {
+		t.SetComment(def.comment)
+	}
+	for _, c := range t.Columns {
+		if x, ok := def.comments[c.Name]; ok && x != "" {
+			c.SetComment(x)
+		}
+	}
+	for _, p := range def.props {
+		t.AddAttrs(p)
+	}
+	return nil
+}
+
+// createDef holds the parts of a CREATE TABLE statement that are
+// not exposed by the information schema.
+type createDef struct {
+	comment  string
+	comments map[string]string
+	props    []*Property
+}
+
+var (
+	reColumnComment = regexp.MustCompile(`(?is)\sCOMMENT\s+('(?:[^']|'')*')`)
+	reTableComment  = regexp.MustCompile(`(?is)^COMMENT\s+('(?:[^']|'')*')`)
+)
+
+// parseCreate parses the output of SHOW CREATE TABLE. For example:
+//
+//	CREATE TABLE iceberg.analytics.events (
+//	   id bigint NOT NULL COMMENT 'event id',
+//	   ts timestamp(6) with time zone
+//	)
+//	COMMENT 'raw events'
+//	WITH (
+//	   format = 'PARQUET',
+//	   partitioning = ARRAY['day(ts)']
+//	)
+func parseCreate(stmt string) (*createDef, error) {
+	def := &createDef{comments: make(map[string]string)}
+	start := strings.IndexByte(stmt, '(')
+	if start == -1 {
+		return nil, fmt.Errorf("missing columns definition: %q", stmt)
+	}
+	end := closingParen(stmt, start)
+	if end == -1 {
+		return nil, fmt.Errorf("unbalanced columns definition: %q", stmt)
+	}
+	for _, c := range splitTop(stmt[start+1 : end]) {
+		name, rest := cutIdent(strings.TrimSpace(c))
+		if m := reColumnComment.FindStringSubmatch(rest); m != nil {
+			def.comments[name] = unquote(m[1])
+		}
+	}
+	tail := strings.TrimSpace(stmt[end+1:])
+	if m := reTableComment.FindStringSubmatch(tail); m != nil {
+		def.comment = unquote(m[1])
+		tail = strings.TrimSpace(tail[len(m[0]):])
+	}
+	if len(tail) < 4 || !strings.EqualFold(tail[:4], "WITH") {
+		return def, nil
+	}
+	start = strings.IndexByte(tail, '(')
+	if end = closingParen(tail, start); start == -1 || end == -1 {
+		return nil, fmt.Errorf("unbalanced table properties: %q", tail)
+	}
+	for _, p := range splitTop(tail[start+1 : end]) {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected table property: %q", p)
+		}
+		k, _ = cutIdent(strings.TrimSpace(k))
+		def.props = append(def.props, &Property{K: k, V: strings.TrimSpace(v)})
+	}
+	return def, nil
+}
+
+// closingParen returns the index of the parenthesis that closes the one at
+// the given position, or -1 if it is missing. Quoted text is skipped.
+func closingParen(s string, start int) int {
+	if start < 0 || start >= len(s) || s[start] != '(' {
+		return -1
+	}
+	var (
+		depth int
+		quote byte
+	)
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTop splits the given list by the commas that are not quoted or nested in parentheses.
+func splitTop(s string) []string {
+	var (
+		parts []string
+		depth int
+		quote byte
+		last  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[last:i]))
+			last = i + 1
+		}
+	}
+	if p := strings.TrimSpace(s[last:]); p != "" {
+		parts = append(parts, p)
+	}
+	return parts
+}
+
+// cutIdent cuts the (optionally quoted) identifier from the beginning of s.
+func cutIdent(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		name, rest, _ := strings.Cut(s, " ")
+		return name, rest
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] != '"' {
+			continue
+		}
+		// Escaped quote.
+		if i+1 < len(s) && s[i+1] == '"' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(s[1:i], `""`, `"`), s[i+1:]
+	}
+	return s, ""
+}
+
+// unquote unquotes a SQL string literal.
+func unquote(s string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(s, "'"), "'"), "''", "'")
+}
+
+// quote returns the SQL string literal of s.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// nArgs returns a list of n placeholders.
+func nArgs(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+const (
+	// Query to list the schemas of the catalog.
+	schemasQuery = "SELECT schema_name FROM information_schema.schemata WHERE schema_name <> 'information_schema' ORDER BY schema_name"
+
+	// Query to list specific schemas of the catalog.
+	schemasQueryArgs = "SELECT schema_name FROM information_schema.schemata WHERE schema_name %s ORDER BY schema_name"
+
+	// Query to list the tables of the schemas.
+	tablesQuery = "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema IN (%s) ORDER BY table_schema, table_name"
+
+	// Query to list specific tables of the schemas.
+	tablesQueryArgs = "SELECT table_schema, table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema IN (%s) AND table_name IN (%s) ORDER BY table_schema, table_name"
+
+	// Query to list the columns of the schemas.
+	columnsQuery = "SELECT table_schema, table_name, column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema IN (%s) ORDER BY table_schema, table_name, ordinal_position"
+)
@@ -0,0 +1,110 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= ?"))).
+		WithArgs("analytics").
+		WillReturnRows(sqltest.Rows(`
+ schema_name
+-------------
+ analytics
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "?"))).
+		WithArgs("analytics").
+		WillReturnRows(sqltest.Rows(`
+ table_schema | table_name
+--------------+------------
+ analytics    | events
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "?"))).
+		WithArgs("analytics").
+		WillReturnRows(sqltest.Rows(`
+ table_schema | table_name | column_name | data_type                   | is_nullable
+--------------+------------+-------------+-----------------------------+-------------
+ analytics    | events     | id          | bigint                      | NO
+ analytics    | events     | ts          | timestamp(6) with time zone | YES
+ analytics    | events     | tags        | array(varchar)              | YES
+ analytics    | daily      | day         | date                        | YES
+`))
+	m.ExpectQuery(sqltest.Escape(`SHOW CREATE TABLE "analytics"."events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"Create Table"}).AddRow(`CREATE TABLE iceberg.analytics.events (
+   id bigint NOT NULL COMMENT 'event''s id',
+   ts timestamp(6) with time zone,
+   tags array(varchar)
+)
+COMMENT 'raw events'
+WITH (
+   format = 'PARQUET',
+   partitioning = ARRAY['day(ts)','bucket(id, 16)']
+)`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "analytics", nil)
+	require.NoError(t, err)
+	require.Equal(t, "analytics", s.Name)
+	require.Len(t, s.Tables, 1)
+	tbl := s.Tables[0]
+	require.Equal(t, "events", tbl.Name)
+	p := 6
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: TypeBigInt}}, Attrs: []schema.Attr{&schema.Comment{Text: "event's id"}}},
+		{Name: "ts", Type: &schema.ColumnType{Raw: "timestamp(6) with time zone", Type: &schema.TimeType{T: TypeTimestampTZ, Precision: &p}, Null: true}},
+		{Name: "tags", Type: &schema.ColumnType{Raw: "array(varchar)", Type: &CompositeType{T: "array(varchar)"}, Null: true}},
+	}, tbl.Columns)
+	require.Equal(t, []schema.Attr{
+		&schema.Comment{Text: "raw events"},
+		&Property{K: "format", V: "'PARQUET'"},
+		&Property{K: "partitioning", V: "ARRAY['day(ts)','bucket(id, 16)']"},
+	}, tbl.Attrs)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_InspectSchema_NotExist(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= current_schema"))).
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	_, err = drv.InspectSchema(context.Background(), "", nil)
+	require.True(t, schema.IsNotExistError(err))
+}
+
+func TestParseCreate(t *testing.T) {
+	def, err := parseCreate(`CREATE TABLE hive.web."page views" (
+   "user id" bigint COMMENT 'a, b',
+   "ds" varchar
+)
+WITH (
+   format = 'ORC',
+   "partitioned_by" = ARRAY['ds']
+)`)
+	require.NoError(t, err)
+	require.Empty(t, def.comment)
+	require.Equal(t, map[string]string{"user id": "a, b"}, def.comments)
+	require.Equal(t, []*Property{{K: "format", V: "'ORC'"}, {K: "partitioned_by", V: "ARRAY['ds']"}}, def.props)
+
+	def, err = parseCreate(`CREATE TABLE memory.default.t (c bigint)`)
+	require.NoError(t, err)
+	require.Empty(t, def.props)
+
+	_, err = parseCreate(`CREATE TABLE memory.default.t (c bigint`)
+	require.Error(t, err)
+}
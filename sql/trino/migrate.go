@@ -0,0 +1,421 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"fmt"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for Trino dialects.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows}}
+
+// A planApply provides migration capabilities for schema elements.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// DDL statements cannot be executed in transactions
+			// by most of the connectors (e.g., Iceberg or Hive).
+			Transactional: false,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It's not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan of the changes. An error is
+// returned if one of the changes is not supported by Trino.
+func (s *state) plan(changes []schema.Change) error {
+	if err := s.Policy.Check(changes); err != nil {
+		return err
+	}
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	for _, c := range sqlx.SplitBackfills(sqlx.SortChanges(changes, nil)) {
+		var err error
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.addSchema(c)
+		case *schema.DropSchema:
+			s.dropSchema(c)
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			m, data := sqlx.DataChanges(c)
+			if len(data) == 0 || len(m.Changes) > 0 {
+				err = s.modifyTable(m)
+			}
+			if err == nil {
+				err = s.dataChanges(data...)
+			}
+		case *schema.DataChange:
+			err = s.dataChanges(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		case *schema.ModifySchema:
+			if len(c.Changes) > 0 {
+				err = fmt.Errorf("trino: unsupported schema changes for %q", c.S.Name)
+			}
+		default:
+			err = fmt.Errorf("trino: unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSchema builds the statement for creating a schema in the catalog.
+func (s *state) addSchema(add *schema.AddSchema) {
+	b := s.Build("CREATE SCHEMA")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.Ident(add.S.Name)
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP SCHEMA").Ident(add.S.Name).String(),
+		Comment: fmt.Sprintf("add new schema named %q", add.S.Name),
+	})
+}
+
+// dropSchema builds the statement for dropping a schema, and its tables, from the catalog.
+func (s *state) dropSchema(drop *schema.DropSchema) {
+	b := s.Build("DROP SCHEMA")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	b.Ident(drop.S.Name).P("CASCADE")
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop schema named %q", drop.S.Name),
+	})
+}
+
+// addTable builds the statement for creating a table in a schema.
+func (s *state) addTable(add *schema.AddTable) error {
+	if err := unsupportedObjects(add.T); err != nil {
+		return err
+	}
+	b := s.Build("CREATE TABLE")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.Table(add.T)
+	if err := b.WrapIndentErr(func(b *sqlx.Builder) error {
+		return b.MapIndentErr(add.T.Columns, func(i int, b *sqlx.Builder) error {
+			return s.column(b, add.T.Columns[i])
+		})
+	}); err != nil {
+		return err
+	}
+	if c := (schema.Comment{}); sqlx.Has(add.T.Attrs, &c) {
+		b.P("COMMENT", quote(c.Text))
+	}
+	if ps := properties(add.T.Attrs); len(ps) > 0 {
+		b.P("WITH")
+		b.WrapIndent(func(b *sqlx.Builder) {
+			b.MapIndent(ps, func(i int, b *sqlx.Builder) {
+				b.P(ps[i].K, "=", ps[i].V)
+			})
+		})
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	return nil
+}
+
+// dropTable builds the statement for dropping a table from a schema.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.Table(drop.T).String(),
+		Source:  drop,
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+	})
+	return nil
+}
+
+// dataChanges plans the given data changes.
+func (s *state) dataChanges(data ...*schema.DataChange) error {
+	for _, d := range data {
+		c, err := sqlx.PlanDataChange(s.Build(), d)
+		if err != nil {
+			return err
+		}
+		s.append(c)
+	}
+	return nil
+}
+
+// renameTable builds the statement for renaming a table.
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Table(c.To).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Table(c.From).String(),
+	})
+}
+
+// modifyTable builds the statements for bringing the table into its modified state.
+// Trino supports a single action per ALTER TABLE statement, and therefore, each
+// change is planned as a separate statement.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	var (
+		t          = modify.T
+		props, rev []*Property
+	)
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		case *schema.AddColumn:
+			b := s.Build("ALTER TABLE").Table(t).P("ADD COLUMN")
+			if err := s.column(b, change.C); err != nil {
+				return err
+			}
+			s.append(&migrate.Change{
+				Cmd:     b.String(),
+				Source:  change,
+				Reverse: s.Build("ALTER TABLE").Table(t).P("DROP COLUMN").Ident(change.C.Name).String(),
+				Comment: fmt.Sprintf("add column %q to table %q", change.C.Name, t.Name),
+			})
+		case *schema.DropColumn:
+			b := s.Build("ALTER TABLE").Table(t).P("ADD COLUMN")
+			if err := s.column(b, change.C); err != nil {
+				return err
+			}
+			s.append(&migrate.Change{
+				Cmd:     s.Build("ALTER TABLE").Table(t).P("DROP COLUMN").Ident(change.C.Name).String(),
+				Source:  change,
+				Reverse: b.String(),
+				Comment: fmt.Sprintf("drop column %q from table %q", change.C.Name, t.Name),
+			})
+		case *schema.RenameColumn:
+			s.append(&migrate.Change{
+				Cmd:     s.Build("ALTER TABLE").Table(t).P("RENAME COLUMN").Ident(change.From.Name).P("TO").Ident(change.To.Name).String(),
+				Source:  change,
+				Reverse: s.Build("ALTER TABLE").Table(t).P("RENAME COLUMN").Ident(change.To.Name).P("TO").Ident(change.From.Name).String(),
+				Comment: fmt.Sprintf("rename column %q of table %q to %q", change.From.Name, t.Name, change.To.Name),
+			})
+		case *schema.ModifyColumn:
+			if err := s.modifyColumn(t, change); err != nil {
+				return err
+			}
+		case *schema.AddAttr, *schema.ModifyAttr, *schema.DropAttr:
+			from, to := attrChange(change)
+			a := to
+			if a == nil {
+				a = from
+			}
+			switch a := a.(type) {
+			case *schema.Comment:
+				s.append(&migrate.Change{
+					Cmd:     s.Build("COMMENT ON TABLE").Table(t).P("IS", commentValue(to)).String(),
+					Source:  change,
+					Reverse: s.Build("COMMENT ON TABLE").Table(t).P("IS", commentValue(from)).String(),
+					Comment: fmt.Sprintf("set comment to table: %q", t.Name),
+				})
+			case *Property:
+				// Properties that were added (or dropped) are reset to their default.
+				p1, p2 := &Property{K: a.K, V: "DEFAULT"}, &Property{K: a.K, V: "DEFAULT"}
+				if p, ok := from.(*Property); ok {
+					p1 = p
+				}
+				if p, ok := to.(*Property); ok {
+					p2 = p
+				}
+				props, rev = append(props, p2), append(rev, p1)
+			default:
+				return fmt.Errorf("trino: unsupported table attribute change %T for table %q", change, t.Name)
+			}
+		default:
+			return fmt.Errorf("trino: unsupported change %T for table %q: indexes and constraints are not supported", change, t.Name)
+		}
+	}
+	if len(props) > 0 {
+		s.append(&migrate.Change{
+			Cmd:     s.setProperties(t, props),
+			Source:  modify,
+			Reverse: s.setProperties(t, rev),
+			Comment: fmt.Sprintf("set properties of table %q", t.Name),
+		})
+	}
+	return nil
+}
+
+// modifyColumn builds the statements for modifying a column.
+func (s *state) modifyColumn(t *schema.Table, m *schema.ModifyColumn) error {
+	if m.Change.Is(schema.ChangeType) {
+		f1, err := FormatType(m.From.Type.Type)
+		if err != nil {
+			return err
+		}
+		f2, err := FormatType(m.To.Type.Type)
+		if err != nil {
+			return err
+		}
+		s.append(&migrate.Change{
+			Cmd:     s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN").Ident(m.To.Name).P("SET DATA TYPE", f2).String(),
+			Source:  m,
+			Reverse: s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN").Ident(m.From.Name).P("SET DATA TYPE", f1).String(),
+			Comment: fmt.Sprintf("modify type of column %q of table %q", m.To.Name, t.Name),
+		})
+	}
+	if m.Change.Is(schema.ChangeNull) {
+		if !m.To.Type.Null {
+			return fmt.Errorf("trino: adding a NOT NULL constraint to existing column %q of table %q is not supported", m.To.Name, t.Name)
+		}
+		s.append(&migrate.Change{
+			Cmd:     s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN").Ident(m.To.Name).P("DROP NOT NULL").String(),
+			Source:  m,
+			Comment: fmt.Sprintf("drop NOT NULL constraint of column %q of table %q", m.To.Name, t.Name),
+		})
+	}
+	if m.Change.Is(schema.ChangeComment) {
+		var from, to schema.Comment
+		sqlx.Has(m.From.Attrs, &from)
+		sqlx.Has(m.To.Attrs, &to)
+		s.append(&migrate.Change{
+			Cmd:     s.Build("COMMENT ON COLUMN").P(s.columnIdent(t, m.To), "IS", commentValue(&to)).String(),
+			Source:  m,
+			Reverse: s.Build("COMMENT ON COLUMN").P(s.columnIdent(t, m.From), "IS", commentValue(&from)).String(),
+			Comment: fmt.Sprintf("set comment of column %q of table %q", m.To.Name, t.Name),
+		})
+	}
+	return nil
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	t, err := FormatType(c.Type.Type)
+	if err != nil {
+		return err
+	}
+	if c.Default != nil {
+		return fmt.Errorf("trino: default value of column %q is not supported", c.Name)
+	}
+	b.Ident(c.Name).P(t)
+	if !c.Type.Null {
+		b.P("NOT NULL")
+	}
+	if x := (schema.Comment{}); sqlx.Has(c.Attrs, &x) && x.Text != "" {
+		b.P("COMMENT", quote(x.Text))
+	}
+	return nil
+}
+
+// columnIdent returns the qualified identifier of the table column.
+func (s *state) columnIdent(t *schema.Table, c *schema.Column) string {
+	return s.Build().Table(t).String() + "." + s.Build().Ident(c.Name).String()
+}
+
+// setProperties returns the statement for setting the table properties.
+func (s *state) setProperties(t *schema.Table, props []*Property) string {
+	b := s.Build("ALTER TABLE").Table(t).P("SET PROPERTIES")
+	b.MapComma(props, func(i int, b *sqlx.Builder) {
+		b.P(props[i].K, "=", props[i].V)
+	})
+	return b.String()
+}
+
+// unsupportedObjects returns an error if the table defines objects that are not supported by Trino.
+func unsupportedObjects(t *schema.Table) error {
+	switch {
+	case t.PrimaryKey != nil:
+		return fmt.Errorf("trino: primary key of table %q is not supported", t.Name)
+	case len(t.Indexes) > 0:
+		return fmt.Errorf("trino: index %q of table %q is not supported", t.Indexes[0].Name, t.Name)
+	case len(t.ForeignKeys) > 0:
+		return fmt.Errorf("trino: foreign key %q of table %q is not supported", t.ForeignKeys[0].Symbol, t.Name)
+	case sqlx.Has(t.Attrs, &schema.Check{}):
+		return fmt.Errorf("trino: check constraints of table %q are not supported", t.Name)
+	}
+	return nil
+}
+
+// attrChange returns the attributes of an attribute change.
+func attrChange(c schema.Change) (from, to schema.Attr) {
+	switch c := c.(type) {
+	case *schema.AddAttr:
+		return nil, c.A
+	case *schema.ModifyAttr:
+		return c.From, c.To
+	case *schema.DropAttr:
+		return c.A, nil
+	}
+	return nil, nil
+}
+
+// commentValue returns the SQL value of the comment attribute, or NULL if it is not set.
+func commentValue(a schema.Attr) string {
+	if c, ok := a.(*schema.Comment); ok && c != nil && c.Text != "" {
+		return quote(c.Text)
+	}
+	return "NULL"
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	return (*Driver)(nil).StmtBuilder(s.PlanOptions).P(phrases...)
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	var (
+		s      = schema.New("analytics")
+		events = schema.NewTable("events").
+			AddColumns(
+				schema.NewIntColumn("id", TypeBigInt).SetComment("event id"),
+				schema.NewTimeColumn("ts", TypeTimestampTZ, schema.TimePrecision(6)).SetNull(true),
+			).
+			SetComment("raw events").
+			AddAttrs(
+				&Property{K: "format", V: "'PARQUET'"},
+				&Property{K: "partitioning", V: "ARRAY['day(ts)']"},
+			)
+	)
+	s.AddTables(events)
+	tests := []struct {
+		changes []schema.Change
+		options []migrate.PlanOption
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			changes: []schema.Change{
+				&schema.AddSchema{S: s, Extra: []schema.Clause{&schema.IfNotExists{}}},
+				&schema.AddTable{T: events},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE SCHEMA IF NOT EXISTS "analytics"`, Reverse: `DROP SCHEMA "analytics"`},
+					{Cmd: `CREATE TABLE "analytics"."events" ("id" bigint NOT NULL COMMENT 'event id', "ts" timestamp(6) with time zone) COMMENT 'raw events' WITH (format = 'PARQUET', partitioning = ARRAY['day(ts)'])`, Reverse: `DROP TABLE "analytics"."events"`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: events,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewNullStringColumn("name", TypeVarchar)},
+						&schema.RenameColumn{From: schema.NewColumn("ts"), To: schema.NewColumn("created_at")},
+						&schema.ModifyAttr{From: &Property{K: "format", V: "'ORC'"}, To: &Property{K: "format", V: "'PARQUET'"}},
+						&schema.AddAttr{A: &Property{K: "format_version", V: "2"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "analytics"."events" ADD COLUMN "name" varchar`, Reverse: `ALTER TABLE "analytics"."events" DROP COLUMN "name"`},
+					{Cmd: `ALTER TABLE "analytics"."events" RENAME COLUMN "ts" TO "created_at"`, Reverse: `ALTER TABLE "analytics"."events" RENAME COLUMN "created_at" TO "ts"`},
+					{Cmd: `ALTER TABLE "analytics"."events" SET PROPERTIES format = 'PARQUET', format_version = 2`, Reverse: `ALTER TABLE "analytics"."events" SET PROPERTIES format = 'ORC', format_version = DEFAULT`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: events,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   schema.NewIntColumn("id", TypeInteger),
+							To:     schema.NewIntColumn("id", TypeBigInt),
+							Change: schema.ChangeType,
+						},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "analytics"."events" ALTER COLUMN "id" SET DATA TYPE bigint`, Reverse: `ALTER TABLE "analytics"."events" ALTER COLUMN "id" SET DATA TYPE integer`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.DropTable{T: events},
+			},
+			options: []migrate.PlanOption{func(o *migrate.PlanOptions) { o.SchemaQualifier = new(string) }},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `DROP TABLE "events"`, Reverse: `CREATE TABLE "events" ("id" bigint NOT NULL COMMENT 'event id', "ts" timestamp(6) with time zone) COMMENT 'raw events' WITH (format = 'PARQUET', partitioning = ARRAY['day(ts)'])`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("users").AddColumns(schema.NewIntColumn("id", TypeInteger)).SetPrimaryKey(schema.NewPrimaryKey(schema.NewIntColumn("id", TypeInteger)))},
+			},
+			wantErr: `trino: primary key of table "users" is not supported`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: events,
+					Changes: []schema.Change{
+						&schema.AddIndex{I: schema.NewIndex("idx").AddColumns(schema.NewColumn("id"))},
+					},
+				},
+			},
+			wantErr: `trino: unsupported change *schema.AddIndex for table "events": indexes and constraints are not supported`,
+		},
+	}
+	for _, tt := range tests {
+		plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", tt.changes, tt.options...)
+		if tt.wantErr != "" {
+			require.EqualError(t, err, tt.wantErr)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.plan.Reversible, plan.Reversible)
+		require.False(t, plan.Transactional)
+		require.Len(t, plan.Changes, len(tt.plan.Changes))
+		for i, c := range plan.Changes {
+			require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+			require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+		}
+	}
+}
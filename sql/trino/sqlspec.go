@@ -0,0 +1,338 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type doc struct {
+	Tables  []*sqlspec.Table  `spec:"table"`
+	Schemas []*sqlspec.Schema `spec:"schema"`
+}
+
+// Codec for schemahcl.
+type Codec struct {
+	State *schemahcl.State
+}
+
+// Eval evaluates an Atlas DDL document into v using the input.
+func (c *Codec) Eval(p *hclparse.Parser, v any, input map[string]cty.Value) error {
+	return c.EvalOptions(p, v, &schemahcl.EvalOptions{Variables: input})
+}
+
+// EvalOptions decodes the HCL with the given options.
+func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptions) error {
+	switch v := v.(type) {
+	case *schema.Realm:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if err := specutil.Scan(v,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			scanFuncs,
+		); err != nil {
+			return fmt.Errorf("trino: failed converting to *schema.Realm: %w", err)
+		}
+	case *schema.Schema:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if len(d.Schemas) != 1 {
+			return fmt.Errorf("trino: expecting document to contain a single schema, got %d", len(d.Schemas))
+		}
+		r := &schema.Realm{}
+		if err := specutil.Scan(r,
+			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables},
+			scanFuncs,
+		); err != nil {
+			return err
+		}
+		*v = *r.Schemas[0]
+	case schema.Schema, schema.Realm:
+		return fmt.Errorf("trino: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
+	default:
+		return fmt.Errorf("trino: unexpected type %T", v)
+	}
+	return nil
+}
+
+// MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
+func (c *Codec) MarshalSpec(v any) ([]byte, error) {
+	var (
+		d       = &doc{}
+		schemas []*schema.Schema
+	)
+	switch v := v.(type) {
+	case *schema.Schema:
+		schemas = []*schema.Schema{v}
+	case *schema.Realm:
+		schemas = v.Schemas
+	default:
+		return nil, fmt.Errorf("trino: failed marshaling spec. %T is not supported", v)
+	}
+	for _, s := range schemas {
+		spec, err := specutil.FromSchema(s, &specutil.SchemaFuncs{
+			Table: tableSpec,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("trino: failed converting schema to spec: %w", err)
+		}
+		d.Tables = append(d.Tables, spec.Tables...)
+		d.Schemas = append(d.Schemas, spec.Schema)
+	}
+	if _, ok := v.(*schema.Realm); ok {
+		if err := specutil.QualifyObjects(d.Tables); err != nil {
+			return nil, err
+		}
+	}
+	return c.State.MarshalSpec(d)
+}
+
+var scanFuncs = &specutil.ScanFuncs{
+	Table: convertTable,
+}
+
+// propertiesBlock is the name of the table block that holds the connector properties.
+const propertiesBlock = "properties"
+
+// convertTable converts a sqlspec.Table to a schema.Table. The connector properties
+// are defined in a nested block and converted to their SQL representation. e.g.,
+//
+//	properties {
+//	  format       = "PARQUET"
+//	  partitioning = ["day(ts)"]
+//	}
+func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
+	t, err := specutil.Table(spec, parent, convertColumn, specutil.PrimaryKey, convertIndex, specutil.Check)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range spec.Extra.Children {
+		if r.Type != propertiesBlock {
+			continue
+		}
+		props := make([]*Property, 0, len(r.Attrs))
+		for _, a := range r.Attrs {
+			v, err := propertyValue(a)
+			if err != nil {
+				return nil, fmt.Errorf("trino: table %q: %w", spec.Name, err)
+			}
+			props = append(props, &Property{K: a.K, V: v})
+		}
+		sort.Slice(props, func(i, j int) bool { return props[i].K < props[j].K })
+		for _, p := range props {
+			t.AddAttrs(p)
+		}
+	}
+	return t, nil
+}
+
+// propertyValue converts the attribute value into its SQL representation.
+func propertyValue(a *schemahcl.Attr) (string, error) {
+	if a.IsRawExpr() {
+		x, err := a.RawExpr()
+		if err != nil {
+			return "", err
+		}
+		return x.X, nil
+	}
+	return literalValue(a.K, a.V)
+}
+
+// literalValue converts an HCL literal into its SQL representation.
+func literalValue(k string, v cty.Value) (string, error) {
+	switch t := v.Type(); {
+	case t == cty.String:
+		return quote(v.AsString()), nil
+	case t == cty.Bool:
+		return strconv.FormatBool(v.True()), nil
+	case t == cty.Number:
+		return v.AsBigFloat().Text('f', -1), nil
+	case t.IsListType(), t.IsTupleType():
+		vs := make([]string, 0, v.LengthInt())
+		for _, e := range v.AsValueSlice() {
+			s, err := literalValue(k, e)
+			if err != nil {
+				return "", err
+			}
+			vs = append(vs, s)
+		}
+		return "ARRAY[" + strings.Join(vs, ", ") + "]", nil
+	default:
+		return "", fmt.Errorf("unsupported value type %s for property %q", t.FriendlyName(), k)
+	}
+}
+
+// convertIndex converts a sqlspec.Index into a schema.Index. Note, indexes are not
+// supported by the Trino connectors, and they are rejected by the planner.
+func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
+	return specutil.Index(spec, t)
+}
+
+// convertColumn converts a sqlspec.Column into a schema.Column.
+func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
+	return specutil.Column(spec, convertColumnType)
+}
+
+// convertColumnType converts a sqlspec.Column into a concrete Trino schema.Type.
+func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
+	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
+}
+
+// tableSpec converts from a concrete Trino schema.Table to a sqlspec.Table.
+func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
+	spec, err := specutil.FromTable(
+		t,
+		columnSpec,
+		specutil.FromPrimaryKey,
+		indexSpec,
+		specutil.FromForeignKey,
+		specutil.FromCheck,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if props := properties(t.Attrs); len(props) > 0 {
+		r := &schemahcl.Resource{Type: propertiesBlock}
+		for _, p := range props {
+			r.SetAttr(propertyAttr(p))
+		}
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
+	return spec, nil
+}
+
+// indexSpec converts from a schema.Index into a sqlspec.Index.
+func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
+	return specutil.FromIndex(idx)
+}
+
+// propertyAttr converts a table property into an HCL attribute. Values
+// that cannot be represented as HCL literals are kept as raw expressions.
+func propertyAttr(p *Property) *schemahcl.Attr {
+	v := strings.TrimSpace(p.V)
+	switch {
+	case isQuoted(v):
+		return schemahcl.StringAttr(p.K, unquote(v))
+	case strings.EqualFold(v, "true"), strings.EqualFold(v, "false"):
+		return schemahcl.BoolAttr(p.K, strings.EqualFold(v, "true"))
+	case strings.HasPrefix(strings.ToUpper(v), "ARRAY[") && strings.HasSuffix(v, "]"):
+		var (
+			vs    []string
+			parts = splitTop(v[len("ARRAY[") : len(v)-1])
+		)
+		for _, e := range parts {
+			if e = strings.TrimSpace(e); !isQuoted(e) {
+				return schemahcl.RawAttr(p.K, v)
+			}
+			vs = append(vs, unquote(e))
+		}
+		return schemahcl.StringsAttr(p.K, vs...)
+	}
+	if f, ok := new(big.Float).SetString(v); ok {
+		return &schemahcl.Attr{K: p.K, V: cty.NumberVal(f)}
+	}
+	return schemahcl.RawAttr(p.K, v)
+}
+
+// isQuoted reports if the given SQL expression is a single string literal.
+func isQuoted(s string) bool {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return false
+	}
+	// Ensure the literal is not a concatenation of multiple ones, like 'a' || 'b'.
+	return !strings.Contains(strings.ReplaceAll(s[1:len(s)-1], "''", ""), "'")
+}
+
+// columnSpec converts from a concrete Trino schema.Column into a sqlspec.Column.
+func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
+	return specutil.FromColumn(c, columnTypeSpec)
+}
+
+// columnTypeSpec converts from a concrete Trino schema.Type into sqlspec.Column Type.
+func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
+	st, err := TypeRegistry.Convert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlspec.Column{Type: st}, nil
+}
+
+// TypeRegistry contains the supported TypeSpecs for the Trino driver.
+var TypeRegistry = schemahcl.NewRegistry(
+	schemahcl.WithFormatter(FormatType),
+	schemahcl.WithParser(ParseType),
+	schemahcl.WithSpecs(
+		schemahcl.NewTypeSpec(TypeBoolean),
+		schemahcl.NewTypeSpec(TypeTinyInt),
+		schemahcl.NewTypeSpec(TypeSmallInt),
+		schemahcl.NewTypeSpec(TypeInteger),
+		schemahcl.NewTypeSpec(TypeBigInt),
+		schemahcl.NewTypeSpec(TypeReal),
+		schemahcl.NewTypeSpec(TypeDouble),
+		schemahcl.NewTypeSpec(TypeDecimal, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeVarbinary),
+		schemahcl.NewTypeSpec(TypeJSON),
+		schemahcl.NewTypeSpec(TypeUUID),
+		schemahcl.NewTypeSpec(TypeDate),
+		schemahcl.NewTypeSpec(TypeTime, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr()), timeFromSpec()),
+		schemahcl.AliasTypeSpec("time_with_time_zone", TypeTimeTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr()), timeFromSpec()),
+		schemahcl.NewTypeSpec(TypeTimestamp, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr()), timeFromSpec()),
+		schemahcl.AliasTypeSpec("timestamp_with_time_zone", TypeTimestampTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr()), timeFromSpec()),
+	),
+)
+
+// timeFromSpec returns a converter for the time types, as their precision is placed
+// before the time zone (e.g., time(3) with time zone) and cannot be printed as a suffix.
+func timeFromSpec() schemahcl.TypeSpecOption {
+	return schemahcl.WithFromSpec(func(t *schemahcl.Type) (schema.Type, error) {
+		tt := &schema.TimeType{T: t.T}
+		for _, a := range t.Attrs {
+			if a.K != "precision" {
+				continue
+			}
+			p, err := a.Int()
+			if err != nil {
+				return nil, fmt.Errorf(`trino: parsing attribute "precision": %w`, err)
+			}
+			tt.Precision = &p
+		}
+		return tt, nil
+	})
+}
+
+var (
+	codec = &Codec{
+		State: schemahcl.New(
+			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+		),
+	}
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(codec.MarshalSpec)
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(codec.Eval)
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
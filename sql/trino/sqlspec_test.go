@@ -0,0 +1,89 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package trino
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	var (
+		s = &schema.Schema{}
+		f = `
+schema "analytics" {}
+
+table "events" {
+  schema  = schema.analytics
+  comment = "raw events"
+  column "id" {
+    type = bigint
+  }
+  column "ts" {
+    null = true
+    type = timestamp_with_time_zone(6)
+  }
+  column "payload" {
+    null = true
+    type = sql("map(varchar, varchar)")
+  }
+  properties {
+    format         = "PARQUET"
+    partitioning   = ["day(ts)", "bucket(id, 16)"]
+    format_version = 2
+    location       = sql("concat('s3://bucket/', 'events')")
+  }
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), s, nil))
+	require.Len(t, s.Tables, 1)
+	tbl := s.Tables[0]
+	p := 6
+	require.Equal(t, &schema.TimeType{T: TypeTimestampTZ, Precision: &p}, tbl.Columns[1].Type.Type)
+	require.Equal(t, &CompositeType{T: "map(varchar, varchar)"}, tbl.Columns[2].Type.Type)
+	require.Equal(t, []*Property{
+		{K: "format", V: "'PARQUET'"},
+		{K: "format_version", V: "2"},
+		{K: "location", V: "concat('s3://bucket/', 'events')"},
+		{K: "partitioning", V: "ARRAY['day(ts)', 'bucket(id, 16)']"},
+	}, properties(tbl.Attrs))
+
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "events" {
+  schema  = schema.analytics
+  comment = "raw events"
+  column "id" {
+    null = false
+    type = bigint
+  }
+  column "ts" {
+    null = true
+    type = timestamp_with_time_zone(6)
+  }
+  column "payload" {
+    null = true
+    type = sql("map(varchar, varchar)")
+  }
+  properties {
+    format         = "PARQUET"
+    format_version = 2
+    location       = sql("concat('s3://bucket/', 'events')")
+    partitioning   = ["day(ts)", "bucket(id, 16)"]
+  }
+}
+schema "analytics" {
+}
+`, string(buf))
+
+	// Round-trip the marshaled document.
+	s2 := &schema.Schema{}
+	require.NoError(t, EvalHCLBytes(buf, s2, nil))
+	require.Equal(t, properties(tbl.Attrs), properties(s2.Tables[0].Attrs))
+}